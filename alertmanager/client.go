@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package alertmanager is a minimal client for the Prometheus Alertmanager v2
+// API to silence alerts while CTS applies changes for a task.
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// CreatedBy is the creator recorded on the silences created by CTS
+	CreatedBy = "consul-terraform-sync"
+
+	silencesPath = "/api/v2/silences"
+
+	// maxErrorBodySize limits how much of an error response is included in
+	// the returned error
+	maxErrorBodySize = 1024
+)
+
+// Matcher matches the label of an alert to silence
+type Matcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+// Silence is a silence of the alerts that match all of its matchers between
+// StartsAt and EndsAt
+type Silence struct {
+	Matchers  []Matcher `json:"matchers"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	CreatedBy string    `json:"createdBy"`
+	Comment   string    `json:"comment"`
+}
+
+// ClientConfig configures the Alertmanager client
+type ClientConfig struct {
+	// Address is the URL of the Alertmanager, e.g. http://localhost:9093
+	Address string
+
+	// Token is an optional bearer token to authenticate requests
+	Token string
+
+	// Timeout is the time limit for a request. No limit if 0.
+	Timeout time.Duration
+
+	// Transport is the transport for requests. Defaults to the default
+	// transport of the http package.
+	Transport http.RoundTripper
+}
+
+// Client is a client for the Alertmanager v2 API
+type Client struct {
+	address *url.URL
+	token   string
+	http    *http.Client
+}
+
+// NewClient returns a client for the Alertmanager at the configured address
+func NewClient(conf ClientConfig) (*Client, error) {
+	u, err := url.Parse(conf.Address)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse Alertmanager address: %s", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported scheme %q for Alertmanager "+
+			"address, supported schemes are: http, https", u.Scheme)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	return &Client{
+		address: u,
+		token:   conf.Token,
+		http: &http.Client{
+			Timeout:   conf.Timeout,
+			Transport: conf.Transport,
+		},
+	}, nil
+}
+
+// CreateSilence creates the silence and returns the ID of the silence
+func (c *Client) CreateSilence(ctx context.Context, s Silence) (string, error) {
+	if len(s.Matchers) == 0 {
+		return "", errors.New("silence requires at least one matcher")
+	}
+	if s.CreatedBy == "" {
+		s.CreatedBy = CreatedBy
+	}
+
+	body, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		c.address.String()+silencesPath, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to create silence: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return "", fmt.Errorf("unable to create silence: unexpected status %d: %s",
+			resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var out struct {
+		SilenceID string `json:"silenceID"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("unable to decode silence response: %s", err)
+	}
+	return out.SilenceID, nil
+}
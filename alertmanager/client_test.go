@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClient(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		address string
+		err     bool
+	}{
+		{"http", "http://localhost:9093", false},
+		{"https with path", "https://example.com/alertmanager/", false},
+		{"missing scheme", "localhost:9093", true},
+		{"unsupported scheme", "tcp://localhost:9093", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewClient(ClientConfig{Address: tc.address})
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestClient_CreateSilence(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	silence := Silence{
+		Matchers: []Matcher{
+			{Name: "service", Value: "api", IsEqual: true},
+			{Name: "alertname", Value: "Instance.*", IsRegex: true, IsEqual: true},
+		},
+		StartsAt: start,
+		EndsAt:   start.Add(10 * time.Minute),
+		Comment:  "task lb is applying changes",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		var received Silence
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/am/api/v2/silences", r.URL.Path)
+			assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			w.Write([]byte(`{"silenceID":"abc-123"}`))
+		}))
+		defer ts.Close()
+
+		c, err := NewClient(ClientConfig{Address: ts.URL + "/am/", Token: "secret"})
+		require.NoError(t, err)
+
+		id, err := c.CreateSilence(context.Background(), silence)
+		require.NoError(t, err)
+		assert.Equal(t, "abc-123", id)
+
+		expected := silence
+		expected.CreatedBy = CreatedBy
+		assert.Equal(t, expected, received)
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("silence invalid: missing matchers"))
+		}))
+		defer ts.Close()
+
+		c, err := NewClient(ClientConfig{Address: ts.URL})
+		require.NoError(t, err)
+
+		_, err = c.CreateSilence(context.Background(), silence)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "400")
+		assert.Contains(t, err.Error(), "missing matchers")
+	})
+
+	t.Run("no matchers", func(t *testing.T) {
+		c, err := NewClient(ClientConfig{Address: "http://localhost:9093"})
+		require.NoError(t, err)
+
+		_, err = c.CreateSilence(context.Background(), Silence{})
+		assert.Error(t, err)
+	})
+}
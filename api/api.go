@@ -8,7 +8,9 @@ import (
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
 	"strings"
 	"sync"
 	"time"
@@ -51,6 +53,13 @@ const (
 	// stored event is all not successful.
 	StatusCritical = "critical"
 
+	// StatusDegraded is the degraded status. This is determined based on
+	// status type.
+	//
+	// Task Status: A task is degraded when it is paused by its circuit breaker
+	// after failing the configured number of consecutive times.
+	StatusDegraded = "degraded"
+
 	// StatusUnknown is when the status is unknown. This is determined
 	// based on status type.
 	//
@@ -64,22 +73,62 @@ const (
 
 // API supports api requests to the cts binary
 type API struct {
-	ctrl    Server
-	health  health.Checker
-	port    int
-	version string
-	srv     *http.Server
-	tls     *config.CTSTLSConfig
+	ctrl      Server
+	health    health.Checker
+	port      int
+	addresses []string
+	version   string
+	srv       *http.Server
+	tls       *config.CTSTLSConfig
+
+	// readOnly serves the read-only endpoints on separate listeners, if
+	// configured
+	readOnly *API
 }
 
 // Config is used to configure the API
 type Config struct {
-	Port          int
+	Port int
+
+	// Addresses are the addresses for the API to listen on. Addresses can be
+	// host:port addresses or unix sockets prefixed with unix://. If no
+	// addresses are set, the API listens on all interfaces on Port. Unix
+	// sockets are always served without TLS, even if TLS is configured, and
+	// access to them is controlled by their file permissions.
+	Addresses []string
+
+	// IdempotencyKeyTTL is the window that the outcome of a request with an
+	// Idempotency-Key header is cached for retries. Caching is disabled if 0.
+	IdempotencyKeyTTL time.Duration
+
 	TLS           *config.CTSTLSConfig
 	Controller    Server
 	Health        health.Checker
 	Interceptor   Interceptor
 	StatusHandler StatusHandler
+
+	// TFCRunTaskHMACKey is the HMAC key to verify Terraform Cloud run task
+	// requests. The run task endpoint is enabled if the key is set.
+	TFCRunTaskHMACKey string
+
+	// Proxy configures the proxy for outbound requests to Terraform Cloud.
+	// Optional, the proxy environment variables are used if not configured.
+	Proxy *config.ProxyConfig
+
+	// ReadOnly configures additional listeners that only serve the read-only
+	// endpoints of the API with a separate TLS configuration. Optional.
+	ReadOnly *ReadOnlyConfig
+}
+
+// ReadOnlyConfig is used to configure the listeners for the read-only
+// endpoints of the API
+type ReadOnlyConfig struct {
+	// Addresses are the addresses for the read-only listeners. Addresses can
+	// be host:port addresses or unix sockets prefixed with unix://. Unix
+	// sockets are served without TLS.
+	Addresses []string
+
+	TLS *config.CTSTLSConfig
 }
 
 // NewAPI create a new API object
@@ -87,11 +136,12 @@ func NewAPI(ctx context.Context, conf Config) (*API, error) {
 	logger := logging.FromContext(ctx).Named(logSystemName)
 
 	api := &API{
-		ctrl:    conf.Controller,
-		health:  conf.Health,
-		port:    conf.Port,
-		version: defaultAPIVersion,
-		tls:     conf.TLS,
+		ctrl:      conf.Controller,
+		health:    conf.Health,
+		port:      conf.Port,
+		addresses: conf.Addresses,
+		version:   defaultAPIVersion,
+		tls:       conf.TLS,
 	}
 
 	if conf.TLS == nil {
@@ -118,6 +168,26 @@ func NewAPI(ctx context.Context, conf Config) (*API, error) {
 		r.Mount(fmt.Sprintf("/%s", overallStatusPath),
 			newOverallStatusHandler(api.ctrl, defaultAPIVersion))
 
+		// retrieve the graph of tasks and their dependencies
+		r.Mount(fmt.Sprintf("/%s", graphPath),
+			newGraphHandler(api.ctrl, defaultAPIVersion))
+
+		// retrieve the readiness of the tasks for their first run
+		r.Mount(fmt.Sprintf("/%s", readinessPath),
+			newReadinessHandler(api.ctrl, defaultAPIVersion))
+
+		// dump internal diagnostics for support
+		r.Mount(fmt.Sprintf("/%s", debugDumpPath),
+			newDebugDumpHandler(api.ctrl, defaultAPIVersion))
+
+		// retrieve the effective configuration
+		r.Mount(fmt.Sprintf("/%s", configPath),
+			newConfigHandler(api.ctrl, defaultAPIVersion))
+
+		// retrieve the inventory of modules and providers of all tasks
+		r.Mount(fmt.Sprintf("/%s", inventoryPath),
+			newInventoryHandler(api.ctrl, defaultAPIVersion))
+
 		// retrieve all task statuses
 		r.Mount(fmt.Sprintf("/%s", taskStatusPath),
 			newTaskStatusHandler(api.ctrl, defaultAPIVersion))
@@ -125,6 +195,13 @@ func NewAPI(ctx context.Context, conf Config) (*API, error) {
 		// crud task
 		r.Mount(fmt.Sprintf("/%s", taskPath),
 			newTaskHandler(api.ctrl, defaultAPIVersion))
+
+		// Terraform Cloud run task integration
+		if conf.TFCRunTaskHMACKey != "" {
+			r.Mount(fmt.Sprintf("/%s", tfcRunTaskPath),
+				newTFCRunTaskHandler(api.ctrl, conf.TFCRunTaskHMACKey,
+					conf.Proxy))
+		}
 	})
 
 	r.Group(func(r chi.Router) {
@@ -140,47 +217,123 @@ func NewAPI(ctx context.Context, conf Config) (*API, error) {
 		}
 
 		// Generated Endpoints
+		taskLifeCycleHandler := NewTaskLifeCycleHandler(api.ctrl)
+		if conf.IdempotencyKeyTTL > 0 {
+			taskLifeCycleHandler.idempotencyKeys = newIdempotencyCache(conf.IdempotencyKeyTTL)
+		}
 		server := Handlers{
-			TaskLifeCycleHandler: NewTaskLifeCycleHandler(api.ctrl),
+			TaskLifeCycleHandler: taskLifeCycleHandler,
 			HealthHandler:        NewHealthHandler(api.health),
+			VersionHandler:       NewVersionHandler(),
 			StatusHandler:        statusHandlerFactory(conf.StatusHandler),
 		}
 
 		oapigen.HandlerFromMux(server, r)
 	})
 
+	t, err := serverTLSConfig(api.tls)
+	if err != nil {
+		logger.Error("error loading TLS configs for api server", "error", err)
+		return nil, err
+	}
+	api.srv = newHTTPServer(fmt.Sprintf(":%d", api.port), r, t, logger)
+
+	if conf.ReadOnly != nil && len(conf.ReadOnly.Addresses) > 0 {
+		roTLS := conf.ReadOnly.TLS
+		if roTLS == nil {
+			roTLS = config.DefaultCTSTLSConfig()
+		}
+		t, err := serverTLSConfig(roTLS)
+		if err != nil {
+			logger.Error("error loading TLS configs for read-only api server",
+				"error", err)
+			return nil, err
+		}
+		api.readOnly = &API{
+			ctrl:      api.ctrl,
+			health:    api.health,
+			addresses: conf.ReadOnly.Addresses,
+			version:   api.version,
+			tls:       roTLS,
+			srv:       newHTTPServer("", withReadOnly(r), t, logger),
+		}
+	}
+
+	return api, nil
+}
+
+// serverTLSConfig returns the TLS configuration for an API server. Client
+// certificates are required if verify incoming is enabled.
+func serverTLSConfig(c *config.CTSTLSConfig) (*tls.Config, error) {
 	t := &tls.Config{}
-	if config.BoolVal(api.tls.Enabled) && config.BoolVal(api.tls.VerifyIncoming) {
+	if config.BoolVal(c.Enabled) && config.BoolVal(c.VerifyIncoming) {
 		certPool, err := rootcerts.LoadCACerts(&rootcerts.Config{
-			CAFile: config.StringVal(api.tls.CACert),
-			CAPath: config.StringVal(api.tls.CAPath),
+			CAFile: config.StringVal(c.CACert),
+			CAPath: config.StringVal(c.CAPath),
 		})
 		if err != nil {
-			logger.Error("error loading TLS configs for api server", "error", err)
 			return nil, err
 		}
 		t.ClientCAs = certPool
 		t.ClientAuth = tls.RequireAndVerifyClientCert
 	}
+	return t, nil
+}
+
+// newHTTPServer returns the http server for the API handler
+func newHTTPServer(addr string, handler http.Handler, t *tls.Config,
+	logger logging.Logger) *http.Server {
 
-	api.srv = &http.Server{
-		Addr:        fmt.Sprintf(":%d", api.port),
+	return &http.Server{
+		Addr:        addr,
 		ReadTimeout: time.Second * 15,
 		IdleTimeout: time.Second * 60,
-		Handler:     r,
+		Handler:     handler,
 		TLSConfig:   t,
 		ErrorLog: logger.StandardLogger(&hclog.StandardLoggerOptions{
 			InferLevels: false,
 			ForceLevel:  hclog.Warn,
 		}),
 	}
-
-	return api, nil
 }
 
 // Serve starts up and handles shutdown for the http server to serve
-// API requests
+// API requests. If read-only listeners are configured, they are served
+// alongside the API and an error serving either stops both.
 func (api *API) Serve(ctx context.Context) error {
+	if api.readOnly == nil {
+		return api.serve(ctx)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	roErrCh := make(chan error, 1)
+	go func() {
+		err := api.readOnly.serve(ctx)
+		if ctx.Err() == nil {
+			// stop the API if the read-only listeners stop unexpectedly
+			cancel()
+		}
+		roErrCh <- err
+	}()
+
+	err := api.serve(ctx)
+	cancel()
+	roErr := <-roErrCh
+
+	if err != nil && err != context.Canceled {
+		return err
+	}
+	if roErr != nil && roErr != context.Canceled {
+		return roErr
+	}
+	return err
+}
+
+// serve starts up and handles shutdown for the http server on the listeners
+// of the API
+func (api *API) serve(ctx context.Context) error {
 	var wg sync.WaitGroup
 	wg.Add(1)
 
@@ -203,23 +356,109 @@ func (api *API) Serve(ctx context.Context) error {
 		}
 	}()
 
-	logger.Info("starting server", "port", api.port)
-	var err error
-	if config.BoolVal(api.tls.Enabled) {
-		err = api.srv.ListenAndServeTLS(*api.tls.Cert, *api.tls.Key)
-	} else {
-		err = api.srv.ListenAndServe()
-	}
-	if err != nil && err != http.ErrServerClosed {
-		logger.Error("error serving api", "port", api.port, "error", err)
+	listeners, err := api.listen()
+	if err != nil {
+		logger.Error("error listening for api", "error", err)
 		return err
 	}
 
+	errCh := make(chan error, len(listeners))
+	for _, l := range listeners {
+		logger.Info("starting server", "address", l.address)
+		if l.unix && config.BoolVal(api.tls.Enabled) {
+			logger.Warn("unix socket is served without TLS, access to the "+
+				"socket is controlled by its file permissions", "address", l.address)
+		}
+		go func(l apiListener) {
+			// Unix sockets are served without TLS. Access to the socket is
+			// controlled by its file permissions.
+			if l.unix || !config.BoolVal(api.tls.Enabled) {
+				errCh <- api.srv.Serve(l)
+				return
+			}
+			errCh <- api.srv.ServeTLS(l, *api.tls.Cert, *api.tls.Key)
+		}(l)
+	}
+
+	for range listeners {
+		if err := <-errCh; err != nil && err != http.ErrServerClosed {
+			logger.Error("error serving api", "error", err)
+			api.srv.Close()
+			return err
+		}
+	}
+
 	// wait for shutdown
 	wg.Wait()
 	return ctx.Err()
 }
 
+// apiListener is a listener for one of the addresses the API is served on
+type apiListener struct {
+	net.Listener
+	address string
+	unix    bool
+}
+
+// listen opens a listener for each of the configured addresses. If no
+// addresses are configured, it listens on all interfaces on the port.
+func (api *API) listen() ([]apiListener, error) {
+	addresses := api.addresses
+	if len(addresses) == 0 {
+		addresses = []string{fmt.Sprintf(":%d", api.port)}
+	}
+
+	listeners := make([]apiListener, 0, len(addresses))
+	for _, addr := range addresses {
+		l, err := listen(addr)
+		if err != nil {
+			for _, opened := range listeners {
+				opened.Close()
+			}
+			return nil, err
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, nil
+}
+
+// listen opens a listener for an address. Addresses prefixed with unix:// are
+// opened as unix sockets and any stale socket file at the path is removed.
+// Errors if a file at the path is not a socket, so that a mistyped address
+// does not remove the file.
+func listen(addr string) (apiListener, error) {
+	if !strings.HasPrefix(addr, config.UnixSocketAddressPrefix) {
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			return apiListener{}, err
+		}
+		return apiListener{Listener: l, address: addr}, nil
+	}
+
+	path := strings.TrimPrefix(addr, config.UnixSocketAddressPrefix)
+	info, err := os.Lstat(path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return apiListener{}, fmt.Errorf("unable to check existing file at "+
+			"unix socket path %s: %s", path, err)
+	case info.Mode()&os.ModeSocket == 0:
+		return apiListener{}, fmt.Errorf("unable to listen on unix socket "+
+			"%s: a file that is not a socket exists at the path", path)
+	default:
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return apiListener{}, fmt.Errorf("unable to remove existing unix "+
+				"socket %s: %s", path, err)
+		}
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return apiListener{}, err
+	}
+	return apiListener{Listener: l, address: addr, unix: true}, nil
+}
+
 // jsonResponse adds the return response for handlers. Returns if json encode
 // errored. Option to check error or add responses to jsonResponse test to
 // test json encoding
@@ -230,7 +469,9 @@ func jsonResponse(w http.ResponseWriter, code int, response interface{}) error {
 }
 
 func jsonErrorResponse(ctx context.Context, w http.ResponseWriter, code int, err error) {
-	err = jsonResponse(w, code, NewErrorResponse(err))
+	resp := NewErrorResponse(err)
+	resp.Error.Code = errorCode(code, err)
+	err = jsonResponse(w, code, resp)
 	if err != nil {
 		logging.FromContext(ctx).Named(logSystemName).Error("error, could not generate json error response",
 			"error", err)
@@ -12,8 +12,10 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"regexp"
 	"testing"
@@ -24,6 +26,8 @@ import (
 	mockHealth "github.com/hashicorp/consul-terraform-sync/mocks/health"
 	mocks "github.com/hashicorp/consul-terraform-sync/mocks/server"
 	"github.com/hashicorp/consul-terraform-sync/state/event"
+	"github.com/hashicorp/consul-terraform-sync/state/revision"
+	"github.com/hashicorp/consul-terraform-sync/templates"
 	"github.com/hashicorp/consul-terraform-sync/testutils"
 	"github.com/hashicorp/go-rootcerts"
 	"github.com/stretchr/testify/assert"
@@ -57,10 +61,11 @@ func TestServe(t *testing.T) {
 			method: http.MethodGet,
 			mock: func(ctrl *mocks.Server) {
 				ctrl.On("Tasks", mock.Anything).Return(config.TaskConfigs{}).
-					On("Events", mock.Anything, "").Return(map[string][]event.Event{}, nil)
+					On("Events", mock.Anything, "").Return(map[string][]event.Event{}, nil).
+					On("DependencyStats", mock.Anything).Return(templates.DependencyStats{})
 			},
 			statusCode: http.StatusOK,
-			respBody: `{"task_summary":{"status":{"successful":0,"errored":0,"critical":0,"unknown":0},"enabled":{"true":0,"false":0}}}
+			respBody: `{"task_summary":{"status":{"successful":0,"errored":0,"critical":0,"unknown":0},"enabled":{"true":0,"false":0}},"dependencies":{"templates":0,"dependencies":0,"shared":0,"references":0,"deduplicated":0}}
 `,
 		}, {
 			name:   "task status: all",
@@ -83,10 +88,13 @@ func TestServe(t *testing.T) {
 					Name:    &taskName,
 					Enabled: config.Bool(true),
 				}, nil).
-					On("Events", mock.Anything, taskName).Return(map[string][]event.Event{}, nil)
+					On("Events", mock.Anything, taskName).Return(map[string][]event.Event{}, nil).
+					On("TaskPendingRuns", mock.Anything, taskName).Return(nil).
+					On("TaskNextScheduledRun", mock.Anything, taskName).Return(time.Time{}, false).
+					On("TaskStats", mock.Anything, taskName).Return(event.Stats{}, false)
 			},
 			statusCode: http.StatusOK,
-			respBody: `{"task_b":{"task_name":"task_b","status":"unknown","enabled":true,"events_url":"","providers":null,"services":null}}
+			respBody: `{"task_b":{"task_name":"task_b","status":"unknown","enabled":true,"events_url":"","pending_runs":0,"providers":null,"services":null}}
 `,
 		}, {
 			name:   "create task",
@@ -144,13 +152,49 @@ func TestServe(t *testing.T) {
 			},
 			statusCode: http.StatusOK,
 			respBody:   "{}\n",
+		}, {
+			name:   "task revisions",
+			path:   "tasks/task_b/revisions",
+			method: http.MethodGet,
+			mock: func(ctrl *mocks.Server) {
+				ctrl.On("TaskRevisions", mock.Anything, "task_b").Return([]revision.Revision{{
+					ID:       1,
+					TaskName: "task_b",
+					Actor:    revision.ActorConfig,
+					Config: config.TaskConfig{
+						Name:    config.String("task_b"),
+						Enabled: config.Bool(true),
+						Module:  config.String("module"),
+					},
+				}}, nil)
+			},
+			statusCode: http.StatusOK,
+			respBody: `{"revisions":[{"id":1,"timestamp":"0001-01-01T00:00:00Z","actor":"config","task":{"condition":{},"enabled":true,"module":"module","name":"task_b"}}]}
+`,
+		}, {
+			name:   "restore task revision",
+			path:   "tasks/task_b/revisions/1/restore",
+			method: http.MethodPost,
+			mock: func(ctrl *mocks.Server) {
+				taskConf := config.TaskConfig{
+					Name:    config.String("task_b"),
+					Enabled: config.Bool(true),
+					Module:  config.String("module"),
+				}
+				ctrl.On("TaskRevisions", mock.Anything, "task_b").Return(
+					[]revision.Revision{{ID: 1, TaskName: "task_b", Config: taskConf}}, nil)
+				ctrl.On("TaskRestoreRevision", mock.Anything, "task_b", 1).Return(taskConf, nil)
+			},
+			statusCode: http.StatusOK,
+			respBody: `{"task":{"condition":{},"enabled":true,"module":"module","name":"task_b"}}
+`,
 		}, {
 			name:       "default status handler",
 			path:       "status/cluster",
 			method:     http.MethodGet,
 			mock:       func(ctrl *mocks.Server) {},
 			statusCode: http.StatusMethodNotAllowed,
-			respBody: fmt.Sprintf(`{"error":{"message":"%s"},}
+			respBody: fmt.Sprintf(`{"error":{"code":"method_not_allowed","message":"%s"},}
 `, haNotAvailableError),
 		},
 		{
@@ -160,7 +204,7 @@ func TestServe(t *testing.T) {
 			mock:          func(ctrl *mocks.Server) {},
 			statusCode:    http.StatusMethodNotAllowed,
 			statusHandler: statusHandlerMock{},
-			respBody: fmt.Sprintf(`{"error":{"message":"%s"},}
+			respBody: fmt.Sprintf(`{"error":{"code":"method_not_allowed","message":"%s"},}
 `, testCustomErrorMessage),
 		},
 	}
@@ -222,6 +266,162 @@ func TestServe_context_cancel(t *testing.T) {
 	}
 }
 
+func TestServe_Addresses(t *testing.T) {
+	t.Parallel()
+
+	port := testutils.FreePort(t)
+	socket := filepath.Join(t.TempDir(), "cts.sock")
+
+	checker := new(mockHealth.Checker)
+	checker.On("Check").Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	api, err := NewAPI(ctx, Config{
+		Addresses: []string{
+			fmt.Sprintf("127.0.0.1:%d", port),
+			config.UnixSocketAddressPrefix + socket,
+		},
+		Health: checker,
+	})
+	require.NoError(t, err)
+
+	go api.Serve(ctx)
+	time.Sleep(500 * time.Millisecond)
+
+	cases := []struct {
+		name string
+		url  string
+	}{
+		{
+			"tcp",
+			fmt.Sprintf("http://127.0.0.1:%d", port),
+		},
+		{
+			"unix socket",
+			"unix://" + socket,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, socketPath, err := parseURL(tc.url)
+			require.NoError(t, err)
+			client, err := newHTTPClient(&TLSConfig{}, socketPath)
+			require.NoError(t, err)
+
+			resp, err := client.Get(u.String() + healthPath)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	}
+}
+
+func TestListen_UnixSocket(t *testing.T) {
+	t.Parallel()
+
+	t.Run("new socket", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cts.sock")
+		l, err := listen(config.UnixSocketAddressPrefix + path)
+		require.NoError(t, err)
+		defer l.Close()
+		assert.True(t, l.unix)
+	})
+
+	t.Run("stale socket", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cts.sock")
+		stale, err := net.Listen("unix", path)
+		require.NoError(t, err)
+		// keep the socket file when closing the listener
+		stale.(*net.UnixListener).SetUnlinkOnClose(false)
+		stale.Close()
+
+		l, err := listen(config.UnixSocketAddressPrefix + path)
+		require.NoError(t, err)
+		defer l.Close()
+	})
+
+	t.Run("not a socket", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.hcl")
+		require.NoError(t, os.WriteFile(path, []byte("content"), 0600))
+
+		_, err := listen(config.UnixSocketAddressPrefix + path)
+		assert.Error(t, err)
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err, "file should not be removed")
+		assert.Equal(t, "content", string(content))
+	})
+}
+
+func TestServe_ReadOnly(t *testing.T) {
+	t.Parallel()
+
+	port := testutils.FreePort(t)
+	roPort := testutils.FreePort(t)
+
+	checker := new(mockHealth.Checker)
+	checker.On("Check").Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	api, err := NewAPI(ctx, Config{
+		Port:   port,
+		Health: checker,
+		ReadOnly: &ReadOnlyConfig{
+			Addresses: []string{fmt.Sprintf("127.0.0.1:%d", roPort)},
+		},
+	})
+	require.NoError(t, err)
+
+	go api.Serve(ctx)
+	time.Sleep(500 * time.Millisecond)
+
+	cases := []struct {
+		name       string
+		port       int
+		method     string
+		path       string
+		statusCode int
+	}{
+		{
+			"read-only get",
+			roPort,
+			http.MethodGet,
+			healthPath,
+			http.StatusOK,
+		},
+		{
+			"read-only delete",
+			roPort,
+			http.MethodDelete,
+			"/v1/tasks/task",
+			http.StatusMethodNotAllowed,
+		},
+		{
+			"admin get",
+			port,
+			http.MethodGet,
+			healthPath,
+			http.StatusOK,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			u := fmt.Sprintf("http://127.0.0.1:%d%s", tc.port, tc.path)
+			req, err := http.NewRequest(tc.method, u, nil)
+			require.NoError(t, err)
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tc.statusCode, resp.StatusCode)
+		})
+	}
+}
+
 func TestServe_LoggingExclusions(t *testing.T) {
 	port := testutils.FreePort(t)
 	checker := new(mockHealth.Checker)
@@ -330,7 +530,8 @@ func TestServeWithTLS(t *testing.T) {
 
 	ctrl := new(mocks.Server)
 	ctrl.On("Tasks", mock.Anything).Return(config.TaskConfigs{}).
-		On("Events", mock.Anything, "").Return(map[string][]event.Event{}, nil)
+		On("Events", mock.Anything, "").Return(map[string][]event.Event{}, nil).
+		On("DependencyStats", mock.Anything).Return(templates.DependencyStats{})
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -437,7 +638,8 @@ func TestServeWithMutualTLS(t *testing.T) {
 	}
 	ctrl := new(mocks.Server)
 	ctrl.On("Tasks", mock.Anything).Return(config.TaskConfigs{}).
-		On("Events", mock.Anything, "").Return(map[string][]event.Event{}, nil)
+		On("Events", mock.Anything, "").Return(map[string][]event.Event{}, nil).
+		On("DependencyStats", mock.Anything).Return(templates.DependencyStats{})
 	api, err := NewAPI(ctx, Config{
 		Controller: ctrl,
 		Port:       port,
@@ -567,7 +769,8 @@ func TestServeWithMutualTLS_MultipleCA(t *testing.T) {
 	}
 	ctrl := new(mocks.Server)
 	ctrl.On("Tasks", mock.Anything).Return(config.TaskConfigs{}).
-		On("Events", mock.Anything, "").Return(map[string][]event.Event{}, nil)
+		On("Events", mock.Anything, "").Return(map[string][]event.Event{}, nil).
+		On("DependencyStats", mock.Anything).Return(templates.DependencyStats{})
 	api, err := NewAPI(ctx, Config{
 		Controller: ctrl,
 		Port:       port,
@@ -4,9 +4,12 @@
 package api
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
@@ -19,15 +22,20 @@ import (
 
 //go:generate mockery --name=httpClient --structname=HttpClient --output=../mocks/api
 
+// unixSocketHost is the placeholder host used for requests made over a unix
+// socket. The host is not used to connect but is required for a valid request.
+const unixSocketHost = "localhost"
+
 const (
 	HTTPScheme  = "http"
 	HTTPSScheme = "https"
+	UnixScheme  = "unix"
 
 	DefaultURL       = "http://localhost:8558"
 	DefaultSSLVerify = true
 
 	// Environment variable names
-	EnvAddress = "CTS_ADDRESS" // The address of the CTS daemon, supports http or https by specifying as part of the address (e.g. https://localhost:8558)
+	EnvAddress = "CTS_ADDRESS" // The address of the CTS daemon, supports http, https, or a unix socket by specifying as part of the address (e.g. https://localhost:8558, unix:///var/run/cts.sock)
 
 	// TLS environment variable names
 	EnvTLSCACert     = "CTS_CACERT"      // Path to a directory of CA certificates to use for TLS when communicating with Consul-Terraform-Sync
@@ -35,8 +43,21 @@ const (
 	EnvTLSClientCert = "CTS_CLIENT_CERT" // Path to a client cert file to use for TLS when verify_incoming is enabled
 	EnvTLSClientKey  = "CTS_CLIENT_KEY"  // Path to a client key file to use for TLS when verify_incoming is enabled
 	EnvTLSSSLVerify  = "CTS_SSL_VERIFY"  // Boolean to verify SSL or not. Set to true to verify SSL. Default is true
+
+	// EnvToken is the environment variable name for the token to send as a
+	// bearer token with each request, e.g. when CTS is behind an
+	// authenticating proxy
+	EnvToken = "CTS_TOKEN"
 )
 
+// setAuthorization sets the token as a bearer token on the request. No-op if
+// the token is empty.
+func setAuthorization(req *http.Request, token string) {
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
 // httpClient describes the interface for the client to make http calls
 type httpClient interface {
 	Do(req *http.Request) (*http.Response, error)
@@ -47,12 +68,17 @@ type Client struct {
 	version string
 	url     *url.URL
 	http    httpClient
+	token   string
 }
 
 // ClientConfig configures the client to make api requests
 type ClientConfig struct {
 	URL       string
 	TLSConfig TLSConfig
+
+	// Token is sent as a bearer token in the Authorization header of each
+	// request when set
+	Token string
 }
 
 type TLSConfig struct {
@@ -98,13 +124,22 @@ func BaseClientConfig() *ClientConfig {
 		}
 	}
 
+	if value, found := os.LookupEnv(EnvToken); found {
+		c.Token = value
+	}
+
 	return c
 }
 
 // NewClient returns a client to make api requests
 func NewClient(c *ClientConfig, httpClient httpClient) (*Client, error) {
+	u, socketPath, err := parseURL(c.URL)
+	if err != nil {
+		return nil, err
+	}
+
 	if httpClient == nil {
-		h, err := newHTTPClient(&c.TLSConfig)
+		h, err := newHTTPClient(&c.TLSConfig, socketPath)
 		if err != nil {
 			return nil, err
 		}
@@ -112,21 +147,19 @@ func NewClient(c *ClientConfig, httpClient httpClient) (*Client, error) {
 		httpClient = h
 	}
 
-	u, err := parseURL(c.URL)
-	if err != nil {
-		return nil, err
-	}
-
 	client := &Client{
 		version: defaultAPIVersion,
 		url:     u,
 		http:    httpClient,
+		token:   c.Token,
 	}
 
 	return client, nil
 }
 
-func newHTTPClient(tc *TLSConfig) (*http.Client, error) {
+// newHTTPClient returns a client configured with the TLS configuration. If a
+// socket path is provided, the client connects over the unix socket.
+func newHTTPClient(tc *TLSConfig, socketPath string) (*http.Client, error) {
 	tlsClientConfig := &tls.Config{
 		// If verify is false, then we set skip verify to true
 		// InsecureSkipVerify will always be the opposite of SSLVerify
@@ -155,7 +188,15 @@ func newHTTPClient(tc *TLSConfig) (*http.Client, error) {
 		}
 	}
 
-	h := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsClientConfig}}
+	transport := &http.Transport{TLSClientConfig: tlsClientConfig}
+	if socketPath != "" {
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socketPath)
+		}
+	}
+
+	h := &http.Client{Transport: transport}
 	return h, nil
 }
 
@@ -266,6 +307,7 @@ func (c *Client) request(method, path, query, body string) (*http.Response, erro
 	if err != nil {
 		return nil, err
 	}
+	setAuthorization(req, c.token)
 
 	resp, err := c.http.Do(req)
 	if err != nil {
@@ -277,18 +319,24 @@ func (c *Client) request(method, path, query, body string) (*http.Response, erro
 	if resp.StatusCode >= 400 {
 		defer resp.Body.Close()
 
-		var errResp ErrorResponse
-		decoder := json.NewDecoder(resp.Body)
-		if err = decoder.Decode(&errResp); err != nil {
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
 			return nil, err
 		}
 
-		if msg, ok := errResp.ErrorMessage(); ok && msg != "" {
-			return nil, fmt.Errorf("request returned %d status code with error: %s",
-				resp.StatusCode, msg)
+		respErr := &ResponseError{StatusCode: resp.StatusCode}
+
+		var errResp ErrorResponse
+		if err = json.Unmarshal(b, &errResp); err != nil {
+			// Errors that are not from a CTS handler are plain text, e.g. the
+			// not found error of a daemon that does not have the endpoint
+			respErr.Message = strings.TrimSpace(string(b))
+			return nil, respErr
 		}
 
-		return nil, fmt.Errorf("request returned %d status code", resp.StatusCode)
+		respErr.Message, _ = errResp.ErrorMessage()
+		respErr.Code, _ = errResp.ErrorCode()
+		return nil, respErr
 	}
 
 	return resp, nil
@@ -296,17 +344,25 @@ func (c *Client) request(method, path, query, body string) (*http.Response, erro
 
 // QueryParam sets query parameters for the api client
 type QueryParam struct {
-	IncludeEvents bool
-	Status        string
-	Run           string
+	IncludeEvents       bool
+	IncludeDependencies bool
+	Status              string
+	Run                 string
 }
 
 // Encode returns QueryParameter values as a URL encoded string. No preceding '?'
 // e.g. "include=events&status=critical"
 func (q *QueryParam) Encode() string {
 	val := url.Values{}
+	var include []string
 	if q.IncludeEvents {
-		val.Set("include", "events") // refactor this out?
+		include = append(include, "events")
+	}
+	if q.IncludeDependencies {
+		include = append(include, "dependencies")
+	}
+	if len(include) > 0 {
+		val.Set("include", strings.Join(include, ","))
 	}
 
 	if q.Status != "" {
@@ -415,20 +471,144 @@ func (t *TaskClient) Update(name string, config UpdateTaskConfig, q *QueryParam)
 	return plan, nil
 }
 
-func parseURL(urlString string) (*url.URL, error) {
+// Batch is used to apply a bulk operation to tasks
+func (t *TaskClient) Batch(req TaskBatchRequest) (TaskBatchResponse, error) {
+	b, err := json.Marshal(req)
+	if err != nil {
+		return TaskBatchResponse{}, err
+	}
+
+	path := fmt.Sprintf("%s/%s", taskPath, taskBatchPath)
+	resp, err := t.request(http.MethodPost, path, "", string(b))
+	if err != nil {
+		return TaskBatchResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+	var batchResp TaskBatchResponse
+	if err = decoder.Decode(&batchResp); err != nil {
+		return TaskBatchResponse{}, err
+	}
+
+	return batchResp, nil
+}
+
+// Render is used to render the template of a task with the latest data of
+// its dependencies without running Terraform
+func (t *TaskClient) Render(name string) (TaskRenderResponse, error) {
+	path := fmt.Sprintf("%s/%s/%s", taskPath, name, taskRenderPath)
+	resp, err := t.request(http.MethodPost, path, "", "")
+	if err != nil {
+		return TaskRenderResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+	var renderResp TaskRenderResponse
+	if err = decoder.Decode(&renderResp); err != nil {
+		return TaskRenderResponse{}, err
+	}
+
+	return renderResp, nil
+}
+
+// InputsDiff is used to get the difference between the input variables
+// applied by two runs of a task. The runs are identified by their event IDs.
+// The latest run is used when to is empty, and the run preceding to is used
+// when from is empty.
+func (t *TaskClient) InputsDiff(name, from, to string) (TaskInputsDiffResponse, error) {
+	val := url.Values{}
+	if from != "" {
+		val.Set(taskInputsDiffFromParam, from)
+	}
+	if to != "" {
+		val.Set(taskInputsDiffToParam, to)
+	}
+
+	path := fmt.Sprintf("%s/%s/%s/%s", taskPath, name, taskInputsPath, taskInputsDiffPath)
+	resp, err := t.request(http.MethodGet, path, val.Encode(), "")
+	if err != nil {
+		return TaskInputsDiffResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+	var diffResp TaskInputsDiffResponse
+	if err = decoder.Decode(&diffResp); err != nil {
+		return TaskInputsDiffResponse{}, err
+	}
+
+	return diffResp, nil
+}
+
+// Reinit is used to re-initialize a task without restarting CTS, e.g. after
+// the files of its local module change on disk. Terraform init and validate
+// are re-run and the template of the task is rendered.
+func (t *TaskClient) Reinit(name string) (TaskReinitResponse, error) {
+	path := fmt.Sprintf("%s/%s/%s", taskPath, name, taskReinitPath)
+	resp, err := t.request(http.MethodPost, path, "", "")
+	if err != nil {
+		return TaskReinitResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(resp.Body)
+	var reinitResp TaskReinitResponse
+	if err = decoder.Decode(&reinitResp); err != nil {
+		return TaskReinitResponse{}, err
+	}
+
+	return reinitResp, nil
+}
+
+// Version is used to query for the version and the supported features of
+// the CTS daemon. Daemons that are older than the version endpoint return a
+// ResponseError with the not found status code.
+func (c *Client) Version() (VersionResponse, error) {
+	resp, err := c.request(http.MethodGet, versionPath, "", "")
+	if err != nil {
+		return VersionResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	var v VersionResponse
+	decoder := json.NewDecoder(resp.Body)
+	if err = decoder.Decode(&v); err != nil {
+		return VersionResponse{}, err
+	}
+
+	return v, nil
+}
+
+// parseURL parses and validates the address of the CTS daemon. For unix socket
+// addresses, e.g. unix:///var/run/cts.sock, it returns an http URL to make
+// requests with and the path to the socket to connect to.
+func parseURL(urlString string) (*url.URL, string, error) {
 	u, err := url.ParseRequestURI(urlString)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	// validations
+	if u.Scheme == UnixScheme {
+		if u.Host != "" {
+			return nil, "", fmt.Errorf("invalid unix socket address, must be "+
+				"an absolute path of the format unix:///path/to/socket: %s", urlString)
+		}
+		if u.Path == "" {
+			return nil, "", fmt.Errorf("invalid unix socket address, socket path is empty")
+		}
+		return &url.URL{Scheme: HTTPScheme, Host: unixSocketHost}, u.Path, nil
+	}
+
 	if u.Scheme != HTTPSScheme && u.Scheme != HTTPScheme {
-		return nil, fmt.Errorf("unknown protocol scheme: %s", u.Scheme)
+		return nil, "", fmt.Errorf("unknown protocol scheme: %s", u.Scheme)
 	}
 
 	if u.Host == "" {
-		return nil, fmt.Errorf("invalid address, host value is empty")
+		return nil, "", fmt.Errorf("invalid address, host value is empty")
 	}
 
-	return u, nil
+	return u, "", nil
 }
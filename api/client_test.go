@@ -5,6 +5,7 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -14,6 +15,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hashicorp/consul-terraform-sync/config"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/stretchr/testify/require"
@@ -50,6 +52,7 @@ func Test_BaseClientConfig_WithEnvVars(t *testing.T) {
 		_ = os.Unsetenv(EnvTLSClientCert)
 		_ = os.Unsetenv(EnvTLSClientKey)
 		_ = os.Unsetenv(EnvTLSSSLVerify)
+		_ = os.Unsetenv(EnvToken)
 	})
 
 	urlString := "https://1.2.3.4:5678"
@@ -65,6 +68,7 @@ func Test_BaseClientConfig_WithEnvVars(t *testing.T) {
 	require.NoError(t, os.Setenv(EnvTLSClientCert, clientCert))
 	require.NoError(t, os.Setenv(EnvTLSClientKey, clientKey))
 	require.NoError(t, os.Setenv(EnvTLSSSLVerify, sslVerify))
+	require.NoError(t, os.Setenv(EnvToken, "secret"))
 
 	clientConfig := BaseClientConfig()
 
@@ -77,6 +81,24 @@ func Test_BaseClientConfig_WithEnvVars(t *testing.T) {
 	expectedSSLVerify, err := strconv.ParseBool(sslVerify)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedSSLVerify, clientConfig.TLSConfig.SSLVerify)
+	assert.Equal(t, "secret", clientConfig.Token)
+}
+
+func Test_Client_Token(t *testing.T) {
+	var auth string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	c, err := NewClient(&ClientConfig{URL: ts.URL, Token: "secret"}, nil)
+	require.NoError(t, err)
+
+	resp, err := c.request(http.MethodGet, "status", "", "")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "Bearer secret", auth)
 }
 
 func Test_Client_Port(t *testing.T) {
@@ -133,6 +155,16 @@ func Test_NewClient(t *testing.T) {
 	})
 }
 
+func Test_NewClient_UnixSocket(t *testing.T) {
+	clientConfig := BaseClientConfig()
+	clientConfig.URL = "unix:///var/run/cts.sock"
+	c, err := NewClient(clientConfig, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, HTTPScheme, c.Scheme())
+	assert.Equal(t, "http://localhost", c.FullAddress())
+}
+
 func Test_NewClient_Error_URL(t *testing.T) {
 	tests := []struct {
 		name string
@@ -150,6 +182,14 @@ func Test_NewClient_Error_URL(t *testing.T) {
 			name: "invalid host",
 			cc:   &ClientConfig{URL: "http://"},
 		},
+		{
+			name: "unix socket missing path",
+			cc:   &ClientConfig{URL: "unix://"},
+		},
+		{
+			name: "unix socket relative path",
+			cc:   &ClientConfig{URL: "unix://cts.sock"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -257,6 +297,11 @@ func Test_QueryParam_Encode(t *testing.T) {
 			queryParams: &QueryParam{Status: "foo", Run: "bar", IncludeEvents: true},
 			want:        "include=events&run=bar&status=foo",
 		},
+		{
+			name:        "include events and dependencies",
+			queryParams: &QueryParam{IncludeEvents: true, IncludeDependencies: true},
+			want:        "include=events%2Cdependencies",
+		},
 	}
 
 	for _, tt := range tests {
@@ -328,6 +373,35 @@ func Test_StatusClient_Overall_Failures(t *testing.T) {
 	}
 }
 
+func Test_TaskClient_Update_ErrorCode(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		e := &ErrorResponse{Error: &ErrorObject{
+			Message: "task 'foo' is active and cannot be updated at this time",
+			Code:    ErrorCodeTaskActive,
+		}}
+		w.WriteHeader(http.StatusInternalServerError)
+		assert.NoError(t, json.NewEncoder(w).Encode(e))
+	}))
+	t.Cleanup(server.Close)
+
+	clientConfig := BaseClientConfig()
+	clientConfig.URL = server.URL
+	c, err := NewClient(clientConfig, nil)
+	require.NoError(t, err)
+
+	_, err = c.Task().Update("foo", UpdateTaskConfig{Enabled: config.Bool(true)}, nil)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrTaskActive))
+	assert.False(t, errors.Is(err, ErrTaskNotFound))
+
+	var respErr *ResponseError
+	require.True(t, errors.As(err, &respErr))
+	assert.Equal(t, http.StatusInternalServerError, respErr.StatusCode)
+	assert.Equal(t, ErrorCodeTaskActive, respErr.Code)
+}
+
 func Test_StatusClient_Overall(t *testing.T) {
 	expectedOverallStatus := OverallStatus{TaskSummary: TaskSummary{
 		Status:  StatusSummary{Successful: 1},
@@ -355,6 +429,49 @@ func Test_StatusClient_Overall(t *testing.T) {
 	assert.Equal(t, expectedOverallStatus, o)
 }
 
+func Test_Client_Version(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/v1/version", r.URL.Path)
+			fmt.Fprint(w, `{"version":"0.7.1","api_version":"v1",`+
+				`"features":["task_batch"],"request_id":"bb63cd70-8f45-4f42-b27b-bc2a6f4931e6"}`)
+		}))
+		t.Cleanup(server.Close)
+
+		clientConfig := BaseClientConfig()
+		clientConfig.URL = server.URL
+		c, err := NewClient(clientConfig, nil)
+		require.NoError(t, err)
+
+		v, err := c.Version()
+		require.NoError(t, err)
+		assert.Equal(t, "0.7.1", v.Version)
+		assert.Equal(t, "v1", v.ApiVersion)
+		assert.True(t, v.HasFeature(FeatureTaskBatch))
+		assert.False(t, v.HasFeature(FeatureTaskFiltering))
+	})
+
+	t.Run("endpoint not found", func(t *testing.T) {
+		// daemons that are older than the version endpoint return the plain
+		// text not found error of the router
+		server := httptest.NewServer(http.NotFoundHandler())
+		t.Cleanup(server.Close)
+
+		clientConfig := BaseClientConfig()
+		clientConfig.URL = server.URL
+		c, err := NewClient(clientConfig, nil)
+		require.NoError(t, err)
+
+		_, err = c.Version()
+		var respErr *ResponseError
+		require.True(t, errors.As(err, &respErr))
+		assert.Equal(t, http.StatusNotFound, respErr.StatusCode)
+		assert.Equal(t, "404 page not found", respErr.Message)
+	})
+}
+
 func Test_WaitForTestReadiness_success(t *testing.T) {
 	expected := map[string]TaskStatus{
 		"task_a": {Enabled: true, Status: StatusCritical},
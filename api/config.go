@@ -0,0 +1,56 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/consul-terraform-sync/logging"
+)
+
+const (
+	configPath          = "config"
+	configSubsystemName = "config"
+)
+
+// configHandler handles the config endpoint
+type configHandler struct {
+	ctrl    Server
+	version string
+}
+
+// newConfigHandler returns a new config handler
+func newConfigHandler(ctrl Server, version string) *configHandler {
+	return &configHandler{
+		ctrl:    ctrl,
+		version: version,
+	}
+}
+
+// ServeHTTP serves the config endpoint which returns the effective
+// configuration of CTS after merging the configuration files and applying
+// defaults. The tasks are the current tasks, which includes tasks created
+// through the API. Sensitive information is redacted.
+func (h *configHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.FromContext(ctx).Named(configSubsystemName)
+	logger.Trace("requesting config", "url_path", r.URL.Path)
+
+	switch r.Method {
+	case http.MethodGet:
+		conf := h.ctrl.Config()
+		tasks := h.ctrl.Tasks(ctx)
+		conf.Tasks = &tasks
+
+		if err := jsonResponse(w, http.StatusOK, conf.Redacted()); err != nil {
+			logger.Error("error, could not generate json response", "error", err)
+		}
+	default:
+		err := fmt.Errorf("'%s' in an unsupported method. The config API "+
+			"currently supports the method(s): '%s'", r.Method, http.MethodGet)
+		logger.Trace("unsupported method: %s", err)
+		jsonErrorResponse(ctx, w, http.StatusMethodNotAllowed, err)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	mocks "github.com/hashicorp/consul-terraform-sync/mocks/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	conf := config.Config{
+		LogLevel: config.String("INFO"),
+		Port:     config.Int(8558),
+		Consul: &config.ConsulConfig{
+			Address: config.String("localhost:8500"),
+			Token:   config.String("consul-token"),
+		},
+		Tasks: &config.TaskConfigs{
+			{Name: config.String("task_a")},
+		},
+	}
+
+	// tasks of the controller include tasks created through the API
+	tasks := config.TaskConfigs{
+		{Name: config.String("task_a")},
+		{
+			Name:        config.String("task_b"),
+			ConsulToken: config.String("task-token"),
+		},
+	}
+
+	ctrl := new(mocks.Server)
+	ctrl.On("Config").Return(conf)
+	ctrl.On("Tasks", mock.Anything).Return(tasks)
+	handler := newConfigHandler(ctrl, "v1")
+
+	t.Run("get", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/v1/config", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+
+		handler.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var actual map[string]interface{}
+		err = json.NewDecoder(resp.Body).Decode(&actual)
+		require.NoError(t, err)
+
+		assert.Equal(t, "INFO", actual["log_level"])
+		assert.Equal(t, float64(8558), actual["port"])
+
+		consul, ok := actual["consul"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "localhost:8500", consul["address"])
+		assert.Equal(t, "(redacted)", consul["token"])
+
+		taskList, ok := actual["task"].([]interface{})
+		require.True(t, ok)
+		require.Len(t, taskList, 2)
+		taskB, ok := taskList[1].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "task_b", taskB["name"])
+		assert.Equal(t, "(redacted)", taskB["consul_token"])
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, "/v1/config", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+
+		handler.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	})
+}
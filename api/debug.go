@@ -0,0 +1,75 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/logging"
+)
+
+const (
+	debugDumpPath          = "debug/dump"
+	debugDumpSubsystemName = "debugdump"
+)
+
+// DiagnosticsFilename returns the name of the diagnostics bundle created at
+// the time
+func DiagnosticsFilename(t time.Time) string {
+	return fmt.Sprintf("cts-diagnostics-%s.tar.gz", t.UTC().Format("20060102T150405Z"))
+}
+
+// debugDumpHandler handles the debug dump endpoint
+type debugDumpHandler struct {
+	ctrl    Server
+	version string
+}
+
+// newDebugDumpHandler returns a new debug dump handler
+func newDebugDumpHandler(ctrl Server, version string) *debugDumpHandler {
+	return &debugDumpHandler{
+		ctrl:    ctrl,
+		version: version,
+	}
+}
+
+// ServeHTTP serves the debug dump endpoint which returns a gzipped tarball of
+// internal diagnostics for support, including the goroutine stacks, the
+// watched dependencies, the state of the drivers, the buffered triggers, and
+// the recent events of the tasks.
+func (h *debugDumpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.FromContext(ctx).Named(debugDumpSubsystemName)
+	logger.Trace("requesting debug dump", "url_path", r.URL.Path)
+
+	switch r.Method {
+	case http.MethodGet:
+		// Write the bundle to a buffer first so that an error can still be
+		// returned as a JSON response
+		var buf bytes.Buffer
+		if err := h.ctrl.WriteDiagnostics(ctx, &buf); err != nil {
+			logger.Error("error writing diagnostics", "error", err)
+			jsonErrorResponse(ctx, w, http.StatusInternalServerError, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+		w.Header().Set("Content-Disposition", fmt.Sprintf(
+			"attachment; filename=%q", DiagnosticsFilename(time.Now())))
+		w.WriteHeader(http.StatusOK)
+		if _, err := buf.WriteTo(w); err != nil {
+			logger.Error("error writing debug dump response", "error", err)
+		}
+	default:
+		err := fmt.Errorf("'%s' in an unsupported method. The debug dump API "+
+			"currently supports the method(s): '%s'", r.Method, http.MethodGet)
+		logger.Trace("unsupported method: %s", err)
+		jsonErrorResponse(ctx, w, http.StatusMethodNotAllowed, err)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mocks "github.com/hashicorp/consul-terraform-sync/mocks/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugDump_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	t.Run("dump", func(t *testing.T) {
+		ctrl := new(mocks.Server)
+		ctrl.On("WriteDiagnostics", mock.Anything, mock.Anything).Return(nil).
+			Run(func(args mock.Arguments) {
+				args.Get(1).(io.Writer).Write([]byte("bundle"))
+			})
+		handler := newDebugDumpHandler(ctrl, "v1")
+
+		req, err := http.NewRequest(http.MethodGet, "/v1/debug/dump", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+
+		handler.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "application/gzip", resp.Header().Get("Content-Type"))
+		assert.Contains(t, resp.Header().Get("Content-Disposition"), "cts-diagnostics-")
+		assert.Equal(t, "bundle", resp.Body.String())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl := new(mocks.Server)
+		ctrl.On("WriteDiagnostics", mock.Anything, mock.Anything).
+			Return(errors.New("error"))
+		handler := newDebugDumpHandler(ctrl, "v1")
+
+		req, err := http.NewRequest(http.MethodGet, "/v1/debug/dump", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+
+		handler.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+		assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		handler := newDebugDumpHandler(new(mocks.Server), "v1")
+		req, err := http.NewRequest(http.MethodPost, "/v1/debug/dump", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+
+		handler.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	})
+}
+
+func TestDiagnosticsFilename(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.Equal(t, "cts-diagnostics-20220102T030405Z.tar.gz", DiagnosticsFilename(now))
+}
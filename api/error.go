@@ -3,9 +3,46 @@
 
 package api
 
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/consul-terraform-sync/driver"
+)
+
+// Error codes are the stable, machine-readable codes of the errors returned
+// by the API server. Unlike error messages, codes do not change between
+// releases and can be used by clients to handle specific errors.
+const (
+	ErrorCodeBadRequest         = "bad_request"
+	ErrorCodeValidationFailed   = "validation_failed"
+	ErrorCodeUnauthorized       = "unauthorized"
+	ErrorCodeNotFound           = "not_found"
+	ErrorCodeTaskNotFound       = "task_not_found"
+	ErrorCodeTaskExists         = "task_exists"
+	ErrorCodeMethodNotAllowed   = "method_not_allowed"
+	ErrorCodeConflict           = "conflict"
+	ErrorCodeTaskActive         = "task_active"
+	ErrorCodeTaskLocked         = "task_locked"
+	ErrorCodeIdempotencyKeyUsed = "idempotency_key_used"
+	ErrorCodeInternal           = "internal_error"
+)
+
+// Errors returned by the API client for error responses with a known error
+// code. Use errors.Is to check the error returned by the client.
+var (
+	ErrTaskNotFound     = errors.New("task not found")
+	ErrTaskExists       = errors.New("task already exists")
+	ErrTaskActive       = errors.New("task is active")
+	ErrTaskLocked       = errors.New("task is locked")
+	ErrValidationFailed = errors.New("validation failed")
+)
+
 // ErrorObject is the object to represent an error object from the API server
 type ErrorObject struct {
 	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
 }
 
 // ErrorResponse is the object to represent an error response from the API server
@@ -30,3 +67,97 @@ func (resp ErrorResponse) ErrorMessage() (string, bool) {
 
 	return resp.Error.Message, true
 }
+
+// ErrorCode returns the error code if there is an error with a code.
+func (resp ErrorResponse) ErrorCode() (string, bool) {
+	if resp.Error == nil || resp.Error.Code == "" {
+		return "", false
+	}
+
+	return resp.Error.Code, true
+}
+
+// ResponseError is the error returned by the API client when the API server
+// responds with an error status code
+type ResponseError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *ResponseError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("request returned %d status code", e.StatusCode)
+	}
+	return fmt.Sprintf("request returned %d status code with error: %s",
+		e.StatusCode, e.Message)
+}
+
+// Is maps the error code of the response to the client errors
+func (e *ResponseError) Is(target error) bool {
+	switch e.Code {
+	case ErrorCodeTaskNotFound:
+		return target == ErrTaskNotFound
+	case ErrorCodeTaskExists:
+		return target == ErrTaskExists
+	case ErrorCodeTaskActive:
+		return target == ErrTaskActive
+	case ErrorCodeTaskLocked:
+		return target == ErrTaskLocked
+	case ErrorCodeValidationFailed:
+		return target == ErrValidationFailed
+	default:
+		return false
+	}
+}
+
+// codedError is an error with an explicit error code for the API response
+type codedError struct {
+	code string
+	err  error
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+// withErrorCode sets the error code to respond with for an error. Errors
+// without an explicit code respond with the default code of the status code.
+func withErrorCode(code string, err error) error {
+	return &codedError{code: code, err: err}
+}
+
+// errorCode returns the error code of an error response
+func errorCode(status int, err error) string {
+	var ce *codedError
+	if errors.As(err, &ce) {
+		return ce.code
+	}
+	if errors.Is(err, driver.ErrTaskActive) {
+		return ErrorCodeTaskActive
+	}
+	if errors.Is(err, driver.ErrTaskLocked) {
+		return ErrorCodeTaskLocked
+	}
+
+	switch status {
+	case http.StatusBadRequest:
+		return ErrorCodeBadRequest
+	case http.StatusUnauthorized:
+		return ErrorCodeUnauthorized
+	case http.StatusNotFound:
+		return ErrorCodeNotFound
+	case http.StatusMethodNotAllowed:
+		return ErrorCodeMethodNotAllowed
+	case http.StatusConflict:
+		return ErrorCodeConflict
+	case http.StatusUnprocessableEntity:
+		return ErrorCodeValidationFailed
+	default:
+		return ErrorCodeInternal
+	}
+}
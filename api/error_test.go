@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCode(t *testing.T) {
+	t.Parallel()
+
+	testErr := errors.New("error")
+	cases := []struct {
+		name     string
+		status   int
+		err      error
+		expected string
+	}{
+		{
+			"bad request",
+			http.StatusBadRequest,
+			testErr,
+			ErrorCodeBadRequest,
+		},
+		{
+			"not found",
+			http.StatusNotFound,
+			testErr,
+			ErrorCodeNotFound,
+		},
+		{
+			"method not allowed",
+			http.StatusMethodNotAllowed,
+			nil,
+			ErrorCodeMethodNotAllowed,
+		},
+		{
+			"internal error",
+			http.StatusServiceUnavailable,
+			testErr,
+			ErrorCodeInternal,
+		},
+		{
+			"explicit code",
+			http.StatusNotFound,
+			withErrorCode(ErrorCodeTaskNotFound, testErr),
+			ErrorCodeTaskNotFound,
+		},
+		{
+			"wrapped explicit code",
+			http.StatusBadRequest,
+			fmt.Errorf("wrapped: %w",
+				withErrorCode(ErrorCodeValidationFailed, testErr)),
+			ErrorCodeValidationFailed,
+		},
+		{
+			"task active",
+			http.StatusInternalServerError,
+			&driver.TaskActiveError{Name: "task", Action: "updated"},
+			ErrorCodeTaskActive,
+		},
+		{
+			"task locked",
+			http.StatusConflict,
+			fmt.Errorf("task 'task' is locked: %w", driver.ErrTaskLocked),
+			ErrorCodeTaskLocked,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, errorCode(tc.status, tc.err))
+		})
+	}
+}
+
+func TestResponseError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("message", func(t *testing.T) {
+		err := &ResponseError{StatusCode: http.StatusNotFound, Message: "foo"}
+		assert.Equal(t, "request returned 404 status code with error: foo", err.Error())
+
+		err = &ResponseError{StatusCode: http.StatusInternalServerError}
+		assert.Equal(t, "request returned 500 status code", err.Error())
+	})
+
+	t.Run("typed errors", func(t *testing.T) {
+		cases := []struct {
+			code     string
+			expected error
+		}{
+			{ErrorCodeTaskNotFound, ErrTaskNotFound},
+			{ErrorCodeTaskExists, ErrTaskExists},
+			{ErrorCodeTaskActive, ErrTaskActive},
+			{ErrorCodeTaskLocked, ErrTaskLocked},
+			{ErrorCodeValidationFailed, ErrValidationFailed},
+		}
+		for _, tc := range cases {
+			var err error = &ResponseError{StatusCode: http.StatusBadRequest, Code: tc.code}
+			for _, target := range []error{ErrTaskNotFound, ErrTaskExists, ErrTaskActive, ErrTaskLocked, ErrValidationFailed} {
+				assert.Equal(t, target == tc.expected, errors.Is(err, target),
+					"code %q, target %q", tc.code, target)
+			}
+		}
+
+		var err error = &ResponseError{StatusCode: http.StatusNotFound, Code: ErrorCodeNotFound}
+		assert.False(t, errors.Is(err, ErrTaskNotFound))
+	})
+}
@@ -0,0 +1,419 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+)
+
+const (
+	graphPath          = "status/graph"
+	graphSubsystemName = "graph"
+
+	// graphFormatParam is the query parameter to select the format of the
+	// returned graph
+	graphFormatParam = "format"
+
+	graphFormatJSON = "json"
+	graphFormatDOT  = "dot"
+)
+
+const (
+	// GraphNodeTypeTask is the type of a node that represents a task
+	GraphNodeTypeTask = "task"
+
+	// GraphNodeTypeDependency is the type of a node that represents an object
+	// monitored by tasks, e.g. a Consul service or a Consul KV path
+	GraphNodeTypeDependency = "dependency"
+
+	// GraphEdgeTypeMonitors is the type of an edge from a task to a dependency
+	// that the task monitors
+	GraphEdgeTypeMonitors = "monitors"
+
+	// GraphEdgeTypeSharedDependency is the type of an edge between two tasks
+	// that monitor one or more of the same dependencies. A change to a shared
+	// dependency triggers both tasks.
+	GraphEdgeTypeSharedDependency = "shared_dependency"
+)
+
+// TaskGraph is the graph of tasks and the dependencies that they monitor
+type TaskGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is a task or a dependency in the task graph
+type GraphNode struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Name string `json:"name"`
+
+	// Kind is the kind of object monitored for a dependency node, e.g.
+	// service, consul-kv. Empty for task nodes.
+	Kind string `json:"kind,omitempty"`
+
+	// Enabled is whether the task is enabled. Only set for task nodes.
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// GraphEdge is a relationship between two nodes in the task graph
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"`
+
+	// Dependencies are the IDs of the dependency nodes shared by the two
+	// tasks of a shared_dependency edge
+	Dependencies []string `json:"dependencies,omitempty"`
+}
+
+// graphHandler handles the task graph endpoint
+type graphHandler struct {
+	ctrl    Server
+	version string
+}
+
+// newGraphHandler returns a new task graph handler
+func newGraphHandler(ctrl Server, version string) *graphHandler {
+	return &graphHandler{
+		ctrl:    ctrl,
+		version: version,
+	}
+}
+
+// ServeHTTP serves the task graph endpoint which returns the graph of tasks,
+// their monitored dependencies, and the dependencies shared between tasks.
+// The graph is returned as JSON by default and in the DOT language when the
+// format query parameter is set to dot.
+func (h *graphHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.FromContext(ctx).Named(graphSubsystemName)
+	logger.Trace("requesting task graph", "url_path", r.URL.Path)
+
+	switch r.Method {
+	case http.MethodGet:
+		format := r.URL.Query().Get(graphFormatParam)
+		switch format {
+		case "", graphFormatJSON, graphFormatDOT:
+		default:
+			err := fmt.Errorf("unsupported graph format '%s'. The format must "+
+				"be one of: '%s', '%s'", format, graphFormatJSON, graphFormatDOT)
+			logger.Trace("bad request", "error", err)
+			jsonErrorResponse(ctx, w, http.StatusBadRequest, err)
+			return
+		}
+
+		graph := newTaskGraph(h.ctrl.Tasks(ctx))
+
+		if format == graphFormatDOT {
+			w.Header().Set("Content-Type", "text/vnd.graphviz")
+			w.WriteHeader(http.StatusOK)
+			w.Write(graph.DOT())
+			return
+		}
+
+		if err := jsonResponse(w, http.StatusOK, graph); err != nil {
+			logger.Error("error, could not generate json response", "error", err)
+		}
+	default:
+		err := fmt.Errorf("'%s' in an unsupported method. The graph API "+
+			"currently supports the method(s): '%s'", r.Method, http.MethodGet)
+		logger.Trace("unsupported method: %s", err)
+		jsonErrorResponse(ctx, w, http.StatusMethodNotAllowed, err)
+	}
+}
+
+// newTaskGraph builds the graph of the tasks and the dependencies monitored
+// by their module inputs and conditions. Schedule conditions are not
+// dependencies and are not included in the graph.
+func newTaskGraph(tasks config.TaskConfigs) TaskGraph {
+	sorted := make(config.TaskConfigs, len(tasks))
+	copy(sorted, tasks)
+	sort.Slice(sorted, func(i, j int) bool {
+		return config.StringVal(sorted[i].Name) < config.StringVal(sorted[j].Name)
+	})
+
+	graph := TaskGraph{
+		Nodes: []GraphNode{},
+		Edges: []GraphEdge{},
+	}
+
+	depNodes := make(map[string]GraphNode)
+	taskDeps := make([][]string, len(sorted))
+	for i, task := range sorted {
+		taskID := graphTaskID(config.StringVal(task.Name))
+		graph.Nodes = append(graph.Nodes, GraphNode{
+			ID:      taskID,
+			Type:    GraphNodeTypeTask,
+			Name:    config.StringVal(task.Name),
+			Enabled: config.Bool(config.BoolVal(task.Enabled)),
+		})
+
+		for _, n := range taskDependencies(task) {
+			depNodes[n.ID] = n
+			taskDeps[i] = append(taskDeps[i], n.ID)
+			graph.Edges = append(graph.Edges, GraphEdge{
+				From: taskID,
+				To:   n.ID,
+				Type: GraphEdgeTypeMonitors,
+			})
+		}
+	}
+
+	depIDs := make([]string, 0, len(depNodes))
+	for id := range depNodes {
+		depIDs = append(depIDs, id)
+	}
+	sort.Strings(depIDs)
+	for _, id := range depIDs {
+		graph.Nodes = append(graph.Nodes, depNodes[id])
+	}
+
+	for i := range sorted {
+		for j := i + 1; j < len(sorted); j++ {
+			shared := sharedDependencies(taskDeps[i], taskDeps[j])
+			if len(shared) == 0 {
+				continue
+			}
+			graph.Edges = append(graph.Edges, GraphEdge{
+				From:         graphTaskID(config.StringVal(sorted[i].Name)),
+				To:           graphTaskID(config.StringVal(sorted[j].Name)),
+				Type:         GraphEdgeTypeSharedDependency,
+				Dependencies: shared,
+			})
+		}
+	}
+
+	return graph
+}
+
+// DOT returns the graph in the DOT language. Task nodes are boxes and
+// dependency nodes are ellipses. Disabled tasks and shared dependency edges
+// are dashed.
+func (g TaskGraph) DOT() []byte {
+	var b bytes.Buffer
+	b.WriteString("digraph \"cts\" {\n")
+	for _, n := range g.Nodes {
+		switch n.Type {
+		case GraphNodeTypeTask:
+			style := "solid"
+			if n.Enabled != nil && !*n.Enabled {
+				style = "dashed"
+			}
+			fmt.Fprintf(&b, "  %q [label=%q, shape=box, style=%s];\n",
+				n.ID, n.Name, style)
+		default:
+			fmt.Fprintf(&b, "  %q [label=%q, shape=ellipse];\n",
+				n.ID, fmt.Sprintf("%s: %s", n.Kind, n.Name))
+		}
+	}
+	for _, e := range g.Edges {
+		switch e.Type {
+		case GraphEdgeTypeSharedDependency:
+			fmt.Fprintf(&b, "  %q -> %q [dir=none, style=dashed, label=%q];\n",
+				e.From, e.To, fmt.Sprintf("%d shared", len(e.Dependencies)))
+		default:
+			fmt.Fprintf(&b, "  %q -> %q;\n", e.From, e.To)
+		}
+	}
+	b.WriteString("}\n")
+	return b.Bytes()
+}
+
+// taskDependencies returns the dependency nodes monitored by a task sorted
+// by ID
+func taskDependencies(task *config.TaskConfig) []GraphNode {
+	nodes := make(map[string]GraphNode)
+	add := func(n GraphNode) {
+		nodes[n.ID] = n
+	}
+
+	for _, name := range task.DeprecatedServices {
+		add(dependencyNode("service", name, nil))
+	}
+
+	if task.ModuleInputs != nil {
+		for _, mi := range *task.ModuleInputs {
+			for _, n := range monitorDependencies(mi) {
+				add(n)
+			}
+		}
+	}
+
+	for _, n := range monitorDependencies(task.Condition) {
+		add(n)
+	}
+
+	ids := make([]string, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	result := make([]GraphNode, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, nodes[id])
+	}
+	return result
+}
+
+// monitorDependencies returns the dependency nodes for the objects monitored
+// by a module_input or condition block
+func monitorDependencies(m config.MonitorConfig) []GraphNode {
+	switch v := m.(type) {
+	case *config.ServicesConditionConfig:
+		if v != nil {
+			return servicesDependencies(v.ServicesMonitorConfig)
+		}
+	case *config.ServicesModuleInputConfig:
+		if v != nil {
+			return servicesDependencies(v.ServicesMonitorConfig)
+		}
+	case *config.ConsulKVConditionConfig:
+		if v != nil {
+			return consulKVDependencies(v.ConsulKVMonitorConfig)
+		}
+	case *config.ConsulKVModuleInputConfig:
+		if v != nil {
+			return consulKVDependencies(v.ConsulKVMonitorConfig)
+		}
+	case *config.CatalogServicesConditionConfig:
+		if v != nil {
+			q := map[string]string{
+				"dc": config.StringVal(v.Datacenter),
+				"ns": config.StringVal(v.Namespace),
+			}
+			for k, val := range v.NodeMeta {
+				q["node_meta."+k] = val
+			}
+			return []GraphNode{dependencyNode("catalog-services",
+				config.StringVal(v.Regexp), q)}
+		}
+	case *config.DNSConditionConfig:
+		if v != nil {
+			q := map[string]string{"record_type": config.StringVal(v.RecordType)}
+			return []GraphNode{dependencyNode("dns", config.StringVal(v.Name), q)}
+		}
+	case *config.FileConditionConfig:
+		if v != nil {
+			return fileDependencies(v.FileMonitorConfig)
+		}
+	case *config.PluginConditionConfig:
+		if v != nil {
+			return []GraphNode{dependencyNode("plugin", config.StringVal(v.Command), nil)}
+		}
+	case *config.FileModuleInputConfig:
+		if v != nil {
+			return fileDependencies(v.FileMonitorConfig)
+		}
+	}
+	return nil
+}
+
+// fileDependencies returns a dependency node for each local path monitored
+func fileDependencies(c config.FileMonitorConfig) []GraphNode {
+	nodes := make([]GraphNode, 0, len(c.Paths))
+	for _, p := range c.Paths {
+		nodes = append(nodes, dependencyNode("file", p, nil))
+	}
+	return nodes
+}
+
+// servicesDependencies returns a dependency node for each service name
+// monitored and a node for the services regexp, if configured
+func servicesDependencies(c config.ServicesMonitorConfig) []GraphNode {
+	q := map[string]string{
+		"dc":     config.StringVal(c.Datacenter),
+		"ns":     config.StringVal(c.Namespace),
+		"filter": config.StringVal(c.Filter),
+	}
+
+	if len(c.Names) == 0 {
+		return []GraphNode{dependencyNode("services-regexp",
+			config.StringVal(c.Regexp), q)}
+	}
+
+	nodes := make([]GraphNode, 0, len(c.Names)+1)
+	if c.Regexp != nil {
+		nodes = append(nodes, dependencyNode("services-regexp", *c.Regexp, q))
+	}
+	for _, name := range c.Names {
+		nodes = append(nodes, dependencyNode("service", name, q))
+	}
+	return nodes
+}
+
+// consulKVDependencies returns the dependency nodes for each of the Consul KV
+// paths
+func consulKVDependencies(c config.ConsulKVMonitorConfig) []GraphNode {
+	q := map[string]string{
+		"dc": config.StringVal(c.Datacenter),
+		"ns": config.StringVal(c.Namespace),
+	}
+	if config.BoolVal(c.Recurse) {
+		q["recurse"] = "true"
+	}
+
+	paths := c.AllPaths()
+	nodes := make([]GraphNode, 0, len(paths))
+	for _, path := range paths {
+		nodes = append(nodes, dependencyNode("consul-kv", path, q))
+	}
+	return nodes
+}
+
+// dependencyNode returns a dependency node. The ID of the node is composed of
+// the kind, name, and any non-empty query qualifiers so that tasks monitoring
+// the same object with the same query share a node.
+func dependencyNode(kind, name string, qualifiers map[string]string) GraphNode {
+	id := fmt.Sprintf("%s:%s", kind, name)
+
+	values := url.Values{}
+	for k, v := range qualifiers {
+		if v != "" {
+			values.Set(k, v)
+		}
+	}
+	if len(values) > 0 {
+		id = fmt.Sprintf("%s?%s", id, values.Encode())
+	}
+
+	return GraphNode{
+		ID:   id,
+		Type: GraphNodeTypeDependency,
+		Name: name,
+		Kind: kind,
+	}
+}
+
+// graphTaskID returns the node ID of a task
+func graphTaskID(taskName string) string {
+	return fmt.Sprintf("task:%s", taskName)
+}
+
+// sharedDependencies returns the IDs found in both sorted ID lists
+func sharedDependencies(a, b []string) []string {
+	var shared []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			shared = append(shared, a[i])
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return shared
+}
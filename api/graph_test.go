@@ -0,0 +1,244 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	mocks "github.com/hashicorp/consul-terraform-sync/mocks/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraph_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	confs := config.TaskConfigs{
+		{
+			Name:    config.String("task_b"),
+			Enabled: config.Bool(false),
+			Condition: &config.ConsulKVConditionConfig{
+				ConsulKVMonitorConfig: config.ConsulKVMonitorConfig{
+					Path:    config.String("key"),
+					Recurse: config.Bool(true),
+				},
+			},
+			ModuleInputs: &config.ModuleInputConfigs{
+				&config.ServicesModuleInputConfig{
+					ServicesMonitorConfig: config.ServicesMonitorConfig{
+						Names: []string{"web"},
+					},
+				},
+			},
+		},
+		{
+			Name:    config.String("task_a"),
+			Enabled: config.Bool(true),
+			Condition: &config.ServicesConditionConfig{
+				ServicesMonitorConfig: config.ServicesMonitorConfig{
+					Names: []string{"api", "web"},
+				},
+			},
+		},
+	}
+
+	ctrl := new(mocks.Server)
+	ctrl.On("Tasks", mock.Anything).Return(confs)
+	handler := newGraphHandler(ctrl, "v1")
+
+	t.Run("json", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/v1/status/graph", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+
+		handler.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var actual TaskGraph
+		err = json.NewDecoder(resp.Body).Decode(&actual)
+		require.NoError(t, err)
+
+		expected := TaskGraph{
+			Nodes: []GraphNode{
+				{ID: "task:task_a", Type: GraphNodeTypeTask, Name: "task_a", Enabled: config.Bool(true)},
+				{ID: "task:task_b", Type: GraphNodeTypeTask, Name: "task_b", Enabled: config.Bool(false)},
+				{ID: "consul-kv:key?recurse=true", Type: GraphNodeTypeDependency, Name: "key", Kind: "consul-kv"},
+				{ID: "service:api", Type: GraphNodeTypeDependency, Name: "api", Kind: "service"},
+				{ID: "service:web", Type: GraphNodeTypeDependency, Name: "web", Kind: "service"},
+			},
+			Edges: []GraphEdge{
+				{From: "task:task_a", To: "service:api", Type: GraphEdgeTypeMonitors},
+				{From: "task:task_a", To: "service:web", Type: GraphEdgeTypeMonitors},
+				{From: "task:task_b", To: "consul-kv:key?recurse=true", Type: GraphEdgeTypeMonitors},
+				{From: "task:task_b", To: "service:web", Type: GraphEdgeTypeMonitors},
+				{From: "task:task_a", To: "task:task_b", Type: GraphEdgeTypeSharedDependency,
+					Dependencies: []string{"service:web"}},
+			},
+		}
+		assert.Equal(t, expected, actual)
+	})
+
+	t.Run("dot", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/v1/status/graph?format=dot", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+
+		handler.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "text/vnd.graphviz", resp.Header().Get("Content-Type"))
+
+		body := resp.Body.String()
+		assert.Contains(t, body, `digraph "cts" {`)
+		assert.Contains(t, body, `"task:task_b" [label="task_b", shape=box, style=dashed];`)
+		assert.Contains(t, body, `"service:web" [label="service: web", shape=ellipse];`)
+		assert.Contains(t, body, `"task:task_a" -> "service:api";`)
+		assert.Contains(t, body,
+			`"task:task_a" -> "task:task_b" [dir=none, style=dashed, label="1 shared"];`)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/v1/status/graph?format=png", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+
+		handler.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, "/v1/status/graph", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+
+		handler.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	})
+}
+
+func TestGraph_TaskDependencies(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		task     *config.TaskConfig
+		expected []string
+	}{
+		{
+			"no dependencies",
+			&config.TaskConfig{
+				Condition: &config.ScheduleConditionConfig{
+					ScheduleMonitorConfig: config.ScheduleMonitorConfig{
+						Cron: config.String("* * * * *"),
+					},
+				},
+			},
+			[]string{},
+		},
+		{
+			"services regexp with qualifiers",
+			&config.TaskConfig{
+				ModuleInputs: &config.ModuleInputConfigs{
+					&config.ServicesModuleInputConfig{
+						ServicesMonitorConfig: config.ServicesMonitorConfig{
+							Regexp:     config.String("^web"),
+							Datacenter: config.String("dc1"),
+							Namespace:  config.String(""),
+						},
+					},
+				},
+			},
+			[]string{"services-regexp:^web?dc=dc1"},
+		},
+		{
+			"catalog-services",
+			&config.TaskConfig{
+				Condition: &config.CatalogServicesConditionConfig{
+					CatalogServicesMonitorConfig: config.CatalogServicesMonitorConfig{
+						Regexp:   config.String(".*"),
+						NodeMeta: map[string]string{"k": "v"},
+					},
+				},
+			},
+			[]string{"catalog-services:.*?node_meta.k=v"},
+		},
+		{
+			"dns",
+			&config.TaskConfig{
+				Condition: &config.DNSConditionConfig{
+					DNSMonitorConfig: config.DNSMonitorConfig{
+						Name:       config.String("example.com"),
+						RecordType: config.String(config.DNSRecordTypeA),
+					},
+				},
+			},
+			[]string{"dns:example.com?record_type=A"},
+		},
+		{
+			"plugin",
+			&config.TaskConfig{
+				Condition: &config.PluginConditionConfig{
+					PluginMonitorConfig: config.PluginMonitorConfig{
+						Command: config.String("/opt/cts/plugins/s3-bucket"),
+					},
+				},
+			},
+			[]string{"plugin:/opt/cts/plugins/s3-bucket"},
+		},
+		{
+			"file",
+			&config.TaskConfig{
+				ModuleInputs: &config.ModuleInputConfigs{
+					&config.FileModuleInputConfig{
+						FileMonitorConfig: config.FileMonitorConfig{
+							Paths: []string{"/etc/cts/rules", "/etc/cts/allowlist.txt"},
+						},
+					},
+				},
+			},
+			[]string{"file:/etc/cts/allowlist.txt", "file:/etc/cts/rules"},
+		},
+		{
+			"consul-kv paths",
+			&config.TaskConfig{
+				Condition: &config.ConsulKVConditionConfig{
+					ConsulKVMonitorConfig: config.ConsulKVMonitorConfig{
+						Paths:      []string{"key/b", "key/a"},
+						Datacenter: config.String("dc1"),
+					},
+				},
+			},
+			[]string{"consul-kv:key/a?dc=dc1", "consul-kv:key/b?dc=dc1"},
+		},
+		{
+			"deduplicated services",
+			&config.TaskConfig{
+				DeprecatedServices: []string{"web"},
+				ModuleInputs: &config.ModuleInputConfigs{
+					&config.ServicesModuleInputConfig{
+						ServicesMonitorConfig: config.ServicesMonitorConfig{
+							Names: []string{"web", "api"},
+						},
+					},
+				},
+			},
+			[]string{"service:api", "service:web"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			nodes := taskDependencies(tc.task)
+			actual := make([]string, 0, len(nodes))
+			for _, n := range nodes {
+				actual = append(actual, n.ID)
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -23,6 +23,7 @@ const (
 type Handlers struct {
 	*TaskLifeCycleHandler
 	*HealthHandler
+	*VersionHandler
 	StatusHandler
 }
 
@@ -39,10 +40,13 @@ func writeResponse(w http.ResponseWriter, r *http.Request, code int, resp interf
 	}
 }
 
-// sendError wraps sending of an error in the Error format
+// sendError wraps sending of an error in the Error format. The error code of
+// the response is set by withErrorCode or defaults to the code of the status.
 func sendError(w http.ResponseWriter, r *http.Request, code int, err error) {
+	errCode := errorCode(code, err)
 	writeResponse(w, r, code, oapigen.ErrorResponse{
 		Error: oapigen.Error{
+			Code:    &errCode,
 			Message: err.Error(),
 		},
 		RequestId: requestIDFromContext(r.Context()),
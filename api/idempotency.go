@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// idempotencyKeyHeader is the request header for clients to make retries
+	// of a request safe
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	// idempotentReplayedHeader is the response header set when the response
+	// is a replay of the cached outcome for an idempotency key
+	idempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// idempotencyCache caches the outcome of requests by their idempotency key
+// for a window of time. Retried requests with the same key are responded to
+// with the cached outcome instead of being processed again. A key is reserved
+// while its first request is processed so that concurrent retries wait for
+// the outcome of the first request.
+type idempotencyCache struct {
+	mu  sync.Mutex
+	ttl time.Duration
+
+	responses map[string]*idempotentResponse // idempotency key => response
+
+	// inFlight tracks the keys of requests that are being processed. The
+	// channel is closed when the request completes.
+	inFlight map[string]chan struct{}
+}
+
+// idempotentResponse is the cached outcome of a request
+type idempotentResponse struct {
+	// fingerprint identifies the request the response is for, to detect a
+	// key reused for a different request
+	fingerprint string
+
+	statusCode  int
+	contentType string
+	body        []byte
+	expiresAt   time.Time
+}
+
+// newIdempotencyCache returns a cache that holds responses for the ttl
+func newIdempotencyCache(ttl time.Duration) *idempotencyCache {
+	return &idempotencyCache{
+		ttl:       ttl,
+		responses: make(map[string]*idempotentResponse),
+		inFlight:  make(map[string]chan struct{}),
+	}
+}
+
+// Get returns the cached response for an idempotency key if one exists and
+// has not expired
+func (c *idempotencyCache) Get(key string) (*idempotentResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.get(key)
+}
+
+// Reserve returns the cached response for an idempotency key if one exists.
+// Otherwise, the key is reserved and true is returned for the caller to
+// process the request, and the caller must call Set or Release when the
+// request completes. If the key is reserved by a request that is in flight,
+// Reserve waits for that request to complete.
+func (c *idempotencyCache) Reserve(ctx context.Context, key string) (*idempotentResponse, bool, error) {
+	for {
+		c.mu.Lock()
+		if resp, ok := c.get(key); ok {
+			c.mu.Unlock()
+			return resp, false, nil
+		}
+		done, ok := c.inFlight[key]
+		if !ok {
+			c.inFlight[key] = make(chan struct{})
+			c.mu.Unlock()
+			return nil, true, nil
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-done:
+			// the response is cached, or the key was released and can be
+			// reserved again
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+	}
+}
+
+// Release removes the reservation of an idempotency key without caching a
+// response, e.g. for server errors so that the request can be retried
+func (c *idempotencyCache) Release(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.release(key)
+}
+
+// Set caches the response for an idempotency key. Expired responses are
+// removed from the cache.
+func (c *idempotencyCache) Set(key string, resp *idempotentResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for k, r := range c.responses {
+		if now.After(r.expiresAt) {
+			delete(c.responses, k)
+		}
+	}
+
+	resp.expiresAt = now.Add(c.ttl)
+	c.responses[key] = resp
+	c.release(key)
+}
+
+// get returns the cached response for an idempotency key. Requires the lock.
+func (c *idempotencyCache) get(key string) (*idempotentResponse, bool) {
+	resp, ok := c.responses[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(resp.expiresAt) {
+		delete(c.responses, key)
+		return nil, false
+	}
+	return resp, true
+}
+
+// release removes the reservation of an idempotency key and notifies the
+// requests waiting on it. Requires the lock.
+func (c *idempotencyCache) release(key string) {
+	if done, ok := c.inFlight[key]; ok {
+		close(done)
+		delete(c.inFlight, key)
+	}
+}
+
+// write replays the cached response to the response writer
+func (r *idempotentResponse) write(w http.ResponseWriter) {
+	if r.contentType != "" {
+		w.Header().Set("Content-Type", r.contentType)
+	}
+	w.Header().Set(idempotentReplayedHeader, "true")
+	w.WriteHeader(r.statusCode)
+	w.Write(r.body)
+}
+
+// requestFingerprint returns a hash that identifies a request by the values
+// that affect its outcome
+func requestFingerprint(values ...[]byte) string {
+	h := sha256.New()
+	for _, v := range values {
+		h.Write(v)
+		// separate values so that different splits do not collide
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyCache(t *testing.T) {
+	t.Parallel()
+
+	t.Run("get and set", func(t *testing.T) {
+		c := newIdempotencyCache(time.Minute)
+
+		_, ok := c.Get("key")
+		assert.False(t, ok)
+
+		c.Set("key", &idempotentResponse{fingerprint: "abc", statusCode: http.StatusCreated})
+		resp, ok := c.Get("key")
+		require.True(t, ok)
+		assert.Equal(t, "abc", resp.fingerprint)
+		assert.Equal(t, http.StatusCreated, resp.statusCode)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		c := newIdempotencyCache(time.Millisecond)
+		c.Set("key", &idempotentResponse{fingerprint: "abc"})
+		time.Sleep(5 * time.Millisecond)
+
+		_, ok := c.Get("key")
+		assert.False(t, ok)
+		assert.Empty(t, c.responses)
+	})
+
+	t.Run("reserve", func(t *testing.T) {
+		c := newIdempotencyCache(time.Minute)
+		ctx := context.Background()
+
+		_, reserved, err := c.Reserve(ctx, "key")
+		require.NoError(t, err)
+		assert.True(t, reserved)
+
+		// concurrent request waits for the reserved key
+		type result struct {
+			resp     *idempotentResponse
+			reserved bool
+		}
+		results := make(chan result, 1)
+		go func() {
+			resp, reserved, err := c.Reserve(ctx, "key")
+			assert.NoError(t, err)
+			results <- result{resp: resp, reserved: reserved}
+		}()
+
+		select {
+		case <-results:
+			t.Fatal("unexpected result while key is reserved")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		c.Set("key", &idempotentResponse{fingerprint: "abc"})
+		select {
+		case r := <-results:
+			assert.False(t, r.reserved)
+			require.NotNil(t, r.resp)
+			assert.Equal(t, "abc", r.resp.fingerprint)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for reserved key")
+		}
+		assert.Empty(t, c.inFlight)
+	})
+
+	t.Run("release", func(t *testing.T) {
+		c := newIdempotencyCache(time.Minute)
+		ctx := context.Background()
+
+		_, reserved, err := c.Reserve(ctx, "key")
+		require.NoError(t, err)
+		require.True(t, reserved)
+		c.Release("key")
+
+		// released key without a response can be reserved again
+		resp, reserved, err := c.Reserve(ctx, "key")
+		require.NoError(t, err)
+		assert.True(t, reserved)
+		assert.Nil(t, resp)
+	})
+
+	t.Run("reserve canceled", func(t *testing.T) {
+		c := newIdempotencyCache(time.Minute)
+		_, _, err := c.Reserve(context.Background(), "key")
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, reserved, err := c.Reserve(ctx, "key")
+		assert.Error(t, err)
+		assert.False(t, reserved)
+	})
+
+	t.Run("set removes expired", func(t *testing.T) {
+		c := newIdempotencyCache(time.Millisecond)
+		c.Set("old", &idempotentResponse{fingerprint: "abc"})
+		time.Sleep(5 * time.Millisecond)
+
+		c.Set("new", &idempotentResponse{fingerprint: "def"})
+		assert.Len(t, c.responses, 1)
+		assert.Contains(t, c.responses, "new")
+	})
+}
+
+func TestIdempotentResponse_write(t *testing.T) {
+	t.Parallel()
+
+	r := &idempotentResponse{
+		statusCode:  http.StatusCreated,
+		contentType: "application/json",
+		body:        []byte(`{"task":{}}`),
+	}
+
+	w := httptest.NewRecorder()
+	r.write(w)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "true", w.Header().Get(idempotentReplayedHeader))
+	assert.Equal(t, `{"task":{}}`, w.Body.String())
+}
+
+func TestRequestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, requestFingerprint([]byte("now"), []byte("body")),
+		requestFingerprint([]byte("now"), []byte("body")))
+	assert.NotEqual(t, requestFingerprint([]byte("now"), []byte("body")),
+		requestFingerprint([]byte(""), []byte("body")))
+	assert.NotEqual(t, requestFingerprint([]byte("no"), []byte("wbody")),
+		requestFingerprint([]byte("now"), []byte("body")))
+}
@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+)
+
+const (
+	inventoryPath          = "inventory"
+	inventorySubsystemName = "inventory"
+)
+
+// InventoryResponse is the response for the inventory endpoint
+type InventoryResponse struct {
+	Tasks []TaskInventory `json:"tasks"`
+}
+
+// TaskInventory is the inventory of the module and providers of a task. The
+// error is set when the inventory of the task could not be read, in which
+// case the inventory may be incomplete.
+type TaskInventory struct {
+	driver.Inventory
+	Error string `json:"error,omitempty"`
+}
+
+// inventoryHandler handles the inventory endpoint
+type inventoryHandler struct {
+	ctrl    Server
+	version string
+}
+
+// newInventoryHandler returns a new inventory handler
+func newInventoryHandler(ctrl Server, version string) *inventoryHandler {
+	return &inventoryHandler{
+		ctrl:    ctrl,
+		version: version,
+	}
+}
+
+// ServeHTTP serves the inventory endpoint which returns, for every task, the
+// module and the provider versions that Terraform installed in the task's
+// workspace
+func (h *inventoryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.FromContext(ctx).Named(inventorySubsystemName)
+	logger.Trace("requesting inventory", "url_path", r.URL.Path)
+
+	switch r.Method {
+	case http.MethodGet:
+		tasks := h.ctrl.Tasks(ctx)
+		resp := InventoryResponse{Tasks: make([]TaskInventory, 0, len(tasks))}
+		for _, task := range tasks {
+			taskName := *task.Name
+			inv, err := h.ctrl.TaskInventory(ctx, taskName)
+			ti := TaskInventory{Inventory: inv}
+			if err != nil {
+				logger.Debug("unable to read inventory for task",
+					"task_name", taskName, "error", err)
+				ti.TaskName = taskName
+				ti.Error = err.Error()
+			}
+			resp.Tasks = append(resp.Tasks, ti)
+		}
+
+		if err := jsonResponse(w, http.StatusOK, resp); err != nil {
+			logger.Error("error, could not generate json response", "error", err)
+		}
+	default:
+		err := fmt.Errorf("'%s' in an unsupported method. The inventory API "+
+			"currently supports the method(s): '%s'", r.Method, http.MethodGet)
+		logger.Trace("unsupported method: %s", err)
+		jsonErrorResponse(ctx, w, http.StatusMethodNotAllowed, err)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	mocks "github.com/hashicorp/consul-terraform-sync/mocks/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInventory_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	tasks := config.TaskConfigs{
+		{Name: config.String("task_a")},
+		{Name: config.String("task_b")},
+	}
+	invA := driver.Inventory{
+		TaskName: "task_a",
+		Module: driver.ModuleInventory{
+			Source:  "registry.terraform.io/org/mod/local",
+			Version: "1.2.0",
+		},
+		Providers: []driver.ProviderInventory{
+			{Source: "registry.terraform.io/hashicorp/local", Version: "2.2.3"},
+		},
+	}
+
+	ctrl := new(mocks.Server)
+	ctrl.On("Tasks", mock.Anything).Return(tasks)
+	ctrl.On("TaskInventory", mock.Anything, "task_a").Return(invA, nil)
+	ctrl.On("TaskInventory", mock.Anything, "task_b").
+		Return(driver.Inventory{}, errors.New("invalid lock file"))
+	handler := newInventoryHandler(ctrl, "v1")
+
+	t.Run("get", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/v1/inventory", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+
+		handler.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var actual InventoryResponse
+		err = json.NewDecoder(resp.Body).Decode(&actual)
+		require.NoError(t, err)
+
+		assert.Equal(t, InventoryResponse{
+			Tasks: []TaskInventory{
+				{Inventory: invA},
+				{
+					Inventory: driver.Inventory{TaskName: "task_b"},
+					Error:     "invalid lock file",
+				},
+			},
+		}, actual)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, "/v1/inventory", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+
+		handler.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	})
+}
@@ -105,6 +105,22 @@ func withCORS(next http.Handler) http.Handler {
 	})
 }
 
+// withReadOnly only allows requests to the read-only endpoints. Requests
+// with methods that mutate resources are rejected.
+func withReadOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			next.ServeHTTP(w, r)
+		default:
+			w.Header().Set("Allow", "GET, HEAD")
+			jsonErrorResponse(r.Context(), w, http.StatusMethodNotAllowed,
+				fmt.Errorf("method %s is not allowed on the read-only listener",
+					r.Method))
+		}
+	})
+}
+
 // withPlaintextErrorToJson processes any plain text errors and converts them
 // to the CTS JSON error response
 func withPlaintextErrorToJson(next http.Handler) http.Handler {
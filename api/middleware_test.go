@@ -267,3 +267,39 @@ func TestWithIntercept(t *testing.T) {
 		assert.True(t, nextCalled, "expected next handler to be served")
 	})
 }
+
+func TestWithReadOnly(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		method     string
+		nextCalled bool
+	}{
+		{http.MethodGet, true},
+		{http.MethodHead, true},
+		{http.MethodOptions, true},
+		{http.MethodPost, false},
+		{http.MethodPatch, false},
+		{http.MethodPut, false},
+		{http.MethodDelete, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.method, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, "/v1/tasks", strings.NewReader(""))
+			require.NoError(t, err)
+			resp := httptest.NewRecorder()
+
+			nextCalled := false
+			nextHandler := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+				nextCalled = true
+			})
+
+			withReadOnly(nextHandler).ServeHTTP(resp, req)
+			assert.Equal(t, tc.nextCalled, nextCalled)
+			if !tc.nextCalled {
+				assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+			}
+		})
+	}
+}
@@ -28,7 +28,7 @@ type ServerInterface interface {
 	GetClusterStatus(w http.ResponseWriter, r *http.Request)
 	// Gets all tasks
 	// (GET /v1/tasks)
-	GetAllTasks(w http.ResponseWriter, r *http.Request)
+	GetAllTasks(w http.ResponseWriter, r *http.Request, params GetAllTasksParams)
 	// Creates a new task
 	// (POST /v1/tasks)
 	CreateTask(w http.ResponseWriter, r *http.Request, params CreateTaskParams)
@@ -38,6 +38,9 @@ type ServerInterface interface {
 	// Gets a task by name
 	// (GET /v1/tasks/{name})
 	GetTaskByName(w http.ResponseWriter, r *http.Request, name string)
+	// Gets version information
+	// (GET /v1/version)
+	GetVersion(w http.ResponseWriter, r *http.Request)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -83,8 +86,79 @@ func (siw *ServerInterfaceWrapper) GetClusterStatus(w http.ResponseWriter, r *ht
 func (siw *ServerInterfaceWrapper) GetAllTasks(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAllTasksParams
+
+	// ------------- Optional query parameter "prefix" -------------
+	if paramValue := r.URL.Query().Get("prefix"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "prefix", r.URL.Query(), &params.Prefix)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "prefix", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "condition_type" -------------
+	if paramValue := r.URL.Query().Get("condition_type"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "condition_type", r.URL.Query(), &params.ConditionType)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "condition_type", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "enabled" -------------
+	if paramValue := r.URL.Query().Get("enabled"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "enabled", r.URL.Query(), &params.Enabled)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "enabled", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "label" -------------
+	if paramValue := r.URL.Query().Get("label"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "label", r.URL.Query(), &params.Label)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "label", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+	if paramValue := r.URL.Query().Get("limit"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "after" -------------
+	if paramValue := r.URL.Query().Get("after"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "after", r.URL.Query(), &params.After)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "after", Err: err})
+		return
+	}
+
 	var handler = func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetAllTasks(w, r)
+		siw.Handler.GetAllTasks(w, r, params)
 	}
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -114,6 +188,27 @@ func (siw *ServerInterfaceWrapper) CreateTask(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithLocation("simple", false, "Idempotency-Key", runtime.ParamLocationHeader, valueList[0], &IdempotencyKey)
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
 	var handler = func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.CreateTask(w, r, params)
 	}
@@ -177,6 +272,21 @@ func (siw *ServerInterfaceWrapper) GetTaskByName(w http.ResponseWriter, r *http.
 	handler(w, r.WithContext(ctx))
 }
 
+// GetVersion operation middleware
+func (siw *ServerInterfaceWrapper) GetVersion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetVersion(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
 type UnescapedCookieParamError struct {
 	ParamName string
 	Err       error
@@ -308,6 +418,9 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/v1/tasks/{name}", wrapper.GetTaskByName)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/v1/version", wrapper.GetVersion)
+	})
 
 	return r
 }
@@ -315,65 +428,107 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xbe28bt5b/KlxmgU3v6ulHEgvoH6nj3RqbpIHte/tHZAgczhmJ9Qw5JTlWBEP72S/4",
-	"mJeGsiQ3SQ20LpBa4uucw/P4nXPoB0xFlgsOXCs8ecCKLiAj9tefiiQB+QkkE7H5TOKYaSY4ST9JkYPU",
-	"DBSeJCRV0MMxKCpZbsbxBN8sAEV2OcrtepQIibRk8zlIxudIE3WH4AvQwqwY4B7OG3s+YOAkSsEe2975",
-	"1wXoBUikOycwhfwqJCSKmbK/D9A7SEiRaoW0sKvmqYhIurGYCp6weSHBUXp+c21ogi8ky1PAEy0L6GG9",
-	"ygFPcCRECoTjdQ9n5EuXRMN8Rr6wrMjK7UWCNMvAkLAkTCOSaJCILgifg0JEAopBA9UQowgSIaElqwVY",
-	"eX0dVvCpwhUrSpsTLCeMb+GE8efKydEowMq6+kZEvwHVhrlzokkq5tcg7xkFdS640+SdWt1WyphoQoFr",
-	"kFZFKzpiOg6JlJMMVE4obMx2rAdXiBhmGWiynbCH7qpq6wd8Bys8wfckLQCHBCFhDl/yNj1LiAb/CFFT",
-	"KJgRNctEXKQwYzwvtFMRR783imojL7JNI7Gn/l4waaz5c0nBbeiW0kJpkNea6EJdgcoFV3DgFVG3x8zI",
-	"vqvPRtPMiNXiBRiNQn5FS7H8d30StBTIIpAqvHvKlDa7m50ZV5pwCgotF4wurHHkRGp3OlOhoz9bbiUo",
-	"ZcjQqj8aD/zggIoM9/ACSKoXq1L8LK4m4h5OgcRGO92YcvruhYGp4KpI+xqkJImQWV+tOMXr3kO9p5dp",
-	"velRY1M/uN+utz3MNGRWTP8pIcET/GJYh5qhjzPDD1aaDWUlUpIV9loDSs9YvGuPKzfz8l1H21rqUF9d",
-	"a/OgKu7tIboOk5ZrkeD+5o2Tc16wcoHmOxf/YIAuk/r7BVH2Qwy5BEqMI/USVyhhkLbcIlGIIGegyBpo",
-	"DzFtIqE0q5XxVTFagAQzsyJsUG7YjbvUecpZOWOX6Ld61nXPa8bs7n7nJnbi//2rtdoMGr52Lb7289qL",
-	"9yQ/QPc6rA4bBD6zwJETvWhPzlZ9EwwCcyXQQipouXJP9S5f/o1igqX+9hG5f7DHXZan/QUlv6/ELqQU",
-	"8kAZZaAUmW+wbAMUU4hwBGZPVM4KAa4maeW8rdQ1I/sG6C+Jf8xkHYdfKT64E9vhYN3DP9t4eL4AevdE",
-	"HHIIKx2E9Gho8gHzMHIqTBHCLH4QMQ9LStxirr9ESQ4D9BBkuV4hYVKwJVPQRk0huNIxggprhEhxg0hZ",
-	"CFiiNKprmvZJylgc3pzFTdwX2rEGUh2ySxC0ubE/FxlijASbW1v7KVFeJUJ7QWERbuOoDblCvPkZHXQb",
-	"5jII2XYZNovxBiX1ZVbyCWrsAd67C6fq6S2g81L9gPSC6Ao4KZRLcc9iqHLKm5K/cqHgjZLDdwJdzUj5",
-	"GO46FCs1hfoEwNNaHoI8tc9shYUoenVM49ej/pvk5LR/kpwc9aOj11E/okfkVXJydjyGV7iHjdSJxhNc",
-	"FFZtOuZ0VRyKoXyJYeZFvL0yJCTiQiPGE0mUlgXVhYSqQrGEZokiLupqFOMqB1qWo7pGmKeEb0R6K8SB",
-	"BqX7tqyRCkrSWcJSGMwlgGa8RtITdAWJBLUwBxoHB4PBAH1m8Y9H8eno5Cw6eR2PX8Vn9CQen1J6enZ2",
-	"Okri+DiGo5Po9dnr8avbKd/nxO0HvTo7Pjmip/T4DE4JnCaj0evXBCg9PqKj5M34zXicRG/GZ8e3Uz7l",
-	"tfUUCmLknEzqxOYtTVpTmwMHSTTYKYlIU7E0J1eWNuVGcgN0BUoUkgIiVsiuWMR4zJy9LZlebGyhVlkk",
-	"UjWZ8v7wv1EMSkuxQoRbajiiEsyxEvKUUMiA6zbdS5amKAdpP7R39iRMzAKEXqCDbhJlhdIoqk6OHX2y",
-	"5G+K69VTjKa4s8MUowdzsPn5f+NaNHCNWj8/omkxGh1T92//4pcb9AIlQprzWxzXS/roZ0hT0UMkZ//R",
-	"HEDlwBKifQYufrmpqWMx6v78iKZ4X7WdYtS3XAB6ecfFkvuaIcnzdPVDfeoL9PIYFdwZaoyI1pJFhQaF",
-	"FiyOgfupa3Nnn1LCJ2hs1I/EcQ+NzG9uZc997bVlMOUh96MTOpMFnxUy7TqSC5Mu5JKZKM3T1QD98+q9",
-	"iam1Zp2nooiRLLgLQVRIaWFiXMUe61FkwdsFy4XWuZoMhyTPB1X0HTBhvhhmq76Q8+FSyDubgijzzVIN",
-	"ZcHtP30S0XfwP/Of2W9346Pjk9P9ap/d/PhAvyvFhtv7B3L/fRB8J2iwq0Og4I/WYqlWs0KBnMWQMA7x",
-	"4WXTDkkH5ooJSztTp9MpNk7E/B8xjjyXgxsyV1vzzdYWn/ESItzDJGe4WUPbRn5VLjs8df1zisFbNeHp",
-	"Sf7fuvA9dSEkrhui7nZeWqNPQZtW38SuXggtzs2JbQ/9FkVEMWq9rHGwZbPQKaHTUUOfnA/9oUP/pZON",
-	"SZqIujt3MNxBGTz5fNvD90Qys5kl5p7IMZ6UdA9sImC4vQepHCHjwWgwsuCwpV+ujTXLq9bpY5C81WZ1",
-	"ZdNaNjtSgbri2RJQqI+3KDLCkQQSG/6Qhi/ax0kqWQR1b64VsYhJmeyHUtjd9lOzVdvyBts7tw5wBxu2",
-	"KJEiK9Ejn+/XhhVlpbjLt8FirhqfBLPCNr9Blem2gDa84KMNjnaiFk7hDaEFZ78X7Qy+ex/mm7fBOmSt",
-	"x0Ep+L5UOc0eo9oZ9H+V2apB/Kp17ueD3E8FbWbUAKVZBWl2yaq6Gwuwfq2WtfasrG+Tz3dV8t4zHDjp",
-	"baVl0NnRFkuAxAPUQYBGhOWsFhLUwh5lXze0lMtBxOo0RJQSlLUzHdcKv/GVVnMSIveEpdZAlybFsalX",
-	"NX9z91iye5DdZnpKTNxBRsBEM7NZxWFic2MFuq1Wzo8F1KrlDx+7un/5iR9I3nKRIWVsSFK7hllZWvH6",
-	"11JLp43bmHwiZxswtewPlhZf++DbLdHuHaSg4TtUh79OoXtHUdlw5BcfyIr2kf9RszZzNimyC7fT8lzl",
-	"2sOy2BmZrwoblp4smz1u66kPJJ7ItK2wmhV7NfUdU5uB4UAmt8SCwyrIHU9+7p2NwwT2pYx6Fl68UxIm",
-	"c+B6lguRzkIdjQ5nb818ZOajy3eGJQX6D7DkSDefqkqecc+2qTF1xE3xAF0wC+paxBo41/jCIhpbHneX",
-	"b3z1o3teJigS2j2YUaB7rtzXPkKTO1DIBHyIgdMNGEfMtP746DgU0zZI20O0Hz0mI7WI/9ryNaF3Vi8I",
-	"pgUlBTNDwR5CvmiT/IcFPEDnhDt7jABNsYRMaJhiI72GMJq4op60oU5mcrCOuBuV/o0lt5cNmqDxkHJN",
-	"6L1tboRZwVUHIe2LO5fxxM3KbJXpNDo9JVWGUMYT4csUmlBdFiasY2F9LUTK+LxPhYQuNW8/XaJ3ghYZ",
-	"cO2CjH276lquldT71ytOe3YoE7YP4lpmZr4CQJ/dAvTx8i16++ny9mVZOl4ulwPXLBwwMYwFVUPOyJDk",
-	"7Afcwymj4DGBJ/jDp/f9o8EIvfcjPWxr3lUpes70oogGVGTDBVELRoXMh8EG8TBKRTTMCOPD95fnFx+v",
-	"L6wFMG1v/fzm2hCKg9URkQMnOcMTfOyVIyd6Ye92eD8eui6y+TSHQGPPvsNw/Vn/PsA9sMR2YxfJL2M8",
-	"wf8L2r3csC86HDyyhxyNRuV1+tYhyfOUucLA8Dfl61AWvezCNqG3Ietuico23xUqG+R23BdH/hRCCl6R",
-	"su5hVWQZkSsnM9V+dmFMgsxtEc5fjK3AmYtyE4blu9WtF3Zz3Uyhf3HAq75FWkhpAmn7mUfjMa7t8UnQ",
-	"heQKkap4UY76Z5xlJ5DJpkfMQJOYaBLSjtYL42+pJOGnzIHbua5EsMHct9CY9vOrADX/5PAldy1eqN4m",
-	"behKSae/PBtavI41EnkbZxZsviijEEuZXjVUq9I1qBSl1rMq2wiq1xVoyeAeVMtrGldK0tS9BQld/ts0",
-	"vfFj3+ze25lZQMJ2glFty0H8bG+5Kcnyytzn23UP50KFzN72443Bclja1bb52r4IN+nG1ZJzIkkG2lXf",
-	"O+U8liRg/YTBg8pesCw4Z3w+QNdFnguplW3CcrH0D61lwVWjwJxlEBuvkK6m3LqUgpfPPPwCWtEcy5Xr",
-	"6NrH2QY9uKdwXlJmecwUJTKGeMp9fQp49aas8XzEss0MD78XIFd100EWZqS+RuBFZstPYmlX2B0a2XCF",
-	"nW6rasVPIl59VXUtyz5blNU21q2QcDN917KA9Tc2pF12hMrTnbepL6DnLtGgU0e6tbOj0fjPIa9XtTsa",
-	"1Dw3q+8ab8Dym+55+GCUeu3cQAo6kOJ9IPLO7KgYn/sGkrViO9/47IiYnES4NNimsSVad2mSy5NZmqII",
-	"ptwdY+ZT8C/uzBWXPiHgbFyR1lzGT6uPrsT7qMsp8/zyDzQ8Y96Y7aPrypZ9ybhtEi3j3tW0cVbdMqCj",
-	"PfSh0UZtFvP2e5q37h2g4Rs17m16nhF55/9Er7zZ56jhpTZ21DAY4g5FHi0l367XIWDydP0sccR31NDv",
-	"7uKfPVLyV75CXt4dp+mf54av1Li5YHHAvhexfxzoEvaHXAotqEjXk+HwYSGUXk8eDAZa44023aJCZ15c",
-	"7j2i/dqCN7kx/Ob09I3vIdsT2qMLrXOL0x1W8R9t/cByd7v+dwAAAP//DhGIvkQ+AAA=",
+	"H4sIAAAAAAACA+1cC3PcNpL+K7zJVV2yN29JfqjWV+XY3o0utuOyHafqLNcUhsTMIOKQswSp0ZxK+9u3",
+	"u/EgQILSjBwnvspltxINCTQajUb3140Gr3txvt7kGc9K2Tu97sl4xdeM/vy+Wix48YYXIk/wN0sSUYo8",
+	"Y+mbIt/wohQc2i1YKnm/l3AZF2KD73unvfcrHs2pe7Sh/tEiL6KyEMsl/MyWUcnkRcSveFxhj2Gv39s4",
+	"NK97PGPzlNOwPuVfVrxcAdmyNYKQke4VwViJkPT3MHrOF6xKSxmVOfVapvmcpY3OcZ4txLIquOL02ft3",
+	"yBO/YutNynunZVHBHMvdBv7uzfM85Szr3fR7a3bVZhEnDy/Eulob8vkiKsWaIwtbJsqILUoYO16xbMll",
+	"xAoeJbzkcQnDzzkwwD1ZAT2U128zld6J7NmpyBJHoJmIrGMmIvtaZzIdB6ZyY5/k81+BEZzcM1ayNF++",
+	"48WliLl8lmdKk+/Ual8pEyATw0bhBamo5SOJJyGRXogsoMB/EykQkDRrqRmK5jv8LYoI+wwjwyhKm6Up",
+	"PVXCXeeZKHOUiFhEWV4CiZKEklXr3ulHZELELIUnILwMpj+AKVzt4Peay9VgyUq+ZfgTeICVZSXw6jyF",
+	"XwWX0nnCNsL++uQKvx6prUvsaiYyWbIs1oLztUophLTqEOVZuou2K57RI5jKHJQA5l7wpZDAKU7X0COZ",
+	"GMlFcZFLyRUpveeG0WslFxTR2NOYyZgUHVv1TseWcwFLCgzpbfCHsK6YuoX16V2cZwxWeAP62dBNtdFC",
+	"y5TlCZ+tecm6t8F1u5clfd274Dt4dcnSivdC2w5kwK82Pj9bPh/+JcSNNhOzPJuVbDnTFkUtgZqC2ZR3",
+	"WuVK8hmTs3WeVCmH5dxUpUdH9bNkNNkmHZrAPypRoBv6aCbzKWRe0gqX+l3Jykq+hVXIM8kPtC2xojHD",
+	"ZWzrHekWvCHzC3+DKYx0D88i6mcDFtyWHJVThqmnoK1IHSnXCrtdiXhFhmfDilKNDn42MPRHmi0aD2Sj",
+	"lIPxZKhfDgFhQNMVZ2m52hnxi8Q2hJcg8gTNqnqnt4gWBpoyWaUDGLFg4AjWA7nLYpjRdU1Ty7QmOnWI",
+	"6pf7UYUFFiVfk5j+veALaPnNqMZIIw2QRq9Imo7eM6Cz62mt4bKcieQuGm9Vy7PnLW3z1KFeOo94UBX3",
+	"dm1tTx+bvmDU9Mqjd1b7srZ48EwBNz6Mzhb18xVTtizhm4LHDBGA9W8LwVPPn0NbFqkNGtEG7UcAJkC1",
+	"Cuwt0ckmEeA8ji0tY0NDsA0YY+XiZ6bFXaLvhAQ3fa0Zs4vLO4lQwx8/eL2T7M7Bn79+53VZCGVQb+sD",
+	"uIF7nTZptVSg7bZub6iV1xFfoeDv6vpOt/M77ynfgGBvwvrakOAXhWSHO8kNK1d+4/VugI6vo23AvL7B",
+	"x7htNHgj+wqmCc0pdhlGuPuA5oBcqUV92tYTVbLBIOa5yAhR0Burp95OGp5nnmGuGYY/cgye6Kdr59rO",
+	"uGXS4qqQ/FZv3OFGf0N33Kk9r4j0maH8f1V/PkPKDfdBo4b8g2d5DhMVYs0CNNRjr3dEMVg9u8lYdgnO",
+	"F8NsVZab4ayMNw2cEBJLXiQz9dwd+6k38ru3H0K9vwgYpOmE5PuiKPLiUOgHOLxtNQBNzlPeh6gmXsGm",
+	"HxRgMfBJhM2NbeA43DD6OUvFBVeRBCAwtoR+ykBA0yQHk4KhooLUEJaXWw7xSsFhahCAaHuhA8g5S2Ya",
+	"Y8BTWG4BWwQ4mi2YwIQMCDRjVbnKC/G/9BMozxZ5leHfiANmrQf8CrClJBQD/RJqAPYt31J/BAWpiEvT",
+	"msWluOTmV5rHF9RMJBx8TMmzeDeD7TODZaXHqJQg5BlJohGhtrhpI2ISlq+YhHDh/yxT4jUiDaYaXKUw",
+	"7Tr1wg0NGukuoza3uVSlW78RwFQj+ngSmvgo41ADAagVQkpYUVin++yyfoeRmTSMzFHYyFj/6zi/ES/j",
+	"EUQEI9I4jHKG5RUqm31RgF2Qh7nDL2JTFPch5cFFub+L+7Msy97i/IGCxmcrHl/cM1g/ZLu20gi3xm86",
+	"qjyMHRt4hwJ7/RIzSsqy6eAeTZxJJahAuR+BiS13EWHErZDcTy2EYvrWktiAPMSKehlJypMYFwZ0LU/7",
+	"pNxFEiYuEjc5EqJYZxtabJtMQZOwHjdCZlCCLmnyESYVYkVICxQWYdeM/LxEaG4mZdhMAYVnGcxr3OW8",
+	"BDpIj5N6Ma18ghp7gGFq5xzq5l4M8638DibJSptdkBGo/CWAAHti8N7Mz3TMM+dA6XfKTLge4LbkxKEJ",
+	"BVeoB+QHGt0OjdW97qF4q5lMOGy934ISDTBfPowsiUhlMWQUs0zl0ufcXQ0d6VK+Uz8lOBqhSCIpcB9S",
+	"kExk6AxQ5aYS1AcyOrlNbLYXhhXLpmcazCuAnCVBIwWQD8MGIN81Uwc/9Z4c5ZuSvJqe7kge1eO0SO4Z",
+	"aa3vH+50n6n+97ufXkd5VUI/m4Cwst0wCbDb7EC9SfTmUq1mGFBHl6wQGKt4Jxj7oSAjvpCpqdGsJ9z5",
+	"/MFRnDwcDx4tjk8Gx4vj6WA+fTgfzOMpe7A4fnw04Q+AEbQVDAVZVSIYDbytDkW8+pBipg1D92k1RBEY",
+	"gYlsUTAYsIrLCvM5+tR0y91j06SqT8hBVTbwVB+Rt13HJmVZI7tAizIsQU4DOmqFCArCI9wuw2XBOR74",
+	"2STpafSWL4D3FQ6IbpkPh8Poo0ieTJOT8fHj+fHDZPIgeRwfJ5OTOD55/PhkvEiSo4RPj+cPHz+cPPh0",
+	"nu0zYvdADx4fHU/jk/joMT9h/GQxHj98yHgcH03j8eLR5NFkspg/mjw+goHOs9rmV6SH5BpTJTbtHwpy",
+	"EEuecTAUyjYscsSYOLL1D+cZSm4IXMm8KsCGMBKySrMJsE3KS2wFwBWfhNyt53kqT8+zweg/YdFgNfMd",
+	"xIrETRbFEKbDsOArUhbzNSiFz/dWpCkeb9MPn7Jm4RQ7RNE30UErGa0BhqDt1CMnir/CzO+8V/c+78HP",
+	"FgV4eo0D4z//jHSwEHn/PIn++tfBi5/eA3PAP47qzbNuOIh+4DCtfsQ24t/cF5F5seXzfV7AYDVPgPDa",
+	"/zyBueyrrDDFwX9F315k+TbThQtss0l339UDfhN9exRVmdqZAAZKsA5zcCcyWokk4ZlueoOL9AZU6DSa",
+	"oL6BzehHY/xL9eyrx1o9VIal7TkW8ayosllVpG3L8QJ9wKYQCCbJZf789iUa5FqVnqV5lURAQCGlOC8K",
+	"imYSC5HIhEADv2oC827ydDSCqQ8tSByKHB+MMEVcLEfbvLigPKfEJ1uMxjL614DN4+f8b8sfxK8Xk+nR",
+	"8cl+BRjto4QDDW2RN+zcXyL1v1d5ULbYIRAQPa3hJrYAg4CmW9LW197OnI6orLwRIViG0nudUBkM9SJK",
+	"aAF00p7GbmXhx5rhyQBlNo7U73H/wWfEuCSWkKf83EoXgCqYYStmgBvwxOHwMoEWSwdm2hdUJuM3PT8/",
+	"76E5xP8iMtSzHL5nSxnGUSrzwa/AliV6FsjHvaoKKPffAItgrVSNzGEY8fBjhINKKb7cgUsoUvh/zfqz",
+	"aFZI+O/BQN6pAk7NUOxaJDdA1ULwZo4jNgx4NGdSxGSX6dRCl4kq0SqNR/7Ah+lBR/qhOYOiY4FnKhJU",
+	"gBEG/YRHHSpmIWbgxwSammQXJQlwtpfQXDEyGY6HY4LgnraqAsbZxhbN3hZ3ewW2qu6gls0daQK3WiFP",
+	"E0A0t5dvmpjSHv9gToLQ8CVXDi5fOPWZTqEbOL48jquC0LA9eVZjEl6W1Ya8KOIlhYMdt5ljhK6wltcR",
+	"to0cRs91la5TzYixEv4HMMxYNipXgxGvN+eQCFYVRJSRPT8rYdNqcAYN57yetTcYHv+oH0bZ2qVwbpHy",
+	"nvG1CuuCpcrRosjXJkbJlnsVIMPWBTAwU7GDny/QZHvBtBu1J5zILnhdv6jLspGmVACIX6LmqcrFBGIt",
+	"DIuolSr1VQ9snGTFqE8TXR6wqX9Ap5+FJKtYmLEyvKRUgtzB9S+hx306zsO5qFQenig39RVe2KXAZdGT",
+	"A4OjskmerB14aA9YVYNmZa5Trjx9MBhPBuPp+8nJ6fj4dHzyP25eAvwHH+DMghlqNuepPMhR+mL70dSX",
+	"RIoUhclFXm3cXU+ZVkzFzVVFoBYRDsN0Tg0swLxKL+qSZUXOmyowwxkoQC8DCXZ4bWOrQ8uLsaoqRFsE",
+	"c72+XIPGvn3W20BDt9b2+XnUcGIeGa0y8Y/Kz8u3LQk+eRo8GKs9UFAKuiTTNKNhpJ8X/w+Tg8aMiL8I",
+	"Hw+s8AEcg9W36S6EYe4waJS5VSSkmxskaVOuFj1BnfACe5HDC/AQGaY+7PrukzDEvHYmBTqumee02xJU",
+	"AtMLY9uC4cgl/qZiK/JghiCW3us/nfbYZFuIsuRkMrGEe8NUemkBraRKRtO6CGlkob2N2iYRuDw8HdL2",
+	"BnaNMzoEc4xSWGT/n745iwp9LNlY0B7mXyE0Tw7DhAZl6Zrq5B7rS4lfWRevW1pWSo2ksPUI5uiqrikm",
+	"OVHKEywZT/ow23V+iX9grUWeCEBbiZPaTxlsA1nF0FcuqpQyHl17rVNlbKJjFmPaZGYTHHeZAqualG75",
+	"xXbzaFpYGFJC/dJP3oC0YMOqW1HW/P5ihFa3SwpQxUJV8xhC5hA3TbXr1aBMtQWLUHfHQ3BSOsqXuqO5",
+	"RYgdnfV4eFnHnpshCSc1tW1zrNJShm+cJE2ucwGGLTEa1tq3hLqZ7HS6Cqffet/Ah00WhCtb2N4aWBdt",
+	"DFmzOFqvoU+j70EM3YWcKR53KftgCNb3e+CFKN3aZ0WaZTvlw2Ym0+gSB/XPnRGAhuVUSxCPZ2DJkbRr",
+	"o6W+egJQPi8spGM23eXw4cC7lqya3Pl4r/U2tCxlegvkg1mkaJud/a+sJmFBdU6rUvCJAwTfv38Z8ZRt",
+	"JOLAJjKk7VHjTaSgw4/SjAqjUQOm1uEnXLdKH4TphD/ToBhoAXxCTZxzokNyrMn7yvlwugrJwPNqtxmn",
+	"D7rhK7bx+s1QJjPv1NkXqDpnjn78AOCCL8SV8pJOCbK2T0w6btAPEJ0bKfWBnykqAUpYX8jIkWpRqpGs",
+	"D1QeUBVRqKkPI8CogKQ54c5FVZAPqhBUQBvVXR3QLDPcIopUzZ9zbJw7EQD4lqIA/OS0JJWxMmg4WUVm",
+	"RFUHoy2fjw7ztvv6Al3AbTjWm9EDdwrTtawgiBVEdF/LF6pktWjczUF86sj2PKe46HeonPptCh3vKLjC",
+	"GenOB06l1JmvW9EDtmlyRB27efla5QrdqjszU3iGftO/v2z2WK373rDL+FUgm/DaCd0IaSqbYa5eLOs0",
+	"AwJWuoFb6JIkDBbK80zXP6gcF3Bf7PCyHJClnBeeRasdjeMrikidCPhnRN2B4j3XiwahEtB9LrSp9Wgb",
+	"tDIvWcAnv7ZXXVV9FlmuNStjfWKurzkzuuZKR2M4d6+EZTIN3GQ9UCE64PlhlUmtw9ZnXq2R7xMNim5C",
+	"XotowWvKPBasnRkDr6lqywn6sUsmUvKapGKEKO4A1IEKpiUs4mwDkc4sVBnZmtlTbB9h++jsOU4JQcr9",
+	"p1Qn4WxtBboy8uvnirnz3jB6IciXe8xiuOc8IDBBuTm1+OjXbqV5tojmebnSAXzZVwUY/hCY3cTSQR7z",
+	"hENA2UjxYrPBZHoU2nMN1vYQrTElrBbxn1u+CFNmdYdwoldzgKeV+wj5hc/yZwsY9rpTeXjew2REiSUy",
+	"QNERhovB6kYNdcLGwZjm7kRBa577Zg7+ECvUBUnRq4DJRGL3jssNSp9BF7nnrmvl+HA8Yo6VJdP3mD9z",
+	"/5kQW0CALliKt5/UKtVE/IwhxeI6GFU5mE3CbJrPz0g2QqvPdxJ+PIP14MAJbFFkHX0w20rvyWE1KC03",
+	"7Aai9z+iUB/O2aA0atEQrqAvEKg0eOJWN9n0t1Me6XCldPCeeJFtRPd+LdvBHCZuMdup8i6majlhYCt8",
+	"mHcZLksARYFIsOMWR77RZUummZOqaY3Wx3TpyhTm2h766w71DmqWKNGFuTlCOHP9TqE45PDAw4R74dVD",
+	"pN0h2/Hw4XByZ9BrBup7i+yswR3fWbihEpBFrgsawMSUpoSBYIUYlODv8NM6MUQLbUVHVXmexxXWTepy",
+	"dvy+kdqodlMP3u2yuE+vKOjAEfGoENtLzqOPOoPx+uwpKt+nb01l33a7Hapdj2V9SR7LUSbYCPj6Du9y",
+	"iJjr3aAZfvXm5WA6HEcv9Zt+j0oSbaXgEuxNNccrP6MVkysBk9qMgtdMRvM0n4/WTGSjl2fPXrx+94LU",
+	"Q5S0OLhmwGgvWEcBWy/Doo/T3pF2Dfbe2OhyMlJ3UfAXxFOBhBbe5lL6rm8ZKW3vEWGF48/w4yB/56W6",
+	"/0VrrI9ZkN50PDbLqUu5sTZUqIPI0a9SV6yQ3t6l1aEbZjftYha6wiMjc82G3us09B/CSJVZVvDYqFqv",
+	"WbFTMpP+5S2yD0sq19ELQ7U6uFCqwch8IqZzwTxLRDlVgGL1KsZVUSCM9i+LOd+9IZdacNivWJxpD0vN",
+	"W/3FFOfEzXHxtloroB3ex3y+pJKEvxoUWJ13VgSNyX0JjfEvKge4+TnjVxtVcs/tDceGrhg+9eIRctE6",
+	"5qQ8CcasxHJlQI5IRblzVMvqGreKUuuZTW4E1estB8vPL7n0rCaaUsxE6PSNV+6gXJ2CaufZhi3x62gu",
+	"2GqkdqD/G9tIpUAQpWBdiTp6Oc/QvKocT0vLnqYpjU5mzlCkryj586CzBl1tqEchhpxSAJBSUdZsqjw5",
+	"XY2H/sR0XftmX9bKUPvOdD4Iec59WSJMBp1JUW0WOcxHfXBEw3n8mLOl5gd93O/yqK/s6Itx9lM4zqdt",
+	"nMtvn+43J4s7A5VaHbMy9WDudNpVtfvK0/kEjCqxISgAmkzxhDzP6KrUwNQiJHjo8kQdqmyYKOR5l/CJ",
+	"WocOYN3OE1W10+fZ5ROAxMk+SvFKf+8ya2YFczM1fWTUSoIq/QVu6yJBfT5dZ03VJU0tDGOQh90TFGtR",
+	"hid44n4BcBLKPt5nBwIOd2oc8U2/bQgwW2xil/asYGdeirySdnpO6hgIdOSOQ7MnRrqWN5xhxo+efTE/",
+	"5+fsAx5FmeFCW+zkq/Vq1nM4Lkr9xi9nbHIZgjkU/yNAyfiWegf8gWr0XlWZ3uoOngusGEZchNkvSQ5N",
+	"F1GBfqmIUFKFTJZv9TfcqAy33lbrNU8QBaWglwShqsxcM9QdYstzAg6PLhjRd990fZNurJMaYBJjhrWU",
+	"QE2dc/DMKrVzfbFTXfFQKWT/YQLUgyjsZcR/VsV4ePaMH9vCwlalVLI2HRTURZIttD3KqxLUxZwAnQM2",
+	"KeC9aWf9GtJE8MLQvzjIU1fE6GHOs7rsCbOt2Eu1VeRMzs7DQFmSbx3prNRNeyues/q7N4Mf6bNRoX09",
+	"Xkznx3EyGTxmJ5PBcXzMBmzKJoMjePqAjxeLk2TSte2Jt+/zZPeb7nhzptqx3+vSiZ4bmmOZ880XtkV3",
+	"maLaAtNq1jrcV/uAPqWk0npoqqbjyR/DXt+mJB1uvjbD2bZ/AePpIvrRNSr+jbKkVB5+2sYaxQVVjYAS",
+	"p6YIyBRsI8y3Fdv2s6kmd+hUbdNN3DlseVPnTTcVVAkRLrExqwF7rSogcDG+371W9RO3Wm17xqwV39a9",
+	"046nb7XZ/a7rMfwt8bnOfLqHPjjF2276br/b9Tf9AzS8UUDSpeegQRfavpqV/Ro13GhjSw2DKOHQYNVT",
+	"8m69DoWY99dPA8V+Rw393U38Vw829ZLvIi3vDqPp5My7dIsyZLem0PvN11ge+vTNWV2s1z5/UHDQlozK",
+	"YfQsFXRXCBMqVPeL51hk7vDLFggnabC6UD5N9Kcx+JZCJ6YvlxGdkOGFGX6wyfovpkHNY6PACn6wlcr1",
+	"ftUJ569VoS7bLDtaZdToE1GnY6ygqUD3GTynoCuzBFzV2cE1mKYyj/P05nQ0usaP8NycXqOi3PQaBa4r",
+	"GziZEmz6VAU9priqaLx+dHLySF+foRH8t3ho4ZQo6590lEGz+3TzL9q+lJrzZAAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -6,6 +6,7 @@ package oapigen
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	openapi_types "github.com/deepmap/oapi-codegen/pkg/types"
 )
@@ -24,11 +25,21 @@ type BufferPeriod struct {
 
 // CatalogServicesCondition defines model for CatalogServicesCondition.
 type CatalogServicesCondition struct {
-	Datacenter       *string                            `json:"datacenter,omitempty"`
-	Namespace        *string                            `json:"namespace,omitempty"`
-	NodeMeta         *CatalogServicesCondition_NodeMeta `json:"node_meta,omitempty"`
-	Regexp           string                             `json:"regexp"`
-	UseAsModuleInput *bool                              `json:"use_as_module_input,omitempty"`
+	Datacenter *string `json:"datacenter,omitempty"`
+
+	// Filters the services by their kind. Services of all kinds are monitored if not set.
+	Kind *string `json:"kind,omitempty"`
+
+	// Triggers the task only when the number of registered instances of a service crosses the maximum. Not set if 0.
+	MaxInstances *int `json:"max_instances,omitempty"`
+
+	// Triggers the task only when the number of registered instances of a service crosses the minimum. Not set if 0.
+	MinInstances        *int                               `json:"min_instances,omitempty"`
+	Namespace           *string                            `json:"namespace,omitempty"`
+	NodeMeta            *CatalogServicesCondition_NodeMeta `json:"node_meta,omitempty"`
+	Regexp              string                             `json:"regexp"`
+	TriggerOnTagChanges *bool                              `json:"trigger_on_tag_changes,omitempty"`
+	UseAsModuleInput    *bool                              `json:"use_as_module_input,omitempty"`
 }
 
 // CatalogServicesCondition_NodeMeta defines model for CatalogServicesCondition.NodeMeta.
@@ -50,17 +61,23 @@ type ClusterStatusResponse struct {
 type Condition struct {
 	CatalogServices *CatalogServicesCondition `json:"catalog_services,omitempty"`
 	ConsulKv        *ConsulKVCondition        `json:"consul_kv,omitempty"`
+	Dns             *DNSCondition             `json:"dns,omitempty"`
+	File            *FileCondition            `json:"file,omitempty"`
+	Plugin          *PluginCondition          `json:"plugin,omitempty"`
 	Schedule        *ScheduleCondition        `json:"schedule,omitempty"`
 	Services        *ServicesCondition        `json:"services,omitempty"`
 }
 
 // ConsulKVCondition defines model for ConsulKVCondition.
 type ConsulKVCondition struct {
-	Datacenter       *string `json:"datacenter,omitempty"`
-	Namespace        *string `json:"namespace,omitempty"`
-	Path             string  `json:"path"`
-	Recurse          *bool   `json:"recurse,omitempty"`
-	UseAsModuleInput *bool   `json:"use_as_module_input,omitempty"`
+	Datacenter *string `json:"datacenter,omitempty"`
+	Namespace  *string `json:"namespace,omitempty"`
+	Path       *string `json:"path,omitempty"`
+
+	// Paths to monitor instead of path. The key-values of all of the paths are combined in the consul_kv module input.
+	Paths            *[]string `json:"paths,omitempty"`
+	Recurse          *bool     `json:"recurse,omitempty"`
+	UseAsModuleInput *bool     `json:"use_as_module_input,omitempty"`
 }
 
 // ConsulKVModuleInput defines model for ConsulKVModuleInput.
@@ -71,9 +88,18 @@ type ConsulKVModuleInput struct {
 	Recurse    *bool   `json:"recurse,omitempty"`
 }
 
+// DNSCondition defines model for DNSCondition.
+type DNSCondition struct {
+	Interval         *string `json:"interval,omitempty"`
+	Name             string  `json:"name"`
+	RecordType       *string `json:"record_type,omitempty"`
+	UseAsModuleInput *bool   `json:"use_as_module_input,omitempty"`
+}
+
 // Error defines model for Error.
 type Error struct {
-	Message string `json:"message"`
+	Code    *string `json:"code,omitempty"`
+	Message string  `json:"message"`
 }
 
 // ErrorResponse defines model for ErrorResponse.
@@ -82,6 +108,21 @@ type ErrorResponse struct {
 	RequestId RequestID `json:"request_id"`
 }
 
+// FileCondition defines model for FileCondition.
+type FileCondition struct {
+	IncludeContent   *bool    `json:"include_content,omitempty"`
+	Interval         *string  `json:"interval,omitempty"`
+	Paths            []string `json:"paths"`
+	UseAsModuleInput *bool    `json:"use_as_module_input,omitempty"`
+}
+
+// FileModuleInput defines model for FileModuleInput.
+type FileModuleInput struct {
+	IncludeContent *bool    `json:"include_content,omitempty"`
+	Interval       *string  `json:"interval,omitempty"`
+	Paths          []string `json:"paths"`
+}
+
 // HealthCheckResponse defines model for HealthCheckResponse.
 type HealthCheckResponse struct {
 	Error *Error `json:"error,omitempty"`
@@ -108,9 +149,20 @@ type Member struct {
 // The additional module input(s) that the tasks provides to the Terraform module on execution. If the task has the deprecated services field configured as a module input, it is represented here as module_input.services.
 type ModuleInput struct {
 	ConsulKv *ConsulKVModuleInput `json:"consul_kv,omitempty"`
+	File     *FileModuleInput     `json:"file,omitempty"`
 	Services *ServicesModuleInput `json:"services,omitempty"`
 }
 
+// Read-only. Condition plugins can only be configured in the CTS configuration file since the plugin is executed on the host of CTS.
+type PluginCondition struct {
+	Args     *[]string `json:"args,omitempty"`
+	Command  string    `json:"command"`
+	Interval *string   `json:"interval,omitempty"`
+
+	// Whether the JSON output of the plugin is passed to the module as the plugin_data variable.
+	UseAsModuleInput *bool `json:"use_as_module_input,omitempty"`
+}
+
 // RequestID defines model for RequestID.
 type RequestID = openapi_types.UUID
 
@@ -126,18 +178,20 @@ type Run struct {
 
 // ScheduleCondition defines model for ScheduleCondition.
 type ScheduleCondition struct {
-	Cron string `json:"cron"`
+	Cron  string    `json:"cron"`
+	Crons *[]string `json:"crons,omitempty"`
 }
 
 // ServicesCondition defines model for ServicesCondition.
 type ServicesCondition struct {
-	CtsUserDefinedMeta *ServicesCondition_CtsUserDefinedMeta `json:"cts_user_defined_meta,omitempty"`
-	Datacenter         *string                               `json:"datacenter,omitempty"`
-	Filter             *string                               `json:"filter,omitempty"`
-	Names              *[]string                             `json:"names,omitempty"`
-	Namespace          *string                               `json:"namespace,omitempty"`
-	Regexp             *string                               `json:"regexp,omitempty"`
-	UseAsModuleInput   *bool                                 `json:"use_as_module_input,omitempty"`
+	CtsUserDefinedMeta      *ServicesCondition_CtsUserDefinedMeta `json:"cts_user_defined_meta,omitempty"`
+	Datacenter              *string                               `json:"datacenter,omitempty"`
+	Filter                  *string                               `json:"filter,omitempty"`
+	IncludeExtendedMetadata *bool                                 `json:"include_extended_metadata,omitempty"`
+	Names                   *[]string                             `json:"names,omitempty"`
+	Namespace               *string                               `json:"namespace,omitempty"`
+	Regexp                  *string                               `json:"regexp,omitempty"`
+	UseAsModuleInput        *bool                                 `json:"use_as_module_input,omitempty"`
 }
 
 // ServicesCondition_CtsUserDefinedMeta defines model for ServicesCondition.CtsUserDefinedMeta.
@@ -147,12 +201,13 @@ type ServicesCondition_CtsUserDefinedMeta struct {
 
 // ServicesModuleInput defines model for ServicesModuleInput.
 type ServicesModuleInput struct {
-	CtsUserDefinedMeta *ServicesModuleInput_CtsUserDefinedMeta `json:"cts_user_defined_meta,omitempty"`
-	Datacenter         *string                                 `json:"datacenter,omitempty"`
-	Filter             *string                                 `json:"filter,omitempty"`
-	Names              *[]string                               `json:"names,omitempty"`
-	Namespace          *string                                 `json:"namespace,omitempty"`
-	Regexp             *string                                 `json:"regexp,omitempty"`
+	CtsUserDefinedMeta      *ServicesModuleInput_CtsUserDefinedMeta `json:"cts_user_defined_meta,omitempty"`
+	Datacenter              *string                                 `json:"datacenter,omitempty"`
+	Filter                  *string                                 `json:"filter,omitempty"`
+	IncludeExtendedMetadata *bool                                   `json:"include_extended_metadata,omitempty"`
+	Names                   *[]string                               `json:"names,omitempty"`
+	Namespace               *string                                 `json:"namespace,omitempty"`
+	Regexp                  *string                                 `json:"regexp,omitempty"`
 }
 
 // ServicesModuleInput_CtsUserDefinedMeta defines model for ServicesModuleInput.CtsUserDefinedMeta.
@@ -168,37 +223,72 @@ type Task struct {
 	// The condition on which to trigger the task to execute. If the task has the deprecated services field configured as a module input, it is represented here as condition.services.
 	Condition Condition `json:"condition"`
 
+	// The minimum interval between consecutive runs of the task. Triggers that occur within the cooldown are suppressed and the task runs once after the cooldown ends. Disabled if not set or set to 0s.
+	Cooldown *string `json:"cooldown,omitempty"`
+
 	// The human readable text to describe the task.
 	Description *string `json:"description,omitempty"`
 
 	// Whether the task is enabled or disabled from executing.
 	Enabled *bool `json:"enabled,omitempty"`
 
+	// The action to take when the task expires. The events of a deleted task are deleted with the task.
+	ExpireAction *string `json:"expire_action,omitempty"`
+
+	// The time when the task expires. When the task expires, an event is recorded and the task is disabled or deleted based on expire_action. The task does not expire if not set.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
 	// The location of the Terraform module.
 	Module string `json:"module"`
 
 	// The additional module input(s) that the tasks provides to the Terraform module on execution. If the task has the deprecated services field configured as a module input, it is represented here as module_input.services.
 	ModuleInput *ModuleInput `json:"module_input,omitempty"`
 
+	// Key-value labels to group the task. Tasks can be listed and operated on in bulk by their labels.
+	Labels *Task_Labels `json:"labels,omitempty"`
+
 	// The unique name of the task.
 	Name string `json:"name"`
 
 	// The list of provider names that the task's module uses.
 	Providers *[]string `json:"providers,omitempty"`
 
+	// Whether the task only renders the module input files on changes without running Terraform.
+	RenderOnly *bool `json:"render_only,omitempty"`
+
+	// The names of the variables whose values are sensitive. Sensitive variables are written to a separate tfvars file that is only readable by the owner, and their values are redacted from API responses.
+	SensitiveVariables *[]string `json:"sensitive_variables,omitempty"`
+
+	// Whether the task passes a services_changed variable to the module with the service instances that were added, removed, or modified since the last successful run of the task.
+	ServicesChanged *bool `json:"services_changed,omitempty"`
+
 	// Enterprise only. Configuration values to use for the Terraform Cloud workspace associated with the task. This is only available when used with the Terraform Cloud driver.
 	TerraformCloudWorkspace *TerraformCloudWorkspace `json:"terraform_cloud_workspace,omitempty"`
 
-	// Deprecated, use task.terraform_cloud_workspace.terraform_version instead. Enterprise only. The version of Terraform to use for the Terraform Cloud workspace associated with the task. This is only available when used with the Terraform Cloud driver. Defaults to the latest compatible version if not set.
+	// The version of Terraform to use for the task. With the Terraform driver, the version is installed within the driver's Terraform path and used for the task instead of the driver's Terraform version. Deprecated for Enterprise with the Terraform Cloud driver, use task.terraform_cloud_workspace.terraform_version instead. Defaults to the driver's Terraform version if not set.
 	TerraformVersion *string `json:"terraform_version,omitempty"`
 
+	// Which changes trigger the task. With condition_only, the task is triggered only by the changes monitored by its condition. With any_input_change, the task is also triggered by changes to the data of its module inputs. Not supported with a schedule condition.
+	TriggerOn *string `json:"trigger_on,omitempty"`
+
+	// The time to live of the task from when it is created. When the TTL elapses, the task expires and expires_at is set to the time of expiration. Only used to create a task and cannot be set with expires_at.
+	Ttl *string `json:"ttl,omitempty"`
+
 	// The map of variables that are provided to the task's module.
 	Variables *VariableMap `json:"variables,omitempty"`
 
+	// Consul KV prefixes of key-values to use as variables of the task. The name of a variable is the key relative to the prefix, and the value is a string. Keys nested further under a prefix are ignored, and variables configured on the task override variables from Consul KV.
+	VariablesFromConsulKv *[]string `json:"variables_from_consul_kv,omitempty"`
+
 	// The version of the configured module that the task uses. Defaults to the latest version if not set.
 	Version *string `json:"version,omitempty"`
 }
 
+// Key-value labels to group the task. Tasks can be listed and operated on in bulk by their labels.
+type Task_Labels struct {
+	AdditionalProperties map[string]string `json:"-"`
+}
+
 // TaskDeleteResponse defines model for TaskDeleteResponse.
 type TaskDeleteResponse struct {
 	Error     *Error    `json:"error,omitempty"`
@@ -220,8 +310,14 @@ type TaskResponse struct {
 
 // TasksResponse defines model for TasksResponse.
 type TasksResponse struct {
+	// Name of the last task of the page when there are more tasks. Set
+	// as the after query parameter to get the next page of tasks.
+	Next      *string   `json:"next,omitempty"`
 	RequestId RequestID `json:"request_id"`
 	Tasks     *[]Task   `json:"tasks,omitempty"`
+
+	// Number of tasks that match the filters across all pages
+	Total *int `json:"total,omitempty"`
 }
 
 // Enterprise only. Configuration values to use for the Terraform Cloud workspace associated with the task. This is only available when used with the Terraform Cloud driver.
@@ -237,6 +333,9 @@ type TerraformCloudWorkspace struct {
 
 	// Enterprise only. The version of Terraform to use for the Terraform Cloud workspace associated with the task. This is only available when used with the Terraform Cloud driver. Defaults to the latest compatible version if not set.
 	TerraformVersion *string `json:"terraform_version,omitempty"`
+
+	// Enterprise only. Names of the variable sets to attach to the Terraform Cloud workspace associated with the task when the task is initialized. The workspace variables are also created and updated from the variables of the task. This is only available when used with the Terraform Cloud driver.
+	VariableSets *[]string `json:"variable_sets,omitempty"`
 }
 
 // The map of variables that are provided to the task's module.
@@ -244,6 +343,47 @@ type VariableMap struct {
 	AdditionalProperties map[string]string `json:"-"`
 }
 
+// VersionResponse defines model for VersionResponse.
+type VersionResponse struct {
+	// the version of the API served by the CTS daemon
+	ApiVersion string `json:"api_version"`
+
+	// the optional features supported by the CTS daemon, such as the features of CTS Enterprise
+	Features  []string  `json:"features"`
+	RequestId RequestID `json:"request_id"`
+
+	// the version of the CTS daemon
+	Version string `json:"version"`
+}
+
+// GetAllTasksParams defines parameters for GetAllTasks.
+type GetAllTasksParams struct {
+	// Only include tasks with names that start with the prefix
+	Prefix *string `form:"prefix,omitempty" json:"prefix,omitempty"`
+
+	// Only include tasks with the type of condition
+	ConditionType *GetAllTasksParamsConditionType `form:"condition_type,omitempty" json:"condition_type,omitempty"`
+
+	// Only include tasks that are enabled or disabled
+	Enabled *bool `form:"enabled,omitempty" json:"enabled,omitempty"`
+
+	// Only include tasks with all of the labels, formatted as
+	// comma-separated key=value pairs
+	Label *string `form:"label,omitempty" json:"label,omitempty"`
+
+	// Maximum number of tasks to include. The next page of tasks starts
+	// after the task in the next field of the response.
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Only include tasks with names that sort after the name, ordered by
+	// name. Set to the next field of the previous response to get the
+	// next page of tasks.
+	After *string `form:"after,omitempty" json:"after,omitempty"`
+}
+
+// GetAllTasksParamsConditionType defines parameters for GetAllTasks.
+type GetAllTasksParamsConditionType string
+
 // CreateTaskJSONBody defines parameters for CreateTask.
 type CreateTaskJSONBody = TaskRequest
 
@@ -253,6 +393,11 @@ type CreateTaskParams struct {
 	// and run inspect which creates a dry run task that is inspected and discarded
 	// at the end of the inspection.
 	Run *CreateTaskParamsRun `form:"run,omitempty" json:"run,omitempty"`
+
+	// Unique key to make retries of the request safe. The outcome of the
+	// first request with the key is cached and returned for retries
+	// with the same key and request for the configured window.
+	IdempotencyKey *string `json:"Idempotency-Key,omitempty"`
 }
 
 // CreateTaskParamsRun defines parameters for CreateTask.
@@ -420,6 +565,59 @@ func (a ServicesModuleInput_CtsUserDefinedMeta) MarshalJSON() ([]byte, error) {
 	return json.Marshal(object)
 }
 
+// Getter for additional properties for Task_Labels. Returns the specified
+// element and whether it was found
+func (a Task_Labels) Get(fieldName string) (value string, found bool) {
+	if a.AdditionalProperties != nil {
+		value, found = a.AdditionalProperties[fieldName]
+	}
+	return
+}
+
+// Setter for additional properties for Task_Labels
+func (a *Task_Labels) Set(fieldName string, value string) {
+	if a.AdditionalProperties == nil {
+		a.AdditionalProperties = make(map[string]string)
+	}
+	a.AdditionalProperties[fieldName] = value
+}
+
+// Override default JSON handling for Task_Labels to handle AdditionalProperties
+func (a *Task_Labels) UnmarshalJSON(b []byte) error {
+	object := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &object)
+	if err != nil {
+		return err
+	}
+
+	if len(object) != 0 {
+		a.AdditionalProperties = make(map[string]string)
+		for fieldName, fieldBuf := range object {
+			var fieldVal string
+			err := json.Unmarshal(fieldBuf, &fieldVal)
+			if err != nil {
+				return fmt.Errorf("error unmarshaling field %s: %w", fieldName, err)
+			}
+			a.AdditionalProperties[fieldName] = fieldVal
+		}
+	}
+	return nil
+}
+
+// Override default JSON handling for Task_Labels to handle AdditionalProperties
+func (a Task_Labels) MarshalJSON() ([]byte, error) {
+	var err error
+	object := make(map[string]json.RawMessage)
+
+	for fieldName, field := range a.AdditionalProperties {
+		object[fieldName], err = json.Marshal(field)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling '%s': %w", fieldName, err)
+		}
+	}
+	return json.Marshal(object)
+}
+
 // Getter for additional properties for VariableMap. Returns the specified
 // element and whether it was found
 func (a VariableMap) Get(fieldName string) (value string, found bool) {
@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/hashicorp/consul-terraform-sync/templates"
 )
 
 const (
@@ -18,6 +19,10 @@ const (
 // OverallStatus is the overall status information for cts and across all the tasks
 type OverallStatus struct {
 	TaskSummary TaskSummary `json:"task_summary"`
+
+	// Dependencies summarizes the dependencies watched for the tasks and how
+	// many of them are shared across tasks
+	Dependencies templates.DependencyStats `json:"dependencies"`
 }
 
 // TaskSummary holds data that summarizes the tasks configured with CTS
@@ -101,7 +106,8 @@ func (h *overallStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		}
 
 		err = jsonResponse(w, http.StatusOK, OverallStatus{
-			TaskSummary: taskSummary,
+			TaskSummary:  taskSummary,
+			Dependencies: h.ctrl.DependencyStats(ctx),
 		})
 		if err != nil {
 			logger.Error("error, could not generate json error response", "error", err)
@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/consul-terraform-sync/config"
 	mocks "github.com/hashicorp/consul-terraform-sync/mocks/server"
 	"github.com/hashicorp/consul-terraform-sync/state/event"
+	"github.com/hashicorp/consul-terraform-sync/templates"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -64,6 +65,13 @@ func TestOverallStatus_ServeHTTP(t *testing.T) {
 						False: 1,
 					},
 				},
+				Dependencies: templates.DependencyStats{
+					Templates:    5,
+					Dependencies: 3,
+					Shared:       1,
+					References:   6,
+					Deduplicated: 3,
+				},
 			},
 		},
 		{
@@ -96,7 +104,14 @@ func TestOverallStatus_ServeHTTP(t *testing.T) {
 		"critical_d": {{Success: false}, {Success: false}, {Success: true}},
 	}
 	ctrl.On("Events", mock.Anything, "").Return(events, nil).
-		On("Tasks", mock.Anything).Return(confs)
+		On("Tasks", mock.Anything).Return(confs).
+		On("DependencyStats", mock.Anything).Return(templates.DependencyStats{
+		Templates:    5,
+		Dependencies: 3,
+		Shared:       1,
+		References:   6,
+		Deduplicated: 3,
+	})
 
 	handler := newOverallStatusHandler(ctrl, "v1")
 
@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+)
+
+const (
+	readinessPath          = "status/readiness"
+	readinessSubsystemName = "readiness"
+)
+
+// ReadinessResponse is the response for the readiness endpoint. Ready is true
+// when all of the enabled tasks are ready.
+type ReadinessResponse struct {
+	Ready bool               `json:"ready"`
+	Tasks []driver.Readiness `json:"tasks"`
+}
+
+// readinessHandler handles the readiness endpoint
+type readinessHandler struct {
+	ctrl    Server
+	version string
+}
+
+// newReadinessHandler returns a new readiness handler
+func newReadinessHandler(ctrl Server, version string) *readinessHandler {
+	return &readinessHandler{
+		ctrl:    ctrl,
+		version: version,
+	}
+}
+
+// ServeHTTP serves the readiness endpoint which returns, for every enabled
+// task, whether the task's template has been resolved for its first run and
+// which of the dependencies of the template have been fetched. The status
+// code is 200 when all of the enabled tasks are ready and 503 otherwise, so
+// that orchestration can wait for CTS to be ready.
+func (h *readinessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.FromContext(ctx).Named(readinessSubsystemName)
+	logger.Trace("requesting readiness", "url_path", r.URL.Path)
+
+	switch r.Method {
+	case http.MethodGet:
+		tasks := h.ctrl.Tasks(ctx)
+		resp := ReadinessResponse{
+			Ready: true,
+			Tasks: make([]driver.Readiness, 0, len(tasks)),
+		}
+		for _, task := range tasks {
+			if !config.BoolVal(task.Enabled) {
+				continue
+			}
+
+			taskName := *task.Name
+			readiness, err := h.ctrl.TaskReadiness(ctx, taskName)
+			if err != nil {
+				// the task was deleted after listing the tasks
+				logger.Debug("unable to read readiness for task",
+					"task_name", taskName, "error", err)
+				continue
+			}
+			resp.Ready = resp.Ready && readiness.Ready
+			resp.Tasks = append(resp.Tasks, readiness)
+		}
+
+		status := http.StatusOK
+		if !resp.Ready {
+			status = http.StatusServiceUnavailable
+		}
+		if err := jsonResponse(w, status, resp); err != nil {
+			logger.Error("error, could not generate json response", "error", err)
+		}
+	default:
+		err := fmt.Errorf("'%s' in an unsupported method. The readiness API "+
+			"currently supports the method(s): '%s'", r.Method, http.MethodGet)
+		logger.Trace("unsupported method: %s", err)
+		jsonErrorResponse(ctx, w, http.StatusMethodNotAllowed, err)
+	}
+}
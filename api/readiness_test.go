@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	mocks "github.com/hashicorp/consul-terraform-sync/mocks/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadiness_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	readyA := driver.Readiness{
+		TaskName: "task_a",
+		Ready:    true,
+		Fetched:  1,
+		Total:    1,
+		Dependencies: []driver.DependencyReadiness{
+			{Name: "health.service(api)", Fetched: true},
+		},
+	}
+	pendingB := driver.Readiness{
+		TaskName: "task_b",
+		Fetched:  1,
+		Total:    2,
+		Dependencies: []driver.DependencyReadiness{
+			{Name: "kv.block(key)"},
+			{Name: "health.service(web)", Fetched: true},
+		},
+	}
+
+	cases := []struct {
+		name         string
+		readinessB   driver.Readiness
+		errB         error
+		expectedCode int
+		expected     ReadinessResponse
+	}{
+		{
+			"ready",
+			driver.Readiness{TaskName: "task_b", Ready: true,
+				Dependencies: []driver.DependencyReadiness{}},
+			nil,
+			http.StatusOK,
+			ReadinessResponse{
+				Ready: true,
+				Tasks: []driver.Readiness{readyA, {TaskName: "task_b", Ready: true,
+					Dependencies: []driver.DependencyReadiness{}}},
+			},
+		},
+		{
+			"not ready",
+			pendingB,
+			nil,
+			http.StatusServiceUnavailable,
+			ReadinessResponse{
+				Ready: false,
+				Tasks: []driver.Readiness{readyA, pendingB},
+			},
+		},
+		{
+			"deleted task",
+			driver.Readiness{},
+			errors.New("task task_b does not exist"),
+			http.StatusOK,
+			ReadinessResponse{
+				Ready: true,
+				Tasks: []driver.Readiness{readyA},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := new(mocks.Server)
+			ctrl.On("Tasks", mock.Anything).Return(config.TaskConfigs{
+				{Name: config.String("task_a"), Enabled: config.Bool(true)},
+				{Name: config.String("task_b"), Enabled: config.Bool(true)},
+				{Name: config.String("task_c"), Enabled: config.Bool(false)},
+			})
+			ctrl.On("TaskReadiness", mock.Anything, "task_a").Return(readyA, nil)
+			ctrl.On("TaskReadiness", mock.Anything, "task_b").
+				Return(tc.readinessB, tc.errB)
+			handler := newReadinessHandler(ctrl, "v1")
+
+			req, err := http.NewRequest(http.MethodGet, "/v1/status/readiness", nil)
+			require.NoError(t, err)
+			resp := httptest.NewRecorder()
+
+			handler.ServeHTTP(resp, req)
+			require.Equal(t, tc.expectedCode, resp.Code)
+
+			var actual ReadinessResponse
+			err = json.NewDecoder(resp.Body).Decode(&actual)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+			ctrl.AssertNotCalled(t, "TaskReadiness", mock.Anything, "task_c")
+		})
+	}
+
+	t.Run("method not allowed", func(t *testing.T) {
+		handler := newReadinessHandler(new(mocks.Server), "v1")
+		req, err := http.NewRequest(http.MethodPost, "/v1/status/readiness", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+
+		handler.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	})
+}
@@ -5,6 +5,7 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/hashicorp/consul-terraform-sync/api/oapigen"
@@ -24,17 +25,31 @@ func TaskRequestFromTaskConfig(tc config.TaskConfig) TaskRequest {
 // ToTaskConfig converts a TaskRequest object to a Config TaskConfig object.
 func (tr TaskRequest) ToTaskConfig() (config.TaskConfig, error) {
 	tc := config.TaskConfig{
-		Description: tr.Task.Description,
-		Name:        &tr.Task.Name,
-		Module:      &tr.Task.Module,
-		Version:     tr.Task.Version,
-		Enabled:     tr.Task.Enabled,
+		Description:     tr.Task.Description,
+		Name:            &tr.Task.Name,
+		Module:          &tr.Task.Module,
+		Version:         tr.Task.Version,
+		Enabled:         tr.Task.Enabled,
+		RenderOnly:      tr.Task.RenderOnly,
+		ServicesChanged: tr.Task.ServicesChanged,
 	}
 
 	if tr.Task.Providers != nil {
 		tc.Providers = *tr.Task.Providers
 	}
 
+	if tr.Task.Labels != nil {
+		tc.Labels = tr.Task.Labels.AdditionalProperties
+	}
+
+	if tr.Task.SensitiveVariables != nil {
+		tc.SensitiveVariables = *tr.Task.SensitiveVariables
+	}
+
+	if tr.Task.VariablesFromConsulKv != nil {
+		tc.VariablesFromConsulKV = *tr.Task.VariablesFromConsulKv
+	}
+
 	// Convert module input
 	if tr.Task.ModuleInput != nil {
 		inputs := make(config.ModuleInputConfigs, 0)
@@ -45,6 +60,8 @@ func (tr TaskRequest) ToTaskConfig() (config.TaskConfig, error) {
 					Datacenter: tr.Task.ModuleInput.Services.Datacenter,
 					Namespace:  tr.Task.ModuleInput.Services.Namespace,
 					Filter:     tr.Task.ModuleInput.Services.Filter,
+
+					IncludeExtendedMetadata: tr.Task.ModuleInput.Services.IncludeExtendedMetadata,
 				},
 			}
 			if tr.Task.ModuleInput.Services.Names != nil {
@@ -66,6 +83,22 @@ func (tr TaskRequest) ToTaskConfig() (config.TaskConfig, error) {
 			}
 			inputs = append(inputs, input)
 		}
+		if tr.Task.ModuleInput.File != nil {
+			input := &config.FileModuleInputConfig{
+				FileMonitorConfig: config.FileMonitorConfig{
+					Paths:          tr.Task.ModuleInput.File.Paths,
+					IncludeContent: tr.Task.ModuleInput.File.IncludeContent,
+				},
+			}
+			if tr.Task.ModuleInput.File.Interval != nil {
+				interval, err := time.ParseDuration(*tr.Task.ModuleInput.File.Interval)
+				if err != nil {
+					return config.TaskConfig{}, err
+				}
+				input.Interval = config.TimeDuration(interval)
+			}
+			inputs = append(inputs, input)
+		}
 		tc.ModuleInputs = &inputs
 	}
 
@@ -76,36 +109,45 @@ func (tr TaskRequest) ToTaskConfig() (config.TaskConfig, error) {
 				Datacenter: tr.Task.Condition.Services.Datacenter,
 				Namespace:  tr.Task.Condition.Services.Namespace,
 				Filter:     tr.Task.Condition.Services.Filter,
+
+				IncludeExtendedMetadata: tr.Task.Condition.Services.IncludeExtendedMetadata,
 			},
 			UseAsModuleInput: tr.Task.Condition.Services.UseAsModuleInput,
 		}
 		if tr.Task.Condition.Services.Names != nil && len(*tr.Task.Condition.Services.Names) > 0 {
 			cond.Names = *tr.Task.Condition.Services.Names
-		} else {
-			cond.Regexp = tr.Task.Condition.Services.Regexp
 		}
+		cond.Regexp = tr.Task.Condition.Services.Regexp
 		if tr.Task.Condition.Services.CtsUserDefinedMeta != nil {
 			cond.ServicesMonitorConfig.CTSUserDefinedMeta =
 				tr.Task.Condition.Services.CtsUserDefinedMeta.AdditionalProperties
 		}
 		tc.Condition = cond
 	} else if tr.Task.Condition.ConsulKv != nil {
-		tc.Condition = &config.ConsulKVConditionConfig{
+		cond := &config.ConsulKVConditionConfig{
 			ConsulKVMonitorConfig: config.ConsulKVMonitorConfig{
 				Datacenter: tr.Task.Condition.ConsulKv.Datacenter,
 				Recurse:    tr.Task.Condition.ConsulKv.Recurse,
-				Path:       &tr.Task.Condition.ConsulKv.Path,
+				Path:       tr.Task.Condition.ConsulKv.Path,
 				Namespace:  tr.Task.Condition.ConsulKv.Namespace,
 			},
 			UseAsModuleInput: tr.Task.Condition.ConsulKv.UseAsModuleInput,
 		}
+		if tr.Task.Condition.ConsulKv.Paths != nil {
+			cond.Paths = *tr.Task.Condition.ConsulKv.Paths
+		}
+		tc.Condition = cond
 	} else if tr.Task.Condition.CatalogServices != nil {
 		cond := &config.CatalogServicesConditionConfig{
 			CatalogServicesMonitorConfig: config.CatalogServicesMonitorConfig{
-				Regexp:           config.String(tr.Task.Condition.CatalogServices.Regexp),
-				UseAsModuleInput: tr.Task.Condition.CatalogServices.UseAsModuleInput,
-				Datacenter:       tr.Task.Condition.CatalogServices.Datacenter,
-				Namespace:        tr.Task.Condition.CatalogServices.Namespace,
+				Regexp:              config.String(tr.Task.Condition.CatalogServices.Regexp),
+				UseAsModuleInput:    tr.Task.Condition.CatalogServices.UseAsModuleInput,
+				Datacenter:          tr.Task.Condition.CatalogServices.Datacenter,
+				Namespace:           tr.Task.Condition.CatalogServices.Namespace,
+				Kind:                tr.Task.Condition.CatalogServices.Kind,
+				TriggerOnTagChanges: tr.Task.Condition.CatalogServices.TriggerOnTagChanges,
+				MinInstances:        tr.Task.Condition.CatalogServices.MinInstances,
+				MaxInstances:        tr.Task.Condition.CatalogServices.MaxInstances,
 			},
 		}
 		if tr.Task.Condition.CatalogServices.NodeMeta != nil {
@@ -113,11 +155,52 @@ func (tr TaskRequest) ToTaskConfig() (config.TaskConfig, error) {
 		}
 		tc.Condition = cond
 	} else if tr.Task.Condition.Schedule != nil {
-		tc.Condition = &config.ScheduleConditionConfig{
+		cond := &config.ScheduleConditionConfig{
 			ScheduleMonitorConfig: config.ScheduleMonitorConfig{
 				Cron: &tr.Task.Condition.Schedule.Cron,
 			},
 		}
+		if tr.Task.Condition.Schedule.Crons != nil {
+			cond.Crons = *tr.Task.Condition.Schedule.Crons
+		}
+		tc.Condition = cond
+	} else if tr.Task.Condition.Dns != nil {
+		cond := &config.DNSConditionConfig{
+			DNSMonitorConfig: config.DNSMonitorConfig{
+				Name:       config.String(tr.Task.Condition.Dns.Name),
+				RecordType: tr.Task.Condition.Dns.RecordType,
+			},
+			UseAsModuleInput: tr.Task.Condition.Dns.UseAsModuleInput,
+		}
+		if tr.Task.Condition.Dns.Interval != nil {
+			interval, err := time.ParseDuration(*tr.Task.Condition.Dns.Interval)
+			if err != nil {
+				return config.TaskConfig{}, err
+			}
+			cond.Interval = config.TimeDuration(interval)
+		}
+		tc.Condition = cond
+	} else if tr.Task.Condition.File != nil {
+		cond := &config.FileConditionConfig{
+			FileMonitorConfig: config.FileMonitorConfig{
+				Paths:          tr.Task.Condition.File.Paths,
+				IncludeContent: tr.Task.Condition.File.IncludeContent,
+			},
+			UseAsModuleInput: tr.Task.Condition.File.UseAsModuleInput,
+		}
+		if tr.Task.Condition.File.Interval != nil {
+			interval, err := time.ParseDuration(*tr.Task.Condition.File.Interval)
+			if err != nil {
+				return config.TaskConfig{}, err
+			}
+			cond.Interval = config.TimeDuration(interval)
+		}
+		tc.Condition = cond
+	} else if tr.Task.Condition.Plugin != nil {
+		// Plugins are executed on the host of CTS, so only operators with
+		// access to the configuration file can configure them
+		return config.TaskConfig{}, fmt.Errorf("plugin conditions can only " +
+			"be configured in the CTS configuration file")
 	}
 
 	if tr.Task.BufferPeriod != nil {
@@ -145,6 +228,35 @@ func (tr TaskRequest) ToTaskConfig() (config.TaskConfig, error) {
 		}
 	}
 
+	if tr.Task.Cooldown != nil {
+		cooldown, err := time.ParseDuration(*tr.Task.Cooldown)
+		if err != nil {
+			return config.TaskConfig{}, err
+		}
+		tc.Cooldown = config.TimeDuration(cooldown)
+	}
+
+	tc.ExpiresAt = tr.Task.ExpiresAt
+	tc.ExpireAction = tr.Task.ExpireAction
+	tc.TriggerOn = tr.Task.TriggerOn
+	if tr.Task.Ttl != nil {
+		if tr.Task.ExpiresAt != nil {
+			return config.TaskConfig{}, fmt.Errorf("ttl and expires_at cannot " +
+				"both be set")
+		}
+		ttl, err := time.ParseDuration(*tr.Task.Ttl)
+		if err != nil {
+			return config.TaskConfig{}, err
+		}
+		if ttl <= 0 {
+			return config.TaskConfig{}, fmt.Errorf("ttl must be positive: %s", ttl)
+		}
+		tc.ExpiresAt = config.Time(time.Now().Add(ttl).UTC().Truncate(time.Second))
+	} else if tc.ExpiresAt != nil && !tc.ExpiresAt.After(time.Now()) {
+		return config.TaskConfig{}, fmt.Errorf("expires_at must be in the "+
+			"future: %s", tc.ExpiresAt.Format(time.RFC3339))
+	}
+
 	if tr.Task.Variables != nil {
 		tc.Variables = make(map[string]string)
 		for k, v := range tr.Task.Variables.AdditionalProperties {
@@ -161,6 +273,9 @@ func (tr TaskRequest) ToTaskConfig() (config.TaskConfig, error) {
 			AgentPoolName:    tr.Task.TerraformCloudWorkspace.AgentPoolName,
 			TerraformVersion: tr.Task.TerraformCloudWorkspace.TerraformVersion,
 		}
+		if tr.Task.TerraformCloudWorkspace.VariableSets != nil {
+			tc.TFCWorkspace.VariableSets = *tr.Task.TerraformCloudWorkspace.VariableSets
+		}
 	}
 
 	return tc, nil
@@ -169,6 +284,13 @@ func (tr TaskRequest) ToTaskConfig() (config.TaskConfig, error) {
 // String writes out the task request in an easily readable way
 // useful for logging
 func (tr TaskRequest) String() string {
+	if tr.Task.Variables != nil && tr.Task.SensitiveVariables != nil {
+		tr.Task.Variables = &oapigen.VariableMap{
+			AdditionalProperties: config.RedactVariables(
+				tr.Task.Variables.AdditionalProperties, *tr.Task.SensitiveVariables),
+		}
+	}
+
 	data, _ := json.Marshal(tr)
 	return string(data)
 }
@@ -178,7 +300,7 @@ type TasksResponse oapigen.TasksResponse
 func tasksResponseFromTaskConfigs(tcs config.TaskConfigs, requestID oapigen.RequestID) TasksResponse {
 	tasks := make([]oapigen.Task, len(tcs))
 	for i, tc := range tcs {
-		tasks[i] = oapigenTaskFromConfigTask(*tc)
+		tasks[i] = redactedTaskFromConfigTask(*tc)
 	}
 
 	return TasksResponse{
@@ -190,7 +312,7 @@ func tasksResponseFromTaskConfigs(tcs config.TaskConfigs, requestID oapigen.Requ
 type TaskResponse oapigen.TaskResponse
 
 func taskResponseFromTaskConfig(tc config.TaskConfig, requestID oapigen.RequestID) TaskResponse {
-	task := oapigenTaskFromConfigTask(tc)
+	task := redactedTaskFromConfigTask(tc)
 
 	tr := TaskResponse{
 		RequestId: requestID,
@@ -204,6 +326,19 @@ func (tresp TaskResponse) String() string {
 	return string(data)
 }
 
+// redactedTaskFromConfigTask converts the task configuration to the task of
+// an API response with the values of the sensitive variables redacted
+func redactedTaskFromConfigTask(tc config.TaskConfig) oapigen.Task {
+	task := oapigenTaskFromConfigTask(tc)
+	if task.Variables != nil {
+		task.Variables = &oapigen.VariableMap{
+			AdditionalProperties: config.RedactVariables(tc.Variables,
+				tc.SensitiveVariables),
+		}
+	}
+	return task
+}
+
 func oapigenTaskFromConfigTask(tc config.TaskConfig) oapigen.Task {
 	task := oapigen.Task{
 		Description: tc.Description,
@@ -229,32 +364,39 @@ func oapigenTaskFromConfigTask(tc config.TaskConfig) oapigen.Task {
 		task.Providers = &tc.Providers
 	}
 
+	if len(tc.Labels) != 0 {
+		task.Labels = &oapigen.Task_Labels{
+			AdditionalProperties: tc.Labels,
+		}
+	}
+
+	if len(tc.SensitiveVariables) != 0 {
+		task.SensitiveVariables = &tc.SensitiveVariables
+	}
+
+	if len(tc.VariablesFromConsulKV) != 0 {
+		task.VariablesFromConsulKv = &tc.VariablesFromConsulKV
+	}
+
 	if tc.ModuleInputs != nil {
 		task.ModuleInput = new(oapigen.ModuleInput)
 		for _, moduleInput := range *tc.ModuleInputs {
 			switch input := moduleInput.(type) {
 			case *config.ServicesModuleInputConfig:
+				services := &oapigen.ServicesModuleInput{
+					Regexp:     input.Regexp,
+					Datacenter: input.Datacenter,
+					Namespace:  input.Namespace,
+					Filter:     input.Filter,
+					CtsUserDefinedMeta: &oapigen.ServicesModuleInput_CtsUserDefinedMeta{
+						AdditionalProperties: input.CTSUserDefinedMeta,
+					},
+					IncludeExtendedMetadata: input.IncludeExtendedMetadata,
+				}
 				if len(input.Names) > 0 {
-					task.ModuleInput.Services = &oapigen.ServicesModuleInput{
-						Names:      &input.Names,
-						Datacenter: input.Datacenter,
-						Namespace:  input.Namespace,
-						Filter:     input.Filter,
-						CtsUserDefinedMeta: &oapigen.ServicesModuleInput_CtsUserDefinedMeta{
-							AdditionalProperties: input.CTSUserDefinedMeta,
-						},
-					}
-				} else {
-					task.ModuleInput.Services = &oapigen.ServicesModuleInput{
-						Regexp:     input.Regexp,
-						Datacenter: input.Datacenter,
-						Namespace:  input.Namespace,
-						Filter:     input.Filter,
-						CtsUserDefinedMeta: &oapigen.ServicesModuleInput_CtsUserDefinedMeta{
-							AdditionalProperties: input.CTSUserDefinedMeta,
-						},
-					}
+					services.Names = &input.Names
 				}
+				task.ModuleInput.Services = services
 			case *config.ConsulKVModuleInputConfig:
 				task.ModuleInput.ConsulKv = &oapigen.ConsulKVModuleInput{
 					Datacenter: input.Datacenter,
@@ -262,6 +404,14 @@ func oapigenTaskFromConfigTask(tc config.TaskConfig) oapigen.Task {
 					Path:       *input.Path,
 					Namespace:  input.Namespace,
 				}
+			case *config.FileModuleInputConfig:
+				task.ModuleInput.File = &oapigen.FileModuleInput{
+					Paths:          input.Paths,
+					IncludeContent: input.IncludeContent,
+				}
+				if input.Interval != nil {
+					task.ModuleInput.File.Interval = config.String(input.Interval.String())
+				}
 			}
 		}
 	}
@@ -275,12 +425,12 @@ func oapigenTaskFromConfigTask(tc config.TaskConfig) oapigen.Task {
 			CtsUserDefinedMeta: &oapigen.ServicesCondition_CtsUserDefinedMeta{
 				AdditionalProperties: cond.CTSUserDefinedMeta,
 			},
-			UseAsModuleInput: cond.UseAsModuleInput,
+			IncludeExtendedMetadata: cond.IncludeExtendedMetadata,
+			UseAsModuleInput:        cond.UseAsModuleInput,
 		}
+		services.Regexp = cond.Regexp
 		if len(cond.Names) > 0 {
 			services.Names = &cond.Names
-		} else {
-			services.Regexp = cond.Regexp
 		}
 		task.Condition.Services = services
 	case *config.CatalogServicesConditionConfig:
@@ -292,19 +442,66 @@ func oapigenTaskFromConfigTask(tc config.TaskConfig) oapigen.Task {
 			NodeMeta: &oapigen.CatalogServicesCondition_NodeMeta{
 				AdditionalProperties: cond.NodeMeta,
 			},
+			TriggerOnTagChanges: cond.TriggerOnTagChanges,
+			MinInstances:        cond.MinInstances,
+			MaxInstances:        cond.MaxInstances,
+		}
+		if config.StringPresent(cond.Kind) {
+			task.Condition.CatalogServices.Kind = config.StringCopy(cond.Kind)
 		}
 	case *config.ConsulKVConditionConfig:
 		task.Condition.ConsulKv = &oapigen.ConsulKVCondition{
 			Datacenter:       cond.Datacenter,
 			Recurse:          cond.Recurse,
-			Path:             *cond.Path,
 			Namespace:        cond.Namespace,
 			UseAsModuleInput: cond.UseAsModuleInput,
 		}
+		if config.StringVal(cond.Path) != "" {
+			task.Condition.ConsulKv.Path = config.String(*cond.Path)
+		}
+		if len(cond.Paths) > 0 {
+			paths := make([]string, len(cond.Paths))
+			copy(paths, cond.Paths)
+			task.Condition.ConsulKv.Paths = &paths
+		}
 	case *config.ScheduleConditionConfig:
 		task.Condition.Schedule = &oapigen.ScheduleCondition{
 			Cron: *cond.Cron,
 		}
+		if len(cond.Crons) > 0 {
+			crons := make([]string, len(cond.Crons))
+			copy(crons, cond.Crons)
+			task.Condition.Schedule.Crons = &crons
+		}
+	case *config.DNSConditionConfig:
+		task.Condition.Dns = &oapigen.DNSCondition{
+			Name:             config.StringVal(cond.Name),
+			RecordType:       cond.RecordType,
+			UseAsModuleInput: cond.UseAsModuleInput,
+		}
+		if cond.Interval != nil {
+			task.Condition.Dns.Interval = config.String(cond.Interval.String())
+		}
+	case *config.FileConditionConfig:
+		task.Condition.File = &oapigen.FileCondition{
+			Paths:            cond.Paths,
+			IncludeContent:   cond.IncludeContent,
+			UseAsModuleInput: cond.UseAsModuleInput,
+		}
+		if cond.Interval != nil {
+			task.Condition.File.Interval = config.String(cond.Interval.String())
+		}
+	case *config.PluginConditionConfig:
+		task.Condition.Plugin = &oapigen.PluginCondition{
+			Command:          config.StringVal(cond.Command),
+			UseAsModuleInput: cond.UseAsModuleInput,
+		}
+		if len(cond.Args) > 0 {
+			task.Condition.Plugin.Args = &cond.Args
+		}
+		if cond.Interval != nil {
+			task.Condition.Plugin.Interval = config.String(cond.Interval.String())
+		}
 	}
 
 	if tc.BufferPeriod != nil {
@@ -317,6 +514,27 @@ func oapigenTaskFromConfigTask(tc config.TaskConfig) oapigen.Task {
 		}
 	}
 
+	if config.BoolVal(tc.RenderOnly) {
+		task.RenderOnly = tc.RenderOnly
+	}
+
+	if config.BoolVal(tc.ServicesChanged) {
+		task.ServicesChanged = tc.ServicesChanged
+	}
+
+	if config.StringVal(tc.TriggerOn) == config.TaskTriggerOnAnyInputChange {
+		task.TriggerOn = config.StringCopy(tc.TriggerOn)
+	}
+
+	if config.TimeDurationVal(tc.Cooldown) > 0 {
+		task.Cooldown = config.String(tc.Cooldown.String())
+	}
+
+	if tc.ExpiresAt != nil {
+		task.ExpiresAt = config.TimeCopy(tc.ExpiresAt)
+		task.ExpireAction = config.StringCopy(tc.ExpireAction)
+	}
+
 	// Tasks created via API cannot configure the `services` field, but tasks
 	// created via CTS config file can currently configure `services` (deprecated).
 	// Handle `services` by converting to condition or module_input. There is
@@ -352,6 +570,11 @@ func oapigenTaskFromConfigTask(tc config.TaskConfig) oapigen.Task {
 			AgentPoolName:    tc.TFCWorkspace.AgentPoolName,
 			TerraformVersion: tc.TFCWorkspace.TerraformVersion,
 		}
+		if len(tc.TFCWorkspace.VariableSets) > 0 {
+			varsets := make([]string, len(tc.TFCWorkspace.VariableSets))
+			copy(varsets, tc.TFCWorkspace.VariableSets)
+			task.TerraformCloudWorkspace.VariableSets = &varsets
+		}
 	}
 
 	return task
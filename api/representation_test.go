@@ -59,6 +59,26 @@ func TestTaskRequest_String(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+func TestTaskRequest_String_SensitiveVariables(t *testing.T) {
+	req := TaskRequest{Task: oapigen.Task{
+		Name:   "task",
+		Module: "path",
+		Variables: &oapigen.VariableMap{AdditionalProperties: map[string]string{
+			"password": "secret",
+			"region":   "us-east-1",
+		}},
+		SensitiveVariables: &[]string{"password"},
+	}}
+
+	actual := req.String()
+	assert.NotContains(t, actual, "secret")
+	assert.Contains(t, actual, `"password":"(redacted)"`)
+	assert.Contains(t, actual, `"region":"us-east-1"`)
+
+	// the request is unchanged
+	assert.Equal(t, "secret", req.Task.Variables.AdditionalProperties["password"])
+}
+
 // Test only bare minimum, task conversion scenarios covered in
 // TestRequest_oapigenTaskFromConfigTask and terraform variable files
 // covered in TestRequest_readToVariablesMap
@@ -97,15 +117,23 @@ func TestRequest_oapigenTaskFromConfigTask(t *testing.T) {
 		{
 			name: "basic_fields_filled",
 			taskConfig: config.TaskConfig{
-				Description:  config.String("test-description"),
-				Name:         config.String("test-name"),
-				Providers:    []string{"test-provider-1", "test-provider-2"},
-				Module:       config.String("path"),
-				Version:      config.String("test-version"),
-				BufferPeriod: config.DefaultBufferPeriodConfig(),
-				Enabled:      config.Bool(true),
-				Condition:    config.EmptyConditionConfig(),
-				ModuleInputs: config.DefaultModuleInputConfigs(),
+				Description:     config.String("test-description"),
+				Name:            config.String("test-name"),
+				Providers:       []string{"test-provider-1", "test-provider-2"},
+				Module:          config.String("path"),
+				Version:         config.String("test-version"),
+				BufferPeriod:    config.DefaultBufferPeriodConfig(),
+				Cooldown:        config.TimeDuration(5 * time.Minute),
+				ExpiresAt:       config.Time(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)),
+				ExpireAction:    config.String(config.TaskExpireActionDelete),
+				TriggerOn:       config.String(config.TaskTriggerOnAnyInputChange),
+				RenderOnly:      config.Bool(true),
+				ServicesChanged: config.Bool(true),
+				Enabled:         config.Bool(true),
+				Condition:       config.EmptyConditionConfig(),
+				ModuleInputs:    config.DefaultModuleInputConfigs(),
+
+				VariablesFromConsulKV: []string{"config/tasks/web/"},
 
 				// Enterprise
 				DeprecatedTFVersion: config.String("1.0.0"),
@@ -114,6 +142,7 @@ func TestRequest_oapigenTaskFromConfigTask(t *testing.T) {
 					AgentPoolID:      config.String("apool-123"),
 					AgentPoolName:    config.String("test_agent_pool"),
 					TerraformVersion: config.String("1.0.0"),
+					VariableSets:     []string{"consul-credentials"},
 				},
 			},
 			expected: oapigen.Task{
@@ -126,10 +155,18 @@ func TestRequest_oapigenTaskFromConfigTask(t *testing.T) {
 					Max:     config.String("20s"),
 					Min:     config.String("5s"),
 				},
-				Enabled:     config.Bool(true),
-				Condition:   oapigen.Condition{},
-				ModuleInput: &oapigen.ModuleInput{},
-				Providers:   &[]string{"test-provider-1", "test-provider-2"},
+				Cooldown:        config.String("5m0s"),
+				ExpiresAt:       config.Time(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)),
+				ExpireAction:    config.String(config.TaskExpireActionDelete),
+				TriggerOn:       config.String(config.TaskTriggerOnAnyInputChange),
+				RenderOnly:      config.Bool(true),
+				ServicesChanged: config.Bool(true),
+				Enabled:         config.Bool(true),
+				Condition:       oapigen.Condition{},
+				ModuleInput:     &oapigen.ModuleInput{},
+				Providers:       &[]string{"test-provider-1", "test-provider-2"},
+
+				VariablesFromConsulKv: &[]string{"config/tasks/web/"},
 
 				// Enterprise
 				TerraformVersion: config.String("1.0.0"),
@@ -138,6 +175,7 @@ func TestRequest_oapigenTaskFromConfigTask(t *testing.T) {
 					AgentPoolId:      config.String("apool-123"),
 					AgentPoolName:    config.String("test_agent_pool"),
 					TerraformVersion: config.String("1.0.0"),
+					VariableSets:     &[]string{"consul-credentials"},
 				},
 			},
 		},
@@ -175,11 +213,12 @@ func TestRequest_oapigenTaskFromConfigTask(t *testing.T) {
 			taskConfig: config.TaskConfig{
 				Condition: &config.ServicesConditionConfig{
 					ServicesMonitorConfig: config.ServicesMonitorConfig{
-						Names:              []string{"api", "web"},
-						Datacenter:         config.String(""),
-						Namespace:          config.String(""),
-						Filter:             config.String(""),
-						CTSUserDefinedMeta: map[string]string{},
+						Names:                   []string{"api", "web"},
+						Datacenter:              config.String(""),
+						Namespace:               config.String(""),
+						Filter:                  config.String(""),
+						CTSUserDefinedMeta:      map[string]string{},
+						IncludeExtendedMetadata: config.Bool(true),
 					},
 					UseAsModuleInput: config.Bool(false),
 				},
@@ -194,7 +233,8 @@ func TestRequest_oapigenTaskFromConfigTask(t *testing.T) {
 						CtsUserDefinedMeta: &oapigen.ServicesCondition_CtsUserDefinedMeta{
 							AdditionalProperties: map[string]string{},
 						},
-						UseAsModuleInput: config.Bool(false),
+						IncludeExtendedMetadata: config.Bool(true),
+						UseAsModuleInput:        config.Bool(false),
 					},
 				},
 			},
@@ -212,6 +252,10 @@ func TestRequest_oapigenTaskFromConfigTask(t *testing.T) {
 							"key1": "value1",
 							"key2": "value2",
 						},
+						Kind:                config.String("typical"),
+						TriggerOnTagChanges: config.Bool(true),
+						MinInstances:        config.Int(2),
+						MaxInstances:        config.Int(10),
 					},
 				},
 			},
@@ -228,6 +272,10 @@ func TestRequest_oapigenTaskFromConfigTask(t *testing.T) {
 								"key2": "value2",
 							},
 						},
+						Kind:                config.String("typical"),
+						TriggerOnTagChanges: config.Bool(true),
+						MinInstances:        config.Int(2),
+						MaxInstances:        config.Int(10),
 					},
 				},
 			},
@@ -248,7 +296,7 @@ func TestRequest_oapigenTaskFromConfigTask(t *testing.T) {
 			expected: oapigen.Task{
 				Condition: oapigen.Condition{
 					ConsulKv: &oapigen.ConsulKVCondition{
-						Path:             "key-path",
+						Path:             config.String("key-path"),
 						Recurse:          config.Bool(true),
 						Datacenter:       config.String("dc2"),
 						Namespace:        config.String("ns2"),
@@ -257,6 +305,24 @@ func TestRequest_oapigenTaskFromConfigTask(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with_consul_kv_condition_paths",
+			taskConfig: config.TaskConfig{
+				Condition: &config.ConsulKVConditionConfig{
+					ConsulKVMonitorConfig: config.ConsulKVMonitorConfig{
+						Path:  config.String(""),
+						Paths: []string{"key-path", "other-key-path"},
+					},
+				},
+			},
+			expected: oapigen.Task{
+				Condition: oapigen.Condition{
+					ConsulKv: &oapigen.ConsulKVCondition{
+						Paths: &[]string{"key-path", "other-key-path"},
+					},
+				},
+			},
+		},
 		{
 			name: "with_schedule_condition",
 			taskConfig: config.TaskConfig{
@@ -272,6 +338,109 @@ func TestRequest_oapigenTaskFromConfigTask(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with_schedule_condition_crons",
+			taskConfig: config.TaskConfig{
+				Condition: &config.ScheduleConditionConfig{
+					ScheduleMonitorConfig: config.ScheduleMonitorConfig{
+						Cron:  config.String(""),
+						Crons: []string{"0 * * * 1-5", "0 0 * * 0,6"},
+					},
+				},
+			},
+			expected: oapigen.Task{
+				Condition: oapigen.Condition{
+					Schedule: &oapigen.ScheduleCondition{
+						Crons: &[]string{"0 * * * 1-5", "0 0 * * 0,6"},
+					},
+				},
+			},
+		},
+		{
+			name: "with_dns_condition",
+			taskConfig: config.TaskConfig{
+				Condition: &config.DNSConditionConfig{
+					DNSMonitorConfig: config.DNSMonitorConfig{
+						Name:       config.String("example.com"),
+						RecordType: config.String("A"),
+						Interval:   config.TimeDuration(10 * time.Second),
+					},
+					UseAsModuleInput: config.Bool(true),
+				},
+			},
+			expected: oapigen.Task{
+				Condition: oapigen.Condition{
+					Dns: &oapigen.DNSCondition{
+						Name:             "example.com",
+						RecordType:       config.String("A"),
+						Interval:         config.String("10s"),
+						UseAsModuleInput: config.Bool(true),
+					},
+				},
+			},
+		},
+		{
+			name: "with_plugin_condition",
+			taskConfig: config.TaskConfig{
+				Condition: &config.PluginConditionConfig{
+					PluginMonitorConfig: config.PluginMonitorConfig{
+						Command:  config.String("/opt/cts/plugins/s3-bucket"),
+						Args:     []string{"-bucket", "releases"},
+						Interval: config.TimeDuration(time.Minute),
+					},
+					UseAsModuleInput: config.Bool(true),
+				},
+			},
+			expected: oapigen.Task{
+				Condition: oapigen.Condition{
+					Plugin: &oapigen.PluginCondition{
+						Command:          "/opt/cts/plugins/s3-bucket",
+						Args:             &[]string{"-bucket", "releases"},
+						Interval:         config.String("1m0s"),
+						UseAsModuleInput: config.Bool(true),
+					},
+				},
+			},
+		},
+		{
+			name: "with_file_condition_and_module_input",
+			taskConfig: config.TaskConfig{
+				Condition: &config.FileConditionConfig{
+					FileMonitorConfig: config.FileMonitorConfig{
+						Paths:          []string{"/etc/cts/allowlist.txt"},
+						Interval:       config.TimeDuration(10 * time.Second),
+						IncludeContent: config.Bool(true),
+					},
+					UseAsModuleInput: config.Bool(false),
+				},
+				ModuleInputs: &config.ModuleInputConfigs{
+					&config.FileModuleInputConfig{
+						FileMonitorConfig: config.FileMonitorConfig{
+							Paths:          []string{"/etc/cts/rules"},
+							Interval:       config.TimeDuration(time.Minute),
+							IncludeContent: config.Bool(false),
+						},
+					},
+				},
+			},
+			expected: oapigen.Task{
+				Condition: oapigen.Condition{
+					File: &oapigen.FileCondition{
+						Paths:            []string{"/etc/cts/allowlist.txt"},
+						Interval:         config.String("10s"),
+						IncludeContent:   config.Bool(true),
+						UseAsModuleInput: config.Bool(false),
+					},
+				},
+				ModuleInput: &oapigen.ModuleInput{
+					File: &oapigen.FileModuleInput{
+						Paths:          []string{"/etc/cts/rules"},
+						Interval:       config.String("1m0s"),
+						IncludeContent: config.Bool(false),
+					},
+				},
+			},
+		},
 		{
 			name: "with_module_inputs",
 			taskConfig: config.TaskConfig{
@@ -488,7 +657,13 @@ func TestTaskRequest_ToTaskConfig(t *testing.T) {
 						Max:     config.String("5m"),
 						Min:     config.String("30s"),
 					},
-					Enabled: config.Bool(true),
+					Cooldown:        config.String("2m"),
+					TriggerOn:       config.String(config.TaskTriggerOnAnyInputChange),
+					RenderOnly:      config.Bool(true),
+					ServicesChanged: config.Bool(true),
+					Enabled:         config.Bool(true),
+
+					VariablesFromConsulKv: &[]string{"config/tasks/web/"},
 
 					// Enterprise
 					TerraformVersion: config.String("1.0.0"),
@@ -497,6 +672,7 @@ func TestTaskRequest_ToTaskConfig(t *testing.T) {
 						AgentPoolId:      config.String("apool-123"),
 						AgentPoolName:    config.String("test_agent_pool"),
 						TerraformVersion: config.String("1.0.0"),
+						VariableSets:     &[]string{"consul-credentials"},
 					},
 				},
 			},
@@ -516,7 +692,13 @@ func TestTaskRequest_ToTaskConfig(t *testing.T) {
 					Max:     config.TimeDuration(5 * time.Minute),
 					Min:     config.TimeDuration(30 * time.Second),
 				},
-				Enabled: config.Bool(true),
+				Cooldown:        config.TimeDuration(2 * time.Minute),
+				TriggerOn:       config.String(config.TaskTriggerOnAnyInputChange),
+				RenderOnly:      config.Bool(true),
+				ServicesChanged: config.Bool(true),
+				Enabled:         config.Bool(true),
+
+				VariablesFromConsulKV: []string{"config/tasks/web/"},
 
 				// Enterprise
 				DeprecatedTFVersion: config.String("1.0.0"),
@@ -525,6 +707,7 @@ func TestTaskRequest_ToTaskConfig(t *testing.T) {
 					AgentPoolID:      config.String("apool-123"),
 					AgentPoolName:    config.String("test_agent_pool"),
 					TerraformVersion: config.String("1.0.0"),
+					VariableSets:     []string{"consul-credentials"},
 				},
 			},
 		},
@@ -600,6 +783,10 @@ func TestTaskRequest_ToTaskConfig(t *testing.T) {
 									"key2": "value2",
 								},
 							},
+							Kind:                config.String("typical"),
+							TriggerOnTagChanges: config.Bool(true),
+							MinInstances:        config.Int(2),
+							MaxInstances:        config.Int(10),
 						},
 					},
 				},
@@ -617,6 +804,10 @@ func TestTaskRequest_ToTaskConfig(t *testing.T) {
 							"key1": "value1",
 							"key2": "value2",
 						},
+						Kind:                config.String("typical"),
+						TriggerOnTagChanges: config.Bool(true),
+						MinInstances:        config.Int(2),
+						MaxInstances:        config.Int(10),
 					},
 				},
 			},
@@ -657,7 +848,7 @@ func TestTaskRequest_ToTaskConfig(t *testing.T) {
 					},
 					Condition: oapigen.Condition{
 						ConsulKv: &oapigen.ConsulKVCondition{
-							Path:             "key-path",
+							Path:             config.String("key-path"),
 							Recurse:          config.Bool(true),
 							Datacenter:       config.String("dc2"),
 							Namespace:        config.String("ns2"),
@@ -687,6 +878,29 @@ func TestTaskRequest_ToTaskConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with_consul_kv_condition_paths",
+			request: &TaskRequest{
+				Task: oapigen.Task{
+					Name:   "task",
+					Module: "path",
+					Condition: oapigen.Condition{
+						ConsulKv: &oapigen.ConsulKVCondition{
+							Paths: &[]string{"key-path", "other-key-path"},
+						},
+					},
+				},
+			},
+			taskConfigExpected: config.TaskConfig{
+				Name:   config.String("task"),
+				Module: config.String("path"),
+				Condition: &config.ConsulKVConditionConfig{
+					ConsulKVMonitorConfig: config.ConsulKVMonitorConfig{
+						Paths: []string{"key-path", "other-key-path"},
+					},
+				},
+			},
+		},
 		{
 			name: "with_schedule_condition",
 			request: &TaskRequest{
@@ -720,6 +934,85 @@ func TestTaskRequest_ToTaskConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with_schedule_condition_crons",
+			request: &TaskRequest{
+				Task: oapigen.Task{
+					Name:   "task",
+					Module: "path",
+					Condition: oapigen.Condition{
+						Schedule: &oapigen.ScheduleCondition{
+							Cron:  "0 * * * 1-5",
+							Crons: &[]string{"0 0 * * 0,6"},
+						},
+					},
+				},
+			},
+			taskConfigExpected: config.TaskConfig{
+				Name:   config.String("task"),
+				Module: config.String("path"),
+				Condition: &config.ScheduleConditionConfig{
+					ScheduleMonitorConfig: config.ScheduleMonitorConfig{
+						Cron:  config.String("0 * * * 1-5"),
+						Crons: []string{"0 0 * * 0,6"},
+					},
+				},
+			},
+		},
+		{
+			name: "with_dns_condition",
+			request: &TaskRequest{
+				Task: oapigen.Task{
+					Name:   "task",
+					Module: "path",
+					Condition: oapigen.Condition{
+						Dns: &oapigen.DNSCondition{
+							Name:       "_http._tcp.example.com",
+							RecordType: config.String("SRV"),
+							Interval:   config.String("1m"),
+						},
+					},
+				},
+			},
+			taskConfigExpected: config.TaskConfig{
+				Name:   config.String("task"),
+				Module: config.String("path"),
+				Condition: &config.DNSConditionConfig{
+					DNSMonitorConfig: config.DNSMonitorConfig{
+						Name:       config.String("_http._tcp.example.com"),
+						RecordType: config.String("SRV"),
+						Interval:   config.TimeDuration(time.Minute),
+					},
+				},
+			},
+		},
+		{
+			name: "with_file_condition",
+			request: &TaskRequest{
+				Task: oapigen.Task{
+					Name:   "task",
+					Module: "path",
+					Condition: oapigen.Condition{
+						File: &oapigen.FileCondition{
+							Paths:          []string{"/etc/cts/allowlist.txt"},
+							Interval:       config.String("30s"),
+							IncludeContent: config.Bool(false),
+						},
+					},
+				},
+			},
+			taskConfigExpected: config.TaskConfig{
+				Name:   config.String("task"),
+				Module: config.String("path"),
+				Condition: &config.FileConditionConfig{
+					FileMonitorConfig: config.FileMonitorConfig{
+						Paths:          []string{"/etc/cts/allowlist.txt"},
+						Interval:       config.TimeDuration(30 * time.Second),
+						IncludeContent: config.Bool(false),
+					},
+				},
+			},
+		},
 		{
 			name: "with_module_inputs",
 			request: &TaskRequest{
@@ -839,6 +1132,66 @@ func TestTaskRequest_ToRequestTaskConfig_Error(t *testing.T) {
 			},
 			contains: "invalid duration",
 		},
+		{
+			name: "invalid cooldown",
+			request: &TaskRequest{
+				Task: oapigen.Task{
+					Name: "test-name",
+					Condition: oapigen.Condition{
+						Services: &oapigen.ServicesCondition{
+							Names: &[]string{"api"},
+						},
+					},
+					Cooldown: config.String("invalid"),
+				},
+			},
+			contains: "invalid duration",
+		},
+		{
+			name: "plugin condition",
+			request: &TaskRequest{
+				Task: oapigen.Task{
+					Name: "test-name",
+					Condition: oapigen.Condition{
+						Plugin: &oapigen.PluginCondition{
+							Command: "/opt/cts/plugins/s3-bucket",
+						},
+					},
+				},
+			},
+			contains: "configuration file",
+		},
+		{
+			name: "ttl and expires_at",
+			request: &TaskRequest{
+				Task: oapigen.Task{
+					Name:      "test-name",
+					Ttl:       config.String("1h"),
+					ExpiresAt: config.Time(time.Now().Add(time.Hour)),
+				},
+			},
+			contains: "cannot both be set",
+		},
+		{
+			name: "negative ttl",
+			request: &TaskRequest{
+				Task: oapigen.Task{
+					Name: "test-name",
+					Ttl:  config.String("-1h"),
+				},
+			},
+			contains: "ttl must be positive",
+		},
+		{
+			name: "expires_at in the past",
+			request: &TaskRequest{
+				Task: oapigen.Task{
+					Name:      "test-name",
+					ExpiresAt: config.Time(time.Now().Add(-time.Hour)),
+				},
+			},
+			contains: "expires_at must be in the future",
+		},
 	}
 
 	for _, tc := range cases {
@@ -914,3 +1267,73 @@ func TestTaskResponse_taskResponseFromTaskConfig(t *testing.T) {
 	actual := taskResponseFromTaskConfig(tc.taskConfig, uuid.MustParse("e9926514-79b8-a8fc-8761-9b6aaccf1e15"))
 	assert.Equal(t, tc.expectedResponse, actual)
 }
+
+func TestTaskResponse_taskResponseFromTaskConfig_SensitiveVariables(t *testing.T) {
+	tc := config.TaskConfig{
+		Name: config.String("task"),
+		Variables: map[string]string{
+			"password": "secret",
+			"region":   "us-east-1",
+		},
+		SensitiveVariables: []string{"password"},
+	}
+
+	actual := taskResponseFromTaskConfig(tc, uuid.New())
+	require.NotNil(t, actual.Task.Variables)
+	assert.Equal(t, map[string]string{
+		"password": "(redacted)",
+		"region":   "us-east-1",
+	}, actual.Task.Variables.AdditionalProperties)
+	assert.Equal(t, &[]string{"password"}, actual.Task.SensitiveVariables)
+
+	// the task configuration is unchanged
+	assert.Equal(t, "secret", tc.Variables["password"])
+
+	// requests to create the task are not redacted
+	req := TaskRequestFromTaskConfig(tc)
+	assert.Equal(t, "secret", req.Task.Variables.AdditionalProperties["password"])
+
+	conf, err := req.ToTaskConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"password"}, conf.SensitiveVariables)
+}
+
+func TestTaskResponse_taskResponseFromTaskConfig_Labels(t *testing.T) {
+	tc := config.TaskConfig{
+		Name:   config.String("task"),
+		Labels: map[string]string{"team": "neteng"},
+	}
+
+	actual := taskResponseFromTaskConfig(tc, uuid.New())
+	require.NotNil(t, actual.Task.Labels)
+	assert.Equal(t, map[string]string{"team": "neteng"},
+		actual.Task.Labels.AdditionalProperties)
+
+	req := TaskRequestFromTaskConfig(tc)
+	conf, err := req.ToTaskConfig()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "neteng"}, conf.Labels)
+
+	// tasks without labels do not have the field
+	actual = taskResponseFromTaskConfig(config.TaskConfig{}, uuid.New())
+	assert.Nil(t, actual.Task.Labels)
+}
+
+func TestTaskRequest_ToTaskConfig_Ttl(t *testing.T) {
+	req := TaskRequest{
+		Task: oapigen.Task{
+			Name:         "test-name",
+			Module:       "path",
+			Ttl:          config.String("72h"),
+			ExpireAction: config.String(config.TaskExpireActionDelete),
+		},
+	}
+
+	before := time.Now().Truncate(time.Second)
+	tc, err := req.ToTaskConfig()
+	require.NoError(t, err)
+	require.NotNil(t, tc.ExpiresAt)
+	assert.WithinDuration(t, before.Add(72*time.Hour), *tc.ExpiresAt, 5*time.Second)
+	assert.False(t, tc.ExpiresAt.Before(before.Add(72*time.Hour)))
+	assert.Equal(t, config.TaskExpireActionDelete, config.StringVal(tc.ExpireAction))
+}
@@ -64,8 +64,10 @@ func (r *plaintextErrorToJsonResponseWriter) Write(p []byte) (int, error) {
 		var errResp oapigen.ErrorResponse
 		if err := json.Unmarshal(p, &errResp); err != nil {
 			msg := strings.TrimSpace(string(p))
+			code := errorCode(r.statusCode, nil)
 			errResp = oapigen.ErrorResponse{
 				Error: oapigen.Error{
+					Code:    &code,
 					Message: msg,
 				},
 				RequestId: r.requestID,
@@ -96,3 +98,37 @@ func (r *loggerResponseWriter) WriteHeader(code int) {
 	r.statusCode = code
 	r.ResponseWriter.WriteHeader(code)
 }
+
+// idempotentResponseWriter is a wrapper around the standard http response
+// writer that captures the response to cache for an idempotency key
+type idempotentResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	buf        bytes.Buffer
+}
+
+// WriteHeader handles writing the header and captures the
+// status code
+func (r *idempotentResponseWriter) WriteHeader(code int) {
+	r.statusCode = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Write captures the response body and writes it to the underlying writer
+func (r *idempotentResponseWriter) Write(p []byte) (int, error) {
+	if r.statusCode == 0 {
+		r.statusCode = http.StatusOK
+	}
+	r.buf.Write(p)
+	return r.ResponseWriter.Write(p)
+}
+
+// response returns the captured response for the request fingerprint
+func (r *idempotentResponseWriter) response(fingerprint string) *idempotentResponse {
+	return &idempotentResponse{
+		fingerprint: fingerprint,
+		statusCode:  r.statusCode,
+		contentType: r.Header().Get("Content-Type"),
+		body:        r.buf.Bytes(),
+	}
+}
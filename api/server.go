@@ -5,9 +5,15 @@ package api
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/driver"
 	"github.com/hashicorp/consul-terraform-sync/state/event"
+	"github.com/hashicorp/consul-terraform-sync/state/plan"
+	"github.com/hashicorp/consul-terraform-sync/state/revision"
+	"github.com/hashicorp/consul-terraform-sync/templates"
 )
 
 //go:generate mockery --name=Server --filename=server.go --output=../mocks/server
@@ -15,17 +21,37 @@ import (
 // Server represents the Controller methods used for the API server
 type Server interface {
 	Config() config.Config
+	DependencyStats(ctx context.Context) templates.DependencyStats
 	Events(ctx context.Context, taskName string) (map[string][]event.Event, error)
 
 	Task(ctx context.Context, taskName string) (config.TaskConfig, error)
+	TaskCircuitBreakerOpen(ctx context.Context, taskName string) (time.Time, bool)
 	TaskCreate(context.Context, config.TaskConfig) (config.TaskConfig, error)
 	TaskCreateAndRun(context.Context, config.TaskConfig) (config.TaskConfig, error)
 	TaskDelete(ctx context.Context, taskName string) error
+	TaskDependencyTriggers(ctx context.Context, taskName string) map[string]int
+	TaskInputsDiff(ctx context.Context, taskName, from, to string) (driver.InputsDiff, error)
 	// TODO: update signatures to return a new run object
 	TaskInspect(context.Context, config.TaskConfig) (bool, string, string, error)
+	TaskInventory(ctx context.Context, taskName string) (driver.Inventory, error)
+	TaskLock(ctx context.Context, taskName, holder, reason string) (driver.TaskLock, error)
+	TaskLockStatus(ctx context.Context, taskName string) (driver.TaskLock, bool)
+	TaskNextScheduledRun(ctx context.Context, taskName string) (time.Time, bool)
+	TaskPendingRuns(ctx context.Context, taskName string) []time.Time
+	TaskPlan(ctx context.Context, taskName, eventID string) (plan.Artifact, error)
+	TaskProgress(ctx context.Context, taskName string) (driver.Progress, error)
+	TaskReadiness(ctx context.Context, taskName string) (driver.Readiness, error)
+	TaskReinit(ctx context.Context, taskName string) (driver.RenderResult, error)
+	TaskRender(ctx context.Context, taskName string) (driver.RenderResult, error)
+	TaskRestoreRevision(ctx context.Context, taskName string, id int) (config.TaskConfig, error)
+	TaskRevisions(ctx context.Context, taskName string) ([]revision.Revision, error)
+	TaskStats(ctx context.Context, taskName string) (event.Stats, bool)
+	TaskUnlock(ctx context.Context, taskName string) error
 	// TODO: update signature with an update config object since only a subset of
 	// options can be changed and determine the location of sharable objects
 	// across packages
 	TaskUpdate(ctx context.Context, updateConf config.TaskConfig, runOp string) (bool, string, string, error)
+	TaskValidate(context.Context, config.TaskConfig) (driver.ModuleValidation, error)
 	Tasks(context.Context) config.TaskConfigs
+	WriteDiagnostics(ctx context.Context, w io.Writer) error
 }
@@ -18,6 +18,7 @@ import (
 	apiMocks "github.com/hashicorp/consul-terraform-sync/mocks/api"
 	mocks "github.com/hashicorp/consul-terraform-sync/mocks/server"
 	"github.com/hashicorp/consul-terraform-sync/state/event"
+	"github.com/hashicorp/consul-terraform-sync/templates"
 	"github.com/hashicorp/consul-terraform-sync/testutils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -124,6 +125,11 @@ func TestStatus(t *testing.T) {
 	}
 	ctrl.On("Tasks", mock.Anything).Return(confs)
 	ctrl.On("Events", mock.Anything, "").Return(events, nil)
+	ctrl.On("DependencyStats", mock.Anything).Return(templates.DependencyStats{})
+	ctrl.On("TaskPendingRuns", mock.Anything, mock.Anything).Return(nil)
+	ctrl.On("TaskCircuitBreakerOpen", mock.Anything, mock.Anything).Return(time.Time{}, false)
+	ctrl.On("TaskNextScheduledRun", mock.Anything, mock.Anything).Return(time.Time{}, false)
+	ctrl.On("TaskStats", mock.Anything, mock.Anything).Return(event.Stats{}, false)
 
 	// start up server
 	port := testutils.FreePort(t)
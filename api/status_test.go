@@ -29,7 +29,8 @@ func TestStatusHandlerDefault_GetClusterStatus(t *testing.T) {
 	err := decoder.Decode(&actual)
 	require.NoError(t, err)
 
-	expected := generateErrorResponse(reqID.String(), haNotAvailableError)
+	expected := generateErrorResponse(reqID.String(), ErrorCodeMethodNotAllowed,
+		haNotAvailableError)
 	assert.Equal(t, expected, actual)
 }
 
@@ -12,7 +12,9 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/hashicorp/consul-terraform-sync/api/oapigen"
 	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/hashicorp/consul-terraform-sync/state/revision"
 	"github.com/mitchellh/mapstructure"
 )
 
@@ -47,12 +49,50 @@ func (h *taskHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	logger := logging.FromContext(r.Context())
 	logger.Trace("requesting tasks", "url_path", r.URL.Path)
 
-	switch r.Method {
-	case http.MethodPatch:
+	revPath, isRevPath := getTaskRevisionPath(r.URL.Path, h.version)
+	isBatchPath := isTaskBatchPath(r.URL.Path, h.version)
+	isValidatePath := isTaskValidatePath(r.URL.Path, h.version)
+	lockTaskName, isLockPath := getTaskLockPath(r.URL.Path, h.version)
+	renderTaskName, isRenderPath := getTaskRenderPath(r.URL.Path, h.version)
+	inputsTaskName, isInputsDiffPath := getTaskInputsDiffPath(r.URL.Path, h.version)
+	reinitTaskName, isReinitPath := getTaskReinitPath(r.URL.Path, h.version)
+
+	switch {
+	case r.Method == http.MethodGet && isLockPath:
+		h.getTaskLock(w, r, lockTaskName)
+	case r.Method == http.MethodPost && isLockPath:
+		h.lockTask(w, r, lockTaskName)
+	case r.Method == http.MethodDelete && isLockPath:
+		h.unlockTask(w, r, lockTaskName)
+	case r.Method == http.MethodPost && isRenderPath:
+		h.renderTask(w, r, renderTaskName)
+	case r.Method == http.MethodPost && isReinitPath:
+		h.reinitTask(w, r, reinitTaskName)
+	case r.Method == http.MethodGet && isInputsDiffPath:
+		h.getTaskInputsDiff(w, r, inputsTaskName)
+	case r.Method == http.MethodPost && isBatchPath:
+		h.batchTasks(w, r)
+	case r.Method == http.MethodPost && isValidatePath:
+		h.validateTask(w, r)
+	case r.Method == http.MethodPatch && !isRevPath && !isLockPath && !isRenderPath &&
+		!isInputsDiffPath && !isReinitPath:
 		h.updateTask(w, r)
+	case r.Method == http.MethodGet && isRevPath && !revPath.restore:
+		h.getTaskRevisions(w, r, revPath.taskName)
+	case r.Method == http.MethodPost && isRevPath && revPath.restore:
+		h.restoreTaskRevision(w, r, revPath.taskName, revPath.revisionID)
 	default:
 		err := fmt.Errorf("'%s' in an unsupported method. The task API "+
-			"currently supports the method(s): '%s'", r.Method, http.MethodPatch)
+			"currently supports the method(s): '%s' for '/v1/tasks/:task_name', "+
+			"'%s' for '/v1/tasks/:task_name/revisions', '%s' for "+
+			"'/v1/tasks/:task_name/revisions/:revision_id/restore', '%s', '%s', "+
+			"and '%s' for '/v1/tasks/:task_name/lock', '%s' for "+
+			"'/v1/tasks/:task_name/render' and '/v1/tasks/:task_name/reinit', "+
+			"'%s' for '/v1/tasks/:task_name/inputs/diff', and '%s' for "+
+			"'/v1/tasks/batch' and '/v1/tasks/validate'", r.Method,
+			http.MethodPatch, http.MethodGet, http.MethodPost, http.MethodGet,
+			http.MethodPost, http.MethodDelete, http.MethodPost, http.MethodGet,
+			http.MethodPost)
 		logger.Trace("unsupported method", "error", err)
 		jsonErrorResponse(r.Context(), w, http.StatusMethodNotAllowed, err)
 	}
@@ -65,7 +105,8 @@ type UpdateTaskConfig struct {
 }
 
 type UpdateTaskResponse struct {
-	Inspect *InspectPlan `json:"inspect,omitempty"`
+	RequestID oapigen.RequestID `json:"request_id"`
+	Inspect   *InspectPlan      `json:"inspect,omitempty"`
 }
 
 type InspectPlan struct {
@@ -125,7 +166,7 @@ func (h *taskHandler) updateTask(w http.ResponseWriter, r *http.Request) {
 	tc, err := h.ctrl.Task(ctx, taskName)
 	if err != nil {
 		logger.Trace("task not found", "error", err)
-		sendError(w, r, http.StatusNotFound, err)
+		sendError(w, r, http.StatusNotFound, withErrorCode(ErrorCodeTaskNotFound, err))
 		return
 	}
 
@@ -140,7 +181,9 @@ func (h *taskHandler) updateTask(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Update the task
+	// Update the task. The API request is recorded as the actor of the
+	// task's revision
+	ctx = revision.WithActor(ctx, requestActor(r))
 	changes, plan, url, err := h.ctrl.TaskUpdate(ctx, tc, runOp)
 	if err != nil {
 		sendError(w, r, http.StatusInternalServerError, err)
@@ -149,16 +192,20 @@ func (h *taskHandler) updateTask(w http.ResponseWriter, r *http.Request) {
 
 	switch runOp {
 	case RunOptionInspect:
-		resp := UpdateTaskResponse{Inspect: &InspectPlan{
-			ChangesPresent: changes,
-			Plan:           plan,
-			URL:            url,
-		}}
+		resp := UpdateTaskResponse{
+			RequestID: requestIDFromContext(ctx),
+			Inspect: &InspectPlan{
+				ChangesPresent: changes,
+				Plan:           plan,
+				URL:            url,
+			},
+		}
 		if err = jsonResponse(w, http.StatusOK, &resp); err != nil {
 			logger.Error("error, could not generate json response", "error", err)
 		}
 	case RunOptionNow, "":
-		if err = jsonResponse(w, http.StatusOK, UpdateTaskResponse{}); err != nil {
+		resp := UpdateTaskResponse{RequestID: requestIDFromContext(ctx)}
+		if err = jsonResponse(w, http.StatusOK, resp); err != nil {
 			logger.Error("error, could not generate json response", "error", err)
 		}
 	}
@@ -167,6 +214,10 @@ func (h *taskHandler) updateTask(w http.ResponseWriter, r *http.Request) {
 type TaskLifeCycleHandler struct {
 	mu   sync.RWMutex
 	ctrl Server
+
+	// idempotencyKeys caches the outcome of task creation requests with an
+	// Idempotency-Key header. Nil if caching is disabled.
+	idempotencyKeys *idempotencyCache
 }
 
 func NewTaskLifeCycleHandler(ctrl Server) *TaskLifeCycleHandler {
@@ -0,0 +1,172 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/consul-terraform-sync/api/oapigen"
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/hashicorp/consul-terraform-sync/state/revision"
+)
+
+const (
+	batchTaskSubsystemName = "batchtask"
+
+	taskBatchPath = "batch"
+
+	BatchOperationDelete  = "delete"
+	BatchOperationEnable  = "enable"
+	BatchOperationDisable = "disable"
+)
+
+// TaskBatchRequest is the request of the task batch endpoint. The operation
+// is applied to the tasks with the names of Tasks or, alternatively, to all
+// tasks with names that match the Filter regular expression and that have all
+// of the Labels.
+type TaskBatchRequest struct {
+	Operation string            `json:"operation"`
+	Tasks     []string          `json:"tasks,omitempty"`
+	Filter    string            `json:"filter,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+// TaskBatchResult is the result of a batch operation for a single task
+type TaskBatchResult struct {
+	Task    string `json:"task"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// TaskBatchResponse is the response of the task batch endpoint
+type TaskBatchResponse struct {
+	RequestId oapigen.RequestID `json:"request_id"`
+	Results   []TaskBatchResult `json:"results"`
+}
+
+// isTaskBatchPath returns true if the path is the task batch path
+// /v1/tasks/batch
+func isTaskBatchPath(reqPath, version string) bool {
+	return reqPath == fmt.Sprintf("/%s/%s/%s", version, taskPath, taskBatchPath)
+}
+
+// validate checks the operation and that either tasks or at least one of
+// filter and labels is set, and returns the compiled filter if set
+func (req TaskBatchRequest) validate() (*regexp.Regexp, error) {
+	switch req.Operation {
+	case BatchOperationDelete, BatchOperationEnable, BatchOperationDisable:
+	case "":
+		return nil, errors.New("missing 'operation' from the request body")
+	default:
+		return nil, fmt.Errorf("unsupported operation '%s'. The task batch API "+
+			"currently supports the operation(s): '%s', '%s', and '%s'",
+			req.Operation, BatchOperationDelete, BatchOperationEnable,
+			BatchOperationDisable)
+	}
+
+	switch {
+	case len(req.Tasks) == 0 && req.Filter == "" && len(req.Labels) == 0:
+		return nil, errors.New("request body requires either 'tasks', " +
+			"'filter', or 'labels'")
+	case len(req.Tasks) > 0 && (req.Filter != "" || len(req.Labels) > 0):
+		return nil, errors.New("request body cannot have 'tasks' with " +
+			"'filter' or 'labels'")
+	case req.Filter != "":
+		re, err := regexp.Compile(req.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse 'filter' as a regular "+
+				"expression: %s", err)
+		}
+		return re, nil
+	}
+
+	return nil, nil
+}
+
+// batchTasks applies a bulk operation to a list of tasks. Each task is
+// operated on independently, and the result for each task is included in
+// the response.
+func (h *taskHandler) batchTasks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestID := requestIDFromContext(ctx)
+	logger := logging.FromContext(ctx).Named(batchTaskSubsystemName)
+	logger.Trace("batch task request")
+
+	var req TaskBatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Trace("problem decoding body from batch request", "error", err)
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	filter, err := req.validate()
+	if err != nil {
+		logger.Trace("bad request", "error", err)
+		sendError(w, r, http.StatusBadRequest,
+			withErrorCode(ErrorCodeValidationFailed, err))
+		return
+	}
+	logger = logger.With("operation", req.Operation)
+
+	tasks := make(map[string]config.TaskConfig)
+	for _, tc := range h.ctrl.Tasks(ctx) {
+		name := config.StringVal(tc.Name)
+		tasks[name] = *tc
+	}
+
+	names := req.Tasks
+	if len(names) == 0 {
+		names = make([]string, 0)
+		for name, tc := range tasks {
+			if filter != nil && !filter.MatchString(name) {
+				continue
+			}
+			if !tc.HasLabels(req.Labels) {
+				continue
+			}
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	// The API request is recorded as the actor of the tasks' revisions
+	ctx = revision.WithActor(ctx, requestActor(r))
+
+	resp := TaskBatchResponse{
+		RequestId: requestID,
+		Results:   make([]TaskBatchResult, 0, len(names)),
+	}
+	for _, name := range names {
+		result := TaskBatchResult{Task: name}
+
+		tc, ok := tasks[name]
+		switch {
+		case !ok:
+			err = fmt.Errorf("a task with name '%s' does not exist", name)
+		case req.Operation == BatchOperationDelete:
+			err = h.ctrl.TaskDelete(ctx, name)
+		default:
+			tc.Enabled = config.Bool(req.Operation == BatchOperationEnable)
+			_, _, _, err = h.ctrl.TaskUpdate(ctx, tc, "")
+		}
+
+		if err != nil {
+			logger.Trace("unable to apply operation to task", "task_name", name,
+				"error", err)
+			result.Error = err.Error()
+		} else {
+			result.Success = true
+		}
+		resp.Results = append(resp.Results, result)
+	}
+
+	logger.Info("applied batch operation to tasks", "task_count", len(names))
+	writeResponse(w, r, http.StatusOK, resp)
+}
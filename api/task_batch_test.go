@@ -0,0 +1,229 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	serverMocks "github.com/hashicorp/consul-terraform-sync/mocks/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskBatchRequest_validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		req     TaskBatchRequest
+		isValid bool
+	}{
+		{
+			"tasks",
+			TaskBatchRequest{Operation: BatchOperationDelete, Tasks: []string{"task_a"}},
+			true,
+		},
+		{
+			"filter",
+			TaskBatchRequest{Operation: BatchOperationDisable, Filter: "^web-"},
+			true,
+		},
+		{
+			"labels",
+			TaskBatchRequest{Operation: BatchOperationDisable,
+				Labels: map[string]string{"team": "neteng"}},
+			true,
+		},
+		{
+			"filter and labels",
+			TaskBatchRequest{Operation: BatchOperationDisable, Filter: "^web-",
+				Labels: map[string]string{"team": "neteng"}},
+			true,
+		},
+		{
+			"missing operation",
+			TaskBatchRequest{Tasks: []string{"task_a"}},
+			false,
+		},
+		{
+			"unsupported operation",
+			TaskBatchRequest{Operation: "run", Tasks: []string{"task_a"}},
+			false,
+		},
+		{
+			"missing tasks and filter",
+			TaskBatchRequest{Operation: BatchOperationEnable},
+			false,
+		},
+		{
+			"both tasks and filter",
+			TaskBatchRequest{Operation: BatchOperationEnable,
+				Tasks: []string{"task_a"}, Filter: "task"},
+			false,
+		},
+		{
+			"both tasks and labels",
+			TaskBatchRequest{Operation: BatchOperationEnable,
+				Tasks: []string{"task_a"}, Labels: map[string]string{"team": "neteng"}},
+			false,
+		},
+		{
+			"invalid filter",
+			TaskBatchRequest{Operation: BatchOperationEnable, Filter: "task_("},
+			false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.req.validate()
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestTaskBatch_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	tasks := config.TaskConfigs{
+		{Name: config.String("web-a"), Enabled: config.Bool(true),
+			Labels: map[string]string{"team": "neteng", "env": "prod"}},
+		{Name: config.String("web-b"), Enabled: config.Bool(true),
+			Labels: map[string]string{"team": "neteng", "env": "dev"}},
+		{Name: config.String("db"), Enabled: config.Bool(true),
+			Labels: map[string]string{"team": "dba", "env": "prod"}},
+	}
+
+	cases := []struct {
+		name       string
+		body       string
+		setup      func(*serverMocks.Server)
+		statusCode int
+		expected   []TaskBatchResult
+	}{
+		{
+			"delete tasks",
+			`{"operation": "delete", "tasks": ["web-a", "db", "missing"]}`,
+			func(ctrl *serverMocks.Server) {
+				ctrl.On("TaskDelete", mock.Anything, "web-a").Return(nil).Once()
+				ctrl.On("TaskDelete", mock.Anything, "db").Return(
+					errors.New("delete error")).Once()
+			},
+			http.StatusOK,
+			[]TaskBatchResult{
+				{Task: "web-a", Success: true},
+				{Task: "db", Error: "delete error"},
+				{Task: "missing", Error: "a task with name 'missing' does not exist"},
+			},
+		},
+		{
+			"disable filter",
+			`{"operation": "disable", "filter": "^web-"}`,
+			func(ctrl *serverMocks.Server) {
+				ctrl.On("TaskUpdate", mock.Anything, mock.MatchedBy(func(tc config.TaskConfig) bool {
+					return strings.HasPrefix(*tc.Name, "web-") && !*tc.Enabled
+				}), "").Return(false, "", "", nil).Twice()
+			},
+			http.StatusOK,
+			[]TaskBatchResult{
+				{Task: "web-a", Success: true},
+				{Task: "web-b", Success: true},
+			},
+		},
+		{
+			"enable tasks",
+			`{"operation": "enable", "tasks": ["db"]}`,
+			func(ctrl *serverMocks.Server) {
+				ctrl.On("TaskUpdate", mock.Anything, mock.MatchedBy(func(tc config.TaskConfig) bool {
+					return *tc.Name == "db" && *tc.Enabled
+				}), "").Return(false, "", "", nil).Once()
+			},
+			http.StatusOK,
+			[]TaskBatchResult{
+				{Task: "db", Success: true},
+			},
+		},
+		{
+			"disable labels",
+			`{"operation": "disable", "labels": {"env": "prod"}}`,
+			func(ctrl *serverMocks.Server) {
+				ctrl.On("TaskUpdate", mock.Anything, mock.MatchedBy(func(tc config.TaskConfig) bool {
+					return tc.Labels["env"] == "prod" && !*tc.Enabled
+				}), "").Return(false, "", "", nil).Twice()
+			},
+			http.StatusOK,
+			[]TaskBatchResult{
+				{Task: "db", Success: true},
+				{Task: "web-a", Success: true},
+			},
+		},
+		{
+			"delete filter and labels",
+			`{"operation": "delete", "filter": "^web-", "labels": {"env": "dev"}}`,
+			func(ctrl *serverMocks.Server) {
+				ctrl.On("TaskDelete", mock.Anything, "web-b").Return(nil).Once()
+			},
+			http.StatusOK,
+			[]TaskBatchResult{
+				{Task: "web-b", Success: true},
+			},
+		},
+		{
+			"filter matches no tasks",
+			`{"operation": "delete", "filter": "^api-"}`,
+			func(ctrl *serverMocks.Server) {},
+			http.StatusOK,
+			[]TaskBatchResult{},
+		},
+		{
+			"invalid request",
+			`{"operation": "delete"}`,
+			func(ctrl *serverMocks.Server) {},
+			http.StatusBadRequest,
+			nil,
+		},
+		{
+			"invalid body",
+			`{"operation": `,
+			func(ctrl *serverMocks.Server) {},
+			http.StatusBadRequest,
+			nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := new(serverMocks.Server)
+			ctrl.On("Tasks", mock.Anything).Maybe().Return(tasks)
+			tc.setup(ctrl)
+			handler := newTaskHandler(ctrl, "v1")
+
+			req, err := http.NewRequest(http.MethodPost, "/v1/tasks/batch",
+				strings.NewReader(tc.body))
+			require.NoError(t, err)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+			require.Equal(t, tc.statusCode, resp.Code)
+			ctrl.AssertExpectations(t)
+
+			if tc.expected == nil {
+				return
+			}
+			var actual TaskBatchResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&actual))
+			assert.Equal(t, tc.expected, actual.Results)
+		})
+	}
+}
@@ -4,29 +4,84 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 
 	"github.com/hashicorp/consul-terraform-sync/api/oapigen"
 	"github.com/hashicorp/consul-terraform-sync/config"
 	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/hashicorp/consul-terraform-sync/state/revision"
 )
 
 // CreateTask creates a task
 func (h *TaskLifeCycleHandler) CreateTask(w http.ResponseWriter, r *http.Request, params oapigen.CreateTaskParams) {
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
 	ctx := r.Context()
 	logger := logging.FromContext(ctx).Named(createTaskSubsystemName)
 	logger.Trace("create task request received, reading request")
 
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		logger.Error("unable to read request body", "error", err)
+		sendError(w, r, http.StatusBadRequest,
+			fmt.Errorf("error reading the request: %v", err))
+		return
+	}
+
+	// Respond to retried requests with the cached outcome of the first
+	// request with the same idempotency key
+	if key := params.IdempotencyKey; key != nil && *key != "" && h.idempotencyKeys != nil {
+		var run string
+		if params.Run != nil {
+			run = string(*params.Run)
+		}
+		fingerprint := requestFingerprint([]byte(run), body)
+
+		cached, reserved, err := h.idempotencyKeys.Reserve(ctx, *key)
+		if err != nil {
+			logger.Trace("canceled waiting for request with idempotency key")
+			sendError(w, r, http.StatusConflict, fmt.Errorf("a request with "+
+				"%s %q is in progress", idempotencyKeyHeader, *key))
+			return
+		}
+		if !reserved {
+			if cached.fingerprint != fingerprint {
+				logger.Trace("idempotency key reused for a different request")
+				sendError(w, r, http.StatusUnprocessableEntity,
+					withErrorCode(ErrorCodeIdempotencyKeyUsed, fmt.Errorf(
+						"%s %q was already used for a different request",
+						idempotencyKeyHeader, *key)))
+				return
+			}
+			logger.Trace("replaying response for idempotency key")
+			cached.write(w)
+			return
+		}
+
+		rw := &idempotentResponseWriter{ResponseWriter: w}
+		w = rw
+		defer func() {
+			// server errors are not cached so that the request can be retried
+			if rw.statusCode != 0 && !CheckStatusCodeCategory(
+				ServerErrorResponseCategory, rw.statusCode) {
+				h.idempotencyKeys.Set(*key, rw.response(fingerprint))
+				return
+			}
+			h.idempotencyKeys.Release(*key)
+		}()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
 	// Decode the task request
 	var req TaskRequest
 	requestID := requestIDFromContext(ctx)
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		logger.Error("bad request", "error", err, "create_task_request", r.Body)
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&req); err != nil {
+		logger.Error("bad request", "error", err, "create_task_request", string(body))
 		sendError(w, r, http.StatusBadRequest,
 			fmt.Errorf("error decoding the request: %v", err))
 		return
@@ -39,7 +94,8 @@ func (h *TaskLifeCycleHandler) CreateTask(w http.ResponseWriter, r *http.Request
 	if _, err := h.ctrl.Task(ctx, req.Task.Name); err == nil {
 		logger.Trace("task already exists")
 		sendError(w, r, http.StatusBadRequest,
-			fmt.Errorf("task with name %s already exists", req.Task.Name))
+			withErrorCode(ErrorCodeTaskExists, fmt.Errorf(
+				"task with name %s already exists", req.Task.Name)))
 		return
 	}
 
@@ -48,10 +104,14 @@ func (h *TaskLifeCycleHandler) CreateTask(w http.ResponseWriter, r *http.Request
 	if err != nil {
 		err = fmt.Errorf("error with task configuration: %s", err)
 		logger.Error("error creating task", "error", err)
-		sendError(w, r, http.StatusBadRequest, err)
+		sendError(w, r, http.StatusBadRequest,
+			withErrorCode(ErrorCodeValidationFailed, err))
 		return
 	}
 
+	// Record the API request as the actor of the task's revision
+	ctx = revision.WithActor(ctx, requestActor(r))
+
 	var tc config.TaskConfig
 	if params.Run == nil || *params.Run == "" {
 		tc, err = h.ctrl.TaskCreate(ctx, trc)
@@ -65,6 +125,13 @@ func (h *TaskLifeCycleHandler) CreateTask(w http.ResponseWriter, r *http.Request
 	}
 
 	if err != nil {
+		var refErr *config.ConsulReferenceError
+		if errors.As(err, &refErr) {
+			logger.Trace("invalid Consul references", "error", err)
+			sendError(w, r, http.StatusBadRequest,
+				withErrorCode(ErrorCodeValidationFailed, err))
+			return
+		}
 		sendError(w, r, http.StatusInternalServerError, err)
 		return
 	}
@@ -9,7 +9,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/consul-terraform-sync/api/oapigen"
@@ -122,6 +124,108 @@ func TestTaskLifeCycleHandler_CreateTask(t *testing.T) {
 	}
 }
 
+func TestTaskLifeCycleHandler_CreateTask_IdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	ctrl := new(mocks.Server)
+	ctrl.On("Task", mock.Anything, testTaskName).Return(config.TaskConfig{}, fmt.Errorf("DNE")).Once().
+		On("TaskCreateAndRun", mock.Anything, testTaskConfig).Return(testTaskConfig, nil).Once()
+	handler := NewTaskLifeCycleHandler(ctrl)
+	handler.idempotencyKeys = newIdempotencyCache(time.Minute)
+
+	createTask := func(request string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodPost, "/v1/tasks", strings.NewReader(request))
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+
+		runOp := oapigen.CreateTaskParamsRun(RunOptionNow)
+		key := "retry-key"
+		handler.CreateTask(resp, req, oapigen.CreateTaskParams{
+			Run:            &runOp,
+			IdempotencyKey: &key,
+		})
+		return resp
+	}
+
+	first := createTask(testTaskJSON)
+	require.Equal(t, http.StatusCreated, first.Code)
+	assert.Empty(t, first.Header().Get(idempotentReplayedHeader))
+
+	t.Run("retry", func(t *testing.T) {
+		retry := createTask(testTaskJSON)
+		require.Equal(t, http.StatusCreated, retry.Code)
+		assert.Equal(t, "true", retry.Header().Get(idempotentReplayedHeader))
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+
+		// task is only created and run once
+		ctrl.AssertNumberOfCalls(t, "TaskCreateAndRun", 1)
+	})
+
+	t.Run("different request", func(t *testing.T) {
+		request := strings.Replace(testTaskJSON, "test.txt", "other.txt", 1)
+		resp := createTask(request)
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+		assert.Contains(t, resp.Body.String(), "already used for a different request")
+	})
+}
+
+func TestTaskLifeCycleHandler_CreateTask_IdempotencyKeyConcurrent(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	ctrl := new(mocks.Server)
+	ctrl.On("Task", mock.Anything, testTaskName).Return(config.TaskConfig{}, fmt.Errorf("DNE")).Once().
+		On("TaskCreateAndRun", mock.Anything, testTaskConfig).Return(testTaskConfig, nil).
+		Run(func(mock.Arguments) {
+			close(started)
+			<-unblock
+		}).Once()
+	handler := NewTaskLifeCycleHandler(ctrl)
+	handler.idempotencyKeys = newIdempotencyCache(time.Minute)
+
+	createTask := func() *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodPost, "/v1/tasks", strings.NewReader(testTaskJSON))
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+
+		runOp := oapigen.CreateTaskParamsRun(RunOptionNow)
+		key := "concurrent-key"
+		handler.CreateTask(resp, req, oapigen.CreateTaskParams{
+			Run:            &runOp,
+			IdempotencyKey: &key,
+		})
+		return resp
+	}
+
+	var wg sync.WaitGroup
+	var first, retry *httptest.ResponseRecorder
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		first = createTask()
+	}()
+	<-started
+	go func() {
+		defer wg.Done()
+		retry = createTask()
+	}()
+
+	// retry is in flight while the first request is still running the task
+	time.Sleep(50 * time.Millisecond)
+	close(unblock)
+	wg.Wait()
+
+	require.Equal(t, http.StatusCreated, first.Code)
+	require.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get(idempotentReplayedHeader))
+	assert.Equal(t, first.Body.String(), retry.Body.String())
+
+	// task is only created and run once
+	ctrl.AssertNumberOfCalls(t, "TaskCreateAndRun", 1)
+	ctrl.AssertNumberOfCalls(t, "Task", 1)
+}
+
 func TestTaskLifeCycleHandler_CreateTask_RunInspect(t *testing.T) {
 	t.Parallel()
 
@@ -158,6 +262,7 @@ func TestTaskLifeCycleHandler_CreateTask_BadRequest(t *testing.T) {
 		request    string
 		statusCode int
 		run        string
+		code       string
 		message    string
 	}{
 		{
@@ -176,6 +281,7 @@ func TestTaskLifeCycleHandler_CreateTask_BadRequest(t *testing.T) {
 					"module": "./example-module"
 				}
 			}`, existingTask),
+			code:       ErrorCodeTaskExists,
 			message:    fmt.Sprintf("task with name %s already exists", existingTask),
 			statusCode: http.StatusBadRequest,
 		},
@@ -183,6 +289,7 @@ func TestTaskLifeCycleHandler_CreateTask_BadRequest(t *testing.T) {
 			name:       "empty request",
 			taskName:   testTaskName,
 			request:    "",
+			code:       ErrorCodeBadRequest,
 			message:    "error decoding the request: EOF",
 			statusCode: http.StatusBadRequest,
 		},
@@ -202,7 +309,7 @@ func TestTaskLifeCycleHandler_CreateTask_BadRequest(t *testing.T) {
 			err := decoder.Decode(&actual)
 			require.NoError(t, err)
 
-			expected := generateErrorResponse(uuid.UUID{}.String(), tc.message)
+			expected := generateErrorResponse(uuid.UUID{}.String(), tc.code, tc.message)
 			assert.Equal(t, expected, actual)
 		})
 	}
@@ -226,7 +333,33 @@ func TestTaskLifeCycleHandler_CreateTask_InternalError(t *testing.T) {
 	err := decoder.Decode(&actual)
 	require.NoError(t, err)
 
-	expected := generateErrorResponse(uuid.UUID{}.String(), errMsg)
+	expected := generateErrorResponse(uuid.UUID{}.String(), ErrorCodeInternal, errMsg)
+	assert.Equal(t, expected, actual)
+}
+
+func TestTaskLifeCycleHandler_CreateTask_ConsulReferences(t *testing.T) {
+	t.Parallel()
+
+	refErr := &config.ConsulReferenceError{
+		Task:       testTaskName,
+		References: []string{`module_input "consul-kv": datacenter "dc2" does not exist`},
+	}
+
+	ctrl := new(mocks.Server)
+	ctrl.On("Task", mock.Anything, testTaskName).Return(config.TaskConfig{}, fmt.Errorf("DNE"))
+	ctrl.On("TaskCreate", mock.Anything, mock.Anything).Return(config.TaskConfig{}, refErr)
+	handler := NewTaskLifeCycleHandler(ctrl)
+
+	resp := runTestCreateTask(t, handler, "", http.StatusBadRequest, testTaskJSON)
+
+	// Check response
+	decoder := json.NewDecoder(resp.Body)
+	var actual oapigen.ErrorResponse
+	err := decoder.Decode(&actual)
+	require.NoError(t, err)
+
+	expected := generateErrorResponse(uuid.UUID{}.String(),
+		ErrorCodeValidationFailed, refErr.Error())
 	assert.Equal(t, expected, actual)
 }
 
@@ -246,9 +379,10 @@ func generateExpectedResponse(t *testing.T, req string) oapigen.TaskResponse {
 	}
 }
 
-func generateErrorResponse(requestID, message string) oapigen.ErrorResponse {
+func generateErrorResponse(requestID, code, message string) oapigen.ErrorResponse {
 	errResp := oapigen.ErrorResponse{
 		Error: oapigen.Error{
+			Code:    &code,
 			Message: message,
 		},
 		RequestId: uuid.MustParse(requestID),
@@ -25,7 +25,7 @@ func (h *TaskLifeCycleHandler) DeleteTaskByName(w http.ResponseWriter, r *http.R
 	_, err := h.ctrl.Task(ctx, name)
 	if err != nil {
 		logger.Trace("task not found", "error", err)
-		sendError(w, r, http.StatusNotFound, err)
+		sendError(w, r, http.StatusNotFound, withErrorCode(ErrorCodeTaskNotFound, err))
 		return
 	}
 
@@ -4,13 +4,19 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
+	"sort"
+	"strings"
 
+	"github.com/hashicorp/consul-terraform-sync/api/oapigen"
+	"github.com/hashicorp/consul-terraform-sync/config"
 	"github.com/hashicorp/consul-terraform-sync/logging"
 )
 
-// GetAllTasks retrieves all tasks currently managed by CTS, and returns their information
-func (h *TaskLifeCycleHandler) GetAllTasks(w http.ResponseWriter, r *http.Request) {
+// GetAllTasks retrieves all tasks currently managed by CTS, and returns their
+// information. The tasks are filtered and paginated by the query parameters.
+func (h *TaskLifeCycleHandler) GetAllTasks(w http.ResponseWriter, r *http.Request, params oapigen.GetAllTasksParams) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
@@ -19,10 +25,36 @@ func (h *TaskLifeCycleHandler) GetAllTasks(w http.ResponseWriter, r *http.Reques
 	logger := logging.FromContext(ctx).Named(getTaskSubsystemName)
 	logger.Trace("get all tasks request")
 
+	if params.Limit != nil && *params.Limit < 1 {
+		err := fmt.Errorf("limit must be greater than 0, got %d", *params.Limit)
+		logger.Trace("bad request", "error", err)
+		sendError(w, r, http.StatusBadRequest,
+			withErrorCode(ErrorCodeValidationFailed, err))
+		return
+	}
+
+	var labels map[string]string
+	if params.Label != nil {
+		var err error
+		labels, err = config.ParseLabelSelector(*params.Label)
+		if err != nil {
+			logger.Trace("bad request", "error", err)
+			sendError(w, r, http.StatusBadRequest,
+				withErrorCode(ErrorCodeValidationFailed, err))
+			return
+		}
+	}
+
 	// Retrieve all tasks
-	taskConfigs := h.ctrl.Tasks(ctx)
+	taskConfigs := filterTasks(h.ctrl.Tasks(ctx), params, labels)
+	total := len(taskConfigs)
+	taskConfigs, next := paginateTasks(taskConfigs, params)
 
 	tasksResponse := tasksResponseFromTaskConfigs(taskConfigs, requestID)
+	tasksResponse.Total = &total
+	if next != "" {
+		tasksResponse.Next = &next
+	}
 	writeResponse(w, r, http.StatusOK, tasksResponse)
 
 	logger.Trace("tasks retrieved", "get_tasks_response", tasksResponse)
@@ -42,7 +74,7 @@ func (h *TaskLifeCycleHandler) GetTaskByName(w http.ResponseWriter, r *http.Requ
 	taskConfig, err := h.ctrl.Task(ctx, name)
 	if err != nil {
 		logger.Trace("task not found", "error", err)
-		sendError(w, r, http.StatusNotFound, err)
+		sendError(w, r, http.StatusNotFound, withErrorCode(ErrorCodeTaskNotFound, err))
 		return
 	}
 
@@ -51,3 +83,76 @@ func (h *TaskLifeCycleHandler) GetTaskByName(w http.ResponseWriter, r *http.Requ
 
 	logger.Trace("task retrieved", "get_task_response", resp)
 }
+
+// filterTasks returns the tasks that match the prefix, condition type, and
+// enabled filters of the parameters and have all of the labels
+func filterTasks(tcs config.TaskConfigs, params oapigen.GetAllTasksParams,
+	labels map[string]string) config.TaskConfigs {
+	filtered := make(config.TaskConfigs, 0, len(tcs))
+	for _, tc := range tcs {
+		if params.Prefix != nil &&
+			!strings.HasPrefix(config.StringVal(tc.Name), *params.Prefix) {
+			continue
+		}
+		if params.ConditionType != nil &&
+			conditionType(tc.Condition) != string(*params.ConditionType) {
+			continue
+		}
+		if params.Enabled != nil && config.BoolVal(tc.Enabled) != *params.Enabled {
+			continue
+		}
+		if !tc.HasLabels(labels) {
+			continue
+		}
+		filtered = append(filtered, tc)
+	}
+	return filtered
+}
+
+// paginateTasks returns the page of tasks after the task name of the after
+// parameter, up to the limit. Tasks are ordered by name when paginated.
+// Returns the name of the last task of the page if there are more tasks.
+func paginateTasks(tcs config.TaskConfigs, params oapigen.GetAllTasksParams) (config.TaskConfigs, string) {
+	if params.Limit == nil && params.After == nil {
+		return tcs, ""
+	}
+
+	sort.Slice(tcs, func(i, j int) bool {
+		return config.StringVal(tcs[i].Name) < config.StringVal(tcs[j].Name)
+	})
+
+	if params.After != nil {
+		i := sort.Search(len(tcs), func(i int) bool {
+			return config.StringVal(tcs[i].Name) > *params.After
+		})
+		tcs = tcs[i:]
+	}
+
+	if params.Limit == nil || len(tcs) <= *params.Limit {
+		return tcs, ""
+	}
+	tcs = tcs[:*params.Limit]
+	return tcs, config.StringVal(tcs[len(tcs)-1].Name)
+}
+
+// conditionType returns the type of the condition as named by the API
+func conditionType(c config.ConditionConfig) string {
+	switch c.(type) {
+	case *config.CatalogServicesConditionConfig:
+		return "catalog_services"
+	case *config.ConsulKVConditionConfig:
+		return "consul_kv"
+	case *config.DNSConditionConfig:
+		return "dns"
+	case *config.FileConditionConfig:
+		return "file"
+	case *config.PluginConditionConfig:
+		return "plugin"
+	case *config.ScheduleConditionConfig:
+		return "schedule"
+	case *config.ServicesConditionConfig:
+		return "services"
+	default:
+		return ""
+	}
+}
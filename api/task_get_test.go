@@ -96,7 +96,7 @@ func TestTaskLifeCycleHandler_GetAllTasks(t *testing.T) {
 	require.NoError(t, err)
 	resp := httptest.NewRecorder()
 
-	handler.GetAllTasks(resp, req)
+	handler.GetAllTasks(resp, req, oapigen.GetAllTasksParams{})
 	assert.Equal(t, http.StatusOK, resp.Code)
 
 	decoder := json.NewDecoder(resp.Body)
@@ -108,3 +108,175 @@ func TestTaskLifeCycleHandler_GetAllTasks(t *testing.T) {
 	assert.ElementsMatch(t, *expectedTasksResponse.Tasks, *actual.Tasks)
 	assert.ElementsMatch(t, expectedTasksResponse.RequestId, reqID)
 }
+
+func TestTaskLifeCycleHandler_GetAllTasks_Filters(t *testing.T) {
+	t.Parallel()
+
+	newTask := func(name string, enabled bool, cond config.ConditionConfig) *config.TaskConfig {
+		return &config.TaskConfig{
+			Name:      config.String(name),
+			Enabled:   config.Bool(enabled),
+			Condition: cond,
+		}
+	}
+	taskConfigs := config.TaskConfigs{
+		newTask("lb-web", true, &config.ServicesConditionConfig{}),
+		newTask("fw-web", true, &config.ConsulKVConditionConfig{
+			ConsulKVMonitorConfig: config.ConsulKVMonitorConfig{Path: config.String("key")}}),
+		newTask("lb-api", false, &config.ServicesConditionConfig{}),
+		newTask("lb-db", true, &config.ScheduleConditionConfig{
+			ScheduleMonitorConfig: config.ScheduleMonitorConfig{Cron: config.String("* * * * *")}}),
+		newTask("fw-api", false, &config.ServicesConditionConfig{}),
+	}
+	taskConfigs[0].Labels = map[string]string{"team": "neteng", "env": "prod"}
+	taskConfigs[1].Labels = map[string]string{"team": "security", "env": "prod"}
+	taskConfigs[2].Labels = map[string]string{"team": "neteng", "env": "dev"}
+
+	services := oapigen.GetAllTasksParamsConditionType("services")
+	cases := []struct {
+		name   string
+		params oapigen.GetAllTasksParams
+		tasks  []string
+		next   string
+		total  int
+	}{
+		{
+			"no filters",
+			oapigen.GetAllTasksParams{},
+			[]string{"lb-web", "fw-web", "lb-api", "lb-db", "fw-api"},
+			"",
+			5,
+		},
+		{
+			"prefix",
+			oapigen.GetAllTasksParams{Prefix: config.String("lb-")},
+			[]string{"lb-web", "lb-api", "lb-db"},
+			"",
+			3,
+		},
+		{
+			"condition type",
+			oapigen.GetAllTasksParams{ConditionType: &services},
+			[]string{"lb-web", "lb-api", "fw-api"},
+			"",
+			3,
+		},
+		{
+			"enabled",
+			oapigen.GetAllTasksParams{Enabled: config.Bool(false)},
+			[]string{"lb-api", "fw-api"},
+			"",
+			2,
+		},
+		{
+			"label",
+			oapigen.GetAllTasksParams{Label: config.String("team=neteng")},
+			[]string{"lb-web", "lb-api"},
+			"",
+			2,
+		},
+		{
+			"multiple labels",
+			oapigen.GetAllTasksParams{Label: config.String("env=prod,team=neteng")},
+			[]string{"lb-web"},
+			"",
+			1,
+		},
+		{
+			"combined filters",
+			oapigen.GetAllTasksParams{
+				Prefix:        config.String("lb-"),
+				ConditionType: &services,
+				Enabled:       config.Bool(true),
+			},
+			[]string{"lb-web"},
+			"",
+			1,
+		},
+		{
+			"first page",
+			oapigen.GetAllTasksParams{Limit: config.Int(2)},
+			[]string{"fw-api", "fw-web"},
+			"fw-web",
+			5,
+		},
+		{
+			"next page",
+			oapigen.GetAllTasksParams{Limit: config.Int(2), After: config.String("fw-web")},
+			[]string{"lb-api", "lb-db"},
+			"lb-db",
+			5,
+		},
+		{
+			"last page",
+			oapigen.GetAllTasksParams{Limit: config.Int(2), After: config.String("lb-db")},
+			[]string{"lb-web"},
+			"",
+			5,
+		},
+		{
+			"paginated filter",
+			oapigen.GetAllTasksParams{Prefix: config.String("lb-"), Limit: config.Int(10)},
+			[]string{"lb-api", "lb-db", "lb-web"},
+			"",
+			3,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := new(mocks.Server)
+			ctrl.On("Tasks", mock.Anything).Return(*taskConfigs.Copy())
+			handler := NewTaskLifeCycleHandler(ctrl)
+
+			req, err := http.NewRequest(http.MethodGet, "/v1/tasks", nil)
+			require.NoError(t, err)
+			resp := httptest.NewRecorder()
+
+			handler.GetAllTasks(resp, req, tc.params)
+			require.Equal(t, http.StatusOK, resp.Code)
+
+			var actual oapigen.TasksResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&actual))
+
+			names := make([]string, 0, len(*actual.Tasks))
+			for _, task := range *actual.Tasks {
+				names = append(names, task.Name)
+			}
+			assert.Equal(t, tc.tasks, names)
+			assert.Equal(t, tc.total, *actual.Total)
+			if tc.next == "" {
+				assert.Nil(t, actual.Next)
+			} else {
+				require.NotNil(t, actual.Next)
+				assert.Equal(t, tc.next, *actual.Next)
+			}
+		})
+	}
+
+	t.Run("invalid limit", func(t *testing.T) {
+		ctrl := new(mocks.Server)
+		handler := NewTaskLifeCycleHandler(ctrl)
+
+		req, err := http.NewRequest(http.MethodGet, "/v1/tasks", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+
+		handler.GetAllTasks(resp, req, oapigen.GetAllTasksParams{Limit: config.Int(0)})
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		ctrl.AssertNotCalled(t, "Tasks", mock.Anything)
+	})
+
+	t.Run("invalid label", func(t *testing.T) {
+		ctrl := new(mocks.Server)
+		handler := NewTaskLifeCycleHandler(ctrl)
+
+		req, err := http.NewRequest(http.MethodGet, "/v1/tasks", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+
+		handler.GetAllTasks(resp, req, oapigen.GetAllTasksParams{Label: config.String("team")})
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		ctrl.AssertNotCalled(t, "Tasks", mock.Anything)
+	})
+}
@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/consul-terraform-sync/api/oapigen"
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+)
+
+const (
+	taskInputsSubsystemName = "taskinputs"
+
+	taskInputsPath     = "inputs"
+	taskInputsDiffPath = "diff"
+
+	// taskInputsDiffFromParam and taskInputsDiffToParam are the query
+	// parameters for the event IDs of the runs to compare the inputs of
+	taskInputsDiffFromParam = "from"
+	taskInputsDiffToParam   = "to"
+)
+
+// TaskInputsDiffResponse is the response of the task inputs diff endpoint
+type TaskInputsDiffResponse struct {
+	RequestId oapigen.RequestID `json:"request_id"`
+	Diff      driver.InputsDiff `json:"diff"`
+}
+
+// getTaskInputsDiffPath parses the task inputs diff path of the format
+// /v1/tasks/:task_name/inputs/diff. Returns false if the path is not a task
+// inputs diff path.
+func getTaskInputsDiffPath(reqPath, version string) (string, bool) {
+	prefix := fmt.Sprintf("/%s/%s/", version, taskPath)
+	if !strings.HasPrefix(reqPath, prefix) {
+		return "", false
+	}
+
+	parts := strings.Split(strings.TrimPrefix(reqPath, prefix), "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] != taskInputsPath ||
+		parts[2] != taskInputsDiffPath {
+		return "", false
+	}
+	return parts[0], true
+}
+
+// getTaskInputsDiff returns the difference between the input variables
+// applied by two runs of a task. The runs are identified by the event IDs of
+// the from and to query parameters. The latest run is used when to is not
+// set, and the run preceding to is used when from is not set.
+func (h *taskHandler) getTaskInputsDiff(w http.ResponseWriter, r *http.Request, taskName string) {
+	ctx := r.Context()
+	query := r.URL.Query()
+	from := query.Get(taskInputsDiffFromParam)
+	to := query.Get(taskInputsDiffToParam)
+	logger := logging.FromContext(ctx).Named(taskInputsSubsystemName).With(
+		"task_name", taskName, "from", from, "to", to)
+	logger.Trace("get task inputs diff request")
+
+	conf, err := h.ctrl.Task(ctx, taskName)
+	if err != nil {
+		logger.Trace("task not found", "error", err)
+		sendError(w, r, http.StatusNotFound, withErrorCode(ErrorCodeTaskNotFound, err))
+		return
+	}
+
+	if conf.InputSnapshots == nil || *conf.InputSnapshots == 0 {
+		err := fmt.Errorf("input snapshots are not enabled for task '%s'. "+
+			"Configure input_snapshots for the task to save the inputs of "+
+			"task runs", taskName)
+		logger.Trace("bad request", "error", err)
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	diff, err := h.ctrl.TaskInputsDiff(ctx, taskName, from, to)
+	if err != nil {
+		if errors.Is(err, driver.ErrInputSnapshotNotFound) {
+			logger.Trace("input snapshot not found", "error", err)
+			sendError(w, r, http.StatusNotFound, err)
+			return
+		}
+		logger.Error("error getting task inputs diff", "error", err)
+		sendError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, TaskInputsDiffResponse{
+		RequestId: requestIDFromContext(ctx),
+		Diff:      diff,
+	})
+}
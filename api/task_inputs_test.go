@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	serverMocks "github.com/hashicorp/consul-terraform-sync/mocks/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTaskInputsDiffPath(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		path     string
+		expected string
+		ok       bool
+	}{
+		{"inputs diff path", "/v1/tasks/task_a/inputs/diff", "task_a", true},
+		{"task path", "/v1/tasks/task_a", "", false},
+		{"inputs path", "/v1/tasks/task_a/inputs", "", false},
+		{"render path", "/v1/tasks/task_a/render", "", false},
+		{"missing task name", "/v1/tasks//inputs/diff", "", false},
+		{"other version", "/v2/tasks/task_a/inputs/diff", "", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, ok := getTaskInputsDiffPath(tc.path, "v1")
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestTaskInputsDiff_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	taskConf := func(name string, snapshots int) config.TaskConfig {
+		return config.TaskConfig{
+			Name:           config.String(name),
+			Enabled:        config.Bool(true),
+			Module:         config.String("module"),
+			InputSnapshots: config.Int(snapshots),
+		}
+	}
+	diff := driver.InputsDiff{
+		From: driver.InputSnapshot{EventID: "event-a",
+			Time: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+		To: driver.InputSnapshot{EventID: "event-b",
+			Time: time.Date(2026, 1, 2, 3, 5, 5, 0, time.UTC)},
+		Changed: true,
+		Diff:    "--- event-a\n+++ event-b\n@@ -1 +1 @@\n-a\n+b\n",
+	}
+
+	ctrl := new(serverMocks.Server)
+	ctrl.On("Task", mock.Anything, "task_a").Return(taskConf("task_a", 5), nil)
+	ctrl.On("Task", mock.Anything, "task_b").Return(taskConf("task_b", 5), nil)
+	ctrl.On("Task", mock.Anything, "task_c").Return(taskConf("task_c", 5), nil)
+	ctrl.On("Task", mock.Anything, "task_d").Return(taskConf("task_d", 0), nil)
+	ctrl.On("Task", mock.Anything, mock.Anything).Return(config.TaskConfig{},
+		errors.New("task does not exist"))
+	ctrl.On("TaskInputsDiff", mock.Anything, "task_a", "event-a", "event-b").
+		Return(diff, nil)
+	ctrl.On("TaskInputsDiff", mock.Anything, "task_a", "", "").Return(diff, nil)
+	ctrl.On("TaskInputsDiff", mock.Anything, "task_b", mock.Anything, mock.Anything).
+		Return(driver.InputsDiff{}, fmt.Errorf("%w: no input snapshot for event",
+			driver.ErrInputSnapshotNotFound))
+	ctrl.On("TaskInputsDiff", mock.Anything, "task_c", mock.Anything, mock.Anything).
+		Return(driver.InputsDiff{}, errors.New("error reading snapshot"))
+	handler := newTaskHandler(ctrl, "v1")
+
+	cases := []struct {
+		name       string
+		method     string
+		path       string
+		statusCode int
+	}{
+		{"diff", http.MethodGet, "/v1/tasks/task_a/inputs/diff?from=event-a&to=event-b", http.StatusOK},
+		{"diff latest", http.MethodGet, "/v1/tasks/task_a/inputs/diff", http.StatusOK},
+		{"snapshot not found", http.MethodGet, "/v1/tasks/task_b/inputs/diff?from=event-z", http.StatusNotFound},
+		{"error", http.MethodGet, "/v1/tasks/task_c/inputs/diff", http.StatusInternalServerError},
+		{"snapshots disabled", http.MethodGet, "/v1/tasks/task_d/inputs/diff", http.StatusBadRequest},
+		{"task not found", http.MethodGet, "/v1/tasks/task_z/inputs/diff", http.StatusNotFound},
+		{"unsupported method", http.MethodPost, "/v1/tasks/task_a/inputs/diff", http.StatusMethodNotAllowed},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, tc.path, nil)
+			require.NoError(t, err)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+			assert.Equal(t, tc.statusCode, resp.Code)
+		})
+	}
+
+	t.Run("diff response", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet,
+			"/v1/tasks/task_a/inputs/diff?from=event-a&to=event-b", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var actual TaskInputsDiffResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&actual))
+		assert.Equal(t, diff, actual.Diff)
+	})
+}
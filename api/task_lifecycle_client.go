@@ -4,6 +4,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -29,8 +30,13 @@ var _ oapigen.ClientWithResponsesInterface = (*TaskLifecycleClient)(nil)
 
 // NewTaskLifecycleClient returns a client to make api requests
 func NewTaskLifecycleClient(c *ClientConfig, httpClient httpClient) (*TaskLifecycleClient, error) {
+	u, socketPath, err := parseURL(c.URL)
+	if err != nil {
+		return nil, err
+	}
+
 	if httpClient == nil {
-		h, err := newHTTPClient(&c.TLSConfig)
+		h, err := newHTTPClient(&c.TLSConfig, socketPath)
 		if err != nil {
 			return nil, err
 		}
@@ -38,15 +44,18 @@ func NewTaskLifecycleClient(c *ClientConfig, httpClient httpClient) (*TaskLifecy
 		httpClient = NewTaskLifecycleHTTPClient(h)
 	}
 
-	u, err := parseURL(c.URL)
-	if err != nil {
-		return nil, err
-	}
-
 	gc := &TaskLifecycleClient{url: u}
 
 	// Create the new underlying client based on generated code
-	oc, err := oapigen.NewClientWithResponses(gc.url.String(), oapigen.WithHTTPClient(httpClient))
+	opts := []oapigen.ClientOption{oapigen.WithHTTPClient(httpClient)}
+	if c.Token != "" {
+		opts = append(opts, oapigen.WithRequestEditorFn(
+			func(ctx context.Context, req *http.Request) error {
+				setAuthorization(req, c.Token)
+				return nil
+			}))
+	}
+	oc, err := oapigen.NewClientWithResponses(gc.url.String(), opts...)
 	if err != nil {
 		return nil, err
 	}
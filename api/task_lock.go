@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/consul-terraform-sync/api/oapigen"
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+)
+
+const (
+	taskLockSubsystemName = "tasklock"
+
+	taskLockPath = "lock"
+)
+
+// TaskLockRequest is the request to lock a task. Holder defaults to the
+// actor of the request.
+type TaskLockRequest struct {
+	Holder string `json:"holder,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// TaskLockResponse is the response of the task lock endpoint. Lock is not
+// set when the task is unlocked.
+type TaskLockResponse struct {
+	RequestId oapigen.RequestID `json:"request_id"`
+	Lock      *driver.TaskLock  `json:"lock,omitempty"`
+}
+
+// getTaskLockPath parses the task lock path of the format
+// /v1/tasks/:task_name/lock. Returns false if the path is not a task lock
+// path.
+func getTaskLockPath(reqPath, version string) (string, bool) {
+	return getTaskResourcePath(reqPath, version, taskLockPath)
+}
+
+// getTaskResourcePath parses the path of a resource of a task of the format
+// /v1/tasks/:task_name/:resource and returns the task name. Returns false if
+// the path is not a path of the resource.
+func getTaskResourcePath(reqPath, version, resource string) (string, bool) {
+	prefix := fmt.Sprintf("/%s/%s/", version, taskPath)
+	if !strings.HasPrefix(reqPath, prefix) {
+		return "", false
+	}
+
+	parts := strings.Split(strings.TrimPrefix(reqPath, prefix), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] != resource {
+		return "", false
+	}
+	return parts[0], true
+}
+
+// getTaskLock returns the lock of a task
+func (h *taskHandler) getTaskLock(w http.ResponseWriter, r *http.Request, taskName string) {
+	ctx := r.Context()
+	logger := logging.FromContext(ctx).Named(taskLockSubsystemName).With(
+		"task_name", taskName)
+	logger.Trace("get task lock request")
+
+	if _, err := h.ctrl.Task(ctx, taskName); err != nil {
+		logger.Trace("task not found", "error", err)
+		sendError(w, r, http.StatusNotFound, withErrorCode(ErrorCodeTaskNotFound, err))
+		return
+	}
+
+	lock, ok := h.ctrl.TaskLockStatus(ctx, taskName)
+	if !ok {
+		sendError(w, r, http.StatusNotFound,
+			fmt.Errorf("task '%s' is not locked", taskName))
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, TaskLockResponse{
+		RequestId: requestIDFromContext(ctx),
+		Lock:      &lock,
+	})
+}
+
+// lockTask locks a task. Runs of the task triggered by CTS are queued until
+// the task is unlocked. The response is sent once the active run of the task,
+// if any, completes.
+func (h *taskHandler) lockTask(w http.ResponseWriter, r *http.Request, taskName string) {
+	ctx := r.Context()
+	logger := logging.FromContext(ctx).Named(taskLockSubsystemName).With(
+		"task_name", taskName)
+	logger.Trace("lock task request")
+
+	var req TaskLockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		logger.Trace("unable to decode lock request", "error", err)
+		sendError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if req.Holder == "" {
+		req.Holder = requestActor(r)
+	}
+
+	if _, err := h.ctrl.Task(ctx, taskName); err != nil {
+		logger.Trace("task not found", "error", err)
+		sendError(w, r, http.StatusNotFound, withErrorCode(ErrorCodeTaskNotFound, err))
+		return
+	}
+
+	lock, err := h.ctrl.TaskLock(ctx, taskName, req.Holder, req.Reason)
+	if err != nil {
+		if errors.Is(err, driver.ErrTaskLocked) {
+			logger.Trace("task already locked", "error", err)
+			sendError(w, r, http.StatusConflict, withErrorCode(ErrorCodeTaskLocked, err))
+			return
+		}
+		logger.Error("error locking task", "error", err)
+		sendError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, TaskLockResponse{
+		RequestId: requestIDFromContext(ctx),
+		Lock:      &lock,
+	})
+}
+
+// unlockTask unlocks a task. The run of the task queued while it was locked,
+// if any, is run.
+func (h *taskHandler) unlockTask(w http.ResponseWriter, r *http.Request, taskName string) {
+	ctx := r.Context()
+	logger := logging.FromContext(ctx).Named(taskLockSubsystemName).With(
+		"task_name", taskName)
+	logger.Trace("unlock task request")
+
+	if _, err := h.ctrl.Task(ctx, taskName); err != nil {
+		logger.Trace("task not found", "error", err)
+		sendError(w, r, http.StatusNotFound, withErrorCode(ErrorCodeTaskNotFound, err))
+		return
+	}
+
+	if err := h.ctrl.TaskUnlock(ctx, taskName); err != nil {
+		if errors.Is(err, driver.ErrTaskNotLocked) {
+			logger.Trace("task not locked", "error", err)
+			sendError(w, r, http.StatusNotFound, err)
+			return
+		}
+		logger.Error("error unlocking task", "error", err)
+		sendError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, TaskLockResponse{
+		RequestId: requestIDFromContext(ctx),
+	})
+}
@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	serverMocks "github.com/hashicorp/consul-terraform-sync/mocks/server"
+	"github.com/hashicorp/consul-terraform-sync/state/revision"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTaskLockPath(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		path     string
+		expected string
+		ok       bool
+	}{
+		{"lock path", "/v1/tasks/task_a/lock", "task_a", true},
+		{"task path", "/v1/tasks/task_a", "", false},
+		{"missing task name", "/v1/tasks//lock", "", false},
+		{"unknown resource", "/v1/tasks/task_a/lock/foo", "", false},
+		{"other version", "/v2/tasks/task_a/lock", "", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, ok := getTaskLockPath(tc.path, "v1")
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestTaskLock_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	taskConf := config.TaskConfig{
+		Name:    config.String("task_a"),
+		Enabled: config.Bool(true),
+		Module:  config.String("module"),
+	}
+	lock := driver.TaskLock{
+		TaskName:  "task_a",
+		Holder:    "alice",
+		Reason:    "terraform import",
+		LockedAt:  time.Now().UTC(),
+		RunQueued: true,
+	}
+	lockedErr := fmt.Errorf("task 'task_b' is already locked: %w", driver.ErrTaskLocked)
+	notLockedErr := fmt.Errorf("task 'task_b' is not locked: %w", driver.ErrTaskNotLocked)
+
+	ctrl := new(serverMocks.Server)
+	ctrl.On("Task", mock.Anything, "task_a").Return(taskConf, nil)
+	ctrl.On("Task", mock.Anything, "task_b").Return(taskConf, nil)
+	ctrl.On("Task", mock.Anything, "task_c").Return(taskConf, nil)
+	ctrl.On("Task", mock.Anything, mock.Anything).Return(config.TaskConfig{},
+		errors.New("task does not exist"))
+	ctrl.On("TaskLockStatus", mock.Anything, "task_a").Return(lock, true)
+	ctrl.On("TaskLockStatus", mock.Anything, mock.Anything).Return(driver.TaskLock{}, false)
+	ctrl.On("TaskLock", mock.Anything, "task_a", "alice", "terraform import").Return(lock, nil)
+	ctrl.On("TaskLock", mock.Anything, "task_a", revision.ActorAPI, "").Return(lock, nil)
+	ctrl.On("TaskLock", mock.Anything, "task_b", mock.Anything, mock.Anything).
+		Return(driver.TaskLock{}, lockedErr)
+	ctrl.On("TaskLock", mock.Anything, "task_c", mock.Anything, mock.Anything).
+		Return(driver.TaskLock{}, errors.New("context canceled"))
+	ctrl.On("TaskUnlock", mock.Anything, "task_a").Return(nil)
+	ctrl.On("TaskUnlock", mock.Anything, "task_b").Return(notLockedErr)
+	handler := newTaskHandler(ctrl, "v1")
+
+	cases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		statusCode int
+	}{
+		{"get lock", http.MethodGet, "/v1/tasks/task_a/lock", "", http.StatusOK},
+		{"get lock not locked", http.MethodGet, "/v1/tasks/task_b/lock", "", http.StatusNotFound},
+		{"get lock task not found", http.MethodGet, "/v1/tasks/task_z/lock", "", http.StatusNotFound},
+		{"lock", http.MethodPost, "/v1/tasks/task_a/lock",
+			`{"holder": "alice", "reason": "terraform import"}`, http.StatusOK},
+		{"lock default holder", http.MethodPost, "/v1/tasks/task_a/lock", "", http.StatusOK},
+		{"lock already locked", http.MethodPost, "/v1/tasks/task_b/lock", "", http.StatusConflict},
+		{"lock error", http.MethodPost, "/v1/tasks/task_c/lock", "", http.StatusInternalServerError},
+		{"lock invalid body", http.MethodPost, "/v1/tasks/task_a/lock", `{"holder":`, http.StatusBadRequest},
+		{"lock task not found", http.MethodPost, "/v1/tasks/task_z/lock", "", http.StatusNotFound},
+		{"unlock", http.MethodDelete, "/v1/tasks/task_a/lock", "", http.StatusOK},
+		{"unlock not locked", http.MethodDelete, "/v1/tasks/task_b/lock", "", http.StatusNotFound},
+		{"unlock task not found", http.MethodDelete, "/v1/tasks/task_z/lock", "", http.StatusNotFound},
+		{"unsupported method", http.MethodPatch, "/v1/tasks/task_a/lock", "", http.StatusMethodNotAllowed},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+			assert.Equal(t, tc.statusCode, resp.Code)
+		})
+	}
+
+	t.Run("lock response", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/v1/tasks/task_a/lock", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var actual TaskLockResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&actual))
+		require.NotNil(t, actual.Lock)
+		assert.Equal(t, "alice", actual.Lock.Holder)
+		assert.True(t, actual.Lock.RunQueued)
+		assert.True(t, lock.LockedAt.Equal(actual.Lock.LockedAt))
+	})
+
+	t.Run("conflict error code", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, "/v1/tasks/task_b/lock", strings.NewReader(""))
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusConflict, resp.Code)
+		assert.Contains(t, resp.Body.String(), ErrorCodeTaskLocked)
+	})
+}
@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/hashicorp/consul-terraform-sync/state/plan"
+)
+
+const (
+	// taskPlanFormatParam is the query parameter to select the format of the
+	// returned plan artifact
+	taskPlanFormatParam = "format"
+
+	taskPlanFormatJSON   = "json"
+	taskPlanFormatBinary = "binary"
+)
+
+// getTaskPlanPath retrieves the task name and event ID from a plan artifact
+// path of the format /v1/status/tasks/:task_name/events/:event_id/plan.
+// Returns false if the path is not a plan artifact path.
+func getTaskPlanPath(reqPath, version string) (string, string, bool) {
+	prefix := fmt.Sprintf("/%s/%s/", version, taskStatusPath)
+	if !strings.HasPrefix(reqPath, prefix) {
+		return "", "", false
+	}
+
+	parts := strings.Split(strings.TrimPrefix(reqPath, prefix), "/")
+	if len(parts) != 4 || parts[1] != "events" || parts[3] != "plan" {
+		return "", "", false
+	}
+	if parts[0] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[2], true
+}
+
+// getTaskPlan returns the plan artifact saved for a task's event. The JSON
+// representation of the plan is returned by default. The binary plan file is
+// returned when the format query parameter is set to binary.
+func (h *taskStatusHandler) getTaskPlan(w http.ResponseWriter, r *http.Request,
+	taskName, eventID string) {
+
+	ctx := r.Context()
+	logger := logging.FromContext(ctx).Named(taskStatusSubsystemName).With(
+		"task_name", taskName, "event_id", eventID)
+
+	format := r.URL.Query().Get(taskPlanFormatParam)
+	switch format {
+	case "", taskPlanFormatJSON, taskPlanFormatBinary:
+	default:
+		err := fmt.Errorf("unsupported plan format '%s'. The format must be "+
+			"one of: '%s', '%s'", format, taskPlanFormatJSON, taskPlanFormatBinary)
+		logger.Trace("bad request", "error", err)
+		jsonErrorResponse(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	conf := h.ctrl.Config()
+	if conf.PlanArtifacts == nil || !config.BoolVal(conf.PlanArtifacts.Enabled) {
+		err := errors.New("plan artifacts are not enabled. Configure the " +
+			"plan_artifacts block to save plans for task runs")
+		logger.Trace("bad request", "error", err)
+		jsonErrorResponse(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := h.ctrl.Task(ctx, taskName); err != nil {
+		logger.Trace("error getting task", "error", err)
+		jsonErrorResponse(ctx, w, http.StatusNotFound, withErrorCode(ErrorCodeTaskNotFound, err))
+		return
+	}
+
+	artifact, err := h.ctrl.TaskPlan(ctx, taskName, eventID)
+	if err != nil {
+		if errors.Is(err, plan.ErrNotFound) {
+			err = fmt.Errorf("plan artifact for event '%s' of task '%s' not "+
+				"found", eventID, taskName)
+			logger.Trace("plan artifact not found", "error", err)
+			jsonErrorResponse(ctx, w, http.StatusNotFound, err)
+			return
+		}
+		logger.Error("error getting plan artifact", "error", err)
+		jsonErrorResponse(ctx, w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if format == taskPlanFormatBinary {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(
+			"attachment; filename=%q", fmt.Sprintf("%s-%s.tfplan", taskName, eventID)))
+		w.WriteHeader(http.StatusOK)
+		w.Write(artifact.Plan)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(artifact.JSON)
+}
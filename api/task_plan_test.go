@@ -0,0 +1,196 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	serverMocks "github.com/hashicorp/consul-terraform-sync/mocks/server"
+	"github.com/hashicorp/consul-terraform-sync/state/plan"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTaskPlanPath(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		path     string
+		taskName string
+		eventID  string
+		ok       bool
+	}{
+		{
+			"plan path",
+			"/v1/status/tasks/task_a/events/123/plan",
+			"task_a",
+			"123",
+			true,
+		},
+		{
+			"task status path",
+			"/v1/status/tasks/task_a",
+			"",
+			"",
+			false,
+		},
+		{
+			"missing event ID",
+			"/v1/status/tasks/task_a/events//plan",
+			"",
+			"",
+			false,
+		},
+		{
+			"unknown resource",
+			"/v1/status/tasks/task_a/events/123/apply",
+			"",
+			"",
+			false,
+		},
+		{
+			"extra path",
+			"/v1/status/tasks/task_a/events/123/plan/json",
+			"",
+			"",
+			false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			taskName, eventID, ok := getTaskPlanPath(tc.path, "v1")
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.taskName, taskName)
+			assert.Equal(t, tc.eventID, eventID)
+		})
+	}
+}
+
+func TestTaskStatus_GetTaskPlan(t *testing.T) {
+	t.Parallel()
+
+	artifact := plan.Artifact{
+		TaskName: "task_a",
+		EventID:  "123",
+		Plan:     []byte("binary plan"),
+		JSON:     []byte(`{"format_version":"1.1"}`),
+	}
+
+	enabledConf := config.Config{PlanArtifacts: &config.PlanArtifactsConfig{
+		Enabled: config.Bool(true),
+		Path:    config.String("plans"),
+	}}
+
+	cases := []struct {
+		name        string
+		path        string
+		conf        config.Config
+		taskErr     error
+		planErr     error
+		statusCode  int
+		contentType string
+		body        string
+	}{
+		{
+			"json",
+			"/v1/status/tasks/task_a/events/123/plan",
+			enabledConf,
+			nil,
+			nil,
+			http.StatusOK,
+			"application/json",
+			`{"format_version":"1.1"}`,
+		},
+		{
+			"binary",
+			"/v1/status/tasks/task_a/events/123/plan?format=binary",
+			enabledConf,
+			nil,
+			nil,
+			http.StatusOK,
+			"application/octet-stream",
+			"binary plan",
+		},
+		{
+			"unsupported format",
+			"/v1/status/tasks/task_a/events/123/plan?format=yaml",
+			enabledConf,
+			nil,
+			nil,
+			http.StatusBadRequest,
+			"application/json",
+			"",
+		},
+		{
+			"plan artifacts disabled",
+			"/v1/status/tasks/task_a/events/123/plan",
+			config.Config{},
+			nil,
+			nil,
+			http.StatusBadRequest,
+			"application/json",
+			"",
+		},
+		{
+			"task not found",
+			"/v1/status/tasks/task_a/events/123/plan",
+			enabledConf,
+			fmt.Errorf("DNE"),
+			nil,
+			http.StatusNotFound,
+			"application/json",
+			"",
+		},
+		{
+			"plan artifact not found",
+			"/v1/status/tasks/task_a/events/123/plan",
+			enabledConf,
+			nil,
+			plan.ErrNotFound,
+			http.StatusNotFound,
+			"application/json",
+			"",
+		},
+		{
+			"store error",
+			"/v1/status/tasks/task_a/events/123/plan",
+			enabledConf,
+			nil,
+			errors.New("read error"),
+			http.StatusInternalServerError,
+			"application/json",
+			"",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := new(serverMocks.Server)
+			ctrl.On("Config").Return(tc.conf)
+			ctrl.On("Task", mock.Anything, "task_a").Return(config.TaskConfig{}, tc.taskErr)
+			ctrl.On("TaskPlan", mock.Anything, "task_a", "123").Return(artifact, tc.planErr)
+			handler := newTaskStatusHandler(ctrl, "v1")
+
+			req, err := http.NewRequest(http.MethodGet, tc.path, nil)
+			require.NoError(t, err)
+			resp := httptest.NewRecorder()
+
+			handler.ServeHTTP(resp, req)
+
+			require.Equal(t, tc.statusCode, resp.Code)
+			assert.Equal(t, tc.contentType, resp.Header().Get("Content-Type"))
+			if tc.body != "" {
+				assert.Equal(t, tc.body, resp.Body.String())
+			}
+		})
+	}
+}
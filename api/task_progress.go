@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/consul-terraform-sync/logging"
+)
+
+// getTaskProgressPath retrieves the task name from a task progress path of the
+// format /v1/status/tasks/:task_name/progress. Returns false if the path is
+// not a task progress path.
+func getTaskProgressPath(reqPath, version string) (string, bool) {
+	prefix := fmt.Sprintf("/%s/%s/", version, taskStatusPath)
+	if !strings.HasPrefix(reqPath, prefix) {
+		return "", false
+	}
+
+	parts := strings.Split(strings.TrimPrefix(reqPath, prefix), "/")
+	if len(parts) != 2 || parts[1] != "progress" || parts[0] == "" {
+		return "", false
+	}
+	return parts[0], true
+}
+
+// getTaskProgress returns the progress of the latest Terraform run of a task.
+// The progress is active while the run is in-flight and lists the resources
+// that Terraform has started or completed changing.
+func (h *taskStatusHandler) getTaskProgress(w http.ResponseWriter, r *http.Request,
+	taskName string) {
+
+	ctx := r.Context()
+	logger := logging.FromContext(ctx).Named(taskStatusSubsystemName).With(
+		"task_name", taskName)
+
+	progress, err := h.ctrl.TaskProgress(ctx, taskName)
+	if err != nil {
+		logger.Trace("error getting task progress", "error", err)
+		jsonErrorResponse(ctx, w, http.StatusNotFound,
+			withErrorCode(ErrorCodeTaskNotFound, err))
+		return
+	}
+
+	if err := jsonResponse(w, http.StatusOK, progress); err != nil {
+		logger.Error("error, could not generate json response", "error", err)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	serverMocks "github.com/hashicorp/consul-terraform-sync/mocks/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTaskProgressPath(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		path     string
+		taskName string
+		ok       bool
+	}{
+		{
+			"progress path",
+			"/v1/status/tasks/task_a/progress",
+			"task_a",
+			true,
+		},
+		{
+			"task status path",
+			"/v1/status/tasks/task_a",
+			"",
+			false,
+		},
+		{
+			"missing task name",
+			"/v1/status/tasks//progress",
+			"",
+			false,
+		},
+		{
+			"extra path",
+			"/v1/status/tasks/task_a/progress/resources",
+			"",
+			false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			taskName, ok := getTaskProgressPath(tc.path, "v1")
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.taskName, taskName)
+		})
+	}
+}
+
+func TestTaskStatus_GetTaskProgress(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2022, time.June, 1, 10, 0, 0, 0, time.UTC)
+	progress := driver.Progress{
+		TaskName:  "task_a",
+		Active:    true,
+		StartTime: start,
+		Resources: []driver.ResourceProgress{
+			{
+				Address:    "module.task_a.local_file.a",
+				Action:     driver.ResourceActionCreate,
+				Status:     driver.ResourceStatusInProgress,
+				UpdateTime: start.Add(time.Second),
+			},
+		},
+	}
+
+	t.Run("in-flight run", func(t *testing.T) {
+		ctrl := new(serverMocks.Server)
+		ctrl.On("TaskProgress", mock.Anything, "task_a").Return(progress, nil)
+		handler := newTaskStatusHandler(ctrl, "v1")
+
+		req, err := http.NewRequest(http.MethodGet, "/v1/status/tasks/task_a/progress", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		var actual driver.Progress
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&actual))
+		assert.Equal(t, progress, actual)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		ctrl := new(serverMocks.Server)
+		ctrl.On("TaskProgress", mock.Anything, "task_b").Return(
+			driver.Progress{}, fmt.Errorf("task task_b does not exist"))
+		handler := newTaskStatusHandler(ctrl, "v1")
+
+		req, err := http.NewRequest(http.MethodGet, "/v1/status/tasks/task_b/progress", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		require.Equal(t, http.StatusNotFound, resp.Code)
+		var actual ErrorResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&actual))
+		code, ok := actual.ErrorCode()
+		assert.True(t, ok)
+		assert.Equal(t, ErrorCodeTaskNotFound, code)
+	})
+}
@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/hashicorp/consul-terraform-sync/api/oapigen"
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+)
+
+const (
+	taskReinitSubsystemName = "taskreinit"
+
+	taskReinitPath = "reinit"
+)
+
+// TaskReinitResponse is the response of the task reinit endpoint
+type TaskReinitResponse struct {
+	RequestId oapigen.RequestID   `json:"request_id"`
+	Render    driver.RenderResult `json:"render"`
+}
+
+// getTaskReinitPath parses the task reinit path of the format
+// /v1/tasks/:task_name/reinit. Returns false if the path is not a task reinit
+// path.
+func getTaskReinitPath(reqPath, version string) (string, bool) {
+	return getTaskResourcePath(reqPath, version, taskReinitPath)
+}
+
+// reinitTask re-initializes a task without restarting CTS, e.g. after the
+// files of its local module change on disk. Terraform init and validate are
+// re-run for the task and its template is rendered, without running the task.
+func (h *taskHandler) reinitTask(w http.ResponseWriter, r *http.Request, taskName string) {
+	ctx := r.Context()
+	logger := logging.FromContext(ctx).Named(taskReinitSubsystemName).With(
+		"task_name", taskName)
+	logger.Trace("reinit task request")
+
+	if _, err := h.ctrl.Task(ctx, taskName); err != nil {
+		logger.Trace("task not found", "error", err)
+		sendError(w, r, http.StatusNotFound, withErrorCode(ErrorCodeTaskNotFound, err))
+		return
+	}
+
+	result, err := h.ctrl.TaskReinit(ctx, taskName)
+	if err != nil {
+		if errors.Is(err, driver.ErrTaskActive) {
+			logger.Trace("task is active", "error", err)
+			sendError(w, r, http.StatusConflict, err)
+			return
+		}
+		logger.Error("error reinitializing task", "error", err)
+		sendError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, TaskReinitResponse{
+		RequestId: requestIDFromContext(ctx),
+		Render:    result,
+	})
+}
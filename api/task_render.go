@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/hashicorp/consul-terraform-sync/api/oapigen"
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+)
+
+const (
+	taskRenderSubsystemName = "taskrender"
+
+	taskRenderPath = "render"
+)
+
+// TaskRenderResponse is the response of the task render endpoint
+type TaskRenderResponse struct {
+	RequestId oapigen.RequestID   `json:"request_id"`
+	Render    driver.RenderResult `json:"render"`
+}
+
+// getTaskRenderPath parses the task render path of the format
+// /v1/tasks/:task_name/render. Returns false if the path is not a task render
+// path.
+func getTaskRenderPath(reqPath, version string) (string, bool) {
+	return getTaskResourcePath(reqPath, version, taskRenderPath)
+}
+
+// renderTask renders the template of a task with the latest data of its
+// dependencies without running Terraform. Render is not complete if the data
+// of the dependencies is not yet fetched.
+func (h *taskHandler) renderTask(w http.ResponseWriter, r *http.Request, taskName string) {
+	ctx := r.Context()
+	logger := logging.FromContext(ctx).Named(taskRenderSubsystemName).With(
+		"task_name", taskName)
+	logger.Trace("render task request")
+
+	if _, err := h.ctrl.Task(ctx, taskName); err != nil {
+		logger.Trace("task not found", "error", err)
+		sendError(w, r, http.StatusNotFound, withErrorCode(ErrorCodeTaskNotFound, err))
+		return
+	}
+
+	result, err := h.ctrl.TaskRender(ctx, taskName)
+	if err != nil {
+		if errors.Is(err, driver.ErrTaskActive) {
+			logger.Trace("task is active", "error", err)
+			sendError(w, r, http.StatusConflict, err)
+			return
+		}
+		logger.Error("error rendering task", "error", err)
+		sendError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, TaskRenderResponse{
+		RequestId: requestIDFromContext(ctx),
+		Render:    result,
+	})
+}
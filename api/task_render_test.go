@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	serverMocks "github.com/hashicorp/consul-terraform-sync/mocks/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTaskRenderPath(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		path     string
+		expected string
+		ok       bool
+	}{
+		{"render path", "/v1/tasks/task_a/render", "task_a", true},
+		{"task path", "/v1/tasks/task_a", "", false},
+		{"lock path", "/v1/tasks/task_a/lock", "", false},
+		{"missing task name", "/v1/tasks//render", "", false},
+		{"other version", "/v2/tasks/task_a/render", "", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, ok := getTaskRenderPath(tc.path, "v1")
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestTaskRender_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	taskConf := config.TaskConfig{
+		Name:    config.String("task_a"),
+		Enabled: config.Bool(true),
+		Module:  config.String("module"),
+	}
+	result := driver.RenderResult{
+		Complete: true,
+		Changed:  true,
+		Files:    []string{"sync-tasks/task_a/terraform.tfvars"},
+	}
+
+	ctrl := new(serverMocks.Server)
+	ctrl.On("Task", mock.Anything, "task_a").Return(taskConf, nil)
+	ctrl.On("Task", mock.Anything, "task_b").Return(taskConf, nil)
+	ctrl.On("Task", mock.Anything, "task_c").Return(taskConf, nil)
+	ctrl.On("Task", mock.Anything, mock.Anything).Return(config.TaskConfig{},
+		errors.New("task does not exist"))
+	ctrl.On("TaskRender", mock.Anything, "task_a").Return(result, nil)
+	ctrl.On("TaskRender", mock.Anything, "task_b").Return(driver.RenderResult{},
+		&driver.TaskActiveError{Name: "task_b", Action: "rendered"})
+	ctrl.On("TaskRender", mock.Anything, "task_c").Return(driver.RenderResult{},
+		errors.New("error rendering"))
+	handler := newTaskHandler(ctrl, "v1")
+
+	cases := []struct {
+		name       string
+		method     string
+		path       string
+		statusCode int
+	}{
+		{"render", http.MethodPost, "/v1/tasks/task_a/render", http.StatusOK},
+		{"render task active", http.MethodPost, "/v1/tasks/task_b/render", http.StatusConflict},
+		{"render error", http.MethodPost, "/v1/tasks/task_c/render", http.StatusInternalServerError},
+		{"render task not found", http.MethodPost, "/v1/tasks/task_z/render", http.StatusNotFound},
+		{"unsupported method", http.MethodGet, "/v1/tasks/task_a/render", http.StatusMethodNotAllowed},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, tc.path, nil)
+			require.NoError(t, err)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+			assert.Equal(t, tc.statusCode, resp.Code)
+		})
+	}
+
+	t.Run("render response", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost, "/v1/tasks/task_a/render", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var actual TaskRenderResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&actual))
+		assert.Equal(t, result, actual.Render)
+	})
+}
@@ -0,0 +1,158 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/api/oapigen"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/hashicorp/consul-terraform-sync/state/revision"
+)
+
+const (
+	taskRevisionSubsystemName = "taskrevision"
+
+	taskRevisionsPath = "revisions"
+	taskRestorePath   = "restore"
+)
+
+// TaskRevision is a revision of a task's configuration
+type TaskRevision struct {
+	ID        int          `json:"id"`
+	Timestamp time.Time    `json:"timestamp"`
+	Actor     string       `json:"actor"`
+	Task      oapigen.Task `json:"task"`
+}
+
+// TaskRevisionsResponse is the response of the task revisions endpoint
+type TaskRevisionsResponse struct {
+	RequestId oapigen.RequestID `json:"request_id"`
+	Revisions []TaskRevision    `json:"revisions"`
+}
+
+// taskRevisionPath is a parsed task revision path
+type taskRevisionPath struct {
+	taskName   string
+	revisionID int
+	restore    bool
+}
+
+// getTaskRevisionPath parses the task revision paths of the formats
+// /v1/tasks/:task_name/revisions and
+// /v1/tasks/:task_name/revisions/:revision_id/restore. Returns false if the
+// path is not a task revision path.
+func getTaskRevisionPath(reqPath, version string) (taskRevisionPath, bool) {
+	prefix := fmt.Sprintf("/%s/%s/", version, taskPath)
+	if !strings.HasPrefix(reqPath, prefix) {
+		return taskRevisionPath{}, false
+	}
+
+	parts := strings.Split(strings.TrimPrefix(reqPath, prefix), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] != taskRevisionsPath {
+		return taskRevisionPath{}, false
+	}
+
+	switch len(parts) {
+	case 2:
+		return taskRevisionPath{taskName: parts[0]}, true
+	case 4:
+		if parts[3] != taskRestorePath {
+			return taskRevisionPath{}, false
+		}
+		id, err := strconv.Atoi(parts[2])
+		if err != nil || id < 1 {
+			return taskRevisionPath{}, false
+		}
+		return taskRevisionPath{taskName: parts[0], revisionID: id, restore: true}, true
+	default:
+		return taskRevisionPath{}, false
+	}
+}
+
+// getTaskRevisions returns the revisions of a task's configuration
+func (h *taskHandler) getTaskRevisions(w http.ResponseWriter, r *http.Request, taskName string) {
+	ctx := r.Context()
+	requestID := requestIDFromContext(ctx)
+	logger := logging.FromContext(ctx).Named(taskRevisionSubsystemName).With(
+		"task_name", taskName)
+	logger.Trace("get task revisions request")
+
+	revs, err := h.ctrl.TaskRevisions(ctx, taskName)
+	if err != nil {
+		logger.Trace("task revisions not found", "error", err)
+		sendError(w, r, http.StatusNotFound, withErrorCode(ErrorCodeTaskNotFound, err))
+		return
+	}
+
+	resp := TaskRevisionsResponse{
+		RequestId: requestID,
+		Revisions: make([]TaskRevision, len(revs)),
+	}
+	for i, rev := range revs {
+		resp.Revisions[i] = TaskRevision{
+			ID:        rev.ID,
+			Timestamp: rev.Timestamp,
+			Actor:     rev.Actor,
+			Task:      redactedTaskFromConfigTask(rev.Config),
+		}
+	}
+	writeResponse(w, r, http.StatusOK, resp)
+}
+
+// restoreTaskRevision restores a task to the configuration of a revision
+func (h *taskHandler) restoreTaskRevision(w http.ResponseWriter, r *http.Request,
+	taskName string, id int) {
+
+	ctx := revision.WithActor(r.Context(), requestActor(r))
+	requestID := requestIDFromContext(ctx)
+	logger := logging.FromContext(ctx).Named(taskRevisionSubsystemName).With(
+		"task_name", taskName, "revision_id", id)
+	logger.Trace("restore task revision request")
+
+	revs, err := h.ctrl.TaskRevisions(ctx, taskName)
+	if err != nil {
+		logger.Trace("task revisions not found", "error", err)
+		sendError(w, r, http.StatusNotFound, withErrorCode(ErrorCodeTaskNotFound, err))
+		return
+	}
+	found := false
+	for _, rev := range revs {
+		if rev.ID == id {
+			found = true
+			break
+		}
+	}
+	if !found {
+		err := fmt.Errorf("revision %d not found for task '%s'", id, taskName)
+		logger.Trace("task revision not found", "error", err)
+		sendError(w, r, http.StatusNotFound, err)
+		return
+	}
+
+	tc, err := h.ctrl.TaskRestoreRevision(ctx, taskName, id)
+	if err != nil {
+		logger.Error("error restoring task revision", "error", err)
+		sendError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	logger.Info("restored task revision")
+	writeResponse(w, r, http.StatusOK, taskResponseFromTaskConfig(tc, requestID))
+}
+
+// requestActor returns the actor of task changes made by an API request,
+// which includes the remote host of the request when it is known
+func requestActor(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil || host == "" {
+		return revision.ActorAPI
+	}
+	return fmt.Sprintf("%s (%s)", revision.ActorAPI, host)
+}
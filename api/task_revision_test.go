@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	serverMocks "github.com/hashicorp/consul-terraform-sync/mocks/server"
+	"github.com/hashicorp/consul-terraform-sync/state/revision"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTaskRevisionPath(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		path     string
+		expected taskRevisionPath
+		ok       bool
+	}{
+		{
+			"revisions path",
+			"/v1/tasks/task_a/revisions",
+			taskRevisionPath{taskName: "task_a"},
+			true,
+		},
+		{
+			"restore path",
+			"/v1/tasks/task_a/revisions/2/restore",
+			taskRevisionPath{taskName: "task_a", revisionID: 2, restore: true},
+			true,
+		},
+		{
+			"task path",
+			"/v1/tasks/task_a",
+			taskRevisionPath{},
+			false,
+		},
+		{
+			"missing task name",
+			"/v1/tasks//revisions",
+			taskRevisionPath{},
+			false,
+		},
+		{
+			"invalid revision ID",
+			"/v1/tasks/task_a/revisions/abc/restore",
+			taskRevisionPath{},
+			false,
+		},
+		{
+			"revision without restore",
+			"/v1/tasks/task_a/revisions/2",
+			taskRevisionPath{},
+			false,
+		},
+		{
+			"unknown resource",
+			"/v1/tasks/task_a/revisions/2/apply",
+			taskRevisionPath{},
+			false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, ok := getTaskRevisionPath(tc.path, "v1")
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestTaskRevisions_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	taskConf := config.TaskConfig{
+		Name:    config.String("task_a"),
+		Enabled: config.Bool(true),
+		Module:  config.String("module"),
+	}
+	now := time.Now().UTC()
+	revs := []revision.Revision{
+		{ID: 2, TaskName: "task_a", Timestamp: now, Actor: revision.ActorAPI, Config: taskConf},
+		{ID: 1, TaskName: "task_a", Timestamp: now, Actor: revision.ActorConfig, Config: taskConf},
+	}
+
+	ctrl := new(serverMocks.Server)
+	ctrl.On("TaskRevisions", mock.Anything, "task_a").Return(revs, nil)
+	ctrl.On("TaskRevisions", mock.Anything, "task_b").Return(revs, nil)
+	ctrl.On("TaskRevisions", mock.Anything, mock.Anything).Return(nil, errors.New("not found"))
+	ctrl.On("TaskRestoreRevision", mock.Anything, "task_a", 1).Return(taskConf, nil)
+	ctrl.On("TaskRestoreRevision", mock.Anything, "task_b", 1).Return(
+		config.TaskConfig{}, errors.New("restore error"))
+	handler := newTaskHandler(ctrl, "v1")
+
+	cases := []struct {
+		name       string
+		method     string
+		path       string
+		statusCode int
+	}{
+		{"get revisions", http.MethodGet, "/v1/tasks/task_a/revisions", http.StatusOK},
+		{"get revisions not found", http.MethodGet, "/v1/tasks/task_z/revisions", http.StatusNotFound},
+		{"restore", http.MethodPost, "/v1/tasks/task_a/revisions/1/restore", http.StatusOK},
+		{"restore task not found", http.MethodPost, "/v1/tasks/task_z/revisions/1/restore", http.StatusNotFound},
+		{"restore revision not found", http.MethodPost, "/v1/tasks/task_a/revisions/3/restore", http.StatusNotFound},
+		{"restore error", http.MethodPost, "/v1/tasks/task_b/revisions/1/restore", http.StatusInternalServerError},
+		{"unsupported method", http.MethodDelete, "/v1/tasks/task_a/revisions", http.StatusMethodNotAllowed},
+		{"get restore", http.MethodGet, "/v1/tasks/task_a/revisions/1/restore", http.StatusMethodNotAllowed},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, tc.path, nil)
+			require.NoError(t, err)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+			assert.Equal(t, tc.statusCode, resp.Code)
+		})
+	}
+
+	t.Run("revisions response", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "/v1/tasks/task_a/revisions", nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var actual TaskRevisionsResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&actual))
+		require.Len(t, actual.Revisions, 2)
+		assert.Equal(t, 2, actual.Revisions[0].ID)
+		assert.Equal(t, revision.ActorAPI, actual.Revisions[0].Actor)
+		assert.Equal(t, "task_a", actual.Revisions[0].Task.Name)
+		assert.True(t, now.Equal(actual.Revisions[0].Timestamp))
+	})
+}
+
+func TestRequestActor(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequest(http.MethodPost, "/v1/tasks", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	assert.Equal(t, "api (10.0.0.1)", requestActor(r))
+
+	r.RemoteAddr = ""
+	assert.Equal(t, revision.ActorAPI, requestActor(r))
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/consul-terraform-sync/config"
 	"github.com/hashicorp/consul-terraform-sync/logging"
@@ -26,6 +27,36 @@ type TaskStatus struct {
 	EventsURL string        `json:"events_url"`
 	Events    []event.Event `json:"events,omitempty"`
 
+	// Suppressed is the number of the task's recent events that recorded
+	// triggers suppressed because the task was within its cooldown
+	Suppressed int `json:"suppressed,omitempty"`
+
+	// PendingRuns is the number of run requests queued while the task is
+	// running. QueuedTriggers are the times each pending run was queued.
+	PendingRuns    int         `json:"pending_runs"`
+	QueuedTriggers []time.Time `json:"queued_triggers,omitempty"`
+
+	// RetryAt is the time that a degraded task paused by its circuit breaker
+	// will be retried. It is not set if the task is only resumed manually.
+	RetryAt *time.Time `json:"retry_at,omitempty"`
+
+	// NextRunAt is the time of the next run of an enabled task with a
+	// schedule condition. Cron and Crons are the cron expressions of the
+	// schedule condition.
+	NextRunAt *time.Time `json:"next_run_at,omitempty"`
+	Cron      string     `json:"cron,omitempty"`
+	Crons     []string   `json:"crons,omitempty"`
+
+	// Stats are the rolling statistics of the runs of the task, e.g. the
+	// success rate over the last 24 hours and the current failure streak. It
+	// is not set if the task has no events.
+	Stats *event.Stats `json:"stats,omitempty"`
+
+	// Dependencies is the number of times that each monitored dependency,
+	// e.g. a service name or Consul KV path, triggered the task. Only set
+	// with the `include=dependencies` parameter.
+	Dependencies map[string]int `json:"dependencies,omitempty"`
+
 	// Providers and Services are deprecated in v0.5. These are configuration
 	// details about the task rather than status information. Users should
 	// switch to using the Get Task API to request the task's provider and
@@ -59,6 +90,14 @@ func (h *taskStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	switch r.Method {
 	case http.MethodGet:
+		if taskName, eventID, ok := getTaskPlanPath(r.URL.Path, h.version); ok {
+			h.getTaskPlan(w, r, taskName, eventID)
+			return
+		}
+		if taskName, ok := getTaskProgressPath(r.URL.Path, h.version); ok {
+			h.getTaskProgress(w, r, taskName)
+			return
+		}
 		h.getTaskStatus(w, r)
 	default:
 		err := fmt.Errorf("'%s' in an unsupported method. The task status API "+
@@ -100,15 +139,22 @@ func (h *taskStatusHandler) getTaskStatus(w http.ResponseWriter, r *http.Request
 		task, err := h.ctrl.Task(ctx, taskName)
 		if err != nil {
 			logger.Trace("error getting task", "error", err)
-			jsonErrorResponse(ctx, w, http.StatusNotFound, err)
+			jsonErrorResponse(ctx, w, http.StatusNotFound,
+				withErrorCode(ErrorCodeTaskNotFound, err))
 			return
 		}
 		status := makeTaskStatus(events, task, h.version)
+		if retryAt, open := h.ctrl.TaskCircuitBreakerOpen(ctx, taskName); open {
+			status.Status = StatusDegraded
+			if !retryAt.IsZero() {
+				status.RetryAt = &retryAt
+			}
+		}
 
 		if filter != "" && status.Status != filter {
 			continue
 		}
-		if include {
+		if include.events {
 			status.Events = events
 		}
 		statuses[taskName] = status
@@ -121,7 +167,8 @@ func (h *taskStatusHandler) getTaskStatus(w http.ResponseWriter, r *http.Request
 			task, err := h.ctrl.Task(ctx, taskName)
 			if err != nil {
 				logger.Trace("error getting task", "error", err)
-				jsonErrorResponse(ctx, w, http.StatusNotFound, err)
+				jsonErrorResponse(ctx, w, http.StatusNotFound,
+					withErrorCode(ErrorCodeTaskNotFound, err))
 				return
 			}
 			statuses[taskName] = makeTaskStatusUnknown(task)
@@ -139,6 +186,21 @@ func (h *taskStatusHandler) getTaskStatus(w http.ResponseWriter, r *http.Request
 		}
 	}
 
+	for name, status := range statuses {
+		status.QueuedTriggers = h.ctrl.TaskPendingRuns(ctx, name)
+		status.PendingRuns = len(status.QueuedTriggers)
+		if include.dependencies {
+			status.Dependencies = h.ctrl.TaskDependencyTriggers(ctx, name)
+		}
+		if nextRun, ok := h.ctrl.TaskNextScheduledRun(ctx, name); ok {
+			status.NextRunAt = &nextRun
+		}
+		if stats, ok := h.ctrl.TaskStats(ctx, name); ok {
+			status.Stats = &stats
+		}
+		statuses[name] = status
+	}
+
 	if err = jsonResponse(w, http.StatusOK, statuses); err != nil {
 		logger.Error("error, could not generate json response", "error", err)
 	}
@@ -148,12 +210,21 @@ func (h *taskStatusHandler) getTaskStatus(w http.ResponseWriter, r *http.Request
 func makeTaskStatus(events []event.Event, task config.TaskConfig,
 	version string) TaskStatus {
 
-	successes := make([]bool, len(events))
+	successes := make([]bool, 0, len(events))
 	uniqProviders := make(map[string]bool)
 	uniqServices := make(map[string]bool)
-
-	for i, e := range events {
-		successes[i] = e.Success
+	suppressed := 0
+
+	for _, e := range events {
+		// Suppressed and expired events do not run the task and do not
+		// affect the status
+		switch {
+		case e.Suppressed:
+			suppressed++
+		case e.Expired:
+		default:
+			successes = append(successes, e.Success)
+		}
 		if e.Config == nil {
 			continue
 		}
@@ -166,20 +237,23 @@ func makeTaskStatus(events []event.Event, task config.TaskConfig,
 	}
 
 	taskName := *task.Name
-	return TaskStatus{
-		TaskName:  taskName,
-		Status:    successToStatus(successes),
-		Enabled:   *task.Enabled,
-		Providers: mapKeyToArray(uniqProviders),
-		Services:  mapKeyToArray(uniqServices),
-		EventsURL: makeEventsURL(events, version, taskName),
+	status := TaskStatus{
+		TaskName:   taskName,
+		Status:     successToStatus(successes),
+		Enabled:    *task.Enabled,
+		Providers:  mapKeyToArray(uniqProviders),
+		Services:   mapKeyToArray(uniqServices),
+		EventsURL:  makeEventsURL(events, version, taskName),
+		Suppressed: suppressed,
 	}
+	setTaskStatusSchedule(&status, task)
+	return status
 }
 
 // makeTaskStatusUnknown returns a task status for tasks that do not have events
 // but still exist within CTS. Example: a task that has been disabled from the start
 func makeTaskStatusUnknown(task config.TaskConfig) TaskStatus {
-	return TaskStatus{
+	status := TaskStatus{
 		TaskName:  *task.Name,
 		Status:    StatusUnknown,
 		Enabled:   *task.Enabled,
@@ -187,6 +261,21 @@ func makeTaskStatusUnknown(task config.TaskConfig) TaskStatus {
 		Services:  task.DeprecatedServices,
 		EventsURL: "",
 	}
+	setTaskStatusSchedule(&status, task)
+	return status
+}
+
+// setTaskStatusSchedule sets the cron expressions of a task with a schedule
+// condition on the task status
+func setTaskStatusSchedule(status *TaskStatus, task config.TaskConfig) {
+	cond, ok := task.Condition.(*config.ScheduleConditionConfig)
+	if !ok {
+		return
+	}
+	status.Cron = config.StringVal(cond.Cron)
+	if len(cond.Crons) > 0 {
+		status.Crons = cond.Crons
+	}
 }
 
 // mapKeyToArray returns an array of map keys
@@ -237,28 +326,47 @@ func makeEventsURL(events []event.Event, version, taskName string) string {
 		version, taskStatusPath, taskName)
 }
 
-// include determines whether to include events in task status payload
-func include(r *http.Request) (bool, error) {
+// statusIncludes are the optional details to include in the task status
+// payload
+type statusIncludes struct {
+	events       bool
+	dependencies bool
+}
+
+// include determines the optional details to include in the task status
+// payload. Multiple details are separated by commas, e.g.
+// `?include=events,dependencies`
+func include(r *http.Request) (statusIncludes, error) {
 	// `?include=events` parameter
 	const includeKey = "include"
-	const includeValue = "events"
+	const includeEvents = "events"
+	const includeDependencies = "dependencies"
 
+	var includes statusIncludes
 	keys, ok := r.URL.Query()[includeKey]
 	if !ok {
-		return false, nil
+		return includes, nil
 	}
 
 	if len(keys) != 1 {
-		return false, fmt.Errorf("cannot support more than one include "+
+		return includes, fmt.Errorf("cannot support more than one include "+
 			"parameter, got include values: %v", keys)
 	}
 
-	if keys[0] != includeValue {
-		return false, fmt.Errorf("unsupported ?include parameter value. only "+
-			"supporting 'include=events' but got 'include=%s'", keys[0])
+	for _, value := range strings.Split(keys[0], ",") {
+		switch value {
+		case includeEvents:
+			includes.events = true
+		case includeDependencies:
+			includes.dependencies = true
+		default:
+			return statusIncludes{}, fmt.Errorf("unsupported ?include parameter "+
+				"value. only supporting 'include=events' and "+
+				"'include=dependencies' but got 'include=%s'", keys[0])
+		}
 	}
 
-	return true, nil
+	return includes, nil
 }
 
 // statusFilter returns a status to filter task statuses
@@ -279,12 +387,13 @@ func statusFilter(r *http.Request) (string, error) {
 	value := keys[0]
 	value = strings.ToLower(value)
 	switch value {
-	case StatusSuccessful, StatusErrored, StatusCritical, StatusUnknown:
+	case StatusSuccessful, StatusErrored, StatusCritical, StatusDegraded,
+		StatusUnknown:
 		return value, nil
 	default:
 		return "", fmt.Errorf("unsupported status parameter value. only "+
-			"supporting status values %s, %s, %s, and %s but got %s",
-			StatusSuccessful, StatusErrored, StatusCritical, StatusUnknown,
-			value)
+			"supporting status values %s, %s, %s, %s, and %s but got %s",
+			StatusSuccessful, StatusErrored, StatusCritical, StatusDegraded,
+			StatusUnknown, value)
 	}
 }
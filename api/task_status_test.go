@@ -10,6 +10,7 @@ import (
 	"net/http/httptest"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/consul-terraform-sync/config"
 	serverMocks "github.com/hashicorp/consul-terraform-sync/mocks/server"
@@ -77,6 +78,15 @@ func TestTaskStatus_ServeHTTP(t *testing.T) {
 	ctrl.On("Events", mock.Anything, "").Return(events, nil)
 	ctrl.On("Tasks", mock.Anything).Return(confs)
 
+	queuedAt := time.Date(2022, time.March, 1, 12, 0, 0, 0, time.UTC)
+	ctrl.On("TaskPendingRuns", mock.Anything, "task_b").Return([]time.Time{queuedAt})
+	ctrl.On("TaskPendingRuns", mock.Anything, mock.Anything).Return(nil)
+	ctrl.On("TaskNextScheduledRun", mock.Anything, mock.Anything).Return(time.Time{}, false)
+	ctrl.On("TaskStats", mock.Anything, mock.Anything).Return(event.Stats{}, false)
+	ctrl.On("TaskCircuitBreakerOpen", mock.Anything, mock.Anything).Return(time.Time{}, false)
+	dependencies := map[string]int{"services.api": 3, "consul_kv.config/": 1}
+	ctrl.On("TaskDependencyTriggers", mock.Anything, "task_b").Return(dependencies)
+
 	handler := newTaskStatusHandler(ctrl, "v1")
 
 	cases := []struct {
@@ -101,12 +111,14 @@ func TestTaskStatus_ServeHTTP(t *testing.T) {
 					EventsURL: "/v1/status/tasks/task_a?include=events",
 				},
 				"task_b": {
-					TaskName:  "task_b",
-					Status:    StatusCritical,
-					Enabled:   true,
-					Providers: []string{},
-					Services:  []string{},
-					EventsURL: "/v1/status/tasks/task_b?include=events",
+					TaskName:       "task_b",
+					Status:         StatusCritical,
+					Enabled:        true,
+					Providers:      []string{},
+					Services:       []string{},
+					EventsURL:      "/v1/status/tasks/task_b?include=events",
+					PendingRuns:    1,
+					QueuedTriggers: []time.Time{queuedAt},
 				},
 				"task_c": {
 					TaskName:  "task_c",
@@ -141,13 +153,15 @@ func TestTaskStatus_ServeHTTP(t *testing.T) {
 					Events:    events["task_a"],
 				},
 				"task_b": {
-					TaskName:  "task_b",
-					Status:    StatusCritical,
-					Enabled:   true,
-					Providers: []string{},
-					Services:  []string{},
-					EventsURL: "/v1/status/tasks/task_b?include=events",
-					Events:    events["task_b"],
+					TaskName:       "task_b",
+					Status:         StatusCritical,
+					Enabled:        true,
+					Providers:      []string{},
+					Services:       []string{},
+					EventsURL:      "/v1/status/tasks/task_b?include=events",
+					PendingRuns:    1,
+					QueuedTriggers: []time.Time{queuedAt},
+					Events:         events["task_b"],
 				},
 				"task_c": {
 					TaskName:  "task_c",
@@ -175,12 +189,14 @@ func TestTaskStatus_ServeHTTP(t *testing.T) {
 			http.StatusOK,
 			map[string]TaskStatus{
 				"task_b": {
-					TaskName:  "task_b",
-					Status:    StatusCritical,
-					Enabled:   true,
-					Providers: []string{},
-					Services:  []string{},
-					EventsURL: "/v1/status/tasks/task_b?include=events",
+					TaskName:       "task_b",
+					Status:         StatusCritical,
+					Enabled:        true,
+					Providers:      []string{},
+					Services:       []string{},
+					EventsURL:      "/v1/status/tasks/task_b?include=events",
+					PendingRuns:    1,
+					QueuedTriggers: []time.Time{queuedAt},
 				},
 			},
 		},
@@ -206,12 +222,14 @@ func TestTaskStatus_ServeHTTP(t *testing.T) {
 			http.StatusOK,
 			map[string]TaskStatus{
 				"task_b": {
-					TaskName:  "task_b",
-					Status:    StatusCritical,
-					Enabled:   true,
-					Providers: []string{},
-					Services:  []string{},
-					EventsURL: "/v1/status/tasks/task_b?include=events",
+					TaskName:       "task_b",
+					Status:         StatusCritical,
+					Enabled:        true,
+					Providers:      []string{},
+					Services:       []string{},
+					EventsURL:      "/v1/status/tasks/task_b?include=events",
+					PendingRuns:    1,
+					QueuedTriggers: []time.Time{queuedAt},
 				},
 			},
 		},
@@ -222,13 +240,35 @@ func TestTaskStatus_ServeHTTP(t *testing.T) {
 			http.StatusOK,
 			map[string]TaskStatus{
 				"task_b": {
-					TaskName:  "task_b",
-					Status:    StatusCritical,
-					Enabled:   true,
-					Providers: []string{},
-					Services:  []string{},
-					EventsURL: "/v1/status/tasks/task_b?include=events",
-					Events:    events["task_b"],
+					TaskName:       "task_b",
+					Status:         StatusCritical,
+					Enabled:        true,
+					Providers:      []string{},
+					Services:       []string{},
+					EventsURL:      "/v1/status/tasks/task_b?include=events",
+					PendingRuns:    1,
+					QueuedTriggers: []time.Time{queuedAt},
+					Events:         events["task_b"],
+				},
+			},
+		},
+		{
+			"single task with events and dependencies",
+			"/v1/status/tasks/task_b?include=events,dependencies",
+			http.MethodGet,
+			http.StatusOK,
+			map[string]TaskStatus{
+				"task_b": {
+					TaskName:       "task_b",
+					Status:         StatusCritical,
+					Enabled:        true,
+					Providers:      []string{},
+					Services:       []string{},
+					EventsURL:      "/v1/status/tasks/task_b?include=events",
+					PendingRuns:    1,
+					QueuedTriggers: []time.Time{queuedAt},
+					Events:         events["task_b"],
+					Dependencies:   dependencies,
 				},
 			},
 		},
@@ -315,6 +355,90 @@ func TestTaskStatus_ServeHTTP(t *testing.T) {
 
 }
 
+func TestTaskStatus_ServeHTTP_Degraded(t *testing.T) {
+	events := map[string][]event.Event{
+		"task_a": {{Success: false}, {Success: false}},
+		"task_b": {{Success: false}, {Success: false}},
+	}
+	retryAt := time.Date(2022, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	ctrl := new(serverMocks.Server)
+	ctrl.On("Events", mock.Anything, "").Return(events, nil)
+	ctrl.On("Task", mock.Anything, "task_a").Return(createTaskConf("task_a", true), nil)
+	ctrl.On("Task", mock.Anything, "task_b").Return(createTaskConf("task_b", true), nil)
+	ctrl.On("Tasks", mock.Anything).Return(config.TaskConfigs{})
+	ctrl.On("TaskPendingRuns", mock.Anything, mock.Anything).Return(nil)
+	ctrl.On("TaskNextScheduledRun", mock.Anything, mock.Anything).Return(time.Time{}, false)
+	ctrl.On("TaskStats", mock.Anything, mock.Anything).Return(event.Stats{}, false)
+	ctrl.On("TaskCircuitBreakerOpen", mock.Anything, "task_a").Return(retryAt, true)
+	ctrl.On("TaskCircuitBreakerOpen", mock.Anything, "task_b").Return(time.Time{}, true)
+
+	handler := newTaskStatusHandler(ctrl, "v1")
+
+	req, err := http.NewRequest(http.MethodGet, "/v1/status/tasks?status=degraded", nil)
+	require.NoError(t, err)
+	resp := httptest.NewRecorder()
+
+	handler.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var actual map[string]TaskStatus
+	err = json.NewDecoder(resp.Body).Decode(&actual)
+	require.NoError(t, err)
+
+	require.Len(t, actual, 2)
+	assert.Equal(t, StatusDegraded, actual["task_a"].Status)
+	require.NotNil(t, actual["task_a"].RetryAt)
+	assert.Equal(t, retryAt, *actual["task_a"].RetryAt)
+	assert.Equal(t, StatusDegraded, actual["task_b"].Status)
+	assert.Nil(t, actual["task_b"].RetryAt)
+}
+
+func TestTaskStatus_ServeHTTP_Schedule(t *testing.T) {
+	events := map[string][]event.Event{
+		"task_a": {{Success: true}},
+	}
+	nextRunAt := time.Date(2022, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	taskConf := createTaskConf("task_a", true)
+	taskConf.Condition = &config.ScheduleConditionConfig{
+		ScheduleMonitorConfig: config.ScheduleMonitorConfig{
+			Cron: config.String("0 * * * *"),
+		},
+	}
+
+	ctrl := new(serverMocks.Server)
+	ctrl.On("Events", mock.Anything, "task_a").Return(events, nil)
+	ctrl.On("Task", mock.Anything, "task_a").Return(taskConf, nil)
+	ctrl.On("TaskPendingRuns", mock.Anything, mock.Anything).Return(nil)
+	ctrl.On("TaskNextScheduledRun", mock.Anything, "task_a").Return(nextRunAt, true)
+	stats := event.Stats{Runs: 4, Successes: 3, SuccessRate: 0.75,
+		AverageDuration: 2 * time.Second, FailureStreak: 1}
+	ctrl.On("TaskStats", mock.Anything, "task_a").Return(stats, true)
+	ctrl.On("TaskCircuitBreakerOpen", mock.Anything, mock.Anything).Return(time.Time{}, false)
+
+	handler := newTaskStatusHandler(ctrl, "v1")
+
+	req, err := http.NewRequest(http.MethodGet, "/v1/status/tasks/task_a", nil)
+	require.NoError(t, err)
+	resp := httptest.NewRecorder()
+
+	handler.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	var actual map[string]TaskStatus
+	err = json.NewDecoder(resp.Body).Decode(&actual)
+	require.NoError(t, err)
+
+	require.Len(t, actual, 1)
+	assert.Equal(t, "0 * * * *", actual["task_a"].Cron)
+	assert.Empty(t, actual["task_a"].Crons)
+	require.NotNil(t, actual["task_a"].NextRunAt)
+	assert.Equal(t, nextRunAt, *actual["task_a"].NextRunAt)
+	require.NotNil(t, actual["task_a"].Stats)
+	assert.Equal(t, stats, *actual["task_a"].Stats)
+}
+
 func TestTaskStatus_MakeStatus(t *testing.T) {
 	enabledTask := createTaskConf("test_task", true)
 	disabledTask := createTaskConf("test_task", false)
@@ -415,6 +539,56 @@ func TestTaskStatus_MakeStatus(t *testing.T) {
 				EventsURL: "/v1/status/tasks/test_task?include=events",
 			},
 		},
+		{
+			"suppressed events",
+			[]event.Event{
+				{
+					Success:    true,
+					Suppressed: true,
+				},
+				{
+					Success: false,
+				},
+				{
+					Success:    true,
+					Suppressed: true,
+				},
+				{
+					Success: true,
+				},
+			},
+			enabledTask,
+			TaskStatus{
+				TaskName:   "test_task",
+				Enabled:    true,
+				Status:     StatusErrored,
+				Providers:  []string{},
+				Services:   []string{},
+				EventsURL:  "/v1/status/tasks/test_task?include=events",
+				Suppressed: 2,
+			},
+		},
+		{
+			"expired event",
+			[]event.Event{
+				{
+					Success: true,
+					Expired: true,
+				},
+				{
+					Success: false,
+				},
+			},
+			disabledTask,
+			TaskStatus{
+				TaskName:  "test_task",
+				Enabled:   false,
+				Status:    StatusErrored,
+				Providers: []string{},
+				Services:  []string{},
+				EventsURL: "/v1/status/tasks/test_task?include=events",
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -537,43 +711,61 @@ func TestTaskStatus_Include(t *testing.T) {
 	cases := []struct {
 		name        string
 		path        string
-		include     bool
+		expected    statusIncludes
 		expectError bool
 	}{
 		{
 			"happy path include",
 			"/v1/status?include=events",
-			true,
+			statusIncludes{events: true},
 			false,
 		},
 		{
 			"happy path include with other parameters",
 			"/v1/status?include=events&status=critical",
-			true,
+			statusIncludes{events: true},
+			false,
+		},
+		{
+			"happy path include dependencies",
+			"/v1/status?include=dependencies",
+			statusIncludes{dependencies: true},
+			false,
+		},
+		{
+			"happy path include events and dependencies",
+			"/v1/status?include=events,dependencies",
+			statusIncludes{events: true, dependencies: true},
 			false,
 		},
 		{
 			"happy path don't include",
 			"/v1/status",
-			false,
+			statusIncludes{},
 			false,
 		},
 		{
 			"bad include parameter",
 			"/v1/status?include=badparam",
-			false,
+			statusIncludes{},
+			true,
+		},
+		{
+			"bad include parameter in list",
+			"/v1/status?include=events,badparam",
+			statusIncludes{},
 			true,
 		},
 		{
 			"missing include value",
 			"/v1/status?include=",
-			false,
+			statusIncludes{},
 			true,
 		},
 		{
 			"too many include parameters",
 			"/v1/status?include=stuff&include=morestuff",
-			false,
+			statusIncludes{},
 			true,
 		},
 	}
@@ -588,7 +780,7 @@ func TestTaskStatus_Include(t *testing.T) {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, tc.include, actual)
+				assert.Equal(t, tc.expected, actual)
 			}
 		})
 	}
@@ -625,6 +817,12 @@ func TestTaskStatus_StatusFilter(t *testing.T) {
 			StatusSuccessful,
 			false,
 		},
+		{
+			"degraded status",
+			"/v1/status/tasks?status=degraded",
+			StatusDegraded,
+			false,
+		},
 		{
 			"unknown status",
 			"/v1/status/tasks?status=badstatus",
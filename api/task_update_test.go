@@ -227,6 +227,27 @@ func TestTask_updateTask(t *testing.T) {
 		})
 	}
 
+	t.Run("request ID", func(t *testing.T) {
+		requestID := "e9926514-79b8-a8fc-8761-9b6aaccf1e15"
+		ctrl := new(mocks.Server)
+		ctrl.On("Task", mock.Anything, "task_a").Return(config.TaskConfig{}, nil).
+			On("TaskUpdate", mock.Anything, mock.Anything, "").Return(true, "", "", nil)
+		handler := newTaskHandler(ctrl, "v1")
+
+		ctx := requestIDWithContext(context.Background(), requestID)
+		req, err := http.NewRequestWithContext(ctx, http.MethodPatch,
+			"/v1/tasks/task_a", strings.NewReader(`{"enabled": true}`))
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+
+		handler.updateTask(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var actual UpdateTaskResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&actual))
+		assert.Equal(t, requestID, actual.RequestID.String())
+	})
+
 	t.Run("cancel", func(t *testing.T) {
 		// have the server delay on response, and the client cancel to ensure
 		// the handler exits immediately
@@ -242,7 +263,7 @@ func TestTask_updateTask(t *testing.T) {
 		require.NoError(t, err)
 
 		ctrl.On("Task", req.Context(), "task_a").Return(config.TaskConfig{}, nil)
-		ctrl.On("TaskUpdate", req.Context(), mock.Anything, "").
+		ctrl.On("TaskUpdate", mock.Anything, mock.Anything, "").
 			Run(func(mock.Arguments) {
 				<-req.Context().Done()
 				assert.Equal(t, req.Context().Err(), context.Canceled)
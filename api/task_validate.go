@@ -0,0 +1,109 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/consul-terraform-sync/api/oapigen"
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+)
+
+const (
+	validateTaskSubsystemName = "validatetask"
+
+	taskValidatePath = "validate"
+
+	// Types of the errors of the task validate endpoint
+	TaskValidateErrorConfig = "config"
+	TaskValidateErrorModule = "module"
+)
+
+// TaskValidateError is an error found validating a task. Errors of the module
+// type include the variable and the path within the variable type of the
+// incompatibility with the module.
+type TaskValidateError struct {
+	Type     string `json:"type"`
+	Message  string `json:"message"`
+	Variable string `json:"variable,omitempty"`
+	Path     string `json:"path,omitempty"`
+}
+
+// TaskValidateResponse is the response of the task validate endpoint.
+// ModuleChecked is false if the variables of the module were not checked,
+// e.g. for remote modules.
+type TaskValidateResponse struct {
+	RequestId     oapigen.RequestID   `json:"request_id"`
+	Valid         bool                `json:"valid"`
+	ModuleChecked bool                `json:"module_checked"`
+	Errors        []TaskValidateError `json:"errors"`
+	Warnings      []string            `json:"warnings"`
+}
+
+// isTaskValidatePath returns true if the path is the task validate path
+// /v1/tasks/validate
+func isTaskValidatePath(reqPath, version string) bool {
+	return reqPath == fmt.Sprintf("/%s/%s/%s", version, taskPath, taskValidatePath)
+}
+
+// validateTask validates a task request with the same body as the create task
+// endpoint without creating the task. The task configuration is validated and
+// the variables of local modules are checked against the variables rendered
+// for the task. Terraform is not run. An invalid task is not an error of the
+// request, so the errors are returned in the response body.
+func (h *taskHandler) validateTask(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.FromContext(ctx).Named(validateTaskSubsystemName)
+	logger.Trace("validate task request")
+
+	var req TaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Trace("problem decoding body from validate request", "error", err)
+		sendError(w, r, http.StatusBadRequest,
+			fmt.Errorf("error decoding the request: %v", err))
+		return
+	}
+	logger = logger.With("task_name", req.Task.Name)
+
+	resp := TaskValidateResponse{
+		RequestId: requestIDFromContext(ctx),
+		Errors:    []TaskValidateError{},
+		Warnings:  []string{},
+	}
+
+	tc, err := req.ToTaskConfig()
+	if err == nil {
+		var result driver.ModuleValidation
+		result, err = h.ctrl.TaskValidate(ctx, tc)
+		resp.ModuleChecked = result.Checked
+		resp.Warnings = append(resp.Warnings, result.Warnings...)
+		for _, m := range result.Mismatches {
+			resp.Errors = append(resp.Errors, TaskValidateError{
+				Type:     TaskValidateErrorModule,
+				Message:  m.Message,
+				Variable: m.Variable,
+				Path:     m.Path,
+			})
+		}
+	}
+	if err != nil {
+		errType := TaskValidateErrorConfig
+		var moduleErr *driver.ModuleError
+		if errors.As(err, &moduleErr) {
+			errType = TaskValidateErrorModule
+		}
+		resp.Errors = append(resp.Errors, TaskValidateError{
+			Type:    errType,
+			Message: err.Error(),
+		})
+	}
+
+	resp.Valid = len(resp.Errors) == 0
+	logger.Trace("task validated", "valid", resp.Valid)
+	writeResponse(w, r, http.StatusOK, resp)
+}
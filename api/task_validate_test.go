@@ -0,0 +1,144 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	serverMocks "github.com/hashicorp/consul-terraform-sync/mocks/server"
+	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskValidate_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		body       string
+		setup      func(*serverMocks.Server)
+		statusCode int
+		expected   *TaskValidateResponse
+	}{
+		{
+			"valid",
+			testTaskJSON,
+			func(ctrl *serverMocks.Server) {
+				ctrl.On("TaskValidate", mock.Anything, testTaskConfig).Return(
+					driver.ModuleValidation{
+						Checked:  true,
+						Warnings: []string{"unable to parse type"},
+					}, nil).Once()
+			},
+			http.StatusOK,
+			&TaskValidateResponse{
+				Valid:         true,
+				ModuleChecked: true,
+				Errors:        []TaskValidateError{},
+				Warnings:      []string{"unable to parse type"},
+			},
+		},
+		{
+			"module mismatches",
+			testTaskJSON,
+			func(ctrl *serverMocks.Server) {
+				ctrl.On("TaskValidate", mock.Anything, testTaskConfig).Return(
+					driver.ModuleValidation{
+						Checked: true,
+						Mismatches: []tftmpl.VariableMismatch{{
+							Variable: "services",
+							Path:     "services[*].port",
+							Message:  "module declares bool, but CTS passes number",
+						}},
+					}, nil).Once()
+			},
+			http.StatusOK,
+			&TaskValidateResponse{
+				ModuleChecked: true,
+				Errors: []TaskValidateError{{
+					Type:     TaskValidateErrorModule,
+					Message:  "module declares bool, but CTS passes number",
+					Variable: "services",
+					Path:     "services[*].port",
+				}},
+				Warnings: []string{},
+			},
+		},
+		{
+			"invalid config",
+			testTaskJSON,
+			func(ctrl *serverMocks.Server) {
+				ctrl.On("TaskValidate", mock.Anything, testTaskConfig).Return(
+					driver.ModuleValidation{}, errors.New("invalid task")).Once()
+			},
+			http.StatusOK,
+			&TaskValidateResponse{
+				Errors: []TaskValidateError{{
+					Type:    TaskValidateErrorConfig,
+					Message: "invalid task",
+				}},
+				Warnings: []string{},
+			},
+		},
+		{
+			"module error",
+			testTaskJSON,
+			func(ctrl *serverMocks.Server) {
+				ctrl.On("TaskValidate", mock.Anything, testTaskConfig).Return(
+					driver.ModuleValidation{}, &driver.ModuleError{
+						Source: "./example-module",
+						Err:    errors.New("no Terraform files found"),
+					}).Once()
+			},
+			http.StatusOK,
+			&TaskValidateResponse{
+				Errors: []TaskValidateError{{
+					Type: TaskValidateErrorModule,
+					Message: `unable to check the variables of module ` +
+						`"./example-module": no Terraform files found`,
+				}},
+				Warnings: []string{},
+			},
+		},
+		{
+			"invalid body",
+			`{"task": `,
+			func(ctrl *serverMocks.Server) {},
+			http.StatusBadRequest,
+			nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := new(serverMocks.Server)
+			tc.setup(ctrl)
+			handler := newTaskHandler(ctrl, "v1")
+
+			req, err := http.NewRequest(http.MethodPost, "/v1/tasks/validate",
+				strings.NewReader(tc.body))
+			require.NoError(t, err)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+			require.Equal(t, tc.statusCode, resp.Code)
+			ctrl.AssertExpectations(t)
+
+			if tc.expected == nil {
+				return
+			}
+			var actual TaskValidateResponse
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&actual))
+			actual.RequestId = tc.expected.RequestId
+			assert.Equal(t, *tc.expected, actual)
+		})
+	}
+}
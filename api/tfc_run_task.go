@@ -0,0 +1,264 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+)
+
+const (
+	tfcRunTaskPath          = "integrations/tfc-run-task"
+	tfcRunTaskSubsystemName = "tfcruntask"
+
+	// tfcRunTaskSignatureHeader is the header of the HMAC signature of a run
+	// task request signed by Terraform Cloud
+	tfcRunTaskSignatureHeader = "X-TFC-Task-Signature"
+
+	// tfcRunTaskVerificationToken is the access token of the request sent by
+	// Terraform Cloud to verify the endpoint when the run task is created
+	tfcRunTaskVerificationToken = "verification-token"
+
+	// Statuses of a run task result
+	tfcRunTaskPassed = "passed"
+	tfcRunTaskFailed = "failed"
+
+	// tfcRunTaskMaxBodySize limits the size of run task requests
+	tfcRunTaskMaxBodySize = 1 << 20
+
+	// tfcRunTaskCallbackTimeout is the timeout to send the run task result
+	tfcRunTaskCallbackTimeout = 30 * time.Second
+)
+
+// tfcRunTaskRequest is the payload of a Terraform Cloud run task request.
+// Only the fields used by CTS are included.
+type tfcRunTaskRequest struct {
+	PayloadVersion        int    `json:"payload_version"`
+	AccessToken           string `json:"access_token"`
+	Stage                 string `json:"stage"`
+	TaskResultCallbackURL string `json:"task_result_callback_url"`
+	RunID                 string `json:"run_id"`
+	WorkspaceName         string `json:"workspace_name"`
+	OrganizationName      string `json:"organization_name"`
+}
+
+// tfcRunTaskResult is the payload to send the result of a run task to
+// Terraform Cloud
+type tfcRunTaskResult struct {
+	Data tfcRunTaskResultData `json:"data"`
+}
+
+type tfcRunTaskResultData struct {
+	Type       string                     `json:"type"`
+	Attributes tfcRunTaskResultAttributes `json:"attributes"`
+}
+
+type tfcRunTaskResultAttributes struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// tfcRunTaskHandler handles the Terraform Cloud run task endpoint. Terraform
+// Cloud calls the endpoint during a run of a workspace, and the handler
+// verifies that the Consul state is applied by the CTS task with the same
+// name as the workspace. The result is sent to the callback URL of the run.
+type tfcRunTaskHandler struct {
+	ctrl    Server
+	hmacKey []byte
+	client  *http.Client
+}
+
+// newTFCRunTaskHandler returns a new Terraform Cloud run task handler
+func newTFCRunTaskHandler(ctrl Server, hmacKey string,
+	proxy *config.ProxyConfig) *tfcRunTaskHandler {
+	return &tfcRunTaskHandler{
+		ctrl:    ctrl,
+		hmacKey: []byte(hmacKey),
+		client: &http.Client{
+			Timeout:   tfcRunTaskCallbackTimeout,
+			Transport: proxy.Transport(),
+		},
+	}
+}
+
+// ServeHTTP serves the run task endpoint. Requests are verified with the
+// HMAC signature and responded to immediately. The result of the run task is
+// sent asynchronously to the callback URL of the request.
+func (h *tfcRunTaskHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := logging.FromContext(ctx).Named(tfcRunTaskSubsystemName)
+	logger.Trace("received run task request", "url_path", r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		err := fmt.Errorf("'%s' in an unsupported method. The run task API "+
+			"currently supports the method(s): '%s'", r.Method, http.MethodPost)
+		logger.Trace("unsupported method", "error", err)
+		jsonErrorResponse(ctx, w, http.StatusMethodNotAllowed, err)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, tfcRunTaskMaxBodySize))
+	if err != nil {
+		jsonErrorResponse(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	if !h.validSignature(body, r.Header.Get(tfcRunTaskSignatureHeader)) {
+		err := fmt.Errorf("invalid %s header", tfcRunTaskSignatureHeader)
+		logger.Warn("run task request failed verification", "error", err)
+		jsonErrorResponse(ctx, w, http.StatusUnauthorized, err)
+		return
+	}
+
+	var req tfcRunTaskRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		logger.Trace("bad request", "error", err)
+		jsonErrorResponse(ctx, w, http.StatusBadRequest, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	if req.AccessToken == tfcRunTaskVerificationToken {
+		logger.Debug("verified run task endpoint")
+		return
+	}
+
+	go func() {
+		// use a new context since the request context is canceled once the
+		// response is sent
+		ctx, cancel := context.WithTimeout(context.Background(),
+			tfcRunTaskCallbackTimeout)
+		defer cancel()
+
+		status, message := h.verify(ctx, req.WorkspaceName)
+		logger.Debug("run task result", "run_id", req.RunID,
+			"workspace_name", req.WorkspaceName, "status", status)
+		if err := h.sendResult(ctx, req, status, message); err != nil {
+			logger.Error("error sending run task result", "run_id", req.RunID,
+				"error", err)
+		}
+	}()
+}
+
+// validSignature returns true if the signature is the HMAC-SHA512 of the
+// request body with the configured key
+func (h *tfcRunTaskHandler) validSignature(body []byte, signature string) bool {
+	sig, err := hex.DecodeString(signature)
+	if err != nil || len(sig) == 0 {
+		return false
+	}
+
+	mac := hmac.New(sha512.New, h.hmacKey)
+	mac.Write(body)
+	return hmac.Equal(sig, mac.Sum(nil))
+}
+
+// verify checks that the Consul state is applied by the task for the
+// workspace. Returns the status of the run task result and a message.
+func (h *tfcRunTaskHandler) verify(ctx context.Context, workspace string) (string, string) {
+	var task *config.TaskConfig
+	for _, tc := range h.ctrl.Tasks(ctx) {
+		if config.StringVal(tc.Name) == workspace {
+			task = tc
+			break
+		}
+	}
+	if task == nil {
+		return tfcRunTaskFailed, fmt.Sprintf("no CTS task found for "+
+			"workspace '%s'", workspace)
+	}
+
+	taskName := config.StringVal(task.Name)
+	if !config.BoolVal(task.Enabled) {
+		return tfcRunTaskFailed, fmt.Sprintf("task '%s' is disabled", taskName)
+	}
+
+	if _, open := h.ctrl.TaskCircuitBreakerOpen(ctx, taskName); open {
+		return tfcRunTaskFailed, fmt.Sprintf("task '%s' is paused by its "+
+			"circuit breaker", taskName)
+	}
+
+	if pending := h.ctrl.TaskPendingRuns(ctx, taskName); len(pending) > 0 {
+		return tfcRunTaskFailed, fmt.Sprintf("task '%s' has %d pending "+
+			"run(s) for Consul changes that are not applied", taskName, len(pending))
+	}
+
+	events, err := h.ctrl.Events(ctx, taskName)
+	if err != nil {
+		return tfcRunTaskFailed, fmt.Sprintf("unable to retrieve events for "+
+			"task '%s': %s", taskName, err)
+	}
+	taskEvents := events[taskName]
+	if len(taskEvents) == 0 {
+		return tfcRunTaskFailed, fmt.Sprintf("task '%s' has not run yet",
+			taskName)
+	}
+
+	// Suppressed events are recorded as successful for the triggers that
+	// are deferred by the task's cooldown or maintenance window. A suppressed
+	// latest event means that the Consul changes are not applied until the
+	// deferred run completes.
+	latest := taskEvents[0]
+	if latest.Suppressed {
+		return tfcRunTaskFailed, fmt.Sprintf("task '%s' has a deferred run "+
+			"for Consul changes that are not applied", taskName)
+	}
+	if !latest.Success {
+		return tfcRunTaskFailed, fmt.Sprintf("the latest run of task '%s' "+
+			"failed", taskName)
+	}
+
+	return tfcRunTaskPassed, fmt.Sprintf("Consul state is applied by task "+
+		"'%s' as of %s", taskName, latest.EndTime.Format(time.RFC3339))
+}
+
+// sendResult sends the result of the run task to the callback URL
+func (h *tfcRunTaskHandler) sendResult(ctx context.Context, req tfcRunTaskRequest,
+	status, message string) error {
+
+	result := tfcRunTaskResult{
+		Data: tfcRunTaskResultData{
+			Type: "task-results",
+			Attributes: tfcRunTaskResultAttributes{
+				Status:  status,
+				Message: message,
+			},
+		},
+	}
+	b, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPatch,
+		req.TaskResultCallbackURL, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/vnd.api+json")
+	httpReq.Header.Set("Authorization", "Bearer "+req.AccessToken)
+
+	resp, err := h.client.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("callback responded with status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	mocks "github.com/hashicorp/consul-terraform-sync/mocks/server"
+	"github.com/hashicorp/consul-terraform-sync/state/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTFCRunTask_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	key := "hmac-key"
+	sign := func(body []byte) string {
+		mac := hmac.New(sha512.New, []byte(key))
+		mac.Write(body)
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+
+	// callback server captures the run task results
+	type result struct {
+		auth   string
+		result tfcRunTaskResult
+	}
+	results := make(chan result, 1)
+	callback := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPatch, r.Method)
+			assert.Equal(t, "application/vnd.api+json", r.Header.Get("Content-Type"))
+			var res tfcRunTaskResult
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&res))
+			results <- result{auth: r.Header.Get("Authorization"), result: res}
+		}))
+	defer callback.Close()
+
+	var confs config.TaskConfigs
+	for _, name := range []string{"task_a", "task_b", "task_c", "task_d", "task_e", "task_f"} {
+		conf := createTaskConf(name, name != "task_c")
+		confs = append(confs, &conf)
+	}
+	ctrl := new(mocks.Server)
+	ctrl.On("Tasks", mock.Anything).Return(confs)
+	ctrl.On("TaskCircuitBreakerOpen", mock.Anything, "task_d").Return(time.Now(), true)
+	ctrl.On("TaskCircuitBreakerOpen", mock.Anything, mock.Anything).Return(time.Time{}, false)
+	ctrl.On("TaskPendingRuns", mock.Anything, "task_e").Return([]time.Time{time.Now()})
+	ctrl.On("TaskPendingRuns", mock.Anything, mock.Anything).Return(nil)
+	ctrl.On("Events", mock.Anything, "task_a").Return(map[string][]event.Event{
+		"task_a": {{TaskName: "task_a", Success: true}, {TaskName: "task_a", Success: false}},
+	}, nil)
+	ctrl.On("Events", mock.Anything, "task_b").Return(map[string][]event.Event{
+		"task_b": {{TaskName: "task_b", Success: false}},
+	}, nil)
+	ctrl.On("Events", mock.Anything, "task_f").Return(map[string][]event.Event{
+		"task_f": {
+			{TaskName: "task_f", Success: true, Suppressed: true},
+			{TaskName: "task_f", Success: true},
+		},
+	}, nil)
+
+	handler := newTFCRunTaskHandler(ctrl, key, nil)
+
+	request := func(payload tfcRunTaskRequest, signature func([]byte) string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(payload)
+		require.NoError(t, err)
+		req, err := http.NewRequest(http.MethodPost,
+			fmt.Sprintf("/v1/%s", tfcRunTaskPath), bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set(tfcRunTaskSignatureHeader, signature(body))
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		return resp
+	}
+
+	payload := func(workspace string) tfcRunTaskRequest {
+		return tfcRunTaskRequest{
+			PayloadVersion:        1,
+			AccessToken:           "token",
+			Stage:                 "post_plan",
+			TaskResultCallbackURL: callback.URL,
+			RunID:                 "run-123",
+			WorkspaceName:         workspace,
+			OrganizationName:      "org",
+		}
+	}
+
+	t.Run("invalid signature", func(t *testing.T) {
+		resp := request(payload("task_a"), func([]byte) string {
+			return sign([]byte("other"))
+		})
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	})
+
+	t.Run("missing signature", func(t *testing.T) {
+		resp := request(payload("task_a"), func([]byte) string { return "" })
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	})
+
+	t.Run("unsupported method", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet,
+			fmt.Sprintf("/v1/%s", tfcRunTaskPath), nil)
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	})
+
+	t.Run("verification", func(t *testing.T) {
+		p := payload("")
+		p.AccessToken = tfcRunTaskVerificationToken
+		resp := request(p, sign)
+		assert.Equal(t, http.StatusOK, resp.Code)
+
+		select {
+		case <-results:
+			t.Fatal("unexpected callback for verification request")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	cases := []struct {
+		name      string
+		workspace string
+		status    string
+	}{
+		{"passed", "task_a", tfcRunTaskPassed},
+		{"latest run failed", "task_b", tfcRunTaskFailed},
+		{"task disabled", "task_c", tfcRunTaskFailed},
+		{"circuit breaker open", "task_d", tfcRunTaskFailed},
+		{"pending runs", "task_e", tfcRunTaskFailed},
+		{"deferred run", "task_f", tfcRunTaskFailed},
+		{"task not found", "task_z", tfcRunTaskFailed},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := request(payload(tc.workspace), sign)
+			assert.Equal(t, http.StatusOK, resp.Code)
+
+			select {
+			case r := <-results:
+				assert.Equal(t, "Bearer token", r.auth)
+				assert.Equal(t, "task-results", r.result.Data.Type)
+				assert.Equal(t, tc.status, r.result.Data.Attributes.Status)
+				assert.NotEmpty(t, r.result.Data.Attributes.Message)
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for run task result")
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/hashicorp/consul-terraform-sync/api/oapigen"
+	"github.com/hashicorp/consul-terraform-sync/version"
+)
+
+const (
+	// APIVersion is the version of the API that is served by the daemon and
+	// requested by the clients of this binary
+	APIVersion = defaultAPIVersion
+
+	versionPath = "version"
+
+	// FeatureTaskBatch is the API feature to enable, disable, or delete tasks
+	// in a batch with the tasks batch endpoint
+	FeatureTaskBatch = "task_batch"
+
+	// FeatureTaskFiltering is the API feature to filter and paginate the
+	// tasks returned by the get all tasks endpoint
+	FeatureTaskFiltering = "task_filtering"
+
+	// FeatureTaskRender is the API feature to render the template of a task
+	// without running the task with the task render endpoint
+	FeatureTaskRender = "task_render"
+
+	// FeatureTaskInputsDiff is the API feature to compare the input variables
+	// applied by two runs of a task with the task inputs diff endpoint
+	FeatureTaskInputsDiff = "task_inputs_diff"
+
+	// FeatureTaskReinit is the API feature to re-initialize a task without
+	// restarting the daemon with the task reinit endpoint
+	FeatureTaskReinit = "task_reinit"
+)
+
+// apiFeatures are the optional features of the API that clients can check
+// for before using them
+var apiFeatures = []string{
+	FeatureTaskBatch,
+	FeatureTaskFiltering,
+	FeatureTaskRender,
+	FeatureTaskInputsDiff,
+	FeatureTaskReinit,
+}
+
+// VersionResponse is the response of the version endpoint
+type VersionResponse oapigen.VersionResponse
+
+// HasFeature returns true if the feature is supported by the CTS daemon
+func (v VersionResponse) HasFeature(feature string) bool {
+	for _, f := range v.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// VersionHandler handles the version endpoint
+type VersionHandler struct {
+	version  string
+	features []string
+}
+
+// NewVersionHandler creates a new version handler that returns the version of
+// the binary and the features of the API and of the binary
+func NewVersionHandler() *VersionHandler {
+	features := make([]string, 0, len(apiFeatures)+len(version.Features))
+	features = append(features, apiFeatures...)
+	features = append(features, version.Features...)
+	sort.Strings(features)
+
+	return &VersionHandler{
+		version:  version.GetSemanticVersion(),
+		features: features,
+	}
+}
+
+// GetVersion returns the version of CTS, the version of the API, and the
+// supported features
+func (h *VersionHandler) GetVersion(w http.ResponseWriter, r *http.Request) {
+	writeResponse(w, r, http.StatusOK, oapigen.VersionResponse{
+		Version:    h.version,
+		ApiVersion: defaultAPIVersion,
+		Features:   h.features,
+		RequestId:  requestIDFromContext(r.Context()),
+	})
+}
@@ -0,0 +1,40 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/api/oapigen"
+	"github.com/hashicorp/consul-terraform-sync/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionHandler_GetVersion(t *testing.T) {
+	// not parallel, the compiled features are global
+	features := version.Features
+	version.Features = []string{version.FeatureEnterprise}
+	t.Cleanup(func() { version.Features = features })
+
+	handler := NewVersionHandler()
+
+	req, err := http.NewRequest(http.MethodGet, "/v1/version", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	handler.GetVersion(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var resp oapigen.VersionResponse
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+	assert.Equal(t, version.GetSemanticVersion(), resp.Version)
+	assert.Equal(t, APIVersion, resp.ApiVersion)
+	assert.Equal(t, []string{version.FeatureEnterprise, FeatureTaskBatch,
+		FeatureTaskFiltering, FeatureTaskInputsDiff, FeatureTaskReinit,
+		FeatureTaskRender},
+		resp.Features)
+}
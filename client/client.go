@@ -5,6 +5,7 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 )
 
@@ -19,6 +20,14 @@ type Client interface {
 	// SetStdout Set the standard out for the client
 	SetStdout(w io.Writer)
 
+	// SetProgress Set the writer for the machine-readable UI of plans and
+	// applies, which is used to track the progress of a run. Nil unsets it.
+	SetProgress(w io.Writer)
+
+	// SetTargets Set the resource addresses to limit plans and applies to.
+	// No targets resets the client to plan and apply all resources.
+	SetTargets(targets []string)
+
 	// Init initializes the client and environment
 	Init(ctx context.Context) error
 
@@ -28,9 +37,20 @@ type Client interface {
 	// Plan makes a request to generate a plan of proposed changes
 	Plan(ctx context.Context) (bool, error)
 
+	// SavePlan generates a plan of proposed changes, saves it to the plan
+	// file, and returns the JSON representation of the plan
+	SavePlan(ctx context.Context, planFile string) ([]byte, error)
+
+	// ApplyPlan makes a request to apply the changes of a saved plan file
+	ApplyPlan(ctx context.Context, planFile string) error
+
 	// Validate verifies that the generated configurations are valid
 	Validate(ctx context.Context) error
 
+	// Outputs returns the JSON values of the Terraform outputs of the root
+	// module by output name
+	Outputs(ctx context.Context) (map[string]json.RawMessage, error)
+
 	// GoString defines the printable version of the client
 	GoString() string
 }
@@ -98,7 +98,9 @@ type ConsulClient struct {
 // potentially change over time.
 type ConsulAgentConfig = map[string]map[string]interface{}
 
-// NewConsulClient constructs a consul api client
+// NewConsulClient constructs a consul api client. When multiple Consul
+// addresses are configured, the client uses the first address where the
+// Consul agent is reachable, falling back to the first address.
 func NewConsulClient(conf *config.ConsulConfig, maxRetry int) (*ConsulClient, error) {
 	t := hcat.TransportInput{
 		SSLEnabled: *conf.TLS.Enabled,
@@ -134,6 +136,10 @@ func NewConsulClient(conf *config.ConsulConfig, maxRetry int) (*ConsulClient, er
 
 	logger := logging.Global().Named(loggingSystemName).Named(consulSubsystemName)
 
+	if addrs := conf.ConsulAddresses(); len(addrs) > 1 {
+		clients = reachableConsulClients(logger, clients, addrs, ci)
+	}
+
 	r := retry.NewRetry(maxRetry, time.Now().UnixNano())
 	c := &ConsulClient{
 		Client: clients.Consul(),
@@ -144,6 +150,45 @@ func NewConsulClient(conf *config.ConsulConfig, maxRetry int) (*ConsulClient, er
 	return c, nil
 }
 
+// reachableConsulClients returns the clients for the first address where the
+// Consul agent is reachable. The clients for the first address are returned
+// if no address is reachable.
+func reachableConsulClients(logger logging.Logger, clients *hcat.ClientSet,
+	addresses []string, ci hcat.ConsulInput) *hcat.ClientSet {
+
+	for i, addr := range addresses {
+		cs := clients
+		if i > 0 {
+			ci.Address = addr
+			cs = hcat.NewClientSet()
+			if err := cs.AddConsul(ci); err != nil {
+				logger.Warn("unable to create client for consul address",
+					"address", addr, "error", err)
+				continue
+			}
+		}
+
+		if _, err := cs.Consul().Status().Leader(); err != nil {
+			logger.Warn("consul address is unreachable", "address", addr,
+				"error", err)
+			if i > 0 {
+				cs.Stop()
+			}
+			continue
+		}
+
+		if i > 0 {
+			logger.Info("using consul address", "address", addr)
+			clients.Stop()
+		}
+		return cs
+	}
+
+	logger.Warn("no reachable consul address, using the first address",
+		"address", addresses[0])
+	return clients
+}
+
 // GetLicense queries Consul for a signed license, and returns it if available
 // GetLicense is a Consul Enterprise only endpoint, a 404 returned assumes we are connected to OSS Consul
 // GetLicense does not require any ACLs
@@ -0,0 +1,166 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var _ Client = (*Environments)(nil)
+
+// ErrEnvironmentOutputs is returned for the outputs of a client with
+// environments, since each environment has its own outputs
+var ErrEnvironmentOutputs = errors.New("outputs are not supported for tasks " +
+	"with environments")
+
+// Environment is the client of an environment of a task
+type Environment struct {
+	Name   string
+	Client Client
+}
+
+// Environments is a client that fans out to the client of each environment
+// of a task. Environments are initialized, planned, and applied in order. An
+// apply stops at the first environment that errors so that the later
+// environments, e.g. production, are not changed.
+type Environments struct {
+	envs []Environment
+}
+
+// NewEnvironments creates a client for the environments
+func NewEnvironments(envs []Environment) (*Environments, error) {
+	if len(envs) == 0 {
+		return nil, errors.New("environments client requires at least one environment")
+	}
+	return &Environments{envs: envs}, nil
+}
+
+// SetEnv sets the environment for the client of each environment
+func (e *Environments) SetEnv(env map[string]string) error {
+	for _, c := range e.envs {
+		if err := c.Client.SetEnv(env); err != nil {
+			return environmentError(c.Name, err)
+		}
+	}
+	return nil
+}
+
+// SetStdout sets the standard out for the client of each environment
+func (e *Environments) SetStdout(w io.Writer) {
+	for _, c := range e.envs {
+		c.Client.SetStdout(w)
+	}
+}
+
+// SetProgress sets the progress writer for the client of each environment
+func (e *Environments) SetProgress(w io.Writer) {
+	for _, c := range e.envs {
+		c.Client.SetProgress(w)
+	}
+}
+
+// SetTargets sets the targets for the client of each environment
+func (e *Environments) SetTargets(targets []string) {
+	for _, c := range e.envs {
+		c.Client.SetTargets(targets)
+	}
+}
+
+// Init initializes each environment
+func (e *Environments) Init(ctx context.Context) error {
+	for _, c := range e.envs {
+		if err := c.Client.Init(ctx); err != nil {
+			return environmentError(c.Name, err)
+		}
+	}
+	return nil
+}
+
+// Apply applies each environment
+func (e *Environments) Apply(ctx context.Context) error {
+	for _, c := range e.envs {
+		if err := c.Client.Apply(ctx); err != nil {
+			return environmentError(c.Name, err)
+		}
+	}
+	return nil
+}
+
+// Plan plans each environment and returns true if any environment has
+// changes
+func (e *Environments) Plan(ctx context.Context) (bool, error) {
+	var changes bool
+	for _, c := range e.envs {
+		ok, err := c.Client.Plan(ctx)
+		if err != nil {
+			return changes, environmentError(c.Name, err)
+		}
+		changes = changes || ok
+	}
+	return changes, nil
+}
+
+// SavePlan saves the plan of each environment to the plan file suffixed with
+// the environment name. The JSON representation is an object of the plans
+// of the environments by name.
+func (e *Environments) SavePlan(ctx context.Context, planFile string) ([]byte, error) {
+	plans := make(map[string]json.RawMessage, len(e.envs))
+	for _, c := range e.envs {
+		plan, err := c.Client.SavePlan(ctx, EnvironmentPlanFile(planFile, c.Name))
+		if err != nil {
+			return nil, environmentError(c.Name, err)
+		}
+		plans[c.Name] = plan
+	}
+	return json.Marshal(plans)
+}
+
+// ApplyPlan applies the saved plan of each environment
+func (e *Environments) ApplyPlan(ctx context.Context, planFile string) error {
+	for _, c := range e.envs {
+		err := c.Client.ApplyPlan(ctx, EnvironmentPlanFile(planFile, c.Name))
+		if err != nil {
+			return environmentError(c.Name, err)
+		}
+	}
+	return nil
+}
+
+// Validate validates the configuration with the client of the first
+// environment, since the environments share the configuration
+func (e *Environments) Validate(ctx context.Context) error {
+	return e.envs[0].Client.Validate(ctx)
+}
+
+// Outputs is not supported for environments
+func (e *Environments) Outputs(context.Context) (map[string]json.RawMessage, error) {
+	return nil, ErrEnvironmentOutputs
+}
+
+// GoString defines the printable version of this struct.
+func (e *Environments) GoString() string {
+	if e == nil {
+		return "(*Environments)(nil)"
+	}
+
+	s := make([]string, len(e.envs))
+	for i, c := range e.envs {
+		s[i] = fmt.Sprintf("%s:%s", c.Name, c.Client.GoString())
+	}
+	return "&Environments{" + strings.Join(s, ", ") + "}"
+}
+
+// EnvironmentPlanFile returns the path of the plan file of an environment
+func EnvironmentPlanFile(planFile, name string) string {
+	return planFile + "." + name
+}
+
+func environmentError(name string, err error) error {
+	return fmt.Errorf("environment %q: %w", name, err)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	mocks "github.com/hashicorp/consul-terraform-sync/mocks/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEnvironments(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewEnvironments(nil)
+	assert.Error(t, err)
+
+	envs, err := NewEnvironments([]Environment{{Name: "prod", Client: new(mocks.Client)}})
+	assert.NoError(t, err)
+	assert.NotNil(t, envs)
+}
+
+func TestEnvironments_Apply(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("happy path", func(t *testing.T) {
+		staging := new(mocks.Client)
+		staging.On("Apply", ctx).Return(nil).Once()
+		prod := new(mocks.Client)
+		prod.On("Apply", ctx).Return(nil).Once()
+
+		envs, err := NewEnvironments([]Environment{
+			{Name: "staging", Client: staging},
+			{Name: "prod", Client: prod},
+		})
+		require.NoError(t, err)
+
+		assert.NoError(t, envs.Apply(ctx))
+		staging.AssertExpectations(t)
+		prod.AssertExpectations(t)
+	})
+
+	t.Run("stop at error", func(t *testing.T) {
+		staging := new(mocks.Client)
+		staging.On("Apply", ctx).Return(errors.New("mock error")).Once()
+		prod := new(mocks.Client)
+
+		envs, err := NewEnvironments([]Environment{
+			{Name: "staging", Client: staging},
+			{Name: "prod", Client: prod},
+		})
+		require.NoError(t, err)
+
+		err = envs.Apply(ctx)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `environment "staging"`)
+		staging.AssertExpectations(t)
+		prod.AssertNotCalled(t, "Apply", mock.Anything)
+	})
+}
+
+func TestEnvironments_Plan(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	cases := []struct {
+		name            string
+		stagingChanges  bool
+		prodChanges     bool
+		expectedChanges bool
+	}{
+		{"no changes", false, false, false},
+		{"staging changes", true, false, true},
+		{"prod changes", false, true, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			staging := new(mocks.Client)
+			staging.On("Plan", ctx).Return(tc.stagingChanges, nil).Once()
+			prod := new(mocks.Client)
+			prod.On("Plan", ctx).Return(tc.prodChanges, nil).Once()
+
+			envs, err := NewEnvironments([]Environment{
+				{Name: "staging", Client: staging},
+				{Name: "prod", Client: prod},
+			})
+			require.NoError(t, err)
+
+			changes, err := envs.Plan(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedChanges, changes)
+		})
+	}
+}
+
+func TestEnvironments_SavePlan(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	staging := new(mocks.Client)
+	staging.On("SavePlan", ctx, "plan.tfplan.staging").
+		Return([]byte(`{"a":1}`), nil).Once()
+	staging.On("ApplyPlan", ctx, "plan.tfplan.staging").Return(nil).Once()
+	prod := new(mocks.Client)
+	prod.On("SavePlan", ctx, "plan.tfplan.prod").
+		Return([]byte(`{"b":2}`), nil).Once()
+	prod.On("ApplyPlan", ctx, "plan.tfplan.prod").Return(nil).Once()
+
+	envs, err := NewEnvironments([]Environment{
+		{Name: "staging", Client: staging},
+		{Name: "prod", Client: prod},
+	})
+	require.NoError(t, err)
+
+	plan, err := envs.SavePlan(ctx, "plan.tfplan")
+	require.NoError(t, err)
+	var plans map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(plan, &plans))
+	assert.JSONEq(t, `{"a":1}`, string(plans["staging"]))
+	assert.JSONEq(t, `{"b":2}`, string(plans["prod"]))
+
+	assert.NoError(t, envs.ApplyPlan(ctx, "plan.tfplan"))
+	staging.AssertExpectations(t)
+	prod.AssertExpectations(t)
+}
+
+func TestEnvironments_Outputs(t *testing.T) {
+	t.Parallel()
+
+	envs, err := NewEnvironments([]Environment{{Name: "prod", Client: new(mocks.Client)}})
+	require.NoError(t, err)
+
+	_, err = envs.Outputs(context.Background())
+	assert.ErrorIs(t, err, ErrEnvironmentOutputs)
+}
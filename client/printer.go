@@ -5,6 +5,7 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -67,6 +68,16 @@ func (p *Printer) SetStdout(io.Writer) {
 	p.logger.Info("setting standard out for workspace")
 }
 
+// SetProgress logs out 'set progress'
+func (p *Printer) SetProgress(io.Writer) {
+	p.logger.Info("setting progress for workspace")
+}
+
+// SetTargets logs out 'setting targets'
+func (p *Printer) SetTargets(targets []string) {
+	p.logger.Info("setting targets for workspace", "targets", targets)
+}
+
 // Init logs out 'init'
 func (p *Printer) Init(context.Context) error {
 	p.logger.Info("initing workspace")
@@ -85,12 +96,30 @@ func (p *Printer) Plan(context.Context) (bool, error) {
 	return true, nil
 }
 
+// SavePlan logs out 'saving plan' and returns an empty JSON plan
+func (p *Printer) SavePlan(_ context.Context, planFile string) ([]byte, error) {
+	p.logger.Info("planning workspace and saving plan", "plan_file", planFile)
+	return []byte("{}"), nil
+}
+
+// ApplyPlan logs out 'applying plan'
+func (p *Printer) ApplyPlan(_ context.Context, planFile string) error {
+	p.logger.Info("applying saved plan for workspace", "plan_file", planFile)
+	return nil
+}
+
 // Validate logs out 'validate'
 func (p *Printer) Validate(context.Context) error {
 	p.logger.Info("validating workspace")
 	return nil
 }
 
+// Outputs logs out 'outputs' and returns no outputs
+func (p *Printer) Outputs(context.Context) (map[string]json.RawMessage, error) {
+	p.logger.Info("reading outputs for workspace")
+	return map[string]json.RawMessage{}, nil
+}
+
 // GoString defines the printable version of this struct.
 func (p *Printer) GoString() string {
 	if p == nil {
@@ -117,6 +117,20 @@ func TestPrinterSetStdout(t *testing.T) {
 	assert.Contains(t, buf.String(), "standard out")
 }
 
+func TestPrinterSetTargets(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	p, err := DefaultTestPrinter(&buf)
+	assert.NoError(t, err)
+
+	p.SetTargets([]string{"module.web"})
+	assert.NotEmpty(t, buf.String())
+	assert.Contains(t, buf.String(), "client.printer")
+	assert.Contains(t, buf.String(), "targets")
+	assert.Contains(t, buf.String(), "module.web")
+}
+
 func TestPrinterInit(t *testing.T) {
 	t.Parallel()
 
@@ -210,6 +224,40 @@ func TestPrinterPlan(t *testing.T) {
 	assert.Contains(t, buf.String(), "plan")
 }
 
+func TestPrinterSavePlan(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	p, err := DefaultTestPrinter(&buf)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	planJSON, err := p.SavePlan(ctx, "tfplan")
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", string(planJSON))
+	assert.Contains(t, buf.String(), "client.printer")
+	assert.Contains(t, buf.String(), "saving plan")
+
+	buf.Reset()
+	err = p.ApplyPlan(ctx, "tfplan")
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "applying saved plan")
+}
+
+func TestPrinterOutputs(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	p, err := DefaultTestPrinter(&buf)
+	assert.NoError(t, err)
+
+	outputs, err := p.Outputs(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, outputs)
+	assert.Contains(t, buf.String(), "client.printer")
+	assert.Contains(t, buf.String(), "reading outputs")
+}
+
 func TestPrinterValidate(t *testing.T) {
 	t.Parallel()
 
@@ -5,13 +5,16 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/hashicorp/consul-terraform-sync/logging"
 	"github.com/hashicorp/terraform-exec/tfexec"
@@ -22,6 +25,7 @@ var (
 
 	wsFailedToSelectRegexp = regexp.MustCompile(`Failed to select workspace`)
 	wsDoesNotExistRegexp   = regexp.MustCompile(`workspace ".*" does not exist`)
+	wsAlreadyExistsRegexp  = regexp.MustCompile(`Workspace ".*" already exists`)
 )
 
 const (
@@ -34,7 +38,23 @@ type TerraformCLI struct {
 	tf         terraformExec
 	workingDir string
 	workspace  string
+	targets    []string
+	sandbox    *sandbox
 	logger     logging.Logger
+
+	// dataDir, varFiles, and backendConfig configure the client for an
+	// environment of a task
+	dataDir       string
+	varFiles      []string
+	backendConfig []string
+
+	// initLock is held while initializing the workspace
+	initLock sync.Locker
+
+	// stdout is the standard out of Terraform. progress is the writer of the
+	// machine-readable UI of plans and applies, which is unset by default.
+	stdout   io.Writer
+	progress io.Writer
 }
 
 // TerraformCLIConfig configures the Terraform client
@@ -44,6 +64,29 @@ type TerraformCLIConfig struct {
 	ExecPath   string
 	WorkingDir string
 	Workspace  string
+
+	// Sandbox configures the client to execute Terraform with reduced
+	// privileges. Terraform is executed with the privileges of CTS if nil.
+	Sandbox *SandboxConfig
+
+	// DataDir is the directory for Terraform to store the backend
+	// configuration and selected workspace, set with TF_DATA_DIR. Defaults
+	// to the .terraform directory of the working directory.
+	DataDir string
+
+	// VarFiles are the variable files passed to plans and applies with the
+	// `-var-file` option, in addition to the variable files that Terraform
+	// loads automatically
+	VarFiles []string
+
+	// BackendConfig are the `key=value` backend arguments passed to init
+	// with the `-backend-config` option
+	BackendConfig []string
+
+	// InitLock is held while initializing the workspace, e.g. to install
+	// providers into a plugin cache that is shared with other clients one
+	// client at a time. Optional.
+	InitLock sync.Locker
 }
 
 // NewTerraformCLI creates a terraform-exec client and configures and
@@ -54,7 +97,22 @@ func NewTerraformCLI(config *TerraformCLIConfig) (*TerraformCLI, error) {
 	}
 
 	tfPath := filepath.Join(config.ExecPath, "terraform")
-	tf, err := tfexec.NewTerraform(config.WorkingDir, tfPath)
+
+	var sb *sandbox
+	execPath := tfPath
+	if config.Sandbox != nil {
+		var err error
+		sb, err = newSandbox(config.Sandbox, tfPath, config.WorkingDir)
+		if err != nil {
+			return nil, err
+		}
+		execPath, err = sb.execPath(tfPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tf, err := tfexec.NewTerraform(config.WorkingDir, execPath)
 	if err != nil {
 		return nil, err
 	}
@@ -64,11 +122,13 @@ func NewTerraformCLI(config *TerraformCLIConfig) (*TerraformCLI, error) {
 	// purposes. It may be difficult to work with log aggregators that expect
 	// uniform log format.
 	logger := logging.Global().Named(loggingSystemName).Named(tcliSubsystemName)
+	var stdout io.Writer
 	if config.Log {
 		logger.Info("Terraform logging is set, Terraform logs will output with Consul-Terraform-Sync logs")
 		lg := log.New(log.Writer(), "", log.Flags())
 		tf.SetLogger(lg)
-		tf.SetStdout(log.Writer())
+		stdout = log.Writer()
+		tf.SetStdout(stdout)
 		tf.SetStderr(log.Writer())
 	} else {
 		logger.Info("Terraform output is muted")
@@ -89,38 +149,104 @@ func NewTerraformCLI(config *TerraformCLIConfig) (*TerraformCLI, error) {
 		tf:         tf,
 		workingDir: config.WorkingDir,
 		workspace:  config.Workspace,
+		sandbox:    sb,
 		logger:     logger,
+		stdout:     stdout,
+
+		dataDir:       config.DataDir,
+		varFiles:      config.VarFiles,
+		backendConfig: config.BackendConfig,
+		initLock:      config.InitLock,
+	}
+
+	if sb != nil {
+		// The environment of CTS is not inherited by Terraform in the sandbox
+		if err := client.SetEnv(nil); err != nil {
+			return nil, err
+		}
+		logger.Info("executing Terraform in sandbox", "uid", config.Sandbox.UID,
+			"gid", config.Sandbox.GID)
+	} else if config.DataDir != "" {
+		if err := client.SetEnv(nil); err != nil {
+			return nil, err
+		}
 	}
 	logger.Trace("created Terraform CLI client", "client", client.GoString())
 
 	return client, nil
 }
 
-// SetEnv sets the environment for the Terraform workspace
+// SetEnv sets the environment for the Terraform workspace. When Terraform is
+// executed in a sandbox, only the allowlisted environment variables of CTS are
+// added to the environment. The data directory of the client is always set.
 func (t *TerraformCLI) SetEnv(env map[string]string) error {
+	if t.sandbox != nil {
+		env = t.sandbox.env(env)
+	}
+	if t.dataDir != "" {
+		// a nil environment inherits the os environment, which is kept when
+		// setting the data directory
+		e := make(map[string]string, len(env)+1)
+		if env == nil {
+			for _, kv := range os.Environ() {
+				if k, v, ok := strings.Cut(kv, "="); ok {
+					e[k] = v
+				}
+			}
+		}
+		for k, v := range env {
+			e[k] = v
+		}
+		e["TF_DATA_DIR"] = t.dataDir
+		env = e
+	}
 	return t.tf.SetEnv(env)
 }
 
 // SetStdout sets the standard out for Terraform
 func (t *TerraformCLI) SetStdout(w io.Writer) {
+	t.stdout = w
 	t.tf.SetStdout(w)
 }
 
+// SetProgress sets the writer for the machine-readable UI of plans and
+// applies. While set, Terraform is executed with the `-json` option and the
+// human-readable message of each line of the UI is written to the standard
+// out. Nil executes Terraform with the human-readable UI.
+func (t *TerraformCLI) SetProgress(w io.Writer) {
+	t.progress = w
+}
+
+// SetTargets sets the resource addresses to target with the `-target` option
+// for plans and applies
+func (t *TerraformCLI) SetTargets(targets []string) {
+	t.targets = targets
+}
+
 // Init initializes by executing the cli command `terraform init` and
 // `terraform workspace new <name>`
 func (t *TerraformCLI) Init(ctx context.Context) error {
 	var wsCreated bool
 
+	if t.initLock != nil {
+		t.initLock.Lock()
+		defer t.initLock.Unlock()
+	}
+
+	opts := make([]tfexec.InitOption, 0, len(t.backendConfig))
+	for _, bc := range t.backendConfig {
+		opts = append(opts, tfexec.BackendConfig(bc))
+	}
+
 	// This is special handling for when the workspace has been detected in
 	// .terraform/environment with a non-existing state. This case is common
 	// when the state for the workspace has been deleted.
 	// https://github.com/hashicorp/terraform/issues/21393
 TF_INIT_AGAIN:
-	if err := t.tf.Init(ctx); err != nil {
-		var wsErr *tfexec.ErrNoWorkspace
+	if err := t.tf.Init(ctx, opts...); err != nil {
 		matchedFailedToSelect := wsFailedToSelectRegexp.MatchString(err.Error())
 		matchedDoesNotExist := wsDoesNotExistRegexp.MatchString(err.Error())
-		if matchedFailedToSelect || matchedDoesNotExist || errors.As(err, &wsErr) {
+		if matchedFailedToSelect || matchedDoesNotExist {
 			t.logger.Info("workspace was detected without state, " +
 				"creating new workspace and attempting Terraform init again")
 			if err := t.tf.WorkspaceNew(ctx, t.workspace); err != nil {
@@ -139,8 +265,7 @@ TF_INIT_AGAIN:
 	if !wsCreated {
 		err := t.tf.WorkspaceNew(ctx, t.workspace)
 		if err != nil {
-			var wsErr *tfexec.ErrWorkspaceExists
-			if !errors.As(err, &wsErr) {
+			if !wsAlreadyExistsRegexp.MatchString(err.Error()) {
 				logws.Error("unable to create workspace", "error", err)
 				return err
 			}
@@ -160,12 +285,110 @@ TF_INIT_AGAIN:
 
 // Apply executes the cli command `terraform apply` for a given workspace
 func (t *TerraformCLI) Apply(ctx context.Context) error {
-	return t.tf.Apply(ctx)
+	opts := make([]tfexec.ApplyOption, 0, len(t.varFiles)+len(t.targets))
+	for _, vf := range t.varFiles {
+		opts = append(opts, tfexec.VarFile(vf))
+	}
+	for _, target := range t.targets {
+		opts = append(opts, tfexec.Target(target))
+	}
+	return t.apply(ctx, opts...)
 }
 
 // Plan executes the cli command `terraform plan` for a given workspace
 func (t *TerraformCLI) Plan(ctx context.Context) (bool, error) {
-	return t.tf.Plan(ctx)
+	return t.plan(ctx, t.planOptions()...)
+}
+
+// SavePlan executes the cli command `terraform plan -out=<planFile>` for a
+// given workspace and returns the JSON representation of the saved plan
+func (t *TerraformCLI) SavePlan(ctx context.Context, planFile string) ([]byte, error) {
+	opts := append(t.planOptions(), tfexec.Out(planFile))
+	if _, err := t.plan(ctx, opts...); err != nil {
+		return nil, err
+	}
+
+	plan, err := t.tf.ShowPlanFile(ctx, planFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(plan)
+}
+
+// ApplyPlan executes the cli command `terraform apply <planFile>` for a given
+// workspace
+func (t *TerraformCLI) ApplyPlan(ctx context.Context, planFile string) error {
+	return t.apply(ctx, tfexec.DirOrPlan(planFile))
+}
+
+// apply executes `terraform apply`, with the `-json` option if the progress
+// writer is set. Versions of Terraform without the machine-readable UI
+// (< 0.15.3) fall back to the human-readable UI, without progress.
+func (t *TerraformCLI) apply(ctx context.Context, opts ...tfexec.ApplyOption) error {
+	if t.progress != nil {
+		// The JSON variants of terraform-exec replace the standard out
+		ui := newJSONUI(t.progress, t.stdout)
+		err := t.tf.ApplyJSON(ctx, ui, opts...)
+		ui.Flush()
+		t.tf.SetStdout(t.stdout)
+
+		var versionErr *tfexec.ErrVersionMismatch
+		if !errors.As(err, &versionErr) {
+			return err
+		}
+		t.logger.Debug("Terraform version does not support the machine-readable "+
+			"UI, progress is not tracked", "error", err)
+	}
+	return t.tf.Apply(ctx, opts...)
+}
+
+// plan executes `terraform plan`, with the `-json` option if the progress
+// writer is set. Versions of Terraform without the machine-readable UI fall
+// back to the human-readable UI, without progress.
+func (t *TerraformCLI) plan(ctx context.Context, opts ...tfexec.PlanOption) (bool, error) {
+	if t.progress != nil {
+		ui := newJSONUI(t.progress, t.stdout)
+		changes, err := t.tf.PlanJSON(ctx, ui, opts...)
+		ui.Flush()
+		t.tf.SetStdout(t.stdout)
+
+		var versionErr *tfexec.ErrVersionMismatch
+		if !errors.As(err, &versionErr) {
+			return changes, err
+		}
+		t.logger.Debug("Terraform version does not support the machine-readable "+
+			"UI, progress is not tracked", "error", err)
+	}
+	return t.tf.Plan(ctx, opts...)
+}
+
+// Outputs executes the cli command `terraform output -json` for a given
+// workspace and returns the value of each output
+func (t *TerraformCLI) Outputs(ctx context.Context) (map[string]json.RawMessage, error) {
+	outputs, err := t.tf.Output(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]json.RawMessage, len(outputs))
+	for name, output := range outputs {
+		values[name] = output.Value
+	}
+	return values, nil
+}
+
+// planOptions returns the plan options for the variable files and targets of
+// the client
+func (t *TerraformCLI) planOptions() []tfexec.PlanOption {
+	opts := make([]tfexec.PlanOption, 0, len(t.varFiles)+len(t.targets))
+	for _, vf := range t.varFiles {
+		opts = append(opts, tfexec.VarFile(vf))
+	}
+	for _, target := range t.targets {
+		opts = append(opts, tfexec.Target(target))
+	}
+	return opts
 }
 
 // Validate verifies the generated configuration files
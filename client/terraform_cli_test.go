@@ -4,9 +4,17 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/hashicorp/consul-terraform-sync/logging"
@@ -81,6 +89,34 @@ func TestNewTerraformCLI(t *testing.T) {
 				Workspace:  "my-workspace",
 			},
 		},
+		{
+			"sandbox",
+			false,
+			&TerraformCLIConfig{
+				ExecPath:   "path/to/tf",
+				WorkingDir: "./",
+				Workspace:  "my-workspace",
+				Sandbox: &SandboxConfig{
+					UID:          -1,
+					GID:          -1,
+					EnvAllowlist: []string{"PATH"},
+				},
+			},
+		},
+		{
+			"sandbox error",
+			true,
+			&TerraformCLIConfig{
+				ExecPath:   "path/to/tf",
+				WorkingDir: "./",
+				Workspace:  "my-workspace",
+				Sandbox: &SandboxConfig{
+					UID:            -1,
+					GID:            -1,
+					SeccompProfile: "does/not/exist.json",
+				},
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -134,7 +170,7 @@ func TestTerraformCLIInit(t *testing.T) {
 			false,
 			&TerraformCLIConfig{},
 			nil,
-			&tfexec.ErrWorkspaceExists{Name: "workspace-name"},
+			errors.New(`Workspace "workspace-name" already exists`),
 		},
 	}
 
@@ -216,6 +252,121 @@ Error: Currently selected workspace "some-task" does not exist
 	}
 }
 
+// fakeTerraformScript is a Terraform binary that writes the stderr of
+// Terraform 1.3 for the workspace errors that Init handles. terraform-exec
+// v0.18 no longer parses the errors into types, so Init matches the stderr.
+const fakeTerraformScript = `#!/bin/sh
+echo "$@" >> calls
+case "$1 $2" in
+"version -json")
+	echo '{"terraform_version":"1.3.7","platform":"linux_amd64","provider_selections":{}}'
+	;;
+"init "*)
+	if [ -n "$INIT_WORKSPACE_MISSING" ] && [ ! -f ws_created ]; then
+		printf '\nError: Currently selected workspace "%s" does not exist\n\n' "$INIT_WORKSPACE_MISSING" >&2
+		exit 1
+	fi
+	;;
+"workspace new")
+	if [ -n "$WORKSPACE_NEW_STDERR" ]; then
+		printf '%s\n' "$WORKSPACE_NEW_STDERR" >&2
+		exit 1
+	fi
+	touch ws_created
+	;;
+esac
+`
+
+func TestTerraformCLIInit_TerraformWorkspaceErrors(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("fake Terraform binary is a shell script")
+	}
+
+	cases := []struct {
+		name        string
+		env         map[string]string
+		expectError bool
+		expected    []string
+	}{
+		{
+			"workspace created",
+			nil,
+			false,
+			[]string{"init", "workspace new", "workspace select"},
+		},
+		{
+			"workspace already exists",
+			map[string]string{
+				"WORKSPACE_NEW_STDERR": `Workspace "task" already exists`,
+			},
+			false,
+			[]string{"init", "workspace new", "workspace select"},
+		},
+		{
+			"workspace new error",
+			map[string]string{
+				"WORKSPACE_NEW_STDERR": "\nError: Failed to get configured named " +
+					"states: querying Consul failed: Unexpected response code: 403",
+			},
+			true,
+			[]string{"init", "workspace new"},
+		},
+		{
+			"selected workspace does not exist",
+			map[string]string{"INIT_WORKSPACE_MISSING": "task"},
+			false,
+			[]string{"init", "workspace new", "init", "workspace select"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			execPath := filepath.Join(dir, "terraform")
+			require.NoError(t, os.WriteFile(execPath, []byte(fakeTerraformScript), 0755))
+
+			tf, err := tfexec.NewTerraform(dir, execPath)
+			require.NoError(t, err)
+			env := map[string]string{"PATH": os.Getenv("PATH")}
+			for k, v := range tc.env {
+				env[k] = v
+			}
+			require.NoError(t, tf.SetEnv(env))
+
+			client := &TerraformCLI{
+				tf:         tf,
+				workingDir: dir,
+				workspace:  "task",
+				logger:     logging.NewNullLogger(),
+			}
+			err = client.Init(context.Background())
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			content, err := os.ReadFile(filepath.Join(dir, "calls"))
+			require.NoError(t, err)
+			var calls []string
+			for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+				args := strings.Fields(line)
+				if args[0] == "version" {
+					continue
+				}
+				if args[0] == "workspace" {
+					calls = append(calls, args[0]+" "+args[1])
+					continue
+				}
+				calls = append(calls, args[0])
+			}
+			assert.Equal(t, tc.expected, calls)
+		})
+	}
+}
+
 func TestTerraformCLIApply(t *testing.T) {
 	t.Parallel()
 
@@ -278,6 +429,261 @@ func TestTerraformCLIPlan(t *testing.T) {
 	}
 }
 
+func TestTerraformCLISavePlan(t *testing.T) {
+	t.Parallel()
+
+	t.Run("happy path", func(t *testing.T) {
+		m := new(mocks.TerraformExec)
+		m.On("Plan", mock.Anything, tfexec.Out("tfplan")).Return(true, nil).Once()
+		m.On("ShowPlanFile", mock.Anything, "tfplan").Return(&tfjson.Plan{
+			FormatVersion: "1.1",
+		}, nil).Once()
+
+		client := NewTestTerraformCLI(&TerraformCLIConfig{}, m)
+		planJSON, err := client.SavePlan(context.Background(), "tfplan")
+		require.NoError(t, err)
+		assert.Contains(t, string(planJSON), `"format_version":"1.1"`)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("plan error", func(t *testing.T) {
+		m := new(mocks.TerraformExec)
+		m.On("Plan", mock.Anything, mock.Anything).Return(false, errors.New("error"))
+
+		client := NewTestTerraformCLI(&TerraformCLIConfig{}, m)
+		_, err := client.SavePlan(context.Background(), "tfplan")
+		assert.Error(t, err)
+		m.AssertNotCalled(t, "ShowPlanFile", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTerraformCLISetTargets(t *testing.T) {
+	t.Parallel()
+
+	targets := []string{"module.web", "module.api"}
+
+	m := new(mocks.TerraformExec)
+	m.On("Plan", mock.Anything, tfexec.Target("module.web"),
+		tfexec.Target("module.api")).Return(true, nil).Once()
+	m.On("Apply", mock.Anything, tfexec.Target("module.web"),
+		tfexec.Target("module.api")).Return(nil).Once()
+	m.On("Apply", mock.Anything).Return(nil).Once()
+
+	client := NewTestTerraformCLI(&TerraformCLIConfig{}, m)
+	ctx := context.Background()
+
+	client.SetTargets(targets)
+	_, err := client.Plan(ctx)
+	require.NoError(t, err)
+	err = client.Apply(ctx)
+	require.NoError(t, err)
+
+	// reset targets
+	client.SetTargets(nil)
+	err = client.Apply(ctx)
+	require.NoError(t, err)
+
+	m.AssertExpectations(t)
+}
+
+func TestTerraformCLISetEnv_Sandbox(t *testing.T) {
+	t.Setenv("CTS_TEST_ALLOWED", "allowed")
+	t.Setenv("CTS_TEST_DENIED", "denied")
+
+	m := new(mocks.TerraformExec)
+	m.On("SetEnv", map[string]string{
+		"CTS_TEST_ALLOWED": "allowed",
+		"TASK":             "task",
+	}).Return(nil).Once()
+
+	client := NewTestTerraformCLI(&TerraformCLIConfig{}, m)
+	client.sandbox = &sandbox{envAllowlist: []string{"CTS_TEST_ALLOWED"}}
+
+	err := client.SetEnv(map[string]string{"TASK": "task"})
+	require.NoError(t, err)
+	m.AssertExpectations(t)
+}
+
+func TestTerraformCLI_Environment(t *testing.T) {
+	t.Parallel()
+
+	m := new(mocks.TerraformExec)
+	m.On("SetEnv", map[string]string{
+		"TASK":        "task",
+		"TF_DATA_DIR": ".terraform-prod",
+	}).Return(nil).Once()
+	m.On("Init", mock.Anything, tfexec.BackendConfig("path=cts/prod")).
+		Return(nil).Once()
+	m.On("WorkspaceNew", mock.Anything, "test-workspace").Return(nil).Once()
+	m.On("WorkspaceSelect", mock.Anything, "test-workspace").Return(nil).Once()
+	m.On("Plan", mock.Anything, tfexec.VarFile("environment.prod.tfvars")).
+		Return(true, nil).Once()
+	m.On("Apply", mock.Anything, tfexec.VarFile("environment.prod.tfvars")).
+		Return(nil).Once()
+
+	client := NewTestTerraformCLI(&TerraformCLIConfig{}, m)
+	client.dataDir = ".terraform-prod"
+	client.varFiles = []string{"environment.prod.tfvars"}
+	client.backendConfig = []string{"path=cts/prod"}
+	ctx := context.Background()
+
+	require.NoError(t, client.SetEnv(map[string]string{"TASK": "task"}))
+	require.NoError(t, client.Init(ctx))
+	_, err := client.Plan(ctx)
+	require.NoError(t, err)
+	require.NoError(t, client.Apply(ctx))
+	m.AssertExpectations(t)
+}
+
+func TestTerraformCLIInit_Lock(t *testing.T) {
+	t.Parallel()
+
+	lock := &sync.Mutex{}
+	m := new(mocks.TerraformExec)
+	m.On("Init", mock.Anything).Run(func(mock.Arguments) {
+		// the lock is held during init
+		assert.False(t, lock.TryLock())
+	}).Return(nil).Once()
+	m.On("WorkspaceNew", mock.Anything, mock.Anything).Return(nil)
+	m.On("WorkspaceSelect", mock.Anything, mock.Anything).Return(nil)
+
+	client := NewTestTerraformCLI(&TerraformCLIConfig{}, m)
+	client.initLock = lock
+	require.NoError(t, client.Init(context.Background()))
+	m.AssertExpectations(t)
+
+	// the lock is released after init
+	assert.True(t, lock.TryLock())
+}
+
+func TestTerraformCLIApplyPlan(t *testing.T) {
+	t.Parallel()
+
+	m := new(mocks.TerraformExec)
+	m.On("Apply", mock.Anything, tfexec.DirOrPlan("tfplan")).Return(nil).Once()
+
+	client := NewTestTerraformCLI(&TerraformCLIConfig{}, m)
+	err := client.ApplyPlan(context.Background(), "tfplan")
+	assert.NoError(t, err)
+	m.AssertExpectations(t)
+}
+
+func TestTerraformCLISetProgress(t *testing.T) {
+	t.Parallel()
+
+	line := `{"@message":"local_file.a: Creating...","type":"apply_start"}` + "\n"
+
+	m := new(mocks.TerraformExec)
+	m.On("SetStdout", mock.Anything)
+	m.On("PlanJSON", mock.Anything, mock.Anything, tfexec.Out("tfplan")).
+		Return(true, nil).Once()
+	m.On("ShowPlanFile", mock.Anything, "tfplan").Return(&tfjson.Plan{}, nil).Once()
+	m.On("ApplyJSON", mock.Anything, mock.Anything, tfexec.DirOrPlan("tfplan")).
+		Run(func(args mock.Arguments) {
+			fmt.Fprint(args.Get(1).(io.Writer), line)
+		}).Return(nil).Once()
+	m.On("Apply", mock.Anything).Return(nil).Once()
+
+	var stdout, progress bytes.Buffer
+	client := NewTestTerraformCLI(&TerraformCLIConfig{}, m)
+	client.SetStdout(&stdout)
+	client.SetProgress(&progress)
+
+	ctx := context.Background()
+	_, err := client.SavePlan(ctx, "tfplan")
+	require.NoError(t, err)
+	require.NoError(t, client.ApplyPlan(ctx, "tfplan"))
+
+	// The machine-readable UI is streamed to the progress writer and the
+	// standard out is restored after each command
+	assert.Equal(t, line, progress.String())
+	assert.Equal(t, "local_file.a: Creating...\n", stdout.String())
+	m.AssertCalled(t, "SetStdout", &stdout)
+
+	// Unsetting the progress writer executes Terraform without -json
+	client.SetProgress(nil)
+	require.NoError(t, client.Apply(ctx))
+	m.AssertExpectations(t)
+}
+
+func TestTerraformCLISetProgress_VersionMismatch(t *testing.T) {
+	t.Parallel()
+
+	// Terraform < 0.15.3 does not support the machine-readable UI
+	versionErr := fmt.Errorf("terraform apply -json was added in 0.15.3: %w",
+		&tfexec.ErrVersionMismatch{MinInclusive: "0.15.3", Actual: "0.14.0"})
+
+	m := new(mocks.TerraformExec)
+	m.On("SetStdout", mock.Anything)
+	m.On("ApplyJSON", mock.Anything, mock.Anything).Return(versionErr).Once()
+	m.On("Apply", mock.Anything).Return(nil).Once()
+
+	client := NewTestTerraformCLI(&TerraformCLIConfig{}, m)
+	client.SetProgress(&bytes.Buffer{})
+	require.NoError(t, client.Apply(context.Background()))
+	m.AssertExpectations(t)
+}
+
+func TestJSONUI(t *testing.T) {
+	t.Parallel()
+
+	var stdout, progress bytes.Buffer
+	ui := newJSONUI(&progress, &stdout)
+
+	first := `{"@message":"Terraform 1.2.2","type":"version"}`
+	last := `{"@message":"Apply complete! Resources: 1 added, 0 changed, 0 destroyed.","type":"change_summary"}`
+	output := first + "\n" + "Error: not JSON\n" + last
+	for _, b := range []string{output[:10], output[10:60], output[60:]} {
+		n, err := ui.Write([]byte(b))
+		require.NoError(t, err)
+		assert.Equal(t, len(b), n)
+	}
+	assert.Equal(t, "Terraform 1.2.2\nError: not JSON\n", stdout.String())
+
+	// The incomplete last line is written when flushed
+	ui.Flush()
+	assert.Equal(t, first+"\nError: not JSON\n"+last+"\n", progress.String())
+	assert.Equal(t, "Terraform 1.2.2\nError: not JSON\n"+
+		"Apply complete! Resources: 1 added, 0 changed, 0 destroyed.\n", stdout.String())
+
+	// The standard out is optional
+	progress.Reset()
+	ui = newJSONUI(&progress, nil)
+	_, err := ui.Write([]byte(first + "\n"))
+	require.NoError(t, err)
+	assert.Equal(t, first+"\n", progress.String())
+}
+
+func TestTerraformCLIOutputs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("happy path", func(t *testing.T) {
+		m := new(mocks.TerraformExec)
+		m.On("Output", mock.Anything).Return(map[string]tfexec.OutputMeta{
+			"members_count": {Value: json.RawMessage(`2`)},
+			"names":         {Value: json.RawMessage(`["a","b"]`)},
+		}, nil).Once()
+
+		client := NewTestTerraformCLI(&TerraformCLIConfig{}, m)
+		outputs, err := client.Outputs(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, map[string]json.RawMessage{
+			"members_count": json.RawMessage(`2`),
+			"names":         json.RawMessage(`["a","b"]`),
+		}, outputs)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		m := new(mocks.TerraformExec)
+		m.On("Output", mock.Anything).Return(nil, errors.New("error")).Once()
+
+		client := NewTestTerraformCLI(&TerraformCLIConfig{}, m)
+		_, err := client.Outputs(context.Background())
+		assert.Error(t, err)
+	})
+}
+
 func TestTerraformCLIValidate(t *testing.T) {
 	t.Parallel()
 
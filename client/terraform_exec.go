@@ -22,7 +22,11 @@ type terraformExec interface {
 	SetStdout(w io.Writer)
 	Init(ctx context.Context, opts ...tfexec.InitOption) error
 	Apply(ctx context.Context, opts ...tfexec.ApplyOption) error
+	ApplyJSON(ctx context.Context, w io.Writer, opts ...tfexec.ApplyOption) error
 	Plan(ctx context.Context, opts ...tfexec.PlanOption) (bool, error)
+	PlanJSON(ctx context.Context, w io.Writer, opts ...tfexec.PlanOption) (bool, error)
+	Output(ctx context.Context, opts ...tfexec.OutputOption) (map[string]tfexec.OutputMeta, error)
+	ShowPlanFile(ctx context.Context, planPath string, opts ...tfexec.ShowOption) (*tfjson.Plan, error)
 	WorkspaceNew(ctx context.Context, workspace string, opts ...tfexec.WorkspaceNewCmdOption) error
 	WorkspaceSelect(ctx context.Context, workspace string) error
 	Validate(ctx context.Context) (*tfjson.ValidateOutput, error)
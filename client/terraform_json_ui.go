@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// jsonUI splits the machine-readable UI of Terraform, which has one JSON
+// message per line. Each line is written as is to the progress writer and the
+// human-readable message of the line is written to the standard out, so that
+// the output of Terraform remains readable for logs.
+type jsonUI struct {
+	progress io.Writer
+	stdout   io.Writer
+
+	// partial is the output of an incomplete line
+	partial []byte
+}
+
+// newJSONUI returns a writer for the machine-readable UI of Terraform. The
+// standard out is optional.
+func newJSONUI(progress, stdout io.Writer) *jsonUI {
+	return &jsonUI{
+		progress: progress,
+		stdout:   stdout,
+	}
+}
+
+// Write consumes the machine-readable UI line by line. It satisfies io.Writer
+// and always consumes the full output.
+func (u *jsonUI) Write(b []byte) (int, error) {
+	u.partial = append(u.partial, b...)
+	for {
+		i := bytes.IndexByte(u.partial, '\n')
+		if i < 0 {
+			break
+		}
+		u.writeLine(u.partial[:i+1])
+		u.partial = u.partial[i+1:]
+	}
+	return len(b), nil
+}
+
+// Flush writes the output of an incomplete line
+func (u *jsonUI) Flush() {
+	if len(u.partial) == 0 {
+		return
+	}
+	u.writeLine(append(u.partial, '\n'))
+	u.partial = nil
+}
+
+func (u *jsonUI) writeLine(line []byte) {
+	u.progress.Write(line)
+	if u.stdout == nil {
+		return
+	}
+
+	var msg struct {
+		Message string `json:"@message"`
+	}
+	if err := json.Unmarshal(line, &msg); err != nil {
+		// Output that is not part of the machine-readable UI is kept as is
+		u.stdout.Write(line)
+		return
+	}
+	fmt.Fprintln(u.stdout, msg.Message)
+}
@@ -0,0 +1,157 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// sandboxEnvVar is the environment variable that is set when CTS executes
+// itself as the wrapper of Terraform for the sandbox. The value is the
+// JSON-encoded sandboxSpec.
+const sandboxEnvVar = "CTS_TERRAFORM_SANDBOX"
+
+// SandboxConfig configures the Terraform CLI client to execute Terraform with
+// reduced privileges
+type SandboxConfig struct {
+	// UID and GID are the user and group IDs to execute Terraform as. -1
+	// keeps the user and group of CTS.
+	UID int
+	GID int
+
+	// EnvAllowlist is the names of the environment variables of CTS that are
+	// passed to Terraform
+	EnvAllowlist []string
+
+	// ConfineWorkingDir restricts Terraform to only write to files within the
+	// working directory and the temporary directory
+	ConfineWorkingDir bool
+
+	// WritablePaths are additional paths that Terraform can write to when
+	// ConfineWorkingDir is enabled, e.g. the provider plugin cache
+	WritablePaths []string
+
+	// SeccompProfile is the path to a seccomp profile that denies system calls
+	// to Terraform
+	SeccompProfile string
+}
+
+// sandboxSpec is the specification for the wrapper to reduce its privileges
+// before executing Terraform
+type sandboxSpec struct {
+	ExecPath      string        `json:"exec_path"`
+	UID           int           `json:"uid"`
+	GID           int           `json:"gid"`
+	WritablePaths []string      `json:"writable_paths,omitempty"`
+	Seccomp       []seccompRule `json:"seccomp,omitempty"`
+}
+
+// seccompRule is a rule of a seccomp filter that returns the action for a
+// system call number
+type seccompRule struct {
+	Syscall uint32 `json:"syscall"`
+	Action  uint32 `json:"action"`
+}
+
+// sandbox executes Terraform with reduced privileges. The environment
+// allowlist is applied by CTS. The other privileges are reduced by a wrapper:
+// CTS executes itself in place of the Terraform binary with the sandbox
+// specification in the environment, and the wrapper reduces its privileges
+// before it executes Terraform.
+type sandbox struct {
+	envAllowlist []string
+
+	// spec is the encoded specification for the wrapper. Empty if the
+	// wrapper is not required.
+	spec string
+}
+
+// newSandbox returns the sandbox for the Terraform binary at the path to
+// execute within the working directory
+func newSandbox(conf *SandboxConfig, tfPath, workingDir string) (*sandbox, error) {
+	s := &sandbox{envAllowlist: conf.EnvAllowlist}
+	if conf.UID == -1 && conf.GID == -1 && !conf.ConfineWorkingDir &&
+		conf.SeccompProfile == "" {
+		return s, nil
+	}
+
+	spec := sandboxSpec{
+		ExecPath: tfPath,
+		UID:      conf.UID,
+		GID:      conf.GID,
+	}
+	if conf.ConfineWorkingDir {
+		spec.WritablePaths = []string{workingDir, os.TempDir(), os.DevNull}
+		spec.WritablePaths = append(spec.WritablePaths, conf.WritablePaths...)
+	}
+	if conf.SeccompProfile != "" {
+		rules, err := loadSeccompProfile(conf.SeccompProfile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load seccomp profile %q: %s",
+				conf.SeccompProfile, err)
+		}
+		spec.Seccomp = rules
+	}
+	if err := checkSandboxSupport(&spec); err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	s.spec = string(b)
+	return s, nil
+}
+
+// execPath returns the path of the binary to execute in place of the
+// Terraform binary at the path
+func (s *sandbox) execPath(tfPath string) (string, error) {
+	if s.spec == "" {
+		return tfPath, nil
+	}
+	return os.Executable()
+}
+
+// env returns the environment for Terraform, which includes the allowlisted
+// environment variables of CTS and the environment variables of the task
+func (s *sandbox) env(taskEnv map[string]string) map[string]string {
+	env := make(map[string]string, len(s.envAllowlist)+len(taskEnv)+1)
+	for _, name := range s.envAllowlist {
+		if v, ok := os.LookupEnv(name); ok {
+			env[name] = v
+		}
+	}
+	for k, v := range taskEnv {
+		env[k] = v
+	}
+	if s.spec != "" {
+		env[sandboxEnvVar] = s.spec
+	}
+	return env
+}
+
+// InTerraformSandbox returns whether CTS was executed as the wrapper of
+// Terraform for the sandbox
+func InTerraformSandbox() bool {
+	_, ok := os.LookupEnv(sandboxEnvVar)
+	return ok
+}
+
+// ExecTerraformSandbox reduces the privileges of the process and executes
+// Terraform with the arguments. It only returns if there is an error.
+func ExecTerraformSandbox(args []string) error {
+	var spec sandboxSpec
+	if err := json.Unmarshal([]byte(os.Getenv(sandboxEnvVar)), &spec); err != nil {
+		return fmt.Errorf("invalid sandbox specification: %s", err)
+	}
+	if err := os.Unsetenv(sandboxEnvVar); err != nil {
+		return err
+	}
+
+	argv := append([]string{spec.ExecPath}, args...)
+	return execSandbox(&spec, argv, os.Environ())
+}
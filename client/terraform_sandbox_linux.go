@@ -0,0 +1,320 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	prSetSeccomp    = 22
+	prSetNoNewPrivs = 38
+
+	// oPath is O_PATH, which is not defined by the syscall package for all
+	// architectures
+	oPath = 0x200000
+)
+
+const (
+	sysLandlockCreateRuleset = 444
+	sysLandlockAddRule       = 445
+	sysLandlockRestrictSelf  = 446
+
+	landlockRulePathBeneath = 1
+
+	landlockAccessFSWriteFile  = 1 << 1
+	landlockAccessFSRemoveDir  = 1 << 4
+	landlockAccessFSRemoveFile = 1 << 5
+	landlockAccessFSMakeChar   = 1 << 6
+	landlockAccessFSMakeDir    = 1 << 7
+	landlockAccessFSMakeReg    = 1 << 8
+	landlockAccessFSMakeSock   = 1 << 9
+	landlockAccessFSMakeFifo   = 1 << 10
+	landlockAccessFSMakeBlock  = 1 << 11
+	landlockAccessFSMakeSym    = 1 << 12
+
+	// landlockAccessFSWrite is the access rights of the first Landlock ABI
+	// to modify the file system. Access rights to read and execute files
+	// are not restricted.
+	landlockAccessFSWrite = landlockAccessFSWriteFile |
+		landlockAccessFSRemoveDir | landlockAccessFSRemoveFile |
+		landlockAccessFSMakeChar | landlockAccessFSMakeDir |
+		landlockAccessFSMakeReg | landlockAccessFSMakeSock |
+		landlockAccessFSMakeFifo | landlockAccessFSMakeBlock |
+		landlockAccessFSMakeSym
+)
+
+type landlockRulesetAttr struct {
+	handledAccessFS uint64
+}
+
+// landlockPathBeneathAttr is packed by the kernel, which only reads the first
+// 12 bytes of the struct
+type landlockPathBeneathAttr struct {
+	allowedAccess uint64
+	parentFd      int32
+}
+
+const (
+	seccompModeFilter = 2
+
+	seccompRetKillProcess = 0x80000000
+	seccompRetKillThread  = 0x00000000
+	seccompRetErrno       = 0x00050000
+	seccompRetLog         = 0x7ffc0000
+	seccompRetAllow       = 0x7fff0000
+
+	// offsets of the system call number and the architecture in the
+	// seccomp_data struct
+	seccompDataNrOffset   = 0
+	seccompDataArchOffset = 4
+
+	// seccompMaxRules limits the rules of a filter to the maximum number of
+	// instructions of a BPF program
+	seccompMaxRules = 2000
+)
+
+// seccompActions are the seccomp actions of profiles that are supported
+var seccompActions = map[string]uint32{
+	"SCMP_ACT_KILL":         seccompRetKillThread,
+	"SCMP_ACT_KILL_THREAD":  seccompRetKillThread,
+	"SCMP_ACT_KILL_PROCESS": seccompRetKillProcess,
+	"SCMP_ACT_ERRNO":        seccompRetErrno,
+	"SCMP_ACT_LOG":          seccompRetLog,
+	"SCMP_ACT_ALLOW":        seccompRetAllow,
+}
+
+// seccompProfile is the subset of the seccomp profile format of container
+// runtimes that is supported by the sandbox. Only profiles that allow system
+// calls by default and deny system calls by name are supported, e.g.
+//
+//	{
+//	  "defaultAction": "SCMP_ACT_ALLOW",
+//	  "syscalls": [
+//	    {"names": ["ptrace", "mount"], "action": "SCMP_ACT_ERRNO"}
+//	  ]
+//	}
+type seccompProfile struct {
+	DefaultAction string `json:"defaultAction"`
+	Syscalls      []struct {
+		Names    []string          `json:"names"`
+		Action   string            `json:"action"`
+		ErrnoRet *uint16           `json:"errnoRet"`
+		Args     []json.RawMessage `json:"args"`
+	} `json:"syscalls"`
+}
+
+// loadSeccompProfile reads the seccomp profile at the path and returns the
+// rules of the filter for the profile
+func loadSeccompProfile(path string) ([]seccompRule, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseSeccompProfile(b)
+}
+
+// parseSeccompProfile returns the rules of the filter for a seccomp profile
+func parseSeccompProfile(b []byte) ([]seccompRule, error) {
+	if len(seccompSyscalls) == 0 {
+		return nil, fmt.Errorf("seccomp profiles are not supported on %s",
+			runtime.GOARCH)
+	}
+
+	var profile seccompProfile
+	if err := json.Unmarshal(b, &profile); err != nil {
+		return nil, err
+	}
+
+	if profile.DefaultAction != "SCMP_ACT_ALLOW" {
+		return nil, fmt.Errorf("unsupported defaultAction %q, only profiles "+
+			"with the default action SCMP_ACT_ALLOW are supported",
+			profile.DefaultAction)
+	}
+
+	var rules []seccompRule
+	seen := make(map[uint32]bool)
+	for _, s := range profile.Syscalls {
+		action, ok := seccompActions[s.Action]
+		if !ok {
+			return nil, fmt.Errorf("unsupported action %q", s.Action)
+		}
+		if len(s.Args) > 0 {
+			return nil, fmt.Errorf("unsupported args for syscalls %v, "+
+				"conditions on arguments are not supported", s.Names)
+		}
+		if action == seccompRetErrno {
+			errno := uint16(syscall.EPERM)
+			if s.ErrnoRet != nil {
+				errno = *s.ErrnoRet
+			}
+			action |= uint32(errno)
+		}
+
+		for _, name := range s.Names {
+			nr, ok := seccompSyscalls[name]
+			if !ok {
+				return nil, fmt.Errorf("unsupported syscall %q", name)
+			}
+			// The first rule for a system call takes precedence
+			if seen[nr] {
+				continue
+			}
+			seen[nr] = true
+			rules = append(rules, seccompRule{Syscall: nr, Action: action})
+		}
+	}
+
+	if len(rules) > seccompMaxRules {
+		return nil, fmt.Errorf("too many syscalls, the maximum is %d",
+			seccompMaxRules)
+	}
+	return rules, nil
+}
+
+// seccompFilter returns the BPF program of the seccomp filter for the rules.
+// System calls of other architectures kill the process, and system calls
+// that do not match a rule are allowed.
+func seccompFilter(rules []seccompRule) []syscall.SockFilter {
+	filter := []syscall.SockFilter{
+		bpfStmt(syscall.BPF_LD|syscall.BPF_W|syscall.BPF_ABS, seccompDataArchOffset),
+		bpfJump(syscall.BPF_JMP|syscall.BPF_JEQ|syscall.BPF_K, seccompAuditArch, 1, 0),
+		bpfStmt(syscall.BPF_RET|syscall.BPF_K, seccompRetKillProcess),
+		bpfStmt(syscall.BPF_LD|syscall.BPF_W|syscall.BPF_ABS, seccompDataNrOffset),
+	}
+	if seccompSyscallLimit > 0 {
+		// Deny the system calls of other ABIs of the architecture, e.g. x32
+		filter = append(filter,
+			bpfJump(syscall.BPF_JMP|syscall.BPF_JGE|syscall.BPF_K, seccompSyscallLimit, 0, 1),
+			bpfStmt(syscall.BPF_RET|syscall.BPF_K, seccompRetKillProcess))
+	}
+	for _, r := range rules {
+		filter = append(filter,
+			bpfJump(syscall.BPF_JMP|syscall.BPF_JEQ|syscall.BPF_K, r.Syscall, 0, 1),
+			bpfStmt(syscall.BPF_RET|syscall.BPF_K, r.Action))
+	}
+	return append(filter, bpfStmt(syscall.BPF_RET|syscall.BPF_K, seccompRetAllow))
+}
+
+func bpfStmt(code uint16, k uint32) syscall.SockFilter {
+	return syscall.SockFilter{Code: code, K: k}
+}
+
+func bpfJump(code uint16, k uint32, jt, jf uint8) syscall.SockFilter {
+	return syscall.SockFilter{Code: code, Jt: jt, Jf: jf, K: k}
+}
+
+// checkSandboxSupport returns an error if the sandbox specification is not
+// supported on the platform
+func checkSandboxSupport(*sandboxSpec) error {
+	return nil
+}
+
+// execSandbox reduces the privileges of the process and executes Terraform
+func execSandbox(spec *sandboxSpec, argv, env []string) error {
+	// The Landlock ruleset and the seccomp filter are applied to the thread,
+	// and are inherited by Terraform when the thread executes it
+	runtime.LockOSThread()
+
+	if spec.UID != -1 || spec.GID != -1 {
+		// Drop the supplementary groups of CTS
+		groups := []int{}
+		if spec.GID != -1 {
+			groups = []int{spec.GID}
+		}
+		if err := syscall.Setgroups(groups); err != nil {
+			return fmt.Errorf("unable to set groups: %s", err)
+		}
+	}
+	if spec.GID != -1 {
+		if err := syscall.Setgid(spec.GID); err != nil {
+			return fmt.Errorf("unable to set gid %d: %s", spec.GID, err)
+		}
+	}
+	if spec.UID != -1 {
+		if err := syscall.Setuid(spec.UID); err != nil {
+			return fmt.Errorf("unable to set uid %d: %s", spec.UID, err)
+		}
+	}
+
+	if len(spec.WritablePaths) > 0 || len(spec.Seccomp) > 0 {
+		// Required to restrict the process without privileges, and prevents
+		// Terraform from gaining privileges
+		_, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, prSetNoNewPrivs, 1, 0)
+		if errno != 0 {
+			return fmt.Errorf("unable to set no_new_privs: %s", errno)
+		}
+	}
+	if len(spec.WritablePaths) > 0 {
+		if err := landlockRestrict(spec.WritablePaths); err != nil {
+			return err
+		}
+	}
+	if len(spec.Seccomp) > 0 {
+		filter := seccompFilter(spec.Seccomp)
+		prog := syscall.SockFprog{
+			Len:    uint16(len(filter)),
+			Filter: &filter[0],
+		}
+		_, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, prSetSeccomp,
+			seccompModeFilter, uintptr(unsafe.Pointer(&prog)))
+		if errno != 0 {
+			return fmt.Errorf("unable to set seccomp filter: %s", errno)
+		}
+	}
+
+	return syscall.Exec(spec.ExecPath, argv, env)
+}
+
+// landlockRestrict restricts the thread to only modify the file system within
+// the paths. Paths that do not exist are ignored.
+func landlockRestrict(paths []string) error {
+	attr := landlockRulesetAttr{handledAccessFS: landlockAccessFSWrite}
+	fd, _, errno := syscall.RawSyscall(sysLandlockCreateRuleset,
+		uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return fmt.Errorf("unable to create Landlock ruleset, the kernel may "+
+			"not support Landlock: %s", errno)
+	}
+	defer syscall.Close(int(fd))
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		// Access rights for directories cannot be added for files
+		access := uint64(landlockAccessFSWrite)
+		if !info.IsDir() {
+			access = landlockAccessFSWriteFile
+		}
+
+		pathFd, err := syscall.Open(path, oPath|syscall.O_CLOEXEC, 0)
+		if err != nil {
+			return fmt.Errorf("unable to open %s for Landlock rule: %s", path, err)
+		}
+		rule := landlockPathBeneathAttr{allowedAccess: access, parentFd: int32(pathFd)}
+		_, _, errno := syscall.RawSyscall6(sysLandlockAddRule, fd,
+			landlockRulePathBeneath, uintptr(unsafe.Pointer(&rule)), 0, 0, 0)
+		syscall.Close(pathFd)
+		if errno != 0 {
+			return fmt.Errorf("unable to add Landlock rule for %s: %s", path, errno)
+		}
+	}
+
+	_, _, errno = syscall.RawSyscall(sysLandlockRestrictSelf, fd, 0, 0)
+	if errno != 0 {
+		return fmt.Errorf("unable to restrict with Landlock ruleset: %s", errno)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+const (
+	// seccompAuditArch is AUDIT_ARCH_X86_64
+	seccompAuditArch = 0xc000003e
+
+	// seccompSyscallLimit is the bit of the system call numbers of the x32
+	// ABI, which share the architecture with x86-64
+	seccompSyscallLimit = 0x40000000
+)
+
+// seccompSyscalls are the numbers of the system calls that can be denied by
+// seccomp profiles. These are the system calls that are commonly denied to
+// untrusted processes.
+var seccompSyscalls = map[string]uint32{
+	"acct":              163,
+	"add_key":           248,
+	"adjtimex":          159,
+	"bpf":               321,
+	"capset":            126,
+	"chroot":            161,
+	"clock_adjtime":     305,
+	"clock_settime":     227,
+	"create_module":     174,
+	"delete_module":     176,
+	"fanotify_init":     300,
+	"finit_module":      313,
+	"fsconfig":          431,
+	"fsmount":           432,
+	"fsopen":            430,
+	"fspick":            433,
+	"get_kernel_syms":   177,
+	"init_module":       175,
+	"io_uring_enter":    426,
+	"io_uring_register": 427,
+	"io_uring_setup":    425,
+	"ioperm":            173,
+	"iopl":              172,
+	"kcmp":              312,
+	"kexec_file_load":   320,
+	"kexec_load":        246,
+	"keyctl":            250,
+	"lookup_dcookie":    212,
+	"mknod":             133,
+	"mknodat":           259,
+	"mount":             165,
+	"mount_setattr":     442,
+	"move_mount":        429,
+	"name_to_handle_at": 303,
+	"nfsservctl":        180,
+	"open_by_handle_at": 304,
+	"open_tree":         428,
+	"perf_event_open":   298,
+	"personality":       135,
+	"pivot_root":        155,
+	"process_vm_readv":  310,
+	"process_vm_writev": 311,
+	"ptrace":            101,
+	"query_module":      178,
+	"quotactl":          179,
+	"reboot":            169,
+	"request_key":       249,
+	"setdomainname":     171,
+	"setfsgid":          123,
+	"setfsuid":          122,
+	"setgid":            106,
+	"setgroups":         116,
+	"sethostname":       170,
+	"setns":             308,
+	"setregid":          114,
+	"setresgid":         119,
+	"setresuid":         117,
+	"setreuid":          113,
+	"settimeofday":      164,
+	"setuid":            105,
+	"swapoff":           168,
+	"swapon":            167,
+	"syslog":            103,
+	"umount2":           166,
+	"unshare":           272,
+	"uselib":            134,
+	"userfaultfd":       323,
+	"ustat":             136,
+	"vhangup":           153,
+}
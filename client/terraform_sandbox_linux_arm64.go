@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+const (
+	// seccompAuditArch is AUDIT_ARCH_AARCH64
+	seccompAuditArch = 0xc00000b7
+
+	seccompSyscallLimit = 0
+)
+
+// seccompSyscalls are the numbers of the system calls that can be denied by
+// seccomp profiles. These are the system calls that are commonly denied to
+// untrusted processes.
+var seccompSyscalls = map[string]uint32{
+	"acct":              89,
+	"add_key":           217,
+	"adjtimex":          171,
+	"bpf":               280,
+	"capset":            91,
+	"chroot":            51,
+	"clock_adjtime":     266,
+	"clock_settime":     112,
+	"delete_module":     106,
+	"fanotify_init":     262,
+	"finit_module":      273,
+	"fsconfig":          431,
+	"fsmount":           432,
+	"fsopen":            430,
+	"fspick":            433,
+	"init_module":       105,
+	"io_uring_enter":    426,
+	"io_uring_register": 427,
+	"io_uring_setup":    425,
+	"kcmp":              272,
+	"kexec_file_load":   294,
+	"kexec_load":        104,
+	"keyctl":            219,
+	"lookup_dcookie":    18,
+	"mknodat":           33,
+	"mount":             40,
+	"mount_setattr":     442,
+	"move_mount":        429,
+	"name_to_handle_at": 264,
+	"nfsservctl":        42,
+	"open_by_handle_at": 265,
+	"open_tree":         428,
+	"perf_event_open":   241,
+	"personality":       92,
+	"pivot_root":        41,
+	"process_vm_readv":  270,
+	"process_vm_writev": 271,
+	"ptrace":            117,
+	"quotactl":          60,
+	"reboot":            142,
+	"request_key":       218,
+	"setdomainname":     162,
+	"setfsgid":          152,
+	"setfsuid":          151,
+	"setgid":            144,
+	"setgroups":         159,
+	"sethostname":       161,
+	"setns":             268,
+	"setregid":          143,
+	"setresgid":         149,
+	"setresuid":         147,
+	"setreuid":          145,
+	"settimeofday":      170,
+	"setuid":            146,
+	"swapoff":           225,
+	"swapon":            224,
+	"syslog":            116,
+	"umount2":           39,
+	"unshare":           97,
+	"userfaultfd":       282,
+	"vhangup":           58,
+}
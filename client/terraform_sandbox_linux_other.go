@@ -0,0 +1,15 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build linux && !amd64 && !arm64
+
+package client
+
+const (
+	seccompAuditArch    = 0
+	seccompSyscallLimit = 0
+)
+
+// seccompSyscalls is empty since seccomp profiles are only supported on
+// amd64 and arm64
+var seccompSyscalls = map[string]uint32{}
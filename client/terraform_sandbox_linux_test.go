@@ -0,0 +1,243 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sandboxHelperEnvVar is set when the sandbox tests execute the test binary as
+// the wrapper and as Terraform. The value is the action for Terraform.
+const sandboxHelperEnvVar = "CTS_TEST_SANDBOX_HELPER"
+
+func TestParseSeccompProfile(t *testing.T) {
+	t.Parallel()
+
+	if len(seccompSyscalls) == 0 {
+		t.Skip("seccomp profiles are not supported on this architecture")
+	}
+	ptrace := seccompSyscalls["ptrace"]
+	mount := seccompSyscalls["mount"]
+
+	cases := []struct {
+		name     string
+		profile  string
+		expected []seccompRule
+	}{
+		{
+			"errno",
+			`{"defaultAction": "SCMP_ACT_ALLOW", "syscalls": [
+				{"names": ["ptrace", "mount"], "action": "SCMP_ACT_ERRNO"}
+			]}`,
+			[]seccompRule{
+				{Syscall: ptrace, Action: seccompRetErrno | uint32(syscall.EPERM)},
+				{Syscall: mount, Action: seccompRetErrno | uint32(syscall.EPERM)},
+			},
+		},
+		{
+			"errnoRet",
+			`{"defaultAction": "SCMP_ACT_ALLOW", "syscalls": [
+				{"names": ["ptrace"], "action": "SCMP_ACT_ERRNO", "errnoRet": 38}
+			]}`,
+			[]seccompRule{
+				{Syscall: ptrace, Action: seccompRetErrno | 38},
+			},
+		},
+		{
+			"first rule takes precedence",
+			`{"defaultAction": "SCMP_ACT_ALLOW", "syscalls": [
+				{"names": ["ptrace"], "action": "SCMP_ACT_KILL_PROCESS"},
+				{"names": ["ptrace"], "action": "SCMP_ACT_ERRNO"}
+			]}`,
+			[]seccompRule{
+				{Syscall: ptrace, Action: seccompRetKillProcess},
+			},
+		},
+		{
+			"no syscalls",
+			`{"defaultAction": "SCMP_ACT_ALLOW"}`,
+			nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules, err := parseSeccompProfile([]byte(tc.profile))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, rules)
+		})
+	}
+
+	errCases := []struct {
+		name    string
+		profile string
+	}{
+		{
+			"invalid json",
+			`{`,
+		},
+		{
+			"unsupported default action",
+			`{"defaultAction": "SCMP_ACT_ERRNO"}`,
+		},
+		{
+			"unsupported action",
+			`{"defaultAction": "SCMP_ACT_ALLOW", "syscalls": [
+				{"names": ["ptrace"], "action": "SCMP_ACT_TRACE"}
+			]}`,
+		},
+		{
+			"unsupported syscall",
+			`{"defaultAction": "SCMP_ACT_ALLOW", "syscalls": [
+				{"names": ["read"], "action": "SCMP_ACT_ERRNO"}
+			]}`,
+		},
+		{
+			"unsupported args",
+			`{"defaultAction": "SCMP_ACT_ALLOW", "syscalls": [
+				{"names": ["personality"], "action": "SCMP_ACT_ERRNO",
+				 "args": [{"index": 0, "value": 8, "op": "SCMP_CMP_EQ"}]}
+			]}`,
+		},
+	}
+
+	for _, tc := range errCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseSeccompProfile([]byte(tc.profile))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSeccompFilter(t *testing.T) {
+	t.Parallel()
+
+	rules := []seccompRule{
+		{Syscall: 1, Action: seccompRetErrno | 1},
+		{Syscall: 2, Action: seccompRetKillProcess},
+	}
+	filter := seccompFilter(rules)
+
+	// The filter loads the architecture and kills the process for other
+	// architectures before it loads the system call number
+	assert.Equal(t, uint32(seccompDataArchOffset), filter[0].K)
+	assert.Equal(t, uint32(seccompAuditArch), filter[1].K)
+	assert.Equal(t, uint32(seccompRetKillProcess), filter[2].K)
+	assert.Equal(t, uint32(seccompDataNrOffset), filter[3].K)
+
+	// Each rule returns its action, and other system calls are allowed
+	rest := filter[4:]
+	if seccompSyscallLimit > 0 {
+		rest = rest[2:]
+	}
+	require.Len(t, rest, 2*len(rules)+1)
+	for i, r := range rules {
+		assert.Equal(t, r.Syscall, rest[2*i].K)
+		assert.Equal(t, r.Action, rest[2*i+1].K)
+	}
+	assert.Equal(t, uint32(seccompRetAllow), rest[len(rest)-1].K)
+}
+
+// TestSandboxHelperProcess is not a test. The sandbox tests execute the test
+// binary with this test as the wrapper, and the wrapper executes the test
+// binary with this test as Terraform.
+func TestSandboxHelperProcess(t *testing.T) {
+	action, ok := os.LookupEnv(sandboxHelperEnvVar)
+	if !ok {
+		return
+	}
+
+	if InTerraformSandbox() {
+		err := ExecTerraformSandbox(os.Args[1:])
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	switch {
+	case action == "chroot":
+		err := syscall.Chroot("/")
+		var errno syscall.Errno
+		errors.As(err, &errno)
+		fmt.Print(int(errno))
+	case strings.HasPrefix(action, "write:"):
+		err := os.WriteFile(strings.TrimPrefix(action, "write:"), []byte("test"), 0644)
+		if err != nil {
+			fmt.Print(err)
+		} else {
+			fmt.Print("ok")
+		}
+	}
+	os.Exit(0)
+}
+
+// runSandboxHelper executes the action with the test binary as Terraform in a
+// sandbox, and returns the output of the action
+func runSandboxHelper(t *testing.T, spec sandboxSpec, action string) string {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	spec.ExecPath = exe
+
+	b, err := json.Marshal(spec)
+	require.NoError(t, err)
+
+	var stderr bytes.Buffer
+	cmd := exec.Command(exe, "-test.run=^TestSandboxHelperProcess$")
+	cmd.Env = append(os.Environ(),
+		sandboxHelperEnvVar+"="+action,
+		sandboxEnvVar+"="+string(b))
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	require.NoError(t, err, stderr.String())
+	return string(out)
+}
+
+func TestExecTerraformSandbox_Seccomp(t *testing.T) {
+	t.Parallel()
+
+	if len(seccompSyscalls) == 0 {
+		t.Skip("seccomp profiles are not supported on this architecture")
+	}
+
+	rules, err := parseSeccompProfile([]byte(`{
+		"defaultAction": "SCMP_ACT_ALLOW",
+		"syscalls": [
+			{"names": ["chroot"], "action": "SCMP_ACT_ERRNO", "errnoRet": 95}
+		]}`))
+	require.NoError(t, err)
+
+	out := runSandboxHelper(t, sandboxSpec{UID: -1, GID: -1, Seccomp: rules}, "chroot")
+	assert.Equal(t, fmt.Sprint(int(syscall.EOPNOTSUPP)), out)
+}
+
+func TestExecTerraformSandbox_ConfineWorkingDir(t *testing.T) {
+	t.Parallel()
+
+	// Check the Landlock ABI version to verify that the kernel supports it
+	_, _, errno := syscall.RawSyscall(sysLandlockCreateRuleset, 0, 0, 1)
+	if errno != 0 {
+		t.Skipf("Landlock is not supported: %s", errno)
+	}
+
+	workingDir := t.TempDir()
+	otherDir := t.TempDir()
+	spec := sandboxSpec{UID: -1, GID: -1, WritablePaths: []string{workingDir}}
+
+	out := runSandboxHelper(t, spec, "write:"+filepath.Join(workingDir, "main.tf"))
+	assert.Equal(t, "ok", out)
+
+	out = runSandboxHelper(t, spec, "write:"+filepath.Join(otherDir, "main.tf"))
+	assert.Contains(t, out, "permission denied")
+}
@@ -0,0 +1,29 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !linux
+
+package client
+
+import (
+	"errors"
+	"runtime"
+)
+
+// errSandboxUnsupported is returned for the options of the sandbox that
+// reduce privileges with the wrapper, which are only supported on Linux
+var errSandboxUnsupported = errors.New("the uid, gid, confine_working_dir, " +
+	"and seccomp_profile sandbox options are only supported on Linux, not " +
+	runtime.GOOS)
+
+func loadSeccompProfile(string) ([]seccompRule, error) {
+	return nil, errSandboxUnsupported
+}
+
+func checkSandboxSupport(*sandboxSpec) error {
+	return errSandboxUnsupported
+}
+
+func execSandbox(*sandboxSpec, []string, []string) error {
+	return errSandboxUnsupported
+}
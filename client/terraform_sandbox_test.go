@@ -0,0 +1,95 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package client
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSandbox(t *testing.T) {
+	t.Parallel()
+
+	t.Run("env allowlist only", func(t *testing.T) {
+		s, err := newSandbox(&SandboxConfig{
+			UID:          -1,
+			GID:          -1,
+			EnvAllowlist: []string{"PATH"},
+		}, "/bin/terraform", "/tmp/task")
+		require.NoError(t, err)
+
+		// The wrapper is not required
+		assert.Empty(t, s.spec)
+		execPath, err := s.execPath("/bin/terraform")
+		require.NoError(t, err)
+		assert.Equal(t, "/bin/terraform", execPath)
+	})
+
+	t.Run("invalid seccomp profile", func(t *testing.T) {
+		_, err := newSandbox(&SandboxConfig{
+			UID:            -1,
+			GID:            -1,
+			SeccompProfile: "does/not/exist.json",
+		}, "/bin/terraform", "/tmp/task")
+		assert.Error(t, err)
+	})
+}
+
+func TestSandbox_Env(t *testing.T) {
+	t.Setenv("CTS_TEST_ALLOWED", "allowed")
+	t.Setenv("CTS_TEST_DENIED", "denied")
+
+	t.Run("allowlist", func(t *testing.T) {
+		s := &sandbox{envAllowlist: []string{"CTS_TEST_ALLOWED", "CTS_TEST_UNSET"}}
+		env := s.env(map[string]string{"TASK": "task"})
+		assert.Equal(t, map[string]string{
+			"CTS_TEST_ALLOWED": "allowed",
+			"TASK":             "task",
+		}, env)
+	})
+
+	t.Run("task env takes precedence", func(t *testing.T) {
+		s := &sandbox{envAllowlist: []string{"CTS_TEST_ALLOWED"}}
+		env := s.env(map[string]string{"CTS_TEST_ALLOWED": "task"})
+		assert.Equal(t, map[string]string{"CTS_TEST_ALLOWED": "task"}, env)
+	})
+
+	t.Run("wrapper", func(t *testing.T) {
+		s := &sandbox{spec: `{"exec_path":"/bin/terraform"}`}
+		env := s.env(nil)
+		assert.Equal(t, map[string]string{
+			sandboxEnvVar: `{"exec_path":"/bin/terraform"}`,
+		}, env)
+	})
+}
+
+func TestExecTerraformSandbox_InvalidSpec(t *testing.T) {
+	t.Setenv(sandboxEnvVar, "invalid")
+
+	assert.True(t, InTerraformSandbox())
+	err := ExecTerraformSandbox(nil)
+	assert.Error(t, err)
+}
+
+func TestSandboxSpec_JSON(t *testing.T) {
+	t.Parallel()
+
+	spec := sandboxSpec{
+		ExecPath:      "/bin/terraform",
+		UID:           1001,
+		GID:           -1,
+		WritablePaths: []string{"/tmp/task", os.TempDir()},
+		Seccomp:       []seccompRule{{Syscall: 101, Action: 0x00050001}},
+	}
+	b, err := json.Marshal(spec)
+	require.NoError(t, err)
+
+	var actual sandboxSpec
+	require.NoError(t, json.Unmarshal(b, &actual))
+	assert.Equal(t, spec, actual)
+}
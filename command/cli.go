@@ -37,6 +37,18 @@ const (
 	logSystemName = "cli"
 )
 
+// Exit codes of once mode that reflect the outcome of the tasks. Errors before
+// the tasks are run, e.g. configuration errors, exit with the codes above.
+const (
+	// ExitCodeOnceFatal is the exit code when running the tasks stopped
+	// early because of an error
+	ExitCodeOnceFatal int = 1
+
+	// ExitCodeOncePartialFailure is the exit code when all tasks were run and
+	// some of the tasks errored, see the once mode failure policy
+	ExitCodeOncePartialFailure int = 2
+)
+
 var _ api.Server = (*controller.TasksManager)(nil)
 
 // CLI is the main entry point.
@@ -58,6 +70,7 @@ var (
 	// Common commands are grouped separately to call them out to operators.
 	commonCommands = []string{
 		"start",
+		"status",
 		"task",
 	}
 )
@@ -51,9 +51,36 @@ func Commands(writer io.Writer, errorWriter io.Writer) map[string]cli.CommandFac
 		cmdTaskCreateName: func() (cli.Command, error) {
 			return newTaskCreateCommand(m), nil
 		},
+		cmdTaskRenderName: func() (cli.Command, error) {
+			return newTaskRenderCommand(m), nil
+		},
+		cmdTaskReinitName: func() (cli.Command, error) {
+			return newTaskReinitCommand(m), nil
+		},
+		cmdModuleScaffoldName: func() (cli.Command, error) {
+			return newModuleScaffoldCommand(m), nil
+		},
+		cmdModuleValidateName: func() (cli.Command, error) {
+			return newModuleValidateCommand(m), nil
+		},
+		cmdMigrateConsulTemplateName: func() (cli.Command, error) {
+			return newMigrateConsulTemplateCommand(m), nil
+		},
+		cmdConfigUpgradeName: func() (cli.Command, error) {
+			return newConfigUpgradeCommand(m), nil
+		},
+		cmdStateBackupName: func() (cli.Command, error) {
+			return newStateBackupCommand(m), nil
+		},
+		cmdStateRestoreName: func() (cli.Command, error) {
+			return newStateRestoreCommand(m), nil
+		},
 		cmdStartName: func() (cli.Command, error) {
 			return newStartCommand(m), nil
 		},
+		cmdStatusName: func() (cli.Command, error) {
+			return newStatusCommand(m), nil
+		},
 	}
 
 	return all
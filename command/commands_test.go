@@ -16,11 +16,20 @@ func Test_Commands(t *testing.T) {
 
 	// map of commands to synopsis
 	expectedCommands := map[string]cli.Command{
-		cmdTaskCreateName:  &taskCreateCommand{},
-		cmdTaskEnableName:  &taskEnableCommand{},
-		cmdTaskDisableName: &taskDisableCommand{},
-		cmdTaskDeleteName:  &taskDeleteCommand{},
-		cmdStartName:       &startCommand{},
+		cmdTaskCreateName:            &taskCreateCommand{},
+		cmdTaskEnableName:            &taskEnableCommand{},
+		cmdTaskDisableName:           &taskDisableCommand{},
+		cmdTaskDeleteName:            &taskDeleteCommand{},
+		cmdTaskRenderName:            &taskRenderCommand{},
+		cmdTaskReinitName:            &taskReinitCommand{},
+		cmdModuleScaffoldName:        &moduleScaffoldCommand{},
+		cmdModuleValidateName:        &moduleValidateCommand{},
+		cmdMigrateConsulTemplateName: &migrateConsulTemplateCommand{},
+		cmdConfigUpgradeName:         &configUpgradeCommand{},
+		cmdStateBackupName:           &stateBackupCommand{},
+		cmdStateRestoreName:          &stateRestoreCommand{},
+		cmdStartName:                 &startCommand{},
+		cmdStatusName:                &statusCommand{},
 	}
 
 	assert.Equal(t, len(expectedCommands), len(cf))
@@ -0,0 +1,457 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/token"
+	"github.com/mitchellh/go-wordwrap"
+	"github.com/posener/complete"
+)
+
+const (
+	cmdConfigUpgradeName = "config upgrade"
+
+	flagWrite = "write"
+)
+
+// configUpgradeCommand handles the `config upgrade` command
+type configUpgradeCommand struct {
+	meta
+	configFile *string
+	write      *string
+	flags      *flag.FlagSet
+}
+
+func newConfigUpgradeCommand(m meta) *configUpgradeCommand {
+	logging.DisableLogging()
+	flags := flag.NewFlagSet(cmdConfigUpgradeName, flag.ContinueOnError)
+	flags.SetOutput(m.writer)
+
+	configFile := flags.String(flagConfigFiles, "", "[Required] The path to the "+
+		"HCL configuration file to upgrade.")
+	write := flags.String(flagWrite, "", "The path to write the upgraded "+
+		"configuration to. \n\t\tThe upgraded configuration is written to stdout "+
+		"if not set.")
+
+	m.flags = flags
+	return &configUpgradeCommand{
+		meta:       m,
+		configFile: configFile,
+		write:      write,
+		flags:      flags,
+	}
+}
+
+// Name returns the subcommand
+func (c *configUpgradeCommand) Name() string {
+	return cmdConfigUpgradeName
+}
+
+// Help returns the command's usage, list of flags, and examples
+func (c *configUpgradeCommand) Help() string {
+	c.meta.setHelpOptions()
+	helpText := fmt.Sprintf(`
+Usage: consul-terraform-sync config upgrade [-help] [options]
+
+  Config Upgrade rewrites the deprecated fields of a configuration file into
+  the current configuration schema:
+
+    - the task 'source' field is renamed to 'module'
+    - the task 'source_input' blocks are renamed to 'module_input'
+    - the task 'services' field is converted into a 'condition "services"'
+      block, or a 'module_input "services"' block if the task has a condition
+    - the condition 'source_includes_var' field is renamed to
+      'use_as_module_input'
+
+  Comments and formatting of the rest of the file are kept. Deprecated
+  configuration that cannot be upgraded automatically, such as the 'service'
+  blocks, is reported for review.
+
+Options:
+%s
+
+Example:
+
+  $ consul-terraform-sync config upgrade -config-file=old.hcl -write=new.hcl
+`, strings.Join(c.meta.helpOptions, "\n"))
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis is a short one-line synopsis of the command
+func (c *configUpgradeCommand) Synopsis() string {
+	return "Rewrites deprecated configuration into the current schema."
+}
+
+// AutocompleteFlags returns a mapping of supported flags and autocomplete
+// options for this command. The map key for the Flags map should be the
+// complete flag such as "-foo" or "--foo".
+func (c *configUpgradeCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		fmt.Sprintf("-%s", flagConfigFiles): complete.PredictFiles("*.hcl"),
+		fmt.Sprintf("-%s", flagWrite):       complete.PredictFiles("*.hcl"),
+	}
+}
+
+// AutocompleteArgs returns the argument predictor for this command.
+// Since argument completion is not supported, this will return
+// complete.PredictNothing.
+func (c *configUpgradeCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+// Run runs the command
+func (c *configUpgradeCommand) Run(args []string) int {
+	c.flags.Usage = func() { c.meta.UI.Output(c.Help()) }
+	if err := c.flags.Parse(args); err != nil {
+		return ExitCodeParseFlagsError
+	}
+
+	if *c.configFile == "" {
+		c.UI.Error(fmt.Sprintf("Error: the -%s flag is required", flagConfigFiles))
+		c.UI.Output(fmt.Sprintf("For additional help try 'consul-terraform-sync %s --help'",
+			c.Name()))
+		return ExitCodeRequiredFlagsError
+	}
+
+	u, err := upgradeConfig(*c.configFile)
+	if err != nil {
+		c.UI.Error("Error: unable to upgrade configuration")
+		msg := wordwrap.WrapString(err.Error(), width)
+		c.UI.Output(msg)
+		return ExitCodeError
+	}
+
+	if *c.write == "" {
+		fmt.Fprint(c.meta.writer, u.config)
+	} else {
+		if err := os.WriteFile(*c.write, []byte(u.config), 0644); err != nil {
+			c.UI.Error(fmt.Sprintf("Error: unable to write the upgraded "+
+				"configuration to '%s'", *c.write))
+			c.UI.Output(wordwrap.WrapString(err.Error(), width))
+			return ExitCodeError
+		}
+		for _, change := range u.changes {
+			c.UI.Info(change)
+		}
+		c.UI.Info(fmt.Sprintf("Upgraded configuration written to '%s'", *c.write))
+	}
+
+	// Notes are written as warnings so that they are not mixed with the
+	// upgraded configuration written to stdout
+	for _, note := range u.notes {
+		c.UI.Warn(fmt.Sprintf("NOTE: %s", note))
+	}
+	return ExitCodeOK
+}
+
+// configUpgrade is the result of upgrading a configuration file. Changes are
+// the deprecated fields that were upgraded, and notes are the deprecated
+// fields that need to be upgraded manually.
+type configUpgrade struct {
+	config  string
+	changes []string
+	notes   []string
+}
+
+// configEdit replaces the content between the start and end offsets
+type configEdit struct {
+	start, end int
+	text       string
+}
+
+// configUpgrader collects the edits to upgrade the content of a
+// configuration file
+type configUpgrader struct {
+	content []byte
+	edits   []configEdit
+	configUpgrade
+}
+
+// upgradeConfig reads the HCL configuration file and rewrites its deprecated
+// fields. The deprecated fields are edited in place using the positions of
+// the parsed fields so that the comments and formatting of the file are kept.
+func upgradeConfig(path string) (*configUpgrade, error) {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return nil, fmt.Errorf("'%s' is a JSON file, only HCL configuration "+
+			"files can be upgraded", path)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := hcl.Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse '%s': %s", path, err)
+	}
+	list, ok := root.Node.(*ast.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("unable to parse '%s': root should be an object", path)
+	}
+
+	u := &configUpgrader{content: content}
+	for _, item := range list.Items {
+		switch keyName(item.Keys[0]) {
+		case "service":
+			u.note("the deprecated 'service' block for %q was not upgraded. "+
+				"Configure the options of the service on the 'condition "+
+				"\"services\"' or 'module_input \"services\"' blocks of the tasks "+
+				"instead", serviceName(item))
+		case "task":
+			if obj, ok := item.Val.(*ast.ObjectType); ok {
+				u.upgradeTask(obj.List)
+			}
+		}
+	}
+
+	u.config = u.apply()
+	return &u.configUpgrade, nil
+}
+
+// upgradeTask collects the edits for the deprecated fields of a task
+func (u *configUpgrader) upgradeTask(task *ast.ObjectList) {
+	name := blockName(task)
+
+	var services, source, module, condition *ast.ObjectItem
+	var conditionType string
+	moduleInputs := make(map[string]bool)
+	for _, item := range task.Items {
+		switch keyName(item.Keys[0]) {
+		case "services":
+			services = item
+		case "source":
+			source = item
+		case "module":
+			module = item
+		case "condition":
+			condition = item
+			if len(item.Keys) > 1 {
+				conditionType = keyName(item.Keys[1])
+			}
+			u.upgradeSourceIncludesVar(name, item)
+		case "module_input", "source_input":
+			if len(item.Keys) > 1 {
+				moduleInputs[keyName(item.Keys[1])] = true
+			}
+		case "terraform_version":
+			u.note("the deprecated 'terraform_version' field of task %q was not "+
+				"upgraded. Configure 'terraform_version' on the "+
+				"'terraform_cloud_workspace' block of the task instead", name)
+		}
+	}
+
+	for _, item := range task.Items {
+		if keyName(item.Keys[0]) == "source_input" {
+			u.rename(item.Keys[0], "module_input")
+			u.change("task %q: renamed 'source_input' to 'module_input'", name)
+		}
+	}
+
+	if source != nil {
+		if module != nil {
+			u.remove(source)
+			u.change("task %q: removed 'source' since 'module' is configured", name)
+		} else {
+			u.rename(source.Keys[0], "module")
+			u.change("task %q: renamed 'source' to 'module'", name)
+		}
+	}
+
+	if services != nil {
+		u.upgradeServices(name, services, condition, conditionType, moduleInputs)
+	}
+}
+
+// upgradeServices converts the services field of a task into a services
+// condition, or a services module input if the task has a condition
+func (u *configUpgrader) upgradeServices(name string, services,
+	condition *ast.ObjectItem, conditionType string, moduleInputs map[string]bool) {
+
+	list, ok := services.Val.(*ast.ListType)
+	if !ok {
+		u.note("the deprecated 'services' field of task %q is not a list and "+
+			"was not upgraded", name)
+		return
+	}
+
+	kind := "condition"
+	if condition != nil {
+		kind = "module_input"
+		if conditionType == "services" {
+			u.note("task %q configures both the deprecated 'services' field and "+
+				"a 'condition \"services\"' block. The 'services' field was not "+
+				"upgraded", name)
+			return
+		}
+		if moduleInputs["services"] {
+			u.note("task %q configures both the deprecated 'services' field and "+
+				"a 'module_input \"services\"' block. The 'services' field was "+
+				"not upgraded", name)
+			return
+		}
+	}
+
+	start := services.Keys[0].Pos().Offset
+	indent := u.indent(start)
+	names := string(u.content[list.Lbrack.Offset : list.Rbrack.Offset+1])
+	block := fmt.Sprintf("%s \"services\" {\n%s  names = %s\n%s}",
+		kind, indent, names, indent)
+
+	u.edits = append(u.edits, configEdit{
+		start: start,
+		end:   list.Rbrack.Offset + 1,
+		text:  block,
+	})
+	u.change("task %q: converted 'services' to a '%s \"services\"' block", name, kind)
+}
+
+// upgradeSourceIncludesVar renames the source_includes_var field of a
+// condition to use_as_module_input
+func (u *configUpgrader) upgradeSourceIncludesVar(name string, condition *ast.ObjectItem) {
+	obj, ok := condition.Val.(*ast.ObjectType)
+	if !ok {
+		return
+	}
+
+	var deprecated, current *ast.ObjectItem
+	for _, item := range obj.List.Items {
+		switch keyName(item.Keys[0]) {
+		case "source_includes_var":
+			deprecated = item
+		case "use_as_module_input":
+			current = item
+		}
+	}
+
+	switch {
+	case deprecated == nil:
+	case current != nil:
+		u.remove(deprecated)
+		u.change("task %q: removed the condition's 'source_includes_var' since "+
+			"'use_as_module_input' is configured", name)
+	default:
+		u.rename(deprecated.Keys[0], "use_as_module_input")
+		u.change("task %q: renamed the condition's 'source_includes_var' to "+
+			"'use_as_module_input'", name)
+	}
+}
+
+// rename replaces the name of the key
+func (u *configUpgrader) rename(key *ast.ObjectKey, name string) {
+	start := key.Token.Pos.Offset
+	u.edits = append(u.edits, configEdit{
+		start: start,
+		end:   start + len(key.Token.Text),
+		text:  name,
+	})
+}
+
+// remove removes the attribute. The line of the attribute is removed if the
+// line does not contain anything else.
+func (u *configUpgrader) remove(item *ast.ObjectItem) {
+	lit, ok := item.Val.(*ast.LiteralType)
+	if !ok {
+		return
+	}
+
+	start := item.Keys[0].Pos().Offset
+	end := lit.Token.Pos.Offset + len(lit.Token.Text)
+
+	lineStart := start - len(u.indent(start))
+	rest := end
+	for rest < len(u.content) && (u.content[rest] == ' ' || u.content[rest] == '\t') {
+		rest++
+	}
+	if rest == len(u.content) || u.content[rest] == '\n' {
+		start = lineStart
+		end = rest
+		if end < len(u.content) {
+			end++
+		}
+	}
+
+	u.edits = append(u.edits, configEdit{start: start, end: end})
+}
+
+// indent returns the whitespace before the offset if the offset is the first
+// non-whitespace character of its line
+func (u *configUpgrader) indent(offset int) string {
+	i := offset
+	for i > 0 && (u.content[i-1] == ' ' || u.content[i-1] == '\t') {
+		i--
+	}
+	if i > 0 && u.content[i-1] != '\n' {
+		return ""
+	}
+	return string(u.content[i:offset])
+}
+
+// apply applies the edits to the content from the last edit to the first so
+// that the offsets of the earlier edits remain valid
+func (u *configUpgrader) apply() string {
+	sort.Slice(u.edits, func(i, j int) bool {
+		return u.edits[i].start > u.edits[j].start
+	})
+
+	content := append([]byte{}, u.content...)
+	for _, e := range u.edits {
+		content = append(content[:e.start:e.start],
+			append([]byte(e.text), content[e.end:]...)...)
+	}
+	return string(content)
+}
+
+func (u *configUpgrader) change(format string, args ...interface{}) {
+	u.changes = append(u.changes, fmt.Sprintf(format, args...))
+}
+
+func (u *configUpgrader) note(format string, args ...interface{}) {
+	u.notes = append(u.notes, fmt.Sprintf(format, args...))
+}
+
+// keyName returns the name of the key, unquoted if the key is a string
+func keyName(key *ast.ObjectKey) string {
+	if key.Token.Type == token.STRING {
+		if s, err := strconv.Unquote(key.Token.Text); err == nil {
+			return s
+		}
+	}
+	return key.Token.Text
+}
+
+// blockName returns the value of the name field of a block, or a placeholder
+// if the name is not a string
+func blockName(block *ast.ObjectList) string {
+	for _, item := range block.Items {
+		if keyName(item.Keys[0]) != "name" {
+			continue
+		}
+		if lit, ok := item.Val.(*ast.LiteralType); ok {
+			if s, ok := lit.Token.Value().(string); ok {
+				return s
+			}
+		}
+	}
+	return "<unnamed>"
+}
+
+// serviceName returns the name of a deprecated service block
+func serviceName(service *ast.ObjectItem) string {
+	if obj, ok := service.Val.(*ast.ObjectType); ok {
+		return blockName(obj.List)
+	}
+	return "<unnamed>"
+}
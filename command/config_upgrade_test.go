@@ -0,0 +1,281 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const deprecatedConfig = `# CTS configuration
+task {
+  name   = "web"
+  source = "./modules/web" # local module
+  services = ["web", "api"]
+
+  source_input "consul-kv" {
+    path = "config/"
+  }
+}
+
+task {
+  name     = "db"
+  module   = "./modules/db"
+  source   = "./modules/old"
+  services = ["db"]
+
+  condition "catalog-services" {
+    regexp              = "db.*"
+    source_includes_var = true
+  }
+}
+
+service {
+  name       = "web"
+  datacenter = "dc2"
+}
+`
+
+const upgradedConfig = `# CTS configuration
+task {
+  name   = "web"
+  module = "./modules/web" # local module
+  condition "services" {
+    names = ["web", "api"]
+  }
+
+  module_input "consul-kv" {
+    path = "config/"
+  }
+}
+
+task {
+  name     = "db"
+  module   = "./modules/db"
+  module_input "services" {
+    names = ["db"]
+  }
+
+  condition "catalog-services" {
+    regexp              = "db.*"
+    use_as_module_input = true
+  }
+}
+
+service {
+  name       = "web"
+  datacenter = "dc2"
+}
+`
+
+func TestConfigUpgradeCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	in := filepath.Join(dir, "old.hcl")
+	require.NoError(t, os.WriteFile(in, []byte(deprecatedConfig), 0644))
+
+	jsonFile := filepath.Join(dir, "old.json")
+	require.NoError(t, os.WriteFile(jsonFile, []byte(`{}`), 0644))
+
+	cases := []struct {
+		name           string
+		args           []string
+		expectedStatus int
+		expectedOutput string
+	}{
+		{
+			name:           "stdout",
+			args:           []string{"-config-file", in},
+			expectedStatus: ExitCodeOK,
+			expectedOutput: upgradedConfig,
+		},
+		{
+			name:           "report",
+			args:           []string{"-config-file", in},
+			expectedStatus: ExitCodeOK,
+			expectedOutput: `the deprecated 'service' block for "web" was not upgraded`,
+		},
+		{
+			name:           "missing config-file flag",
+			args:           []string{},
+			expectedStatus: ExitCodeRequiredFlagsError,
+			expectedOutput: "-config-file flag is required",
+		},
+		{
+			name:           "file does not exist",
+			args:           []string{"-config-file", filepath.Join(dir, "missing.hcl")},
+			expectedStatus: ExitCodeError,
+			expectedOutput: "no such file or directory",
+		},
+		{
+			name:           "json file",
+			args:           []string{"-config-file", jsonFile},
+			expectedStatus: ExitCodeError,
+			expectedOutput: "only HCL configuration files can be upgraded",
+		},
+		{
+			name:           "unsupported flag",
+			args:           []string{"-foo", "bar"},
+			expectedStatus: ExitCodeParseFlagsError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var b bytes.Buffer
+			ui := cli.NewMockUi()
+			cmd := newConfigUpgradeCommand(meta{UI: ui, writer: &b})
+
+			status := cmd.Run(tc.args)
+			assert.Equal(t, tc.expectedStatus, status)
+
+			// errors are wrapped to the width of the terminal
+			output := b.String() + ui.OutputWriter.String() + ui.ErrorWriter.String()
+			assert.Contains(t, strings.ReplaceAll(output, "\n", " "),
+				strings.ReplaceAll(tc.expectedOutput, "\n", " "))
+		})
+	}
+}
+
+func TestConfigUpgradeCommand_Run_Write(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	in := filepath.Join(dir, "old.hcl")
+	out := filepath.Join(dir, "new.hcl")
+	require.NoError(t, os.WriteFile(in, []byte(deprecatedConfig), 0644))
+
+	var b bytes.Buffer
+	ui := cli.NewMockUi()
+	cmd := newConfigUpgradeCommand(meta{UI: ui, writer: &b})
+
+	status := cmd.Run([]string{"-config-file", in, "-write", out})
+	require.Equal(t, ExitCodeOK, status)
+	assert.Empty(t, b.String())
+
+	actual, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, upgradedConfig, string(actual))
+
+	output := ui.OutputWriter.String()
+	assert.Contains(t, output, `task "web": renamed 'source' to 'module'`)
+	assert.Contains(t, output, `task "db": removed 'source' since 'module' is configured`)
+	assert.Contains(t, output, "Upgraded configuration written to")
+}
+
+func TestUpgradeConfig(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name            string
+		config          string
+		expectedConfig  string
+		expectedChanges int
+		expectedNotes   int
+	}{
+		{
+			"deprecated config",
+			deprecatedConfig,
+			upgradedConfig,
+			6,
+			1,
+		},
+		{
+			"current config",
+			upgradedConfig,
+			upgradedConfig,
+			0,
+			1,
+		},
+		{
+			"services with services condition",
+			`task {
+  name     = "web"
+  module   = "./modules/web"
+  services = ["web"]
+
+  condition "services" {
+    names = ["api"]
+  }
+}
+`,
+			`task {
+  name     = "web"
+  module   = "./modules/web"
+  services = ["web"]
+
+  condition "services" {
+    names = ["api"]
+  }
+}
+`,
+			0,
+			1,
+		},
+		{
+			"condition with use_as_module_input",
+			`task {
+  name   = "web"
+  module = "./modules/web"
+
+  condition "consul-kv" {
+    path                = "key"
+    use_as_module_input = true
+    source_includes_var = false
+  }
+}
+`,
+			`task {
+  name   = "web"
+  module = "./modules/web"
+
+  condition "consul-kv" {
+    path                = "key"
+    use_as_module_input = true
+  }
+}
+`,
+			1,
+			0,
+		},
+		{
+			"terraform_version",
+			`task {
+  name              = "web"
+  module            = "./modules/web"
+  terraform_version = "1.0.0"
+}
+`,
+			`task {
+  name              = "web"
+  module            = "./modules/web"
+  terraform_version = "1.0.0"
+}
+`,
+			0,
+			1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.hcl")
+			require.NoError(t, os.WriteFile(path, []byte(tc.config), 0644))
+
+			u, err := upgradeConfig(path)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedConfig, u.config)
+			assert.Len(t, u.changes, tc.expectedChanges)
+			assert.Len(t, u.notes, tc.expectedNotes)
+		})
+	}
+}
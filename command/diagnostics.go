@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/api"
+)
+
+// diagnosticsWriter writes a bundle of internal diagnostics for support
+type diagnosticsWriter interface {
+	WriteDiagnostics(ctx context.Context, w io.Writer) error
+}
+
+// writeDiagnosticsFile writes a diagnostics bundle to a new file in the
+// directory and returns the path of the file. The file is removed if the
+// bundle cannot be written.
+func writeDiagnosticsFile(ctx context.Context, dw diagnosticsWriter, dir string,
+	now time.Time) (string, error) {
+
+	path := filepath.Join(dir, api.DiagnosticsFilename(now))
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", err
+	}
+
+	err = dw.WriteDiagnostics(ctx, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
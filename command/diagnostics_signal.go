@@ -0,0 +1,15 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build !windows
+
+package command
+
+import (
+	"os"
+	"syscall"
+)
+
+// diagnosticsSignals are the signals that dump the internal diagnostics of
+// the daemon to a file
+var diagnosticsSignals = []os.Signal{syscall.SIGUSR2}
@@ -0,0 +1,12 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build windows
+
+package command
+
+import "os"
+
+// diagnosticsSignals is empty since SIGUSR2 is not supported on Windows. The
+// diagnostics can be dumped with the debug dump API instead.
+var diagnosticsSignals []os.Signal
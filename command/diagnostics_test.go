@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDiagnosticsWriter struct {
+	err error
+}
+
+func (w *testDiagnosticsWriter) WriteDiagnostics(_ context.Context, out io.Writer) error {
+	if _, err := out.Write([]byte("bundle")); err != nil {
+		return err
+	}
+	return w.err
+}
+
+func TestWriteDiagnosticsFile(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		dir := t.TempDir()
+		path, err := writeDiagnosticsFile(context.Background(),
+			&testDiagnosticsWriter{}, dir, now)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "cts-diagnostics-20220102T030405Z.tar.gz"), path)
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "bundle", string(content))
+
+		// an existing bundle is not overwritten
+		_, err = writeDiagnosticsFile(context.Background(),
+			&testDiagnosticsWriter{}, dir, now)
+		assert.Error(t, err)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		dir := t.TempDir()
+		_, err := writeDiagnosticsFile(context.Background(),
+			&testDiagnosticsWriter{err: errors.New("error")}, dir, now)
+		assert.Error(t, err)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries, "partial bundle is removed")
+	})
+}
@@ -35,7 +35,7 @@ func mergeAutocompleteFlags(flags ...complete.Flags) complete.Flags {
 }
 
 func getTasks(ctx context.Context, client oapigen.ClientWithResponsesInterface) (api.TasksResponse, error) {
-	resp, err := client.GetAllTasksWithResponse(ctx)
+	resp, err := client.GetAllTasksWithResponse(ctx, nil)
 	if err != nil {
 		return api.TasksResponse{}, err
 	}
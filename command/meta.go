@@ -9,6 +9,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/url"
+	"os"
 	"strings"
 
 	"github.com/hashicorp/consul-terraform-sync/api"
@@ -29,6 +30,8 @@ type meta struct {
 	helpOptions []string
 	port        *int
 	addr        *string
+	profile     *string
+	output      *string
 
 	tls    tls
 	writer io.Writer
@@ -54,6 +57,10 @@ const (
 	FlagSSLVerify  = "ssl-verify"
 
 	FlagAutoApprove = "auto-approve"
+
+	FlagAll    = "all"
+	FlagFilter = "filter"
+	FlagLabel  = "label"
 )
 
 func (m *meta) defaultFlagSet(name string) *flag.FlagSet {
@@ -67,7 +74,9 @@ func (m *meta) defaultFlagSet(name string) *flag.FlagSet {
 	m.addr = m.flags.String(FlagHTTPAddr, api.DefaultURL, fmt.Sprintf("The `address` and port of the CTS daemon. The value can be an IP address "+
 		"\n\t\tor DNS address, but it must also include the port. This can also be specified "+
 		"\n\t\tvia the %s environment variable. The scheme can also be set to HTTPS "+
-		"\n\t\tby including https in the provided address (eg. https://127.0.0.1:8558)", api.EnvAddress))
+		"\n\t\tby including https in the provided address (eg. https://127.0.0.1:8558). "+
+		"\n\t\tA unix socket can be used by providing its absolute path with the unix "+
+		"\n\t\tscheme (eg. unix:///var/run/cts.sock)", api.EnvAddress))
 
 	// Initialize TLS flags
 	m.tls.caPath = m.flags.String(FlagCAPath, "", fmt.Sprintf("Path to a directory of CA certificates to use for TLS when communicating "+
@@ -82,6 +91,15 @@ func (m *meta) defaultFlagSet(name string) *flag.FlagSet {
 	m.tls.sslVerify = m.flags.Bool(FlagSSLVerify, true, fmt.Sprintf("Boolean to verify SSL or not. Set to true to verify SSL. "+
 		"\n\t\tThis can also be specified using the %s environment variable.", api.EnvTLSSSLVerify))
 
+	m.profile = m.flags.String(FlagProfile, "", fmt.Sprintf("The name of the profile in the CLI configuration file to use for the "+
+		"\n\t\tconnection settings. The file defaults to ~/.cts/config.hcl and can be "+
+		"\n\t\tset with the %s environment variable. If not set, the file's "+
+		"\n\t\tdefault_profile is used. Flags and environment variables take "+
+		"\n\t\tprecedence over the profile. This can also be specified using the "+
+		"\n\t\t%s environment variable.", EnvCLIConfig, EnvProfile))
+
+	m.outputFlag(m.flags)
+
 	m.flags.SetOutput(ioutil.Discard)
 
 	return m.flags
@@ -133,9 +151,14 @@ func (m *meta) oneArgCheck(name string, args []string) bool {
 // clientConfig is used to initialize and return a new API ClientConfig using
 // the default command line arguments and env vars.
 func (m *meta) clientConfig() (*api.ClientConfig, error) {
-	// Let the Client determine its default first, then override with command flag values
+	// Let the Client determine its default first, then override with the
+	// profile and command flag values
 	c := api.BaseClientConfig()
 
+	if err := m.applyProfile(c); err != nil {
+		return nil, err
+	}
+
 	// override config values from flags
 	if m.isFlagParsedAndFound(FlagPort) {
 		m.UI.Warn(fmt.Sprintf("Warning: '%s' option is deprecated and will be removed in a later version. "+
@@ -174,6 +197,45 @@ func (m *meta) clientConfig() (*api.ClientConfig, error) {
 	return c, nil
 }
 
+// applyProfile applies the connection settings of the selected CLI profile to
+// the client configuration. The profile is selected by the profile flag, then
+// the profile environment variable, then the default profile of the CLI
+// configuration file.
+func (m *meta) applyProfile(c *api.ClientConfig) error {
+	name := os.Getenv(EnvProfile)
+	if m.isFlagParsedAndFound(FlagProfile) {
+		name = *m.profile
+	}
+
+	path, err := cliConfigPath()
+	if err != nil {
+		if name == "" {
+			return nil
+		}
+		return err
+	}
+
+	conf, err := loadCLIConfig(path)
+	if err != nil {
+		return err
+	}
+	if conf == nil {
+		if name == "" {
+			return nil
+		}
+		return fmt.Errorf("unable to use profile %q: CLI configuration file "+
+			"%q does not exist", name, path)
+	}
+
+	p, err := conf.profile(name)
+	if err != nil || p == nil {
+		return err
+	}
+
+	p.apply(c)
+	return nil
+}
+
 func (m *meta) client() (*api.Client, error) {
 	clientConfig, err := m.clientConfig()
 	if err != nil {
@@ -281,5 +343,7 @@ func (m *meta) autoCompleteFlags() complete.Flags {
 		fmt.Sprintf("-%s", FlagClientCert): complete.PredictFiles("*"),
 		fmt.Sprintf("-%s", FlagClientKey):  complete.PredictFiles("*"),
 		fmt.Sprintf("-%s", FlagSSLVerify):  complete.PredictNothing,
+		fmt.Sprintf("-%s", FlagProfile):    complete.PredictAnything,
+		fmt.Sprintf("-%s", FlagOutput):     complete.PredictSet(OutputTable, OutputJSON),
 	}
 }
@@ -0,0 +1,577 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/mitchellh/go-wordwrap"
+	"github.com/posener/complete"
+)
+
+const (
+	cmdMigrateConsulTemplateName = "migrate consul-template"
+
+	flagIn = "in"
+)
+
+var (
+	// reTemplateAction matches the actions of a consul-template template
+	reTemplateAction = regexp.MustCompile(`(?s){{-?(.*?)-?}}`)
+
+	// reTemplateDependency matches the consul-template functions that query a
+	// dependency and the function's first argument if it is a string literal
+	reTemplateDependency = regexp.MustCompile(`(?:^|[\s(|])(services|service|` +
+		`connect|keyOrDefault|keyExists|key|safeLs|ls|safeTree|tree|secrets|` +
+		`secret|nodes|node|file|pkiCert|caRoots|caLeaf)\b` +
+		"(?:\\s+(?:\"([^\"]*)\"|`([^`]*)`))?")
+
+	// reInvalidTaskNameChars matches the characters that are not allowed in a
+	// task name
+	reInvalidTaskNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+)
+
+// migrateConsulTemplateCommand handles the `migrate consul-template` command
+type migrateConsulTemplateCommand struct {
+	meta
+	in    *string
+	flags *flag.FlagSet
+}
+
+func newMigrateConsulTemplateCommand(m meta) *migrateConsulTemplateCommand {
+	logging.DisableLogging()
+	flags := flag.NewFlagSet(cmdMigrateConsulTemplateName, flag.ContinueOnError)
+	flags.SetOutput(m.writer)
+
+	in := flags.String(flagIn, "", "[Required] The path to the consul-template "+
+		"configuration file to migrate. \n\t\tRelative template source paths are "+
+		"resolved from the directory of the file.")
+
+	m.outputFlag(flags)
+	m.flags = flags
+	return &migrateConsulTemplateCommand{
+		meta:  m,
+		in:    in,
+		flags: flags,
+	}
+}
+
+// Name returns the subcommand
+func (c *migrateConsulTemplateCommand) Name() string {
+	return cmdMigrateConsulTemplateName
+}
+
+// Help returns the command's usage, list of flags, and examples
+func (c *migrateConsulTemplateCommand) Help() string {
+	c.meta.setHelpOptions()
+	helpText := fmt.Sprintf(`
+Usage: consul-terraform-sync migrate consul-template [-help] [options]
+
+  Migrate Consul-Template converts the template blocks of a consul-template
+  configuration file into Consul-Terraform-Sync task configuration. The
+  Consul services and KV paths that each template queries are converted into
+  the task's condition and module input blocks. Dependencies that CTS does not
+  support, such as Vault secrets, are listed as comments for review.
+
+  The generated configuration is written to stdout. Each task uses a
+  placeholder module that needs to be replaced with a Terraform module that
+  acts on the rendered variables, e.g. one generated with the 'module
+  scaffold' command.
+
+Options:
+%s
+
+Example:
+
+  $ consul-terraform-sync migrate consul-template -in=ctmpl-config.hcl > tasks.hcl
+`, strings.Join(c.meta.helpOptions, "\n"))
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis is a short one-line synopsis of the command
+func (c *migrateConsulTemplateCommand) Synopsis() string {
+	return "Converts consul-template templates into task configuration."
+}
+
+// AutocompleteFlags returns a mapping of supported flags and autocomplete
+// options for this command. The map key for the Flags map should be the
+// complete flag such as "-foo" or "--foo".
+func (c *migrateConsulTemplateCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		fmt.Sprintf("-%s", flagIn):     complete.PredictFiles("*"),
+		fmt.Sprintf("-%s", FlagOutput): complete.PredictSet(OutputTable, OutputJSON),
+	}
+}
+
+// AutocompleteArgs returns the argument predictor for this command.
+// Since argument completion is not supported, this will return
+// complete.PredictNothing.
+func (c *migrateConsulTemplateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+// Run runs the command
+func (c *migrateConsulTemplateCommand) Run(args []string) int {
+	c.flags.Usage = func() { c.meta.UI.Output(c.Help()) }
+	if err := c.flags.Parse(args); err != nil {
+		return ExitCodeParseFlagsError
+	}
+	if !c.meta.setupOutput() {
+		return ExitCodeRequiredFlagsError
+	}
+
+	if *c.in == "" {
+		c.UI.Error(fmt.Sprintf("Error: the -%s flag is required", flagIn))
+		c.UI.Output(fmt.Sprintf("For additional help try 'consul-terraform-sync %s --help'",
+			c.Name()))
+		return ExitCodeRequiredFlagsError
+	}
+
+	m, err := migrateConsulTemplate(*c.in)
+	if err != nil {
+		c.UI.Error("Error: unable to migrate consul-template configuration")
+		msg := wordwrap.WrapString(err.Error(), width)
+		c.UI.Output(msg)
+		return ExitCodeError
+	}
+
+	if c.outputJSON() {
+		out := migrateConsulTemplateResult{
+			Config: m.render(),
+			Tasks:  []migrateConsulTemplateTask{},
+		}
+		for _, t := range m.tasks {
+			out.Tasks = append(out.Tasks, migrateConsulTemplateTask{
+				Name:  t.name,
+				Notes: append([]string{}, t.notes...),
+			})
+		}
+		return c.writeJSON(out)
+	}
+
+	fmt.Fprint(c.meta.writer, m.render())
+	return ExitCodeOK
+}
+
+// migrateConsulTemplateResult is the machine-readable result of the command.
+// Config is the rendered CTS configuration.
+type migrateConsulTemplateResult struct {
+	Config string                      `json:"config"`
+	Tasks  []migrateConsulTemplateTask `json:"tasks"`
+}
+
+// migrateConsulTemplateTask is a migrated task and the notes for the parts of
+// the template that require manual migration
+type migrateConsulTemplateTask struct {
+	Name  string   `json:"name"`
+	Notes []string `json:"notes"`
+}
+
+// consulTemplateConsulConfig is the subset of the consul-template consul
+// block that is migrated
+type consulTemplateConsulConfig struct {
+	Address string `hcl:"address"`
+}
+
+// consulTemplateTemplateConfig is the subset of the consul-template template
+// block that is migrated
+type consulTemplateTemplateConfig struct {
+	Source      string `hcl:"source"`
+	Destination string `hcl:"destination"`
+	Contents    string `hcl:"contents"`
+}
+
+// ctMigration is the result of migrating a consul-template configuration
+type ctMigration struct {
+	in            string
+	consulAddress string
+	tasks         []*ctMigratedTask
+}
+
+// ctMigratedTask is a task skeleton for a consul-template template
+type ctMigratedTask struct {
+	name        string
+	source      string
+	destination string
+
+	services        []string
+	datacenters     []string
+	tags            []string
+	catalogServices bool
+	kvPaths         []ctKVPath
+	notes           []string
+}
+
+type ctKVPath struct {
+	path    string
+	recurse bool
+}
+
+// migrateConsulTemplate reads the consul-template configuration file and
+// converts the templates into task skeletons
+func migrateConsulTemplate(in string) (*ctMigration, error) {
+	content, err := os.ReadFile(in)
+	if err != nil {
+		return nil, err
+	}
+
+	// Repeated blocks are decoded individually since decoding a list of
+	// blocks into a slice of structs does not keep the blocks separate
+	root, err := hcl.Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse '%s': %s", in, err)
+	}
+	list, ok := root.Node.(*ast.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("unable to parse '%s': root should be an object", in)
+	}
+
+	m := &ctMigration{in: in}
+	if consul := list.Filter("consul").Items; len(consul) > 0 {
+		var c consulTemplateConsulConfig
+		if err := hcl.DecodeObject(&c, consul[0].Val); err != nil {
+			return nil, fmt.Errorf("unable to decode consul block of '%s': %s", in, err)
+		}
+		m.consulAddress = c.Address
+	}
+
+	templates := list.Filter("template").Items
+	if len(templates) == 0 {
+		return nil, fmt.Errorf("'%s' does not contain any template blocks", in)
+	}
+
+	names := make(map[string]bool)
+	for i, item := range templates {
+		var tmpl consulTemplateTemplateConfig
+		if err := hcl.DecodeObject(&tmpl, item.Val); err != nil {
+			return nil, fmt.Errorf("unable to decode template block %d of '%s': %s",
+				i+1, in, err)
+		}
+
+		task := &ctMigratedTask{
+			name:        uniqueTaskName(taskNameFromTemplate(tmpl, i), names),
+			source:      tmpl.Source,
+			destination: tmpl.Destination,
+		}
+
+		contents := tmpl.Contents
+		if contents == "" && tmpl.Source != "" {
+			path := tmpl.Source
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(filepath.Dir(in), path)
+			}
+			b, err := os.ReadFile(path)
+			if err != nil {
+				task.notes = append(task.notes, fmt.Sprintf("unable to read the "+
+					"template source, the template was not migrated: %s", err))
+				m.tasks = append(m.tasks, task)
+				continue
+			}
+			contents = string(b)
+		}
+
+		task.parse(contents)
+		m.tasks = append(m.tasks, task)
+	}
+
+	return m, nil
+}
+
+// parse finds the dependencies that the template contents query
+func (t *ctMigratedTask) parse(contents string) {
+	for _, action := range reTemplateAction.FindAllStringSubmatch(contents, -1) {
+		for _, match := range reTemplateDependency.FindAllStringSubmatch(action[1], -1) {
+			fn := match[1]
+			arg := match[2]
+			if match[3] != "" {
+				arg = match[3]
+			}
+			literal := arg != ""
+
+			switch fn {
+			case "services":
+				t.catalogServices = true
+			case "service", "connect":
+				if !literal {
+					t.addNote(fmt.Sprintf("'%s' is called with a dynamic "+
+						"argument and was not migrated", fn))
+					continue
+				}
+				t.addService(arg)
+			case "key", "keyOrDefault", "keyExists", "ls", "safeLs", "tree", "safeTree":
+				if !literal {
+					t.addNote(fmt.Sprintf("'%s' is called with a dynamic "+
+						"argument and was not migrated", fn))
+					continue
+				}
+				recurse := fn != "key" && fn != "keyOrDefault" && fn != "keyExists"
+				t.addKVPath(arg, recurse)
+			default:
+				note := fmt.Sprintf("'%s' is not supported by CTS and was not migrated", fn)
+				if literal {
+					note = fmt.Sprintf("'%s %q' is not supported by CTS and was not "+
+						"migrated", fn, arg)
+				}
+				t.addNote(note)
+			}
+		}
+	}
+}
+
+// addService adds the service of a consul-template service query in the
+// format [tag.]name[@datacenter][~near]
+func (t *ctMigratedTask) addService(query string) {
+	query = strings.SplitN(query, "~", 2)[0]
+
+	var dc string
+	if parts := strings.SplitN(query, "@", 2); len(parts) == 2 {
+		query, dc = parts[0], parts[1]
+	}
+
+	name := query
+	if i := strings.LastIndex(query, "."); i >= 0 {
+		name = query[i+1:]
+		t.tags = appendUnique(t.tags, query[:i])
+	}
+
+	t.services = appendUnique(t.services, name)
+	if dc != "" {
+		t.datacenters = appendUnique(t.datacenters, dc)
+	}
+}
+
+func (t *ctMigratedTask) addKVPath(path string, recurse bool) {
+	for i, p := range t.kvPaths {
+		if p.path == path {
+			t.kvPaths[i].recurse = p.recurse || recurse
+			return
+		}
+	}
+	t.kvPaths = append(t.kvPaths, ctKVPath{path: path, recurse: recurse})
+}
+
+func (t *ctMigratedTask) addNote(note string) {
+	t.notes = appendUnique(t.notes, note)
+}
+
+// kvInput returns the Consul KV path to monitor for the task. Multiple paths
+// are combined into their longest common prefix, which is monitored
+// recursively.
+func (t *ctMigratedTask) kvInput() (ctKVPath, bool) {
+	switch len(t.kvPaths) {
+	case 0:
+		return ctKVPath{}, false
+	case 1:
+		return t.kvPaths[0], true
+	}
+
+	prefix := strings.Split(strings.Trim(t.kvPaths[0].path, "/"), "/")
+	for _, p := range t.kvPaths[1:] {
+		parts := strings.Split(strings.Trim(p.path, "/"), "/")
+		n := 0
+		for n < len(prefix) && n < len(parts) && prefix[n] == parts[n] {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+
+	if len(prefix) == 0 {
+		for _, p := range t.kvPaths[1:] {
+			t.addNote(fmt.Sprintf("Consul KV path %q has no common prefix with "+
+				"%q and was not migrated", p.path, t.kvPaths[0].path))
+		}
+		return t.kvPaths[0], true
+	}
+
+	return ctKVPath{path: strings.Join(prefix, "/"), recurse: true}, true
+}
+
+// render returns the CTS configuration for the migrated templates
+func (m *ctMigration) render() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# Generated by 'consul-terraform-sync %s' from '%s'.\n",
+		cmdMigrateConsulTemplateName, m.in)
+	fmt.Fprintln(&b, "# Review each task and replace the placeholder module with "+
+		"a Terraform module\n# that acts on the task's variables.")
+
+	if m.consulAddress != "" {
+		fmt.Fprintf(&b, "\nconsul {\n  address = %q\n}\n", m.consulAddress)
+	}
+
+	for _, t := range m.tasks {
+		b.WriteString("\n")
+		t.render(&b)
+	}
+
+	return b.String()
+}
+
+func (t *ctMigratedTask) render(b *bytes.Buffer) {
+	var from []string
+	if t.source != "" {
+		from = append(from, fmt.Sprintf("source %q", t.source))
+	}
+	if t.destination != "" {
+		from = append(from, fmt.Sprintf("destination %q", t.destination))
+	}
+	if len(from) == 0 {
+		from = append(from, "inline contents")
+	}
+	fmt.Fprintf(b, "# Migrated from the template with %s\n", strings.Join(from, " and "))
+
+	kv, hasKV := t.kvInput()
+	if len(t.services) > 0 && len(t.datacenters) > 1 {
+		t.addNote(fmt.Sprintf("services are queried in multiple datacenters "+
+			"%s, only %q was migrated", strings.Join(t.datacenters, ", "),
+			t.datacenters[0]))
+	}
+	if len(t.tags) > 0 {
+		t.addNote(fmt.Sprintf("services are filtered by the tags %s, configure "+
+			"the condition's 'filter' option to filter by tag", strings.Join(t.tags, ", ")))
+	}
+
+	for _, note := range t.notes {
+		fmt.Fprintf(b, "# NOTE: %s\n", note)
+	}
+
+	var condition, moduleInputs []string
+	var scaffoldArgs []string
+	switch {
+	case len(t.services) > 0:
+		condition = servicesBlock("condition", t.services, t.datacenters)
+		scaffoldArgs = append(scaffoldArgs, "-condition=services")
+		if t.catalogServices {
+			moduleInputs = append(moduleInputs, catalogServicesBlock("module_input")...)
+			scaffoldArgs = append(scaffoldArgs, "-module-input=catalog-services")
+		}
+		if hasKV {
+			moduleInputs = append(moduleInputs, kvBlock("module_input", kv)...)
+			scaffoldArgs = append(scaffoldArgs, "-module-input=consul-kv")
+		}
+	case t.catalogServices:
+		condition = catalogServicesBlock("condition")
+		scaffoldArgs = append(scaffoldArgs, "-condition=catalog-services")
+		if hasKV {
+			moduleInputs = append(moduleInputs, kvBlock("module_input", kv)...)
+			scaffoldArgs = append(scaffoldArgs, "-module-input=consul-kv")
+		}
+	case hasKV:
+		condition = kvBlock("condition", kv)
+		scaffoldArgs = append(scaffoldArgs, "-condition=consul-kv")
+	default:
+		fmt.Fprintf(b, "# The template does not query any Consul services or KV "+
+			"paths supported by CTS.\n# No task was generated for %q.\n", t.name)
+		return
+	}
+
+	module := fmt.Sprintf("./modules/%s", t.name)
+	fmt.Fprintf(b, "task {\n")
+	fmt.Fprintf(b, "  name        = %q\n", t.name)
+	fmt.Fprintf(b, "  description = %q\n", fmt.Sprintf("Migrated from "+
+		"consul-template template %q", t.destination))
+	fmt.Fprintf(b, "  # Placeholder module, generate a starter module with:\n"+
+		"  # consul-terraform-sync module scaffold %s -path=%s\n",
+		strings.Join(scaffoldArgs, " "), module)
+	fmt.Fprintf(b, "  module      = %q\n", module)
+
+	for _, block := range [][]string{condition, moduleInputs} {
+		if len(block) == 0 {
+			continue
+		}
+		b.WriteString("\n")
+		for _, line := range block {
+			fmt.Fprintf(b, "  %s\n", line)
+		}
+	}
+	b.WriteString("}\n")
+}
+
+func servicesBlock(kind string, names, datacenters []string) []string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = fmt.Sprintf("%q", n)
+	}
+
+	if len(datacenters) == 0 {
+		return []string{
+			fmt.Sprintf("%s \"services\" {", kind),
+			fmt.Sprintf("  names = [%s]", strings.Join(quoted, ", ")),
+			"}",
+		}
+	}
+
+	return []string{
+		fmt.Sprintf("%s \"services\" {", kind),
+		fmt.Sprintf("  names      = [%s]", strings.Join(quoted, ", ")),
+		fmt.Sprintf("  datacenter = %q", datacenters[0]),
+		"}",
+	}
+}
+
+func catalogServicesBlock(kind string) []string {
+	return []string{
+		fmt.Sprintf("%s \"catalog-services\" {", kind),
+		`  regexp = ".*"`,
+		"}",
+	}
+}
+
+func kvBlock(kind string, kv ctKVPath) []string {
+	return []string{
+		fmt.Sprintf("%s \"consul-kv\" {", kind),
+		fmt.Sprintf("  path    = %q", kv.path),
+		fmt.Sprintf("  recurse = %t", kv.recurse),
+		"}",
+	}
+}
+
+// taskNameFromTemplate derives a valid task name from the template's
+// destination, or source, file name
+func taskNameFromTemplate(tmpl consulTemplateTemplateConfig, i int) string {
+	file := tmpl.Destination
+	if file == "" {
+		file = tmpl.Source
+	}
+
+	base := filepath.Base(file)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	name := strings.Trim(reInvalidTaskNameChars.ReplaceAllString(base, "_"), "_-")
+	if file == "" || name == "" {
+		return fmt.Sprintf("template_%d", i+1)
+	}
+
+	if c := name[0]; c >= '0' && c <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// uniqueTaskName returns the name with a numeric suffix if the name is
+// already used
+func uniqueTaskName(name string, used map[string]bool) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+func appendUnique(s []string, v string) []string {
+	for _, e := range s {
+		if e == v {
+			return s
+		}
+	}
+	return append(s, v)
+}
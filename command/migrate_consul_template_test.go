@@ -0,0 +1,300 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateConsulTemplateCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	in := filepath.Join(dir, "config.hcl")
+	err := os.WriteFile(in, []byte(`
+template {
+  contents    = "{{ range service \"web\" }}{{ .Address }}{{ end }}"
+  destination = "/etc/web.conf"
+}
+`), 0644)
+	require.NoError(t, err)
+
+	empty := filepath.Join(dir, "empty.hcl")
+	require.NoError(t, os.WriteFile(empty, []byte(`consul {}`), 0644))
+
+	cases := []struct {
+		name           string
+		args           []string
+		expectedStatus int
+		expectedOutput string
+	}{
+		{
+			name:           "happy path",
+			args:           []string{"-in", in},
+			expectedStatus: ExitCodeOK,
+			expectedOutput: `condition "services"`,
+		},
+		{
+			name:           "missing in flag",
+			args:           []string{},
+			expectedStatus: ExitCodeRequiredFlagsError,
+			expectedOutput: "-in flag is required",
+		},
+		{
+			name:           "file does not exist",
+			args:           []string{"-in", filepath.Join(dir, "missing.hcl")},
+			expectedStatus: ExitCodeError,
+			expectedOutput: "no such file or directory",
+		},
+		{
+			name:           "no templates",
+			args:           []string{"-in", empty},
+			expectedStatus: ExitCodeError,
+			expectedOutput: "does not contain any template blocks",
+		},
+		{
+			name:           "unsupported flag",
+			args:           []string{"-foo", "bar"},
+			expectedStatus: ExitCodeParseFlagsError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var b bytes.Buffer
+			ui := cli.NewMockUi()
+			cmd := newMigrateConsulTemplateCommand(meta{UI: ui, writer: &b})
+
+			status := cmd.Run(tc.args)
+			assert.Equal(t, tc.expectedStatus, status)
+
+			// errors are wrapped to the width of the terminal
+			output := b.String() + ui.OutputWriter.String() + ui.ErrorWriter.String()
+			assert.Contains(t, strings.ReplaceAll(output, "\n", " "),
+				strings.ReplaceAll(tc.expectedOutput, "\n", " "))
+		})
+	}
+}
+
+func TestMigrateConsulTemplateCommand_Run_JSON(t *testing.T) {
+	t.Parallel()
+
+	in := filepath.Join(t.TempDir(), "config.hcl")
+	err := os.WriteFile(in, []byte(`
+template {
+  contents    = "{{ range service \"web\" }}{{ .Address }}{{ end }}"
+  destination = "/etc/web.conf"
+}
+`), 0644)
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	ui := cli.NewMockUi()
+	cmd := newMigrateConsulTemplateCommand(meta{UI: ui, writer: &b})
+
+	status := cmd.Run([]string{"-in", in, "-output", "json"})
+	assert.Equal(t, ExitCodeOK, status)
+
+	var result migrateConsulTemplateResult
+	require.NoError(t, json.Unmarshal(b.Bytes(), &result))
+	assert.Contains(t, result.Config, `condition "services"`)
+	require.Len(t, result.Tasks, 1)
+	assert.NotEmpty(t, result.Tasks[0].Name)
+}
+
+func TestMigrateConsulTemplate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "app.ctmpl"), []byte(`
+{{ range service "api@dc2" }}{{ .Address }}{{ end }}
+{{ range service "primary.db" }}{{ .Address }}{{ end }}
+{{ key "app/config/port" }}
+{{ keyOrDefault "app/config/host" "localhost" }}
+{{ with secret "secret/data/app" }}{{ .Data.password }}{{ end }}
+`), 0644)
+	require.NoError(t, err)
+
+	in := filepath.Join(dir, "config.hcl")
+	err = os.WriteFile(in, []byte(`
+consul {
+  address = "consul.example.com:8500"
+}
+
+template {
+  source      = "app.ctmpl"
+  destination = "/etc/app/app.conf"
+}
+
+template {
+  contents    = "{{ range tree \"lb/\" }}{{ .Key }}{{ end }}"
+  destination = "/etc/lb/app.conf"
+}
+
+template {
+  contents    = "{{ range services }}{{ .Name }}{{ end }}"
+  destination = "/etc/catalog.json"
+}
+
+template {
+  contents    = "{{ file \"/etc/hosts\" }}"
+  destination = "/etc/hosts.out"
+}
+
+template {
+  source      = "missing.ctmpl"
+  destination = "/etc/missing.conf"
+}
+`), 0644)
+	require.NoError(t, err)
+
+	m, err := migrateConsulTemplate(in)
+	require.NoError(t, err)
+	assert.Equal(t, "consul.example.com:8500", m.consulAddress)
+	require.Len(t, m.tasks, 5)
+
+	app := m.tasks[0]
+	assert.Equal(t, "app", app.name)
+	assert.Equal(t, []string{"api", "db"}, app.services)
+	assert.Equal(t, []string{"dc2"}, app.datacenters)
+	assert.Equal(t, []string{"primary"}, app.tags)
+	assert.Equal(t, []ctKVPath{
+		{path: "app/config/port"},
+		{path: "app/config/host"},
+	}, app.kvPaths)
+	assert.Equal(t, []string{`'secret "secret/data/app"' is not supported by ` +
+		`CTS and was not migrated`}, app.notes)
+
+	assert.Equal(t, "app_2", m.tasks[1].name)
+	assert.Equal(t, []ctKVPath{{path: "lb/", recurse: true}}, m.tasks[1].kvPaths)
+	assert.True(t, m.tasks[2].catalogServices)
+	assert.Len(t, m.tasks[3].notes, 1)
+	assert.Len(t, m.tasks[4].notes, 1)
+
+	out := m.render()
+	assert.Contains(t, out, "consul {\n  address = \"consul.example.com:8500\"\n}")
+	assert.Contains(t, out, `task {
+  name        = "app"
+  description = "Migrated from consul-template template \"/etc/app/app.conf\""
+  # Placeholder module, generate a starter module with:
+  # consul-terraform-sync module scaffold -condition=services -module-input=consul-kv -path=./modules/app
+  module      = "./modules/app"
+
+  condition "services" {
+    names      = ["api", "db"]
+    datacenter = "dc2"
+  }
+
+  module_input "consul-kv" {
+    path    = "app/config"
+    recurse = true
+  }
+}`)
+	assert.Contains(t, out, "# NOTE: services are filtered by the tags primary")
+	assert.Contains(t, out, `condition "consul-kv" {
+    path    = "lb/"
+    recurse = true
+  }`)
+	assert.Contains(t, out, `condition "catalog-services" {
+    regexp = ".*"
+  }`)
+	assert.Contains(t, out, `No task was generated for "hosts"`)
+	assert.Contains(t, out, `No task was generated for "missing"`)
+}
+
+func TestCTMigratedTask_kvInput(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		paths    []ctKVPath
+		expected ctKVPath
+		ok       bool
+		notes    int
+	}{
+		{
+			name: "none",
+		},
+		{
+			name:     "single",
+			paths:    []ctKVPath{{path: "a/b"}},
+			expected: ctKVPath{path: "a/b"},
+			ok:       true,
+		},
+		{
+			name:     "common prefix",
+			paths:    []ctKVPath{{path: "a/b/c"}, {path: "a/b/d", recurse: true}},
+			expected: ctKVPath{path: "a/b", recurse: true},
+			ok:       true,
+		},
+		{
+			name:     "no common prefix",
+			paths:    []ctKVPath{{path: "a/b"}, {path: "c/d"}},
+			expected: ctKVPath{path: "a/b"},
+			ok:       true,
+			notes:    1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			task := &ctMigratedTask{kvPaths: tc.paths}
+			kv, ok := task.kvInput()
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, kv)
+			assert.Len(t, task.notes, tc.notes)
+		})
+	}
+}
+
+func TestTaskNameFromTemplate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		tmpl     consulTemplateTemplateConfig
+		expected string
+	}{
+		{
+			"destination",
+			consulTemplateTemplateConfig{Destination: "/etc/nginx/nginx.conf"},
+			"nginx",
+		},
+		{
+			"source",
+			consulTemplateTemplateConfig{Source: "templates/web.ctmpl"},
+			"web",
+		},
+		{
+			"invalid characters",
+			consulTemplateTemplateConfig{Destination: "/tmp/my app.v2.conf"},
+			"my_app_v2",
+		},
+		{
+			"leading digit",
+			consulTemplateTemplateConfig{Destination: "/tmp/1-app.conf"},
+			"_1-app",
+		},
+		{
+			"no file",
+			consulTemplateTemplateConfig{Contents: "{{ key \"a\" }}"},
+			"template_1",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, taskNameFromTemplate(tc.tmpl, 0))
+		})
+	}
+}
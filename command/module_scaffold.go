@@ -0,0 +1,170 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl"
+	"github.com/mitchellh/go-wordwrap"
+	"github.com/posener/complete"
+)
+
+const (
+	cmdModuleScaffoldName = "module scaffold"
+
+	flagCondition        = "condition"
+	flagModuleInput      = "module-input"
+	flagPath             = "path"
+	flagExtendedMetadata = "include-extended-metadata"
+)
+
+var (
+	// moduleInputTypes are the types of module inputs that a module can be
+	// generated for
+	moduleInputTypes = []string{"services", "catalog-services", "consul-kv",
+		"dns", "file"}
+
+	// conditionTypes are the types of conditions that a module can be
+	// generated for
+	conditionTypes = append(moduleInputTypes, "schedule")
+)
+
+// moduleScaffoldCommand handles the `module scaffold` command
+type moduleScaffoldCommand struct {
+	meta
+	condition        *string
+	moduleInputs     *config.FlagAppendSliceValue
+	path             *string
+	extendedMetadata *bool
+	flags            *flag.FlagSet
+}
+
+// moduleScaffoldResult is the machine-readable result of the command
+type moduleScaffoldResult struct {
+	Path         string   `json:"path"`
+	Condition    string   `json:"condition"`
+	ModuleInputs []string `json:"module_inputs"`
+}
+
+func newModuleScaffoldCommand(m meta) *moduleScaffoldCommand {
+	logging.DisableLogging()
+	flags := flag.NewFlagSet(cmdModuleScaffoldName, flag.ContinueOnError)
+	flags.SetOutput(m.writer)
+
+	var moduleInputs config.FlagAppendSliceValue
+	c := flags.String(flagCondition, "services", fmt.Sprintf("The type of the task "+
+		"condition for the module. Supported types are: \n\t\t%s",
+		strings.Join(conditionTypes, ", ")))
+	flags.Var(&moduleInputs, flagModuleInput, "The type of a module input for the "+
+		"module. This option can be specified \n\t\tmultiple times for multiple "+
+		"module inputs.")
+	p := flags.String(flagPath, ".", "The directory to generate the module in. "+
+		"\n\t\tThe directory is created if it does not exist.")
+	e := flags.Bool(flagExtendedMetadata, false, "Include the extended service "+
+		"metadata in the services variable.")
+
+	m.outputFlag(flags)
+	m.flags = flags
+	return &moduleScaffoldCommand{
+		meta:             m,
+		condition:        c,
+		moduleInputs:     &moduleInputs,
+		path:             p,
+		extendedMetadata: e,
+		flags:            flags,
+	}
+}
+
+// Name returns the subcommand
+func (c moduleScaffoldCommand) Name() string {
+	return cmdModuleScaffoldName
+}
+
+// Help returns the command's usage, list of flags, and examples
+func (c *moduleScaffoldCommand) Help() string {
+	c.meta.setHelpOptions()
+	helpText := fmt.Sprintf(`
+Usage: consul-terraform-sync module scaffold [-help] [options]
+
+  Module Scaffold generates a starter Terraform module for a task. The module
+  includes the variable definitions that match the variables rendered by
+  Consul-Terraform-Sync for the task's condition and module inputs. Existing
+  files are not overwritten.
+
+Options:
+%s
+
+Example:
+
+  $ consul-terraform-sync module scaffold -condition=services -module-input=consul-kv -path=./my-module
+  ==> Module generated at './my-module'
+`, strings.Join(c.meta.helpOptions, "\n"))
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis is a short one-line synopsis of the command
+func (c *moduleScaffoldCommand) Synopsis() string {
+	return "Generates a starter module for a task."
+}
+
+// AutocompleteFlags returns a mapping of supported flags and autocomplete
+// options for this command. The map key for the Flags map should be the
+// complete flag such as "-foo" or "--foo".
+func (c *moduleScaffoldCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		fmt.Sprintf("-%s", flagCondition):        complete.PredictSet(conditionTypes...),
+		fmt.Sprintf("-%s", flagModuleInput):      complete.PredictSet(moduleInputTypes...),
+		fmt.Sprintf("-%s", flagPath):             complete.PredictDirs("*"),
+		fmt.Sprintf("-%s", flagExtendedMetadata): complete.PredictNothing,
+		fmt.Sprintf("-%s", FlagOutput):           complete.PredictSet(OutputTable, OutputJSON),
+	}
+}
+
+// AutocompleteArgs returns the argument predictor for this command.
+// Since argument completion is not supported, this will return
+// complete.PredictNothing.
+func (c *moduleScaffoldCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+// Run runs the command
+func (c *moduleScaffoldCommand) Run(args []string) int {
+	c.flags.Usage = func() { c.meta.UI.Output(c.Help()) }
+	if err := c.flags.Parse(args); err != nil {
+		return ExitCodeParseFlagsError
+	}
+	if !c.meta.setupOutput() {
+		return ExitCodeRequiredFlagsError
+	}
+
+	err := tftmpl.ScaffoldModule(tftmpl.ModuleScaffoldInput{
+		Path:             *c.path,
+		Condition:        *c.condition,
+		ModuleInputs:     *c.moduleInputs,
+		ExtendedMetadata: *c.extendedMetadata,
+	})
+	if err != nil {
+		c.UI.Error("Error: unable to generate module")
+		msg := wordwrap.WrapString(err.Error(), width)
+		c.UI.Output(msg)
+
+		return ExitCodeError
+	}
+
+	if c.outputJSON() {
+		return c.writeJSON(moduleScaffoldResult{
+			Path:         *c.path,
+			Condition:    *c.condition,
+			ModuleInputs: append([]string{}, *c.moduleInputs...),
+		})
+	}
+
+	c.UI.Info(fmt.Sprintf("Module generated at '%s'", *c.path))
+	return ExitCodeOK
+}
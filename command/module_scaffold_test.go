@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleScaffoldCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name           string
+		args           func(dir string) []string
+		expectedStatus int
+		expectedOutput string
+	}{
+		{
+			name: "services condition",
+			args: func(dir string) []string {
+				return []string{"-path", dir}
+			},
+			expectedStatus: ExitCodeOK,
+			expectedOutput: "Module generated at",
+		},
+		{
+			name: "condition and module inputs",
+			args: func(dir string) []string {
+				return []string{"-condition", "schedule", "-module-input",
+					"consul-kv", "-module-input", "catalog-services", "-path", dir}
+			},
+			expectedStatus: ExitCodeOK,
+			expectedOutput: "Module generated at",
+		},
+		{
+			name: "unsupported condition",
+			args: func(dir string) []string {
+				return []string{"-condition", "nodes", "-path", dir}
+			},
+			expectedStatus: ExitCodeError,
+			expectedOutput: "unsupported type",
+		},
+		{
+			name: "unsupported flag",
+			args: func(dir string) []string {
+				return []string{"-foo", "bar"}
+			},
+			expectedStatus: ExitCodeParseFlagsError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "my-module")
+			ui := cli.NewMockUi()
+			cmd := newModuleScaffoldCommand(meta{UI: ui})
+
+			status := cmd.Run(tc.args(dir))
+			assert.Equal(t, tc.expectedStatus, status)
+
+			output := ui.OutputWriter.String() + ui.ErrorWriter.String()
+			assert.Contains(t, output, tc.expectedOutput)
+
+			if tc.expectedStatus == ExitCodeOK {
+				content, err := os.ReadFile(filepath.Join(dir, "variables.tf"))
+				require.NoError(t, err)
+				assert.Contains(t, string(content), `variable "services"`)
+			}
+		})
+	}
+}
+
+func TestModuleScaffoldCommand_Run_JSON(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "my-module")
+	var b bytes.Buffer
+	ui := cli.NewMockUi()
+	cmd := newModuleScaffoldCommand(meta{UI: ui, writer: &b})
+
+	status := cmd.Run([]string{"-module-input", "consul-kv", "-path", dir,
+		"-output", "json"})
+	assert.Equal(t, ExitCodeOK, status)
+	assert.Empty(t, ui.OutputWriter.String())
+
+	var result moduleScaffoldResult
+	require.NoError(t, json.Unmarshal(b.Bytes(), &result))
+	assert.Equal(t, moduleScaffoldResult{
+		Path:         dir,
+		Condition:    "services",
+		ModuleInputs: []string{"consul-kv"},
+	}, result)
+}
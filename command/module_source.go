@@ -0,0 +1,254 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-getter"
+)
+
+const (
+	// defaultRegistryHost is the host of registry module sources that do not
+	// include a hostname
+	defaultRegistryHost = "registry.terraform.io"
+
+	// registryDiscoveryPath is the path of the service discovery document of
+	// a Terraform registry
+	registryDiscoveryPath = "/.well-known/terraform.json"
+
+	registryModulesService = "modules.v1"
+	registryDownloadHeader = "X-Terraform-Get"
+
+	// registryRequestTimeout is the timeout of requests to a registry
+	registryRequestTimeout = 30 * time.Second
+)
+
+// registrySourceRe matches Terraform registry module sources of the format
+// [<hostname>/]<namespace>/<name>/<provider>
+var registrySourceRe = regexp.MustCompile(
+	`^(?:([0-9A-Za-z\-]+(?:\.[0-9A-Za-z\-]+)+(?::[0-9]+)?)/)?` +
+		`([0-9A-Za-z][0-9A-Za-z\-_]*)/([0-9A-Za-z][0-9A-Za-z\-_]*)/([0-9a-z]+)$`)
+
+// vcsHosts are hosts of version control systems that are detected as git or
+// mercurial sources instead of registry sources
+var vcsHosts = map[string]bool{
+	"github.com":    true,
+	"bitbucket.org": true,
+}
+
+// registrySource is a module source of a Terraform registry
+type registrySource struct {
+	host      string
+	namespace string
+	name      string
+	provider  string
+}
+
+// parseRegistrySource parses a Terraform registry module source. Returns
+// false if the source is not a registry source.
+func parseRegistrySource(source string) (registrySource, bool) {
+	m := registrySourceRe.FindStringSubmatch(source)
+	if m == nil {
+		return registrySource{}, false
+	}
+
+	host := m[1]
+	if vcsHosts[strings.ToLower(host)] {
+		return registrySource{}, false
+	}
+	if host == "" {
+		host = defaultRegistryHost
+	}
+	return registrySource{
+		host:      host,
+		namespace: m[2],
+		name:      m[3],
+		provider:  m[4],
+	}, true
+}
+
+// isLocalModuleSource returns true if the source is a path to a module on
+// the local file system
+func isLocalModuleSource(source string) bool {
+	for _, prefix := range []string{"./", "../", "/"} {
+		if strings.HasPrefix(source, prefix) {
+			return true
+		}
+	}
+	info, err := os.Stat(source)
+	return err == nil && info.IsDir()
+}
+
+// fetchModule retrieves the module from the source and returns the
+// directory of the module. Local modules are not copied. Remote modules are
+// downloaded to a directory within dst. Registry sources are resolved to the
+// download location of the module version, or the latest version if no
+// version is specified.
+func fetchModule(ctx context.Context, client *http.Client, source, version,
+	dst string) (string, error) {
+
+	if isLocalModuleSource(source) {
+		if version != "" {
+			return "", fmt.Errorf("version is only supported for registry " +
+				"module sources")
+		}
+		return source, nil
+	}
+
+	src := source
+	base, subdir := getter.SourceDirSubdir(source)
+	if rs, ok := parseRegistrySource(base); ok {
+		var err error
+		src, err = registryDownloadURL(ctx, client, rs, version)
+		if err != nil {
+			return "", err
+		}
+		if subdir != "" {
+			src = fmt.Sprintf("%s//%s", src, subdir)
+		}
+	} else if version != "" {
+		return "", fmt.Errorf("version is only supported for registry " +
+			"module sources")
+	}
+
+	pwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(dst, "module")
+	gc := &getter.Client{
+		Ctx:  ctx,
+		Src:  src,
+		Dst:  dir,
+		Pwd:  pwd,
+		Mode: getter.ClientModeDir,
+	}
+	if err := gc.Get(); err != nil {
+		return "", fmt.Errorf("unable to download module from %q: %s",
+			source, err)
+	}
+	return dir, nil
+}
+
+// registryDownloadURL returns the location to download the module version
+// from the registry
+func registryDownloadURL(ctx context.Context, client *http.Client,
+	rs registrySource, version string) (string, error) {
+
+	modulesURL, err := registryModulesURL(ctx, client, rs.host)
+	if err != nil {
+		return "", err
+	}
+
+	path := fmt.Sprintf("%s/%s/%s", rs.namespace, rs.name, rs.provider)
+	if version != "" {
+		path = fmt.Sprintf("%s/%s", path, version)
+	}
+	downloadURL, err := modulesURL.Parse(path + "/download")
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := registryRequest(ctx, client, rs.host, downloadURL.String())
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+	case http.StatusNotFound:
+		return "", fmt.Errorf("module %q not found in registry %s", path,
+			rs.host)
+	default:
+		return "", fmt.Errorf("error retrieving module %q from registry %s: %s",
+			path, rs.host, resp.Status)
+	}
+
+	location := resp.Header.Get(registryDownloadHeader)
+	if location == "" {
+		return "", fmt.Errorf("registry %s did not return the download "+
+			"location of module %q", rs.host, path)
+	}
+
+	// relative locations are resolved against the download URL
+	if strings.HasPrefix(location, "/") || strings.HasPrefix(location, "./") ||
+		strings.HasPrefix(location, "../") {
+		u, err := downloadURL.Parse(location)
+		if err != nil {
+			return "", err
+		}
+		location = u.String()
+	}
+	return location, nil
+}
+
+// registryModulesURL discovers the base URL of the modules API of the
+// registry host
+func registryModulesURL(ctx context.Context, client *http.Client,
+	host string) (*url.URL, error) {
+
+	discoveryURL := &url.URL{Scheme: "https", Host: host, Path: registryDiscoveryPath}
+	resp, err := registryRequest(ctx, client, host, discoveryURL.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error discovering services of registry %s: %s",
+			host, resp.Status)
+	}
+
+	var services map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&services); err != nil {
+		return nil, fmt.Errorf("error decoding services of registry %s: %s",
+			host, err)
+	}
+
+	modulesPath, ok := services[registryModulesService].(string)
+	if !ok || modulesPath == "" {
+		return nil, fmt.Errorf("registry %s does not support modules", host)
+	}
+	if !strings.HasSuffix(modulesPath, "/") {
+		modulesPath += "/"
+	}
+	return discoveryURL.Parse(modulesPath)
+}
+
+// registryRequest sends a GET request to the registry. The request is
+// authenticated with the TF_TOKEN_<host> environment variable if it is set,
+// which is the same variable used by Terraform.
+func registryRequest(ctx context.Context, client *http.Client, host,
+	u string) (*http.Response, error) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token := os.Getenv(registryTokenEnv(host)); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return client.Do(req)
+}
+
+// registryTokenEnv returns the name of the environment variable of the
+// token for a registry host. Periods are replaced with underscores and
+// hyphens with double underscores.
+func registryTokenEnv(host string) string {
+	host = strings.SplitN(host, ":", 2)[0]
+	host = strings.ReplaceAll(host, "-", "__")
+	host = strings.ReplaceAll(host, ".", "_")
+	return "TF_TOKEN_" + host
+}
@@ -0,0 +1,117 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRegistrySource(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		source   string
+		expected registrySource
+		ok       bool
+	}{
+		{
+			"hashicorp/consul/aws",
+			registrySource{host: defaultRegistryHost, namespace: "hashicorp",
+				name: "consul", provider: "aws"},
+			true,
+		},
+		{
+			"app.terraform.io/my-org/cts-module/local",
+			registrySource{host: "app.terraform.io", namespace: "my-org",
+				name: "cts-module", provider: "local"},
+			true,
+		},
+		{
+			"localhost.localdomain:8443/org/name/local",
+			registrySource{host: "localhost.localdomain:8443", namespace: "org",
+				name: "name", provider: "local"},
+			true,
+		},
+		{"github.com/hashicorp/example", registrySource{}, false},
+		{"github.com/hashicorp/example/module", registrySource{}, false},
+		{"git::https://example.com/module.git", registrySource{}, false},
+		{"https://example.com/module.zip", registrySource{}, false},
+		{"./my-module", registrySource{}, false},
+		{"hashicorp/consul", registrySource{}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.source, func(t *testing.T) {
+			actual, ok := parseRegistrySource(tc.source)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestRegistryDownloadURL(t *testing.T) {
+	var authorization string
+	mux := http.NewServeMux()
+	mux.HandleFunc(registryDiscoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"%s": "/api/registry/v1/modules/"}`, registryModulesService)
+	})
+	mux.HandleFunc("/api/registry/v1/modules/org/name/local/download",
+		func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+			w.Header().Set(registryDownloadHeader, "git::https://example.com/name.git?ref=v1.1.0")
+			w.WriteHeader(http.StatusNoContent)
+		})
+	mux.HandleFunc("/api/registry/v1/modules/org/name/local/1.0.0/download",
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(registryDownloadHeader, "./archive/v1.0.0.tar.gz")
+			w.WriteHeader(http.StatusNoContent)
+		})
+	ts := httptest.NewTLSServer(mux)
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	rs := registrySource{host: u.Host, namespace: "org", name: "name", provider: "local"}
+
+	t.Run("latest version", func(t *testing.T) {
+		actual, err := registryDownloadURL(context.Background(), ts.Client(), rs, "")
+		require.NoError(t, err)
+		assert.Equal(t, "git::https://example.com/name.git?ref=v1.1.0", actual)
+	})
+
+	t.Run("relative location", func(t *testing.T) {
+		actual, err := registryDownloadURL(context.Background(), ts.Client(), rs, "1.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("%s/api/registry/v1/modules/org/name/local/"+
+			"1.0.0/archive/v1.0.0.tar.gz", ts.URL), actual)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := registryDownloadURL(context.Background(), ts.Client(), rs, "2.0.0")
+		assert.Error(t, err)
+	})
+
+	t.Run("token", func(t *testing.T) {
+		t.Setenv(registryTokenEnv(u.Host), "registry-token")
+		_, err := registryDownloadURL(context.Background(), ts.Client(), rs, "")
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer registry-token", authorization)
+	})
+}
+
+func TestRegistryTokenEnv(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "TF_TOKEN_app_terraform_io", registryTokenEnv("app.terraform.io"))
+	assert.Equal(t, "TF_TOKEN_my__registry_example_com",
+		registryTokenEnv("my-registry.example.com:8443"))
+}
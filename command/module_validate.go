@@ -0,0 +1,217 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl"
+	"github.com/mitchellh/go-wordwrap"
+	"github.com/posener/complete"
+)
+
+const (
+	cmdModuleValidateName = "module validate"
+
+	flagModuleVersion = "version"
+)
+
+// moduleValidateCommand handles the `module validate` command
+type moduleValidateCommand struct {
+	meta
+	condition        *string
+	moduleInputs     *config.FlagAppendSliceValue
+	version          *string
+	extendedMetadata *bool
+	flags            *flag.FlagSet
+}
+
+// moduleValidateResult is the machine-readable result of the command
+type moduleValidateResult struct {
+	Source     string                   `json:"source"`
+	Compatible bool                     `json:"compatible"`
+	Warnings   []string                 `json:"warnings"`
+	Mismatches []moduleValidateMismatch `json:"mismatches"`
+}
+
+// moduleValidateMismatch is an incompatibility of a module variable
+type moduleValidateMismatch struct {
+	Variable string `json:"variable"`
+	Path     string `json:"path"`
+	Message  string `json:"message"`
+}
+
+func newModuleValidateCommand(m meta) *moduleValidateCommand {
+	logging.DisableLogging()
+	flags := flag.NewFlagSet(cmdModuleValidateName, flag.ContinueOnError)
+	flags.SetOutput(m.writer)
+
+	var moduleInputs config.FlagAppendSliceValue
+	c := flags.String(flagCondition, "services", fmt.Sprintf("The type of the task "+
+		"condition for the module. Supported types are: \n\t\t%s",
+		strings.Join(conditionTypes, ", ")))
+	flags.Var(&moduleInputs, flagModuleInput, "The type of a module input for the "+
+		"module. This option can be specified \n\t\tmultiple times for multiple "+
+		"module inputs.")
+	v := flags.String(flagModuleVersion, "", "The version of a registry module. "+
+		"Defaults to the latest version.")
+	e := flags.Bool(flagExtendedMetadata, false, "Validate the module against "+
+		"the services variable with the \n\t\textended service metadata.")
+
+	m.outputFlag(flags)
+	m.flags = flags
+	return &moduleValidateCommand{
+		meta:             m,
+		condition:        c,
+		moduleInputs:     &moduleInputs,
+		version:          v,
+		extendedMetadata: e,
+		flags:            flags,
+	}
+}
+
+// Name returns the subcommand
+func (c moduleValidateCommand) Name() string {
+	return cmdModuleValidateName
+}
+
+// Help returns the command's usage, list of flags, and examples
+func (c *moduleValidateCommand) Help() string {
+	c.meta.setHelpOptions()
+	helpText := fmt.Sprintf(`
+Usage: consul-terraform-sync module validate [-help] [options] <module source>
+
+  Module Validate checks that a module declares the variables that
+  Consul-Terraform-Sync passes to the module for a task's condition and module
+  inputs, and that the variable types are compatible with the values passed.
+  The module source can be a local path, a Terraform registry module, or any
+  other source supported by Terraform modules.
+
+Options:
+%s
+
+Example:
+
+  $ consul-terraform-sync module validate -module-input=consul-kv ./my-module
+  ==> Module './my-module' is compatible with Consul-Terraform-Sync
+`, strings.Join(c.meta.helpOptions, "\n"))
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis is a short one-line synopsis of the command
+func (c *moduleValidateCommand) Synopsis() string {
+	return "Validates a module is compatible with the variables for a task."
+}
+
+// AutocompleteFlags returns a mapping of supported flags and autocomplete
+// options for this command. The map key for the Flags map should be the
+// complete flag such as "-foo" or "--foo".
+func (c *moduleValidateCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		fmt.Sprintf("-%s", flagCondition):        complete.PredictSet(conditionTypes...),
+		fmt.Sprintf("-%s", flagModuleInput):      complete.PredictSet(moduleInputTypes...),
+		fmt.Sprintf("-%s", flagModuleVersion):    complete.PredictAnything,
+		fmt.Sprintf("-%s", flagExtendedMetadata): complete.PredictNothing,
+		fmt.Sprintf("-%s", FlagOutput):           complete.PredictSet(OutputTable, OutputJSON),
+	}
+}
+
+// AutocompleteArgs returns the argument predictor for this command.
+func (c *moduleValidateCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictDirs("*")
+}
+
+// Run runs the command
+func (c *moduleValidateCommand) Run(args []string) int {
+	c.flags.Usage = func() { c.meta.UI.Output(c.Help()) }
+	if err := c.flags.Parse(args); err != nil {
+		return ExitCodeParseFlagsError
+	}
+	if !c.meta.setupOutput() {
+		return ExitCodeRequiredFlagsError
+	}
+
+	args = c.flags.Args()
+	if len(args) != 1 {
+		c.UI.Error("Error: this command requires one argument: [options] <module source>")
+		c.UI.Output(fmt.Sprintf("For additional help try 'consul-terraform-sync %s --help'",
+			c.Name()))
+		return ExitCodeRequiredFlagsError
+	}
+	source := args[0]
+
+	tmpDir, err := os.MkdirTemp("", "cts-module-")
+	if err != nil {
+		c.UI.Error("Error: unable to create a directory to download the module")
+		c.UI.Output(wordwrap.WrapString(err.Error(), width))
+		return ExitCodeError
+	}
+	defer os.RemoveAll(tmpDir)
+
+	client := &http.Client{Timeout: registryRequestTimeout}
+	dir, err := fetchModule(context.Background(), client, source, *c.version, tmpDir)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error: unable to retrieve module '%s'", source))
+		c.UI.Output(wordwrap.WrapString(err.Error(), width))
+		return ExitCodeError
+	}
+
+	result, err := tftmpl.ValidateModule(tftmpl.ModuleValidateInput{
+		Path:             dir,
+		Condition:        *c.condition,
+		ModuleInputs:     *c.moduleInputs,
+		ExtendedMetadata: *c.extendedMetadata,
+	})
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error: unable to validate module '%s'", source))
+		c.UI.Output(wordwrap.WrapString(err.Error(), width))
+		return ExitCodeError
+	}
+
+	if c.outputJSON() {
+		out := moduleValidateResult{
+			Source:     source,
+			Compatible: len(result.Mismatches) == 0,
+			Warnings:   append([]string{}, result.Warnings...),
+			Mismatches: []moduleValidateMismatch{},
+		}
+		for _, m := range result.Mismatches {
+			out.Mismatches = append(out.Mismatches, moduleValidateMismatch{
+				Variable: m.Variable,
+				Path:     m.Path,
+				Message:  m.Message,
+			})
+		}
+		if code := c.writeJSON(out); code != ExitCodeOK || out.Compatible {
+			return code
+		}
+		// An incompatible module exits with an error for scripts that only
+		// check the exit code
+		return ExitCodeError
+	}
+
+	for _, w := range result.Warnings {
+		c.UI.Warn(wordwrap.WrapString(fmt.Sprintf("Warning: %s", w), width))
+	}
+
+	if len(result.Mismatches) > 0 {
+		c.UI.Error(fmt.Sprintf("Error: module '%s' is not compatible with "+
+			"Consul-Terraform-Sync", source))
+		for _, m := range result.Mismatches {
+			c.UI.Output(fmt.Sprintf("  - %s", m))
+		}
+		return ExitCodeError
+	}
+
+	c.UI.Info(fmt.Sprintf("Module '%s' is compatible with Consul-Terraform-Sync",
+		source))
+	return ExitCodeOK
+}
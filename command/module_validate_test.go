@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleValidateCommand_Run(t *testing.T) {
+	t.Parallel()
+
+	// generate a module with the variables for services and consul-kv
+	moduleDir := filepath.Join(t.TempDir(), "my-module")
+	ui := cli.NewMockUi()
+	status := newModuleScaffoldCommand(meta{UI: ui}).Run([]string{
+		"-module-input", "consul-kv", "-path", moduleDir})
+	require.Equal(t, ExitCodeOK, status)
+
+	incompatibleDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(incompatibleDir, "variables.tf"),
+		[]byte(`
+variable "services" {
+  type = map(object({
+    port = bool
+  }))
+}`), 0644))
+
+	cases := []struct {
+		name           string
+		args           []string
+		expectedStatus int
+		expectedOutput []string
+	}{
+		{
+			name:           "compatible",
+			args:           []string{"-module-input", "consul-kv", moduleDir},
+			expectedStatus: ExitCodeOK,
+			expectedOutput: []string{"is compatible"},
+		},
+		{
+			name:           "missing variable",
+			args:           []string{"-module-input", "catalog-services", moduleDir},
+			expectedStatus: ExitCodeError,
+			expectedOutput: []string{"is not compatible",
+				"catalog_services: variable is not declared"},
+		},
+		{
+			name:           "type mismatch",
+			args:           []string{incompatibleDir},
+			expectedStatus: ExitCodeError,
+			expectedOutput: []string{"is not compatible",
+				"services[*].port: module declares bool, but CTS passes number"},
+		},
+		{
+			name:           "version for local module",
+			args:           []string{"-version", "1.0.0", moduleDir},
+			expectedStatus: ExitCodeError,
+			expectedOutput: []string{"version is only supported for registry"},
+		},
+		{
+			name:           "no Terraform files",
+			args:           []string{t.TempDir()},
+			expectedStatus: ExitCodeError,
+			expectedOutput: []string{"unable to validate module"},
+		},
+		{
+			name:           "missing source",
+			args:           []string{},
+			expectedStatus: ExitCodeRequiredFlagsError,
+			expectedOutput: []string{"requires one argument"},
+		},
+		{
+			name:           "unsupported flag",
+			args:           []string{"-foo", "bar"},
+			expectedStatus: ExitCodeParseFlagsError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			cmd := newModuleValidateCommand(meta{UI: ui})
+
+			status := cmd.Run(tc.args)
+			assert.Equal(t, tc.expectedStatus, status)
+
+			output := ui.OutputWriter.String() + ui.ErrorWriter.String()
+			for _, expected := range tc.expectedOutput {
+				assert.Contains(t, output, expected)
+			}
+		})
+	}
+}
+
+func TestModuleValidateCommand_Run_JSON(t *testing.T) {
+	t.Parallel()
+
+	moduleDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "variables.tf"),
+		[]byte(`
+variable "services" {
+  type = map(object({
+    port = bool
+  }))
+}`), 0644))
+
+	var b bytes.Buffer
+	ui := cli.NewMockUi()
+	cmd := newModuleValidateCommand(meta{UI: ui, writer: &b})
+
+	// An incompatible module still exits with an error
+	status := cmd.Run([]string{"-output", "json", moduleDir})
+	assert.Equal(t, ExitCodeError, status)
+
+	var result moduleValidateResult
+	require.NoError(t, json.Unmarshal(b.Bytes(), &result))
+	assert.Equal(t, moduleDir, result.Source)
+	assert.False(t, result.Compatible)
+	assert.Contains(t, result.Mismatches, moduleValidateMismatch{
+		Variable: "services",
+		Path:     "services[*].port",
+		Message:  "module declares bool, but CTS passes number",
+	})
+}
@@ -0,0 +1,165 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+
+	"github.com/hashicorp/consul-terraform-sync/api"
+	"github.com/hashicorp/consul-terraform-sync/api/oapigen"
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	"github.com/mitchellh/cli"
+)
+
+const (
+	FlagOutput = "output"
+
+	// Output formats of the commands
+	OutputTable = "table"
+	OutputJSON  = "json"
+)
+
+// taskResult is the machine-readable result of a task command for a task
+type taskResult struct {
+	Task      string `json:"task"`
+	Action    string `json:"action"`
+	Success   bool   `json:"success"`
+	RequestID string `json:"request_id,omitempty"`
+	EventID   string `json:"event_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+
+	// Plan is the inspect plan of the task, and whether the plan has changes
+	ChangesPresent *bool  `json:"changes_present,omitempty"`
+	Plan           string `json:"plan,omitempty"`
+	TFCRunURL      string `json:"tfc_run_url,omitempty"`
+
+	// Render is the result of rendering the task without running it
+	Render *driver.RenderResult `json:"render,omitempty"`
+}
+
+// taskResults is the machine-readable result of a task command for multiple
+// tasks
+type taskResults struct {
+	RequestID string       `json:"request_id,omitempty"`
+	Results   []taskResult `json:"results"`
+}
+
+// stderrUi wraps a UI to write all messages to the error writer, which leaves
+// the output writer for the machine-readable output of the command
+type stderrUi struct {
+	cli.Ui
+}
+
+// Output writes the message to the error writer
+func (u *stderrUi) Output(message string) {
+	u.Ui.Warn(message)
+}
+
+// Info writes the message to the error writer
+func (u *stderrUi) Info(message string) {
+	u.Ui.Warn(message)
+}
+
+// outputFlag adds the -output flag to the flags of the command. All commands
+// support the flag, including the commands that do not use the default flags.
+func (m *meta) outputFlag(flags *flag.FlagSet) {
+	m.output = flags.String(FlagOutput, OutputTable, fmt.Sprintf("The format "+
+		"of the output of the command. Set to '%s' for human-readable output "+
+		"\n\t\tor '%s' for machine-readable output. Commands that call the API "+
+		"\n\t\tinclude the request IDs and event IDs in the JSON output. With "+
+		"\n\t\t'%s', messages are written to stderr.",
+		OutputTable, OutputJSON, OutputJSON))
+}
+
+// setupOutput validates the output flag after the flags are parsed. For JSON
+// output, the human-readable messages of the command are written to the error
+// writer instead. Returns false if the output format is not supported.
+func (m *meta) setupOutput() bool {
+	switch m.outputFormat() {
+	case OutputTable:
+		return true
+	case OutputJSON:
+		if _, ok := m.UI.(*stderrUi); !ok {
+			m.UI = &stderrUi{Ui: m.UI}
+		}
+		return true
+	default:
+		m.UI.Error(fmt.Sprintf("Error: unsupported value '%s' for the -%s "+
+			"flag, expected '%s' or '%s'", m.outputFormat(), FlagOutput,
+			OutputTable, OutputJSON))
+		return false
+	}
+}
+
+// outputFormat returns the output format of the command
+func (m *meta) outputFormat() string {
+	if m.output == nil || *m.output == "" {
+		return OutputTable
+	}
+	return *m.output
+}
+
+// outputJSON returns true if the command outputs machine-readable JSON
+func (m *meta) outputJSON() bool {
+	return m.outputFormat() == OutputJSON
+}
+
+// requireAutoApproveJSON returns false and outputs an error if the output is
+// JSON and interactive approval is not skipped. Approval prompts cannot be
+// answered by scripts that parse the output.
+func (m *meta) requireAutoApproveJSON(autoApprove bool) bool {
+	if !m.outputJSON() || autoApprove {
+		return true
+	}
+	m.UI.Error(fmt.Sprintf("Error: the -%s=%s flag requires the -%s flag",
+		FlagOutput, OutputJSON, FlagAutoApprove))
+	return false
+}
+
+// writeJSON writes the machine-readable output of the command to the output
+// writer
+func (m *meta) writeJSON(v interface{}) int {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		m.UI.Error(fmt.Sprintf("Error: unable to encode output: %s", err))
+		return ExitCodeError
+	}
+	fmt.Fprintln(m.writer, string(b))
+	return ExitCodeOK
+}
+
+// latestEventID returns the ID of the latest event of a task that ran the
+// task, or an empty string if the event is not available. Commands that run a
+// task include the event in their machine-readable output on a best-effort
+// basis.
+func (m *meta) latestEventID(taskName string) string {
+	client, err := m.client()
+	if err != nil {
+		return ""
+	}
+	statuses, err := client.Status().Task(taskName, &api.QueryParam{IncludeEvents: true})
+	if err != nil {
+		return ""
+	}
+	for _, e := range statuses[taskName].Events {
+		// suppressed, degraded, and expired events do not run the task
+		if e.Suppressed || e.Degraded || e.Expired {
+			continue
+		}
+		return e.ID
+	}
+	return ""
+}
+
+// requestIDString returns the request ID as a string, or an empty string if
+// the response did not include a request ID
+func requestIDString(id oapigen.RequestID) string {
+	var zero oapigen.RequestID
+	if id == zero {
+		return ""
+	}
+	return id.String()
+}
@@ -0,0 +1,222 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/consul-terraform-sync/api"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	// FlagProfile is the command line flag name to select a CLI profile
+	FlagProfile = "profile"
+
+	// EnvCLIConfig is the environment variable name for the path to the CLI
+	// configuration file. Defaults to ~/.cts/config.hcl
+	EnvCLIConfig = "CTS_CLI_CONFIG"
+
+	// EnvProfile is the environment variable name to select a CLI profile
+	EnvProfile = "CTS_PROFILE"
+
+	defaultCLIConfigDir  = ".cts"
+	defaultCLIConfigFile = "config.hcl"
+)
+
+// cliConfig is the CLI configuration file. It contains named profiles with
+// the connection settings for CTS daemons so that they do not need to be
+// passed as flags with every command.
+//
+// Example:
+//
+//	default_profile = "prod"
+//
+//	profile "prod" {
+//	  address   = "https://cts.example.com:8558"
+//	  ca_cert   = "/etc/cts/ca.pem"
+//	  token     = "..."
+//	}
+type cliConfig struct {
+	DefaultProfile string     `hcl:"default_profile"`
+	Profiles       []*profile `hcl:"profile"`
+}
+
+// profile contains the connection settings for a CTS daemon
+type profile struct {
+	Name string `hcl:",key"`
+
+	Address    string `hcl:"address"`
+	CACert     string `hcl:"ca_cert"`
+	CAPath     string `hcl:"ca_path"`
+	ClientCert string `hcl:"client_cert"`
+	ClientKey  string `hcl:"client_key"`
+	SSLVerify  *bool  `hcl:"ssl_verify"`
+	Token      string `hcl:"token"`
+}
+
+// cliConfigPath returns the path to the CLI configuration file
+func cliConfigPath() (string, error) {
+	if path, ok := os.LookupEnv(EnvCLIConfig); ok && path != "" {
+		return path, nil
+	}
+
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", fmt.Errorf("unable to determine the home directory for "+
+			"the CLI configuration file: %s", err)
+	}
+	return filepath.Join(home, defaultCLIConfigDir, defaultCLIConfigFile), nil
+}
+
+// loadCLIConfig reads and decodes the CLI configuration file. Returns nil if
+// the file does not exist.
+func loadCLIConfig(path string) (*cliConfig, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unable to read CLI configuration file %q: %s",
+			path, err)
+	}
+
+	root, err := hcl.Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode CLI configuration file %q: %s",
+			path, err)
+	}
+	list, ok := root.Node.(*ast.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("unable to decode CLI configuration file %q: "+
+			"root should be an object", path)
+	}
+	if err := checkCLIConfigKeys(list); err != nil {
+		return nil, fmt.Errorf("invalid CLI configuration file %q: %s", path, err)
+	}
+
+	var c cliConfig
+	if err := hcl.DecodeObject(&c, list); err != nil {
+		return nil, fmt.Errorf("unable to decode CLI configuration file %q: %s",
+			path, err)
+	}
+
+	if err := c.validate(); err != nil {
+		return nil, fmt.Errorf("invalid CLI configuration file %q: %s", path, err)
+	}
+
+	return &c, nil
+}
+
+// checkCLIConfigKeys returns an error if the CLI configuration or its
+// profiles contain unsupported keys. The keys are checked against the parsed
+// configuration since the decoder ignores unknown keys.
+func checkCLIConfigKeys(list *ast.ObjectList) error {
+	if keys := unsupportedKeys(list, "default_profile", "profile"); len(keys) > 0 {
+		return fmt.Errorf("unsupported keys: %s", strings.Join(keys, ", "))
+	}
+
+	for _, item := range list.Filter("profile").Items {
+		obj, ok := item.Val.(*ast.ObjectType)
+		if !ok || len(item.Keys) == 0 {
+			continue
+		}
+		keys := unsupportedKeys(obj.List, "address", "ca_cert", "ca_path",
+			"client_cert", "client_key", "ssl_verify", "token")
+		if len(keys) > 0 {
+			name := item.Keys[0].Token.Value()
+			return fmt.Errorf("unsupported keys in profile %q: %s", name,
+				strings.Join(keys, ", "))
+		}
+	}
+	return nil
+}
+
+// unsupportedKeys returns the sorted keys of the items in the list that are
+// not supported
+func unsupportedKeys(list *ast.ObjectList, supported ...string) []string {
+	allowed := make(map[string]bool, len(supported))
+	for _, k := range supported {
+		allowed[k] = true
+	}
+
+	var keys []string
+	for _, item := range list.Items {
+		if len(item.Keys) == 0 {
+			continue
+		}
+		key, _ := item.Keys[0].Token.Value().(string)
+		if !allowed[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (c *cliConfig) validate() error {
+	names := make(map[string]bool)
+	for _, p := range c.Profiles {
+		if p.Name == "" {
+			return fmt.Errorf("profile blocks require a name")
+		}
+		if names[p.Name] {
+			return fmt.Errorf("more than one profile named %q", p.Name)
+		}
+		names[p.Name] = true
+	}
+
+	if c.DefaultProfile != "" && !names[c.DefaultProfile] {
+		return fmt.Errorf("default_profile %q is not configured", c.DefaultProfile)
+	}
+
+	return nil
+}
+
+// profile returns the profile with the given name. If the name is empty, the
+// default profile is returned. Returns nil if there is no default profile.
+func (c *cliConfig) profile(name string) (*profile, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	for _, p := range c.Profiles {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("profile %q is not configured", name)
+}
+
+// apply sets the profile's connection settings on the client configuration.
+// Settings that are also set by environment variables are not applied so
+// that environment variables take precedence over the profile.
+func (p *profile) apply(c *api.ClientConfig) {
+	set := func(env, value string, field *string) {
+		if _, ok := os.LookupEnv(env); ok || value == "" {
+			return
+		}
+		*field = value
+	}
+
+	set(api.EnvAddress, p.Address, &c.URL)
+	set(api.EnvTLSCACert, p.CACert, &c.TLSConfig.CACert)
+	set(api.EnvTLSCAPath, p.CAPath, &c.TLSConfig.CAPath)
+	set(api.EnvTLSClientCert, p.ClientCert, &c.TLSConfig.ClientCert)
+	set(api.EnvTLSClientKey, p.ClientKey, &c.TLSConfig.ClientKey)
+	set(api.EnvToken, p.Token, &c.Token)
+
+	if _, ok := os.LookupEnv(api.EnvTLSSSLVerify); !ok && p.SSLVerify != nil {
+		c.TLSConfig.SSLVerify = *p.SSLVerify
+	}
+}
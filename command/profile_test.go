@@ -0,0 +1,207 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCLIConfig = `
+default_profile = "dev"
+
+profile "dev" {
+  address = "http://localhost:8558"
+}
+
+profile "prod" {
+  address     = "https://cts.example.com:8558"
+  ca_cert     = "/etc/cts/ca.pem"
+  client_cert = "/etc/cts/client.pem"
+  client_key  = "/etc/cts/client-key.pem"
+  ssl_verify  = false
+  token       = "secret"
+}
+`
+
+func writeTestCLIConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.hcl")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoadCLIConfig(t *testing.T) {
+	t.Run("happy path", func(t *testing.T) {
+		path := writeTestCLIConfig(t, testCLIConfig)
+		c, err := loadCLIConfig(path)
+		require.NoError(t, err)
+		require.NotNil(t, c)
+
+		assert.Equal(t, "dev", c.DefaultProfile)
+		require.Len(t, c.Profiles, 2)
+
+		p, err := c.profile("prod")
+		require.NoError(t, err)
+		assert.Equal(t, "https://cts.example.com:8558", p.Address)
+		assert.Equal(t, "/etc/cts/ca.pem", p.CACert)
+		assert.Equal(t, "secret", p.Token)
+		require.NotNil(t, p.SSLVerify)
+		assert.False(t, *p.SSLVerify)
+
+		p, err = c.profile("")
+		require.NoError(t, err)
+		assert.Equal(t, "dev", p.Name)
+
+		_, err = c.profile("staging")
+		assert.Error(t, err)
+	})
+
+	t.Run("file does not exist", func(t *testing.T) {
+		c, err := loadCLIConfig(filepath.Join(t.TempDir(), "config.hcl"))
+		assert.NoError(t, err)
+		assert.Nil(t, c)
+	})
+
+	errCases := []struct {
+		name    string
+		content string
+	}{
+		{
+			"unsupported key",
+			`profile "dev" { port = 8558 }`,
+		},
+		{
+			"duplicate profile",
+			`
+profile "dev" { address = "http://localhost:8558" }
+profile "dev" { address = "http://localhost:8559" }`,
+		},
+		{
+			"unknown default profile",
+			`
+default_profile = "prod"
+profile "dev" { address = "http://localhost:8558" }`,
+		},
+		{
+			"invalid hcl",
+			`profile "dev" {`,
+		},
+	}
+
+	for _, tc := range errCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := writeTestCLIConfig(t, tc.content)
+			_, err := loadCLIConfig(path)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestMeta_clientConfig_Profile(t *testing.T) {
+	path := writeTestCLIConfig(t, testCLIConfig)
+	t.Setenv(EnvCLIConfig, path)
+
+	cases := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		expected func(c *api.ClientConfig)
+	}{
+		{
+			"default profile",
+			[]string{},
+			nil,
+			func(c *api.ClientConfig) {
+				c.URL = "http://localhost:8558"
+			},
+		},
+		{
+			"profile flag",
+			[]string{"-profile", "prod"},
+			nil,
+			func(c *api.ClientConfig) {
+				c.URL = "https://cts.example.com:8558"
+				c.TLSConfig.CACert = "/etc/cts/ca.pem"
+				c.TLSConfig.ClientCert = "/etc/cts/client.pem"
+				c.TLSConfig.ClientKey = "/etc/cts/client-key.pem"
+				c.TLSConfig.SSLVerify = false
+				c.Token = "secret"
+			},
+		},
+		{
+			"profile env",
+			[]string{},
+			map[string]string{EnvProfile: "prod", api.EnvAddress: "https://localhost:9000"},
+			func(c *api.ClientConfig) {
+				c.URL = "https://localhost:9000"
+				c.TLSConfig.CACert = "/etc/cts/ca.pem"
+				c.TLSConfig.ClientCert = "/etc/cts/client.pem"
+				c.TLSConfig.ClientKey = "/etc/cts/client-key.pem"
+				c.TLSConfig.SSLVerify = false
+				c.Token = "secret"
+			},
+		},
+		{
+			"flags override profile",
+			[]string{"-profile", "prod", "-http-addr", "https://localhost:9001", "-ssl-verify=true"},
+			nil,
+			func(c *api.ClientConfig) {
+				c.URL = "https://localhost:9001"
+				c.TLSConfig.CACert = "/etc/cts/ca.pem"
+				c.TLSConfig.ClientCert = "/etc/cts/client.pem"
+				c.TLSConfig.ClientKey = "/etc/cts/client-key.pem"
+				c.TLSConfig.SSLVerify = true
+				c.Token = "secret"
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			m := meta{UI: cli.NewMockUi()}
+			m.defaultFlagSet("test")
+			require.NoError(t, m.flags.Parse(tc.args))
+
+			expected := &api.ClientConfig{
+				URL:       api.DefaultURL,
+				TLSConfig: api.TLSConfig{SSLVerify: true},
+			}
+			tc.expected(expected)
+
+			actual, err := m.clientConfig()
+			require.NoError(t, err)
+			assert.Equal(t, expected, actual)
+		})
+	}
+
+	t.Run("unknown profile", func(t *testing.T) {
+		m := meta{UI: cli.NewMockUi()}
+		m.defaultFlagSet("test")
+		require.NoError(t, m.flags.Parse([]string{"-profile", "staging"}))
+
+		_, err := m.clientConfig()
+		assert.Error(t, err)
+	})
+
+	t.Run("missing config file", func(t *testing.T) {
+		t.Setenv(EnvCLIConfig, filepath.Join(t.TempDir(), "config.hcl"))
+
+		m := meta{UI: cli.NewMockUi()}
+		m.defaultFlagSet("test")
+		require.NoError(t, m.flags.Parse([]string{"-profile", "prod"}))
+
+		_, err := m.clientConfig()
+		assert.Error(t, err)
+	})
+}
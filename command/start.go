@@ -5,8 +5,11 @@ package command
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"math/rand"
 	"os"
 	"os/signal"
 	"syscall"
@@ -14,7 +17,9 @@ import (
 
 	"github.com/hashicorp/consul-terraform-sync/config"
 	"github.com/hashicorp/consul-terraform-sync/controller"
+	"github.com/hashicorp/consul-terraform-sync/health"
 	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/hashicorp/consul-terraform-sync/retry"
 	"github.com/hashicorp/consul-terraform-sync/version"
 	"github.com/mitchellh/go-wordwrap"
 	"github.com/posener/complete"
@@ -28,10 +33,19 @@ const (
 	flagInspect               = "inspect"
 	flagInspectTask           = "inspect-task"
 	flagOnce                  = "once"
+	flagOnceTask              = "task"
+	flagOnceReport            = "once-report"
+	flagChangedOnly           = "changed-only"
+	flagSkipUnchanged         = "skip-unchanged"
+	flagSupervisor            = "supervisor"
 	flagAutocompleteInstall   = "autocomplete-install"
 	flagAutocompleteUninstall = "autocomplete-uninstall"
 	flagClientType            = "client-type"
 	flagDeprecatedStartUp     = "deprecated-start-up"
+
+	// supervisorMaxWaitTime is the maximum wait time between retries of
+	// transient errors in supervisor mode
+	supervisorMaxWaitTime = time.Minute
 )
 
 // startCommand handles the `start` command
@@ -41,9 +55,14 @@ type startCommand struct {
 
 	configFiles  *config.FlagAppendSliceValue
 	inspectTasks *config.FlagAppendSliceValue
+	onceTasks    *config.FlagAppendSliceValue
+	onceReport   *string
 
 	isInspect             *bool
 	isOnce                *bool
+	isChangedOnly         *bool
+	isSkipUnchanged       *bool
+	isSupervisor          *bool
 	autocompleteInstall   *bool
 	autocompleteUninstall *bool
 
@@ -60,9 +79,9 @@ func (c *startCommand) startFlags() *flag.FlagSet {
 	flags := flag.NewFlagSet(cmdStartName, flag.ContinueOnError)
 	flags.SetOutput(c.meta.writer)
 
-	var configFiles, inspectTasks config.FlagAppendSliceValue
-	var isInspect, isOnce, autocompleteInstall, autocompleteUninstall, isDeprecatedStartup bool
-	var clientType string
+	var configFiles, inspectTasks, onceTasks config.FlagAppendSliceValue
+	var isInspect, isOnce, isChangedOnly, isSkipUnchanged, isSupervisor, autocompleteInstall, autocompleteUninstall, isDeprecatedStartup bool
+	var clientType, onceReport string
 
 	// Parse the flags
 	flags.Var(&configFiles, flagConfigDir,
@@ -92,6 +111,40 @@ func (c *startCommand) startFlags() *flag.FlagSet {
 		"\n\t\tas a daemon and disables buffer periods.")
 	c.isOnce = &isOnce
 
+	flags.Var(&onceTasks, flagOnceTask, "Use with -once to run only the specified task once. Other "+
+		"\n\t\tconfigured tasks are not run. This option can be specified multiple "+
+		"\n\t\ttimes to run multiple tasks.")
+	c.onceTasks = &onceTasks
+
+	flags.StringVar(&onceReport, flagOnceReport, "", "Use with -once to write a JSON "+
+		"report of the outcome and latest event ID \n\t\tof each task to the file. "+
+		fmt.Sprintf("Once mode exits with code %d if all tasks \n\t\tsucceed, %d if "+
+			"some tasks errored, and %d if running the \n\t\ttasks stopped early "+
+			"because of an error.", ExitCodeOK, ExitCodeOncePartialFailure,
+			ExitCodeOnceFatal))
+	c.onceReport = &onceReport
+
+	flags.BoolVar(&isChangedOnly, flagChangedOnly, false, "Use with -once to run only "+
+		"the tasks that changed since their last \n\t\tsuccessful run. A task changed "+
+		"when its rendered Consul data or \n\t\tgenerated files differ from the last run. "+
+		"Requires the working \n\t\tdirectory to be kept between runs.")
+	c.isChangedOnly = &isChangedOnly
+
+	flags.BoolVar(&isSkipUnchanged, flagSkipUnchanged, false, "Skip re-applying the tasks "+
+		"that have not changed since their last \n\t\tsuccessful run when the daemon runs "+
+		"all tasks once at startup, \n\t\te.g. after CTS restarts. Use -changed-only "+
+		"with -once instead.")
+	c.isSkipUnchanged = &isSkipUnchanged
+
+	flags.BoolVar(&isSupervisor, flagSupervisor, false, "Run the daemon with supervisor "+
+		"semantics, e.g. as the entrypoint of a \n\t\tcontainer. Errors reaching Consul "+
+		"at startup are retried with an \n\t\texponential backoff instead of exiting, and "+
+		"the health API reports \n\t\tthat CTS is degraded until it recovers. Configuration "+
+		fmt.Sprintf("errors \n\t\texit with code %d and should not be restarted.", ExitCodeConfigError))
+	c.isSupervisor = &isSupervisor
+
+	c.meta.outputFlag(flags)
+
 	// Flags for installing the shell autocomplete
 	flags.BoolVar(&autocompleteInstall, flagAutocompleteInstall, false, "Install the autocomplete")
 	c.autocompleteInstall = &autocompleteInstall
@@ -167,9 +220,15 @@ func (c *startCommand) AutocompleteFlags() complete.Flags {
 		fmt.Sprintf("-%s", flagInspect):               complete.PredictNothing,
 		fmt.Sprintf("-%s", flagInspectTask):           complete.PredictNothing,
 		fmt.Sprintf("-%s", flagOnce):                  complete.PredictNothing,
+		fmt.Sprintf("-%s", flagOnceTask):              complete.PredictNothing,
+		fmt.Sprintf("-%s", flagOnceReport):            complete.PredictFiles("*.json"),
+		fmt.Sprintf("-%s", flagChangedOnly):           complete.PredictNothing,
+		fmt.Sprintf("-%s", flagSkipUnchanged):         complete.PredictNothing,
+		fmt.Sprintf("-%s", flagSupervisor):            complete.PredictNothing,
 		fmt.Sprintf("-%s", flagAutocompleteInstall):   complete.PredictNothing,
 		fmt.Sprintf("-%s", flagAutocompleteUninstall): complete.PredictNothing,
 		fmt.Sprintf("-%s", flagClientType):            complete.PredictNothing,
+		fmt.Sprintf("-%s", FlagOutput):                complete.PredictSet(OutputTable, OutputJSON),
 	}
 }
 
@@ -186,6 +245,9 @@ func (c *startCommand) Run(args []string) int {
 	if err := c.flags.Parse(args); err != nil {
 		return ExitCodeParseFlagsError
 	}
+	if !c.meta.setupOutput() {
+		return ExitCodeRequiredFlagsError
+	}
 
 	// TODO: Remove this after default subcommands are not supported.
 	// We have to check the length of the config files to ensure we don't print any message
@@ -216,6 +278,50 @@ func (c *startCommand) Run(args []string) int {
 		return ExitCodeRequiredFlagsError
 	}
 
+	if len(*c.onceTasks) != 0 && !*c.isOnce {
+		c.UI.Error("unable to start consul-terraform-sync")
+		c.UI.Output(fmt.Sprintf("the -%s flag can only be used with -%s",
+			flagOnceTask, flagOnce))
+		return ExitCodeRequiredFlagsError
+	}
+
+	if *c.onceReport != "" && !*c.isOnce {
+		c.UI.Error("unable to start consul-terraform-sync")
+		c.UI.Output(fmt.Sprintf("the -%s flag can only be used with -%s",
+			flagOnceReport, flagOnce))
+		return ExitCodeRequiredFlagsError
+	}
+
+	if *c.isChangedOnly && !*c.isOnce {
+		c.UI.Error("unable to start consul-terraform-sync")
+		c.UI.Output(fmt.Sprintf("the -%s flag can only be used with -%s",
+			flagChangedOnly, flagOnce))
+		return ExitCodeRequiredFlagsError
+	}
+
+	if *c.isSkipUnchanged && (*c.isOnce || *c.isInspect || len(*c.inspectTasks) != 0) {
+		c.UI.Error("unable to start consul-terraform-sync")
+		c.UI.Output(fmt.Sprintf("the -%s flag cannot be used with -%s or -%s",
+			flagSkipUnchanged, flagOnce, flagInspect))
+		return ExitCodeRequiredFlagsError
+	}
+
+	if *c.isSupervisor && (*c.isOnce || *c.isInspect || len(*c.inspectTasks) != 0) {
+		c.UI.Error("unable to start consul-terraform-sync")
+		c.UI.Output(fmt.Sprintf("the -%s flag cannot be used with -%s or -%s",
+			flagSupervisor, flagOnce, flagInspect))
+		return ExitCodeRequiredFlagsError
+	}
+
+	// The daemon does not exit with a result, so only the modes that exit
+	// after running the tasks support machine-readable output
+	if c.outputJSON() && !*c.isOnce && !*c.isInspect && len(*c.inspectTasks) == 0 {
+		c.UI.Error("unable to start consul-terraform-sync")
+		c.UI.Output(fmt.Sprintf("the -%s=%s flag can only be used with -%s or -%s",
+			FlagOutput, OutputJSON, flagOnce, flagInspect))
+		return ExitCodeRequiredFlagsError
+	}
+
 	// Build the config.
 	conf, err := config.BuildConfig(*c.configFiles)
 	logger := logging.Global().Named(logSystemName)
@@ -265,18 +371,42 @@ func (c *startCommand) Run(args []string) int {
 		}
 	}
 
+	if len(*c.onceTasks) != 0 && !*c.isInspect {
+		conf.Tasks, err = config.FilterTasks(conf.Tasks, *c.onceTasks)
+		if err != nil {
+			logger.Error("error running tasks once", "error", err)
+			return ExitCodeConfigError
+		}
+	}
+
 	// Set up controller
 	conf.ClientType = config.String(*c.clientType)
 	var ctrl controller.Controller
+	var once *controller.Once
+	var diagnostics diagnosticsWriter
 	switch {
 	case *c.isInspect:
 		logger.Debug("inspect mode enabled, processing then exiting")
 		ctrl, err = controller.NewInspect(conf)
 	case *c.isOnce:
 		logger.Debug("once mode enabled, processing then exiting")
-		ctrl, err = controller.NewOnce(conf)
+		once, err = controller.NewOnce(conf)
+		if err == nil {
+			once.SetChangedOnly(*c.isChangedOnly)
+			ctrl = once
+		}
 	default:
-		ctrl, err = controller.NewDaemon(conf)
+		var daemon *controller.Daemon
+		daemon, err = controller.NewDaemon(conf)
+		if err == nil {
+			daemon.SetChangedOnly(*c.isSkipUnchanged)
+			diagnostics = daemon.TasksManager()
+			if *c.isSupervisor {
+				logger.Info("supervisor mode enabled, retrying transient errors")
+				daemon.SetHealth(&health.StatusChecker{})
+			}
+			ctrl = daemon
+		}
 	}
 	if err != nil {
 		logger.Error("error setting up controller", "error", err)
@@ -284,8 +414,18 @@ func (c *startCommand) Run(args []string) int {
 	}
 	defer ctrl.Stop()
 
+	// Check the configuration against the connected Consul before any tasks
+	// query Consul
+	if err := controller.CheckConsulCompatibility(ctx, conf); err != nil {
+		logger.Error("error checking compatibility with Consul", "error", err)
+		return ExitCodeConfigError
+	}
+
 	// Install the driver after controller has tested Consul connection
-	if err := controller.InstallDriver(ctx, conf); err != nil {
+	err = c.supervise(ctx, logger, "installing driver", func(ctx context.Context) error {
+		return controller.InstallDriver(ctx, conf)
+	})
+	if err != nil {
 		logger.Error("error installing driver", "error", err)
 		return ExitCodeDriverError
 	}
@@ -296,7 +436,7 @@ func (c *startCommand) Run(args []string) int {
 
 	go func() {
 		logger.Info("initializing controller")
-		err := ctrl.Init(ctx)
+		err := c.supervise(ctx, logger, "initializing controller", ctrl.Init)
 		if err != nil {
 			if err == context.Canceled {
 				exitCh <- struct{}{}
@@ -322,8 +462,25 @@ func (c *startCommand) Run(args []string) int {
 
 	interruptCh := make(chan os.Signal, 1)
 	signal.Notify(interruptCh, os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
+
+	// Dump the internal diagnostics of the daemon on signal
+	diagnosticsCh := make(chan os.Signal, 1)
+	if diagnostics != nil && len(diagnosticsSignals) > 0 {
+		signal.Notify(diagnosticsCh, diagnosticsSignals...)
+		defer signal.Stop(diagnosticsCh)
+	}
+
 	for {
 		select {
+		case sig := <-diagnosticsCh:
+			logger.Info("signal received to dump diagnostics", "signal", sig)
+			path, err := writeDiagnosticsFile(ctx, diagnostics, os.TempDir(), time.Now())
+			if err != nil {
+				logger.Error("error dumping diagnostics", "error", err)
+				continue
+			}
+			logger.Info("diagnostics dumped", "path", path)
+
 		case sig := <-interruptCh:
 			// Cancel the context and wait for controller go routine to gracefully
 			// shutdown
@@ -349,13 +506,125 @@ func (c *startCommand) Run(args []string) int {
 		case <-exitCh:
 			if *c.isOnce || *c.isInspect {
 				logger.Info("graceful shutdown")
-				return ExitCodeOK
+				return c.exitResult(nil, once)
 			}
 			logger.Warn("unexpected shutdown")
 			return ExitCodeError
 
-		case <-errCh:
-			return ExitCodeError
+		case err := <-errCh:
+			return c.exitResult(err, once)
+		}
+	}
+}
+
+// supervise calls f and returns its error. In supervisor mode, f is retried
+// with an exponential backoff until it succeeds or the context is canceled.
+func (c *startCommand) supervise(ctx context.Context, logger logging.Logger,
+	desc string, f func(context.Context) error) error {
+
+	if !*c.isSupervisor {
+		return f(ctx)
+	}
+
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return superviseRetry(ctx, logger, desc, f, func(attempt int) time.Duration {
+		return retry.WaitTime(attempt, random, supervisorMaxWaitTime)
+	})
+}
+
+// superviseRetry calls f until it succeeds or the context is canceled,
+// waiting between attempts for the wait time of the attempt
+func superviseRetry(ctx context.Context, logger logging.Logger, desc string,
+	f func(context.Context) error, waitTime func(attempt int) time.Duration) error {
+
+	for attempt := 1; ; attempt++ {
+		err := f(ctx)
+		if err == nil || ctx.Err() != nil {
+			return err
+		}
+
+		wait := waitTime(attempt)
+		logger.Warn("error "+desc+", retrying", "attempt", attempt,
+			"retry_in", wait, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
 		}
 	}
 }
+
+// startResult is the machine-readable result of the modes of the command
+// that exit after running the tasks
+type startResult struct {
+	Mode        string   `json:"mode"`
+	Success     bool     `json:"success"`
+	Error       string   `json:"error,omitempty"`
+	FailedTasks []string `json:"failed_tasks,omitempty"`
+}
+
+// onceReport is the report of the outcome of the tasks in once mode
+type onceReport struct {
+	ExitCode int                         `json:"exit_code"`
+	Error    string                      `json:"error,omitempty"`
+	Tasks    []controller.OnceTaskStatus `json:"tasks"`
+}
+
+// exitResult returns the exit code for the error of the controller, and
+// writes the machine-readable result of the run and the once report if
+// requested. In once mode, the exit code reflects the outcome of the tasks.
+func (c *startCommand) exitResult(err error, once *controller.Once) int {
+	code := ExitCodeOK
+	var failed *controller.OnceTasksFailedError
+	switch {
+	case err == nil:
+	case !*c.isOnce:
+		code = ExitCodeError
+	case errors.As(err, &failed):
+		code = ExitCodeOncePartialFailure
+	default:
+		code = ExitCodeOnceFatal
+	}
+
+	if once != nil && *c.onceReport != "" {
+		report := onceReport{ExitCode: code, Tasks: once.TaskStatuses()}
+		if err != nil {
+			report.Error = err.Error()
+		}
+		if rErr := writeOnceReport(*c.onceReport, report); rErr != nil {
+			c.UI.Error(fmt.Sprintf("Error: unable to write once report: %s", rErr))
+			if code == ExitCodeOK {
+				code = ExitCodeError
+			}
+		}
+	}
+
+	if !c.outputJSON() {
+		return code
+	}
+
+	result := startResult{Mode: flagOnce, Success: err == nil}
+	if *c.isInspect {
+		result.Mode = flagInspect
+	}
+	if err != nil {
+		result.Error = err.Error()
+		if failed != nil {
+			result.FailedTasks = failed.Tasks
+		}
+	}
+	if c.writeJSON(result) != ExitCodeOK {
+		return ExitCodeError
+	}
+	return code
+}
+
+// writeOnceReport writes the once report to the file as JSON
+func writeOnceReport(path string, report onceReport) error {
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0644)
+}
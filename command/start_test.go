@@ -4,13 +4,21 @@
 package command
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/consul-terraform-sync/controller"
+	"github.com/hashicorp/consul-terraform-sync/logging"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStartCommand_Name(t *testing.T) {
@@ -29,6 +37,12 @@ func TestStartCommand_Help(t *testing.T) {
 		"-inspect",
 		"-inspect-task",
 		"-once",
+		"-task",
+		"-once-report",
+		"-changed-only",
+		"-skip-unchanged",
+		"-supervisor",
+		"-output",
 	}
 
 	doesNotContain := []string{
@@ -97,6 +111,199 @@ func TestStartCommand_AutocompleteFlags(t *testing.T) {
 		"new flags to the command AutoCompleteFlags function")
 }
 
+func TestStartCommand_Run_OnceTask(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	cmd := newStartCommand(meta{UI: ui})
+
+	exitCode := cmd.Run([]string{"-config-file", "config.hcl", "-task", "task_a"})
+	assert.Equal(t, ExitCodeRequiredFlagsError, exitCode)
+	assert.Contains(t, ui.OutputWriter.String(), "the -task flag can only be used with -once")
+}
+
+func TestStartCommand_Run_OnceReport(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	cmd := newStartCommand(meta{UI: ui})
+
+	exitCode := cmd.Run([]string{"-config-file", "config.hcl", "-once-report", "out.json"})
+	assert.Equal(t, ExitCodeRequiredFlagsError, exitCode)
+	assert.Contains(t, ui.OutputWriter.String(),
+		"the -once-report flag can only be used with -once")
+}
+
+func TestStartCommand_exitResult(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		args     []string
+		err      error
+		expected int
+	}{
+		{"once success", []string{"-once"}, nil, ExitCodeOK},
+		{
+			"once partial failure",
+			[]string{"-once"},
+			fmt.Errorf("wrapped: %w", &controller.OnceTasksFailedError{Tasks: []string{"task_a"}}),
+			ExitCodeOncePartialFailure,
+		},
+		{"once fatal", []string{"-once"}, errors.New("error"), ExitCodeOnceFatal},
+		{"inspect error", []string{"-inspect"}, errors.New("error"), ExitCodeError},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := newStartCommand(meta{UI: cli.NewMockUi()})
+			require.NoError(t, cmd.flags.Parse(tc.args))
+			assert.Equal(t, tc.expected, cmd.exitResult(tc.err, nil))
+		})
+	}
+}
+
+func TestWriteOnceReport(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "out.json")
+	report := onceReport{
+		ExitCode: ExitCodeOncePartialFailure,
+		Error:    "1 task(s) errored while running once: task_b",
+		Tasks: []controller.OnceTaskStatus{
+			{Name: "task_a", Status: controller.OnceTaskStatusSuccess, EventID: "abc"},
+			{Name: "task_b", Status: controller.OnceTaskStatusErrored, Error: "apply error"},
+		},
+	}
+	require.NoError(t, writeOnceReport(path, report))
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "exit_code": 2,
+  "error": "1 task(s) errored while running once: task_b",
+  "tasks": [
+    {"name": "task_a", "status": "success", "event_id": "abc"},
+    {"name": "task_b", "status": "errored", "error": "apply error"}
+  ]
+}`, string(b))
+}
+
+func TestStartCommand_Run_ChangedOnly(t *testing.T) {
+	t.Parallel()
+
+	ui := cli.NewMockUi()
+	cmd := newStartCommand(meta{UI: ui})
+
+	exitCode := cmd.Run([]string{"-config-file", "config.hcl", "-changed-only"})
+	assert.Equal(t, ExitCodeRequiredFlagsError, exitCode)
+	assert.Contains(t, ui.OutputWriter.String(),
+		"the -changed-only flag can only be used with -once")
+}
+
+func TestStartCommand_Run_SkipUnchanged(t *testing.T) {
+	t.Parallel()
+
+	cases := [][]string{
+		{"-skip-unchanged", "-once"},
+		{"-skip-unchanged", "-inspect"},
+	}
+	for _, flags := range cases {
+		ui := cli.NewMockUi()
+		cmd := newStartCommand(meta{UI: ui})
+
+		args := append([]string{"-config-file", "config.hcl"}, flags...)
+		exitCode := cmd.Run(args)
+		assert.Equal(t, ExitCodeRequiredFlagsError, exitCode)
+		assert.Contains(t, ui.OutputWriter.String(),
+			"the -skip-unchanged flag cannot be used with -once or -inspect")
+	}
+}
+
+func TestStartCommand_Run_Supervisor(t *testing.T) {
+	t.Parallel()
+
+	cases := [][]string{
+		{"-supervisor", "-once"},
+		{"-supervisor", "-inspect"},
+		{"-supervisor", "-inspect-task", "task_a"},
+	}
+	for _, flags := range cases {
+		ui := cli.NewMockUi()
+		cmd := newStartCommand(meta{UI: ui})
+
+		args := append([]string{"-config-file", "config.hcl"}, flags...)
+		exitCode := cmd.Run(args)
+		assert.Equal(t, ExitCodeRequiredFlagsError, exitCode)
+		assert.Contains(t, ui.OutputWriter.String(),
+			"the -supervisor flag cannot be used with -once or -inspect")
+	}
+}
+
+func TestSuperviseRetry(t *testing.T) {
+	t.Parallel()
+
+	logger := logging.NewNullLogger()
+
+	t.Run("retries until success", func(t *testing.T) {
+		var calls int
+		f := func(context.Context) error {
+			calls++
+			if calls < 3 {
+				return errors.New("connection refused")
+			}
+			return nil
+		}
+
+		var attempts []int
+		err := superviseRetry(context.Background(), logger, "testing", f,
+			func(attempt int) time.Duration {
+				attempts = append(attempts, attempt)
+				return 0
+			})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, []int{1, 2}, attempts)
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		f := func(context.Context) error {
+			cancel()
+			return errors.New("connection refused")
+		}
+
+		err := superviseRetry(ctx, logger, "testing", f,
+			func(int) time.Duration { return time.Hour })
+		assert.Error(t, err)
+	})
+}
+
+func TestStartCommand_Run_Output(t *testing.T) {
+	t.Parallel()
+
+	t.Run("json requires once or inspect", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := newStartCommand(meta{UI: ui})
+
+		exitCode := cmd.Run([]string{"-config-file", "config.hcl", "-output", "json"})
+		assert.Equal(t, ExitCodeRequiredFlagsError, exitCode)
+		assert.Empty(t, ui.OutputWriter.String())
+		assert.Contains(t, ui.ErrorWriter.String(),
+			"the -output=json flag can only be used with -once or -inspect")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		cmd := newStartCommand(meta{UI: ui})
+
+		exitCode := cmd.Run([]string{"-config-file", "config.hcl", "-output", "yaml"})
+		assert.Equal(t, ExitCodeRequiredFlagsError, exitCode)
+		assert.Contains(t, ui.ErrorWriter.String(),
+			"unsupported value 'yaml' for the -output flag")
+	})
+}
+
 func TestStartCommand_AutocompleteArgs(t *testing.T) {
 	cmd := newStartCommand(meta{UI: cli.NewMockUi()})
 	c := cmd.AutocompleteArgs()
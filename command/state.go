@@ -0,0 +1,418 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	consulapi "github.com/hashicorp/consul/api"
+)
+
+const (
+	flagOut = "out"
+
+	// stateArchiveConsulDir is the directory of the state backup archive for
+	// the Terraform state stored in Consul KV. Files are named by their KV key.
+	stateArchiveConsulDir = "consul"
+
+	// stateArchiveLocalDir is the directory of the state backup archive for
+	// the Terraform state of local backends. Files are named by task.
+	stateArchiveLocalDir = "local"
+
+	// stateFilePerms are the permissions of restored local state files since
+	// state can contain sensitive values
+	stateFilePerms = 0600
+)
+
+// stateKV is the subset of the Consul KV API used to back up and restore the
+// Terraform state of tasks
+type stateKV interface {
+	Get(key string, q *consulapi.QueryOptions) (*consulapi.KVPair, *consulapi.QueryMeta, error)
+	List(prefix string, q *consulapi.QueryOptions) (consulapi.KVPairs, *consulapi.QueryMeta, error)
+	Put(p *consulapi.KVPair, q *consulapi.WriteOptions) (*consulapi.WriteMeta, error)
+}
+
+// stateArchiveResult is the machine-readable result of the state commands.
+// Count is the number of tasks backed up or the number of state files
+// restored.
+type stateArchiveResult struct {
+	Archive string   `json:"archive"`
+	Count   int      `json:"count"`
+	Skipped []string `json:"skipped"`
+}
+
+// taskState is the location of the Terraform state of a task
+type taskState struct {
+	task string
+
+	// backend is the type of the Terraform backend of the task
+	backend string
+
+	// key is the Consul KV key of the state for the Consul backend
+	key string
+
+	// path is the path of the state file for the local backend
+	path string
+}
+
+// addConfigFlags adds the flags to load the CTS configuration to the flag set
+func addConfigFlags(flags *flag.FlagSet, configFiles *config.FlagAppendSliceValue) {
+	flags.Var(configFiles, flagConfigDir,
+		"A directory to load files for configuring Consul-Terraform-Sync. "+
+			"\n\t\tConfiguration files require an .hcl or .json file extension in order "+
+			"\n\t\tto specify their format. This option can be specified multiple times to "+
+			"\n\t\tload different directories.")
+	flags.Var(configFiles, flagConfigFiles,
+		"A file to load for configuring Consul-Terraform-Sync. Configuration "+
+			"\n\t\tfile requires an .hcl or .json extension in order to specify their format. "+
+			"\n\t\tThis option can be specified multiple times to load different "+
+			"\n\t\tconfiguration files.")
+}
+
+// loadStateConfig builds, finalizes, and validates the CTS configuration to
+// locate the Terraform state of the tasks
+func loadStateConfig(paths []string) (*config.Config, error) {
+	conf, err := config.BuildConfig(paths)
+	if err != nil {
+		return nil, err
+	}
+	if err = conf.Finalize(); err != nil {
+		return nil, err
+	}
+	if err = conf.Validate(); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+// taskStates returns the location of the Terraform state of each task in the
+// configuration, sorted by task name. The state of tasks with a backend other
+// than Consul or local is not supported and only the backend type is set.
+func taskStates(conf *config.Config) ([]taskState, error) {
+	tfConf := conf.Driver.Terraform
+	if tfConf == nil {
+		return nil, fmt.Errorf("the Terraform driver is not configured")
+	}
+
+	var states []taskState
+	for _, t := range *conf.Tasks {
+		tc := t.InheritParentConfig(*conf.WorkingDir, *conf.BufferPeriod)
+		name := config.StringVal(tc.Name)
+
+		workspace, err := tfConf.Workspace(name)
+		if err != nil {
+			return nil, err
+		}
+
+		backend := tfConf.Backend
+		if b := tfConf.TaskBackend(tc.Backend, conf.Consul); len(b) > 0 {
+			backend = b
+		}
+
+		for backendType, v := range backend {
+			args, _ := v.(map[string]interface{})
+			s := taskState{task: name, backend: backendType}
+
+			switch backendType {
+			case "consul":
+				// Terraform stores the state of non-default workspaces at the
+				// path with the workspace suffix
+				key, _ := args["path"].(string)
+				if workspace != "default" {
+					key = fmt.Sprintf("%s-env:%s", key, workspace)
+				}
+				s.key = key
+			case "local":
+				s.path = localStatePath(config.StringVal(tc.WorkingDir), workspace, args)
+			}
+			states = append(states, s)
+		}
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].task < states[j].task
+	})
+	return states, nil
+}
+
+// localStatePath returns the path of the state file of the local backend for
+// a workspace. Relative paths are relative to the task's working directory.
+func localStatePath(workingDir, workspace string, args map[string]interface{}) string {
+	p := "terraform.tfstate"
+	if v, ok := args["path"].(string); ok && v != "" {
+		p = v
+	}
+
+	if workspace != "default" {
+		dir := "terraform.tfstate.d"
+		if v, ok := args["workspace_dir"].(string); ok && v != "" {
+			dir = v
+		}
+		p = filepath.Join(dir, workspace, filepath.Base(p))
+	}
+
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(workingDir, p)
+}
+
+// isStateKey returns whether the KV key is part of the state stored at the
+// state key. Large states are split into chunks under the state key. The lock
+// keys of the state are excluded.
+func isStateKey(stateKey, key string) bool {
+	if key == stateKey {
+		return true
+	}
+
+	if !strings.HasPrefix(key, stateKey+"/") {
+		return false
+	}
+
+	switch strings.TrimPrefix(key, stateKey+"/") {
+	case ".lock", ".lockinfo":
+		return false
+	}
+	return true
+}
+
+// backupState writes the Terraform state of the tasks to a gzipped tar
+// archive. Returns the number of tasks with state in the archive and the
+// messages for the tasks that were skipped.
+func backupState(w io.Writer, kv stateKV, states []taskState) (int, []string, error) {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	var count int
+	var skipped []string
+	for _, s := range states {
+		switch s.backend {
+		case "consul":
+			if kv == nil {
+				return 0, nil, fmt.Errorf("unable to back up state of task '%s': "+
+					"Consul is not configured", s.task)
+			}
+			pairs, _, err := kv.List(s.key, nil)
+			if err != nil {
+				return 0, nil, fmt.Errorf("unable to read state of task '%s' from "+
+					"Consul KV: %s", s.task, err)
+			}
+
+			var found bool
+			for _, p := range pairs {
+				if !isStateKey(s.key, p.Key) {
+					continue
+				}
+				name := path.Join(stateArchiveConsulDir, p.Key)
+				if err := writeArchiveFile(tw, name, p.Value); err != nil {
+					return 0, nil, err
+				}
+				found = true
+			}
+			if !found {
+				skipped = append(skipped, fmt.Sprintf("task '%s': no state found "+
+					"in Consul KV at '%s'", s.task, s.key))
+				continue
+			}
+
+		case "local":
+			content, err := os.ReadFile(s.path)
+			if os.IsNotExist(err) {
+				skipped = append(skipped, fmt.Sprintf("task '%s': no state found "+
+					"at '%s'", s.task, s.path))
+				continue
+			}
+			if err != nil {
+				return 0, nil, fmt.Errorf("unable to read state of task '%s': %s",
+					s.task, err)
+			}
+			name := path.Join(stateArchiveLocalDir, s.task+".tfstate")
+			if err := writeArchiveFile(tw, name, content); err != nil {
+				return 0, nil, err
+			}
+
+		default:
+			skipped = append(skipped, fmt.Sprintf("task '%s': backing up the "+
+				"state of the '%s' backend is not supported", s.task, s.backend))
+			continue
+		}
+		count++
+	}
+
+	if err := tw.Close(); err != nil {
+		return 0, nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return 0, nil, err
+	}
+	return count, skipped, nil
+}
+
+// writeArchiveFile writes the content as a file to the tar archive
+func writeArchiveFile(tw *tar.Writer, name string, content []byte) error {
+	hdr := &tar.Header{
+		Name: name,
+		Mode: stateFilePerms,
+		Size: int64(len(content)),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(content)
+	return err
+}
+
+// archiveFile is a file read from the state backup archive
+type archiveFile struct {
+	name    string
+	content []byte
+}
+
+// restoreState restores the Terraform state from a gzipped tar archive that
+// was written by backupState. State in Consul KV is restored to the same KV
+// keys. State of local backends is restored to the path of the task's state
+// file in the current configuration. Returns the number of restored files
+// and the messages for the files that were skipped.
+//
+// Nothing is restored if the archive has a Consul KV key that is not part of
+// the state of a task configured with the Consul backend, or if the state of
+// a task is locked, e.g. by a running task.
+func restoreState(r io.Reader, kv stateKV, states []taskState) (int, []string, error) {
+	files, err := readArchive(r)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	localPaths := make(map[string]string)
+	for _, s := range states {
+		if s.backend == "local" {
+			localPaths[s.task] = s.path
+		}
+	}
+
+	// Check that each key is the state of a configured task before writing
+	// any state to Consul KV
+	locked := make(map[string]taskState)
+	for _, f := range files {
+		dir, name, _ := strings.Cut(f.name, "/")
+		if dir != stateArchiveConsulDir {
+			continue
+		}
+		s, ok := consulTaskState(states, name)
+		if !ok {
+			return 0, nil, fmt.Errorf("refusing to restore Consul KV key '%s' "+
+				"that is not the state of a task configured with the Consul "+
+				"backend", name)
+		}
+		locked[s.key] = s
+	}
+
+	if len(locked) > 0 && kv == nil {
+		return 0, nil, fmt.Errorf("unable to restore state to Consul KV: " +
+			"Consul is not configured")
+	}
+	for _, s := range locked {
+		lockKey := s.key + "/.lock"
+		pair, _, err := kv.Get(lockKey, nil)
+		if err != nil {
+			return 0, nil, fmt.Errorf("unable to check the state lock of task "+
+				"'%s': %s", s.task, err)
+		}
+		if pair != nil && pair.Session != "" {
+			return 0, nil, fmt.Errorf("the state of task '%s' is locked at "+
+				"Consul KV key '%s'. Stop Consul-Terraform-Sync before restoring "+
+				"state", s.task, lockKey)
+		}
+	}
+
+	var count int
+	var skipped []string
+	for _, f := range files {
+		dir, name, _ := strings.Cut(f.name, "/")
+		switch dir {
+		case stateArchiveConsulDir:
+			_, err := kv.Put(&consulapi.KVPair{Key: name, Value: f.content}, nil)
+			if err != nil {
+				return count, skipped, fmt.Errorf("unable to restore state to "+
+					"Consul KV key '%s': %s", name, err)
+			}
+
+		case stateArchiveLocalDir:
+			task := strings.TrimSuffix(name, ".tfstate")
+			p, ok := localPaths[task]
+			if !ok {
+				skipped = append(skipped, fmt.Sprintf("task '%s': task is not "+
+					"configured with a local backend", task))
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+				return count, skipped, err
+			}
+			if err := os.WriteFile(p, f.content, stateFilePerms); err != nil {
+				return count, skipped, fmt.Errorf("unable to restore state of "+
+					"task '%s': %s", task, err)
+			}
+
+		default:
+			skipped = append(skipped, fmt.Sprintf("unexpected file '%s' in "+
+				"backup archive", f.name))
+			continue
+		}
+		count++
+	}
+
+	return count, skipped, nil
+}
+
+// readArchive reads the regular files of a gzipped tar archive
+func readArchive(r io.Reader) ([]archiveFile, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read backup archive: %s", err)
+	}
+	defer gr.Close()
+
+	var files []archiveFile
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read backup archive: %s", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read backup archive: %s", err)
+		}
+		files = append(files, archiveFile{name: hdr.Name, content: content})
+	}
+	return files, nil
+}
+
+// consulTaskState returns the state of the task configured with the Consul
+// backend that the KV key is part of
+func consulTaskState(states []taskState, key string) (taskState, bool) {
+	for _, s := range states {
+		if s.backend == "consul" && s.key != "" && isStateKey(s.key, key) {
+			return s, true
+		}
+	}
+	return taskState{}, false
+}
@@ -0,0 +1,180 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/consul-terraform-sync/client"
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/mitchellh/go-wordwrap"
+	"github.com/posener/complete"
+)
+
+const cmdStateBackupName = "state backup"
+
+// stateBackupCommand handles the `state backup` command
+type stateBackupCommand struct {
+	meta
+	configFiles *config.FlagAppendSliceValue
+	out         *string
+	flags       *flag.FlagSet
+}
+
+func newStateBackupCommand(m meta) *stateBackupCommand {
+	logging.DisableLogging()
+	flags := flag.NewFlagSet(cmdStateBackupName, flag.ContinueOnError)
+	flags.SetOutput(m.writer)
+
+	var configFiles config.FlagAppendSliceValue
+	addConfigFlags(flags, &configFiles)
+	out := flags.String(flagOut, "", "[Required] The path of the backup archive "+
+		"to write. The archive is \n\t\ta gzipped tar file.")
+
+	m.outputFlag(flags)
+	m.flags = flags
+	return &stateBackupCommand{
+		meta:        m,
+		configFiles: &configFiles,
+		out:         out,
+		flags:       flags,
+	}
+}
+
+// Name returns the subcommand
+func (c *stateBackupCommand) Name() string {
+	return cmdStateBackupName
+}
+
+// Help returns the command's usage, list of flags, and examples
+func (c *stateBackupCommand) Help() string {
+	c.meta.setHelpOptions()
+	helpText := fmt.Sprintf(`
+Usage: consul-terraform-sync state backup [-help] [options]
+
+  State Backup writes the Terraform state of all tasks to a backup archive for
+  disaster recovery. The state is read from the Terraform backend of each task
+  in the Consul-Terraform-Sync configuration. The state of tasks using the
+  Consul backend is read from Consul KV and the state of tasks using the local
+  backend is read from the task's working directory. Other backends are not
+  supported and are skipped.
+
+  The archive can be restored with the 'state restore' command.
+
+Options:
+%s
+
+Example:
+
+  $ consul-terraform-sync state backup -config-file=config.hcl -out=backup.tgz
+`, strings.Join(c.meta.helpOptions, "\n"))
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis is a short one-line synopsis of the command
+func (c *stateBackupCommand) Synopsis() string {
+	return "Backs up the Terraform state of all tasks."
+}
+
+// AutocompleteFlags returns a mapping of supported flags and autocomplete
+// options for this command. The map key for the Flags map should be the
+// complete flag such as "-foo" or "--foo".
+func (c *stateBackupCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		fmt.Sprintf("-%s", flagConfigDir): complete.PredictDirs("*"),
+		fmt.Sprintf("-%s", flagConfigFiles): complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		fmt.Sprintf("-%s", flagOut):    complete.PredictFiles("*"),
+		fmt.Sprintf("-%s", FlagOutput): complete.PredictSet(OutputTable, OutputJSON),
+	}
+}
+
+// AutocompleteArgs returns the argument predictor for this command.
+// Since argument completion is not supported, this will return
+// complete.PredictNothing.
+func (c *stateBackupCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+// Run runs the command
+func (c *stateBackupCommand) Run(args []string) int {
+	c.flags.Usage = func() { c.meta.UI.Output(c.Help()) }
+	if err := c.flags.Parse(args); err != nil {
+		return ExitCodeParseFlagsError
+	}
+	if !c.meta.setupOutput() {
+		return ExitCodeRequiredFlagsError
+	}
+
+	if len(*c.configFiles) == 0 || *c.out == "" {
+		c.UI.Error(fmt.Sprintf("Error: the -%s and one of the -%s or -%s flags "+
+			"are required", flagOut, flagConfigFiles, flagConfigDir))
+		c.UI.Output(fmt.Sprintf("For additional help try 'consul-terraform-sync %s --help'",
+			c.Name()))
+		return ExitCodeRequiredFlagsError
+	}
+
+	conf, err := loadStateConfig(*c.configFiles)
+	if err != nil {
+		c.UI.Error("Error: unable to load configuration")
+		c.UI.Output(wordwrap.WrapString(err.Error(), width))
+		return ExitCodeConfigError
+	}
+
+	states, err := taskStates(conf)
+	if err != nil {
+		c.UI.Error("Error: unable to locate the state of tasks")
+		c.UI.Output(wordwrap.WrapString(err.Error(), width))
+		return ExitCodeConfigError
+	}
+
+	var kv stateKV
+	for _, s := range states {
+		if s.backend == "consul" {
+			consul, err := client.NewConsulClient(conf.Consul, client.ConsulDefaultMaxRetry)
+			if err != nil {
+				c.UI.Error("Error: unable to create Consul client")
+				c.UI.Output(wordwrap.WrapString(err.Error(), width))
+				return ExitCodeError
+			}
+			kv = consul.KV()
+			break
+		}
+	}
+
+	f, err := os.OpenFile(*c.out, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, stateFilePerms)
+	if err != nil {
+		c.UI.Error("Error: unable to create backup archive")
+		c.UI.Output(wordwrap.WrapString(err.Error(), width))
+		return ExitCodeError
+	}
+	defer f.Close()
+
+	count, skipped, err := backupState(f, kv, states)
+	if err != nil {
+		c.UI.Error("Error: unable to back up state")
+		c.UI.Output(wordwrap.WrapString(err.Error(), width))
+		return ExitCodeError
+	}
+
+	if c.outputJSON() {
+		return c.writeJSON(stateArchiveResult{
+			Archive: *c.out,
+			Count:   count,
+			Skipped: append([]string{}, skipped...),
+		})
+	}
+
+	for _, msg := range skipped {
+		c.UI.Warn(fmt.Sprintf("Skipped %s", msg))
+	}
+	c.UI.Info(fmt.Sprintf("Backed up the state of %d task(s) to '%s'", count, *c.out))
+	return ExitCodeOK
+}
@@ -0,0 +1,176 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/consul-terraform-sync/client"
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/mitchellh/go-wordwrap"
+	"github.com/posener/complete"
+)
+
+const cmdStateRestoreName = "state restore"
+
+// stateRestoreCommand handles the `state restore` command
+type stateRestoreCommand struct {
+	meta
+	configFiles *config.FlagAppendSliceValue
+	in          *string
+	flags       *flag.FlagSet
+}
+
+func newStateRestoreCommand(m meta) *stateRestoreCommand {
+	logging.DisableLogging()
+	flags := flag.NewFlagSet(cmdStateRestoreName, flag.ContinueOnError)
+	flags.SetOutput(m.writer)
+
+	var configFiles config.FlagAppendSliceValue
+	addConfigFlags(flags, &configFiles)
+	in := flags.String(flagIn, "", "[Required] The path of the backup archive "+
+		"to restore, which was \n\t\twritten by the 'state backup' command.")
+
+	m.outputFlag(flags)
+	m.flags = flags
+	return &stateRestoreCommand{
+		meta:        m,
+		configFiles: &configFiles,
+		in:          in,
+		flags:       flags,
+	}
+}
+
+// Name returns the subcommand
+func (c *stateRestoreCommand) Name() string {
+	return cmdStateRestoreName
+}
+
+// Help returns the command's usage, list of flags, and examples
+func (c *stateRestoreCommand) Help() string {
+	c.meta.setHelpOptions()
+	helpText := fmt.Sprintf(`
+Usage: consul-terraform-sync state restore [-help] [options]
+
+  State Restore restores the Terraform state of tasks from a backup archive
+  that was written by the 'state backup' command. State stored in Consul KV is
+  restored to the same KV keys. State of tasks using the local backend is
+  restored to the task's working directory in the Consul-Terraform-Sync
+  configuration.
+
+  Existing state is overwritten. Stop Consul-Terraform-Sync before restoring
+  state so that no tasks are running. Nothing is restored if the archive has a
+  Consul KV key that is not the state of a task configured with the Consul
+  backend, or if the state of a task is locked.
+
+Options:
+%s
+
+Example:
+
+  $ consul-terraform-sync state restore -config-file=config.hcl -in=backup.tgz
+`, strings.Join(c.meta.helpOptions, "\n"))
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis is a short one-line synopsis of the command
+func (c *stateRestoreCommand) Synopsis() string {
+	return "Restores the Terraform state of tasks from a backup."
+}
+
+// AutocompleteFlags returns a mapping of supported flags and autocomplete
+// options for this command. The map key for the Flags map should be the
+// complete flag such as "-foo" or "--foo".
+func (c *stateRestoreCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		fmt.Sprintf("-%s", flagConfigDir): complete.PredictDirs("*"),
+		fmt.Sprintf("-%s", flagConfigFiles): complete.PredictOr(
+			complete.PredictFiles("*.hcl"),
+			complete.PredictFiles("*.json"),
+		),
+		fmt.Sprintf("-%s", flagIn):     complete.PredictFiles("*"),
+		fmt.Sprintf("-%s", FlagOutput): complete.PredictSet(OutputTable, OutputJSON),
+	}
+}
+
+// AutocompleteArgs returns the argument predictor for this command.
+// Since argument completion is not supported, this will return
+// complete.PredictNothing.
+func (c *stateRestoreCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+// Run runs the command
+func (c *stateRestoreCommand) Run(args []string) int {
+	c.flags.Usage = func() { c.meta.UI.Output(c.Help()) }
+	if err := c.flags.Parse(args); err != nil {
+		return ExitCodeParseFlagsError
+	}
+	if !c.meta.setupOutput() {
+		return ExitCodeRequiredFlagsError
+	}
+
+	if len(*c.configFiles) == 0 || *c.in == "" {
+		c.UI.Error(fmt.Sprintf("Error: the -%s and one of the -%s or -%s flags "+
+			"are required", flagIn, flagConfigFiles, flagConfigDir))
+		c.UI.Output(fmt.Sprintf("For additional help try 'consul-terraform-sync %s --help'",
+			c.Name()))
+		return ExitCodeRequiredFlagsError
+	}
+
+	conf, err := loadStateConfig(*c.configFiles)
+	if err != nil {
+		c.UI.Error("Error: unable to load configuration")
+		c.UI.Output(wordwrap.WrapString(err.Error(), width))
+		return ExitCodeConfigError
+	}
+
+	states, err := taskStates(conf)
+	if err != nil {
+		c.UI.Error("Error: unable to locate the state of tasks")
+		c.UI.Output(wordwrap.WrapString(err.Error(), width))
+		return ExitCodeConfigError
+	}
+
+	f, err := os.Open(*c.in)
+	if err != nil {
+		c.UI.Error("Error: unable to open backup archive")
+		c.UI.Output(wordwrap.WrapString(err.Error(), width))
+		return ExitCodeError
+	}
+	defer f.Close()
+
+	// State in Consul KV is restored with the Consul configuration of CTS
+	consul, err := client.NewConsulClient(conf.Consul, client.ConsulDefaultMaxRetry)
+	if err != nil {
+		c.UI.Error("Error: unable to create Consul client")
+		c.UI.Output(wordwrap.WrapString(err.Error(), width))
+		return ExitCodeError
+	}
+
+	count, skipped, err := restoreState(f, consul.KV(), states)
+	for _, msg := range skipped {
+		c.UI.Warn(fmt.Sprintf("Skipped %s", msg))
+	}
+	if err != nil {
+		c.UI.Error("Error: unable to restore state")
+		c.UI.Output(wordwrap.WrapString(err.Error(), width))
+		return ExitCodeError
+	}
+
+	if c.outputJSON() {
+		return c.writeJSON(stateArchiveResult{
+			Archive: *c.in,
+			Count:   count,
+			Skipped: append([]string{}, skipped...),
+		})
+	}
+
+	c.UI.Info(fmt.Sprintf("Restored %d state file(s) from '%s'", count, *c.in))
+	return ExitCodeOK
+}
@@ -0,0 +1,308 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStateKV is an in-memory stateKV for testing
+type fakeStateKV map[string][]byte
+
+// Get returns the pair of the key. Lock keys are held by a session.
+func (kv fakeStateKV) Get(key string, q *consulapi.QueryOptions) (*consulapi.KVPair, *consulapi.QueryMeta, error) {
+	v, ok := kv[key]
+	if !ok {
+		return nil, nil, nil
+	}
+	p := &consulapi.KVPair{Key: key, Value: v}
+	if strings.HasSuffix(key, "/.lock") {
+		p.Session = "session"
+	}
+	return p, nil, nil
+}
+
+func (kv fakeStateKV) List(prefix string, q *consulapi.QueryOptions) (consulapi.KVPairs, *consulapi.QueryMeta, error) {
+	var pairs consulapi.KVPairs
+	for k, v := range kv {
+		if strings.HasPrefix(k, prefix) {
+			pairs = append(pairs, &consulapi.KVPair{Key: k, Value: v})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].Key < pairs[j].Key })
+	return pairs, nil, nil
+}
+
+func (kv fakeStateKV) Put(p *consulapi.KVPair, q *consulapi.WriteOptions) (*consulapi.WriteMeta, error) {
+	kv[p.Key] = p.Value
+	return nil, nil
+}
+
+func TestState_BackupRestore(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	localPath := filepath.Join(dir, "task_b", "terraform.tfstate.d", "task_b",
+		"terraform.tfstate")
+	require.NoError(t, os.MkdirAll(filepath.Dir(localPath), 0755))
+	require.NoError(t, os.WriteFile(localPath, []byte("local-state"), 0644))
+
+	kv := fakeStateKV{
+		"consul-terraform-sync/terraform-env:task_a":           []byte("consul-state"),
+		"consul-terraform-sync/terraform-env:task_a/.lock":     []byte("lock"),
+		"consul-terraform-sync/terraform-env:task_a/.lockinfo": []byte("lockinfo"),
+		"consul-terraform-sync/terraform-env:task_ab":          []byte("other-state"),
+	}
+
+	states := []taskState{
+		{
+			task:    "task_a",
+			backend: "consul",
+			key:     "consul-terraform-sync/terraform-env:task_a",
+		},
+		{
+			task:    "task_b",
+			backend: "local",
+			path:    localPath,
+		},
+		{
+			task:    "task_c",
+			backend: "local",
+			path:    filepath.Join(dir, "task_c", "terraform.tfstate"),
+		},
+		{
+			task:    "task_d",
+			backend: "s3",
+		},
+	}
+
+	var archive bytes.Buffer
+	count, skipped, err := backupState(&archive, kv, states)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	require.Len(t, skipped, 2)
+	assert.Contains(t, skipped[0], "task_c")
+	assert.Contains(t, skipped[1], "task_d")
+
+	// restore to an empty Consul KV and a new working directory
+	restoreKV := fakeStateKV{}
+	restorePath := filepath.Join(t.TempDir(), "terraform.tfstate")
+	restoreStates := []taskState{
+		{
+			task:    "task_a",
+			backend: "consul",
+			key:     "consul-terraform-sync/terraform-env:task_a",
+		},
+		{task: "task_b", backend: "local", path: restorePath},
+	}
+
+	count, skipped, err = restoreState(&archive, restoreKV, restoreStates)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Empty(t, skipped)
+
+	assert.Equal(t, fakeStateKV{
+		"consul-terraform-sync/terraform-env:task_a": []byte("consul-state"),
+	}, restoreKV)
+
+	content, err := os.ReadFile(restorePath)
+	require.NoError(t, err)
+	assert.Equal(t, "local-state", string(content))
+}
+
+func TestState_RestoreUnknownTask(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	localPath := filepath.Join(dir, "terraform.tfstate")
+	require.NoError(t, os.WriteFile(localPath, []byte("local-state"), 0644))
+
+	var archive bytes.Buffer
+	_, _, err := backupState(&archive, nil, []taskState{
+		{task: "task", backend: "local", path: localPath},
+	})
+	require.NoError(t, err)
+
+	count, skipped, err := restoreState(&archive, fakeStateKV{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	require.Len(t, skipped, 1)
+	assert.Contains(t, skipped[0], "not configured with a local backend")
+}
+
+func TestState_RestoreRejected(t *testing.T) {
+	t.Parallel()
+
+	var archive bytes.Buffer
+	_, _, err := backupState(&archive, fakeStateKV{
+		"consul-terraform-sync/terraform-env:task_a": []byte("consul-state"),
+		"other/key": []byte("other"),
+	}, []taskState{
+		{task: "task_a", backend: "consul", key: "consul-terraform-sync/terraform-env:task_a"},
+		{task: "task_b", backend: "consul", key: "other/key"},
+	})
+	require.NoError(t, err)
+	content := archive.Bytes()
+
+	t.Run("key not configured", func(t *testing.T) {
+		kv := fakeStateKV{}
+		_, _, err := restoreState(bytes.NewReader(content), kv, []taskState{
+			{task: "task_a", backend: "consul", key: "consul-terraform-sync/terraform-env:task_a"},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "'other/key' that is not the state")
+		assert.Empty(t, kv, "no state should be restored")
+	})
+
+	t.Run("key of task with other backend", func(t *testing.T) {
+		kv := fakeStateKV{}
+		_, _, err := restoreState(bytes.NewReader(content), kv, []taskState{
+			{task: "task_a", backend: "consul", key: "consul-terraform-sync/terraform-env:task_a"},
+			{task: "task_b", backend: "local", path: filepath.Join(t.TempDir(), "tfstate")},
+		})
+		require.Error(t, err)
+		assert.Empty(t, kv, "no state should be restored")
+	})
+
+	t.Run("state locked", func(t *testing.T) {
+		kv := fakeStateKV{
+			"other/key/.lock": []byte("lock"),
+		}
+		_, _, err := restoreState(bytes.NewReader(content), kv, []taskState{
+			{task: "task_a", backend: "consul", key: "consul-terraform-sync/terraform-env:task_a"},
+			{task: "task_b", backend: "consul", key: "other/key"},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "state of task 'task_b' is locked")
+		assert.Equal(t, fakeStateKV{"other/key/.lock": []byte("lock")}, kv,
+			"no state should be restored")
+	})
+}
+
+func TestState_RestoreInvalidArchive(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := restoreState(strings.NewReader("not an archive"), fakeStateKV{}, nil)
+	assert.Error(t, err)
+}
+
+func TestIsStateKey(t *testing.T) {
+	t.Parallel()
+
+	stateKey := "consul-terraform-sync/terraform-env:task"
+	cases := []struct {
+		name     string
+		key      string
+		expected bool
+	}{
+		{"state key", stateKey, true},
+		{"chunk", stateKey + "/tfstate.0", true},
+		{"lock", stateKey + "/.lock", false},
+		{"lock info", stateKey + "/.lockinfo", false},
+		{"shared prefix", stateKey + "_b", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isStateKey(stateKey, tc.key))
+		})
+	}
+}
+
+func TestLocalStatePath(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		workspace string
+		args      map[string]interface{}
+		expected  string
+	}{
+		{
+			name:      "default workspace",
+			workspace: "default",
+			expected:  filepath.Join("sync-tasks", "task", "terraform.tfstate"),
+		},
+		{
+			name:      "named workspace",
+			workspace: "task",
+			expected: filepath.Join("sync-tasks", "task", "terraform.tfstate.d",
+				"task", "terraform.tfstate"),
+		},
+		{
+			name:      "workspace dir",
+			workspace: "task",
+			args:      map[string]interface{}{"workspace_dir": "states"},
+			expected: filepath.Join("sync-tasks", "task", "states", "task",
+				"terraform.tfstate"),
+		},
+		{
+			name:      "absolute path",
+			workspace: "default",
+			args:      map[string]interface{}{"path": "/states/task.tfstate"},
+			expected:  "/states/task.tfstate",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := localStatePath(filepath.Join("sync-tasks", "task"),
+				tc.workspace, tc.args)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestStateCommands_RequiredFlags(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		newCmd func(meta) cli.Command
+		args   []string
+	}{
+		{
+			name:   "backup missing out",
+			newCmd: func(m meta) cli.Command { return newStateBackupCommand(m) },
+			args:   []string{"-config-file", "config.hcl"},
+		},
+		{
+			name:   "backup missing config",
+			newCmd: func(m meta) cli.Command { return newStateBackupCommand(m) },
+			args:   []string{"-out", "backup.tgz"},
+		},
+		{
+			name:   "restore missing in",
+			newCmd: func(m meta) cli.Command { return newStateRestoreCommand(m) },
+			args:   []string{"-config-file", "config.hcl"},
+		},
+		{
+			name:   "restore missing config",
+			newCmd: func(m meta) cli.Command { return newStateRestoreCommand(m) },
+			args:   []string{"-in", "backup.tgz"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var b bytes.Buffer
+			ui := cli.NewMockUi()
+			cmd := tc.newCmd(meta{UI: ui, writer: &b})
+
+			status := cmd.Run(tc.args)
+			assert.Equal(t, ExitCodeRequiredFlagsError, status)
+			assert.Contains(t, ui.ErrorWriter.String(), "flags are required")
+		})
+	}
+}
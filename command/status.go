@@ -0,0 +1,271 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/api"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/mitchellh/go-wordwrap"
+	"github.com/posener/complete"
+)
+
+const (
+	cmdStatusName = "status"
+
+	FlagWatch    = "watch"
+	FlagInterval = "interval"
+
+	defaultStatusInterval = 2 * time.Second
+
+	// clearScreen is the ANSI escape sequence to move the cursor to the top
+	// left of the terminal and clear the screen
+	clearScreen = "\033[H\033[2J"
+)
+
+// statusCommand handles the `status` command
+type statusCommand struct {
+	meta
+	watch    *bool
+	interval *time.Duration
+	flags    *flag.FlagSet
+}
+
+func newStatusCommand(m meta) *statusCommand {
+	logging.DisableLogging()
+	flags := m.defaultFlagSet(cmdStatusName)
+	flags.SetOutput(m.writer)
+	w := flags.Bool(FlagWatch, false, "Continuously refresh the status summary "+
+		"until interrupted")
+	i := flags.Duration(FlagInterval, defaultStatusInterval, "The interval to "+
+		"refresh the status summary in watch mode")
+	return &statusCommand{
+		meta:     m,
+		watch:    w,
+		interval: i,
+		flags:    flags,
+	}
+}
+
+// Name returns the subcommand
+func (c *statusCommand) Name() string {
+	return cmdStatusName
+}
+
+// Help returns the command's usage, list of flags, and examples
+func (c *statusCommand) Help() string {
+	c.meta.setHelpOptions()
+	helpText := fmt.Sprintf(`
+Usage: consul-terraform-sync status [-help] [options]
+
+  Status is used to display a summary of the status of all tasks. For each
+  task, the summary includes whether the task is enabled, the status of the
+  task, when the task last ran and how long the run took, and the number of
+  runs pending for the task.
+
+  With the -watch flag, the summary is refreshed at the -interval until the
+  command is interrupted.
+
+  With the -output=json flag, the status of each task is output as JSON,
+  including the events of the task and their IDs. The -watch flag is not
+  supported for JSON output.
+
+Options:
+%s
+
+Example:
+
+  $ consul-terraform-sync status -watch -interval=5s
+  TASK       ENABLED    STATUS        LAST RUN    DURATION    PENDING
+  my_task    true       successful    12s ago     3.2s        0
+`, strings.Join(c.meta.helpOptions, "\n"))
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis is a short one-line synopsis of the command
+func (c *statusCommand) Synopsis() string {
+	return "Displays a summary of the status of all tasks."
+}
+
+// AutocompleteFlags returns a mapping of supported flags and autocomplete
+// options for this command. The map key for the Flags map should be the
+// complete flag such as "-foo" or "--foo".
+func (c *statusCommand) AutocompleteFlags() complete.Flags {
+	return mergeAutocompleteFlags(c.meta.autoCompleteFlags(),
+		complete.Flags{
+			fmt.Sprintf("-%s", FlagWatch):    complete.PredictNothing,
+			fmt.Sprintf("-%s", FlagInterval): complete.PredictAnything,
+		})
+}
+
+// AutocompleteArgs returns the argument predictor for this command.
+// Since status does not take any arguments, no arguments are predicted.
+func (c *statusCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+// Run runs the command
+func (c *statusCommand) Run(args []string) int {
+	c.meta.setFlagsUsage(c.flags, args, c.Help())
+
+	if err := c.flags.Parse(args); err != nil {
+		return ExitCodeParseFlagsError
+	}
+
+	if len(c.flags.Args()) > 0 {
+		c.UI.Error(fmt.Sprintf("Error: command '%s' does not take any arguments",
+			c.Name()))
+		c.UI.Output(fmt.Sprintf("For additional help try 'consul-terraform-sync %s --help'",
+			c.Name()))
+		return ExitCodeRequiredFlagsError
+	}
+
+	if !c.meta.setupOutput() {
+		return ExitCodeRequiredFlagsError
+	}
+
+	if *c.watch && c.meta.outputJSON() {
+		c.UI.Error(fmt.Sprintf("Error: the -%s flag is not supported with "+
+			"the -%s=%s flag", FlagWatch, FlagOutput, OutputJSON))
+		return ExitCodeRequiredFlagsError
+	}
+
+	if *c.interval <= 0 {
+		c.UI.Error(fmt.Sprintf("Error: the -%s flag must be a positive duration",
+			FlagInterval))
+		return ExitCodeRequiredFlagsError
+	}
+
+	client, err := c.meta.client()
+	if err != nil {
+		c.UI.Error(errCreatingClient)
+		msg := wordwrap.WrapString(err.Error(), width)
+		c.UI.Output(msg)
+		return ExitCodeError
+	}
+
+	if c.meta.outputJSON() {
+		statuses, err := c.statuses(client)
+		if err != nil {
+			c.UI.Error("Error: unable to retrieve the status of tasks")
+			msg := wordwrap.WrapString(err.Error(), width)
+			c.UI.Output(msg)
+			c.meta.warnVersionSkew()
+			return ExitCodeError
+		}
+		return c.meta.writeJSON(statuses)
+	}
+
+	if !*c.watch {
+		summary, err := c.summary(client)
+		if err != nil {
+			c.UI.Error("Error: unable to retrieve the status of tasks")
+			msg := wordwrap.WrapString(err.Error(), width)
+			c.UI.Output(msg)
+			c.meta.warnVersionSkew()
+			return ExitCodeError
+		}
+		fmt.Fprint(c.meta.writer, summary)
+		return ExitCodeOK
+	}
+
+	interruptCh := make(chan os.Signal, 1)
+	signal.Notify(interruptCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interruptCh)
+
+	ticker := time.NewTicker(*c.interval)
+	defer ticker.Stop()
+
+	for {
+		// Errors are displayed in place of the summary and the summary
+		// continues to refresh, e.g. while CTS is restarting
+		summary, err := c.summary(client)
+		if err != nil {
+			summary = fmt.Sprintf("Error: unable to retrieve the status of "+
+				"tasks\n%s\n", wordwrap.WrapString(err.Error(), width))
+		}
+		fmt.Fprintf(c.meta.writer, "%s%s\nRefreshing every %s at %s. "+
+			"Press Ctrl+C to exit.\n", clearScreen, summary, *c.interval,
+			time.Now().Format(time.Kitchen))
+
+		select {
+		case <-interruptCh:
+			return ExitCodeOK
+		case <-ticker.C:
+		}
+	}
+}
+
+// summary requests the status of all tasks and returns the formatted summary
+func (c *statusCommand) summary(client *api.Client) (string, error) {
+	statuses, err := c.statuses(client)
+	if err != nil {
+		return "", err
+	}
+	return formatStatusSummary(statuses, time.Now()), nil
+}
+
+// statuses requests the status of all tasks including their events
+func (c *statusCommand) statuses(client *api.Client) (map[string]api.TaskStatus, error) {
+	statuses, err := client.Status().Task("", &api.QueryParam{IncludeEvents: true})
+	if err != nil {
+		return nil, processEOFError(client.Scheme(), err)
+	}
+	if statuses == nil {
+		return nil, errors.New("empty response for the status of tasks")
+	}
+	return statuses, nil
+}
+
+// formatStatusSummary formats the status of the tasks as a table sorted by
+// task name. The last run is the latest event of a task that ran the task,
+// relative to now.
+func formatStatusSummary(statuses map[string]api.TaskStatus, now time.Time) string {
+	if len(statuses) == 0 {
+		return "No tasks\n"
+	}
+
+	names := make([]string, 0, len(statuses))
+	for name := range statuses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	tw := tabwriter.NewWriter(&b, 0, 2, 4, ' ', 0)
+	fmt.Fprintln(tw, "TASK\tENABLED\tSTATUS\tLAST RUN\tDURATION\tPENDING")
+	for _, name := range names {
+		s := statuses[name]
+		lastRun, duration := "-", "-"
+		for _, e := range s.Events {
+			// suppressed, degraded, and expired events do not run the task
+			if e.Suppressed || e.Degraded || e.Expired || e.StartTime.IsZero() {
+				continue
+			}
+			lastRun = fmt.Sprintf("%s ago",
+				now.Sub(e.StartTime).Truncate(time.Second))
+			if !e.EndTime.IsZero() {
+				duration = e.EndTime.Sub(e.StartTime).Round(
+					100 * time.Millisecond).String()
+			}
+			break
+		}
+		fmt.Fprintf(tw, "%s\t%t\t%s\t%s\t%s\t%d\n", name, s.Enabled, s.Status,
+			lastRun, duration, s.PendingRuns)
+	}
+	tw.Flush()
+
+	return b.String()
+}
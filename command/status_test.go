@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/api"
+	"github.com/hashicorp/consul-terraform-sync/state/event"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusCommand_AutocompleteFlags(t *testing.T) {
+	t.Parallel()
+	cmd := newStatusCommand(meta{UI: cli.NewMockUi()})
+
+	predictor := cmd.AutocompleteFlags()
+
+	// Test that we get the expected number of predictions
+	args := complete.Args{Last: "-"}
+	res := predictor.Predict(args)
+
+	// Grab the list of flags from the Flag object
+	flags := make([]string, 0)
+	cmd.flags.VisitAll(func(flag *flag.Flag) {
+		flags = append(flags, fmt.Sprintf("-%s", flag.Name))
+	})
+
+	// Verify that there is a prediction for each flag associated with the command
+	assert.Equal(t, len(flags), len(res))
+	assert.ElementsMatch(t, flags, res, "flags and predictions didn't match, make sure to add "+
+		"new flags to the command AutoCompleteFlags function")
+}
+
+func TestStatusCommand_Run_Errors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{
+			"argument",
+			[]string{"task_a"},
+			"does not take any arguments",
+		},
+		{
+			"zero interval",
+			[]string{"-watch", "-interval=0s"},
+			"must be a positive duration",
+		},
+		{
+			"watch with json output",
+			[]string{"-watch", "-output=json"},
+			"-watch flag is not supported with the -output=json flag",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			cmd := newStatusCommand(meta{UI: ui})
+
+			exitCode := cmd.Run(tc.args)
+			assert.Equal(t, ExitCodeRequiredFlagsError, exitCode)
+			assert.Contains(t, ui.ErrorWriter.String(), tc.contains)
+		})
+	}
+}
+
+func TestStatusCommand_Run_Output(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/status/tasks", r.URL.Path)
+		fmt.Fprint(w, `{"task_a":{"task_name":"task_a","status":"successful",`+
+			`"enabled":true,"events":[{"id":"e9926514-79b8-a8fc-8761-9b6aaccf1e15",`+
+			`"success":true,"task_name":"task_a"}]}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Run("table", func(t *testing.T) {
+		var b bytes.Buffer
+		ui := cli.NewMockUi()
+		cmd := newStatusCommand(meta{UI: ui, writer: &b})
+
+		exitCode := cmd.Run([]string{"-http-addr", server.URL})
+		assert.Equal(t, ExitCodeOK, exitCode)
+		assert.Contains(t, b.String(), "task_a")
+		assert.NotContains(t, b.String(), "{")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var b bytes.Buffer
+		ui := cli.NewMockUi()
+		cmd := newStatusCommand(meta{UI: ui, writer: &b})
+
+		exitCode := cmd.Run([]string{"-http-addr", server.URL, "-output", "json"})
+		assert.Equal(t, ExitCodeOK, exitCode)
+		assert.Empty(t, ui.OutputWriter.String())
+
+		var statuses map[string]api.TaskStatus
+		require.NoError(t, json.Unmarshal(b.Bytes(), &statuses))
+		require.Contains(t, statuses, "task_a")
+		assert.Equal(t, "successful", statuses["task_a"].Status)
+		require.Len(t, statuses["task_a"].Events, 1)
+		assert.Equal(t, "e9926514-79b8-a8fc-8761-9b6aaccf1e15",
+			statuses["task_a"].Events[0].ID)
+	})
+}
+
+func TestFormatStatusSummary(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, time.May, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("no tasks", func(t *testing.T) {
+		assert.Equal(t, "No tasks\n", formatStatusSummary(nil, now))
+	})
+
+	t.Run("tasks", func(t *testing.T) {
+		statuses := map[string]api.TaskStatus{
+			"web": {
+				TaskName:    "web",
+				Status:      api.StatusSuccessful,
+				Enabled:     true,
+				PendingRuns: 1,
+				Events: []event.Event{
+					{
+						// suppressed events are skipped for the last run
+						Suppressed: true,
+						StartTime:  now.Add(-5 * time.Second),
+						EndTime:    now.Add(-5 * time.Second),
+					},
+					{
+						Success:   true,
+						StartTime: now.Add(-90 * time.Second),
+						EndTime:   now.Add(-90*time.Second + 3200*time.Millisecond),
+					},
+				},
+			},
+			"db": {
+				TaskName: "db",
+				Status:   api.StatusUnknown,
+				Enabled:  false,
+			},
+		}
+
+		expected := "" +
+			"TASK    ENABLED    STATUS        LAST RUN     DURATION    PENDING\n" +
+			"db      false      unknown       -            -           0\n" +
+			"web     true       successful    1m30s ago    3.2s        1\n"
+		assert.Equal(t, expected, formatStatusSummary(statuses, now))
+	})
+}
@@ -119,6 +119,10 @@ func (c *taskCreateCommand) Run(args []string) int {
 		return ExitCodeParseFlagsError
 	}
 
+	if !c.meta.setupOutput() || !c.meta.requireAutoApproveJSON(*c.autoApprove) {
+		return ExitCodeRequiredFlagsError
+	}
+
 	// Check that a task file was provided
 	taskFile := *c.taskFile
 	if len(taskFile) == 0 {
@@ -203,6 +207,7 @@ func (c *taskCreateCommand) Run(args []string) int {
 		c.UI.Error(fmt.Sprintf("Error: unable to generate plan for '%s'", taskName))
 		msg := wordwrap.WrapString(err.Error(), uint(78))
 		c.UI.Output(msg)
+		c.meta.warnVersionSkew()
 
 		return ExitCodeError
 	}
@@ -241,6 +246,7 @@ func (c *taskCreateCommand) Run(args []string) int {
 		c.UI.Error(fmt.Sprintf("Error: unable to create '%s'", taskName))
 		msg := wordwrap.WrapString(err.Error(), uint(78))
 		c.UI.Output(msg)
+		c.meta.warnVersionSkew()
 
 		return ExitCodeError
 	}
@@ -249,11 +255,26 @@ func (c *taskCreateCommand) Run(args []string) int {
 		c.UI.Error(fmt.Sprintf("Error: received nil response with status %s", resp.Status()))
 		return ExitCodeError
 	}
+	inspectRun := taskResp.Run
 	taskResp = resp.JSON201
 
 	c.UI.Info(fmt.Sprintf("Task '%s' created", taskResp.Task.Name))
 	c.UI.Output(fmt.Sprintf("Request ID: '%s'", taskResp.RequestId))
 
+	if c.meta.outputJSON() {
+		result := taskResult{
+			Task:           taskResp.Task.Name,
+			Action:         "create",
+			Success:        true,
+			RequestID:      requestIDString(taskResp.RequestId),
+			EventID:        c.meta.latestEventID(taskResp.Task.Name),
+			ChangesPresent: inspectRun.ChangesPresent,
+			Plan:           config.StringVal(inspectRun.Plan),
+			TFCRunURL:      config.StringVal(inspectRun.TfcRunUrl),
+		}
+		return c.meta.writeJSON(result)
+	}
+
 	return ExitCodeOK
 }
 
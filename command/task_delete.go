@@ -135,6 +135,10 @@ func (c *taskDeleteCommand) Run(args []string) int {
 		return ExitCodeRequiredFlagsError
 	}
 
+	if !c.meta.setupOutput() || !c.meta.requireAutoApproveJSON(*c.autoApprove) {
+		return ExitCodeRequiredFlagsError
+	}
+
 	taskName := args[0]
 
 	client, err := c.meta.taskLifecycleClient()
@@ -154,16 +158,14 @@ func (c *taskDeleteCommand) Run(args []string) int {
 	}
 
 	c.UI.Info(fmt.Sprintf("Marking task '%s' for deletion...\n", taskName))
-	resp, err := client.DeleteTaskByName(context.Background(), taskName)
-	if resp != nil {
-		defer resp.Body.Close()
-	}
+	resp, err := client.DeleteTaskByNameWithResponse(context.Background(), taskName)
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error: unable to delete '%s'", taskName))
 		err = processEOFError(client.Scheme(), err)
 
 		msg := wordwrap.WrapString(err.Error(), uint(78))
 		c.UI.Output(msg)
+		c.meta.warnVersionSkew()
 
 		return ExitCodeError
 	}
@@ -171,5 +173,13 @@ func (c *taskDeleteCommand) Run(args []string) int {
 	c.UI.Info(fmt.Sprintf("Task '%s' has been marked for deletion "+
 		"and will be deleted when not running.", taskName))
 
+	if c.meta.outputJSON() {
+		result := taskResult{Task: taskName, Action: "delete", Success: true}
+		if resp.JSON202 != nil {
+			result.RequestID = requestIDString(resp.JSON202.RequestId)
+		}
+		return c.meta.writeJSON(result)
+	}
+
 	return ExitCodeOK
 }
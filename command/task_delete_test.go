@@ -78,7 +78,7 @@ func TestTaskDeleteCommand_AutocompleteArgs(t *testing.T) {
 			}
 
 			// Return the response, and expect each task name to be present in the prediction
-			p.On("GetAllTasksWithResponse", mock.Anything).Return(&resp, nil)
+			p.On("GetAllTasksWithResponse", mock.Anything, mock.Anything).Return(&resp, nil)
 
 			predictor := cmd.AutocompleteArgs()
 
@@ -119,10 +119,10 @@ func TestTaskDeleteCommand_AutocompleteArgs_Errors(t *testing.T) {
 			switch tc.scenario {
 			case scenarioClientError:
 				err := errors.New("some error")
-				p.On("GetAllTasksWithResponse", mock.Anything).Return(nil, err)
+				p.On("GetAllTasksWithResponse", mock.Anything, mock.Anything).Return(nil, err)
 			case scenarioEmptyTasks:
 				resp := oapigen.GetAllTasksResponse{}
-				p.On("GetAllTasksWithResponse", mock.Anything).Return(&resp, nil)
+				p.On("GetAllTasksWithResponse", mock.Anything, mock.Anything).Return(&resp, nil)
 			}
 
 			predictor := cmd.AutocompleteArgs()
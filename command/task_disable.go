@@ -23,6 +23,9 @@ const cmdTaskDisableName = "task disable"
 type taskDisableCommand struct {
 	meta
 
+	all             *bool
+	filter          *string
+	label           *string
 	flags           *flag.FlagSet
 	predictorClient oapigen.ClientWithResponsesInterface
 }
@@ -31,9 +34,18 @@ func newTaskDisableCommand(m meta) *taskDisableCommand {
 	logging.DisableLogging()
 	flags := m.defaultFlagSet(cmdTaskDisableName)
 	flags.SetOutput(m.writer)
+	all := flags.Bool(FlagAll, false, "Disable all tasks instead of a single task")
+	filter := flags.String(FlagFilter, "", fmt.Sprintf("A regular expression to "+
+		"only disable tasks with matching names.\n\t\tRequires the -%s flag.", FlagAll))
+	label := flags.String(FlagLabel, "", fmt.Sprintf("Comma-separated key=value "+
+		"labels to only disable tasks that have all\n\t\tof the labels. "+
+		"Requires the -%s flag.", FlagAll))
 	return &taskDisableCommand{
-		meta:  m,
-		flags: flags,
+		meta:   m,
+		all:    all,
+		filter: filter,
+		label:  label,
+		flags:  flags,
 	}
 }
 
@@ -47,10 +59,15 @@ func (c *taskDisableCommand) Help() string {
 	c.meta.setHelpOptions()
 	helpText := fmt.Sprintf(`
 Usage: consul-terraform-sync task disable [-help] [options] <task name>
+       consul-terraform-sync task disable [-help] [options] -all [-filter=<regex>] [-label=<key=value,...>]
 
   Task Disable is used to disable existing tasks. Once disabled, a task will no
   longer run and make changes to your network infrastructure resources.
 
+  With the -all flag, all tasks are disabled at once, or only the tasks with
+  names that match the -filter regular expression and that have all of the
+  -label labels.
+
 Options:
 %s
 
@@ -60,6 +77,17 @@ Example:
     ==> Waiting to disable 'Test_2'...
 
     ==> 'Test_2' disable complete!
+
+  $ consul-terraform-sync task disable -all -filter="^web-"
+    ==> Waiting to disable tasks...
+
+    ==> 'web-api' disable complete!
+    ==> 'web-ui' disable complete!
+
+  $ consul-terraform-sync task disable -all -label="team=neteng"
+    ==> Waiting to disable tasks...
+
+    ==> 'lb-web' disable complete!
 `, strings.Join(c.meta.helpOptions, "\n"))
 	return strings.TrimSpace(helpText)
 }
@@ -73,7 +101,12 @@ func (c *taskDisableCommand) Synopsis() string {
 // options for this command. The map key for the Flags map should be the
 // complete flag such as "-foo" or "--foo".
 func (c *taskDisableCommand) AutocompleteFlags() complete.Flags {
-	return c.meta.autoCompleteFlags()
+	return mergeAutocompleteFlags(c.meta.autoCompleteFlags(),
+		complete.Flags{
+			fmt.Sprintf("-%s", FlagAll):    complete.PredictNothing,
+			fmt.Sprintf("-%s", FlagFilter): complete.PredictAnything,
+			fmt.Sprintf("-%s", FlagLabel):  complete.PredictAnything,
+		})
 }
 
 // AutocompleteArgs returns the argument predictor for this command.
@@ -118,7 +151,27 @@ func (c *taskDisableCommand) Run(args []string) int {
 		return ExitCodeParseFlagsError
 	}
 
+	if !c.meta.setupOutput() {
+		return ExitCodeRequiredFlagsError
+	}
+
 	args = c.flags.Args()
+	if *c.all {
+		return c.disableAll(args)
+	}
+
+	if *c.filter != "" {
+		c.UI.Error(fmt.Sprintf("Error: the -%s flag requires the -%s flag",
+			FlagFilter, FlagAll))
+		return ExitCodeRequiredFlagsError
+	}
+
+	if *c.label != "" {
+		c.UI.Error(fmt.Sprintf("Error: the -%s flag requires the -%s flag",
+			FlagLabel, FlagAll))
+		return ExitCodeRequiredFlagsError
+	}
+
 	if ok := c.meta.oneArgCheck(c.Name(), args); !ok {
 		return ExitCodeRequiredFlagsError
 	}
@@ -137,7 +190,7 @@ func (c *taskDisableCommand) Run(args []string) int {
 		return ExitCodeError
 	}
 
-	_, err = client.Task().Update(taskName, api.UpdateTaskConfig{
+	resp, err := client.Task().Update(taskName, api.UpdateTaskConfig{
 		Enabled: config.Bool(false),
 	}, nil)
 	if err != nil {
@@ -146,11 +199,107 @@ func (c *taskDisableCommand) Run(args []string) int {
 
 		msg := wordwrap.WrapString(err.Error(), uint(78))
 		c.UI.Output(msg)
+		c.meta.warnVersionSkew()
 
 		return ExitCodeError
 	}
 
 	c.UI.Info(fmt.Sprintf("'%s' disable complete!", taskName))
 
+	if c.meta.outputJSON() {
+		return c.meta.writeJSON(taskResult{
+			Task:      taskName,
+			Action:    "disable",
+			Success:   true,
+			RequestID: requestIDString(resp.RequestID),
+		})
+	}
+
 	return ExitCodeOK
 }
+
+// disableAll disables all tasks, or the tasks with names that match the
+// filter and that have the labels, with a single batch request
+func (c *taskDisableCommand) disableAll(args []string) int {
+	if len(args) > 0 {
+		c.UI.Error(fmt.Sprintf("Error: the -%s flag does not support a task "+
+			"name argument", FlagAll))
+		c.UI.Output(fmt.Sprintf("%d arguments were passed to the command: '%s'",
+			len(args), strings.Join(args, ", ")))
+		return ExitCodeRequiredFlagsError
+	}
+
+	labels, err := config.ParseLabelSelector(*c.label)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error: unable to parse the -%s flag", FlagLabel))
+		c.UI.Output(err.Error())
+		return ExitCodeRequiredFlagsError
+	}
+
+	filter := *c.filter
+	if filter == "" {
+		filter = ".*"
+	}
+
+	c.UI.Info("Waiting to disable tasks...")
+	c.UI.Output("")
+
+	client, err := c.meta.client()
+	if err != nil {
+		c.UI.Error("Error: unable to create client to disable tasks")
+		msg := wordwrap.WrapString(err.Error(), uint(78))
+		c.UI.Output(msg)
+
+		return ExitCodeError
+	}
+
+	resp, err := client.Task().Batch(api.TaskBatchRequest{
+		Operation: api.BatchOperationDisable,
+		Filter:    filter,
+		Labels:    labels,
+	})
+	if err != nil {
+		c.UI.Error("Error: unable to disable tasks")
+		err = processEOFError(client.Scheme(), err)
+
+		msg := wordwrap.WrapString(err.Error(), uint(78))
+		c.UI.Output(msg)
+		c.meta.warnVersionSkew()
+
+		return ExitCodeError
+	}
+
+	if len(resp.Results) == 0 {
+		c.UI.Info("No tasks to disable")
+	}
+
+	exitCode := ExitCodeOK
+	results := taskResults{
+		RequestID: requestIDString(resp.RequestId),
+		Results:   make([]taskResult, 0, len(resp.Results)),
+	}
+	for _, result := range resp.Results {
+		results.Results = append(results.Results, taskResult{
+			Task:    result.Task,
+			Action:  "disable",
+			Success: result.Success,
+			Error:   result.Error,
+		})
+		if !result.Success {
+			c.UI.Error(fmt.Sprintf("Error: unable to disable '%s'", result.Task))
+			msg := wordwrap.WrapString(result.Error, uint(78))
+			c.UI.Output(msg)
+			exitCode = ExitCodeError
+			continue
+		}
+		c.UI.Info(fmt.Sprintf("'%s' disable complete!", result.Task))
+	}
+
+	if c.meta.outputJSON() {
+		if code := c.meta.writeJSON(results); code != ExitCodeOK {
+			return code
+		}
+	}
+
+	return exitCode
+}
@@ -4,18 +4,24 @@
 package command
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/google/uuid"
+	"github.com/hashicorp/consul-terraform-sync/api"
 	"github.com/hashicorp/consul-terraform-sync/api/oapigen"
 	mocks "github.com/hashicorp/consul-terraform-sync/mocks/api"
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTaskDisableCommand_AutocompleteFlags(t *testing.T) {
@@ -91,7 +97,7 @@ func TestTaskDisableCommand_AutocompleteArgs(t *testing.T) {
 			}
 
 			// Return the response, and expect only enabled task names to be present in the prediction
-			p.On("GetAllTasksWithResponse", mock.Anything).Return(&resp, nil)
+			p.On("GetAllTasksWithResponse", mock.Anything, mock.Anything).Return(&resp, nil)
 
 			predictor := cmd.AutocompleteArgs()
 
@@ -132,10 +138,10 @@ func TestTaskDisableCommand_AutocompleteArgs_Errors(t *testing.T) {
 			switch tc.scenario {
 			case scenarioClientError:
 				err := errors.New("some error")
-				p.On("GetAllTasksWithResponse", mock.Anything).Return(nil, err)
+				p.On("GetAllTasksWithResponse", mock.Anything, mock.Anything).Return(nil, err)
 			case scenarioEmptyTasks:
 				resp := oapigen.GetAllTasksResponse{}
-				p.On("GetAllTasksWithResponse", mock.Anything).Return(&resp, nil)
+				p.On("GetAllTasksWithResponse", mock.Anything, mock.Anything).Return(&resp, nil)
 			}
 
 			predictor := cmd.AutocompleteArgs()
@@ -145,3 +151,118 @@ func TestTaskDisableCommand_AutocompleteArgs_Errors(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskDisableCommand_Run_AllErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		args     []string
+		contains string
+	}{
+		{
+			"filter without all",
+			[]string{"-filter=^web-"},
+			"requires the -all flag",
+		},
+		{
+			"label without all",
+			[]string{"-label=team=neteng"},
+			"requires the -all flag",
+		},
+		{
+			"invalid label",
+			[]string{"-all", "-label=team"},
+			"unable to parse the -label flag",
+		},
+		{
+			"all with task name",
+			[]string{"-all", "task_a"},
+			"does not support a task name argument",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ui := cli.NewMockUi()
+			cmd := newTaskDisableCommand(meta{UI: ui})
+
+			exitCode := cmd.Run(tc.args)
+			assert.Equal(t, ExitCodeRequiredFlagsError, exitCode)
+			assert.Contains(t, ui.ErrorWriter.String(), tc.contains)
+		})
+	}
+}
+
+func TestTaskDisableCommand_Run_AllLabels(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v1/tasks/batch", r.URL.Path)
+
+		var req api.TaskBatchRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, api.TaskBatchRequest{
+			Operation: api.BatchOperationDisable,
+			Filter:    "^lb-",
+			Labels:    map[string]string{"team": "neteng", "env": "prod"},
+		}, req)
+		fmt.Fprint(w, `{"request_id":"e9926514-79b8-a8fc-8761-9b6aaccf1e15",`+
+			`"results":[{"task":"lb-web","success":true}]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	ui := cli.NewMockUi()
+	cmd := newTaskDisableCommand(meta{UI: ui})
+
+	exitCode := cmd.Run([]string{"-http-addr", server.URL, "-all",
+		"-filter=^lb-", "-label=team=neteng,env=prod"})
+	assert.Equal(t, ExitCodeOK, exitCode)
+	assert.Contains(t, ui.OutputWriter.String(), "'lb-web' disable complete!")
+}
+
+func TestTaskDisableCommand_Run_Output(t *testing.T) {
+	t.Parallel()
+
+	requestID := "e9926514-79b8-a8fc-8761-9b6aaccf1e15"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/v1/tasks/task_a", r.URL.Path)
+		fmt.Fprintf(w, `{"request_id":%q}`, requestID)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Run("table", func(t *testing.T) {
+		var b bytes.Buffer
+		ui := cli.NewMockUi()
+		cmd := newTaskDisableCommand(meta{UI: ui, writer: &b})
+
+		exitCode := cmd.Run([]string{"-http-addr", server.URL, "task_a"})
+		assert.Equal(t, ExitCodeOK, exitCode)
+		assert.Contains(t, ui.OutputWriter.String(), "'task_a' disable complete!")
+		assert.Empty(t, b.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var b bytes.Buffer
+		ui := cli.NewMockUi()
+		cmd := newTaskDisableCommand(meta{UI: ui, writer: &b})
+
+		exitCode := cmd.Run([]string{"-http-addr", server.URL, "-output", "json", "task_a"})
+		assert.Equal(t, ExitCodeOK, exitCode)
+
+		// Human-readable messages are written to stderr
+		assert.Empty(t, ui.OutputWriter.String())
+		assert.Contains(t, ui.ErrorWriter.String(), "'task_a' disable complete!")
+
+		var result taskResult
+		require.NoError(t, json.Unmarshal(b.Bytes(), &result))
+		assert.Equal(t, taskResult{
+			Task:      "task_a",
+			Action:    "disable",
+			Success:   true,
+			RequestID: requestID,
+		}, result)
+	})
+}
@@ -144,6 +144,10 @@ func (c *taskEnableCommand) Run(args []string) int {
 		return ExitCodeRequiredFlagsError
 	}
 
+	if !c.meta.setupOutput() || !c.meta.requireAutoApproveJSON(*c.autoApprove) {
+		return ExitCodeRequiredFlagsError
+	}
+
 	taskName := args[0]
 
 	c.UI.Info(fmt.Sprintf("Inspecting changes to resource if enabling '%s'...\n",
@@ -168,6 +172,7 @@ func (c *taskEnableCommand) Run(args []string) int {
 
 		msg := wordwrap.WrapString(err.Error(), uint(78))
 		c.UI.Output(msg)
+		c.meta.warnVersionSkew()
 
 		return ExitCodeError
 	}
@@ -178,19 +183,33 @@ func (c *taskEnableCommand) Run(args []string) int {
 
 	c.UI.Output(resp.Inspect.Plan)
 
+	result := taskResult{
+		Task:           taskName,
+		Action:         "enable",
+		Success:        true,
+		ChangesPresent: config.Bool(resp.Inspect.ChangesPresent),
+		Plan:           resp.Inspect.Plan,
+		TFCRunURL:      resp.Inspect.URL,
+	}
+
 	if !resp.Inspect.ChangesPresent {
 		// enable the task but no need to run it now
-		_, err = client.Task().Update(taskName, api.UpdateTaskConfig{
+		resp, err = client.Task().Update(taskName, api.UpdateTaskConfig{
 			Enabled: config.Bool(true)}, nil)
 		if err != nil {
 			c.UI.Error(fmt.Sprintf("Error: unable to enable '%s'", taskName))
 			msg := wordwrap.WrapString(err.Error(), uint(78))
 			c.UI.Output(msg)
+			c.meta.warnVersionSkew()
 
 			return ExitCodeError
 		}
 
 		c.UI.Info(fmt.Sprintf("'%s' enable complete!", taskName))
+		if c.meta.outputJSON() {
+			result.RequestID = requestIDString(resp.RequestID)
+			return c.meta.writeJSON(result)
+		}
 		return ExitCodeOK
 	}
 
@@ -201,17 +220,23 @@ func (c *taskEnableCommand) Run(args []string) int {
 	}
 
 	c.UI.Info(fmt.Sprintf("Enabling and running '%s'...\n", taskName))
-	_, err = client.Task().Update(taskName, api.UpdateTaskConfig{
+	resp, err = client.Task().Update(taskName, api.UpdateTaskConfig{
 		Enabled: config.Bool(true),
 	}, &api.QueryParam{Run: driver.RunOptionNow})
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error: unable to enable and run '%s'", taskName))
 		msg := wordwrap.WrapString(err.Error(), uint(78))
 		c.UI.Output(msg)
+		c.meta.warnVersionSkew()
 
 		return ExitCodeError
 	}
 
 	c.UI.Info(fmt.Sprintf("'%s' enable complete!", taskName))
+	if c.meta.outputJSON() {
+		result.RequestID = requestIDString(resp.RequestID)
+		result.EventID = c.meta.latestEventID(taskName)
+		return c.meta.writeJSON(result)
+	}
 	return ExitCodeOK
 }
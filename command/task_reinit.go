@@ -0,0 +1,184 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/consul-terraform-sync/api/oapigen"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/mitchellh/go-wordwrap"
+	"github.com/posener/complete"
+)
+
+const cmdTaskReinitName = "task reinit"
+
+// taskReinitCommand handles the `task reinit` command
+type taskReinitCommand struct {
+	meta
+	flags *flag.FlagSet
+
+	predictorClient oapigen.ClientWithResponsesInterface
+}
+
+func newTaskReinitCommand(m meta) *taskReinitCommand {
+	logging.DisableLogging()
+	flags := m.defaultFlagSet(cmdTaskReinitName)
+	flags.SetOutput(m.writer)
+	return &taskReinitCommand{
+		meta:  m,
+		flags: flags,
+	}
+}
+
+// Name returns the subcommand
+func (c *taskReinitCommand) Name() string {
+	return cmdTaskReinitName
+}
+
+// Help returns the command's usage, list of flags, and examples
+func (c *taskReinitCommand) Help() string {
+	c.meta.setHelpOptions()
+	helpText := fmt.Sprintf(`
+Usage: consul-terraform-sync task reinit [-help] [options] <task name>
+
+  Task Reinit is used to re-initialize an existing task without restarting
+  Consul-Terraform-Sync, e.g. after changing the files of a local module on
+  disk. The root module of the task is regenerated, Terraform init and
+  validate are re-run, and the template of the task is rendered with the
+  latest data of its dependencies, without running the task.
+
+Options:
+%s
+
+Example:
+
+  $ consul-terraform-sync task reinit my_task
+    ==> Reinitializing 'my_task'...
+
+    Rendered files:
+      sync-tasks/my_task/terraform.tfvars
+
+    ==> 'my_task' reinit complete!
+`, strings.Join(c.meta.helpOptions, "\n"))
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis is a short one-line synopsis of the command
+func (c *taskReinitCommand) Synopsis() string {
+	return "Re-initializes a task without restarting."
+}
+
+// AutocompleteFlags returns a mapping of supported flags and autocomplete
+// options for this command. The map key for the Flags map should be the
+// complete flag such as "-foo" or "--foo".
+func (c *taskReinitCommand) AutocompleteFlags() complete.Flags {
+	return c.meta.autoCompleteFlags()
+}
+
+// AutocompleteArgs returns the argument predictor for this command.
+// This commands uses a client to fetch a list of existing tasks
+// to predict the correct reinit argument
+func (c *taskReinitCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		var client oapigen.ClientWithResponsesInterface
+		var err error
+		if c.predictorClient == nil {
+			client, err = c.meta.taskLifecycleClient()
+			if err != nil {
+				return nil
+			}
+		} else {
+			client = c.predictorClient
+		}
+
+		tasksResp, err := getTasks(context.Background(), client)
+		if err != nil {
+			return nil
+		}
+
+		taskNames := make([]string, 0)
+		if tasksResp.Tasks != nil {
+			for _, task := range *tasksResp.Tasks {
+				taskNames = append(taskNames, task.Name)
+			}
+		}
+		return taskNames
+	})
+}
+
+// Run runs the command
+func (c *taskReinitCommand) Run(args []string) int {
+	c.meta.setFlagsUsage(c.flags, args, c.Help())
+
+	if err := c.flags.Parse(args); err != nil {
+		return ExitCodeParseFlagsError
+	}
+
+	if !c.meta.setupOutput() {
+		return ExitCodeRequiredFlagsError
+	}
+
+	args = c.flags.Args()
+	if ok := c.meta.oneArgCheck(c.Name(), args); !ok {
+		return ExitCodeRequiredFlagsError
+	}
+
+	taskName := args[0]
+
+	c.UI.Info(fmt.Sprintf("Reinitializing '%s'...", taskName))
+	c.UI.Output("")
+
+	client, err := c.meta.client()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error: unable to create client for '%s'", taskName))
+		msg := wordwrap.WrapString(err.Error(), uint(78))
+		c.UI.Output(msg)
+
+		return ExitCodeError
+	}
+
+	resp, err := client.Task().Reinit(taskName)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error: unable to reinitialize '%s'", taskName))
+		err = processEOFError(client.Scheme(), err)
+
+		msg := wordwrap.WrapString(err.Error(), uint(78))
+		c.UI.Output(msg)
+		c.meta.warnVersionSkew()
+
+		return ExitCodeError
+	}
+
+	result := taskResult{
+		Task:      taskName,
+		Action:    "reinit",
+		Success:   true,
+		RequestID: requestIDString(resp.RequestId),
+		Render:    &resp.Render,
+	}
+
+	if !resp.Render.Complete {
+		// the workspace is re-initialized, but the template is rendered once
+		// the data of the dependencies is fetched
+		c.UI.Output("The data of the dependencies of the task is not yet " +
+			"fetched from Consul. The template will be rendered once the " +
+			"data is fetched.\n")
+	} else if len(resp.Render.Files) > 0 {
+		c.UI.Output("Rendered files:")
+		for _, f := range resp.Render.Files {
+			c.UI.Output("  " + f)
+		}
+		c.UI.Output("")
+	}
+
+	c.UI.Info(fmt.Sprintf("'%s' reinit complete!", taskName))
+	if c.meta.outputJSON() {
+		return c.meta.writeJSON(result)
+	}
+	return ExitCodeOK
+}
@@ -0,0 +1,192 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/consul-terraform-sync/api/oapigen"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/mitchellh/go-wordwrap"
+	"github.com/posener/complete"
+)
+
+const cmdTaskRenderName = "task render"
+
+// taskRenderCommand handles the `task render` command
+type taskRenderCommand struct {
+	meta
+	flags *flag.FlagSet
+
+	predictorClient oapigen.ClientWithResponsesInterface
+}
+
+func newTaskRenderCommand(m meta) *taskRenderCommand {
+	logging.DisableLogging()
+	flags := m.defaultFlagSet(cmdTaskRenderName)
+	flags.SetOutput(m.writer)
+	return &taskRenderCommand{
+		meta:  m,
+		flags: flags,
+	}
+}
+
+// Name returns the subcommand
+func (c *taskRenderCommand) Name() string {
+	return cmdTaskRenderName
+}
+
+// Help returns the command's usage, list of flags, and examples
+func (c *taskRenderCommand) Help() string {
+	c.meta.setHelpOptions()
+	helpText := fmt.Sprintf(`
+Usage: consul-terraform-sync task render [-help] [options] <task name>
+
+  Task Render is used to render the template of an existing task with the
+  latest data of its dependencies and write the terraform.tfvars file of the
+  task to disk, without running Terraform. Use it to verify the data that the
+  task would run with, e.g. after changing Consul, without making changes to
+  your network infrastructure resources.
+
+Options:
+%s
+
+Example:
+
+  $ consul-terraform-sync task render my_task
+    ==> Rendering 'my_task'...
+
+    Rendered files:
+      sync-tasks/my_task/terraform.tfvars
+
+    ==> 'my_task' render complete!
+`, strings.Join(c.meta.helpOptions, "\n"))
+	return strings.TrimSpace(helpText)
+}
+
+// Synopsis is a short one-line synopsis of the command
+func (c *taskRenderCommand) Synopsis() string {
+	return "Renders the template of a task without running it."
+}
+
+// AutocompleteFlags returns a mapping of supported flags and autocomplete
+// options for this command. The map key for the Flags map should be the
+// complete flag such as "-foo" or "--foo".
+func (c *taskRenderCommand) AutocompleteFlags() complete.Flags {
+	return c.meta.autoCompleteFlags()
+}
+
+// AutocompleteArgs returns the argument predictor for this command.
+// This commands uses a client to fetch a list of existing tasks
+// to predict the correct render argument
+func (c *taskRenderCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		var client oapigen.ClientWithResponsesInterface
+		var err error
+		if c.predictorClient == nil {
+			client, err = c.meta.taskLifecycleClient()
+			if err != nil {
+				return nil
+			}
+		} else {
+			client = c.predictorClient
+		}
+
+		tasksResp, err := getTasks(context.Background(), client)
+		if err != nil {
+			return nil
+		}
+
+		taskNames := make([]string, 0)
+		if tasksResp.Tasks != nil {
+			for _, task := range *tasksResp.Tasks {
+				taskNames = append(taskNames, task.Name)
+			}
+		}
+		return taskNames
+	})
+}
+
+// Run runs the command
+func (c *taskRenderCommand) Run(args []string) int {
+	c.meta.setFlagsUsage(c.flags, args, c.Help())
+
+	if err := c.flags.Parse(args); err != nil {
+		return ExitCodeParseFlagsError
+	}
+
+	if !c.meta.setupOutput() {
+		return ExitCodeRequiredFlagsError
+	}
+
+	args = c.flags.Args()
+	if ok := c.meta.oneArgCheck(c.Name(), args); !ok {
+		return ExitCodeRequiredFlagsError
+	}
+
+	taskName := args[0]
+
+	c.UI.Info(fmt.Sprintf("Rendering '%s'...", taskName))
+	c.UI.Output("")
+
+	client, err := c.meta.client()
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error: unable to create client for '%s'", taskName))
+		msg := wordwrap.WrapString(err.Error(), uint(78))
+		c.UI.Output(msg)
+
+		return ExitCodeError
+	}
+
+	resp, err := client.Task().Render(taskName)
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error: unable to render '%s'", taskName))
+		err = processEOFError(client.Scheme(), err)
+
+		msg := wordwrap.WrapString(err.Error(), uint(78))
+		c.UI.Output(msg)
+		c.meta.warnVersionSkew()
+
+		return ExitCodeError
+	}
+
+	result := taskResult{
+		Task:      taskName,
+		Action:    "render",
+		Success:   resp.Render.Complete,
+		RequestID: requestIDString(resp.RequestId),
+		Render:    &resp.Render,
+	}
+
+	if !resp.Render.Complete {
+		c.UI.Error(fmt.Sprintf("Error: unable to render '%s'", taskName))
+		c.UI.Output("The data of the dependencies of the task is not yet " +
+			"fetched from Consul. Try again once the data is fetched.")
+		if c.meta.outputJSON() {
+			result.Error = "dependencies not yet fetched"
+			c.meta.writeJSON(result)
+		}
+		return ExitCodeError
+	}
+
+	if len(resp.Render.Files) > 0 {
+		c.UI.Output("Rendered files:")
+		for _, f := range resp.Render.Files {
+			c.UI.Output("  " + f)
+		}
+		c.UI.Output("")
+	}
+	if !resp.Render.Changed {
+		c.UI.Output("The rendered files did not change.\n")
+	}
+
+	c.UI.Info(fmt.Sprintf("'%s' render complete!", taskName))
+	if c.meta.outputJSON() {
+		return c.meta.writeJSON(result)
+	}
+	return ExitCodeOK
+}
@@ -0,0 +1,113 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRenderCommand_AutocompleteFlags(t *testing.T) {
+	t.Parallel()
+	cmd := newTaskRenderCommand(meta{UI: cli.NewMockUi()})
+
+	predictor := cmd.AutocompleteFlags()
+
+	// Test that we get the expected number of predictions
+	args := complete.Args{Last: "-"}
+	res := predictor.Predict(args)
+
+	// Grab the list of flags from the Flag object
+	flags := make([]string, 0)
+	cmd.flags.VisitAll(func(flag *flag.Flag) {
+		flags = append(flags, fmt.Sprintf("-%s", flag.Name))
+	})
+
+	// Verify that there is a prediction for each flag associated with the command
+	assert.Equal(t, len(flags), len(res))
+	assert.ElementsMatch(t, flags, res, "flags and predictions didn't match, make sure to add "+
+		"new flags to the command AutoCompleteFlags function")
+}
+
+func TestTaskRenderCommand_Run_Output(t *testing.T) {
+	t.Parallel()
+
+	requestID := "e9926514-79b8-a8fc-8761-9b6aaccf1e15"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		switch r.URL.Path {
+		case "/v1/tasks/task_a/render":
+			fmt.Fprintf(w, `{"request_id":%q,"render":{"complete":true,`+
+				`"changed":true,"files":["sync-tasks/task_a/terraform.tfvars"]}}`, requestID)
+		case "/v1/tasks/task_b/render":
+			fmt.Fprintf(w, `{"request_id":%q,"render":{"complete":false,`+
+				`"changed":false}}`, requestID)
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	t.Run("table", func(t *testing.T) {
+		var b bytes.Buffer
+		ui := cli.NewMockUi()
+		cmd := newTaskRenderCommand(meta{UI: ui, writer: &b})
+
+		exitCode := cmd.Run([]string{"-http-addr", server.URL, "task_a"})
+		assert.Equal(t, ExitCodeOK, exitCode)
+		output := ui.OutputWriter.String()
+		assert.Contains(t, output, "sync-tasks/task_a/terraform.tfvars")
+		assert.Contains(t, output, "'task_a' render complete!")
+		assert.Empty(t, b.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var b bytes.Buffer
+		ui := cli.NewMockUi()
+		cmd := newTaskRenderCommand(meta{UI: ui, writer: &b})
+
+		exitCode := cmd.Run([]string{"-http-addr", server.URL, "-output", "json", "task_a"})
+		assert.Equal(t, ExitCodeOK, exitCode)
+
+		// Human-readable messages are written to stderr
+		assert.Empty(t, ui.OutputWriter.String())
+		assert.Contains(t, ui.ErrorWriter.String(), "'task_a' render complete!")
+
+		var result taskResult
+		require.NoError(t, json.Unmarshal(b.Bytes(), &result))
+		assert.Equal(t, taskResult{
+			Task:      "task_a",
+			Action:    "render",
+			Success:   true,
+			RequestID: requestID,
+			Render: &driver.RenderResult{
+				Complete: true,
+				Changed:  true,
+				Files:    []string{"sync-tasks/task_a/terraform.tfvars"},
+			},
+		}, result)
+	})
+
+	t.Run("not complete", func(t *testing.T) {
+		var b bytes.Buffer
+		ui := cli.NewMockUi()
+		cmd := newTaskRenderCommand(meta{UI: ui, writer: &b})
+
+		exitCode := cmd.Run([]string{"-http-addr", server.URL, "task_b"})
+		assert.Equal(t, ExitCodeError, exitCode)
+		assert.Contains(t, ui.ErrorWriter.String(), "unable to render 'task_b'")
+		assert.Contains(t, ui.OutputWriter.String(), "not yet fetched")
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/consul-terraform-sync/api"
+	"github.com/hashicorp/consul-terraform-sync/version"
+	goversion "github.com/hashicorp/go-version"
+	"github.com/mitchellh/go-wordwrap"
+)
+
+// daemonVersionClient looks up the version of the CTS daemon
+type daemonVersionClient interface {
+	Version() (api.VersionResponse, error)
+}
+
+// warnVersionSkew warns if the version of the CTS daemon differs from the
+// version of the CLI. Intended to be called after a request to the daemon
+// fails, since a request that the daemon does not support can fail with an
+// error that does not point to the difference in versions.
+func (m *meta) warnVersionSkew() {
+	client, err := m.client()
+	if err != nil {
+		return
+	}
+
+	if msg := versionSkewWarning(client, version.GetSemanticVersion()); msg != "" {
+		m.UI.Warn(wordwrap.WrapString(msg, width))
+	}
+}
+
+// versionSkewWarning returns a warning if the version of the CTS daemon
+// differs from the version of the CLI, or an empty string if the versions
+// match or the version of the daemon is unable to be looked up.
+func versionSkewWarning(client daemonVersionClient, cliVersion string) string {
+	v, err := client.Version()
+	if err != nil {
+		var respErr *api.ResponseError
+		if errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound {
+			return fmt.Sprintf("Warning: the CTS daemon does not support the "+
+				"version endpoint and is older than the CLI (v%s). The request "+
+				"may not be supported by the daemon. Use a CLI with the same "+
+				"version as the daemon.", cliVersion)
+		}
+		return ""
+	}
+
+	if v.ApiVersion != api.APIVersion {
+		return fmt.Sprintf("Warning: the CTS daemon serves API version %s "+
+			"but the CLI uses API version %s. Use a CLI with the same version "+
+			"as the daemon (v%s).", v.ApiVersion, api.APIVersion, v.Version)
+	}
+
+	daemon, err := goversion.NewVersion(v.Version)
+	if err != nil {
+		return ""
+	}
+	cli, err := goversion.NewVersion(cliVersion)
+	if err != nil {
+		return ""
+	}
+
+	switch {
+	case daemon.LessThan(cli):
+		return fmt.Sprintf("Warning: the CTS daemon (v%s) is older than the "+
+			"CLI (v%s). The request may use options that the daemon does not "+
+			"support. Use a CLI with the same version as the daemon.",
+			v.Version, cliVersion)
+	case daemon.GreaterThan(cli):
+		return fmt.Sprintf("Warning: the CTS daemon (v%s) is newer than the "+
+			"CLI (v%s). The CLI may not support the responses of the daemon. "+
+			"Upgrade the CLI to the same version as the daemon.",
+			v.Version, cliVersion)
+	}
+	return ""
+}
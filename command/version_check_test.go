@@ -0,0 +1,111 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/api"
+	"github.com/mitchellh/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+// testVersionClient returns the version response and error
+type testVersionClient struct {
+	resp api.VersionResponse
+	err  error
+}
+
+func (c testVersionClient) Version() (api.VersionResponse, error) {
+	return c.resp, c.err
+}
+
+func TestVersionSkewWarning(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		client   testVersionClient
+		expected string
+	}{
+		{
+			"same version",
+			testVersionClient{resp: api.VersionResponse{
+				Version: "0.7.1", ApiVersion: api.APIVersion}},
+			"",
+		},
+		{
+			"older daemon",
+			testVersionClient{resp: api.VersionResponse{
+				Version: "0.6.0", ApiVersion: api.APIVersion}},
+			"is older than the CLI",
+		},
+		{
+			"newer daemon",
+			testVersionClient{resp: api.VersionResponse{
+				Version: "0.8.0", ApiVersion: api.APIVersion}},
+			"is newer than the CLI",
+		},
+		{
+			"different api version",
+			testVersionClient{resp: api.VersionResponse{
+				Version: "1.0.0", ApiVersion: "v2"}},
+			"serves API version v2",
+		},
+		{
+			"daemon without version endpoint",
+			testVersionClient{err: &api.ResponseError{
+				StatusCode: http.StatusNotFound}},
+			"does not support the version endpoint",
+		},
+		{
+			"daemon unreachable",
+			testVersionClient{err: errors.New("connection refused")},
+			"",
+		},
+		{
+			"invalid version",
+			testVersionClient{resp: api.VersionResponse{
+				Version: "unknown", ApiVersion: api.APIVersion}},
+			"",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			msg := versionSkewWarning(tc.client, "0.7.1")
+			if tc.expected == "" {
+				assert.Empty(t, msg)
+				return
+			}
+			assert.Contains(t, msg, tc.expected)
+		})
+	}
+}
+
+func TestTaskDisableCommand_Run_VersionSkew(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/version" {
+			fmt.Fprint(w, `{"version":"0.1.0","api_version":"v1","features":[]}`)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error":{"message":"request body has an unknown field"}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	ui := cli.NewMockUi()
+	cmd := newTaskDisableCommand(meta{UI: ui})
+
+	exitCode := cmd.Run([]string{"-http-addr", server.URL, "task_a"})
+	assert.Equal(t, ExitCodeError, exitCode)
+	assert.Contains(t, ui.ErrorWriter.String(), "Error: unable to disable 'task_a'")
+	assert.Contains(t, ui.ErrorWriter.String(), "the CTS daemon (v0.1.0) is older than the CLI")
+}
@@ -0,0 +1,481 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAlertmanagerTimeout is the default time limit for requests to
+	// the Alertmanager
+	DefaultAlertmanagerTimeout = 10 * time.Second
+
+	// DefaultAlertmanagerSilenceDuration is the default duration of the
+	// silence that is created when a task applies changes
+	DefaultAlertmanagerSilenceDuration = 15 * time.Minute
+)
+
+// AlertmanagerConfig configures the Prometheus Alertmanager integration.
+// Tasks configured with an alertmanager_silence block create a temporary
+// silence in the Alertmanager before applying changes, so that alerts of the
+// expected reconvergence do not page.
+type AlertmanagerConfig struct {
+	// Enabled determines if the integration is enabled. Enabled by default
+	// if the address is configured.
+	Enabled *bool `mapstructure:"enabled" json:"enabled"`
+
+	// Address is the URL of the Alertmanager, e.g. http://localhost:9093
+	Address *string `mapstructure:"address" json:"address"`
+
+	// Token is an optional bearer token to authenticate with the Alertmanager
+	Token *string `mapstructure:"token" json:"token"`
+
+	// Timeout is the time limit for requests to the Alertmanager. Defaults
+	// to 10s.
+	Timeout *time.Duration `mapstructure:"timeout" json:"timeout"`
+}
+
+// DefaultAlertmanagerConfig returns a configuration that is populated with
+// the default values.
+func DefaultAlertmanagerConfig() *AlertmanagerConfig {
+	return &AlertmanagerConfig{}
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *AlertmanagerConfig) Copy() *AlertmanagerConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o AlertmanagerConfig
+	o.Enabled = BoolCopy(c.Enabled)
+	o.Address = StringCopy(c.Address)
+	o.Token = StringCopy(c.Token)
+	o.Timeout = TimeDurationCopy(c.Timeout)
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+// Maps and slices are merged, most other values are overwritten. Complex
+// structs define their own merge functionality.
+func (c *AlertmanagerConfig) Merge(o *AlertmanagerConfig) *AlertmanagerConfig {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+
+	if o.Enabled != nil {
+		r.Enabled = BoolCopy(o.Enabled)
+	}
+
+	if o.Address != nil {
+		r.Address = StringCopy(o.Address)
+	}
+
+	if o.Token != nil {
+		r.Token = StringCopy(o.Token)
+	}
+
+	if o.Timeout != nil {
+		r.Timeout = TimeDurationCopy(o.Timeout)
+	}
+
+	return r
+}
+
+// Finalize ensures there no nil pointers.
+func (c *AlertmanagerConfig) Finalize() {
+	if c == nil {
+		return
+	}
+
+	if c.Enabled == nil {
+		c.Enabled = Bool(StringPresent(c.Address))
+	}
+
+	if c.Address == nil {
+		c.Address = String("")
+	}
+
+	if c.Token == nil {
+		c.Token = String("")
+	}
+
+	if c.Timeout == nil {
+		c.Timeout = TimeDuration(DefaultAlertmanagerTimeout)
+	}
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *AlertmanagerConfig) Validate() error {
+	if !c.IsEnabled() {
+		return nil
+	}
+
+	u, err := url.Parse(StringVal(c.Address))
+	if err != nil {
+		return fmt.Errorf("alertmanager: unable to parse address: %s", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("alertmanager: address %q must be an http or https "+
+			"URL", StringVal(c.Address))
+	}
+
+	if TimeDurationVal(c.Timeout) < 0 {
+		return fmt.Errorf("alertmanager: timeout cannot be negative")
+	}
+
+	return nil
+}
+
+// IsEnabled returns true if the Alertmanager integration is enabled
+func (c *AlertmanagerConfig) IsEnabled() bool {
+	if c == nil {
+		return false
+	}
+	return BoolVal(c.Enabled)
+}
+
+// GoString defines the printable version of this struct.
+// Sensitive information is redacted.
+func (c *AlertmanagerConfig) GoString() string {
+	if c == nil {
+		return "(*AlertmanagerConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&AlertmanagerConfig{"+
+		"Enabled:%t, "+
+		"Address:%s, "+
+		"Token:%s, "+
+		"Timeout:%s"+
+		"}",
+		BoolVal(c.Enabled),
+		StringVal(c.Address),
+		sensitiveGoString(c.Token),
+		TimeDurationVal(c.Timeout),
+	)
+}
+
+// AlertmanagerSilenceConfig configures a task to create a temporary silence in
+// the Alertmanager before it applies changes. The silence matches the alerts
+// with labels that match all of its matchers and expires after the duration.
+// Requires the top-level alertmanager block.
+type AlertmanagerSilenceConfig struct {
+	// Enabled determines if the task creates silences. Enabled by default if
+	// the block is configured.
+	Enabled *bool `mapstructure:"enabled" json:"enabled"`
+
+	// Duration is how long the silence lasts from the start of the apply.
+	// Defaults to 15m.
+	Duration *time.Duration `mapstructure:"duration" json:"duration"`
+
+	// Comment is the comment of the silence. Defaults to a comment with the
+	// name of the task.
+	Comment *string `mapstructure:"comment" json:"comment"`
+
+	// Matchers match the labels of the alerts to silence
+	Matchers *AlertmanagerMatcherConfigs `mapstructure:"matcher" json:"matcher"`
+}
+
+// AlertmanagerMatcherConfig configures a matcher of an Alertmanager silence
+type AlertmanagerMatcherConfig struct {
+	// Name is the name of the label
+	Name *string `mapstructure:"name" json:"name"`
+
+	// Value is the value of the label, or a regular expression if Regex is
+	// true
+	Value *string `mapstructure:"value" json:"value"`
+
+	// Regex determines if the value is a regular expression. Defaults to false.
+	Regex *bool `mapstructure:"regex" json:"regex"`
+
+	// Equal determines if the label matches the value, or does not match the
+	// value if false. Defaults to true.
+	Equal *bool `mapstructure:"equal" json:"equal"`
+}
+
+// AlertmanagerMatcherConfigs is a collection of AlertmanagerMatcherConfig
+type AlertmanagerMatcherConfigs []*AlertmanagerMatcherConfig
+
+// Copy returns a deep copy of this configuration.
+func (c *AlertmanagerSilenceConfig) Copy() *AlertmanagerSilenceConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o AlertmanagerSilenceConfig
+	o.Enabled = BoolCopy(c.Enabled)
+	o.Duration = TimeDurationCopy(c.Duration)
+	o.Comment = StringCopy(c.Comment)
+	o.Matchers = c.Matchers.Copy()
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+// Matchers of the other configuration are appended.
+func (c *AlertmanagerSilenceConfig) Merge(o *AlertmanagerSilenceConfig) *AlertmanagerSilenceConfig {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+
+	if o.Enabled != nil {
+		r.Enabled = BoolCopy(o.Enabled)
+	}
+
+	if o.Duration != nil {
+		r.Duration = TimeDurationCopy(o.Duration)
+	}
+
+	if o.Comment != nil {
+		r.Comment = StringCopy(o.Comment)
+	}
+
+	if o.Matchers != nil {
+		r.Matchers = r.Matchers.Merge(o.Matchers)
+	}
+
+	return r
+}
+
+// Finalize ensures there are no nil pointers.
+func (c *AlertmanagerSilenceConfig) Finalize() {
+	if c == nil {
+		return
+	}
+
+	if c.Enabled == nil {
+		// some options configured, assume user intention is enabled
+		c.Enabled = Bool(c.Duration != nil || c.Comment != nil ||
+			c.Matchers.Len() > 0)
+	}
+
+	if c.Duration == nil {
+		c.Duration = TimeDuration(DefaultAlertmanagerSilenceDuration)
+	}
+
+	if c.Comment == nil {
+		c.Comment = String("")
+	}
+
+	if c.Matchers == nil {
+		c.Matchers = &AlertmanagerMatcherConfigs{}
+	}
+	c.Matchers.Finalize()
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *AlertmanagerSilenceConfig) Validate() error {
+	if c == nil || !BoolVal(c.Enabled) {
+		return nil
+	}
+
+	if TimeDurationVal(c.Duration) <= 0 {
+		return fmt.Errorf("alertmanager_silence: duration must be greater than 0")
+	}
+
+	if c.Matchers.Len() == 0 {
+		return fmt.Errorf("alertmanager_silence: at least one matcher is required")
+	}
+
+	return c.Matchers.Validate()
+}
+
+// GoString defines the printable version of this struct.
+func (c *AlertmanagerSilenceConfig) GoString() string {
+	if c == nil {
+		return "(*AlertmanagerSilenceConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&AlertmanagerSilenceConfig{"+
+		"Enabled:%t, "+
+		"Duration:%s, "+
+		"Comment:%s, "+
+		"Matchers:%s"+
+		"}",
+		BoolVal(c.Enabled),
+		TimeDurationVal(c.Duration),
+		StringVal(c.Comment),
+		c.Matchers.GoString(),
+	)
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *AlertmanagerMatcherConfig) Copy() *AlertmanagerMatcherConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o AlertmanagerMatcherConfig
+	o.Name = StringCopy(c.Name)
+	o.Value = StringCopy(c.Value)
+	o.Regex = BoolCopy(c.Regex)
+	o.Equal = BoolCopy(c.Equal)
+	return &o
+}
+
+// Finalize ensures there are no nil pointers.
+func (c *AlertmanagerMatcherConfig) Finalize() {
+	if c == nil {
+		return
+	}
+
+	if c.Name == nil {
+		c.Name = String("")
+	}
+
+	if c.Value == nil {
+		c.Value = String("")
+	}
+
+	if c.Regex == nil {
+		c.Regex = Bool(false)
+	}
+
+	if c.Equal == nil {
+		c.Equal = Bool(true)
+	}
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *AlertmanagerMatcherConfig) Validate() error {
+	if c == nil {
+		return fmt.Errorf("alertmanager_silence matcher: missing configuration")
+	}
+
+	if StringVal(c.Name) == "" {
+		return fmt.Errorf("alertmanager_silence matcher: name is required")
+	}
+
+	if StringVal(c.Value) == "" {
+		return fmt.Errorf("alertmanager_silence matcher: value is required "+
+			"for label %q", StringVal(c.Name))
+	}
+
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *AlertmanagerMatcherConfig) GoString() string {
+	if c == nil {
+		return "(*AlertmanagerMatcherConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&AlertmanagerMatcherConfig{"+
+		"Name:%s, "+
+		"Value:%s, "+
+		"Regex:%t, "+
+		"Equal:%t"+
+		"}",
+		StringVal(c.Name),
+		StringVal(c.Value),
+		BoolVal(c.Regex),
+		BoolVal(c.Equal),
+	)
+}
+
+// Len is a helper method to get the length of the underlying config list
+func (c *AlertmanagerMatcherConfigs) Len() int {
+	if c == nil {
+		return 0
+	}
+	return len(*c)
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *AlertmanagerMatcherConfigs) Copy() *AlertmanagerMatcherConfigs {
+	if c == nil {
+		return nil
+	}
+
+	o := make(AlertmanagerMatcherConfigs, c.Len())
+	for i, m := range *c {
+		o[i] = m.Copy()
+	}
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration. Matchers of the other configuration are appended.
+func (c *AlertmanagerMatcherConfigs) Merge(o *AlertmanagerMatcherConfigs) *AlertmanagerMatcherConfigs {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+	for _, m := range *o {
+		*r = append(*r, m.Copy())
+	}
+	return r
+}
+
+// Finalize ensures the configuration has no nil pointers.
+func (c *AlertmanagerMatcherConfigs) Finalize() {
+	if c == nil {
+		return
+	}
+
+	for _, m := range *c {
+		m.Finalize()
+	}
+}
+
+// Validate validates the values and nested values of the configuration struct.
+func (c *AlertmanagerMatcherConfigs) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	for _, m := range *c {
+		if err := m.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *AlertmanagerMatcherConfigs) GoString() string {
+	if c == nil {
+		return "(*AlertmanagerMatcherConfigs)(nil)"
+	}
+
+	s := make([]string, len(*c))
+	for i, m := range *c {
+		s[i] = m.GoString()
+	}
+	return "{" + strings.Join(s, ", ") + "}"
+}
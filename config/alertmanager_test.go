@@ -0,0 +1,489 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func defaultAlertmanagerSilenceConfig() *AlertmanagerSilenceConfig {
+	return &AlertmanagerSilenceConfig{
+		Enabled:  Bool(false),
+		Duration: TimeDuration(DefaultAlertmanagerSilenceDuration),
+		Comment:  String(""),
+		Matchers: &AlertmanagerMatcherConfigs{},
+	}
+}
+
+func TestAlertmanagerConfig_Copy(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *AlertmanagerConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&AlertmanagerConfig{},
+		},
+		{
+			"fully_configured",
+			&AlertmanagerConfig{
+				Enabled: Bool(true),
+				Address: String("http://localhost:9093"),
+				Token:   String("token"),
+				Timeout: TimeDuration(5 * time.Second),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tc.a.Copy()
+			assert.Equal(t, tc.a, r)
+		})
+	}
+}
+
+func TestAlertmanagerConfig_Merge(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *AlertmanagerConfig
+		b    *AlertmanagerConfig
+		r    *AlertmanagerConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&AlertmanagerConfig{},
+			&AlertmanagerConfig{},
+		},
+		{
+			"nil_b",
+			&AlertmanagerConfig{},
+			nil,
+			&AlertmanagerConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"address_overrides",
+			&AlertmanagerConfig{Address: String("http://a:9093")},
+			&AlertmanagerConfig{Address: String("http://b:9093")},
+			&AlertmanagerConfig{Address: String("http://b:9093")},
+		},
+		{
+			"token_empty_one",
+			&AlertmanagerConfig{Token: String("token")},
+			&AlertmanagerConfig{},
+			&AlertmanagerConfig{Token: String("token")},
+		},
+		{
+			"timeout_overrides",
+			&AlertmanagerConfig{Timeout: TimeDuration(time.Second)},
+			&AlertmanagerConfig{Timeout: TimeDuration(time.Minute)},
+			&AlertmanagerConfig{Timeout: TimeDuration(time.Minute)},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			assert.Equal(t, tc.r, r)
+		})
+	}
+}
+
+func TestAlertmanagerConfig_Finalize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		i    *AlertmanagerConfig
+		r    *AlertmanagerConfig
+	}{
+		{
+			"nil",
+			nil,
+			nil,
+		},
+		{
+			"empty",
+			&AlertmanagerConfig{},
+			&AlertmanagerConfig{
+				Enabled: Bool(false),
+				Address: String(""),
+				Token:   String(""),
+				Timeout: TimeDuration(DefaultAlertmanagerTimeout),
+			},
+		},
+		{
+			"address",
+			&AlertmanagerConfig{Address: String("http://localhost:9093")},
+			&AlertmanagerConfig{
+				Enabled: Bool(true),
+				Address: String("http://localhost:9093"),
+				Token:   String(""),
+				Timeout: TimeDuration(DefaultAlertmanagerTimeout),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.i.Finalize()
+			assert.Equal(t, tc.r, tc.i)
+		})
+	}
+}
+
+func TestAlertmanagerConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		expectErr bool
+		c         *AlertmanagerConfig
+	}{
+		{
+			"nil",
+			false,
+			nil,
+		},
+		{
+			"disabled",
+			false,
+			&AlertmanagerConfig{Enabled: Bool(false), Address: String("invalid")},
+		},
+		{
+			"valid",
+			false,
+			&AlertmanagerConfig{
+				Enabled: Bool(true),
+				Address: String("https://alertmanager.example.com"),
+				Timeout: TimeDuration(time.Second),
+			},
+		},
+		{
+			"missing_address",
+			true,
+			&AlertmanagerConfig{Enabled: Bool(true), Address: String("")},
+		},
+		{
+			"missing_scheme",
+			true,
+			&AlertmanagerConfig{Enabled: Bool(true), Address: String("localhost:9093")},
+		},
+		{
+			"negative_timeout",
+			true,
+			&AlertmanagerConfig{
+				Enabled: Bool(true),
+				Address: String("http://localhost:9093"),
+				Timeout: TimeDuration(-time.Second),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.c.Validate()
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAlertmanagerSilenceConfig_Copy(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *AlertmanagerSilenceConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&AlertmanagerSilenceConfig{},
+		},
+		{
+			"finalized",
+			defaultAlertmanagerSilenceConfig(),
+		},
+		{
+			"fully_configured",
+			&AlertmanagerSilenceConfig{
+				Enabled:  Bool(true),
+				Duration: TimeDuration(10 * time.Minute),
+				Comment:  String("reconverging"),
+				Matchers: &AlertmanagerMatcherConfigs{
+					{
+						Name:  String("service"),
+						Value: String("api"),
+						Regex: Bool(false),
+						Equal: Bool(true),
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tc.a.Copy()
+			assert.Equal(t, tc.a, r)
+		})
+	}
+}
+
+func TestAlertmanagerSilenceConfig_Merge(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *AlertmanagerSilenceConfig
+		b    *AlertmanagerSilenceConfig
+		r    *AlertmanagerSilenceConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&AlertmanagerSilenceConfig{},
+			&AlertmanagerSilenceConfig{},
+		},
+		{
+			"nil_b",
+			&AlertmanagerSilenceConfig{},
+			nil,
+			&AlertmanagerSilenceConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"duration_overrides",
+			&AlertmanagerSilenceConfig{Duration: TimeDuration(time.Minute)},
+			&AlertmanagerSilenceConfig{Duration: TimeDuration(time.Hour)},
+			&AlertmanagerSilenceConfig{Duration: TimeDuration(time.Hour)},
+		},
+		{
+			"comment_empty_one",
+			&AlertmanagerSilenceConfig{Comment: String("a")},
+			&AlertmanagerSilenceConfig{},
+			&AlertmanagerSilenceConfig{Comment: String("a")},
+		},
+		{
+			"matchers_appended",
+			&AlertmanagerSilenceConfig{Matchers: &AlertmanagerMatcherConfigs{
+				{Name: String("a"), Value: String("1")},
+			}},
+			&AlertmanagerSilenceConfig{Matchers: &AlertmanagerMatcherConfigs{
+				{Name: String("b"), Value: String("2")},
+			}},
+			&AlertmanagerSilenceConfig{Matchers: &AlertmanagerMatcherConfigs{
+				{Name: String("a"), Value: String("1")},
+				{Name: String("b"), Value: String("2")},
+			}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			assert.Equal(t, tc.r, r)
+		})
+	}
+}
+
+func TestAlertmanagerSilenceConfig_Finalize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		i    *AlertmanagerSilenceConfig
+		r    *AlertmanagerSilenceConfig
+	}{
+		{
+			"nil",
+			nil,
+			nil,
+		},
+		{
+			"empty",
+			&AlertmanagerSilenceConfig{},
+			defaultAlertmanagerSilenceConfig(),
+		},
+		{
+			"matcher",
+			&AlertmanagerSilenceConfig{
+				Matchers: &AlertmanagerMatcherConfigs{
+					{Name: String("service"), Value: String("api")},
+				},
+			},
+			&AlertmanagerSilenceConfig{
+				Enabled:  Bool(true),
+				Duration: TimeDuration(DefaultAlertmanagerSilenceDuration),
+				Comment:  String(""),
+				Matchers: &AlertmanagerMatcherConfigs{
+					{
+						Name:  String("service"),
+						Value: String("api"),
+						Regex: Bool(false),
+						Equal: Bool(true),
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.i.Finalize()
+			assert.Equal(t, tc.r, tc.i)
+		})
+	}
+}
+
+func TestAlertmanagerSilenceConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	matchers := &AlertmanagerMatcherConfigs{
+		{Name: String("service"), Value: String("api")},
+	}
+
+	cases := []struct {
+		name      string
+		expectErr bool
+		c         *AlertmanagerSilenceConfig
+	}{
+		{
+			"nil",
+			false,
+			nil,
+		},
+		{
+			"disabled",
+			false,
+			&AlertmanagerSilenceConfig{Enabled: Bool(false)},
+		},
+		{
+			"valid",
+			false,
+			&AlertmanagerSilenceConfig{
+				Enabled:  Bool(true),
+				Duration: TimeDuration(time.Minute),
+				Matchers: matchers,
+			},
+		},
+		{
+			"missing_matchers",
+			true,
+			&AlertmanagerSilenceConfig{
+				Enabled:  Bool(true),
+				Duration: TimeDuration(time.Minute),
+			},
+		},
+		{
+			"zero_duration",
+			true,
+			&AlertmanagerSilenceConfig{
+				Enabled:  Bool(true),
+				Duration: TimeDuration(0),
+				Matchers: matchers,
+			},
+		},
+		{
+			"matcher_missing_name",
+			true,
+			&AlertmanagerSilenceConfig{
+				Enabled:  Bool(true),
+				Duration: TimeDuration(time.Minute),
+				Matchers: &AlertmanagerMatcherConfigs{{Value: String("api")}},
+			},
+		},
+		{
+			"matcher_missing_value",
+			true,
+			&AlertmanagerSilenceConfig{
+				Enabled:  Bool(true),
+				Duration: TimeDuration(time.Minute),
+				Matchers: &AlertmanagerMatcherConfigs{{Name: String("service")}},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.c.Validate()
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateAlertmanagerSilences(t *testing.T) {
+	t.Parallel()
+
+	newConfig := func(am *AlertmanagerConfig) *Config {
+		c := &Config{
+			Alertmanager: am,
+			Tasks: &TaskConfigs{
+				{
+					Name: String("lb"),
+					Condition: &ServicesConditionConfig{
+						ServicesMonitorConfig: ServicesMonitorConfig{
+							Names: []string{"api"},
+						},
+					},
+					Module: String("path"),
+					AlertmanagerSilence: &AlertmanagerSilenceConfig{
+						Matchers: &AlertmanagerMatcherConfigs{
+							{Name: String("service"), Value: String("api")},
+						},
+					},
+				},
+			},
+		}
+		require.NoError(t, c.Finalize())
+		return c
+	}
+
+	t.Run("alertmanager configured", func(t *testing.T) {
+		c := newConfig(&AlertmanagerConfig{Address: String("http://localhost:9093")})
+		assert.NoError(t, c.Validate())
+	})
+
+	t.Run("alertmanager not configured", func(t *testing.T) {
+		c := newConfig(nil)
+		err := c.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "alertmanager block is not configured")
+	})
+}
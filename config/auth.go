@@ -9,9 +9,9 @@ const redactMessage = "(redacted)"
 
 // AuthConfig is the HTTP basic authentication data.
 type AuthConfig struct {
-	Enabled  *bool   `mapstructure:"enabled"`
-	Username *string `mapstructure:"username"`
-	Password *string `mapstructure:"password"`
+	Enabled  *bool   `mapstructure:"enabled" json:"enabled"`
+	Username *string `mapstructure:"username" json:"username"`
+	Password *string `mapstructure:"password" json:"password"`
 }
 
 // DefaultAuthConfig is the default configuration.
@@ -0,0 +1,171 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultCircuitBreakerThreshold is the default number of consecutive
+	// failures before a task is paused
+	DefaultCircuitBreakerThreshold = 5
+
+	// DefaultCircuitBreakerBackoff is the default time to wait before retrying
+	// a paused task
+	DefaultCircuitBreakerBackoff = 10 * time.Minute
+
+	// DefaultCircuitBreakerMaxBackoff is the default limit for the backoff as
+	// it doubles with each failed retry
+	DefaultCircuitBreakerMaxBackoff = 6 * time.Hour
+)
+
+// CircuitBreakerConfig configures the circuit breaker for a task. When a task
+// fails the threshold number of consecutive times, the circuit breaker opens
+// and the task is paused in a degraded state. A paused task is retried after
+// the backoff, which doubles after each failed retry up to the max backoff.
+// A paused task can also be resumed manually by enabling the task.
+type CircuitBreakerConfig struct {
+	// Enabled determines if the circuit breaker is enabled. Disabled by
+	// default, and enabled if any other option is configured.
+	Enabled *bool `mapstructure:"enabled" json:"enabled"`
+
+	// Threshold is the number of consecutive failures to pause the task
+	Threshold *int `mapstructure:"threshold" json:"threshold"`
+
+	// Backoff is the time to wait before retrying a paused task. When set to
+	// 0, the task is not retried and remains paused until manually resumed.
+	Backoff *time.Duration `mapstructure:"backoff" json:"backoff"`
+
+	// MaxBackoff is the maximum time to wait before retrying a paused task
+	MaxBackoff *time.Duration `mapstructure:"max_backoff" json:"max_backoff"`
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *CircuitBreakerConfig) Copy() *CircuitBreakerConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o CircuitBreakerConfig
+	o.Enabled = BoolCopy(c.Enabled)
+	o.Threshold = IntCopy(c.Threshold)
+	o.Backoff = TimeDurationCopy(c.Backoff)
+	o.MaxBackoff = TimeDurationCopy(c.MaxBackoff)
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+// Maps and slices are merged, most other values are overwritten. Complex
+// structs define their own merge functionality.
+func (c *CircuitBreakerConfig) Merge(o *CircuitBreakerConfig) *CircuitBreakerConfig {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+
+	if o.Enabled != nil {
+		r.Enabled = BoolCopy(o.Enabled)
+	}
+
+	if o.Threshold != nil {
+		r.Threshold = IntCopy(o.Threshold)
+	}
+
+	if o.Backoff != nil {
+		r.Backoff = TimeDurationCopy(o.Backoff)
+	}
+
+	if o.MaxBackoff != nil {
+		r.MaxBackoff = TimeDurationCopy(o.MaxBackoff)
+	}
+
+	return r
+}
+
+// Finalize ensures that the receiver contains no nil pointers. For nil pointers,
+// Finalize sets default values where necessary
+func (c *CircuitBreakerConfig) Finalize() {
+	if c == nil {
+		return
+	}
+
+	if c.Enabled == nil {
+		// some options configured, assume user intention is enabled
+		c.Enabled = Bool(c.Threshold != nil || c.Backoff != nil ||
+			c.MaxBackoff != nil)
+	}
+
+	if c.Threshold == nil {
+		c.Threshold = Int(DefaultCircuitBreakerThreshold)
+	}
+
+	if c.Backoff == nil {
+		c.Backoff = TimeDuration(DefaultCircuitBreakerBackoff)
+	}
+
+	if c.MaxBackoff == nil {
+		c.MaxBackoff = TimeDuration(DefaultCircuitBreakerMaxBackoff)
+		if *c.Backoff > *c.MaxBackoff {
+			*c.MaxBackoff = *c.Backoff
+		}
+	}
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *CircuitBreakerConfig) Validate() error {
+	if c == nil {
+		// config is not required, return early
+		return nil
+	}
+
+	if !BoolVal(c.Enabled) {
+		return nil
+	}
+
+	if IntVal(c.Threshold) < 1 {
+		return fmt.Errorf("circuit_breaker: threshold must be at least 1, "+
+			"got %d", IntVal(c.Threshold))
+	}
+
+	if TimeDurationVal(c.Backoff) < 0 || TimeDurationVal(c.MaxBackoff) < 0 {
+		return fmt.Errorf("circuit_breaker: backoff cannot be negative")
+	}
+
+	if TimeDurationVal(c.MaxBackoff) < TimeDurationVal(c.Backoff) {
+		return fmt.Errorf("circuit_breaker: backoff must be less than max_backoff")
+	}
+
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *CircuitBreakerConfig) GoString() string {
+	if c == nil {
+		return "(*CircuitBreakerConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&CircuitBreakerConfig{"+
+		"Enabled:%v, "+
+		"Threshold:%d, "+
+		"Backoff:%s, "+
+		"MaxBackoff:%s"+
+		"}",
+		BoolVal(c.Enabled),
+		IntVal(c.Threshold),
+		TimeDurationVal(c.Backoff),
+		TimeDurationVal(c.MaxBackoff),
+	)
+}
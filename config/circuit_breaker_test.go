@@ -0,0 +1,285 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// defaultCircuitBreakerConfig returns the finalized circuit breaker
+// configuration for a task that does not configure a circuit breaker
+func defaultCircuitBreakerConfig() *CircuitBreakerConfig {
+	return &CircuitBreakerConfig{
+		Enabled:    Bool(false),
+		Threshold:  Int(DefaultCircuitBreakerThreshold),
+		Backoff:    TimeDuration(DefaultCircuitBreakerBackoff),
+		MaxBackoff: TimeDuration(DefaultCircuitBreakerMaxBackoff),
+	}
+}
+
+func TestCircuitBreakerConfig_Copy(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *CircuitBreakerConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&CircuitBreakerConfig{},
+		},
+		{
+			"fully configured",
+			&CircuitBreakerConfig{
+				Enabled:    Bool(true),
+				Threshold:  Int(3),
+				Backoff:    TimeDuration(time.Minute),
+				MaxBackoff: TimeDuration(time.Hour),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Copy()
+			assert.Equal(t, tc.a, r)
+		})
+	}
+}
+
+func TestCircuitBreakerConfig_Merge(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *CircuitBreakerConfig
+		b    *CircuitBreakerConfig
+		r    *CircuitBreakerConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&CircuitBreakerConfig{},
+			&CircuitBreakerConfig{},
+		},
+		{
+			"nil_b",
+			&CircuitBreakerConfig{},
+			nil,
+			&CircuitBreakerConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"overrides",
+			&CircuitBreakerConfig{
+				Enabled:    Bool(false),
+				Threshold:  Int(3),
+				Backoff:    TimeDuration(time.Minute),
+				MaxBackoff: TimeDuration(time.Hour),
+			},
+			&CircuitBreakerConfig{
+				Enabled:    Bool(true),
+				Threshold:  Int(5),
+				Backoff:    TimeDuration(2 * time.Minute),
+				MaxBackoff: TimeDuration(2 * time.Hour),
+			},
+			&CircuitBreakerConfig{
+				Enabled:    Bool(true),
+				Threshold:  Int(5),
+				Backoff:    TimeDuration(2 * time.Minute),
+				MaxBackoff: TimeDuration(2 * time.Hour),
+			},
+		},
+		{
+			"empty_one",
+			&CircuitBreakerConfig{
+				Threshold: Int(3),
+			},
+			&CircuitBreakerConfig{
+				Backoff: TimeDuration(time.Minute),
+			},
+			&CircuitBreakerConfig{
+				Threshold: Int(3),
+				Backoff:   TimeDuration(time.Minute),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			assert.Equal(t, tc.r, r)
+		})
+	}
+}
+
+func TestCircuitBreakerConfig_Finalize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		i    *CircuitBreakerConfig
+		r    *CircuitBreakerConfig
+	}{
+		{
+			"empty",
+			&CircuitBreakerConfig{},
+			defaultCircuitBreakerConfig(),
+		},
+		{
+			"enabled",
+			&CircuitBreakerConfig{
+				Enabled: Bool(true),
+			},
+			&CircuitBreakerConfig{
+				Enabled:    Bool(true),
+				Threshold:  Int(5),
+				Backoff:    TimeDuration(10 * time.Minute),
+				MaxBackoff: TimeDuration(6 * time.Hour),
+			},
+		},
+		{
+			"only threshold",
+			&CircuitBreakerConfig{
+				Threshold: Int(3),
+			},
+			&CircuitBreakerConfig{
+				Enabled:    Bool(true),
+				Threshold:  Int(3),
+				Backoff:    TimeDuration(10 * time.Minute),
+				MaxBackoff: TimeDuration(6 * time.Hour),
+			},
+		},
+		{
+			"backoff greater than default max",
+			&CircuitBreakerConfig{
+				Backoff: TimeDuration(12 * time.Hour),
+			},
+			&CircuitBreakerConfig{
+				Enabled:    Bool(true),
+				Threshold:  Int(5),
+				Backoff:    TimeDuration(12 * time.Hour),
+				MaxBackoff: TimeDuration(12 * time.Hour),
+			},
+		},
+		{
+			"disabled",
+			&CircuitBreakerConfig{
+				Enabled:   Bool(false),
+				Threshold: Int(3),
+			},
+			&CircuitBreakerConfig{
+				Enabled:    Bool(false),
+				Threshold:  Int(3),
+				Backoff:    TimeDuration(10 * time.Minute),
+				MaxBackoff: TimeDuration(6 * time.Hour),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			tc.i.Finalize()
+			assert.Equal(t, tc.r, tc.i)
+		})
+	}
+}
+
+func TestCircuitBreakerConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		i       *CircuitBreakerConfig
+		isValid bool
+	}{
+		{
+			"nil",
+			nil,
+			true,
+		},
+		{
+			"disabled",
+			&CircuitBreakerConfig{
+				Enabled:   Bool(false),
+				Threshold: Int(0),
+			},
+			true,
+		},
+		{
+			"valid",
+			&CircuitBreakerConfig{
+				Enabled:    Bool(true),
+				Threshold:  Int(3),
+				Backoff:    TimeDuration(time.Minute),
+				MaxBackoff: TimeDuration(time.Hour),
+			},
+			true,
+		},
+		{
+			"valid: no backoff",
+			&CircuitBreakerConfig{
+				Enabled:    Bool(true),
+				Threshold:  Int(3),
+				Backoff:    TimeDuration(0),
+				MaxBackoff: TimeDuration(0),
+			},
+			true,
+		},
+		{
+			"threshold less than 1",
+			&CircuitBreakerConfig{
+				Enabled:    Bool(true),
+				Threshold:  Int(0),
+				Backoff:    TimeDuration(time.Minute),
+				MaxBackoff: TimeDuration(time.Hour),
+			},
+			false,
+		},
+		{
+			"negative backoff",
+			&CircuitBreakerConfig{
+				Enabled:    Bool(true),
+				Threshold:  Int(3),
+				Backoff:    TimeDuration(-time.Minute),
+				MaxBackoff: TimeDuration(time.Hour),
+			},
+			false,
+		},
+		{
+			"backoff greater than max",
+			&CircuitBreakerConfig{
+				Enabled:    Bool(true),
+				Threshold:  Int(3),
+				Backoff:    TimeDuration(2 * time.Hour),
+				MaxBackoff: TimeDuration(time.Hour),
+			},
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			err := tc.i.Validate()
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// ComputedInputConfig configures a variable of the task's module whose value
+// is computed from the monitored data with an HCL expression. The expression
+// is evaluated each time the module input variables are rendered, e.g.
+//
+//	computed_input "lb_members" {
+//	  expr = "[for s in services : s.address if s.status == \"passing\"]"
+//	}
+//
+// The variables rendered for the task's condition and module inputs, e.g.
+// services and consul_kv, can be referenced by name.
+type ComputedInputConfig struct {
+	// Expr is the HCL expression to evaluate
+	Expr *string `mapstructure:"expr" json:"expr"`
+}
+
+// ComputedInputConfigs is a collection of ComputedInputConfig by the name of
+// the variable
+type ComputedInputConfigs map[string]*ComputedInputConfig
+
+// Copy returns a deep copy of this configuration.
+func (c *ComputedInputConfig) Copy() *ComputedInputConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o ComputedInputConfig
+	o.Expr = StringCopy(c.Expr)
+	return &o
+}
+
+// Finalize ensures there are no nil pointers.
+func (c *ComputedInputConfig) Finalize() {
+	if c == nil {
+		return
+	}
+
+	if c.Expr == nil {
+		c.Expr = String("")
+	}
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *ComputedInputConfig) Validate() error {
+	if c == nil {
+		return fmt.Errorf("missing configuration")
+	}
+
+	expr := StringVal(c.Expr)
+	if strings.TrimSpace(expr) == "" {
+		return fmt.Errorf("expr is required")
+	}
+
+	if _, diags := hclsyntax.ParseExpression([]byte(expr), "expr",
+		hcl.InitialPos); diags.HasErrors() {
+		return fmt.Errorf("invalid expr: %s", diags.Error())
+	}
+
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *ComputedInputConfig) GoString() string {
+	if c == nil {
+		return "(*ComputedInputConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&ComputedInputConfig{"+
+		"Expr:%s"+
+		"}",
+		StringVal(c.Expr),
+	)
+}
+
+// Copy returns a deep copy of this configuration.
+func (c ComputedInputConfigs) Copy() ComputedInputConfigs {
+	if c == nil {
+		return nil
+	}
+
+	o := make(ComputedInputConfigs, len(c))
+	for name, ci := range c {
+		o[name] = ci.Copy()
+	}
+	return o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration. Computed inputs of the other configuration take precedence
+// over computed inputs with the same name.
+func (c ComputedInputConfigs) Merge(o ComputedInputConfigs) ComputedInputConfigs {
+	if c == nil {
+		return o.Copy()
+	}
+
+	r := c.Copy()
+	for name, ci := range o {
+		r[name] = ci.Copy()
+	}
+	return r
+}
+
+// Finalize ensures the configuration has no nil pointers.
+func (c ComputedInputConfigs) Finalize() {
+	for _, ci := range c {
+		ci.Finalize()
+	}
+}
+
+// Validate validates the values and nested values of the configuration. The
+// name of each computed input must be a valid Terraform variable name.
+func (c ComputedInputConfigs) Validate() error {
+	for _, name := range c.names() {
+		if !hclsyntax.ValidIdentifier(name) {
+			return fmt.Errorf("computed_input: name %q is not a valid "+
+				"variable name", name)
+		}
+		if err := c[name].Validate(); err != nil {
+			return fmt.Errorf("computed_input %q: %s", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Exprs returns the expressions of the computed inputs by name
+func (c ComputedInputConfigs) Exprs() map[string]string {
+	exprs := make(map[string]string, len(c))
+	for name, ci := range c {
+		exprs[name] = StringVal(ci.Expr)
+	}
+	return exprs
+}
+
+// GoString defines the printable version of this struct.
+func (c ComputedInputConfigs) GoString() string {
+	if c == nil {
+		return "(ComputedInputConfigs)(nil)"
+	}
+
+	names := c.names()
+	s := make([]string, len(names))
+	for i, name := range names {
+		s[i] = fmt.Sprintf("%s:%s", name, c[name].GoString())
+	}
+
+	return "{" + strings.Join(s, ", ") + "}"
+}
+
+// names returns the sorted names of the computed inputs
+func (c ComputedInputConfigs) names() []string {
+	names := make([]string, 0, len(c))
+	for name := range c {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,181 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputedInputConfigs_Copy(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    ComputedInputConfigs
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			ComputedInputConfigs{},
+		},
+		{
+			"fully_configured",
+			ComputedInputConfigs{
+				"lb_members": {Expr: String(`[for s in services : s.address]`)},
+				"lb_count":   {Expr: String(`length(services)`)},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tc.a.Copy()
+			assert.Equal(t, tc.a, r)
+			for name, ci := range tc.a {
+				assert.NotSame(t, ci, r[name])
+			}
+		})
+	}
+}
+
+func TestComputedInputConfigs_Merge(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    ComputedInputConfigs
+		b    ComputedInputConfigs
+		r    ComputedInputConfigs
+	}{
+		{
+			"nil_a",
+			nil,
+			ComputedInputConfigs{},
+			ComputedInputConfigs{},
+		},
+		{
+			"nil_b",
+			ComputedInputConfigs{},
+			nil,
+			ComputedInputConfigs{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"combines",
+			ComputedInputConfigs{"a": {Expr: String("1")}},
+			ComputedInputConfigs{"b": {Expr: String("2")}},
+			ComputedInputConfigs{
+				"a": {Expr: String("1")},
+				"b": {Expr: String("2")},
+			},
+		},
+		{
+			"expr_overrides",
+			ComputedInputConfigs{"a": {Expr: String("1")}},
+			ComputedInputConfigs{"a": {Expr: String("2")}},
+			ComputedInputConfigs{"a": {Expr: String("2")}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			assert.Equal(t, tc.r, r)
+		})
+	}
+}
+
+func TestComputedInputConfigs_Finalize(t *testing.T) {
+	t.Parallel()
+
+	c := ComputedInputConfigs{"a": {}}
+	c.Finalize()
+	assert.Equal(t, ComputedInputConfigs{"a": {Expr: String("")}}, c)
+}
+
+func TestComputedInputConfigs_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		expectErr bool
+		c         ComputedInputConfigs
+	}{
+		{
+			"nil",
+			false,
+			nil,
+		},
+		{
+			"valid",
+			false,
+			ComputedInputConfigs{
+				"lb_members": {Expr: String(`[for s in services : s.address if s.status == "passing"]`)},
+				"lb_count":   {Expr: String(`length(services)`)},
+			},
+		},
+		{
+			"invalid_name",
+			true,
+			ComputedInputConfigs{
+				"lb-members!": {Expr: String(`[]`)},
+			},
+		},
+		{
+			"missing_expr",
+			true,
+			ComputedInputConfigs{
+				"lb_members": {Expr: String(" ")},
+			},
+		},
+		{
+			"nil_config",
+			true,
+			ComputedInputConfigs{
+				"lb_members": nil,
+			},
+		},
+		{
+			"invalid_expr",
+			true,
+			ComputedInputConfigs{
+				"lb_members": {Expr: String(`[for s in services`)},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.c.Validate()
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestComputedInputConfigs_Exprs(t *testing.T) {
+	t.Parallel()
+
+	c := ComputedInputConfigs{
+		"lb_members": {Expr: String(`[for s in services : s.address]`)},
+		"lb_count":   {Expr: String(`length(services)`)},
+	}
+	assert.Equal(t, map[string]string{
+		"lb_members": `[for s in services : s.address]`,
+		"lb_count":   `length(services)`,
+	}, c.Exprs())
+}
@@ -74,6 +74,18 @@ func conditionToTypeFunc() mapstructure.DecodeHookFunc {
 			var config ScheduleConditionConfig
 			return decodeConditionToType(c, &config)
 		}
+		if c, ok := conditions[dnsType]; ok {
+			var config DNSConditionConfig
+			return decodeConditionToType(c, &config)
+		}
+		if c, ok := conditions[fileType]; ok {
+			var config FileConditionConfig
+			return decodeConditionToType(c, &config)
+		}
+		if c, ok := conditions[pluginType]; ok {
+			var config PluginConditionConfig
+			return decodeConditionToType(c, &config)
+		}
 
 		return nil, fmt.Errorf("unsupported condition type: %v", data)
 	}
@@ -88,6 +100,7 @@ func decodeConditionToType(data interface{}, monitor MonitorConfig) (MonitorConf
 	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 		DecodeHook: mapstructure.ComposeDecodeHookFunc(
 			decode.HookWeakDecodeFromSlice,
+			mapstructure.StringToTimeDurationHookFunc(),
 		),
 		WeaklyTypedInput: true,
 		ErrorUnused:      false,
@@ -158,11 +158,15 @@ func TestCatalogServicesConditionConfig_Finalize(t *testing.T) {
 			&CatalogServicesConditionConfig{},
 			&CatalogServicesConditionConfig{
 				CatalogServicesMonitorConfig{
-					Regexp:           nil,
-					UseAsModuleInput: Bool(true),
-					Datacenter:       String(""),
-					Namespace:        String(""),
-					NodeMeta:         map[string]string{},
+					Regexp:              nil,
+					UseAsModuleInput:    Bool(true),
+					Datacenter:          String(""),
+					Namespace:           String(""),
+					NodeMeta:            map[string]string{},
+					Kind:                String(""),
+					TriggerOnTagChanges: Bool(false),
+					MinInstances:        Int(0),
+					MaxInstances:        Int(0),
 				},
 			},
 		},
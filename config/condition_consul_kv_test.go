@@ -31,6 +31,15 @@ func TestConsulKVConditionConfig_Copy(t *testing.T) {
 			"finalized",
 			finalizedConf,
 		},
+		{
+			"paths",
+			&ConsulKVConditionConfig{
+				ConsulKVMonitorConfig: ConsulKVMonitorConfig{
+					Paths:   []string{"a/b", "c/d"},
+					Recurse: Bool(true),
+				},
+			},
+		},
 		{
 			"fully_configured",
 			&ConsulKVConditionConfig{
@@ -116,6 +125,18 @@ func TestConsulKVConditionConfig_Merge(t *testing.T) {
 			&ConsulKVConditionConfig{ConsulKVMonitorConfig: ConsulKVMonitorConfig{Path: String("same")}},
 			&ConsulKVConditionConfig{ConsulKVMonitorConfig: ConsulKVMonitorConfig{Path: String("same")}},
 		},
+		{
+			"paths_merges",
+			&ConsulKVConditionConfig{ConsulKVMonitorConfig: ConsulKVMonitorConfig{Paths: []string{"a/b", "c/d"}}},
+			&ConsulKVConditionConfig{ConsulKVMonitorConfig: ConsulKVMonitorConfig{Paths: []string{"c/d", "e/f"}}},
+			&ConsulKVConditionConfig{ConsulKVMonitorConfig: ConsulKVMonitorConfig{Paths: []string{"a/b", "c/d", "e/f"}}},
+		},
+		{
+			"paths_empty_one",
+			&ConsulKVConditionConfig{ConsulKVMonitorConfig: ConsulKVMonitorConfig{Paths: []string{"a/b"}}},
+			&ConsulKVConditionConfig{},
+			&ConsulKVConditionConfig{ConsulKVMonitorConfig: ConsulKVMonitorConfig{Paths: []string{"a/b"}}},
+		},
 		{
 			"recurse_overrides",
 			&ConsulKVConditionConfig{ConsulKVMonitorConfig: ConsulKVMonitorConfig{Recurse: Bool(true)}},
@@ -265,6 +286,7 @@ func TestConsulKVConditionConfig_Finalize(t *testing.T) {
 			&ConsulKVConditionConfig{
 				ConsulKVMonitorConfig: ConsulKVMonitorConfig{
 					Path:       String(""),
+					Paths:      []string{},
 					Recurse:    Bool(false),
 					Datacenter: String(""),
 					Namespace:  String(""),
@@ -352,6 +374,64 @@ func TestConsulKVConditionConfig_Validate(t *testing.T) {
 			true,
 			&ConsulKVConditionConfig{},
 		},
+		{
+			"paths",
+			false,
+			&ConsulKVConditionConfig{
+				ConsulKVMonitorConfig: ConsulKVMonitorConfig{
+					Paths:   []string{"a/b", "c/d"},
+					Recurse: Bool(true),
+				},
+			},
+		},
+		{
+			"path_and_paths",
+			true,
+			&ConsulKVConditionConfig{
+				ConsulKVMonitorConfig: ConsulKVMonitorConfig{
+					Path:  String("a/b"),
+					Paths: []string{"c/d"},
+				},
+			},
+		},
+		{
+			"paths_empty_path",
+			true,
+			&ConsulKVConditionConfig{
+				ConsulKVMonitorConfig: ConsulKVMonitorConfig{
+					Paths: []string{"a/b", ""},
+				},
+			},
+		},
+		{
+			"paths_duplicate",
+			true,
+			&ConsulKVConditionConfig{
+				ConsulKVMonitorConfig: ConsulKVMonitorConfig{
+					Paths: []string{"a/b", "a/b"},
+				},
+			},
+		},
+		{
+			"paths_overlap_recurse",
+			true,
+			&ConsulKVConditionConfig{
+				ConsulKVMonitorConfig: ConsulKVMonitorConfig{
+					Paths:   []string{"a", "a/b"},
+					Recurse: Bool(true),
+				},
+			},
+		},
+		{
+			"paths_overlap_no_recurse",
+			false,
+			&ConsulKVConditionConfig{
+				ConsulKVMonitorConfig: ConsulKVMonitorConfig{
+					Paths:   []string{"a", "a/b"},
+					Recurse: Bool(false),
+				},
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -365,3 +445,40 @@ func TestConsulKVConditionConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestConsulKVMonitorConfig_AllPaths(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		c        *ConsulKVMonitorConfig
+		expected []string
+	}{
+		{
+			"nil",
+			nil,
+			nil,
+		},
+		{
+			"empty",
+			&ConsulKVMonitorConfig{Path: String(""), Paths: []string{}},
+			nil,
+		},
+		{
+			"path",
+			&ConsulKVMonitorConfig{Path: String("a/b")},
+			[]string{"a/b"},
+		},
+		{
+			"paths",
+			&ConsulKVMonitorConfig{Path: String(""), Paths: []string{"a/b", "c/d"}},
+			[]string{"a/b", "c/d"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.c.AllPaths())
+		})
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	dnsType = "dns"
+
+	// DNSRecordTypeA resolves the IPv4 addresses of a DNS name
+	DNSRecordTypeA = "A"
+
+	// DNSRecordTypeSRV resolves the SRV records of a DNS name
+	DNSRecordTypeSRV = "SRV"
+
+	// DefaultDNSInterval is the default interval between DNS lookups
+	DefaultDNSInterval = 30 * time.Second
+
+	// minDNSInterval is the minimum allowed interval between DNS lookups
+	minDNSInterval = 1 * time.Second
+)
+
+var _ ConditionConfig = (*DNSConditionConfig)(nil)
+
+// DNSMonitorConfig exists purely to allow json / hcl conversions
+// to work seamlessly by encoding and decoding under the "dns" name.
+// It should not be treated as a standalone module input.
+type DNSMonitorConfig struct {
+	// Name is the DNS name to resolve. For SRV records, this is the full
+	// service name, e.g. "_http._tcp.example.com"
+	Name *string `mapstructure:"name" json:"name"`
+
+	// RecordType is the type of DNS record to resolve. Supported values are
+	// "A" and "SRV".
+	RecordType *string `mapstructure:"record_type" json:"record_type"`
+
+	// Interval is the period of time to wait between DNS lookups.
+	Interval *time.Duration `mapstructure:"interval" json:"interval"`
+}
+
+// DNSConditionConfig configures a condition configuration block of type
+// 'dns'. A dns condition periodically resolves a DNS name and is triggered
+// when the resolved record set changes.
+type DNSConditionConfig struct {
+	DNSMonitorConfig `mapstructure:",squash" json:"dns"`
+
+	UseAsModuleInput *bool `mapstructure:"use_as_module_input" json:"use_as_module_input"`
+}
+
+func (c *DNSConditionConfig) VariableType() string {
+	return "dns_records"
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *DNSConditionConfig) Copy() MonitorConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o DNSConditionConfig
+	o.Name = StringCopy(c.Name)
+	o.RecordType = StringCopy(c.RecordType)
+	o.Interval = TimeDurationCopy(c.Interval)
+	o.UseAsModuleInput = BoolCopy(c.UseAsModuleInput)
+
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+func (c *DNSConditionConfig) Merge(o MonitorConfig) MonitorConfig {
+	if c == nil {
+		if isConditionNil(o) { // o is interface, use isConditionNil()
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if isConditionNil(o) {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+	o2, ok := o.(*DNSConditionConfig)
+	if !ok {
+		return r
+	}
+
+	r2 := r.(*DNSConditionConfig)
+
+	if o2.Name != nil {
+		r2.Name = StringCopy(o2.Name)
+	}
+
+	if o2.RecordType != nil {
+		r2.RecordType = StringCopy(o2.RecordType)
+	}
+
+	if o2.Interval != nil {
+		r2.Interval = TimeDurationCopy(o2.Interval)
+	}
+
+	if o2.UseAsModuleInput != nil {
+		r2.UseAsModuleInput = BoolCopy(o2.UseAsModuleInput)
+	}
+
+	return r2
+}
+
+// Finalize ensures there no nil pointers.
+func (c *DNSConditionConfig) Finalize() {
+	if c == nil { // config not required, return early
+		return
+	}
+
+	if c.Name == nil {
+		c.Name = String("")
+	}
+
+	if c.RecordType == nil {
+		c.RecordType = String(DNSRecordTypeA)
+	}
+
+	if c.Interval == nil {
+		c.Interval = TimeDuration(DefaultDNSInterval)
+	}
+
+	if c.UseAsModuleInput == nil {
+		c.UseAsModuleInput = Bool(true)
+	}
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *DNSConditionConfig) Validate() error {
+	if c == nil { // config not required, return early
+		return nil
+	}
+
+	if c.Name == nil || strings.TrimSpace(*c.Name) == "" {
+		return fmt.Errorf("name is required for dns condition")
+	}
+
+	switch StringVal(c.RecordType) {
+	case DNSRecordTypeA, DNSRecordTypeSRV:
+	default:
+		return fmt.Errorf("unsupported record_type %q for dns condition. "+
+			"Supported types are %q and %q", StringVal(c.RecordType),
+			DNSRecordTypeA, DNSRecordTypeSRV)
+	}
+
+	if c.Interval != nil && *c.Interval < minDNSInterval {
+		return fmt.Errorf("interval for dns condition must be at least %s, "+
+			"got %s", minDNSInterval, *c.Interval)
+	}
+
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *DNSConditionConfig) GoString() string {
+	if c == nil {
+		return "(*DNSConditionConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&DNSConditionConfig{"+
+		"Name:%s, "+
+		"RecordType:%s, "+
+		"Interval:%s, "+
+		"UseAsModuleInput:%v"+
+		"}",
+		StringVal(c.Name),
+		StringVal(c.RecordType),
+		TimeDurationVal(c.Interval),
+		BoolVal(c.UseAsModuleInput),
+	)
+}
@@ -0,0 +1,256 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDNSConditionConfig_Copy(t *testing.T) {
+	t.Parallel()
+
+	finalizedConf := &DNSConditionConfig{}
+	finalizedConf.Finalize()
+
+	cases := []struct {
+		name string
+		a    *DNSConditionConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&DNSConditionConfig{},
+		},
+		{
+			"finalized",
+			finalizedConf,
+		},
+		{
+			"fully_configured",
+			&DNSConditionConfig{
+				DNSMonitorConfig: DNSMonitorConfig{
+					Name:       String("_http._tcp.example.com"),
+					RecordType: String(DNSRecordTypeSRV),
+					Interval:   TimeDuration(10 * time.Second),
+				},
+				UseAsModuleInput: Bool(false),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tc.a.Copy()
+			if tc.a == nil {
+				// returned nil interface has nil type, which is unequal to tc.a
+				assert.Nil(t, r)
+			} else {
+				assert.Equal(t, tc.a, r)
+			}
+		})
+	}
+}
+
+func TestDNSConditionConfig_Merge(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *DNSConditionConfig
+		b    *DNSConditionConfig
+		r    *DNSConditionConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&DNSConditionConfig{},
+			&DNSConditionConfig{},
+		},
+		{
+			"nil_b",
+			&DNSConditionConfig{},
+			nil,
+			&DNSConditionConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"empty",
+			&DNSConditionConfig{},
+			&DNSConditionConfig{},
+			&DNSConditionConfig{},
+		},
+		{
+			"name_overrides",
+			&DNSConditionConfig{DNSMonitorConfig: DNSMonitorConfig{Name: String("a.example.com")}},
+			&DNSConditionConfig{DNSMonitorConfig: DNSMonitorConfig{Name: String("b.example.com")}},
+			&DNSConditionConfig{DNSMonitorConfig: DNSMonitorConfig{Name: String("b.example.com")}},
+		},
+		{
+			"name_empty_one",
+			&DNSConditionConfig{DNSMonitorConfig: DNSMonitorConfig{Name: String("a.example.com")}},
+			&DNSConditionConfig{},
+			&DNSConditionConfig{DNSMonitorConfig: DNSMonitorConfig{Name: String("a.example.com")}},
+		},
+		{
+			"record_type_overrides",
+			&DNSConditionConfig{DNSMonitorConfig: DNSMonitorConfig{RecordType: String(DNSRecordTypeA)}},
+			&DNSConditionConfig{DNSMonitorConfig: DNSMonitorConfig{RecordType: String(DNSRecordTypeSRV)}},
+			&DNSConditionConfig{DNSMonitorConfig: DNSMonitorConfig{RecordType: String(DNSRecordTypeSRV)}},
+		},
+		{
+			"interval_overrides",
+			&DNSConditionConfig{DNSMonitorConfig: DNSMonitorConfig{Interval: TimeDuration(time.Second)}},
+			&DNSConditionConfig{DNSMonitorConfig: DNSMonitorConfig{Interval: TimeDuration(time.Minute)}},
+			&DNSConditionConfig{DNSMonitorConfig: DNSMonitorConfig{Interval: TimeDuration(time.Minute)}},
+		},
+		{
+			"interval_empty_two",
+			&DNSConditionConfig{},
+			&DNSConditionConfig{DNSMonitorConfig: DNSMonitorConfig{Interval: TimeDuration(time.Minute)}},
+			&DNSConditionConfig{DNSMonitorConfig: DNSMonitorConfig{Interval: TimeDuration(time.Minute)}},
+		},
+		{
+			"use_as_module_input_overrides",
+			&DNSConditionConfig{UseAsModuleInput: Bool(true)},
+			&DNSConditionConfig{UseAsModuleInput: Bool(false)},
+			&DNSConditionConfig{UseAsModuleInput: Bool(false)},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			if tc.r == nil {
+				// returned nil interface has nil type, which is unequal to tc.r
+				assert.Nil(t, r)
+			} else {
+				assert.Equal(t, tc.r, r)
+			}
+		})
+	}
+}
+
+func TestDNSConditionConfig_Finalize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		i    *DNSConditionConfig
+		r    *DNSConditionConfig
+	}{
+		{
+			"nil",
+			nil,
+			nil,
+		},
+		{
+			"empty",
+			&DNSConditionConfig{},
+			&DNSConditionConfig{
+				DNSMonitorConfig: DNSMonitorConfig{
+					Name:       String(""),
+					RecordType: String(DNSRecordTypeA),
+					Interval:   TimeDuration(DefaultDNSInterval),
+				},
+				UseAsModuleInput: Bool(true),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.i.Finalize()
+			assert.Equal(t, tc.r, tc.i)
+		})
+	}
+}
+
+func TestDNSConditionConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		expectErr bool
+		c         *DNSConditionConfig
+	}{
+		{
+			"nil",
+			false,
+			nil,
+		},
+		{
+			"valid_a",
+			false,
+			&DNSConditionConfig{
+				DNSMonitorConfig: DNSMonitorConfig{
+					Name:       String("example.com"),
+					RecordType: String(DNSRecordTypeA),
+					Interval:   TimeDuration(5 * time.Second),
+				},
+			},
+		},
+		{
+			"valid_srv",
+			false,
+			&DNSConditionConfig{
+				DNSMonitorConfig: DNSMonitorConfig{
+					Name:       String("_http._tcp.example.com"),
+					RecordType: String(DNSRecordTypeSRV),
+				},
+			},
+		},
+		{
+			"missing_name",
+			true,
+			&DNSConditionConfig{
+				DNSMonitorConfig: DNSMonitorConfig{
+					RecordType: String(DNSRecordTypeA),
+				},
+			},
+		},
+		{
+			"unsupported_record_type",
+			true,
+			&DNSConditionConfig{
+				DNSMonitorConfig: DNSMonitorConfig{
+					Name:       String("example.com"),
+					RecordType: String("MX"),
+				},
+			},
+		},
+		{
+			"interval_too_short",
+			true,
+			&DNSConditionConfig{
+				DNSMonitorConfig: DNSMonitorConfig{
+					Name:       String("example.com"),
+					RecordType: String(DNSRecordTypeA),
+					Interval:   TimeDuration(time.Millisecond),
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.c.Validate()
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
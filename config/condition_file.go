@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+)
+
+var _ ConditionConfig = (*FileConditionConfig)(nil)
+
+// FileConditionConfig configures a condition configuration block of type
+// 'file'. A file condition periodically hashes local files and is triggered
+// when the content of any of the files changes, or when a file is added or
+// removed.
+type FileConditionConfig struct {
+	FileMonitorConfig `mapstructure:",squash" json:"file"`
+
+	UseAsModuleInput *bool `mapstructure:"use_as_module_input" json:"use_as_module_input"`
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *FileConditionConfig) Copy() MonitorConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o FileConditionConfig
+	o.UseAsModuleInput = BoolCopy(c.UseAsModuleInput)
+
+	m, ok := c.FileMonitorConfig.Copy().(*FileMonitorConfig)
+	if !ok {
+		return nil
+	}
+
+	o.FileMonitorConfig = *m
+
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+func (c *FileConditionConfig) Merge(o MonitorConfig) MonitorConfig {
+	if c == nil {
+		if isConditionNil(o) { // o is interface, use isConditionNil()
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if isConditionNil(o) {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+	o2, ok := o.(*FileConditionConfig)
+	if !ok {
+		return nil
+	}
+
+	r2 := r.(*FileConditionConfig)
+
+	if o2.UseAsModuleInput != nil {
+		r2.UseAsModuleInput = BoolCopy(o2.UseAsModuleInput)
+	}
+
+	mm, ok := c.FileMonitorConfig.Merge(&o2.FileMonitorConfig).(*FileMonitorConfig)
+	if !ok {
+		return nil
+	}
+	r2.FileMonitorConfig = *mm
+
+	return r2
+}
+
+// Finalize ensures there no nil pointers.
+func (c *FileConditionConfig) Finalize() {
+	if c == nil { // config not required, return early
+		return
+	}
+
+	if c.UseAsModuleInput == nil {
+		c.UseAsModuleInput = Bool(true)
+	}
+
+	c.FileMonitorConfig.Finalize()
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *FileConditionConfig) Validate() error {
+	if c == nil { // config not required, return early
+		return nil
+	}
+
+	return c.FileMonitorConfig.Validate()
+}
+
+// GoString defines the printable version of this struct.
+func (c *FileConditionConfig) GoString() string {
+	if c == nil {
+		return "(*FileConditionConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&FileConditionConfig{"+
+		"%s, "+
+		"UseAsModuleInput:%v"+
+		"}",
+		c.FileMonitorConfig.GoString(),
+		BoolVal(c.UseAsModuleInput),
+	)
+}
@@ -0,0 +1,236 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileConditionConfig_Copy(t *testing.T) {
+	t.Parallel()
+
+	finalizedConf := &FileConditionConfig{}
+	finalizedConf.Finalize()
+
+	cases := []struct {
+		name string
+		a    *FileConditionConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&FileConditionConfig{},
+		},
+		{
+			"finalized",
+			finalizedConf,
+		},
+		{
+			"fully_configured",
+			&FileConditionConfig{
+				FileMonitorConfig: FileMonitorConfig{
+					Paths:          []string{"/etc/cts/allowlist.txt"},
+					Interval:       TimeDuration(5 * time.Second),
+					IncludeContent: Bool(false),
+				},
+				UseAsModuleInput: Bool(false),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tc.a.Copy()
+			if tc.a == nil {
+				// returned nil interface has nil type, which is unequal to tc.a
+				assert.Nil(t, r)
+			} else {
+				assert.Equal(t, tc.a, r)
+			}
+		})
+	}
+}
+
+func TestFileConditionConfig_Merge(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *FileConditionConfig
+		b    *FileConditionConfig
+		r    *FileConditionConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&FileConditionConfig{},
+			&FileConditionConfig{},
+		},
+		{
+			"nil_b",
+			&FileConditionConfig{},
+			nil,
+			&FileConditionConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"empty",
+			&FileConditionConfig{},
+			&FileConditionConfig{},
+			&FileConditionConfig{},
+		},
+		{
+			"paths_merges",
+			&FileConditionConfig{FileMonitorConfig: FileMonitorConfig{Paths: []string{"/a"}}},
+			&FileConditionConfig{FileMonitorConfig: FileMonitorConfig{Paths: []string{"/b"}}},
+			&FileConditionConfig{FileMonitorConfig: FileMonitorConfig{Paths: []string{"/a", "/b"}}},
+		},
+		{
+			"interval_overrides",
+			&FileConditionConfig{FileMonitorConfig: FileMonitorConfig{Interval: TimeDuration(time.Second)}},
+			&FileConditionConfig{FileMonitorConfig: FileMonitorConfig{Interval: TimeDuration(time.Minute)}},
+			&FileConditionConfig{FileMonitorConfig: FileMonitorConfig{Interval: TimeDuration(time.Minute)}},
+		},
+		{
+			"include_content_overrides",
+			&FileConditionConfig{FileMonitorConfig: FileMonitorConfig{IncludeContent: Bool(true)}},
+			&FileConditionConfig{FileMonitorConfig: FileMonitorConfig{IncludeContent: Bool(false)}},
+			&FileConditionConfig{FileMonitorConfig: FileMonitorConfig{IncludeContent: Bool(false)}},
+		},
+		{
+			"use_as_module_input_overrides",
+			&FileConditionConfig{UseAsModuleInput: Bool(true)},
+			&FileConditionConfig{UseAsModuleInput: Bool(false)},
+			&FileConditionConfig{UseAsModuleInput: Bool(false)},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			if tc.r == nil {
+				// returned nil interface has nil type, which is unequal to tc.r
+				assert.Nil(t, r)
+			} else {
+				assert.Equal(t, tc.r, r)
+			}
+		})
+	}
+}
+
+func TestFileConditionConfig_Finalize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		i    *FileConditionConfig
+		r    *FileConditionConfig
+	}{
+		{
+			"nil",
+			nil,
+			nil,
+		},
+		{
+			"empty",
+			&FileConditionConfig{},
+			&FileConditionConfig{
+				FileMonitorConfig: FileMonitorConfig{
+					Paths:          []string{},
+					Interval:       TimeDuration(DefaultFileInterval),
+					IncludeContent: Bool(true),
+				},
+				UseAsModuleInput: Bool(true),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.i.Finalize()
+			assert.Equal(t, tc.r, tc.i)
+		})
+	}
+}
+
+func TestFileConditionConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		expectErr bool
+		c         *FileConditionConfig
+	}{
+		{
+			"nil",
+			false,
+			nil,
+		},
+		{
+			"valid",
+			false,
+			&FileConditionConfig{
+				FileMonitorConfig: FileMonitorConfig{
+					Paths:    []string{"/etc/cts/allowlist.txt", "/etc/cts/rules"},
+					Interval: TimeDuration(5 * time.Second),
+				},
+			},
+		},
+		{
+			"missing_paths",
+			true,
+			&FileConditionConfig{},
+		},
+		{
+			"empty_path",
+			true,
+			&FileConditionConfig{
+				FileMonitorConfig: FileMonitorConfig{
+					Paths: []string{" "},
+				},
+			},
+		},
+		{
+			"relative_path",
+			true,
+			&FileConditionConfig{
+				FileMonitorConfig: FileMonitorConfig{
+					Paths: []string{"rules/allowlist.txt"},
+				},
+			},
+		},
+		{
+			"interval_too_short",
+			true,
+			&FileConditionConfig{
+				FileMonitorConfig: FileMonitorConfig{
+					Paths:    []string{"/etc/cts/allowlist.txt"},
+					Interval: TimeDuration(time.Millisecond),
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.c.Validate()
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	pluginType = "plugin"
+
+	// DefaultPluginInterval is the default interval between executions of a
+	// condition plugin
+	DefaultPluginInterval = 30 * time.Second
+
+	// minPluginInterval is the minimum allowed interval between executions of
+	// a condition plugin
+	minPluginInterval = 1 * time.Second
+)
+
+var _ ConditionConfig = (*PluginConditionConfig)(nil)
+
+// PluginMonitorConfig exists purely to allow json / hcl conversions
+// to work seamlessly by encoding and decoding under the "plugin" name.
+// It should not be treated as a standalone module input.
+type PluginMonitorConfig struct {
+	// Command is the absolute path of the plugin executable. The plugin is
+	// executed once per interval and writes a JSON value to stdout that
+	// represents the current state of what it monitors.
+	Command *string `mapstructure:"command" json:"command"`
+
+	// Args are the arguments to pass to the plugin executable.
+	Args []string `mapstructure:"args" json:"args"`
+
+	// Interval is the period of time to wait between executions of the
+	// plugin.
+	Interval *time.Duration `mapstructure:"interval" json:"interval"`
+}
+
+// PluginConditionConfig configures a condition configuration block of type
+// 'plugin'. A plugin condition periodically executes an external condition
+// plugin and is triggered when the JSON output of the plugin changes. This
+// allows monitoring objects outside of Consul, e.g. a database or an object
+// storage bucket, without changes to CTS.
+type PluginConditionConfig struct {
+	PluginMonitorConfig `mapstructure:",squash" json:"plugin"`
+
+	UseAsModuleInput *bool `mapstructure:"use_as_module_input" json:"use_as_module_input"`
+}
+
+func (c *PluginConditionConfig) VariableType() string {
+	return "plugin_data"
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *PluginConditionConfig) Copy() MonitorConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o PluginConditionConfig
+	o.Command = StringCopy(c.Command)
+	if c.Args != nil {
+		o.Args = make([]string, 0, len(c.Args))
+		o.Args = append(o.Args, c.Args...)
+	}
+	o.Interval = TimeDurationCopy(c.Interval)
+	o.UseAsModuleInput = BoolCopy(c.UseAsModuleInput)
+
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+// Args are not appended since the arguments of different plugin commands are
+// not meaningful when combined.
+func (c *PluginConditionConfig) Merge(o MonitorConfig) MonitorConfig {
+	if c == nil {
+		if isConditionNil(o) { // o is interface, use isConditionNil()
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if isConditionNil(o) {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+	o2, ok := o.(*PluginConditionConfig)
+	if !ok {
+		return r
+	}
+
+	r2 := r.(*PluginConditionConfig)
+
+	if o2.Command != nil {
+		r2.Command = StringCopy(o2.Command)
+	}
+
+	if o2.Args != nil {
+		r2.Args = make([]string, 0, len(o2.Args))
+		r2.Args = append(r2.Args, o2.Args...)
+	}
+
+	if o2.Interval != nil {
+		r2.Interval = TimeDurationCopy(o2.Interval)
+	}
+
+	if o2.UseAsModuleInput != nil {
+		r2.UseAsModuleInput = BoolCopy(o2.UseAsModuleInput)
+	}
+
+	return r2
+}
+
+// Finalize ensures there no nil pointers.
+func (c *PluginConditionConfig) Finalize() {
+	if c == nil { // config not required, return early
+		return
+	}
+
+	if c.Command == nil {
+		c.Command = String("")
+	}
+
+	if c.Args == nil {
+		c.Args = []string{}
+	}
+
+	if c.Interval == nil {
+		c.Interval = TimeDuration(DefaultPluginInterval)
+	}
+
+	if c.UseAsModuleInput == nil {
+		c.UseAsModuleInput = Bool(true)
+	}
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *PluginConditionConfig) Validate() error {
+	if c == nil { // config not required, return early
+		return nil
+	}
+
+	command := StringVal(c.Command)
+	if strings.TrimSpace(command) == "" {
+		return fmt.Errorf("command is required for plugin condition")
+	}
+
+	// The plugin is executed without a shell, so require an absolute path to
+	// avoid depending on the PATH of the CTS process.
+	if !filepath.IsAbs(command) {
+		return fmt.Errorf("command for plugin condition must be an absolute "+
+			"path, got %q", command)
+	}
+
+	if c.Interval != nil && *c.Interval < minPluginInterval {
+		return fmt.Errorf("interval for plugin condition must be at least %s, "+
+			"got %s", minPluginInterval, *c.Interval)
+	}
+
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *PluginConditionConfig) GoString() string {
+	if c == nil {
+		return "(*PluginConditionConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&PluginConditionConfig{"+
+		"Command:%s, "+
+		"Args:%s, "+
+		"Interval:%s, "+
+		"UseAsModuleInput:%v"+
+		"}",
+		StringVal(c.Command),
+		c.Args,
+		TimeDurationVal(c.Interval),
+		BoolVal(c.UseAsModuleInput),
+	)
+}
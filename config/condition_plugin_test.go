@@ -0,0 +1,244 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluginConditionConfig_Copy(t *testing.T) {
+	t.Parallel()
+
+	finalizedConf := &PluginConditionConfig{}
+	finalizedConf.Finalize()
+
+	cases := []struct {
+		name string
+		a    *PluginConditionConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&PluginConditionConfig{},
+		},
+		{
+			"finalized",
+			finalizedConf,
+		},
+		{
+			"fully_configured",
+			&PluginConditionConfig{
+				PluginMonitorConfig: PluginMonitorConfig{
+					Command:  String("/opt/cts/plugins/s3-bucket"),
+					Args:     []string{"-bucket", "releases"},
+					Interval: TimeDuration(10 * time.Second),
+				},
+				UseAsModuleInput: Bool(false),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tc.a.Copy()
+			if tc.a == nil {
+				// returned nil interface has nil type, which is unequal to tc.a
+				assert.Nil(t, r)
+			} else {
+				assert.Equal(t, tc.a, r)
+			}
+		})
+	}
+}
+
+func TestPluginConditionConfig_Merge(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *PluginConditionConfig
+		b    *PluginConditionConfig
+		r    *PluginConditionConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&PluginConditionConfig{},
+			&PluginConditionConfig{},
+		},
+		{
+			"nil_b",
+			&PluginConditionConfig{},
+			nil,
+			&PluginConditionConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"empty",
+			&PluginConditionConfig{},
+			&PluginConditionConfig{},
+			&PluginConditionConfig{},
+		},
+		{
+			"command_overrides",
+			&PluginConditionConfig{PluginMonitorConfig: PluginMonitorConfig{Command: String("/a")}},
+			&PluginConditionConfig{PluginMonitorConfig: PluginMonitorConfig{Command: String("/b")}},
+			&PluginConditionConfig{PluginMonitorConfig: PluginMonitorConfig{Command: String("/b")}},
+		},
+		{
+			"command_empty_one",
+			&PluginConditionConfig{PluginMonitorConfig: PluginMonitorConfig{Command: String("/a")}},
+			&PluginConditionConfig{},
+			&PluginConditionConfig{PluginMonitorConfig: PluginMonitorConfig{Command: String("/a")}},
+		},
+		{
+			"args_overrides",
+			&PluginConditionConfig{PluginMonitorConfig: PluginMonitorConfig{Args: []string{"a"}}},
+			&PluginConditionConfig{PluginMonitorConfig: PluginMonitorConfig{Args: []string{"b", "c"}}},
+			&PluginConditionConfig{PluginMonitorConfig: PluginMonitorConfig{Args: []string{"b", "c"}}},
+		},
+		{
+			"args_empty_two",
+			&PluginConditionConfig{PluginMonitorConfig: PluginMonitorConfig{Args: []string{"a"}}},
+			&PluginConditionConfig{},
+			&PluginConditionConfig{PluginMonitorConfig: PluginMonitorConfig{Args: []string{"a"}}},
+		},
+		{
+			"interval_overrides",
+			&PluginConditionConfig{PluginMonitorConfig: PluginMonitorConfig{Interval: TimeDuration(time.Second)}},
+			&PluginConditionConfig{PluginMonitorConfig: PluginMonitorConfig{Interval: TimeDuration(time.Minute)}},
+			&PluginConditionConfig{PluginMonitorConfig: PluginMonitorConfig{Interval: TimeDuration(time.Minute)}},
+		},
+		{
+			"use_as_module_input_overrides",
+			&PluginConditionConfig{UseAsModuleInput: Bool(true)},
+			&PluginConditionConfig{UseAsModuleInput: Bool(false)},
+			&PluginConditionConfig{UseAsModuleInput: Bool(false)},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			if tc.r == nil {
+				// returned nil interface has nil type, which is unequal to tc.r
+				assert.Nil(t, r)
+			} else {
+				assert.Equal(t, tc.r, r)
+			}
+		})
+	}
+}
+
+func TestPluginConditionConfig_Finalize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		i    *PluginConditionConfig
+		r    *PluginConditionConfig
+	}{
+		{
+			"nil",
+			nil,
+			nil,
+		},
+		{
+			"empty",
+			&PluginConditionConfig{},
+			&PluginConditionConfig{
+				PluginMonitorConfig: PluginMonitorConfig{
+					Command:  String(""),
+					Args:     []string{},
+					Interval: TimeDuration(DefaultPluginInterval),
+				},
+				UseAsModuleInput: Bool(true),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.i.Finalize()
+			assert.Equal(t, tc.r, tc.i)
+		})
+	}
+}
+
+func TestPluginConditionConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		expectErr bool
+		c         *PluginConditionConfig
+	}{
+		{
+			"nil",
+			false,
+			nil,
+		},
+		{
+			"valid",
+			false,
+			&PluginConditionConfig{
+				PluginMonitorConfig: PluginMonitorConfig{
+					Command:  String("/opt/cts/plugins/s3-bucket"),
+					Args:     []string{"-bucket", "releases"},
+					Interval: TimeDuration(5 * time.Second),
+				},
+			},
+		},
+		{
+			"missing_command",
+			true,
+			&PluginConditionConfig{
+				PluginMonitorConfig: PluginMonitorConfig{
+					Interval: TimeDuration(5 * time.Second),
+				},
+			},
+		},
+		{
+			"relative_command",
+			true,
+			&PluginConditionConfig{
+				PluginMonitorConfig: PluginMonitorConfig{
+					Command: String("s3-bucket"),
+				},
+			},
+		},
+		{
+			"interval_too_short",
+			true,
+			&PluginConditionConfig{
+				PluginMonitorConfig: PluginMonitorConfig{
+					Command:  String("/opt/cts/plugins/s3-bucket"),
+					Interval: TimeDuration(time.Millisecond),
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.c.Validate()
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -5,11 +5,18 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/cronexpr"
 )
 
-const scheduleType = "schedule"
+const (
+	scheduleType = "schedule"
+
+	// DefaultScheduleCatchUpMaxAge is the default maximum age of a missed
+	// scheduled run that is caught up
+	DefaultScheduleCatchUpMaxAge = 24 * time.Hour
+)
 
 var _ ConditionConfig = (*ScheduleConditionConfig)(nil)
 
@@ -18,6 +25,22 @@ var _ ConditionConfig = (*ScheduleConditionConfig)(nil)
 // It should not be treated as a standalone module input.
 type ScheduleMonitorConfig struct {
 	Cron *string `mapstructure:"cron" json:"cron"`
+
+	// Crons configures additional cron expressions of the schedule, e.g. to
+	// run hourly on weekdays and daily on weekends. The task runs at the
+	// scheduled times of Cron and all of Crons. Crons merged from multiple
+	// configurations are combined.
+	Crons []string `mapstructure:"crons" json:"crons"`
+
+	// CatchUp configures the task to run once when CTS starts if a scheduled
+	// run was missed while CTS was not running, e.g. for nightly jobs. The
+	// time of the last scheduled run is recorded in the task's working
+	// directory. Disabled by default.
+	CatchUp *bool `mapstructure:"catch_up" json:"catch_up"`
+
+	// CatchUpMaxAge is the maximum age of a missed scheduled run to catch up.
+	// Missed runs that are older are skipped.
+	CatchUpMaxAge *time.Duration `mapstructure:"catch_up_max_age" json:"catch_up_max_age"`
 }
 
 // ScheduleConditionConfig configures a condition configuration block of type
@@ -39,6 +62,14 @@ func (c *ScheduleConditionConfig) Copy() MonitorConfig {
 	var o ScheduleConditionConfig
 	o.Cron = StringCopy(c.Cron)
 
+	if c.Crons != nil {
+		o.Crons = make([]string, 0, len(c.Crons))
+		o.Crons = append(o.Crons, c.Crons...)
+	}
+
+	o.CatchUp = BoolCopy(c.CatchUp)
+	o.CatchUpMaxAge = TimeDurationCopy(c.CatchUpMaxAge)
+
 	return &o
 }
 
@@ -68,6 +99,16 @@ func (c *ScheduleConditionConfig) Merge(o MonitorConfig) MonitorConfig {
 		r2.Cron = StringCopy(o2.Cron)
 	}
 
+	r2.Crons = mergeSlices(r2.Crons, o2.Crons)
+
+	if o2.CatchUp != nil {
+		r2.CatchUp = BoolCopy(o2.CatchUp)
+	}
+
+	if o2.CatchUpMaxAge != nil {
+		r2.CatchUpMaxAge = TimeDurationCopy(o2.CatchUpMaxAge)
+	}
+
 	return r2
 }
 
@@ -80,6 +121,18 @@ func (c *ScheduleConditionConfig) Finalize() {
 	if c.Cron == nil {
 		c.Cron = String("")
 	}
+
+	if c.Crons == nil {
+		c.Crons = []string{}
+	}
+
+	if c.CatchUp == nil {
+		c.CatchUp = Bool(false)
+	}
+
+	if c.CatchUpMaxAge == nil {
+		c.CatchUpMaxAge = TimeDuration(DefaultScheduleCatchUpMaxAge)
+	}
 }
 
 // Validate validates the values and required options. This method is recommended
@@ -89,19 +142,48 @@ func (c *ScheduleConditionConfig) Validate() error {
 		return nil
 	}
 
-	if c.Cron == nil || len(*c.Cron) == 0 {
-		return fmt.Errorf("cron config is required for schedule condition")
+	crons := c.CronExpressions()
+	if len(crons) == 0 {
+		return fmt.Errorf("cron or crons config is required for schedule condition")
+	}
+
+	for _, cron := range c.Crons {
+		if cron == "" {
+			return fmt.Errorf("crons config for schedule condition includes " +
+				"empty string(s). cron expressions cannot be empty")
+		}
+	}
+
+	for _, cron := range crons {
+		if _, err := cronexpr.Parse(cron); err != nil {
+			return fmt.Errorf("unable to parse schedule condition's cron config "+
+				"%q: %s. for more information on writing cron expressions, see %s",
+				cron, err, "https://github.com/hashicorp/cronexpr")
+		}
 	}
 
-	if _, err := cronexpr.Parse(*c.Cron); err != nil {
-		return fmt.Errorf("unable to parse schedule condition's cron config "+
-			"%q: %s. for more information on writing cron expressions, see %s",
-			StringVal(c.Cron), err, "https://github.com/hashicorp/cronexpr")
+	if BoolVal(c.CatchUp) && c.CatchUpMaxAge != nil && *c.CatchUpMaxAge <= 0 {
+		return fmt.Errorf("catch_up_max_age for schedule condition must be "+
+			"positive: %s", *c.CatchUpMaxAge)
 	}
 
 	return nil
 }
 
+// CronExpressions returns all of the configured cron expressions of the
+// schedule, starting with Cron followed by Crons
+func (c *ScheduleMonitorConfig) CronExpressions() []string {
+	if c == nil {
+		return nil
+	}
+
+	var crons []string
+	if cron := StringVal(c.Cron); cron != "" {
+		crons = append(crons, cron)
+	}
+	return append(crons, c.Crons...)
+}
+
 // GoString defines the printable version of this struct.
 func (c *ScheduleConditionConfig) GoString() string {
 	if c == nil {
@@ -110,7 +192,13 @@ func (c *ScheduleConditionConfig) GoString() string {
 
 	return fmt.Sprintf("&ScheduleConditionConfig{"+
 		"Cron:%s, "+
+		"Crons:%s, "+
+		"CatchUp:%t, "+
+		"CatchUpMaxAge:%s, "+
 		"}",
 		StringVal(c.Cron),
+		c.Crons,
+		BoolVal(c.CatchUp),
+		TimeDurationVal(c.CatchUpMaxAge),
 	)
 }
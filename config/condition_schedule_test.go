@@ -5,6 +5,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -35,7 +36,10 @@ func TestScheduleConditionConfig_Copy(t *testing.T) {
 			"fully_configured",
 			&ScheduleConditionConfig{
 				ScheduleMonitorConfig: ScheduleMonitorConfig{
-					Cron: String("* * * * * * *"),
+					Cron:          String("* * * * * * *"),
+					Crons:         []string{"0 * * * 1-5", "0 0 * * 0,6"},
+					CatchUp:       Bool(true),
+					CatchUpMaxAge: TimeDuration(time.Hour),
 				},
 			},
 		},
@@ -151,6 +155,44 @@ func TestScheduleConditionConfig_Merge(t *testing.T) {
 				},
 			},
 		},
+		{
+			"crons_merges",
+			&ScheduleConditionConfig{
+				ScheduleMonitorConfig: ScheduleMonitorConfig{
+					Crons: []string{"0 * * * 1-5"},
+				},
+			},
+			&ScheduleConditionConfig{
+				ScheduleMonitorConfig: ScheduleMonitorConfig{
+					Crons: []string{"0 * * * 1-5", "0 0 * * 0,6"},
+				},
+			},
+			&ScheduleConditionConfig{
+				ScheduleMonitorConfig: ScheduleMonitorConfig{
+					Crons: []string{"0 * * * 1-5", "0 0 * * 0,6"},
+				},
+			},
+		},
+		{
+			"catch_up_overrides",
+			&ScheduleConditionConfig{
+				ScheduleMonitorConfig: ScheduleMonitorConfig{
+					CatchUp:       Bool(false),
+					CatchUpMaxAge: TimeDuration(time.Hour),
+				},
+			},
+			&ScheduleConditionConfig{
+				ScheduleMonitorConfig: ScheduleMonitorConfig{
+					CatchUp: Bool(true),
+				},
+			},
+			&ScheduleConditionConfig{
+				ScheduleMonitorConfig: ScheduleMonitorConfig{
+					CatchUp:       Bool(true),
+					CatchUpMaxAge: TimeDuration(time.Hour),
+				},
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -184,7 +226,10 @@ func TestScheduleConditionConfig_Finalize(t *testing.T) {
 			&ScheduleConditionConfig{},
 			&ScheduleConditionConfig{
 				ScheduleMonitorConfig: ScheduleMonitorConfig{
-					Cron: String(""),
+					Cron:          String(""),
+					Crons:         []string{},
+					CatchUp:       Bool(false),
+					CatchUpMaxAge: TimeDuration(DefaultScheduleCatchUpMaxAge),
 				},
 			},
 		},
@@ -197,7 +242,44 @@ func TestScheduleConditionConfig_Finalize(t *testing.T) {
 			},
 			&ScheduleConditionConfig{
 				ScheduleMonitorConfig: ScheduleMonitorConfig{
-					Cron: String("* * * * *"),
+					Cron:          String("* * * * *"),
+					Crons:         []string{},
+					CatchUp:       Bool(false),
+					CatchUpMaxAge: TimeDuration(DefaultScheduleCatchUpMaxAge),
+				},
+			},
+		},
+		{
+			"catch_up_configured",
+			&ScheduleConditionConfig{
+				ScheduleMonitorConfig: ScheduleMonitorConfig{
+					Cron:          String("* * * * *"),
+					CatchUp:       Bool(true),
+					CatchUpMaxAge: TimeDuration(time.Hour),
+				},
+			},
+			&ScheduleConditionConfig{
+				ScheduleMonitorConfig: ScheduleMonitorConfig{
+					Cron:          String("* * * * *"),
+					Crons:         []string{},
+					CatchUp:       Bool(true),
+					CatchUpMaxAge: TimeDuration(time.Hour),
+				},
+			},
+		},
+		{
+			"crons_configured",
+			&ScheduleConditionConfig{
+				ScheduleMonitorConfig: ScheduleMonitorConfig{
+					Crons: []string{"0 * * * 1-5", "0 0 * * 0,6"},
+				},
+			},
+			&ScheduleConditionConfig{
+				ScheduleMonitorConfig: ScheduleMonitorConfig{
+					Cron:          String(""),
+					Crons:         []string{"0 * * * 1-5", "0 0 * * 0,6"},
+					CatchUp:       Bool(false),
+					CatchUpMaxAge: TimeDuration(DefaultScheduleCatchUpMaxAge),
 				},
 			},
 		},
@@ -247,6 +329,75 @@ func TestScheduleConditionConfig_Validate(t *testing.T) {
 				},
 			},
 		},
+		{
+			"valid_crons",
+			false,
+			&ScheduleConditionConfig{
+				ScheduleMonitorConfig: ScheduleMonitorConfig{
+					Crons: []string{"0 * * * 1-5", "0 0 * * 0,6"},
+				},
+			},
+		},
+		{
+			"valid_cron_and_crons",
+			false,
+			&ScheduleConditionConfig{
+				ScheduleMonitorConfig: ScheduleMonitorConfig{
+					Cron:  String("0 * * * 1-5"),
+					Crons: []string{"0 0 * * 0,6"},
+				},
+			},
+		},
+		{
+			"empty_crons",
+			true,
+			&ScheduleConditionConfig{
+				ScheduleMonitorConfig: ScheduleMonitorConfig{
+					Cron:  String(""),
+					Crons: []string{},
+				},
+			},
+		},
+		{
+			"crons_empty_string",
+			true,
+			&ScheduleConditionConfig{
+				ScheduleMonitorConfig: ScheduleMonitorConfig{
+					Crons: []string{"0 * * * 1-5", ""},
+				},
+			},
+		},
+		{
+			"invalid_crons",
+			true,
+			&ScheduleConditionConfig{
+				ScheduleMonitorConfig: ScheduleMonitorConfig{
+					Crons: []string{"0 * * * 1-5", "invalid"},
+				},
+			},
+		},
+		{
+			"valid_catch_up",
+			false,
+			&ScheduleConditionConfig{
+				ScheduleMonitorConfig: ScheduleMonitorConfig{
+					Cron:          String("* * * * * * *"),
+					CatchUp:       Bool(true),
+					CatchUpMaxAge: TimeDuration(time.Hour),
+				},
+			},
+		},
+		{
+			"invalid_catch_up_max_age",
+			true,
+			&ScheduleConditionConfig{
+				ScheduleMonitorConfig: ScheduleMonitorConfig{
+					Cron:          String("* * * * * * *"),
+					CatchUp:       Bool(true),
+					CatchUpMaxAge: TimeDuration(0),
+				},
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -260,3 +411,46 @@ func TestScheduleConditionConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestScheduleMonitorConfig_CronExpressions(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		c        *ScheduleMonitorConfig
+		expected []string
+	}{
+		{
+			"nil",
+			nil,
+			nil,
+		},
+		{
+			"cron",
+			&ScheduleMonitorConfig{Cron: String("0 * * * 1-5")},
+			[]string{"0 * * * 1-5"},
+		},
+		{
+			"crons",
+			&ScheduleMonitorConfig{
+				Cron:  String(""),
+				Crons: []string{"0 * * * 1-5", "0 0 * * 0,6"},
+			},
+			[]string{"0 * * * 1-5", "0 0 * * 0,6"},
+		},
+		{
+			"cron_and_crons",
+			&ScheduleMonitorConfig{
+				Cron:  String("0 * * * 1-5"),
+				Crons: []string{"0 0 * * 0,6"},
+			},
+			[]string{"0 * * * 1-5", "0 0 * * 0,6"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.c.CronExpressions())
+		})
+	}
+}
@@ -20,6 +20,11 @@ type ServicesConditionConfig struct {
 	// UseAsModuleInput was previously named SourceIncludesVar - deprecated v0.5
 	UseAsModuleInput            *bool `mapstructure:"use_as_module_input" json:"use_as_module_input"`
 	DeprecatedSourceIncludesVar *bool `mapstructure:"source_includes_var" json:"source_includes_var"`
+
+	// FlapSuppression configures the condition to not trigger the task for
+	// service instances that register and deregister repeatedly. When unset,
+	// it will retain a nil value even after Finalize().
+	FlapSuppression *FlapSuppressionConfig `mapstructure:"flap_suppression" json:"flap_suppression"`
 }
 
 // Copy returns a deep copy of this configuration.
@@ -31,6 +36,7 @@ func (c *ServicesConditionConfig) Copy() MonitorConfig {
 	var o ServicesConditionConfig
 	o.UseAsModuleInput = BoolCopy(c.UseAsModuleInput)
 	o.DeprecatedSourceIncludesVar = BoolCopy(c.DeprecatedSourceIncludesVar)
+	o.FlapSuppression = c.FlapSuppression.Copy()
 
 	svc, ok := c.ServicesMonitorConfig.Copy().(*ServicesMonitorConfig)
 	if !ok {
@@ -71,6 +77,9 @@ func (c *ServicesConditionConfig) Merge(o MonitorConfig) MonitorConfig {
 	if o2.DeprecatedSourceIncludesVar != nil {
 		r2.DeprecatedSourceIncludesVar = BoolCopy(o2.DeprecatedSourceIncludesVar)
 	}
+	if o2.FlapSuppression != nil {
+		r2.FlapSuppression = r2.FlapSuppression.Merge(o2.FlapSuppression)
+	}
 
 	merged, ok := c.ServicesMonitorConfig.Merge(&o2.ServicesMonitorConfig).(*ServicesMonitorConfig)
 	if !ok {
@@ -102,6 +111,7 @@ func (c *ServicesConditionConfig) Finalize() {
 	if c.UseAsModuleInput == nil {
 		c.UseAsModuleInput = Bool(true)
 	}
+	c.FlapSuppression.Finalize()
 
 	c.ServicesMonitorConfig.Finalize()
 }
@@ -116,6 +126,10 @@ func (c *ServicesConditionConfig) Validate() error {
 		return fmt.Errorf("error validating `condition \"services\"` block: %s",
 			err)
 	}
+	if err := c.FlapSuppression.Validate(); err != nil {
+		return fmt.Errorf("error validating `condition \"services\"` block: %s",
+			err)
+	}
 	return nil
 }
 
@@ -127,9 +141,11 @@ func (c *ServicesConditionConfig) GoString() string {
 
 	return fmt.Sprintf("&ServicesConditionConfig{"+
 		"%s, "+
-		"UseAsModuleInput:%v"+
+		"UseAsModuleInput:%v, "+
+		"FlapSuppression:%s"+
 		"}",
 		c.ServicesMonitorConfig.GoString(),
 		BoolVal(c.UseAsModuleInput),
+		c.FlapSuppression.GoString(),
 	)
 }
@@ -5,6 +5,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -45,6 +46,11 @@ func TestServicesConditionConfig_Copy(t *testing.T) {
 				},
 				UseAsModuleInput:            Bool(false),
 				DeprecatedSourceIncludesVar: Bool(false),
+				FlapSuppression: &FlapSuppressionConfig{
+					Enabled:     Bool(true),
+					Transitions: Int(2),
+					Window:      TimeDuration(time.Minute),
+				},
 			},
 		},
 	}
@@ -95,6 +101,19 @@ func TestServicesConditionConfig_Merge(t *testing.T) {
 			&ServicesConditionConfig{},
 			&ServicesConditionConfig{},
 		},
+		{
+			"flap_suppression_merges",
+			&ServicesConditionConfig{FlapSuppression: &FlapSuppressionConfig{
+				Transitions: Int(2),
+			}},
+			&ServicesConditionConfig{FlapSuppression: &FlapSuppressionConfig{
+				Window: TimeDuration(time.Minute),
+			}},
+			&ServicesConditionConfig{FlapSuppression: &FlapSuppressionConfig{
+				Transitions: Int(2),
+				Window:      TimeDuration(time.Minute),
+			}},
+		},
 		{
 			"source_includes_var_overrides",
 			&ServicesConditionConfig{DeprecatedSourceIncludesVar: Bool(true)},
@@ -212,12 +231,15 @@ func TestServicesConditionConfig_Finalize(t *testing.T) {
 			&ServicesConditionConfig{},
 			&ServicesConditionConfig{
 				ServicesMonitorConfig: ServicesMonitorConfig{
-					Regexp:             nil,
-					Names:              []string{},
-					Datacenter:         String(""),
-					Namespace:          String(""),
-					Filter:             String(""),
-					CTSUserDefinedMeta: map[string]string{},
+					Regexp:                  nil,
+					Names:                   []string{},
+					Datacenter:              String(""),
+					Namespace:               String(""),
+					Filter:                  String(""),
+					CTSUserDefinedMeta:      map[string]string{},
+					IncludeExtendedMetadata: Bool(false),
+					Query:                   String(""),
+					Bootstrap:               Bool(false),
 				},
 				UseAsModuleInput: Bool(true),
 			},
@@ -302,6 +324,20 @@ func TestServicesConditionConfig_Validate(t *testing.T) {
 				},
 			},
 		},
+		{
+			"invalid flap_suppression",
+			true,
+			&ServicesConditionConfig{
+				ServicesMonitorConfig: ServicesMonitorConfig{
+					Regexp: String(".*"),
+				},
+				FlapSuppression: &FlapSuppressionConfig{
+					Enabled:     Bool(true),
+					Transitions: Int(0),
+					Window:      TimeDuration(time.Minute),
+				},
+			},
+		},
 		{
 			"nil",
 			false,
@@ -350,7 +386,8 @@ func TestServicesCondition_GoString(t *testing.T) {
 			},
 			"&ServicesConditionConfig{&ServicesMonitorConfig{Regexp:^api$, Names:[], " +
 				"Datacenter:dc, Namespace:namespace, Filter:filter, " +
-				"CTSUserDefinedMeta:map[key:value]}, UseAsModuleInput:false}",
+				"CTSUserDefinedMeta:map[key:value], IncludeExtendedMetadata:false, Query:, Bootstrap:false}, " +
+				"UseAsModuleInput:false, FlapSuppression:(*FlapSuppressionConfig)(nil)}",
 		},
 	}
 
@@ -5,6 +5,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -31,6 +32,10 @@ func TestCondition_DecodeConfig(t *testing.T) {
 						"key1": "value1",
 						"key2": "value2",
 					},
+					Kind:                String("typical"),
+					TriggerOnTagChanges: Bool(true),
+					MinInstances:        Int(0),
+					MaxInstances:        Int(0),
 				},
 			},
 			"config.hcl",
@@ -43,6 +48,8 @@ task {
 		use_as_module_input = true
 		namespace = "ns2"
 		datacenter = "dc2"
+		kind = "typical"
+		trigger_on_tag_changes = true
 		node_meta {
 		  "key1" = "value1"
 		  "key2" = "value2"
@@ -75,6 +82,9 @@ task {
 					CTSUserDefinedMeta: map[string]string{
 						"key": "value",
 					},
+					IncludeExtendedMetadata: Bool(false),
+					Query:                   String(""),
+					Bootstrap:               Bool(false),
 				},
 				UseAsModuleInput: Bool(true),
 			},
@@ -92,6 +102,42 @@ task {
 			key = "value"
 		}
 	}
+}`,
+		},
+		{
+			"services: flap suppression",
+			false,
+			&ServicesConditionConfig{
+				ServicesMonitorConfig: ServicesMonitorConfig{
+					Regexp:                  nil,
+					Names:                   []string{"api"},
+					Datacenter:              String(""),
+					Namespace:               String(""),
+					Filter:                  String(""),
+					CTSUserDefinedMeta:      map[string]string{},
+					IncludeExtendedMetadata: Bool(false),
+					Query:                   String(""),
+					Bootstrap:               Bool(false),
+				},
+				UseAsModuleInput: Bool(true),
+				FlapSuppression: &FlapSuppressionConfig{
+					Enabled:     Bool(true),
+					Transitions: Int(4),
+					Window:      TimeDuration(10 * time.Minute),
+				},
+			},
+			"config.hcl",
+			`
+task {
+	name = "services_condition_task"
+	module = "..."
+	condition "services" {
+		names = ["api"]
+		flap_suppression {
+			transitions = 4
+			window = "10m"
+		}
+	}
 }`,
 		},
 		{
@@ -113,7 +159,10 @@ task {
 			false,
 			&ScheduleConditionConfig{
 				ScheduleMonitorConfig: ScheduleMonitorConfig{
-					Cron: String("* * * * * * *"),
+					Cron:          String("* * * * * * *"),
+					Crons:         []string{},
+					CatchUp:       Bool(false),
+					CatchUpMaxAge: TimeDuration(DefaultScheduleCatchUpMaxAge),
 				},
 			},
 			"config.hcl",
@@ -124,6 +173,98 @@ task {
 	condition "schedule" {
 		cron = "* * * * * * *"
 	}
+}`,
+		},
+		{
+			"schedule: catch up",
+			false,
+			&ScheduleConditionConfig{
+				ScheduleMonitorConfig: ScheduleMonitorConfig{
+					Cron:          String("0 0 2 * * * *"),
+					Crons:         []string{},
+					CatchUp:       Bool(true),
+					CatchUpMaxAge: TimeDuration(12 * time.Hour),
+				},
+			},
+			"config.hcl",
+			`
+task {
+	name = "schedule_condition_task"
+	module = "..."
+	condition "schedule" {
+		cron = "0 0 2 * * * *"
+		catch_up = true
+		catch_up_max_age = "12h"
+	}
+}`,
+		},
+		{
+			"dns: happy path",
+			false,
+			&DNSConditionConfig{
+				DNSMonitorConfig: DNSMonitorConfig{
+					Name:       String("_http._tcp.example.com"),
+					RecordType: String("SRV"),
+					Interval:   TimeDuration(10 * time.Second),
+				},
+				UseAsModuleInput: Bool(true),
+			},
+			"config.hcl",
+			`
+task {
+	name = "dns_condition_task"
+	module = "..."
+	condition "dns" {
+		name = "_http._tcp.example.com"
+		record_type = "SRV"
+		interval = "10s"
+	}
+}`,
+		},
+		{
+			"file: happy path",
+			false,
+			&FileConditionConfig{
+				FileMonitorConfig: FileMonitorConfig{
+					Paths:          []string{"/etc/cts/allowlist.txt", "/etc/cts/rules"},
+					Interval:       TimeDuration(5 * time.Second),
+					IncludeContent: Bool(false),
+				},
+				UseAsModuleInput: Bool(true),
+			},
+			"config.hcl",
+			`
+task {
+	name = "file_condition_task"
+	module = "..."
+	condition "file" {
+		paths = ["/etc/cts/allowlist.txt", "/etc/cts/rules"]
+		interval = "5s"
+		include_content = false
+	}
+}`,
+		},
+		{
+			"plugin: happy path",
+			false,
+			&PluginConditionConfig{
+				PluginMonitorConfig: PluginMonitorConfig{
+					Command:  String("/opt/cts/plugins/s3-bucket"),
+					Args:     []string{"-bucket", "releases"},
+					Interval: TimeDuration(time.Minute),
+				},
+				UseAsModuleInput: Bool(true),
+			},
+			"config.hcl",
+			`
+task {
+	name = "plugin_condition_task"
+	module = "..."
+	condition "plugin" {
+		command = "/opt/cts/plugins/s3-bucket"
+		args = ["-bucket", "releases"]
+		interval = "1m"
+	}
 }`,
 		},
 		{
@@ -163,6 +304,7 @@ task {
 			&ConsulKVConditionConfig{
 				ConsulKVMonitorConfig: ConsulKVMonitorConfig{
 					Path:       String("key-path"),
+					Paths:      []string{},
 					Datacenter: String("dc2"),
 					Namespace:  String("ns2"),
 					Recurse:    Bool(true),
@@ -181,6 +323,30 @@ task {
 		datacenter = "dc2"
 		recurse = true
 	}
+}`,
+		},
+		{
+			"consul-kv: paths",
+			false,
+			&ConsulKVConditionConfig{
+				ConsulKVMonitorConfig: ConsulKVMonitorConfig{
+					Path:       String(""),
+					Paths:      []string{"a/b", "c/d"},
+					Datacenter: String(""),
+					Namespace:  String(""),
+					Recurse:    Bool(true),
+				},
+				UseAsModuleInput: Bool(true),
+			},
+			"config.hcl",
+			`
+task {
+	name = "condition_task"
+	module = "..."
+	condition "consul-kv" {
+		paths = ["a/b", "c/d"]
+		recurse = true
+	}
 }`,
 		},
 		{
@@ -209,6 +375,10 @@ task {
 						"key1": "value1",
 						"key2": "value2",
 					},
+					Kind:                String(""),
+					TriggerOnTagChanges: Bool(false),
+					MinInstances:        Int(2),
+					MaxInstances:        Int(10),
 				},
 			},
 			"config.json",
@@ -227,6 +397,8 @@ task {
 			  "use_as_module_input": true,
 			  "datacenter": "dc2",
 			  "namespace": "ns2",
+			  "min_instances": 2,
+			  "max_instances": 10,
 			  "node_meta": {
 				"key1": "value1",
 				"key2": "value2"
@@ -7,10 +7,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/consul-terraform-sync/internal/decode"
 	"github.com/hashicorp/consul-terraform-sync/logging"
@@ -31,6 +33,20 @@ const (
 	// created for each task with its task name.
 	DefaultWorkingDir = "sync-tasks"
 
+	// UnixSocketAddressPrefix is the prefix for API addresses that are Unix
+	// domain sockets, e.g. unix:///var/run/cts.sock
+	UnixSocketAddressPrefix = "unix://"
+
+	// DefaultIdempotencyKeyTTL is the default window that the outcome of an
+	// API request with an Idempotency-Key header is cached for retries.
+	DefaultIdempotencyKeyTTL = 24 * time.Hour
+
+	// OnceModeFailurePolicy options for handling a task that errors while
+	// running tasks once on start-up
+	OnceModeFailurePolicyFailFast   = "fail_fast"
+	OnceModeFailurePolicyContinue   = "continue"
+	OnceModeFailurePolicyBestEffort = "best_effort"
+
 	filePathLogKey = "file_path"
 )
 
@@ -39,6 +55,7 @@ var (
 		conditionToTypeFunc(),
 		moduleInputToTypeFunc(),
 		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToTimeHookFunc(time.RFC3339),
 		decode.HookTranslateKeys,
 	)
 	DecodeHclHook = mapstructure.ComposeDecodeHookFunc(
@@ -46,26 +63,48 @@ var (
 		moduleInputToTypeFunc(),
 		decode.HookWeakDecodeFromSlice,
 		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToTimeHookFunc(time.RFC3339),
 		decode.HookTranslateKeys)
 )
 
 // Config is used to configure CTS
 type Config struct {
-	LogLevel   *string `mapstructure:"log_level"`
-	ClientType *string `mapstructure:"client_type"`
-	Port       *int    `mapstructure:"port"`
-	WorkingDir *string `mapstructure:"working_dir"`
-	ID         *string `mapstructure:"id"`
-
-	Syslog             *SyslogConfig             `mapstructure:"syslog"`
-	Consul             *ConsulConfig             `mapstructure:"consul"`
-	Vault              *VaultConfig              `mapstructure:"vault"`
-	Driver             *DriverConfig             `mapstructure:"driver"`
-	Tasks              *TaskConfigs              `mapstructure:"task"`
-	DeprecatedServices *ServiceConfigs           `mapstructure:"service"`
-	TerraformProviders *TerraformProviderConfigs `mapstructure:"terraform_provider"`
-	BufferPeriod       *BufferPeriodConfig       `mapstructure:"buffer_period"`
-	TLS                *CTSTLSConfig             `mapstructure:"tls"`
+	LogLevel   *string  `mapstructure:"log_level" json:"log_level"`
+	ClientType *string  `mapstructure:"client_type" json:"client_type"`
+	Port       *int     `mapstructure:"port" json:"port"`
+	Addresses  []string `mapstructure:"address" json:"address"`
+	WorkingDir *string  `mapstructure:"working_dir" json:"working_dir"`
+	ID         *string  `mapstructure:"id" json:"id"`
+
+	// IdempotencyKeyTTL is the window that the outcome of an API request
+	// with an Idempotency-Key header is cached. Retries of the request with
+	// the same key within the window return the cached outcome. Setting to 0
+	// disables caching.
+	IdempotencyKeyTTL *time.Duration `mapstructure:"idempotency_key_ttl" json:"idempotency_key_ttl"`
+
+	// OnceModeFailurePolicy configures how CTS handles a task that errors
+	// while running all tasks once on start-up. "fail_fast" exits with the
+	// error, "continue" logs the error and runs the remaining tasks, and
+	// "best_effort" also retries the task in the background. With the -once
+	// flag, "continue" and "best_effort" exit with an error after all tasks
+	// have run if any task errored.
+	OnceModeFailurePolicy *string `mapstructure:"once_mode_failure_policy" json:"once_mode_failure_policy"`
+
+	Syslog             *SyslogConfig             `mapstructure:"syslog" json:"syslog"`
+	Consul             *ConsulConfig             `mapstructure:"consul" json:"consul"`
+	Vault              *VaultConfig              `mapstructure:"vault" json:"vault"`
+	Driver             *DriverConfig             `mapstructure:"driver" json:"driver"`
+	Tasks              *TaskConfigs              `mapstructure:"task" json:"task"`
+	DeprecatedServices *ServiceConfigs           `mapstructure:"service" json:"service"`
+	TerraformProviders *TerraformProviderConfigs `mapstructure:"terraform_provider" json:"terraform_provider"`
+	BufferPeriod       *BufferPeriodConfig       `mapstructure:"buffer_period" json:"buffer_period"`
+	TLS                *CTSTLSConfig             `mapstructure:"tls" json:"tls"`
+	PlanArtifacts      *PlanArtifactsConfig      `mapstructure:"plan_artifacts" json:"plan_artifacts"`
+	ReadOnlyAPI        *ReadOnlyAPIConfig        `mapstructure:"read_only_api" json:"read_only_api"`
+	TFCRunTask         *TFCRunTaskConfig         `mapstructure:"tfc_run_task" json:"tfc_run_task"`
+	Proxy              *ProxyConfig              `mapstructure:"proxy" json:"proxy"`
+	Alertmanager       *AlertmanagerConfig       `mapstructure:"alertmanager" json:"alertmanager"`
+	Sharding           *ShardingConfig           `mapstructure:"sharding" json:"sharding"`
 }
 
 // BuildConfig builds a new Config object from the default configuration and
@@ -106,6 +145,12 @@ func DefaultConfig() *Config {
 		TerraformProviders: DefaultTerraformProviderConfigs(),
 		BufferPeriod:       DefaultBufferPeriodConfig(),
 		TLS:                DefaultCTSTLSConfig(),
+		PlanArtifacts:      DefaultPlanArtifactsConfig(),
+		ReadOnlyAPI:        DefaultReadOnlyAPIConfig(),
+		TFCRunTask:         DefaultTFCRunTaskConfig(),
+		Proxy:              DefaultProxyConfig(),
+		Alertmanager:       DefaultAlertmanagerConfig(),
+		Sharding:           DefaultShardingConfig(),
 	}
 }
 
@@ -116,7 +161,7 @@ func (c *Config) Copy() *Config {
 		return nil
 	}
 
-	return &Config{
+	o := &Config{
 		LogLevel:           StringCopy(c.LogLevel),
 		Syslog:             c.Syslog.Copy(),
 		Port:               IntCopy(c.Port),
@@ -130,8 +175,24 @@ func (c *Config) Copy() *Config {
 		TerraformProviders: c.TerraformProviders.Copy(),
 		BufferPeriod:       c.BufferPeriod.Copy(),
 		TLS:                c.TLS.Copy(),
+		PlanArtifacts:      c.PlanArtifacts.Copy(),
+		ReadOnlyAPI:        c.ReadOnlyAPI.Copy(),
+		TFCRunTask:         c.TFCRunTask.Copy(),
+		Proxy:              c.Proxy.Copy(),
+		Alertmanager:       c.Alertmanager.Copy(),
+		Sharding:           c.Sharding.Copy(),
 		ClientType:         StringCopy(c.ClientType),
+		IdempotencyKeyTTL:  TimeDurationCopy(c.IdempotencyKeyTTL),
+
+		OnceModeFailurePolicy: StringCopy(c.OnceModeFailurePolicy),
+	}
+
+	if c.Addresses != nil {
+		o.Addresses = make([]string, 0, len(c.Addresses))
+		o.Addresses = append(o.Addresses, c.Addresses...)
 	}
+
+	return o
 }
 
 // Merge combines all values in this configuration with the values in the other
@@ -160,6 +221,8 @@ func (c *Config) Merge(o *Config) *Config {
 		r.Port = IntCopy(o.Port)
 	}
 
+	r.Addresses = mergeSlices(r.Addresses, o.Addresses)
+
 	if o.WorkingDir != nil {
 		r.WorkingDir = StringCopy(o.WorkingDir)
 	}
@@ -168,6 +231,14 @@ func (c *Config) Merge(o *Config) *Config {
 		r.ID = StringCopy(o.ID)
 	}
 
+	if o.IdempotencyKeyTTL != nil {
+		r.IdempotencyKeyTTL = TimeDurationCopy(o.IdempotencyKeyTTL)
+	}
+
+	if o.OnceModeFailurePolicy != nil {
+		r.OnceModeFailurePolicy = StringCopy(o.OnceModeFailurePolicy)
+	}
+
 	if o.Syslog != nil {
 		r.Syslog = r.Syslog.Merge(o.Syslog)
 	}
@@ -204,6 +275,30 @@ func (c *Config) Merge(o *Config) *Config {
 		r.TLS = r.TLS.Merge(o.TLS)
 	}
 
+	if o.PlanArtifacts != nil {
+		r.PlanArtifacts = r.PlanArtifacts.Merge(o.PlanArtifacts)
+	}
+
+	if o.ReadOnlyAPI != nil {
+		r.ReadOnlyAPI = r.ReadOnlyAPI.Merge(o.ReadOnlyAPI)
+	}
+
+	if o.TFCRunTask != nil {
+		r.TFCRunTask = r.TFCRunTask.Merge(o.TFCRunTask)
+	}
+
+	if o.Proxy != nil {
+		r.Proxy = r.Proxy.Merge(o.Proxy)
+	}
+
+	if o.Alertmanager != nil {
+		r.Alertmanager = r.Alertmanager.Merge(o.Alertmanager)
+	}
+
+	if o.Sharding != nil {
+		r.Sharding = r.Sharding.Merge(o.Sharding)
+	}
+
 	return r
 }
 
@@ -217,6 +312,10 @@ func (c *Config) Finalize() error {
 		c.Port = Int(DefaultPort)
 	}
 
+	if c.Addresses == nil {
+		c.Addresses = []string{}
+	}
+
 	if c.ClientType == nil {
 		c.ClientType = String("")
 	}
@@ -229,6 +328,14 @@ func (c *Config) Finalize() error {
 		c.ID = &id
 	}
 
+	if c.IdempotencyKeyTTL == nil {
+		c.IdempotencyKeyTTL = TimeDuration(DefaultIdempotencyKeyTTL)
+	}
+
+	if c.OnceModeFailurePolicy == nil {
+		c.OnceModeFailurePolicy = String(OnceModeFailurePolicyFailFast)
+	}
+
 	if c.Syslog == nil {
 		c.Syslog = DefaultSyslogConfig()
 	}
@@ -291,6 +398,36 @@ func (c *Config) Finalize() error {
 	}
 	c.TLS.Finalize()
 
+	if c.PlanArtifacts == nil {
+		c.PlanArtifacts = DefaultPlanArtifactsConfig()
+	}
+	c.PlanArtifacts.Finalize()
+
+	if c.ReadOnlyAPI == nil {
+		c.ReadOnlyAPI = DefaultReadOnlyAPIConfig()
+	}
+	c.ReadOnlyAPI.Finalize()
+
+	if c.TFCRunTask == nil {
+		c.TFCRunTask = DefaultTFCRunTaskConfig()
+	}
+	c.TFCRunTask.Finalize()
+
+	if c.Proxy == nil {
+		c.Proxy = DefaultProxyConfig()
+	}
+	c.Proxy.Finalize()
+
+	if c.Alertmanager == nil {
+		c.Alertmanager = DefaultAlertmanagerConfig()
+	}
+	c.Alertmanager.Finalize()
+
+	if c.Sharding == nil {
+		c.Sharding = DefaultShardingConfig()
+	}
+	c.Sharding.Finalize(StringVal(c.Consul.KVPath))
+
 	return nil
 }
 
@@ -300,6 +437,24 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("missing required configuration")
 	}
 
+	if err := validateAPIAddresses(c.Addresses); err != nil {
+		return err
+	}
+
+	if TimeDurationVal(c.IdempotencyKeyTTL) < 0 {
+		return fmt.Errorf("idempotency_key_ttl cannot be negative")
+	}
+
+	switch StringVal(c.OnceModeFailurePolicy) {
+	case "", OnceModeFailurePolicyFailFast, OnceModeFailurePolicyContinue,
+		OnceModeFailurePolicyBestEffort:
+	default:
+		return fmt.Errorf("once_mode_failure_policy %q is not supported, "+
+			"expected one of %q, %q, or %q", StringVal(c.OnceModeFailurePolicy),
+			OnceModeFailurePolicyFailFast, OnceModeFailurePolicyContinue,
+			OnceModeFailurePolicyBestEffort)
+	}
+
 	if err := c.Driver.Validate(); err != nil {
 		return err
 	}
@@ -336,6 +491,61 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	if err := c.PlanArtifacts.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.ReadOnlyAPI.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.TFCRunTask.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Proxy.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Alertmanager.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.validateAlertmanagerSilences(); err != nil {
+		return err
+	}
+
+	if err := c.Sharding.Validate(); err != nil {
+		return err
+	}
+
+	if c.ReadOnlyAPI.Enabled() && !BoolVal(c.TLS.VerifyIncoming) {
+		logging.Global().Named(logSystemName).Warn("read_only_api is " +
+			"configured but mutual TLS is not enabled for the CTS API. " +
+			"Configure tls.verify_incoming to restrict access to the " +
+			"endpoints that mutate tasks")
+	}
+
+	return nil
+}
+
+// validateAPIAddresses checks that addresses for the CTS API are either
+// host:port addresses or Unix domain sockets with an absolute path
+func validateAPIAddresses(addresses []string) error {
+	for _, addr := range addresses {
+		if strings.HasPrefix(addr, UnixSocketAddressPrefix) {
+			path := strings.TrimPrefix(addr, UnixSocketAddressPrefix)
+			if !filepath.IsAbs(path) {
+				return fmt.Errorf("invalid address %q: unix socket path must "+
+					"be an absolute path", addr)
+			}
+			continue
+		}
+
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return fmt.Errorf("invalid address %q: %s", addr, err)
+		}
+	}
 	return nil
 }
 
@@ -380,8 +590,11 @@ func (c *Config) GoString() string {
 	return fmt.Sprintf("&Config{"+
 		"LogLevel:%s, "+
 		"Port:%d, "+
+		"Addresses:%s, "+
 		"WorkingDir:%s, "+
 		"ID:%s, "+
+		"IdempotencyKeyTTL:%s, "+
+		"OnceModeFailurePolicy:%s, "+
 		"Syslog:%s, "+
 		"Consul:%s, "+
 		"Vault:%s, "+
@@ -390,12 +603,21 @@ func (c *Config) GoString() string {
 		"Services (deprecated):%s, "+
 		"TerraformProviders:%s, "+
 		"BufferPeriod:%s,"+
-		"TLS:%s"+
+		"TLS:%s, "+
+		"PlanArtifacts:%s, "+
+		"ReadOnlyAPI:%s, "+
+		"TFCRunTask:%s, "+
+		"Proxy:%s, "+
+		"Alertmanager:%s, "+
+		"Sharding:%s"+
 		"}",
 		StringVal(c.LogLevel),
 		IntVal(c.Port),
+		c.Addresses,
 		StringVal(c.WorkingDir),
 		StringVal(c.ID),
+		TimeDurationVal(c.IdempotencyKeyTTL),
+		StringVal(c.OnceModeFailurePolicy),
 		c.Syslog.GoString(),
 		c.Consul.GoString(),
 		c.Vault.GoString(),
@@ -405,9 +627,31 @@ func (c *Config) GoString() string {
 		c.TerraformProviders.GoString(),
 		c.BufferPeriod.GoString(),
 		c.TLS.GoString(),
+		c.PlanArtifacts.GoString(),
+		c.ReadOnlyAPI.GoString(),
+		c.TFCRunTask.GoString(),
+		c.Proxy.GoString(),
+		c.Alertmanager.GoString(),
+		c.Sharding.GoString(),
 	)
 }
 
+// validateAlertmanagerSilences checks that the Alertmanager integration is
+// enabled when a task creates silences
+func (c *Config) validateAlertmanagerSilences() error {
+	if c.Alertmanager.IsEnabled() || c.Tasks == nil {
+		return nil
+	}
+
+	for _, t := range *c.Tasks {
+		if t.AlertmanagerSilence != nil && BoolVal(t.AlertmanagerSilence.Enabled) {
+			return fmt.Errorf("task %q configures alertmanager_silence but the "+
+				"alertmanager block is not configured", StringVal(t.Name))
+		}
+	}
+	return nil
+}
+
 func (c *Config) validateDynamicConfigs() error {
 	// If dynamic provider configs contain Vault dependency, verify that Vault is
 	// configured.
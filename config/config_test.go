@@ -26,10 +26,14 @@ var (
 	}
 
 	longConfig = Config{
-		LogLevel:   String("ERR"),
-		Port:       Int(8502),
-		WorkingDir: String("working"),
-		ID:         String("cts-123"),
+		LogLevel:          String("ERR"),
+		Port:              Int(8502),
+		Addresses:         []string{"127.0.0.1:8558", "unix:///var/run/cts.sock"},
+		WorkingDir:        String("working"),
+		ID:                String("cts-123"),
+		IdempotencyKeyTTL: TimeDuration(time.Hour),
+
+		OnceModeFailurePolicy: String(OnceModeFailurePolicyContinue),
 		Syslog: &SyslogConfig{
 			Enabled: Bool(true),
 			Name:    String("syslog"),
@@ -77,6 +81,24 @@ var (
 			VerifyIncoming: Bool(true),
 			CACert:         String("../testutils/certs/consul_cert.pem"),
 		},
+		PlanArtifacts: &PlanArtifactsConfig{
+			Path: String("plans"),
+		},
+		TFCRunTask: &TFCRunTaskConfig{
+			HMACKey: String("hmac-key"),
+		},
+		Proxy: &ProxyConfig{
+			HTTP:    String("http://proxy.example.com:3128"),
+			HTTPS:   String("http://proxy.example.com:3128"),
+			NoProxy: []string{"consul-example.com", "10.0.0.0/8"},
+		},
+		ReadOnlyAPI: &ReadOnlyAPIConfig{
+			Addresses: []string{"127.0.0.1:8559"},
+			TLS: &CTSTLSConfig{
+				Cert: String("../testutils/certs/consul_cert.pem"),
+				Key:  String("../testutils/certs/consul_key.pem"),
+			},
+		},
 		Driver: &DriverConfig{
 			Terraform: &TerraformConfig{
 				Log:  Bool(true),
@@ -113,7 +135,10 @@ var (
 				Name:               String("task"),
 				DeprecatedServices: []string{"serviceA", "serviceB", "serviceC"},
 				Providers:          []string{"X"},
-				Module:             String("Y"),
+				ProviderOverrides: map[string]map[string]interface{}{
+					"X": {"hostname": "fw-2"},
+				},
+				Module: String("Y"),
 				Condition: &CatalogServicesConditionConfig{
 					CatalogServicesMonitorConfig{
 						Regexp:           String(".*"),
@@ -214,6 +239,80 @@ func TestDecodeConfig(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "terraform_provider")
 	})
+
+	t.Run("task expires_at", func(t *testing.T) {
+		content := []byte(`
+task {
+  name          = "migration"
+  module        = "path"
+  expires_at    = "2026-01-02T03:04:05Z"
+  expire_action = "delete"
+}`)
+		c, err := decodeConfig(content, "config.hcl")
+		require.NoError(t, err)
+		require.Equal(t, 1, c.Tasks.Len())
+		task := (*c.Tasks)[0]
+		assert.Equal(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			TimeVal(task.ExpiresAt))
+		assert.Equal(t, TaskExpireActionDelete, StringVal(task.ExpireAction))
+	})
+
+	t.Run("alertmanager silence", func(t *testing.T) {
+		content := []byte(`
+alertmanager {
+  address = "http://localhost:9093"
+  token   = "secret"
+}
+
+task {
+  name   = "lb"
+  module = "path"
+  alertmanager_silence {
+    duration = "10m"
+    matcher {
+      name  = "service"
+      value = "api"
+    }
+    matcher {
+      name  = "alertname"
+      value = "Instance.*"
+      regex = true
+    }
+  }
+}`)
+		c, err := decodeConfig(content, "config.hcl")
+		require.NoError(t, err)
+		assert.Equal(t, "http://localhost:9093", StringVal(c.Alertmanager.Address))
+		require.Equal(t, 1, c.Tasks.Len())
+		assert.Equal(t, &AlertmanagerSilenceConfig{
+			Duration: TimeDuration(10 * time.Minute),
+			Matchers: &AlertmanagerMatcherConfigs{
+				{Name: String("service"), Value: String("api")},
+				{Name: String("alertname"), Value: String("Instance.*"), Regex: Bool(true)},
+			},
+		}, (*c.Tasks)[0].AlertmanagerSilence)
+	})
+
+	t.Run("task computed_input", func(t *testing.T) {
+		content := []byte(`
+task {
+  name   = "lb"
+  module = "path"
+  computed_input "lb_members" {
+    expr = "[for s in services : s.address if s.status == \"passing\"]"
+  }
+  computed_input "lb_count" {
+    expr = "length(services)"
+  }
+}`)
+		c, err := decodeConfig(content, "config.hcl")
+		require.NoError(t, err)
+		require.Equal(t, 1, c.Tasks.Len())
+		assert.Equal(t, ComputedInputConfigs{
+			"lb_members": {Expr: String(`[for s in services : s.address if s.status == "passing"]`)},
+			"lb_count":   {Expr: String("length(services)")},
+		}, (*c.Tasks)[0].ComputedInputs)
+	})
 }
 
 func TestFromPath(t *testing.T) {
@@ -361,18 +460,35 @@ func TestConfig_Finalize(t *testing.T) {
 	expected.Syslog.Facility = String("LOCAL0")
 	expected.BufferPeriod.Enabled = Bool(true)
 	expected.Consul.KVNamespace = String("")
+	expected.Consul.QueryRateLimit = Int(0)
+	expected.Consul.DNSRefreshInterval = TimeDuration(0)
+	expected.Consul.Addresses = []string{}
 	expected.Consul.TLS.Cert = String("")
 	expected.Consul.Transport.MaxIdleConns = Int(0)
 	expected.Vault = DefaultVaultConfig()
 	expected.Vault.Finalize()
+	expected.Alertmanager = DefaultAlertmanagerConfig()
+	expected.Alertmanager.Finalize()
+	expected.Sharding = DefaultShardingConfig()
+	expected.Sharding.Finalize("kv_path")
 	expected.TLS.Cert = String("../testutils/certs/consul_cert.pem")
 	expected.TLS.Key = String("../testutils/certs/consul_key.pem")
 	expected.TLS.VerifyIncoming = Bool(true)
 	expected.TLS.CACert = String("../testutils/certs/consul_cert.pem")
 	expected.TLS.Finalize()
+	expected.PlanArtifacts.Enabled = Bool(true)
+	expected.ReadOnlyAPI.TLS.Finalize()
+	expected.TFCRunTask.Enabled = Bool(true)
 	expected.Driver.consul = expected.Consul
 	expected.Driver.Terraform.Version = String("")
 	expected.Driver.Terraform.PersistLog = Bool(false)
+	expected.Driver.Terraform.WorkspacePrefix = String("")
+	expected.Driver.Terraform.WorkspaceName = String("")
+	expected.Driver.Terraform.Sandbox = defaultTerraformSandboxConfig()
+	expected.Driver.Terraform.NetworkMirror = String("")
+	expected.Driver.Terraform.BinarySource = String("")
+	expected.Driver.Terraform.BinaryChecksum = String("")
+	expected.Driver.Terraform.PluginCache = defaultTerraformPluginCacheConfig("path")
 	backend := expected.Driver.Terraform.Backend["consul"].(map[string]interface{})
 	backend["scheme"] = "https"
 	backend["ca_file"] = "ca_cert"
@@ -383,8 +499,42 @@ func TestConfig_Finalize(t *testing.T) {
 	(*expected.Tasks)[0].VarFiles = []string{}
 	(*expected.Tasks)[0].Version = String("")
 	(*expected.Tasks)[0].BufferPeriod = nil
+	(*expected.Tasks)[0].Cooldown = TimeDuration(0)
+	(*expected.Tasks)[0].Priority = Int(0)
+	(*expected.Tasks)[0].InputSnapshots = Int(0)
+	(*expected.Tasks)[0].CircuitBreaker = defaultCircuitBreakerConfig()
+	(*expected.Tasks)[0].MaintenanceWindow = defaultMaintenanceWindowConfig()
+	(*expected.Tasks)[0].DependsOn = []string{}
+	(*expected.Tasks)[0].SkipOnDependencyFailure = Bool(false)
+	(*expected.Tasks)[0].ExpireAction = String(TaskExpireActionDisable)
+	(*expected.Tasks)[0].TriggerOn = String(TaskTriggerOnConditionOnly)
+	(*expected.Tasks)[0].RenderOnly = Bool(false)
+	(*expected.Tasks)[0].ServicesChanged = Bool(false)
+	(*expected.Tasks)[0].TargetedApply = Bool(false)
+	(*expected.Tasks)[0].ProviderForeachDatacenter = Bool(false)
+	(*expected.Tasks)[0].ConsulToken = String("")
+	(*expected.Tasks)[0].ConsulTokenFile = String("")
+	(*expected.Tasks)[0].Backend = map[string]interface{}{}
+	(*expected.Tasks)[0].Environments = []string{}
+	(*expected.Tasks)[0].EnvironmentConfigs = &TaskEnvironmentConfigs{}
+	(*expected.Tasks)[0].ExtraTemplates = []string{}
+	(*expected.Tasks)[0].Postconditions = &PostconditionConfigs{}
+	(*expected.Tasks)[0].ComputedInputs = ComputedInputConfigs{}
+	(*expected.Tasks)[0].PublishOutputs = defaultPublishOutputsConfig()
+	(*expected.Tasks)[0].AlertmanagerSilence = defaultAlertmanagerSilenceConfig()
+	(*expected.Tasks)[0].Handlers = &HandlerConfigs{}
+	(*expected.Tasks)[0].Gates = &GateConfigs{}
+	(*expected.Tasks)[0].ApplyTargets = map[string][]string{}
 	(*expected.Tasks)[0].Variables = map[string]string{}
+	(*expected.Tasks)[0].SensitiveVariables = []string{}
+	(*expected.Tasks)[0].VariablesFromConsulKV = []string{}
+	(*expected.Tasks)[0].Labels = map[string]string{}
+	(*(*expected.Tasks)[0].ModuleInputs)[0].(*ConsulKVModuleInputConfig).Paths = []string{}
 	(*expected.Tasks)[0].WorkingDir = nil
+	(*expected.Tasks)[0].Condition.(*CatalogServicesConditionConfig).TriggerOnTagChanges = Bool(false)
+	(*expected.Tasks)[0].Condition.(*CatalogServicesConditionConfig).MinInstances = Int(0)
+	(*expected.Tasks)[0].Condition.(*CatalogServicesConditionConfig).MaxInstances = Int(0)
+	(*expected.Tasks)[0].Condition.(*CatalogServicesConditionConfig).Kind = String("")
 	(*expected.DeprecatedServices)[0].ID = String("serviceA")
 	(*expected.DeprecatedServices)[0].Namespace = String("")
 	(*expected.DeprecatedServices)[0].Datacenter = String("")
@@ -447,6 +597,18 @@ func TestConfig_Validate(t *testing.T) {
 	validEmptyTasks := longConfig.Copy()
 	*validEmptyTasks.Tasks = TaskConfigs{}
 
+	invalidAddress := longConfig.Copy()
+	invalidAddress.Addresses = []string{"127.0.0.1"}
+
+	relativeSocket := longConfig.Copy()
+	relativeSocket.Addresses = []string{"unix://cts.sock"}
+
+	negativeIdempotencyKeyTTL := longConfig.Copy()
+	negativeIdempotencyKeyTTL.IdempotencyKeyTTL = TimeDuration(-time.Minute)
+
+	invalidOnceModeFailurePolicy := longConfig.Copy()
+	invalidOnceModeFailurePolicy.OnceModeFailurePolicy = String("retry")
+
 	cases := []struct {
 		name    string
 		i       *Config
@@ -484,6 +646,22 @@ func TestConfig_Validate(t *testing.T) {
 			"autocommitting provider reuse error",
 			autoCommit.Copy(),
 			false,
+		}, {
+			"address missing port",
+			invalidAddress.Copy(),
+			false,
+		}, {
+			"unix socket relative path",
+			relativeSocket.Copy(),
+			false,
+		}, {
+			"negative idempotency key ttl",
+			negativeIdempotencyKeyTTL.Copy(),
+			false,
+		}, {
+			"invalid once mode failure policy",
+			invalidOnceModeFailurePolicy.Copy(),
+			false,
 		},
 	}
 
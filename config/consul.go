@@ -3,7 +3,11 @@
 
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 const (
 	// DefaultConsulAddress is the default address to connect with Consul
@@ -17,31 +21,55 @@ const (
 // ConsulConfig is the configuration for Consul client.
 type ConsulConfig struct {
 	// Address is the address of the Consul server. It may be an IP or FQDN.
-	Address *string `mapstructure:"address"`
+	Address *string `mapstructure:"address" json:"address"`
+
+	// Addresses is a list of addresses of Consul agents or servers to fail
+	// over to when the Consul agent at the current address is unavailable.
+	// Address defaults to the first address of the list.
+	//
+	// Only the blocking queries that monitor Consul fail over at runtime.
+	// Service registration and the writes of CTS to Consul KV, such as
+	// publish_outputs, use the first address that is reachable when CTS
+	// starts, and the Terraform consul backend defaults to Address. CTS needs
+	// to be restarted for them to use a different address.
+	Addresses []string `mapstructure:"addresses" json:"addresses"`
 
 	// Auth is the HTTP basic authentication for communicating with Consul.
-	Auth *AuthConfig `mapstructure:"auth"`
+	Auth *AuthConfig `mapstructure:"auth" json:"auth"`
+
+	// DNSRefreshInterval is the interval to re-resolve the host names of the
+	// Consul addresses. When the resolved IPs of an address change, e.g. when
+	// a load balancer in front of Consul fails over, the connections to the
+	// previous IPs are closed and queries reconnect to the new IPs. Defaults
+	// to 0, which only resolves the addresses when connecting.
+	DNSRefreshInterval *time.Duration `mapstructure:"dns_refresh_interval" json:"dns_refresh_interval"`
 
 	// KVNamespace is the optional namespace for CTS to use for Consul KV
 	// queries and operations.
-	KVNamespace *string `mapstructure:"kv_namespace"`
+	KVNamespace *string `mapstructure:"kv_namespace" json:"kv_namespace"`
 
 	// KVPath is the directory in the Consul KV store to use for storing run time
 	// data
-	KVPath *string `mapstructure:"kv_path"`
+	KVPath *string `mapstructure:"kv_path" json:"kv_path"`
+
+	// QueryRateLimit is the maximum number of Consul blocking queries per
+	// second, across all the dependencies monitored by CTS. Limits the rate
+	// that blocking queries are restarted to protect Consul servers when CTS
+	// monitors many dependencies. Defaults to 0, which is unlimited.
+	QueryRateLimit *int `mapstructure:"query_rate_limit" json:"query_rate_limit"`
 
 	// TLS indicates we should use a secure connection while talking to
 	// Consul. This requires Consul to be configured to serve HTTPS.
-	TLS *TLSConfig `mapstructure:"tls"`
+	TLS *TLSConfig `mapstructure:"tls" json:"tls"`
 
 	// Token is the token to communicate with Consul securely.
-	Token *string `mapstructure:"token"`
+	Token *string `mapstructure:"token" json:"token"`
 
 	// Transport configures the low-level network connection details.
-	Transport *TransportConfig `mapstructure:"transport"`
+	Transport *TransportConfig `mapstructure:"transport" json:"transport"`
 
 	// ServiceRegistration configures registering CTS as a service with Consul
-	ServiceRegistration *ServiceRegistrationConfig `mapstructure:"service_registration"`
+	ServiceRegistration *ServiceRegistrationConfig `mapstructure:"service_registration" json:"service_registration"`
 }
 
 // DefaultConsulConfig returns the default configuration struct
@@ -65,14 +93,23 @@ func (c *ConsulConfig) Copy() *ConsulConfig {
 
 	o.Address = StringCopy(c.Address)
 
+	if c.Addresses != nil {
+		o.Addresses = make([]string, 0, len(c.Addresses))
+		o.Addresses = append(o.Addresses, c.Addresses...)
+	}
+
 	if c.Auth != nil {
 		o.Auth = c.Auth.Copy()
 	}
 
+	o.DNSRefreshInterval = TimeDurationCopy(c.DNSRefreshInterval)
+
 	o.KVNamespace = StringCopy(c.KVNamespace)
 
 	o.KVPath = StringCopy(c.KVPath)
 
+	o.QueryRateLimit = IntCopy(c.QueryRateLimit)
+
 	if c.TLS != nil {
 		o.TLS = c.TLS.Copy()
 	}
@@ -112,10 +149,16 @@ func (c *ConsulConfig) Merge(o *ConsulConfig) *ConsulConfig {
 		r.Address = StringCopy(o.Address)
 	}
 
+	r.Addresses = mergeSlices(r.Addresses, o.Addresses)
+
 	if o.Auth != nil {
 		r.Auth = r.Auth.Merge(o.Auth)
 	}
 
+	if o.DNSRefreshInterval != nil {
+		r.DNSRefreshInterval = TimeDurationCopy(o.DNSRefreshInterval)
+	}
+
 	if o.KVNamespace != nil {
 		r.KVNamespace = StringCopy(o.KVNamespace)
 	}
@@ -124,6 +167,10 @@ func (c *ConsulConfig) Merge(o *ConsulConfig) *ConsulConfig {
 		r.KVPath = StringCopy(o.KVPath)
 	}
 
+	if o.QueryRateLimit != nil {
+		r.QueryRateLimit = IntCopy(o.QueryRateLimit)
+	}
+
 	if o.TLS != nil {
 		r.TLS = r.TLS.Merge(o.TLS)
 	}
@@ -149,6 +196,14 @@ func (c *ConsulConfig) Finalize() {
 		return
 	}
 
+	if c.Addresses == nil {
+		c.Addresses = []string{}
+	}
+
+	if c.Address == nil && len(c.Addresses) > 0 {
+		c.Address = String(c.Addresses[0])
+	}
+
 	if c.Address == nil {
 		c.Address = stringFromEnv([]string{
 			"CONSUL_HTTP_ADDR",
@@ -160,6 +215,10 @@ func (c *ConsulConfig) Finalize() {
 	}
 	c.Auth.Finalize()
 
+	if c.DNSRefreshInterval == nil {
+		c.DNSRefreshInterval = TimeDuration(0)
+	}
+
 	if c.KVNamespace == nil {
 		c.KVNamespace = String("")
 	}
@@ -168,6 +227,10 @@ func (c *ConsulConfig) Finalize() {
 		c.KVPath = String(DefaultConsulKVPath)
 	}
 
+	if c.QueryRateLimit == nil {
+		c.QueryRateLimit = Int(0)
+	}
+
 	if c.TLS == nil {
 		c.TLS = DefaultTLSConfig()
 	}
@@ -199,6 +262,22 @@ func (c *ConsulConfig) Validate() error {
 		return nil
 	}
 
+	for _, addr := range c.Addresses {
+		if strings.TrimSpace(addr) == "" {
+			return fmt.Errorf("consul addresses cannot contain an empty address")
+		}
+	}
+
+	if TimeDurationVal(c.DNSRefreshInterval) < 0 {
+		return fmt.Errorf("consul dns_refresh_interval cannot be negative, got %s",
+			TimeDurationVal(c.DNSRefreshInterval))
+	}
+
+	if IntVal(c.QueryRateLimit) < 0 {
+		return fmt.Errorf("consul query_rate_limit cannot be negative, got %d",
+			IntVal(c.QueryRateLimit))
+	}
+
 	if c.ServiceRegistration != nil {
 		if err := c.ServiceRegistration.Validate(); err != nil {
 			return err
@@ -217,18 +296,24 @@ func (c *ConsulConfig) GoString() string {
 
 	return fmt.Sprintf("&ConsulConfig{"+
 		"Address:%s, "+
+		"Addresses:%s, "+
 		"Auth:%s, "+
+		"DNSRefreshInterval:%s, "+
 		"KVNamespace:%s, "+
 		"KVPath:%s, "+
+		"QueryRateLimit:%d, "+
 		"TLS:%s, "+
 		"Token:%s, "+
 		"Transport:%s, "+
 		"ServiceRegistration:%s"+
 		"}",
 		StringVal(c.Address),
+		c.Addresses,
 		c.Auth.GoString(),
+		TimeDurationVal(c.DNSRefreshInterval),
 		StringVal(c.KVNamespace),
 		StringVal(c.KVPath),
+		IntVal(c.QueryRateLimit),
 		c.TLS.GoString(),
 		sensitiveGoString(c.Token),
 		c.Transport.GoString(),
@@ -236,6 +321,26 @@ func (c *ConsulConfig) GoString() string {
 	)
 }
 
+// ConsulAddresses returns the addresses of Consul in order of preference for
+// failover, starting with Address followed by the other addresses of
+// Addresses. Duplicate addresses are removed.
+func (c *ConsulConfig) ConsulAddresses() []string {
+	if c == nil {
+		return nil
+	}
+
+	addrs := make([]string, 0, len(c.Addresses)+1)
+	seen := make(map[string]bool, len(c.Addresses)+1)
+	for _, addr := range append([]string{StringVal(c.Address)}, c.Addresses...) {
+		if addr == "" || seen[addr] {
+			continue
+		}
+		seen[addr] = true
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
 // Env returns an environment map of supported Consul configuration
 func (c *ConsulConfig) Env() map[string]string {
 	if c == nil {
@@ -37,12 +37,15 @@ func TestConsulConfig_Copy(t *testing.T) {
 		{
 			"same_enabled",
 			&ConsulConfig{
-				Address:     String("1.2.3.4"),
-				Auth:        &AuthConfig{Enabled: Bool(true)},
-				KVPath:      String("consul-terraform-sync/"),
-				KVNamespace: String("org"),
-				TLS:         &TLSConfig{Enabled: Bool(true)},
-				Token:       String("abcd1234"),
+				Address:            String("1.2.3.4"),
+				Addresses:          []string{"1.2.3.4", "1.2.3.5"},
+				Auth:               &AuthConfig{Enabled: Bool(true)},
+				KVPath:             String("consul-terraform-sync/"),
+				DNSRefreshInterval: TimeDuration(30 * time.Second),
+				KVNamespace:        String("org"),
+				QueryRateLimit:     Int(10),
+				TLS:                &TLSConfig{Enabled: Bool(true)},
+				Token:              String("abcd1234"),
 				ServiceRegistration: &ServiceRegistrationConfig{
 					Enabled:     Bool(false),
 					ServiceName: String("test-service"),
@@ -170,6 +173,36 @@ func TestConsulConfig_Merge(t *testing.T) {
 			&ConsulConfig{TLS: &TLSConfig{Enabled: Bool(true)}},
 			&ConsulConfig{TLS: &TLSConfig{Enabled: Bool(true)}},
 		},
+		{
+			"addresses_merges",
+			&ConsulConfig{Addresses: []string{"c1:8500"}},
+			&ConsulConfig{Addresses: []string{"c2:8500"}},
+			&ConsulConfig{Addresses: []string{"c1:8500", "c2:8500"}},
+		},
+		{
+			"dns_refresh_interval_overrides",
+			&ConsulConfig{DNSRefreshInterval: TimeDuration(10 * time.Second)},
+			&ConsulConfig{DNSRefreshInterval: TimeDuration(20 * time.Second)},
+			&ConsulConfig{DNSRefreshInterval: TimeDuration(20 * time.Second)},
+		},
+		{
+			"dns_refresh_interval_empty_one",
+			&ConsulConfig{DNSRefreshInterval: TimeDuration(10 * time.Second)},
+			&ConsulConfig{},
+			&ConsulConfig{DNSRefreshInterval: TimeDuration(10 * time.Second)},
+		},
+		{
+			"query_rate_limit_overrides",
+			&ConsulConfig{QueryRateLimit: Int(10)},
+			&ConsulConfig{QueryRateLimit: Int(20)},
+			&ConsulConfig{QueryRateLimit: Int(20)},
+		},
+		{
+			"query_rate_limit_empty_one",
+			&ConsulConfig{QueryRateLimit: Int(10)},
+			&ConsulConfig{},
+			&ConsulConfig{QueryRateLimit: Int(10)},
+		},
 		{
 			"token_overrides",
 			&ConsulConfig{Token: String("same")},
@@ -264,14 +297,17 @@ func TestConsulConfig_Finalize(t *testing.T) {
 			"empty",
 			&ConsulConfig{},
 			&ConsulConfig{
-				Address: String("localhost:8500"),
+				Address:   String("localhost:8500"),
+				Addresses: []string{},
 				Auth: &AuthConfig{
 					Enabled:  Bool(false),
 					Username: String(""),
 					Password: String(""),
 				},
-				KVNamespace: String(""),
-				KVPath:      String(DefaultConsulKVPath),
+				DNSRefreshInterval: TimeDuration(0),
+				KVNamespace:        String(""),
+				KVPath:             String(DefaultConsulKVPath),
+				QueryRateLimit:     Int(0),
 				TLS: &TLSConfig{
 					CACert:     String(""),
 					CAPath:     String(""),
@@ -311,6 +347,12 @@ func TestConsulConfig_Finalize(t *testing.T) {
 			assert.Equal(t, tc.r, tc.i)
 		})
 	}
+
+	t.Run("addresses", func(t *testing.T) {
+		c := &ConsulConfig{Addresses: []string{"c1:8500", "c2:8500"}}
+		c.Finalize()
+		assert.Equal(t, "c1:8500", *c.Address)
+	})
 }
 
 func TestConsulConfig_Validate(t *testing.T) {
@@ -342,6 +384,27 @@ func TestConsulConfig_Validate(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"empty address in addresses",
+			&ConsulConfig{
+				Addresses: []string{"c1:8500", ""},
+			},
+			true,
+		},
+		{
+			"negative dns refresh interval",
+			&ConsulConfig{
+				DNSRefreshInterval: TimeDuration(-time.Second),
+			},
+			true,
+		},
+		{
+			"negative query rate limit",
+			&ConsulConfig{
+				QueryRateLimit: Int(-1),
+			},
+			true,
+		},
 	}
 
 	for _, tc := range cases {
@@ -355,3 +418,46 @@ func TestConsulConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestConsulConfig_ConsulAddresses(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		c        *ConsulConfig
+		expected []string
+	}{
+		{
+			"nil",
+			nil,
+			nil,
+		},
+		{
+			"address",
+			&ConsulConfig{Address: String("c1:8500")},
+			[]string{"c1:8500"},
+		},
+		{
+			"addresses",
+			&ConsulConfig{
+				Address:   String("c1:8500"),
+				Addresses: []string{"c1:8500", "c2:8500", "c3:8500"},
+			},
+			[]string{"c1:8500", "c2:8500", "c3:8500"},
+		},
+		{
+			"address not in addresses",
+			&ConsulConfig{
+				Address:   String("local:8500"),
+				Addresses: []string{"c1:8500", "c2:8500", "c1:8500"},
+			},
+			[]string{"local:8500", "c1:8500", "c2:8500"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.c.ConsulAddresses())
+		})
+	}
+}
@@ -111,3 +111,26 @@ func TimeDurationCopy(t *time.Duration) *time.Duration {
 
 	return TimeDuration(*t)
 }
+
+// Time returns a pointer to the given time.Time.
+func Time(t time.Time) *time.Time {
+	return &t
+}
+
+// TimeVal returns the value of the time at the pointer, or the zero time if
+// the pointer is nil.
+func TimeVal(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+// TimeCopy returns a copy of the time.Time pointer
+func TimeCopy(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	return Time(*t)
+}
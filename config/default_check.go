@@ -14,8 +14,8 @@ import (
 // also allows for some modification of the health check to account
 // for different setups of CTS.
 type DefaultCheckConfig struct {
-	Enabled *bool   `mapstructure:"enabled"`
-	Address *string `mapstructure:"address"`
+	Enabled *bool   `mapstructure:"enabled" json:"enabled"`
+	Address *string `mapstructure:"address" json:"address"`
 }
 
 // Copy returns a deep copy of this configuration.
@@ -10,7 +10,7 @@ import "fmt"
 type DriverConfig struct {
 	consul *ConsulConfig
 
-	Terraform *TerraformConfig `mapstructure:"terraform"`
+	Terraform *TerraformConfig `mapstructure:"terraform" json:"terraform"`
 }
 
 // DefaultDriverConfig returns the default configuration struct.
@@ -165,6 +165,13 @@ func TestDriverConfig_Finalize(t *testing.T) {
 					Path:              String(wd),
 					Backend:           map[string]interface{}{},
 					RequiredProviders: map[string]interface{}{},
+					WorkspacePrefix:   String(""),
+					WorkspaceName:     String(""),
+					Sandbox:           defaultTerraformSandboxConfig(),
+					NetworkMirror:     String(""),
+					BinarySource:      String(""),
+					BinaryChecksum:    String(""),
+					PluginCache:       defaultTerraformPluginCacheConfig(wd),
 				},
 			},
 		},
@@ -183,6 +190,13 @@ func TestDriverConfig_Finalize(t *testing.T) {
 					Path:              String(wd),
 					Backend:           map[string]interface{}{},
 					RequiredProviders: map[string]interface{}{},
+					WorkspacePrefix:   String(""),
+					WorkspaceName:     String(""),
+					Sandbox:           defaultTerraformSandboxConfig(),
+					NetworkMirror:     String(""),
+					BinarySource:      String(""),
+					BinaryChecksum:    String(""),
+					PluginCache:       defaultTerraformPluginCacheConfig(wd),
 				},
 			},
 		},
@@ -0,0 +1,147 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultFlapSuppressionTransitions is the default number of times a
+	// service instance can register or deregister within the window before
+	// it is considered to be flapping
+	DefaultFlapSuppressionTransitions = 3
+
+	// DefaultFlapSuppressionWindow is the default window of time in which the
+	// transitions of a service instance are counted
+	DefaultFlapSuppressionWindow = 5 * time.Minute
+)
+
+// FlapSuppressionConfig configures the suppression of triggers caused by
+// flapping service instances. A service instance that registers or
+// deregisters more than the transitions number of times within the window is
+// flapping, and its registrations and deregistrations do not trigger the
+// task until it stabilizes. The instance is still included in the rendered
+// variables of the next task execution.
+type FlapSuppressionConfig struct {
+	// Enabled determines if flap suppression is enabled. Disabled by
+	// default, and enabled if any other option is configured.
+	Enabled *bool `mapstructure:"enabled" json:"enabled"`
+
+	// Transitions is the number of registrations and deregistrations of an
+	// instance allowed within the window before the instance is flapping
+	Transitions *int `mapstructure:"transitions" json:"transitions"`
+
+	// Window is the window of time in which transitions are counted
+	Window *time.Duration `mapstructure:"window" json:"window"`
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *FlapSuppressionConfig) Copy() *FlapSuppressionConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o FlapSuppressionConfig
+	o.Enabled = BoolCopy(c.Enabled)
+	o.Transitions = IntCopy(c.Transitions)
+	o.Window = TimeDurationCopy(c.Window)
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+// Maps and slices are merged, most other values are overwritten. Complex
+// structs define their own merge functionality.
+func (c *FlapSuppressionConfig) Merge(o *FlapSuppressionConfig) *FlapSuppressionConfig {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+
+	if o.Enabled != nil {
+		r.Enabled = BoolCopy(o.Enabled)
+	}
+
+	if o.Transitions != nil {
+		r.Transitions = IntCopy(o.Transitions)
+	}
+
+	if o.Window != nil {
+		r.Window = TimeDurationCopy(o.Window)
+	}
+
+	return r
+}
+
+// Finalize ensures that the receiver contains no nil pointers. For nil pointers,
+// Finalize sets default values where necessary
+func (c *FlapSuppressionConfig) Finalize() {
+	if c == nil {
+		return
+	}
+
+	if c.Enabled == nil {
+		// some options configured, assume user intention is enabled
+		c.Enabled = Bool(c.Transitions != nil || c.Window != nil)
+	}
+
+	if c.Transitions == nil {
+		c.Transitions = Int(DefaultFlapSuppressionTransitions)
+	}
+
+	if c.Window == nil {
+		c.Window = TimeDuration(DefaultFlapSuppressionWindow)
+	}
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *FlapSuppressionConfig) Validate() error {
+	if c == nil {
+		// config is not required, return early
+		return nil
+	}
+
+	if !BoolVal(c.Enabled) {
+		return nil
+	}
+
+	if IntVal(c.Transitions) < 1 {
+		return fmt.Errorf("flap_suppression: transitions must be at least 1, "+
+			"got %d", IntVal(c.Transitions))
+	}
+
+	if TimeDurationVal(c.Window) <= 0 {
+		return fmt.Errorf("flap_suppression: window must be greater than 0")
+	}
+
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *FlapSuppressionConfig) GoString() string {
+	if c == nil {
+		return "(*FlapSuppressionConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&FlapSuppressionConfig{"+
+		"Enabled:%v, "+
+		"Transitions:%d, "+
+		"Window:%s"+
+		"}",
+		BoolVal(c.Enabled),
+		IntVal(c.Transitions),
+		TimeDurationVal(c.Window),
+	)
+}
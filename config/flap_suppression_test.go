@@ -0,0 +1,230 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlapSuppressionConfig_Copy(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *FlapSuppressionConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&FlapSuppressionConfig{},
+		},
+		{
+			"fully configured",
+			&FlapSuppressionConfig{
+				Enabled:     Bool(true),
+				Transitions: Int(4),
+				Window:      TimeDuration(time.Minute),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Copy()
+			assert.Equal(t, tc.a, r)
+		})
+	}
+}
+
+func TestFlapSuppressionConfig_Merge(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *FlapSuppressionConfig
+		b    *FlapSuppressionConfig
+		r    *FlapSuppressionConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&FlapSuppressionConfig{},
+			&FlapSuppressionConfig{},
+		},
+		{
+			"nil_b",
+			&FlapSuppressionConfig{},
+			nil,
+			&FlapSuppressionConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"overrides",
+			&FlapSuppressionConfig{
+				Enabled:     Bool(false),
+				Transitions: Int(2),
+				Window:      TimeDuration(time.Minute),
+			},
+			&FlapSuppressionConfig{
+				Enabled:     Bool(true),
+				Transitions: Int(4),
+				Window:      TimeDuration(2 * time.Minute),
+			},
+			&FlapSuppressionConfig{
+				Enabled:     Bool(true),
+				Transitions: Int(4),
+				Window:      TimeDuration(2 * time.Minute),
+			},
+		},
+		{
+			"empty_one",
+			&FlapSuppressionConfig{
+				Transitions: Int(2),
+			},
+			&FlapSuppressionConfig{
+				Window: TimeDuration(time.Minute),
+			},
+			&FlapSuppressionConfig{
+				Transitions: Int(2),
+				Window:      TimeDuration(time.Minute),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			assert.Equal(t, tc.r, r)
+		})
+	}
+}
+
+func TestFlapSuppressionConfig_Finalize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		i    *FlapSuppressionConfig
+		r    *FlapSuppressionConfig
+	}{
+		{
+			"nil",
+			nil,
+			nil,
+		},
+		{
+			"empty",
+			&FlapSuppressionConfig{},
+			&FlapSuppressionConfig{
+				Enabled:     Bool(false),
+				Transitions: Int(DefaultFlapSuppressionTransitions),
+				Window:      TimeDuration(DefaultFlapSuppressionWindow),
+			},
+		},
+		{
+			"only window",
+			&FlapSuppressionConfig{
+				Window: TimeDuration(time.Minute),
+			},
+			&FlapSuppressionConfig{
+				Enabled:     Bool(true),
+				Transitions: Int(3),
+				Window:      TimeDuration(time.Minute),
+			},
+		},
+		{
+			"disabled",
+			&FlapSuppressionConfig{
+				Enabled:     Bool(false),
+				Transitions: Int(2),
+			},
+			&FlapSuppressionConfig{
+				Enabled:     Bool(false),
+				Transitions: Int(2),
+				Window:      TimeDuration(5 * time.Minute),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			tc.i.Finalize()
+			assert.Equal(t, tc.r, tc.i)
+		})
+	}
+}
+
+func TestFlapSuppressionConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		i       *FlapSuppressionConfig
+		isValid bool
+	}{
+		{
+			"nil",
+			nil,
+			true,
+		},
+		{
+			"disabled",
+			&FlapSuppressionConfig{
+				Enabled:     Bool(false),
+				Transitions: Int(0),
+			},
+			true,
+		},
+		{
+			"valid",
+			&FlapSuppressionConfig{
+				Enabled:     Bool(true),
+				Transitions: Int(3),
+				Window:      TimeDuration(time.Minute),
+			},
+			true,
+		},
+		{
+			"transitions less than 1",
+			&FlapSuppressionConfig{
+				Enabled:     Bool(true),
+				Transitions: Int(0),
+				Window:      TimeDuration(time.Minute),
+			},
+			false,
+		},
+		{
+			"zero window",
+			&FlapSuppressionConfig{
+				Enabled:     Bool(true),
+				Transitions: Int(3),
+				Window:      TimeDuration(0),
+			},
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			err := tc.i.Validate()
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GateConfig configures a gate that must be open for a task to apply changes.
+// While a gate is closed, triggers of the task are queued and the task runs
+// once when all of its gates open again. Only one gate type can be configured
+// per gate block.
+type GateConfig struct {
+	// ConsulKV configures the gate to be open while a Consul KV key has a
+	// value, e.g. a central change freeze flag
+	ConsulKV *ConsulKVGateConfig `mapstructure:"consul-kv" json:"consul-kv"`
+}
+
+// GateConfigs is a collection of GateConfig
+type GateConfigs []*GateConfig
+
+// ConsulKVGateConfig configures a gate that is open while the value of the
+// Consul KV key at the path equals the configured value. A key that does not
+// exist has an empty value.
+type ConsulKVGateConfig struct {
+	// Path is the Consul KV key of the flag
+	Path *string `mapstructure:"path" json:"path"`
+
+	// Equals is the value of the key for the gate to be open
+	Equals *string `mapstructure:"equals" json:"equals"`
+
+	// Datacenter is the datacenter of the key. Defaults to the datacenter of
+	// the Consul agent.
+	Datacenter *string `mapstructure:"datacenter" json:"datacenter"`
+
+	// Namespace is the namespace of the key
+	Namespace *string `mapstructure:"namespace" json:"namespace"`
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *ConsulKVGateConfig) Copy() *ConsulKVGateConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o ConsulKVGateConfig
+	o.Path = StringCopy(c.Path)
+	o.Equals = StringCopy(c.Equals)
+	o.Datacenter = StringCopy(c.Datacenter)
+	o.Namespace = StringCopy(c.Namespace)
+	return &o
+}
+
+// Finalize ensures there are no nil pointers.
+func (c *ConsulKVGateConfig) Finalize() {
+	if c == nil {
+		return
+	}
+
+	if c.Path == nil {
+		c.Path = String("")
+	}
+
+	if c.Equals == nil {
+		c.Equals = String("")
+	}
+
+	if c.Datacenter == nil {
+		c.Datacenter = String("")
+	}
+
+	if c.Namespace == nil {
+		c.Namespace = String("")
+	}
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *ConsulKVGateConfig) Validate() error {
+	if c == nil {
+		return fmt.Errorf("consul-kv gate: missing configuration")
+	}
+
+	if StringVal(c.Path) == "" {
+		return fmt.Errorf("consul-kv gate: path is required")
+	}
+
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *ConsulKVGateConfig) GoString() string {
+	if c == nil {
+		return "(*ConsulKVGateConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&ConsulKVGateConfig{"+
+		"Path:%s, "+
+		"Equals:%s, "+
+		"Datacenter:%s, "+
+		"Namespace:%s"+
+		"}",
+		StringVal(c.Path),
+		StringVal(c.Equals),
+		StringVal(c.Datacenter),
+		StringVal(c.Namespace),
+	)
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *GateConfig) Copy() *GateConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o GateConfig
+	o.ConsulKV = c.ConsulKV.Copy()
+	return &o
+}
+
+// Finalize ensures there are no nil pointers.
+func (c *GateConfig) Finalize() {
+	if c == nil {
+		return
+	}
+
+	c.ConsulKV.Finalize()
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *GateConfig) Validate() error {
+	if c == nil || c.ConsulKV == nil {
+		return fmt.Errorf("gate: a gate type is required, supported " +
+			"types are: consul-kv")
+	}
+
+	return c.ConsulKV.Validate()
+}
+
+// GoString defines the printable version of this struct.
+func (c *GateConfig) GoString() string {
+	if c == nil {
+		return "(*GateConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&GateConfig{"+
+		"ConsulKV:%s"+
+		"}",
+		c.ConsulKV.GoString(),
+	)
+}
+
+// Len is a helper method to get the length of the underlying config list
+func (c *GateConfigs) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	return len(*c)
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *GateConfigs) Copy() *GateConfigs {
+	if c == nil {
+		return nil
+	}
+
+	o := make(GateConfigs, c.Len())
+	for i, g := range *c {
+		o[i] = g.Copy()
+	}
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration. Gates of the other configuration are appended.
+func (c *GateConfigs) Merge(o *GateConfigs) *GateConfigs {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+	for _, g := range *o {
+		*r = append(*r, g.Copy())
+	}
+
+	return r
+}
+
+// Finalize ensures the configuration has no nil pointers.
+func (c *GateConfigs) Finalize() {
+	if c == nil {
+		return
+	}
+
+	for _, g := range *c {
+		g.Finalize()
+	}
+}
+
+// Validate validates the values and nested values of the configuration struct.
+func (c *GateConfigs) Validate() error {
+	if c == nil {
+		// config is not required, return early
+		return nil
+	}
+
+	for _, g := range *c {
+		if err := g.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *GateConfigs) GoString() string {
+	if c == nil {
+		return "(*GateConfigs)(nil)"
+	}
+
+	s := make([]string, len(*c))
+	for i, g := range *c {
+		s[i] = g.GoString()
+	}
+
+	return "{" + strings.Join(s, ", ") + "}"
+}
@@ -0,0 +1,185 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsulKVGateConfig_Copy(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *ConsulKVGateConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&ConsulKVGateConfig{},
+		},
+		{
+			"happy_path",
+			&ConsulKVGateConfig{
+				Path:       String("flags/freeze"),
+				Equals:     String("false"),
+				Datacenter: String("dc1"),
+				Namespace:  String("ns"),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Copy()
+			assert.Equal(t, tc.a, r)
+		})
+	}
+}
+
+func TestConsulKVGateConfig_Finalize(t *testing.T) {
+	t.Parallel()
+
+	c := &ConsulKVGateConfig{Path: String("flags/freeze")}
+	c.Finalize()
+	assert.Equal(t, &ConsulKVGateConfig{
+		Path:       String("flags/freeze"),
+		Equals:     String(""),
+		Datacenter: String(""),
+		Namespace:  String(""),
+	}, c)
+}
+
+func TestGateConfigs_Merge(t *testing.T) {
+	t.Parallel()
+
+	a := &GateConfigs{{ConsulKV: &ConsulKVGateConfig{Path: String("a")}}}
+	b := &GateConfigs{{ConsulKV: &ConsulKVGateConfig{Path: String("b")}}}
+
+	r := a.Merge(b)
+	assert.Equal(t, &GateConfigs{
+		{ConsulKV: &ConsulKVGateConfig{Path: String("a")}},
+		{ConsulKV: &ConsulKVGateConfig{Path: String("b")}},
+	}, r)
+
+	var nilGates *GateConfigs
+	assert.Equal(t, b, nilGates.Merge(b))
+	assert.Equal(t, a, a.Merge(nil))
+}
+
+func TestGateConfigs_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		c       *GateConfigs
+		isValid bool
+	}{
+		{
+			"nil",
+			nil,
+			true,
+		},
+		{
+			"valid",
+			&GateConfigs{{ConsulKV: &ConsulKVGateConfig{
+				Path:   String("flags/freeze"),
+				Equals: String("false"),
+			}}},
+			true,
+		},
+		{
+			"missing gate type",
+			&GateConfigs{{}},
+			false,
+		},
+		{
+			"missing path",
+			&GateConfigs{{ConsulKV: &ConsulKVGateConfig{
+				Equals: String("false"),
+			}}},
+			false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.c.Finalize()
+			err := tc.c.Validate()
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestGateConfigs_Decode(t *testing.T) {
+	t.Parallel()
+
+	expected := &GateConfigs{
+		{ConsulKV: &ConsulKVGateConfig{
+			Path:   String("flags/freeze"),
+			Equals: String("false"),
+		}},
+	}
+
+	cases := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			"hcl",
+			"config.hcl",
+			`task {
+  name = "task"
+  gate "consul-kv" {
+    path   = "flags/freeze"
+    equals = "false"
+  }
+}`,
+		},
+		{
+			"json",
+			"config.json",
+			`{"task": [{"name": "task", "gate": [
+  {"consul-kv": {"path": "flags/freeze", "equals": "false"}}
+]}]}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := decodeConfig([]byte(tc.content), tc.file)
+			require.NoError(t, err)
+			require.Equal(t, 1, c.Tasks.Len())
+			assert.Equal(t, expected, (*c.Tasks)[0].Gates)
+		})
+	}
+}
+
+func TestGateConfigs_GoString(t *testing.T) {
+	t.Parallel()
+
+	c := &GateConfigs{
+		{ConsulKV: &ConsulKVGateConfig{
+			Path:       String("flags/freeze"),
+			Equals:     String("false"),
+			Datacenter: String("dc1"),
+			Namespace:  String(""),
+		}},
+	}
+	assert.Equal(t, "{&GateConfig{ConsulKV:&ConsulKVGateConfig{"+
+		"Path:flags/freeze, Equals:false, Datacenter:dc1, Namespace:}}}",
+		c.GoString())
+}
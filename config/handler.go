@@ -0,0 +1,269 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Handler failure policies to determine whether a failed handler fails the
+// task run
+const (
+	HandlerFailurePolicyFail   = "fail"
+	HandlerFailurePolicyIgnore = "ignore"
+)
+
+// DefaultExecHandlerTimeout is the default time limit for an exec handler's
+// program to complete
+const DefaultExecHandlerTimeout = 30 * time.Second
+
+// HandlerConfig configures a post-apply handler that runs out-of-band actions
+// after each successful apply of a task. Handlers run after the built-in
+// handlers of the task's providers, in the order they are configured. Only
+// one handler type can be configured per handler block.
+type HandlerConfig struct {
+	// Exec configures the handler to run a program
+	Exec *ExecHandlerConfig `mapstructure:"exec" json:"exec"`
+}
+
+// HandlerConfigs is a collection of HandlerConfig
+type HandlerConfigs []*HandlerConfig
+
+// ExecHandlerConfig configures a handler that runs a program in the task's
+// working directory. The task context is passed to the program as JSON on
+// stdin. The handler fails if the program exits with a non-zero status or
+// does not complete within the timeout.
+type ExecHandlerConfig struct {
+	// Command is the path of the program to run
+	Command *string `mapstructure:"command" json:"command"`
+
+	// Args are the arguments to the program
+	Args []string `mapstructure:"args" json:"args"`
+
+	// Timeout is the time limit for the program to complete. Defaults to 30s.
+	Timeout *time.Duration `mapstructure:"timeout" json:"timeout"`
+
+	// FailurePolicy determines whether a failure of the handler fails the task
+	// run. Supported values are "fail" and "ignore", which only logs the
+	// failure. Defaults to "fail".
+	FailurePolicy *string `mapstructure:"failure_policy" json:"failure_policy"`
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *ExecHandlerConfig) Copy() *ExecHandlerConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o ExecHandlerConfig
+	o.Command = StringCopy(c.Command)
+
+	if c.Args != nil {
+		o.Args = make([]string, 0, len(c.Args))
+		o.Args = append(o.Args, c.Args...)
+	}
+
+	o.Timeout = TimeDurationCopy(c.Timeout)
+	o.FailurePolicy = StringCopy(c.FailurePolicy)
+	return &o
+}
+
+// Finalize ensures there are no nil pointers.
+func (c *ExecHandlerConfig) Finalize() {
+	if c == nil {
+		return
+	}
+
+	if c.Command == nil {
+		c.Command = String("")
+	}
+
+	if c.Args == nil {
+		c.Args = []string{}
+	}
+
+	if c.Timeout == nil {
+		c.Timeout = TimeDuration(DefaultExecHandlerTimeout)
+	}
+
+	if c.FailurePolicy == nil {
+		c.FailurePolicy = String(HandlerFailurePolicyFail)
+	}
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *ExecHandlerConfig) Validate() error {
+	if c == nil {
+		return fmt.Errorf("exec handler: missing configuration")
+	}
+
+	if StringVal(c.Command) == "" {
+		return fmt.Errorf("exec handler: command is required")
+	}
+
+	if TimeDurationVal(c.Timeout) <= 0 {
+		return fmt.Errorf("exec handler: timeout must be greater than 0")
+	}
+
+	switch policy := StringVal(c.FailurePolicy); policy {
+	case HandlerFailurePolicyFail, HandlerFailurePolicyIgnore:
+	default:
+		return fmt.Errorf("exec handler: unsupported failure_policy %q, "+
+			"supported policies are: %s, %s", policy, HandlerFailurePolicyFail,
+			HandlerFailurePolicyIgnore)
+	}
+
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *ExecHandlerConfig) GoString() string {
+	if c == nil {
+		return "(*ExecHandlerConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&ExecHandlerConfig{"+
+		"Command:%s, "+
+		"Args:%s, "+
+		"Timeout:%s, "+
+		"FailurePolicy:%s"+
+		"}",
+		StringVal(c.Command),
+		c.Args,
+		TimeDurationVal(c.Timeout),
+		StringVal(c.FailurePolicy),
+	)
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *HandlerConfig) Copy() *HandlerConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o HandlerConfig
+	o.Exec = c.Exec.Copy()
+	return &o
+}
+
+// Finalize ensures there are no nil pointers.
+func (c *HandlerConfig) Finalize() {
+	if c == nil {
+		return
+	}
+
+	c.Exec.Finalize()
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *HandlerConfig) Validate() error {
+	if c == nil || c.Exec == nil {
+		return fmt.Errorf("handler: a handler type is required, supported " +
+			"types are: exec")
+	}
+
+	return c.Exec.Validate()
+}
+
+// GoString defines the printable version of this struct.
+func (c *HandlerConfig) GoString() string {
+	if c == nil {
+		return "(*HandlerConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&HandlerConfig{"+
+		"Exec:%s"+
+		"}",
+		c.Exec.GoString(),
+	)
+}
+
+// Len is a helper method to get the length of the underlying config list
+func (c *HandlerConfigs) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	return len(*c)
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *HandlerConfigs) Copy() *HandlerConfigs {
+	if c == nil {
+		return nil
+	}
+
+	o := make(HandlerConfigs, c.Len())
+	for i, h := range *c {
+		o[i] = h.Copy()
+	}
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration. Handlers of the other configuration are appended.
+func (c *HandlerConfigs) Merge(o *HandlerConfigs) *HandlerConfigs {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+	for _, h := range *o {
+		*r = append(*r, h.Copy())
+	}
+
+	return r
+}
+
+// Finalize ensures the configuration has no nil pointers.
+func (c *HandlerConfigs) Finalize() {
+	if c == nil {
+		return
+	}
+
+	for _, h := range *c {
+		h.Finalize()
+	}
+}
+
+// Validate validates the values and nested values of the configuration struct.
+func (c *HandlerConfigs) Validate() error {
+	if c == nil {
+		// config is not required, return early
+		return nil
+	}
+
+	for _, h := range *c {
+		if err := h.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *HandlerConfigs) GoString() string {
+	if c == nil {
+		return "(*HandlerConfigs)(nil)"
+	}
+
+	s := make([]string, len(*c))
+	for i, h := range *c {
+		s[i] = h.GoString()
+	}
+
+	return "{" + strings.Join(s, ", ") + "}"
+}
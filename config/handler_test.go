@@ -0,0 +1,279 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecHandlerConfig_Copy(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *ExecHandlerConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&ExecHandlerConfig{},
+		},
+		{
+			"happy_path",
+			&ExecHandlerConfig{
+				Command:       String("./notify.sh"),
+				Args:          []string{"-v"},
+				Timeout:       TimeDuration(time.Minute),
+				FailurePolicy: String(HandlerFailurePolicyIgnore),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Copy()
+			assert.Equal(t, tc.a, r)
+		})
+	}
+}
+
+func TestExecHandlerConfig_Finalize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		i    *ExecHandlerConfig
+		r    *ExecHandlerConfig
+	}{
+		{
+			"nil",
+			nil,
+			nil,
+		},
+		{
+			"empty",
+			&ExecHandlerConfig{},
+			&ExecHandlerConfig{
+				Command:       String(""),
+				Args:          []string{},
+				Timeout:       TimeDuration(DefaultExecHandlerTimeout),
+				FailurePolicy: String(HandlerFailurePolicyFail),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			tc.i.Finalize()
+			assert.Equal(t, tc.r, tc.i)
+		})
+	}
+}
+
+func TestExecHandlerConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		i       *ExecHandlerConfig
+		isValid bool
+	}{
+		{
+			"nil",
+			nil,
+			false,
+		},
+		{
+			"valid",
+			&ExecHandlerConfig{
+				Command:       String("./notify.sh"),
+				Timeout:       TimeDuration(time.Minute),
+				FailurePolicy: String(HandlerFailurePolicyFail),
+			},
+			true,
+		},
+		{
+			"missing_command",
+			&ExecHandlerConfig{
+				Command:       String(""),
+				Timeout:       TimeDuration(time.Minute),
+				FailurePolicy: String(HandlerFailurePolicyFail),
+			},
+			false,
+		},
+		{
+			"invalid_timeout",
+			&ExecHandlerConfig{
+				Command:       String("./notify.sh"),
+				Timeout:       TimeDuration(0),
+				FailurePolicy: String(HandlerFailurePolicyFail),
+			},
+			false,
+		},
+		{
+			"unsupported_failure_policy",
+			&ExecHandlerConfig{
+				Command:       String("./notify.sh"),
+				Timeout:       TimeDuration(time.Minute),
+				FailurePolicy: String("retry"),
+			},
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			err := tc.i.Validate()
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestHandlerConfigs_Merge(t *testing.T) {
+	t.Parallel()
+
+	a := &HandlerConfigs{{Exec: &ExecHandlerConfig{Command: String("a")}}}
+	b := &HandlerConfigs{{Exec: &ExecHandlerConfig{Command: String("b")}}}
+
+	var nilHandlers *HandlerConfigs
+	assert.Nil(t, nilHandlers.Merge(nil))
+	assert.Equal(t, a, nilHandlers.Merge(a))
+	assert.Equal(t, a, a.Merge(nil))
+	assert.Equal(t, &HandlerConfigs{
+		{Exec: &ExecHandlerConfig{Command: String("a")}},
+		{Exec: &ExecHandlerConfig{Command: String("b")}},
+	}, a.Merge(b))
+}
+
+func TestHandlerConfigs_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		i       *HandlerConfigs
+		isValid bool
+	}{
+		{
+			"nil",
+			nil,
+			true,
+		},
+		{
+			"empty",
+			&HandlerConfigs{},
+			true,
+		},
+		{
+			"valid",
+			&HandlerConfigs{{Exec: &ExecHandlerConfig{Command: String("./notify.sh")}}},
+			true,
+		},
+		{
+			"missing_type",
+			&HandlerConfigs{{}},
+			false,
+		},
+		{
+			"invalid_exec",
+			&HandlerConfigs{{Exec: &ExecHandlerConfig{}}},
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			tc.i.Finalize()
+			err := tc.i.Validate()
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestHandlerConfigs_Decode(t *testing.T) {
+	t.Parallel()
+
+	expected := &HandlerConfigs{
+		{Exec: &ExecHandlerConfig{
+			Command: String("./notify.sh"),
+			Args:    []string{"-v"},
+			Timeout: TimeDuration(time.Minute),
+		}},
+		{Exec: &ExecHandlerConfig{
+			Command:       String("./audit.sh"),
+			FailurePolicy: String(HandlerFailurePolicyIgnore),
+		}},
+	}
+
+	cases := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			"hcl",
+			"config.hcl",
+			`task {
+  name = "task"
+  handler "exec" {
+    command = "./notify.sh"
+    args    = ["-v"]
+    timeout = "1m"
+  }
+  handler "exec" {
+    command        = "./audit.sh"
+    failure_policy = "ignore"
+  }
+}`,
+		},
+		{
+			"json",
+			"config.json",
+			`{"task": [{"name": "task", "handler": [
+  {"exec": {"command": "./notify.sh", "args": ["-v"], "timeout": "1m"}},
+  {"exec": {"command": "./audit.sh", "failure_policy": "ignore"}}
+]}]}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := decodeConfig([]byte(tc.content), tc.file)
+			require.NoError(t, err)
+			require.Equal(t, 1, c.Tasks.Len())
+			assert.Equal(t, expected, (*c.Tasks)[0].Handlers)
+		})
+	}
+}
+
+func TestHandlerConfigs_GoString(t *testing.T) {
+	t.Parallel()
+
+	c := &HandlerConfigs{
+		{Exec: &ExecHandlerConfig{
+			Command:       String("./notify.sh"),
+			Args:          []string{"-v"},
+			Timeout:       TimeDuration(time.Minute),
+			FailurePolicy: String(HandlerFailurePolicyFail),
+		}},
+	}
+	assert.Equal(t, "{&HandlerConfig{Exec:&ExecHandlerConfig{"+
+		"Command:./notify.sh, Args:[-v], Timeout:1m0s, FailurePolicy:fail}}}",
+		c.GoString())
+}
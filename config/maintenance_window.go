@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/cronexpr"
+)
+
+// MaintenanceWindowConfig configures a recurring window for a task during
+// which triggers are queued instead of applied. Each window starts at the
+// times of the cron expression and lasts for the duration. A task that was
+// triggered during a window runs once when the window ends.
+type MaintenanceWindowConfig struct {
+	// Enabled determines if the maintenance window is enabled. Disabled by
+	// default, and enabled if any other option is configured.
+	Enabled *bool `mapstructure:"enabled" json:"enabled"`
+
+	// Cron is the cron expression for the start of each window, e.g.
+	// "0 22 * * 6" for a weekly window that starts on Saturday at 22:00.
+	// Times are in the local time zone of CTS.
+	Cron *string `mapstructure:"cron" json:"cron"`
+
+	// Duration is the length of each window
+	Duration *time.Duration `mapstructure:"duration" json:"duration"`
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *MaintenanceWindowConfig) Copy() *MaintenanceWindowConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o MaintenanceWindowConfig
+	o.Enabled = BoolCopy(c.Enabled)
+	o.Cron = StringCopy(c.Cron)
+	o.Duration = TimeDurationCopy(c.Duration)
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+// Maps and slices are merged, most other values are overwritten. Complex
+// structs define their own merge functionality.
+func (c *MaintenanceWindowConfig) Merge(o *MaintenanceWindowConfig) *MaintenanceWindowConfig {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+
+	if o.Enabled != nil {
+		r.Enabled = BoolCopy(o.Enabled)
+	}
+
+	if o.Cron != nil {
+		r.Cron = StringCopy(o.Cron)
+	}
+
+	if o.Duration != nil {
+		r.Duration = TimeDurationCopy(o.Duration)
+	}
+
+	return r
+}
+
+// Finalize ensures that the receiver contains no nil pointers. For nil pointers,
+// Finalize sets default values where necessary
+func (c *MaintenanceWindowConfig) Finalize() {
+	if c == nil {
+		return
+	}
+
+	if c.Enabled == nil {
+		// some options configured, assume user intention is enabled
+		c.Enabled = Bool(c.Cron != nil || c.Duration != nil)
+	}
+
+	if c.Cron == nil {
+		c.Cron = String("")
+	}
+
+	if c.Duration == nil {
+		c.Duration = TimeDuration(0 * time.Second)
+	}
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *MaintenanceWindowConfig) Validate() error {
+	if c == nil {
+		// config is not required, return early
+		return nil
+	}
+
+	if !BoolVal(c.Enabled) {
+		return nil
+	}
+
+	if StringVal(c.Cron) == "" {
+		return fmt.Errorf("maintenance_window: cron is required")
+	}
+
+	if _, err := cronexpr.Parse(*c.Cron); err != nil {
+		return fmt.Errorf("maintenance_window: unable to parse cron %q: %s. "+
+			"for more information on writing cron expressions, see %s",
+			*c.Cron, err, "https://github.com/hashicorp/cronexpr")
+	}
+
+	if TimeDurationVal(c.Duration) <= 0 {
+		return fmt.Errorf("maintenance_window: duration must be greater than 0")
+	}
+
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *MaintenanceWindowConfig) GoString() string {
+	if c == nil {
+		return "(*MaintenanceWindowConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&MaintenanceWindowConfig{"+
+		"Enabled:%v, "+
+		"Cron:%s, "+
+		"Duration:%s"+
+		"}",
+		BoolVal(c.Enabled),
+		StringVal(c.Cron),
+		TimeDurationVal(c.Duration),
+	)
+}
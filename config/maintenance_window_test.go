@@ -0,0 +1,238 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// defaultMaintenanceWindowConfig returns the finalized maintenance window
+// configuration for a task that does not configure a maintenance window
+func defaultMaintenanceWindowConfig() *MaintenanceWindowConfig {
+	return &MaintenanceWindowConfig{
+		Enabled:  Bool(false),
+		Cron:     String(""),
+		Duration: TimeDuration(0),
+	}
+}
+
+func TestMaintenanceWindowConfig_Copy(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *MaintenanceWindowConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&MaintenanceWindowConfig{},
+		},
+		{
+			"fully configured",
+			&MaintenanceWindowConfig{
+				Enabled:  Bool(true),
+				Cron:     String("0 22 * * 6"),
+				Duration: TimeDuration(4 * time.Hour),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Copy()
+			assert.Equal(t, tc.a, r)
+		})
+	}
+}
+
+func TestMaintenanceWindowConfig_Merge(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *MaintenanceWindowConfig
+		b    *MaintenanceWindowConfig
+		r    *MaintenanceWindowConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&MaintenanceWindowConfig{},
+			&MaintenanceWindowConfig{},
+		},
+		{
+			"nil_b",
+			&MaintenanceWindowConfig{},
+			nil,
+			&MaintenanceWindowConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"overrides",
+			&MaintenanceWindowConfig{
+				Enabled:  Bool(false),
+				Cron:     String("0 22 * * 6"),
+				Duration: TimeDuration(time.Hour),
+			},
+			&MaintenanceWindowConfig{
+				Enabled:  Bool(true),
+				Cron:     String("0 2 * * *"),
+				Duration: TimeDuration(2 * time.Hour),
+			},
+			&MaintenanceWindowConfig{
+				Enabled:  Bool(true),
+				Cron:     String("0 2 * * *"),
+				Duration: TimeDuration(2 * time.Hour),
+			},
+		},
+		{
+			"empty_one",
+			&MaintenanceWindowConfig{
+				Cron: String("0 22 * * 6"),
+			},
+			&MaintenanceWindowConfig{
+				Duration: TimeDuration(time.Hour),
+			},
+			&MaintenanceWindowConfig{
+				Cron:     String("0 22 * * 6"),
+				Duration: TimeDuration(time.Hour),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			assert.Equal(t, tc.r, r)
+		})
+	}
+}
+
+func TestMaintenanceWindowConfig_Finalize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		i    *MaintenanceWindowConfig
+		r    *MaintenanceWindowConfig
+	}{
+		{
+			"empty",
+			&MaintenanceWindowConfig{},
+			defaultMaintenanceWindowConfig(),
+		},
+		{
+			"configured",
+			&MaintenanceWindowConfig{
+				Cron:     String("0 22 * * 6"),
+				Duration: TimeDuration(4 * time.Hour),
+			},
+			&MaintenanceWindowConfig{
+				Enabled:  Bool(true),
+				Cron:     String("0 22 * * 6"),
+				Duration: TimeDuration(4 * time.Hour),
+			},
+		},
+		{
+			"disabled",
+			&MaintenanceWindowConfig{
+				Enabled: Bool(false),
+				Cron:    String("0 22 * * 6"),
+			},
+			&MaintenanceWindowConfig{
+				Enabled:  Bool(false),
+				Cron:     String("0 22 * * 6"),
+				Duration: TimeDuration(0),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			tc.i.Finalize()
+			assert.Equal(t, tc.r, tc.i)
+		})
+	}
+}
+
+func TestMaintenanceWindowConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		i       *MaintenanceWindowConfig
+		isValid bool
+	}{
+		{
+			"nil",
+			nil,
+			true,
+		},
+		{
+			"disabled",
+			defaultMaintenanceWindowConfig(),
+			true,
+		},
+		{
+			"valid",
+			&MaintenanceWindowConfig{
+				Enabled:  Bool(true),
+				Cron:     String("0 22 * * 6"),
+				Duration: TimeDuration(4 * time.Hour),
+			},
+			true,
+		},
+		{
+			"missing cron",
+			&MaintenanceWindowConfig{
+				Enabled:  Bool(true),
+				Cron:     String(""),
+				Duration: TimeDuration(4 * time.Hour),
+			},
+			false,
+		},
+		{
+			"invalid cron",
+			&MaintenanceWindowConfig{
+				Enabled:  Bool(true),
+				Cron:     String("not a cron"),
+				Duration: TimeDuration(4 * time.Hour),
+			},
+			false,
+		},
+		{
+			"missing duration",
+			&MaintenanceWindowConfig{
+				Enabled:  Bool(true),
+				Cron:     String("0 22 * * 6"),
+				Duration: TimeDuration(0),
+			},
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			err := tc.i.Validate()
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
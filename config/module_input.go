@@ -67,6 +67,11 @@ func moduleInputToTypeFunc() mapstructure.DecodeHookFunc {
 			return decodeModuleInputToType(c, &config)
 		}
 
+		if c, ok := moduleInputs[fileType]; ok {
+			var config FileModuleInputConfig
+			return decodeModuleInputToType(c, &config)
+		}
+
 		return nil, fmt.Errorf("unsupported module_input type: %v", data)
 	}
 }
@@ -80,6 +85,7 @@ func decodeModuleInputToType(data interface{}, moduleInput ModuleInputConfig) (M
 	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
 		DecodeHook: mapstructure.ComposeDecodeHookFunc(
 			decode.HookWeakDecodeFromSlice,
+			mapstructure.StringToTimeDurationHookFunc(),
 		),
 		WeaklyTypedInput: true,
 		ErrorUnused:      false,
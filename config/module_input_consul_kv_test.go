@@ -217,6 +217,7 @@ func TestConsulKVModuleInputConfig_Finalize(t *testing.T) {
 			&ConsulKVModuleInputConfig{
 				ConsulKVMonitorConfig{
 					Path:       String(""),
+					Paths:      []string{},
 					Recurse:    Bool(false),
 					Datacenter: String(""),
 					Namespace:  String(""),
@@ -293,6 +294,7 @@ func TestConsulKVModuleInputConfig_GoString(t *testing.T) {
 			"&ConsulKVModuleInputConfig{" +
 				"&ConsulKVMonitorConfig{" +
 				"Path:path, " +
+				"Paths:[], " +
 				"Recurse:true, " +
 				"Datacenter:dc, " +
 				"Namespace:ns, " +
@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+)
+
+var _ ModuleInputConfig = (*FileModuleInputConfig)(nil)
+
+// FileModuleInputConfig configures a module_input configuration block of
+// type 'file'. The paths, hashes, and optionally the content of local files
+// will be used as input for the module variables.
+type FileModuleInputConfig struct {
+	FileMonitorConfig `mapstructure:",squash" json:"file"`
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *FileModuleInputConfig) Copy() MonitorConfig {
+	if c == nil {
+		return nil
+	}
+
+	svc, ok := c.FileMonitorConfig.Copy().(*FileMonitorConfig)
+	if !ok {
+		return nil
+	}
+	return &FileModuleInputConfig{
+		FileMonitorConfig: *svc,
+	}
+}
+
+// Merge combines all values in this configuration `c` with the values in the other
+// configuration `o`, with values in the other configuration taking precedence.
+// Maps and slices are merged, most other values are overwritten. Complex
+// structs define their own merge functionality.
+func (c *FileModuleInputConfig) Merge(o MonitorConfig) MonitorConfig {
+	if c == nil {
+		if isModuleInputNil(o) { // o is interface, use isConditionNil()
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if isModuleInputNil(o) {
+		return c.Copy()
+	}
+
+	scc, ok := o.(*FileModuleInputConfig)
+	if !ok {
+		return nil
+	}
+
+	merged, ok := c.FileMonitorConfig.Merge(&scc.FileMonitorConfig).(*FileMonitorConfig)
+	if !ok {
+		return nil
+	}
+
+	return &FileModuleInputConfig{
+		FileMonitorConfig: *merged,
+	}
+}
+
+// Finalize ensures there are no nil pointers.
+func (c *FileModuleInputConfig) Finalize() {
+	if c == nil { // config not required, return early
+		return
+	}
+	c.FileMonitorConfig.Finalize()
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *FileModuleInputConfig) Validate() error {
+	if c == nil { // config not required, return early
+		return nil
+	}
+	return c.FileMonitorConfig.Validate()
+}
+
+// GoString defines the printable version of this struct.
+func (c *FileModuleInputConfig) GoString() string {
+	if c == nil {
+		return "(*FileModuleInputConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&FileModuleInputConfig{"+
+		"%s"+
+		"}",
+		c.FileMonitorConfig.GoString(),
+	)
+}
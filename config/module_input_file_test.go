@@ -0,0 +1,203 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileModuleInputConfig_Copy(t *testing.T) {
+	t.Parallel()
+
+	finalizedConf := &FileModuleInputConfig{}
+	finalizedConf.Finalize()
+
+	cases := []struct {
+		name string
+		a    *FileModuleInputConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&FileModuleInputConfig{},
+		},
+		{
+			"finalized",
+			finalizedConf,
+		},
+		{
+			"fully_configured",
+			&FileModuleInputConfig{
+				FileMonitorConfig{
+					Paths:          []string{"/etc/cts/rules"},
+					Interval:       TimeDuration(time.Minute),
+					IncludeContent: Bool(false),
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tc.a.Copy()
+			if tc.a == nil {
+				// returned nil interface has nil type, which is unequal to tc.a
+				assert.Nil(t, r)
+			} else {
+				assert.Equal(t, tc.a, r)
+			}
+		})
+	}
+}
+
+func TestFileModuleInputConfig_Merge(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *FileModuleInputConfig
+		b    *FileModuleInputConfig
+		r    *FileModuleInputConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&FileModuleInputConfig{},
+			&FileModuleInputConfig{},
+		},
+		{
+			"nil_b",
+			&FileModuleInputConfig{},
+			nil,
+			&FileModuleInputConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"paths_merges",
+			&FileModuleInputConfig{FileMonitorConfig{Paths: []string{"/a"}}},
+			&FileModuleInputConfig{FileMonitorConfig{Paths: []string{"/b"}}},
+			&FileModuleInputConfig{FileMonitorConfig{Paths: []string{"/a", "/b"}}},
+		},
+		{
+			"include_content_overrides",
+			&FileModuleInputConfig{FileMonitorConfig{IncludeContent: Bool(true)}},
+			&FileModuleInputConfig{FileMonitorConfig{IncludeContent: Bool(false)}},
+			&FileModuleInputConfig{FileMonitorConfig{IncludeContent: Bool(false)}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			if tc.r == nil {
+				// returned nil interface has nil type, which is unequal to tc.r
+				assert.Nil(t, r)
+			} else {
+				assert.Equal(t, tc.r, r)
+			}
+		})
+	}
+}
+
+func TestFileModuleInputConfig_Finalize(t *testing.T) {
+	t.Parallel()
+
+	i := &FileModuleInputConfig{}
+	i.Finalize()
+	assert.Equal(t, &FileModuleInputConfig{
+		FileMonitorConfig{
+			Paths:          []string{},
+			Interval:       TimeDuration(DefaultFileInterval),
+			IncludeContent: Bool(true),
+		},
+	}, i)
+}
+
+func TestFileModuleInputConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		expectErr bool
+		c         *FileModuleInputConfig
+	}{
+		{
+			"happy_path",
+			false,
+			&FileModuleInputConfig{
+				FileMonitorConfig{
+					Paths: []string{"/etc/cts/rules"},
+				},
+			},
+		},
+		{
+			"nil_paths",
+			true,
+			&FileModuleInputConfig{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.c.Validate()
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFileModuleInputConfig_GoString(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		f        *FileModuleInputConfig
+		expected string
+	}{
+		{
+			"configured file module_input",
+			&FileModuleInputConfig{
+				FileMonitorConfig{
+					Paths:          []string{"/etc/cts/rules"},
+					Interval:       TimeDuration(time.Minute),
+					IncludeContent: Bool(true),
+				},
+			},
+			"&FileModuleInputConfig{" +
+				"&FileMonitorConfig{" +
+				"Paths:[/etc/cts/rules], " +
+				"Interval:1m0s, " +
+				"IncludeContent:true" +
+				"}" +
+				"}",
+		},
+		{
+			"nil file module_input",
+			nil,
+			"(*FileModuleInputConfig)(nil)",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tc.f.GoString()
+			require.Equal(t, actual, tc.expected)
+		})
+	}
+}
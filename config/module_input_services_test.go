@@ -157,12 +157,15 @@ func TestServicesModuleInputConfig_Finalize(t *testing.T) {
 			&ServicesModuleInputConfig{},
 			&ServicesModuleInputConfig{
 				ServicesMonitorConfig{
-					Regexp:             nil,
-					Names:              []string{},
-					Datacenter:         String(""),
-					Namespace:          String(""),
-					Filter:             String(""),
-					CTSUserDefinedMeta: map[string]string{},
+					Regexp:                  nil,
+					Names:                   []string{},
+					Datacenter:              String(""),
+					Namespace:               String(""),
+					Filter:                  String(""),
+					CTSUserDefinedMeta:      map[string]string{},
+					IncludeExtendedMetadata: Bool(false),
+					Query:                   String(""),
+					Bootstrap:               Bool(false),
 				},
 			},
 		},
@@ -249,7 +252,10 @@ func TestGoString(t *testing.T) {
 				"Datacenter:dc2, " +
 				"Namespace:ns2, " +
 				"Filter:some-filter, " +
-				"CTSUserDefinedMeta:map[key:value]" +
+				"CTSUserDefinedMeta:map[key:value], " +
+				"IncludeExtendedMetadata:false, " +
+				"Query:, " +
+				"Bootstrap:false" +
 				"}" +
 				"}",
 		},
@@ -5,6 +5,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -44,6 +45,18 @@ task {
 		datacenter = "dc2"
 		recurse = true
 	}
+}`
+	testModuleInputFileSuccess = `
+task {
+	name = "module_input_task"
+	module = "..."
+	condition "schedule" {
+		cron = "* * * * * * *"
+	}
+	module_input "file" {
+		paths = ["/etc/cts/allowlist.txt"]
+		interval = "30s"
+	}
 }`
 	testModuleInputsSuccess = `
 task {
@@ -103,12 +116,15 @@ func TestModuleInput_DecodeConfig_Success(t *testing.T) {
 			expected: &ModuleInputConfigs{
 				&ServicesModuleInputConfig{
 					ServicesMonitorConfig{
-						Regexp:             String(".*"),
-						Names:              []string{},
-						Datacenter:         String("dc2"),
-						Namespace:          String("ns2"),
-						Filter:             String("some-filter"),
-						CTSUserDefinedMeta: map[string]string{"key": "value"},
+						Regexp:                  String(".*"),
+						Names:                   []string{},
+						Datacenter:              String("dc2"),
+						Namespace:               String("ns2"),
+						Filter:                  String("some-filter"),
+						CTSUserDefinedMeta:      map[string]string{"key": "value"},
+						IncludeExtendedMetadata: Bool(false),
+						Query:                   String(""),
+						Bootstrap:               Bool(false),
 					},
 				},
 			},
@@ -120,6 +136,7 @@ func TestModuleInput_DecodeConfig_Success(t *testing.T) {
 				&ConsulKVModuleInputConfig{
 					ConsulKVMonitorConfig{
 						Path:       String("key-path"),
+						Paths:      []string{},
 						Datacenter: String("dc2"),
 						Namespace:  String("ns2"),
 						Recurse:    Bool(true),
@@ -128,21 +145,38 @@ func TestModuleInput_DecodeConfig_Success(t *testing.T) {
 			},
 			config: testModuleInputConsulKVSuccess,
 		},
+		{
+			name: "file",
+			expected: &ModuleInputConfigs{
+				&FileModuleInputConfig{
+					FileMonitorConfig{
+						Paths:          []string{"/etc/cts/allowlist.txt"},
+						Interval:       TimeDuration(30 * time.Second),
+						IncludeContent: Bool(true),
+					},
+				},
+			},
+			config: testModuleInputFileSuccess,
+		},
 		{
 			name: "multiple unique module_inputs",
 			expected: &ModuleInputConfigs{
 				&ServicesModuleInputConfig{
 					ServicesMonitorConfig{
-						Names:              []string{"api"},
-						Datacenter:         String(""),
-						Namespace:          String(""),
-						Filter:             String(""),
-						CTSUserDefinedMeta: map[string]string{},
+						Names:                   []string{"api"},
+						Datacenter:              String(""),
+						Namespace:               String(""),
+						Filter:                  String(""),
+						CTSUserDefinedMeta:      map[string]string{},
+						IncludeExtendedMetadata: Bool(false),
+						Query:                   String(""),
+						Bootstrap:               Bool(false),
 					},
 				},
 				&ConsulKVModuleInputConfig{
 					ConsulKVMonitorConfig{
 						Path:       String("my/path"),
+						Paths:      []string{},
 						Recurse:    Bool(false),
 						Datacenter: String(""),
 						Namespace:  String(""),
@@ -393,12 +427,15 @@ func TestModuleInputConfigs_Finalize(t *testing.T) {
 			&ModuleInputConfigs{
 				&ServicesModuleInputConfig{
 					ServicesMonitorConfig{
-						Regexp:             nil,
-						Names:              []string{},
-						Datacenter:         String(""),
-						Namespace:          String(""),
-						Filter:             String(""),
-						CTSUserDefinedMeta: map[string]string{},
+						Regexp:                  nil,
+						Names:                   []string{},
+						Datacenter:              String(""),
+						Namespace:               String(""),
+						Filter:                  String(""),
+						CTSUserDefinedMeta:      map[string]string{},
+						IncludeExtendedMetadata: Bool(false),
+						Query:                   String(""),
+						Bootstrap:               Bool(false),
 					},
 				},
 			},
@@ -525,9 +562,10 @@ func TestModuleInputConfigs_GoString(t *testing.T) {
 				},
 			},
 			"{&ServicesModuleInputConfig{&ServicesMonitorConfig{Regexp:^api$, Names:[], " +
-				"Datacenter:, Namespace:, Filter:, CTSUserDefinedMeta:map[]}}, " +
+				"Datacenter:, Namespace:, Filter:, CTSUserDefinedMeta:map[], " +
+				"IncludeExtendedMetadata:false, Query:, Bootstrap:false}}, " +
 				"&ConsulKVModuleInputConfig{&ConsulKVMonitorConfig{Path:my/path, " +
-				"Recurse:false, Datacenter:, Namespace:, }}}",
+				"Paths:[], Recurse:false, Datacenter:, Namespace:, }}}",
 		},
 	}
 
@@ -26,6 +26,13 @@ type MonitorConfig interface {
 	VariableType() string
 }
 
+// datacenterMonitor is a monitor of objects in a Consul datacenter
+type datacenterMonitor interface {
+	// datacenter returns the configured datacenter of the monitor, or an
+	// empty string if the datacenter of the Consul agent is monitored
+	datacenter() string
+}
+
 // isMonitorNil can be used to check if a MonitorConfig interface is nil by
 // checking both the type and value. Not needed for checking a MonitorConfig
 // implementation i.e. isMonitorNil(MonitorConfig),
@@ -43,12 +50,20 @@ func isMonitorNil(c MonitorConfig) bool {
 		result = v == nil
 	case *ScheduleConditionConfig:
 		result = v == nil
+	case *DNSConditionConfig:
+		result = v == nil
+	case *FileConditionConfig:
+		result = v == nil
+	case *PluginConditionConfig:
+		result = v == nil
 
 	// Module Inputs
 	case *ServicesModuleInputConfig:
 		result = v == nil
 	case *ConsulKVModuleInputConfig:
 		result = v == nil
+	case *FileModuleInputConfig:
+		result = v == nil
 	default:
 		return c == nil || reflect.ValueOf(c).IsNil()
 	}
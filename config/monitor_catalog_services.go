@@ -12,6 +12,21 @@ import (
 
 const catalogServicesType = "catalog-services"
 
+// CatalogServicesKindTypical is the kind of the typical services registered
+// in Consul that are not proxies or gateways
+const CatalogServicesKindTypical = "typical"
+
+// catalogServicesKinds are the kinds of services that the catalog-services
+// monitor can filter on
+var catalogServicesKinds = []string{
+	CatalogServicesKindTypical,
+	"connect-proxy",
+	"mesh-gateway",
+	"terminating-gateway",
+	"ingress-gateway",
+	"api-gateway",
+}
+
 var _ ConditionConfig = (*CatalogServicesConditionConfig)(nil)
 
 // CatalogServicesMonitorConfig configures a configuration block adhering to the monitor interface
@@ -23,6 +38,23 @@ type CatalogServicesMonitorConfig struct {
 	Namespace  *string           `mapstructure:"namespace" json:"namespace"`
 	NodeMeta   map[string]string `mapstructure:"node_meta" json:"node_meta"`
 
+	// Kind filters the services by their kind, e.g. "typical" to exclude the
+	// sidecar proxies of services or "ingress-gateway". Services of all kinds
+	// are monitored if not set.
+	Kind *string `mapstructure:"kind" json:"kind"`
+
+	// TriggerOnTagChanges configures the monitor to also trigger when the set
+	// of tags changes for any of the matched services, rather than only when
+	// services are registered or deregistered.
+	TriggerOnTagChanges *bool `mapstructure:"trigger_on_tag_changes" json:"trigger_on_tag_changes"`
+
+	// MinInstances and MaxInstances configure the monitor to only trigger
+	// when the number of registered instances of a matched service crosses
+	// the threshold, e.g. scaling out past 10 instances, rather than on every
+	// registration change. A threshold of 0 is not set.
+	MinInstances *int `mapstructure:"min_instances" json:"min_instances"`
+	MaxInstances *int `mapstructure:"max_instances" json:"max_instances"`
+
 	// UseAsModuleInput was previously named SourceIncludesVar - deprecated v0.5
 	UseAsModuleInput            *bool `mapstructure:"use_as_module_input" json:"use_as_module_input"`
 	DeprecatedSourceIncludesVar *bool `mapstructure:"source_includes_var" json:"source_includes_var"`
@@ -32,6 +64,13 @@ func (c *CatalogServicesMonitorConfig) VariableType() string {
 	return "catalog_services"
 }
 
+func (c *CatalogServicesMonitorConfig) datacenter() string {
+	if c == nil {
+		return ""
+	}
+	return StringVal(c.Datacenter)
+}
+
 // Copy returns a deep copy of this configuration.
 func (c *CatalogServicesMonitorConfig) Copy() MonitorConfig {
 	if c == nil {
@@ -43,6 +82,10 @@ func (c *CatalogServicesMonitorConfig) Copy() MonitorConfig {
 	o.Regexp = StringCopy(c.Regexp)
 	o.Datacenter = StringCopy(c.Datacenter)
 	o.Namespace = StringCopy(c.Namespace)
+	o.Kind = StringCopy(c.Kind)
+	o.TriggerOnTagChanges = BoolCopy(c.TriggerOnTagChanges)
+	o.MinInstances = IntCopy(c.MinInstances)
+	o.MaxInstances = IntCopy(c.MaxInstances)
 
 	o.UseAsModuleInput = BoolCopy(c.UseAsModuleInput)
 	o.DeprecatedSourceIncludesVar = BoolCopy(c.DeprecatedSourceIncludesVar)
@@ -100,6 +143,22 @@ func (c *CatalogServicesMonitorConfig) Merge(o MonitorConfig) MonitorConfig {
 		r2.Namespace = StringCopy(o2.Namespace)
 	}
 
+	if o2.Kind != nil {
+		r2.Kind = StringCopy(o2.Kind)
+	}
+
+	if o2.TriggerOnTagChanges != nil {
+		r2.TriggerOnTagChanges = BoolCopy(o2.TriggerOnTagChanges)
+	}
+
+	if o2.MinInstances != nil {
+		r2.MinInstances = IntCopy(o2.MinInstances)
+	}
+
+	if o2.MaxInstances != nil {
+		r2.MaxInstances = IntCopy(o2.MaxInstances)
+	}
+
 	if o2.NodeMeta != nil {
 		if r2.NodeMeta == nil {
 			r2.NodeMeta = make(map[string]string)
@@ -147,6 +206,22 @@ func (c *CatalogServicesMonitorConfig) Finalize() {
 	if c.NodeMeta == nil {
 		c.NodeMeta = make(map[string]string)
 	}
+
+	if c.Kind == nil {
+		c.Kind = String("")
+	}
+
+	if c.TriggerOnTagChanges == nil {
+		c.TriggerOnTagChanges = Bool(false)
+	}
+
+	if c.MinInstances == nil {
+		c.MinInstances = Int(0)
+	}
+
+	if c.MaxInstances == nil {
+		c.MaxInstances = Int(0)
+	}
 }
 
 // Validate validates the values and required options. This method is recommended
@@ -164,9 +239,46 @@ func (c *CatalogServicesMonitorConfig) Validate() error {
 	if _, err := regexp.Compile(StringVal(c.Regexp)); err != nil {
 		return fmt.Errorf("unable to compile catalog-services 'regexp': %s", err)
 	}
+
+	if kind := StringVal(c.Kind); kind != "" {
+		valid := false
+		for _, k := range catalogServicesKinds {
+			if kind == k {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("catalog-services 'kind' must be one of %q: %q",
+				catalogServicesKinds, kind)
+		}
+	}
+
+	min, max := IntVal(c.MinInstances), IntVal(c.MaxInstances)
+	if min < 0 || max < 0 {
+		return fmt.Errorf("catalog-services 'min_instances' and 'max_instances' "+
+			"must not be negative: %d, %d", min, max)
+	}
+	if min > 0 && max > 0 && min > max {
+		return fmt.Errorf("catalog-services 'min_instances' must be less than "+
+			"or equal to 'max_instances': %d > %d", min, max)
+	}
+	if c.HasInstanceThresholds() && BoolVal(c.TriggerOnTagChanges) {
+		return fmt.Errorf("catalog-services 'trigger_on_tag_changes' cannot be " +
+			"used with 'min_instances' or 'max_instances'")
+	}
 	return nil
 }
 
+// HasInstanceThresholds returns true if the monitor is configured with a min
+// or max threshold for the number of instances of the services.
+func (c *CatalogServicesMonitorConfig) HasInstanceThresholds() bool {
+	if c == nil {
+		return false
+	}
+	return IntVal(c.MinInstances) > 0 || IntVal(c.MaxInstances) > 0
+}
+
 // GoString defines the printable version of this struct.
 func (c *CatalogServicesMonitorConfig) GoString() string {
 	if c == nil {
@@ -178,12 +290,20 @@ func (c *CatalogServicesMonitorConfig) GoString() string {
 		"Datacenter:%v, "+
 		"Namespace:%v, "+
 		"NodeMeta:%s, "+
+		"Kind:%s, "+
+		"TriggerOnTagChanges:%v, "+
+		"MinInstances:%d, "+
+		"MaxInstances:%d, "+
 		"UseAsModuleInput:%v"+
 		"}",
 		StringVal(c.Regexp),
 		StringVal(c.Datacenter),
 		StringVal(c.Namespace),
 		c.NodeMeta,
+		StringVal(c.Kind),
+		BoolVal(c.TriggerOnTagChanges),
+		IntVal(c.MinInstances),
+		IntVal(c.MaxInstances),
 		BoolVal(c.UseAsModuleInput),
 	)
 }
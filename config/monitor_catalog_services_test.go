@@ -43,6 +43,10 @@ func TestCatalogServicesMonitorConfig_Copy(t *testing.T) {
 						"key1": "value1",
 						"key2": "value2",
 					},
+					Kind:                String("typical"),
+					TriggerOnTagChanges: Bool(true),
+					MinInstances:        Int(2),
+					MaxInstances:        Int(10),
 				},
 			},
 		},
@@ -214,6 +218,48 @@ func TestCatalogServicesMonitorConfig_Merge(t *testing.T) {
 			&CatalogServicesConditionConfig{CatalogServicesMonitorConfig{Namespace: String("same")}},
 			&CatalogServicesConditionConfig{CatalogServicesMonitorConfig{Namespace: String("same")}},
 		},
+		{
+			"kind_overrides",
+			&CatalogServicesConditionConfig{CatalogServicesMonitorConfig{Kind: String("typical")}},
+			&CatalogServicesConditionConfig{CatalogServicesMonitorConfig{Kind: String("ingress-gateway")}},
+			&CatalogServicesConditionConfig{CatalogServicesMonitorConfig{Kind: String("ingress-gateway")}},
+		},
+		{
+			"kind_empty_one",
+			&CatalogServicesConditionConfig{CatalogServicesMonitorConfig{Kind: String("typical")}},
+			&CatalogServicesConditionConfig{},
+			&CatalogServicesConditionConfig{CatalogServicesMonitorConfig{Kind: String("typical")}},
+		},
+		{
+			"trigger_on_tag_changes_overrides",
+			&CatalogServicesConditionConfig{CatalogServicesMonitorConfig{TriggerOnTagChanges: Bool(false)}},
+			&CatalogServicesConditionConfig{CatalogServicesMonitorConfig{TriggerOnTagChanges: Bool(true)}},
+			&CatalogServicesConditionConfig{CatalogServicesMonitorConfig{TriggerOnTagChanges: Bool(true)}},
+		},
+		{
+			"trigger_on_tag_changes_empty_one",
+			&CatalogServicesConditionConfig{CatalogServicesMonitorConfig{TriggerOnTagChanges: Bool(true)}},
+			&CatalogServicesConditionConfig{},
+			&CatalogServicesConditionConfig{CatalogServicesMonitorConfig{TriggerOnTagChanges: Bool(true)}},
+		},
+		{
+			"trigger_on_tag_changes_empty_two",
+			&CatalogServicesConditionConfig{},
+			&CatalogServicesConditionConfig{CatalogServicesMonitorConfig{TriggerOnTagChanges: Bool(true)}},
+			&CatalogServicesConditionConfig{CatalogServicesMonitorConfig{TriggerOnTagChanges: Bool(true)}},
+		},
+		{
+			"instances_overrides",
+			&CatalogServicesConditionConfig{CatalogServicesMonitorConfig{MinInstances: Int(1), MaxInstances: Int(5)}},
+			&CatalogServicesConditionConfig{CatalogServicesMonitorConfig{MaxInstances: Int(10)}},
+			&CatalogServicesConditionConfig{CatalogServicesMonitorConfig{MinInstances: Int(1), MaxInstances: Int(10)}},
+		},
+		{
+			"instances_empty_one",
+			&CatalogServicesConditionConfig{CatalogServicesMonitorConfig{MinInstances: Int(1)}},
+			&CatalogServicesConditionConfig{},
+			&CatalogServicesConditionConfig{CatalogServicesMonitorConfig{MinInstances: Int(1)}},
+		},
 		{
 			"node_meta_overrides",
 			&CatalogServicesConditionConfig{CatalogServicesMonitorConfig{NodeMeta: map[string]string{"key": "value"}}},
@@ -271,11 +317,15 @@ func TestCatalogServicesMonitorConfig_Finalize(t *testing.T) {
 			&CatalogServicesConditionConfig{},
 			&CatalogServicesConditionConfig{
 				CatalogServicesMonitorConfig{
-					Regexp:           nil,
-					UseAsModuleInput: Bool(true),
-					Datacenter:       String(""),
-					Namespace:        String(""),
-					NodeMeta:         map[string]string{},
+					Regexp:              nil,
+					UseAsModuleInput:    Bool(true),
+					Datacenter:          String(""),
+					Namespace:           String(""),
+					NodeMeta:            map[string]string{},
+					Kind:                String(""),
+					TriggerOnTagChanges: Bool(false),
+					MinInstances:        Int(0),
+					MaxInstances:        Int(0),
 				},
 			},
 		},
@@ -375,6 +425,80 @@ func TestCatalogServicesMonitorConfig_Validate(t *testing.T) {
 				},
 			},
 		},
+		{
+			"valid_kind",
+			false,
+			&CatalogServicesConditionConfig{
+				CatalogServicesMonitorConfig{
+					Regexp: String(""),
+					Kind:   String("connect-proxy"),
+				},
+			},
+		},
+		{
+			"invalid_kind",
+			true,
+			&CatalogServicesConditionConfig{
+				CatalogServicesMonitorConfig{
+					Regexp: String(""),
+					Kind:   String("sidecar"),
+				},
+			},
+		},
+		{
+			"valid_instances",
+			false,
+			&CatalogServicesConditionConfig{
+				CatalogServicesMonitorConfig{
+					Regexp:       String(""),
+					MinInstances: Int(2),
+					MaxInstances: Int(10),
+				},
+			},
+		},
+		{
+			"valid_max_instances_only",
+			false,
+			&CatalogServicesConditionConfig{
+				CatalogServicesMonitorConfig{
+					Regexp:       String(""),
+					MinInstances: Int(0),
+					MaxInstances: Int(10),
+				},
+			},
+		},
+		{
+			"negative_instances",
+			true,
+			&CatalogServicesConditionConfig{
+				CatalogServicesMonitorConfig{
+					Regexp:       String(""),
+					MinInstances: Int(-1),
+				},
+			},
+		},
+		{
+			"min_instances_greater_than_max",
+			true,
+			&CatalogServicesConditionConfig{
+				CatalogServicesMonitorConfig{
+					Regexp:       String(""),
+					MinInstances: Int(10),
+					MaxInstances: Int(2),
+				},
+			},
+		},
+		{
+			"instances_with_trigger_on_tag_changes",
+			true,
+			&CatalogServicesConditionConfig{
+				CatalogServicesMonitorConfig{
+					Regexp:              String(""),
+					TriggerOnTagChanges: Bool(true),
+					MaxInstances:        Int(10),
+				},
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -5,6 +5,7 @@ package config
 
 import (
 	"fmt"
+	"strings"
 )
 
 const consulKVType = "consul-kv"
@@ -15,7 +16,12 @@ var _ MonitorConfig = (*ConsulKVMonitorConfig)(nil)
 // of type 'consul-kv'. A consul-kv monitor watches for changes
 // that occur in the consul kv.
 type ConsulKVMonitorConfig struct {
-	Path       *string `mapstructure:"path" json:"path"`
+	Path *string `mapstructure:"path" json:"path"`
+
+	// Paths is the list of paths to monitor as an alternative to Path. The
+	// key-values of the paths are combined into a single consul_kv variable.
+	Paths []string `mapstructure:"paths" json:"paths"`
+
 	Recurse    *bool   `mapstructure:"recurse" json:"recurse"`
 	Datacenter *string `mapstructure:"datacenter" json:"datacenter"`
 	Namespace  *string `mapstructure:"namespace" json:"namespace"`
@@ -25,6 +31,13 @@ func (c *ConsulKVMonitorConfig) VariableType() string {
 	return "consul_kv"
 }
 
+func (c *ConsulKVMonitorConfig) datacenter() string {
+	if c == nil {
+		return ""
+	}
+	return StringVal(c.Datacenter)
+}
+
 // Copy returns a deep copy of this configuration.
 func (c *ConsulKVMonitorConfig) Copy() MonitorConfig {
 	if c == nil {
@@ -33,6 +46,10 @@ func (c *ConsulKVMonitorConfig) Copy() MonitorConfig {
 
 	var o ConsulKVMonitorConfig
 	o.Path = StringCopy(c.Path)
+	if c.Paths != nil {
+		o.Paths = make([]string, 0, len(c.Paths))
+		o.Paths = append(o.Paths, c.Paths...)
+	}
 	o.Recurse = BoolCopy(c.Recurse)
 	o.Datacenter = StringCopy(c.Datacenter)
 	o.Namespace = StringCopy(c.Namespace)
@@ -66,6 +83,8 @@ func (c *ConsulKVMonitorConfig) Merge(o MonitorConfig) MonitorConfig {
 		r2.Path = StringCopy(o2.Path)
 	}
 
+	r2.Paths = mergeSlices(r2.Paths, o2.Paths)
+
 	if o2.Recurse != nil {
 		r2.Recurse = BoolCopy(o2.Recurse)
 	}
@@ -91,6 +110,10 @@ func (c *ConsulKVMonitorConfig) Finalize() {
 		c.Path = String("")
 	}
 
+	if c.Paths == nil {
+		c.Paths = []string{}
+	}
+
 	if c.Recurse == nil {
 		c.Recurse = Bool(false)
 	}
@@ -112,10 +135,55 @@ func (c *ConsulKVMonitorConfig) Validate() error {
 		return nil
 	}
 
-	if c.Path == nil || *c.Path == "" {
-		return fmt.Errorf("path is required for consul-kv condition")
+	hasPath := c.Path != nil && *c.Path != ""
+	switch {
+	case hasPath && len(c.Paths) > 0:
+		return fmt.Errorf("path and paths cannot both be configured for " +
+			"consul-kv condition")
+	case !hasPath && len(c.Paths) == 0:
+		return fmt.Errorf("path or paths is required for consul-kv condition")
 	}
 
+	seen := make(map[string]bool, len(c.Paths))
+	for _, p := range c.Paths {
+		if p == "" {
+			return fmt.Errorf("paths for consul-kv condition cannot include " +
+				"an empty path")
+		}
+		if seen[p] {
+			return fmt.Errorf("paths for consul-kv condition includes the "+
+				"duplicate path %q", p)
+		}
+		seen[p] = true
+	}
+
+	// Recursive paths that overlap would render the same key-values twice
+	if BoolVal(c.Recurse) {
+		for _, a := range c.Paths {
+			for _, b := range c.Paths {
+				if a != b && strings.HasPrefix(b, a) {
+					return fmt.Errorf("paths %q and %q for consul-kv condition "+
+						"overlap when recurse is true", a, b)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// AllPaths returns the paths that are monitored, either the path or the
+// list of paths
+func (c *ConsulKVMonitorConfig) AllPaths() []string {
+	if c == nil {
+		return nil
+	}
+	if len(c.Paths) > 0 {
+		return c.Paths
+	}
+	if c.Path != nil && *c.Path != "" {
+		return []string{*c.Path}
+	}
 	return nil
 }
 
@@ -127,11 +195,13 @@ func (c *ConsulKVMonitorConfig) GoString() string {
 
 	return fmt.Sprintf("&ConsulKVMonitorConfig{"+
 		"Path:%s, "+
+		"Paths:%v, "+
 		"Recurse:%v, "+
 		"Datacenter:%v, "+
 		"Namespace:%v, "+
 		"}",
 		StringVal(c.Path),
+		c.Paths,
 		BoolVal(c.Recurse),
 		StringVal(c.Datacenter),
 		StringVal(c.Namespace),
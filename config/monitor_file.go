@@ -0,0 +1,163 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	fileType = "file"
+
+	// DefaultFileInterval is the default interval between checking local
+	// files for changes
+	DefaultFileInterval = 10 * time.Second
+
+	// minFileInterval is the minimum allowed interval between checking local
+	// files for changes
+	minFileInterval = 1 * time.Second
+)
+
+var _ MonitorConfig = (*FileMonitorConfig)(nil)
+
+// FileMonitorConfig configures a configuration block adhering to the monitor
+// interface of type 'file'. A file monitor periodically hashes local files
+// and watches for changes to their contents.
+type FileMonitorConfig struct {
+	// Paths is the list of absolute paths of local files or directories to
+	// watch. For directories, the regular files directly within the directory
+	// are watched.
+	Paths []string `mapstructure:"paths" json:"paths"`
+
+	// Interval is the period of time to wait between checking the files for
+	// changes.
+	Interval *time.Duration `mapstructure:"interval" json:"interval"`
+
+	// IncludeContent determines whether the content of the files is passed
+	// to the module. When false, only the paths and hashes are passed.
+	IncludeContent *bool `mapstructure:"include_content" json:"include_content"`
+}
+
+func (c *FileMonitorConfig) VariableType() string {
+	return "files"
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *FileMonitorConfig) Copy() MonitorConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o FileMonitorConfig
+	if c.Paths != nil {
+		o.Paths = make([]string, 0, len(c.Paths))
+		o.Paths = append(o.Paths, c.Paths...)
+	}
+	o.Interval = TimeDurationCopy(c.Interval)
+	o.IncludeContent = BoolCopy(c.IncludeContent)
+
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+func (c *FileMonitorConfig) Merge(o MonitorConfig) MonitorConfig {
+	if c == nil {
+		if isConditionNil(o) { // o is interface, use isConditionNil()
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if isConditionNil(o) {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+	o2, ok := o.(*FileMonitorConfig)
+	if !ok {
+		return r
+	}
+
+	r2 := r.(*FileMonitorConfig)
+
+	r2.Paths = mergeSlices(r2.Paths, o2.Paths)
+
+	if o2.Interval != nil {
+		r2.Interval = TimeDurationCopy(o2.Interval)
+	}
+
+	if o2.IncludeContent != nil {
+		r2.IncludeContent = BoolCopy(o2.IncludeContent)
+	}
+
+	return r2
+}
+
+// Finalize ensures there no nil pointers.
+func (c *FileMonitorConfig) Finalize() {
+	if c == nil { // config not required, return early
+		return
+	}
+
+	if c.Paths == nil {
+		c.Paths = []string{}
+	}
+
+	if c.Interval == nil {
+		c.Interval = TimeDuration(DefaultFileInterval)
+	}
+
+	if c.IncludeContent == nil {
+		c.IncludeContent = Bool(true)
+	}
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *FileMonitorConfig) Validate() error {
+	if c == nil { // config not required, return early
+		return nil
+	}
+
+	if len(c.Paths) == 0 {
+		return fmt.Errorf("paths is required for file monitor")
+	}
+
+	for _, p := range c.Paths {
+		if strings.TrimSpace(p) == "" {
+			return fmt.Errorf("paths for file monitor cannot contain an empty path")
+		}
+		if !filepath.IsAbs(p) {
+			return fmt.Errorf("path %q for file monitor must be an absolute path", p)
+		}
+	}
+
+	if c.Interval != nil && *c.Interval < minFileInterval {
+		return fmt.Errorf("interval for file monitor must be at least %s, "+
+			"got %s", minFileInterval, *c.Interval)
+	}
+
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *FileMonitorConfig) GoString() string {
+	if c == nil {
+		return "(*FileMonitorConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&FileMonitorConfig{"+
+		"Paths:%v, "+
+		"Interval:%s, "+
+		"IncludeContent:%v"+
+		"}",
+		c.Paths,
+		TimeDurationVal(c.Interval),
+		BoolVal(c.IncludeContent),
+	)
+}
@@ -6,6 +6,7 @@ package config
 import (
 	"fmt"
 	"regexp"
+	"strings"
 )
 
 const servicesType = "services"
@@ -18,12 +19,15 @@ var _ MonitorConfig = (*ServicesMonitorConfig)(nil)
 // deprecated ServiceConfig
 type ServicesMonitorConfig struct {
 	// Regexp configures the services to monitor by matching on the service name.
-	// Either Regexp or Names must be configured, not both. When Regexp is unset,
-	// it will retain a nil value even after Finalize().
+	// Regexp, Names, or both must be configured. When both are configured, the
+	// services monitored are the union of the services matching Regexp and the
+	// services listed in Names. When Regexp is unset, it will retain a nil
+	// value even after Finalize().
 	Regexp *string `mapstructure:"regexp" json:"regexp"`
 
 	// Names configures the services to monitor by listing the service name.
-	// Either Regexp or Names must be configured, not both.
+	// Regexp, Names, or both must be configured. Names merged from multiple
+	// configurations are deduplicated, keeping the first occurrence.
 	Names []string `mapstructure:"names" json:"names"`
 
 	// Datacenter is the datacenter the service is deployed in.
@@ -41,12 +45,39 @@ type ServicesMonitorConfig struct {
 	// CTSUserDefinedMeta is metadata added to a service automated by CTS for
 	// network infrastructure automation.
 	CTSUserDefinedMeta map[string]string `mapstructure:"cts_user_defined_meta" json:"cts_user_defined_meta"`
+
+	// IncludeExtendedMetadata configures whether the services variable
+	// includes extended metadata for each service instance, such as weights.
+	// Disabled by default to avoid changing the variable type for existing
+	// modules.
+	IncludeExtendedMetadata *bool `mapstructure:"include_extended_metadata" json:"include_extended_metadata"`
+
+	// Query configures the services to monitor by the name or ID of a Consul
+	// prepared query. The services are the results of executing the query,
+	// which reflect the failover resolution of the query. Query cannot be
+	// configured with Regexp, Names, Namespace, or Filter.
+	Query *string `mapstructure:"query" json:"query"`
+
+	// Bootstrap configures whether the initial load of the services
+	// monitored by Regexp fetches the health of the matching services
+	// concurrently, before switching to blocking queries. This reduces the
+	// startup time of a regexp that matches many services, though one request
+	// per matching service is still made. Bootstrap requires Regexp to be
+	// configured.
+	Bootstrap *bool `mapstructure:"bootstrap" json:"bootstrap"`
 }
 
 func (c *ServicesMonitorConfig) VariableType() string {
 	return "services"
 }
 
+func (c *ServicesMonitorConfig) datacenter() string {
+	if c == nil {
+		return ""
+	}
+	return StringVal(c.Datacenter)
+}
+
 // Copy returns a deep copy of this configuration.
 func (c *ServicesMonitorConfig) Copy() MonitorConfig {
 	if c == nil {
@@ -74,6 +105,11 @@ func (c *ServicesMonitorConfig) Copy() MonitorConfig {
 		}
 	}
 
+	o.IncludeExtendedMetadata = BoolCopy(c.IncludeExtendedMetadata)
+
+	o.Query = StringCopy(c.Query)
+	o.Bootstrap = BoolCopy(c.Bootstrap)
+
 	return &o
 }
 
@@ -124,6 +160,15 @@ func (c *ServicesMonitorConfig) Merge(o MonitorConfig) MonitorConfig {
 			r2.CTSUserDefinedMeta[k] = v
 		}
 	}
+	if o2.IncludeExtendedMetadata != nil {
+		r2.IncludeExtendedMetadata = BoolCopy(o2.IncludeExtendedMetadata)
+	}
+	if o2.Query != nil {
+		r2.Query = StringCopy(o2.Query)
+	}
+	if o2.Bootstrap != nil {
+		r2.Bootstrap = BoolCopy(o2.Bootstrap)
+	}
 
 	return r2
 }
@@ -143,6 +188,7 @@ func (c *ServicesMonitorConfig) Finalize() {
 	if c.Names == nil {
 		c.Names = []string{}
 	}
+	c.Names = dedupeNames(c.Names)
 	if c.Datacenter == nil {
 		c.Datacenter = String("")
 	}
@@ -155,6 +201,15 @@ func (c *ServicesMonitorConfig) Finalize() {
 	if c.CTSUserDefinedMeta == nil {
 		c.CTSUserDefinedMeta = make(map[string]string)
 	}
+	if c.IncludeExtendedMetadata == nil {
+		c.IncludeExtendedMetadata = Bool(false)
+	}
+	if c.Query == nil {
+		c.Query = String("")
+	}
+	if c.Bootstrap == nil {
+		c.Bootstrap = Bool(false)
+	}
 }
 
 // Validate validates the values and required options. This method is recommended
@@ -165,16 +220,25 @@ func (c *ServicesMonitorConfig) Validate() error {
 		return nil
 	}
 
-	// Check that either regex or names is configured but not both
+	// Check that regex, names, or both are configured, or a query
 	namesConfigured := c.Names != nil && len(c.Names) > 0
 	regexConfigured := c.Regexp != nil
-	if namesConfigured && regexConfigured {
-		return fmt.Errorf("regexp and names fields cannot both be " +
-			"unconfigured. If both are needed, consider including the list of " +
-			"names as part of the regex or creating separate tasks")
+	if BoolVal(c.Bootstrap) && !regexConfigured {
+		return fmt.Errorf("the bootstrap field requires the regexp field to be configured")
+	}
+	if StringVal(c.Query) != "" {
+		if namesConfigured || regexConfigured {
+			return fmt.Errorf("the query field cannot be configured with the " +
+				"regexp or names fields")
+		}
+		if StringVal(c.Namespace) != "" || StringVal(c.Filter) != "" {
+			return fmt.Errorf("the query field cannot be configured with the " +
+				"namespace or filter fields. Configure them in the prepared query")
+		}
+		return nil
 	}
 	if !namesConfigured && !regexConfigured {
-		return fmt.Errorf("either the regexp or names field must be configured")
+		return fmt.Errorf("either the regexp, names, or query field must be configured")
 	}
 
 	// Validate regex
@@ -197,6 +261,26 @@ func (c *ServicesMonitorConfig) Validate() error {
 	return nil
 }
 
+// CombinedRegexp returns the regular expression that matches the union of
+// the services configured by Regexp and Names. Returns nil if only Names is
+// configured, since the services can then be monitored by name.
+func (c *ServicesMonitorConfig) CombinedRegexp() *string {
+	if c == nil || c.Regexp == nil {
+		return nil
+	}
+
+	if len(c.Names) == 0 {
+		return StringCopy(c.Regexp)
+	}
+
+	names := make([]string, 0, len(c.Names))
+	for _, name := range dedupeNames(c.Names) {
+		names = append(names, regexp.QuoteMeta(name))
+	}
+	return String(fmt.Sprintf("(?:%s)|^(?:%s)$", *c.Regexp,
+		strings.Join(names, "|")))
+}
+
 // GoString defines the printable version of this struct.
 func (c *ServicesMonitorConfig) GoString() string {
 	if c == nil {
@@ -209,7 +293,10 @@ func (c *ServicesMonitorConfig) GoString() string {
 		"Datacenter:%s, "+
 		"Namespace:%s, "+
 		"Filter:%s, "+
-		"CTSUserDefinedMeta:%s"+
+		"CTSUserDefinedMeta:%s, "+
+		"IncludeExtendedMetadata:%v, "+
+		"Query:%s, "+
+		"Bootstrap:%v"+
 		"}",
 		StringVal(c.Regexp),
 		c.Names,
@@ -217,5 +304,23 @@ func (c *ServicesMonitorConfig) GoString() string {
 		StringVal(c.Namespace),
 		StringVal(c.Filter),
 		c.CTSUserDefinedMeta,
+		BoolVal(c.IncludeExtendedMetadata),
+		StringVal(c.Query),
+		BoolVal(c.Bootstrap),
 	)
 }
+
+// dedupeNames returns the names with duplicates removed, keeping the order of
+// the first occurrence of each name
+func dedupeNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	deduped := make([]string, 0, len(names))
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		deduped = append(deduped, name)
+	}
+	return deduped
+}
@@ -4,6 +4,7 @@
 package config
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -54,6 +55,21 @@ func TestServicesMonitorConfig_Copy(t *testing.T) {
 				CTSUserDefinedMeta: map[string]string{
 					"key": "value",
 				},
+				IncludeExtendedMetadata: Bool(true),
+			},
+		},
+		{
+			"query_configured",
+			&ServicesMonitorConfig{
+				Query:      String("nearest-api"),
+				Datacenter: String("dc"),
+			},
+		},
+		{
+			"bootstrap_configured",
+			&ServicesMonitorConfig{
+				Regexp:    String(".*"),
+				Bootstrap: Bool(true),
 			},
 		},
 	}
@@ -248,6 +264,30 @@ func TestServicesMonitorConfig_Merge(t *testing.T) {
 			&ServicesMonitorConfig{CTSUserDefinedMeta: map[string]string{"key": "value"}},
 			&ServicesMonitorConfig{CTSUserDefinedMeta: map[string]string{"key": "value"}},
 		},
+		{
+			"include_extended_metadata_overrides",
+			&ServicesMonitorConfig{IncludeExtendedMetadata: Bool(false)},
+			&ServicesMonitorConfig{IncludeExtendedMetadata: Bool(true)},
+			&ServicesMonitorConfig{IncludeExtendedMetadata: Bool(true)},
+		},
+		{
+			"include_extended_metadata_empty_one",
+			&ServicesMonitorConfig{IncludeExtendedMetadata: Bool(true)},
+			&ServicesMonitorConfig{},
+			&ServicesMonitorConfig{IncludeExtendedMetadata: Bool(true)},
+		},
+		{
+			"query_overrides",
+			&ServicesMonitorConfig{Query: String("nearest-api")},
+			&ServicesMonitorConfig{Query: String("failover-api")},
+			&ServicesMonitorConfig{Query: String("failover-api")},
+		},
+		{
+			"bootstrap_overrides",
+			&ServicesMonitorConfig{Bootstrap: Bool(true)},
+			&ServicesMonitorConfig{Bootstrap: Bool(false)},
+			&ServicesMonitorConfig{Bootstrap: Bool(false)},
+		},
 	}
 
 	for _, tc := range cases {
@@ -280,12 +320,15 @@ func TestServicesMonitorConfig_Finalize(t *testing.T) {
 			"empty",
 			&ServicesMonitorConfig{},
 			&ServicesMonitorConfig{
-				Regexp:             nil,
-				Names:              []string{},
-				Datacenter:         String(""),
-				Namespace:          String(""),
-				Filter:             String(""),
-				CTSUserDefinedMeta: map[string]string{},
+				Regexp:                  nil,
+				Names:                   []string{},
+				Datacenter:              String(""),
+				Namespace:               String(""),
+				Filter:                  String(""),
+				CTSUserDefinedMeta:      map[string]string{},
+				IncludeExtendedMetadata: Bool(false),
+				Query:                   String(""),
+				Bootstrap:               Bool(false),
 			},
 		},
 		{
@@ -308,6 +351,9 @@ func TestServicesMonitorConfig_Finalize(t *testing.T) {
 				CTSUserDefinedMeta: map[string]string{
 					"key": "value",
 				},
+				IncludeExtendedMetadata: Bool(false),
+				Query:                   String(""),
+				Bootstrap:               Bool(false),
 			},
 		},
 		{
@@ -330,6 +376,25 @@ func TestServicesMonitorConfig_Finalize(t *testing.T) {
 				CTSUserDefinedMeta: map[string]string{
 					"key": "value",
 				},
+				IncludeExtendedMetadata: Bool(false),
+				Query:                   String(""),
+				Bootstrap:               Bool(false),
+			},
+		},
+		{
+			"duplicate_names",
+			&ServicesMonitorConfig{
+				Names: []string{"web", "api", "web"},
+			},
+			&ServicesMonitorConfig{
+				Names:                   []string{"web", "api"},
+				Datacenter:              String(""),
+				Namespace:               String(""),
+				Filter:                  String(""),
+				CTSUserDefinedMeta:      map[string]string{},
+				IncludeExtendedMetadata: Bool(false),
+				Query:                   String(""),
+				Bootstrap:               Bool(false),
 			},
 		},
 	}
@@ -391,8 +456,8 @@ func TestServicesMonitorConfig_Validate(t *testing.T) {
 			},
 		},
 		{
-			"invalid_both_regexp_and_names_configured",
-			true,
+			"valid_both_regexp_and_names_configured",
+			false,
 			&ServicesMonitorConfig{
 				Regexp: String(".*"),
 				Names:  []string{"api"},
@@ -403,6 +468,54 @@ func TestServicesMonitorConfig_Validate(t *testing.T) {
 			true,
 			&ServicesMonitorConfig{},
 		},
+		{
+			"valid_query",
+			false,
+			&ServicesMonitorConfig{
+				Query:      String("nearest-api"),
+				Datacenter: String("dc1"),
+			},
+		},
+		{
+			"invalid_query_and_names",
+			true,
+			&ServicesMonitorConfig{
+				Query: String("nearest-api"),
+				Names: []string{"api"},
+			},
+		},
+		{
+			"invalid_query_and_regexp",
+			true,
+			&ServicesMonitorConfig{
+				Query:  String("nearest-api"),
+				Regexp: String(".*"),
+			},
+		},
+		{
+			"invalid_query_and_filter",
+			true,
+			&ServicesMonitorConfig{
+				Query:  String("nearest-api"),
+				Filter: String("Service.Tags contains \"a\""),
+			},
+		},
+		{
+			"valid_bootstrap",
+			false,
+			&ServicesMonitorConfig{
+				Regexp:    String("^web.*"),
+				Bootstrap: Bool(true),
+			},
+		},
+		{
+			"invalid_bootstrap_names_only",
+			true,
+			&ServicesMonitorConfig{
+				Names:     []string{"api"},
+				Bootstrap: Bool(true),
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -417,6 +530,60 @@ func TestServicesMonitorConfig_Validate(t *testing.T) {
 	}
 }
 
+func TestServicesMonitorConfig_CombinedRegexp(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		c        *ServicesMonitorConfig
+		expected *string
+	}{
+		{
+			"nil",
+			nil,
+			nil,
+		},
+		{
+			"names_only",
+			&ServicesMonitorConfig{Names: []string{"api"}},
+			nil,
+		},
+		{
+			"regexp_only",
+			&ServicesMonitorConfig{Regexp: String("^web.*")},
+			String("^web.*"),
+		},
+		{
+			"regexp_and_names",
+			&ServicesMonitorConfig{
+				Regexp: String("^web.*"),
+				Names:  []string{"api", "db.v2", "api"},
+			},
+			String(`(?:^web.*)|^(?:api|db\.v2)$`),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.c.CombinedRegexp())
+		})
+	}
+
+	t.Run("union", func(t *testing.T) {
+		c := &ServicesMonitorConfig{
+			Regexp: String("^web"),
+			Names:  []string{"api", "db.v2"},
+		}
+		re := regexp.MustCompile(*c.CombinedRegexp())
+		for _, name := range []string{"web", "web-2", "api", "db.v2"} {
+			assert.True(t, re.MatchString(name), name)
+		}
+		for _, name := range []string{"api-2", "db-v2", "my-web"} {
+			assert.False(t, re.MatchString(name), name)
+		}
+	})
+}
+
 func TestServicesMonitorConfig_GoString(t *testing.T) {
 	t.Parallel()
 
@@ -440,10 +607,11 @@ func TestServicesMonitorConfig_GoString(t *testing.T) {
 				CTSUserDefinedMeta: map[string]string{
 					"key": "value",
 				},
+				IncludeExtendedMetadata: Bool(true),
 			},
 			"&ServicesMonitorConfig{Regexp:^api$, Names:[], Datacenter:dc, " +
 				"Namespace:namespace, Filter:filter, " +
-				"CTSUserDefinedMeta:map[key:value]}",
+				"CTSUserDefinedMeta:map[key:value], IncludeExtendedMetadata:true, Query:, Bootstrap:false}",
 		},
 		{
 			"names_fully_configured",
@@ -458,7 +626,7 @@ func TestServicesMonitorConfig_GoString(t *testing.T) {
 			},
 			"&ServicesMonitorConfig{Regexp:, Names:[api web], Datacenter:dc, " +
 				"Namespace:namespace, Filter:filter, " +
-				"CTSUserDefinedMeta:map[key:value]}",
+				"CTSUserDefinedMeta:map[key:value], IncludeExtendedMetadata:false, Query:, Bootstrap:false}",
 		},
 	}
 
@@ -0,0 +1,116 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// DefaultPlanArtifactsPath is the default directory where plan artifacts
+	// are stored when plan artifacts are enabled.
+	DefaultPlanArtifactsPath = "plan-artifacts"
+)
+
+// PlanArtifactsConfig is the configuration for persisting the Terraform plan
+// of each task run. When enabled, tasks save their plan before applying and
+// the plan file and its JSON representation are stored for auditing.
+type PlanArtifactsConfig struct {
+	Enabled *bool   `mapstructure:"enabled" json:"enabled"`
+	Path    *string `mapstructure:"path" json:"path"`
+}
+
+// DefaultPlanArtifactsConfig returns the default configuration struct.
+func DefaultPlanArtifactsConfig() *PlanArtifactsConfig {
+	return &PlanArtifactsConfig{
+		// No default values. `Enabled` value depends on other fields as
+		// handled in Finalize()
+	}
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *PlanArtifactsConfig) Copy() *PlanArtifactsConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o PlanArtifactsConfig
+	o.Enabled = BoolCopy(c.Enabled)
+	o.Path = StringCopy(c.Path)
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+// Maps and slices are merged, most other values are overwritten. Complex
+// structs define their own merge functionality.
+func (c *PlanArtifactsConfig) Merge(o *PlanArtifactsConfig) *PlanArtifactsConfig {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+
+	if o.Enabled != nil {
+		r.Enabled = BoolCopy(o.Enabled)
+	}
+
+	if o.Path != nil {
+		r.Path = StringCopy(o.Path)
+	}
+
+	return r
+}
+
+// Finalize ensures there no nil pointers.
+func (c *PlanArtifactsConfig) Finalize() {
+	if c == nil {
+		return
+	}
+
+	if c.Enabled == nil {
+		c.Enabled = Bool(StringPresent(c.Path))
+	}
+
+	if c.Path == nil {
+		c.Path = String(DefaultPlanArtifactsPath)
+	}
+}
+
+// Validate validates the values and nested values of the configuration struct
+func (c *PlanArtifactsConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+
+	if BoolVal(c.Enabled) && !StringPresent(c.Path) {
+		return errors.New("plan_artifacts: path is required when plan " +
+			"artifacts are enabled")
+	}
+
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *PlanArtifactsConfig) GoString() string {
+	if c == nil {
+		return "(*PlanArtifactsConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&PlanArtifactsConfig{"+
+		"Enabled:%t, "+
+		"Path:%s"+
+		"}",
+		BoolVal(c.Enabled),
+		StringVal(c.Path),
+	)
+}
@@ -0,0 +1,199 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanArtifactsConfig_Copy(t *testing.T) {
+	t.Parallel()
+
+	finalizedConf := &PlanArtifactsConfig{}
+	finalizedConf.Finalize()
+
+	cases := []struct {
+		name string
+		a    *PlanArtifactsConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&PlanArtifactsConfig{},
+		},
+		{
+			"finalized",
+			finalizedConf,
+		},
+		{
+			"fields",
+			&PlanArtifactsConfig{
+				Enabled: Bool(true),
+				Path:    String("/var/cts/plans"),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Copy()
+			assert.Equal(t, tc.a, r)
+		})
+	}
+}
+
+func TestPlanArtifactsConfig_Merge(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *PlanArtifactsConfig
+		b    *PlanArtifactsConfig
+		r    *PlanArtifactsConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&PlanArtifactsConfig{},
+			&PlanArtifactsConfig{},
+		},
+		{
+			"nil_b",
+			&PlanArtifactsConfig{},
+			nil,
+			&PlanArtifactsConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"enabled_overrides",
+			&PlanArtifactsConfig{Enabled: Bool(true)},
+			&PlanArtifactsConfig{Enabled: Bool(false)},
+			&PlanArtifactsConfig{Enabled: Bool(false)},
+		},
+		{
+			"enabled_empty_one",
+			&PlanArtifactsConfig{Enabled: Bool(true)},
+			&PlanArtifactsConfig{},
+			&PlanArtifactsConfig{Enabled: Bool(true)},
+		},
+		{
+			"path_overrides",
+			&PlanArtifactsConfig{Path: String("path")},
+			&PlanArtifactsConfig{Path: String("other")},
+			&PlanArtifactsConfig{Path: String("other")},
+		},
+		{
+			"path_empty_two",
+			&PlanArtifactsConfig{},
+			&PlanArtifactsConfig{Path: String("path")},
+			&PlanArtifactsConfig{Path: String("path")},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			assert.Equal(t, tc.r, r)
+		})
+	}
+}
+
+func TestPlanArtifactsConfig_Finalize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		i    *PlanArtifactsConfig
+		r    *PlanArtifactsConfig
+	}{
+		{
+			"empty",
+			&PlanArtifactsConfig{},
+			&PlanArtifactsConfig{
+				Enabled: Bool(false),
+				Path:    String(DefaultPlanArtifactsPath),
+			},
+		},
+		{
+			"enabled",
+			&PlanArtifactsConfig{
+				Enabled: Bool(true),
+			},
+			&PlanArtifactsConfig{
+				Enabled: Bool(true),
+				Path:    String(DefaultPlanArtifactsPath),
+			},
+		},
+		{
+			"with_path",
+			&PlanArtifactsConfig{
+				Path: String("/var/cts/plans"),
+			},
+			&PlanArtifactsConfig{
+				Enabled: Bool(true),
+				Path:    String("/var/cts/plans"),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			tc.i.Finalize()
+			assert.Equal(t, tc.r, tc.i)
+		})
+	}
+}
+
+func TestPlanArtifactsConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		i       *PlanArtifactsConfig
+		isValid bool
+	}{
+		{
+			"nil",
+			nil,
+			true,
+		},
+		{
+			"disabled",
+			&PlanArtifactsConfig{Enabled: Bool(false), Path: String("")},
+			true,
+		},
+		{
+			"enabled",
+			&PlanArtifactsConfig{Enabled: Bool(true), Path: String("plans")},
+			true,
+		},
+		{
+			"enabled_missing_path",
+			&PlanArtifactsConfig{Enabled: Bool(true), Path: String("")},
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			err := tc.i.Validate()
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
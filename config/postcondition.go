@@ -0,0 +1,228 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Postcondition operators to compare a Terraform output to a value
+const (
+	PostconditionOperatorEqual              = "=="
+	PostconditionOperatorNotEqual           = "!="
+	PostconditionOperatorGreaterThan        = ">"
+	PostconditionOperatorGreaterThanOrEqual = ">="
+	PostconditionOperatorLessThan           = "<"
+	PostconditionOperatorLessThanOrEqual    = "<="
+)
+
+var postconditionOperators = []string{
+	PostconditionOperatorEqual,
+	PostconditionOperatorNotEqual,
+	PostconditionOperatorGreaterThan,
+	PostconditionOperatorGreaterThanOrEqual,
+	PostconditionOperatorLessThan,
+	PostconditionOperatorLessThanOrEqual,
+}
+
+// PostconditionConfig configures an assertion on a Terraform output of the
+// task's module that is evaluated after each apply. The task run fails if the
+// assertion does not hold.
+//
+// Number outputs are compared numerically to the value. List, set, tuple, map,
+// and object outputs compare their number of elements to the value. String
+// and bool outputs only support the == and != operators.
+type PostconditionConfig struct {
+	// Output is the name of the Terraform output of the module
+	Output *string `mapstructure:"output" json:"output"`
+
+	// Operator is the operator to compare the output to the value. Supported
+	// operators are ==, !=, >, >=, <, and <=.
+	Operator *string `mapstructure:"operator" json:"operator"`
+
+	// Value is the value to compare the output to
+	Value *string `mapstructure:"value" json:"value"`
+
+	// ErrorMessage is an optional message to include in the error when the
+	// postcondition fails
+	ErrorMessage *string `mapstructure:"error_message" json:"error_message"`
+}
+
+// PostconditionConfigs is a collection of PostconditionConfig
+type PostconditionConfigs []*PostconditionConfig
+
+// Copy returns a deep copy of this configuration.
+func (c *PostconditionConfig) Copy() *PostconditionConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o PostconditionConfig
+	o.Output = StringCopy(c.Output)
+	o.Operator = StringCopy(c.Operator)
+	o.Value = StringCopy(c.Value)
+	o.ErrorMessage = StringCopy(c.ErrorMessage)
+	return &o
+}
+
+// Finalize ensures there are no nil pointers.
+func (c *PostconditionConfig) Finalize() {
+	if c == nil {
+		return
+	}
+
+	if c.Output == nil {
+		c.Output = String("")
+	}
+
+	if c.Operator == nil {
+		c.Operator = String("")
+	}
+
+	if c.Value == nil {
+		c.Value = String("")
+	}
+
+	if c.ErrorMessage == nil {
+		c.ErrorMessage = String("")
+	}
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *PostconditionConfig) Validate() error {
+	if c == nil {
+		return fmt.Errorf("postcondition: missing configuration")
+	}
+
+	if StringVal(c.Output) == "" {
+		return fmt.Errorf("postcondition: output is required")
+	}
+
+	operator := StringVal(c.Operator)
+	valid := false
+	for _, op := range postconditionOperators {
+		if operator == op {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("postcondition: unsupported operator %q for output "+
+			"%q, supported operators are: %s", operator, StringVal(c.Output),
+			strings.Join(postconditionOperators, ", "))
+	}
+
+	if StringVal(c.Value) == "" {
+		return fmt.Errorf("postcondition: value is required for output %q",
+			StringVal(c.Output))
+	}
+
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *PostconditionConfig) GoString() string {
+	if c == nil {
+		return "(*PostconditionConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&PostconditionConfig{"+
+		"Output:%s, "+
+		"Operator:%s, "+
+		"Value:%s, "+
+		"ErrorMessage:%s"+
+		"}",
+		StringVal(c.Output),
+		StringVal(c.Operator),
+		StringVal(c.Value),
+		StringVal(c.ErrorMessage),
+	)
+}
+
+// Len is a helper method to get the length of the underlying config list
+func (c *PostconditionConfigs) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	return len(*c)
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *PostconditionConfigs) Copy() *PostconditionConfigs {
+	if c == nil {
+		return nil
+	}
+
+	o := make(PostconditionConfigs, c.Len())
+	for i, p := range *c {
+		o[i] = p.Copy()
+	}
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration. Postconditions of the other configuration are appended.
+func (c *PostconditionConfigs) Merge(o *PostconditionConfigs) *PostconditionConfigs {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+	for _, p := range *o {
+		*r = append(*r, p.Copy())
+	}
+
+	return r
+}
+
+// Finalize ensures the configuration has no nil pointers.
+func (c *PostconditionConfigs) Finalize() {
+	if c == nil {
+		return
+	}
+
+	for _, p := range *c {
+		p.Finalize()
+	}
+}
+
+// Validate validates the values and nested values of the configuration struct.
+func (c *PostconditionConfigs) Validate() error {
+	if c == nil {
+		// config is not required, return early
+		return nil
+	}
+
+	for _, p := range *c {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *PostconditionConfigs) GoString() string {
+	if c == nil {
+		return "(*PostconditionConfigs)(nil)"
+	}
+
+	s := make([]string, len(*c))
+	for i, p := range *c {
+		s[i] = p.GoString()
+	}
+
+	return "{" + strings.Join(s, ", ") + "}"
+}
@@ -0,0 +1,173 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostconditionConfigs_Copy(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *PostconditionConfigs
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&PostconditionConfigs{},
+		},
+		{
+			"fully configured",
+			&PostconditionConfigs{
+				{
+					Output:       String("members_count"),
+					Operator:     String(">="),
+					Value:        String("1"),
+					ErrorMessage: String("no members"),
+				},
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Copy()
+			assert.Equal(t, tc.a, r)
+		})
+	}
+}
+
+func TestPostconditionConfigs_Merge(t *testing.T) {
+	t.Parallel()
+
+	a := &PostconditionConfig{Output: String("a"), Operator: String("=="),
+		Value: String("1")}
+	b := &PostconditionConfig{Output: String("b"), Operator: String("!="),
+		Value: String("2")}
+
+	cases := []struct {
+		name string
+		a    *PostconditionConfigs
+		b    *PostconditionConfigs
+		r    *PostconditionConfigs
+	}{
+		{
+			"nil_a",
+			nil,
+			&PostconditionConfigs{a},
+			&PostconditionConfigs{a},
+		},
+		{
+			"nil_b",
+			&PostconditionConfigs{a},
+			nil,
+			&PostconditionConfigs{a},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"appends",
+			&PostconditionConfigs{a},
+			&PostconditionConfigs{b},
+			&PostconditionConfigs{a, b},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			assert.Equal(t, tc.r, r)
+		})
+	}
+}
+
+func TestPostconditionConfig_Finalize(t *testing.T) {
+	t.Parallel()
+
+	c := &PostconditionConfig{Output: String("members_count")}
+	c.Finalize()
+	assert.Equal(t, &PostconditionConfig{
+		Output:       String("members_count"),
+		Operator:     String(""),
+		Value:        String(""),
+		ErrorMessage: String(""),
+	}, c)
+}
+
+func TestPostconditionConfigs_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		i       *PostconditionConfigs
+		isValid bool
+	}{
+		{
+			"nil",
+			nil,
+			true,
+		},
+		{
+			"valid",
+			&PostconditionConfigs{
+				{Output: String("members_count"), Operator: String(">="),
+					Value: String("1")},
+				{Output: String("address"), Operator: String("!="),
+					Value: String("0.0.0.0")},
+			},
+			true,
+		},
+		{
+			"missing output",
+			&PostconditionConfigs{
+				{Output: String(""), Operator: String(">="), Value: String("1")},
+			},
+			false,
+		},
+		{
+			"unsupported operator",
+			&PostconditionConfigs{
+				{Output: String("members_count"), Operator: String("=>"),
+					Value: String("1")},
+			},
+			false,
+		},
+		{
+			"missing value",
+			&PostconditionConfigs{
+				{Output: String("members_count"), Operator: String(">=")},
+			},
+			false,
+		},
+		{
+			"nil postcondition",
+			&PostconditionConfigs{nil},
+			false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.i.Finalize()
+			err := tc.i.Validate()
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -5,12 +5,30 @@ package config
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+
+	"github.com/hashicorp/consul-terraform-sync/internal/decode"
+	"github.com/mitchellh/mapstructure"
 )
 
+// rateLimitKey is the name of the block of a terraform_provider block that
+// configures the rate limit of the runs of tasks that use the provider. The
+// block is enforced by CTS and is not a provider argument.
+const rateLimitKey = "rate_limit"
+
 // TerraformProviderConfigs is an array of configuration for each provider.
 type TerraformProviderConfigs []*TerraformProviderConfig
 
+// ProviderRateLimitConfig configures the maximum frequency of the runs of tasks
+// that use a provider, across all of the tasks. Runs that would exceed the
+// limit are queued after rendering until the provider has capacity.
+type ProviderRateLimitConfig struct {
+	// RunsPerMinute is the maximum number of task runs that apply with the
+	// provider within a minute. Required.
+	RunsPerMinute *int `mapstructure:"runs_per_minute" json:"runs_per_minute"`
+}
+
 // TerraformProviderConfig is a map representing the configuration for a single
 // provider where the key is the name of provider and value is the configuration.
 type TerraformProviderConfig map[string]interface{}
@@ -103,6 +121,25 @@ func (c *TerraformProviderConfigs) Validate() error {
 	return nil
 }
 
+// RateLimits returns the maximum number of task runs per minute for each
+// provider that configures a rate limit, by the provider ID. Assumes that the
+// provider configurations are validated.
+func (c *TerraformProviderConfigs) RateLimits() map[string]int {
+	limits := make(map[string]int)
+	if c == nil {
+		return limits
+	}
+
+	for _, p := range *c {
+		rl, err := p.RateLimit()
+		if err != nil || rl == nil {
+			continue
+		}
+		limits[p.id()] = IntVal(rl.RunsPerMinute)
+	}
+	return limits
+}
+
 // GoString defines the printable version of this struct. Provider configuration
 // is completely redacted since providers will have varying arguments containing
 // secrets
@@ -161,9 +198,65 @@ func (c *TerraformProviderConfig) Validate() error {
 		}
 	}
 
+	rl, err := c.RateLimit()
+	if err != nil {
+		return err
+	}
+	if rl != nil && IntVal(rl.RunsPerMinute) <= 0 {
+		return fmt.Errorf("rate_limit runs_per_minute for provider %s must be "+
+			"greater than 0, got %d", c.id(), IntVal(rl.RunsPerMinute))
+	}
+
 	return nil
 }
 
+// RateLimit decodes the rate_limit block of the provider. Returns nil if the
+// provider does not configure a rate limit.
+func (c *TerraformProviderConfig) RateLimit() (*ProviderRateLimitConfig, error) {
+	if c == nil {
+		return nil, nil
+	}
+
+	var raw interface{}
+	for _, v := range *c {
+		block, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+		raw = block[rateLimitKey]
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	if blocks, ok := raw.([]map[string]interface{}); ok && len(blocks) > 1 {
+		return nil, fmt.Errorf("only one rate_limit block can be configured " +
+			"for a terraform_provider block")
+	}
+
+	var rl ProviderRateLimitConfig
+	var md mapstructure.Metadata
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       decode.HookWeakDecodeFromSlice,
+		WeaklyTypedInput: true,
+		Metadata:         &md,
+		Result:           &rl,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(raw); err != nil {
+		return nil, fmt.Errorf("unexpected rate_limit block format: %s", err)
+	}
+	if len(md.Unused) > 0 {
+		sort.Strings(md.Unused)
+		return nil, fmt.Errorf("invalid keys for rate_limit block: %s",
+			strings.Join(md.Unused, ", "))
+	}
+
+	return &rl, nil
+}
+
 // id returns the unique name to represent the provider configuration. If alias is set,
 // the ID is <name>.<alias>. Otherwise, the name is used as the ID.
 func (c *TerraformProviderConfig) id() string {
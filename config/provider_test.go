@@ -304,6 +304,65 @@ func TestProviderConfigs_Validate(t *testing.T) {
 				},
 			}},
 			false,
+		}, {
+			"rate_limit",
+			&TerraformProviderConfigs{{
+				"panos": map[string]interface{}{
+					"rate_limit": []map[string]interface{}{
+						{"runs_per_minute": 2},
+					},
+				},
+			}},
+			true,
+		}, {
+			"rate_limit json",
+			&TerraformProviderConfigs{{
+				"panos": map[string]interface{}{
+					"rate_limit": map[string]interface{}{
+						"runs_per_minute": float64(2),
+					},
+				},
+			}},
+			true,
+		}, {
+			"rate_limit missing runs_per_minute",
+			&TerraformProviderConfigs{{
+				"panos": map[string]interface{}{
+					"rate_limit": []map[string]interface{}{{}},
+				},
+			}},
+			false,
+		}, {
+			"rate_limit zero",
+			&TerraformProviderConfigs{{
+				"panos": map[string]interface{}{
+					"rate_limit": []map[string]interface{}{
+						{"runs_per_minute": 0},
+					},
+				},
+			}},
+			false,
+		}, {
+			"rate_limit invalid key",
+			&TerraformProviderConfigs{{
+				"panos": map[string]interface{}{
+					"rate_limit": []map[string]interface{}{
+						{"runs_per_minute": 2, "burst": 5},
+					},
+				},
+			}},
+			false,
+		}, {
+			"rate_limit multiple blocks",
+			&TerraformProviderConfigs{{
+				"panos": map[string]interface{}{
+					"rate_limit": []map[string]interface{}{
+						{"runs_per_minute": 2},
+						{"runs_per_minute": 3},
+					},
+				},
+			}},
+			false,
 		},
 	}
 
@@ -319,6 +378,35 @@ func TestProviderConfigs_Validate(t *testing.T) {
 	}
 }
 
+func TestProviderConfigs_RateLimits(t *testing.T) {
+	t.Parallel()
+
+	providers := &TerraformProviderConfigs{{
+		"panos": map[string]interface{}{
+			"rate_limit": []map[string]interface{}{
+				{"runs_per_minute": 2},
+			},
+		},
+	}, {
+		"panos": map[string]interface{}{
+			"alias": "dc2",
+			"rate_limit": map[string]interface{}{
+				"runs_per_minute": float64(5),
+			},
+		},
+	}, {
+		"null": map[string]interface{}{},
+	}}
+
+	assert.Equal(t, map[string]int{
+		"panos":     2,
+		"panos.dc2": 5,
+	}, providers.RateLimits())
+
+	var nilProviders *TerraformProviderConfigs
+	assert.Empty(t, nilProviders.RateLimits())
+}
+
 func TestProviderConfigs_GoString(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,282 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ProxyConfig configures the proxies for outbound HTTP requests of CTS, such
+// as Terraform Cloud API calls, and of Terraform, such as module registry and
+// provider downloads. Requests to HTTPS destinations are tunneled through the
+// proxy with the CONNECT method.
+//
+// When neither proxy is configured, the HTTP_PROXY, HTTPS_PROXY, and NO_PROXY
+// environment variables of the CTS process are used.
+type ProxyConfig struct {
+	// HTTP is the URL of the proxy for requests to HTTP destinations
+	HTTP *string `mapstructure:"http" json:"http"`
+
+	// HTTPS is the URL of the proxy for requests to HTTPS destinations
+	HTTPS *string `mapstructure:"https" json:"https"`
+
+	// NoProxy are the hosts to not use the proxies for. An entry can be a
+	// host name, which also matches its subdomains, an IP address, a CIDR
+	// block, or "*" to match all hosts. Host names and IP addresses can
+	// include a port.
+	NoProxy []string `mapstructure:"no_proxy" json:"no_proxy"`
+}
+
+// DefaultProxyConfig returns a configuration that is populated with the
+// default values.
+func DefaultProxyConfig() *ProxyConfig {
+	return &ProxyConfig{}
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *ProxyConfig) Copy() *ProxyConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o ProxyConfig
+	o.HTTP = StringCopy(c.HTTP)
+	o.HTTPS = StringCopy(c.HTTPS)
+
+	if c.NoProxy != nil {
+		o.NoProxy = make([]string, 0, len(c.NoProxy))
+		o.NoProxy = append(o.NoProxy, c.NoProxy...)
+	}
+
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+// Maps and slices are merged, most other values are overwritten. Complex
+// structs define their own merge functionality.
+func (c *ProxyConfig) Merge(o *ProxyConfig) *ProxyConfig {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+
+	if o.HTTP != nil {
+		r.HTTP = StringCopy(o.HTTP)
+	}
+
+	if o.HTTPS != nil {
+		r.HTTPS = StringCopy(o.HTTPS)
+	}
+
+	r.NoProxy = mergeSlices(r.NoProxy, o.NoProxy)
+
+	return r
+}
+
+// Finalize ensures there no nil pointers.
+func (c *ProxyConfig) Finalize() {
+	if c == nil {
+		return
+	}
+
+	if c.HTTP == nil {
+		c.HTTP = String("")
+	}
+
+	if c.HTTPS == nil {
+		c.HTTPS = String("")
+	}
+
+	if c.NoProxy == nil {
+		c.NoProxy = []string{}
+	}
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *ProxyConfig) Validate() error {
+	if c == nil {
+		// config is not required, return early
+		return nil
+	}
+
+	for name, proxy := range map[string]string{
+		"http":  StringVal(c.HTTP),
+		"https": StringVal(c.HTTPS),
+	} {
+		if proxy == "" {
+			continue
+		}
+		u, err := url.Parse(proxy)
+		if err != nil {
+			return fmt.Errorf("proxy: unable to parse %s proxy URL: %s", name, err)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("proxy: unsupported scheme %q for %s proxy URL, "+
+				"supported schemes are: http, https, socks5", u.Scheme, name)
+		}
+		if u.Host == "" {
+			return fmt.Errorf("proxy: %s proxy URL is missing a host", name)
+		}
+	}
+
+	for _, host := range c.NoProxy {
+		if strings.TrimSpace(host) == "" {
+			return fmt.Errorf("proxy: no_proxy cannot contain empty hosts")
+		}
+	}
+
+	return nil
+}
+
+// Configured returns true if a proxy is configured
+func (c *ProxyConfig) Configured() bool {
+	if c == nil {
+		return false
+	}
+	return StringVal(c.HTTP) != "" || StringVal(c.HTTPS) != ""
+}
+
+// ProxyFunc returns the function for an HTTP transport to select the proxy
+// for a request. The proxy configured for the scheme of the request is
+// selected unless the host matches NoProxy. When no proxy is configured,
+// the proxy is selected from the environment variables.
+func (c *ProxyConfig) ProxyFunc() func(*http.Request) (*url.URL, error) {
+	if !c.Configured() {
+		return http.ProxyFromEnvironment
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		var proxy string
+		switch req.URL.Scheme {
+		case "http":
+			proxy = StringVal(c.HTTP)
+		case "https":
+			proxy = StringVal(c.HTTPS)
+		}
+
+		if proxy == "" || !c.useProxy(req.URL.Host) {
+			return nil, nil
+		}
+		return url.Parse(proxy)
+	}
+}
+
+// Transport returns an HTTP transport for outbound requests that selects the
+// proxy with ProxyFunc and is otherwise the same as the default transport
+func (c *ProxyConfig) Transport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = c.ProxyFunc()
+	return t
+}
+
+// Env returns the environment variables for processes that CTS runs, such as
+// Terraform, to use the configured proxies. Returns nil if no proxy is
+// configured.
+func (c *ProxyConfig) Env() map[string]string {
+	if !c.Configured() {
+		return nil
+	}
+
+	env := make(map[string]string)
+	if proxy := StringVal(c.HTTP); proxy != "" {
+		env["HTTP_PROXY"] = proxy
+	}
+	if proxy := StringVal(c.HTTPS); proxy != "" {
+		env["HTTPS_PROXY"] = proxy
+	}
+	if len(c.NoProxy) > 0 {
+		env["NO_PROXY"] = strings.Join(c.NoProxy, ",")
+	}
+	return env
+}
+
+// GoString defines the printable version of this struct.
+// Sensitive information is redacted.
+func (c *ProxyConfig) GoString() string {
+	if c == nil {
+		return "(*ProxyConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&ProxyConfig{"+
+		"HTTP:%s, "+
+		"HTTPS:%s, "+
+		"NoProxy:%s"+
+		"}",
+		redactProxyURL(StringVal(c.HTTP)),
+		redactProxyURL(StringVal(c.HTTPS)),
+		c.NoProxy,
+	)
+}
+
+// useProxy returns false if the host matches NoProxy or is a loopback
+// address, which is never proxied
+func (c *ProxyConfig) useProxy(hostport string) bool {
+	host, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+	host = strings.ToLower(host)
+
+	if host == "localhost" {
+		return false
+	}
+	ip := net.ParseIP(host)
+	if ip != nil && ip.IsLoopback() {
+		return false
+	}
+
+	for _, entry := range c.NoProxy {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "*" {
+			return false
+		}
+
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return false
+			}
+			continue
+		}
+
+		entryHost, entryPort, err := net.SplitHostPort(entry)
+		if err != nil {
+			entryHost, entryPort = entry, ""
+		}
+		if entryPort != "" && entryPort != port {
+			continue
+		}
+
+		entryHost = strings.TrimPrefix(entryHost, ".")
+		if host == entryHost || strings.HasSuffix(host, "."+entryHost) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// redactProxyURL redacts the password of a proxy URL
+func redactProxyURL(proxy string) string {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return proxy
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultPublishOutputsPathPrefix is the prefix of the default Consul KV path
+// that a task publishes its Terraform outputs under. The default path is the
+// prefix followed by the task name.
+const DefaultPublishOutputsPathPrefix = "cts/outputs/"
+
+// PublishOutputsConfig configures a task to write its Terraform outputs to
+// Consul KV after each successful apply. Each output is written to the key
+// of the output name under the path. String outputs are written as their
+// value and other outputs are written as JSON.
+type PublishOutputsConfig struct {
+	// Enabled determines if the task publishes its outputs. Disabled by
+	// default, and enabled if any other option is configured.
+	Enabled *bool `mapstructure:"enabled" json:"enabled"`
+
+	// Path is the Consul KV path that the outputs are written under. Defaults
+	// to "cts/outputs/<task name>".
+	Path *string `mapstructure:"path" json:"path"`
+
+	// Outputs are the names of the outputs to publish. All outputs are
+	// published when unset.
+	Outputs []string `mapstructure:"outputs" json:"outputs"`
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *PublishOutputsConfig) Copy() *PublishOutputsConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o PublishOutputsConfig
+	o.Enabled = BoolCopy(c.Enabled)
+	o.Path = StringCopy(c.Path)
+
+	if c.Outputs != nil {
+		o.Outputs = make([]string, 0, len(c.Outputs))
+		o.Outputs = append(o.Outputs, c.Outputs...)
+	}
+
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+// Maps and slices are merged, most other values are overwritten. Complex
+// structs define their own merge functionality.
+func (c *PublishOutputsConfig) Merge(o *PublishOutputsConfig) *PublishOutputsConfig {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+
+	if o.Enabled != nil {
+		r.Enabled = BoolCopy(o.Enabled)
+	}
+
+	if o.Path != nil {
+		r.Path = StringCopy(o.Path)
+	}
+
+	r.Outputs = mergeSlices(r.Outputs, o.Outputs)
+
+	return r
+}
+
+// Finalize ensures that the receiver contains no nil pointers. For nil pointers,
+// Finalize sets default values where necessary. The default path depends on
+// the task name and is set by the task's Finalize().
+func (c *PublishOutputsConfig) Finalize() {
+	if c == nil {
+		return
+	}
+
+	if c.Enabled == nil {
+		// some options configured, assume user intention is enabled
+		c.Enabled = Bool(c.Path != nil || len(c.Outputs) > 0)
+	}
+
+	if c.Path == nil {
+		c.Path = String("")
+	}
+
+	if c.Outputs == nil {
+		c.Outputs = []string{}
+	}
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *PublishOutputsConfig) Validate() error {
+	if c == nil {
+		// config is not required, return early
+		return nil
+	}
+
+	if !BoolVal(c.Enabled) {
+		return nil
+	}
+
+	path := StringVal(c.Path)
+	if strings.HasPrefix(path, "/") {
+		return fmt.Errorf("publish_outputs: path %q cannot begin with a '/'", path)
+	}
+
+	for _, output := range c.Outputs {
+		if output == "" || strings.Contains(output, "/") {
+			return fmt.Errorf("publish_outputs: invalid output name %q", output)
+		}
+	}
+
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *PublishOutputsConfig) GoString() string {
+	if c == nil {
+		return "(*PublishOutputsConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&PublishOutputsConfig{"+
+		"Enabled:%v, "+
+		"Path:%s, "+
+		"Outputs:%s"+
+		"}",
+		BoolVal(c.Enabled),
+		StringVal(c.Path),
+		c.Outputs,
+	)
+}
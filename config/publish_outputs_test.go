@@ -0,0 +1,242 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// defaultPublishOutputsConfig is the finalized default publish outputs
+// configuration of a task
+func defaultPublishOutputsConfig() *PublishOutputsConfig {
+	return &PublishOutputsConfig{
+		Enabled: Bool(false),
+		Path:    String(""),
+		Outputs: []string{},
+	}
+}
+
+func TestPublishOutputsConfig_Copy(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *PublishOutputsConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&PublishOutputsConfig{},
+		},
+		{
+			"happy_path",
+			&PublishOutputsConfig{
+				Enabled: Bool(true),
+				Path:    String("cts/outputs/task"),
+				Outputs: []string{"id"},
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Copy()
+			assert.Equal(t, tc.a, r)
+		})
+	}
+}
+
+func TestPublishOutputsConfig_Merge(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *PublishOutputsConfig
+		b    *PublishOutputsConfig
+		r    *PublishOutputsConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&PublishOutputsConfig{},
+			&PublishOutputsConfig{},
+		},
+		{
+			"nil_b",
+			&PublishOutputsConfig{},
+			nil,
+			&PublishOutputsConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"overrides",
+			&PublishOutputsConfig{Enabled: Bool(false), Path: String("a")},
+			&PublishOutputsConfig{Enabled: Bool(true), Path: String("b")},
+			&PublishOutputsConfig{Enabled: Bool(true), Path: String("b")},
+		},
+		{
+			"empty_one",
+			&PublishOutputsConfig{Path: String("a")},
+			&PublishOutputsConfig{},
+			&PublishOutputsConfig{Path: String("a")},
+		},
+		{
+			"outputs_merge",
+			&PublishOutputsConfig{Outputs: []string{"id"}},
+			&PublishOutputsConfig{Outputs: []string{"id", "arn"}},
+			&PublishOutputsConfig{Outputs: []string{"id", "arn"}},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			assert.Equal(t, tc.r, r)
+		})
+	}
+}
+
+func TestPublishOutputsConfig_Finalize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		i    *PublishOutputsConfig
+		r    *PublishOutputsConfig
+	}{
+		{
+			"nil",
+			nil,
+			nil,
+		},
+		{
+			"empty",
+			&PublishOutputsConfig{},
+			defaultPublishOutputsConfig(),
+		},
+		{
+			"path_configured",
+			&PublishOutputsConfig{Path: String("cts/outputs/task")},
+			&PublishOutputsConfig{
+				Enabled: Bool(true),
+				Path:    String("cts/outputs/task"),
+				Outputs: []string{},
+			},
+		},
+		{
+			"outputs_configured",
+			&PublishOutputsConfig{Outputs: []string{"id"}},
+			&PublishOutputsConfig{
+				Enabled: Bool(true),
+				Path:    String(""),
+				Outputs: []string{"id"},
+			},
+		},
+		{
+			"disabled",
+			&PublishOutputsConfig{Enabled: Bool(false), Path: String("a")},
+			&PublishOutputsConfig{
+				Enabled: Bool(false),
+				Path:    String("a"),
+				Outputs: []string{},
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			tc.i.Finalize()
+			assert.Equal(t, tc.r, tc.i)
+		})
+	}
+
+	t.Run("task_default_path", func(t *testing.T) {
+		task := &TaskConfig{
+			Name:           String("task"),
+			PublishOutputs: &PublishOutputsConfig{Enabled: Bool(true)},
+		}
+		require.NoError(t, task.Finalize())
+		assert.Equal(t, "cts/outputs/task", *task.PublishOutputs.Path)
+	})
+}
+
+func TestPublishOutputsConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		i       *PublishOutputsConfig
+		isValid bool
+	}{
+		{
+			"nil",
+			nil,
+			true,
+		},
+		{
+			"disabled",
+			&PublishOutputsConfig{Enabled: Bool(false), Path: String("/invalid")},
+			true,
+		},
+		{
+			"valid",
+			&PublishOutputsConfig{
+				Enabled: Bool(true),
+				Path:    String("cts/outputs/task"),
+				Outputs: []string{"id", "arn"},
+			},
+			true,
+		},
+		{
+			"leading_slash",
+			&PublishOutputsConfig{Enabled: Bool(true), Path: String("/cts/outputs")},
+			false,
+		},
+		{
+			"empty_output",
+			&PublishOutputsConfig{Enabled: Bool(true), Outputs: []string{""}},
+			false,
+		},
+		{
+			"output_with_slash",
+			&PublishOutputsConfig{Enabled: Bool(true), Outputs: []string{"a/b"}},
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			err := tc.i.Validate()
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestPublishOutputsConfig_GoString(t *testing.T) {
+	t.Parallel()
+
+	c := &PublishOutputsConfig{
+		Enabled: Bool(true),
+		Path:    String("cts/outputs/task"),
+		Outputs: []string{"id"},
+	}
+	assert.Equal(t, "&PublishOutputsConfig{Enabled:true, "+
+		"Path:cts/outputs/task, Outputs:[id]}", c.GoString())
+}
@@ -0,0 +1,132 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+)
+
+// ReadOnlyAPIConfig configures additional listeners for the CTS API that only
+// serve the read-only endpoints, such as the status endpoints. The listeners
+// have their own TLS configuration, which allows exposing the status of tasks
+// more broadly than the endpoints that mutate tasks.
+type ReadOnlyAPIConfig struct {
+	// Addresses are the addresses for the read-only listeners. Addresses can
+	// be host:port addresses or unix sockets prefixed with unix://. The
+	// read-only listeners are disabled if no addresses are configured.
+	Addresses []string `mapstructure:"address" json:"address"`
+
+	// TLS is the TLS configuration for the read-only listeners
+	TLS *CTSTLSConfig `mapstructure:"tls" json:"tls"`
+}
+
+// DefaultReadOnlyAPIConfig returns a configuration that is populated with the
+// default values.
+func DefaultReadOnlyAPIConfig() *ReadOnlyAPIConfig {
+	return &ReadOnlyAPIConfig{
+		Addresses: []string{},
+		TLS:       DefaultCTSTLSConfig(),
+	}
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *ReadOnlyAPIConfig) Copy() *ReadOnlyAPIConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o ReadOnlyAPIConfig
+	if c.Addresses != nil {
+		o.Addresses = make([]string, 0, len(c.Addresses))
+		o.Addresses = append(o.Addresses, c.Addresses...)
+	}
+
+	if c.TLS != nil {
+		o.TLS = c.TLS.Copy()
+	}
+
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+// Maps and slices are merged, most other values are overwritten. Complex
+// structs define their own merge functionality.
+func (c *ReadOnlyAPIConfig) Merge(o *ReadOnlyAPIConfig) *ReadOnlyAPIConfig {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+
+	r.Addresses = mergeSlices(r.Addresses, o.Addresses)
+
+	if o.TLS != nil {
+		r.TLS = r.TLS.Merge(o.TLS)
+	}
+
+	return r
+}
+
+// Finalize ensures there no nil pointers.
+func (c *ReadOnlyAPIConfig) Finalize() {
+	if c == nil {
+		return
+	}
+
+	if c.Addresses == nil {
+		c.Addresses = []string{}
+	}
+
+	if c.TLS == nil {
+		c.TLS = DefaultCTSTLSConfig()
+	}
+	c.TLS.Finalize()
+}
+
+// Enabled returns true if read-only listeners are configured
+func (c *ReadOnlyAPIConfig) Enabled() bool {
+	return c != nil && len(c.Addresses) > 0
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *ReadOnlyAPIConfig) Validate() error {
+	if c == nil {
+		// config is not required, return early
+		return nil
+	}
+
+	if err := validateAPIAddresses(c.Addresses); err != nil {
+		return fmt.Errorf("read_only_api: %s", err)
+	}
+
+	if err := c.TLS.Validate(); err != nil {
+		return fmt.Errorf("read_only_api: %s", err)
+	}
+
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *ReadOnlyAPIConfig) GoString() string {
+	if c == nil {
+		return "(*ReadOnlyAPIConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&ReadOnlyAPIConfig{"+
+		"Addresses:%s, "+
+		"TLS:%s"+
+		"}",
+		c.Addresses,
+		c.TLS.GoString(),
+	)
+}
@@ -0,0 +1,171 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnlyAPIConfig_Copy(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *ReadOnlyAPIConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&ReadOnlyAPIConfig{},
+		},
+		{
+			"fully configured",
+			&ReadOnlyAPIConfig{
+				Addresses: []string{"127.0.0.1:8559"},
+				TLS: &CTSTLSConfig{
+					Enabled: Bool(true),
+					Cert:    String("cert.pem"),
+					Key:     String("key.pem"),
+				},
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Copy()
+			assert.Equal(t, tc.a, r)
+		})
+	}
+}
+
+func TestReadOnlyAPIConfig_Merge(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *ReadOnlyAPIConfig
+		b    *ReadOnlyAPIConfig
+		r    *ReadOnlyAPIConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&ReadOnlyAPIConfig{},
+			&ReadOnlyAPIConfig{},
+		},
+		{
+			"nil_b",
+			&ReadOnlyAPIConfig{},
+			nil,
+			&ReadOnlyAPIConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"addresses_merge",
+			&ReadOnlyAPIConfig{Addresses: []string{"127.0.0.1:8559"}},
+			&ReadOnlyAPIConfig{Addresses: []string{"10.0.0.1:8559"}},
+			&ReadOnlyAPIConfig{Addresses: []string{"127.0.0.1:8559", "10.0.0.1:8559"}},
+		},
+		{
+			"tls_merge",
+			&ReadOnlyAPIConfig{TLS: &CTSTLSConfig{Cert: String("cert.pem")}},
+			&ReadOnlyAPIConfig{TLS: &CTSTLSConfig{Key: String("key.pem")}},
+			&ReadOnlyAPIConfig{TLS: &CTSTLSConfig{
+				Cert: String("cert.pem"),
+				Key:  String("key.pem"),
+			}},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			assert.Equal(t, tc.r, r)
+		})
+	}
+}
+
+func TestReadOnlyAPIConfig_Finalize(t *testing.T) {
+	t.Parallel()
+
+	c := &ReadOnlyAPIConfig{}
+	c.Finalize()
+
+	expected := DefaultReadOnlyAPIConfig()
+	expected.TLS.Finalize()
+	assert.Equal(t, expected, c)
+	assert.False(t, c.Enabled())
+
+	c = &ReadOnlyAPIConfig{Addresses: []string{"127.0.0.1:8559"}}
+	c.Finalize()
+	assert.True(t, c.Enabled())
+}
+
+func TestReadOnlyAPIConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		i       *ReadOnlyAPIConfig
+		isValid bool
+	}{
+		{
+			"nil",
+			nil,
+			true,
+		},
+		{
+			"valid",
+			&ReadOnlyAPIConfig{
+				Addresses: []string{"127.0.0.1:8559", "unix:///var/run/cts-read.sock"},
+				TLS: &CTSTLSConfig{
+					Enabled: Bool(true),
+					Cert:    String("../testutils/certs/consul_cert.pem"),
+					Key:     String("../testutils/certs/consul_key.pem"),
+				},
+			},
+			true,
+		},
+		{
+			"invalid address",
+			&ReadOnlyAPIConfig{
+				Addresses: []string{"127.0.0.1"},
+			},
+			false,
+		},
+		{
+			"invalid tls",
+			&ReadOnlyAPIConfig{
+				Addresses: []string{"127.0.0.1:8559"},
+				TLS: &CTSTLSConfig{
+					Enabled: Bool(true),
+				},
+			},
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			err := tc.i.Validate()
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
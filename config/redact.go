@@ -0,0 +1,158 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import "strings"
+
+// sensitiveArgs are substrings of the names of backend arguments whose values
+// are redacted, e.g. access_token, password, secret_key
+var sensitiveArgs = []string{"token", "password", "secret", "access_key"}
+
+// Redacted returns a copy of the configuration with sensitive information
+// redacted so that it is safe to share, e.g. Consul tokens, passwords, and
+// provider arguments. Provider configuration is completely redacted since
+// providers will have varying arguments containing secrets.
+func (c *Config) Redacted() *Config {
+	if c == nil {
+		return nil
+	}
+
+	r := c.Copy()
+
+	if r.Consul != nil {
+		r.Consul.Token = redactString(r.Consul.Token)
+		if r.Consul.Auth != nil {
+			r.Consul.Auth.Password = redactString(r.Consul.Auth.Password)
+		}
+	}
+
+	if r.Driver != nil && r.Driver.Terraform != nil {
+		r.Driver.Terraform.Backend = redactArgs(r.Driver.Terraform.Backend)
+	}
+
+	if r.TerraformProviders != nil {
+		for _, p := range *r.TerraformProviders {
+			for name := range *p {
+				(*p)[name] = redactMessage
+			}
+		}
+	}
+
+	if r.TFCRunTask != nil {
+		r.TFCRunTask.HMACKey = redactString(r.TFCRunTask.HMACKey)
+	}
+
+	if r.Alertmanager != nil {
+		r.Alertmanager.Token = redactString(r.Alertmanager.Token)
+	}
+
+	if r.Proxy != nil {
+		if StringPresent(r.Proxy.HTTP) {
+			r.Proxy.HTTP = String(redactProxyURL(*r.Proxy.HTTP))
+		}
+		if StringPresent(r.Proxy.HTTPS) {
+			r.Proxy.HTTPS = String(redactProxyURL(*r.Proxy.HTTPS))
+		}
+	}
+
+	if r.Tasks != nil {
+		for _, t := range *r.Tasks {
+			t.redact()
+		}
+	}
+
+	return r
+}
+
+// redact redacts the sensitive information of the task configuration in place
+func (c *TaskConfig) redact() {
+	if c == nil {
+		return
+	}
+
+	c.ConsulToken = redactString(c.ConsulToken)
+	for _, args := range c.ProviderOverrides {
+		for name := range args {
+			args[name] = redactMessage
+		}
+	}
+	c.Backend = redactArgs(c.Backend)
+	c.RedactSensitiveVariables()
+}
+
+// RedactSensitiveVariables redacts the values of the sensitive variables of
+// the task in place
+func (c *TaskConfig) RedactSensitiveVariables() {
+	if c == nil {
+		return
+	}
+
+	c.Variables = RedactVariables(c.Variables, c.SensitiveVariables)
+	if c.EnvironmentConfigs != nil {
+		for _, e := range *c.EnvironmentConfigs {
+			e.Variables = RedactVariables(e.Variables, c.SensitiveVariables)
+		}
+	}
+}
+
+// RedactVariables returns a copy of the variables with the values of the
+// sensitive variables redacted
+func RedactVariables(vars map[string]string, sensitive []string) map[string]string {
+	if vars == nil {
+		return nil
+	}
+
+	r := make(map[string]string, len(vars))
+	for k, v := range vars {
+		r[k] = v
+	}
+	for _, name := range sensitive {
+		if _, ok := r[name]; ok {
+			r[name] = redactMessage
+		}
+	}
+	return r
+}
+
+// redactString returns the redact message if the value is set
+func redactString(s *string) *string {
+	if StringPresent(s) {
+		return String(redactMessage)
+	}
+	return s
+}
+
+// redactArgs returns a copy of the arguments with the values of sensitive
+// arguments redacted, including the arguments of nested blocks
+func redactArgs(args map[string]interface{}) map[string]interface{} {
+	if args == nil {
+		return nil
+	}
+
+	r := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		switch val := v.(type) {
+		case map[string]interface{}:
+			r[k] = redactArgs(val)
+			continue
+		case []map[string]interface{}:
+			blocks := make([]map[string]interface{}, len(val))
+			for i, m := range val {
+				blocks[i] = redactArgs(m)
+			}
+			r[k] = blocks
+			continue
+		}
+
+		r[k] = v
+		name := strings.ToLower(k)
+		for _, s := range sensitiveArgs {
+			if strings.Contains(name, s) {
+				r[k] = redactMessage
+				break
+			}
+		}
+	}
+	return r
+}
@@ -16,26 +16,26 @@ type ServiceConfig struct {
 	Datacenter *string `mapstricture:"datacenter"`
 
 	// Description is the human readable text to describe the service.
-	Description *string `mapstructure:"description"`
+	Description *string `mapstructure:"description" json:"description"`
 
 	// ID identifies the service for CTS. This is used to explicitly
 	// identify the service config for a task to use.
-	ID *string `mapstructure:"id"`
+	ID *string `mapstructure:"id" json:"id"`
 
 	// Name is the Consul logical name of the service (required).
-	Name *string `mapstructure:"name"`
+	Name *string `mapstructure:"name" json:"name"`
 
 	// Namespace is the namespace of the service (Consul Enterprise only). If not
 	// provided, the namespace will be inferred from the CTS ACL token, or
 	// default to the `default` namespace.
-	Namespace *string `mapstructure:"namespace"`
+	Namespace *string `mapstructure:"namespace" json:"namespace"`
 
 	// Filter is used to filter nodes based on a Consul compatible filter expression.
-	Filter *string `mapstructure:"filter"`
+	Filter *string `mapstructure:"filter" json:"filter"`
 
 	// CTSUserDefinedMeta is metadata added to a service automated by CTS for
 	// network infrastructure automation.
-	CTSUserDefinedMeta map[string]string `mapstructure:"cts_user_defined_meta"`
+	CTSUserDefinedMeta map[string]string `mapstructure:"cts_user_defined_meta" json:"cts_user_defined_meta"`
 }
 
 // ServiceConfigs is a collection of ServiceConfig
@@ -12,11 +12,11 @@ const (
 // ServiceRegistrationConfig is a configuration that controls how CTS will
 // register itself as a service with Consul.
 type ServiceRegistrationConfig struct {
-	Enabled      *bool               `mapstructure:"enabled"`
-	ServiceName  *string             `mapstructure:"service_name"`
-	Address      *string             `mapstructure:"address"`
-	Namespace    *string             `mapstructure:"namespace"`
-	DefaultCheck *DefaultCheckConfig `mapstructure:"default_check"`
+	Enabled      *bool               `mapstructure:"enabled" json:"enabled"`
+	ServiceName  *string             `mapstructure:"service_name" json:"service_name"`
+	Address      *string             `mapstructure:"address" json:"address"`
+	Namespace    *string             `mapstructure:"namespace" json:"namespace"`
+	DefaultCheck *DefaultCheckConfig `mapstructure:"default_check" json:"default_check"`
 }
 
 // DefaultServiceRegistrationConfig returns a ServiceRegistrationConfig with
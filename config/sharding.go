@@ -0,0 +1,186 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultShardingSessionTTL is the default TTL of the Consul session that
+	// holds the membership of a CTS instance
+	DefaultShardingSessionTTL = 15 * time.Second
+
+	// DefaultShardingVirtualNodes is the default number of points of each
+	// CTS instance on the hash ring that assigns tasks to instances
+	DefaultShardingVirtualNodes = 64
+
+	// shardingKVPathSuffix is appended to the Consul KV path of CTS for the
+	// default sharding KV path
+	shardingKVPathSuffix = "shards"
+
+	// minShardingSessionTTL is the minimum TTL of a Consul session
+	minShardingSessionTTL = 10 * time.Second
+)
+
+// ShardingConfig configures multiple CTS instances to share the tasks of the
+// configuration file. Each instance joins the shard membership with a Consul
+// session and only watches and runs the tasks that are assigned to it by
+// consistent hashing over the task names. Tasks are reassigned when instances
+// join or leave.
+//
+// All instances that share the tasks are expected to have the same tasks in
+// their configuration files, the same sharding configuration, and a unique ID.
+type ShardingConfig struct {
+	// Enabled determines if the tasks are sharded between CTS instances.
+	// Disabled by default.
+	Enabled *bool `mapstructure:"enabled" json:"enabled"`
+
+	// KVPath is the path in Consul KV where the instances hold their shard
+	// membership. Defaults to "shards" under the Consul KV path of CTS.
+	KVPath *string `mapstructure:"kv_path" json:"kv_path"`
+
+	// SessionTTL is the TTL of the Consul session of an instance. An instance
+	// that stops renewing its session leaves the membership after the TTL
+	// and its tasks are reassigned. Defaults to 15s.
+	SessionTTL *time.Duration `mapstructure:"session_ttl" json:"session_ttl"`
+
+	// VirtualNodes is the number of points of each instance on the hash
+	// ring. More points distribute the tasks more evenly. Defaults to 64.
+	VirtualNodes *int `mapstructure:"virtual_nodes" json:"virtual_nodes"`
+}
+
+// DefaultShardingConfig returns a configuration that is populated with the
+// default values.
+func DefaultShardingConfig() *ShardingConfig {
+	return &ShardingConfig{}
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *ShardingConfig) Copy() *ShardingConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o ShardingConfig
+	o.Enabled = BoolCopy(c.Enabled)
+	o.KVPath = StringCopy(c.KVPath)
+	o.SessionTTL = TimeDurationCopy(c.SessionTTL)
+	o.VirtualNodes = IntCopy(c.VirtualNodes)
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+// Maps and slices are merged, most other values are overwritten. Complex
+// structs define their own merge functionality.
+func (c *ShardingConfig) Merge(o *ShardingConfig) *ShardingConfig {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+
+	if o.Enabled != nil {
+		r.Enabled = BoolCopy(o.Enabled)
+	}
+
+	if o.KVPath != nil {
+		r.KVPath = StringCopy(o.KVPath)
+	}
+
+	if o.SessionTTL != nil {
+		r.SessionTTL = TimeDurationCopy(o.SessionTTL)
+	}
+
+	if o.VirtualNodes != nil {
+		r.VirtualNodes = IntCopy(o.VirtualNodes)
+	}
+
+	return r
+}
+
+// Finalize ensures there no nil pointers. The default KV path is under the
+// Consul KV path of CTS.
+func (c *ShardingConfig) Finalize(consulKVPath string) {
+	if c == nil {
+		return
+	}
+
+	if c.Enabled == nil {
+		c.Enabled = Bool(false)
+	}
+
+	if c.KVPath == nil {
+		c.KVPath = String(path.Join(consulKVPath, shardingKVPathSuffix))
+	}
+
+	if c.SessionTTL == nil {
+		c.SessionTTL = TimeDuration(DefaultShardingSessionTTL)
+	}
+
+	if c.VirtualNodes == nil {
+		c.VirtualNodes = Int(DefaultShardingVirtualNodes)
+	}
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *ShardingConfig) Validate() error {
+	if !c.IsEnabled() {
+		return nil
+	}
+
+	if strings.Trim(StringVal(c.KVPath), "/") == "" {
+		return fmt.Errorf("sharding: kv_path is required")
+	}
+
+	if ttl := TimeDurationVal(c.SessionTTL); ttl < minShardingSessionTTL {
+		return fmt.Errorf("sharding: session_ttl %s is less than the minimum "+
+			"Consul session TTL %s", ttl, minShardingSessionTTL)
+	}
+
+	if IntVal(c.VirtualNodes) < 1 {
+		return fmt.Errorf("sharding: virtual_nodes must be greater than 0")
+	}
+
+	return nil
+}
+
+// IsEnabled returns true if the tasks are sharded between CTS instances
+func (c *ShardingConfig) IsEnabled() bool {
+	if c == nil {
+		return false
+	}
+	return BoolVal(c.Enabled)
+}
+
+// GoString defines the printable version of this struct.
+func (c *ShardingConfig) GoString() string {
+	if c == nil {
+		return "(*ShardingConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&ShardingConfig{"+
+		"Enabled:%t, "+
+		"KVPath:%s, "+
+		"SessionTTL:%s, "+
+		"VirtualNodes:%d"+
+		"}",
+		BoolVal(c.Enabled),
+		StringVal(c.KVPath),
+		TimeDurationVal(c.SessionTTL),
+		IntVal(c.VirtualNodes),
+	)
+}
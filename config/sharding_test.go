@@ -0,0 +1,221 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShardingConfig_Copy(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *ShardingConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&ShardingConfig{},
+		},
+		{
+			"fully_configured",
+			&ShardingConfig{
+				Enabled:      Bool(true),
+				KVPath:       String("cts/shards"),
+				SessionTTL:   TimeDuration(30 * time.Second),
+				VirtualNodes: Int(16),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tc.a.Copy()
+			assert.Equal(t, tc.a, r)
+		})
+	}
+}
+
+func TestShardingConfig_Merge(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *ShardingConfig
+		b    *ShardingConfig
+		r    *ShardingConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&ShardingConfig{},
+			&ShardingConfig{},
+		},
+		{
+			"nil_b",
+			&ShardingConfig{},
+			nil,
+			&ShardingConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"enabled_overrides",
+			&ShardingConfig{Enabled: Bool(false)},
+			&ShardingConfig{Enabled: Bool(true)},
+			&ShardingConfig{Enabled: Bool(true)},
+		},
+		{
+			"kv_path_empty_one",
+			&ShardingConfig{KVPath: String("cts/shards")},
+			&ShardingConfig{},
+			&ShardingConfig{KVPath: String("cts/shards")},
+		},
+		{
+			"session_ttl_overrides",
+			&ShardingConfig{SessionTTL: TimeDuration(15 * time.Second)},
+			&ShardingConfig{SessionTTL: TimeDuration(time.Minute)},
+			&ShardingConfig{SessionTTL: TimeDuration(time.Minute)},
+		},
+		{
+			"virtual_nodes_overrides",
+			&ShardingConfig{VirtualNodes: Int(8)},
+			&ShardingConfig{VirtualNodes: Int(32)},
+			&ShardingConfig{VirtualNodes: Int(32)},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			assert.Equal(t, tc.r, r)
+		})
+	}
+}
+
+func TestShardingConfig_Finalize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		i    *ShardingConfig
+		r    *ShardingConfig
+	}{
+		{
+			"nil",
+			nil,
+			nil,
+		},
+		{
+			"empty",
+			&ShardingConfig{},
+			&ShardingConfig{
+				Enabled:      Bool(false),
+				KVPath:       String("consul-terraform-sync/shards"),
+				SessionTTL:   TimeDuration(DefaultShardingSessionTTL),
+				VirtualNodes: Int(DefaultShardingVirtualNodes),
+			},
+		},
+		{
+			"kv_path",
+			&ShardingConfig{Enabled: Bool(true), KVPath: String("cts/members")},
+			&ShardingConfig{
+				Enabled:      Bool(true),
+				KVPath:       String("cts/members"),
+				SessionTTL:   TimeDuration(DefaultShardingSessionTTL),
+				VirtualNodes: Int(DefaultShardingVirtualNodes),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.i.Finalize(DefaultConsulKVPath)
+			assert.Equal(t, tc.r, tc.i)
+		})
+	}
+}
+
+func TestShardingConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		expectErr bool
+		c         *ShardingConfig
+	}{
+		{
+			"nil",
+			false,
+			nil,
+		},
+		{
+			"disabled",
+			false,
+			&ShardingConfig{Enabled: Bool(false), SessionTTL: TimeDuration(0)},
+		},
+		{
+			"valid",
+			false,
+			&ShardingConfig{
+				Enabled:      Bool(true),
+				KVPath:       String("cts/shards"),
+				SessionTTL:   TimeDuration(15 * time.Second),
+				VirtualNodes: Int(64),
+			},
+		},
+		{
+			"missing_kv_path",
+			true,
+			&ShardingConfig{
+				Enabled:      Bool(true),
+				KVPath:       String("/"),
+				SessionTTL:   TimeDuration(15 * time.Second),
+				VirtualNodes: Int(64),
+			},
+		},
+		{
+			"session_ttl_too_short",
+			true,
+			&ShardingConfig{
+				Enabled:      Bool(true),
+				KVPath:       String("cts/shards"),
+				SessionTTL:   TimeDuration(time.Second),
+				VirtualNodes: Int(64),
+			},
+		},
+		{
+			"zero_virtual_nodes",
+			true,
+			&ShardingConfig{
+				Enabled:      Bool(true),
+				KVPath:       String("cts/shards"),
+				SessionTTL:   TimeDuration(15 * time.Second),
+				VirtualNodes: Int(0),
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.c.Validate()
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -21,9 +21,9 @@ var (
 
 // SyslogConfig is the configuration for syslog.
 type SyslogConfig struct {
-	Enabled  *bool   `mapstructure:"enabled"`
-	Facility *string `mapstructure:"facility"`
-	Name     *string `mapstructure:"name"`
+	Enabled  *bool   `mapstructure:"enabled" json:"enabled"`
+	Facility *string `mapstructure:"facility" json:"facility"`
+	Name     *string `mapstructure:"name" json:"name"`
 }
 
 // DefaultSyslogConfig returns the default configuration struct.
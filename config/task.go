@@ -8,6 +8,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -18,8 +20,23 @@ import (
 	ctyjson "github.com/zclconf/go-cty/cty/json"
 )
 
+// labelKeyRegexp matches the valid keys of task labels
+var labelKeyRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.\-/]*$`)
+
 const (
 	taskSubsystemName = "task"
+
+	// TaskExpireActionDisable disables an expired task
+	TaskExpireActionDisable = "disable"
+	// TaskExpireActionDelete deletes an expired task
+	TaskExpireActionDelete = "delete"
+
+	// TaskTriggerOnConditionOnly triggers the task only on the changes that
+	// are monitored by the task's condition
+	TaskTriggerOnConditionOnly = "condition_only"
+	// TaskTriggerOnAnyInputChange triggers the task on the changes monitored
+	// by the condition and on any change to the module inputs
+	TaskTriggerOnAnyInputChange = "any_input_change"
 )
 
 // TaskConfig is the configuration for a CTS task. This block may be
@@ -31,10 +48,31 @@ type TaskConfig struct {
 	// Name is the unique name of the task.
 	Name *string `mapstructure:"name" json:"name"`
 
+	// Labels are arbitrary key-value pairs to group tasks, e.g.
+	// `{ team = "neteng", env = "prod" }`. Tasks can be listed and operated
+	// on in bulk by their labels.
+	Labels map[string]string `mapstructure:"labels" json:"labels"`
+
 	// Providers is the list of provider names the task is dependent on. This is
 	// used to map provider configuration to the task.
 	Providers []string `mapstructure:"providers" json:"providers"`
 
+	// ProviderOverrides overrides arguments of the terraform_provider blocks
+	// used by the task. The map key is the provider ID as listed in Providers,
+	// and the value is the arguments to override for the task. This allows
+	// tasks to use the same provider configuration with different arguments,
+	// e.g. a different hostname, without defining a provider alias per task.
+	ProviderOverrides map[string]map[string]interface{} `mapstructure:"provider_overrides" json:"provider_overrides"`
+
+	// ProviderForeachDatacenter configures the task to use an instance of each
+	// of its providers for each datacenter that the task monitors, instead of
+	// configuring a provider alias per datacenter. The instances are passed to
+	// the module with the datacenter as the alias, e.g. aws.dc1. The
+	// terraform_provider block with the datacenter as its alias is used for a
+	// datacenter if configured, otherwise the task's provider configuration is
+	// used. Disabled by default.
+	ProviderForeachDatacenter *bool `mapstructure:"provider_foreach_datacenter" json:"provider_foreach_datacenter"`
+
 	// DeprecatedServices is the list of service IDs or logical service names the task
 	// executes on. CTS monitors the Consul Catalog for changes to these
 	// services and triggers the task to run. Any service value not explicitly
@@ -63,17 +101,33 @@ type TaskConfig struct {
 
 	// Variables are loaded in the same order as they appear in the map.
 	// Duplicate variables are overwritten with the later value.
-	// No validation is performed on the Variables, as this is not set by the configuration
+	// No validation is performed on the Variables, as this is not set by the configuration.
+	// Values that are HCL expressions referencing the task, e.g.
+	// "\"${task.name}-lb\"", or calling functions, e.g. "upper(task.name)",
+	// are evaluated when the configuration is finalized.
 	Variables map[string]string `mapstructure:"variables" json:"variables"`
 
+	// SensitiveVariables are the names of the variables of the task whose
+	// values are sensitive, e.g. variables from variable_files with secrets.
+	// Sensitive variables are written to a separate tfvars file readable only
+	// by the owner and are redacted from the API and logs.
+	SensitiveVariables []string `mapstructure:"sensitive_variables" json:"sensitive_variables"`
+
+	// VariablesFromConsulKV are Consul KV prefixes of key-values to use as
+	// variables of the task. The name of a variable is the key relative to
+	// the prefix, and keys nested further under the prefix are ignored.
+	// Variables configured on the task override variables from Consul KV.
+	VariablesFromConsulKV []string `mapstructure:"variables_from_consul_kv" json:"variables_from_consul_kv"`
+
 	// Version is the module version for the task to use. The latest version
 	// will be used as the default if omitted.
 	Version *string `mapstructure:"version" json:"version"`
 
-	// The Terraform client version to use for the task when configured with CTS
-	// enterprise and the Terraform Cloud driver. This option is not supported
-	// when using CTS OSS or the Terraform driver.
-	// - Deprecated in 0.6. Use `terraform_cloud_workspace.terraform_version` instead
+	// The Terraform client version to use for the task. With the Terraform
+	// driver, CTS installs the version under the driver's Terraform path and
+	// uses it for the task instead of the driver's Terraform version.
+	// - Deprecated in 0.6 for CTS Enterprise and the Terraform Cloud driver.
+	//   Use `terraform_cloud_workspace.terraform_version` instead
 	DeprecatedTFVersion *string `mapstructure:"terraform_version" json:"terraform_version"`
 
 	// The workspace configurations to use for the task when configured with CTS
@@ -84,10 +138,154 @@ type TaskConfig struct {
 	// BufferPeriod configures per-task buffer timers.
 	BufferPeriod *BufferPeriodConfig `mapstructure:"buffer_period" json:"buffer_period"`
 
+	// Cooldown is the minimum interval between consecutive runs of the task.
+	// Triggers that occur within the cooldown are suppressed and the task is
+	// run once after the cooldown ends. Unlike the buffer period, which smooths
+	// bursts of changes, the cooldown caps how often the task can run.
+	// Disabled when set to 0.
+	Cooldown *time.Duration `mapstructure:"cooldown" json:"cooldown"`
+
+	// Priority orders the runs of tasks that are pending at the same time,
+	// e.g. when CTS starts or when the buffer periods of multiple tasks
+	// expire together. Tasks with a higher priority run first, after the
+	// tasks that they depend on. Defaults to 0.
+	Priority *int `mapstructure:"priority" json:"priority"`
+
+	// InputSnapshots is the number of snapshots of the task's applied input
+	// variables (terraform.tfvars) to retain in the task's working directory.
+	// A snapshot is saved after each successful run so that the inputs of two
+	// runs can be compared. Disabled when set to 0.
+	InputSnapshots *int `mapstructure:"input_snapshots" json:"input_snapshots"`
+
+	// CircuitBreaker configures the task to pause after consecutive failures
+	// instead of retrying on every trigger.
+	CircuitBreaker *CircuitBreakerConfig `mapstructure:"circuit_breaker" json:"circuit_breaker"`
+
+	// MaintenanceWindow configures a recurring window during which triggers
+	// for the task are queued instead of applied. The task runs once when
+	// the window ends if it was triggered during the window.
+	MaintenanceWindow *MaintenanceWindowConfig `mapstructure:"maintenance_window" json:"maintenance_window"`
+
+	// DependsOn is the list of names of the tasks that the task depends on.
+	// When the task is triggered, its dependencies with pending changes are
+	// run before the task, e.g. to create firewall objects before the load
+	// balancer pools that reference them.
+	DependsOn []string `mapstructure:"depends_on" json:"depends_on"`
+
+	// SkipOnDependencyFailure configures the task to be skipped when the
+	// latest run of any of the tasks in DependsOn failed. Disabled by default.
+	SkipOnDependencyFailure *bool `mapstructure:"skip_on_dependency_failure" json:"skip_on_dependency_failure"`
+
+	// ExpiresAt is the time when the task expires, e.g. for temporary
+	// automation during a migration. When the task expires, an event is
+	// recorded and the task is disabled or deleted based on ExpireAction.
+	// The task does not expire if not set.
+	ExpiresAt *time.Time `mapstructure:"expires_at" json:"expires_at"`
+
+	// ExpireAction is the action to take when the task expires. Either
+	// "disable" or "delete". Defaults to "disable".
+	ExpireAction *string `mapstructure:"expire_action" json:"expire_action"`
+
+	// TriggerOn configures which changes trigger the task. Either
+	// "condition_only" to trigger only on the changes monitored by the
+	// condition or "any_input_change" to also trigger on changes to the data
+	// of the module inputs. Defaults to "condition_only".
+	TriggerOn *string `mapstructure:"trigger_on" json:"trigger_on"`
+
 	// Enabled determines if the task is enabled or not. Enabled by default.
 	// If not enabled, this task will not make any changes to resources.
 	Enabled *bool `mapstructure:"enabled" json:"enabled"`
 
+	// RenderOnly configures the task to only render the module input files
+	// on changes without running Terraform. This allows CTS to be used to
+	// template Consul information into files for other pipelines to consume.
+	// Disabled by default.
+	RenderOnly *bool `mapstructure:"render_only" json:"render_only"`
+
+	// ServicesChanged configures the task to pass a services_changed variable
+	// to the module in addition to the services variable. The variable
+	// contains the service instances that were added, removed, or modified
+	// since the last successful run of the task. Disabled by default.
+	ServicesChanged *bool `mapstructure:"services_changed" json:"services_changed"`
+
+	// TargetedApply configures the task to apply only the resources that are
+	// mapped to the services that changed since the last successful run of
+	// the task, using Terraform resource targeting. The task falls back to
+	// applying all resources if it has not run successfully yet or if a
+	// changed service is not mapped in ApplyTargets. Disabled by default.
+	TargetedApply *bool `mapstructure:"targeted_apply" json:"targeted_apply"`
+
+	// ApplyTargets maps service names to the addresses of the resources in
+	// the task's module that are applied when the service changes, e.g.
+	// `web = ["module.web.aws_instance.web"]`. Required with TargetedApply.
+	ApplyTargets map[string][]string `mapstructure:"apply_targets" json:"apply_targets"`
+
+	// ConsulToken is the Consul ACL token for the task. CTS uses the token
+	// for the blocking queries of the task's dependencies and, with the Consul
+	// backend, for Terraform state access. The value can be a dynamic
+	// template to read the token from Vault, e.g.
+	// `{{ with secret "consul/creds/task" }}{{ .Data.token }}{{ end }}`.
+	// Defaults to the token of the consul block.
+	ConsulToken *string `mapstructure:"consul_token" json:"consul_token"`
+
+	// ConsulTokenFile is the path to a file containing the Consul ACL token
+	// for the task. Cannot be configured with ConsulToken.
+	ConsulTokenFile *string `mapstructure:"consul_token_file" json:"consul_token_file"`
+
+	// Backend overrides the Terraform backend of the driver for the task.
+	// Arguments for the same backend type as the driver are merged with the
+	// driver's backend arguments, e.g. to store the task's state at a
+	// different Consul KV path. A different backend type replaces the
+	// driver's backend for the task. Only one backend can be configured.
+	Backend map[string]interface{} `mapstructure:"backend" json:"backend"`
+
+	// Environments is the list of names of the environments of the task,
+	// e.g. ["staging", "prod"]. When the task is triggered, it runs Terraform
+	// for each environment in order with the same rendered module inputs,
+	// each in its own workspace. The task runs Terraform once without
+	// environments.
+	Environments []string `mapstructure:"environments" json:"environments"`
+
+	// EnvironmentConfigs override the workspace, variables, and backend key
+	// of environments listed in Environments.
+	EnvironmentConfigs *TaskEnvironmentConfigs `mapstructure:"environment" json:"environment"`
+
+	// ExtraTemplates is a list of paths to additional hcat template files for
+	// the task. Each template is rendered into the task's working directory
+	// alongside the module input variables file whenever the task runs. The
+	// rendered file is named after the template file with the .tmpl, .tpl,
+	// or .tftpl extension removed, e.g. "lb.json.tpl" renders to "lb.json".
+	ExtraTemplates []string `mapstructure:"extra_templates" json:"extra_templates"`
+
+	// Postconditions are assertions on the Terraform outputs of the module
+	// that are evaluated after each apply. The task run fails if any
+	// postcondition does not hold.
+	Postconditions *PostconditionConfigs `mapstructure:"postcondition" json:"postcondition"`
+
+	// ComputedInputs are variables of the module that are computed from the
+	// monitored data with HCL expressions each time the module input
+	// variables are rendered, by the name of the variable.
+	ComputedInputs ComputedInputConfigs `mapstructure:"computed_input" json:"computed_input"`
+
+	// PublishOutputs configures the task to write its Terraform outputs to
+	// Consul KV after each successful apply.
+	PublishOutputs *PublishOutputsConfig `mapstructure:"publish_outputs" json:"publish_outputs"`
+
+	// AlertmanagerSilence configures the task to create a temporary silence
+	// in the Alertmanager before it applies changes, so that alerts of the
+	// expected reconvergence do not page. Requires the alertmanager block.
+	AlertmanagerSilence *AlertmanagerSilenceConfig `mapstructure:"alertmanager_silence" json:"alertmanager_silence"`
+
+	// Handlers are user-defined post-apply handlers that run out-of-band
+	// actions after each successful apply of the task, e.g. a program
+	// configured with a `handler "exec"` block.
+	Handlers *HandlerConfigs `mapstructure:"handler" json:"handler"`
+
+	// Gates must be open for the task to apply changes, e.g. a change freeze
+	// flag configured with a `gate "consul-kv"` block. Triggers of the task
+	// are queued while a gate is closed.
+	Gates *GateConfigs `mapstructure:"gate" json:"gate"`
+
 	// Condition optionally configures a single run condition under which the
 	// task will start executing
 	Condition ConditionConfig `mapstructure:"condition" json:"condition"`
@@ -112,11 +310,27 @@ func (c *TaskConfig) Copy() *TaskConfig {
 	o.Description = StringCopy(c.Description)
 	o.Name = StringCopy(c.Name)
 
+	if c.Labels != nil {
+		o.Labels = make(map[string]string, len(c.Labels))
+		for k, v := range c.Labels {
+			o.Labels[k] = v
+		}
+	}
+
 	if c.Providers != nil {
 		o.Providers = make([]string, 0, len(c.Providers))
 		o.Providers = append(o.Providers, c.Providers...)
 	}
 
+	if c.ProviderOverrides != nil {
+		o.ProviderOverrides = make(map[string]map[string]interface{}, len(c.ProviderOverrides))
+		for id, args := range c.ProviderOverrides {
+			o.ProviderOverrides[id] = copyProviderArgs(args)
+		}
+	}
+
+	o.ProviderForeachDatacenter = BoolCopy(c.ProviderForeachDatacenter)
+
 	if c.DeprecatedServices != nil {
 		o.DeprecatedServices = make([]string, 0, len(c.DeprecatedServices))
 		o.DeprecatedServices = append(o.DeprecatedServices, c.DeprecatedServices...)
@@ -140,6 +354,16 @@ func (c *TaskConfig) Copy() *TaskConfig {
 		}
 	}
 
+	if c.SensitiveVariables != nil {
+		o.SensitiveVariables = make([]string, 0, len(c.SensitiveVariables))
+		o.SensitiveVariables = append(o.SensitiveVariables, c.SensitiveVariables...)
+	}
+
+	if c.VariablesFromConsulKV != nil {
+		o.VariablesFromConsulKV = make([]string, 0, len(c.VariablesFromConsulKV))
+		o.VariablesFromConsulKV = append(o.VariablesFromConsulKV, c.VariablesFromConsulKV...)
+	}
+
 	o.Version = StringCopy(c.Version)
 
 	o.DeprecatedTFVersion = StringCopy(c.DeprecatedTFVersion)
@@ -150,8 +374,79 @@ func (c *TaskConfig) Copy() *TaskConfig {
 
 	o.BufferPeriod = c.BufferPeriod.Copy()
 
+	o.Cooldown = TimeDurationCopy(c.Cooldown)
+
+	o.Priority = IntCopy(c.Priority)
+
+	o.InputSnapshots = IntCopy(c.InputSnapshots)
+
+	o.CircuitBreaker = c.CircuitBreaker.Copy()
+
+	o.MaintenanceWindow = c.MaintenanceWindow.Copy()
+
+	if c.DependsOn != nil {
+		o.DependsOn = make([]string, 0, len(c.DependsOn))
+		o.DependsOn = append(o.DependsOn, c.DependsOn...)
+	}
+
+	o.SkipOnDependencyFailure = BoolCopy(c.SkipOnDependencyFailure)
+
+	o.ExpiresAt = TimeCopy(c.ExpiresAt)
+
+	o.ExpireAction = StringCopy(c.ExpireAction)
+
+	o.TriggerOn = StringCopy(c.TriggerOn)
+
 	o.Enabled = BoolCopy(c.Enabled)
 
+	o.RenderOnly = BoolCopy(c.RenderOnly)
+
+	o.ServicesChanged = BoolCopy(c.ServicesChanged)
+
+	o.TargetedApply = BoolCopy(c.TargetedApply)
+
+	if c.ApplyTargets != nil {
+		o.ApplyTargets = make(map[string][]string, len(c.ApplyTargets))
+		for service, targets := range c.ApplyTargets {
+			o.ApplyTargets[service] = append([]string{}, targets...)
+		}
+	}
+
+	o.ConsulToken = StringCopy(c.ConsulToken)
+
+	o.ConsulTokenFile = StringCopy(c.ConsulTokenFile)
+
+	if c.Backend != nil {
+		o.Backend = make(map[string]interface{}, len(c.Backend))
+		for k, v := range c.Backend {
+			o.Backend[k] = v
+		}
+	}
+
+	if c.Environments != nil {
+		o.Environments = make([]string, 0, len(c.Environments))
+		o.Environments = append(o.Environments, c.Environments...)
+	}
+
+	o.EnvironmentConfigs = c.EnvironmentConfigs.Copy()
+
+	if c.ExtraTemplates != nil {
+		o.ExtraTemplates = make([]string, 0, len(c.ExtraTemplates))
+		o.ExtraTemplates = append(o.ExtraTemplates, c.ExtraTemplates...)
+	}
+
+	o.Postconditions = c.Postconditions.Copy()
+
+	o.ComputedInputs = c.ComputedInputs.Copy()
+
+	o.PublishOutputs = c.PublishOutputs.Copy()
+
+	o.AlertmanagerSilence = c.AlertmanagerSilence.Copy()
+
+	o.Handlers = c.Handlers.Copy()
+
+	o.Gates = c.Gates.Copy()
+
 	if !isConditionNil(c.Condition) {
 		o.Condition = c.Condition.Copy()
 	}
@@ -189,8 +484,35 @@ func (c *TaskConfig) Merge(o *TaskConfig) *TaskConfig {
 		r.Name = StringCopy(o.Name)
 	}
 
+	if o.Labels != nil {
+		if r.Labels == nil {
+			r.Labels = make(map[string]string)
+		}
+		for k, v := range o.Labels {
+			r.Labels[k] = v
+		}
+	}
+
 	r.Providers = mergeSlices(r.Providers, o.Providers)
 
+	if o.ProviderOverrides != nil {
+		if r.ProviderOverrides == nil {
+			r.ProviderOverrides = make(map[string]map[string]interface{})
+		}
+		for id, args := range o.ProviderOverrides {
+			if _, ok := r.ProviderOverrides[id]; !ok {
+				r.ProviderOverrides[id] = make(map[string]interface{})
+			}
+			for k, v := range args {
+				r.ProviderOverrides[id][k] = v
+			}
+		}
+	}
+
+	if o.ProviderForeachDatacenter != nil {
+		r.ProviderForeachDatacenter = BoolCopy(o.ProviderForeachDatacenter)
+	}
+
 	r.DeprecatedServices = mergeSlices(r.DeprecatedServices, o.DeprecatedServices)
 
 	if o.Module != nil {
@@ -213,6 +535,10 @@ func (c *TaskConfig) Merge(o *TaskConfig) *TaskConfig {
 		r.Variables[k] = v
 	}
 
+	r.SensitiveVariables = mergeSlices(r.SensitiveVariables, o.SensitiveVariables)
+
+	r.VariablesFromConsulKV = mergeSlices(r.VariablesFromConsulKV, o.VariablesFromConsulKV)
+
 	if o.Version != nil {
 		r.Version = StringCopy(o.Version)
 	}
@@ -229,10 +555,118 @@ func (c *TaskConfig) Merge(o *TaskConfig) *TaskConfig {
 		r.BufferPeriod = r.BufferPeriod.Merge(o.BufferPeriod)
 	}
 
+	if o.Cooldown != nil {
+		r.Cooldown = TimeDurationCopy(o.Cooldown)
+	}
+
+	if o.Priority != nil {
+		r.Priority = IntCopy(o.Priority)
+	}
+
+	if o.InputSnapshots != nil {
+		r.InputSnapshots = IntCopy(o.InputSnapshots)
+	}
+
+	if o.CircuitBreaker != nil {
+		r.CircuitBreaker = r.CircuitBreaker.Merge(o.CircuitBreaker)
+	}
+
+	if o.MaintenanceWindow != nil {
+		r.MaintenanceWindow = r.MaintenanceWindow.Merge(o.MaintenanceWindow)
+	}
+
+	r.DependsOn = mergeSlices(r.DependsOn, o.DependsOn)
+
+	if o.SkipOnDependencyFailure != nil {
+		r.SkipOnDependencyFailure = BoolCopy(o.SkipOnDependencyFailure)
+	}
+
+	if o.ExpiresAt != nil {
+		r.ExpiresAt = TimeCopy(o.ExpiresAt)
+	}
+
+	if o.ExpireAction != nil {
+		r.ExpireAction = StringCopy(o.ExpireAction)
+	}
+
+	if o.TriggerOn != nil {
+		r.TriggerOn = StringCopy(o.TriggerOn)
+	}
+
 	if o.Enabled != nil {
 		r.Enabled = BoolCopy(o.Enabled)
 	}
 
+	if o.RenderOnly != nil {
+		r.RenderOnly = BoolCopy(o.RenderOnly)
+	}
+
+	if o.ServicesChanged != nil {
+		r.ServicesChanged = BoolCopy(o.ServicesChanged)
+	}
+
+	if o.TargetedApply != nil {
+		r.TargetedApply = BoolCopy(o.TargetedApply)
+	}
+
+	if o.ApplyTargets != nil {
+		if r.ApplyTargets == nil {
+			r.ApplyTargets = make(map[string][]string)
+		}
+		for service, targets := range o.ApplyTargets {
+			r.ApplyTargets[service] = append([]string{}, targets...)
+		}
+	}
+
+	if o.ConsulToken != nil {
+		r.ConsulToken = StringCopy(o.ConsulToken)
+	}
+
+	if o.ConsulTokenFile != nil {
+		r.ConsulTokenFile = StringCopy(o.ConsulTokenFile)
+	}
+
+	// The backend of the other configuration replaces the backend since only
+	// one backend can be configured
+	if len(o.Backend) > 0 {
+		r.Backend = make(map[string]interface{}, len(o.Backend))
+		for k, v := range o.Backend {
+			r.Backend[k] = v
+		}
+	}
+
+	r.Environments = mergeSlices(r.Environments, o.Environments)
+
+	if o.EnvironmentConfigs != nil {
+		r.EnvironmentConfigs = r.EnvironmentConfigs.Merge(o.EnvironmentConfigs)
+	}
+
+	r.ExtraTemplates = mergeSlices(r.ExtraTemplates, o.ExtraTemplates)
+
+	if o.Postconditions != nil {
+		r.Postconditions = r.Postconditions.Merge(o.Postconditions)
+	}
+
+	if o.ComputedInputs != nil {
+		r.ComputedInputs = r.ComputedInputs.Merge(o.ComputedInputs)
+	}
+
+	if o.PublishOutputs != nil {
+		r.PublishOutputs = r.PublishOutputs.Merge(o.PublishOutputs)
+	}
+
+	if o.AlertmanagerSilence != nil {
+		r.AlertmanagerSilence = r.AlertmanagerSilence.Merge(o.AlertmanagerSilence)
+	}
+
+	if o.Handlers != nil {
+		r.Handlers = r.Handlers.Merge(o.Handlers)
+	}
+
+	if o.Gates != nil {
+		r.Gates = r.Gates.Merge(o.Gates)
+	}
+
 	if !isConditionNil(o.Condition) {
 		if isConditionNil(r.Condition) {
 			r.Condition = o.Condition.Copy()
@@ -263,10 +697,22 @@ func (c *TaskConfig) Finalize() error {
 		c.Name = String("")
 	}
 
+	if c.Labels == nil {
+		c.Labels = make(map[string]string)
+	}
+
 	if c.Providers == nil {
 		c.Providers = []string{}
 	}
 
+	if c.ProviderOverrides == nil {
+		c.ProviderOverrides = make(map[string]map[string]interface{})
+	}
+
+	if c.ProviderForeachDatacenter == nil {
+		c.ProviderForeachDatacenter = Bool(false)
+	}
+
 	if c.DeprecatedServices == nil {
 		c.DeprecatedServices = []string{}
 	} else if len(c.DeprecatedServices) > 0 {
@@ -297,6 +743,14 @@ func (c *TaskConfig) Finalize() error {
 		return err
 	}
 
+	if c.SensitiveVariables == nil {
+		c.SensitiveVariables = []string{}
+	}
+
+	if c.VariablesFromConsulKV == nil {
+		c.VariablesFromConsulKV = []string{}
+	}
+
 	if c.Version == nil {
 		c.Version = String("")
 	}
@@ -310,10 +764,124 @@ func (c *TaskConfig) Finalize() error {
 		c.DeprecatedTFVersion = String("")
 	}
 
+	if c.Cooldown == nil {
+		c.Cooldown = TimeDuration(0 * time.Second)
+	}
+
+	if c.Priority == nil {
+		c.Priority = Int(0)
+	}
+
+	if c.InputSnapshots == nil {
+		c.InputSnapshots = Int(0)
+	}
+
+	if c.CircuitBreaker == nil {
+		c.CircuitBreaker = &CircuitBreakerConfig{}
+	}
+	c.CircuitBreaker.Finalize()
+
+	if c.MaintenanceWindow == nil {
+		c.MaintenanceWindow = &MaintenanceWindowConfig{}
+	}
+	c.MaintenanceWindow.Finalize()
+
+	if c.DependsOn == nil {
+		c.DependsOn = []string{}
+	}
+
+	if c.SkipOnDependencyFailure == nil {
+		c.SkipOnDependencyFailure = Bool(false)
+	}
+
+	if c.ExpireAction == nil {
+		c.ExpireAction = String(TaskExpireActionDisable)
+	}
+
+	if c.TriggerOn == nil {
+		c.TriggerOn = String(TaskTriggerOnConditionOnly)
+	}
+
 	if c.Enabled == nil {
 		c.Enabled = Bool(true)
 	}
 
+	if c.RenderOnly == nil {
+		c.RenderOnly = Bool(false)
+	}
+
+	if c.ServicesChanged == nil {
+		c.ServicesChanged = Bool(false)
+	}
+
+	if c.TargetedApply == nil {
+		c.TargetedApply = Bool(false)
+	}
+
+	if c.ApplyTargets == nil {
+		c.ApplyTargets = make(map[string][]string)
+	}
+
+	if c.ConsulToken == nil {
+		c.ConsulToken = String("")
+	}
+
+	if c.ConsulTokenFile == nil {
+		c.ConsulTokenFile = String("")
+	}
+
+	if c.Backend == nil {
+		c.Backend = make(map[string]interface{})
+	}
+
+	if c.Environments == nil {
+		c.Environments = []string{}
+	}
+
+	if c.EnvironmentConfigs == nil {
+		c.EnvironmentConfigs = &TaskEnvironmentConfigs{}
+	}
+	if err := c.EnvironmentConfigs.Finalize(c.Environments); err != nil {
+		return err
+	}
+
+	if c.ExtraTemplates == nil {
+		c.ExtraTemplates = []string{}
+	}
+
+	if c.Postconditions == nil {
+		c.Postconditions = &PostconditionConfigs{}
+	}
+	c.Postconditions.Finalize()
+
+	if c.ComputedInputs == nil {
+		c.ComputedInputs = ComputedInputConfigs{}
+	}
+	c.ComputedInputs.Finalize()
+
+	if c.PublishOutputs == nil {
+		c.PublishOutputs = &PublishOutputsConfig{}
+	}
+	c.PublishOutputs.Finalize()
+	if *c.PublishOutputs.Enabled && *c.PublishOutputs.Path == "" {
+		c.PublishOutputs.Path = String(DefaultPublishOutputsPathPrefix + *c.Name)
+	}
+
+	if c.AlertmanagerSilence == nil {
+		c.AlertmanagerSilence = &AlertmanagerSilenceConfig{}
+	}
+	c.AlertmanagerSilence.Finalize()
+
+	if c.Handlers == nil {
+		c.Handlers = &HandlerConfigs{}
+	}
+	c.Handlers.Finalize()
+
+	if c.Gates == nil {
+		c.Gates = &GateConfigs{}
+	}
+	c.Gates.Finalize()
+
 	if isConditionNil(c.Condition) {
 		c.Condition = EmptyConditionConfig()
 	}
@@ -382,6 +950,10 @@ func (c *TaskConfig) SetVariables() error {
 		c.Variables = make(map[string]string)
 	}
 
+	if err := c.evaluateVariables(); err != nil {
+		return err
+	}
+
 	if len(c.VarFiles) > 0 {
 		for _, vf := range c.VarFiles {
 			f, err := os.Open(vf)
@@ -423,15 +995,19 @@ func (c *TaskConfig) Validate() error {
 		return err
 	}
 
+	if err := c.validateLabels(); err != nil {
+		return err
+	}
+
 	if c.Module == nil || len(*c.Module) == 0 {
 		return fmt.Errorf("module for the task is required")
 	}
 
 	if c.DeprecatedTFVersion != nil && *c.DeprecatedTFVersion != "" {
-		return fmt.Errorf("unsupported configuration 'terraform_version' for "+
-			"task %q. This option is available for Consul-Terraform-Sync Enterprise "+
-			"when using the Terraform Cloud driver, or configure the Terraform client "+
-			"version within the Terraform driver block", *c.Name)
+		if err := validateTerraformVersion(*c.DeprecatedTFVersion); err != nil {
+			return fmt.Errorf("invalid 'terraform_version' for task %q: %s",
+				*c.Name, err)
+		}
 	}
 
 	if c.TFCWorkspace != nil && !c.TFCWorkspace.IsEmpty() {
@@ -440,6 +1016,67 @@ func (c *TaskConfig) Validate() error {
 			"when using the Terraform Cloud driver", *c.Name)
 	}
 
+	if c.Cooldown != nil && *c.Cooldown < 0 {
+		return fmt.Errorf("cooldown for task %q cannot be negative: %s",
+			*c.Name, *c.Cooldown)
+	}
+
+	if c.InputSnapshots != nil && *c.InputSnapshots < 0 {
+		return fmt.Errorf("input_snapshots for task %q cannot be negative: %d",
+			*c.Name, *c.InputSnapshots)
+	}
+
+	if TimeDurationVal(c.Cooldown) > 0 {
+		if _, ok := c.Condition.(*ScheduleConditionConfig); ok {
+			return fmt.Errorf("cooldown is not supported for task %q with a "+
+				"schedule condition", *c.Name)
+		}
+	}
+
+	if err := c.CircuitBreaker.Validate(); err != nil {
+		return fmt.Errorf("invalid circuit_breaker for task %q: %s", *c.Name, err)
+	}
+
+	if err := c.MaintenanceWindow.Validate(); err != nil {
+		return fmt.Errorf("invalid maintenance_window for task %q: %s", *c.Name, err)
+	}
+
+	if c.MaintenanceWindow != nil && BoolVal(c.MaintenanceWindow.Enabled) {
+		if _, ok := c.Condition.(*ScheduleConditionConfig); ok {
+			return fmt.Errorf("maintenance_window is not supported for task %q "+
+				"with a schedule condition", *c.Name)
+		}
+	}
+
+	if err := c.validateDependsOn(); err != nil {
+		return err
+	}
+
+	if c.ExpireAction != nil {
+		switch *c.ExpireAction {
+		case TaskExpireActionDisable, TaskExpireActionDelete:
+		default:
+			return fmt.Errorf("expire_action for task %q must be %q or %q: %q",
+				*c.Name, TaskExpireActionDisable, TaskExpireActionDelete,
+				*c.ExpireAction)
+		}
+	}
+
+	if c.TriggerOn != nil {
+		switch *c.TriggerOn {
+		case TaskTriggerOnConditionOnly:
+		case TaskTriggerOnAnyInputChange:
+			if _, ok := c.Condition.(*ScheduleConditionConfig); ok {
+				return fmt.Errorf("trigger_on %q is not supported for task %q "+
+					"with a schedule condition", TaskTriggerOnAnyInputChange, *c.Name)
+			}
+		default:
+			return fmt.Errorf("trigger_on for task %q must be %q or %q: %q",
+				*c.Name, TaskTriggerOnConditionOnly, TaskTriggerOnAnyInputChange,
+				*c.TriggerOn)
+		}
+	}
+
 	// Restrict only one provider instance per task
 	pNames := make(map[string]bool)
 	for _, p := range c.Providers {
@@ -450,6 +1087,73 @@ func (c *TaskConfig) Validate() error {
 		pNames[name] = true
 	}
 
+	if err := c.validateProviderOverrides(); err != nil {
+		return err
+	}
+
+	if err := c.validateProviderForeachDatacenter(); err != nil {
+		return err
+	}
+
+	if err := c.validateTargetedApply(); err != nil {
+		return err
+	}
+
+	if err := c.validateSensitiveVariables(); err != nil {
+		return err
+	}
+
+	if err := c.validateVariablesFromConsulKV(); err != nil {
+		return err
+	}
+
+	if StringPresent(c.ConsulToken) && StringPresent(c.ConsulTokenFile) {
+		return fmt.Errorf("consul_token and consul_token_file cannot both be "+
+			"configured for task %q", *c.Name)
+	}
+
+	if len(c.Backend) > 1 {
+		return fmt.Errorf("only one backend can be configured for task %q",
+			*c.Name)
+	}
+	for name, args := range c.Backend {
+		if err := validateBackend(name, args); err != nil {
+			return fmt.Errorf("invalid backend for task %q: %s", *c.Name, err)
+		}
+	}
+
+	if err := c.validateEnvironments(); err != nil {
+		return err
+	}
+
+	if err := c.validateExtraTemplates(); err != nil {
+		return err
+	}
+
+	if err := c.Postconditions.Validate(); err != nil {
+		return fmt.Errorf("invalid postcondition for task %q: %s", *c.Name, err)
+	}
+
+	if err := c.validateComputedInputs(); err != nil {
+		return err
+	}
+
+	if err := c.PublishOutputs.Validate(); err != nil {
+		return fmt.Errorf("invalid publish_outputs for task %q: %s", *c.Name, err)
+	}
+
+	if err := c.AlertmanagerSilence.Validate(); err != nil {
+		return fmt.Errorf("invalid alertmanager_silence for task %q: %s", *c.Name, err)
+	}
+
+	if err := c.Handlers.Validate(); err != nil {
+		return fmt.Errorf("invalid handler for task %q: %s", *c.Name, err)
+	}
+
+	if err := c.Gates.Validate(); err != nil {
+		return fmt.Errorf("invalid gate for task %q: %s", *c.Name, err)
+	}
+
 	if !isConditionNil(c.Condition) {
 		if err := c.Condition.Validate(); err != nil {
 			return err
@@ -463,6 +1167,278 @@ func (c *TaskConfig) Validate() error {
 	return nil
 }
 
+// validateComputedInputs validates the computed inputs of the task. The name
+// of a computed input cannot be the name of another variable of the module.
+func (c *TaskConfig) validateComputedInputs() error {
+	if err := c.ComputedInputs.Validate(); err != nil {
+		return fmt.Errorf("invalid computed_input for task %q: %s", *c.Name, err)
+	}
+
+	reserved := map[string]bool{"services": true, "services_changed": true}
+	if !isConditionNil(c.Condition) {
+		reserved[c.Condition.VariableType()] = true
+	}
+	if c.ModuleInputs != nil {
+		for _, mi := range *c.ModuleInputs {
+			reserved[mi.VariableType()] = true
+		}
+	}
+
+	for name := range c.ComputedInputs {
+		if _, ok := c.Variables[name]; ok {
+			return fmt.Errorf("computed_input %q of task %q has the same name "+
+				"as a variable of the task", name, *c.Name)
+		}
+		if reserved[name] {
+			return fmt.Errorf("computed_input %q of task %q has the same name "+
+				"as a variable generated by CTS", name, *c.Name)
+		}
+	}
+
+	return nil
+}
+
+// validateTargetedApply validates that the apply targets are configured when
+// targeted apply is enabled for the task
+func (c *TaskConfig) validateTargetedApply() error {
+	if !BoolVal(c.TargetedApply) {
+		return nil
+	}
+
+	if BoolVal(c.RenderOnly) {
+		return fmt.Errorf("targeted_apply is not supported for task %q with "+
+			"render_only enabled", *c.Name)
+	}
+
+	if len(c.ApplyTargets) == 0 {
+		return fmt.Errorf("apply_targets is required for task %q with "+
+			"targeted_apply enabled", *c.Name)
+	}
+
+	for service, targets := range c.ApplyTargets {
+		if len(targets) == 0 {
+			return fmt.Errorf("apply_targets for service %q of task %q "+
+				"requires at least one resource address", service, *c.Name)
+		}
+		for _, target := range targets {
+			if strings.TrimSpace(target) == "" {
+				return fmt.Errorf("apply_targets for service %q of task %q "+
+					"cannot contain an empty resource address", service, *c.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateLabels validates that the keys of the labels are not empty and only
+// contain characters that can be used in label selectors
+func (c *TaskConfig) validateLabels() error {
+	for k := range c.Labels {
+		if !labelKeyRegexp.MatchString(k) {
+			return fmt.Errorf("label %q for task %q must start with a letter or "+
+				"digit and may contain only letters, digits, underscores, dashes, "+
+				"dots, and slashes", k, *c.Name)
+		}
+	}
+	return nil
+}
+
+// HasLabels returns true if the task has all of the labels of the selector.
+// All tasks match an empty selector.
+func (c *TaskConfig) HasLabels(selector map[string]string) bool {
+	for k, v := range selector {
+		if value, ok := c.Labels[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseLabelSelector parses a label selector of comma-separated key=value
+// pairs, e.g. "team=neteng,env=prod"
+func ParseLabelSelector(s string) (map[string]string, error) {
+	selector := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(pair, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid label selector %q, expected "+
+				"comma-separated key=value pairs", s)
+		}
+		selector[k] = strings.TrimSpace(v)
+	}
+	return selector, nil
+}
+
+// validateSensitiveVariables validates that the sensitive variables are
+// variables of the task. The variables from Consul KV are only known once
+// they are loaded, so any sensitive variable is allowed with Consul KV
+// prefixes.
+func (c *TaskConfig) validateSensitiveVariables() error {
+	if len(c.VariablesFromConsulKV) > 0 {
+		return nil
+	}
+	for _, name := range c.SensitiveVariables {
+		if _, ok := c.Variables[name]; !ok {
+			return fmt.Errorf("sensitive variable %q is not a variable of "+
+				"task %q", name, *c.Name)
+		}
+	}
+	return nil
+}
+
+// validateVariablesFromConsulKV validates that the Consul KV prefixes of the
+// variables are not empty or duplicated
+func (c *TaskConfig) validateVariablesFromConsulKV() error {
+	seen := make(map[string]bool, len(c.VariablesFromConsulKV))
+	for _, prefix := range c.VariablesFromConsulKV {
+		if strings.TrimSpace(prefix) == "" {
+			return fmt.Errorf("variables_from_consul_kv of task %q cannot "+
+				"contain an empty prefix", *c.Name)
+		}
+		if seen[prefix] {
+			return fmt.Errorf("variables_from_consul_kv of task %q contains "+
+				"duplicate prefix %q", *c.Name, prefix)
+		}
+		seen[prefix] = true
+	}
+	return nil
+}
+
+// validateEnvironments validates the environments of the task. The variables
+// of an environment must override variables of the task, and the Terraform
+// outputs of a task with environments are not supported since each
+// environment has its own outputs.
+func (c *TaskConfig) validateEnvironments() error {
+	if err := c.EnvironmentConfigs.Validate(c.Environments); err != nil {
+		return fmt.Errorf("invalid environments for task %q: %s", *c.Name, err)
+	}
+
+	if len(c.Environments) == 0 {
+		return nil
+	}
+
+	if BoolVal(c.RenderOnly) {
+		return fmt.Errorf("environments are not supported for task %q with "+
+			"render_only enabled", *c.Name)
+	}
+
+	if c.Postconditions.Len() > 0 {
+		return fmt.Errorf("environments are not supported for task %q with "+
+			"postconditions", *c.Name)
+	}
+
+	if c.PublishOutputs != nil && BoolVal(c.PublishOutputs.Enabled) {
+		return fmt.Errorf("environments are not supported for task %q with "+
+			"publish_outputs enabled", *c.Name)
+	}
+
+	if c.EnvironmentConfigs == nil {
+		return nil
+	}
+	for _, e := range *c.EnvironmentConfigs {
+		for k := range e.Variables {
+			if _, ok := c.Variables[k]; !ok {
+				return fmt.Errorf("environment %q of task %q overrides variable "+
+					"%q, which is not set for the task", StringVal(e.Name),
+					*c.Name, k)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateDependsOn validates the names of the tasks that the task depends
+// on. The names are validated against the other tasks by TaskConfigs.
+func (c *TaskConfig) validateDependsOn() error {
+	if len(c.DependsOn) == 0 {
+		return nil
+	}
+
+	if _, ok := c.Condition.(*ScheduleConditionConfig); ok {
+		return fmt.Errorf("depends_on is not supported for task %q with a "+
+			"schedule condition", *c.Name)
+	}
+
+	seen := make(map[string]bool, len(c.DependsOn))
+	for _, name := range c.DependsOn {
+		if strings.TrimSpace(name) == "" {
+			return fmt.Errorf("depends_on for task %q cannot contain an empty "+
+				"task name", *c.Name)
+		}
+		if name == *c.Name {
+			return fmt.Errorf("task %q cannot depend on itself", *c.Name)
+		}
+		if seen[name] {
+			return fmt.Errorf("depends_on for task %q contains duplicate task "+
+				"%q", *c.Name, name)
+		}
+		seen[name] = true
+	}
+
+	return nil
+}
+
+// ExtraTemplateFilename returns the name of the file that an extra template
+// is rendered to in the task's working directory
+func ExtraTemplateFilename(tmplPath string) string {
+	name := filepath.Base(tmplPath)
+	for _, ext := range []string{".tmpl", ".tpl", ".tftpl"} {
+		if strings.HasSuffix(name, ext) {
+			return strings.TrimSuffix(name, ext)
+		}
+	}
+	return name
+}
+
+// validateExtraTemplates validates that each extra template renders to a
+// unique file that is not generated by CTS for the task
+func (c *TaskConfig) validateExtraTemplates() error {
+	reserved := map[string]bool{
+		tftmpl.RootFilename:             true,
+		tftmpl.VarsFilename:             true,
+		tftmpl.ModuleVarsFilename:       true,
+		tftmpl.TFVarsFilename:           true,
+		tftmpl.VarsTFVarsFileName:       true,
+		tftmpl.SecretsTFVarsFilename:    true,
+		tftmpl.TFVarsTmplFilename:       true,
+		tftmpl.ProvidersTFVarsFilename:  true,
+		tftmpl.ComputedTFVarsFilename:   true,
+		tftmpl.ServicesChangedFilename:  true,
+		tftmpl.ServicesSnapshotFilename: true,
+	}
+
+	filenames := make(map[string]string, len(c.ExtraTemplates))
+	for _, tmplPath := range c.ExtraTemplates {
+		if strings.TrimSpace(tmplPath) == "" {
+			return fmt.Errorf("extra_templates for task %q cannot contain an "+
+				"empty path", *c.Name)
+		}
+
+		name := ExtraTemplateFilename(tmplPath)
+		if name == "" || name == "." || name == string(filepath.Separator) {
+			return fmt.Errorf("extra_templates path %q for task %q does not "+
+				"have a file name", tmplPath, *c.Name)
+		}
+		if reserved[name] {
+			return fmt.Errorf("extra_templates path %q for task %q renders to "+
+				"%q, which is a file generated by CTS", tmplPath, *c.Name, name)
+		}
+		if other, ok := filenames[name]; ok {
+			return fmt.Errorf("extra_templates paths %q and %q for task %q "+
+				"both render to %q", other, tmplPath, *c.Name, name)
+		}
+		filenames[name] = tmplPath
+	}
+
+	return nil
+}
+
 // ValidateForDriver validates all remaining values and required options that were not checked during
 // the normal Validate() call. This method is recommended to run after:
 //   - Finalize()
@@ -494,32 +1470,103 @@ func (c *TaskConfig) GoString() string {
 	return fmt.Sprintf("&TaskConfig{"+
 		"Name:%s, "+
 		"Description:%s, "+
+		"Labels:%v, "+
 		"Providers:%s, "+
+		"ProviderOverrides:%s, "+
+		"ProviderForeachDatacenter:%t, "+
 		"Services (deprecated):%s, "+
 		"Module:%s, "+
 		"VarFiles:%s, "+
+		"SensitiveVariables:%s, "+
+		"VariablesFromConsulKV:%s, "+
 		"Version:%s, "+
 		"TFVersion: %s, "+
 		"BufferPeriod:%s, "+
+		"Cooldown:%s, "+
+		"Priority:%d, "+
+		"InputSnapshots:%d, "+
+		"CircuitBreaker:%s, "+
+		"MaintenanceWindow:%s, "+
+		"DependsOn:%s, "+
+		"SkipOnDependencyFailure:%t, "+
+		"ExpiresAt:%s, "+
+		"ExpireAction:%s, "+
+		"TriggerOn:%s, "+
 		"Enabled:%t, "+
+		"RenderOnly:%t, "+
+		"ServicesChanged:%t, "+
+		"TargetedApply:%t, "+
+		"ApplyTargets:%v, "+
+		"ConsulToken:%s, "+
+		"ConsulTokenFile:%s, "+
+		"Backend:%+v, "+
+		"Environments:%s, "+
+		"EnvironmentConfigs:%s, "+
+		"ExtraTemplates:%s, "+
+		"Postconditions:%s, "+
+		"ComputedInputs:%s, "+
+		"PublishOutputs:%s, "+
+		"AlertmanagerSilence:%s, "+
+		"Handlers:%s, "+
+		"Gates:%s, "+
 		"Condition:%s, "+
 		"ModuleInput:%s"+
 		"}",
 		StringVal(c.Name),
 		StringVal(c.Description),
+		c.Labels,
 		c.Providers,
+		providerOverridesGoString(c.ProviderOverrides),
+		BoolVal(c.ProviderForeachDatacenter),
 		c.DeprecatedServices,
 		StringVal(c.Module),
 		c.VarFiles,
+		c.SensitiveVariables,
+		c.VariablesFromConsulKV,
 		StringVal(c.Version),
 		StringVal(c.DeprecatedTFVersion),
 		c.BufferPeriod.GoString(),
+		TimeDurationVal(c.Cooldown),
+		IntVal(c.Priority),
+		IntVal(c.InputSnapshots),
+		c.CircuitBreaker.GoString(),
+		c.MaintenanceWindow.GoString(),
+		c.DependsOn,
+		BoolVal(c.SkipOnDependencyFailure),
+		expiresAtGoString(c.ExpiresAt),
+		StringVal(c.ExpireAction),
+		StringVal(c.TriggerOn),
 		BoolVal(c.Enabled),
+		BoolVal(c.RenderOnly),
+		BoolVal(c.ServicesChanged),
+		BoolVal(c.TargetedApply),
+		c.ApplyTargets,
+		sensitiveGoString(c.ConsulToken),
+		StringVal(c.ConsulTokenFile),
+		c.Backend,
+		c.Environments,
+		c.EnvironmentConfigs.GoString(),
+		c.ExtraTemplates,
+		c.Postconditions.GoString(),
+		c.ComputedInputs.GoString(),
+		c.PublishOutputs.GoString(),
+		c.AlertmanagerSilence.GoString(),
+		c.Handlers.GoString(),
+		c.Gates.GoString(),
 		c.Condition.GoString(),
 		c.ModuleInputs.GoString(),
 	)
 }
 
+// expiresAtGoString returns the printable version of the expiration time of a
+// task
+func expiresAtGoString(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
 // DefaultTaskConfigs returns a configuration that is populated with the
 // default values.
 func DefaultTaskConfigs() *TaskConfigs {
@@ -608,9 +1655,92 @@ func (c *TaskConfigs) Validate() error {
 		unique[taskName] = true
 	}
 
+	for _, t := range *c {
+		for _, dep := range t.DependsOn {
+			if !unique[dep] {
+				return fmt.Errorf("task %q depends on task %q, which does not "+
+					"exist", *t.Name, dep)
+			}
+		}
+	}
+
+	if _, err := orderTasks(*c); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// OrderByPriority returns the tasks ordered from the highest to the lowest
+// priority. Tasks with the same priority keep their order.
+func OrderByPriority(tasks TaskConfigs) TaskConfigs {
+	ordered := make(TaskConfigs, len(tasks))
+	copy(ordered, tasks)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return IntVal(ordered[i].Priority) > IntVal(ordered[j].Priority)
+	})
+	return ordered
+}
+
+// OrderByDependencies returns the tasks ordered so that each task comes after
+// the tasks that it depends on. Tasks are otherwise kept in their order.
+// Dependencies on tasks that are not in the list are ignored.
+func OrderByDependencies(tasks TaskConfigs) TaskConfigs {
+	ordered, err := orderTasks(tasks)
+	if err != nil {
+		// cycles are rejected by validation
+		return tasks
+	}
+	return ordered
+}
+
+// orderTasks orders the tasks after their dependencies with a depth-first
+// search. Returns an error if the dependencies of the tasks form a cycle.
+func orderTasks(tasks TaskConfigs) (TaskConfigs, error) {
+	byName := make(map[string]*TaskConfig, len(tasks))
+	for _, t := range tasks {
+		byName[StringVal(t.Name)] = t
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	states := make(map[string]int, len(tasks))
+	ordered := make(TaskConfigs, 0, len(tasks))
+
+	var visit func(t *TaskConfig, path []string) error
+	visit = func(t *TaskConfig, path []string) error {
+		name := StringVal(t.Name)
+		switch states[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("tasks have a dependency cycle: %s",
+				strings.Join(append(path, name), " -> "))
+		}
+
+		states[name] = visiting
+		for _, dep := range t.DependsOn {
+			if d, ok := byName[dep]; ok {
+				if err := visit(d, append(path, name)); err != nil {
+					return err
+				}
+			}
+		}
+		states[name] = visited
+		ordered = append(ordered, t)
+		return nil
+	}
+
+	for _, t := range tasks {
+		if err := visit(t, nil); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
 // GoString defines the printable version of this struct.
 func (c *TaskConfigs) GoString() string {
 	if c == nil {
@@ -643,6 +1773,151 @@ func FilterTasks(tasks *TaskConfigs, names []string) (*TaskConfigs, error) {
 	return &filtered, nil
 }
 
+// validateProviderOverrides validates that provider overrides are only
+// configured for providers used by the task and do not override the alias,
+// which identifies the provider configuration to override.
+func (c *TaskConfig) validateProviderOverrides() error {
+	for id, args := range c.ProviderOverrides {
+		var found bool
+		for _, p := range c.Providers {
+			if p == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("provider_overrides for task %q configured for "+
+				"provider %q that is not in the task's providers", *c.Name, id)
+		}
+
+		if _, ok := args["alias"]; ok {
+			return fmt.Errorf("provider_overrides for task %q cannot override "+
+				"the 'alias' argument of provider %q", *c.Name, id)
+		}
+	}
+	return nil
+}
+
+// validateProviderForeachDatacenter validates that a task that uses an
+// instance of its providers for each datacenter monitors at least one
+// datacenter and that its providers are not aliased, since the datacenter is
+// the alias of each instance.
+func (c *TaskConfig) validateProviderForeachDatacenter() error {
+	if !BoolVal(c.ProviderForeachDatacenter) {
+		return nil
+	}
+
+	if len(c.Providers) == 0 {
+		return fmt.Errorf("provider_foreach_datacenter for task %q requires "+
+			"the task to have providers", *c.Name)
+	}
+
+	for _, p := range c.Providers {
+		if strings.Contains(p, ".") {
+			return fmt.Errorf("provider_foreach_datacenter for task %q does "+
+				"not support the provider alias %q. The datacenter is used as "+
+				"the alias of each provider instance", *c.Name, p)
+		}
+	}
+
+	dcs := c.Datacenters()
+	if len(dcs) == 0 {
+		return fmt.Errorf("provider_foreach_datacenter for task %q requires "+
+			"the datacenter to be configured for the task's condition or "+
+			"module_input blocks", *c.Name)
+	}
+
+	for _, dc := range dcs {
+		if !hclsyntax.ValidIdentifier(dc) {
+			return fmt.Errorf("provider_foreach_datacenter for task %q "+
+				"requires datacenter %q to be a valid provider alias", *c.Name, dc)
+		}
+	}
+	return nil
+}
+
+// Datacenters returns the sorted, unique datacenters that are configured for
+// the condition and module_input blocks of the task. Blocks without a
+// datacenter monitor the datacenter of the Consul agent and are not included.
+func (c *TaskConfig) Datacenters() []string {
+	var monitors []MonitorConfig
+	if !isConditionNil(c.Condition) {
+		monitors = append(monitors, c.Condition)
+	}
+	if c.ModuleInputs != nil {
+		for _, mi := range *c.ModuleInputs {
+			monitors = append(monitors, mi)
+		}
+	}
+
+	seen := make(map[string]bool)
+	var dcs []string
+	for _, m := range monitors {
+		dm, ok := m.(datacenterMonitor)
+		if !ok {
+			continue
+		}
+		dc := dm.datacenter()
+		if dc == "" || seen[dc] {
+			continue
+		}
+		seen[dc] = true
+		dcs = append(dcs, dc)
+	}
+	sort.Strings(dcs)
+	return dcs
+}
+
+// ConsulReferenceError is the error returned when a task references Consul
+// resources, e.g. a datacenter or namespace, that do not exist in the
+// connected Consul
+type ConsulReferenceError struct {
+	Task       string
+	References []string
+}
+
+func (e *ConsulReferenceError) Error() string {
+	return fmt.Sprintf("task %q references Consul resources that do not "+
+		"exist in the connected Consul:\n  - %s", e.Task,
+		strings.Join(e.References, "\n  - "))
+}
+
+// copyProviderArgs returns a copy of the provider arguments. Nested values
+// are not copied.
+func copyProviderArgs(args map[string]interface{}) map[string]interface{} {
+	if args == nil {
+		return nil
+	}
+
+	o := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		o[k] = v
+	}
+	return o
+}
+
+// providerOverridesGoString returns the printable version of provider
+// overrides. Argument values may be sensitive, so only the provider IDs and
+// argument names are included.
+func providerOverridesGoString(overrides map[string]map[string]interface{}) []string {
+	ids := make([]string, 0, len(overrides))
+	for id := range overrides {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		names := make([]string, 0, len(overrides[id]))
+		for name := range overrides[id] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		parts = append(parts, fmt.Sprintf("%s:%s", id, names))
+	}
+	return parts
+}
+
 // validateCondition validates condition block taking into account services list
 //   - ensure task is configured with a condition (condition block or services
 //     list)
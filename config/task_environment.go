@@ -0,0 +1,297 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// environmentNameRegexp restricts environment names to characters that are
+// safe for file names and Terraform workspace names
+var environmentNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// TaskEnvironmentConfig configures the overrides of an environment of a task.
+// A task with environments runs Terraform for each environment when it is
+// triggered, each with its own workspace and backend state.
+type TaskEnvironmentConfig struct {
+	// Name is the name of the environment. The name must be listed in the
+	// environments of the task.
+	Name *string `mapstructure:"name" json:"name"`
+
+	// Workspace is the name of the Terraform workspace of the environment.
+	// Defaults to the workspace of the task suffixed with the environment
+	// name, e.g. "task-staging".
+	Workspace *string `mapstructure:"workspace" json:"workspace"`
+
+	// VarFiles is a list of paths to files containing variables that
+	// override the values of the task's variables for the environment. The
+	// variables must be set for the task. VarFiles are read into the
+	// Variables map like the variable files of the task.
+	VarFiles []string `mapstructure:"variable_files" json:"variable_files"`
+
+	// Variables are the variables of the environment loaded from VarFiles
+	Variables map[string]string `mapstructure:"variables" json:"variables"`
+
+	// BackendKey overrides the key of the state in the backend for the
+	// environment, e.g. the path for the Consul backend or the key for the S3
+	// backend. Defaults to the key of the task's backend.
+	BackendKey *string `mapstructure:"backend_key" json:"backend_key"`
+}
+
+// TaskEnvironmentConfigs is a collection of TaskEnvironmentConfig
+type TaskEnvironmentConfigs []*TaskEnvironmentConfig
+
+// Copy returns a deep copy of this configuration.
+func (c *TaskEnvironmentConfig) Copy() *TaskEnvironmentConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o TaskEnvironmentConfig
+	o.Name = StringCopy(c.Name)
+	o.Workspace = StringCopy(c.Workspace)
+	if c.VarFiles != nil {
+		o.VarFiles = make([]string, 0, len(c.VarFiles))
+		o.VarFiles = append(o.VarFiles, c.VarFiles...)
+	}
+	if c.Variables != nil {
+		o.Variables = make(map[string]string, len(c.Variables))
+		for k, v := range c.Variables {
+			o.Variables[k] = v
+		}
+	}
+	o.BackendKey = StringCopy(c.BackendKey)
+	return &o
+}
+
+// Finalize ensures there are no nil pointers and loads the variables of the
+// variable files.
+func (c *TaskEnvironmentConfig) Finalize() error {
+	if c == nil {
+		return nil
+	}
+
+	if c.Name == nil {
+		c.Name = String("")
+	}
+
+	if c.Workspace == nil {
+		c.Workspace = String("")
+	}
+
+	if c.VarFiles == nil {
+		c.VarFiles = []string{}
+	}
+
+	if c.Variables == nil {
+		c.Variables = make(map[string]string)
+	}
+	for _, vf := range c.VarFiles {
+		f, err := os.Open(vf)
+		if err != nil {
+			return err
+		}
+		err = readToVariablesMap(vf, f, c.Variables)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.BackendKey == nil {
+		c.BackendKey = String("")
+	}
+
+	return nil
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *TaskEnvironmentConfig) Validate() error {
+	if c == nil {
+		return fmt.Errorf("environment: missing configuration")
+	}
+
+	name := StringVal(c.Name)
+	if !environmentNameRegexp.MatchString(name) {
+		return fmt.Errorf("environment: name %q must only contain letters, "+
+			"digits, underscores, and dashes", name)
+	}
+
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *TaskEnvironmentConfig) GoString() string {
+	if c == nil {
+		return "(*TaskEnvironmentConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&TaskEnvironmentConfig{"+
+		"Name:%s, "+
+		"Workspace:%s, "+
+		"VarFiles:%s, "+
+		"Variables:%s, "+
+		"BackendKey:%s"+
+		"}",
+		StringVal(c.Name),
+		StringVal(c.Workspace),
+		c.VarFiles,
+		c.variablesGoString(),
+		StringVal(c.BackendKey),
+	)
+}
+
+// variablesGoString returns the names of the variables. Values are omitted
+// since variables may be sensitive.
+func (c *TaskEnvironmentConfig) variablesGoString() string {
+	names := make([]string, 0, len(c.Variables))
+	for k := range c.Variables {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return "[" + strings.Join(names, ", ") + "]"
+}
+
+// Len is a helper method to get the length of the underlying config list
+func (c *TaskEnvironmentConfigs) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	return len(*c)
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *TaskEnvironmentConfigs) Copy() *TaskEnvironmentConfigs {
+	if c == nil {
+		return nil
+	}
+
+	o := make(TaskEnvironmentConfigs, c.Len())
+	for i, e := range *c {
+		o[i] = e.Copy()
+	}
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration. Environments of the other configuration are appended.
+func (c *TaskEnvironmentConfigs) Merge(o *TaskEnvironmentConfigs) *TaskEnvironmentConfigs {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+	for _, e := range *o {
+		*r = append(*r, e.Copy())
+	}
+
+	return r
+}
+
+// Finalize ensures the configuration has no nil pointers and that there is
+// a configuration for each of the names of the environments. The
+// configurations are ordered by the names, followed by any configurations
+// for environments that are not named.
+func (c *TaskEnvironmentConfigs) Finalize(names []string) error {
+	if c == nil {
+		return nil
+	}
+
+	for _, e := range *c {
+		if err := e.Finalize(); err != nil {
+			return err
+		}
+	}
+
+	byName := make(map[string]*TaskEnvironmentConfig, len(*c))
+	for _, e := range *c {
+		if _, ok := byName[*e.Name]; !ok {
+			byName[*e.Name] = e
+		}
+	}
+
+	ordered := make(TaskEnvironmentConfigs, 0, len(*c)+len(names))
+	named := make(map[*TaskEnvironmentConfig]bool, len(names))
+	for _, name := range names {
+		e, ok := byName[name]
+		if !ok {
+			e = &TaskEnvironmentConfig{Name: String(name)}
+			if err := e.Finalize(); err != nil {
+				return err
+			}
+		}
+		named[e] = true
+		ordered = append(ordered, e)
+	}
+	for _, e := range *c {
+		if !named[e] {
+			ordered = append(ordered, e)
+		}
+	}
+	*c = ordered
+	return nil
+}
+
+// Validate validates the values and nested values of the configuration
+// struct. The configured environments must be named and unique.
+func (c *TaskEnvironmentConfigs) Validate(names []string) error {
+	listed := make(map[string]bool, len(names))
+	for _, name := range names {
+		if listed[name] {
+			return fmt.Errorf("environments: duplicate environment %q", name)
+		}
+		listed[name] = true
+	}
+
+	if c == nil {
+		// config is not required, return early
+		return nil
+	}
+
+	configured := make(map[string]bool, len(*c))
+	for _, e := range *c {
+		if err := e.Validate(); err != nil {
+			return err
+		}
+
+		name := StringVal(e.Name)
+		if !listed[name] {
+			return fmt.Errorf("environment: %q is not listed in environments", name)
+		}
+		if configured[name] {
+			return fmt.Errorf("environment: duplicate configuration for "+
+				"environment %q", name)
+		}
+		configured[name] = true
+	}
+
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *TaskEnvironmentConfigs) GoString() string {
+	if c == nil {
+		return "(*TaskEnvironmentConfigs)(nil)"
+	}
+
+	s := make([]string, len(*c))
+	for i, e := range *c {
+		s[i] = e.GoString()
+	}
+
+	return "{" + strings.Join(s, ", ") + "}"
+}
@@ -0,0 +1,159 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskEnvironmentConfigs_Copy(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *TaskEnvironmentConfigs
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&TaskEnvironmentConfigs{},
+		},
+		{
+			"fully configured",
+			&TaskEnvironmentConfigs{
+				{
+					Name:       String("prod"),
+					Workspace:  String("web-production"),
+					VarFiles:   []string{"prod.tfvars"},
+					Variables:  map[string]string{"size": `"large"`},
+					BackendKey: String("cts/prod"),
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tc.a.Copy()
+			assert.Equal(t, tc.a, r)
+		})
+	}
+}
+
+func TestTaskEnvironmentConfigs_Finalize(t *testing.T) {
+	t.Parallel()
+
+	varFile := filepath.Join(t.TempDir(), "prod.tfvars")
+	require.NoError(t, os.WriteFile(varFile, []byte(`size = "large"`), 0644))
+
+	prod := &TaskEnvironmentConfig{
+		Name:     String("prod"),
+		VarFiles: []string{varFile},
+	}
+	c := &TaskEnvironmentConfigs{prod}
+	require.NoError(t, c.Finalize([]string{"staging", "prod"}))
+
+	expected := &TaskEnvironmentConfigs{
+		{
+			Name:       String("staging"),
+			Workspace:  String(""),
+			VarFiles:   []string{},
+			Variables:  map[string]string{},
+			BackendKey: String(""),
+		},
+		{
+			Name:       String("prod"),
+			Workspace:  String(""),
+			VarFiles:   []string{varFile},
+			Variables:  map[string]string{"size": `"large"`},
+			BackendKey: String(""),
+		},
+	}
+	assert.Equal(t, expected, c)
+
+	missing := &TaskEnvironmentConfigs{{
+		Name:     String("prod"),
+		VarFiles: []string{filepath.Join(t.TempDir(), "missing.tfvars")},
+	}}
+	assert.Error(t, missing.Finalize([]string{"prod"}))
+}
+
+func TestTaskEnvironmentConfigs_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		names   []string
+		c       *TaskEnvironmentConfigs
+		isValid bool
+	}{
+		{
+			"nil",
+			nil,
+			nil,
+			true,
+		},
+		{
+			"valid",
+			[]string{"staging", "prod"},
+			&TaskEnvironmentConfigs{
+				{Name: String("staging")},
+				{Name: String("prod")},
+			},
+			true,
+		},
+		{
+			"invalid name",
+			[]string{"prod env"},
+			&TaskEnvironmentConfigs{
+				{Name: String("prod env")},
+			},
+			false,
+		},
+		{
+			"duplicate name",
+			[]string{"prod", "prod"},
+			&TaskEnvironmentConfigs{
+				{Name: String("prod")},
+			},
+			false,
+		},
+		{
+			"duplicate configuration",
+			[]string{"prod"},
+			&TaskEnvironmentConfigs{
+				{Name: String("prod")},
+				{Name: String("prod")},
+			},
+			false,
+		},
+		{
+			"not listed",
+			[]string{"staging"},
+			&TaskEnvironmentConfigs{
+				{Name: String("prod")},
+			},
+			false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.c.Validate(tc.names)
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
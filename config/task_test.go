@@ -44,13 +44,18 @@ func TestTaskConfig_Copy(t *testing.T) {
 		{
 			"same_enabled",
 			&TaskConfig{
-				Description:        String("description"),
-				Name:               String("name"),
-				Providers:          []string{"provider"},
-				DeprecatedServices: []string{"service"},
-				Module:             String("path"),
-				Version:            String("0.0.0"),
-				Enabled:            Bool(true),
+				Description: String("description"),
+				Name:        String("name"),
+				Labels:      map[string]string{"team": "neteng"},
+				Providers:   []string{"provider"},
+				ProviderOverrides: map[string]map[string]interface{}{
+					"provider": {"hostname": "host"},
+				},
+				ProviderForeachDatacenter: Bool(true),
+				DeprecatedServices:        []string{"service"},
+				Module:                    String("path"),
+				Version:                   String("0.0.0"),
+				Enabled:                   Bool(true),
 				Condition: &CatalogServicesConditionConfig{
 					CatalogServicesMonitorConfig{
 						Regexp:           String(".*"),
@@ -76,8 +81,30 @@ func TestTaskConfig_Copy(t *testing.T) {
 						},
 					},
 				},
-				WorkingDir:          String("cts-dir"),
-				DeprecatedTFVersion: String("1.0.0"),
+				WorkingDir:              String("cts-dir"),
+				Cooldown:                TimeDuration(30 * time.Second),
+				Priority:                Int(10),
+				InputSnapshots:          Int(5),
+				DependsOn:               []string{"other"},
+				SkipOnDependencyFailure: Bool(true),
+				ExpiresAt:               Time(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)),
+				ExpireAction:            String(TaskExpireActionDelete),
+				TriggerOn:               String(TaskTriggerOnAnyInputChange),
+				RenderOnly:              Bool(true),
+				ServicesChanged:         Bool(true),
+				TargetedApply:           Bool(true),
+				ApplyTargets: map[string][]string{
+					"web": {"module.web.aws_instance.web"},
+				},
+				ConsulToken:     String("token"),
+				ConsulTokenFile: String(""),
+				Backend: map[string]interface{}{
+					"consul": map[string]interface{}{"path": "kv-path"},
+				},
+				Variables:             map[string]string{"password": "secret"},
+				SensitiveVariables:    []string{"password"},
+				VariablesFromConsulKV: []string{"config/tasks/web/"},
+				DeprecatedTFVersion:   String("1.0.0"),
 				TFCWorkspace: &TerraformCloudWorkspaceConfig{
 					ExecutionMode: String("agent"),
 					AgentPoolID:   String("apool-1"),
@@ -342,6 +369,268 @@ func TestTaskConfig_Merge(t *testing.T) {
 			&TaskConfig{DeprecatedTFVersion: String("0.15.0")},
 			&TaskConfig{DeprecatedTFVersion: String("0.15.0")},
 		},
+		{
+			"provider_overrides_merge",
+			&TaskConfig{ProviderOverrides: map[string]map[string]interface{}{
+				"a": {"hostname": "host-a", "port": 8080},
+				"b": {"hostname": "host-b"},
+			}},
+			&TaskConfig{ProviderOverrides: map[string]map[string]interface{}{
+				"a": {"hostname": "host-a2"},
+				"c": {"hostname": "host-c"},
+			}},
+			&TaskConfig{ProviderOverrides: map[string]map[string]interface{}{
+				"a": {"hostname": "host-a2", "port": 8080},
+				"b": {"hostname": "host-b"},
+				"c": {"hostname": "host-c"},
+			}},
+		},
+		{
+			"provider_overrides_empty_one",
+			&TaskConfig{ProviderOverrides: map[string]map[string]interface{}{
+				"a": {"hostname": "host-a"},
+			}},
+			&TaskConfig{},
+			&TaskConfig{ProviderOverrides: map[string]map[string]interface{}{
+				"a": {"hostname": "host-a"},
+			}},
+		},
+		{
+			"provider_foreach_datacenter_overrides",
+			&TaskConfig{ProviderForeachDatacenter: Bool(false)},
+			&TaskConfig{ProviderForeachDatacenter: Bool(true)},
+			&TaskConfig{ProviderForeachDatacenter: Bool(true)},
+		},
+		{
+			"cooldown_overrides",
+			&TaskConfig{Cooldown: TimeDuration(10 * time.Second)},
+			&TaskConfig{Cooldown: TimeDuration(20 * time.Second)},
+			&TaskConfig{Cooldown: TimeDuration(20 * time.Second)},
+		},
+		{
+			"cooldown_empty_one",
+			&TaskConfig{Cooldown: TimeDuration(10 * time.Second)},
+			&TaskConfig{},
+			&TaskConfig{Cooldown: TimeDuration(10 * time.Second)},
+		},
+		{
+			"priority_overrides",
+			&TaskConfig{Priority: Int(10)},
+			&TaskConfig{Priority: Int(20)},
+			&TaskConfig{Priority: Int(20)},
+		},
+		{
+			"priority_empty_one",
+			&TaskConfig{Priority: Int(10)},
+			&TaskConfig{},
+			&TaskConfig{Priority: Int(10)},
+		},
+		{
+			"input_snapshots_overrides",
+			&TaskConfig{InputSnapshots: Int(5)},
+			&TaskConfig{InputSnapshots: Int(0)},
+			&TaskConfig{InputSnapshots: Int(0)},
+		},
+		{
+			"input_snapshots_empty_one",
+			&TaskConfig{InputSnapshots: Int(5)},
+			&TaskConfig{},
+			&TaskConfig{InputSnapshots: Int(5)},
+		},
+		{
+			"circuit_breaker_merges",
+			&TaskConfig{CircuitBreaker: &CircuitBreakerConfig{Threshold: Int(3)}},
+			&TaskConfig{CircuitBreaker: &CircuitBreakerConfig{Enabled: Bool(true)}},
+			&TaskConfig{CircuitBreaker: &CircuitBreakerConfig{
+				Enabled:   Bool(true),
+				Threshold: Int(3),
+			}},
+		},
+		{
+			"maintenance_window_merges",
+			&TaskConfig{MaintenanceWindow: &MaintenanceWindowConfig{Cron: String("0 22 * * 6")}},
+			&TaskConfig{MaintenanceWindow: &MaintenanceWindowConfig{Duration: TimeDuration(time.Hour)}},
+			&TaskConfig{MaintenanceWindow: &MaintenanceWindowConfig{
+				Cron:     String("0 22 * * 6"),
+				Duration: TimeDuration(time.Hour),
+			}},
+		},
+		{
+			"depends_on_merges",
+			&TaskConfig{DependsOn: []string{"a", "b"}},
+			&TaskConfig{DependsOn: []string{"b", "c"}},
+			&TaskConfig{DependsOn: []string{"a", "b", "c"}},
+		},
+		{
+			"skip_on_dependency_failure_overrides",
+			&TaskConfig{SkipOnDependencyFailure: Bool(false)},
+			&TaskConfig{SkipOnDependencyFailure: Bool(true)},
+			&TaskConfig{SkipOnDependencyFailure: Bool(true)},
+		},
+		{
+			"expires_at_overrides",
+			&TaskConfig{ExpiresAt: Time(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))},
+			&TaskConfig{ExpiresAt: Time(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))},
+			&TaskConfig{ExpiresAt: Time(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))},
+		},
+		{
+			"expire_action_overrides",
+			&TaskConfig{ExpireAction: String(TaskExpireActionDisable)},
+			&TaskConfig{ExpireAction: String(TaskExpireActionDelete)},
+			&TaskConfig{ExpireAction: String(TaskExpireActionDelete)},
+		},
+		{
+			"trigger_on_overrides",
+			&TaskConfig{TriggerOn: String(TaskTriggerOnConditionOnly)},
+			&TaskConfig{TriggerOn: String(TaskTriggerOnAnyInputChange)},
+			&TaskConfig{TriggerOn: String(TaskTriggerOnAnyInputChange)},
+		},
+		{
+			"publish_outputs_merges",
+			&TaskConfig{PublishOutputs: &PublishOutputsConfig{Path: String("a")}},
+			&TaskConfig{PublishOutputs: &PublishOutputsConfig{Outputs: []string{"id"}}},
+			&TaskConfig{PublishOutputs: &PublishOutputsConfig{
+				Path:    String("a"),
+				Outputs: []string{"id"},
+			}},
+		},
+		{
+			"handlers_append",
+			&TaskConfig{Handlers: &HandlerConfigs{
+				{Exec: &ExecHandlerConfig{Command: String("a")}},
+			}},
+			&TaskConfig{Handlers: &HandlerConfigs{
+				{Exec: &ExecHandlerConfig{Command: String("b")}},
+			}},
+			&TaskConfig{Handlers: &HandlerConfigs{
+				{Exec: &ExecHandlerConfig{Command: String("a")}},
+				{Exec: &ExecHandlerConfig{Command: String("b")}},
+			}},
+		},
+		{
+			"render_only_overrides",
+			&TaskConfig{RenderOnly: Bool(false)},
+			&TaskConfig{RenderOnly: Bool(true)},
+			&TaskConfig{RenderOnly: Bool(true)},
+		},
+		{
+			"render_only_empty_one",
+			&TaskConfig{RenderOnly: Bool(true)},
+			&TaskConfig{},
+			&TaskConfig{RenderOnly: Bool(true)},
+		},
+		{
+			"services_changed_overrides",
+			&TaskConfig{ServicesChanged: Bool(false)},
+			&TaskConfig{ServicesChanged: Bool(true)},
+			&TaskConfig{ServicesChanged: Bool(true)},
+		},
+		{
+			"services_changed_empty_one",
+			&TaskConfig{ServicesChanged: Bool(true)},
+			&TaskConfig{},
+			&TaskConfig{ServicesChanged: Bool(true)},
+		},
+		{
+			"targeted_apply_overrides",
+			&TaskConfig{TargetedApply: Bool(false)},
+			&TaskConfig{TargetedApply: Bool(true)},
+			&TaskConfig{TargetedApply: Bool(true)},
+		},
+		{
+			"sensitive_variables_merges",
+			&TaskConfig{SensitiveVariables: []string{"a", "b"}},
+			&TaskConfig{SensitiveVariables: []string{"b", "c"}},
+			&TaskConfig{SensitiveVariables: []string{"a", "b", "c"}},
+		},
+		{
+			"variables_from_consul_kv_merges",
+			&TaskConfig{VariablesFromConsulKV: []string{"global/", "web/"}},
+			&TaskConfig{VariablesFromConsulKV: []string{"web/", "prod/"}},
+			&TaskConfig{VariablesFromConsulKV: []string{"global/", "web/", "prod/"}},
+		},
+		{
+			"labels_merges",
+			&TaskConfig{Labels: map[string]string{"team": "neteng", "env": "dev"}},
+			&TaskConfig{Labels: map[string]string{"env": "prod"}},
+			&TaskConfig{Labels: map[string]string{"team": "neteng", "env": "prod"}},
+		},
+		{
+			"labels_empty_one",
+			&TaskConfig{Labels: map[string]string{"team": "neteng"}},
+			&TaskConfig{},
+			&TaskConfig{Labels: map[string]string{"team": "neteng"}},
+		},
+		{
+			"apply_targets_merges",
+			&TaskConfig{ApplyTargets: map[string][]string{
+				"api": {"a.api"},
+				"web": {"a.web"},
+			}},
+			&TaskConfig{ApplyTargets: map[string][]string{
+				"web": {"b.web"},
+				"db":  {"b.db"},
+			}},
+			&TaskConfig{ApplyTargets: map[string][]string{
+				"api": {"a.api"},
+				"web": {"b.web"},
+				"db":  {"b.db"},
+			}},
+		},
+		{
+			"consul_token_overrides",
+			&TaskConfig{ConsulToken: String("token-a")},
+			&TaskConfig{ConsulToken: String("token-b")},
+			&TaskConfig{ConsulToken: String("token-b")},
+		},
+		{
+			"consul_token_file_empty_one",
+			&TaskConfig{ConsulTokenFile: String("/path/token")},
+			&TaskConfig{},
+			&TaskConfig{ConsulTokenFile: String("/path/token")},
+		},
+		{
+			"backend_replaces",
+			&TaskConfig{Backend: map[string]interface{}{
+				"consul": map[string]interface{}{"path": "a"},
+			}},
+			&TaskConfig{Backend: map[string]interface{}{
+				"local": map[string]interface{}{"path": "b"},
+			}},
+			&TaskConfig{Backend: map[string]interface{}{
+				"local": map[string]interface{}{"path": "b"},
+			}},
+		},
+		{
+			"backend_empty_one",
+			&TaskConfig{Backend: map[string]interface{}{
+				"consul": map[string]interface{}{"path": "a"},
+			}},
+			&TaskConfig{},
+			&TaskConfig{Backend: map[string]interface{}{
+				"consul": map[string]interface{}{"path": "a"},
+			}},
+		},
+		{
+			"extra_templates_merge",
+			&TaskConfig{ExtraTemplates: []string{"a.tpl"}},
+			&TaskConfig{ExtraTemplates: []string{"b.tpl"}},
+			&TaskConfig{ExtraTemplates: []string{"a.tpl", "b.tpl"}},
+		},
+		{
+			"computed_inputs_merge",
+			&TaskConfig{ComputedInputs: ComputedInputConfigs{
+				"a": {Expr: String("1")},
+				"b": {Expr: String("2")},
+			}},
+			&TaskConfig{ComputedInputs: ComputedInputConfigs{
+				"b": {Expr: String("3")},
+			}},
+			&TaskConfig{ComputedInputs: ComputedInputConfigs{
+				"a": {Expr: String("1")},
+				"b": {Expr: String("3")},
+			}},
+		},
 		{
 			"enabled_overrides",
 			&TaskConfig{Enabled: Bool(false)},
@@ -522,21 +811,51 @@ func TestTaskConfig_Finalize(t *testing.T) {
 			name: "empty",
 			i:    &TaskConfig{},
 			r: &TaskConfig{
-				Description:         String(""),
-				Name:                String(""),
-				Providers:           []string{},
-				DeprecatedServices:  []string{},
-				Module:              String(""),
-				VarFiles:            []string{},
-				Variables:           map[string]string{},
-				Version:             String(""),
-				DeprecatedTFVersion: String(""),
-				TFCWorkspace:        DefaultTerraformCloudWorkspaceConfig(),
-				BufferPeriod:        nil,
-				Enabled:             Bool(true),
-				Condition:           EmptyConditionConfig(),
-				WorkingDir:          nil,
-				ModuleInputs:        DefaultModuleInputConfigs(),
+				Description:               String(""),
+				Name:                      String(""),
+				Labels:                    map[string]string{},
+				Providers:                 []string{},
+				ProviderOverrides:         map[string]map[string]interface{}{},
+				ProviderForeachDatacenter: Bool(false),
+				DeprecatedServices:        []string{},
+				Module:                    String(""),
+				VarFiles:                  []string{},
+				Variables:                 map[string]string{},
+				SensitiveVariables:        []string{},
+				VariablesFromConsulKV:     []string{},
+				Version:                   String(""),
+				DeprecatedTFVersion:       String(""),
+				TFCWorkspace:              DefaultTerraformCloudWorkspaceConfig(),
+				BufferPeriod:              nil,
+				Cooldown:                  TimeDuration(0),
+				Priority:                  Int(0),
+				InputSnapshots:            Int(0),
+				CircuitBreaker:            defaultCircuitBreakerConfig(),
+				MaintenanceWindow:         defaultMaintenanceWindowConfig(),
+				DependsOn:                 []string{},
+				SkipOnDependencyFailure:   Bool(false),
+				ExpireAction:              String(TaskExpireActionDisable),
+				TriggerOn:                 String(TaskTriggerOnConditionOnly),
+				Enabled:                   Bool(true),
+				RenderOnly:                Bool(false),
+				ServicesChanged:           Bool(false),
+				TargetedApply:             Bool(false),
+				ApplyTargets:              map[string][]string{},
+				ConsulToken:               String(""),
+				ConsulTokenFile:           String(""),
+				Backend:                   map[string]interface{}{},
+				Environments:              []string{},
+				EnvironmentConfigs:        &TaskEnvironmentConfigs{},
+				ExtraTemplates:            []string{},
+				Postconditions:            &PostconditionConfigs{},
+				ComputedInputs:            ComputedInputConfigs{},
+				PublishOutputs:            defaultPublishOutputsConfig(),
+				AlertmanagerSilence:       defaultAlertmanagerSilenceConfig(),
+				Handlers:                  &HandlerConfigs{},
+				Gates:                     &GateConfigs{},
+				Condition:                 EmptyConditionConfig(),
+				WorkingDir:                nil,
+				ModuleInputs:              DefaultModuleInputConfigs(),
 			},
 		},
 		{
@@ -545,21 +864,51 @@ func TestTaskConfig_Finalize(t *testing.T) {
 				Name: String("task"),
 			},
 			r: &TaskConfig{
-				Description:         String(""),
-				Name:                String("task"),
-				Providers:           []string{},
-				DeprecatedServices:  []string{},
-				Module:              String(""),
-				VarFiles:            []string{},
-				Variables:           map[string]string{},
-				Version:             String(""),
-				DeprecatedTFVersion: String(""),
-				TFCWorkspace:        DefaultTerraformCloudWorkspaceConfig(),
-				BufferPeriod:        nil,
-				Enabled:             Bool(true),
-				Condition:           EmptyConditionConfig(),
-				WorkingDir:          nil,
-				ModuleInputs:        DefaultModuleInputConfigs(),
+				Description:               String(""),
+				Name:                      String("task"),
+				Labels:                    map[string]string{},
+				Providers:                 []string{},
+				ProviderOverrides:         map[string]map[string]interface{}{},
+				ProviderForeachDatacenter: Bool(false),
+				DeprecatedServices:        []string{},
+				Module:                    String(""),
+				VarFiles:                  []string{},
+				Variables:                 map[string]string{},
+				SensitiveVariables:        []string{},
+				VariablesFromConsulKV:     []string{},
+				Version:                   String(""),
+				DeprecatedTFVersion:       String(""),
+				TFCWorkspace:              DefaultTerraformCloudWorkspaceConfig(),
+				BufferPeriod:              nil,
+				Cooldown:                  TimeDuration(0),
+				Priority:                  Int(0),
+				InputSnapshots:            Int(0),
+				CircuitBreaker:            defaultCircuitBreakerConfig(),
+				MaintenanceWindow:         defaultMaintenanceWindowConfig(),
+				DependsOn:                 []string{},
+				SkipOnDependencyFailure:   Bool(false),
+				ExpireAction:              String(TaskExpireActionDisable),
+				TriggerOn:                 String(TaskTriggerOnConditionOnly),
+				Enabled:                   Bool(true),
+				RenderOnly:                Bool(false),
+				ServicesChanged:           Bool(false),
+				TargetedApply:             Bool(false),
+				ApplyTargets:              map[string][]string{},
+				ConsulToken:               String(""),
+				ConsulTokenFile:           String(""),
+				Backend:                   map[string]interface{}{},
+				Environments:              []string{},
+				EnvironmentConfigs:        &TaskEnvironmentConfigs{},
+				ExtraTemplates:            []string{},
+				Postconditions:            &PostconditionConfigs{},
+				ComputedInputs:            ComputedInputConfigs{},
+				PublishOutputs:            defaultPublishOutputsConfig(),
+				AlertmanagerSilence:       defaultAlertmanagerSilenceConfig(),
+				Handlers:                  &HandlerConfigs{},
+				Gates:                     &GateConfigs{},
+				Condition:                 EmptyConditionConfig(),
+				WorkingDir:                nil,
+				ModuleInputs:              DefaultModuleInputConfigs(),
 			},
 		},
 		{
@@ -569,21 +918,54 @@ func TestTaskConfig_Finalize(t *testing.T) {
 				Condition: &ScheduleConditionConfig{},
 			},
 			r: &TaskConfig{
-				Description:         String(""),
-				Name:                String("task"),
-				Providers:           []string{},
-				DeprecatedServices:  []string{},
-				Module:              String(""),
-				VarFiles:            []string{},
-				Variables:           map[string]string{},
-				Version:             String(""),
-				DeprecatedTFVersion: String(""),
-				TFCWorkspace:        DefaultTerraformCloudWorkspaceConfig(),
-				BufferPeriod:        emptyBufferPeriodConfig,
-				Enabled:             Bool(true),
+				Description:               String(""),
+				Name:                      String("task"),
+				Labels:                    map[string]string{},
+				Providers:                 []string{},
+				ProviderOverrides:         map[string]map[string]interface{}{},
+				ProviderForeachDatacenter: Bool(false),
+				DeprecatedServices:        []string{},
+				Module:                    String(""),
+				VarFiles:                  []string{},
+				Variables:                 map[string]string{},
+				SensitiveVariables:        []string{},
+				VariablesFromConsulKV:     []string{},
+				Version:                   String(""),
+				DeprecatedTFVersion:       String(""),
+				TFCWorkspace:              DefaultTerraformCloudWorkspaceConfig(),
+				BufferPeriod:              emptyBufferPeriodConfig,
+				Cooldown:                  TimeDuration(0),
+				Priority:                  Int(0),
+				InputSnapshots:            Int(0),
+				CircuitBreaker:            defaultCircuitBreakerConfig(),
+				MaintenanceWindow:         defaultMaintenanceWindowConfig(),
+				DependsOn:                 []string{},
+				SkipOnDependencyFailure:   Bool(false),
+				ExpireAction:              String(TaskExpireActionDisable),
+				TriggerOn:                 String(TaskTriggerOnConditionOnly),
+				Enabled:                   Bool(true),
+				RenderOnly:                Bool(false),
+				ServicesChanged:           Bool(false),
+				TargetedApply:             Bool(false),
+				ApplyTargets:              map[string][]string{},
+				ConsulToken:               String(""),
+				ConsulTokenFile:           String(""),
+				Backend:                   map[string]interface{}{},
+				Environments:              []string{},
+				EnvironmentConfigs:        &TaskEnvironmentConfigs{},
+				ExtraTemplates:            []string{},
+				Postconditions:            &PostconditionConfigs{},
+				ComputedInputs:            ComputedInputConfigs{},
+				PublishOutputs:            defaultPublishOutputsConfig(),
+				AlertmanagerSilence:       defaultAlertmanagerSilenceConfig(),
+				Handlers:                  &HandlerConfigs{},
+				Gates:                     &GateConfigs{},
 				Condition: &ScheduleConditionConfig{
 					ScheduleMonitorConfig: ScheduleMonitorConfig{
-						String(""),
+						Cron:          String(""),
+						Crons:         []string{},
+						CatchUp:       Bool(false),
+						CatchUpMaxAge: TimeDuration(DefaultScheduleCatchUpMaxAge),
 					},
 				},
 				WorkingDir:   nil,
@@ -601,32 +983,68 @@ func TestTaskConfig_Finalize(t *testing.T) {
 				},
 			},
 			r: &TaskConfig{
-				Description:         String(""),
-				Name:                String("task"),
-				Providers:           []string{},
-				DeprecatedServices:  []string{},
-				Module:              String(""),
-				VarFiles:            []string{},
-				Variables:           map[string]string{},
-				Version:             String(""),
-				DeprecatedTFVersion: String(""),
-				TFCWorkspace:        DefaultTerraformCloudWorkspaceConfig(),
-				BufferPeriod:        emptyBufferPeriodConfig,
-				Enabled:             Bool(true),
+				Description:               String(""),
+				Name:                      String("task"),
+				Labels:                    map[string]string{},
+				Providers:                 []string{},
+				ProviderOverrides:         map[string]map[string]interface{}{},
+				ProviderForeachDatacenter: Bool(false),
+				DeprecatedServices:        []string{},
+				Module:                    String(""),
+				VarFiles:                  []string{},
+				Variables:                 map[string]string{},
+				SensitiveVariables:        []string{},
+				VariablesFromConsulKV:     []string{},
+				Version:                   String(""),
+				DeprecatedTFVersion:       String(""),
+				TFCWorkspace:              DefaultTerraformCloudWorkspaceConfig(),
+				BufferPeriod:              emptyBufferPeriodConfig,
+				Cooldown:                  TimeDuration(0),
+				Priority:                  Int(0),
+				InputSnapshots:            Int(0),
+				CircuitBreaker:            defaultCircuitBreakerConfig(),
+				MaintenanceWindow:         defaultMaintenanceWindowConfig(),
+				DependsOn:                 []string{},
+				SkipOnDependencyFailure:   Bool(false),
+				ExpireAction:              String(TaskExpireActionDisable),
+				TriggerOn:                 String(TaskTriggerOnConditionOnly),
+				Enabled:                   Bool(true),
+				RenderOnly:                Bool(false),
+				ServicesChanged:           Bool(false),
+				TargetedApply:             Bool(false),
+				ApplyTargets:              map[string][]string{},
+				ConsulToken:               String(""),
+				ConsulTokenFile:           String(""),
+				Backend:                   map[string]interface{}{},
+				Environments:              []string{},
+				EnvironmentConfigs:        &TaskEnvironmentConfigs{},
+				ExtraTemplates:            []string{},
+				Postconditions:            &PostconditionConfigs{},
+				ComputedInputs:            ComputedInputConfigs{},
+				PublishOutputs:            defaultPublishOutputsConfig(),
+				AlertmanagerSilence:       defaultAlertmanagerSilenceConfig(),
+				Handlers:                  &HandlerConfigs{},
+				Gates:                     &GateConfigs{},
 				Condition: &ScheduleConditionConfig{
 					ScheduleMonitorConfig: ScheduleMonitorConfig{
-						String(""),
+						Cron:          String(""),
+						Crons:         []string{},
+						CatchUp:       Bool(false),
+						CatchUpMaxAge: TimeDuration(DefaultScheduleCatchUpMaxAge),
 					},
 				},
 				WorkingDir: nil,
 				ModuleInputs: &ModuleInputConfigs{&ServicesModuleInputConfig{
 					ServicesMonitorConfig{
-						Regexp:             String("^api$"),
-						Names:              []string{},
-						Datacenter:         String(""),
-						Namespace:          String(""),
-						Filter:             String(""),
-						CTSUserDefinedMeta: map[string]string{},
+						Regexp:                  String("^api$"),
+						Names:                   []string{},
+						Datacenter:              String(""),
+						Namespace:               String(""),
+						Filter:                  String(""),
+						CTSUserDefinedMeta:      map[string]string{},
+						IncludeExtendedMetadata: Bool(false),
+						Query:                   String(""),
+						Bootstrap:               Bool(false),
 					}}},
 			},
 		},
@@ -634,12 +1052,15 @@ func TestTaskConfig_Finalize(t *testing.T) {
 			name: "with_actual_var_file",
 			i:    &TaskConfig{VarFiles: []string{"testdata/simple.tfvars", "testdata/complex.tfvars"}},
 			r: &TaskConfig{
-				Description:        String(""),
-				Name:               String(""),
-				Providers:          []string{},
-				DeprecatedServices: []string{},
-				Module:             String(""),
-				VarFiles:           []string{"testdata/simple.tfvars", "testdata/complex.tfvars"},
+				Description:               String(""),
+				Name:                      String(""),
+				Labels:                    map[string]string{},
+				Providers:                 []string{},
+				ProviderOverrides:         map[string]map[string]interface{}{},
+				ProviderForeachDatacenter: Bool(false),
+				DeprecatedServices:        []string{},
+				Module:                    String(""),
+				VarFiles:                  []string{"testdata/simple.tfvars", "testdata/complex.tfvars"},
 				Variables: map[string]string{
 					"singleKey": "\"value\"",
 					"key":       "\"some_key\"",
@@ -649,14 +1070,41 @@ func TestTaskConfig_Finalize(t *testing.T) {
 					"l":         "[1,2,3]",
 					"tup":       "[\"abc\",123,true]",
 				},
-				Version:             String(""),
-				DeprecatedTFVersion: String(""),
-				TFCWorkspace:        DefaultTerraformCloudWorkspaceConfig(),
-				BufferPeriod:        nil,
-				Enabled:             Bool(true),
-				Condition:           EmptyConditionConfig(),
-				WorkingDir:          nil,
-				ModuleInputs:        DefaultModuleInputConfigs(),
+				SensitiveVariables:      []string{},
+				VariablesFromConsulKV:   []string{},
+				Version:                 String(""),
+				DeprecatedTFVersion:     String(""),
+				TFCWorkspace:            DefaultTerraformCloudWorkspaceConfig(),
+				BufferPeriod:            nil,
+				Cooldown:                TimeDuration(0),
+				Priority:                Int(0),
+				InputSnapshots:          Int(0),
+				CircuitBreaker:          defaultCircuitBreakerConfig(),
+				MaintenanceWindow:       defaultMaintenanceWindowConfig(),
+				DependsOn:               []string{},
+				SkipOnDependencyFailure: Bool(false),
+				ExpireAction:            String(TaskExpireActionDisable),
+				TriggerOn:               String(TaskTriggerOnConditionOnly),
+				Enabled:                 Bool(true),
+				RenderOnly:              Bool(false),
+				ServicesChanged:         Bool(false),
+				TargetedApply:           Bool(false),
+				ApplyTargets:            map[string][]string{},
+				ConsulToken:             String(""),
+				ConsulTokenFile:         String(""),
+				Backend:                 map[string]interface{}{},
+				Environments:            []string{},
+				EnvironmentConfigs:      &TaskEnvironmentConfigs{},
+				ExtraTemplates:          []string{},
+				Postconditions:          &PostconditionConfigs{},
+				ComputedInputs:          ComputedInputConfigs{},
+				PublishOutputs:          defaultPublishOutputsConfig(),
+				AlertmanagerSilence:     defaultAlertmanagerSilenceConfig(),
+				Handlers:                &HandlerConfigs{},
+				Gates:                   &GateConfigs{},
+				Condition:               EmptyConditionConfig(),
+				WorkingDir:              nil,
+				ModuleInputs:            DefaultModuleInputConfigs(),
 			},
 		},
 		{
@@ -673,14 +1121,19 @@ func TestTaskConfig_Finalize(t *testing.T) {
 					"tup":       "[\"abc\",123,true]",
 					"newValue":  "42", // This value does not exist in VarFiles, and is expected to exist
 				},
+				SensitiveVariables:    []string{},
+				VariablesFromConsulKV: []string{},
 			},
 			r: &TaskConfig{
-				Description:        String(""),
-				Name:               String(""),
-				Providers:          []string{},
-				DeprecatedServices: []string{},
-				Module:             String(""),
-				VarFiles:           []string{"testdata/simple.tfvars", "testdata/complex.tfvars"},
+				Description:               String(""),
+				Name:                      String(""),
+				Labels:                    map[string]string{},
+				Providers:                 []string{},
+				ProviderOverrides:         map[string]map[string]interface{}{},
+				ProviderForeachDatacenter: Bool(false),
+				DeprecatedServices:        []string{},
+				Module:                    String(""),
+				VarFiles:                  []string{"testdata/simple.tfvars", "testdata/complex.tfvars"},
 				Variables: map[string]string{
 					"singleKey": "\"value\"",
 					"key":       "\"some_key\"",
@@ -691,14 +1144,41 @@ func TestTaskConfig_Finalize(t *testing.T) {
 					"tup":       "[\"abc\",123,true]",
 					"newValue":  "42",
 				},
-				Version:             String(""),
-				DeprecatedTFVersion: String(""),
-				TFCWorkspace:        DefaultTerraformCloudWorkspaceConfig(),
-				BufferPeriod:        nil,
-				Enabled:             Bool(true),
-				Condition:           EmptyConditionConfig(),
-				WorkingDir:          nil,
-				ModuleInputs:        DefaultModuleInputConfigs(),
+				SensitiveVariables:      []string{},
+				VariablesFromConsulKV:   []string{},
+				Version:                 String(""),
+				DeprecatedTFVersion:     String(""),
+				TFCWorkspace:            DefaultTerraformCloudWorkspaceConfig(),
+				BufferPeriod:            nil,
+				Cooldown:                TimeDuration(0),
+				Priority:                Int(0),
+				InputSnapshots:          Int(0),
+				CircuitBreaker:          defaultCircuitBreakerConfig(),
+				MaintenanceWindow:       defaultMaintenanceWindowConfig(),
+				DependsOn:               []string{},
+				SkipOnDependencyFailure: Bool(false),
+				ExpireAction:            String(TaskExpireActionDisable),
+				TriggerOn:               String(TaskTriggerOnConditionOnly),
+				Enabled:                 Bool(true),
+				RenderOnly:              Bool(false),
+				ServicesChanged:         Bool(false),
+				TargetedApply:           Bool(false),
+				ApplyTargets:            map[string][]string{},
+				ConsulToken:             String(""),
+				ConsulTokenFile:         String(""),
+				Backend:                 map[string]interface{}{},
+				Environments:            []string{},
+				EnvironmentConfigs:      &TaskEnvironmentConfigs{},
+				ExtraTemplates:          []string{},
+				Postconditions:          &PostconditionConfigs{},
+				ComputedInputs:          ComputedInputConfigs{},
+				PublishOutputs:          defaultPublishOutputsConfig(),
+				AlertmanagerSilence:     defaultAlertmanagerSilenceConfig(),
+				Handlers:                &HandlerConfigs{},
+				Gates:                   &GateConfigs{},
+				Condition:               EmptyConditionConfig(),
+				WorkingDir:              nil,
+				ModuleInputs:            DefaultModuleInputConfigs(),
 			},
 		},
 	}
@@ -786,6 +1266,7 @@ func TestTaskConfig_Finalize_DeprecatedSourceInputs(t *testing.T) {
 				&ConsulKVModuleInputConfig{
 					ConsulKVMonitorConfig: ConsulKVMonitorConfig{
 						Path:       String("path"),
+						Paths:      []string{},
 						Recurse:    Bool(false),
 						Datacenter: String(""),
 						Namespace:  String(""),
@@ -807,12 +1288,15 @@ func TestTaskConfig_Finalize_DeprecatedSourceInputs(t *testing.T) {
 			&ModuleInputConfigs{
 				&ServicesModuleInputConfig{
 					ServicesMonitorConfig: ServicesMonitorConfig{
-						Regexp:             String(".*"),
-						Names:              []string{},
-						Datacenter:         String(""),
-						Namespace:          String(""),
-						Filter:             String(""),
-						CTSUserDefinedMeta: map[string]string{},
+						Regexp:                  String(".*"),
+						Names:                   []string{},
+						Datacenter:              String(""),
+						Namespace:               String(""),
+						Filter:                  String(""),
+						CTSUserDefinedMeta:      map[string]string{},
+						IncludeExtendedMetadata: Bool(false),
+						Query:                   String(""),
+						Bootstrap:               Bool(false),
 					},
 				},
 			},
@@ -839,6 +1323,7 @@ func TestTaskConfig_Finalize_DeprecatedSourceInputs(t *testing.T) {
 				&ConsulKVModuleInputConfig{
 					ConsulKVMonitorConfig: ConsulKVMonitorConfig{
 						Path:       String("path"),
+						Paths:      []string{},
 						Recurse:    Bool(false),
 						Datacenter: String(""),
 						Namespace:  String(""),
@@ -846,12 +1331,15 @@ func TestTaskConfig_Finalize_DeprecatedSourceInputs(t *testing.T) {
 				},
 				&ServicesModuleInputConfig{
 					ServicesMonitorConfig: ServicesMonitorConfig{
-						Regexp:             String(".*"),
-						Names:              []string{},
-						Datacenter:         String(""),
-						Namespace:          String(""),
-						Filter:             String(""),
-						CTSUserDefinedMeta: map[string]string{},
+						Regexp:                  String(".*"),
+						Names:                   []string{},
+						Datacenter:              String(""),
+						Namespace:               String(""),
+						Filter:                  String(""),
+						CTSUserDefinedMeta:      map[string]string{},
+						IncludeExtendedMetadata: Bool(false),
+						Query:                   String(""),
+						Bootstrap:               Bool(false),
 					},
 				},
 			},
@@ -926,7 +1414,7 @@ func TestTaskConfig_Validate(t *testing.T) {
 			false,
 		},
 		{
-			"invalid: TF version: unsupported version",
+			"valid: TF version",
 			&TaskConfig{
 				Name: String("task"),
 				Condition: &ServicesConditionConfig{
@@ -937,10 +1425,24 @@ func TestTaskConfig_Validate(t *testing.T) {
 				Module:              String("path"),
 				DeprecatedTFVersion: String("0.15.0"),
 			},
+			true,
+		},
+		{
+			"invalid: TF version: unsupported version",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:              String("path"),
+				DeprecatedTFVersion: String("0.12.0"),
+			},
 			false,
 		},
 		{
-			"invalid: TFC workspace unsupported",
+			"invalid: TF version: inexact version",
 			&TaskConfig{
 				Name: String("task"),
 				Condition: &ServicesConditionConfig{
@@ -948,15 +1450,27 @@ func TestTaskConfig_Validate(t *testing.T) {
 						Names: []string{"api"},
 					},
 				},
-				Module: String("path"),
-				TFCWorkspace: &TerraformCloudWorkspaceConfig{
-					ExecutionMode: String("remote"),
+				Module:              String("path"),
+				DeprecatedTFVersion: String("1.0"),
+			},
+			false,
+		},
+		{
+			"invalid: TF version: malformed",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
 				},
+				Module:              String("path"),
+				DeprecatedTFVersion: String("latest"),
 			},
 			false,
 		},
 		{
-			"valid: empty TFC workspace",
+			"valid: provider overrides",
 			&TaskConfig{
 				Name: String("task"),
 				Condition: &ServicesConditionConfig{
@@ -964,13 +1478,16 @@ func TestTaskConfig_Validate(t *testing.T) {
 						Names: []string{"api"},
 					},
 				},
-				Module:       String("path"),
-				TFCWorkspace: DefaultTerraformCloudWorkspaceConfig(),
+				Module:    String("path"),
+				Providers: []string{"panos.fw"},
+				ProviderOverrides: map[string]map[string]interface{}{
+					"panos.fw": {"hostname": "value"},
+				},
 			},
 			true,
 		},
 		{
-			"invalid: provider: duplicate",
+			"invalid: provider overrides: provider not used by task",
 			&TaskConfig{
 				Name: String("task"),
 				Condition: &ServicesConditionConfig{
@@ -979,12 +1496,15 @@ func TestTaskConfig_Validate(t *testing.T) {
 					},
 				},
 				Module:    String("path"),
-				Providers: []string{"providerA", "providerA"},
+				Providers: []string{"panos"},
+				ProviderOverrides: map[string]map[string]interface{}{
+					"aws": {"region": "value"},
+				},
 			},
 			false,
 		},
 		{
-			"invalid: provider: duplicate with alias",
+			"invalid: provider overrides: alias",
 			&TaskConfig{
 				Name: String("task"),
 				Condition: &ServicesConditionConfig{
@@ -993,110 +1513,1091 @@ func TestTaskConfig_Validate(t *testing.T) {
 					},
 				},
 				Module:    String("path"),
-				Providers: []string{"providerA", "providerA.alias"},
+				Providers: []string{"panos"},
+				ProviderOverrides: map[string]map[string]interface{}{
+					"panos": {"alias": "value"},
+				},
 			},
 			false,
 		},
-	}
-
-	for i, tc := range cases {
-		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
-			err := tc.i.Validate()
-			if tc.isValid {
-				assert.NoError(t, err)
-			} else {
-				assert.Error(t, err)
-			}
-		})
-	}
-}
-
-func TestTaskConfig_InheritParentConfig(t *testing.T) {
-	cases := []struct {
-		name               string
-		parentWorkingDir   string
-		parentBufferPeriod BufferPeriodConfig
-		inputTaskConfig    *TaskConfig
-		expectedTaskConfig *TaskConfig
-	}{
 		{
-			name:             "input values set",
-			parentWorkingDir: "path/to/parent",
-			parentBufferPeriod: BufferPeriodConfig{
-				Enabled: Bool(false),
-				Max:     TimeDuration(0 * time.Second),
-				Min:     TimeDuration(0 * time.Second),
-			},
-			inputTaskConfig: &TaskConfig{
-				WorkingDir: String("path"),
-				BufferPeriod: &BufferPeriodConfig{
-					Enabled: Bool(true),
-					Max:     TimeDuration(3 * time.Second),
-					Min:     TimeDuration(3 * time.Second),
+			"valid: provider_foreach_datacenter",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &CatalogServicesConditionConfig{
+					CatalogServicesMonitorConfig{
+						Regexp:     String(".*"),
+						Datacenter: String("dc1"),
+					},
 				},
-			},
-			expectedTaskConfig: &TaskConfig{
-				WorkingDir: String("path"),
-				BufferPeriod: &BufferPeriodConfig{
-					Enabled: Bool(true),
-					Max:     TimeDuration(3 * time.Second),
-					Min:     TimeDuration(3 * time.Second),
+				ModuleInputs: &ModuleInputConfigs{
+					&ServicesModuleInputConfig{
+						ServicesMonitorConfig{
+							Names:      []string{"api"},
+							Datacenter: String("dc2"),
+						},
+					},
 				},
+				Module:                    String("path"),
+				Providers:                 []string{"panos"},
+				ProviderForeachDatacenter: Bool(true),
 			},
+			true,
 		},
 		{
-			name:             "empty input, parent enabled",
-			parentWorkingDir: "path/to/parent",
-			parentBufferPeriod: BufferPeriodConfig{
-				Enabled: Bool(true),
-				Max:     TimeDuration(4 * time.Second),
-				Min:     TimeDuration(4 * time.Second),
-			},
-			inputTaskConfig: &TaskConfig{},
-			expectedTaskConfig: &TaskConfig{
-				WorkingDir: String("path/to/parent"),
-				BufferPeriod: &BufferPeriodConfig{
-					Enabled: Bool(true),
-					Max:     TimeDuration(4 * time.Second),
-					Min:     TimeDuration(4 * time.Second),
+			"invalid: provider_foreach_datacenter: no datacenter",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
 				},
+				Module:                    String("path"),
+				Providers:                 []string{"panos"},
+				ProviderForeachDatacenter: Bool(true),
 			},
+			false,
 		},
 		{
-			name:             "empty input, parent bp disabled",
-			parentWorkingDir: "",
-			parentBufferPeriod: BufferPeriodConfig{
-				Enabled: Bool(false),
-				Min:     TimeDuration(0 * time.Second),
-				Max:     TimeDuration(0 * time.Second),
-			},
-			inputTaskConfig: &TaskConfig{},
-			expectedTaskConfig: &TaskConfig{
-				WorkingDir: String(""),
-				BufferPeriod: &BufferPeriodConfig{
-					Enabled: Bool(false),
-					Min:     TimeDuration(0 * time.Second),
-					Max:     TimeDuration(0 * time.Second),
+			"invalid: provider_foreach_datacenter: alias",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names:      []string{"api"},
+						Datacenter: String("dc1"),
+					},
 				},
+				Module:                    String("path"),
+				Providers:                 []string{"panos.dc1"},
+				ProviderForeachDatacenter: Bool(true),
 			},
+			false,
 		},
 		{
-			name:             "input no bp min parent disabled",
-			parentWorkingDir: "path/to/parent",
-			parentBufferPeriod: BufferPeriodConfig{
-				Enabled: Bool(false),
-				Min:     TimeDuration(0 * time.Second),
-				Max:     TimeDuration(0 * time.Second),
-			},
-			inputTaskConfig: &TaskConfig{
-				BufferPeriod: &BufferPeriodConfig{
-					Enabled: Bool(true),
-					Max:     TimeDuration(5 * time.Second),
+			"invalid: provider_foreach_datacenter: no providers",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names:      []string{"api"},
+						Datacenter: String("dc1"),
+					},
 				},
+				Module:                    String("path"),
+				ProviderForeachDatacenter: Bool(true),
 			},
-			expectedTaskConfig: &TaskConfig{
-				WorkingDir: String("path/to/parent"),
-				BufferPeriod: &BufferPeriodConfig{
+			false,
+		},
+		{
+			"invalid: provider_foreach_datacenter: datacenter not an identifier",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names:      []string{"api"},
+						Datacenter: String("dc.1"),
+					},
+				},
+				Module:                    String("path"),
+				Providers:                 []string{"panos"},
+				ProviderForeachDatacenter: Bool(true),
+			},
+			false,
+		},
+		{
+			"invalid: TFC workspace unsupported",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module: String("path"),
+				TFCWorkspace: &TerraformCloudWorkspaceConfig{
+					ExecutionMode: String("remote"),
+				},
+			},
+			false,
+		},
+		{
+			"valid: empty TFC workspace",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:       String("path"),
+				TFCWorkspace: DefaultTerraformCloudWorkspaceConfig(),
+			},
+			true,
+		},
+		{
+			"invalid: provider: duplicate",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:    String("path"),
+				Providers: []string{"providerA", "providerA"},
+			},
+			false,
+		},
+		{
+			"invalid: provider: duplicate with alias",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:    String("path"),
+				Providers: []string{"providerA", "providerA.alias"},
+			},
+			false,
+		},
+		{
+			"valid: cooldown",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:   String("path"),
+				Cooldown: TimeDuration(5 * time.Minute),
+			},
+			true,
+		},
+		{
+			"valid: depends_on",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:                  String("path"),
+				DependsOn:               []string{"firewall"},
+				SkipOnDependencyFailure: Bool(true),
+			},
+			true,
+		},
+		{
+			"valid: expires_at",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:       String("path"),
+				ExpiresAt:    Time(time.Now().Add(time.Hour)),
+				ExpireAction: String(TaskExpireActionDelete),
+			},
+			true,
+		},
+		{
+			"invalid: depends_on: self",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:    String("path"),
+				DependsOn: []string{"task"},
+			},
+			false,
+		},
+		{
+			"invalid: depends_on: duplicate",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:    String("path"),
+				DependsOn: []string{"firewall", "firewall"},
+			},
+			false,
+		},
+		{
+			"invalid: depends_on: empty name",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:    String("path"),
+				DependsOn: []string{""},
+			},
+			false,
+		},
+		{
+			"invalid: depends_on: schedule condition",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ScheduleConditionConfig{
+					ScheduleMonitorConfig: ScheduleMonitorConfig{
+						Cron: String("* * * * * * *"),
+					},
+				},
+				Module:    String("path"),
+				DependsOn: []string{"firewall"},
+			},
+			false,
+		},
+		{
+			"invalid: cooldown: negative",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:   String("path"),
+				Cooldown: TimeDuration(-5 * time.Minute),
+			},
+			false,
+		},
+		{
+			"invalid: input_snapshots: negative",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:         String("path"),
+				InputSnapshots: Int(-1),
+			},
+			false,
+		},
+		{
+			"invalid: expire_action",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:       String("path"),
+				ExpiresAt:    Time(time.Now().Add(time.Hour)),
+				ExpireAction: String("destroy"),
+			},
+			false,
+		},
+		{
+			"valid: trigger_on any_input_change",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &CatalogServicesConditionConfig{
+					CatalogServicesMonitorConfig{
+						Regexp: String(".*"),
+					},
+				},
+				ModuleInputs: &ModuleInputConfigs{
+					&ServicesModuleInputConfig{
+						ServicesMonitorConfig: ServicesMonitorConfig{
+							Names: []string{"api"},
+						},
+					},
+				},
+				Module:    String("path"),
+				TriggerOn: String(TaskTriggerOnAnyInputChange),
+			},
+			true,
+		},
+		{
+			"invalid: trigger_on",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:    String("path"),
+				TriggerOn: String("always"),
+			},
+			false,
+		},
+		{
+			"invalid: trigger_on any_input_change with schedule condition",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ScheduleConditionConfig{
+					ScheduleMonitorConfig: ScheduleMonitorConfig{
+						Cron: String("* * * * *"),
+					},
+				},
+				ModuleInputs: &ModuleInputConfigs{
+					&ServicesModuleInputConfig{
+						ServicesMonitorConfig: ServicesMonitorConfig{
+							Names: []string{"api"},
+						},
+					},
+				},
+				Module:    String("path"),
+				TriggerOn: String(TaskTriggerOnAnyInputChange),
+			},
+			false,
+		},
+		{
+			"invalid: circuit_breaker",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module: String("path"),
+				CircuitBreaker: &CircuitBreakerConfig{
+					Enabled:    Bool(true),
+					Threshold:  Int(0),
+					Backoff:    TimeDuration(time.Minute),
+					MaxBackoff: TimeDuration(time.Hour),
+				},
+			},
+			false,
+		},
+		{
+			"valid: maintenance_window",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module: String("path"),
+				MaintenanceWindow: &MaintenanceWindowConfig{
+					Enabled:  Bool(true),
+					Cron:     String("0 22 * * 6"),
+					Duration: TimeDuration(4 * time.Hour),
+				},
+			},
+			true,
+		},
+		{
+			"invalid: maintenance_window",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module: String("path"),
+				MaintenanceWindow: &MaintenanceWindowConfig{
+					Enabled:  Bool(true),
+					Cron:     String("0 22 * * 6"),
+					Duration: TimeDuration(0),
+				},
+			},
+			false,
+		},
+		{
+			"invalid: publish_outputs",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module: String("path"),
+				PublishOutputs: &PublishOutputsConfig{
+					Enabled: Bool(true),
+					Path:    String("/cts/outputs"),
+				},
+			},
+			false,
+		},
+		{
+			"invalid: handler",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:   String("path"),
+				Handlers: &HandlerConfigs{{Exec: &ExecHandlerConfig{}}},
+			},
+			false,
+		},
+		{
+			"invalid: maintenance_window: schedule condition",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ScheduleConditionConfig{
+					ScheduleMonitorConfig: ScheduleMonitorConfig{
+						Cron: String("* * * * * * *"),
+					},
+				},
+				Module: String("path"),
+				MaintenanceWindow: &MaintenanceWindowConfig{
+					Enabled:  Bool(true),
+					Cron:     String("0 22 * * 6"),
+					Duration: TimeDuration(4 * time.Hour),
+				},
+			},
+			false,
+		},
+		{
+			"invalid: cooldown: schedule condition",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ScheduleConditionConfig{
+					ScheduleMonitorConfig: ScheduleMonitorConfig{
+						Cron: String("* * * * * * *"),
+					},
+				},
+				Module:   String("path"),
+				Cooldown: TimeDuration(5 * time.Minute),
+			},
+			false,
+		},
+		{
+			"valid: targeted_apply",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:        String("path"),
+				TargetedApply: Bool(true),
+				ApplyTargets: map[string][]string{
+					"api": {"module.api.aws_instance.api"},
+				},
+			},
+			true,
+		},
+		{
+			"invalid: targeted_apply: missing apply_targets",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:        String("path"),
+				TargetedApply: Bool(true),
+			},
+			false,
+		},
+		{
+			"invalid: targeted_apply: empty target",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:        String("path"),
+				TargetedApply: Bool(true),
+				ApplyTargets: map[string][]string{
+					"api": {""},
+				},
+			},
+			false,
+		},
+		{
+			"invalid: targeted_apply: render_only",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:        String("path"),
+				RenderOnly:    Bool(true),
+				TargetedApply: Bool(true),
+				ApplyTargets: map[string][]string{
+					"api": {"module.api.aws_instance.api"},
+				},
+			},
+			false,
+		},
+		{
+			"valid: labels",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module: String("path"),
+				Labels: map[string]string{"team": "neteng", "example.com/env": ""},
+			},
+			true,
+		},
+		{
+			"invalid: labels: key with invalid characters",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module: String("path"),
+				Labels: map[string]string{"team=neteng": "prod"},
+			},
+			false,
+		},
+		{
+			"invalid: labels: empty key",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module: String("path"),
+				Labels: map[string]string{"": "prod"},
+			},
+			false,
+		},
+		{
+			"valid: sensitive_variables",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:             String("path"),
+				Variables:          map[string]string{"password": "secret"},
+				SensitiveVariables: []string{"password"},
+			},
+			true,
+		},
+		{
+			"invalid: sensitive_variables: not a variable",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:             String("path"),
+				Variables:          map[string]string{"password": "secret"},
+				SensitiveVariables: []string{"token"},
+			},
+			false,
+		},
+		{
+			"valid: variables_from_consul_kv with sensitive_variables",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:                String("path"),
+				SensitiveVariables:    []string{"token"},
+				VariablesFromConsulKV: []string{"config/tasks/web/"},
+			},
+			true,
+		},
+		{
+			"invalid: variables_from_consul_kv: empty prefix",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:                String("path"),
+				VariablesFromConsulKV: []string{""},
+			},
+			false,
+		},
+		{
+			"invalid: variables_from_consul_kv: duplicate prefix",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:                String("path"),
+				VariablesFromConsulKV: []string{"web/", "web/"},
+			},
+			false,
+		},
+		{
+			"valid: consul_token",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:      String("path"),
+				ConsulToken: String("token"),
+			},
+			true,
+		},
+		{
+			"invalid: consul_token and consul_token_file",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:          String("path"),
+				ConsulToken:     String("token"),
+				ConsulTokenFile: String("/path/token"),
+			},
+			false,
+		},
+		{
+			"valid: backend",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module: String("path"),
+				Backend: map[string]interface{}{
+					"consul": map[string]interface{}{
+						"path": "cts/heavy",
+						"lock": false,
+					},
+				},
+			},
+			true,
+		},
+		{
+			"invalid: multiple backends",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module: String("path"),
+				Backend: map[string]interface{}{
+					"consul": map[string]interface{}{},
+					"local":  map[string]interface{}{},
+				},
+			},
+			false,
+		},
+		{
+			"invalid: unsupported backend",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module: String("path"),
+				Backend: map[string]interface{}{
+					"etcd": map[string]interface{}{},
+				},
+			},
+			false,
+		},
+		{
+			"valid: extra_templates",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:         String("path"),
+				ExtraTemplates: []string{"./lb.json.tpl", "./haproxy.cfg.tmpl"},
+			},
+			true,
+		},
+		{
+			"invalid: extra_templates empty path",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:         String("path"),
+				ExtraTemplates: []string{""},
+			},
+			false,
+		},
+		{
+			"invalid: extra_templates duplicate file name",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:         String("path"),
+				ExtraTemplates: []string{"./a/lb.json.tpl", "./b/lb.json.tmpl"},
+			},
+			false,
+		},
+		{
+			"invalid: extra_templates generated file name",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:         String("path"),
+				ExtraTemplates: []string{"./main.tf.tpl"},
+			},
+			false,
+		},
+		{
+			"valid: environments",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:       String("path"),
+				Variables:    map[string]string{"size": `"small"`},
+				Environments: []string{"staging", "prod"},
+				EnvironmentConfigs: &TaskEnvironmentConfigs{
+					{
+						Name:       String("prod"),
+						Variables:  map[string]string{"size": `"large"`},
+						BackendKey: String("cts/prod"),
+					},
+				},
+			},
+			true,
+		},
+		{
+			"invalid: environments duplicate name",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:       String("path"),
+				Environments: []string{"prod", "prod"},
+			},
+			false,
+		},
+		{
+			"invalid: environment not listed",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:       String("path"),
+				Environments: []string{"staging"},
+				EnvironmentConfigs: &TaskEnvironmentConfigs{
+					{Name: String("prod")},
+				},
+			},
+			false,
+		},
+		{
+			"invalid: environment variable not set for task",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:       String("path"),
+				Environments: []string{"prod"},
+				EnvironmentConfigs: &TaskEnvironmentConfigs{
+					{
+						Name:      String("prod"),
+						Variables: map[string]string{"size": `"large"`},
+					},
+				},
+			},
+			false,
+		},
+		{
+			"invalid: environments with render_only",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:       String("path"),
+				RenderOnly:   Bool(true),
+				Environments: []string{"prod"},
+			},
+			false,
+		},
+		{
+			"valid: computed_input",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module: String("path"),
+				ComputedInputs: ComputedInputConfigs{
+					"lb_members": {Expr: String(`[for s in services : s.address]`)},
+				},
+			},
+			true,
+		},
+		{
+			"invalid: computed_input same name as variable",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ServicesConditionConfig{
+					ServicesMonitorConfig: ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+				Module:    String("path"),
+				Variables: map[string]string{"lb_members": `[]`},
+				ComputedInputs: ComputedInputConfigs{
+					"lb_members": {Expr: String(`[for s in services : s.address]`)},
+				},
+			},
+			false,
+		},
+		{
+			"invalid: computed_input same name as condition variable",
+			&TaskConfig{
+				Name: String("task"),
+				Condition: &ConsulKVConditionConfig{
+					ConsulKVMonitorConfig: ConsulKVMonitorConfig{
+						Path: String("key"),
+					},
+				},
+				Module: String("path"),
+				ComputedInputs: ComputedInputConfigs{
+					"consul_kv": {Expr: String(`{}`)},
+				},
+			},
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			err := tc.i.Validate()
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestTaskConfig_Datacenters(t *testing.T) {
+	t.Parallel()
+
+	t.Run("condition and module inputs", func(t *testing.T) {
+		tc := &TaskConfig{
+			Condition: &ServicesConditionConfig{
+				ServicesMonitorConfig: ServicesMonitorConfig{
+					Names:      []string{"api"},
+					Datacenter: String("dc2"),
+				},
+			},
+			ModuleInputs: &ModuleInputConfigs{
+				&ConsulKVModuleInputConfig{
+					ConsulKVMonitorConfig{
+						Path:       String("key"),
+						Datacenter: String("dc1"),
+					},
+				},
+				&ServicesModuleInputConfig{
+					ServicesMonitorConfig{
+						Names:      []string{"web"},
+						Datacenter: String("dc2"),
+					},
+				},
+			},
+		}
+		assert.Equal(t, []string{"dc1", "dc2"}, tc.Datacenters())
+	})
+
+	t.Run("no datacenters", func(t *testing.T) {
+		tc := &TaskConfig{
+			Condition: &ScheduleConditionConfig{ScheduleMonitorConfig{Cron: String("* * * * * * *")}},
+			ModuleInputs: &ModuleInputConfigs{
+				&ServicesModuleInputConfig{
+					ServicesMonitorConfig{Names: []string{"web"}},
+				},
+			},
+		}
+		assert.Empty(t, tc.Datacenters())
+	})
+}
+
+func TestExtraTemplateFilename(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		path     string
+		expected string
+	}{
+		{"lb.json.tpl", "lb.json"},
+		{"./templates/haproxy.cfg.tmpl", "haproxy.cfg"},
+		{"/etc/cts/nodes.tftpl", "nodes"},
+		{"./config.yaml", "config.yaml"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.path, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ExtraTemplateFilename(tc.path))
+		})
+	}
+}
+
+func TestTaskConfig_InheritParentConfig(t *testing.T) {
+	cases := []struct {
+		name               string
+		parentWorkingDir   string
+		parentBufferPeriod BufferPeriodConfig
+		inputTaskConfig    *TaskConfig
+		expectedTaskConfig *TaskConfig
+	}{
+		{
+			name:             "input values set",
+			parentWorkingDir: "path/to/parent",
+			parentBufferPeriod: BufferPeriodConfig{
+				Enabled: Bool(false),
+				Max:     TimeDuration(0 * time.Second),
+				Min:     TimeDuration(0 * time.Second),
+			},
+			inputTaskConfig: &TaskConfig{
+				WorkingDir: String("path"),
+				BufferPeriod: &BufferPeriodConfig{
+					Enabled: Bool(true),
+					Max:     TimeDuration(3 * time.Second),
+					Min:     TimeDuration(3 * time.Second),
+				},
+			},
+			expectedTaskConfig: &TaskConfig{
+				WorkingDir: String("path"),
+				BufferPeriod: &BufferPeriodConfig{
+					Enabled: Bool(true),
+					Max:     TimeDuration(3 * time.Second),
+					Min:     TimeDuration(3 * time.Second),
+				},
+			},
+		},
+		{
+			name:             "empty input, parent enabled",
+			parentWorkingDir: "path/to/parent",
+			parentBufferPeriod: BufferPeriodConfig{
+				Enabled: Bool(true),
+				Max:     TimeDuration(4 * time.Second),
+				Min:     TimeDuration(4 * time.Second),
+			},
+			inputTaskConfig: &TaskConfig{},
+			expectedTaskConfig: &TaskConfig{
+				WorkingDir: String("path/to/parent"),
+				BufferPeriod: &BufferPeriodConfig{
+					Enabled: Bool(true),
+					Max:     TimeDuration(4 * time.Second),
+					Min:     TimeDuration(4 * time.Second),
+				},
+			},
+		},
+		{
+			name:             "empty input, parent bp disabled",
+			parentWorkingDir: "",
+			parentBufferPeriod: BufferPeriodConfig{
+				Enabled: Bool(false),
+				Min:     TimeDuration(0 * time.Second),
+				Max:     TimeDuration(0 * time.Second),
+			},
+			inputTaskConfig: &TaskConfig{},
+			expectedTaskConfig: &TaskConfig{
+				WorkingDir: String(""),
+				BufferPeriod: &BufferPeriodConfig{
+					Enabled: Bool(false),
+					Min:     TimeDuration(0 * time.Second),
+					Max:     TimeDuration(0 * time.Second),
+				},
+			},
+		},
+		{
+			name:             "input no bp min parent disabled",
+			parentWorkingDir: "path/to/parent",
+			parentBufferPeriod: BufferPeriodConfig{
+				Enabled: Bool(false),
+				Min:     TimeDuration(0 * time.Second),
+				Max:     TimeDuration(0 * time.Second),
+			},
+			inputTaskConfig: &TaskConfig{
+				BufferPeriod: &BufferPeriodConfig{
+					Enabled: Bool(true),
+					Max:     TimeDuration(5 * time.Second),
+				},
+			},
+			expectedTaskConfig: &TaskConfig{
+				WorkingDir: String("path/to/parent"),
+				BufferPeriod: &BufferPeriodConfig{
 					Enabled: Bool(true),
 					Min:     TimeDuration(DefaultBufferPeriodMin),
 					Max:     TimeDuration(5 * time.Second),
@@ -1218,7 +2719,7 @@ func TestTasksConfig_Validate(t *testing.T) {
 			},
 			isValid: false,
 		}, {
-			name: "unsupported TF version per task",
+			name: "TF version per task",
 			i: []*TaskConfig{
 				{
 					Name: String("task"),
@@ -1231,6 +2732,27 @@ func TestTasksConfig_Validate(t *testing.T) {
 					DeprecatedTFVersion: String("0.15.0"),
 				},
 			},
+			isValid: true,
+		}, {
+			name: "depends on task",
+			i: []*TaskConfig{
+				dependsOnTask("lb_pools", "firewall"),
+				dependsOnTask("firewall"),
+			},
+			isValid: true,
+		}, {
+			name: "depends on unknown task",
+			i: []*TaskConfig{
+				dependsOnTask("lb_pools", "firewall"),
+			},
+			isValid: false,
+		}, {
+			name: "dependency cycle",
+			i: []*TaskConfig{
+				dependsOnTask("a", "c"),
+				dependsOnTask("b", "a"),
+				dependsOnTask("c", "b"),
+			},
 			isValid: false,
 		},
 	}
@@ -1247,6 +2769,146 @@ func TestTasksConfig_Validate(t *testing.T) {
 	}
 }
 
+func TestOrderByDependencies(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		tasks    TaskConfigs
+		expected []string
+	}{
+		{
+			"no dependencies",
+			TaskConfigs{dependsOnTask("b"), dependsOnTask("a")},
+			[]string{"b", "a"},
+		},
+		{
+			"dependency after task",
+			TaskConfigs{
+				dependsOnTask("lb_pools", "firewall"),
+				dependsOnTask("dns"),
+				dependsOnTask("firewall"),
+			},
+			[]string{"firewall", "lb_pools", "dns"},
+		},
+		{
+			"chain",
+			TaskConfigs{
+				dependsOnTask("c", "b"),
+				dependsOnTask("b", "a"),
+				dependsOnTask("a"),
+			},
+			[]string{"a", "b", "c"},
+		},
+		{
+			"dependency not in list",
+			TaskConfigs{dependsOnTask("b", "a")},
+			[]string{"b"},
+		},
+		{
+			"cycle keeps order",
+			TaskConfigs{dependsOnTask("a", "b"), dependsOnTask("b", "a")},
+			[]string{"a", "b"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ordered := OrderByDependencies(tc.tasks)
+			names := make([]string, len(ordered))
+			for i, task := range ordered {
+				names[i] = StringVal(task.Name)
+			}
+			assert.Equal(t, tc.expected, names)
+		})
+	}
+}
+
+func TestOrderByPriority(t *testing.T) {
+	t.Parallel()
+
+	withPriority := func(name string, priority int, dependsOn ...string) *TaskConfig {
+		task := dependsOnTask(name, dependsOn...)
+		task.Priority = Int(priority)
+		return task
+	}
+
+	cases := []struct {
+		name     string
+		tasks    TaskConfigs
+		expected []string
+	}{
+		{
+			"higher priority first",
+			TaskConfigs{
+				withPriority("housekeeping", 0),
+				withPriority("edge_firewall", 100),
+				withPriority("dns", 10),
+			},
+			[]string{"edge_firewall", "dns", "housekeeping"},
+		},
+		{
+			"same priority keeps order",
+			TaskConfigs{
+				withPriority("b", 1),
+				dependsOnTask("c"),
+				withPriority("a", 1),
+			},
+			[]string{"b", "a", "c"},
+		},
+		{
+			"negative priority last",
+			TaskConfigs{withPriority("a", -1), dependsOnTask("b")},
+			[]string{"b", "a"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ordered := OrderByPriority(tc.tasks)
+			names := make([]string, len(ordered))
+			for i, task := range ordered {
+				names[i] = StringVal(task.Name)
+			}
+			assert.Equal(t, tc.expected, names)
+		})
+	}
+
+	t.Run("dependencies before priority", func(t *testing.T) {
+		tasks := TaskConfigs{
+			withPriority("firewall", 0),
+			withPriority("lb_pools", 100, "firewall"),
+			withPriority("dns", 10),
+		}
+		ordered := OrderByDependencies(OrderByPriority(tasks))
+		names := make([]string, len(ordered))
+		for i, task := range ordered {
+			names[i] = StringVal(task.Name)
+		}
+		assert.Equal(t, []string{"firewall", "lb_pools", "dns"}, names)
+	})
+
+	t.Run("does not modify tasks", func(t *testing.T) {
+		tasks := TaskConfigs{withPriority("a", 0), withPriority("b", 1)}
+		OrderByPriority(tasks)
+		assert.Equal(t, "a", StringVal(tasks[0].Name))
+	})
+}
+
+// dependsOnTask returns a valid task configuration that depends on the tasks
+func dependsOnTask(name string, dependsOn ...string) *TaskConfig {
+	return &TaskConfig{
+		Name: String(name),
+		Condition: &ServicesConditionConfig{
+			ServicesMonitorConfig: ServicesMonitorConfig{
+				Names: []string{"api"},
+			},
+		},
+		Module:    String("path"),
+		DependsOn: dependsOn,
+	}
+}
+
 func TestTaskConfig_validateCondition(t *testing.T) {
 	t.Parallel()
 
@@ -1308,6 +2970,117 @@ func TestTaskConfig_validateCondition(t *testing.T) {
 	}
 }
 
+func TestTaskConfig_HasLabels(t *testing.T) {
+	t.Parallel()
+
+	task := &TaskConfig{
+		Labels: map[string]string{"team": "neteng", "env": "prod"},
+	}
+
+	cases := []struct {
+		name     string
+		selector map[string]string
+		expected bool
+	}{
+		{
+			"nil selector",
+			nil,
+			true,
+		},
+		{
+			"empty selector",
+			map[string]string{},
+			true,
+		},
+		{
+			"single label",
+			map[string]string{"team": "neteng"},
+			true,
+		},
+		{
+			"all labels",
+			map[string]string{"team": "neteng", "env": "prod"},
+			true,
+		},
+		{
+			"different value",
+			map[string]string{"env": "dev"},
+			false,
+		},
+		{
+			"missing label",
+			map[string]string{"team": "neteng", "region": "us"},
+			false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, task.HasLabels(tc.selector))
+		})
+	}
+}
+
+func TestParseLabelSelector(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		input    string
+		expected map[string]string
+		isValid  bool
+	}{
+		{
+			"empty",
+			"",
+			map[string]string{},
+			true,
+		},
+		{
+			"single pair",
+			"team=neteng",
+			map[string]string{"team": "neteng"},
+			true,
+		},
+		{
+			"multiple pairs with spaces",
+			"team = neteng, env=prod,",
+			map[string]string{"team": "neteng", "env": "prod"},
+			true,
+		},
+		{
+			"empty value",
+			"team=",
+			map[string]string{"team": ""},
+			true,
+		},
+		{
+			"missing equals",
+			"team",
+			nil,
+			false,
+		},
+		{
+			"missing key",
+			"=neteng",
+			nil,
+			false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ParseLabelSelector(tc.input)
+			if !tc.isValid {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
 func TestTaskConfig_ValidateForDriver(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,126 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+// variableFuncs are the functions that are available to the expressions of
+// the task variables. The functions are a subset of the Terraform functions
+// with the same names. Functions that read the environment or files of the
+// process are not available, since tasks created with the API can use the
+// expressions and their variables are returned by the API.
+var variableFuncs = map[string]function.Function{
+	"coalesce":  stdlib.CoalesceFunc,
+	"format":    stdlib.FormatFunc,
+	"join":      stdlib.JoinFunc,
+	"lower":     stdlib.LowerFunc,
+	"replace":   stdlib.ReplaceFunc,
+	"split":     stdlib.SplitFunc,
+	"title":     stdlib.TitleFunc,
+	"trimspace": stdlib.TrimSpaceFunc,
+	"upper":     stdlib.UpperFunc,
+}
+
+// evaluateVariables evaluates the values of the task variables that are HCL
+// expressions referencing the task or calling functions, e.g.
+//
+//	variables = {
+//	  lb_name = "\"${task.name}-lb\""
+//	  team    = "upper(task.labels.team)"
+//	}
+//
+// The task can be referenced as task.name, task.description, task.labels,
+// and task.environments. Each value is replaced with the literal of its
+// result so that the variables are derived once when the configuration is
+// finalized. Values that do not reference the task or call functions, or that
+// are not valid expressions, are left as-is.
+func (c *TaskConfig) evaluateVariables() error {
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"task": c.variablesTaskValue(),
+		},
+		Functions: variableFuncs,
+	}
+
+	names := make([]string, 0, len(c.Variables))
+	for name := range c.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		expr, diags := hclsyntax.ParseExpression([]byte(c.Variables[name]), name,
+			hcl.InitialPos)
+		if diags.HasErrors() || !isDerivedExpression(expr) {
+			continue
+		}
+
+		val, diags := expr.Value(ctx)
+		if diags.HasErrors() {
+			return fmt.Errorf("unable to evaluate variable %q for task %q: %s",
+				name, StringVal(c.Name), diags.Error())
+		}
+		c.Variables[name] = string(hclwrite.TokensForValue(val).Bytes())
+	}
+
+	return nil
+}
+
+// variablesTaskValue returns the value of the task that can be referenced by
+// the expressions of the task variables
+func (c *TaskConfig) variablesTaskValue() cty.Value {
+	labels := cty.MapValEmpty(cty.String)
+	if len(c.Labels) > 0 {
+		m := make(map[string]cty.Value, len(c.Labels))
+		for k, v := range c.Labels {
+			m[k] = cty.StringVal(v)
+		}
+		labels = cty.MapVal(m)
+	}
+
+	environments := cty.ListValEmpty(cty.String)
+	if len(c.Environments) > 0 {
+		l := make([]cty.Value, 0, len(c.Environments))
+		for _, env := range c.Environments {
+			l = append(l, cty.StringVal(env))
+		}
+		environments = cty.ListVal(l)
+	}
+
+	return cty.ObjectVal(map[string]cty.Value{
+		"name":         cty.StringVal(StringVal(c.Name)),
+		"description":  cty.StringVal(StringVal(c.Description)),
+		"labels":       labels,
+		"environments": environments,
+	})
+}
+
+// isDerivedExpression returns whether the expression references the task or
+// calls functions and needs to be evaluated. Other values, including values
+// referencing other variables, are passed to the driver as-is.
+func isDerivedExpression(expr hclsyntax.Expression) bool {
+	derived := false
+	hclsyntax.VisitAll(expr, func(n hclsyntax.Node) hcl.Diagnostics {
+		switch n := n.(type) {
+		case *hclsyntax.FunctionCallExpr:
+			derived = true
+		case *hclsyntax.ScopeTraversalExpr:
+			if n.Traversal.RootName() == "task" {
+				derived = true
+			}
+		}
+		return nil
+	})
+	return derived
+}
@@ -0,0 +1,141 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskConfig_evaluateVariables(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		variables map[string]string
+		expected  map[string]string
+	}{
+		{
+			"literals",
+			map[string]string{
+				"count":   "2",
+				"name":    `"web"`,
+				"tags":    `["a", "b"]`,
+				"object":  `{ key = "value" }`,
+				"enabled": "true",
+			},
+			map[string]string{
+				"count":   "2",
+				"name":    `"web"`,
+				"tags":    `["a", "b"]`,
+				"object":  `{ key = "value" }`,
+				"enabled": "true",
+			},
+		},
+		{
+			"interpolation",
+			map[string]string{
+				"lb_name": `"${task.name}-lb"`,
+				"team":    `"team-${task.labels.team}"`,
+			},
+			map[string]string{
+				"lb_name": `"task_a-lb"`,
+				"team":    `"team-neteng"`,
+			},
+		},
+		{
+			"functions",
+			map[string]string{
+				"envs":  `join(",", task.environments)`,
+				"desc":  `format("%s: %s", upper(task.name), task.description)`,
+				"parts": `split("_", task.name)`,
+			},
+			map[string]string{
+				"envs":  `"staging,prod"`,
+				"desc":  `"TASK_A: load balancer"`,
+				"parts": `["task", "a"]`,
+			},
+		},
+		{
+			"not derived from the task is unchanged",
+			map[string]string{
+				"invalid":   `"${task.name`,
+				"reference": `"${services.web}"`,
+			},
+			map[string]string{
+				"invalid":   `"${task.name`,
+				"reference": `"${services.web}"`,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &TaskConfig{
+				Name:         String("task_a"),
+				Description:  String("load balancer"),
+				Labels:       map[string]string{"team": "neteng"},
+				Environments: []string{"staging", "prod"},
+				Variables:    tc.variables,
+			}
+			require.NoError(t, c.evaluateVariables())
+			assert.Equal(t, tc.expected, c.Variables)
+
+			// evaluating again does not change the derived values
+			require.NoError(t, c.evaluateVariables())
+			assert.Equal(t, tc.expected, c.Variables)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		cases := []struct {
+			name  string
+			value string
+		}{
+			{"unknown reference", `lower(services.web)`},
+			{"unknown attribute", `task.datacenter`},
+			{"unsupported function", `env("CONSUL_HTTP_TOKEN")`},
+			{"function error", `format("%d", task.name)`},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				c := &TaskConfig{
+					Name:      String("task_a"),
+					Variables: map[string]string{"var": tc.value},
+				}
+				err := c.evaluateVariables()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), `variable "var" for task "task_a"`)
+			})
+		}
+	})
+}
+
+func TestTaskConfig_Finalize_DerivedVariables(t *testing.T) {
+	t.Parallel()
+
+	varFile := filepath.Join(t.TempDir(), "task.tfvars")
+	require.NoError(t, os.WriteFile(varFile, []byte(`size = "large"`), 0644))
+
+	c := &TaskConfig{
+		Name: String("task_a"),
+		Variables: map[string]string{
+			"lb_name": `"${task.name}-lb"`,
+			"size":    `"small-${task.name}"`,
+		},
+		VarFiles: []string{varFile},
+	}
+	require.NoError(t, c.Finalize())
+
+	// variable files override the variables of the configuration
+	assert.Equal(t, map[string]string{
+		"lb_name": `"task_a-lb"`,
+		"size":    `"large"`,
+	}, c.Variables)
+}
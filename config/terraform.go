@@ -5,9 +5,14 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"text/template"
 
 	"github.com/hashicorp/consul-terraform-sync/logging"
 	ctsVersion "github.com/hashicorp/consul-terraform-sync/version"
@@ -19,18 +24,63 @@ import (
 const (
 	DefaultTFBackendKVPath = "consul-terraform-sync/terraform"
 	logSystemName          = "config"
+
+	// TerraformCLIConfigFilename is the name of the Terraform CLI
+	// configuration file that is generated within the Terraform path for the
+	// provider network mirror
+	TerraformCLIConfigFilename = "cts.tfrc"
 )
 
 // TerraformConfig is the configuration for the Terraform driver.
 type TerraformConfig struct {
-	Version           *string                `mapstructure:"version"`
-	Log               *bool                  `mapstructure:"log"`
-	PersistLog        *bool                  `mapstructure:"persist_log"`
-	Path              *string                `mapstructure:"path"`
-	Backend           map[string]interface{} `mapstructure:"backend"`
-	RequiredProviders map[string]interface{} `mapstructure:"required_providers"`
+	Version           *string                `mapstructure:"version" json:"version"`
+	Log               *bool                  `mapstructure:"log" json:"log"`
+	PersistLog        *bool                  `mapstructure:"persist_log" json:"persist_log"`
+	Path              *string                `mapstructure:"path" json:"path"`
+	Backend           map[string]interface{} `mapstructure:"backend" json:"backend"`
+	RequiredProviders map[string]interface{} `mapstructure:"required_providers" json:"required_providers"`
+
+	// WorkspacePrefix is prepended to the task name for the name of the
+	// task's Terraform workspace. The workspace name is also used for the
+	// task's state in the backend, e.g. the Consul KV path.
+	WorkspacePrefix *string `mapstructure:"workspace_prefix" json:"workspace_prefix"`
+
+	// WorkspaceName is a template for the name of the task's Terraform
+	// workspace. The template can use the function `task` for the task name
+	// and `env` for environment variables, e.g.
+	// `cts-{{ env "CTS_ENV" }}-{{ task }}`. Cannot be configured with
+	// WorkspacePrefix.
+	WorkspaceName *string `mapstructure:"workspace_name" json:"workspace_name"`
+
+	// Sandbox configures Terraform to execute with reduced privileges
+	Sandbox *TerraformSandboxConfig `mapstructure:"sandbox" json:"sandbox"`
+
+	// NetworkMirror is the URL of a provider network mirror. Terraform
+	// installs providers from the mirror instead of the public registry.
+	NetworkMirror *string `mapstructure:"network_mirror" json:"network_mirror"`
+
+	// BinarySource is the URL of the zip archive of the Terraform binary to
+	// install instead of downloading it from releases.hashicorp.com, e.g.
+	// file:///opt/tf/terraform.zip. Requires BinaryChecksum.
+	BinarySource *string `mapstructure:"binary_source" json:"binary_source"`
+
+	// BinaryChecksum is the SHA-256 checksum of the zip archive of the binary
+	// source, e.g. sha256:<hex>
+	BinaryChecksum *string `mapstructure:"binary_checksum" json:"binary_checksum"`
+
+	// PluginCache configures a provider plugin cache directory that is
+	// shared by the tasks
+	PluginCache *TerraformPluginCacheConfig `mapstructure:"plugin_cache" json:"plugin_cache"`
 }
 
+// binaryChecksumRegexp matches a SHA-256 checksum in hex with an optional
+// sha256: prefix
+var binaryChecksumRegexp = regexp.MustCompile(`^(sha256:)?[0-9a-fA-F]{64}$`)
+
+// workspaceNameRegexp matches the names that are supported for Terraform
+// workspaces across the supported backends
+var workspaceNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+
 // DefaultTerraformConfig returns the default configuration struct.
 func DefaultTerraformConfig() *TerraformConfig {
 	wd, err := os.Getwd()
@@ -130,6 +180,20 @@ func (c *TerraformConfig) Copy() *TerraformConfig {
 		}
 	}
 
+	o.WorkspacePrefix = StringCopy(c.WorkspacePrefix)
+
+	o.WorkspaceName = StringCopy(c.WorkspaceName)
+
+	o.Sandbox = c.Sandbox.Copy()
+
+	o.NetworkMirror = StringCopy(c.NetworkMirror)
+
+	o.BinarySource = StringCopy(c.BinarySource)
+
+	o.BinaryChecksum = StringCopy(c.BinaryChecksum)
+
+	o.PluginCache = c.PluginCache.Copy()
+
 	return &o
 }
 
@@ -188,6 +252,34 @@ func (c *TerraformConfig) Merge(o *TerraformConfig) *TerraformConfig {
 		}
 	}
 
+	if o.WorkspacePrefix != nil {
+		r.WorkspacePrefix = StringCopy(o.WorkspacePrefix)
+	}
+
+	if o.WorkspaceName != nil {
+		r.WorkspaceName = StringCopy(o.WorkspaceName)
+	}
+
+	if o.Sandbox != nil {
+		r.Sandbox = r.Sandbox.Merge(o.Sandbox)
+	}
+
+	if o.NetworkMirror != nil {
+		r.NetworkMirror = StringCopy(o.NetworkMirror)
+	}
+
+	if o.BinarySource != nil {
+		r.BinarySource = StringCopy(o.BinarySource)
+	}
+
+	if o.BinaryChecksum != nil {
+		r.BinaryChecksum = StringCopy(o.BinaryChecksum)
+	}
+
+	if o.PluginCache != nil {
+		r.PluginCache = r.PluginCache.Merge(o.PluginCache)
+	}
+
 	return r
 }
 
@@ -237,6 +329,36 @@ func (c *TerraformConfig) Finalize(consul *ConsulConfig) {
 	if c.RequiredProviders == nil {
 		c.RequiredProviders = make(map[string]interface{})
 	}
+
+	if c.WorkspacePrefix == nil {
+		c.WorkspacePrefix = String("")
+	}
+
+	if c.WorkspaceName == nil {
+		c.WorkspaceName = String("")
+	}
+
+	if c.Sandbox == nil {
+		c.Sandbox = &TerraformSandboxConfig{}
+	}
+	c.Sandbox.Finalize()
+
+	if c.NetworkMirror == nil {
+		c.NetworkMirror = String("")
+	}
+
+	if c.BinarySource == nil {
+		c.BinarySource = String("")
+	}
+
+	if c.BinaryChecksum == nil {
+		c.BinaryChecksum = String("")
+	}
+
+	if c.PluginCache == nil {
+		c.PluginCache = &TerraformPluginCacheConfig{}
+	}
+	c.PluginCache.Finalize(*c.Path)
 }
 
 // Validate validates the values and nested values of the configuration struct
@@ -246,48 +368,138 @@ func (c *TerraformConfig) Validate() error {
 	}
 
 	if c.Version != nil && *c.Version != "" {
-		v, err := goVersion.NewSemver(*c.Version)
-		if err != nil {
+		if err := validateTerraformVersion(*c.Version); err != nil {
 			return err
 		}
+	}
 
-		if len(strings.Split(*c.Version, ".")) < 3 {
-			return fmt.Errorf("provide the exact Terraform version to install: %s", *c.Version)
-		}
+	if c.Backend == nil {
+		return fmt.Errorf("missing Terraform backend configuration")
+	}
 
-		if !ctsVersion.TerraformConstraint.Check(v) {
-			return fmt.Errorf("Terraform version is not supported by Consul-"+
-				"Terraform-Sync, try updating to a different version (%s): %s",
-				ctsVersion.CompatibleTerraformVersionConstraint, *c.Version)
+	for k, v := range c.Backend {
+		if err := validateBackend(k, v); err != nil {
+			return err
 		}
 	}
 
-	if c.Backend == nil {
-		return fmt.Errorf("missing Terraform backend configuration")
+	if StringVal(c.WorkspacePrefix) != "" && StringVal(c.WorkspaceName) != "" {
+		return fmt.Errorf("only one of workspace_prefix and workspace_name " +
+			"can be configured for the Terraform driver")
+	}
+
+	// Verify the workspace name is valid using an example task name
+	if _, err := c.Workspace("task"); err != nil {
+		return err
+	}
+
+	if err := c.Sandbox.Validate(); err != nil {
+		return err
 	}
 
-	// Backend is only validated for supported backend label. The backend
-	// configuration options are verified at run time. The allowed backends
-	// for state store have state locking and workspace suppport.
-	for k := range c.Backend {
-		switch k {
-		case "azurerm",
-			"consul",
-			"cos",
-			"gcs",
-			"kubernetes",
-			"local",
-			"manta",
-			"pg",
-			"s3":
+	if mirror := StringVal(c.NetworkMirror); mirror != "" {
+		u, err := url.Parse(mirror)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("invalid network_mirror %q for the Terraform "+
+				"driver, the network mirror must be an https URL", mirror)
+		}
+	}
+
+	if source := StringVal(c.BinarySource); source != "" {
+		u, err := url.Parse(source)
+		if err != nil {
+			return fmt.Errorf("invalid binary_source %q for the Terraform "+
+				"driver: %s", source, err)
+		}
+		switch u.Scheme {
+		case "file", "http", "https":
 		default:
-			return fmt.Errorf("unsupported Terraform backend by Sync %q", k)
+			return fmt.Errorf("invalid binary_source %q for the Terraform "+
+				"driver, the scheme must be file, http, or https", source)
+		}
+
+		if StringVal(c.BinaryChecksum) == "" {
+			return fmt.Errorf("binary_checksum is required to verify the " +
+				"binary_source of the Terraform driver")
 		}
 	}
 
+	if checksum := StringVal(c.BinaryChecksum); checksum != "" {
+		if StringVal(c.BinarySource) == "" {
+			return fmt.Errorf("binary_checksum requires the binary_source " +
+				"of the Terraform driver to be configured")
+		}
+		if !binaryChecksumRegexp.MatchString(checksum) {
+			return fmt.Errorf("invalid binary_checksum %q for the Terraform "+
+				"driver, the checksum must be a SHA-256 checksum in hex, "+
+				"e.g. sha256:<hex>", checksum)
+		}
+	}
+
+	if err := c.PluginCache.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// BinarySHA256 returns the SHA-256 checksum in lowercase hex of the binary
+// source without the sha256: prefix
+func (c *TerraformConfig) BinarySHA256() string {
+	return strings.ToLower(strings.TrimPrefix(StringVal(c.BinaryChecksum), "sha256:"))
+}
+
+// CLIConfigFile returns the path of the Terraform CLI configuration file that
+// is generated for the provider network mirror. Returns an empty string if a
+// network mirror is not configured.
+func (c *TerraformConfig) CLIConfigFile() string {
+	if c == nil || StringVal(c.NetworkMirror) == "" {
+		return ""
+	}
+	return filepath.Join(StringVal(c.Path), TerraformCLIConfigFilename)
+}
+
+// Env returns the environment variables for Terraform to use the generated
+// Terraform CLI configuration file. Returns nil if a network mirror is not
+// configured.
+func (c *TerraformConfig) Env() map[string]string {
+	path := c.CLIConfigFile()
+	if path == "" {
+		return nil
+	}
+	return map[string]string{"TF_CLI_CONFIG_FILE": path}
+}
+
+// Workspace returns the name of the Terraform workspace for a task. The task
+// name is used if neither a workspace prefix nor name is configured.
+func (c *TerraformConfig) Workspace(taskName string) (string, error) {
+	name := StringVal(c.WorkspacePrefix) + taskName
+
+	if tmpl := StringVal(c.WorkspaceName); tmpl != "" {
+		t, err := template.New("workspace_name").Funcs(template.FuncMap{
+			"task": func() string { return taskName },
+			"env":  os.Getenv,
+		}).Option("missingkey=error").Parse(tmpl)
+		if err != nil {
+			return "", fmt.Errorf("unable to parse workspace_name %q: %s", tmpl, err)
+		}
+
+		var sb strings.Builder
+		if err := t.Execute(&sb, nil); err != nil {
+			return "", fmt.Errorf("unable to render workspace_name %q: %s", tmpl, err)
+		}
+		name = sb.String()
+	}
+
+	if !workspaceNameRegexp.MatchString(name) {
+		return "", fmt.Errorf("invalid Terraform workspace name %q for task %q. "+
+			"Workspace names can only contain letters, numbers, dots, "+
+			"underscores, and hyphens", name, taskName)
+	}
+
+	return name, nil
+}
+
 // GoString defines the printable version of this struct.
 func (c *TerraformConfig) GoString() string {
 	if c == nil {
@@ -300,7 +512,14 @@ func (c *TerraformConfig) GoString() string {
 		"PersistLog:%v, "+
 		"Path:%s, "+
 		"Backend:%+v, "+
-		"RequiredProviders:%+v"+
+		"RequiredProviders:%+v, "+
+		"WorkspacePrefix:%s, "+
+		"WorkspaceName:%s, "+
+		"Sandbox:%s, "+
+		"NetworkMirror:%s, "+
+		"BinarySource:%s, "+
+		"BinaryChecksum:%s, "+
+		"PluginCache:%s"+
 		"}",
 		StringVal(c.Version),
 		BoolVal(c.Log),
@@ -308,9 +527,47 @@ func (c *TerraformConfig) GoString() string {
 		StringVal(c.Path),
 		c.Backend,
 		c.RequiredProviders,
+		StringVal(c.WorkspacePrefix),
+		StringVal(c.WorkspaceName),
+		c.Sandbox.GoString(),
+		StringVal(c.NetworkMirror),
+		StringVal(c.BinarySource),
+		StringVal(c.BinaryChecksum),
+		c.PluginCache.GoString(),
 	)
 }
 
+// TaskBackend returns the Terraform backend for a task with backend
+// overrides. Overrides for the same backend type as the driver are merged with
+// the driver's backend arguments. Overrides for a different backend type
+// replace the driver's backend, and the Consul backend uses the default
+// arguments for the Consul configuration. Returns nil if there are no
+// overrides.
+func (c *TerraformConfig) TaskBackend(overrides map[string]interface{},
+	consul *ConsulConfig) map[string]interface{} {
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	backend := make(map[string]interface{}, len(overrides))
+	for k, v := range overrides {
+		args, _ := v.(map[string]interface{})
+		if c != nil {
+			if base, ok := c.Backend[k].(map[string]interface{}); ok {
+				backend[k] = mergeMaps(base, args)
+				continue
+			}
+		}
+
+		if k == "consul" && consul != nil {
+			defaultBackend, _ := DefaultTerraformBackend(consul)
+			args = mergeMaps(defaultBackend["consul"].(map[string]interface{}), args)
+		}
+		backend[k] = args
+	}
+	return backend
+}
+
 // IsConsulBackend returns if the Terraform backend is using Consul KV for
 // remote state store.
 func (c *TerraformConfig) IsConsulBackend() bool {
@@ -321,3 +578,68 @@ func (c *TerraformConfig) IsConsulBackend() bool {
 	_, ok := c.Backend["consul"]
 	return ok
 }
+
+// validateBackend validates that the backend is supported by Sync. The
+// allowed backends for state store have state locking and workspace support.
+// Only the lock and gzip arguments of the Consul backend are validated, the
+// other backend configuration options are verified at run time.
+func validateBackend(name string, args interface{}) error {
+	switch name {
+	case "azurerm",
+		"consul",
+		"cos",
+		"gcs",
+		"kubernetes",
+		"local",
+		"manta",
+		"pg",
+		"s3":
+	default:
+		return fmt.Errorf("unsupported Terraform backend by Sync %q", name)
+	}
+
+	consulArgs, ok := args.(map[string]interface{})
+	if name != "consul" || !ok {
+		return nil
+	}
+
+	for _, arg := range []string{"lock", "gzip"} {
+		v, ok := consulArgs[arg]
+		if !ok {
+			continue
+		}
+		switch val := v.(type) {
+		case bool:
+		case string:
+			if _, err := strconv.ParseBool(val); err != nil {
+				return fmt.Errorf("the %q argument of the consul backend must "+
+					"be a boolean: %q", arg, val)
+			}
+		default:
+			return fmt.Errorf("the %q argument of the consul backend must "+
+				"be a boolean: %v", arg, v)
+		}
+	}
+	return nil
+}
+
+// validateTerraformVersion checks that the Terraform version is an exact
+// version that is supported by Consul-Terraform-Sync
+func validateTerraformVersion(version string) error {
+	v, err := goVersion.NewSemver(version)
+	if err != nil {
+		return err
+	}
+
+	if len(strings.Split(version, ".")) < 3 {
+		return fmt.Errorf("provide the exact Terraform version to install: %s", version)
+	}
+
+	if !ctsVersion.TerraformConstraint.Check(v) {
+		return fmt.Errorf("Terraform version is not supported by Consul-"+
+			"Terraform-Sync, try updating to a different version (%s): %s",
+			ctsVersion.CompatibleTerraformVersionConstraint, version)
+	}
+
+	return nil
+}
@@ -6,7 +6,6 @@ package config
 import (
 	"errors"
 	"fmt"
-	"reflect"
 	"strings"
 
 	"github.com/hashicorp/consul-terraform-sync/logging"
@@ -21,6 +20,11 @@ type TerraformCloudWorkspaceConfig struct {
 	AgentPoolID      *string `mapstructure:"agent_pool_id" json:"agent_pool_id"`
 	AgentPoolName    *string `mapstructure:"agent_pool_name" json:"agent_pool_name"`
 	TerraformVersion *string `mapstructure:"terraform_version" json:"terraform_version"`
+
+	// VariableSets are the names of the variable sets to attach to the
+	// workspace when the task is initialized, in addition to the workspace
+	// variables that are managed from the task's variables.
+	VariableSets []string `mapstructure:"variable_sets" json:"variable_sets"`
 }
 
 func DefaultTerraformCloudWorkspaceConfig() *TerraformCloudWorkspaceConfig {
@@ -29,17 +33,16 @@ func DefaultTerraformCloudWorkspaceConfig() *TerraformCloudWorkspaceConfig {
 		AgentPoolID:      String(""),
 		AgentPoolName:    String(""),
 		TerraformVersion: String(""),
+		VariableSets:     []string{},
 	}
 }
 
 func (c *TerraformCloudWorkspaceConfig) IsEmpty() bool {
-	return (*c == TerraformCloudWorkspaceConfig{}) ||
-		(reflect.DeepEqual(c, &TerraformCloudWorkspaceConfig{
-			ExecutionMode:    String(""),
-			AgentPoolID:      String(""),
-			AgentPoolName:    String(""),
-			TerraformVersion: String(""),
-		}))
+	return StringVal(c.ExecutionMode) == "" &&
+		StringVal(c.AgentPoolID) == "" &&
+		StringVal(c.AgentPoolName) == "" &&
+		StringVal(c.TerraformVersion) == "" &&
+		len(c.VariableSets) == 0
 }
 
 // Copy returns a deep copy of this configuration.
@@ -53,6 +56,11 @@ func (c *TerraformCloudWorkspaceConfig) Copy() *TerraformCloudWorkspaceConfig {
 	o.AgentPoolID = StringCopy(c.AgentPoolID)
 	o.AgentPoolName = StringCopy(c.AgentPoolName)
 	o.TerraformVersion = StringCopy(c.TerraformVersion)
+
+	if c.VariableSets != nil {
+		o.VariableSets = make([]string, len(c.VariableSets))
+		copy(o.VariableSets, c.VariableSets)
+	}
 	return &o
 }
 
@@ -88,6 +96,8 @@ func (c *TerraformCloudWorkspaceConfig) Merge(o *TerraformCloudWorkspaceConfig)
 		r.TerraformVersion = StringCopy(o.TerraformVersion)
 	}
 
+	r.VariableSets = mergeSlices(r.VariableSets, o.VariableSets)
+
 	return r
 }
 
@@ -112,6 +122,10 @@ func (c *TerraformCloudWorkspaceConfig) Finalize() {
 	if c.TerraformVersion == nil {
 		c.TerraformVersion = String("")
 	}
+
+	if c.VariableSets == nil {
+		c.VariableSets = []string{}
+	}
 }
 
 // Validate validates the values of the configuration struct
@@ -156,6 +170,17 @@ func (c *TerraformCloudWorkspaceConfig) Validate() error {
 		}
 	}
 
+	seen := make(map[string]bool, len(c.VariableSets))
+	for _, name := range c.VariableSets {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("variable set names cannot be empty")
+		}
+		if seen[name] {
+			return fmt.Errorf("duplicate variable set %q", name)
+		}
+		seen[name] = true
+	}
+
 	return nil
 }
 
@@ -169,11 +194,13 @@ func (c *TerraformCloudWorkspaceConfig) GoString() string {
 		"AgentPoolID:%s, "+
 		"AgentPoolName:%s, "+
 		"ExecutionMode:%s, "+
-		"TerraformVersion:%s"+
+		"TerraformVersion:%s, "+
+		"VariableSets:%v"+
 		"}",
 		StringVal(c.AgentPoolID),
 		StringVal(c.AgentPoolName),
 		StringVal(c.ExecutionMode),
 		StringVal(c.TerraformVersion),
+		c.VariableSets,
 	)
 }
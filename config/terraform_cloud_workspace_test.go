@@ -46,6 +46,7 @@ func TestTerraformCloudWorkspaceConfig_Copy(t *testing.T) {
 			AgentPoolID:      String("test-id"),
 			AgentPoolName:    String("test-name"),
 			TerraformVersion: String("test-version"),
+			VariableSets:     []string{"varset-1"},
 		}
 		c := o.Copy()
 		assert.Equal(t, o, c)
@@ -53,6 +54,9 @@ func TestTerraformCloudWorkspaceConfig_Copy(t *testing.T) {
 		assert.NotSame(t, o.AgentPoolID, c.AgentPoolID)
 		assert.NotSame(t, o.AgentPoolName, c.AgentPoolName)
 		assert.NotSame(t, o.TerraformVersion, c.TerraformVersion)
+
+		c.VariableSets[0] = "varset-2"
+		assert.Equal(t, "varset-1", o.VariableSets[0])
 	})
 }
 
@@ -160,6 +164,18 @@ func TestTerraformCloudWorkspaceConfig_Merge(t *testing.T) {
 			&TerraformCloudWorkspaceConfig{},
 			&TerraformCloudWorkspaceConfig{TerraformVersion: String("1.0.0")},
 		},
+		{
+			"variable_sets_merges",
+			&TerraformCloudWorkspaceConfig{VariableSets: []string{"a", "b"}},
+			&TerraformCloudWorkspaceConfig{VariableSets: []string{"b", "c"}},
+			&TerraformCloudWorkspaceConfig{VariableSets: []string{"a", "b", "c"}},
+		},
+		{
+			"variable_sets_empty_a",
+			&TerraformCloudWorkspaceConfig{},
+			&TerraformCloudWorkspaceConfig{VariableSets: []string{"a"}},
+			&TerraformCloudWorkspaceConfig{VariableSets: []string{"a"}},
+		},
 	}
 
 	for _, tc := range cases {
@@ -190,6 +206,7 @@ func TestTerraformCloudWorkspaceConfig_Finalize(t *testing.T) {
 				AgentPoolID:      String(""),
 				AgentPoolName:    String(""),
 				TerraformVersion: String(""),
+				VariableSets:     []string{},
 			},
 		},
 		{
@@ -199,12 +216,14 @@ func TestTerraformCloudWorkspaceConfig_Finalize(t *testing.T) {
 				AgentPoolID:      String("apool-1"),
 				AgentPoolName:    String("test"),
 				TerraformVersion: String("1.1.1"),
+				VariableSets:     []string{"varset"},
 			},
 			&TerraformCloudWorkspaceConfig{
 				ExecutionMode:    String("test_mode"),
 				AgentPoolID:      String("apool-1"),
 				AgentPoolName:    String("test"),
 				TerraformVersion: String("1.1.1"),
+				VariableSets:     []string{"varset"},
 			},
 		},
 	}
@@ -318,6 +337,27 @@ func TestTerraformCloudWorkspaceConfig_Validate(t *testing.T) {
 				TerraformVersion: String("0.12.0"),
 			},
 		},
+		{
+			"valid_variable_sets",
+			false,
+			&TerraformCloudWorkspaceConfig{
+				VariableSets: []string{"consul", "aws"},
+			},
+		},
+		{
+			"empty_variable_set",
+			true,
+			&TerraformCloudWorkspaceConfig{
+				VariableSets: []string{"consul", " "},
+			},
+		},
+		{
+			"duplicate_variable_set",
+			true,
+			&TerraformCloudWorkspaceConfig{
+				VariableSets: []string{"consul", "consul"},
+			},
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -351,7 +391,8 @@ func TestTerraformCloudWorkspaceConfig_GoString(t *testing.T) {
 				"AgentPoolID:, " +
 				"AgentPoolName:, " +
 				"ExecutionMode:remote, " +
-				"TerraformVersion:" +
+				"TerraformVersion:, " +
+				"VariableSets:[]" +
 				"}",
 		},
 		{
@@ -361,12 +402,14 @@ func TestTerraformCloudWorkspaceConfig_GoString(t *testing.T) {
 				AgentPoolID:      String("apool-1"),
 				AgentPoolName:    String("test_agent_pool"),
 				TerraformVersion: String("1.0.0"),
+				VariableSets:     []string{"consul"},
 			},
 			"&TerraformCloudWorkspaceConfig{" +
 				"AgentPoolID:apool-1, " +
 				"AgentPoolName:test_agent_pool, " +
 				"ExecutionMode:agent, " +
-				"TerraformVersion:1.0.0" +
+				"TerraformVersion:1.0.0, " +
+				"VariableSets:[consul]" +
 				"}",
 		},
 	}
@@ -385,6 +428,7 @@ func TestTerraformCloudWorkspaceConfig_DefaultTerraformCloudWorkspaceConfig(t *t
 		AgentPoolID:      String(""),
 		AgentPoolName:    String(""),
 		TerraformVersion: String(""),
+		VariableSets:     []string{},
 	}
 	assert.Equal(t, expected, r)
 }
@@ -424,9 +468,17 @@ func TestTerraformCloudWorkspaceConfig_isEmpty(t *testing.T) {
 				AgentPoolID:      String(""),
 				AgentPoolName:    String(""),
 				TerraformVersion: String(""),
+				VariableSets:     []string{},
 			},
 			true,
 		},
+		{
+			"variable_sets",
+			&TerraformCloudWorkspaceConfig{
+				VariableSets: []string{"consul"},
+			},
+			false,
+		},
 	}
 
 	for _, tc := range cases {
@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// DefaultPluginCacheDirname is the name of the default plugin cache directory
+// within the path of the Terraform driver
+const DefaultPluginCacheDirname = "plugin-cache"
+
+// TerraformPluginCacheConfig configures a provider plugin cache directory for
+// the Terraform driver. Without a cache, Terraform downloads the providers
+// into the working directory of each task.
+type TerraformPluginCacheConfig struct {
+	// Enabled determines if the plugin cache is enabled. Disabled by default,
+	// and enabled if any other option is configured.
+	Enabled *bool `mapstructure:"enabled" json:"enabled"`
+
+	// Dir is the path of the plugin cache directory. Defaults to the
+	// plugin-cache directory within the path of the Terraform driver.
+	Dir *string `mapstructure:"dir" json:"dir"`
+
+	// PerTask configures a separate cache directory for each task within Dir
+	// instead of a cache shared by all tasks. Terraform does not support
+	// concurrent installs into the same cache, so installs into a shared cache
+	// are executed one task at a time. A cache per task allows tasks to
+	// initialize in parallel.
+	PerTask *bool `mapstructure:"per_task" json:"per_task"`
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *TerraformPluginCacheConfig) Copy() *TerraformPluginCacheConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o TerraformPluginCacheConfig
+	o.Enabled = BoolCopy(c.Enabled)
+	o.Dir = StringCopy(c.Dir)
+	o.PerTask = BoolCopy(c.PerTask)
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+// Maps and slices are merged, most other values are overwritten. Complex
+// structs define their own merge functionality.
+func (c *TerraformPluginCacheConfig) Merge(o *TerraformPluginCacheConfig) *TerraformPluginCacheConfig {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+
+	if o.Enabled != nil {
+		r.Enabled = BoolCopy(o.Enabled)
+	}
+
+	if o.Dir != nil {
+		r.Dir = StringCopy(o.Dir)
+	}
+
+	if o.PerTask != nil {
+		r.PerTask = BoolCopy(o.PerTask)
+	}
+
+	return r
+}
+
+// Finalize ensures that the receiver contains no nil pointers. For nil pointers,
+// Finalize sets default values where necessary. The path is the path of the
+// Terraform driver for the default cache directory.
+func (c *TerraformPluginCacheConfig) Finalize(path string) {
+	if c == nil {
+		return
+	}
+
+	if c.Enabled == nil {
+		// some options configured, assume user intention is enabled
+		c.Enabled = Bool(c.Dir != nil || c.PerTask != nil)
+	}
+
+	if c.Dir == nil || *c.Dir == "" {
+		c.Dir = String(filepath.Join(path, DefaultPluginCacheDirname))
+	}
+
+	if c.PerTask == nil {
+		c.PerTask = Bool(false)
+	}
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *TerraformPluginCacheConfig) Validate() error {
+	if c == nil {
+		// config is not required, return early
+		return nil
+	}
+
+	if !BoolVal(c.Enabled) {
+		return nil
+	}
+
+	if StringVal(c.Dir) == "" {
+		return fmt.Errorf("plugin_cache: dir is required")
+	}
+
+	return nil
+}
+
+// TaskDir returns the plugin cache directory for a task. Returns an empty
+// string if the plugin cache is not enabled.
+func (c *TerraformPluginCacheConfig) TaskDir(taskName string) string {
+	if c == nil || !BoolVal(c.Enabled) {
+		return ""
+	}
+
+	dir := StringVal(c.Dir)
+	if BoolVal(c.PerTask) {
+		return filepath.Join(dir, taskName)
+	}
+	return dir
+}
+
+// GoString defines the printable version of this struct.
+func (c *TerraformPluginCacheConfig) GoString() string {
+	if c == nil {
+		return "(*TerraformPluginCacheConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&TerraformPluginCacheConfig{"+
+		"Enabled:%v, "+
+		"Dir:%s, "+
+		"PerTask:%v"+
+		"}",
+		BoolVal(c.Enabled),
+		StringVal(c.Dir),
+		BoolVal(c.PerTask),
+	)
+}
@@ -0,0 +1,218 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// defaultTerraformPluginCacheConfig returns the finalized plugin cache
+// configuration for a Terraform driver with the path that does not configure
+// a plugin cache
+func defaultTerraformPluginCacheConfig(path string) *TerraformPluginCacheConfig {
+	return &TerraformPluginCacheConfig{
+		Enabled: Bool(false),
+		Dir:     String(filepath.Join(path, DefaultPluginCacheDirname)),
+		PerTask: Bool(false),
+	}
+}
+
+func TestTerraformPluginCacheConfig_Copy(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *TerraformPluginCacheConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&TerraformPluginCacheConfig{},
+		},
+		{
+			"fully configured",
+			&TerraformPluginCacheConfig{
+				Enabled: Bool(true),
+				Dir:     String("/opt/cts/plugin-cache"),
+				PerTask: Bool(true),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Copy()
+			assert.Equal(t, tc.a, r)
+		})
+	}
+}
+
+func TestTerraformPluginCacheConfig_Merge(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *TerraformPluginCacheConfig
+		b    *TerraformPluginCacheConfig
+		r    *TerraformPluginCacheConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&TerraformPluginCacheConfig{},
+			&TerraformPluginCacheConfig{},
+		},
+		{
+			"nil_b",
+			&TerraformPluginCacheConfig{},
+			nil,
+			&TerraformPluginCacheConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"overrides",
+			&TerraformPluginCacheConfig{
+				Enabled: Bool(false),
+				Dir:     String("a"),
+				PerTask: Bool(false),
+			},
+			&TerraformPluginCacheConfig{
+				Enabled: Bool(true),
+				Dir:     String("b"),
+				PerTask: Bool(true),
+			},
+			&TerraformPluginCacheConfig{
+				Enabled: Bool(true),
+				Dir:     String("b"),
+				PerTask: Bool(true),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			assert.Equal(t, tc.r, r)
+		})
+	}
+}
+
+func TestTerraformPluginCacheConfig_Finalize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		i    *TerraformPluginCacheConfig
+		r    *TerraformPluginCacheConfig
+	}{
+		{
+			"empty",
+			&TerraformPluginCacheConfig{},
+			defaultTerraformPluginCacheConfig("/opt/cts"),
+		},
+		{
+			"only per_task",
+			&TerraformPluginCacheConfig{
+				PerTask: Bool(true),
+			},
+			&TerraformPluginCacheConfig{
+				Enabled: Bool(true),
+				Dir:     String("/opt/cts/plugin-cache"),
+				PerTask: Bool(true),
+			},
+		},
+		{
+			"dir",
+			&TerraformPluginCacheConfig{
+				Dir: String("/var/cache/terraform"),
+			},
+			&TerraformPluginCacheConfig{
+				Enabled: Bool(true),
+				Dir:     String("/var/cache/terraform"),
+				PerTask: Bool(false),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			tc.i.Finalize("/opt/cts")
+			assert.Equal(t, tc.r, tc.i)
+		})
+	}
+}
+
+func TestTerraformPluginCacheConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		i       *TerraformPluginCacheConfig
+		isValid bool
+	}{
+		{
+			"nil",
+			nil,
+			true,
+		},
+		{
+			"disabled",
+			&TerraformPluginCacheConfig{Enabled: Bool(false)},
+			true,
+		},
+		{
+			"enabled",
+			&TerraformPluginCacheConfig{
+				Enabled: Bool(true),
+				Dir:     String("/opt/cts/plugin-cache"),
+			},
+			true,
+		},
+		{
+			"missing dir",
+			&TerraformPluginCacheConfig{
+				Enabled: Bool(true),
+				Dir:     String(""),
+			},
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			err := tc.i.Validate()
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestTerraformPluginCacheConfig_TaskDir(t *testing.T) {
+	t.Parallel()
+
+	var disabled *TerraformPluginCacheConfig
+	assert.Equal(t, "", disabled.TaskDir("web"))
+
+	shared := &TerraformPluginCacheConfig{Enabled: Bool(true), Dir: String("/cache")}
+	assert.Equal(t, "/cache", shared.TaskDir("web"))
+
+	perTask := &TerraformPluginCacheConfig{Enabled: Bool(true),
+		Dir: String("/cache"), PerTask: Bool(true)}
+	assert.Equal(t, filepath.Join("/cache", "web"), perTask.TaskDir("web"))
+}
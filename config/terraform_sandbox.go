@@ -0,0 +1,198 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultSandboxID is the default user and group ID of the sandbox, which
+// keeps the user and group of CTS
+const DefaultSandboxID = -1
+
+// DefaultSandboxEnvAllowlist returns the default environment variables of CTS
+// that are passed to Terraform when the sandbox is enabled
+func DefaultSandboxEnvAllowlist() []string {
+	return []string{"PATH", "HOME", "TMPDIR"}
+}
+
+// TerraformSandboxConfig configures the Terraform driver to execute Terraform
+// with reduced privileges. CTS often runs with powerful credentials, and the
+// sandbox limits what a malicious module or provider can access.
+type TerraformSandboxConfig struct {
+	// Enabled determines if the sandbox is enabled. Disabled by default, and
+	// enabled if any other option is configured.
+	Enabled *bool `mapstructure:"enabled" json:"enabled"`
+
+	// UID and GID are the user and group IDs to execute Terraform as. The
+	// default of -1 keeps the user and group of CTS. Linux only.
+	UID *int `mapstructure:"uid" json:"uid"`
+	GID *int `mapstructure:"gid" json:"gid"`
+
+	// EnvAllowlist is the names of the environment variables of CTS that are
+	// passed to Terraform. Environment variables configured for the task are
+	// always passed.
+	EnvAllowlist []string `mapstructure:"env_allowlist" json:"env_allowlist"`
+
+	// ConfineWorkingDir restricts Terraform to only write to files within the
+	// task's working directory and the temporary directory. Requires Landlock
+	// support of the Linux kernel.
+	ConfineWorkingDir *bool `mapstructure:"confine_working_dir" json:"confine_working_dir"`
+
+	// SeccompProfile is the path to a seccomp profile in JSON format that
+	// denies system calls to Terraform. Linux only.
+	SeccompProfile *string `mapstructure:"seccomp_profile" json:"seccomp_profile"`
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *TerraformSandboxConfig) Copy() *TerraformSandboxConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o TerraformSandboxConfig
+	o.Enabled = BoolCopy(c.Enabled)
+	o.UID = IntCopy(c.UID)
+	o.GID = IntCopy(c.GID)
+
+	if c.EnvAllowlist != nil {
+		o.EnvAllowlist = make([]string, 0, len(c.EnvAllowlist))
+		o.EnvAllowlist = append(o.EnvAllowlist, c.EnvAllowlist...)
+	}
+
+	o.ConfineWorkingDir = BoolCopy(c.ConfineWorkingDir)
+	o.SeccompProfile = StringCopy(c.SeccompProfile)
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+// Maps and slices are merged, most other values are overwritten. Complex
+// structs define their own merge functionality.
+func (c *TerraformSandboxConfig) Merge(o *TerraformSandboxConfig) *TerraformSandboxConfig {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+
+	if o.Enabled != nil {
+		r.Enabled = BoolCopy(o.Enabled)
+	}
+
+	if o.UID != nil {
+		r.UID = IntCopy(o.UID)
+	}
+
+	if o.GID != nil {
+		r.GID = IntCopy(o.GID)
+	}
+
+	r.EnvAllowlist = mergeSlices(r.EnvAllowlist, o.EnvAllowlist)
+
+	if o.ConfineWorkingDir != nil {
+		r.ConfineWorkingDir = BoolCopy(o.ConfineWorkingDir)
+	}
+
+	if o.SeccompProfile != nil {
+		r.SeccompProfile = StringCopy(o.SeccompProfile)
+	}
+
+	return r
+}
+
+// Finalize ensures that the receiver contains no nil pointers. For nil pointers,
+// Finalize sets default values where necessary
+func (c *TerraformSandboxConfig) Finalize() {
+	if c == nil {
+		return
+	}
+
+	if c.Enabled == nil {
+		// some options configured, assume user intention is enabled
+		c.Enabled = Bool(c.UID != nil || c.GID != nil || c.EnvAllowlist != nil ||
+			c.ConfineWorkingDir != nil || c.SeccompProfile != nil)
+	}
+
+	if c.UID == nil {
+		c.UID = Int(DefaultSandboxID)
+	}
+
+	if c.GID == nil {
+		c.GID = Int(DefaultSandboxID)
+	}
+
+	if c.EnvAllowlist == nil {
+		c.EnvAllowlist = DefaultSandboxEnvAllowlist()
+	}
+
+	if c.ConfineWorkingDir == nil {
+		c.ConfineWorkingDir = Bool(false)
+	}
+
+	if c.SeccompProfile == nil {
+		c.SeccompProfile = String("")
+	}
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *TerraformSandboxConfig) Validate() error {
+	if c == nil {
+		// config is not required, return early
+		return nil
+	}
+
+	if !BoolVal(c.Enabled) {
+		return nil
+	}
+
+	if IntVal(c.UID) < DefaultSandboxID {
+		return fmt.Errorf("sandbox: invalid uid %d", IntVal(c.UID))
+	}
+
+	if IntVal(c.GID) < DefaultSandboxID {
+		return fmt.Errorf("sandbox: invalid gid %d", IntVal(c.GID))
+	}
+
+	for _, name := range c.EnvAllowlist {
+		if name == "" || strings.Contains(name, "=") {
+			return fmt.Errorf("sandbox: invalid environment variable name %q "+
+				"in env_allowlist", name)
+		}
+	}
+
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *TerraformSandboxConfig) GoString() string {
+	if c == nil {
+		return "(*TerraformSandboxConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&TerraformSandboxConfig{"+
+		"Enabled:%v, "+
+		"UID:%d, "+
+		"GID:%d, "+
+		"EnvAllowlist:%s, "+
+		"ConfineWorkingDir:%v, "+
+		"SeccompProfile:%s"+
+		"}",
+		BoolVal(c.Enabled),
+		IntVal(c.UID),
+		IntVal(c.GID),
+		c.EnvAllowlist,
+		BoolVal(c.ConfineWorkingDir),
+		StringVal(c.SeccompProfile),
+	)
+}
@@ -0,0 +1,273 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// defaultTerraformSandboxConfig returns the finalized sandbox configuration
+// for a Terraform driver that does not configure a sandbox
+func defaultTerraformSandboxConfig() *TerraformSandboxConfig {
+	return &TerraformSandboxConfig{
+		Enabled:           Bool(false),
+		UID:               Int(DefaultSandboxID),
+		GID:               Int(DefaultSandboxID),
+		EnvAllowlist:      DefaultSandboxEnvAllowlist(),
+		ConfineWorkingDir: Bool(false),
+		SeccompProfile:    String(""),
+	}
+}
+
+func TestTerraformSandboxConfig_Copy(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *TerraformSandboxConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&TerraformSandboxConfig{},
+		},
+		{
+			"fully configured",
+			&TerraformSandboxConfig{
+				Enabled:           Bool(true),
+				UID:               Int(1001),
+				GID:               Int(1001),
+				EnvAllowlist:      []string{"PATH"},
+				ConfineWorkingDir: Bool(true),
+				SeccompProfile:    String("seccomp.json"),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Copy()
+			assert.Equal(t, tc.a, r)
+		})
+	}
+}
+
+func TestTerraformSandboxConfig_Merge(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *TerraformSandboxConfig
+		b    *TerraformSandboxConfig
+		r    *TerraformSandboxConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&TerraformSandboxConfig{},
+			&TerraformSandboxConfig{},
+		},
+		{
+			"nil_b",
+			&TerraformSandboxConfig{},
+			nil,
+			&TerraformSandboxConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"overrides",
+			&TerraformSandboxConfig{
+				Enabled:           Bool(false),
+				UID:               Int(1001),
+				GID:               Int(1001),
+				ConfineWorkingDir: Bool(false),
+				SeccompProfile:    String("a.json"),
+			},
+			&TerraformSandboxConfig{
+				Enabled:           Bool(true),
+				UID:               Int(1002),
+				GID:               Int(1002),
+				ConfineWorkingDir: Bool(true),
+				SeccompProfile:    String("b.json"),
+			},
+			&TerraformSandboxConfig{
+				Enabled:           Bool(true),
+				UID:               Int(1002),
+				GID:               Int(1002),
+				ConfineWorkingDir: Bool(true),
+				SeccompProfile:    String("b.json"),
+			},
+		},
+		{
+			"merges env_allowlist",
+			&TerraformSandboxConfig{
+				EnvAllowlist: []string{"PATH", "HOME"},
+			},
+			&TerraformSandboxConfig{
+				EnvAllowlist: []string{"HOME", "HTTPS_PROXY"},
+			},
+			&TerraformSandboxConfig{
+				EnvAllowlist: []string{"PATH", "HOME", "HTTPS_PROXY"},
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			assert.Equal(t, tc.r, r)
+		})
+	}
+}
+
+func TestTerraformSandboxConfig_Finalize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		i    *TerraformSandboxConfig
+		r    *TerraformSandboxConfig
+	}{
+		{
+			"empty",
+			&TerraformSandboxConfig{},
+			defaultTerraformSandboxConfig(),
+		},
+		{
+			"only uid",
+			&TerraformSandboxConfig{
+				UID: Int(1001),
+			},
+			&TerraformSandboxConfig{
+				Enabled:           Bool(true),
+				UID:               Int(1001),
+				GID:               Int(DefaultSandboxID),
+				EnvAllowlist:      []string{"PATH", "HOME", "TMPDIR"},
+				ConfineWorkingDir: Bool(false),
+				SeccompProfile:    String(""),
+			},
+		},
+		{
+			"empty env_allowlist",
+			&TerraformSandboxConfig{
+				EnvAllowlist: []string{},
+			},
+			&TerraformSandboxConfig{
+				Enabled:           Bool(true),
+				UID:               Int(DefaultSandboxID),
+				GID:               Int(DefaultSandboxID),
+				EnvAllowlist:      []string{},
+				ConfineWorkingDir: Bool(false),
+				SeccompProfile:    String(""),
+			},
+		},
+		{
+			"disabled",
+			&TerraformSandboxConfig{
+				Enabled:           Bool(false),
+				ConfineWorkingDir: Bool(true),
+			},
+			&TerraformSandboxConfig{
+				Enabled:           Bool(false),
+				UID:               Int(DefaultSandboxID),
+				GID:               Int(DefaultSandboxID),
+				EnvAllowlist:      []string{"PATH", "HOME", "TMPDIR"},
+				ConfineWorkingDir: Bool(true),
+				SeccompProfile:    String(""),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			tc.i.Finalize()
+			assert.Equal(t, tc.r, tc.i)
+		})
+	}
+}
+
+func TestTerraformSandboxConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		i       *TerraformSandboxConfig
+		isValid bool
+	}{
+		{
+			"nil",
+			nil,
+			true,
+		},
+		{
+			"default",
+			defaultTerraformSandboxConfig(),
+			true,
+		},
+		{
+			"valid",
+			&TerraformSandboxConfig{
+				Enabled:      Bool(true),
+				UID:          Int(1001),
+				GID:          Int(0),
+				EnvAllowlist: []string{"PATH"},
+			},
+			true,
+		},
+		{
+			"invalid uid",
+			&TerraformSandboxConfig{
+				Enabled: Bool(true),
+				UID:     Int(-2),
+			},
+			false,
+		},
+		{
+			"invalid gid",
+			&TerraformSandboxConfig{
+				Enabled: Bool(true),
+				GID:     Int(-2),
+			},
+			false,
+		},
+		{
+			"invalid env_allowlist",
+			&TerraformSandboxConfig{
+				Enabled:      Bool(true),
+				EnvAllowlist: []string{"PATH=/bin"},
+			},
+			false,
+		},
+		{
+			"disabled invalid",
+			&TerraformSandboxConfig{
+				Enabled: Bool(false),
+				UID:     Int(-2),
+			},
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			err := tc.i.Validate()
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
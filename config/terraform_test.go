@@ -6,6 +6,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -159,6 +161,18 @@ func TestTerraformConfig_Merge(t *testing.T) {
 			&TerraformConfig{PersistLog: Bool(true)},
 			&TerraformConfig{PersistLog: Bool(true)},
 		},
+		{
+			"workspace_prefix_overrides",
+			&TerraformConfig{WorkspacePrefix: String("cts-")},
+			&TerraformConfig{WorkspacePrefix: String("prod-")},
+			&TerraformConfig{WorkspacePrefix: String("prod-")},
+		},
+		{
+			"workspace_name_empty_one",
+			&TerraformConfig{WorkspaceName: String("{{ task }}")},
+			&TerraformConfig{},
+			&TerraformConfig{WorkspaceName: String("{{ task }}")},
+		},
 		{
 			"path_overrides",
 			&TerraformConfig{Path: String("path")},
@@ -409,6 +423,13 @@ func TestTerraformConfig_Finalize(t *testing.T) {
 				Path:              String(wd),
 				Backend:           map[string]interface{}{},
 				RequiredProviders: map[string]interface{}{},
+				WorkspacePrefix:   String(""),
+				WorkspaceName:     String(""),
+				Sandbox:           defaultTerraformSandboxConfig(),
+				NetworkMirror:     String(""),
+				BinarySource:      String(""),
+				BinaryChecksum:    String(""),
+				PluginCache:       defaultTerraformPluginCacheConfig(wd),
 			},
 		},
 		{
@@ -428,6 +449,13 @@ func TestTerraformConfig_Finalize(t *testing.T) {
 					},
 				},
 				RequiredProviders: map[string]interface{}{},
+				WorkspacePrefix:   String(""),
+				WorkspaceName:     String(""),
+				Sandbox:           defaultTerraformSandboxConfig(),
+				NetworkMirror:     String(""),
+				BinarySource:      String(""),
+				BinaryChecksum:    String(""),
+				PluginCache:       defaultTerraformPluginCacheConfig(wd),
 			},
 		},
 		{
@@ -457,6 +485,13 @@ func TestTerraformConfig_Finalize(t *testing.T) {
 					},
 				},
 				RequiredProviders: map[string]interface{}{},
+				WorkspacePrefix:   String(""),
+				WorkspaceName:     String(""),
+				Sandbox:           defaultTerraformSandboxConfig(),
+				NetworkMirror:     String(""),
+				BinarySource:      String(""),
+				BinaryChecksum:    String(""),
+				PluginCache:       defaultTerraformPluginCacheConfig(wd),
 			},
 		},
 		{
@@ -479,6 +514,13 @@ func TestTerraformConfig_Finalize(t *testing.T) {
 					},
 				},
 				RequiredProviders: map[string]interface{}{},
+				WorkspacePrefix:   String(""),
+				WorkspaceName:     String(""),
+				Sandbox:           defaultTerraformSandboxConfig(),
+				NetworkMirror:     String(""),
+				BinarySource:      String(""),
+				BinaryChecksum:    String(""),
+				PluginCache:       defaultTerraformPluginCacheConfig(wd),
 			},
 		},
 		{
@@ -497,6 +539,13 @@ func TestTerraformConfig_Finalize(t *testing.T) {
 				Path:              String(wd),
 				Backend:           map[string]interface{}{},
 				RequiredProviders: map[string]interface{}{},
+				WorkspacePrefix:   String(""),
+				WorkspaceName:     String(""),
+				Sandbox:           defaultTerraformSandboxConfig(),
+				NetworkMirror:     String(""),
+				BinarySource:      String(""),
+				BinaryChecksum:    String(""),
+				PluginCache:       defaultTerraformPluginCacheConfig(wd),
 			},
 		},
 	}
@@ -545,6 +594,121 @@ func TestTerraformConfig_Validate(t *testing.T) {
 			"backend_invalid",
 			&TerraformConfig{Backend: map[string]interface{}{"unsupported": nil}},
 			false,
+		}, {
+			"valid consul backend lock and gzip",
+			&TerraformConfig{Backend: map[string]interface{}{"consul": map[string]interface{}{
+				"lock": false,
+				"gzip": "true",
+			}}},
+			true,
+		}, {
+			"invalid consul backend lock",
+			&TerraformConfig{Backend: map[string]interface{}{"consul": map[string]interface{}{
+				"lock": "no",
+			}}},
+			false,
+		}, {
+			"invalid consul backend gzip",
+			&TerraformConfig{Backend: map[string]interface{}{"consul": map[string]interface{}{
+				"gzip": 1,
+			}}},
+			false,
+		}, {
+			"valid workspace_prefix",
+			&TerraformConfig{
+				Backend:         map[string]interface{}{"local": nil},
+				WorkspacePrefix: String("cts-"),
+			},
+			true,
+		}, {
+			"valid workspace_name",
+			&TerraformConfig{
+				Backend:       map[string]interface{}{"local": nil},
+				WorkspaceName: String(`cts-{{ env "CTS_TEST_ENV" }}-{{ task }}`),
+			},
+			true,
+		}, {
+			"workspace_prefix and workspace_name",
+			&TerraformConfig{
+				Backend:         map[string]interface{}{"local": nil},
+				WorkspacePrefix: String("cts-"),
+				WorkspaceName:   String("{{ task }}"),
+			},
+			false,
+		}, {
+			"invalid workspace_name template",
+			&TerraformConfig{
+				Backend:       map[string]interface{}{"local": nil},
+				WorkspaceName: String("{{ task"),
+			},
+			false,
+		}, {
+			"invalid workspace_prefix characters",
+			&TerraformConfig{
+				Backend:         map[string]interface{}{"local": nil},
+				WorkspacePrefix: String("cts/"),
+			},
+			false,
+		}, {
+			"valid network_mirror",
+			&TerraformConfig{
+				Backend:       map[string]interface{}{"local": nil},
+				NetworkMirror: String("https://mirror.internal/providers"),
+			},
+			true,
+		}, {
+			"invalid network_mirror scheme",
+			&TerraformConfig{
+				Backend:       map[string]interface{}{"local": nil},
+				NetworkMirror: String("http://mirror.internal/providers"),
+			},
+			false,
+		}, {
+			"valid binary_source",
+			&TerraformConfig{
+				Backend:        map[string]interface{}{"local": nil},
+				BinarySource:   String("file:///opt/tf/terraform.zip"),
+				BinaryChecksum: String("sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"),
+			},
+			true,
+		}, {
+			"valid binary_checksum without prefix",
+			&TerraformConfig{
+				Backend:        map[string]interface{}{"local": nil},
+				BinarySource:   String("https://mirror.internal/terraform.zip"),
+				BinaryChecksum: String("AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"),
+			},
+			true,
+		}, {
+			"invalid binary_source scheme",
+			&TerraformConfig{
+				Backend:        map[string]interface{}{"local": nil},
+				BinarySource:   String("ftp://mirror.internal/terraform.zip"),
+				BinaryChecksum: String("sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"),
+			},
+			false,
+		}, {
+			"binary_source without binary_checksum",
+			&TerraformConfig{
+				Backend:      map[string]interface{}{"local": nil},
+				BinarySource: String("file:///opt/tf/terraform.zip"),
+			},
+			false,
+		}, {
+			"binary_checksum without binary_source",
+			&TerraformConfig{
+				Backend:        map[string]interface{}{"local": nil},
+				BinaryChecksum: String("sha256:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"),
+			},
+			false,
+		}, {
+			"invalid binary_checksum",
+			&TerraformConfig{
+				Backend:        map[string]interface{}{"local": nil},
+				BinarySource:   String("file:///opt/tf/terraform.zip"),
+				BinaryChecksum: String("md5:abc"),
+			},
+			false,
 		},
 	}
 
@@ -560,6 +724,176 @@ func TestTerraformConfig_Validate(t *testing.T) {
 	}
 }
 
+func TestTerraformConfig_Workspace(t *testing.T) {
+	t.Setenv("CTS_TEST_ENV", "prod")
+
+	cases := []struct {
+		name     string
+		conf     *TerraformConfig
+		expected string
+		isValid  bool
+	}{
+		{
+			"default",
+			&TerraformConfig{},
+			"web",
+			true,
+		},
+		{
+			"prefix",
+			&TerraformConfig{WorkspacePrefix: String("cts-")},
+			"cts-web",
+			true,
+		},
+		{
+			"name",
+			&TerraformConfig{
+				WorkspaceName: String(`cts-{{ env "CTS_TEST_ENV" }}-{{ task }}`),
+			},
+			"cts-prod-web",
+			true,
+		},
+		{
+			"name renders empty",
+			&TerraformConfig{
+				WorkspaceName: String(`{{ env "CTS_TEST_UNSET" }}`),
+			},
+			"",
+			false,
+		},
+		{
+			"name with invalid function arguments",
+			&TerraformConfig{WorkspaceName: String("{{ env }}")},
+			"",
+			false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := tc.conf.Workspace("web")
+			if !tc.isValid {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestTerraformConfig_Env(t *testing.T) {
+	t.Parallel()
+
+	t.Run("network mirror", func(t *testing.T) {
+		c := &TerraformConfig{
+			Path:          String("/opt/cts"),
+			NetworkMirror: String("https://mirror.internal/providers"),
+		}
+		assert.Equal(t, filepath.Join("/opt/cts", TerraformCLIConfigFilename),
+			c.CLIConfigFile())
+		assert.Equal(t, map[string]string{
+			"TF_CLI_CONFIG_FILE": filepath.Join("/opt/cts", TerraformCLIConfigFilename),
+		}, c.Env())
+	})
+
+	t.Run("no network mirror", func(t *testing.T) {
+		c := &TerraformConfig{
+			Path:          String("/opt/cts"),
+			NetworkMirror: String(""),
+		}
+		assert.Empty(t, c.CLIConfigFile())
+		assert.Nil(t, c.Env())
+	})
+}
+
+func TestTerraformConfig_BinarySHA256(t *testing.T) {
+	t.Parallel()
+
+	c := &TerraformConfig{BinaryChecksum: String("sha256:" + strings.Repeat("AB", 32))}
+	assert.Equal(t, strings.Repeat("ab", 32), c.BinarySHA256())
+}
+
+func TestTerraformConfig_TaskBackend(t *testing.T) {
+	t.Parallel()
+
+	consul := &ConsulConfig{Address: String("127.0.0.1:8500")}
+	consul.Finalize()
+
+	driverBackend := &TerraformConfig{Backend: map[string]interface{}{
+		"consul": map[string]interface{}{
+			"address": "consul.example.com",
+			"path":    "cts/terraform",
+			"gzip":    true,
+		},
+	}}
+
+	cases := []struct {
+		name      string
+		conf      *TerraformConfig
+		overrides map[string]interface{}
+		expected  map[string]interface{}
+	}{
+		{
+			"no overrides",
+			driverBackend,
+			map[string]interface{}{},
+			nil,
+		},
+		{
+			"same backend merges",
+			driverBackend,
+			map[string]interface{}{
+				"consul": map[string]interface{}{
+					"path": "cts/heavy",
+					"lock": false,
+				},
+			},
+			map[string]interface{}{
+				"consul": map[string]interface{}{
+					"address": "consul.example.com",
+					"path":    "cts/heavy",
+					"gzip":    true,
+					"lock":    false,
+				},
+			},
+		},
+		{
+			"different backend replaces",
+			driverBackend,
+			map[string]interface{}{
+				"s3": map[string]interface{}{"bucket": "state"},
+			},
+			map[string]interface{}{
+				"s3": map[string]interface{}{"bucket": "state"},
+			},
+		},
+		{
+			"consul backend defaults",
+			&TerraformConfig{Backend: map[string]interface{}{
+				"local": map[string]interface{}{},
+			}},
+			map[string]interface{}{
+				"consul": map[string]interface{}{"path": "cts/heavy"},
+			},
+			map[string]interface{}{
+				"consul": map[string]interface{}{
+					"address": "127.0.0.1:8500",
+					"path":    "cts/heavy",
+					"gzip":    true,
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tc.conf.TaskBackend(tc.overrides, consul)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
 func TestDefaultTerraformBackend(t *testing.T) {
 	testCases := []struct {
 		name     string
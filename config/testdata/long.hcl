@@ -3,8 +3,11 @@
 
 log_level = "ERR"
 port = 8502
+address = ["127.0.0.1:8558", "unix:///var/run/cts.sock"]
 working_dir = "working"
 id = "cts-123"
+idempotency_key_ttl = "1h"
+once_mode_failure_policy = "continue"
 
 syslog {
   enabled = true
@@ -24,6 +27,28 @@ tls {
   ca_cert = "../testutils/certs/consul_cert.pem"
 }
 
+plan_artifacts {
+  path = "plans"
+}
+
+tfc_run_task {
+  hmac_key = "hmac-key"
+}
+
+proxy {
+  http = "http://proxy.example.com:3128"
+  https = "http://proxy.example.com:3128"
+  no_proxy = ["consul-example.com", "10.0.0.0/8"]
+}
+
+read_only_api {
+  address = ["127.0.0.1:8559"]
+  tls {
+    cert = "../testutils/certs/consul_cert.pem"
+    key = "../testutils/certs/consul_key.pem"
+  }
+}
+
 consul {
   address = "consul-example.com"
   auth {
@@ -96,6 +121,11 @@ task {
   description = "automate services for X to do Y"
   services = ["serviceA", "serviceB", "serviceC"]
   providers = ["X"]
+  provider_overrides = {
+    X = {
+      hostname = "fw-2"
+    }
+  }
   module = "Y"
   condition "catalog-services" {
     regexp = ".*"
@@ -0,0 +1,114 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+)
+
+// TFCRunTaskConfig configures CTS as a Terraform Cloud run task endpoint.
+// Terraform Cloud workspaces can call the endpoint to verify that the Consul
+// state is applied by the CTS task for the workspace before allowing applies.
+type TFCRunTaskConfig struct {
+	// Enabled determines if the run task endpoint is enabled. Disabled by
+	// default, and enabled if the HMAC key is configured.
+	Enabled *bool `mapstructure:"enabled" json:"enabled"`
+
+	// HMACKey is the key configured for the run task in Terraform Cloud. It
+	// is used to verify the signature of run task requests.
+	HMACKey *string `mapstructure:"hmac_key" json:"hmac_key"`
+}
+
+// DefaultTFCRunTaskConfig returns a configuration that is populated with the
+// default values.
+func DefaultTFCRunTaskConfig() *TFCRunTaskConfig {
+	return &TFCRunTaskConfig{}
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *TFCRunTaskConfig) Copy() *TFCRunTaskConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o TFCRunTaskConfig
+	o.Enabled = BoolCopy(c.Enabled)
+	o.HMACKey = StringCopy(c.HMACKey)
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+// Maps and slices are merged, most other values are overwritten. Complex
+// structs define their own merge functionality.
+func (c *TFCRunTaskConfig) Merge(o *TFCRunTaskConfig) *TFCRunTaskConfig {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+
+	if o.Enabled != nil {
+		r.Enabled = BoolCopy(o.Enabled)
+	}
+
+	if o.HMACKey != nil {
+		r.HMACKey = StringCopy(o.HMACKey)
+	}
+
+	return r
+}
+
+// Finalize ensures there no nil pointers.
+func (c *TFCRunTaskConfig) Finalize() {
+	if c == nil {
+		return
+	}
+
+	if c.Enabled == nil {
+		c.Enabled = Bool(StringPresent(c.HMACKey))
+	}
+
+	if c.HMACKey == nil {
+		c.HMACKey = String("")
+	}
+}
+
+// Validate validates the values and required options. This method is recommended
+// to run after Finalize() to ensure the configuration is safe to proceed.
+func (c *TFCRunTaskConfig) Validate() error {
+	if c == nil {
+		// config is not required, return early
+		return nil
+	}
+
+	if BoolVal(c.Enabled) && StringVal(c.HMACKey) == "" {
+		return fmt.Errorf("tfc_run_task: hmac_key is required to verify " +
+			"Terraform Cloud run task requests")
+	}
+
+	return nil
+}
+
+// GoString defines the printable version of this struct.
+func (c *TFCRunTaskConfig) GoString() string {
+	if c == nil {
+		return "(*TFCRunTaskConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&TFCRunTaskConfig{"+
+		"Enabled:%v, "+
+		"HMACKey:%s"+
+		"}",
+		BoolVal(c.Enabled),
+		sensitiveGoString(c.HMACKey),
+	)
+}
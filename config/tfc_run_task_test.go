@@ -0,0 +1,138 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTFCRunTaskConfig_Merge(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		a    *TFCRunTaskConfig
+		b    *TFCRunTaskConfig
+		r    *TFCRunTaskConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&TFCRunTaskConfig{},
+			&TFCRunTaskConfig{},
+		},
+		{
+			"nil_b",
+			&TFCRunTaskConfig{},
+			nil,
+			&TFCRunTaskConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"overrides",
+			&TFCRunTaskConfig{Enabled: Bool(false), HMACKey: String("a")},
+			&TFCRunTaskConfig{Enabled: Bool(true), HMACKey: String("b")},
+			&TFCRunTaskConfig{Enabled: Bool(true), HMACKey: String("b")},
+		},
+		{
+			"empty_one",
+			&TFCRunTaskConfig{HMACKey: String("a")},
+			&TFCRunTaskConfig{},
+			&TFCRunTaskConfig{HMACKey: String("a")},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			assert.Equal(t, tc.r, r)
+		})
+	}
+}
+
+func TestTFCRunTaskConfig_Finalize(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		i    *TFCRunTaskConfig
+		r    *TFCRunTaskConfig
+	}{
+		{
+			"empty",
+			&TFCRunTaskConfig{},
+			&TFCRunTaskConfig{Enabled: Bool(false), HMACKey: String("")},
+		},
+		{
+			"hmac key",
+			&TFCRunTaskConfig{HMACKey: String("key")},
+			&TFCRunTaskConfig{Enabled: Bool(true), HMACKey: String("key")},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			tc.i.Finalize()
+			assert.Equal(t, tc.r, tc.i)
+		})
+	}
+}
+
+func TestTFCRunTaskConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		i       *TFCRunTaskConfig
+		isValid bool
+	}{
+		{
+			"nil",
+			nil,
+			true,
+		},
+		{
+			"disabled",
+			&TFCRunTaskConfig{Enabled: Bool(false), HMACKey: String("")},
+			true,
+		},
+		{
+			"enabled",
+			&TFCRunTaskConfig{Enabled: Bool(true), HMACKey: String("key")},
+			true,
+		},
+		{
+			"enabled without hmac key",
+			&TFCRunTaskConfig{Enabled: Bool(true), HMACKey: String("")},
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			err := tc.i.Validate()
+			if tc.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestTFCRunTaskConfig_GoString(t *testing.T) {
+	t.Parallel()
+
+	c := &TFCRunTaskConfig{Enabled: Bool(true), HMACKey: String("key")}
+	assert.Equal(t, "&TFCRunTaskConfig{Enabled:true, HMACKey:(redacted)}",
+		c.GoString())
+}
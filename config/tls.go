@@ -16,13 +16,13 @@ const (
 
 // TLSConfig is the configuration for TLS.
 type TLSConfig struct {
-	CACert     *string `mapstructure:"ca_cert"`
-	CAPath     *string `mapstructure:"ca_path"`
-	Cert       *string `mapstructure:"cert"`
-	Enabled    *bool   `mapstructure:"enabled"`
-	Key        *string `mapstructure:"key"`
-	ServerName *string `mapstructure:"server_name"`
-	Verify     *bool   `mapstructure:"verify"`
+	CACert     *string `mapstructure:"ca_cert" json:"ca_cert"`
+	CAPath     *string `mapstructure:"ca_path" json:"ca_path"`
+	Cert       *string `mapstructure:"cert" json:"cert"`
+	Enabled    *bool   `mapstructure:"enabled" json:"enabled"`
+	Key        *string `mapstructure:"key" json:"key"`
+	ServerName *string `mapstructure:"server_name" json:"server_name"`
+	Verify     *bool   `mapstructure:"verify" json:"verify"`
 }
 
 // DefaultTLSConfig returns a configuration that is populated with the
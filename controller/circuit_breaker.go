@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/driver"
+)
+
+// taskCircuitBreakers tracks the consecutive failures of tasks and the tasks
+// that are paused because their circuit breaker is open.
+type taskCircuitBreakers struct {
+	mu *sync.Mutex
+
+	circuits map[string]*circuit // taskname => circuit
+}
+
+// circuit is the circuit breaker state for a single task
+type circuit struct {
+	failures int // consecutive failed runs
+	trips    int // consecutive times the circuit opened without a success
+
+	open     bool
+	retrying bool      // true after the backoff until the next run result
+	retryAt  time.Time // zero if the task is not retried automatically
+	timer    *time.Timer
+}
+
+// newTaskCircuitBreakers returns a new tracker for task circuit breakers
+func newTaskCircuitBreakers() *taskCircuitBreakers {
+	return &taskCircuitBreakers{
+		mu:       &sync.Mutex{},
+		circuits: make(map[string]*circuit),
+	}
+}
+
+// Open returns true if the circuit breaker for a task is open. When open, the
+// time the task will be retried is also returned, which is zero if the task
+// is not retried automatically.
+func (b *taskCircuitBreakers) Open(taskName string) (time.Time, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[taskName]
+	if !ok || !c.open {
+		return time.Time{}, false
+	}
+	return c.retryAt, true
+}
+
+// Retrying returns true if the task is being retried after its circuit
+// breaker backoff and the result of the retry has not been recorded yet
+func (b *taskCircuitBreakers) Retrying(taskName string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[taskName]
+	return ok && c.retrying
+}
+
+// Failure records a failed run for a task. If the task has reached the
+// threshold of consecutive failures, the circuit breaker opens and the
+// returned bool is true. The backoff doubles each time the circuit reopens,
+// up to the max backoff. If the backoff is greater than 0, retry is called
+// once the backoff ends.
+func (b *taskCircuitBreakers) Failure(taskName string, cb driver.CircuitBreaker,
+	retry func()) (time.Duration, bool) {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[taskName]
+	if !ok {
+		c = &circuit{}
+		b.circuits[taskName] = c
+	}
+
+	c.failures++
+	c.retrying = false
+	if c.failures < cb.Threshold {
+		return 0, false
+	}
+
+	backoff := cb.Backoff
+	for i := 0; i < c.trips && backoff < cb.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > cb.MaxBackoff {
+		backoff = cb.MaxBackoff
+	}
+
+	c.trips++
+	c.open = true
+	c.retryAt = time.Time{}
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+
+	if backoff > 0 {
+		c.retryAt = time.Now().Add(backoff)
+		var timer *time.Timer
+		timer = time.AfterFunc(backoff, func() {
+			b.mu.Lock()
+			if b.circuits[taskName] != c || c.timer != timer {
+				// circuit was reset or reopened
+				b.mu.Unlock()
+				return
+			}
+			c.open = false
+			c.retrying = true
+			c.retryAt = time.Time{}
+			c.timer = nil
+			b.mu.Unlock()
+
+			retry()
+		})
+		c.timer = timer
+	}
+
+	return backoff, true
+}
+
+// Reset closes the circuit breaker for a task and clears its failures. Any
+// scheduled retry is stopped. Returns true if the circuit breaker was open.
+func (b *taskCircuitBreakers) Reset(taskName string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[taskName]
+	if !ok {
+		return false
+	}
+
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	delete(b.circuits, taskName)
+	return c.open
+}
@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_taskCircuitBreakers_Failure(t *testing.T) {
+	t.Parallel()
+
+	cb := driver.CircuitBreaker{
+		Threshold:  3,
+		Backoff:    time.Minute,
+		MaxBackoff: 3 * time.Minute,
+	}
+	noop := func() {}
+
+	t.Run("opens at threshold", func(t *testing.T) {
+		b := newTaskCircuitBreakers()
+		defer b.Reset("task")
+
+		for i := 0; i < 2; i++ {
+			_, tripped := b.Failure("task", cb, noop)
+			assert.False(t, tripped)
+			_, open := b.Open("task")
+			assert.False(t, open)
+		}
+
+		backoff, tripped := b.Failure("task", cb, noop)
+		assert.True(t, tripped)
+		assert.Equal(t, time.Minute, backoff)
+
+		retryAt, open := b.Open("task")
+		assert.True(t, open)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), retryAt, time.Second)
+	})
+
+	t.Run("backoff doubles up to max", func(t *testing.T) {
+		b := newTaskCircuitBreakers()
+		defer b.Reset("task")
+
+		for i := 0; i < 2; i++ {
+			b.Failure("task", cb, noop)
+		}
+
+		expected := []time.Duration{time.Minute, 2 * time.Minute,
+			3 * time.Minute, 3 * time.Minute}
+		for _, e := range expected {
+			backoff, tripped := b.Failure("task", cb, noop)
+			assert.True(t, tripped)
+			assert.Equal(t, e, backoff)
+		}
+	})
+
+	t.Run("no backoff", func(t *testing.T) {
+		b := newTaskCircuitBreakers()
+		noBackoff := driver.CircuitBreaker{Threshold: 1}
+
+		backoff, tripped := b.Failure("task", noBackoff, func() {
+			t.Fatal("task should not be retried")
+		})
+		assert.True(t, tripped)
+		assert.Equal(t, time.Duration(0), backoff)
+
+		retryAt, open := b.Open("task")
+		assert.True(t, open)
+		assert.True(t, retryAt.IsZero())
+	})
+
+	t.Run("retry after backoff", func(t *testing.T) {
+		b := newTaskCircuitBreakers()
+		retryCh := make(chan struct{}, 1)
+		quick := driver.CircuitBreaker{
+			Threshold:  1,
+			Backoff:    10 * time.Millisecond,
+			MaxBackoff: time.Second,
+		}
+
+		_, tripped := b.Failure("task", quick, func() {
+			retryCh <- struct{}{}
+		})
+		require.True(t, tripped)
+
+		select {
+		case <-retryCh:
+		case <-time.After(time.Second):
+			t.Fatal("task was not retried")
+		}
+
+		// Closed while retrying until the result of the retry is recorded
+		_, open := b.Open("task")
+		assert.False(t, open)
+		assert.True(t, b.Retrying("task"))
+
+		// A failed retry reopens the circuit with a doubled backoff
+		backoff, tripped := b.Failure("task", quick, func() {})
+		assert.True(t, tripped)
+		assert.Equal(t, 20*time.Millisecond, backoff)
+		assert.False(t, b.Retrying("task"))
+		b.Reset("task")
+	})
+}
+
+func Test_taskCircuitBreakers_Reset(t *testing.T) {
+	t.Parallel()
+
+	b := newTaskCircuitBreakers()
+	assert.False(t, b.Reset("task"))
+
+	retryCh := make(chan struct{}, 1)
+	cb := driver.CircuitBreaker{
+		Threshold:  1,
+		Backoff:    50 * time.Millisecond,
+		MaxBackoff: time.Second,
+	}
+	_, tripped := b.Failure("task", cb, func() { retryCh <- struct{}{} })
+	require.True(t, tripped)
+
+	assert.True(t, b.Reset("task"))
+	_, open := b.Open("task")
+	assert.False(t, open)
+
+	select {
+	case <-retryCh:
+		t.Fatal("retry should have been stopped")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Failures are cleared
+	_, tripped = b.Failure("task", driver.CircuitBreaker{Threshold: 2}, func() {})
+	assert.False(t, tripped)
+}
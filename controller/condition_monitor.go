@@ -10,9 +10,9 @@ import (
 	"time"
 
 	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/health"
 	"github.com/hashicorp/consul-terraform-sync/logging"
 	"github.com/hashicorp/consul-terraform-sync/templates"
-	"github.com/hashicorp/cronexpr"
 )
 
 // ConditionMonitor monitors the the conditions for all of the tasks and is
@@ -28,6 +28,13 @@ type ConditionMonitor struct {
 
 	// scheduleStopChs is a map of channels used to stop scheduled tasks
 	scheduleStopChs map[string](chan struct{})
+
+	// reloadMu ensures that provider configuration is reloaded one at a time
+	reloadMu sync.Mutex
+
+	// health is set when CTS runs with supervisor semantics to report that
+	// CTS is degraded while monitoring Consul errors. Optional.
+	health *health.StatusChecker
 }
 
 // NewConditionMonitor configures a new condition monitor
@@ -96,19 +103,39 @@ func (cm *ConditionMonitor) Run(ctx context.Context) error {
 				return
 			}
 			cm.logger.Error("error monitoring template dependencies", "error", err)
+			if cm.health != nil {
+				cm.health.SetDegraded(err)
+			}
 		}
 	}()
 
 	for i := int64(1); ; i++ {
 		select {
 		case tmplID := <-cm.watcherCh:
-			taskName, ok := cm.tasksManager.TaskByTemplate(tmplID)
-			if !ok {
-				cm.logger.Debug("template was notified for update but the template ID does not match any task", "template_id", tmplID)
-				continue
+			// data was received from Consul
+			if cm.health != nil {
+				cm.health.SetHealthy()
 			}
 
-			go cm.runDynamicTask(ctx, taskName) // errors are logged for now
+			// Collect the other templates that were notified at the same time,
+			// e.g. when buffer periods expire together, so that the tasks are
+			// triggered by priority
+			taskNames := make([]string, 0, len(cm.watcherCh)+1)
+			for _, id := range cm.drainWatcherCh(tmplID) {
+				if cm.tasksManager.IsProviderTemplate(id) {
+					go cm.reloadProviders(ctx) // errors are logged for now
+					continue
+				}
+
+				taskName, ok := cm.tasksManager.TaskByTemplate(id)
+				if !ok {
+					cm.logger.Debug("template was notified for update but the template ID does not match any task", "template_id", id)
+					continue
+				}
+				taskNames = append(taskNames, taskName)
+			}
+
+			cm.runDynamicTasks(ctx, taskNames)
 
 		case taskName := <-cm.tasksManager.WatchCreatedScheduleTasks():
 			// Cancel existing goroutines before creating the new scheduled task.
@@ -143,6 +170,17 @@ func (cm *ConditionMonitor) Run(ctx context.Context) error {
 	}
 }
 
+// reloadProviders reloads the provider configuration when the dynamic values
+// of the provider blocks change and re-initializes the affected tasks
+func (cm *ConditionMonitor) reloadProviders(ctx context.Context) {
+	cm.reloadMu.Lock()
+	defer cm.reloadMu.Unlock()
+
+	if err := cm.tasksManager.TaskReloadProviders(ctx); err != nil {
+		cm.logger.Error("error reloading provider configuration", "error", err)
+	}
+}
+
 // runDynamicTask will execute the task as necessary
 func (cm *ConditionMonitor) runDynamicTask(ctx context.Context, taskName string) error {
 	logger := cm.logger.With(taskNameLogKey, taskName)
@@ -161,7 +199,7 @@ func (cm *ConditionMonitor) runDynamicTask(ctx context.Context, taskName string)
 			"a scheduled condition type")
 	}
 
-	if err := cm.tasksManager.TaskRunNow(ctx, taskName); err != nil {
+	if err := cm.tasksManager.triggerTask(ctx, taskName); err != nil {
 		logger.Error("error running task", "error", err)
 		return err
 	}
@@ -169,6 +207,58 @@ func (cm *ConditionMonitor) runDynamicTask(ctx context.Context, taskName string)
 	return nil
 }
 
+// drainWatcherCh returns the template ID and any other template IDs that are
+// already notified on the watcher channel, without blocking. Duplicate IDs are
+// removed.
+func (cm *ConditionMonitor) drainWatcherCh(tmplID string) []string {
+	ids := []string{tmplID}
+	seen := map[string]bool{tmplID: true}
+	for {
+		select {
+		case id := <-cm.watcherCh:
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		default:
+			return ids
+		}
+	}
+}
+
+// runDynamicTasks triggers the dynamic tasks. When the tasks have different
+// priorities, the tasks are triggered in groups by descending priority where
+// each group waits for the runs of the higher priority groups to complete.
+// Tasks of the same priority run concurrently.
+func (cm *ConditionMonitor) runDynamicTasks(ctx context.Context, taskNames []string) {
+	groups := cm.tasksManager.groupByPriority(taskNames)
+	if len(groups) <= 1 {
+		for _, taskName := range taskNames {
+			go cm.runDynamicTask(ctx, taskName) // errors are logged for now
+		}
+		return
+	}
+
+	cm.logger.Debug("triggering tasks by priority", "tasks", taskNames)
+	go func() {
+		for _, group := range groups {
+			var wg sync.WaitGroup
+			for _, taskName := range group {
+				wg.Add(1)
+				go func(taskName string) {
+					defer wg.Done()
+					cm.runDynamicTask(ctx, taskName) // errors are logged for now
+				}(taskName)
+			}
+			wg.Wait()
+
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}()
+}
+
 // runScheduledTask starts up a go-routine for a given scheduled task/driver.
 // The go-routine will manage the task's schedule and trigger the task on time.
 // If there are dependency changes since the task's last run time, then the task
@@ -191,12 +281,22 @@ func (cm *ConditionMonitor) runScheduledTask(ctx context.Context, taskName strin
 			"condition type %T", task.Condition)
 	}
 
-	expr, err := cronexpr.Parse(*cond.Cron)
+	crons := cond.CronExpressions()
+	expr, err := parseSchedule(crons)
 	if err != nil {
-		logger.Error("error parsing task cron", "cron", *cond.Cron, "error", err)
+		logger.Error("error parsing task cron", "crons", crons, "error", err)
 		return err
 	}
 
+	catchUp := config.BoolVal(cond.CatchUp)
+	if catchUp {
+		maxAge := config.TimeDurationVal(cond.CatchUpMaxAge)
+		if err := cm.tasksManager.TaskCatchUpSchedule(ctx, taskName, expr, maxAge); err != nil {
+			// print error but continue
+			logger.Error("error catching up missed scheduled run", "error", err)
+		}
+	}
+
 	nextTime := expr.Next(time.Now())
 	waitTime := time.Until(nextTime)
 	logger.Info("scheduled task next run time", "wait_time", waitTime,
@@ -214,11 +314,20 @@ func (cm *ConditionMonitor) runScheduledTask(ctx context.Context, taskName strin
 				return nil
 			}
 
-			if err := cm.tasksManager.TaskRunNow(ctx, taskName); err != nil {
+			// scheduled runs are suppressed the same as dynamic triggers, e.g.
+			// the run is queued until the task is unlocked if the task is
+			// locked, or until the gates open if a gate is closed
+			if err := cm.tasksManager.triggerTask(ctx, taskName); err != nil {
 				// print error but continue
 				logger.Error("error running task", "error", err)
 			}
 
+			if catchUp {
+				if err := cm.tasksManager.TaskRecordScheduledRun(taskName, time.Now()); err != nil {
+					logger.Error("error recording scheduled run", "error", err)
+				}
+			}
+
 			nextTime := expr.Next(time.Now())
 			waitTime = time.Until(nextTime)
 			logger.Info("scheduled task next run time", "wait_time", waitTime,
@@ -91,6 +91,75 @@ func Test_ConditionMonitor_runDynamicTask(t *testing.T) {
 	})
 }
 
+func Test_ConditionMonitor_runDynamicTasks(t *testing.T) {
+	t.Parallel()
+
+	tm := newTestTasksManager()
+	applied := make(chan string, 2)
+	release := make(chan struct{})
+	for name, priority := range map[string]int{"high": 10, "low": 0} {
+		name := name
+		conf := validTaskConf
+		conf.Name = config.String(name)
+		require.NoError(t, tm.state.SetTask(conf))
+
+		task, err := driver.NewTask(driver.TaskConfig{
+			Name:     name,
+			Enabled:  true,
+			Priority: priority,
+		})
+		require.NoError(t, err)
+		d := new(mocksD.Driver)
+		d.On("Task").Return(task)
+		d.On("TemplateIDs").Return(nil)
+		d.On("RenderTemplate", mock.Anything).Return(true, nil)
+		d.On("ApplyTask", mock.Anything).Return(nil).Run(func(mock.Arguments) {
+			applied <- name
+			if name == "high" {
+				<-release
+			}
+		})
+		require.NoError(t, tm.drivers.Add(name, d))
+	}
+
+	cm := newTestConditionMonitor(tm)
+	cm.runDynamicTasks(context.Background(), []string{"low", "high"})
+
+	select {
+	case name := <-applied:
+		assert.Equal(t, "high", name)
+	case <-time.After(time.Second):
+		t.Fatal("high priority task did not run")
+	}
+
+	select {
+	case <-applied:
+		t.Fatal("low priority task ran before the high priority task completed")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case name := <-applied:
+		assert.Equal(t, "low", name)
+	case <-time.After(time.Second):
+		t.Fatal("low priority task did not run")
+	}
+}
+
+func Test_ConditionMonitor_drainWatcherCh(t *testing.T) {
+	t.Parallel()
+
+	cm := newTestConditionMonitor(nil)
+	cm.watcherCh = make(chan string, 5)
+	cm.watcherCh <- "tmpl_b"
+	cm.watcherCh <- "tmpl_a"
+	cm.watcherCh <- "tmpl_b"
+
+	assert.Equal(t, []string{"tmpl_a", "tmpl_b"}, cm.drainWatcherCh("tmpl_a"))
+	assert.Empty(t, cm.watcherCh)
+}
+
 func Test_ConditionMonitor_runScheduledTask(t *testing.T) {
 	t.Run("happy-path", func(t *testing.T) {
 		tm := newTestTasksManager()
@@ -98,7 +167,7 @@ func Test_ConditionMonitor_runScheduledTask(t *testing.T) {
 		require.NoError(t, err, "unexpected error while setting task state")
 
 		d := new(mocksD.Driver)
-		d.On("Task").Return(scheduledTestTask(t, schedTaskName)).Once()
+		d.On("Task").Return(scheduledTestTask(t, schedTaskName))
 		d.On("RenderTemplate", mock.Anything).Return(true, nil).Once()
 		d.On("ApplyTask", mock.Anything).Return(nil).Once()
 		d.On("TemplateIDs").Return(nil)
@@ -128,6 +197,50 @@ func Test_ConditionMonitor_runScheduledTask(t *testing.T) {
 		d.AssertExpectations(t)
 	})
 
+	t.Run("locked-scheduled-task", func(t *testing.T) {
+		// Tests that a scheduled run of a locked task is queued until the
+		// task is unlocked
+		tm := newTestTasksManager()
+		err := tm.state.SetTask(schedTaskConf)
+		require.NoError(t, err, "unexpected error while setting task state")
+
+		d := new(mocksD.Driver)
+		d.On("Task").Return(scheduledTestTask(t, schedTaskName))
+		d.On("RenderTemplate", mock.Anything).Return(true, nil)
+		applied := make(chan struct{}, 1)
+		d.On("ApplyTask", mock.Anything).Return(nil).Run(func(mock.Arguments) {
+			select {
+			case applied <- struct{}{}:
+			default:
+			}
+		})
+		d.On("TemplateIDs").Return(nil)
+		tm.drivers.Add(schedTaskName, d)
+		_, err = tm.TaskLock(context.Background(), schedTaskName, "operator", "")
+		require.NoError(t, err)
+
+		cm := newTestConditionMonitor(tm)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		stopCh := make(chan struct{}, 1)
+		go cm.runScheduledTask(ctx, schedTaskName, stopCh)
+
+		assert.Eventually(t, func() bool {
+			lock, ok := tm.TaskLockStatus(ctx, schedTaskName)
+			return ok && lock.RunQueued
+		}, 5*time.Second, 50*time.Millisecond)
+		d.AssertNotCalled(t, "ApplyTask", mock.Anything)
+
+		require.NoError(t, tm.TaskUnlock(ctx, schedTaskName))
+		select {
+		case <-applied:
+		case <-time.After(time.Second):
+			t.Fatal("queued run of scheduled task did not run after unlock")
+		}
+		stopCh <- struct{}{}
+	})
+
 	t.Run("dynamic-task-errors", func(t *testing.T) {
 		tm := newTestTasksManager()
 		err := tm.state.SetTask(validTaskConf)
@@ -299,9 +412,14 @@ func Test_ConditionMonitor_Run_ActiveTask(t *testing.T) {
 	// Set task_a to active
 	tm.drivers.SetActive("task_a")
 
-	// Trigger twice on active task_a, task should not complete
-	for i := 0; i < 2; i++ {
+	// Trigger twice on active task_a, task should not complete. Each trigger
+	// is pending before the next is sent, since notifications that are
+	// received together are deduplicated.
+	for i := 1; i <= 2; i++ {
 		cm.watcherCh <- "tmpl_task_a"
+		require.Eventually(t, func() bool {
+			return len(tm.TaskPendingRuns(ctx, "task_a")) == i
+		}, time.Second, time.Millisecond)
 	}
 	select {
 	case <-completedTasksCh:
@@ -0,0 +1,146 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/consul-terraform-sync/client"
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+)
+
+// CheckConsulCompatibility checks that the configuration is compatible with
+// the connected Consul. Namespaces are a Consul Enterprise feature, so when
+// CTS is connected to Consul OSS, namespaces configured for tasks and
+// services are reported up front instead of tasks failing later at query time
+// with "Invalid query parameter: ns" errors.
+func CheckConsulCompatibility(ctx context.Context, conf *config.Config) error {
+	c, err := client.NewConsulClient(conf.Consul, defaultRetry)
+	if err != nil {
+		return err
+	}
+	return checkConsulCompatibility(ctx, conf, c)
+}
+
+func checkConsulCompatibility(ctx context.Context, conf *config.Config,
+	c client.ConsulClientInterface) error {
+
+	logger := logging.FromContext(ctx).Named(ctrlSystemName)
+
+	_, err := c.GetLicense(ctx, nil)
+	if err == nil {
+		logger.Debug("connected to Consul Enterprise")
+		return nil
+	}
+
+	var nonEntErr *client.NonEnterpriseConsulError
+	if !errors.As(err, &nonEntErr) {
+		// Do not block starting up if the edition of Consul cannot be
+		// determined. Any incompatibilities will surface when querying Consul.
+		logger.Warn("unable to determine if connected to Consul Enterprise, "+
+			"skipping Consul Enterprise configuration checks", "error", err)
+		return nil
+	}
+
+	logger.Debug("connected to Consul OSS")
+	fields := namespaceFields(conf)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("namespaces are a Consul Enterprise feature and the "+
+		"connected Consul is not Consul Enterprise. Remove the namespace from "+
+		"the following configuration or connect to Consul Enterprise:\n  - %s",
+		strings.Join(fields, "\n  - "))
+}
+
+// namespaceFields returns a description of each configuration block that
+// has a Consul namespace configured
+func namespaceFields(conf *config.Config) []string {
+	var fields []string
+
+	if conf.DeprecatedServices != nil {
+		for _, s := range *conf.DeprecatedServices {
+			if ns := config.StringVal(s.Namespace); ns != "" {
+				fields = append(fields, fmt.Sprintf("service %q: namespace %q",
+					config.StringVal(s.ID), ns))
+			}
+		}
+	}
+
+	if conf.Consul != nil && conf.Consul.ServiceRegistration != nil {
+		if ns := config.StringVal(conf.Consul.ServiceRegistration.Namespace); ns != "" {
+			fields = append(fields, fmt.Sprintf(
+				"consul.service_registration: namespace %q", ns))
+		}
+	}
+
+	if conf.Consul != nil {
+		if ns := config.StringVal(conf.Consul.KVNamespace); ns != "" {
+			fields = append(fields, fmt.Sprintf("consul: kv_namespace %q", ns))
+		}
+	}
+
+	if conf.Tasks == nil {
+		return fields
+	}
+
+	for _, t := range *conf.Tasks {
+		name := config.StringVal(t.Name)
+		if ns := monitorNamespace(t.Condition); ns != "" {
+			fields = append(fields, fmt.Sprintf("task %q: condition %q: namespace %q",
+				name, monitorBlockLabel(t.Condition), ns))
+		}
+
+		if t.ModuleInputs == nil {
+			continue
+		}
+		for _, mi := range *t.ModuleInputs {
+			if ns := monitorNamespace(mi); ns != "" {
+				fields = append(fields, fmt.Sprintf("task %q: module_input %q: namespace %q",
+					name, monitorBlockLabel(mi), ns))
+			}
+		}
+	}
+
+	return fields
+}
+
+// monitorNamespace returns the namespace configured for a condition or
+// module_input block. Returns an empty string if not configured.
+func monitorNamespace(m config.MonitorConfig) string {
+	switch v := m.(type) {
+	case *config.ServicesConditionConfig:
+		if v != nil {
+			return config.StringVal(v.Namespace)
+		}
+	case *config.ServicesModuleInputConfig:
+		if v != nil {
+			return config.StringVal(v.Namespace)
+		}
+	case *config.CatalogServicesConditionConfig:
+		if v != nil {
+			return config.StringVal(v.Namespace)
+		}
+	case *config.ConsulKVConditionConfig:
+		if v != nil {
+			return config.StringVal(v.Namespace)
+		}
+	case *config.ConsulKVModuleInputConfig:
+		if v != nil {
+			return config.StringVal(v.Namespace)
+		}
+	}
+	return ""
+}
+
+// monitorBlockLabel returns the label of a condition or module_input block,
+// e.g. "consul-kv", from the variable type that it monitors
+func monitorBlockLabel(m config.MonitorConfig) string {
+	return strings.ReplaceAll(m.VariableType(), "_", "-")
+}
@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/client"
+	"github.com/hashicorp/consul-terraform-sync/config"
+	mocksC "github.com/hashicorp/consul-terraform-sync/mocks/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_checkConsulCompatibility(t *testing.T) {
+	t.Parallel()
+
+	nsConf := &config.Config{
+		Tasks: &config.TaskConfigs{
+			{
+				Name: config.String("task_a"),
+				Condition: &config.ServicesConditionConfig{
+					ServicesMonitorConfig: config.ServicesMonitorConfig{
+						Names:     []string{"api"},
+						Namespace: config.String("ns1"),
+					},
+				},
+				ModuleInputs: &config.ModuleInputConfigs{
+					&config.ConsulKVModuleInputConfig{
+						ConsulKVMonitorConfig: config.ConsulKVMonitorConfig{
+							Path:      config.String("key"),
+							Namespace: config.String("ns2"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	noNSConf := &config.Config{
+		Tasks: &config.TaskConfigs{
+			{
+				Name: config.String("task_a"),
+				Condition: &config.ServicesConditionConfig{
+					ServicesMonitorConfig: config.ServicesMonitorConfig{
+						Names: []string{"api"},
+					},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		name        string
+		conf        *config.Config
+		licenseErr  error
+		expectErr   bool
+		errContains []string
+	}{
+		{
+			name: "enterprise with namespaces",
+			conf: nsConf,
+		},
+		{
+			name:       "oss without namespaces",
+			conf:       noNSConf,
+			licenseErr: &client.NonEnterpriseConsulError{Err: errors.New("404")},
+		},
+		{
+			name:       "oss with namespaces",
+			conf:       nsConf,
+			licenseErr: &client.NonEnterpriseConsulError{Err: errors.New("404")},
+			expectErr:  true,
+			errContains: []string{
+				`task "task_a": condition "services": namespace "ns1"`,
+				`task "task_a": module_input "consul-kv": namespace "ns2"`,
+			},
+		},
+		{
+			name: "oss with kv namespace",
+			conf: &config.Config{
+				Consul: &config.ConsulConfig{
+					KVNamespace: config.String("ns3"),
+				},
+				Tasks: noNSConf.Tasks,
+			},
+			licenseErr:  &client.NonEnterpriseConsulError{Err: errors.New("404")},
+			expectErr:   true,
+			errContains: []string{`consul: kv_namespace "ns3"`},
+		},
+		{
+			name:       "unknown edition",
+			conf:       nsConf,
+			licenseErr: errors.New("connection refused"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := new(mocksC.ConsulClientInterface)
+			c.On("GetLicense", mock.Anything, mock.Anything).Return("license", tc.licenseErr)
+
+			err := checkConsulCompatibility(context.Background(), tc.conf, c)
+			if !tc.expectErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Error(t, err)
+			for _, s := range tc.errContains {
+				assert.Contains(t, err.Error(), s)
+			}
+		})
+	}
+}
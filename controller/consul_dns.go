@@ -0,0 +1,124 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcat"
+)
+
+// consulDNSLookupTimeout is the timeout to resolve the host name of a Consul
+// address
+const consulDNSLookupTimeout = 5 * time.Second
+
+// refreshDNS re-resolves the host names of the Consul addresses. When the IPs
+// of an address change, e.g. when a load balancer in front of Consul fails
+// over, the Consul client of the address is recreated so that new queries
+// connect to the new IPs instead of reusing the kept-alive connections to the
+// previous IPs. Queries in progress complete on their current connection.
+func (c *failoverClients) refreshDNS() {
+	for i, addr := range c.addresses {
+		host := consulAddressHost(addr)
+		if host == "" {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), consulDNSLookupTimeout)
+		ips, err := c.lookupHost(ctx, host)
+		cancel()
+		if err != nil {
+			c.logger.Debug("unable to resolve consul address", "address", addr,
+				"error", err)
+			continue
+		}
+		sort.Strings(ips)
+
+		c.mu.RLock()
+		previous := c.resolved[i]
+		c.mu.RUnlock()
+		if previous == nil || equalStrings(previous, ips) {
+			c.mu.Lock()
+			c.resolved[i] = ips
+			c.mu.Unlock()
+			continue
+		}
+
+		input := c.input
+		input.Address = addr
+		cs, err := c.newConsulClients(input)
+		if err != nil {
+			c.logger.Error("unable to reconnect to consul address with changed "+
+				"IPs", "address", addr, "error", err)
+			continue
+		}
+
+		c.logger.Info("consul address resolved to new IPs, reconnecting",
+			"address", addr, "ips", ips, "previous_ips", previous)
+		c.mu.Lock()
+		old := c.dnsSets[i]
+		c.dnsSets[i] = cs
+		c.consul[i] = cs.Consul()
+		c.resolved[i] = ips
+		c.mu.Unlock()
+
+		// the idle connections of the original clients are closed once they
+		// time out, since the original clients are still used for Vault
+		if old != nil {
+			old.Stop()
+		}
+	}
+}
+
+// runDNSRefresh periodically re-resolves the Consul addresses until stopped
+func (c *failoverClients) runDNSRefresh(interval time.Duration) {
+	c.refreshDNS()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+			c.refreshDNS()
+		}
+	}
+}
+
+// newConsulClientSet returns hcat clients with a Consul client for the input
+func newConsulClientSet(input hcat.ConsulInput) (hcat.Looker, error) {
+	cs := hcat.NewClientSet()
+	if err := cs.AddConsul(input); err != nil {
+		return nil, err
+	}
+	return cs, nil
+}
+
+// consulAddressHost returns the host name of a Consul address. Returns an
+// empty string if the address is an IP or a unix socket, which do not need
+// to be resolved.
+func consulAddressHost(addr string) string {
+	if strings.HasPrefix(addr, "unix://") {
+		return ""
+	}
+	addr = strings.TrimPrefix(addr, "http://")
+	addr = strings.TrimPrefix(addr, "https://")
+	addr = strings.TrimSuffix(addr, "/")
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		// the address does not have a port
+		host = strings.Trim(addr, "[]")
+	}
+	if host == "" || net.ParseIP(host) != nil {
+		return ""
+	}
+	return host
+}
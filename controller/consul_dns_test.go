@@ -0,0 +1,142 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsulAddressHost(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		addr     string
+		expected string
+	}{
+		{"host and port", "consul.example.com:8500", "consul.example.com"},
+		{"host", "consul.example.com", "consul.example.com"},
+		{"http scheme", "http://consul.example.com:8500", "consul.example.com"},
+		{"https scheme", "https://consul.example.com:8501/", "consul.example.com"},
+		{"ipv4", "10.0.0.1:8500", ""},
+		{"ipv6", "[::1]:8500", ""},
+		{"ipv6 without port", "[::1]", ""},
+		{"unix socket", "unix:///var/run/consul.sock", ""},
+		{"empty", "", ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, consulAddressHost(tc.addr))
+		})
+	}
+}
+
+// testResolver resolves host names to the configured IPs
+type testResolver struct {
+	mu  sync.Mutex
+	ips map[string][]string
+	err error
+}
+
+func (r *testResolver) set(host string, ips ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ips[host] = ips
+}
+
+func (r *testResolver) lookupHost(_ context.Context, host string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.ips[host], nil
+}
+
+func TestFailoverClients_refreshDNS(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestFailoverClients(t, []string{"consul.example.com:8500", "10.0.0.2:8500"})
+	defer c.Stop()
+	r := &testResolver{ips: map[string][]string{
+		"consul.example.com": {"10.0.0.1", "10.0.0.3"},
+	}}
+	c.lookupHost = r.lookupHost
+	original := c.Consul()
+
+	// the first resolution is recorded without reconnecting
+	c.refreshDNS()
+	assert.Equal(t, original, c.Consul())
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.3"}, c.resolved[0])
+	assert.Nil(t, c.resolved[1], "IP addresses are not resolved")
+
+	// the same IPs in a different order do not reconnect
+	r.set("consul.example.com", "10.0.0.3", "10.0.0.1")
+	c.refreshDNS()
+	assert.Equal(t, original, c.Consul())
+
+	// resolution errors keep the current client
+	r.err = errors.New("no such host")
+	c.refreshDNS()
+	assert.Equal(t, original, c.Consul())
+	r.err = nil
+
+	// new IPs reconnect with a new client
+	r.set("consul.example.com", "10.0.0.4")
+	c.refreshDNS()
+	reconnected := c.Consul()
+	assert.NotEqual(t, original, reconnected)
+	assert.Equal(t, []string{"10.0.0.4"}, c.resolved[0])
+	require.Contains(t, c.dnsSets, 0)
+	assert.Equal(t, c.dnsSets[0].Consul(), reconnected)
+
+	// the reconnected client is replaced again on the next change
+	r.set("consul.example.com", "10.0.0.5")
+	c.refreshDNS()
+	assert.NotEqual(t, reconnected, c.Consul())
+	assert.Len(t, c.dnsSets, 1)
+}
+
+func TestFailoverClients_runDNSRefreshStop(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newTestFailoverClients(t, []string{"consul.example.com:8500"})
+	r := &testResolver{ips: map[string][]string{
+		"consul.example.com": {"10.0.0.1"},
+	}}
+	c.lookupHost = r.lookupHost
+	original := c.Consul()
+
+	done := make(chan struct{})
+	go func() {
+		c.runDNSRefresh(10 * time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		return c.resolved[0] != nil
+	}, time.Second, 10*time.Millisecond)
+
+	r.set("consul.example.com", "10.0.0.2")
+	assert.Eventually(t, func() bool {
+		return c.Consul() != original
+	}, time.Second, 10*time.Millisecond)
+
+	c.Stop()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		assert.Fail(t, "expected DNS refresh to stop")
+	}
+}
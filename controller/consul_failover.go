@@ -0,0 +1,191 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/hashicorp/hcat"
+)
+
+const (
+	consulFailoverSubsystemName = "consulfailover"
+
+	// consulHealthCheckInterval is the interval to check the health of the
+	// Consul addresses to fail over to a healthy address, or back to a more
+	// preferred address once it is healthy again
+	consulHealthCheckInterval = 10 * time.Second
+)
+
+// failoverClients wraps the hcat clients to fail over between Consul clients
+// for multiple addresses. hcat dependencies request the Consul client for
+// each query, so each query uses the client of the current address. The
+// current address is the first healthy address in order of preference.
+//
+// The Consul client of an address is also recreated when the IPs that the
+// host name of the address resolves to change, see refreshDNS.
+type failoverClients struct {
+	hcat.Looker
+	logger logging.Logger
+
+	mu        sync.RWMutex
+	addresses []string
+	sets      []hcat.Looker // Consul clients for addresses[1:]
+	consul    []*consulapi.Client
+	current   int
+
+	// input is used to recreate the Consul client of an address
+	input hcat.ConsulInput
+
+	// dnsSets are the Consul clients recreated for the addresses with
+	// changed resolved IPs, by the index of the address
+	dnsSets map[int]hcat.Looker
+
+	// resolved are the IPs that the host name of each address last resolved
+	// to, which is nil if the address has not been resolved
+	resolved [][]string
+
+	// lookupHost resolves a host name to its IPs
+	lookupHost func(ctx context.Context, host string) ([]string, error)
+
+	// newConsulClients creates the Consul clients for an address
+	newConsulClients func(input hcat.ConsulInput) (hcat.Looker, error)
+
+	// checkFunc checks the health of the Consul client
+	checkFunc func(*consulapi.Client) error
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// newFailoverClients returns clients that fail over between the Consul
+// addresses. The clients already include the Consul client for the first
+// address, and Consul clients for the remaining addresses are created from
+// the input.
+func newFailoverClients(clients hcat.Looker, addresses []string,
+	input hcat.ConsulInput) (*failoverClients, error) {
+
+	sets := make([]hcat.Looker, 0, len(addresses)-1)
+	consul := []*consulapi.Client{clients.Consul()}
+	for _, addr := range addresses[1:] {
+		input.Address = addr
+		cs := hcat.NewClientSet()
+		if err := cs.AddConsul(input); err != nil {
+			for _, s := range sets {
+				s.Stop()
+			}
+			return nil, err
+		}
+		sets = append(sets, cs)
+		consul = append(consul, cs.Consul())
+	}
+
+	return &failoverClients{
+		Looker:           clients,
+		logger:           logging.Global().Named(consulFailoverSubsystemName),
+		addresses:        addresses,
+		sets:             sets,
+		consul:           consul,
+		input:            input,
+		dnsSets:          make(map[int]hcat.Looker),
+		resolved:         make([][]string, len(addresses)),
+		lookupHost:       net.DefaultResolver.LookupHost,
+		newConsulClients: newConsulClientSet,
+		checkFunc:        checkConsulHealth,
+		stopCh:           make(chan struct{}),
+	}, nil
+}
+
+// Consul returns the Consul client of the current address
+func (c *failoverClients) Consul() *consulapi.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.consul[c.current]
+}
+
+// checkHealth switches to the first healthy address in order of preference.
+// The current address is kept if no address is healthy.
+func (c *failoverClients) checkHealth() {
+	// check the health without holding the lock to not block queries
+	c.mu.RLock()
+	consul := make([]*consulapi.Client, len(c.consul))
+	copy(consul, c.consul)
+	c.mu.RUnlock()
+
+	healthy := -1
+	for i, client := range consul {
+		if err := c.checkFunc(client); err != nil {
+			c.logger.Debug("consul address is unhealthy", "address",
+				c.addresses[i], "error", err)
+			continue
+		}
+		healthy = i
+		break
+	}
+
+	if healthy < 0 {
+		c.logger.Error("no healthy consul address to fail over to",
+			"addresses", c.addresses)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if healthy != c.current {
+		c.logger.Warn("failing over to consul address", "address",
+			c.addresses[healthy], "previous_address", c.addresses[c.current])
+		c.current = healthy
+	}
+}
+
+// run periodically checks the health of the addresses until stopped
+func (c *failoverClients) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+			c.checkHealth()
+		}
+	}
+}
+
+// Stop stops checking the health of the addresses and closes the idle
+// connections of all clients
+func (c *failoverClients) Stop() {
+	c.stopOnce.Do(func() {
+		close(c.stopCh)
+	})
+	for _, s := range c.sets {
+		s.Stop()
+	}
+	c.mu.Lock()
+	for _, s := range c.dnsSets {
+		s.Stop()
+	}
+	c.mu.Unlock()
+	c.Looker.Stop()
+}
+
+// checkConsulHealth checks that the Consul agent is reachable and that the
+// Consul cluster has a leader
+func checkConsulHealth(client *consulapi.Client) error {
+	leader, err := client.Status().Leader()
+	if err != nil {
+		return err
+	}
+	if leader == "" {
+		return errors.New("consul cluster has no leader")
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/hashicorp/hcat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFailoverClients(t *testing.T, addresses []string) (*failoverClients, map[*consulapi.Client]bool) {
+	consul := make([]*consulapi.Client, len(addresses))
+	healthy := make(map[*consulapi.Client]bool, len(addresses))
+	for i, addr := range addresses {
+		client, err := consulapi.NewClient(&consulapi.Config{Address: addr})
+		require.NoError(t, err)
+		consul[i] = client
+	}
+
+	c := &failoverClients{
+		Looker:    hcat.NewClientSet(),
+		logger:    logging.NewNullLogger(),
+		addresses: addresses,
+		consul:    consul,
+		dnsSets:   make(map[int]hcat.Looker),
+		resolved:  make([][]string, len(addresses)),
+		newConsulClients: func(input hcat.ConsulInput) (hcat.Looker, error) {
+			client, err := consulapi.NewClient(&consulapi.Config{Address: input.Address})
+			if err != nil {
+				return nil, err
+			}
+			return &testConsulClients{ClientSet: hcat.NewClientSet(), consul: client}, nil
+		},
+		checkFunc: func(client *consulapi.Client) error {
+			if !healthy[client] {
+				return errors.New("unhealthy")
+			}
+			return nil
+		},
+		stopCh: make(chan struct{}),
+	}
+	return c, healthy
+}
+
+// testConsulClients are hcat clients with a Consul client that is created
+// without connecting to Consul
+type testConsulClients struct {
+	*hcat.ClientSet
+	consul *consulapi.Client
+}
+
+func (c *testConsulClients) Consul() *consulapi.Client {
+	return c.consul
+}
+
+func TestFailoverClients_checkHealth(t *testing.T) {
+	t.Parallel()
+
+	c, healthy := newTestFailoverClients(t, []string{"c1:8500", "c2:8500", "c3:8500"})
+	c1, c2, c3 := c.consul[0], c.consul[1], c.consul[2]
+	assert.Equal(t, c1, c.Consul())
+
+	// fail over to the next healthy address
+	healthy[c3] = true
+	c.checkHealth()
+	assert.Equal(t, c3, c.Consul())
+
+	healthy[c2] = true
+	c.checkHealth()
+	assert.Equal(t, c2, c.Consul())
+
+	// keep the current address if no address is healthy
+	healthy[c2], healthy[c3] = false, false
+	c.checkHealth()
+	assert.Equal(t, c2, c.Consul())
+
+	// fail back to the preferred address once healthy
+	healthy[c1] = true
+	c.checkHealth()
+	assert.Equal(t, c1, c.Consul())
+}
+
+func TestFailoverClients_runStop(t *testing.T) {
+	t.Parallel()
+
+	c, healthy := newTestFailoverClients(t, []string{"c1:8500", "c2:8500"})
+	healthy[c.consul[1]] = true
+
+	done := make(chan struct{})
+	go func() {
+		c.run(10 * time.Millisecond)
+		close(done)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return c.Consul() == c.consul[1]
+	}, time.Second, 10*time.Millisecond)
+
+	c.Stop()
+	c.Stop() // stopping more than once is safe
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		assert.Fail(t, "expected run to stop")
+	}
+}
+
+func TestNewFailoverClients(t *testing.T) {
+	t.Parallel()
+
+	// the client sets wait for a Consul leader
+	var addrs []string
+	for i := 0; i < 2; i++ {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `"127.0.0.1:8300"`)
+		}))
+		defer srv.Close()
+		addrs = append(addrs, srv.Listener.Addr().String())
+	}
+
+	input := hcat.ConsulInput{Address: addrs[0]}
+	clients := hcat.NewClientSet()
+	require.NoError(t, clients.AddConsul(input))
+
+	c, err := newFailoverClients(clients, addrs, input)
+	require.NoError(t, err)
+	defer c.Stop()
+
+	assert.Len(t, c.consul, 2)
+	assert.Len(t, c.sets, 1)
+	assert.Equal(t, clients.Consul(), c.Consul())
+	assert.NotEqual(t, c.consul[0], c.consul[1])
+}
@@ -0,0 +1,220 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	consulapi "github.com/hashicorp/consul/api"
+)
+
+// consulResources looks up the datacenters and namespaces of the connected
+// Consul that tasks reference
+type consulResources interface {
+	// Datacenters returns the names of the known datacenters
+	Datacenters(ctx context.Context) ([]string, error)
+
+	// Namespaces returns the names of the namespaces of the datacenter. An
+	// empty datacenter is the datacenter of the Consul agent.
+	Namespaces(ctx context.Context, datacenter string) ([]string, error)
+}
+
+// consulAPIResources looks up the resources with the Consul API client
+type consulAPIResources struct {
+	client *consulapi.Client
+}
+
+func (r *consulAPIResources) Datacenters(_ context.Context) ([]string, error) {
+	return r.client.Catalog().Datacenters()
+}
+
+func (r *consulAPIResources) Namespaces(ctx context.Context, datacenter string) ([]string, error) {
+	q := (&consulapi.QueryOptions{Datacenter: datacenter}).WithContext(ctx)
+	namespaces, _, err := r.client.Namespaces().List(q)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(namespaces))
+	for i, ns := range namespaces {
+		names[i] = ns.Name
+	}
+	return names, nil
+}
+
+// consulReference is a Consul datacenter and namespace referenced by a block
+// of a task's configuration
+type consulReference struct {
+	block      string
+	datacenter string
+	namespace  string
+}
+
+// taskConsulReferences returns the datacenters and namespaces referenced by
+// the condition, module_input, and gate blocks of the task. Blocks that do
+// not configure either are not included.
+func taskConsulReferences(tc config.TaskConfig) []consulReference {
+	var refs []consulReference
+	addMonitor := func(block string, m config.MonitorConfig) {
+		dc, ns := monitorDatacenter(m), monitorNamespace(m)
+		if dc != "" || ns != "" {
+			refs = append(refs, consulReference{
+				block:      fmt.Sprintf("%s %q", block, monitorBlockLabel(m)),
+				datacenter: dc,
+				namespace:  ns,
+			})
+		}
+	}
+
+	if tc.Condition != nil {
+		addMonitor("condition", tc.Condition)
+	}
+
+	if tc.ModuleInputs != nil {
+		for _, mi := range *tc.ModuleInputs {
+			addMonitor("module_input", mi)
+		}
+	}
+
+	if tc.Gates != nil {
+		for _, g := range *tc.Gates {
+			if g == nil || g.ConsulKV == nil {
+				continue
+			}
+			dc := config.StringVal(g.ConsulKV.Datacenter)
+			ns := config.StringVal(g.ConsulKV.Namespace)
+			if dc != "" || ns != "" {
+				refs = append(refs, consulReference{
+					block: fmt.Sprintf("gate \"consul-kv\" %q",
+						config.StringVal(g.ConsulKV.Path)),
+					datacenter: dc,
+					namespace:  ns,
+				})
+			}
+		}
+	}
+
+	return refs
+}
+
+// monitorDatacenter returns the datacenter configured for a condition or
+// module_input block. Returns an empty string if not configured.
+func monitorDatacenter(m config.MonitorConfig) string {
+	switch v := m.(type) {
+	case *config.ServicesConditionConfig:
+		if v != nil {
+			return config.StringVal(v.Datacenter)
+		}
+	case *config.ServicesModuleInputConfig:
+		if v != nil {
+			return config.StringVal(v.Datacenter)
+		}
+	case *config.CatalogServicesConditionConfig:
+		if v != nil {
+			return config.StringVal(v.Datacenter)
+		}
+	case *config.ConsulKVConditionConfig:
+		if v != nil {
+			return config.StringVal(v.Datacenter)
+		}
+	case *config.ConsulKVModuleInputConfig:
+		if v != nil {
+			return config.StringVal(v.Datacenter)
+		}
+	}
+	return ""
+}
+
+// checkConsulReferences checks that the datacenters and namespaces referenced
+// by a task exist in the connected Consul. A task that references a resource
+// that does not exist would otherwise never trigger, or fail later when its
+// dependencies are queried. Lookups that fail, e.g. when Consul is not
+// reachable, are logged and do not block the task.
+func (tm *TasksManager) checkConsulReferences(ctx context.Context, tc config.TaskConfig) error {
+	if tm.consulResources == nil {
+		return nil
+	}
+	refs := taskConsulReferences(tc)
+	if len(refs) == 0 {
+		return nil
+	}
+
+	taskName := config.StringVal(tc.Name)
+	logger := tm.logger.With(taskNameLogKey, taskName)
+	resources := tm.consulResources()
+
+	var invalid []string
+	knownDCs := make(map[string]bool)
+	if dcs, err := resources.Datacenters(ctx); err != nil {
+		logger.Warn("unable to look up Consul datacenters, skipping "+
+			"datacenter validation", "error", err)
+		knownDCs = nil
+	} else {
+		for _, dc := range dcs {
+			knownDCs[dc] = true
+		}
+	}
+
+	// namespaces are looked up once per datacenter. The namespaces of a
+	// datacenter are nil if they are unable to be looked up.
+	knownNamespaces := make(map[string]map[string]bool)
+	nsUnsupported := false
+	for _, ref := range refs {
+		if ref.datacenter != "" && knownDCs != nil && !knownDCs[ref.datacenter] {
+			invalid = append(invalid, fmt.Sprintf("%s: datacenter %q does not exist",
+				ref.block, ref.datacenter))
+			continue
+		}
+		if ref.namespace == "" {
+			continue
+		}
+
+		namespaces, ok := knownNamespaces[ref.datacenter]
+		if !ok && !nsUnsupported {
+			names, err := resources.Namespaces(ctx, ref.datacenter)
+			switch {
+			case err != nil && isConsulNotFoundError(err):
+				// the namespaces endpoint is not available for Consul OSS
+				nsUnsupported = true
+			case err != nil:
+				logger.Warn("unable to look up Consul namespaces, skipping "+
+					"namespace validation", "datacenter", ref.datacenter,
+					"error", err)
+			default:
+				namespaces = make(map[string]bool, len(names))
+				for _, name := range names {
+					namespaces[name] = true
+				}
+			}
+			knownNamespaces[ref.datacenter] = namespaces
+		}
+
+		if nsUnsupported {
+			invalid = append(invalid, fmt.Sprintf("%s: namespace %q is not "+
+				"supported, namespaces are a Consul Enterprise feature",
+				ref.block, ref.namespace))
+			continue
+		}
+		if namespaces != nil && !namespaces[ref.namespace] {
+			invalid = append(invalid, fmt.Sprintf("%s: namespace %q does not exist",
+				ref.block, ref.namespace))
+		}
+	}
+
+	if len(invalid) == 0 {
+		return nil
+	}
+	sort.Strings(invalid)
+	return &config.ConsulReferenceError{Task: taskName, References: invalid}
+}
+
+// isConsulNotFoundError returns whether the error from the Consul API client
+// is for a 404 response
+func isConsulNotFoundError(err error) bool {
+	return strings.Contains(err.Error(), "Unexpected response code: 404")
+}
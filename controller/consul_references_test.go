@@ -0,0 +1,173 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testConsulResources is a lookup of Consul resources for tests that records
+// the datacenters that namespaces are looked up for
+type testConsulResources struct {
+	datacenters []string
+	dcErr       error
+	namespaces  map[string][]string // datacenter => namespaces
+	nsErr       error
+	nsLookups   []string
+}
+
+func (r *testConsulResources) Datacenters(context.Context) ([]string, error) {
+	return r.datacenters, r.dcErr
+}
+
+func (r *testConsulResources) Namespaces(_ context.Context, dc string) ([]string, error) {
+	r.nsLookups = append(r.nsLookups, dc)
+	return r.namespaces[dc], r.nsErr
+}
+
+func consulReferencesTestTask(dc, ns string) config.TaskConfig {
+	return config.TaskConfig{
+		Name:   config.String("task"),
+		Module: config.String("module"),
+		Condition: &config.ServicesConditionConfig{
+			ServicesMonitorConfig: config.ServicesMonitorConfig{
+				Names:      []string{"api"},
+				Datacenter: config.String(dc),
+				Namespace:  config.String(ns),
+			},
+		},
+		ModuleInputs: &config.ModuleInputConfigs{
+			&config.ConsulKVModuleInputConfig{
+				ConsulKVMonitorConfig: config.ConsulKVMonitorConfig{
+					Path:      config.String("key"),
+					Namespace: config.String(ns),
+				},
+			},
+		},
+		Gates: &config.GateConfigs{
+			{ConsulKV: &config.ConsulKVGateConfig{
+				Path:       config.String("flags/freeze"),
+				Datacenter: config.String(dc),
+			}},
+		},
+	}
+}
+
+func Test_taskConsulReferences(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no references", func(t *testing.T) {
+		assert.Empty(t, taskConsulReferences(consulReferencesTestTask("", "")))
+	})
+
+	t.Run("references", func(t *testing.T) {
+		refs := taskConsulReferences(consulReferencesTestTask("dc2", "ns1"))
+		assert.Equal(t, []consulReference{
+			{block: `condition "services"`, datacenter: "dc2", namespace: "ns1"},
+			{block: `module_input "consul-kv"`, namespace: "ns1"},
+			{block: `gate "consul-kv" "flags/freeze"`, datacenter: "dc2"},
+		}, refs)
+	})
+}
+
+func Test_TasksManager_checkConsulReferences(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	newTM := func(r *testConsulResources) *TasksManager {
+		tm := newTestTasksManager()
+		tm.consulResources = func() consulResources { return r }
+		return tm
+	}
+
+	t.Run("not configured", func(t *testing.T) {
+		tm := newTestTasksManager()
+		assert.NoError(t, tm.checkConsulReferences(ctx, consulReferencesTestTask("dc3", "ns3")))
+	})
+
+	t.Run("no references", func(t *testing.T) {
+		r := &testConsulResources{dcErr: errors.New("unexpected lookup")}
+		tm := newTM(r)
+		assert.NoError(t, tm.checkConsulReferences(ctx, consulReferencesTestTask("", "")))
+		assert.Empty(t, r.nsLookups)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		r := &testConsulResources{
+			datacenters: []string{"dc1", "dc2"},
+			namespaces: map[string][]string{
+				"dc2": {"default", "ns1"},
+				"":    {"default", "ns1"},
+			},
+		}
+		tm := newTM(r)
+		assert.NoError(t, tm.checkConsulReferences(ctx, consulReferencesTestTask("dc2", "ns1")))
+		assert.Equal(t, []string{"dc2", ""}, r.nsLookups,
+			"namespaces should be looked up once per datacenter")
+	})
+
+	t.Run("does not exist", func(t *testing.T) {
+		r := &testConsulResources{
+			datacenters: []string{"dc1"},
+			namespaces:  map[string][]string{"": {"default"}},
+		}
+		tm := newTM(r)
+		err := tm.checkConsulReferences(ctx, consulReferencesTestTask("dc3", "ns3"))
+
+		var refErr *config.ConsulReferenceError
+		require.ErrorAs(t, err, &refErr)
+		assert.Equal(t, "task", refErr.Task)
+		assert.Equal(t, []string{
+			`condition "services": datacenter "dc3" does not exist`,
+			`gate "consul-kv" "flags/freeze": datacenter "dc3" does not exist`,
+			`module_input "consul-kv": namespace "ns3" does not exist`,
+		}, refErr.References)
+	})
+
+	t.Run("namespaces not supported", func(t *testing.T) {
+		r := &testConsulResources{
+			datacenters: []string{"dc1"},
+			nsErr:       errors.New("Unexpected response code: 404 ()"),
+		}
+		tm := newTM(r)
+		err := tm.checkConsulReferences(ctx, consulReferencesTestTask("", "ns1"))
+
+		var refErr *config.ConsulReferenceError
+		require.ErrorAs(t, err, &refErr)
+		assert.Equal(t, []string{
+			`condition "services": namespace "ns1" is not supported, namespaces ` +
+				`are a Consul Enterprise feature`,
+			`module_input "consul-kv": namespace "ns1" is not supported, ` +
+				`namespaces are a Consul Enterprise feature`,
+		}, refErr.References)
+		assert.Len(t, r.nsLookups, 1)
+	})
+
+	t.Run("lookup errors", func(t *testing.T) {
+		r := &testConsulResources{
+			dcErr: errors.New("connection refused"),
+			nsErr: errors.New("connection refused"),
+		}
+		tm := newTM(r)
+		assert.NoError(t, tm.checkConsulReferences(ctx, consulReferencesTestTask("dc3", "ns3")),
+			"task should not be blocked when Consul is unable to be queried")
+	})
+
+	t.Run("task create", func(t *testing.T) {
+		r := &testConsulResources{datacenters: []string{"dc1"}}
+		tm := newTM(r)
+		_, err := tm.TaskCreate(ctx, consulReferencesTestTask("dc3", ""))
+
+		var refErr *config.ConsulReferenceError
+		assert.ErrorAs(t, err, &refErr)
+		_, ok := tm.drivers.Get("task")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"sync"
+	"time"
+)
+
+// taskCooldowns tracks when tasks last ran and the deferred runs for tasks
+// with triggers that were suppressed during their cooldown.
+type taskCooldowns struct {
+	mu *sync.Mutex
+
+	lastRun map[string]time.Time   // taskname => time of last run
+	pending map[string]*time.Timer // taskname => deferred run
+}
+
+// newTaskCooldowns returns a new tracker for task cooldowns
+func newTaskCooldowns() *taskCooldowns {
+	return &taskCooldowns{
+		mu:      &sync.Mutex{},
+		lastRun: make(map[string]time.Time),
+		pending: make(map[string]*time.Timer),
+	}
+}
+
+// Remaining returns the time remaining in the cooldown for a task. Returns 0
+// if the task is not within its cooldown.
+func (c *taskCooldowns) Remaining(taskName string, cooldown time.Duration) time.Duration {
+	if cooldown <= 0 {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	last, ok := c.lastRun[taskName]
+	if !ok {
+		return 0
+	}
+
+	remaining := cooldown - time.Since(last)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// SetRan records that a task ran at the current time
+func (c *taskCooldowns) SetRan(taskName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastRun[taskName] = time.Now()
+}
+
+// Defer schedules f to be called for a task after the wait duration. Only one
+// run can be deferred per task at a time. Returns false if a run is already
+// deferred for the task, in which case f is not scheduled.
+func (c *taskCooldowns) Defer(taskName string, wait time.Duration, f func()) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.pending[taskName]; ok {
+		return false
+	}
+
+	c.pending[taskName] = time.AfterFunc(wait, func() {
+		c.mu.Lock()
+		delete(c.pending, taskName)
+		c.mu.Unlock()
+
+		f()
+	})
+	return true
+}
+
+// Deferred returns whether a run is deferred for a task
+func (c *taskCooldowns) Deferred(taskName string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.pending[taskName]
+	return ok
+}
+
+// Delete stops any deferred run and removes all cooldown information for a
+// task
+func (c *taskCooldowns) Delete(taskName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if t, ok := c.pending[taskName]; ok {
+		t.Stop()
+		delete(c.pending, taskName)
+	}
+	delete(c.lastRun, taskName)
+}
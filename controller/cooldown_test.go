@@ -0,0 +1,87 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_taskCooldowns_Remaining(t *testing.T) {
+	t.Parallel()
+
+	t.Run("never ran", func(t *testing.T) {
+		c := newTaskCooldowns()
+		assert.Equal(t, time.Duration(0), c.Remaining("task", time.Minute))
+	})
+
+	t.Run("cooldown disabled", func(t *testing.T) {
+		c := newTaskCooldowns()
+		c.SetRan("task")
+		assert.Equal(t, time.Duration(0), c.Remaining("task", 0))
+	})
+
+	t.Run("within cooldown", func(t *testing.T) {
+		c := newTaskCooldowns()
+		c.SetRan("task")
+		remaining := c.Remaining("task", time.Minute)
+		assert.Greater(t, remaining, time.Duration(0))
+		assert.LessOrEqual(t, remaining, time.Minute)
+	})
+
+	t.Run("cooldown ended", func(t *testing.T) {
+		c := newTaskCooldowns()
+		c.lastRun["task"] = time.Now().Add(-2 * time.Minute)
+		assert.Equal(t, time.Duration(0), c.Remaining("task", time.Minute))
+	})
+}
+
+func Test_taskCooldowns_Defer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("deduplicates deferred runs", func(t *testing.T) {
+		c := newTaskCooldowns()
+		ranCh := make(chan struct{}, 2)
+		f := func() { ranCh <- struct{}{} }
+
+		assert.False(t, c.Deferred("task"))
+		assert.True(t, c.Defer("task", 10*time.Millisecond, f))
+		assert.False(t, c.Defer("task", 10*time.Millisecond, f))
+		assert.True(t, c.Deferred("task"))
+
+		select {
+		case <-ranCh:
+		case <-time.After(time.Second):
+			t.Fatal("deferred run did not occur")
+		}
+
+		// Able to defer again after the pending run has occurred
+		assert.True(t, c.Defer("task", 10*time.Millisecond, f))
+		select {
+		case <-ranCh:
+		case <-time.After(time.Second):
+			t.Fatal("deferred run did not occur")
+		}
+	})
+
+	t.Run("delete stops deferred run", func(t *testing.T) {
+		c := newTaskCooldowns()
+		ranCh := make(chan struct{}, 1)
+		c.SetRan("task")
+
+		assert.True(t, c.Defer("task", 50*time.Millisecond, func() {
+			ranCh <- struct{}{}
+		}))
+		c.Delete("task")
+
+		select {
+		case <-ranCh:
+			t.Fatal("deferred run should have been stopped")
+		case <-time.After(100 * time.Millisecond):
+		}
+		assert.Equal(t, time.Duration(0), c.Remaining("task", time.Minute))
+	})
+}
@@ -5,6 +5,8 @@ package controller
 
 import (
 	"context"
+	"math/rand"
+	"time"
 
 	"github.com/hashicorp/consul-terraform-sync/api"
 	"github.com/hashicorp/consul-terraform-sync/client"
@@ -12,6 +14,7 @@ import (
 	"github.com/hashicorp/consul-terraform-sync/health"
 	"github.com/hashicorp/consul-terraform-sync/logging"
 	"github.com/hashicorp/consul-terraform-sync/registration"
+	"github.com/hashicorp/consul-terraform-sync/retry"
 	"github.com/hashicorp/consul-terraform-sync/state"
 	"github.com/hashicorp/consul-terraform-sync/templates"
 )
@@ -23,6 +26,10 @@ var (
 	defaultRetry = 2
 )
 
+// supervisorMaxWaitTime is the maximum wait time between attempts to run the
+// tasks once when the daemon runs with supervisor semantics
+const supervisorMaxWaitTime = time.Minute
+
 // Daemon is the controller to run CTS as a daemon. It executes the tasks once
 // (once-mode) and then runs the task in long-running mode. It also starts
 // daemon-only features such as the API server
@@ -38,17 +45,30 @@ type Daemon struct {
 
 	// indicates whether the tasks have gone through once-mode or not
 	once bool
+
+	// changedOnly skips running tasks in once-mode that have not changed
+	// since their last successful run
+	changedOnly bool
+
+	// health reports whether the daemon is healthy or degraded when the
+	// daemon runs with supervisor semantics. Nil otherwise.
+	health *health.StatusChecker
 }
 
 // NewDaemon configures and initializes a new Daemon controller
 func NewDaemon(conf *config.Config) (*Daemon, error) {
+	return NewDaemonWithStore(conf, state.NewInMemoryStore(conf))
+}
+
+// NewDaemonWithStore configures and initializes a new Daemon controller that
+// uses the provided state store. The store is expected to already contain the
+// configuration.
+func NewDaemonWithStore(conf *config.Config, s state.Store) (*Daemon, error) {
 	logger := logging.Global().Named(ctrlSystemName)
 	logger.Info("setting up controller", "type", "daemon")
 
-	s := state.NewInMemoryStore(conf)
-
 	logger.Info("initializing Consul client and testing connection")
-	watcher, err := newWatcher(conf, client.ConsulDefaultMaxRetry)
+	watcher, err := newWatcherSet(conf, client.ConsulDefaultMaxRetry)
 	if err != nil {
 		return nil, err
 	}
@@ -67,6 +87,28 @@ func NewDaemon(conf *config.Config) (*Daemon, error) {
 	}, nil
 }
 
+// SetChangedOnly configures the controller to only run tasks that changed
+// since their last successful run when the tasks are run once at startup.
+// This avoids re-applying unchanged tasks when CTS restarts. Tasks are
+// compared to the state saved in their working directory by the last
+// successful run.
+func (ctrl *Daemon) SetChangedOnly(changedOnly bool) {
+	ctrl.changedOnly = changedOnly
+}
+
+// SetHealth configures the controller to run with supervisor semantics and
+// report its health with the checker through the health API. Instead of
+// stopping, the daemon keeps running while it is unable to reach Consul and
+// reports that it is degraded: running the tasks once at startup is retried
+// with an exponential backoff, and errors monitoring Consul are reported until
+// the monitor receives data again.
+func (ctrl *Daemon) SetHealth(h *health.StatusChecker) {
+	ctrl.health = h
+	if ctrl.monitor != nil {
+		ctrl.monitor.health = h
+	}
+}
+
 // Init initializes the controller before it can be run. Ensures that
 // driver is initializes, works are created for each task.
 func (ctrl *Daemon) Init(ctx context.Context) error {
@@ -79,11 +121,32 @@ func (ctrl *Daemon) Run(ctx context.Context) error {
 
 	// Configure API
 	conf := ctrl.tasksManager.state.GetConfig()
+	var readOnly *api.ReadOnlyConfig
+	if conf.ReadOnlyAPI.Enabled() {
+		readOnly = &api.ReadOnlyConfig{
+			Addresses: conf.ReadOnlyAPI.Addresses,
+			TLS:       conf.ReadOnlyAPI.TLS,
+		}
+	}
+	var tfcRunTaskHMACKey string
+	if conf.TFCRunTask != nil && config.BoolVal(conf.TFCRunTask.Enabled) {
+		tfcRunTaskHMACKey = config.StringVal(conf.TFCRunTask.HMACKey)
+	}
+	var checker health.Checker = &health.BasicChecker{}
+	if ctrl.health != nil {
+		checker = ctrl.health
+	}
 	s, err := api.NewAPI(ctx, api.Config{
 		Controller: ctrl.tasksManager,
-		Health:     &health.BasicChecker{},
+		Health:     checker,
 		Port:       config.IntVal(conf.Port),
+		Addresses:  conf.Addresses,
 		TLS:        conf.TLS,
+
+		IdempotencyKeyTTL: config.TimeDurationVal(conf.IdempotencyKeyTTL),
+		ReadOnly:          readOnly,
+		TFCRunTaskHMACKey: tfcRunTaskHMACKey,
+		Proxy:             conf.Proxy,
 	})
 	if err != nil {
 		return err
@@ -127,13 +190,45 @@ func (ctrl *Daemon) Run(ctx context.Context) error {
 		}()
 	}
 
+	var sharder *taskSharder
+	if conf.Sharding.IsEnabled() && !ctrl.once {
+		// Expect one more long-running goroutine
+		exitBufLen++
+		exitCh = make(chan error, exitBufLen)
+
+		c, err := client.NewConsulClient(conf.Consul, client.ConsulDefaultMaxRetry)
+		if err != nil {
+			ctrl.logger.Error("error setting up Consul client for sharding", "error", err)
+			return err
+		}
+
+		// Only keep the tasks assigned to this instance before running once
+		sharder = newTaskSharder(&conf, ctrl.state, ctrl.tasksManager,
+			newConsulShardMembership(c.Client, config.StringVal(conf.ID), conf.Sharding))
+		if err := sharder.start(ctx); err != nil {
+			ctrl.logger.Error("error starting task sharding", "error", err)
+			return err
+		}
+	}
+
 	// Run tasks once through once-mode
 	if !ctrl.once {
-		if err := ctrl.Once(ctx); err != nil {
+		random := rand.New(rand.NewSource(time.Now().UnixNano()))
+		err := ctrl.superviseOnce(ctx, ctrl.Once, func(attempt int) time.Duration {
+			return retry.WaitTime(attempt, random, supervisorMaxWaitTime)
+		})
+		if err != nil {
 			return err
 		}
 	}
 
+	// Rebalance tasks when the shard membership changes
+	if sharder != nil {
+		go func() {
+			exitCh <- sharder.run(ctx)
+		}()
+	}
+
 	// Run long-running mode and monitor existing
 	// and created tasks
 	go func() {
@@ -159,23 +254,131 @@ func (ctrl *Daemon) Run(ctx context.Context) error {
 
 // Once runs the tasks once. Intended to only be called by Run()
 func (ctrl *Daemon) Once(ctx context.Context) error {
+	conf := ctrl.state.GetConfig()
 	once := Once{
-		logger:       ctrl.logger,
-		state:        ctrl.state,
-		tasksManager: ctrl.tasksManager,
-		monitor:      ctrl.monitor,
+		logger:        ctrl.logger,
+		state:         ctrl.state,
+		tasksManager:  ctrl.tasksManager,
+		monitor:       ctrl.monitor,
+		failurePolicy: config.StringVal(conf.OnceModeFailurePolicy),
 	}
 
+	// Only skip unchanged tasks in once-mode. Tasks created afterwards, e.g.
+	// through the API, always run.
+	ctrl.tasksManager.setChangedOnly(ctrl.changedOnly)
+	defer ctrl.tasksManager.setChangedOnly(false)
+
 	// no need to init or stop Once controller since it shares tasksManager
-	// with Daemon controller
-	if err := once.Run(ctx); err != nil {
+	// with Daemon controller. Tasks that errored when the failure policy
+	// allows tasks to fail do not stop the daemon.
+	if err := once.run(ctx); err != nil {
 		return err
 	}
 
+	if once.failurePolicy == config.OnceModeFailurePolicyBestEffort &&
+		len(once.failed) > 0 {
+		random := rand.New(rand.NewSource(time.Now().UnixNano()))
+		go retryOnceFailures(ctx, ctrl.tasksManager, once.failed,
+			func(attempt int) time.Duration {
+				return retry.WaitTime(attempt, random, retry.DefaultMaxWaitTime)
+			})
+	}
+
 	ctrl.once = true
 	return nil
 }
 
+// superviseOnce runs the tasks once. With supervisor semantics, errors are
+// retried with an exponential backoff and the daemon reports that it is
+// degraded until the tasks run successfully. Otherwise errors are returned.
+func (ctrl *Daemon) superviseOnce(ctx context.Context,
+	once func(context.Context) error, waitTime func(attempt int) time.Duration) error {
+
+	if ctrl.health == nil {
+		return once(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := once(ctx)
+		if err == nil {
+			ctrl.health.SetHealthy()
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		wait := waitTime(attempt)
+		ctrl.logger.Warn("error running tasks once, reporting degraded and retrying",
+			"attempt", attempt, "retry_in", wait, "error", err)
+		ctrl.health.SetDegraded(err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// retryOnceFailures retries the tasks that errored in once-mode in the
+// background until they succeed. Tasks that errored while running are run
+// again, and tasks that errored while being created are created and run
+// again. Tasks are retried in the order that they were run in once-mode, with
+// exponential backoff between attempts.
+func retryOnceFailures(ctx context.Context, tm *TasksManager,
+	failed []onceFailure, waitTime func(attempt int) time.Duration) {
+
+	logger := tm.logger.With("once_mode_failure_policy",
+		config.OnceModeFailurePolicyBestEffort)
+	logger.Info("retrying tasks that errored in once-mode in the background",
+		"failed_tasks", len(failed))
+
+	for attempt := 1; len(failed) > 0; attempt++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(waitTime(attempt)):
+		}
+
+		var remaining []onceFailure
+		for _, f := range failed {
+			taskName := config.StringVal(f.task.Name)
+			taskLogger := logger.With(taskNameLogKey, taskName, "attempt", attempt)
+
+			var err error
+			if f.added {
+				if _, ok := tm.drivers.Get(taskName); !ok ||
+					tm.drivers.IsMarkedForDeletion(taskName) {
+					taskLogger.Debug("task was deleted, stopping retry")
+					continue
+				}
+				err = tm.runTask(ctx, taskName, true)
+			} else {
+				if _, ok := tm.drivers.Get(taskName); ok {
+					taskLogger.Debug("task was created, stopping retry")
+					continue
+				}
+				_, err = tm.TaskCreateAndRun(ctx, f.task)
+			}
+
+			if err != nil {
+				taskLogger.Warn("retry of task failed", "error", err)
+				remaining = append(remaining, f)
+				continue
+			}
+			taskLogger.Info("retry of task succeeded")
+		}
+		failed = remaining
+	}
+}
+
+// TasksManager returns the tasks manager used by the controller to manage
+// the lifecycle of tasks
+func (ctrl *Daemon) TasksManager() *TasksManager {
+	return ctrl.tasksManager
+}
+
 func (ctrl *Daemon) Stop() {
 	ctrl.watcher.Stop()
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/hashicorp/consul-terraform-sync/config"
 	"github.com/hashicorp/consul-terraform-sync/driver"
+	"github.com/hashicorp/consul-terraform-sync/health"
 	"github.com/hashicorp/consul-terraform-sync/logging"
 	mocksC "github.com/hashicorp/consul-terraform-sync/mocks/client"
 	mocksD "github.com/hashicorp/consul-terraform-sync/mocks/driver"
@@ -218,3 +219,160 @@ func testOnceThenLong(t *testing.T, driverConf *config.DriverConfig) {
 		d.(*mocksD.Driver).AssertExpectations(t)
 	}
 }
+
+func Test_Daemon_Once_ChangedOnly(t *testing.T) {
+	t.Parallel()
+
+	tm := newTestTasksManager()
+	ctl := Daemon{
+		state:        tm.state,
+		tasksManager: tm,
+		logger:       logging.NewNullLogger(),
+	}
+	ctl.SetChangedOnly(true)
+
+	err := ctl.Once(context.Background())
+	require.NoError(t, err)
+	assert.True(t, ctl.once)
+
+	// tasks created after once-mode always run
+	assert.False(t, tm.isChangedOnly())
+}
+
+func Test_Daemon_Once_FailurePolicyContinue(t *testing.T) {
+	// Tasks that error in once-mode do not stop the daemon when the failure
+	// policy allows tasks to fail
+	t.Parallel()
+
+	conf := multipleTaskConfig(t, 3)
+	conf.Driver = &config.DriverConfig{Terraform: &config.TerraformConfig{}}
+	conf.OnceModeFailurePolicy = config.String(config.OnceModeFailurePolicyContinue)
+	ss := state.NewInMemoryStore(conf)
+
+	tm := newTestTasksManager()
+	tm.state = ss
+	tm.factory.initConf = conf
+	tm.factory.newDriver = func(ctx context.Context, c *config.Config, task *driver.Task, w templates.Watcher) (driver.Driver, error) {
+		if task.Name() == "task_01" {
+			return onceMockDriver(task, errors.New("apply error")), nil
+		}
+		return onceMockDriver(task, nil), nil
+	}
+
+	cm := newTestConditionMonitor(tm)
+	errCh := make(chan error)
+	var errChRc <-chan error = errCh
+	go func() { errCh <- nil }()
+	w := new(mocksTmpl.Watcher)
+	w.On("WaitCh", mock.Anything).Return(errChRc)
+	w.On("Size").Return(3)
+	cm.watcher = w
+
+	ctl := Daemon{
+		state:        ss,
+		tasksManager: tm,
+		monitor:      cm,
+		logger:       logging.NewNullLogger(),
+	}
+
+	err := ctl.Once(context.Background())
+	require.NoError(t, err)
+	assert.True(t, ctl.once)
+	assert.Equal(t, 3, tm.drivers.Len())
+}
+
+func Test_retryOnceFailures(t *testing.T) {
+	t.Parallel()
+
+	tm := newTestTasksManager()
+
+	// task_a errors on the first retry and succeeds on the second retry
+	d := new(mocksD.Driver)
+	d.On("Task").Return(enabledTestTask(t, "task_a"))
+	d.On("TemplateIDs").Return(nil)
+	d.On("RenderTemplate", mock.Anything).Return(false, nil)
+	d.On("ApplyTask", mock.Anything).Return(errors.New("apply error")).Once()
+	d.On("ApplyTask", mock.Anything).Return(nil).Once()
+	require.NoError(t, tm.drivers.Add("task_a", d))
+
+	// task_b was created after once-mode, e.g. through the API
+	created := new(mocksD.Driver)
+	created.On("TemplateIDs").Return(nil)
+	require.NoError(t, tm.drivers.Add("task_b", created))
+
+	failed := []onceFailure{
+		{task: config.TaskConfig{Name: config.String("task_a")}, added: true},
+		{task: config.TaskConfig{Name: config.String("task_b")}, added: false},
+		// task_c was deleted after once-mode
+		{task: config.TaskConfig{Name: config.String("task_c")}, added: true},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var attempts int
+	retryOnceFailures(ctx, tm, failed, func(attempt int) time.Duration {
+		attempts = attempt
+		return 0
+	})
+	require.NoError(t, ctx.Err(), "retries did not stop after tasks succeeded")
+
+	assert.Equal(t, 2, attempts)
+	d.AssertExpectations(t)
+	created.AssertNotCalled(t, "ApplyTask", mock.Anything)
+}
+
+func Test_Daemon_superviseOnce(t *testing.T) {
+	t.Parallel()
+
+	t.Run("without supervisor", func(t *testing.T) {
+		ctl := Daemon{logger: logging.NewNullLogger()}
+		expectedErr := errors.New("connection refused")
+		err := ctl.superviseOnce(context.Background(),
+			func(context.Context) error { return expectedErr },
+			func(int) time.Duration { return 0 })
+		assert.Equal(t, expectedErr, err)
+	})
+
+	t.Run("retries while degraded", func(t *testing.T) {
+		h := &health.StatusChecker{}
+		ctl := Daemon{logger: logging.NewNullLogger()}
+		ctl.SetHealth(h)
+
+		var calls int
+		once := func(context.Context) error {
+			calls++
+			if calls < 3 {
+				// degraded while the tasks are retried
+				if calls > 1 {
+					assert.Error(t, h.Check())
+				}
+				return errors.New("connection refused")
+			}
+			return nil
+		}
+
+		var attempts []int
+		err := ctl.superviseOnce(context.Background(), once,
+			func(attempt int) time.Duration {
+				attempts = append(attempts, attempt)
+				return 0
+			})
+		require.NoError(t, err)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, []int{1, 2}, attempts)
+		assert.NoError(t, h.Check())
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		ctl := Daemon{logger: logging.NewNullLogger()}
+		ctl.SetHealth(&health.StatusChecker{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := ctl.superviseOnce(ctx,
+			func(context.Context) error { return errors.New("connection refused") },
+			func(int) time.Duration { return time.Hour })
+		assert.Equal(t, context.Canceled, err)
+	})
+}
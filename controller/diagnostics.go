@@ -0,0 +1,210 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	"github.com/hashicorp/consul-terraform-sync/state/event"
+	"github.com/hashicorp/consul-terraform-sync/version"
+)
+
+// Names of the files in the diagnostics bundle
+const (
+	diagnosticsManifestFile      = "manifest.json"
+	diagnosticsGoroutinesFile    = "goroutines.txt"
+	diagnosticsDependenciesFile  = "dependencies.json"
+	diagnosticsDriversFile       = "drivers.json"
+	diagnosticsNotificationsFile = "notifications.json"
+	diagnosticsEventsFile        = "events.json"
+)
+
+// diagnosticsManifest summarizes the diagnostics bundle
+type diagnosticsManifest struct {
+	Version             string    `json:"version"`
+	CreatedAt           time.Time `json:"created_at"`
+	Goroutines          int       `json:"goroutines"`
+	Tasks               int       `json:"tasks"`
+	ActiveTasks         []string  `json:"active_tasks"`
+	WatcherDependencies int       `json:"watcher_dependencies"`
+}
+
+// taskDependencies are the dependencies of the template of a task watched by
+// the watcher, and the number of times each dependency triggered the task
+type taskDependencies struct {
+	TaskName     string                       `json:"task_name"`
+	Ready        bool                         `json:"ready"`
+	Dependencies []driver.DependencyReadiness `json:"dependencies"`
+	Triggers     map[string]int               `json:"triggers"`
+}
+
+// driverState is the state of the driver of a task
+type driverState struct {
+	TaskName            string     `json:"task_name"`
+	Enabled             bool       `json:"enabled"`
+	Active              bool       `json:"active"`
+	MarkedForDeletion   bool       `json:"marked_for_deletion"`
+	TemplateIDs         []string   `json:"template_ids"`
+	DriverVersion       string     `json:"driver_version"`
+	CircuitBreakerOpen  bool       `json:"circuit_breaker_open"`
+	CircuitBreakerUntil *time.Time `json:"circuit_breaker_until,omitempty"`
+}
+
+// bufferedNotifications are the triggers of a task that are buffered until
+// the task can run
+type bufferedNotifications struct {
+	TaskName string `json:"task_name"`
+
+	// PendingRuns are the times that the runs waiting for the active run of
+	// the task to complete were queued
+	PendingRuns []time.Time `json:"pending_runs"`
+
+	CooldownDeferred bool `json:"cooldown_deferred"`
+	WindowDeferred   bool `json:"maintenance_window_deferred"`
+	GateQueued       bool `json:"gate_queued"`
+	LockQueued       bool `json:"lock_queued"`
+}
+
+// WriteDiagnostics writes a gzipped tarball of internal diagnostics to w for
+// support: the goroutine stacks, the dependencies watched for each task, the
+// state of the drivers, the active tasks, the triggers buffered until the
+// tasks can run, and the recent events of the tasks.
+func (tm *TasksManager) WriteDiagnostics(_ context.Context, w io.Writer) error {
+	now := time.Now().UTC()
+	drivers := tm.drivers.Map()
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	manifest := diagnosticsManifest{
+		Version:     version.GetHumanVersion(),
+		CreatedAt:   now,
+		Goroutines:  runtime.NumGoroutine(),
+		Tasks:       len(names),
+		ActiveTasks: []string{},
+	}
+	if tm.factory != nil && tm.factory.watcher != nil {
+		manifest.WatcherDependencies = tm.factory.watcher.Size()
+	}
+
+	deps := make([]taskDependencies, 0, len(names))
+	states := make([]driverState, 0, len(names))
+	notifications := []bufferedNotifications{}
+	for _, name := range names {
+		d := drivers[name]
+
+		active := tm.drivers.IsActive(name)
+		if active {
+			manifest.ActiveTasks = append(manifest.ActiveTasks, name)
+		}
+
+		readiness := d.Readiness()
+		deps = append(deps, taskDependencies{
+			TaskName:     name,
+			Ready:        readiness.Ready,
+			Dependencies: readiness.Dependencies,
+			Triggers:     d.DependencyTriggers(),
+		})
+
+		s := driverState{
+			TaskName:          name,
+			Enabled:           d.Task().IsEnabled(),
+			Active:            active,
+			MarkedForDeletion: tm.drivers.IsMarkedForDeletion(name),
+			TemplateIDs:       d.TemplateIDs(),
+			DriverVersion:     d.Version(),
+		}
+		if until, ok := tm.breakers.Open(name); ok {
+			s.CircuitBreakerOpen = true
+			if !until.IsZero() {
+				s.CircuitBreakerUntil = &until
+			}
+		}
+		states = append(states, s)
+
+		n := bufferedNotifications{
+			TaskName:         name,
+			PendingRuns:      tm.pendingRuns.Get(name),
+			CooldownDeferred: tm.cooldowns.Deferred(name),
+			WindowDeferred:   tm.windowRuns.Deferred(name),
+			GateQueued:       tm.gateRuns.Queued(name),
+		}
+		if lock, ok := tm.locks.Get(name); ok {
+			n.LockQueued = lock.RunQueued
+		}
+		if len(n.PendingRuns) > 0 || n.CooldownDeferred || n.WindowDeferred ||
+			n.GateQueued || n.LockQueued {
+			notifications = append(notifications, n)
+		}
+	}
+
+	var goroutines bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&goroutines, 2); err != nil {
+		return err
+	}
+
+	events := tm.state.GetTaskEvents("")
+	if events == nil {
+		events = map[string][]event.Event{}
+	}
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	files := []struct {
+		name string
+		v    interface{}
+	}{
+		{diagnosticsManifestFile, manifest},
+		{diagnosticsGoroutinesFile, goroutines.Bytes()},
+		{diagnosticsDependenciesFile, deps},
+		{diagnosticsDriversFile, states},
+		{diagnosticsNotificationsFile, notifications},
+		{diagnosticsEventsFile, events},
+	}
+	for _, f := range files {
+		content, ok := f.v.([]byte)
+		if !ok {
+			var err error
+			content, err = json.MarshalIndent(f.v, "", "  ")
+			if err != nil {
+				return err
+			}
+		}
+		if err := writeTarFile(tw, f.name, content, now); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// writeTarFile writes a file with the content to the tarball
+func writeTarFile(tw *tar.Writer, name string, content []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(content)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(content)
+	return err
+}
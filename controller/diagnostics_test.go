@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	mocksD "github.com/hashicorp/consul-terraform-sync/mocks/driver"
+	mocksTmpl "github.com/hashicorp/consul-terraform-sync/mocks/templates"
+	"github.com/hashicorp/consul-terraform-sync/state/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TasksManager_WriteDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	newDriver := func(t *testing.T, name string) *mocksD.Driver {
+		d := new(mocksD.Driver)
+		d.On("Task").Return(enabledTestTask(t, name))
+		d.On("TemplateIDs").Return([]string{"tmpl_" + name})
+		d.On("Version").Return("1.0.0")
+		d.On("DependencyTriggers").Return(map[string]int{"health.service(web)": 2})
+		d.On("Readiness").Return(driver.Readiness{
+			TaskName: name,
+			Ready:    true,
+			Fetched:  1,
+			Total:    1,
+			Dependencies: []driver.DependencyReadiness{
+				{Name: "health.service(web)", Fetched: true},
+			},
+		})
+		return d
+	}
+
+	w := new(mocksTmpl.Watcher)
+	w.On("Size").Return(3)
+
+	tm := newTestTasksManager()
+	tm.factory.watcher = w
+	require.NoError(t, tm.drivers.Add("task_b", newDriver(t, "task_b")))
+	require.NoError(t, tm.drivers.Add("task_a", newDriver(t, "task_a")))
+	tm.drivers.SetActive("task_a")
+	tm.pendingRuns.Add("task_a")
+	_, err := tm.locks.Lock("task_b", "alice", "", time.Now())
+	require.NoError(t, err)
+	tm.locks.Queue("task_b", func() {})
+	require.NoError(t, tm.state.AddTaskEvent(event.Event{
+		ID:       "123",
+		TaskName: "task_a",
+		Success:  true,
+	}))
+
+	var buf bytes.Buffer
+	require.NoError(t, tm.WriteDiagnostics(context.Background(), &buf))
+	files := readTarball(t, &buf)
+
+	assert.ElementsMatch(t, []string{
+		diagnosticsManifestFile,
+		diagnosticsGoroutinesFile,
+		diagnosticsDependenciesFile,
+		diagnosticsDriversFile,
+		diagnosticsNotificationsFile,
+		diagnosticsEventsFile,
+	}, fileNames(files))
+
+	var manifest diagnosticsManifest
+	require.NoError(t, json.Unmarshal(files[diagnosticsManifestFile], &manifest))
+	assert.Equal(t, 2, manifest.Tasks)
+	assert.Equal(t, []string{"task_a"}, manifest.ActiveTasks)
+	assert.Equal(t, 3, manifest.WatcherDependencies)
+	assert.Positive(t, manifest.Goroutines)
+
+	assert.Contains(t, string(files[diagnosticsGoroutinesFile]), "goroutine")
+
+	var deps []taskDependencies
+	require.NoError(t, json.Unmarshal(files[diagnosticsDependenciesFile], &deps))
+	require.Len(t, deps, 2)
+	assert.Equal(t, "task_a", deps[0].TaskName)
+	assert.Equal(t, 2, deps[0].Triggers["health.service(web)"])
+	assert.Len(t, deps[0].Dependencies, 1)
+
+	var states []driverState
+	require.NoError(t, json.Unmarshal(files[diagnosticsDriversFile], &states))
+	assert.Equal(t, []driverState{
+		{
+			TaskName:      "task_a",
+			Enabled:       true,
+			Active:        true,
+			TemplateIDs:   []string{"tmpl_task_a"},
+			DriverVersion: "1.0.0",
+		},
+		{
+			TaskName:      "task_b",
+			Enabled:       true,
+			TemplateIDs:   []string{"tmpl_task_b"},
+			DriverVersion: "1.0.0",
+		},
+	}, states)
+
+	var notifications []bufferedNotifications
+	require.NoError(t, json.Unmarshal(files[diagnosticsNotificationsFile], &notifications))
+	require.Len(t, notifications, 2)
+	assert.Equal(t, "task_a", notifications[0].TaskName)
+	assert.Len(t, notifications[0].PendingRuns, 1)
+	assert.Equal(t, "task_b", notifications[1].TaskName)
+	assert.True(t, notifications[1].LockQueued)
+
+	var events map[string][]event.Event
+	require.NoError(t, json.Unmarshal(files[diagnosticsEventsFile], &events))
+	require.Len(t, events["task_a"], 1)
+	assert.Equal(t, "123", events["task_a"][0].ID)
+}
+
+// readTarball reads the files of a gzipped tarball by name
+func readTarball(t *testing.T, r io.Reader) map[string][]byte {
+	gr, err := gzip.NewReader(r)
+	require.NoError(t, err)
+	tr := tar.NewReader(gr)
+
+	files := make(map[string][]byte)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = content
+	}
+	return files
+}
+
+// fileNames returns the names of the files read from a tarball
+func fileNames(files map[string][]byte) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	return names
+}
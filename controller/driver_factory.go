@@ -7,10 +7,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/hashicorp/consul-terraform-sync/alertmanager"
+	"github.com/hashicorp/consul-terraform-sync/client"
 	"github.com/hashicorp/consul-terraform-sync/config"
 	"github.com/hashicorp/consul-terraform-sync/driver"
 	"github.com/hashicorp/consul-terraform-sync/logging"
@@ -28,7 +31,13 @@ type driverFactory struct {
 	watcher   templates.Watcher
 	resolver  templates.Resolver
 	logger    logging.Logger
-	providers []driver.TerraformProviderBlock
+
+	// providers are the provider blocks with their dynamic values evaluated.
+	// providerTmplIDs are the IDs of the templates that fetch the dynamic
+	// values, which are used to reload the providers when the values change.
+	mu              sync.RWMutex
+	providers       []driver.TerraformProviderBlock
+	providerTmplIDs map[string]bool
 
 	// config that CTS is initialized with i.e. only used by driver factory.
 	// subsequent access to the configs should be through the state store.
@@ -58,15 +67,52 @@ func (f *driverFactory) Init(ctx context.Context) error {
 	f.logger.Info("initializing driver factory")
 
 	// Load provider configuration and evaluate dynamic values
-	var err error
-	f.providers, err = f.loadProviderConfigs(ctx)
+	providers, tmplIDs, err := f.loadProviderConfigs(ctx)
 	if err != nil {
 		return err
 	}
 
+	f.mu.Lock()
+	f.providers = providers
+	f.providerTmplIDs = tmplIDs
+	f.mu.Unlock()
+
 	return nil
 }
 
+// IsProviderTemplate returns whether the template fetches dynamic values of
+// the provider blocks, e.g. values from Consul KV.
+func (f *driverFactory) IsProviderTemplate(tmplID string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.providerTmplIDs[tmplID]
+}
+
+// ReloadProviders evaluates the dynamic values of the provider blocks again
+// and returns the IDs of the providers with changed configuration. Drivers
+// created after reloading use the updated provider configuration.
+func (f *driverFactory) ReloadProviders(ctx context.Context) ([]string, error) {
+	providers, tmplIDs, err := f.loadProviderConfigs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var changed []string
+	for i, p := range providers {
+		if i >= len(f.providers) || !p.Equal(f.providers[i]) {
+			changed = append(changed, p.ID())
+		}
+	}
+	f.providers = providers
+	f.providerTmplIDs = tmplIDs
+
+	return changed, nil
+}
+
 // Make makes a new driver for a task
 func (f *driverFactory) Make(ctx context.Context, conf *config.Config,
 	taskConf config.TaskConfig) (driver.Driver, error) {
@@ -98,12 +144,30 @@ func (f *driverFactory) Make(ctx context.Context, conf *config.Config,
 func (f *driverFactory) createNewTaskDriver(ctx context.Context, conf *config.Config, taskConfig config.TaskConfig) (driver.Driver, error) {
 	logger := f.logger.With("task_name", *taskConfig.Name)
 	logger.Trace("creating new task driver")
-	task, err := newDriverTask(conf, &taskConfig, f.providers)
+	token, err := f.loadConsulToken(ctx, taskConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	d, err := f.newDriver(ctx, conf, task, f.watcher)
+	if err := f.loadVariablesFromConsulKV(ctx, &taskConfig); err != nil {
+		return nil, err
+	}
+
+	f.mu.RLock()
+	providers := f.providers
+	f.mu.RUnlock()
+
+	task, err := newDriverTask(conf, &taskConfig, providers, token)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := f.taskWatcher(token)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := f.newDriver(ctx, conf, task, w)
 	if err != nil {
 		return nil, err
 	}
@@ -112,22 +176,128 @@ func (f *driverFactory) createNewTaskDriver(ctx context.Context, conf *config.Co
 	return d, nil
 }
 
+// loadConsulToken loads the Consul token of the task from the token file or
+// evaluates the token if it is a dynamic value. Returns an empty string if
+// the task is not configured with its own token. Dynamic tokens are evaluated
+// when the driver is created and are not updated if the secret changes.
+func (f *driverFactory) loadConsulToken(ctx context.Context, taskConfig config.TaskConfig) (string, error) {
+	taskName := *taskConfig.Name
+	if path := config.StringVal(taskConfig.ConsulTokenFile); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("error reading consul_token_file for task %s: %s",
+				taskName, err)
+		}
+		token := strings.TrimSpace(string(b))
+		if token == "" {
+			return "", fmt.Errorf("consul_token_file for task %s is empty", taskName)
+		}
+		return token, nil
+	}
+
+	token := config.StringVal(taskConfig.ConsulToken)
+	if !hcltmpl.ContainsDynamicTemplate(token) {
+		return token, nil
+	}
+
+	ctxTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	block, err := hcltmpl.LoadDynamicConfig(ctxTimeout, f.watcher, f.resolver,
+		map[string]interface{}{
+			taskName: map[string]interface{}{"consul_token": token},
+		})
+	if err != nil {
+		return "", fmt.Errorf("error loading dynamic consul_token for task %s: %s",
+			taskName, err)
+	}
+	return block.Variables["consul_token"].AsString(), nil
+}
+
+// loadVariablesFromConsulKV loads the variables of the task from its Consul
+// KV prefixes. The variables configured on the task override the variables
+// from Consul KV. The key-values are read when the driver is created, so
+// changes in Consul KV are used by the driver created when the task is
+// updated or CTS is reloaded.
+func (f *driverFactory) loadVariablesFromConsulKV(ctx context.Context,
+	taskConfig *config.TaskConfig) error {
+
+	if len(taskConfig.VariablesFromConsulKV) == 0 {
+		return nil
+	}
+
+	ctxTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	vars, err := loadKVVariables(ctxTimeout, f.variablesKV(),
+		taskConfig.VariablesFromConsulKV)
+	if err != nil {
+		return fmt.Errorf("error loading variables_from_consul_kv for task %s: %s",
+			*taskConfig.Name, err)
+	}
+
+	for k, v := range taskConfig.Variables {
+		vars[k] = v
+	}
+	taskConfig.Variables = vars
+	return nil
+}
+
+// variablesKV returns the Consul KV client to load the variables of tasks
+func (f *driverFactory) variablesKV() variablesKV {
+	return f.watcher.Clients().Consul().KV()
+}
+
+// taskWatcher returns the watcher for a task. Tasks with their own Consul
+// token use a watcher that queries Consul with the token.
+func (f *driverFactory) taskWatcher(token string) (templates.Watcher, error) {
+	if token == "" {
+		return f.watcher, nil
+	}
+
+	tw, ok := f.watcher.(tokenWatchers)
+	if !ok {
+		return nil, errors.New("task Consul tokens are not supported by the watcher")
+	}
+	return tw.ForToken(token)
+}
+
+// gateKV returns the Consul KV client to look up the gates of tasks. The
+// gates are looked up with the Consul token of CTS.
+func (f *driverFactory) gateKV() gateKV {
+	return f.watcher.Clients().Consul().KV()
+}
+
+// consulResources returns the lookup of the datacenters and namespaces of
+// the connected Consul. The resources are looked up with the Consul token of
+// CTS.
+func (f *driverFactory) consulResources() consulResources {
+	return &consulAPIResources{client: f.watcher.Clients().Consul()}
+}
+
 // loadProviderConfigs loads provider configs and evaluates provider blocks
-// for dynamic values in parallel.
-func (f *driverFactory) loadProviderConfigs(ctx context.Context) ([]driver.TerraformProviderBlock, error) {
+// for dynamic values in parallel. Returns the provider blocks and the IDs of
+// the templates for the dynamic values.
+func (f *driverFactory) loadProviderConfigs(ctx context.Context) (
+	[]driver.TerraformProviderBlock, map[string]bool, error) {
 	numBlocks := len(*f.initConf.TerraformProviders)
 	var wg sync.WaitGroup
 	wg.Add(numBlocks)
 
+	var mu sync.Mutex
 	var lastErr error
 	providerConfigs := make([]driver.TerraformProviderBlock, numBlocks)
+	tmplIDs := make(map[string]bool)
 	for i, providerConf := range *f.initConf.TerraformProviders {
 		go func(i int, initConf map[string]interface{}) {
 			ctxTimeout, cancel := context.WithTimeout(ctx, 5*time.Second)
 			defer cancel()
 			defer wg.Done()
 
-			block, err := hcltmpl.LoadDynamicConfig(ctxTimeout, f.watcher, f.resolver, initConf)
+			block, ids, err := hcltmpl.LoadDynamicConfigWithTemplateIDs(ctxTimeout,
+				f.watcher, f.resolver, initConf)
+			mu.Lock()
+			defer mu.Unlock()
 			if err != nil {
 				f.logger.Error("error loading dynamic configuration for provider",
 					"provider", block.Name, "error", err)
@@ -135,14 +305,17 @@ func (f *driverFactory) loadProviderConfigs(ctx context.Context) ([]driver.Terra
 				return
 			}
 			providerConfigs[i] = driver.NewTerraformProviderBlock(block)
+			for _, id := range ids {
+				tmplIDs[id] = true
+			}
 		}(i, *providerConf)
 	}
 
 	wg.Wait()
 	if lastErr != nil {
-		return nil, lastErr
+		return nil, nil, lastErr
 	}
-	return providerConfigs, nil
+	return providerConfigs, tmplIDs, nil
 }
 
 // newDriverFunc is a constructor abstraction for all of supported drivers
@@ -155,22 +328,130 @@ func newDriverFunc(conf *config.Config) (driverFactoryFunc, error) {
 
 // newTerraformDriver maps user configuration to initialize a Terraform driver
 // for a task
-func newTerraformDriver(_ context.Context, conf *config.Config, task *driver.Task, w templates.Watcher) (driver.Driver, error) {
+func newTerraformDriver(ctx context.Context, conf *config.Config, task *driver.Task, w templates.Watcher) (driver.Driver, error) {
 	tfConf := *conf.Driver.Terraform
+
+	// Tasks configured with their own Terraform version use the binary for
+	// that version, which is installed if it is not already cached
+	path := *tfConf.Path
+	if v := task.TFVersion(); v != "" {
+		var err error
+		path, err = driver.InstallTerraformVersion(ctx, &tfConf, v)
+		if err != nil {
+			return nil, fmt.Errorf("error installing Terraform version %s "+
+				"for task %s: %s", v, task.Name(), err)
+		}
+	}
+
+	workspace, err := tfConf.Workspace(task.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	silencer, err := newSilencer(conf, task)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tasks configured with their own backend use the backend instead of the
+	// driver's backend
+	backend := tfConf.Backend
+	if b := task.Backend(); len(b) > 0 {
+		backend = b
+	}
+
 	return driver.NewTerraform(&driver.TerraformConfig{
 		Task:              task,
 		Watcher:           w,
 		Log:               *tfConf.Log,
 		PersistLog:        *tfConf.PersistLog,
-		Path:              *tfConf.Path,
-		Backend:           tfConf.Backend,
+		Path:              path,
+		Backend:           backend,
 		RequiredProviders: tfConf.RequiredProviders,
+		Workspace:         workspace,
 		ClientType:        *conf.ClientType,
+		Sandbox:           newSandboxConfig(tfConf.Sandbox),
+		PluginCache:       newPluginCache(tfConf.PluginCache, task.Name()),
+		Silencer:          silencer,
+	})
+}
+
+// newSilencer returns the Alertmanager client to create the silences of a
+// task. Returns nil if the task does not silence alerts.
+func newSilencer(conf *config.Config, task *driver.Task) (driver.Silencer, error) {
+	if _, ok := task.AlertSilence(); !ok || !conf.Alertmanager.IsEnabled() {
+		return nil, nil
+	}
+
+	am := conf.Alertmanager
+	c, err := alertmanager.NewClient(alertmanager.ClientConfig{
+		Address:   config.StringVal(am.Address),
+		Token:     config.StringVal(am.Token),
+		Timeout:   config.TimeDurationVal(am.Timeout),
+		Transport: conf.Proxy.Transport(),
 	})
+	if err != nil {
+		return nil, fmt.Errorf("error initializing Alertmanager client for "+
+			"task %s: %s", task.Name(), err)
+	}
+	return c, nil
+}
+
+// newAlertSilence maps the alertmanager_silence configuration of a task to
+// the silence of the driver task
+func newAlertSilence(conf *config.AlertmanagerSilenceConfig) *driver.AlertSilence {
+	var matchers []alertmanager.Matcher
+	if conf.Matchers != nil {
+		matchers = make([]alertmanager.Matcher, 0, conf.Matchers.Len())
+		for _, m := range *conf.Matchers {
+			matchers = append(matchers, alertmanager.Matcher{
+				Name:    config.StringVal(m.Name),
+				Value:   config.StringVal(m.Value),
+				IsRegex: config.BoolVal(m.Regex),
+				IsEqual: config.BoolVal(m.Equal),
+			})
+		}
+	}
+
+	return &driver.AlertSilence{
+		Duration: config.TimeDurationVal(conf.Duration),
+		Comment:  config.StringVal(conf.Comment),
+		Matchers: matchers,
+	}
+}
+
+// newPluginCache maps the plugin cache configuration of the Terraform driver
+// to the plugin cache of a task. Returns nil if the plugin cache is disabled.
+func newPluginCache(conf *config.TerraformPluginCacheConfig, taskName string) *driver.PluginCache {
+	dir := conf.TaskDir(taskName)
+	if dir == "" {
+		return nil
+	}
+
+	return &driver.PluginCache{
+		Dir:    dir,
+		Shared: !config.BoolVal(conf.PerTask),
+	}
+}
+
+// newSandboxConfig maps the sandbox configuration of the Terraform driver to
+// the sandbox of the Terraform client. Returns nil if the sandbox is disabled.
+func newSandboxConfig(conf *config.TerraformSandboxConfig) *client.SandboxConfig {
+	if conf == nil || !config.BoolVal(conf.Enabled) {
+		return nil
+	}
+
+	return &client.SandboxConfig{
+		UID:               config.IntVal(conf.UID),
+		GID:               config.IntVal(conf.GID),
+		EnvAllowlist:      conf.EnvAllowlist,
+		ConfineWorkingDir: config.BoolVal(conf.ConfineWorkingDir),
+		SeccompProfile:    config.StringVal(conf.SeccompProfile),
+	}
 }
 
 func newDriverTask(conf *config.Config, taskConfig *config.TaskConfig,
-	providerConfigs driver.TerraformProviderBlocks) (*driver.Task, error) {
+	providerConfigs driver.TerraformProviderBlocks, consulToken string) (*driver.Task, error) {
 	if conf == nil || conf.Driver == nil {
 		// only expected for testing
 		return nil, nil
@@ -190,11 +471,15 @@ func newDriverTask(conf *config.Config, taskConfig *config.TaskConfig,
 	}
 
 	tfConf := conf.Driver.Terraform
+	backend := tfConf.TaskBackend(tc.Backend, conf.Consul)
 
 	providers := make(driver.TerraformProviderBlocks, len(tc.Providers))
 	providerInfo := make(map[string]interface{})
 	for pi, providerID := range tc.Providers {
 		providers[pi] = getProvider(providerConfigs, providerID)
+		if overrides, ok := tc.ProviderOverrides[providerID]; ok {
+			providers[pi] = providers[pi].Override(overrides)
+		}
 
 		// This is Terraform specific to pass version and source info for
 		// providers from the required_provider block
@@ -206,6 +491,12 @@ func newDriverTask(conf *config.Config, taskConfig *config.TaskConfig,
 		}
 	}
 
+	providerDCs := config.BoolVal(tc.ProviderForeachDatacenter)
+	if providerDCs {
+		providers = getDatacenterProviders(providerConfigs, tc.Providers,
+			tc.Datacenters(), tc.ProviderOverrides)
+	}
+
 	var bp *driver.BufferPeriod // nil if disabled
 	if *tc.BufferPeriod.Enabled {
 		bp = &driver.BufferPeriod{
@@ -214,21 +505,97 @@ func newDriverTask(conf *config.Config, taskConfig *config.TaskConfig,
 		}
 	}
 
+	var cb *driver.CircuitBreaker // nil if disabled
+	if tc.CircuitBreaker != nil && config.BoolVal(tc.CircuitBreaker.Enabled) {
+		cb = &driver.CircuitBreaker{
+			Threshold:  config.IntVal(tc.CircuitBreaker.Threshold),
+			Backoff:    config.TimeDurationVal(tc.CircuitBreaker.Backoff),
+			MaxBackoff: config.TimeDurationVal(tc.CircuitBreaker.MaxBackoff),
+		}
+	}
+
+	var window *driver.MaintenanceWindow // nil if disabled
+	if tc.MaintenanceWindow != nil && config.BoolVal(tc.MaintenanceWindow.Enabled) {
+		window = &driver.MaintenanceWindow{
+			Cron:     config.StringVal(tc.MaintenanceWindow.Cron),
+			Duration: config.TimeDurationVal(tc.MaintenanceWindow.Duration),
+		}
+	}
+
+	var gates []driver.KVGate
+	if tc.Gates != nil {
+		for _, g := range *tc.Gates {
+			if g.ConsulKV == nil {
+				continue
+			}
+			gates = append(gates, driver.KVGate{
+				Path:       config.StringVal(g.ConsulKV.Path),
+				Equals:     config.StringVal(g.ConsulKV.Equals),
+				Datacenter: config.StringVal(g.ConsulKV.Datacenter),
+				Namespace:  config.StringVal(g.ConsulKV.Namespace),
+			})
+		}
+	}
+
+	var publish *driver.PublishOutputs // nil if disabled
+	if tc.PublishOutputs != nil && config.BoolVal(tc.PublishOutputs.Enabled) {
+		publish = &driver.PublishOutputs{
+			Path:    config.StringVal(tc.PublishOutputs.Path),
+			Outputs: tc.PublishOutputs.Outputs,
+		}
+	}
+
+	var silence *driver.AlertSilence // nil if disabled
+	if tc.AlertmanagerSilence != nil && config.BoolVal(tc.AlertmanagerSilence.Enabled) {
+		silence = newAlertSilence(tc.AlertmanagerSilence)
+	}
+
+	var savePlan bool
+	if conf.PlanArtifacts != nil {
+		savePlan = config.BoolVal(conf.PlanArtifacts.Enabled)
+	}
+
 	task, err := driver.NewTask(driver.TaskConfig{
-		Description:  *tc.Description,
-		Name:         *tc.Name,
-		Enabled:      *tc.Enabled,
-		Env:          buildTaskEnv(conf, providers.Env()),
-		Providers:    providers,
-		ProviderInfo: providerInfo,
-		Services:     services,
-		Module:       *tc.Module,
-		Version:      *tc.Version,
-		Variables:    tc.Variables,
-		BufferPeriod: bp,
-		Condition:    tc.Condition,
-		ModuleInputs: *tc.ModuleInputs,
-		WorkingDir:   *tc.WorkingDir,
+		Description:       *tc.Description,
+		Name:              *tc.Name,
+		Enabled:           *tc.Enabled,
+		RenderOnly:        config.BoolVal(tc.RenderOnly),
+		SavePlan:          savePlan,
+		ServicesChanged:   config.BoolVal(tc.ServicesChanged),
+		TargetedApply:     config.BoolVal(tc.TargetedApply),
+		ApplyTargets:      tc.ApplyTargets,
+		Env:               buildTaskEnv(conf, backend, consulToken, providers.Env()),
+		Providers:         providers,
+		ProviderInfo:      providerInfo,
+		Backend:           backend,
+		Environments:      newTaskEnvironments(tc.EnvironmentConfigs),
+		ExtraTemplates:    tc.ExtraTemplates,
+		Postconditions:    *tc.Postconditions,
+		ComputedInputs:    tc.ComputedInputs.Exprs(),
+		PublishOutputs:    publish,
+		AlertSilence:      silence,
+		Handlers:          *tc.Handlers,
+		Services:          services,
+		Module:            *tc.Module,
+		Version:           *tc.Version,
+		TFVersion:         config.StringVal(tc.DeprecatedTFVersion),
+		Variables:         tc.Variables,
+		BufferPeriod:      bp,
+		Cooldown:          config.TimeDurationVal(tc.Cooldown),
+		Priority:          config.IntVal(tc.Priority),
+		InputSnapshots:    config.IntVal(tc.InputSnapshots),
+		CircuitBreaker:    cb,
+		MaintenanceWindow: window,
+		Gates:             gates,
+		DependsOn:         tc.DependsOn,
+		SkipOnDepFailure:  config.BoolVal(tc.SkipOnDependencyFailure),
+		Condition:         tc.Condition,
+		ModuleInputs:      *tc.ModuleInputs,
+		WorkingDir:        *tc.WorkingDir,
+
+		ProviderForeachDatacenter: providerDCs,
+		SensitiveVariables:        tc.SensitiveVariables,
+		TriggerOnAnyInputChange:   config.StringVal(tc.TriggerOn) == config.TaskTriggerOnAnyInputChange,
 
 		// Enterprise
 		DeprecatedTFVersion: *tc.DeprecatedTFVersion,
@@ -242,6 +609,26 @@ func newDriverTask(conf *config.Config, taskConfig *config.TaskConfig,
 	return task, nil
 }
 
+// newTaskEnvironments maps the environment configurations of a task to the
+// environments of the driver task. Returns nil if the task has no
+// environments.
+func newTaskEnvironments(conf *config.TaskEnvironmentConfigs) []driver.TaskEnvironment {
+	if conf.Len() == 0 {
+		return nil
+	}
+
+	envs := make([]driver.TaskEnvironment, 0, conf.Len())
+	for _, e := range *conf {
+		envs = append(envs, driver.TaskEnvironment{
+			Name:       config.StringVal(e.Name),
+			Workspace:  config.StringVal(e.Workspace),
+			Variables:  e.Variables,
+			BackendKey: config.StringVal(e.BackendKey),
+		})
+	}
+	return envs
+}
+
 // getService is a helper to find and convert a user-defined service
 // configuration by ID to a driver service type. If a service is not
 // explicitly configured, it assumes the service is a logical service name
@@ -306,18 +693,94 @@ func getProvider(providers driver.TerraformProviderBlocks, id string) driver.Ter
 	})
 }
 
-func buildTaskEnv(conf *config.Config, customEnv map[string]string) map[string]string {
+// getDatacenterProviders returns an aliased provider block for each provider
+// name and datacenter, where the alias is the datacenter. A provider block
+// configured with the datacenter as the alias is used if one exists, otherwise
+// the provider block without an alias is copied for the datacenter.
+//
+// terraform_provider "name" { alias = "<datacenter>" }
+func getDatacenterProviders(providers driver.TerraformProviderBlocks,
+	names []string, dcs []string,
+	overrides map[string]map[string]interface{}) driver.TerraformProviderBlocks {
+
+	dcProviders := make(driver.TerraformProviderBlocks, 0, len(names)*len(dcs))
+	for _, name := range names {
+		for _, dc := range dcs {
+			p, ok := findProvider(providers, name, dc)
+			if !ok {
+				p, ok = findProvider(providers, name, "")
+			}
+			if !ok {
+				p = getProvider(providers, name)
+			}
+			if o, ok := overrides[name]; ok {
+				p = p.Override(o)
+			}
+			dcProviders = append(dcProviders, p.Override(map[string]interface{}{
+				"alias": dc,
+			}))
+		}
+	}
+	return dcProviders
+}
+
+// findProvider returns the provider block with the name and alias. An empty
+// alias matches the provider block without an alias.
+func findProvider(providers driver.TerraformProviderBlocks, name,
+	alias string) (driver.TerraformProviderBlock, bool) {
+
+	for _, p := range providers {
+		if p.Name() != name {
+			continue
+		}
+		a, ok := p.ProviderBlock().Variables["alias"]
+		if !ok && alias == "" {
+			return p, true
+		}
+		if ok && a.AsString() == alias {
+			return p, true
+		}
+	}
+	return driver.TerraformProviderBlock{}, false
+}
+
+func buildTaskEnv(conf *config.Config, taskBackend map[string]interface{},
+	consulToken string, customEnv map[string]string) map[string]string {
 	consulEnv := conf.Consul.Env()
-	if len(customEnv) == 0 && len(consulEnv) == 0 {
+	proxyEnv := conf.Proxy.Env()
+	tfEnv := conf.Driver.Terraform.Env()
+	if len(customEnv) == 0 && len(consulEnv) == 0 && len(proxyEnv) == 0 &&
+		len(tfEnv) == 0 {
 		return nil
 	}
 
-	// Merge the Consul environment if Consul KV is used as the Terraform backend
+	isConsulBackend := conf.Driver.Terraform.IsConsulBackend()
+	if len(taskBackend) > 0 {
+		_, isConsulBackend = taskBackend["consul"]
+	}
+
+	// Merge the Consul environment if Consul KV is used as the Terraform backend.
+	// The task's Consul token is used for state access instead of the token of
+	// the consul block.
+	// Merge the proxy environment so that Terraform uses the configured proxy
+	// for registry, provider, and Terraform Cloud requests.
+	// Merge the Terraform environment so that Terraform uses the generated CLI
+	// configuration for the provider network mirror
 	env := make(map[string]string)
-	if conf.Driver.Terraform.IsConsulBackend() {
+	for k, v := range tfEnv {
+		env[k] = v
+	}
+	for k, v := range proxyEnv {
+		env[k] = v
+	}
+
+	if isConsulBackend {
 		for k, v := range consulEnv {
 			env[k] = v
 		}
+		if consulToken != "" {
+			env["CONSUL_HTTP_TOKEN"] = consulToken
+		}
 	}
 
 	for k, v := range customEnv {
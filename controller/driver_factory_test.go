@@ -6,9 +6,13 @@ package controller
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/consul-terraform-sync/alertmanager"
+	"github.com/hashicorp/consul-terraform-sync/client"
 	"github.com/hashicorp/consul-terraform-sync/config"
 	"github.com/hashicorp/consul-terraform-sync/driver"
 	"github.com/hashicorp/consul-terraform-sync/logging"
@@ -16,6 +20,7 @@ import (
 	mocksTmpl "github.com/hashicorp/consul-terraform-sync/mocks/templates"
 	"github.com/hashicorp/consul-terraform-sync/templates"
 	"github.com/hashicorp/consul-terraform-sync/templates/hcltmpl"
+	"github.com/hashicorp/hcat"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -61,6 +66,52 @@ func Test_driverFactory_Init(t *testing.T) {
 	}
 }
 
+func Test_driverFactory_ReloadProviders(t *testing.T) {
+	t.Parallel()
+
+	keyTmpl := `{{ key "cts/providers/aws/region" }}`
+	conf := &config.Config{
+		TerraformProviders: &config.TerraformProviderConfigs{
+			{"aws": map[string]interface{}{"region": keyTmpl}},
+			{"local": map[string]interface{}{"attr": "value"}},
+		},
+	}
+
+	w := new(mocksTmpl.Watcher)
+	w.On("Register", mock.Anything).Return(nil)
+	r := new(mocksTmpl.Resolver)
+	r.On("Run", mock.Anything, mock.Anything).Return(hcat.ResolveEvent{
+		Complete: true, Contents: []byte("us-east-1")}, nil).Twice()
+	r.On("Run", mock.Anything, mock.Anything).Return(hcat.ResolveEvent{
+		Complete: true, Contents: []byte("us-west-2")}, nil).Once()
+
+	f := &driverFactory{
+		initConf: conf,
+		watcher:  w,
+		resolver: r,
+		logger:   logging.NewNullLogger(),
+	}
+	ctx := context.Background()
+	require.NoError(t, f.Init(ctx))
+
+	tmplID := hcat.NewTemplate(hcat.TemplateInput{Contents: keyTmpl}).ID()
+	assert.True(t, f.IsProviderTemplate(tmplID))
+	assert.False(t, f.IsProviderTemplate("other"))
+
+	// KV value is unchanged
+	changed, err := f.ReloadProviders(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+
+	// KV value changed
+	changed, err = f.ReloadProviders(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"aws"}, changed)
+	assert.Equal(t, "us-west-2",
+		f.providers[0].ProviderBlock().Variables["region"].AsString())
+	r.AssertExpectations(t)
+}
+
 func Test_driverFactory_Make(t *testing.T) {
 	t.Parallel()
 
@@ -153,13 +204,21 @@ func TestNewDriverTask(t *testing.T) {
 				Enabled:     true,
 				Module:      "path",
 				Version:     "version",
+				TFVersion:   "1.0.0",
 				BufferPeriod: &driver.BufferPeriod{
 					Min: 5 * time.Second,
 					Max: 20 * time.Second,
 				},
-				Condition:    config.EmptyConditionConfig(),
-				ModuleInputs: *config.DefaultModuleInputConfigs(),
-				WorkingDir:   "working-dir/name",
+				ApplyTargets:       map[string][]string{},
+				ExtraTemplates:     []string{},
+				Postconditions:     config.PostconditionConfigs{},
+				ComputedInputs:     map[string]string{},
+				DependsOn:          []string{},
+				SensitiveVariables: []string{},
+				Handlers:           config.HandlerConfigs{},
+				Condition:          config.EmptyConditionConfig(),
+				ModuleInputs:       *config.DefaultModuleInputConfigs(),
+				WorkingDir:         "working-dir/name",
 
 				// Enterprise
 				DeprecatedTFVersion: "1.0.0",
@@ -216,10 +275,17 @@ func TestNewDriverTask(t *testing.T) {
 						"source": "source/providerA",
 					},
 				},
-				Services:     []driver.Service{},
-				Module:       "path",
-				Condition:    config.EmptyConditionConfig(),
-				ModuleInputs: *config.DefaultModuleInputConfigs(),
+				Services:           []driver.Service{},
+				Module:             "path",
+				ApplyTargets:       map[string][]string{},
+				ExtraTemplates:     []string{},
+				Postconditions:     config.PostconditionConfigs{},
+				ComputedInputs:     map[string]string{},
+				DependsOn:          []string{},
+				SensitiveVariables: []string{},
+				Handlers:           config.HandlerConfigs{},
+				Condition:          config.EmptyConditionConfig(),
+				ModuleInputs:       *config.DefaultModuleInputConfigs(),
 				BufferPeriod: &driver.BufferPeriod{
 					Min: 5 * time.Second,
 					Max: 20 * time.Second,
@@ -285,10 +351,72 @@ func TestNewDriverTask(t *testing.T) {
 						"source": "source/providerA",
 					},
 				},
-				Services:     []driver.Service{},
-				Module:       "path",
-				Condition:    config.EmptyConditionConfig(),
-				ModuleInputs: *config.DefaultModuleInputConfigs(),
+				Services:           []driver.Service{},
+				Module:             "path",
+				ApplyTargets:       map[string][]string{},
+				ExtraTemplates:     []string{},
+				Postconditions:     config.PostconditionConfigs{},
+				ComputedInputs:     map[string]string{},
+				DependsOn:          []string{},
+				SensitiveVariables: []string{},
+				Handlers:           config.HandlerConfigs{},
+				Condition:          config.EmptyConditionConfig(),
+				ModuleInputs:       *config.DefaultModuleInputConfigs(),
+				BufferPeriod: &driver.BufferPeriod{
+					Min: 5 * time.Second,
+					Max: 20 * time.Second,
+				},
+				WorkingDir: "sync-tasks/name",
+
+				// Enterprise
+				TFCWorkspace: *config.DefaultTerraformCloudWorkspaceConfig(),
+			})},
+		}, {
+			// Task provider overrides replace the provider block arguments
+			"provider overrides",
+			&config.Config{
+				Tasks: &config.TaskConfigs{
+					{
+						Name:      config.String("name"),
+						Providers: []string{"providerA"},
+						ProviderOverrides: map[string]map[string]interface{}{
+							"providerA": {"hostname": "host-2"},
+						},
+						Module: config.String("path"),
+					},
+				},
+				TerraformProviders: &config.TerraformProviderConfigs{
+					{"providerA": map[string]interface{}{
+						"hostname": "host-1",
+						"username": "admin",
+					}},
+				},
+			},
+			[]*driver.Task{newTestTask(t, driver.TaskConfig{
+				Name:    "name",
+				Enabled: true,
+				Env: map[string]string{
+					"CONSUL_HTTP_ADDR": "localhost:8500",
+				},
+				Providers: driver.NewTerraformProviderBlocks(
+					hcltmpl.NewNamedBlocksTest([]map[string]interface{}{
+						{"providerA": map[string]interface{}{
+							"hostname": "host-2",
+							"username": "admin",
+						}},
+					})),
+				ProviderInfo:       map[string]interface{}{},
+				Services:           []driver.Service{},
+				Module:             "path",
+				ApplyTargets:       map[string][]string{},
+				ExtraTemplates:     []string{},
+				Postconditions:     config.PostconditionConfigs{},
+				ComputedInputs:     map[string]string{},
+				DependsOn:          []string{},
+				SensitiveVariables: []string{},
+				Handlers:           config.HandlerConfigs{},
+				Condition:          config.EmptyConditionConfig(),
+				ModuleInputs:       *config.DefaultModuleInputConfigs(),
 				BufferPeriod: &driver.BufferPeriod{
 					Min: 5 * time.Second,
 					Max: 20 * time.Second,
@@ -338,11 +466,109 @@ func TestNewDriverTask(t *testing.T) {
 							},
 						}},
 					})),
+				ProviderInfo:       map[string]interface{}{},
+				Services:           []driver.Service{},
+				Module:             "path",
+				ApplyTargets:       map[string][]string{},
+				ExtraTemplates:     []string{},
+				Postconditions:     config.PostconditionConfigs{},
+				ComputedInputs:     map[string]string{},
+				DependsOn:          []string{},
+				SensitiveVariables: []string{},
+				Handlers:           config.HandlerConfigs{},
+				Condition:          config.EmptyConditionConfig(),
+				ModuleInputs:       *config.DefaultModuleInputConfigs(),
+				BufferPeriod: &driver.BufferPeriod{
+					Min: 5 * time.Second,
+					Max: 20 * time.Second,
+				},
+				WorkingDir: "sync-tasks/name",
+				// Enterprise
+				TFCWorkspace: *config.DefaultTerraformCloudWorkspaceConfig(),
+			})},
+		}, {
+			// Task Consul token is used for the default backend instead of
+			// the token of the consul block
+			"task consul token",
+			&config.Config{
+				Consul: &config.ConsulConfig{
+					Address: config.String("my.consul.address"),
+					Token:   config.String("TEST_CONSUL_TOKEN"),
+				},
+				Tasks: &config.TaskConfigs{
+					{
+						Name:        config.String("name"),
+						Module:      config.String("path"),
+						ConsulToken: config.String("TEST_TASK_TOKEN"),
+					},
+				},
+			},
+			[]*driver.Task{newTestTask(t, driver.TaskConfig{
+				Name:    "name",
+				Enabled: true,
+				Env: map[string]string{
+					"CONSUL_HTTP_ADDR":  "my.consul.address",
+					"CONSUL_HTTP_TOKEN": "TEST_TASK_TOKEN",
+				},
+				Providers:          driver.TerraformProviderBlocks{},
+				ProviderInfo:       map[string]interface{}{},
+				Services:           []driver.Service{},
+				Module:             "path",
+				ApplyTargets:       map[string][]string{},
+				ExtraTemplates:     []string{},
+				Postconditions:     config.PostconditionConfigs{},
+				ComputedInputs:     map[string]string{},
+				DependsOn:          []string{},
+				SensitiveVariables: []string{},
+				Handlers:           config.HandlerConfigs{},
+				Condition:          config.EmptyConditionConfig(),
+				ModuleInputs:       *config.DefaultModuleInputConfigs(),
+				BufferPeriod: &driver.BufferPeriod{
+					Min: 5 * time.Second,
+					Max: 20 * time.Second,
+				},
+				WorkingDir: "sync-tasks/name",
+				// Enterprise
+				TFCWorkspace: *config.DefaultTerraformCloudWorkspaceConfig(),
+			})},
+		}, {
+			// Task backend replaces the default backend and the Consul
+			// environment is not used for the task
+			"task backend",
+			&config.Config{
+				Consul: &config.ConsulConfig{
+					Address: config.String("my.consul.address"),
+				},
+				Tasks: &config.TaskConfigs{
+					{
+						Name:   config.String("name"),
+						Module: config.String("path"),
+						Backend: map[string]interface{}{
+							"local": map[string]interface{}{"path": "state"},
+						},
+					},
+				},
+			},
+			[]*driver.Task{newTestTask(t, driver.TaskConfig{
+				Name:         "name",
+				Enabled:      true,
+				Env:          map[string]string{},
+				Providers:    driver.TerraformProviderBlocks{},
 				ProviderInfo: map[string]interface{}{},
-				Services:     []driver.Service{},
-				Module:       "path",
-				Condition:    config.EmptyConditionConfig(),
-				ModuleInputs: *config.DefaultModuleInputConfigs(),
+				Backend: map[string]interface{}{
+					"local": map[string]interface{}{"path": "state"},
+				},
+				Services:           []driver.Service{},
+				Module:             "path",
+				ApplyTargets:       map[string][]string{},
+				ExtraTemplates:     []string{},
+				Postconditions:     config.PostconditionConfigs{},
+				ComputedInputs:     map[string]string{},
+				DependsOn:          []string{},
+				SensitiveVariables: []string{},
+				Handlers:           config.HandlerConfigs{},
+				Condition:          config.EmptyConditionConfig(),
+				ModuleInputs:       *config.DefaultModuleInputConfigs(),
 				BufferPeriod: &driver.BufferPeriod{
 					Min: 5 * time.Second,
 					Max: 20 * time.Second,
@@ -374,6 +600,354 @@ func TestNewDriverTask(t *testing.T) {
 	}
 }
 
+func Test_getDatacenterProviders(t *testing.T) {
+	t.Parallel()
+
+	providers := driver.NewTerraformProviderBlocks([]hcltmpl.NamedBlock{
+		hcltmpl.NewNamedBlock(map[string]interface{}{"panos": map[string]interface{}{
+			"hostname": "default",
+			"username": "admin",
+		}}),
+		hcltmpl.NewNamedBlock(map[string]interface{}{"panos": map[string]interface{}{
+			"alias":    "dc2",
+			"hostname": "dc2-firewall",
+		}}),
+	})
+	overrides := map[string]map[string]interface{}{
+		"panos": {"username": "cts"},
+	}
+
+	dcProviders := getDatacenterProviders(providers, []string{"panos", "other"},
+		[]string{"dc1", "dc2"}, overrides)
+	require.Len(t, dcProviders, 4)
+
+	ids := make([]string, len(dcProviders))
+	for i, p := range dcProviders {
+		ids[i] = p.ID()
+	}
+	assert.Equal(t, []string{"panos.dc1", "panos.dc2", "other.dc1", "other.dc2"}, ids)
+
+	// the provider block without an alias is used for dc1
+	dc1 := dcProviders[0].ProviderBlock().Variables
+	assert.Equal(t, "default", dc1["hostname"].AsString())
+	assert.Equal(t, "cts", dc1["username"].AsString())
+
+	// the provider block with the datacenter as the alias is used for dc2
+	dc2 := dcProviders[1].ProviderBlock().Variables
+	assert.Equal(t, "dc2-firewall", dc2["hostname"].AsString())
+	assert.Equal(t, "cts", dc2["username"].AsString())
+
+	// the default provider block is used for providers not configured
+	other := dcProviders[2].ProviderBlock().Variables
+	assert.Len(t, other, 1)
+}
+
+func Test_buildTaskEnv_NetworkMirror(t *testing.T) {
+	t.Parallel()
+
+	conf := config.DefaultConfig()
+	conf.Driver.Terraform = &config.TerraformConfig{
+		Path:          config.String("/opt/cts"),
+		NetworkMirror: config.String("https://mirror.internal/providers"),
+	}
+	require.NoError(t, conf.Finalize())
+
+	env := buildTaskEnv(conf, nil, "", map[string]string{"CUSTOM": "value"})
+	assert.Equal(t, filepath.Join("/opt/cts", config.TerraformCLIConfigFilename),
+		env["TF_CLI_CONFIG_FILE"])
+	assert.Equal(t, "value", env["CUSTOM"])
+}
+
+func Test_newSandboxConfig(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		conf     *config.TerraformSandboxConfig
+		expected *client.SandboxConfig
+	}{
+		{
+			"nil",
+			nil,
+			nil,
+		},
+		{
+			"disabled",
+			&config.TerraformSandboxConfig{Enabled: config.Bool(false)},
+			nil,
+		},
+		{
+			"enabled",
+			&config.TerraformSandboxConfig{
+				UID:               config.Int(1001),
+				EnvAllowlist:      []string{"PATH"},
+				ConfineWorkingDir: config.Bool(true),
+			},
+			&client.SandboxConfig{
+				UID:               1001,
+				GID:               -1,
+				EnvAllowlist:      []string{"PATH"},
+				ConfineWorkingDir: true,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.conf.Finalize()
+			assert.Equal(t, tc.expected, newSandboxConfig(tc.conf))
+		})
+	}
+}
+
+func Test_newPluginCache(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		conf     *config.TerraformPluginCacheConfig
+		expected *driver.PluginCache
+	}{
+		{
+			"nil",
+			nil,
+			nil,
+		},
+		{
+			"disabled",
+			&config.TerraformPluginCacheConfig{Enabled: config.Bool(false)},
+			nil,
+		},
+		{
+			"shared",
+			&config.TerraformPluginCacheConfig{Dir: config.String("/cache")},
+			&driver.PluginCache{
+				Dir:    "/cache",
+				Shared: true,
+			},
+		},
+		{
+			"per task",
+			&config.TerraformPluginCacheConfig{
+				Dir:     config.String("/cache"),
+				PerTask: config.Bool(true),
+			},
+			&driver.PluginCache{
+				Dir:    filepath.Join("/cache", "web"),
+				Shared: false,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.conf.Finalize("/opt/cts")
+			assert.Equal(t, tc.expected, newPluginCache(tc.conf, "web"))
+		})
+	}
+}
+
+func Test_newAlertSilence(t *testing.T) {
+	t.Parallel()
+
+	conf := &config.AlertmanagerSilenceConfig{
+		Duration: config.TimeDuration(10 * time.Minute),
+		Matchers: &config.AlertmanagerMatcherConfigs{
+			{Name: config.String("service"), Value: config.String("api")},
+			{Name: config.String("env"), Value: config.String("dev.*"),
+				Regex: config.Bool(true), Equal: config.Bool(false)},
+		},
+	}
+	conf.Finalize()
+
+	assert.Equal(t, &driver.AlertSilence{
+		Duration: 10 * time.Minute,
+		Matchers: []alertmanager.Matcher{
+			{Name: "service", Value: "api", IsEqual: true},
+			{Name: "env", Value: "dev.*", IsRegex: true, IsEqual: false},
+		},
+	}, newAlertSilence(conf))
+}
+
+func Test_newSilencer(t *testing.T) {
+	t.Parallel()
+
+	silenced, err := driver.NewTask(driver.TaskConfig{
+		Name:         "task",
+		AlertSilence: &driver.AlertSilence{Duration: time.Minute},
+	})
+	require.NoError(t, err)
+	notSilenced, err := driver.NewTask(driver.TaskConfig{Name: "task"})
+	require.NoError(t, err)
+
+	enabled := &config.Config{Alertmanager: &config.AlertmanagerConfig{
+		Address: config.String("http://localhost:9093"),
+	}}
+	require.NoError(t, enabled.Finalize())
+	disabled := &config.Config{}
+	require.NoError(t, disabled.Finalize())
+
+	s, err := newSilencer(enabled, silenced)
+	require.NoError(t, err)
+	assert.NotNil(t, s)
+
+	s, err = newSilencer(enabled, notSilenced)
+	require.NoError(t, err)
+	assert.Nil(t, s)
+
+	s, err = newSilencer(disabled, silenced)
+	require.NoError(t, err)
+	assert.Nil(t, s)
+}
+
+func Test_newTaskEnvironments(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		names    []string
+		conf     *config.TaskEnvironmentConfigs
+		expected []driver.TaskEnvironment
+	}{
+		{
+			"nil",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"empty",
+			[]string{},
+			&config.TaskEnvironmentConfigs{},
+			nil,
+		},
+		{
+			"environments",
+			[]string{"staging", "prod"},
+			&config.TaskEnvironmentConfigs{
+				{
+					Name:       config.String("prod"),
+					Workspace:  config.String("web-production"),
+					BackendKey: config.String("cts/prod"),
+				},
+			},
+			[]driver.TaskEnvironment{
+				{
+					Name:      "staging",
+					Variables: map[string]string{},
+				},
+				{
+					Name:       "prod",
+					Workspace:  "web-production",
+					Variables:  map[string]string{},
+					BackendKey: "cts/prod",
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, tc.conf.Finalize(tc.names))
+			assert.Equal(t, tc.expected, newTaskEnvironments(tc.conf))
+		})
+	}
+}
+
+func Test_driverFactory_loadConsulToken(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("file-token\n"), 0600))
+	emptyFile := filepath.Join(dir, "empty")
+	require.NoError(t, os.WriteFile(emptyFile, []byte(""), 0600))
+
+	cases := []struct {
+		name      string
+		token     string
+		tokenFile string
+		expected  string
+		expectErr bool
+	}{
+		{
+			name: "no token",
+		},
+		{
+			name:     "token",
+			token:    "task-token",
+			expected: "task-token",
+		},
+		{
+			name:      "token file",
+			tokenFile: tokenFile,
+			expected:  "file-token",
+		},
+		{
+			name:      "empty token file",
+			tokenFile: emptyFile,
+			expectErr: true,
+		},
+		{
+			name:      "missing token file",
+			tokenFile: filepath.Join(dir, "missing"),
+			expectErr: true,
+		},
+	}
+
+	f := &driverFactory{logger: logging.NewNullLogger()}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := f.loadConsulToken(context.Background(), config.TaskConfig{
+				Name:            config.String("task"),
+				ConsulToken:     config.String(tc.token),
+				ConsulTokenFile: config.String(tc.tokenFile),
+			})
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func Test_driverFactory_taskWatcher(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default watcher", func(t *testing.T) {
+		w := new(mocksTmpl.Watcher)
+		f := &driverFactory{watcher: w}
+
+		actual, err := f.taskWatcher("")
+		require.NoError(t, err)
+		assert.Equal(t, w, actual)
+
+		_, err = f.taskWatcher("token")
+		assert.Error(t, err, "mock watcher does not support tokens")
+	})
+
+	t.Run("token watcher", func(t *testing.T) {
+		w := new(mocksTmpl.Watcher)
+		tokenW := new(mocksTmpl.Watcher)
+		ws := &watcherSet{
+			Watcher: w,
+			newWatcher: func(token string) (templates.Watcher, error) {
+				assert.Equal(t, "token", token)
+				return tokenW, nil
+			},
+			scoped:  make(map[string]templates.Watcher),
+			addedCh: make(chan struct{}),
+		}
+		f := &driverFactory{watcher: ws}
+
+		actual, err := f.taskWatcher("token")
+		require.NoError(t, err)
+		assert.Equal(t, tokenW, actual)
+	})
+}
+
 func newTestDriverTasks(conf *config.Config, providerConfigs driver.TerraformProviderBlocks) ([]*driver.Task, error) {
 	if conf == nil {
 		return []*driver.Task{}, nil
@@ -382,7 +956,7 @@ func newTestDriverTasks(conf *config.Config, providerConfigs driver.TerraformPro
 	tasks := make([]*driver.Task, len(*conf.Tasks))
 	for i, t := range *conf.Tasks {
 		var err error
-		tasks[i], err = newDriverTask(conf, t, providerConfigs)
+		tasks[i], err = newDriverTask(conf, t, providerConfigs, config.StringVal(t.ConsulToken))
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,219 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	consulapi "github.com/hashicorp/consul/api"
+)
+
+// gateRetryWait is the time to wait before checking a gate again after an
+// error looking up its Consul KV key
+const gateRetryWait = 5 * time.Second
+
+// gateKV looks up the Consul KV keys of task gates
+type gateKV interface {
+	Get(key string, q *consulapi.QueryOptions) (*consulapi.KVPair, *consulapi.QueryMeta, error)
+}
+
+// gateOpen returns whether the gate is open and the index of the key. Blocks
+// until the index changes from waitIndex if waitIndex is not 0. A key that
+// does not exist has an empty value.
+func gateOpen(ctx context.Context, kv gateKV, gate driver.KVGate, waitIndex uint64) (
+	bool, uint64, error) {
+
+	qOpts := (&consulapi.QueryOptions{
+		Datacenter: gate.Datacenter,
+		Namespace:  gate.Namespace,
+		WaitIndex:  waitIndex,
+	}).WithContext(ctx)
+	pair, meta, err := kv.Get(gate.Path, qOpts)
+	if err != nil {
+		return false, 0, err
+	}
+
+	var value string
+	if pair != nil {
+		value = string(pair.Value)
+	}
+	return value == gate.Equals, meta.LastIndex, nil
+}
+
+// closedGate returns the first gate that is closed. Returns false if all of
+// the gates are open. A gate that is unable to be looked up is closed.
+func closedGate(ctx context.Context, kv gateKV, gates []driver.KVGate) (
+	driver.KVGate, bool, error) {
+
+	for _, g := range gates {
+		open, _, err := gateOpen(ctx, kv, g, 0)
+		if err != nil {
+			return g, true, err
+		}
+		if !open {
+			return g, true, nil
+		}
+	}
+	return driver.KVGate{}, false, nil
+}
+
+// taskGates tracks the runs of tasks that are queued while a gate of the
+// task is closed. The Consul KV keys of the gates are watched with blocking
+// queries, and the queued run is called once all of the gates are open.
+type taskGates struct {
+	mu *sync.Mutex
+
+	kv      func() gateKV
+	pending map[string]*gateWait // taskname => queued run
+}
+
+// gateWait is a run that is queued until the gates of a task are open
+type gateWait struct {
+	cancel context.CancelFunc
+}
+
+// newTaskGates returns a new tracker for task gates that looks up the gates
+// with the Consul KV client
+func newTaskGates(kv func() gateKV) *taskGates {
+	return &taskGates{
+		mu:      &sync.Mutex{},
+		kv:      kv,
+		pending: make(map[string]*gateWait),
+	}
+}
+
+// Closed returns the first closed gate of the task. Returns false if all of
+// the gates are open.
+func (g *taskGates) Closed(ctx context.Context, gates []driver.KVGate) (
+	driver.KVGate, bool, error) {
+
+	if len(gates) == 0 {
+		return driver.KVGate{}, false, nil
+	}
+	return closedGate(ctx, g.kv(), gates)
+}
+
+// Wait calls f for a task once all of the gates are open. Only one run can be
+// queued per task at a time. Returns false if a run is already queued for the
+// task, in which case f is not called.
+func (g *taskGates) Wait(ctx context.Context, logger logging.Logger,
+	taskName string, gates []driver.KVGate, f func()) bool {
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.pending[taskName]; ok {
+		return false
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	w := &gateWait{cancel: cancel}
+	g.pending[taskName] = w
+
+	go func() {
+		err := g.waitOpen(ctx, logger, gates)
+
+		g.mu.Lock()
+		if g.pending[taskName] == w {
+			delete(g.pending, taskName)
+		}
+		g.mu.Unlock()
+		cancel()
+
+		if err == nil {
+			f()
+		}
+	}()
+	return true
+}
+
+// waitOpen blocks until all of the gates are open at the same time or the
+// context is canceled
+func (g *taskGates) waitOpen(ctx context.Context, logger logging.Logger,
+	gates []driver.KVGate) error {
+
+	kv := g.kv()
+	for {
+		gate, closed, err := closedGate(ctx, kv, gates)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !closed {
+			return nil
+		}
+		if err != nil {
+			logger.Warn("error checking task gate, retrying", "path", gate.Path,
+				"retry_in", gateRetryWait, "error", err)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(gateRetryWait):
+			}
+			continue
+		}
+
+		if err := waitGateOpen(ctx, logger, kv, gate); err != nil {
+			return err
+		}
+	}
+}
+
+// waitGateOpen watches the key of the gate with blocking queries until the
+// gate is open or the context is canceled
+func waitGateOpen(ctx context.Context, logger logging.Logger, kv gateKV,
+	gate driver.KVGate) error {
+
+	var index uint64
+	for {
+		open, newIndex, err := gateOpen(ctx, kv, gate, index)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			logger.Warn("error watching task gate, retrying", "path", gate.Path,
+				"retry_in", gateRetryWait, "error", err)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(gateRetryWait):
+			}
+			index = 0
+			continue
+		}
+		if open {
+			return nil
+		}
+
+		// reset the index if it goes backwards, e.g. after a Consul snapshot
+		// restore
+		if newIndex < index {
+			newIndex = 0
+		}
+		index = newIndex
+	}
+}
+
+// Queued returns whether a run is queued for a task until its gates open
+func (g *taskGates) Queued(taskName string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	_, ok := g.pending[taskName]
+	return ok
+}
+
+// Delete stops waiting for the gates of a task and removes the queued run
+func (g *taskGates) Delete(taskName string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if w, ok := g.pending[taskName]; ok {
+		w.cancel()
+		delete(g.pending, taskName)
+	}
+}
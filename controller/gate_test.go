@@ -0,0 +1,293 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	mocksD "github.com/hashicorp/consul-terraform-sync/mocks/driver"
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testGateKV is an in-memory Consul KV for gates. Blocking queries wait until
+// a key is put.
+type testGateKV struct {
+	mu      sync.Mutex
+	values  map[string]string
+	index   uint64
+	err     error
+	changed chan struct{}
+}
+
+func newTestGateKV(values map[string]string) *testGateKV {
+	return &testGateKV{
+		values:  values,
+		index:   1,
+		changed: make(chan struct{}),
+	}
+}
+
+func (kv *testGateKV) Get(key string, q *consulapi.QueryOptions) (
+	*consulapi.KVPair, *consulapi.QueryMeta, error) {
+
+	kv.mu.Lock()
+	index, changed := kv.index, kv.changed
+	kv.mu.Unlock()
+
+	if q.WaitIndex != 0 && q.WaitIndex >= index {
+		select {
+		case <-changed:
+		case <-q.Context().Done():
+			return nil, nil, q.Context().Err()
+		}
+	}
+
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	if kv.err != nil {
+		return nil, nil, kv.err
+	}
+	meta := &consulapi.QueryMeta{LastIndex: kv.index}
+	v, ok := kv.values[key]
+	if !ok {
+		return nil, meta, nil
+	}
+	return &consulapi.KVPair{Key: key, Value: []byte(v)}, meta, nil
+}
+
+func (kv *testGateKV) put(key, value string) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	kv.values[key] = value
+	kv.index++
+	close(kv.changed)
+	kv.changed = make(chan struct{})
+}
+
+func TestGateOpen(t *testing.T) {
+	t.Parallel()
+
+	kv := newTestGateKV(map[string]string{"flags/freeze": "false"})
+	ctx := context.Background()
+
+	cases := []struct {
+		name     string
+		gate     driver.KVGate
+		expected bool
+	}{
+		{"equal value", driver.KVGate{Path: "flags/freeze", Equals: "false"}, true},
+		{"different value", driver.KVGate{Path: "flags/freeze", Equals: "true"}, false},
+		{"missing key", driver.KVGate{Path: "flags/other", Equals: "false"}, false},
+		{"missing key empty value", driver.KVGate{Path: "flags/other", Equals: ""}, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			open, index, err := gateOpen(ctx, kv, tc.gate, 0)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, open)
+			assert.Equal(t, uint64(1), index)
+		})
+	}
+
+	t.Run("error", func(t *testing.T) {
+		errKV := newTestGateKV(map[string]string{})
+		errKV.err = errors.New("connection refused")
+		gate := driver.KVGate{Path: "flags/freeze"}
+		_, closed, err := closedGate(ctx, errKV, []driver.KVGate{gate})
+		assert.Error(t, err)
+		assert.True(t, closed)
+	})
+}
+
+func TestTaskGates_Wait(t *testing.T) {
+	t.Parallel()
+
+	kv := newTestGateKV(map[string]string{
+		"flags/freeze": "true",
+		"flags/lb":     "false",
+	})
+	gates := newTaskGates(func() gateKV { return kv })
+	taskGates := []driver.KVGate{
+		{Path: "flags/freeze", Equals: "false"},
+		{Path: "flags/lb", Equals: "false"},
+	}
+
+	ran := make(chan struct{}, 2)
+	f := func() { ran <- struct{}{} }
+	ctx := context.Background()
+	logger := logging.NewNullLogger()
+
+	assert.False(t, gates.Queued("task"))
+	assert.True(t, gates.Wait(ctx, logger, "task", taskGates, f))
+	assert.False(t, gates.Wait(ctx, logger, "task", taskGates, f),
+		"only one run is queued per task")
+	assert.True(t, gates.Queued("task"))
+
+	// the run is queued while a gate is closed
+	kv.put("flags/freeze", "maybe")
+	select {
+	case <-ran:
+		t.Fatal("unexpected run while gate is closed")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	kv.put("flags/freeze", "false")
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("expected run once the gates are open")
+	}
+
+	// a new run can be queued once the previous run is called
+	assert.Eventually(t, func() bool {
+		gates.mu.Lock()
+		defer gates.mu.Unlock()
+		return len(gates.pending) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestTaskGates_Delete(t *testing.T) {
+	t.Parallel()
+
+	kv := newTestGateKV(map[string]string{"flags/freeze": "true"})
+	gates := newTaskGates(func() gateKV { return kv })
+	taskGates := []driver.KVGate{{Path: "flags/freeze", Equals: "false"}}
+
+	ran := make(chan struct{}, 1)
+	assert.True(t, gates.Wait(context.Background(), logging.NewNullLogger(),
+		"task", taskGates, func() { ran <- struct{}{} }))
+	gates.Delete("task")
+
+	kv.put("flags/freeze", "false")
+	select {
+	case <-ran:
+		t.Fatal("unexpected run after the queued run is deleted")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func Test_TasksManager_TaskSuppressByGate(t *testing.T) {
+	t.Parallel()
+
+	newGateTask := func(t *testing.T, name string) *driver.Task {
+		task, err := driver.NewTask(driver.TaskConfig{
+			Name:    name,
+			Enabled: true,
+			Gates:   []driver.KVGate{{Path: "flags/freeze", Equals: "false"}},
+		})
+		require.NoError(t, err)
+		return task
+	}
+
+	t.Run("no gates", func(t *testing.T) {
+		d := new(mocksD.Driver)
+		d.On("Task").Return(enabledTestTask(t, "task_a"))
+		d.On("TemplateIDs").Return(nil)
+
+		tm := newTestTasksManager()
+		tm.drivers.Add("task_a", d)
+
+		assert.False(t, tm.TaskSuppressByGate(context.Background(), "task_a"))
+	})
+
+	t.Run("gate open", func(t *testing.T) {
+		d := new(mocksD.Driver)
+		d.On("Task").Return(newGateTask(t, "task_a"))
+		d.On("TemplateIDs").Return(nil)
+
+		tm := newTestTasksManager()
+		kv := newTestGateKV(map[string]string{"flags/freeze": "false"})
+		tm.gateRuns = newTaskGates(func() gateKV { return kv })
+		tm.drivers.Add("task_a", d)
+
+		assert.False(t, tm.TaskSuppressByGate(context.Background(), "task_a"))
+	})
+
+	t.Run("queued while gate is closed", func(t *testing.T) {
+		d := new(mocksD.Driver)
+		d.On("Task").Return(newGateTask(t, "task_a"))
+		d.On("TemplateIDs").Return(nil)
+		rendered := make(chan struct{}, 1)
+		d.On("RenderTemplate", mock.Anything).Return(false, nil).
+			Run(func(mock.Arguments) { rendered <- struct{}{} })
+
+		tm := newTestTasksManager()
+		kv := newTestGateKV(map[string]string{"flags/freeze": "true"})
+		tm.gateRuns = newTaskGates(func() gateKV { return kv })
+		tm.drivers.Add("task_a", d)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// Repeated triggers are suppressed and only recorded once
+		assert.True(t, tm.TaskSuppressByGate(ctx, "task_a"))
+		assert.True(t, tm.TaskSuppressByGate(ctx, "task_a"))
+
+		events := tm.state.GetTaskEvents("task_a")["task_a"]
+		require.Len(t, events, 1)
+		assert.True(t, events[0].Suppressed)
+		d.AssertNotCalled(t, "RenderTemplate", mock.Anything)
+
+		// the task runs once the flag clears
+		kv.put("flags/freeze", "false")
+		select {
+		case <-rendered:
+		case <-time.After(time.Second):
+			t.Fatal("expected task to run once the gate is open")
+		}
+	})
+
+	t.Run("queued run runs dependencies first", func(t *testing.T) {
+		rendered := make(chan string, 2)
+		depD := new(mocksD.Driver)
+		depD.On("Task").Return(enabledTestTask(t, "firewall"))
+		depD.On("TemplateIDs").Return(nil)
+		depD.On("RenderTemplate", mock.Anything).Return(false, nil).
+			Run(func(mock.Arguments) { rendered <- "firewall" })
+
+		task, err := driver.NewTask(driver.TaskConfig{
+			Name:      "lb_pools",
+			Enabled:   true,
+			Gates:     []driver.KVGate{{Path: "flags/freeze", Equals: "false"}},
+			DependsOn: []string{"firewall"},
+		})
+		require.NoError(t, err)
+		d := new(mocksD.Driver)
+		d.On("Task").Return(task)
+		d.On("TemplateIDs").Return(nil)
+		d.On("RenderTemplate", mock.Anything).Return(false, nil).
+			Run(func(mock.Arguments) { rendered <- "lb_pools" })
+
+		tm := newTestTasksManager()
+		kv := newTestGateKV(map[string]string{"flags/freeze": "true"})
+		tm.gateRuns = newTaskGates(func() gateKV { return kv })
+		tm.drivers.Add("firewall", depD)
+		tm.drivers.Add("lb_pools", d)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		assert.True(t, tm.TaskSuppressByGate(ctx, "lb_pools"))
+		kv.put("flags/freeze", "false")
+
+		var order []string
+		for len(order) < 2 {
+			select {
+			case name := <-rendered:
+				order = append(order, name)
+			case <-time.After(time.Second):
+				t.Fatal("expected task and its dependency to run once the gate is open")
+			}
+		}
+		assert.Equal(t, []string{"firewall", "lb_pools"}, order)
+	})
+}
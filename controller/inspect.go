@@ -35,7 +35,7 @@ func NewInspect(conf *config.Config) (*Inspect, error) {
 	s := state.NewInMemoryStore(conf)
 
 	logger.Info("initializing Consul client and testing connection")
-	watcher, err := newWatcher(conf, client.ConsulDefaultMaxRetry)
+	watcher, err := newWatcherSet(conf, client.ConsulDefaultMaxRetry)
 	if err != nil {
 		return nil, err
 	}
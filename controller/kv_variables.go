@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// variablesKV lists the Consul KV key-values of task variables
+type variablesKV interface {
+	List(prefix string, q *consulapi.QueryOptions) (consulapi.KVPairs, *consulapi.QueryMeta, error)
+}
+
+// loadKVVariables loads the key-values under the Consul KV prefixes as
+// variables. The name of a variable is the key relative to its prefix, and
+// the value is a string encoded as an HCL expression to be parsed with the
+// other variables of the task. Keys nested further under a prefix are
+// ignored. Variables of later prefixes override variables of earlier ones.
+func loadKVVariables(ctx context.Context, kv variablesKV, prefixes []string) (
+	map[string]string, error) {
+
+	vars := make(map[string]string)
+	for _, prefix := range prefixes {
+		qOpts := (&consulapi.QueryOptions{}).WithContext(ctx)
+		pairs, _, err := kv.List(prefix, qOpts)
+		if err != nil {
+			return nil, fmt.Errorf("error listing Consul KV prefix %q: %s",
+				prefix, err)
+		}
+
+		for _, pair := range pairs {
+			name := strings.TrimPrefix(strings.TrimPrefix(pair.Key, prefix), "/")
+			if name == "" || strings.Contains(name, "/") {
+				continue
+			}
+			value := hclwrite.TokensForValue(cty.StringVal(string(pair.Value)))
+			vars[name] = string(value.Bytes())
+		}
+	}
+	return vars, nil
+}
@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testVariablesKV is an in-memory Consul KV for variables of tasks
+type testVariablesKV struct {
+	pairs consulapi.KVPairs
+	err   error
+}
+
+func (kv *testVariablesKV) List(prefix string, q *consulapi.QueryOptions) (
+	consulapi.KVPairs, *consulapi.QueryMeta, error) {
+
+	if kv.err != nil {
+		return nil, nil, kv.err
+	}
+
+	var pairs consulapi.KVPairs
+	for _, p := range kv.pairs {
+		if strings.HasPrefix(p.Key, prefix) {
+			pairs = append(pairs, p)
+		}
+	}
+	return pairs, &consulapi.QueryMeta{}, nil
+}
+
+func TestLoadKVVariables(t *testing.T) {
+	t.Parallel()
+
+	kv := &testVariablesKV{
+		pairs: consulapi.KVPairs{
+			{Key: "config/global/region", Value: []byte("us-east-1")},
+			{Key: "config/global/owner", Value: []byte("neteng")},
+			{Key: "config/tasks/web/", Value: nil},
+			{Key: "config/tasks/web/region", Value: []byte("us-west-2")},
+			{Key: "config/tasks/web/greeting", Value: []byte(`say "hi" ${name}`)},
+			{Key: "config/tasks/web/nested/key", Value: []byte("ignored")},
+		},
+	}
+
+	t.Run("prefixes", func(t *testing.T) {
+		vars, err := loadKVVariables(context.Background(), kv,
+			[]string{"config/global/", "config/tasks/web"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"region":   `"us-west-2"`,
+			"owner":    `"neteng"`,
+			"greeting": `"say \"hi\" $${name}"`,
+		}, vars)
+	})
+
+	t.Run("no keys", func(t *testing.T) {
+		vars, err := loadKVVariables(context.Background(), kv,
+			[]string{"config/tasks/db/"})
+		require.NoError(t, err)
+		assert.Empty(t, vars)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := loadKVVariables(context.Background(),
+			&testVariablesKV{err: errors.New("unavailable")},
+			[]string{"config/tasks/web/"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"time"
+
+	"github.com/hashicorp/cronexpr"
+)
+
+// maxMaintenanceWindowStarts limits the number of overlapping windows that
+// are combined to find the end of a maintenance window
+const maxMaintenanceWindowStarts = 1000
+
+// maintenanceWindowRemaining returns the time remaining in the maintenance
+// window at the time now. Each window starts at the times of the cron
+// expression and lasts for the duration. Overlapping windows are combined
+// into a single window. Returns 0 if now is not within a window.
+func maintenanceWindowRemaining(expr *cronexpr.Expression, duration time.Duration,
+	now time.Time) time.Duration {
+
+	// the earliest start of a window that includes now is after now-duration
+	start := expr.Next(now.Add(-duration))
+	if start.IsZero() || start.After(now) {
+		return 0
+	}
+
+	end := start.Add(duration)
+	for i := 0; i < maxMaintenanceWindowStarts; i++ {
+		next := expr.Next(start)
+		if next.IsZero() || next.After(end) {
+			break
+		}
+		start = next
+		if nextEnd := next.Add(duration); nextEnd.After(end) {
+			end = nextEnd
+		}
+	}
+
+	return end.Sub(now)
+}
@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/cronexpr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceWindowRemaining(t *testing.T) {
+	t.Parallel()
+
+	// Saturday, January 6 2024
+	saturday := func(hour, min int) time.Time {
+		return time.Date(2024, time.January, 6, hour, min, 0, 0, time.UTC)
+	}
+
+	cases := []struct {
+		name     string
+		cron     string
+		duration time.Duration
+		now      time.Time
+		expected time.Duration
+	}{
+		{
+			"before window",
+			"0 22 * * 6",
+			4 * time.Hour,
+			saturday(21, 0),
+			0,
+		},
+		{
+			"start of window",
+			"0 22 * * 6",
+			4 * time.Hour,
+			saturday(22, 0),
+			4 * time.Hour,
+		},
+		{
+			"within window",
+			"0 22 * * 6",
+			4 * time.Hour,
+			saturday(23, 30),
+			150 * time.Minute,
+		},
+		{
+			"end of window",
+			"0 22 * * 6",
+			4 * time.Hour,
+			saturday(22, 0).Add(4 * time.Hour),
+			0,
+		},
+		{
+			"overlapping windows are combined",
+			"0 * * * *",
+			90 * time.Minute,
+			saturday(10, 15),
+			// windows start every hour, so the window never ends. The
+			// combined window is limited by maxMaintenanceWindowStarts
+			time.Duration(maxMaintenanceWindowStarts)*time.Hour + 15*time.Minute,
+		},
+		{
+			"adjacent windows",
+			"0 */2 * * *",
+			time.Hour,
+			saturday(10, 30),
+			30 * time.Minute,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			expr := cronexpr.MustParse(tc.cron)
+			actual := maintenanceWindowRemaining(expr, tc.duration, tc.now)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
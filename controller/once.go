@@ -5,6 +5,9 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/hashicorp/consul-terraform-sync/client"
 	"github.com/hashicorp/consul-terraform-sync/config"
@@ -26,8 +29,55 @@ type Once struct {
 	watcher      templates.Watcher
 	monitor      *ConditionMonitor
 
-	// When true, does not handle errors beyond logging. Otherwise fails fast.
-	allowFail bool
+	// failurePolicy configures how errors of tasks are handled. Fails fast
+	// by default, otherwise does not handle errors beyond logging.
+	failurePolicy string
+
+	// failed is the tasks that errored when the failure policy allows tasks
+	// to fail
+	failed []onceFailure
+
+	// errs are the errors of the tasks that errored when run once by the
+	// task name, regardless of the failure policy
+	mu   sync.Mutex
+	errs map[string]error
+}
+
+// onceFailure is a task that errored when it was run once
+type onceFailure struct {
+	task config.TaskConfig
+
+	// added is whether the task was added to CTS, i.e. the task errored
+	// while running instead of while being created
+	added bool
+}
+
+// OnceTasksFailedError is the error returned by the Once controller when
+// tasks errored and the failure policy allowed the remaining tasks to run
+type OnceTasksFailedError struct {
+	Tasks []string
+}
+
+func (e *OnceTasksFailedError) Error() string {
+	return fmt.Sprintf("%d task(s) errored while running once: %s",
+		len(e.Tasks), strings.Join(e.Tasks, ", "))
+}
+
+// Statuses of a task after the tasks are run once
+const (
+	OnceTaskStatusSuccess = "success"
+	OnceTaskStatusErrored = "errored"
+	OnceTaskStatusSkipped = "skipped"
+)
+
+// OnceTaskStatus is the outcome of a task that was run once. A task is
+// skipped when it was not run, e.g. the task is disabled, has no changes, or
+// an earlier task errored with the fail-fast failure policy.
+type OnceTaskStatus struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	EventID string `json:"event_id,omitempty"`
+	Error   string `json:"error,omitempty"`
 }
 
 // NewOnce configures and initializes a new Once controller
@@ -38,7 +88,7 @@ func NewOnce(conf *config.Config) (*Once, error) {
 	s := state.NewInMemoryStore(conf)
 
 	logger.Info("initializing Consul client and testing connection")
-	watcher, err := newWatcher(conf, client.ConsulDefaultMaxRetry)
+	watcher, err := newWatcherSet(conf, client.ConsulDefaultMaxRetry)
 	if err != nil {
 		return nil, err
 	}
@@ -48,22 +98,55 @@ func NewOnce(conf *config.Config) (*Once, error) {
 		return nil, err
 	}
 
+	if config.StringVal(conf.OnceModeFailurePolicy) == config.OnceModeFailurePolicyBestEffort {
+		logger.Warn("tasks that error are not retried in the background in once "+
+			"mode", "once_mode_failure_policy", config.OnceModeFailurePolicyBestEffort)
+	}
+
 	return &Once{
 		logger:       logger,
 		state:        s,
 		tasksManager: tm,
 		watcher:      watcher,
 		monitor:      NewConditionMonitor(tm, watcher),
-		allowFail:    false,
+
+		failurePolicy: config.StringVal(conf.OnceModeFailurePolicy),
 	}, nil
 }
 
+// SetChangedOnly configures the controller to only run tasks that changed
+// since their last successful run. Tasks are compared to the state saved in
+// their working directory by the last successful run.
+func (ctrl *Once) SetChangedOnly(changedOnly bool) {
+	ctrl.tasksManager.setChangedOnly(changedOnly)
+}
+
 // Init initializes the controller before it can be run.
 func (ctrl *Once) Init(ctx context.Context) error {
 	return ctrl.tasksManager.Init(ctx)
 }
 
+// Run runs all tasks once. When the failure policy allows tasks to fail, all
+// tasks are run and an OnceTasksFailedError is returned afterwards if any task
+// errored.
 func (ctrl *Once) Run(ctx context.Context) error {
+	if err := ctrl.run(ctx); err != nil {
+		return err
+	}
+
+	if len(ctrl.failed) == 0 {
+		return nil
+	}
+	names := make([]string, len(ctrl.failed))
+	for i, f := range ctrl.failed {
+		names[i] = config.StringVal(f.task.Name)
+	}
+	return &OnceTasksFailedError{Tasks: names}
+}
+
+// run runs all tasks once. Errors of tasks that the failure policy allows to
+// fail are tracked instead of returned.
+func (ctrl *Once) run(ctx context.Context) error {
 	// Check if tasks are configured, if none are configured
 	// exit early
 	tasks := ctrl.state.GetAllTasks()
@@ -112,7 +195,9 @@ func (ctrl *Once) Run(ctx context.Context) error {
 }
 
 func (ctrl *Once) onceConsecutive(ctx context.Context) error {
-	tasks := ctrl.state.GetAllTasks()
+	// run tasks by priority and after the tasks that they depend on
+	tasks := config.OrderByDependencies(
+		config.OrderByPriority(ctrl.state.GetAllTasks()))
 	for _, task := range tasks {
 		select {
 		case <-ctx.Done():
@@ -121,20 +206,30 @@ func (ctrl *Once) onceConsecutive(ctx context.Context) error {
 			taskName := *task.Name
 			ctrl.logger.Info("running task once", taskNameLogKey, taskName)
 
-			if ctrl.allowFail {
-				ctrl.tasksManager.TaskCreateAndRunAllowFail(ctx, *task)
+			if ctrl.allowFail() {
+				err := ctrl.tasksManager.TaskCreateAndRunAllowFail(ctx, *task)
+				if err != nil {
+					ctrl.setTaskError(taskName, err)
+					_, added := ctrl.tasksManager.drivers.Get(taskName)
+					ctrl.failed = append(ctrl.failed, onceFailure{
+						task:  *task,
+						added: added,
+					})
+				}
 				continue
 			}
 
 			if _, err := ctrl.tasksManager.TaskCreateAndRun(ctx, *task); err != nil {
+				ctrl.setTaskError(taskName, err)
 				return err
 			}
 			ctrl.logger.Info("task completed", taskNameLogKey, taskName)
 		}
 	}
 
-	if ctrl.allowFail {
-		ctrl.logger.Info("attempted to run all tasks once")
+	if ctrl.allowFail() {
+		ctrl.logger.Info("attempted to run all tasks once",
+			"failed_tasks", len(ctrl.failed))
 	} else {
 		ctrl.logger.Info("all tasks completed once")
 	}
@@ -142,6 +237,62 @@ func (ctrl *Once) onceConsecutive(ctx context.Context) error {
 	return nil
 }
 
+// setTaskError records the error of a task that errored when run once
+func (ctrl *Once) setTaskError(taskName string, err error) {
+	ctrl.mu.Lock()
+	defer ctrl.mu.Unlock()
+	if ctrl.errs == nil {
+		ctrl.errs = make(map[string]error)
+	}
+	ctrl.errs[taskName] = err
+}
+
+// TaskStatuses returns the outcome of each task after the tasks are run
+// once, in the order that the tasks are run. The event ID is the ID of the
+// latest event of the task.
+func (ctrl *Once) TaskStatuses() []OnceTaskStatus {
+	ctrl.mu.Lock()
+	defer ctrl.mu.Unlock()
+
+	tasks := config.OrderByDependencies(
+		config.OrderByPriority(ctrl.state.GetAllTasks()))
+	statuses := make([]OnceTaskStatus, len(tasks))
+	for i, task := range tasks {
+		name := config.StringVal(task.Name)
+		status := OnceTaskStatus{Name: name, Status: OnceTaskStatusSkipped}
+
+		// events are in reverse chronological order
+		if events := ctrl.state.GetTaskEvents(name)[name]; len(events) > 0 {
+			ev := events[0]
+			status.EventID = ev.ID
+			status.Status = OnceTaskStatusSuccess
+			if !ev.Success {
+				status.Status = OnceTaskStatusErrored
+			}
+			if ev.EventError != nil {
+				status.Error = ev.EventError.Message
+			}
+		}
+		if err, ok := ctrl.errs[name]; ok {
+			status.Status = OnceTaskStatusErrored
+			status.Error = err.Error()
+		}
+		statuses[i] = status
+	}
+	return statuses
+}
+
+// allowFail returns whether the failure policy allows tasks to fail without
+// exiting
+func (ctrl *Once) allowFail() bool {
+	switch ctrl.failurePolicy {
+	case config.OnceModeFailurePolicyContinue, config.OnceModeFailurePolicyBestEffort:
+		return true
+	default:
+		return false
+	}
+}
+
 func (ctrl *Once) Stop() {
 	ctrl.watcher.Stop()
 }
@@ -6,6 +6,7 @@ package controller
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -48,7 +49,7 @@ func Test_Once_Run_Terraform(t *testing.T) {
 				return onceMockDriver(task, nil)
 			}
 
-			mockDrivers, err := testOnce(t, tc.numTasks, driverConf, false, setupNewDriver)
+			_, mockDrivers, err := testOnce(t, tc.numTasks, driverConf, "", setupNewDriver)
 			require.NoError(t, err)
 			assert.Len(t, mockDrivers, tc.numTasks)
 
@@ -68,16 +69,24 @@ func Test_Once_Run_Terraform_errors(t *testing.T) {
 	expectedErr := errors.New("test error")
 
 	testCases := []struct {
-		name      string
-		allowFail bool
+		name          string
+		failurePolicy string
 	}{
 		{
-			"consecutive allow-fail",
-			true,
+			"consecutive continue",
+			config.OnceModeFailurePolicyContinue,
+		},
+		{
+			"consecutive best-effort",
+			config.OnceModeFailurePolicyBestEffort,
 		},
 		{
 			"consecutive fail-fast",
-			false,
+			config.OnceModeFailurePolicyFailFast,
+		},
+		{
+			"consecutive default",
+			"",
 		},
 	}
 
@@ -95,13 +104,21 @@ func Test_Once_Run_Terraform_errors(t *testing.T) {
 				return onceMockDriver(task, nil)
 			}
 
-			mockDrivers, err := testOnce(t, 5, driverConf, tc.allowFail, setupNewDriver)
+			ctrl, mockDrivers, err := testOnce(t, 5, driverConf, tc.failurePolicy, setupNewDriver)
 
-			if tc.allowFail {
-				require.NoError(t, err)
+			if ctrl.allowFail() {
+				// all tasks ran before the failed tasks are reported
+				var failedErr *OnceTasksFailedError
+				require.ErrorAs(t, err, &failedErr)
+				assert.Equal(t, []string{"task_03"}, failedErr.Tasks)
 
 				// all drivers should have been created even if 03 errored
 				assert.Len(t, mockDrivers, 5)
+
+				// the failed task is tracked to be retried
+				require.Len(t, ctrl.failed, 1)
+				assert.Equal(t, "task_03", *ctrl.failed[0].task.Name)
+				assert.True(t, ctrl.failed[0].added)
 			} else {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), expectedErr.Error(),
@@ -111,6 +128,24 @@ func Test_Once_Run_Terraform_errors(t *testing.T) {
 				assert.Len(t, mockDrivers, 3)
 			}
 
+			// the outcome of each task is reported
+			statuses := ctrl.TaskStatuses()
+			require.Len(t, statuses, 5)
+			for i, status := range statuses {
+				assert.Equal(t, fmt.Sprintf("task_%02d", i), status.Name)
+				switch {
+				case i < 3 || (i == 4 && ctrl.allowFail()):
+					assert.Equal(t, OnceTaskStatusSuccess, status.Status)
+					assert.NotEmpty(t, status.EventID)
+				case i == 3:
+					assert.Equal(t, OnceTaskStatusErrored, status.Status)
+					assert.Equal(t, expectedErr.Error(), status.Error)
+				default:
+					assert.Equal(t, OnceTaskStatusSkipped, status.Status)
+					assert.Empty(t, status.EventID)
+				}
+			}
+
 			for _, mockD := range mockDrivers {
 				mockD.AssertExpectations(t)
 			}
@@ -222,19 +257,19 @@ func Test_Once_onceConsecutive_context_canceled(t *testing.T) {
 	}
 }
 
-// testOnce test running once-mode. Returns the mocked drivers for the caller
-// to assert expectations
-func testOnce(t *testing.T, numTasks int, driverConf *config.DriverConfig, allowFail bool,
-	setupNewDriver func(*driver.Task) driver.Driver) ([]*mocksD.Driver, error) {
+// testOnce test running once-mode. Returns the controller and the mocked
+// drivers for the caller to assert expectations
+func testOnce(t *testing.T, numTasks int, driverConf *config.DriverConfig, failurePolicy string,
+	setupNewDriver func(*driver.Task) driver.Driver) (*Once, []*mocksD.Driver, error) {
 
 	conf := multipleTaskConfig(t, numTasks)
 	conf.Driver = driverConf
 	ss := state.NewInMemoryStore(conf)
 
 	ctrl := Once{
-		logger:    logging.NewNullLogger(),
-		state:     ss,
-		allowFail: allowFail,
+		logger:        logging.NewNullLogger(),
+		state:         ss,
+		failurePolicy: failurePolicy,
 	}
 
 	// Set up tasks manager
@@ -271,7 +306,7 @@ func testOnce(t *testing.T, numTasks int, driverConf *config.DriverConfig, allow
 		mockDrivers = append(mockDrivers, mockDriver)
 	}
 
-	return mockDrivers, err
+	return &ctrl, mockDrivers, err
 }
 
 func testOnceWatchDepErrors(t *testing.T, driverConf *config.DriverConfig) {
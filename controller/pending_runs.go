@@ -0,0 +1,79 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"sync"
+	"time"
+)
+
+// taskPendingRuns tracks the runs of tasks that are queued, waiting for the
+// active run of the task to complete.
+type taskPendingRuns struct {
+	mu *sync.Mutex
+
+	queued map[string][]time.Time // taskname => times the runs were queued
+}
+
+// newTaskPendingRuns returns a new tracker for pending task runs
+func newTaskPendingRuns() *taskPendingRuns {
+	return &taskPendingRuns{
+		mu:     &sync.Mutex{},
+		queued: make(map[string][]time.Time),
+	}
+}
+
+// Add records a queued run for a task and returns the time it was queued.
+// The time is used to remove the run once it is no longer pending.
+func (p *taskPendingRuns) Add(taskName string) time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	p.queued[taskName] = append(p.queued[taskName], now)
+	return now
+}
+
+// Remove removes a queued run for a task that was queued at the given time
+func (p *taskPendingRuns) Remove(taskName string, queuedAt time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	queued := p.queued[taskName]
+	for i, t := range queued {
+		if t.Equal(queuedAt) {
+			queued = append(queued[:i], queued[i+1:]...)
+			break
+		}
+	}
+
+	if len(queued) == 0 {
+		delete(p.queued, taskName)
+		return
+	}
+	p.queued[taskName] = queued
+}
+
+// Get returns the times that the pending runs of a task were queued, ordered
+// from oldest to newest
+func (p *taskPendingRuns) Get(taskName string) []time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	queued := p.queued[taskName]
+	if len(queued) == 0 {
+		return nil
+	}
+
+	cp := make([]time.Time, len(queued))
+	copy(cp, queued)
+	return cp
+}
+
+// Delete removes all pending run information for a task
+func (p *taskPendingRuns) Delete(taskName string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.queued, taskName)
+}
@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_taskPendingRuns(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no pending runs", func(t *testing.T) {
+		p := newTaskPendingRuns()
+		assert.Nil(t, p.Get("task"))
+	})
+
+	t.Run("add and remove", func(t *testing.T) {
+		p := newTaskPendingRuns()
+		first := p.Add("task")
+		second := p.Add("task")
+		p.Add("other")
+
+		queued := p.Get("task")
+		require.Len(t, queued, 2)
+		assert.True(t, queued[0].Equal(first))
+		assert.True(t, queued[1].Equal(second))
+
+		p.Remove("task", first)
+		queued = p.Get("task")
+		require.Len(t, queued, 1)
+		assert.True(t, queued[0].Equal(second))
+
+		p.Remove("task", second)
+		assert.Nil(t, p.Get("task"))
+		assert.Len(t, p.Get("other"), 1)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		p := newTaskPendingRuns()
+		p.Add("task")
+		p.Delete("task")
+		assert.Nil(t, p.Get("task"))
+	})
+}
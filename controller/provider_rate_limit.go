@@ -0,0 +1,131 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// providerRateLimitWindow is the window that the rate limits of providers
+// are enforced over
+const providerRateLimitWindow = time.Minute
+
+// providerRateLimits limits the frequency of the task runs that apply with a
+// provider, across all of the tasks that use the provider. Runs that would
+// exceed the rate limit of any of their providers wait until each provider
+// has capacity.
+type providerRateLimits struct {
+	mu *sync.Mutex
+
+	limits map[string]int         // provider ID => runs per minute
+	runs   map[string][]time.Time // provider ID => start times of recent runs
+
+	now func() time.Time
+}
+
+// newProviderRateLimits returns a new rate limiter for the runs of tasks by
+// the provider IDs with a rate limit
+func newProviderRateLimits(limits map[string]int) *providerRateLimits {
+	if limits == nil {
+		limits = make(map[string]int)
+	}
+	return &providerRateLimits{
+		mu:     &sync.Mutex{},
+		limits: limits,
+		runs:   make(map[string][]time.Time),
+		now:    time.Now,
+	}
+}
+
+// Limited returns the providers of the list that have a rate limit
+func (l *providerRateLimits) Limited(providerIDs []string) []string {
+	var limited []string
+	for _, id := range providerIDs {
+		if _, ok := l.limits[id]; ok {
+			limited = append(limited, id)
+		}
+	}
+	return limited
+}
+
+// Wait blocks until all of the providers have capacity for a run and then
+// records the run for each provider. Returns the duration that the run
+// waited, which is 0 if the providers had capacity, or an error if the
+// context is canceled while waiting.
+func (l *providerRateLimits) Wait(ctx context.Context, providerIDs []string) (time.Duration, error) {
+	providerIDs = l.Limited(providerIDs)
+	if len(providerIDs) == 0 {
+		return 0, nil
+	}
+
+	start := l.now()
+	var waited time.Duration
+	for {
+		delay := l.reserve(providerIDs)
+		if delay == 0 {
+			return waited, nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return l.now().Sub(start), ctx.Err()
+		case <-timer.C:
+		}
+		waited = l.now().Sub(start)
+	}
+}
+
+// reserve records a run for each of the providers if all of them have
+// capacity. Otherwise, returns the time until all of the providers are
+// expected to have capacity.
+func (l *providerRateLimits) reserve(providerIDs []string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var delay time.Duration
+	for _, id := range providerIDs {
+		runs := l.recentRuns(id, now)
+		if len(runs) < l.limits[id] {
+			continue
+		}
+
+		// capacity frees up once the oldest run that exceeds the limit is
+		// outside of the window
+		free := runs[len(runs)-l.limits[id]].Add(providerRateLimitWindow)
+		if d := free.Sub(now); d > delay {
+			delay = d
+		}
+	}
+	if delay > 0 {
+		return delay
+	}
+
+	for _, id := range providerIDs {
+		l.runs[id] = append(l.runs[id], now)
+	}
+	return 0
+}
+
+// recentRuns prunes and returns the runs of the provider within the window.
+// Must be called with the lock held.
+func (l *providerRateLimits) recentRuns(providerID string, now time.Time) []time.Time {
+	runs := l.runs[providerID]
+	i := 0
+	for i < len(runs) && !runs[i].After(now.Add(-providerRateLimitWindow)) {
+		i++
+	}
+	runs = runs[i:]
+
+	if len(runs) == 0 {
+		delete(l.runs, providerID)
+		return nil
+	}
+	l.runs[providerID] = runs
+	return runs
+}
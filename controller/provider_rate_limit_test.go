@@ -0,0 +1,169 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	mocksD "github.com/hashicorp/consul-terraform-sync/mocks/driver"
+	"github.com/hashicorp/consul-terraform-sync/templates/hcltmpl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_providerRateLimits_Limited(t *testing.T) {
+	t.Parallel()
+
+	l := newProviderRateLimits(map[string]int{"panos": 2, "panos.dc2": 1})
+	assert.Equal(t, []string{"panos", "panos.dc2"},
+		l.Limited([]string{"null", "panos", "panos.dc2"}))
+	assert.Empty(t, l.Limited([]string{"null"}))
+}
+
+func Test_providerRateLimits_reserve(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	l := newProviderRateLimits(map[string]int{"panos": 2, "fortios": 1})
+	l.now = func() time.Time { return now }
+
+	// runs within the limit
+	assert.Zero(t, l.reserve([]string{"panos"}))
+	now = now.Add(10 * time.Second)
+	assert.Zero(t, l.reserve([]string{"panos"}))
+
+	// limit reached until the first run is outside of the window
+	now = now.Add(10 * time.Second)
+	assert.Equal(t, 40*time.Second, l.reserve([]string{"panos"}))
+	assert.Len(t, l.runs["panos"], 2)
+
+	// waits on the provider that is limited the longest
+	assert.Zero(t, l.reserve([]string{"fortios"}))
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, 30*time.Second, l.reserve([]string{"panos", "fortios"}))
+	assert.Len(t, l.runs["panos"], 2, "run should not be recorded for "+
+		"any provider when one provider is limited")
+
+	// capacity once the first run is outside of the window
+	now = now.Add(10 * time.Second)
+	assert.Zero(t, l.reserve([]string{"panos"}))
+	assert.Len(t, l.runs["panos"], 2)
+}
+
+func Test_providerRateLimits_Wait(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not limited", func(t *testing.T) {
+		l := newProviderRateLimits(nil)
+		waited, err := l.Wait(context.Background(), []string{"null"})
+		assert.NoError(t, err)
+		assert.Zero(t, waited)
+	})
+
+	t.Run("capacity", func(t *testing.T) {
+		l := newProviderRateLimits(map[string]int{"panos": 1})
+		waited, err := l.Wait(context.Background(), []string{"panos"})
+		assert.NoError(t, err)
+		assert.Zero(t, waited)
+		assert.Len(t, l.runs["panos"], 1)
+	})
+
+	t.Run("waits for capacity", func(t *testing.T) {
+		l := newProviderRateLimits(map[string]int{"panos": 1})
+		// the previous run is about to leave the window
+		l.runs["panos"] = []time.Time{time.Now().Add(-time.Minute + 50*time.Millisecond)}
+
+		waited, err := l.Wait(context.Background(), []string{"panos"})
+		assert.NoError(t, err)
+		assert.Positive(t, waited)
+		assert.Len(t, l.runs["panos"], 1)
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		l := newProviderRateLimits(map[string]int{"panos": 1})
+		l.runs["panos"] = []time.Time{time.Now()}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := l.Wait(ctx, []string{"panos"})
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.Len(t, l.runs["panos"], 1)
+	})
+}
+
+func Test_TasksManager_waitForProviderRateLimits(t *testing.T) {
+	t.Parallel()
+
+	task, err := driver.NewTask(driver.TaskConfig{
+		Name:    "task",
+		Enabled: true,
+		Providers: driver.NewTerraformProviderBlocks(
+			hcltmpl.NewNamedBlocksTest([]map[string]interface{}{
+				{"panos": map[string]interface{}{}},
+			})),
+	})
+	require.NoError(t, err)
+
+	t.Run("not limited", func(t *testing.T) {
+		tm := newTestTasksManager()
+		d := new(mocksD.Driver)
+		d.On("Task").Return(task)
+
+		assert.NoError(t, tm.waitForProviderRateLimits(context.Background(), d))
+		d.AssertNotCalled(t, "RenderTemplate", mock.Anything)
+	})
+
+	t.Run("capacity", func(t *testing.T) {
+		tm := newTestTasksManager()
+		tm.providerLimits = newProviderRateLimits(map[string]int{"panos": 1})
+		d := new(mocksD.Driver)
+		d.On("Task").Return(task)
+
+		assert.NoError(t, tm.waitForProviderRateLimits(context.Background(), d))
+		d.AssertNotCalled(t, "RenderTemplate", mock.Anything)
+	})
+
+	t.Run("renders after waiting", func(t *testing.T) {
+		tm := newTestTasksManager()
+		tm.providerLimits = newProviderRateLimits(map[string]int{"panos": 1})
+		tm.providerLimits.runs["panos"] = []time.Time{
+			time.Now().Add(-time.Minute + 10*time.Millisecond)}
+		d := new(mocksD.Driver)
+		d.On("Task").Return(task)
+		d.On("RenderTemplate", mock.Anything).Return(false, nil).Once()
+
+		assert.NoError(t, tm.waitForProviderRateLimits(context.Background(), d))
+		d.AssertExpectations(t)
+	})
+
+	t.Run("render error", func(t *testing.T) {
+		tm := newTestTasksManager()
+		tm.providerLimits = newProviderRateLimits(map[string]int{"panos": 1})
+		tm.providerLimits.runs["panos"] = []time.Time{
+			time.Now().Add(-time.Minute + 10*time.Millisecond)}
+		d := new(mocksD.Driver)
+		d.On("Task").Return(task)
+		d.On("RenderTemplate", mock.Anything).Return(false, errors.New("error"))
+
+		assert.Error(t, tm.waitForProviderRateLimits(context.Background(), d))
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		tm := newTestTasksManager()
+		tm.providerLimits = newProviderRateLimits(map[string]int{"panos": 1})
+		tm.providerLimits.runs["panos"] = []time.Time{time.Now()}
+		d := new(mocksD.Driver)
+		d.On("Task").Return(task)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.Error(t, tm.waitForProviderRateLimits(ctx, d))
+		d.AssertNotCalled(t, "RenderTemplate", mock.Anything)
+	})
+}
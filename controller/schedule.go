@@ -0,0 +1,49 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"time"
+
+	"github.com/hashicorp/cronexpr"
+)
+
+// cronSchedule returns the next scheduled time after a given time. It is
+// satisfied by a single cron expression and by a schedule.
+type cronSchedule interface {
+	Next(fromTime time.Time) time.Time
+}
+
+// schedule is the combination of the cron expressions of a scheduled task.
+// The task is scheduled to run at the times of every expression.
+type schedule []*cronexpr.Expression
+
+// parseSchedule parses the cron expressions of a scheduled task
+func parseSchedule(crons []string) (schedule, error) {
+	s := make(schedule, 0, len(crons))
+	for _, cron := range crons {
+		expr, err := cronexpr.Parse(cron)
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, expr)
+	}
+	return s, nil
+}
+
+// Next returns the earliest scheduled time of the cron expressions after the
+// given time. Returns the zero time if no expression has a next time.
+func (s schedule) Next(fromTime time.Time) time.Time {
+	var next time.Time
+	for _, expr := range s {
+		t := expr.Next(fromTime)
+		if t.IsZero() {
+			continue
+		}
+		if next.IsZero() || t.Before(next) {
+			next = t
+		}
+	}
+	return next
+}
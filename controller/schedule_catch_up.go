@@ -0,0 +1,62 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// scheduleLastRunFilename is the name of the file in the working directory of
+// a scheduled task that records the time of the task's last scheduled run.
+// The file persists across restarts so that scheduled runs missed while CTS
+// was not running can be caught up.
+const scheduleLastRunFilename = "schedule_last_run"
+
+// readScheduleLastRun reads the time of the last scheduled run of a task from
+// its working directory. Returns false if no run was recorded.
+func readScheduleLastRun(workingDir string) (time.Time, bool, error) {
+	content, err := os.ReadFile(filepath.Join(workingDir, scheduleLastRunFilename))
+	if os.IsNotExist(err) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	lastRun, err := time.Parse(time.RFC3339, strings.TrimSpace(string(content)))
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("unable to parse last scheduled "+
+			"run time: %s", err)
+	}
+	return lastRun, true, nil
+}
+
+// writeScheduleLastRun records the time of the last scheduled run of a task in
+// its working directory
+func writeScheduleLastRun(workingDir string, lastRun time.Time) error {
+	path := filepath.Join(workingDir, scheduleLastRunFilename)
+	return os.WriteFile(path, []byte(lastRun.Format(time.RFC3339)+"\n"), 0644)
+}
+
+// missedScheduledRun returns the earliest scheduled time of the cron
+// schedule after the last run and up to now that is within the max age.
+// Returns false if no scheduled run was missed.
+func missedScheduledRun(expr cronSchedule, lastRun, now time.Time,
+	maxAge time.Duration) (time.Time, bool) {
+
+	from := lastRun
+	if oldest := now.Add(-maxAge); from.Before(oldest) {
+		from = oldest
+	}
+
+	missed := expr.Next(from)
+	if missed.IsZero() || missed.After(now) {
+		return time.Time{}, false
+	}
+	return missed, true
+}
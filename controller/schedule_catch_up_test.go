@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/cronexpr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_scheduleLastRun(t *testing.T) {
+	t.Parallel()
+
+	t.Run("read and write", func(t *testing.T) {
+		dir := t.TempDir()
+
+		_, ok, err := readScheduleLastRun(dir)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		lastRun := time.Date(2022, time.June, 1, 2, 0, 0, 0, time.UTC)
+		require.NoError(t, writeScheduleLastRun(dir, lastRun))
+
+		actual, ok, err := readScheduleLastRun(dir)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.True(t, lastRun.Equal(actual))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, scheduleLastRunFilename)
+		require.NoError(t, os.WriteFile(path, []byte("yesterday"), 0644))
+
+		_, _, err := readScheduleLastRun(dir)
+		assert.Error(t, err)
+	})
+}
+
+func Test_missedScheduledRun(t *testing.T) {
+	t.Parallel()
+
+	// daily at 02:00
+	expr := cronexpr.MustParse("0 0 2 * * * *")
+	now := time.Date(2022, time.June, 2, 9, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		lastRun  time.Time
+		maxAge   time.Duration
+		expected time.Time
+		missed   bool
+	}{
+		{
+			"no missed run",
+			time.Date(2022, time.June, 2, 2, 0, 0, 0, time.UTC),
+			24 * time.Hour,
+			time.Time{},
+			false,
+		},
+		{
+			"missed run",
+			time.Date(2022, time.June, 1, 2, 0, 0, 0, time.UTC),
+			24 * time.Hour,
+			time.Date(2022, time.June, 2, 2, 0, 0, 0, time.UTC),
+			true,
+		},
+		{
+			"earliest missed run within max age",
+			time.Date(2022, time.May, 1, 2, 0, 0, 0, time.UTC),
+			48 * time.Hour,
+			time.Date(2022, time.June, 1, 2, 0, 0, 0, time.UTC),
+			true,
+		},
+		{
+			"missed run older than max age",
+			time.Date(2022, time.June, 1, 2, 0, 0, 0, time.UTC),
+			time.Hour,
+			time.Time{},
+			false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, missed := missedScheduledRun(expr, tc.lastRun, now, tc.maxAge)
+			assert.Equal(t, tc.missed, missed)
+			assert.True(t, tc.expected.Equal(actual))
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseSchedule(t *testing.T) {
+	t.Parallel()
+
+	s, err := parseSchedule([]string{"0 * * * 1-5", "0 0 * * 0,6"})
+	require.NoError(t, err)
+	assert.Len(t, s, 2)
+
+	_, err = parseSchedule([]string{"0 * * * 1-5", "invalid"})
+	assert.Error(t, err)
+}
+
+func Test_schedule_Next(t *testing.T) {
+	t.Parallel()
+
+	// Hourly on weekdays and daily at midnight on weekends
+	s, err := parseSchedule([]string{"0 * * * 1-5", "0 0 * * 0,6"})
+	require.NoError(t, err)
+
+	cases := []struct {
+		name     string
+		from     time.Time
+		expected time.Time
+	}{
+		{
+			"weekday",
+			// Wednesday
+			time.Date(2022, time.June, 1, 10, 30, 0, 0, time.UTC),
+			time.Date(2022, time.June, 1, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			"friday night",
+			time.Date(2022, time.June, 3, 23, 30, 0, 0, time.UTC),
+			time.Date(2022, time.June, 4, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			"weekend",
+			// Saturday
+			time.Date(2022, time.June, 4, 10, 30, 0, 0, time.UTC),
+			time.Date(2022, time.June, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			"sunday",
+			time.Date(2022, time.June, 5, 10, 30, 0, 0, time.UTC),
+			time.Date(2022, time.June, 6, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, s.Next(tc.from))
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		assert.True(t, schedule{}.Next(time.Now()).IsZero())
+	})
+}
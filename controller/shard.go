@@ -0,0 +1,410 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/hashicorp/consul-terraform-sync/state"
+	consulapi "github.com/hashicorp/consul/api"
+)
+
+const (
+	shardingSubsystemName = "sharding"
+
+	// shardRetryWait is the time to wait before retrying to look up the
+	// shard membership after an error
+	shardRetryWait = 5 * time.Second
+)
+
+// hashRing assigns keys to members by consistent hashing. Each member has a
+// number of virtual nodes on the ring, and a key is assigned to the member of
+// the first virtual node at or after the hash of the key. Adding or removing
+// a member only reassigns the keys of the virtual nodes of that member.
+type hashRing struct {
+	points  []uint64
+	members map[uint64]string
+}
+
+// newHashRing returns a hash ring of the members with the number of virtual
+// nodes for each member
+func newHashRing(members []string, virtualNodes int) *hashRing {
+	r := &hashRing{
+		points:  make([]uint64, 0, len(members)*virtualNodes),
+		members: make(map[uint64]string, len(members)*virtualNodes),
+	}
+	for _, m := range members {
+		for i := 0; i < virtualNodes; i++ {
+			h := hashKey(fmt.Sprintf("%s#%d", m, i))
+			if existing, ok := r.members[h]; ok && existing < m {
+				// keep collisions deterministic across instances
+				continue
+			} else if !ok {
+				r.points = append(r.points, h)
+			}
+			r.members[h] = m
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i] < r.points[j] })
+	return r
+}
+
+// owner returns the member that the key is assigned to. Returns an empty
+// string if the ring has no members.
+func (r *hashRing) owner(key string) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+	h := hashKey(key)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.members[r.points[i]]
+}
+
+// hashKey hashes the key onto the ring. SHA-256 is used instead of a faster
+// hash since the keys are short and similar, e.g. "cts-a#1", and only hashed
+// when the members change.
+func hashKey(key string) uint64 {
+	sum := sha256.Sum256([]byte(key))
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+// shardMembership tracks the CTS instances that share the tasks
+type shardMembership interface {
+	// join adds this instance to the membership
+	join(ctx context.Context) error
+
+	// members returns the sorted IDs of the instances in the membership and
+	// an index of the membership. Blocks until the index changes from
+	// waitIndex if waitIndex is not 0.
+	members(ctx context.Context, waitIndex uint64) ([]string, uint64, error)
+
+	// leave removes this instance from the membership
+	leave()
+}
+
+// consulShardMembership is a shard membership held in Consul KV. Each
+// instance acquires a key under the KV path with a Consul session. The key is
+// deleted when the session is destroyed or expires, e.g. when the instance
+// stops or is unable to reach Consul.
+type consulShardMembership struct {
+	logger logging.Logger
+	client *consulapi.Client
+
+	id     string
+	prefix string
+	ttl    time.Duration
+
+	session string
+	doneCh  chan struct{}
+}
+
+// newConsulShardMembership returns a shard membership in Consul KV for the
+// instance with the ID
+func newConsulShardMembership(client *consulapi.Client, id string,
+	conf *config.ShardingConfig) *consulShardMembership {
+
+	return &consulShardMembership{
+		logger: logging.Global().Named(shardingSubsystemName),
+		client: client,
+		id:     id,
+		prefix: path.Join(config.StringVal(conf.KVPath), "members") + "/",
+		ttl:    config.TimeDurationVal(conf.SessionTTL),
+	}
+}
+
+// join creates a session for this instance that is renewed in the background
+// and acquires the key of this instance with the session. A previous session
+// is destroyed.
+func (m *consulShardMembership) join(ctx context.Context) error {
+	m.leave()
+
+	wOpts := (&consulapi.WriteOptions{}).WithContext(ctx)
+	session, _, err := m.client.Session().Create(&consulapi.SessionEntry{
+		Name:      fmt.Sprintf("cts-shard-%s", m.id),
+		TTL:       m.ttl.String(),
+		Behavior:  consulapi.SessionBehaviorDelete,
+		LockDelay: time.Nanosecond, // allow the instance to rejoin right away
+	}, wOpts)
+	if err != nil {
+		return fmt.Errorf("unable to create session for shard membership: %s", err)
+	}
+
+	doneCh := make(chan struct{})
+	go func() {
+		err := m.client.Session().RenewPeriodic(m.ttl.String(), session, nil, doneCh)
+		if err != nil {
+			m.logger.Warn("stopped renewing shard membership session",
+				"session", session, "error", err)
+		}
+	}()
+	m.session, m.doneCh = session, doneCh
+
+	ok, _, err := m.client.KV().Acquire(&consulapi.KVPair{
+		Key:     m.prefix + m.id,
+		Value:   []byte(m.id),
+		Session: session,
+	}, wOpts)
+	if err != nil {
+		m.leave()
+		return fmt.Errorf("unable to join shard membership: %s", err)
+	}
+	if !ok {
+		m.leave()
+		return fmt.Errorf("unable to join shard membership: another instance "+
+			"with the ID %q is already a member", m.id)
+	}
+
+	m.logger.Info("joined shard membership", "id", m.id, "session", session)
+	return nil
+}
+
+// members returns the IDs of the keys under the KV path that are held by a
+// session
+func (m *consulShardMembership) members(ctx context.Context, waitIndex uint64) (
+	[]string, uint64, error) {
+
+	qOpts := (&consulapi.QueryOptions{WaitIndex: waitIndex}).WithContext(ctx)
+	pairs, meta, err := m.client.KV().List(m.prefix, qOpts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var members []string
+	for _, p := range pairs {
+		if p.Session == "" {
+			continue
+		}
+		members = append(members, strings.TrimPrefix(p.Key, m.prefix))
+	}
+	sort.Strings(members)
+	return members, meta.LastIndex, nil
+}
+
+// leave stops renewing the session, which destroys the session and deletes
+// the key of this instance
+func (m *consulShardMembership) leave() {
+	if m.doneCh == nil {
+		return
+	}
+	close(m.doneCh)
+	m.doneCh = nil
+	m.session = ""
+}
+
+// shardTasks creates and deletes the tasks assigned to this instance
+type shardTasks interface {
+	TaskCreateAndRunAllowFail(ctx context.Context, taskConfig config.TaskConfig) error
+	TaskDelete(ctx context.Context, name string) error
+}
+
+// taskSharder shares the tasks of the configuration file between CTS
+// instances. This instance only watches and runs the tasks that are assigned
+// to it by the hash ring of the members, and creates or deletes tasks when
+// the members change.
+//
+// Tasks created through the API are not sharded and only run on the instance
+// that created them. While tasks are rebalanced, the Terraform state lock of
+// the task's backend prevents instances from applying a task concurrently.
+type taskSharder struct {
+	logger logging.Logger
+
+	id           string
+	virtualNodes int
+	membership   shardMembership
+	state        state.Store
+	tasks        shardTasks
+
+	// configured is the latest configuration of the sharded tasks, by name
+	configured map[string]config.TaskConfig
+
+	// owned is the set of sharded tasks that are assigned to this instance
+	owned map[string]bool
+
+	members []string
+	index   uint64
+}
+
+// newTaskSharder returns a task sharder for the tasks in the state. Expected
+// to be created before the tasks are created so that the tasks are the tasks
+// of the configuration file.
+func newTaskSharder(conf *config.Config, s state.Store, tasks shardTasks,
+	membership shardMembership) *taskSharder {
+
+	configured := make(map[string]config.TaskConfig)
+	for _, tc := range s.GetAllTasks() {
+		configured[config.StringVal(tc.Name)] = *tc
+	}
+
+	return &taskSharder{
+		logger:       logging.Global().Named(shardingSubsystemName),
+		id:           config.StringVal(conf.ID),
+		virtualNodes: config.IntVal(conf.Sharding.VirtualNodes),
+		membership:   membership,
+		state:        s,
+		tasks:        tasks,
+		configured:   configured,
+		owned:        make(map[string]bool),
+	}
+}
+
+// start joins the membership and removes the tasks that are not assigned to
+// this instance from the state, so that only the assigned tasks are created
+// when the tasks are run once.
+func (s *taskSharder) start(ctx context.Context) error {
+	if err := s.membership.join(ctx); err != nil {
+		return err
+	}
+
+	members, index, err := s.membership.members(ctx, 0)
+	if err != nil {
+		return fmt.Errorf("unable to look up shard membership: %s", err)
+	}
+	s.members, s.index = members, index
+
+	ring := newHashRing(members, s.virtualNodes)
+	for _, name := range s.taskNames() {
+		if ring.owner(name) == s.id {
+			s.owned[name] = true
+			continue
+		}
+		if err := s.state.DeleteTask(name); err != nil {
+			return err
+		}
+	}
+
+	s.logger.Info("assigned tasks to instance", "members", members,
+		"tasks_assigned", len(s.owned), "tasks_total", len(s.configured))
+	return nil
+}
+
+// run watches the membership and rebalances the tasks when the members
+// change. Leaves the membership when the context is canceled.
+func (s *taskSharder) run(ctx context.Context) error {
+	defer s.membership.leave()
+
+	for {
+		members, index, err := s.membership.members(ctx, s.index)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			s.logger.Warn("error looking up shard membership, retrying",
+				"retry_in", shardRetryWait, "error", err)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(shardRetryWait):
+			}
+			continue
+		}
+
+		// reset the index if it goes backwards, e.g. after a Consul snapshot
+		// restore
+		if index < s.index {
+			index = 0
+		}
+		s.index = index
+
+		if !contains(members, s.id) {
+			s.logger.Warn("instance is no longer a shard member, rejoining")
+			if err := s.membership.join(ctx); err != nil {
+				s.logger.Error("error rejoining shard membership", "error", err)
+				s.index = 0
+			}
+			continue
+		}
+
+		if equalStrings(members, s.members) {
+			continue
+		}
+		s.rebalance(ctx, members)
+	}
+}
+
+// rebalance creates the tasks that are newly assigned to this instance and
+// deletes the tasks that are assigned to another instance
+func (s *taskSharder) rebalance(ctx context.Context, members []string) {
+	s.logger.Info("shard membership changed, rebalancing tasks",
+		"members", members, "previous_members", s.members)
+	s.members = members
+
+	ring := newHashRing(members, s.virtualNodes)
+	for _, name := range s.taskNames() {
+		logger := s.logger.With(taskNameLogKey, name)
+		owns := ring.owner(name) == s.id
+
+		switch {
+		case owns && !s.owned[name]:
+			logger.Info("task assigned to instance, creating task")
+			if err := s.tasks.TaskCreateAndRunAllowFail(ctx, s.configured[name]); err != nil {
+				logger.Error("error creating assigned task", "error", err)
+			}
+			if _, ok := s.state.GetTask(name); ok {
+				s.owned[name] = true
+			}
+
+		case !owns && s.owned[name]:
+			delete(s.owned, name)
+			tc, ok := s.state.GetTask(name)
+			if !ok {
+				// the task was deleted through the API and is no longer sharded
+				logger.Info("task assigned to another instance was deleted, " +
+					"no longer sharding task")
+				delete(s.configured, name)
+				continue
+			}
+
+			logger.Info("task assigned to another instance, deleting task",
+				"owner", ring.owner(name))
+			s.configured[name] = tc
+			if err := s.tasks.TaskDelete(ctx, name); err != nil {
+				logger.Error("error deleting task assigned to another instance",
+					"error", err)
+			}
+		}
+	}
+}
+
+// taskNames returns the sorted names of the sharded tasks
+func (s *taskSharder) taskNames() []string {
+	names := make([]string, 0, len(s.configured))
+	for name := range s.configured {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,307 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/hashicorp/consul-terraform-sync/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testMembership is a shard membership that returns the members sent on its
+// channel
+type testMembership struct {
+	mu      sync.Mutex
+	joined  int
+	left    bool
+	initial []string
+	updates chan []string
+	index   uint64
+}
+
+func (m *testMembership) join(_ context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.joined++
+	return nil
+}
+
+func (m *testMembership) members(ctx context.Context, waitIndex uint64) ([]string, uint64, error) {
+	if waitIndex == 0 {
+		return m.initial, 1, nil
+	}
+	select {
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	case members := <-m.updates:
+		m.index++
+		return members, waitIndex + m.index, nil
+	}
+}
+
+func (m *testMembership) leave() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.left = true
+}
+
+// testShardTasks records the tasks created and deleted by the sharder
+type testShardTasks struct {
+	state   state.Store
+	created []string
+	deleted []string
+	done    chan struct{}
+}
+
+func (t *testShardTasks) TaskCreateAndRunAllowFail(_ context.Context, tc config.TaskConfig) error {
+	err := t.state.SetTask(tc)
+	t.created = append(t.created, config.StringVal(tc.Name))
+	t.done <- struct{}{}
+	return err
+}
+
+func (t *testShardTasks) TaskDelete(_ context.Context, name string) error {
+	err := t.state.DeleteTask(name)
+	t.deleted = append(t.deleted, name)
+	t.done <- struct{}{}
+	return err
+}
+
+func newTestTaskSharder(t *testing.T, id string, taskNames []string,
+	initial []string) (*taskSharder, *testMembership, *testShardTasks) {
+
+	tasks := make(config.TaskConfigs, len(taskNames))
+	for i, name := range taskNames {
+		tasks[i] = &config.TaskConfig{Name: config.String(name)}
+	}
+	conf := &config.Config{
+		ID:    config.String(id),
+		Tasks: &tasks,
+		Sharding: &config.ShardingConfig{
+			Enabled:      config.Bool(true),
+			VirtualNodes: config.Int(config.DefaultShardingVirtualNodes),
+		},
+	}
+
+	s := state.NewInMemoryStore(conf)
+	m := &testMembership{initial: initial, updates: make(chan []string)}
+	tm := &testShardTasks{state: s, done: make(chan struct{}, len(taskNames))}
+
+	sharder := newTaskSharder(conf, s, tm, m)
+	sharder.logger = logging.NewNullLogger()
+	return sharder, m, tm
+}
+
+func testTaskNames(n int) []string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("task_%d", i)
+	}
+	return names
+}
+
+func TestHashRing_owner(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no members", func(t *testing.T) {
+		r := newHashRing(nil, 10)
+		assert.Equal(t, "", r.owner("task"))
+	})
+
+	t.Run("one member", func(t *testing.T) {
+		r := newHashRing([]string{"cts-a"}, 10)
+		for _, name := range testTaskNames(20) {
+			assert.Equal(t, "cts-a", r.owner(name))
+		}
+	})
+
+	t.Run("order of members", func(t *testing.T) {
+		r1 := newHashRing([]string{"cts-a", "cts-b", "cts-c"}, 16)
+		r2 := newHashRing([]string{"cts-c", "cts-a", "cts-b"}, 16)
+		for _, name := range testTaskNames(50) {
+			assert.Equal(t, r1.owner(name), r2.owner(name))
+		}
+	})
+
+	t.Run("distribution", func(t *testing.T) {
+		members := []string{"cts-a", "cts-b", "cts-c"}
+		r := newHashRing(members, config.DefaultShardingVirtualNodes)
+		counts := make(map[string]int)
+		for _, name := range testTaskNames(300) {
+			counts[r.owner(name)]++
+		}
+		for _, m := range members {
+			assert.Greater(t, counts[m], 50, "member %s", m)
+		}
+	})
+
+	t.Run("member leaves", func(t *testing.T) {
+		before := newHashRing([]string{"cts-a", "cts-b", "cts-c"}, 32)
+		after := newHashRing([]string{"cts-a", "cts-b"}, 32)
+		for _, name := range testTaskNames(100) {
+			// only the tasks of the member that left are reassigned
+			if owner := before.owner(name); owner != "cts-c" {
+				assert.Equal(t, owner, after.owner(name), "task %s", name)
+			}
+		}
+	})
+}
+
+func TestTaskSharder_start(t *testing.T) {
+	t.Parallel()
+
+	names := testTaskNames(20)
+	members := []string{"cts-a", "cts-b"}
+	sharder, m, _ := newTestTaskSharder(t, "cts-a", names, members)
+
+	require.NoError(t, sharder.start(context.Background()))
+	assert.Equal(t, 1, m.joined)
+
+	ring := newHashRing(members, config.DefaultShardingVirtualNodes)
+	for _, name := range names {
+		_, ok := sharder.state.GetTask(name)
+		owned := ring.owner(name) == "cts-a"
+		assert.Equal(t, owned, ok, "task %s", name)
+		assert.Equal(t, owned, sharder.owned[name], "task %s", name)
+	}
+	assert.Len(t, sharder.configured, len(names))
+}
+
+func TestTaskSharder_run(t *testing.T) {
+	t.Parallel()
+
+	names := testTaskNames(20)
+	sharder, m, tm := newTestTaskSharder(t, "cts-a", names, []string{"cts-a", "cts-b"})
+	require.NoError(t, sharder.start(context.Background()))
+	initialOwned := len(sharder.owned)
+	require.Less(t, initialOwned, len(names))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() {
+		errCh <- sharder.run(ctx)
+	}()
+
+	// member leaves, all tasks are assigned to this instance
+	m.updates <- []string{"cts-a"}
+	waitForShardTasks(t, tm.done, len(names)-initialOwned)
+	assert.Len(t, tm.created, len(names)-initialOwned)
+	assert.Len(t, sharder.state.GetAllTasks(), len(names))
+
+	// member joins, tasks are reassigned to the member
+	m.updates <- []string{"cts-a", "cts-c"}
+	ring := newHashRing([]string{"cts-a", "cts-c"}, config.DefaultShardingVirtualNodes)
+	var lost int
+	for _, name := range names {
+		if ring.owner(name) != "cts-a" {
+			lost++
+		}
+	}
+	waitForShardTasks(t, tm.done, lost)
+	assert.Len(t, tm.deleted, lost)
+	for _, name := range tm.deleted {
+		assert.Equal(t, "cts-c", ring.owner(name))
+	}
+
+	cancel()
+	select {
+	case err := <-errCh:
+		assert.Equal(t, context.Canceled, err)
+	case <-time.After(time.Second):
+		t.Fatal("sharder did not stop")
+	}
+	assert.True(t, m.left)
+}
+
+func TestTaskSharder_rebalance(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rejoin when not a member", func(t *testing.T) {
+		sharder, m, tm := newTestTaskSharder(t, "cts-a", testTaskNames(5), []string{"cts-a"})
+		require.NoError(t, sharder.start(context.Background()))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go sharder.run(ctx)
+
+		m.updates <- []string{"cts-b"}
+		m.updates <- []string{"cts-a"} // blocks until the previous is handled
+		m.mu.Lock()
+		assert.Equal(t, 2, m.joined)
+		m.mu.Unlock()
+		assert.Empty(t, tm.created)
+		assert.Empty(t, tm.deleted)
+	})
+
+	t.Run("deleted task is no longer sharded", func(t *testing.T) {
+		names := testTaskNames(10)
+		sharder, _, tm := newTestTaskSharder(t, "cts-a", names, []string{"cts-a"})
+		require.NoError(t, sharder.start(context.Background()))
+
+		// delete the tasks through the API
+		for _, name := range names {
+			require.NoError(t, sharder.state.DeleteTask(name))
+		}
+
+		sharder.rebalance(context.Background(), []string{"cts-a", "cts-b"})
+		assert.Empty(t, tm.deleted)
+		assert.Less(t, len(sharder.configured), len(names))
+		for name := range sharder.configured {
+			assert.True(t, sharder.owned[name], "task %s", name)
+		}
+	})
+
+	t.Run("latest task config is kept", func(t *testing.T) {
+		names := testTaskNames(10)
+		sharder, _, tm := newTestTaskSharder(t, "cts-a", names, []string{"cts-a"})
+		require.NoError(t, sharder.start(context.Background()))
+
+		for _, name := range names {
+			tc, ok := sharder.state.GetTask(name)
+			require.True(t, ok)
+			tc.Description = config.String("updated")
+			require.NoError(t, sharder.state.SetTask(tc))
+		}
+
+		sharder.rebalance(context.Background(), []string{"cts-a", "cts-b"})
+		require.NotEmpty(t, tm.deleted)
+		for _, name := range tm.deleted {
+			assert.Equal(t, "updated", config.StringVal(sharder.configured[name].Description))
+		}
+	})
+}
+
+func TestTaskSharder_start_error(t *testing.T) {
+	t.Parallel()
+
+	sharder, _, _ := newTestTaskSharder(t, "cts-a", testTaskNames(2), nil)
+	sharder.membership = &errMembership{}
+	err := sharder.start(context.Background())
+	assert.Error(t, err)
+}
+
+type errMembership struct{ testMembership }
+
+func (m *errMembership) join(context.Context) error {
+	return errors.New("session error")
+}
+
+func waitForShardTasks(t *testing.T, done chan struct{}, n int) {
+	for i := 0; i < n; i++ {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %d of %d tasks", i, n)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/driver"
+)
+
+// taskLocks tracks the advisory locks of tasks and the run queued for each
+// task that was triggered while locked. Locks are held in memory and are
+// released when CTS stops.
+type taskLocks struct {
+	mu *sync.Mutex
+
+	locks  map[string]driver.TaskLock // taskname => lock
+	queued map[string]func()          // taskname => queued run
+}
+
+// newTaskLocks returns a new tracker for task locks
+func newTaskLocks() *taskLocks {
+	return &taskLocks{
+		mu:     &sync.Mutex{},
+		locks:  make(map[string]driver.TaskLock),
+		queued: make(map[string]func()),
+	}
+}
+
+// Lock locks a task for the holder. Returns an error if the task is already
+// locked.
+func (l *taskLocks) Lock(taskName, holder, reason string, now time.Time) (driver.TaskLock, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if lock, ok := l.locks[taskName]; ok {
+		return driver.TaskLock{}, fmt.Errorf("task '%s' is already locked by "+
+			"'%s' since %s: %w", taskName, lock.Holder,
+			lock.LockedAt.Format(time.RFC3339), driver.ErrTaskLocked)
+	}
+
+	lock := driver.TaskLock{
+		TaskName: taskName,
+		Holder:   holder,
+		Reason:   reason,
+		LockedAt: now,
+	}
+	l.locks[taskName] = lock
+	return lock, nil
+}
+
+// Unlock unlocks a task and returns the run queued while the task was
+// locked, which is nil if the task was not triggered. Returns an error if
+// the task is not locked.
+func (l *taskLocks) Unlock(taskName string) (func(), error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.locks[taskName]; !ok {
+		return nil, fmt.Errorf("task '%s' is not locked: %w", taskName,
+			driver.ErrTaskNotLocked)
+	}
+
+	run := l.queued[taskName]
+	delete(l.locks, taskName)
+	delete(l.queued, taskName)
+	return run, nil
+}
+
+// Queue queues the run of a task if the task is locked. Returns whether the
+// task is locked and whether the run was queued. Only the first run is
+// queued while a task is locked since the run applies all of the changes
+// since the last run.
+func (l *taskLocks) Queue(taskName string, run func()) (bool, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.locks[taskName]; !ok {
+		return false, false
+	}
+	if _, ok := l.queued[taskName]; ok {
+		return true, false
+	}
+	l.queued[taskName] = run
+	return true, true
+}
+
+// Get returns the lock of a task. Returns false if the task is not locked.
+func (l *taskLocks) Get(taskName string) (driver.TaskLock, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lock, ok := l.locks[taskName]
+	if !ok {
+		return driver.TaskLock{}, false
+	}
+	_, lock.RunQueued = l.queued[taskName]
+	return lock, true
+}
+
+// Delete removes the lock and the queued run of a task
+func (l *taskLocks) Delete(taskName string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.locks, taskName)
+	delete(l.queued, taskName)
+}
@@ -0,0 +1,245 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/driver"
+	mocksD "github.com/hashicorp/consul-terraform-sync/mocks/driver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskLocks(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("lock and unlock", func(t *testing.T) {
+		locks := newTaskLocks()
+
+		lock, err := locks.Lock("task", "alice", "manual import", now)
+		require.NoError(t, err)
+		assert.Equal(t, driver.TaskLock{
+			TaskName: "task",
+			Holder:   "alice",
+			Reason:   "manual import",
+			LockedAt: now,
+		}, lock)
+
+		_, err = locks.Lock("task", "bob", "", now)
+		assert.ErrorIs(t, err, driver.ErrTaskLocked)
+		assert.Contains(t, err.Error(), "alice")
+
+		actual, ok := locks.Get("task")
+		require.True(t, ok)
+		assert.Equal(t, lock, actual)
+
+		run, err := locks.Unlock("task")
+		require.NoError(t, err)
+		assert.Nil(t, run)
+
+		_, ok = locks.Get("task")
+		assert.False(t, ok)
+
+		_, err = locks.Unlock("task")
+		assert.ErrorIs(t, err, driver.ErrTaskNotLocked)
+	})
+
+	t.Run("queue", func(t *testing.T) {
+		locks := newTaskLocks()
+
+		locked, queued := locks.Queue("task", func() {})
+		assert.False(t, locked)
+		assert.False(t, queued)
+
+		_, err := locks.Lock("task", "alice", "", now)
+		require.NoError(t, err)
+
+		ran := 0
+		locked, queued = locks.Queue("task", func() { ran++ })
+		assert.True(t, locked)
+		assert.True(t, queued)
+
+		locked, queued = locks.Queue("task", func() { ran += 10 })
+		assert.True(t, locked)
+		assert.False(t, queued, "only the first run is queued")
+
+		lock, ok := locks.Get("task")
+		require.True(t, ok)
+		assert.True(t, lock.RunQueued)
+
+		run, err := locks.Unlock("task")
+		require.NoError(t, err)
+		require.NotNil(t, run)
+		run()
+		assert.Equal(t, 1, ran)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		locks := newTaskLocks()
+
+		_, err := locks.Lock("task", "alice", "", now)
+		require.NoError(t, err)
+		locks.Queue("task", func() {})
+		locks.Delete("task")
+
+		_, ok := locks.Get("task")
+		assert.False(t, ok)
+		locked, _ := locks.Queue("task", func() {})
+		assert.False(t, locked)
+	})
+}
+
+func Test_TasksManager_TaskLock(t *testing.T) {
+	t.Parallel()
+
+	t.Run("task does not exist", func(t *testing.T) {
+		tm := newTestTasksManager()
+
+		_, err := tm.TaskLock(context.Background(), "task_a", "alice", "")
+		assert.Error(t, err)
+		_, ok := tm.TaskLockStatus(context.Background(), "task_a")
+		assert.False(t, ok)
+	})
+
+	t.Run("waits for active run", func(t *testing.T) {
+		d := new(mocksD.Driver)
+		d.On("Task").Return(enabledTestTask(t, "task_a"))
+		d.On("TemplateIDs").Return(nil)
+
+		tm := newTestTasksManager()
+		tm.drivers.Add("task_a", d)
+		tm.drivers.SetActive("task_a")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := tm.TaskLock(ctx, "task_a", "alice", "")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		_, ok := tm.TaskLockStatus(ctx, "task_a")
+		assert.False(t, ok, "lock is released when waiting fails")
+
+		tm.drivers.SetInactive("task_a")
+		lock, err := tm.TaskLock(context.Background(), "task_a", "alice", "")
+		require.NoError(t, err)
+		assert.Equal(t, "alice", lock.Holder)
+	})
+}
+
+func Test_TasksManager_TaskSuppressByLock(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not locked", func(t *testing.T) {
+		d := new(mocksD.Driver)
+		d.On("Task").Return(enabledTestTask(t, "task_a"))
+		d.On("TemplateIDs").Return(nil)
+
+		tm := newTestTasksManager()
+		tm.drivers.Add("task_a", d)
+
+		assert.False(t, tm.TaskSuppressByLock(context.Background(), "task_a"))
+	})
+
+	t.Run("queued while locked", func(t *testing.T) {
+		d := new(mocksD.Driver)
+		d.On("Task").Return(enabledTestTask(t, "task_a"))
+		d.On("TemplateIDs").Return(nil)
+		rendered := make(chan struct{}, 1)
+		d.On("RenderTemplate", mock.Anything).Return(false, nil).
+			Run(func(mock.Arguments) { rendered <- struct{}{} })
+
+		tm := newTestTasksManager()
+		tm.drivers.Add("task_a", d)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		_, err := tm.TaskLock(ctx, "task_a", "alice", "")
+		require.NoError(t, err)
+
+		// Repeated triggers are suppressed and only recorded once
+		assert.True(t, tm.TaskSuppressByLock(ctx, "task_a"))
+		assert.True(t, tm.TaskSuppressByLock(ctx, "task_a"))
+
+		events := tm.state.GetTaskEvents("task_a")["task_a"]
+		require.Len(t, events, 1)
+		assert.True(t, events[0].Suppressed)
+
+		lock, ok := tm.TaskLockStatus(ctx, "task_a")
+		require.True(t, ok)
+		assert.True(t, lock.RunQueued)
+
+		select {
+		case <-rendered:
+			t.Fatal("unexpected run while task is locked")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		// the task runs once it is unlocked
+		require.NoError(t, tm.TaskUnlock(ctx, "task_a"))
+		select {
+		case <-rendered:
+		case <-time.After(time.Second):
+			t.Fatal("expected task to run once it is unlocked")
+		}
+
+		assert.ErrorIs(t, tm.TaskUnlock(ctx, "task_a"), driver.ErrTaskNotLocked)
+	})
+	t.Run("locked while trigger waits for active run", func(t *testing.T) {
+		d := new(mocksD.Driver)
+		d.On("Task").Return(enabledTestTask(t, "task_a"))
+		d.On("TemplateIDs").Return(nil)
+		rendered := make(chan struct{}, 1)
+		d.On("RenderTemplate", mock.Anything).Return(false, nil).
+			Run(func(mock.Arguments) { rendered <- struct{}{} })
+
+		tm := newTestTasksManager()
+		tm.drivers.Add("task_a", d)
+		tm.drivers.SetActive("task_a")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// The trigger is not suppressed and waits for the active run
+		triggered := make(chan error, 1)
+		go func() { triggered <- tm.triggerTask(ctx, "task_a") }()
+		require.Eventually(t, func() bool {
+			return len(tm.TaskPendingRuns(ctx, "task_a")) == 1
+		}, time.Second, time.Millisecond)
+
+		// The task is locked before the active run completes
+		locked := make(chan error, 1)
+		go func() {
+			_, err := tm.TaskLock(ctx, "task_a", "alice", "")
+			locked <- err
+		}()
+		require.Eventually(t, func() bool {
+			_, ok := tm.TaskLockStatus(ctx, "task_a")
+			return ok
+		}, time.Second, time.Millisecond)
+		tm.drivers.SetInactive("task_a")
+
+		require.NoError(t, <-triggered)
+		require.NoError(t, <-locked)
+
+		// The waiting run is queued instead of running while locked
+		lock, ok := tm.TaskLockStatus(ctx, "task_a")
+		require.True(t, ok)
+		assert.True(t, lock.RunQueued)
+		select {
+		case <-rendered:
+			t.Fatal("unexpected run while task is locked")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		require.NoError(t, tm.TaskUnlock(ctx, "task_a"))
+		select {
+		case <-rendered:
+		case <-time.After(time.Second):
+			t.Fatal("expected task to run once it is unlocked")
+		}
+	})
+}
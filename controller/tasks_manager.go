@@ -6,6 +6,9 @@ package controller
 import (
 	"context"
 	"fmt"
+	"os"
+	"sort"
+	"sync/atomic"
 	"time"
 
 	"github.com/hashicorp/consul-terraform-sync/config"
@@ -14,7 +17,10 @@ import (
 	"github.com/hashicorp/consul-terraform-sync/retry"
 	"github.com/hashicorp/consul-terraform-sync/state"
 	"github.com/hashicorp/consul-terraform-sync/state/event"
+	"github.com/hashicorp/consul-terraform-sync/state/plan"
+	"github.com/hashicorp/consul-terraform-sync/state/revision"
 	"github.com/hashicorp/consul-terraform-sync/templates"
+	"github.com/hashicorp/cronexpr"
 	"github.com/pkg/errors"
 )
 
@@ -30,6 +36,51 @@ type TasksManager struct {
 
 	retry retry.Retry
 
+	// cooldowns tracks the last run of tasks and any runs deferred because
+	// the task was triggered within its cooldown
+	cooldowns *taskCooldowns
+
+	// windowRuns tracks the runs deferred to the end of the maintenance
+	// window for tasks that were triggered during their window
+	windowRuns *taskCooldowns
+
+	// gateRuns tracks the runs queued until the closed gates of tasks open
+	gateRuns *taskGates
+
+	// locks tracks the advisory locks of tasks and the runs queued until the
+	// tasks are unlocked
+	locks *taskLocks
+
+	// expirations tracks the tasks that are scheduled to expire
+	expirations *taskCooldowns
+
+	// breakers tracks the consecutive failures of tasks and the tasks paused
+	// by their circuit breaker
+	breakers *taskCircuitBreakers
+
+	// pendingRuns tracks the runs of tasks that are queued waiting for the
+	// active run of the task to complete
+	pendingRuns *taskPendingRuns
+
+	// providerLimits limits the frequency of the runs of tasks that use
+	// providers configured with a rate limit
+	providerLimits *providerRateLimits
+
+	// consulResources returns the lookup of the datacenters and namespaces
+	// of the connected Consul to check the references of new tasks. The
+	// references are not checked when nil.
+	consulResources func() consulResources
+
+	// plans stores the plan artifacts of task runs. It is nil when plan
+	// artifacts are not enabled
+	plans plan.Store
+
+	// changedOnly skips running new tasks that have not changed since their
+	// last successful run when set to 1. It is only set in once mode,
+	// including when the daemon runs the tasks once at startup. Accessed
+	// atomically since it is toggled while tasks are running.
+	changedOnly int32
+
 	// createdScheduleCh sends the task name of newly created scheduled tasks
 	// that will need to be monitored
 	createdScheduleCh chan string
@@ -56,12 +107,29 @@ func NewTasksManager(conf *config.Config, state state.Store, watcher templates.W
 		return nil, err
 	}
 
+	var plans plan.Store
+	if conf.PlanArtifacts != nil && config.BoolVal(conf.PlanArtifacts.Enabled) {
+		path := config.StringVal(conf.PlanArtifacts.Path)
+		logger.Info("plan artifacts enabled", "path", path)
+		plans = plan.NewLocalStore(path)
+	}
+
 	return &TasksManager{
 		logger:            logger,
 		factory:           factory,
 		state:             state,
 		drivers:           driver.NewDrivers(),
 		retry:             retry.NewRetry(defaultRetry, time.Now().UnixNano()),
+		cooldowns:         newTaskCooldowns(),
+		windowRuns:        newTaskCooldowns(),
+		gateRuns:          newTaskGates(factory.gateKV),
+		locks:             newTaskLocks(),
+		expirations:       newTaskCooldowns(),
+		breakers:          newTaskCircuitBreakers(),
+		pendingRuns:       newTaskPendingRuns(),
+		providerLimits:    newProviderRateLimits(conf.TerraformProviders.RateLimits()),
+		consulResources:   factory.consulResources,
+		plans:             plans,
 		createdScheduleCh: make(chan string, 100), // arbitrarily chosen size
 		deletedScheduleCh: make(chan string, 100), // arbitrarily chosen size
 	}, nil
@@ -173,6 +241,34 @@ func (tm *TasksManager) TaskInspect(ctx context.Context, taskConfig config.TaskC
 	return plan.ChangesPresent, plan.Plan, plan.URL, err
 }
 
+// TaskValidate validates a task configuration as it is validated to create
+// the task, and checks the variables of the task's module. Unlike
+// TaskInspect, no task driver is created and Terraform is not run. Returns an
+// error if the configuration is invalid or the module cannot be checked.
+func (tm *TasksManager) TaskValidate(_ context.Context, taskConfig config.TaskConfig) (driver.ModuleValidation, error) {
+	taskConfig = *taskConfig.Copy()
+	if err := taskConfig.Finalize(); err != nil {
+		return driver.ModuleValidation{}, err
+	}
+	if err := taskConfig.Validate(); err != nil {
+		return driver.ModuleValidation{}, err
+	}
+
+	taskName := *taskConfig.Name
+	if _, ok := tm.drivers.Get(taskName); ok {
+		return driver.ModuleValidation{}, fmt.Errorf("task with name %s "+
+			"already exists", taskName)
+	}
+
+	conf := tm.state.GetConfig()
+	tc := taskConfig.InheritParentConfig(*conf.WorkingDir, *conf.BufferPeriod)
+	if err := tc.ValidateForDriver(); err != nil {
+		return driver.ModuleValidation{}, err
+	}
+
+	return driver.ValidateTaskModule(*tc)
+}
+
 // TaskUpdate patches a managed task with the provided configuration.
 // If runOp is set to runtimeNow it will immediately run before completing the update, otherwise it will perform
 // the update without running.
@@ -189,7 +285,7 @@ func (tm *TasksManager) TaskUpdate(ctx context.Context, updateConf config.TaskCo
 	logger := tm.logger.With(taskNameLogKey, taskName)
 	logger.Trace("updating task")
 	if tm.drivers.IsActive(taskName) {
-		return false, "", "", fmt.Errorf("task '%s' is active and cannot be updated at this time", taskName)
+		return false, "", "", &driver.TaskActiveError{Name: taskName, Action: "updated"}
 	}
 	tm.drivers.SetActive(taskName)
 	defer tm.drivers.SetInactive(taskName)
@@ -200,9 +296,11 @@ func (tm *TasksManager) TaskUpdate(ctx context.Context, updateConf config.TaskCo
 	}
 
 	var storedErr error
+	var ev *event.Event
 	if runOp == driver.RunOptionNow {
 		task := d.Task()
-		ev, err := event.NewEvent(taskName, &event.Config{
+		var err error
+		ev, err = event.NewEvent(taskName, &event.Config{
 			Providers: task.ProviderIDs(),
 			Services:  task.ServiceNames(),
 			Source:    task.Module(),
@@ -230,20 +328,179 @@ func (tm *TasksManager) TaskUpdate(ctx context.Context, updateConf config.TaskCo
 			logger.Error("error while setting task state", "error", err)
 			return false, "", "", err
 		}
+		tm.addTaskRevision(ctx, updateConf)
+	}
+
+	if *updateConf.Enabled && runOp != driver.RunOptionInspect {
+		if tm.breakers.Reset(taskName) {
+			logger.Info("resuming task paused by its circuit breaker")
+		}
 	}
 
 	patch := driver.PatchTask{
 		RunOption: runOp,
 		Enabled:   *updateConf.Enabled,
 	}
-	var plan driver.InspectPlan
-	plan, storedErr = d.UpdateTask(ctx, patch)
+	var inspectPlan driver.InspectPlan
+	inspectPlan, storedErr = d.UpdateTask(ctx, patch)
+	if ev != nil {
+		setRunMetadata(d.Task(), ev)
+	}
 	if storedErr != nil {
 		logger.Trace("error while updating task", "error", storedErr)
 		return false, "", "", storedErr
 	}
 
-	return plan.ChangesPresent, plan.Plan, "", nil
+	if ev != nil {
+		tm.savePlanArtifact(d.Task(), ev)
+		tm.saveInputSnapshot(d.Task(), ev)
+	}
+
+	return inspectPlan.ChangesPresent, inspectPlan.Plan, "", nil
+}
+
+// TaskRevisions returns the revisions of a task's configuration, latest
+// first. Revisions of a deleted task are returned until CTS is restarted.
+func (tm *TasksManager) TaskRevisions(_ context.Context, taskName string) ([]revision.Revision, error) {
+	revs := tm.state.GetTaskRevisions(taskName)
+	if len(revs) == 0 {
+		return nil, fmt.Errorf("no revisions found for task '%s'", taskName)
+	}
+	return revs, nil
+}
+
+// TaskRestoreRevision restores a task to the configuration of a revision. The
+// task is recreated with the configuration of the revision, which is recorded
+// as the latest revision of the task. A deleted task is created again. If the
+// task fails to be recreated, the prior configuration of the task is restored.
+func (tm *TasksManager) TaskRestoreRevision(ctx context.Context, taskName string, id int) (config.TaskConfig, error) {
+	var rev *revision.Revision
+	for _, r := range tm.state.GetTaskRevisions(taskName) {
+		if r.ID == id {
+			rev = &r
+			break
+		}
+	}
+	if rev == nil {
+		return config.TaskConfig{}, fmt.Errorf("revision %d not found for "+
+			"task '%s'", id, taskName)
+	}
+
+	logger := tm.logger.With(taskNameLogKey, taskName, "revision_id", id)
+	logger.Info("restoring task configuration")
+
+	priorConf, exists := tm.state.GetTask(taskName)
+	if exists {
+		if tm.drivers.IsMarkedForDeletion(taskName) {
+			return config.TaskConfig{}, fmt.Errorf("task '%s' is marked for "+
+				"deletion and cannot be restored", taskName)
+		}
+		if err := tm.deleteTask(ctx, taskName); err != nil {
+			return config.TaskConfig{}, err
+		}
+	}
+
+	restoredConf, err := tm.TaskCreate(ctx, rev.Config)
+	if err != nil {
+		logger.Error("error restoring task configuration", "error", err)
+		if exists {
+			if _, priorErr := tm.TaskCreate(ctx, priorConf); priorErr != nil {
+				logger.Error("error recreating task with its prior configuration",
+					"error", priorErr)
+			}
+		}
+		return config.TaskConfig{}, err
+	}
+
+	return restoredConf, nil
+}
+
+// IsProviderTemplate returns whether the template fetches dynamic values of
+// the provider blocks, e.g. values from Consul KV.
+func (tm *TasksManager) IsProviderTemplate(tmplID string) bool {
+	return tm.factory.IsProviderTemplate(tmplID)
+}
+
+// TaskReloadProviders reloads the provider blocks with dynamic values and
+// re-initializes the tasks that use the providers with changed configuration,
+// e.g. rotated credentials stored in Consul KV. The configuration and events
+// of the tasks are unchanged and the tasks use the updated provider
+// configuration when they next run.
+func (tm *TasksManager) TaskReloadProviders(ctx context.Context) error {
+	changed, err := tm.factory.ReloadProviders(ctx)
+	if err != nil {
+		return err
+	}
+	if len(changed) == 0 {
+		tm.logger.Trace("provider configuration unchanged")
+		return nil
+	}
+	tm.logger.Info("provider configuration changed, re-initializing tasks",
+		"providers", changed)
+
+	var lastErr error
+	for _, tc := range tm.state.GetAllTasks() {
+		if !usesProviders(*tc, changed) {
+			continue
+		}
+		if err := tm.reinitTask(ctx, *tc); err != nil {
+			lastErr = err
+		}
+	}
+	return lastErr
+}
+
+// reinitTask replaces the driver of an existing task with a new driver that
+// is created from the configuration of the task, e.g. to use updated provider
+// configuration
+func (tm *TasksManager) reinitTask(ctx context.Context, tc config.TaskConfig) error {
+	name := config.StringVal(tc.Name)
+	logger := tm.logger.With(taskNameLogKey, name)
+
+	if tm.drivers.IsMarkedForDeletion(name) {
+		logger.Trace("task is marked for deletion, skipping re-initializing")
+		return nil
+	}
+
+	if err := tm.waitForTaskInactive(ctx, name); err != nil {
+		return err
+	}
+	tm.drivers.SetActive(name)
+	defer tm.drivers.SetInactive(name)
+
+	// The existing driver is deleted before creating the new driver since the
+	// drivers share the same templates
+	if err := tm.drivers.Delete(name); err != nil {
+		return err
+	}
+
+	_, d, err := tm.createTask(ctx, tc)
+	if err != nil {
+		logger.Error("error re-initializing task", "error", err)
+		return err
+	}
+
+	d.SetBufferPeriod()
+	if err := tm.drivers.Add(name, d); err != nil {
+		tm.cleanupTask(ctx, d)
+		return err
+	}
+
+	logger.Info("task re-initialized")
+	return nil
+}
+
+// usesProviders returns whether the task is configured with any of the
+// providers
+func usesProviders(tc config.TaskConfig, providerIDs []string) bool {
+	for _, id := range tc.Providers {
+		for _, pID := range providerIDs {
+			if id == pID {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // TaskCreateAndRunAllowFail creates, runs, and adds a new task. It expects that
@@ -252,8 +509,9 @@ func (tm *TasksManager) TaskUpdate(ctx context.Context, updateConf config.TaskCo
 // logging
 //
 // This method is used when we do not want the caller to error and exit when
-// creating, running, and adding a new task.
-func (tm *TasksManager) TaskCreateAndRunAllowFail(ctx context.Context, taskConfig config.TaskConfig) {
+// creating, running, and adding a new task. The first error is returned for
+// the caller to track the task, e.g. to retry the task later.
+func (tm *TasksManager) TaskCreateAndRunAllowFail(ctx context.Context, taskConfig config.TaskConfig) error {
 	logger := tm.logger.With(taskNameLogKey, *taskConfig.Name)
 
 	actionSteps := `
@@ -265,21 +523,22 @@ then restarting the instance for changes to take effect`
 	if err != nil {
 		logger.Error(fmt.Sprintf("error creating driver for task.%s", actionSteps),
 			"error", err)
-		return
+		return err
 	}
 
-	ev, err := tm.runNewTask(ctx, d, true)
+	ev, runErr := tm.runNewTask(ctx, d, true)
 
-	if err != nil {
+	if runErr != nil {
 		// Expects that this task has run successfully before and any error is
 		// intermittent and next run will succeed
 		logger.Error("error while running task once after creation. will still "+
-			"add task to CTS", "error", err)
+			"add task to CTS", "error", runErr)
 	}
 
 	if _, err := tm.addTask(ctx, *tc, d); err != nil {
 		logger.Error(fmt.Sprintf("error adding task to CTS.%s", actionSteps),
 			"error", err)
+		return err
 	}
 
 	// Store event from runNewTask now that the task has been successfully added
@@ -291,7 +550,12 @@ then restarting the instance for changes to take effect`
 		}
 	}
 
+	if runErr != nil {
+		return runErr
+	}
+
 	logger.Info("task was created and run successfully")
+	return nil
 }
 
 // addTask handles the necessary steps to add a task for CTS to monitor and run.
@@ -300,7 +564,7 @@ then restarting the instance for changes to take effect`
 // Assumes that the task driver has already been successfully created. On any
 // error, the task will be cleaned up. Returns a copy of the added task's
 // config
-func (tm TasksManager) addTask(ctx context.Context, tc config.TaskConfig, d driver.Driver) (config.TaskConfig, error) {
+func (tm *TasksManager) addTask(ctx context.Context, tc config.TaskConfig, d driver.Driver) (config.TaskConfig, error) {
 	d.SetBufferPeriod()
 
 	name := d.Task().Name()
@@ -313,14 +577,96 @@ func (tm TasksManager) addTask(ctx context.Context, tc config.TaskConfig, d driv
 		tm.cleanupTask(ctx, d)
 		return config.TaskConfig{}, err
 	}
+	tm.addTaskRevision(ctx, tc)
 
 	if d.Task().IsScheduled() {
 		tm.createdScheduleCh <- name
 	}
 
+	tm.scheduleExpiration(name, tc)
+
 	return tc, nil
 }
 
+// scheduleExpiration schedules a task with an expiration time to expire. The
+// task expires right away if the time has already passed, e.g. for a task in
+// the configuration file with an expires_at in the past.
+func (tm *TasksManager) scheduleExpiration(name string, tc config.TaskConfig) {
+	if tc.ExpiresAt == nil {
+		return
+	}
+
+	expiresAt := *tc.ExpiresAt
+	action := config.StringVal(tc.ExpireAction)
+	tm.expirations.Defer(name, time.Until(expiresAt), func() {
+		// Use new context. Tasks created by the API would otherwise expire
+		// with the context of the API request.
+		tm.expireTask(context.Background(), name, action)
+	})
+	tm.logger.Debug("task expiration scheduled", taskNameLogKey, name,
+		"expires_at", expiresAt, "expire_action", action)
+}
+
+// expireTask records an expired event for a task and then disables or
+// deletes the task based on the expire action. The events of a deleted task,
+// including the expired event, are deleted with the task.
+func (tm *TasksManager) expireTask(ctx context.Context, name, action string) {
+	logger := tm.logger.With(taskNameLogKey, name)
+
+	d, ok := tm.drivers.Get(name)
+	if !ok || tm.drivers.IsMarkedForDeletion(name) {
+		logger.Trace("expired task no longer exists")
+		return
+	}
+
+	logger.Info("task expired", "expire_action", action)
+	tm.addExpiredEvent(d.Task())
+
+	if action == config.TaskExpireActionDelete {
+		if err := tm.TaskDelete(ctx, name); err != nil {
+			logger.Error("error deleting expired task", "error", err)
+		}
+		return
+	}
+
+	ctx = revision.WithActor(ctx, revision.ActorExpiration)
+	for {
+		if err := tm.waitForTaskInactive(ctx, name); err != nil {
+			logger.Error("error disabling expired task", "error", err)
+			return
+		}
+
+		tc, ok := tm.state.GetTask(name)
+		if !ok {
+			logger.Trace("expired task no longer exists")
+			return
+		}
+		tc.Enabled = config.Bool(false)
+
+		// The task can become active again before it is updated
+		_, _, _, err := tm.TaskUpdate(ctx, tc, "")
+		var activeErr *driver.TaskActiveError
+		if errors.As(err, &activeErr) {
+			continue
+		}
+		if err != nil {
+			logger.Error("error disabling expired task", "error", err)
+		}
+		return
+	}
+}
+
+// addTaskRevision records a revision of the task configuration. The actor of
+// the revision is retrieved from the context.
+func (tm TasksManager) addTaskRevision(ctx context.Context, tc config.TaskConfig) {
+	actor := revision.ActorFromContext(ctx)
+	if err := tm.state.AddTaskRevision(tc, actor); err != nil {
+		// only log error since the task configuration is already stored
+		tm.logger.Error("error storing task revision", taskNameLogKey,
+			config.StringVal(tc.Name), "actor", actor, "error", err)
+	}
+}
+
 // cleanupTask cleans up a newly created task that has not yet been added to CTS
 // and started monitoring. Use TaskDelete for added and monitored tasks
 func (tm TasksManager) cleanupTask(ctx context.Context, d driver.Driver) {
@@ -342,6 +688,19 @@ func (tm TasksManager) cleanupTask(ctx context.Context, d driver.Driver) {
 // This can occur because driver.RenderTemplate() may need to be called multiple
 // times before a template is ready to be applied.
 func (tm *TasksManager) TaskRunNow(ctx context.Context, taskName string) error {
+	return tm.runTask(ctx, taskName, false)
+}
+
+// TaskRunCatchUp runs a scheduled task to catch up on a scheduled run that
+// was missed while CTS was not running. Unlike TaskRunNow, the task is applied
+// even if there are no dependency changes since its last run.
+func (tm *TasksManager) TaskRunCatchUp(ctx context.Context, taskName string) error {
+	return tm.runTask(ctx, taskName, true)
+}
+
+// runTask runs an existing task. When force is true, the task is applied even
+// if its template did not render new changes.
+func (tm *TasksManager) runTask(ctx context.Context, taskName string, force bool) error {
 	logger := tm.logger.With(taskNameLogKey, taskName)
 
 	if tm.drivers.IsMarkedForDeletion(taskName) {
@@ -359,17 +718,31 @@ func (tm *TasksManager) TaskRunNow(ctx context.Context, taskName string) error {
 
 	// For scheduled tasks, do not wait if task is active
 	if tm.drivers.IsActive(taskName) && task.IsScheduled() {
-		return fmt.Errorf("task '%s' is active and cannot be run at this time", taskName)
+		return &driver.TaskActiveError{Name: taskName, Action: "run"}
 	}
 
-	// For dynamic tasks, wait to see if the task will become inactive
-	if err := tm.waitForTaskInactive(ctx, taskName); err != nil {
-		return err
+	// For dynamic tasks, wait to see if the task will become inactive. The
+	// run is pending while waiting.
+	if tm.drivers.IsActive(taskName) {
+		queuedAt := tm.pendingRuns.Add(taskName)
+		err := tm.waitForTaskInactive(ctx, taskName)
+		tm.pendingRuns.Remove(taskName, queuedAt)
+		if err != nil {
+			return err
+		}
 	}
 
 	tm.drivers.SetActive(taskName)
 	defer tm.drivers.SetInactive(taskName)
 
+	// The task may have been locked while this run waited for the task to
+	// become inactive. TaskLock returns the lock once the task is inactive, so
+	// without this check the run could apply while the task is locked. Queue
+	// the run until the task is unlocked instead.
+	if tm.TaskSuppressByLock(ctx, taskName) {
+		return nil
+	}
+
 	// Note: order of these checks matters. Must check task.enabled after the
 	// in/active checks. It's possible that the task becomes disabled during the
 	// active period.
@@ -390,6 +763,11 @@ func (tm *TasksManager) TaskRunNow(ctx context.Context, taskName string) error {
 		return nil
 	}
 
+	if _, open := tm.breakers.Open(taskName); open {
+		logger.Debug("task is paused by its circuit breaker, skipping")
+		return nil
+	}
+
 	// setup to store event information
 	ev, err := event.NewEvent(taskName, &event.Config{
 		Providers: task.ProviderIDs(),
@@ -407,6 +785,9 @@ func (tm *TasksManager) TaskRunNow(ctx context.Context, taskName string) error {
 		if err := tm.state.AddTaskEvent(*ev); err != nil {
 			logger.Error("error storing event", "event", ev.GoString())
 		}
+		if storedErr != nil {
+			tm.recordTaskFailure(ctx, task)
+		}
 	}
 	ev.Start()
 
@@ -418,6 +799,18 @@ func (tm *TasksManager) TaskRunNow(ctx context.Context, taskName string) error {
 			taskName, storedErr)
 	}
 
+	if !rendered && tm.breakers.Retrying(taskName) {
+		// The retry after a circuit breaker backoff applies the task even
+		// without new changes since the previous runs failed to apply
+		logger.Debug("retrying task paused by its circuit breaker")
+		rendered = true
+	}
+
+	if !rendered && force {
+		logger.Debug("applying task without new changes")
+		rendered = true
+	}
+
 	if !rendered {
 		if task.IsScheduled() {
 			// We want to store an event even when a scheduled task did not
@@ -432,16 +825,30 @@ func (tm *TasksManager) TaskRunNow(ctx context.Context, taskName string) error {
 	// rendering a template may take several cycles in order to completely fetch
 	// new data
 	if rendered {
-		logger.Info("executing task")
 		defer storeEvent()
+		if storedErr = tm.waitForProviderRateLimits(ctx, d); storedErr != nil {
+			return storedErr
+		}
+
+		logger.Info("executing task")
+		tm.cooldowns.SetRan(taskName)
 
 		desc := fmt.Sprintf("ApplyTask %s", taskName)
 		storedErr = tm.retry.Do(ctx, d.ApplyTask, desc)
+		setRunMetadata(task, ev)
 		if storedErr != nil {
 			return fmt.Errorf("could not apply changes for task %s: %s",
 				taskName, storedErr)
 		}
 
+		if task.IsRenderOnly() {
+			ev.RenderedFiles = task.RenderedFiles()
+		}
+		tm.savePlanArtifact(task, ev)
+		tm.saveInputSnapshot(task, ev)
+		if tm.breakers.Reset(taskName) {
+			logger.Info("task succeeded, closing circuit breaker")
+		}
 		logger.Info("task completed")
 
 		if tm.ranTaskNotify != nil {
@@ -452,89 +859,838 @@ func (tm *TasksManager) TaskRunNow(ctx context.Context, taskName string) error {
 	return nil
 }
 
-// TaskByTemplate returns the name of the task associated with a template id.
-// If no task is associated with the template id, returns false.
-func (tm TasksManager) TaskByTemplate(tmplID string) (string, bool) {
-	d, ok := tm.drivers.GetTaskByTemplate(tmplID)
-	if !ok {
-		return "", false
+// waitForProviderRateLimits waits until the providers of a task that are
+// configured with a rate limit have capacity for the task to apply. Changes
+// received while the task waits are rendered before the task applies, so
+// that the triggers are coalesced into the run instead of queueing more runs
+// of the task.
+func (tm *TasksManager) waitForProviderRateLimits(ctx context.Context, d driver.Driver) error {
+	task := d.Task()
+	taskName := task.Name()
+	limited := tm.providerLimits.Limited(task.ProviderIDs())
+	if len(limited) == 0 {
+		return nil
 	}
-	return d.Task().Name(), true
-}
 
-// EnableTaskRanNotify is a helper for enabling notifications when a task has
-// finished executing after being triggered. Callers of this method must consume
-// from ranTaskNotify channel to prevent the buffered channel from filling and
-// causing a dead lock. EnableTaskRanNotify is typically used for testing.
-func (tm *TasksManager) EnableTaskRanNotify() <-chan string {
-	tasks := tm.state.GetAllTasks()
-	tm.ranTaskNotify = make(chan string, tasks.Len())
-	return tm.ranTaskNotify
-}
+	logger := tm.logger.With(taskNameLogKey, taskName)
+	logger.Trace("waiting for provider rate limits", "providers", limited)
+	waited, err := tm.providerLimits.Wait(ctx, limited)
+	if err != nil {
+		return fmt.Errorf("error waiting for provider rate limits for task "+
+			"%s: %s", taskName, err)
+	}
+	if waited == 0 {
+		return nil
+	}
 
-// EnableTaskDeletedNotify is a helper for enabling notifications when a task
-// has finished deleting. Callers of this method must consume from
-// deletedTaskNotify channel to prevent the buffered channel from filling and
-// causing a dead lock. EnableTaskDeletedNotify is typically used for testing.
-func (tm *TasksManager) EnableTaskDeletedNotify() <-chan string {
-	tasks := tm.state.GetAllTasks()
-	tm.deletedTaskNotify = make(chan string, tasks.Len())
-	return tm.deletedTaskNotify
+	logger.Info("task waited for provider rate limits", "providers", limited,
+		"waited", waited)
+	if _, err := d.RenderTemplate(ctx); err != nil {
+		return fmt.Errorf("error rendering template for task %s: %s",
+			taskName, err)
+	}
+	return nil
 }
 
-// WatchCreatedScheduleTasks returns a channel to inform any watcher that a new
-// scheduled task has been created and added to CTS.
-func (tm TasksManager) WatchCreatedScheduleTasks() <-chan string {
-	return tm.createdScheduleCh
+// TaskRunDependencies runs the dependencies of a dynamic task, configured
+// with depends_on, before the task is run. Dependencies with pending changes
+// are applied first and active dependencies are waited on, so that tasks
+// triggered by overlapping changes run in order. Returns false if the task
+// should be skipped because the latest run of a dependency failed and the task
+// is configured to skip on dependency failure.
+func (tm *TasksManager) TaskRunDependencies(ctx context.Context, taskName string) bool {
+	return tm.runDependencies(ctx, taskName, map[string]bool{taskName: true})
 }
 
-// WatchDeletedScheduleTask returns a channel to inform any watcher that a new
-// scheduled task has been deleted and removed from CTS.
-func (tm TasksManager) WatchDeletedScheduleTask() <-chan string {
-	return tm.deletedScheduleCh
-}
+// runDependencies recursively runs the dependencies of a task. Visited tracks
+// the tasks that were already run to run shared dependencies once.
+func (tm *TasksManager) runDependencies(ctx context.Context, taskName string,
+	visited map[string]bool) bool {
 
-// createTask creates and initializes a singular task from configuration
-func (tm *TasksManager) createTask(ctx context.Context, taskConfig config.TaskConfig) (*config.TaskConfig, driver.Driver, error) {
-	conf := tm.state.GetConfig()
-	if err := taskConfig.Finalize(); err != nil {
-		tm.logger.Trace("invalid config to create task", "error", err)
-		return nil, nil, err
+	d, ok := tm.drivers.Get(taskName)
+	if !ok {
+		return true
 	}
+	task := d.Task()
+	logger := tm.logger.With(taskNameLogKey, taskName)
 
-	if err := taskConfig.Validate(); err != nil {
-		tm.logger.Trace("invalid config to create task", "error", err)
-		return nil, nil, err
-	}
+	for _, dep := range task.DependsOn() {
+		if visited[dep] {
+			continue
+		}
+		visited[dep] = true
 
-	// Create a copy of the valid config, which was used to construct the driver in the factory.
-	// This should be the reusable clone that is acceptable to persist to storage.
-	validConfig := taskConfig.Copy()
+		depDriver, ok := tm.drivers.Get(dep)
+		if !ok {
+			logger.Debug("dependency of task does not exist, skipping",
+				"dependency", dep)
+			continue
+		}
 
-	// task config needs to be validated/finalized before retrieving task name
-	taskName := *taskConfig.Name
-	logger := tm.logger.With(taskNameLogKey, taskName)
+		// Scheduled dependencies only run on their schedule
+		if depDriver.Task().IsScheduled() {
+			if err := tm.waitForTaskInactive(ctx, dep); err != nil {
+				return false
+			}
+			continue
+		}
 
-	// Check if task exists, if it does, do not create again
-	if _, ok := tm.drivers.Get(taskName); ok {
-		logger.Trace("task already exists")
-		return nil, nil, fmt.Errorf("task with name %s already exists", taskName)
+		if tm.suppressTrigger(ctx, dep) {
+			continue
+		}
+		if !tm.runDependencies(ctx, dep, visited) {
+			continue
+		}
+
+		// The dependency only applies if it has pending changes. A later
+		// trigger of the dependency for the same changes is a no-op.
+		logger.Trace("running dependency before task", "dependency", dep)
+		if err := tm.TaskRunNow(ctx, dep); err != nil {
+			logger.Warn("error running dependency of task", "dependency", dep,
+				"error", err)
+		}
 	}
 
-	d, err := tm.factory.Make(ctx, &conf, taskConfig)
-	if err != nil {
-		return nil, nil, err
+	if dep, failed := tm.failedDependency(task); failed {
+		logger.Warn("skipping task since the latest run of its dependency failed",
+			"dependency", dep)
+		return false
 	}
+	return true
+}
 
-	timeout := time.After(1 * time.Minute)
-	for {
-		select {
-		case <-ctx.Done():
-			return nil, nil, ctx.Err()
-		case <-timeout:
-			logger.Error("timed out rendering template")
-			// Cleanup the task
-			d.DestroyTask(ctx)
+// failedDependency returns the name of a dependency of the task whose latest
+// run failed, if the task is configured to skip on dependency failure
+func (tm *TasksManager) failedDependency(task *driver.Task) (string, bool) {
+	if !task.SkipOnDependencyFailure() {
+		return "", false
+	}
+
+	for _, dep := range task.DependsOn() {
+		// events are ordered latest first
+		for _, ev := range tm.state.GetTaskEvents(dep)[dep] {
+			if ev.Suppressed || ev.Expired {
+				continue
+			}
+			if !ev.Success {
+				return dep, true
+			}
+			break
+		}
+	}
+	return "", false
+}
+
+// TaskPendingRuns returns the times that the pending runs of a task were
+// queued. A run is pending when the task is triggered while it is active.
+func (tm *TasksManager) TaskPendingRuns(_ context.Context, taskName string) []time.Time {
+	return tm.pendingRuns.Get(taskName)
+}
+
+// TaskStats returns the rolling statistics of the runs of a task. Returns
+// false if the task has no events.
+func (tm *TasksManager) TaskStats(_ context.Context, taskName string) (event.Stats, bool) {
+	return tm.state.GetTaskStats(taskName)
+}
+
+// TaskInventory returns the inventory of the module and providers that
+// Terraform installed for a task
+func (tm *TasksManager) TaskInventory(_ context.Context, taskName string) (driver.Inventory, error) {
+	d, ok := tm.drivers.Get(taskName)
+	if !ok {
+		return driver.Inventory{}, fmt.Errorf("task %s does not exist", taskName)
+	}
+	return driver.ReadInventory(d.Task())
+}
+
+// TaskProgress returns the progress of the latest Terraform run of a task,
+// which is active while the run is in-flight. The progress has no resources
+// if the task has not run Terraform.
+func (tm *TasksManager) TaskProgress(_ context.Context, taskName string) (driver.Progress, error) {
+	d, ok := tm.drivers.Get(taskName)
+	if !ok {
+		return driver.Progress{}, fmt.Errorf("task %s does not exist", taskName)
+	}
+
+	progress, ok := d.Task().Progress()
+	if !ok {
+		return driver.Progress{TaskName: taskName,
+			Resources: []driver.ResourceProgress{}}, nil
+	}
+	return progress, nil
+}
+
+// TaskDependencyTriggers returns the number of times that each monitored
+// dependency of a task triggered the task, by the name of the dependency.
+// Returns nil if the task does not exist.
+func (tm *TasksManager) TaskDependencyTriggers(_ context.Context, taskName string) map[string]int {
+	d, ok := tm.drivers.Get(taskName)
+	if !ok {
+		return nil
+	}
+	return d.DependencyTriggers()
+}
+
+// DependencyStats returns the statistics of the dependencies watched for the
+// templates of the tasks, including how many are shared across templates.
+// Returns empty statistics if the watcher does not track the sharing of
+// dependencies.
+func (tm *TasksManager) DependencyStats(_ context.Context) templates.DependencyStats {
+	if tm.factory == nil || tm.factory.watcher == nil {
+		return templates.DependencyStats{}
+	}
+	if statser, ok := tm.factory.watcher.(dependencyStatser); ok {
+		return statser.DependencyStats()
+	}
+	return templates.DependencyStats{}
+}
+
+// TaskReadiness returns the progress of resolving the template of a task for
+// its first run, including which of the dependencies of the template have
+// been fetched
+func (tm *TasksManager) TaskReadiness(_ context.Context, taskName string) (driver.Readiness, error) {
+	d, ok := tm.drivers.Get(taskName)
+	if !ok {
+		return driver.Readiness{}, fmt.Errorf("task %s does not exist", taskName)
+	}
+	return d.Readiness(), nil
+}
+
+// TaskReinit re-initializes a task without restarting, e.g. after the files
+// of its local module change on disk. The Terraform workspace of the task is
+// re-initialized and validated and the template is rendered, without running
+// the task. Returns an error if the task is active.
+func (tm *TasksManager) TaskReinit(ctx context.Context, taskName string) (driver.RenderResult, error) {
+	d, ok := tm.drivers.Get(taskName)
+	if !ok {
+		return driver.RenderResult{}, fmt.Errorf("task %s does not exist", taskName)
+	}
+
+	if tm.drivers.IsActive(taskName) {
+		return driver.RenderResult{}, &driver.TaskActiveError{
+			Name: taskName, Action: "reinitialized"}
+	}
+	tm.drivers.SetActive(taskName)
+	defer tm.drivers.SetInactive(taskName)
+
+	tm.logger.Info("re-initializing task", taskNameLogKey, taskName)
+	return d.ReinitTask(ctx)
+}
+
+// TaskRender renders the template of a task with the latest data of its
+// dependencies without running the task, to verify the data that the task
+// would run with. Returns an error if the task is active.
+func (tm *TasksManager) TaskRender(ctx context.Context, taskName string) (driver.RenderResult, error) {
+	d, ok := tm.drivers.Get(taskName)
+	if !ok {
+		return driver.RenderResult{}, fmt.Errorf("task %s does not exist", taskName)
+	}
+
+	if tm.drivers.IsActive(taskName) {
+		return driver.RenderResult{}, &driver.TaskActiveError{
+			Name: taskName, Action: "rendered"}
+	}
+	tm.drivers.SetActive(taskName)
+	defer tm.drivers.SetInactive(taskName)
+
+	tm.logger.Info("rendering task without running", taskNameLogKey, taskName)
+	return d.RenderTask(ctx)
+}
+
+// TaskNextScheduledRun returns the time of the next run of a scheduled task.
+// Returns false if the task does not exist, is disabled, or does not have a
+// schedule condition.
+func (tm *TasksManager) TaskNextScheduledRun(ctx context.Context, taskName string) (time.Time, bool) {
+	task, err := tm.Task(ctx, taskName)
+	if err != nil || !config.BoolVal(task.Enabled) {
+		return time.Time{}, false
+	}
+
+	cond, ok := task.Condition.(*config.ScheduleConditionConfig)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	s, err := parseSchedule(cond.CronExpressions())
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	next := s.Next(time.Now())
+	return next, !next.IsZero()
+}
+
+// TaskCircuitBreakerOpen returns true if a task is paused because its circuit
+// breaker is open. The time that the task will be retried is also returned,
+// which is zero if the task is only resumed manually.
+func (tm *TasksManager) TaskCircuitBreakerOpen(_ context.Context, taskName string) (time.Time, bool) {
+	return tm.breakers.Open(taskName)
+}
+
+// recordTaskFailure records a failed run of a task with a circuit breaker.
+// When the task fails the threshold number of consecutive times, the circuit
+// breaker opens: the task is paused and an event is stored for the task
+// becoming degraded. The task is retried after the backoff or when it is
+// manually resumed by enabling the task.
+func (tm *TasksManager) recordTaskFailure(ctx context.Context, task *driver.Task) {
+	cb, ok := task.CircuitBreaker()
+	if !ok {
+		return
+	}
+
+	taskName := task.Name()
+	logger := tm.logger.With(taskNameLogKey, taskName)
+	backoff, tripped := tm.breakers.Failure(taskName, cb, func() {
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Info("circuit breaker backoff ended, retrying task")
+		if err := tm.TaskRunNow(ctx, taskName); err != nil {
+			logger.Error("error retrying task", "error", err)
+		}
+	})
+	if !tripped {
+		return
+	}
+
+	var degradedErr error
+	if backoff > 0 {
+		logger.Warn("task failed consecutively, pausing task", "threshold",
+			cb.Threshold, "retry_in", backoff)
+		degradedErr = fmt.Errorf("task paused after %d consecutive failures, "+
+			"retrying in %s", cb.Threshold, backoff)
+	} else {
+		logger.Warn("task failed consecutively, pausing task until it is "+
+			"resumed", "threshold", cb.Threshold)
+		degradedErr = fmt.Errorf("task paused after %d consecutive failures "+
+			"until it is resumed by enabling the task", cb.Threshold)
+	}
+
+	ev, err := event.NewEvent(taskName, &event.Config{
+		Providers: task.ProviderIDs(),
+		Services:  task.ServiceNames(),
+		Source:    task.Module(),
+	})
+	if err != nil {
+		logger.Error("error creating degraded event", "error", err)
+		return
+	}
+	ev.Start()
+	ev.Degraded = true
+	ev.End(degradedErr)
+	logger.Trace("adding event", "event", ev.GoString())
+	if err := tm.state.AddTaskEvent(*ev); err != nil {
+		logger.Error("error storing event", "event", ev.GoString(), "error", err)
+	}
+}
+
+// suppressTrigger checks whether a trigger of a task is suppressed by the
+// lock, maintenance window, gates, or cooldown of the task, in that order of
+// precedence. The first mechanism that suppresses the trigger defers the run
+// of the task. Returns true if the trigger was suppressed.
+func (tm *TasksManager) suppressTrigger(ctx context.Context, taskName string) bool {
+	return tm.TaskSuppressByLock(ctx, taskName) ||
+		tm.TaskSuppressInMaintenanceWindow(ctx, taskName) ||
+		tm.TaskSuppressByGate(ctx, taskName) ||
+		tm.TaskSuppressInCooldown(ctx, taskName)
+}
+
+// triggerTask runs a task for a trigger unless the trigger is suppressed.
+// The dependencies of the task are run before the task. Both triggers from
+// the condition monitor and runs deferred by a suppressed trigger go through
+// triggerTask, so that a deferred run is checked with the same precedence as
+// a new trigger and runs the dependencies of the task first.
+func (tm *TasksManager) triggerTask(ctx context.Context, taskName string) error {
+	if tm.suppressTrigger(ctx, taskName) {
+		return nil
+	}
+	if !tm.TaskRunDependencies(ctx, taskName) {
+		return nil
+	}
+	return tm.TaskRunNow(ctx, taskName)
+}
+
+// TaskSuppressInCooldown checks whether a dynamic task was triggered within
+// its cooldown. If so, the trigger is suppressed and the task is deferred to
+// run once the cooldown ends. Returns true if the trigger was suppressed.
+//
+// Triggers are deduplicated: only the first suppressed trigger within a
+// cooldown stores a suppressed event and defers a run. Later triggers within
+// the same cooldown are covered by the deferred run.
+func (tm *TasksManager) TaskSuppressInCooldown(ctx context.Context, taskName string) bool {
+	d, ok := tm.drivers.Get(taskName)
+	if !ok {
+		return false
+	}
+
+	task := d.Task()
+	if task.IsScheduled() {
+		return false
+	}
+
+	remaining := tm.cooldowns.Remaining(taskName, task.Cooldown())
+	if remaining <= 0 {
+		return false
+	}
+
+	logger := tm.logger.With(taskNameLogKey, taskName)
+	deferred := tm.cooldowns.Defer(taskName, remaining, func() {
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Debug("cooldown ended, triggering task with suppressed triggers")
+		if err := tm.triggerTask(ctx, taskName); err != nil {
+			logger.Error("error running task after cooldown", "error", err)
+		}
+	})
+	if !deferred {
+		logger.Trace("task triggered within cooldown, run already deferred")
+		return true
+	}
+
+	logger.Info("task triggered within cooldown, suppressing trigger",
+		"cooldown_remaining", remaining)
+	tm.addSuppressedEvent(task)
+	return true
+}
+
+// TaskSuppressInMaintenanceWindow checks whether a dynamic task was triggered
+// during its maintenance window. If so, the trigger is queued and the task is
+// deferred to run once the window ends. Returns true if the trigger was
+// suppressed.
+//
+// Like cooldowns, only the first suppressed trigger within a window stores a
+// suppressed event and defers a run.
+func (tm *TasksManager) TaskSuppressInMaintenanceWindow(ctx context.Context, taskName string) bool {
+	d, ok := tm.drivers.Get(taskName)
+	if !ok {
+		return false
+	}
+
+	task := d.Task()
+	if task.IsScheduled() {
+		return false
+	}
+
+	mw, ok := task.MaintenanceWindow()
+	if !ok {
+		return false
+	}
+
+	logger := tm.logger.With(taskNameLogKey, taskName)
+	expr, err := cronexpr.Parse(mw.Cron)
+	if err != nil {
+		logger.Error("error parsing maintenance window cron", "cron", mw.Cron,
+			"error", err)
+		return false
+	}
+
+	remaining := maintenanceWindowRemaining(expr, mw.Duration, time.Now())
+	if remaining <= 0 {
+		return false
+	}
+
+	deferred := tm.windowRuns.Defer(taskName, remaining, func() {
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Info("maintenance window ended, triggering task with queued triggers")
+		if err := tm.triggerTask(ctx, taskName); err != nil {
+			logger.Error("error running task after maintenance window", "error", err)
+		}
+	})
+	if !deferred {
+		logger.Trace("task triggered during maintenance window, run already deferred")
+		return true
+	}
+
+	logger.Info("task triggered during maintenance window, queuing trigger",
+		"window_remaining", remaining)
+	tm.addSuppressedEvent(task)
+	return true
+}
+
+// TaskSuppressByGate checks whether a task was triggered while one of its
+// gates is closed, e.g. while a change freeze flag is set in Consul KV. If so,
+// the trigger is queued and the task runs once all of its gates are open.
+// Returns true if the trigger was suppressed. A gate that is unable to be
+// looked up is closed.
+//
+// Like maintenance windows, only the first suppressed trigger while a gate is
+// closed stores a suppressed event and queues a run.
+func (tm *TasksManager) TaskSuppressByGate(ctx context.Context, taskName string) bool {
+	d, ok := tm.drivers.Get(taskName)
+	if !ok {
+		return false
+	}
+
+	task := d.Task()
+	gates := task.Gates()
+	if len(gates) == 0 {
+		return false
+	}
+
+	logger := tm.logger.With(taskNameLogKey, taskName)
+	gate, closed, err := tm.gateRuns.Closed(ctx, gates)
+	if !closed {
+		return false
+	}
+	if err != nil {
+		logger.Error("error checking task gate, treating gate as closed",
+			"path", gate.Path, "error", err)
+	}
+
+	queued := tm.gateRuns.Wait(ctx, logger, taskName, gates, func() {
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Info("task gates opened, triggering task with queued triggers")
+		if err := tm.triggerTask(ctx, taskName); err != nil {
+			logger.Error("error running task after gates opened", "error", err)
+		}
+	})
+	if !queued {
+		logger.Trace("task triggered while gate is closed, run already queued")
+		return true
+	}
+
+	logger.Info("task triggered while gate is closed, queuing trigger",
+		"path", gate.Path, "equals", gate.Equals)
+	tm.addSuppressedEvent(task)
+	return true
+}
+
+// TaskLock locks a task for the holder so that an operator can run Terraform
+// in the working directory of the task. Runs of the task triggered by CTS are
+// queued while the task is locked. Waits for the active run of the task to
+// complete before returning the lock.
+func (tm *TasksManager) TaskLock(ctx context.Context, taskName, holder,
+	reason string) (driver.TaskLock, error) {
+
+	if _, ok := tm.drivers.Get(taskName); !ok {
+		return driver.TaskLock{}, fmt.Errorf("task %s does not exist", taskName)
+	}
+
+	lock, err := tm.locks.Lock(taskName, holder, reason, time.Now())
+	if err != nil {
+		return driver.TaskLock{}, err
+	}
+
+	logger := tm.logger.With(taskNameLogKey, taskName)
+	if err := tm.waitForTaskInactive(ctx, taskName); err != nil {
+		logger.Debug("unable to wait for task to become inactive, unlocking",
+			"error", err)
+		tm.locks.Delete(taskName)
+		return driver.TaskLock{}, err
+	}
+
+	logger.Info("task locked", "holder", holder, "reason", reason)
+	return lock, nil
+}
+
+// TaskUnlock unlocks a task. If the task was triggered while it was locked,
+// the queued run of the task is run.
+func (tm *TasksManager) TaskUnlock(_ context.Context, taskName string) error {
+	run, err := tm.locks.Unlock(taskName)
+	if err != nil {
+		return err
+	}
+
+	tm.logger.Info("task unlocked", taskNameLogKey, taskName)
+	if run != nil {
+		go run()
+	}
+	return nil
+}
+
+// TaskLockStatus returns the lock of a task. Returns false if the task is
+// not locked.
+func (tm *TasksManager) TaskLockStatus(_ context.Context, taskName string) (driver.TaskLock, bool) {
+	return tm.locks.Get(taskName)
+}
+
+// TaskSuppressByLock checks whether a task was triggered while it is locked.
+// If so, the trigger is queued and the task runs once it is unlocked. Returns
+// true if the trigger was suppressed.
+//
+// Like gates, only the first suppressed trigger while a task is locked stores
+// a suppressed event and queues a run.
+func (tm *TasksManager) TaskSuppressByLock(ctx context.Context, taskName string) bool {
+	d, ok := tm.drivers.Get(taskName)
+	if !ok {
+		return false
+	}
+
+	logger := tm.logger.With(taskNameLogKey, taskName)
+	locked, queued := tm.locks.Queue(taskName, func() {
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Info("task unlocked, triggering task with queued triggers")
+		if err := tm.triggerTask(ctx, taskName); err != nil {
+			logger.Error("error running task after unlock", "error", err)
+		}
+	})
+	if !locked {
+		return false
+	}
+	if !queued {
+		logger.Trace("task triggered while locked, run already queued")
+		return true
+	}
+
+	logger.Info("task triggered while locked, queuing trigger")
+	tm.addSuppressedEvent(d.Task())
+	return true
+}
+
+// TaskCatchUpSchedule runs a scheduled task once if a scheduled run of the
+// task was missed while CTS was not running. The missed run is caught up if
+// it is within the max age and the task has not run successfully since, e.g.
+// when all tasks are run once as CTS starts. The time of the task's last
+// scheduled run is read from and recorded in its working directory.
+func (tm *TasksManager) TaskCatchUpSchedule(ctx context.Context, taskName string,
+	expr cronSchedule, maxAge time.Duration) error {
+
+	d, ok := tm.drivers.Get(taskName)
+	if !ok {
+		return fmt.Errorf("task '%s' does not have a driver. task may have been"+
+			" deleted", taskName)
+	}
+	workingDir := d.Task().WorkingDir()
+	logger := tm.logger.With(taskNameLogKey, taskName)
+	now := time.Now()
+
+	lastRun, ok, err := readScheduleLastRun(workingDir)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		// No scheduled run was recorded yet, so missed runs are caught up
+		// from now on
+		return writeScheduleLastRun(workingDir, now)
+	}
+
+	missed, ok := missedScheduledRun(expr, lastRun, now, maxAge)
+	if !ok {
+		logger.Trace("no missed scheduled runs to catch up", "last_runtime", lastRun)
+		return nil
+	}
+
+	if tm.ranSuccessfullySince(taskName, missed) {
+		logger.Info("missed scheduled run was covered by a later run of the task",
+			"missed_runtime", missed)
+		return writeScheduleLastRun(workingDir, now)
+	}
+
+	logger.Info("catching up missed scheduled run", "missed_runtime", missed)
+	runErr := tm.TaskRunCatchUp(ctx, taskName)
+	if err := writeScheduleLastRun(workingDir, now); err != nil {
+		logger.Error("error recording scheduled run", "error", err)
+	}
+	return runErr
+}
+
+// TaskRecordScheduledRun records the time of a scheduled run of a task in its
+// working directory, for tasks that catch up missed scheduled runs
+func (tm *TasksManager) TaskRecordScheduledRun(taskName string, runTime time.Time) error {
+	d, ok := tm.drivers.Get(taskName)
+	if !ok {
+		return fmt.Errorf("task '%s' does not have a driver. task may have been"+
+			" deleted", taskName)
+	}
+	return writeScheduleLastRun(d.Task().WorkingDir(), runTime)
+}
+
+// ranSuccessfullySince returns true if the latest run of the task started
+// after the given time and succeeded
+func (tm *TasksManager) ranSuccessfullySince(taskName string, since time.Time) bool {
+	// events are ordered latest first
+	for _, ev := range tm.state.GetTaskEvents(taskName)[taskName] {
+		if ev.Suppressed || ev.Expired {
+			continue
+		}
+		return ev.Success && ev.StartTime.After(since)
+	}
+	return false
+}
+
+// addSuppressedEvent stores an event for a trigger of the task that was
+// suppressed
+func (tm *TasksManager) addSuppressedEvent(task *driver.Task) {
+	taskName := task.Name()
+	logger := tm.logger.With(taskNameLogKey, taskName)
+
+	ev, err := event.NewEvent(taskName, &event.Config{
+		Providers: task.ProviderIDs(),
+		Services:  task.ServiceNames(),
+		Source:    task.Module(),
+	})
+	if err != nil {
+		logger.Error("error creating suppressed event", "error", err)
+		return
+	}
+	ev.Start()
+	ev.Suppressed = true
+	ev.End(nil)
+	logger.Trace("adding event", "event", ev.GoString())
+	if err := tm.state.AddTaskEvent(*ev); err != nil {
+		logger.Error("error storing event", "event", ev.GoString(), "error", err)
+	}
+}
+
+// addExpiredEvent stores an event for a task that expired
+func (tm *TasksManager) addExpiredEvent(task *driver.Task) {
+	taskName := task.Name()
+	logger := tm.logger.With(taskNameLogKey, taskName)
+
+	ev, err := event.NewEvent(taskName, &event.Config{
+		Providers: task.ProviderIDs(),
+		Services:  task.ServiceNames(),
+		Source:    task.Module(),
+	})
+	if err != nil {
+		logger.Error("error creating expired event", "error", err)
+		return
+	}
+	ev.Start()
+	ev.Expired = true
+	ev.End(nil)
+	logger.Trace("adding event", "event", ev.GoString())
+	if err := tm.state.AddTaskEvent(*ev); err != nil {
+		logger.Error("error storing event", "event", ev.GoString(), "error", err)
+	}
+}
+
+// setChangedOnly configures whether new tasks that have not changed since
+// their last successful run are skipped
+func (tm *TasksManager) setChangedOnly(changedOnly bool) {
+	var v int32
+	if changedOnly {
+		v = 1
+	}
+	atomic.StoreInt32(&tm.changedOnly, v)
+}
+
+// isChangedOnly returns whether new tasks that have not changed since their
+// last successful run are skipped
+func (tm *TasksManager) isChangedOnly() bool {
+	return atomic.LoadInt32(&tm.changedOnly) == 1
+}
+
+// TaskByTemplate returns the name of the task associated with a template id.
+// If no task is associated with the template id, returns false.
+func (tm TasksManager) TaskByTemplate(tmplID string) (string, bool) {
+	d, ok := tm.drivers.GetTaskByTemplate(tmplID)
+	if !ok {
+		return "", false
+	}
+	return d.Task().Name(), true
+}
+
+// groupByPriority groups the tasks by the priority of the tasks, from the
+// highest to the lowest priority. The order of the tasks within a group is
+// kept. Tasks that no longer exist have the default priority.
+func (tm *TasksManager) groupByPriority(taskNames []string) [][]string {
+	byPriority := make(map[int][]string)
+	priorities := []int{}
+	for _, taskName := range taskNames {
+		var priority int
+		if d, ok := tm.drivers.Get(taskName); ok {
+			priority = d.Task().Priority()
+		}
+		if _, ok := byPriority[priority]; !ok {
+			priorities = append(priorities, priority)
+		}
+		byPriority[priority] = append(byPriority[priority], taskName)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(priorities)))
+
+	groups := make([][]string, 0, len(priorities))
+	for _, priority := range priorities {
+		groups = append(groups, byPriority[priority])
+	}
+	return groups
+}
+
+// EnableTaskRanNotify is a helper for enabling notifications when a task has
+// finished executing after being triggered. Callers of this method must consume
+// from ranTaskNotify channel to prevent the buffered channel from filling and
+// causing a dead lock. EnableTaskRanNotify is typically used for testing.
+func (tm *TasksManager) EnableTaskRanNotify() <-chan string {
+	tasks := tm.state.GetAllTasks()
+	tm.ranTaskNotify = make(chan string, tasks.Len())
+	return tm.ranTaskNotify
+}
+
+// EnableTaskDeletedNotify is a helper for enabling notifications when a task
+// has finished deleting. Callers of this method must consume from
+// deletedTaskNotify channel to prevent the buffered channel from filling and
+// causing a dead lock. EnableTaskDeletedNotify is typically used for testing.
+func (tm *TasksManager) EnableTaskDeletedNotify() <-chan string {
+	tasks := tm.state.GetAllTasks()
+	tm.deletedTaskNotify = make(chan string, tasks.Len())
+	return tm.deletedTaskNotify
+}
+
+// WatchCreatedScheduleTasks returns a channel to inform any watcher that a new
+// scheduled task has been created and added to CTS.
+func (tm TasksManager) WatchCreatedScheduleTasks() <-chan string {
+	return tm.createdScheduleCh
+}
+
+// WatchDeletedScheduleTask returns a channel to inform any watcher that a new
+// scheduled task has been deleted and removed from CTS.
+func (tm TasksManager) WatchDeletedScheduleTask() <-chan string {
+	return tm.deletedScheduleCh
+}
+
+// createTask creates and initializes a singular task from configuration
+func (tm *TasksManager) createTask(ctx context.Context, taskConfig config.TaskConfig) (*config.TaskConfig, driver.Driver, error) {
+	conf := tm.state.GetConfig()
+	if err := taskConfig.Finalize(); err != nil {
+		tm.logger.Trace("invalid config to create task", "error", err)
+		return nil, nil, err
+	}
+
+	if err := taskConfig.Validate(); err != nil {
+		tm.logger.Trace("invalid config to create task", "error", err)
+		return nil, nil, err
+	}
+
+	// Create a copy of the valid config, which was used to construct the driver in the factory.
+	// This should be the reusable clone that is acceptable to persist to storage.
+	validConfig := taskConfig.Copy()
+
+	// task config needs to be validated/finalized before retrieving task name
+	taskName := *taskConfig.Name
+	logger := tm.logger.With(taskNameLogKey, taskName)
+
+	// Check if task exists, if it does, do not create again
+	if _, ok := tm.drivers.Get(taskName); ok {
+		logger.Trace("task already exists")
+		return nil, nil, fmt.Errorf("task with name %s already exists", taskName)
+	}
+
+	if err := tm.checkConsulReferences(ctx, taskConfig); err != nil {
+		logger.Trace("invalid config to create task", "error", err)
+		return nil, nil, err
+	}
+
+	d, err := tm.factory.Make(ctx, &conf, taskConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	timeout := time.After(1 * time.Minute)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-timeout:
+			logger.Error("timed out rendering template")
+			// Cleanup the task
+			d.DestroyTask(ctx)
 			logger.Debug("task destroyed", "task_name", *taskConfig.Name)
 			return nil, nil, fmt.Errorf("error initializing task")
 		default:
@@ -576,6 +1732,24 @@ func (tm *TasksManager) runNewTask(ctx context.Context, d driver.Driver,
 		return nil, nil
 	}
 
+	if dep, failed := tm.failedDependency(task); failed {
+		logger.Warn("skipping task since the latest run of its dependency failed",
+			"dependency", dep)
+		return nil, nil
+	}
+
+	if tm.isChangedOnly() {
+		changed, err := d.RenderChanged()
+		if err != nil {
+			logger.Error("error checking changes for task", "error", err)
+			return nil, err
+		}
+		if !changed {
+			logger.Info("skipping task with no changes since its last run")
+			return nil, nil
+		}
+	}
+
 	// Create new event for task run
 	ev, err := event.NewEvent(taskName, &event.Config{
 		Providers: task.ProviderIDs(),
@@ -590,11 +1764,18 @@ func (tm *TasksManager) runNewTask(ctx context.Context, d driver.Driver,
 
 	// Apply task
 	err = d.ApplyTask(ctx)
+	setRunMetadata(task, ev)
 	if err != nil {
 		logger.Error("error applying task", "error", err)
 		if !allowApplyErr {
 			return nil, err
 		}
+	} else {
+		if task.IsRenderOnly() {
+			ev.RenderedFiles = task.RenderedFiles()
+		}
+		tm.savePlanArtifact(task, ev)
+		tm.saveInputSnapshot(task, ev)
 	}
 
 	ev.End(err)
@@ -606,6 +1787,98 @@ func (tm *TasksManager) runNewTask(ctx context.Context, d driver.Driver,
 	return ev, err
 }
 
+// TaskPlan returns the plan artifact saved for a task's event
+func (tm *TasksManager) TaskPlan(_ context.Context, taskName, eventID string) (plan.Artifact, error) {
+	if tm.plans == nil {
+		return plan.Artifact{}, fmt.Errorf("plan artifacts are not enabled")
+	}
+	return tm.plans.Get(taskName, eventID)
+}
+
+// TaskInputsDiff returns the difference between the input variables applied
+// by two runs of a task, identified by their event IDs
+func (tm *TasksManager) TaskInputsDiff(_ context.Context, taskName, from, to string) (driver.InputsDiff, error) {
+	d, ok := tm.drivers.Get(taskName)
+	if !ok {
+		return driver.InputsDiff{}, fmt.Errorf("task %s does not exist", taskName)
+	}
+	return d.Task().InputsDiff(from, to)
+}
+
+// setRunMetadata records the metadata of the task's latest Terraform run on
+// the event
+func setRunMetadata(task *driver.Task, ev *event.Event) {
+	run := task.LastRun()
+	if run == nil {
+		return
+	}
+
+	ev.Run = &event.RunMetadata{
+		TerraformVersion: run.TerraformVersion,
+		ModuleVersion:    run.ModuleVersion,
+		ApplyDuration:    run.ApplyDuration,
+	}
+	if run.Changes != nil {
+		ev.Run.Changes = &event.ChangeSummary{
+			Add:     run.Changes.Add,
+			Change:  run.Changes.Change,
+			Destroy: run.Changes.Destroy,
+		}
+	}
+}
+
+// savePlanArtifact stores the plan saved by the task's latest run as the plan
+// artifact for the event. Failing to store the artifact does not fail the
+// task run and is only logged.
+func (tm *TasksManager) savePlanArtifact(task *driver.Task, ev *event.Event) {
+	if tm.plans == nil || !task.SavesPlan() || task.IsRenderOnly() {
+		return
+	}
+
+	logger := tm.logger.With(taskNameLogKey, task.Name(), "event_id", ev.ID)
+	planFile, jsonFile := task.PlanFiles()
+	planBytes, err := os.ReadFile(planFile)
+	if err != nil {
+		logger.Error("unable to read saved plan file", "error", err)
+		return
+	}
+	jsonBytes, err := os.ReadFile(jsonFile)
+	if err != nil {
+		logger.Error("unable to read saved plan JSON file", "error", err)
+		return
+	}
+
+	err = tm.plans.Save(plan.Artifact{
+		TaskName: task.Name(),
+		EventID:  ev.ID,
+		Plan:     planBytes,
+		JSON:     jsonBytes,
+	})
+	if err != nil {
+		logger.Error("error storing plan artifact", "error", err)
+		return
+	}
+
+	logger.Debug("stored plan artifact")
+	ev.PlanSaved = true
+}
+
+// saveInputSnapshot stores a snapshot of the input variables applied by the
+// task's latest run for the event. Failing to store the snapshot does not
+// fail the task run and is only logged.
+func (tm *TasksManager) saveInputSnapshot(task *driver.Task, ev *event.Event) {
+	if task.InputSnapshotRetention() == 0 || task.IsRenderOnly() {
+		return
+	}
+
+	logger := tm.logger.With(taskNameLogKey, task.Name(), "event_id", ev.ID)
+	if err := task.SaveInputSnapshot(ev.ID, time.Now()); err != nil {
+		logger.Error("error storing input snapshot", "error", err)
+		return
+	}
+	logger.Debug("stored input snapshot")
+}
+
 // deleteTask deletes an existing task that has been added to CTS. If a task is
 // active and running, it will wait until the task has completed before
 // proceeding with the deletion. Deletion:
@@ -636,6 +1909,16 @@ func (tm *TasksManager) deleteTask(ctx context.Context, name string) error {
 		tm.deletedScheduleCh <- name
 	}
 
+	// Stop any run deferred by the task's cooldown or circuit breaker and
+	// the task's expiration
+	tm.cooldowns.Delete(name)
+	tm.windowRuns.Delete(name)
+	tm.gateRuns.Delete(name)
+	tm.locks.Delete(name)
+	tm.expirations.Delete(name)
+	tm.breakers.Reset(name)
+	tm.pendingRuns.Delete(name)
+
 	// Delete task from drivers
 	err = tm.drivers.Delete(name)
 	if err != nil {
@@ -7,6 +7,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -18,7 +21,12 @@ import (
 	mocksTmpl "github.com/hashicorp/consul-terraform-sync/mocks/templates"
 	"github.com/hashicorp/consul-terraform-sync/state"
 	"github.com/hashicorp/consul-terraform-sync/state/event"
+	"github.com/hashicorp/consul-terraform-sync/state/plan"
+	"github.com/hashicorp/consul-terraform-sync/state/revision"
 	"github.com/hashicorp/consul-terraform-sync/templates"
+	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl"
+	"github.com/hashicorp/cronexpr"
+	"github.com/hashicorp/hcat"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -185,6 +193,59 @@ func Test_TasksManager_TaskCreate(t *testing.T) {
 	})
 }
 
+func Test_TasksManager_TaskValidate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	conf := &config.Config{
+		BufferPeriod: config.DefaultBufferPeriodConfig(),
+		WorkingDir:   config.String(config.DefaultWorkingDir),
+	}
+	require.NoError(t, conf.Finalize())
+
+	tm := newTestTasksManager()
+	tm.state = state.NewInMemoryStore(conf)
+
+	moduleDir := filepath.Join(t.TempDir(), "module")
+	require.NoError(t, tftmpl.ScaffoldModule(tftmpl.ModuleScaffoldInput{
+		Path:      moduleDir,
+		Condition: "catalog-services",
+	}))
+
+	t.Run("remote module", func(t *testing.T) {
+		result, err := tm.TaskValidate(ctx, validTaskConf)
+		require.NoError(t, err)
+		assert.False(t, result.Checked)
+	})
+
+	t.Run("local module", func(t *testing.T) {
+		taskConf := *validTaskConf.Copy()
+		taskConf.Module = config.String(moduleDir)
+		result, err := tm.TaskValidate(ctx, taskConf)
+		require.NoError(t, err)
+		assert.True(t, result.Checked)
+		assert.Empty(t, result.Mismatches)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		taskConf := *validTaskConf.Copy()
+		taskConf.Name = config.String("")
+		_, err := tm.TaskValidate(ctx, taskConf)
+		assert.Error(t, err)
+	})
+
+	t.Run("task exists", func(t *testing.T) {
+		tm := newTestTasksManager()
+		tm.state = state.NewInMemoryStore(conf)
+		d := new(mocksD.Driver)
+		d.On("TemplateIDs").Return(nil)
+		require.NoError(t, tm.drivers.Add(validTaskName, d))
+
+		_, err := tm.TaskValidate(ctx, validTaskConf)
+		assert.Contains(t, err.Error(), "already exists")
+	})
+}
+
 func Test_TasksManager_TaskCreateAndRun(t *testing.T) {
 	// TaskCreateAndRun is similar to TaskCreate but with added run behavior.
 	// This tests what hasn't been testeed in Test_TasksManager_TaskCreate
@@ -282,6 +343,54 @@ func Test_TasksManager_TaskCreateAndRun(t *testing.T) {
 		events := tm.state.GetTaskEvents(validTaskName)
 		assert.Len(t, events, 0, "event is only stored on successful creation and run")
 	})
+
+	t.Run("changed only", func(t *testing.T) {
+		cases := []struct {
+			name       string
+			changed    bool
+			changedErr error
+			expectErr  bool
+			events     int
+		}{
+			{"changed", true, nil, false, 1},
+			{"unchanged", false, nil, false, 0},
+			{"error checking changes", false, fmt.Errorf("read err"), true, 0},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				mockD := new(mocksD.Driver)
+				mockD.On("SetBufferPeriod").Return()
+				mockD.On("RenderChanged").Return(tc.changed, tc.changedErr).Once()
+				task, err := driver.NewTask(driver.TaskConfig{
+					Enabled: true,
+					Name:    validTaskName,
+				})
+				require.NoError(t, err)
+				mockDriver(ctx, mockD, task)
+				tm.state = state.NewInMemoryStore(conf)
+				tm.drivers = driver.NewDrivers()
+				tm.factory.newDriver = func(context.Context, *config.Config, *driver.Task, templates.Watcher) (driver.Driver, error) {
+					return mockD, nil
+				}
+				tm.setChangedOnly(true)
+				defer tm.setChangedOnly(false)
+
+				_, err = tm.TaskCreateAndRun(ctx, validTaskConf)
+				if tc.expectErr {
+					assert.Error(t, err)
+				} else {
+					assert.NoError(t, err)
+				}
+
+				events := tm.state.GetTaskEvents(validTaskName)
+				assert.Len(t, events[validTaskName], tc.events)
+				if !tc.changed {
+					mockD.AssertNotCalled(t, "ApplyTask", mock.Anything)
+				}
+			})
+		}
+	})
 }
 
 func Test_TasksManager_TaskDelete(t *testing.T) {
@@ -323,6 +432,155 @@ func Test_TasksManager_TaskDelete(t *testing.T) {
 	})
 }
 
+func Test_TasksManager_TaskRestoreRevision(t *testing.T) {
+	ctx := revision.WithActor(context.Background(), revision.ActorAPI)
+	conf := &config.Config{
+		BufferPeriod: config.DefaultBufferPeriodConfig(),
+		WorkingDir:   config.String(config.DefaultWorkingDir),
+	}
+	require.NoError(t, conf.Finalize())
+
+	tm := newTestTasksManager()
+	tm.factory.watcher = new(mocksTmpl.Watcher)
+	tm.state = state.NewInMemoryStore(conf)
+
+	task, err := driver.NewTask(driver.TaskConfig{
+		Enabled: true,
+		Name:    validTaskName,
+	})
+	require.NoError(t, err)
+	mockD := new(mocksD.Driver)
+	mockD.On("SetBufferPeriod").Return()
+	mockD.On("DestroyTask", mock.Anything).Return()
+	mockDriver(ctx, mockD, task)
+
+	var driverErr error
+	tm.factory.newDriver = func(context.Context, *config.Config, *driver.Task, templates.Watcher) (driver.Driver, error) {
+		err := driverErr
+		driverErr = nil
+		return mockD, err
+	}
+
+	_, err = tm.TaskCreate(ctx, validTaskConf)
+	require.NoError(t, err)
+	updateConf := validTaskConf.Copy()
+	updateConf.Description = config.String("updated")
+	require.NoError(t, tm.state.SetTask(*updateConf))
+	require.NoError(t, tm.state.AddTaskRevision(*updateConf, revision.ActorAPI))
+
+	t.Run("success", func(t *testing.T) {
+		restored, err := tm.TaskRestoreRevision(ctx, validTaskName, 1)
+		require.NoError(t, err)
+		assert.Empty(t, config.StringVal(restored.Description))
+
+		_, ok := tm.drivers.Get(validTaskName)
+		assert.True(t, ok, "task should be recreated")
+
+		stored, ok := tm.state.GetTask(validTaskName)
+		require.True(t, ok)
+		assert.Empty(t, config.StringVal(stored.Description))
+
+		revs, err := tm.TaskRevisions(ctx, validTaskName)
+		require.NoError(t, err)
+		require.Len(t, revs, 3)
+		assert.Equal(t, 3, revs[0].ID)
+		assert.Equal(t, revision.ActorAPI, revs[0].Actor)
+	})
+
+	t.Run("revision not found", func(t *testing.T) {
+		_, err := tm.TaskRestoreRevision(ctx, validTaskName, 10)
+		assert.Error(t, err)
+
+		_, err = tm.TaskRevisions(ctx, "non-existent-task")
+		assert.Error(t, err)
+	})
+
+	t.Run("error restores prior config", func(t *testing.T) {
+		driverErr = fmt.Errorf("driver error")
+		_, err := tm.TaskRestoreRevision(ctx, validTaskName, 2)
+		assert.Error(t, err)
+
+		_, ok := tm.drivers.Get(validTaskName)
+		assert.True(t, ok, "task should be recreated with its prior config")
+
+		stored, ok := tm.state.GetTask(validTaskName)
+		require.True(t, ok)
+		assert.Empty(t, config.StringVal(stored.Description))
+	})
+}
+
+func Test_TasksManager_TaskReloadProviders(t *testing.T) {
+	ctx := context.Background()
+	conf := &config.Config{
+		BufferPeriod: config.DefaultBufferPeriodConfig(),
+		WorkingDir:   config.String(config.DefaultWorkingDir),
+	}
+	require.NoError(t, conf.Finalize())
+
+	tm := newTestTasksManager()
+	tm.state = state.NewInMemoryStore(conf)
+
+	w := new(mocksTmpl.Watcher)
+	w.On("Register", mock.Anything).Return(nil)
+	r := new(mocksTmpl.Resolver)
+	r.On("Run", mock.Anything, mock.Anything).Return(hcat.ResolveEvent{
+		Complete: true, Contents: []byte("token")}, nil).Once()
+	r.On("Run", mock.Anything, mock.Anything).Return(hcat.ResolveEvent{
+		Complete: true, Contents: []byte("rotated")}, nil).Once()
+	tm.factory.watcher = w
+	tm.factory.resolver = r
+	tm.factory.initConf = &config.Config{
+		TerraformProviders: &config.TerraformProviderConfigs{
+			{"providerA": map[string]interface{}{
+				"token": `{{ key "cts/providerA/token" }}`,
+			}},
+		},
+	}
+	require.NoError(t, tm.Init(ctx))
+
+	// a new mock driver is created for each task driver to track which
+	// drivers are initialized and destroyed
+	var mockDs []*mocksD.Driver
+	tm.factory.newDriver = func(_ context.Context, _ *config.Config, task *driver.Task, _ templates.Watcher) (driver.Driver, error) {
+		mockD := new(mocksD.Driver)
+		mockD.On("SetBufferPeriod").Return()
+		mockD.On("DestroyTask", mock.Anything).Return()
+		mockDriver(ctx, mockD, task)
+		mockDs = append(mockDs, mockD)
+		return mockD, nil
+	}
+
+	taskA := validTaskConf.Copy()
+	taskA.Name = config.String("task_a")
+	taskA.Providers = []string{"providerA"}
+	taskB := validTaskConf.Copy()
+	taskB.Name = config.String("task_b")
+	for _, tc := range []*config.TaskConfig{taskA, taskB} {
+		_, err := tm.TaskCreate(ctx, *tc)
+		require.NoError(t, err)
+	}
+	require.Len(t, mockDs, 2)
+
+	err := tm.TaskReloadProviders(ctx)
+	require.NoError(t, err)
+
+	// only the task using the changed provider is re-initialized
+	require.Len(t, mockDs, 3)
+	for _, mockD := range mockDs {
+		mockD.AssertNumberOfCalls(t, "InitTask", 1)
+	}
+	mockDs[0].AssertNumberOfCalls(t, "DestroyTask", 1)
+	mockDs[1].AssertNumberOfCalls(t, "DestroyTask", 0)
+	assert.Equal(t, "task_a", mockDs[2].Task().Name())
+	for _, name := range []string{"task_a", "task_b"} {
+		_, ok := tm.drivers.Get(name)
+		assert.True(t, ok)
+		_, ok = tm.state.GetTask(name)
+		assert.True(t, ok)
+	}
+	assert.False(t, tm.drivers.IsActive("task_a"))
+}
+
 func Test_TasksManager_TaskUpdate(t *testing.T) {
 	t.Parallel()
 
@@ -346,7 +604,7 @@ func Test_TasksManager_TaskUpdate(t *testing.T) {
 		}
 		err = taskConf.Finalize()
 		require.NoError(t, err)
-		task, err := newDriverTask(conf, &taskConf, nil)
+		task, err := newDriverTask(conf, &taskConf, nil, "")
 		require.NoError(t, err)
 
 		d := new(mocksD.Driver)
@@ -532,6 +790,7 @@ func Test_TasksManager_addTask(t *testing.T) {
 		// Mock state
 		s := new(mocksS.Store)
 		s.On("SetTask", mock.Anything).Return(nil).Once()
+		s.On("AddTaskRevision", mock.Anything, revision.ActorConfig).Return(nil).Once()
 		tm.state = s
 
 		// Test addTask
@@ -587,6 +846,84 @@ func Test_TasksManager_addTask(t *testing.T) {
 	})
 }
 
+func Test_TasksManager_expireTask(t *testing.T) {
+	t.Parallel()
+
+	conf := &config.Config{}
+	require.NoError(t, conf.Finalize())
+	ctx := context.Background()
+
+	t.Run("disable", func(t *testing.T) {
+		tm := newTestTasksManager()
+		tm.state = state.NewInMemoryStore(conf)
+
+		taskName := "task_a"
+		require.NoError(t, tm.state.SetTask(config.TaskConfig{
+			Name:    config.String(taskName),
+			Module:  config.String("path"),
+			Enabled: config.Bool(true),
+		}))
+
+		d := new(mocksD.Driver)
+		d.On("TemplateIDs").Return(nil)
+		d.On("Task").Return(enabledTestTask(t, taskName))
+		d.On("UpdateTask", mock.Anything, driver.PatchTask{Enabled: false}).
+			Return(driver.InspectPlan{}, nil).Once()
+		require.NoError(t, tm.drivers.Add(taskName, d))
+
+		tm.expireTask(ctx, taskName, config.TaskExpireActionDisable)
+		d.AssertExpectations(t)
+
+		events := tm.state.GetTaskEvents(taskName)[taskName]
+		require.Len(t, events, 1)
+		assert.True(t, events[0].Expired)
+		assert.True(t, events[0].Success)
+
+		tc, ok := tm.state.GetTask(taskName)
+		require.True(t, ok)
+		assert.False(t, config.BoolVal(tc.Enabled))
+		assert.Equal(t, "path", config.StringVal(tc.Module))
+
+		revs := tm.state.GetTaskRevisions(taskName)
+		require.NotEmpty(t, revs)
+		assert.Equal(t, revision.ActorExpiration, revs[0].Actor)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		tm := newTestTasksManager()
+		deletedCh := tm.EnableTaskDeletedNotify()
+
+		taskName := "task_b"
+		d := new(mocksD.Driver)
+		d.On("TemplateIDs").Return(nil)
+		d.On("Task").Return(enabledTestTask(t, taskName))
+		d.On("DestroyTask", ctx).Return()
+		require.NoError(t, tm.drivers.Add(taskName, d))
+
+		// Schedule the expiration in the past to expire the task right away
+		tm.scheduleExpiration(taskName, config.TaskConfig{
+			ExpiresAt:    config.Time(time.Now().Add(-time.Minute)),
+			ExpireAction: config.String(config.TaskExpireActionDelete),
+		})
+
+		select {
+		case n := <-deletedCh:
+			assert.Equal(t, taskName, n)
+		case <-time.After(5 * time.Second):
+			t.Fatal("expired task was not deleted")
+		}
+		assert.Equal(t, 0, tm.drivers.Len())
+	})
+
+	t.Run("deleted task", func(t *testing.T) {
+		tm := newTestTasksManager()
+
+		// No-op for a task that was deleted before it expired
+		tm.expireTask(ctx, "task_c", config.TaskExpireActionDisable)
+		assert.Empty(t, tm.state.GetTaskEvents("task_c"))
+	})
+}
+
 func Test_TasksManager_TaskRunNow(t *testing.T) {
 	t.Parallel()
 
@@ -792,6 +1129,9 @@ func Test_TasksManager_TaskRunNow(t *testing.T) {
 			break
 		}
 
+		// Check that the run is pending while the task is active
+		assert.Len(t, tm.TaskPendingRuns(ctx, validTaskName), 1)
+
 		// Set task to inactive, wait for run to happen
 		drivers.SetInactive(validTaskName)
 		select {
@@ -800,6 +1140,7 @@ func Test_TasksManager_TaskRunNow(t *testing.T) {
 		case <-tm.ranTaskNotify:
 			break
 		}
+		assert.Empty(t, tm.TaskPendingRuns(ctx, validTaskName))
 	})
 }
 
@@ -833,88 +1174,999 @@ func Test_TasksManager_TaskRunNow_Store(t *testing.T) {
 		assert.Equal(t, 4, len(taskStatuses["task_a"]))
 		assert.Equal(t, 0, len(taskStatuses["task_b"]))
 	})
-}
 
-func Test_ConditionMonitor_EnableTaskRanNotify(t *testing.T) {
-	t.Parallel()
+	t.Run("render-only-store", func(t *testing.T) {
+		task, err := driver.NewTask(driver.TaskConfig{
+			Name:       "task_a",
+			Enabled:    true,
+			RenderOnly: true,
+			WorkingDir: "sync-tasks/task_a",
+		})
+		require.NoError(t, err)
 
-	// Mock state store
-	taskConfs := config.TaskConfigs{
-		{Name: config.String("task_a")},
-		{Name: config.String("task_b")},
-	}
-	s := new(mocksS.Store)
-	s.On("GetAllTasks", mock.Anything, mock.Anything).Return(taskConfs)
+		d := new(mocksD.Driver)
+		d.On("Task").Return(task)
+		d.On("TemplateIDs").Return(nil)
+		d.On("RenderTemplate", mock.Anything).Return(true, nil)
+		d.On("ApplyTask", mock.Anything).Return(nil)
 
-	// Set up tasks manager
-	tm := newTestTasksManager()
-	tm.state = s
+		tm := newTestTasksManager()
+		tm.drivers.Add("task_a", d)
 
-	// Test EnableTaskRanNotify
-	channel := tm.EnableTaskRanNotify()
-	assert.Equal(t, 2, cap(channel))
-	s.AssertExpectations(t)
-}
+		err = tm.TaskRunNow(context.Background(), "task_a")
+		require.NoError(t, err)
 
-func Test_TasksManager_TaskCreateAndRunAllowFail(t *testing.T) {
-	// TaskCreateAndRunAllowFail is similar to TaskCreateAndRun but with
-	// modified error handling. This tests what hasn't been tested in
-	// Test_TasksManager_TaskCreate
-	ctx := context.Background()
+		events := tm.state.GetTaskEvents("task_a")["task_a"]
+		require.Len(t, events, 1)
+		assert.True(t, events[0].Success)
+		assert.Equal(t, []string{"sync-tasks/task_a/terraform.tfvars"},
+			events[0].RenderedFiles)
+	})
 
-	tm := newTestTasksManager()
-	tm.factory.watcher = new(mocksTmpl.Watcher)
+	t.Run("plan-artifact-store", func(t *testing.T) {
+		wd := t.TempDir()
+		task, err := driver.NewTask(driver.TaskConfig{
+			Name:       "task_a",
+			Enabled:    true,
+			SavePlan:   true,
+			WorkingDir: wd,
+		})
+		require.NoError(t, err)
 
-	conf := &config.Config{
-		BufferPeriod: config.DefaultBufferPeriodConfig(),
-		WorkingDir:   config.String(config.DefaultWorkingDir),
-		Driver:       config.DefaultDriverConfig(),
-	}
+		planFile, jsonFile := task.PlanFiles()
+		d := new(mocksD.Driver)
+		d.On("Task").Return(task)
+		d.On("TemplateIDs").Return(nil)
+		d.On("RenderTemplate", mock.Anything).Return(true, nil)
+		d.On("ApplyTask", mock.Anything).Return(nil).Run(func(mock.Arguments) {
+			require.NoError(t, os.WriteFile(planFile, []byte("binary plan"), 0640))
+			require.NoError(t, os.WriteFile(jsonFile, []byte(`{}`), 0640))
+		})
 
-	t.Run("run error", func(t *testing.T) {
-		mockD := new(mocksD.Driver)
+		tm := newTestTasksManager()
+		tm.plans = plan.NewLocalStore(t.TempDir())
+		tm.drivers.Add("task_a", d)
+
+		err = tm.TaskRunNow(context.Background(), "task_a")
+		require.NoError(t, err)
+
+		events := tm.state.GetTaskEvents("task_a")["task_a"]
+		require.Len(t, events, 1)
+		assert.True(t, events[0].PlanSaved)
+
+		artifact, err := tm.TaskPlan(context.Background(), "task_a", events[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("binary plan"), artifact.Plan)
+		assert.Equal(t, []byte(`{}`), artifact.JSON)
+	})
+
+	t.Run("input-snapshot", func(t *testing.T) {
+		wd := t.TempDir()
 		task, err := driver.NewTask(driver.TaskConfig{
-			Enabled: true,
-			Name:    validTaskName,
+			Name:           "task_a",
+			Enabled:        true,
+			InputSnapshots: 2,
+			WorkingDir:     wd,
 		})
 		require.NoError(t, err)
-		mockD.On("Task").Return(task).
-			On("InitTask", ctx).Return(nil).
-			On("RenderTemplate", mock.Anything).Return(true, nil).
-			On("ApplyTask", ctx).Return(fmt.Errorf("apply err")).
-			On("SetBufferPeriod").Return().Once().
-			On("TemplateIDs").Return(nil).Once()
 
-		tm.state = state.NewInMemoryStore(conf)
-		tm.drivers = driver.NewDrivers()
-		tm.factory.newDriver = func(context.Context, *config.Config, *driver.Task, templates.Watcher) (driver.Driver, error) {
-			return mockD, nil
-		}
+		d := new(mocksD.Driver)
+		d.On("Task").Return(task)
+		d.On("TemplateIDs").Return(nil)
+		d.On("RenderTemplate", mock.Anything).Return(true, nil)
+		d.On("ApplyTask", mock.Anything).Return(nil).Run(func(mock.Arguments) {
+			require.NoError(t, os.WriteFile(filepath.Join(wd, "terraform.tfvars"),
+				[]byte("services = {}\n"), 0640))
+		})
 
-		tm.TaskCreateAndRunAllowFail(ctx, validTaskConf)
+		tm := newTestTasksManager()
+		tm.drivers.Add("task_a", d)
 
-		_, ok := tm.drivers.Get(validTaskName)
-		assert.True(t, ok, "driver is added even if run is unsuccessful")
+		err = tm.TaskRunNow(context.Background(), "task_a")
+		require.NoError(t, err)
 
-		events := tm.state.GetTaskEvents(validTaskName)
-		taskEvents := events[validTaskName]
-		require.Len(t, taskEvents, 1, "event is stored even on failed run")
-		assert.False(t, taskEvents[0].Success, "event should have failed")
+		events := tm.state.GetTaskEvents("task_a")["task_a"]
+		require.Len(t, events, 1)
+		snapshots, err := task.InputSnapshots()
+		require.NoError(t, err)
+		require.Len(t, snapshots, 1)
+		assert.Equal(t, events[0].ID, snapshots[0].EventID)
 	})
 }
 
-func Test_TasksManager_deleteTask(t *testing.T) {
+func Test_TasksManager_TaskPlan(t *testing.T) {
+	t.Parallel()
+
+	t.Run("disabled", func(t *testing.T) {
+		tm := newTestTasksManager()
+		_, err := tm.TaskPlan(context.Background(), "task_a", "123")
+		assert.Error(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		tm := newTestTasksManager()
+		tm.plans = plan.NewLocalStore(t.TempDir())
+		_, err := tm.TaskPlan(context.Background(), "task_a", "123")
+		assert.ErrorIs(t, err, plan.ErrNotFound)
+	})
+}
+
+func Test_TasksManager_TaskInputsDiff(t *testing.T) {
+	t.Parallel()
+
 	ctx := context.Background()
-	mockD := new(mocksD.Driver)
 
-	testCases := []struct {
-		name         string
-		setupDrivers func() *driver.Drivers
-	}{
-		{
-			"success",
-			func() *driver.Drivers {
-				mockD.On("TemplateIDs").Return(nil)
+	t.Run("not found", func(t *testing.T) {
+		tm := newTestTasksManager()
+		_, err := tm.TaskInputsDiff(ctx, "task_a", "", "")
+		assert.Error(t, err)
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		wd := t.TempDir()
+		task, err := driver.NewTask(driver.TaskConfig{
+			Name:           "task_a",
+			Enabled:        true,
+			InputSnapshots: 2,
+			WorkingDir:     wd,
+		})
+		require.NoError(t, err)
+
+		start := time.Now()
+		for i, id := range []string{"event-a", "event-b"} {
+			require.NoError(t, os.WriteFile(filepath.Join(wd, "terraform.tfvars"),
+				[]byte(id), 0640))
+			require.NoError(t, task.SaveInputSnapshot(id,
+				start.Add(time.Duration(i)*time.Second)))
+		}
+
+		d := new(mocksD.Driver)
+		d.On("Task").Return(task)
+		d.On("TemplateIDs").Return(nil)
+		tm := newTestTasksManager()
+		require.NoError(t, tm.drivers.Add("task_a", d))
+
+		diff, err := tm.TaskInputsDiff(ctx, "task_a", "", "")
+		require.NoError(t, err)
+		assert.Equal(t, "event-a", diff.From.EventID)
+		assert.Equal(t, "event-b", diff.To.EventID)
+		assert.True(t, diff.Changed)
+	})
+}
+
+func Test_TasksManager_TaskDependencyTriggers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tm := newTestTasksManager()
+
+	triggers := map[string]int{"services.web": 2}
+	d := new(mocksD.Driver)
+	d.On("TemplateIDs").Return(nil)
+	d.On("DependencyTriggers").Return(triggers)
+	require.NoError(t, tm.drivers.Add("task_a", d))
+
+	assert.Equal(t, triggers, tm.TaskDependencyTriggers(ctx, "task_a"))
+	assert.Nil(t, tm.TaskDependencyTriggers(ctx, "task_b"))
+}
+
+func Test_TasksManager_DependencyStats(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("no watcher", func(t *testing.T) {
+		tm := newTestTasksManager()
+		assert.Equal(t, templates.DependencyStats{}, tm.DependencyStats(ctx))
+	})
+
+	t.Run("watcher without stats", func(t *testing.T) {
+		tm := newTestTasksManager()
+		tm.factory.watcher = new(mocksTmpl.Watcher)
+		assert.Equal(t, templates.DependencyStats{}, tm.DependencyStats(ctx))
+	})
+
+	t.Run("registry", func(t *testing.T) {
+		w := new(mocksTmpl.Watcher)
+		w.On("Register", mock.Anything).Return(nil)
+		registry := templates.NewRegistry(w)
+		require.NoError(t, registry.Register(&depsNotifier{id: "task_a",
+			deps: map[string]bool{"health.service(web)": true}}))
+
+		tm := newTestTasksManager()
+		tm.factory.watcher = registry
+		assert.Equal(t, templates.DependencyStats{
+			Templates:    1,
+			Dependencies: 1,
+			References:   1,
+		}, tm.DependencyStats(ctx))
+	})
+}
+
+func Test_TasksManager_TaskReadiness(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tm := newTestTasksManager()
+
+	readiness := driver.Readiness{
+		TaskName: "task_a",
+		Fetched:  1,
+		Total:    2,
+		Dependencies: []driver.DependencyReadiness{
+			{Name: "kv.block(key)"},
+			{Name: "health.service(web)", Fetched: true},
+		},
+	}
+	d := new(mocksD.Driver)
+	d.On("TemplateIDs").Return(nil)
+	d.On("Readiness").Return(readiness)
+	require.NoError(t, tm.drivers.Add("task_a", d))
+
+	actual, err := tm.TaskReadiness(ctx, "task_a")
+	require.NoError(t, err)
+	assert.Equal(t, readiness, actual)
+
+	_, err = tm.TaskReadiness(ctx, "task_b")
+	assert.Error(t, err)
+}
+
+func Test_TasksManager_TaskInventory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tm := newTestTasksManager()
+
+	task, err := driver.NewTask(driver.TaskConfig{
+		Name:       "task_a",
+		Enabled:    true,
+		Module:     "org/mod/local",
+		WorkingDir: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	d := new(mocksD.Driver)
+	d.On("TemplateIDs").Return(nil)
+	d.On("Task").Return(task)
+	require.NoError(t, tm.drivers.Add("task_a", d))
+
+	inv, err := tm.TaskInventory(ctx, "task_a")
+	require.NoError(t, err)
+	assert.Equal(t, driver.Inventory{
+		TaskName:  "task_a",
+		Module:    driver.ModuleInventory{Source: "org/mod/local"},
+		Providers: []driver.ProviderInventory{},
+	}, inv)
+
+	_, err = tm.TaskInventory(ctx, "task_b")
+	assert.Error(t, err)
+}
+
+func Test_TasksManager_TaskProgress(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tm := newTestTasksManager()
+
+	task, err := driver.NewTask(driver.TaskConfig{
+		Name:    "task_a",
+		Enabled: true,
+	})
+	require.NoError(t, err)
+
+	d := new(mocksD.Driver)
+	d.On("TemplateIDs").Return(nil)
+	d.On("Task").Return(task)
+	require.NoError(t, tm.drivers.Add("task_a", d))
+
+	// Task has not run Terraform
+	progress, err := tm.TaskProgress(ctx, "task_a")
+	require.NoError(t, err)
+	assert.Equal(t, driver.Progress{
+		TaskName:  "task_a",
+		Resources: []driver.ResourceProgress{},
+	}, progress)
+
+	_, err = tm.TaskProgress(ctx, "task_b")
+	assert.Error(t, err)
+}
+
+func Test_TasksManager_TaskSuppressInCooldown(t *testing.T) {
+	t.Parallel()
+
+	newCooldownTask := func(t *testing.T, name string, cooldown time.Duration) *driver.Task {
+		task, err := driver.NewTask(driver.TaskConfig{
+			Name:     name,
+			Enabled:  true,
+			Cooldown: cooldown,
+		})
+		require.NoError(t, err)
+		return task
+	}
+
+	t.Run("no cooldown", func(t *testing.T) {
+		d := new(mocksD.Driver)
+		d.On("Task").Return(enabledTestTask(t, "task_a"))
+		d.On("TemplateIDs").Return(nil)
+		d.On("RenderTemplate", mock.Anything).Return(true, nil)
+		d.On("ApplyTask", mock.Anything).Return(nil)
+
+		tm := newTestTasksManager()
+		tm.drivers.Add("task_a", d)
+		ctx := context.Background()
+
+		require.NoError(t, tm.TaskRunNow(ctx, "task_a"))
+		assert.False(t, tm.TaskSuppressInCooldown(ctx, "task_a"))
+	})
+
+	t.Run("suppressed within cooldown", func(t *testing.T) {
+		d := new(mocksD.Driver)
+		d.On("Task").Return(newCooldownTask(t, "task_a", time.Hour))
+		d.On("TemplateIDs").Return(nil)
+		d.On("RenderTemplate", mock.Anything).Return(true, nil)
+		d.On("ApplyTask", mock.Anything).Return(nil).Once()
+
+		tm := newTestTasksManager()
+		tm.drivers.Add("task_a", d)
+		ctx := context.Background()
+
+		// Not suppressed before the first run
+		assert.False(t, tm.TaskSuppressInCooldown(ctx, "task_a"))
+		require.NoError(t, tm.TaskRunNow(ctx, "task_a"))
+
+		// Repeated triggers are suppressed and only recorded once
+		assert.True(t, tm.TaskSuppressInCooldown(ctx, "task_a"))
+		assert.True(t, tm.TaskSuppressInCooldown(ctx, "task_a"))
+
+		events := tm.state.GetTaskEvents("task_a")["task_a"]
+		require.Len(t, events, 2)
+		assert.True(t, events[0].Suppressed)
+		assert.True(t, events[0].Success)
+		assert.False(t, events[1].Suppressed)
+
+		tm.cooldowns.Delete("task_a")
+		d.AssertExpectations(t)
+	})
+
+	t.Run("deferred run after cooldown", func(t *testing.T) {
+		d := new(mocksD.Driver)
+		d.On("Task").Return(newCooldownTask(t, "task_a", 50*time.Millisecond))
+		d.On("TemplateIDs").Return(nil)
+		d.On("RenderTemplate", mock.Anything).Return(true, nil)
+		d.On("ApplyTask", mock.Anything).Return(nil).Twice()
+
+		tm := newTestTasksManager()
+		tm.drivers.Add("task_a", d)
+		ctx := context.Background()
+
+		require.NoError(t, tm.TaskRunNow(ctx, "task_a"))
+		assert.True(t, tm.TaskSuppressInCooldown(ctx, "task_a"))
+
+		// run event, suppressed event, deferred run event
+		assert.Eventually(t, func() bool {
+			events := tm.state.GetTaskEvents("task_a")["task_a"]
+			return len(events) == 3 && !events[0].Suppressed
+		}, time.Second, 10*time.Millisecond)
+		d.AssertExpectations(t)
+	})
+
+	t.Run("deferred run runs dependencies first", func(t *testing.T) {
+		var mu sync.Mutex
+		var applied []string
+		recordApply := func(name string) func(mock.Arguments) {
+			return func(mock.Arguments) {
+				mu.Lock()
+				defer mu.Unlock()
+				applied = append(applied, name)
+			}
+		}
+
+		depD := new(mocksD.Driver)
+		depD.On("Task").Return(enabledTestTask(t, "firewall"))
+		depD.On("TemplateIDs").Return(nil)
+		depD.On("RenderTemplate", mock.Anything).Return(true, nil)
+		depD.On("ApplyTask", mock.Anything).Return(nil).Run(recordApply("firewall"))
+
+		task, err := driver.NewTask(driver.TaskConfig{
+			Name:      "lb_pools",
+			Enabled:   true,
+			Cooldown:  50 * time.Millisecond,
+			DependsOn: []string{"firewall"},
+		})
+		require.NoError(t, err)
+		d := new(mocksD.Driver)
+		d.On("Task").Return(task)
+		d.On("TemplateIDs").Return(nil)
+		d.On("RenderTemplate", mock.Anything).Return(true, nil)
+		d.On("ApplyTask", mock.Anything).Return(nil).Run(recordApply("lb_pools"))
+
+		tm := newTestTasksManager()
+		tm.drivers.Add("firewall", depD)
+		tm.drivers.Add("lb_pools", d)
+		ctx := context.Background()
+
+		require.NoError(t, tm.TaskRunNow(ctx, "lb_pools"))
+		assert.True(t, tm.TaskSuppressInCooldown(ctx, "lb_pools"))
+
+		assert.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(applied) == 3
+		}, time.Second, 10*time.Millisecond)
+		assert.Equal(t, []string{"lb_pools", "firewall", "lb_pools"}, applied,
+			"deferred run should run the dependency before the task")
+	})
+
+	t.Run("scheduled task", func(t *testing.T) {
+		d := new(mocksD.Driver)
+		d.On("Task").Return(scheduledTestTask(t, schedTaskName))
+		d.On("TemplateIDs").Return(nil)
+
+		tm := newTestTasksManager()
+		tm.drivers.Add(schedTaskName, d)
+
+		assert.False(t, tm.TaskSuppressInCooldown(context.Background(), schedTaskName))
+	})
+}
+
+func Test_TasksManager_TaskSuppressInMaintenanceWindow(t *testing.T) {
+	t.Parallel()
+
+	newWindowTask := func(t *testing.T, name string) *driver.Task {
+		task, err := driver.NewTask(driver.TaskConfig{
+			Name:    name,
+			Enabled: true,
+			// window starts every minute and lasts an hour, so the task is
+			// always within the window
+			MaintenanceWindow: &driver.MaintenanceWindow{
+				Cron:     "* * * * *",
+				Duration: time.Hour,
+			},
+		})
+		require.NoError(t, err)
+		return task
+	}
+
+	t.Run("no maintenance window", func(t *testing.T) {
+		d := new(mocksD.Driver)
+		d.On("Task").Return(enabledTestTask(t, "task_a"))
+		d.On("TemplateIDs").Return(nil)
+
+		tm := newTestTasksManager()
+		tm.drivers.Add("task_a", d)
+
+		assert.False(t, tm.TaskSuppressInMaintenanceWindow(context.Background(), "task_a"))
+	})
+
+	t.Run("suppressed within window", func(t *testing.T) {
+		d := new(mocksD.Driver)
+		d.On("Task").Return(newWindowTask(t, "task_a"))
+		d.On("TemplateIDs").Return(nil)
+
+		tm := newTestTasksManager()
+		tm.drivers.Add("task_a", d)
+		ctx := context.Background()
+
+		// Repeated triggers are suppressed and only recorded once
+		assert.True(t, tm.TaskSuppressInMaintenanceWindow(ctx, "task_a"))
+		assert.True(t, tm.TaskSuppressInMaintenanceWindow(ctx, "task_a"))
+
+		events := tm.state.GetTaskEvents("task_a")["task_a"]
+		require.Len(t, events, 1)
+		assert.True(t, events[0].Suppressed)
+		assert.True(t, events[0].Success)
+
+		tm.windowRuns.Delete("task_a")
+		d.AssertNotCalled(t, "ApplyTask", mock.Anything)
+	})
+
+	t.Run("scheduled task", func(t *testing.T) {
+		d := new(mocksD.Driver)
+		d.On("Task").Return(scheduledTestTask(t, schedTaskName))
+		d.On("TemplateIDs").Return(nil)
+
+		tm := newTestTasksManager()
+		tm.drivers.Add(schedTaskName, d)
+
+		assert.False(t, tm.TaskSuppressInMaintenanceWindow(context.Background(), schedTaskName))
+	})
+}
+
+func Test_TasksManager_TaskRunDependencies(t *testing.T) {
+	t.Parallel()
+
+	newDependentTask := func(t *testing.T, skipOnFailure bool) *driver.Task {
+		task, err := driver.NewTask(driver.TaskConfig{
+			Name:             "lb_pools",
+			Enabled:          true,
+			DependsOn:        []string{"firewall"},
+			SkipOnDepFailure: skipOnFailure,
+		})
+		require.NoError(t, err)
+		return task
+	}
+
+	cases := []struct {
+		name          string
+		skipOnFailure bool
+		rendered      bool
+		applyErr      error
+		expected      bool
+		expectedRuns  int
+	}{
+		{"dependency with changes", true, true, nil, true, 1},
+		{"dependency without changes", true, false, nil, true, 0},
+		{"failed dependency skips task", true, true, fmt.Errorf("apply err"), false, 1},
+		{"failed dependency without skip", false, true, fmt.Errorf("apply err"), true, 1},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			depD := new(mocksD.Driver)
+			depD.On("Task").Return(enabledTestTask(t, "firewall"))
+			depD.On("TemplateIDs").Return(nil)
+			depD.On("RenderTemplate", mock.Anything).Return(tc.rendered, nil)
+			depD.On("ApplyTask", mock.Anything).Return(tc.applyErr)
+
+			d := new(mocksD.Driver)
+			d.On("Task").Return(newDependentTask(t, tc.skipOnFailure))
+			d.On("TemplateIDs").Return(nil)
+
+			tm := newTestTasksManager()
+			tm.drivers.Add("firewall", depD)
+			tm.drivers.Add("lb_pools", d)
+
+			actual := tm.TaskRunDependencies(context.Background(), "lb_pools")
+			assert.Equal(t, tc.expected, actual)
+
+			depD.AssertNumberOfCalls(t, "ApplyTask", tc.expectedRuns)
+			d.AssertNotCalled(t, "RenderTemplate", mock.Anything)
+			d.AssertNotCalled(t, "ApplyTask", mock.Anything)
+		})
+	}
+
+	t.Run("deleted dependency", func(t *testing.T) {
+		d := new(mocksD.Driver)
+		d.On("Task").Return(newDependentTask(t, true))
+		d.On("TemplateIDs").Return(nil)
+
+		tm := newTestTasksManager()
+		tm.drivers.Add("lb_pools", d)
+
+		assert.True(t, tm.TaskRunDependencies(context.Background(), "lb_pools"))
+	})
+}
+
+func Test_TasksManager_TaskCatchUpSchedule(t *testing.T) {
+	t.Parallel()
+
+	// daily at 02:00
+	expr := cronexpr.MustParse("0 0 2 * * * *")
+	day := 24 * time.Hour
+
+	newScheduledTask := func(t *testing.T, workingDir string) *driver.Task {
+		task, err := driver.NewTask(driver.TaskConfig{
+			Name:    "task_a",
+			Enabled: true,
+			Condition: &config.ScheduleConditionConfig{
+				ScheduleMonitorConfig: config.ScheduleMonitorConfig{
+					Cron: config.String("0 0 2 * * * *"),
+				},
+			},
+			WorkingDir: workingDir,
+		})
+		require.NoError(t, err)
+		return task
+	}
+
+	cases := []struct {
+		name         string
+		lastRun      time.Duration // ago, zero if not recorded
+		ranSince     bool
+		expectedRuns int
+		recorded     bool
+	}{
+		{"first start", 0, false, 0, true},
+		{"no missed run", time.Minute, false, 0, false},
+		{"missed run", 2 * day, false, 1, true},
+		{"missed run covered", 2 * day, true, 0, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tc.lastRun > 0 {
+				require.NoError(t, writeScheduleLastRun(dir, time.Now().Add(-tc.lastRun)))
+			}
+
+			d := new(mocksD.Driver)
+			d.On("Task").Return(newScheduledTask(t, dir))
+			d.On("TemplateIDs").Return(nil)
+			d.On("RenderTemplate", mock.Anything).Return(false, nil)
+			d.On("ApplyTask", mock.Anything).Return(nil)
+
+			tm := newTestTasksManager()
+			tm.drivers.Add("task_a", d)
+			if tc.ranSince {
+				ev, err := event.NewEvent("task_a", nil)
+				require.NoError(t, err)
+				ev.Start()
+				ev.End(nil)
+				require.NoError(t, tm.state.AddTaskEvent(*ev))
+			}
+
+			err := tm.TaskCatchUpSchedule(context.Background(), "task_a", expr, day)
+			require.NoError(t, err)
+			d.AssertNumberOfCalls(t, "ApplyTask", tc.expectedRuns)
+
+			lastRun, ok, err := readScheduleLastRun(dir)
+			require.NoError(t, err)
+			require.True(t, ok)
+			if tc.recorded {
+				assert.WithinDuration(t, time.Now(), lastRun, 5*time.Second)
+			} else {
+				assert.WithinDuration(t, time.Now().Add(-tc.lastRun), lastRun, 5*time.Second)
+			}
+		})
+	}
+}
+
+func Test_TasksManager_TaskReinit(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("not found", func(t *testing.T) {
+		tm := newTestTasksManager()
+		_, err := tm.TaskReinit(ctx, "task_a")
+		assert.Error(t, err)
+	})
+
+	t.Run("active", func(t *testing.T) {
+		tm := newTestTasksManager()
+		d := new(mocksD.Driver)
+		d.On("TemplateIDs").Return(nil)
+		require.NoError(t, tm.drivers.Add("task_a", d))
+		tm.drivers.SetActive("task_a")
+
+		_, err := tm.TaskReinit(ctx, "task_a")
+		assert.ErrorIs(t, err, driver.ErrTaskActive)
+		d.AssertNotCalled(t, "ReinitTask", mock.Anything)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		tm := newTestTasksManager()
+		d := new(mocksD.Driver)
+		d.On("TemplateIDs").Return(nil)
+		d.On("ReinitTask", ctx).Return(driver.RenderResult{},
+			errors.New("error on validate()")).Once()
+		require.NoError(t, tm.drivers.Add("task_a", d))
+
+		_, err := tm.TaskReinit(ctx, "task_a")
+		assert.Error(t, err)
+		assert.False(t, tm.drivers.IsActive("task_a"),
+			"task should be inactive once reinitialized")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		tm := newTestTasksManager()
+		result := driver.RenderResult{
+			Complete: true,
+			Files:    []string{"terraform.tfvars"},
+		}
+		d := new(mocksD.Driver)
+		d.On("TemplateIDs").Return(nil)
+		d.On("ReinitTask", ctx).Return(result, nil).Once()
+		require.NoError(t, tm.drivers.Add("task_a", d))
+
+		actual, err := tm.TaskReinit(ctx, "task_a")
+		require.NoError(t, err)
+		assert.Equal(t, result, actual)
+		assert.False(t, tm.drivers.IsActive("task_a"),
+			"task should be inactive once reinitialized")
+		d.AssertExpectations(t)
+	})
+}
+
+func Test_TasksManager_TaskRender(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("not found", func(t *testing.T) {
+		tm := newTestTasksManager()
+		_, err := tm.TaskRender(ctx, "task_a")
+		assert.Error(t, err)
+	})
+
+	t.Run("active", func(t *testing.T) {
+		tm := newTestTasksManager()
+		d := new(mocksD.Driver)
+		d.On("TemplateIDs").Return(nil)
+		require.NoError(t, tm.drivers.Add("task_a", d))
+		tm.drivers.SetActive("task_a")
+
+		_, err := tm.TaskRender(ctx, "task_a")
+		assert.ErrorIs(t, err, driver.ErrTaskActive)
+		d.AssertNotCalled(t, "RenderTask", mock.Anything)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		tm := newTestTasksManager()
+		result := driver.RenderResult{
+			Complete: true,
+			Changed:  true,
+			Files:    []string{"terraform.tfvars"},
+		}
+		d := new(mocksD.Driver)
+		d.On("TemplateIDs").Return(nil)
+		d.On("RenderTask", ctx).Return(result, nil).Once()
+		require.NoError(t, tm.drivers.Add("task_a", d))
+
+		actual, err := tm.TaskRender(ctx, "task_a")
+		require.NoError(t, err)
+		assert.Equal(t, result, actual)
+		assert.False(t, tm.drivers.IsActive("task_a"),
+			"task should be inactive once rendered")
+		d.AssertExpectations(t)
+	})
+}
+
+func Test_TasksManager_TaskNextScheduledRun(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	scheduleCond := &config.ScheduleConditionConfig{
+		ScheduleMonitorConfig: config.ScheduleMonitorConfig{
+			Cron: config.String("*/10 * * * *"),
+		},
+	}
+
+	cases := []struct {
+		name     string
+		conf     config.TaskConfig
+		found    bool
+		expected bool
+	}{
+		{
+			"schedule condition",
+			config.TaskConfig{Enabled: config.Bool(true), Condition: scheduleCond},
+			true,
+			true,
+		},
+		{
+			"disabled",
+			config.TaskConfig{Enabled: config.Bool(false), Condition: scheduleCond},
+			true,
+			false,
+		},
+		{
+			"no schedule condition",
+			config.TaskConfig{
+				Enabled:   config.Bool(true),
+				Condition: &config.ServicesConditionConfig{},
+			},
+			true,
+			false,
+		},
+		{
+			"task not found",
+			config.TaskConfig{},
+			false,
+			false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := new(mocksS.Store)
+			s.On("GetTask", "task_a").Return(tc.conf, tc.found)
+			tm := newTestTasksManager()
+			tm.state = s
+
+			next, ok := tm.TaskNextScheduledRun(ctx, "task_a")
+			assert.Equal(t, tc.expected, ok)
+			if !tc.expected {
+				assert.True(t, next.IsZero())
+				return
+			}
+			assert.True(t, next.After(time.Now()))
+			assert.WithinDuration(t, time.Now(), next, 10*time.Minute)
+			assert.Zero(t, next.Minute()%10)
+		})
+	}
+}
+
+func Test_TasksManager_TaskRunNow_CircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	newBreakerTask := func(t *testing.T, name string, backoff time.Duration) *driver.Task {
+		task, err := driver.NewTask(driver.TaskConfig{
+			Name:    name,
+			Enabled: true,
+			CircuitBreaker: &driver.CircuitBreaker{
+				Threshold:  2,
+				Backoff:    backoff,
+				MaxBackoff: backoff,
+			},
+		})
+		require.NoError(t, err)
+		return task
+	}
+
+	t.Run("pauses after consecutive failures", func(t *testing.T) {
+		d := new(mocksD.Driver)
+		d.On("Task").Return(newBreakerTask(t, "task_a", 0))
+		d.On("TemplateIDs").Return(nil)
+		d.On("RenderTemplate", mock.Anything).Return(true, nil)
+		d.On("ApplyTask", mock.Anything).Return(errors.New("apply error")).Twice()
+
+		tm := newTestTasksManager()
+		tm.drivers.Add("task_a", d)
+		ctx := context.Background()
+
+		assert.Error(t, tm.TaskRunNow(ctx, "task_a"))
+		_, open := tm.TaskCircuitBreakerOpen(ctx, "task_a")
+		assert.False(t, open)
+
+		assert.Error(t, tm.TaskRunNow(ctx, "task_a"))
+		retryAt, open := tm.TaskCircuitBreakerOpen(ctx, "task_a")
+		assert.True(t, open)
+		assert.True(t, retryAt.IsZero())
+
+		// Paused task is not run
+		assert.NoError(t, tm.TaskRunNow(ctx, "task_a"))
+
+		// failure event, failure event, degraded event
+		events := tm.state.GetTaskEvents("task_a")["task_a"]
+		require.Len(t, events, 3)
+		assert.True(t, events[0].Degraded)
+		assert.False(t, events[0].Success)
+		assert.False(t, events[1].Degraded)
+		d.AssertExpectations(t)
+	})
+
+	t.Run("resumed by enabling the task", func(t *testing.T) {
+		d := new(mocksD.Driver)
+		d.On("Task").Return(newBreakerTask(t, "task_a", 0))
+		d.On("TemplateIDs").Return(nil)
+		d.On("RenderTemplate", mock.Anything).Return(true, nil)
+		d.On("ApplyTask", mock.Anything).Return(errors.New("apply error")).Twice()
+		d.On("ApplyTask", mock.Anything).Return(nil).Once()
+		d.On("UpdateTask", mock.Anything, mock.Anything).Return(driver.InspectPlan{}, nil)
+
+		tm := newTestTasksManager()
+		tm.drivers.Add("task_a", d)
+		ctx := context.Background()
+
+		assert.Error(t, tm.TaskRunNow(ctx, "task_a"))
+		assert.Error(t, tm.TaskRunNow(ctx, "task_a"))
+		_, open := tm.TaskCircuitBreakerOpen(ctx, "task_a")
+		require.True(t, open)
+
+		_, _, _, err := tm.TaskUpdate(ctx, config.TaskConfig{
+			Name:    config.String("task_a"),
+			Enabled: config.Bool(true),
+		}, "")
+		require.NoError(t, err)
+		_, open = tm.TaskCircuitBreakerOpen(ctx, "task_a")
+		assert.False(t, open)
+
+		assert.NoError(t, tm.TaskRunNow(ctx, "task_a"))
+		d.AssertExpectations(t)
+	})
+
+	t.Run("retried after backoff", func(t *testing.T) {
+		d := new(mocksD.Driver)
+		d.On("Task").Return(newBreakerTask(t, "task_a", 50*time.Millisecond))
+		d.On("TemplateIDs").Return(nil)
+		d.On("RenderTemplate", mock.Anything).Return(true, nil).Twice()
+		// The retry applies the task even though the template has no changes
+		d.On("RenderTemplate", mock.Anything).Return(false, nil)
+		d.On("ApplyTask", mock.Anything).Return(errors.New("apply error")).Twice()
+		d.On("ApplyTask", mock.Anything).Return(nil).Once()
+
+		tm := newTestTasksManager()
+		tm.drivers.Add("task_a", d)
+		ctx := context.Background()
+
+		assert.Error(t, tm.TaskRunNow(ctx, "task_a"))
+		assert.Error(t, tm.TaskRunNow(ctx, "task_a"))
+		retryAt, open := tm.TaskCircuitBreakerOpen(ctx, "task_a")
+		require.True(t, open)
+		assert.False(t, retryAt.IsZero())
+
+		assert.Eventually(t, func() bool {
+			events := tm.state.GetTaskEvents("task_a")["task_a"]
+			return len(events) == 4 && events[0].Success
+		}, time.Second, 10*time.Millisecond)
+		_, open = tm.TaskCircuitBreakerOpen(ctx, "task_a")
+		assert.False(t, open)
+		d.AssertExpectations(t)
+	})
+}
+
+func Test_ConditionMonitor_EnableTaskRanNotify(t *testing.T) {
+	t.Parallel()
+
+	// Mock state store
+	taskConfs := config.TaskConfigs{
+		{Name: config.String("task_a")},
+		{Name: config.String("task_b")},
+	}
+	s := new(mocksS.Store)
+	s.On("GetAllTasks", mock.Anything, mock.Anything).Return(taskConfs)
+
+	// Set up tasks manager
+	tm := newTestTasksManager()
+	tm.state = s
+
+	// Test EnableTaskRanNotify
+	channel := tm.EnableTaskRanNotify()
+	assert.Equal(t, 2, cap(channel))
+	s.AssertExpectations(t)
+}
+
+func Test_TasksManager_TaskCreateAndRunAllowFail(t *testing.T) {
+	// TaskCreateAndRunAllowFail is similar to TaskCreateAndRun but with
+	// modified error handling. This tests what hasn't been tested in
+	// Test_TasksManager_TaskCreate
+	ctx := context.Background()
+
+	tm := newTestTasksManager()
+	tm.factory.watcher = new(mocksTmpl.Watcher)
+
+	conf := &config.Config{
+		BufferPeriod: config.DefaultBufferPeriodConfig(),
+		WorkingDir:   config.String(config.DefaultWorkingDir),
+		Driver:       config.DefaultDriverConfig(),
+	}
+
+	t.Run("run error", func(t *testing.T) {
+		mockD := new(mocksD.Driver)
+		task, err := driver.NewTask(driver.TaskConfig{
+			Enabled: true,
+			Name:    validTaskName,
+		})
+		require.NoError(t, err)
+		mockD.On("Task").Return(task).
+			On("InitTask", ctx).Return(nil).
+			On("RenderTemplate", mock.Anything).Return(true, nil).
+			On("ApplyTask", ctx).Return(fmt.Errorf("apply err")).
+			On("SetBufferPeriod").Return().Once().
+			On("TemplateIDs").Return(nil).Once()
+
+		tm.state = state.NewInMemoryStore(conf)
+		tm.drivers = driver.NewDrivers()
+		tm.factory.newDriver = func(context.Context, *config.Config, *driver.Task, templates.Watcher) (driver.Driver, error) {
+			return mockD, nil
+		}
+
+		err = tm.TaskCreateAndRunAllowFail(ctx, validTaskConf)
+		assert.Error(t, err, "run error is returned")
+
+		_, ok := tm.drivers.Get(validTaskName)
+		assert.True(t, ok, "driver is added even if run is unsuccessful")
+
+		events := tm.state.GetTaskEvents(validTaskName)
+		taskEvents := events[validTaskName]
+		require.Len(t, taskEvents, 1, "event is stored even on failed run")
+		assert.False(t, taskEvents[0].Success, "event should have failed")
+	})
+}
+
+func Test_TasksManager_deleteTask(t *testing.T) {
+	ctx := context.Background()
+	mockD := new(mocksD.Driver)
+
+	testCases := []struct {
+		name         string
+		setupDrivers func() *driver.Drivers
+	}{
+		{
+			"success",
+			func() *driver.Drivers {
+				mockD.On("TemplateIDs").Return(nil)
 				mockD.On("Task").Return(enabledTestTask(t, "success"))
 				mockD.On("DestroyTask", ctx).Return()
 				d := driver.NewDrivers()
@@ -1087,13 +2339,50 @@ func mockDriver(ctx context.Context, d *mocksD.Driver, task *driver.Task) {
 		On("ApplyTask", ctx).Return(nil)
 }
 
+func Test_TasksManager_groupByPriority(t *testing.T) {
+	t.Parallel()
+
+	tm := newTestTasksManager()
+	for name, priority := range map[string]int{"a": 0, "b": 10, "c": 0, "d": -5, "e": 10} {
+		task, err := driver.NewTask(driver.TaskConfig{Name: name, Priority: priority})
+		require.NoError(t, err)
+		d := new(mocksD.Driver)
+		d.On("Task").Return(task)
+		d.On("TemplateIDs").Return(nil)
+		require.NoError(t, tm.drivers.Add(name, d))
+	}
+
+	t.Run("grouped by descending priority", func(t *testing.T) {
+		groups := tm.groupByPriority([]string{"a", "b", "c", "d", "e"})
+		assert.Equal(t, [][]string{{"b", "e"}, {"a", "c"}, {"d"}}, groups)
+	})
+
+	t.Run("same priority", func(t *testing.T) {
+		groups := tm.groupByPriority([]string{"c", "a"})
+		assert.Equal(t, [][]string{{"c", "a"}}, groups)
+	})
+
+	t.Run("deleted task default priority", func(t *testing.T) {
+		groups := tm.groupByPriority([]string{"deleted", "d", "b"})
+		assert.Equal(t, [][]string{{"b"}, {"deleted"}, {"d"}}, groups)
+	})
+}
+
 func newTestTasksManager() *TasksManager {
 	return &TasksManager{
 		logger: logging.NewNullLogger(),
 		factory: &driverFactory{
 			logger: logging.NewNullLogger(),
 		},
-		drivers: driver.NewDrivers(),
-		state:   state.NewInMemoryStore(nil),
+		drivers:        driver.NewDrivers(),
+		state:          state.NewInMemoryStore(nil),
+		cooldowns:      newTaskCooldowns(),
+		windowRuns:     newTaskCooldowns(),
+		gateRuns:       newTaskGates(nil),
+		locks:          newTaskLocks(),
+		expirations:    newTaskCooldowns(),
+		breakers:       newTaskCircuitBreakers(),
+		pendingRuns:    newTaskPendingRuns(),
+		providerLimits: newProviderRateLimits(nil),
 	}
 }
@@ -4,6 +4,7 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/rand"
@@ -13,8 +14,10 @@ import (
 	"github.com/hashicorp/consul-terraform-sync/config"
 	"github.com/hashicorp/consul-terraform-sync/logging"
 	"github.com/hashicorp/consul-terraform-sync/retry"
+	consulapi "github.com/hashicorp/consul/api"
 	"github.com/hashicorp/hcat"
 	"github.com/hashicorp/hcat/events"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -22,8 +25,12 @@ const (
 )
 
 // newWatcher initializes a new hcat Watcher with a Consul client and optional
-// Vault client if configured.
-func newWatcher(conf *config.Config, maxRetries int) (*hcat.Watcher, error) {
+// Vault client if configured. The Consul client uses the token to query
+// Consul. When multiple Consul addresses are configured, queries fail over
+// to a healthy address when the current address is unavailable. When DNS
+// refresh is configured, queries reconnect to an address once its host name
+// resolves to new IPs.
+func newWatcher(conf *config.Config, token string, maxRetries int) (*hcat.Watcher, error) {
 	consulConf := conf.Consul
 	transport := hcat.TransportInput{
 		SSLEnabled: *consulConf.TLS.Enabled,
@@ -45,7 +52,7 @@ func newWatcher(conf *config.Config, maxRetries int) (*hcat.Watcher, error) {
 
 	consul := hcat.ConsulInput{
 		Address:      *consulConf.Address,
-		Token:        *consulConf.Token,
+		Token:        token,
 		AuthEnabled:  *consulConf.Auth.Enabled,
 		AuthUsername: *consulConf.Auth.Username,
 		AuthPassword: *consulConf.Auth.Password,
@@ -61,22 +68,84 @@ func newWatcher(conf *config.Config, maxRetries int) (*hcat.Watcher, error) {
 		return nil, err
 	}
 
+	var looker hcat.Looker = clients
+	var onRetry func()
+	addrs := consulConf.ConsulAddresses()
+	dnsRefresh := config.TimeDurationVal(consulConf.DNSRefreshInterval)
+	if len(addrs) > 1 || dnsRefresh > 0 {
+		failover, err := newFailoverClients(clients, addrs, consul)
+		if err != nil {
+			return nil, err
+		}
+		looker = failover
+
+		var retryFuncs []func()
+		if len(addrs) > 1 {
+			go failover.run(consulHealthCheckInterval)
+			retryFuncs = append(retryFuncs, failover.checkHealth)
+		}
+		if dnsRefresh > 0 {
+			go failover.runDNSRefresh(dnsRefresh)
+			// re-resolve before failing over since the address may have
+			// moved to new IPs
+			retryFuncs = append([]func(){failover.refreshDNS}, retryFuncs...)
+		}
+		onRetry = func() {
+			for _, f := range retryFuncs {
+				f()
+			}
+		}
+	}
+
+	if limit := config.IntVal(consulConf.QueryRateLimit); limit > 0 {
+		looker = newRateLimitedClients(looker, limit)
+	}
+
 	wr := watcherRetry{
 		maxRetries: maxRetries,
 		waitFunc:   retry.WaitTime,
+		onRetry:    onRetry,
 	}
 
 	return hcat.NewWatcher(hcat.WatcherInput{
-		Clients:         clients,
+		Clients:         looker,
 		Cache:           hcat.NewStore(),
 		ConsulRetryFunc: wr.retryConsul,
 		EventHandler:    newWatcherEventHandler(logging.Global().Named(hcatLogSystemName)),
 	}), nil
 }
 
+// rateLimitedClients wraps the hcat clients to limit the rate of Consul
+// queries. hcat dependencies request the Consul client for each query,
+// including each restart of a blocking query, so waiting on the limiter
+// throttles the aggregate rate of queries across all dependencies.
+type rateLimitedClients struct {
+	hcat.Looker
+	limiter *rate.Limiter
+}
+
+// newRateLimitedClients returns clients that allow up to limit Consul queries
+// per second
+func newRateLimitedClients(clients hcat.Looker, limit int) *rateLimitedClients {
+	return &rateLimitedClients{
+		Looker:  clients,
+		limiter: rate.NewLimiter(rate.Limit(limit), limit),
+	}
+}
+
+// Consul returns the Consul client once the rate limit allows a query
+func (c *rateLimitedClients) Consul() *consulapi.Client {
+	c.limiter.Wait(context.Background())
+	return c.Looker.Consul()
+}
+
 type watcherRetry struct {
 	maxRetries int
 	waitFunc   func(attempt int, random *rand.Rand, maxWaitTime time.Duration) time.Duration
+
+	// onRetry is optionally called before each retry, e.g. to fail over to
+	// another Consul address
+	onRetry func()
 }
 
 // retryConsul will be used by hashicat watcher to retry polling Consul for
@@ -93,6 +162,10 @@ func (wr watcherRetry) retryConsul(retryCount int) (bool, time.Duration) {
 		return false, 0 * time.Second
 	}
 
+	if wr.onRetry != nil {
+		wr.onRetry()
+	}
+
 	random := rand.New(rand.NewSource(time.Now().UnixNano()))
 	wait := wr.waitFunc(retryCount, random, retry.DefaultMaxWaitTime) // max wait time of retry.maxWaitTime minutes
 	logger.Debug("couldn't connect with Consul. Waiting to retry",
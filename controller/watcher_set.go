@@ -0,0 +1,203 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/templates"
+)
+
+// tokenWatchers provides watchers that query Consul with a specific token
+type tokenWatchers interface {
+	ForToken(token string) (templates.Watcher, error)
+}
+
+// watcherSet is a watcher for the template dependencies of tasks. It queries
+// Consul with the token of the consul block and manages additional watchers
+// for tasks that are configured with their own Consul token. hcat queries
+// Consul with the token of the watcher's client, so each distinct task token
+// has a separate watcher. Watch, WaitCh, Size, and Stop apply to all of the
+// watchers. All other methods apply to the default watcher.
+type watcherSet struct {
+	templates.Watcher
+
+	newWatcher func(token string) (templates.Watcher, error)
+
+	mu     sync.RWMutex
+	scoped map[string]templates.Watcher
+
+	// addedCh is closed and replaced when a watcher is added to notify
+	// ongoing calls to Watch
+	addedCh chan struct{}
+}
+
+// dependencyStatser is implemented by watchers that track the sharing of
+// the dependencies of their templates, e.g. templates.Registry
+type dependencyStatser interface {
+	DependencyStats() templates.DependencyStats
+}
+
+// newWatcherSet initializes a new watcher set with the default watcher for
+// the Consul configuration. The templates of each watcher are registered
+// through a registry that shares the templates with the same ID.
+func newWatcherSet(conf *config.Config, maxRetries int) (*watcherSet, error) {
+	w, err := newWatcher(conf, *conf.Consul.Token, maxRetries)
+	if err != nil {
+		return nil, err
+	}
+
+	return &watcherSet{
+		Watcher: templates.NewRegistry(w),
+		newWatcher: func(token string) (templates.Watcher, error) {
+			w, err := newWatcher(conf, token, maxRetries)
+			if err != nil {
+				return nil, err
+			}
+			return templates.NewRegistry(w), nil
+		},
+		scoped:  make(map[string]templates.Watcher),
+		addedCh: make(chan struct{}),
+	}, nil
+}
+
+// ForToken returns the watcher that queries Consul with the token. The
+// watcher is created if it does not exist yet and is kept until the watcher
+// set is stopped.
+func (s *watcherSet) ForToken(token string) (templates.Watcher, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if w, ok := s.scoped[token]; ok {
+		return w, nil
+	}
+
+	w, err := s.newWatcher(token)
+	if err != nil {
+		return nil, err
+	}
+	s.scoped[token] = w
+
+	close(s.addedCh)
+	s.addedCh = make(chan struct{})
+	return w, nil
+}
+
+// watchers returns all of the watchers and the channel that is closed when
+// another watcher is added
+func (s *watcherSet) watchers() ([]templates.Watcher, <-chan struct{}) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ws := make([]templates.Watcher, 0, len(s.scoped)+1)
+	ws = append(ws, s.Watcher)
+	for _, w := range s.scoped {
+		ws = append(ws, w)
+	}
+	return ws, s.addedCh
+}
+
+// Watch watches the template dependencies of all of the watchers, including
+// watchers added while watching, and sends the IDs of the templates with
+// updated dependencies to tmplCh. It returns when the context is canceled or
+// when a watcher returns an error.
+func (s *watcherSet) Watch(ctx context.Context, tmplCh chan string) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, 1)
+	watching := make(map[templates.Watcher]bool)
+	for {
+		ws, addedCh := s.watchers()
+		for _, w := range ws {
+			if watching[w] {
+				continue
+			}
+			watching[w] = true
+
+			wg.Add(1)
+			go func(w templates.Watcher) {
+				defer wg.Done()
+				err := w.Watch(ctx, tmplCh)
+				if err != nil && err != context.Canceled {
+					select {
+					case errCh <- err:
+					default:
+					}
+				}
+			}(w)
+		}
+
+		select {
+		case <-addedCh:
+		case err := <-errCh:
+			cancel()
+			wg.Wait()
+			return err
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		}
+	}
+}
+
+// WaitCh returns a channel that receives the result of the first watcher to
+// receive updated dependency data
+func (s *watcherSet) WaitCh(ctx context.Context) <-chan error {
+	ws, _ := s.watchers()
+	if len(ws) == 1 {
+		return s.Watcher.WaitCh(ctx)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	resultCh := make(chan error, len(ws))
+	for _, w := range ws {
+		go func(ch <-chan error) {
+			resultCh <- <-ch
+		}(w.WaitCh(ctx))
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		// the first result is sent before the other watchers are canceled,
+		// so it is the result received
+		defer cancel()
+		errCh <- <-resultCh
+	}()
+	return errCh
+}
+
+// Size returns the number of dependencies of all of the watchers
+func (s *watcherSet) Size() int {
+	ws, _ := s.watchers()
+	var size int
+	for _, w := range ws {
+		size += w.Size()
+	}
+	return size
+}
+
+// Stop stops all of the watchers
+func (s *watcherSet) Stop() {
+	ws, _ := s.watchers()
+	for _, w := range ws {
+		w.Stop()
+	}
+}
+
+// DependencyStats returns the statistics of the dependencies of the templates
+// of all of the watchers
+func (s *watcherSet) DependencyStats() templates.DependencyStats {
+	ws, _ := s.watchers()
+	var stats templates.DependencyStats
+	for _, w := range ws {
+		if statser, ok := w.(dependencyStatser); ok {
+			stats = stats.Add(statser.DependencyStats())
+		}
+	}
+	return stats
+}
@@ -0,0 +1,229 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mocksTmpl "github.com/hashicorp/consul-terraform-sync/mocks/templates"
+	"github.com/hashicorp/consul-terraform-sync/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWatcherSet(w templates.Watcher, scoped map[string]templates.Watcher) *watcherSet {
+	return &watcherSet{
+		Watcher: w,
+		newWatcher: func(token string) (templates.Watcher, error) {
+			if w, ok := scoped[token]; ok {
+				return w, nil
+			}
+			return nil, errors.New("unexpected token")
+		},
+		scoped:  make(map[string]templates.Watcher),
+		addedCh: make(chan struct{}),
+	}
+}
+
+func TestWatcherSet_ForToken(t *testing.T) {
+	t.Parallel()
+
+	tokenW := new(mocksTmpl.Watcher)
+	ws := newTestWatcherSet(new(mocksTmpl.Watcher), map[string]templates.Watcher{
+		"token": tokenW,
+	})
+	addedCh := ws.addedCh
+
+	w, err := ws.ForToken("token")
+	require.NoError(t, err)
+	assert.Equal(t, tokenW, w)
+
+	select {
+	case <-addedCh:
+	default:
+		assert.Fail(t, "expected added channel to be closed")
+	}
+
+	// the same watcher is returned for the token
+	addedCh = ws.addedCh
+	w, err = ws.ForToken("token")
+	require.NoError(t, err)
+	assert.Equal(t, tokenW, w)
+	select {
+	case <-addedCh:
+		assert.Fail(t, "expected added channel to be open")
+	default:
+	}
+
+	_, err = ws.ForToken("other")
+	assert.Error(t, err)
+}
+
+func TestWatcherSet_Watch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("watches added watchers", func(t *testing.T) {
+		w := new(mocksTmpl.Watcher)
+		w.On("Watch", mock.Anything, mock.Anything).Return(func(ctx context.Context, ch chan string) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		tokenW := new(mocksTmpl.Watcher)
+		tokenW.On("Watch", mock.Anything, mock.Anything).Return(func(ctx context.Context, ch chan string) error {
+			ch <- "tmpl"
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		ws := newTestWatcherSet(w, map[string]templates.Watcher{"token": tokenW})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := make(chan string, 1)
+		errCh := make(chan error)
+		go func() { errCh <- ws.Watch(ctx, ch) }()
+
+		_, err := ws.ForToken("token")
+		require.NoError(t, err)
+
+		select {
+		case id := <-ch:
+			assert.Equal(t, "tmpl", id)
+		case <-time.After(time.Second):
+			assert.Fail(t, "expected template from added watcher")
+		}
+
+		cancel()
+		assert.Equal(t, context.Canceled, <-errCh)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		w := new(mocksTmpl.Watcher)
+		w.On("Watch", mock.Anything, mock.Anything).Return(func(ctx context.Context, ch chan string) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		tokenW := new(mocksTmpl.Watcher)
+		tokenW.On("Watch", mock.Anything, mock.Anything).Return(errors.New("watch error"))
+		ws := newTestWatcherSet(w, map[string]templates.Watcher{"token": tokenW})
+		_, err := ws.ForToken("token")
+		require.NoError(t, err)
+
+		err = ws.Watch(context.Background(), make(chan string))
+		assert.EqualError(t, err, "watch error")
+	})
+}
+
+func TestWatcherSet_WaitCh(t *testing.T) {
+	t.Parallel()
+
+	waitCh := func(err error) func(context.Context) <-chan error {
+		return func(ctx context.Context) <-chan error {
+			ch := make(chan error, 1)
+			go func() {
+				if err != nil {
+					ch <- err
+					return
+				}
+				<-ctx.Done()
+				ch <- nil
+			}()
+			return ch
+		}
+	}
+
+	w := new(mocksTmpl.Watcher)
+	w.On("WaitCh", mock.Anything).Return(waitCh(nil))
+	tokenW := new(mocksTmpl.Watcher)
+	tokenW.On("WaitCh", mock.Anything).Return(waitCh(errors.New("wait error")))
+	ws := newTestWatcherSet(w, map[string]templates.Watcher{"token": tokenW})
+	_, err := ws.ForToken("token")
+	require.NoError(t, err)
+
+	select {
+	case err := <-ws.WaitCh(context.Background()):
+		assert.EqualError(t, err, "wait error")
+	case <-time.After(time.Second):
+		assert.Fail(t, "expected result from token watcher")
+	}
+}
+
+func TestWatcherSet_SizeStop(t *testing.T) {
+	t.Parallel()
+
+	w := new(mocksTmpl.Watcher)
+	w.On("Size").Return(2)
+	w.On("Stop").Return().Once()
+	tokenW := new(mocksTmpl.Watcher)
+	tokenW.On("Size").Return(3)
+	tokenW.On("Stop").Return().Once()
+	ws := newTestWatcherSet(w, map[string]templates.Watcher{"token": tokenW})
+
+	assert.Equal(t, 2, ws.Size())
+
+	_, err := ws.ForToken("token")
+	require.NoError(t, err)
+	assert.Equal(t, 5, ws.Size())
+
+	ws.Stop()
+	w.AssertExpectations(t)
+	tokenW.AssertExpectations(t)
+}
+
+// depsNotifier is a notifier of a template with dependencies
+type depsNotifier struct {
+	id   string
+	deps map[string]bool
+}
+
+func (n *depsNotifier) ID() string                    { return n.id }
+func (n *depsNotifier) Notify(interface{}) bool       { return true }
+func (n *depsNotifier) Dependencies() map[string]bool { return n.deps }
+
+func TestWatcherSet_DependencyStats(t *testing.T) {
+	t.Parallel()
+
+	w := new(mocksTmpl.Watcher)
+	w.On("Register", mock.Anything).Return(nil)
+	tokenW := new(mocksTmpl.Watcher)
+	tokenW.On("Register", mock.Anything).Return(nil)
+
+	registry := templates.NewRegistry(w)
+	tokenRegistry := templates.NewRegistry(tokenW)
+	ws := newTestWatcherSet(registry, map[string]templates.Watcher{
+		"token": tokenRegistry,
+		"mock":  new(mocksTmpl.Watcher),
+	})
+
+	web := map[string]bool{"health.service(web)": true}
+	require.NoError(t, registry.Register(
+		&depsNotifier{id: "task_a", deps: web},
+		&depsNotifier{id: "task_b", deps: web},
+	))
+	require.NoError(t, tokenRegistry.Register(&depsNotifier{id: "task_c", deps: web}))
+
+	assert.Equal(t, templates.DependencyStats{
+		Templates:    2,
+		Dependencies: 1,
+		Shared:       1,
+		References:   2,
+		Deduplicated: 1,
+	}, ws.DependencyStats())
+
+	// dependencies are not shared across watchers, and watchers that do not
+	// track dependencies are skipped
+	_, err := ws.ForToken("token")
+	require.NoError(t, err)
+	_, err = ws.ForToken("mock")
+	require.NoError(t, err)
+	assert.Equal(t, templates.DependencyStats{
+		Templates:    3,
+		Dependencies: 2,
+		Shared:       1,
+		References:   3,
+		Deduplicated: 1,
+	}, ws.DependencyStats())
+}
@@ -8,10 +8,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/hashicorp/hcat"
 	"github.com/hashicorp/hcat/events"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -65,6 +68,26 @@ func TestWatchRetry_retryConsul(t *testing.T) {
 	}
 }
 
+func TestWatchRetry_onRetry(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	wr := watcherRetry{
+		maxRetries: 2,
+		waitFunc: func(attempt int, random *rand.Rand, maxWaitTime time.Duration) time.Duration {
+			return 1 * time.Nanosecond
+		},
+		onRetry: func() { calls++ },
+	}
+
+	for i := 0; i <= 3; i++ {
+		wr.retryConsul(i)
+	}
+
+	// not called once the retries are exhausted
+	assert.Equal(t, 3, calls)
+}
+
 func Test_newWatcherEventHandler(t *testing.T) {
 	toJson := func(v any) string {
 		b, err := json.Marshal(v)
@@ -103,3 +126,33 @@ func Test_newWatcherEventHandler(t *testing.T) {
 		}
 	}
 }
+
+func TestRateLimitedClients_Consul(t *testing.T) {
+	t.Parallel()
+
+	// the client set waits for a Consul leader
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `"127.0.0.1:8300"`)
+	}))
+	defer srv.Close()
+
+	clients := hcat.NewClientSet()
+	require.NoError(t, clients.AddConsul(hcat.ConsulInput{
+		Address: srv.Listener.Addr().String(),
+	}))
+	limited := newRateLimitedClients(clients, 10)
+
+	// The burst of queries is allowed without waiting
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		assert.NotNil(t, limited.Consul())
+	}
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	// Queries beyond the burst wait for the rate limit
+	start = time.Now()
+	for i := 0; i < 2; i++ {
+		limited.Consul()
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+}
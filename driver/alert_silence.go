@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/alertmanager"
+)
+
+// Silencer creates silences of alerts, e.g. in the Prometheus Alertmanager
+type Silencer interface {
+	CreateSilence(ctx context.Context, s alertmanager.Silence) (string, error)
+}
+
+// silenceAlerts creates a silence in the Alertmanager for the alerts that are
+// expected while the task applies changes. Failing to create the silence does
+// not fail the task run and is only logged.
+func (tf *Terraform) silenceAlerts(ctx context.Context) {
+	silence, ok := tf.task.AlertSilence()
+	if !ok || tf.silencer == nil {
+		return
+	}
+
+	taskName := tf.task.Name()
+	comment := silence.Comment
+	if comment == "" {
+		comment = fmt.Sprintf("Consul-Terraform-Sync task %s is applying changes",
+			taskName)
+	}
+
+	now := time.Now()
+	id, err := tf.silencer.CreateSilence(ctx, alertmanager.Silence{
+		Matchers: silence.Matchers,
+		StartsAt: now,
+		EndsAt:   now.Add(silence.Duration),
+		Comment:  comment,
+	})
+	if err != nil {
+		tf.logger.Warn("unable to create Alertmanager silence before applying task",
+			taskNameLogKey, taskName, "error", err)
+		return
+	}
+
+	tf.logger.Info("created Alertmanager silence for task", taskNameLogKey,
+		taskName, "silence_id", id, "duration", silence.Duration)
+}
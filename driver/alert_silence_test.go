@@ -0,0 +1,112 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/alertmanager"
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	mocks "github.com/hashicorp/consul-terraform-sync/mocks/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testSilencer records the silences it creates
+type testSilencer struct {
+	silences []alertmanager.Silence
+	err      error
+}
+
+func (s *testSilencer) CreateSilence(_ context.Context, silence alertmanager.Silence) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	s.silences = append(s.silences, silence)
+	return "silence-id", nil
+}
+
+func TestApplyTask_AlertSilence(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	matchers := []alertmanager.Matcher{{Name: "service", Value: "api", IsEqual: true}}
+
+	newTerraform := func(c *mocks.Client, silence *AlertSilence, s Silencer) *Terraform {
+		return &Terraform{
+			task: &Task{name: "task", enabled: true, workingDir: t.TempDir(),
+				silence: silence, logger: logging.NewNullLogger()},
+			client:     c,
+			silencer:   s,
+			fileReader: os.ReadFile,
+			logger:     logging.NewNullLogger(),
+		}
+	}
+
+	t.Run("silence before apply", func(t *testing.T) {
+		s := &testSilencer{}
+		c := new(mocks.Client)
+		c.On("SetStdout", mock.Anything)
+		c.On("SetProgress", mock.Anything)
+		c.On("Apply", ctx).Run(func(mock.Arguments) {
+			assert.Len(t, s.silences, 1, "silence is created before apply")
+		}).Return(nil).Once()
+
+		tf := newTerraform(c, &AlertSilence{Duration: 10 * time.Minute,
+			Matchers: matchers}, s)
+		require.NoError(t, tf.ApplyTask(ctx))
+		c.AssertExpectations(t)
+
+		require.Len(t, s.silences, 1)
+		silence := s.silences[0]
+		assert.Equal(t, matchers, silence.Matchers)
+		assert.Equal(t, 10*time.Minute, silence.EndsAt.Sub(silence.StartsAt))
+		assert.Contains(t, silence.Comment, "task")
+	})
+
+	t.Run("custom comment", func(t *testing.T) {
+		s := &testSilencer{}
+		c := new(mocks.Client)
+		c.On("SetStdout", mock.Anything)
+		c.On("SetProgress", mock.Anything)
+		c.On("Apply", ctx).Return(nil).Once()
+
+		tf := newTerraform(c, &AlertSilence{Duration: time.Minute,
+			Comment: "lb reconverging", Matchers: matchers}, s)
+		require.NoError(t, tf.ApplyTask(ctx))
+
+		require.Len(t, s.silences, 1)
+		assert.Equal(t, "lb reconverging", s.silences[0].Comment)
+	})
+
+	t.Run("silence error does not fail apply", func(t *testing.T) {
+		s := &testSilencer{err: errors.New("connection refused")}
+		c := new(mocks.Client)
+		c.On("SetStdout", mock.Anything)
+		c.On("SetProgress", mock.Anything)
+		c.On("Apply", ctx).Return(nil).Once()
+
+		tf := newTerraform(c, &AlertSilence{Duration: time.Minute,
+			Matchers: matchers}, s)
+		assert.NoError(t, tf.ApplyTask(ctx))
+		c.AssertExpectations(t)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		s := &testSilencer{}
+		c := new(mocks.Client)
+		c.On("SetStdout", mock.Anything)
+		c.On("SetProgress", mock.Anything)
+		c.On("Apply", ctx).Return(nil).Once()
+
+		tf := newTerraform(c, nil, s)
+		require.NoError(t, tf.ApplyTask(ctx))
+		assert.Empty(t, s.silences)
+	})
+}
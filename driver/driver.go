@@ -25,6 +25,14 @@ type Driver interface {
 	// completed or not
 	RenderTemplate(ctx context.Context) (bool, error)
 
+	// RenderTask renders the template with the latest data of its
+	// dependencies, even if unchanged, without running the task
+	RenderTask(ctx context.Context) (RenderResult, error)
+
+	// ReinitTask re-initializes the task, e.g. after its module changed, and
+	// renders the template without running the task
+	ReinitTask(ctx context.Context) (RenderResult, error)
+
 	// InspectTask inspects for any differences pertaining to the task between
 	// the state of Consul and network infrastructure
 	InspectTask(ctx context.Context) (InspectPlan, error)
@@ -41,6 +49,18 @@ type Driver interface {
 	// Task returns the task information of the driver
 	Task() *Task
 
+	// DependencyTriggers returns the number of times that each monitored
+	// dependency triggered the task, by the name of the dependency
+	DependencyTriggers() map[string]int
+
+	// Readiness returns the progress of resolving the template of the task
+	// managed by the driver for its first run
+	Readiness() Readiness
+
+	// RenderChanged returns whether the rendered task changed since the last
+	// successful run of the task
+	RenderChanged() (bool, error)
+
 	// Version returns the version of the driver.
 	Version() string
 }
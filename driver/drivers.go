@@ -12,6 +12,27 @@ import (
 	"github.com/hashicorp/consul-terraform-sync/logging"
 )
 
+// ErrTaskActive is the error matched by errors returned when an operation is
+// rejected because the task is currently active
+var ErrTaskActive = errors.New("task is active")
+
+// TaskActiveError is the error returned when an operation on a task is
+// rejected because the task is currently active
+type TaskActiveError struct {
+	Name   string
+	Action string
+}
+
+func (e *TaskActiveError) Error() string {
+	return fmt.Sprintf("task '%s' is active and cannot be %s at this time",
+		e.Name, e.Action)
+}
+
+// Is reports whether target is ErrTaskActive
+func (e *TaskActiveError) Is(target error) bool {
+	return target == ErrTaskActive
+}
+
 // Drivers wraps the map of task-name to associated driver so that the map
 // can be accessed concurrently
 type Drivers struct {
@@ -5,6 +5,8 @@ package driver
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	mocks "github.com/hashicorp/consul-terraform-sync/mocks/templates"
@@ -394,3 +396,13 @@ func (md *mockDriver) SetBufferPeriod() {
 func (md *mockDriver) TemplateIDs() []string {
 	return md.templateIDs
 }
+
+func TestTaskActiveError(t *testing.T) {
+	t.Parallel()
+
+	var err error = &TaskActiveError{Name: "task", Action: "updated"}
+	assert.Equal(t, "task 'task' is active and cannot be updated at this time", err.Error())
+	assert.True(t, errors.Is(err, ErrTaskActive))
+	assert.True(t, errors.Is(fmt.Errorf("wrapped: %w", err), ErrTaskActive))
+	assert.False(t, errors.Is(errors.New("task is active"), ErrTaskActive))
+}
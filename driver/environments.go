@@ -0,0 +1,101 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/consul-terraform-sync/client"
+	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl"
+)
+
+// backendKeyAttributes are the attributes of the supported backends that set
+// the key of the state in the backend
+var backendKeyAttributes = map[string]string{
+	"azurerm": "key",
+	"consul":  "path",
+	"gcs":     "prefix",
+	"local":   "path",
+	"s3":      "key",
+}
+
+// TaskEnvironment is an environment of a task. A task with environments runs
+// Terraform for each environment with its own workspace and data directory.
+type TaskEnvironment struct {
+	Name string
+
+	// Workspace is the Terraform workspace of the environment. Defaults to
+	// the workspace of the task suffixed with the environment name.
+	Workspace string
+
+	// Variables override the values of the task's variables
+	Variables map[string]string
+
+	// BackendKey overrides the key of the state in the backend. Defaults to
+	// the key of the task's backend.
+	BackendKey string
+}
+
+// copy returns a deep copy of the environment
+func (e TaskEnvironment) copy() TaskEnvironment {
+	cp := e
+	cp.Variables = make(map[string]string, len(e.Variables))
+	for k, v := range e.Variables {
+		cp.Variables[k] = v
+	}
+	return cp
+}
+
+// environmentDataDir returns the Terraform data directory of an environment,
+// relative to the working directory of the task
+func environmentDataDir(name string) string {
+	return ".terraform-" + name
+}
+
+// backendType returns the type of the backend, which is the only key of the
+// backend configuration
+func backendType(backend map[string]interface{}) string {
+	for k := range backend {
+		return k
+	}
+	return ""
+}
+
+// newEnvironmentsClient creates a client for each environment of the task
+// that fans out to the clients of the environments
+func newEnvironmentsClient(conf *clientConfig) (client.Client, error) {
+	workspace := conf.workspace
+	if workspace == "" {
+		workspace = conf.taskName
+	}
+
+	envs := make([]client.Environment, 0, len(conf.environments))
+	for _, env := range conf.environments {
+		envConf := *conf
+		envConf.environments = nil
+		envConf.workspace = env.Workspace
+		if envConf.workspace == "" {
+			envConf.workspace = fmt.Sprintf("%s-%s", workspace, env.Name)
+		}
+		envConf.dataDir = environmentDataDir(env.Name)
+		envConf.varFiles = []string{tftmpl.EnvironmentTFVarsFilename(env.Name)}
+
+		if env.BackendKey != "" {
+			attr, ok := backendKeyAttributes[conf.backendType]
+			if !ok {
+				return nil, fmt.Errorf("environment %q: backend_key is not "+
+					"supported for the %q backend", env.Name, conf.backendType)
+			}
+			envConf.backendConfig = []string{fmt.Sprintf("%s=%s", attr, env.BackendKey)}
+		}
+
+		c, err := newClient(&envConf)
+		if err != nil {
+			return nil, err
+		}
+		envs = append(envs, client.Environment{Name: env.Name, Client: c})
+	}
+
+	return client.NewEnvironments(envs)
+}
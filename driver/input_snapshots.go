@@ -0,0 +1,213 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	// InputSnapshotsDir is the directory within the task's working directory
+	// where the snapshots of the applied input variables are stored
+	InputSnapshotsDir = "input-snapshots"
+
+	// inputSnapshotTimeFormat is the format of the time in the file name of a
+	// snapshot. The format sorts in chronological order.
+	inputSnapshotTimeFormat = "20060102T150405.000000000Z"
+
+	inputSnapshotExt = ".tfvars"
+)
+
+// ErrInputSnapshotNotFound is returned when a snapshot of the input variables
+// of a task does not exist
+var ErrInputSnapshotNotFound = errors.New("input snapshot not found")
+
+// InputSnapshot is a copy of the input variables (terraform.tfvars) applied
+// by a run of a task. It is identified by the ID of the event that recorded
+// the run.
+type InputSnapshot struct {
+	EventID string    `json:"event_id"`
+	Time    time.Time `json:"time"`
+
+	filename string
+}
+
+// InputsDiff is the difference between the input variables applied by two
+// runs of a task
+type InputsDiff struct {
+	From InputSnapshot `json:"from"`
+	To   InputSnapshot `json:"to"`
+
+	// Changed is whether the input variables changed between the runs
+	Changed bool `json:"changed"`
+
+	// Diff is the unified diff of the input variables. Empty if the input
+	// variables did not change.
+	Diff string `json:"diff"`
+}
+
+// SaveInputSnapshot copies the task's rendered input variables to a snapshot
+// for the run of the event. The oldest snapshots beyond the task's retention
+// are removed. No-op if input snapshots are disabled for the task.
+func (t *Task) SaveInputSnapshot(eventID string, at time.Time) error {
+	retention := t.InputSnapshotRetention()
+	if retention == 0 {
+		return nil
+	}
+	if eventID == "" || strings.ContainsAny(eventID, `/\_`) {
+		return fmt.Errorf("invalid event ID %q for input snapshot", eventID)
+	}
+
+	workingDir := t.WorkingDir()
+	content, err := os.ReadFile(filepath.Join(workingDir, tftmpl.TFVarsFilename))
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(workingDir, InputSnapshotsDir)
+	if err := os.MkdirAll(dir, workingDirPerms); err != nil {
+		return err
+	}
+
+	filename := at.UTC().Format(inputSnapshotTimeFormat) + "_" + eventID + inputSnapshotExt
+	if err := os.WriteFile(filepath.Join(dir, filename), content, filePerms); err != nil {
+		return err
+	}
+
+	snapshots, err := t.InputSnapshots()
+	if err != nil {
+		return err
+	}
+	for len(snapshots) > retention {
+		if err := os.Remove(filepath.Join(dir, snapshots[0].filename)); err != nil {
+			return err
+		}
+		snapshots = snapshots[1:]
+	}
+	return nil
+}
+
+// InputSnapshots returns the stored snapshots of the task's input variables,
+// oldest first
+func (t *Task) InputSnapshots() ([]InputSnapshot, error) {
+	entries, err := os.ReadDir(filepath.Join(t.WorkingDir(), InputSnapshotsDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var snapshots []InputSnapshot
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, inputSnapshotExt) {
+			continue
+		}
+
+		parts := strings.SplitN(strings.TrimSuffix(name, inputSnapshotExt), "_", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			continue
+		}
+		at, err := time.Parse(inputSnapshotTimeFormat, parts[0])
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, InputSnapshot{
+			EventID:  parts[1],
+			Time:     at,
+			filename: name,
+		})
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Time.Before(snapshots[j].Time)
+	})
+	return snapshots, nil
+}
+
+// InputsDiff returns the difference between the input variables applied by
+// the runs of the from and to events. When to is empty, the latest snapshot
+// is used. When from is empty, the snapshot preceding to is used.
+func (t *Task) InputsDiff(from, to string) (InputsDiff, error) {
+	snapshots, err := t.InputSnapshots()
+	if err != nil {
+		return InputsDiff{}, err
+	}
+	if len(snapshots) == 0 {
+		return InputsDiff{}, fmt.Errorf("%w: task '%s' has no input snapshots",
+			ErrInputSnapshotNotFound, t.Name())
+	}
+
+	toIdx := len(snapshots) - 1
+	if to != "" {
+		if toIdx = indexOfInputSnapshot(snapshots, to); toIdx == -1 {
+			return InputsDiff{}, fmt.Errorf("%w: no input snapshot for event "+
+				"'%s' of task '%s'", ErrInputSnapshotNotFound, to, t.Name())
+		}
+	}
+
+	fromIdx := toIdx - 1
+	if from != "" {
+		if fromIdx = indexOfInputSnapshot(snapshots, from); fromIdx == -1 {
+			return InputsDiff{}, fmt.Errorf("%w: no input snapshot for event "+
+				"'%s' of task '%s'", ErrInputSnapshotNotFound, from, t.Name())
+		}
+	} else if fromIdx < 0 {
+		return InputsDiff{}, fmt.Errorf("%w: no input snapshot preceding event "+
+			"'%s' of task '%s'", ErrInputSnapshotNotFound, snapshots[toIdx].EventID,
+			t.Name())
+	}
+
+	dir := filepath.Join(t.WorkingDir(), InputSnapshotsDir)
+	fromSnapshot, toSnapshot := snapshots[fromIdx], snapshots[toIdx]
+	a, err := os.ReadFile(filepath.Join(dir, fromSnapshot.filename))
+	if err != nil {
+		return InputsDiff{}, err
+	}
+	b, err := os.ReadFile(filepath.Join(dir, toSnapshot.filename))
+	if err != nil {
+		return InputsDiff{}, err
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(strings.TrimSuffix(string(a), "\n")),
+		B:        difflib.SplitLines(strings.TrimSuffix(string(b), "\n")),
+		FromFile: fromSnapshot.EventID,
+		FromDate: fromSnapshot.Time.Format(time.RFC3339),
+		ToFile:   toSnapshot.EventID,
+		ToDate:   toSnapshot.Time.Format(time.RFC3339),
+		Context:  3,
+	})
+	if err != nil {
+		return InputsDiff{}, err
+	}
+
+	return InputsDiff{
+		From:    fromSnapshot,
+		To:      toSnapshot,
+		Changed: diff != "",
+		Diff:    diff,
+	}, nil
+}
+
+// indexOfInputSnapshot returns the index of the snapshot for the event.
+// Returns -1 if there is no snapshot for the event.
+func indexOfInputSnapshot(snapshots []InputSnapshot, eventID string) int {
+	for i, s := range snapshots {
+		if s.EventID == eventID {
+			return i
+		}
+	}
+	return -1
+}
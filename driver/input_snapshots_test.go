@@ -0,0 +1,184 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// saveTestInputSnapshot renders the input variables to the task's working
+// directory and saves a snapshot of them for the event
+func saveTestInputSnapshot(t *testing.T, task *Task, eventID, content string, at time.Time) {
+	path := filepath.Join(task.workingDir, tftmpl.TFVarsFilename)
+	require.NoError(t, os.WriteFile(path, []byte(content), filePerms))
+	require.NoError(t, task.SaveInputSnapshot(eventID, at))
+}
+
+func TestTask_SaveInputSnapshot(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("disabled", func(t *testing.T) {
+		task := &Task{name: "task", workingDir: t.TempDir()}
+		assert.NoError(t, task.SaveInputSnapshot("event-a", start))
+
+		_, err := os.Stat(filepath.Join(task.workingDir, InputSnapshotsDir))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("saves snapshot", func(t *testing.T) {
+		task := &Task{name: "task", workingDir: t.TempDir(), snapshots: 2}
+		saveTestInputSnapshot(t, task, "event-a", "services = {}\n", start)
+
+		snapshots, err := task.InputSnapshots()
+		require.NoError(t, err)
+		require.Len(t, snapshots, 1)
+		assert.Equal(t, "event-a", snapshots[0].EventID)
+		assert.Equal(t, start, snapshots[0].Time)
+
+		content, err := os.ReadFile(filepath.Join(task.workingDir,
+			InputSnapshotsDir, snapshots[0].filename))
+		require.NoError(t, err)
+		assert.Equal(t, "services = {}\n", string(content))
+	})
+
+	t.Run("prunes oldest snapshots", func(t *testing.T) {
+		task := &Task{name: "task", workingDir: t.TempDir(), snapshots: 2}
+		saveTestInputSnapshot(t, task, "event-a", "a", start)
+		saveTestInputSnapshot(t, task, "event-b", "b", start.Add(time.Minute))
+		saveTestInputSnapshot(t, task, "event-c", "c", start.Add(2*time.Minute))
+
+		snapshots, err := task.InputSnapshots()
+		require.NoError(t, err)
+		require.Len(t, snapshots, 2)
+		assert.Equal(t, "event-b", snapshots[0].EventID)
+		assert.Equal(t, "event-c", snapshots[1].EventID)
+
+		entries, err := os.ReadDir(filepath.Join(task.workingDir, InputSnapshotsDir))
+		require.NoError(t, err)
+		assert.Len(t, entries, 2)
+	})
+
+	t.Run("missing input variables", func(t *testing.T) {
+		task := &Task{name: "task", workingDir: t.TempDir(), snapshots: 2}
+		assert.Error(t, task.SaveInputSnapshot("event-a", start))
+	})
+
+	t.Run("invalid event ID", func(t *testing.T) {
+		task := &Task{name: "task", workingDir: t.TempDir(), snapshots: 2}
+		assert.Error(t, task.SaveInputSnapshot("../event", start))
+	})
+}
+
+func TestTask_InputSnapshots(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no snapshots", func(t *testing.T) {
+		task := &Task{name: "task", workingDir: t.TempDir()}
+		snapshots, err := task.InputSnapshots()
+		assert.NoError(t, err)
+		assert.Empty(t, snapshots)
+	})
+
+	t.Run("ignores other files", func(t *testing.T) {
+		task := &Task{name: "task", workingDir: t.TempDir()}
+		dir := filepath.Join(task.workingDir, InputSnapshotsDir)
+		require.NoError(t, os.MkdirAll(dir, workingDirPerms))
+		for _, name := range []string{
+			"20260102T030405.000000000Z_event-a.tfvars",
+			"notes.txt",
+			"invalid_event-b.tfvars",
+			"20260102T030405.000000000Z_.tfvars",
+		} {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, filePerms))
+		}
+
+		snapshots, err := task.InputSnapshots()
+		require.NoError(t, err)
+		require.Len(t, snapshots, 1)
+		assert.Equal(t, "event-a", snapshots[0].EventID)
+	})
+}
+
+func TestTask_InputsDiff(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	task := &Task{name: "task", workingDir: t.TempDir(), snapshots: 5}
+
+	_, err := task.InputsDiff("", "")
+	assert.ErrorIs(t, err, ErrInputSnapshotNotFound)
+
+	saveTestInputSnapshot(t, task, "event-a", "services = {\n  web = 1\n}\n", start)
+
+	_, err = task.InputsDiff("", "")
+	assert.ErrorIs(t, err, ErrInputSnapshotNotFound,
+		"a single snapshot does not have a preceding snapshot")
+
+	saveTestInputSnapshot(t, task, "event-b", "services = {\n  web = 2\n}\n",
+		start.Add(time.Minute))
+	saveTestInputSnapshot(t, task, "event-c", "services = {\n  web = 2\n}\n",
+		start.Add(2*time.Minute))
+
+	cases := []struct {
+		name    string
+		from    string
+		to      string
+		expFrom string
+		expTo   string
+		changed bool
+	}{
+		{"latest", "", "", "event-b", "event-c", false},
+		{"to", "", "event-b", "event-a", "event-b", true},
+		{"from", "event-a", "", "event-a", "event-c", true},
+		{"from and to", "event-a", "event-b", "event-a", "event-b", true},
+		{"same event", "event-b", "event-b", "event-b", "event-b", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			diff, err := task.InputsDiff(tc.from, tc.to)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expFrom, diff.From.EventID)
+			assert.Equal(t, tc.expTo, diff.To.EventID)
+			assert.Equal(t, tc.changed, diff.Changed)
+			if !tc.changed {
+				assert.Empty(t, diff.Diff)
+			}
+		})
+	}
+
+	t.Run("diff", func(t *testing.T) {
+		diff, err := task.InputsDiff("event-a", "event-b")
+		require.NoError(t, err)
+		expected := "--- event-a\t2026-01-02T03:04:05Z\n" +
+			"+++ event-b\t2026-01-02T03:05:05Z\n" +
+			"@@ -1,3 +1,3 @@\n" +
+			" services = {\n" +
+			"-  web = 1\n" +
+			"+  web = 2\n" +
+			" }\n"
+		assert.Equal(t, expected, diff.Diff)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := task.InputsDiff("event-z", "")
+		assert.ErrorIs(t, err, ErrInputSnapshotNotFound)
+
+		_, err = task.InputsDiff("", "event-z")
+		assert.ErrorIs(t, err, ErrInputSnapshotNotFound)
+
+		_, err = task.InputsDiff("", "event-a")
+		assert.ErrorIs(t, err, ErrInputSnapshotNotFound,
+			"the oldest snapshot does not have a preceding snapshot")
+	})
+}
@@ -0,0 +1,152 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+)
+
+const (
+	// lockFilename is the name of the dependency lock file that Terraform
+	// writes on init with the provider versions selected for the workspace
+	lockFilename = ".terraform.lock.hcl"
+)
+
+// modulesManifestPath is the path, relative to the task's working directory,
+// of the manifest that Terraform writes on init with the modules it installed
+var modulesManifestPath = filepath.Join(".terraform", "modules", "modules.json")
+
+// Inventory lists the module and the providers that Terraform installed in
+// the working directory of a task
+type Inventory struct {
+	TaskName  string              `json:"task_name"`
+	Module    ModuleInventory     `json:"module"`
+	Providers []ProviderInventory `json:"providers"`
+}
+
+// ModuleInventory is the module of a task. The source is the source resolved
+// by Terraform when the module is installed, otherwise the configured source.
+// The version is empty for modules that are not versioned, e.g. local modules.
+type ModuleInventory struct {
+	Source  string `json:"source"`
+	Version string `json:"version,omitempty"`
+}
+
+// ProviderInventory is a provider selected in the dependency lock file of a
+// task's workspace
+type ProviderInventory struct {
+	Source  string `json:"source"`
+	Version string `json:"version"`
+}
+
+// ReadInventory reads the inventory of the module and providers of a task
+// from its working directory. The module and providers are not installed
+// until the task is initialized, so the providers are empty for tasks that
+// have not been initialized.
+func ReadInventory(task *Task) (Inventory, error) {
+	wd := task.WorkingDir()
+	inv := Inventory{
+		TaskName:  task.Name(),
+		Module:    ModuleInventory{Source: task.Module()},
+		Providers: []ProviderInventory{},
+	}
+
+	source, version, err := readInstalledModule(wd, task.Name())
+	if err != nil {
+		return inv, err
+	}
+	if source != "" {
+		inv.Module.Source = source
+	}
+	inv.Module.Version = version
+
+	providers, err := readLockedProviders(filepath.Join(wd, lockFilename))
+	if err != nil {
+		return inv, err
+	}
+	inv.Providers = providers
+
+	return inv, nil
+}
+
+// readInstalledModule returns the source and version of the module that
+// Terraform resolved and installed for the module block of a task. Returns
+// empty strings if the module is not installed or the values are not known,
+// e.g. the version of local modules.
+func readInstalledModule(workingDir, moduleName string) (string, string, error) {
+	content, err := os.ReadFile(filepath.Join(workingDir, modulesManifestPath))
+	if os.IsNotExist(err) {
+		return "", "", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	var manifest struct {
+		Modules []struct {
+			Key     string `json:"Key"`
+			Source  string `json:"Source"`
+			Version string `json:"Version"`
+		} `json:"Modules"`
+	}
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return "", "", err
+	}
+
+	for _, m := range manifest.Modules {
+		if m.Key == moduleName {
+			return m.Source, m.Version, nil
+		}
+	}
+	return "", "", nil
+}
+
+// readLockedProviders returns the providers selected in a dependency lock
+// file, sorted by source. Returns no providers if the lock file does not
+// exist.
+func readLockedProviders(path string) ([]ProviderInventory, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return []ProviderInventory{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	file, diags := hclparse.NewParser().ParseHCL(content, path)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	var lock struct {
+		Providers []struct {
+			Source  string   `hcl:"source,label"`
+			Version string   `hcl:"version"`
+			Remain  hcl.Body `hcl:",remain"`
+		} `hcl:"provider,block"`
+		Remain hcl.Body `hcl:",remain"`
+	}
+	if diags := gohcl.DecodeBody(file.Body, nil, &lock); diags.HasErrors() {
+		return nil, diags
+	}
+
+	providers := make([]ProviderInventory, 0, len(lock.Providers))
+	for _, p := range lock.Providers {
+		providers = append(providers, ProviderInventory{
+			Source:  p.Source,
+			Version: p.Version,
+		})
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].Source < providers[j].Source
+	})
+	return providers, nil
+}
@@ -0,0 +1,123 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testLockFile = `# This file is maintained automatically by "terraform init".
+# Manual edits may be lost in future updates.
+
+provider "registry.terraform.io/hashicorp/null" {
+  version     = "3.1.1"
+  constraints = "~> 3.1"
+  hashes = [
+    "h1:abc=",
+  ]
+}
+
+provider "registry.terraform.io/hashicorp/local" {
+  version = "2.2.3"
+  hashes = [
+    "h1:def=",
+  ]
+}
+`
+
+const testModulesManifest = `{"Modules":[` +
+	`{"Key":"","Source":"","Dir":"."},` +
+	`{"Key":"task","Source":"registry.terraform.io/org/mod/local",` +
+	`"Version":"1.2.0","Dir":".terraform/modules/task"}]}`
+
+func writeTestFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestReadInventory(t *testing.T) {
+	t.Parallel()
+
+	t.Run("initialized", func(t *testing.T) {
+		wd := t.TempDir()
+		writeTestFile(t, filepath.Join(wd, modulesManifestPath), testModulesManifest)
+		writeTestFile(t, filepath.Join(wd, lockFilename), testLockFile)
+
+		task := &Task{name: "task", module: "org/mod/local", workingDir: wd,
+			logger: logging.NewNullLogger()}
+		inv, err := ReadInventory(task)
+		require.NoError(t, err)
+		assert.Equal(t, Inventory{
+			TaskName: "task",
+			Module: ModuleInventory{
+				Source:  "registry.terraform.io/org/mod/local",
+				Version: "1.2.0",
+			},
+			Providers: []ProviderInventory{
+				{Source: "registry.terraform.io/hashicorp/local", Version: "2.2.3"},
+				{Source: "registry.terraform.io/hashicorp/null", Version: "3.1.1"},
+			},
+		}, inv)
+	})
+
+	t.Run("not initialized", func(t *testing.T) {
+		task := &Task{name: "task", module: "./local-module",
+			workingDir: t.TempDir(), logger: logging.NewNullLogger()}
+		inv, err := ReadInventory(task)
+		require.NoError(t, err)
+		assert.Equal(t, Inventory{
+			TaskName:  "task",
+			Module:    ModuleInventory{Source: "./local-module"},
+			Providers: []ProviderInventory{},
+		}, inv)
+	})
+
+	t.Run("invalid lock file", func(t *testing.T) {
+		wd := t.TempDir()
+		writeTestFile(t, filepath.Join(wd, lockFilename), `provider "a" {`)
+
+		task := &Task{name: "task", workingDir: wd, logger: logging.NewNullLogger()}
+		_, err := ReadInventory(task)
+		assert.Error(t, err)
+	})
+}
+
+func TestReadInstalledModule(t *testing.T) {
+	t.Parallel()
+
+	t.Run("registry module", func(t *testing.T) {
+		wd := t.TempDir()
+		writeTestFile(t, filepath.Join(wd, modulesManifestPath), testModulesManifest)
+
+		source, version, err := readInstalledModule(wd, "task")
+		require.NoError(t, err)
+		assert.Equal(t, "registry.terraform.io/org/mod/local", source)
+		assert.Equal(t, "1.2.0", version)
+
+		source, version, err = readInstalledModule(wd, "other")
+		require.NoError(t, err)
+		assert.Empty(t, source)
+		assert.Empty(t, version)
+	})
+
+	t.Run("no manifest", func(t *testing.T) {
+		_, version, err := readInstalledModule(t.TempDir(), "task")
+		require.NoError(t, err)
+		assert.Empty(t, version)
+	})
+
+	t.Run("invalid manifest", func(t *testing.T) {
+		wd := t.TempDir()
+		writeTestFile(t, filepath.Join(wd, modulesManifestPath), "{")
+
+		_, _, err := readInstalledModule(wd, "task")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,34 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrTaskLocked is the error returned when a task is locked while it is
+	// already locked
+	ErrTaskLocked = errors.New("task is locked")
+
+	// ErrTaskNotLocked is the error returned when a task is unlocked while it
+	// is not locked
+	ErrTaskNotLocked = errors.New("task is not locked")
+)
+
+// TaskLock is an advisory lock of a task. While a task is locked, runs of the
+// task triggered by CTS are queued so that an operator can run Terraform in
+// the working directory of the task without interleaving changes. The queued
+// run is run once the task is unlocked.
+type TaskLock struct {
+	TaskName string    `json:"task_name"`
+	Holder   string    `json:"holder"`
+	Reason   string    `json:"reason,omitempty"`
+	LockedAt time.Time `json:"locked_at"`
+
+	// RunQueued is whether the task was triggered while locked and will run
+	// once it is unlocked
+	RunQueued bool `json:"run_queued"`
+}
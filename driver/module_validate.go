@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl"
+)
+
+// moduleContractTypes maps the variable types of the monitored objects to the
+// types of the variable contract of modules
+var moduleContractTypes = map[string]string{
+	"services":         "services",
+	"catalog_services": "catalog-services",
+	"consul_kv":        "consul-kv",
+	"dns_records":      "dns",
+	"files":            "file",
+}
+
+// ModuleValidation is the result of checking that the module of a task
+// declares the variables rendered by CTS for the task with compatible types
+type ModuleValidation struct {
+	// Checked is false if the module is not a local module. Remote modules
+	// are not downloaded to be checked.
+	Checked bool
+
+	// Mismatches are the incompatibilities between the module variables and
+	// the variables rendered for the task
+	Mismatches []tftmpl.VariableMismatch
+
+	// Warnings are the module variables that could not be checked
+	Warnings []string
+}
+
+// ModuleError is an error loading the variables of a task's module
+type ModuleError struct {
+	Source string
+	Err    error
+}
+
+func (e *ModuleError) Error() string {
+	return fmt.Sprintf("unable to check the variables of module %q: %s",
+		e.Source, e.Err)
+}
+
+func (e *ModuleError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateTaskModule checks the variables of the module of a finalized task
+// configuration against the variables rendered for the task's condition and
+// module inputs. Relative local module paths are resolved from the working
+// directory of the task, as they are by Terraform.
+func ValidateTaskModule(tc config.TaskConfig) (ModuleValidation, error) {
+	source := config.StringVal(tc.Module)
+	if !isLocalModuleSource(source) {
+		return ModuleValidation{}, nil
+	}
+
+	path := source
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(config.StringVal(tc.WorkingDir), path)
+	}
+
+	input := tftmpl.ModuleValidateInput{Path: path}
+	if tc.Condition != nil && renderConditionVar(tc.Condition) {
+		input.Condition = moduleContractTypes[tc.Condition.VariableType()]
+		if c, ok := tc.Condition.(*config.ServicesConditionConfig); ok {
+			input.ExtendedMetadata = config.BoolVal(c.IncludeExtendedMetadata)
+		}
+	}
+	if tc.ModuleInputs != nil {
+		for _, mi := range *tc.ModuleInputs {
+			input.ModuleInputs = append(input.ModuleInputs,
+				moduleContractTypes[mi.VariableType()])
+			if s, ok := mi.(*config.ServicesModuleInputConfig); ok &&
+				config.BoolVal(s.IncludeExtendedMetadata) {
+				input.ExtendedMetadata = true
+			}
+		}
+	}
+
+	result, err := tftmpl.ValidateModule(input)
+	if err != nil {
+		return ModuleValidation{}, &ModuleError{Source: source, Err: err}
+	}
+	return ModuleValidation{
+		Checked:    true,
+		Mismatches: result.Mismatches,
+		Warnings:   result.Warnings,
+	}, nil
+}
+
+// renderConditionVar returns true if the variable of the objects monitored by
+// the condition is passed to the module
+func renderConditionVar(c config.ConditionConfig) bool {
+	switch v := c.(type) {
+	case *config.ServicesConditionConfig:
+		return config.BoolVal(v.UseAsModuleInput)
+	case *config.CatalogServicesConditionConfig:
+		return config.BoolVal(v.UseAsModuleInput)
+	case *config.ConsulKVConditionConfig:
+		return config.BoolVal(v.UseAsModuleInput)
+	case *config.DNSConditionConfig:
+		return config.BoolVal(v.UseAsModuleInput)
+	case *config.FileConditionConfig:
+		return config.BoolVal(v.UseAsModuleInput)
+	case *config.PluginConditionConfig:
+		return config.BoolVal(v.UseAsModuleInput)
+	default:
+		return false
+	}
+}
+
+// isLocalModuleSource returns true if the source is a local path. Terraform
+// only treats sources with these prefixes as local paths.
+func isLocalModuleSource(source string) bool {
+	for _, prefix := range []string{"./", "../", "/"} {
+		if strings.HasPrefix(source, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTaskModule(t *testing.T) {
+	t.Parallel()
+
+	// module with the variables for the services condition and no module
+	// inputs
+	dir := t.TempDir()
+	moduleDir := filepath.Join(dir, "my-module")
+	require.NoError(t, tftmpl.ScaffoldModule(tftmpl.ModuleScaffoldInput{
+		Path:      moduleDir,
+		Condition: "services",
+	}))
+
+	taskConfig := func(module string, moduleInputs ...config.ModuleInputConfig) config.TaskConfig {
+		mi := config.ModuleInputConfigs(moduleInputs)
+		tc := config.TaskConfig{
+			Name:       config.String("task"),
+			Module:     config.String(module),
+			WorkingDir: config.String(filepath.Join(dir, "sync-tasks", "task")),
+			Condition: &config.ServicesConditionConfig{
+				ServicesMonitorConfig: config.ServicesMonitorConfig{
+					Names: []string{"api"},
+				},
+			},
+			ModuleInputs: &mi,
+		}
+		require.NoError(t, tc.Finalize())
+		return tc
+	}
+
+	t.Run("compatible", func(t *testing.T) {
+		result, err := ValidateTaskModule(taskConfig(moduleDir))
+		require.NoError(t, err)
+		assert.True(t, result.Checked)
+		assert.Empty(t, result.Mismatches)
+	})
+
+	t.Run("relative path", func(t *testing.T) {
+		result, err := ValidateTaskModule(taskConfig("../../my-module"))
+		require.NoError(t, err)
+		assert.True(t, result.Checked)
+		assert.Empty(t, result.Mismatches)
+	})
+
+	t.Run("missing module input variable", func(t *testing.T) {
+		result, err := ValidateTaskModule(taskConfig(moduleDir,
+			&config.ConsulKVModuleInputConfig{
+				ConsulKVMonitorConfig: config.ConsulKVMonitorConfig{
+					Path: config.String("key"),
+				},
+			}))
+		require.NoError(t, err)
+		assert.True(t, result.Checked)
+		require.Len(t, result.Mismatches, 1)
+		assert.Equal(t, "consul_kv", result.Mismatches[0].Variable)
+	})
+
+	t.Run("remote module", func(t *testing.T) {
+		result, err := ValidateTaskModule(taskConfig("mkam/hello/cts"))
+		require.NoError(t, err)
+		assert.False(t, result.Checked)
+	})
+
+	t.Run("missing module", func(t *testing.T) {
+		_, err := ValidateTaskModule(taskConfig(filepath.Join(dir, "missing")))
+		var moduleErr *ModuleError
+		require.True(t, errors.As(err, &moduleErr))
+	})
+}
@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// pluginCacheLocks are the locks of the shared plugin cache directories by
+// the path of the directory. Terraform does not support concurrent installs
+// into the same plugin cache, so the tasks that share a cache initialize one
+// at a time.
+var pluginCacheLocks = struct {
+	sync.Mutex
+	locks map[string]*sync.Mutex
+}{locks: make(map[string]*sync.Mutex)}
+
+// PluginCache configures Terraform to install providers into a plugin cache
+// directory instead of downloading the providers for each task
+type PluginCache struct {
+	// Dir is the path of the plugin cache directory
+	Dir string
+
+	// Shared is whether the directory is shared with other tasks
+	Shared bool
+}
+
+// pluginCacheLock returns the lock for the plugin cache directory
+func pluginCacheLock(dir string) sync.Locker {
+	pluginCacheLocks.Lock()
+	defer pluginCacheLocks.Unlock()
+
+	key := filepath.Clean(dir)
+	lock, ok := pluginCacheLocks.locks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		pluginCacheLocks.locks[key] = lock
+	}
+	return lock
+}
+
+// initPluginCache creates the plugin cache directory, which Terraform requires
+// to exist, and returns the environment variables for Terraform to use the
+// cache.
+func initPluginCache(cache *PluginCache) (map[string]string, error) {
+	if err := os.MkdirAll(cache.Dir, workingDirPerms); err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		"TF_PLUGIN_CACHE_DIR": cache.Dir,
+		// The generated root modules of tasks do not have a dependency lock
+		// file on the first init. Without this option, Terraform does not
+		// install providers from the cache until the lock file exists.
+		"TF_PLUGIN_CACHE_MAY_BREAK_DEPENDENCY_LOCK_FILE": "true",
+	}, nil
+}
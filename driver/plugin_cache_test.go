@@ -0,0 +1,33 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginCacheLock(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	assert.Same(t, pluginCacheLock(dir), pluginCacheLock(dir+"/"))
+	assert.NotSame(t, pluginCacheLock(dir), pluginCacheLock(filepath.Join(dir, "web")))
+}
+
+func TestInitPluginCache(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "plugin-cache")
+	env, err := initPluginCache(&PluginCache{Dir: dir, Shared: true})
+	require.NoError(t, err)
+	assert.DirExists(t, dir)
+	assert.Equal(t, map[string]string{
+		"TF_PLUGIN_CACHE_DIR":                            dir,
+		"TF_PLUGIN_CACHE_MAY_BREAK_DEPENDENCY_LOCK_FILE": "true",
+	}, env)
+}
@@ -0,0 +1,129 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+)
+
+// checkPostconditions evaluates the postconditions against the Terraform
+// outputs and returns an error for the first postcondition that fails
+func checkPostconditions(postconds config.PostconditionConfigs,
+	outputs map[string]json.RawMessage) error {
+
+	for _, p := range postconds {
+		if err := checkPostcondition(p, outputs); err != nil {
+			if msg := config.StringVal(p.ErrorMessage); msg != "" {
+				return fmt.Errorf("postcondition failed: %s: %s", msg, err)
+			}
+			return fmt.Errorf("postcondition failed: %s", err)
+		}
+	}
+	return nil
+}
+
+// checkPostcondition evaluates a postcondition against the Terraform outputs
+func checkPostcondition(p *config.PostconditionConfig,
+	outputs map[string]json.RawMessage) error {
+
+	name := config.StringVal(p.Output)
+	op := config.StringVal(p.Operator)
+	expected := config.StringVal(p.Value)
+
+	raw, ok := outputs[name]
+	if !ok {
+		return fmt.Errorf("output %q does not exist", name)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("unable to decode output %q: %s", name, err)
+	}
+
+	var holds bool
+	var err error
+	switch v := value.(type) {
+	case nil:
+		return fmt.Errorf("output %q is null", name)
+	case json.Number:
+		var actual float64
+		if actual, err = v.Float64(); err != nil {
+			return fmt.Errorf("unable to parse output %q as a number: %s", name, err)
+		}
+		holds, err = compareNumbers(actual, op, expected)
+	case []interface{}:
+		// lists, sets, and tuples compare their number of elements
+		holds, err = compareNumbers(float64(len(v)), op, expected)
+	case map[string]interface{}:
+		// maps and objects compare their number of elements
+		holds, err = compareNumbers(float64(len(v)), op, expected)
+	case bool:
+		b, parseErr := strconv.ParseBool(expected)
+		if parseErr != nil {
+			return fmt.Errorf("unable to parse value %q as a bool to compare "+
+				"to output %q", expected, name)
+		}
+		holds, err = compareEquality(v == b, op, name)
+	case string:
+		holds, err = compareEquality(v == expected, op, name)
+	default:
+		return fmt.Errorf("unsupported type %T for output %q", value, name)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !holds {
+		return fmt.Errorf("output %q with value %s is not %s %s", name,
+			string(raw), op, expected)
+	}
+	return nil
+}
+
+// compareNumbers compares the actual number to the expected value using
+// the operator
+func compareNumbers(actual float64, op, value string) (bool, error) {
+	expected, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false, fmt.Errorf("unable to parse value %q as a number", value)
+	}
+
+	switch op {
+	case config.PostconditionOperatorEqual:
+		return actual == expected, nil
+	case config.PostconditionOperatorNotEqual:
+		return actual != expected, nil
+	case config.PostconditionOperatorGreaterThan:
+		return actual > expected, nil
+	case config.PostconditionOperatorGreaterThanOrEqual:
+		return actual >= expected, nil
+	case config.PostconditionOperatorLessThan:
+		return actual < expected, nil
+	case config.PostconditionOperatorLessThanOrEqual:
+		return actual <= expected, nil
+	default:
+		return false, fmt.Errorf("unsupported operator %q", op)
+	}
+}
+
+// compareEquality returns whether the postcondition holds given whether the
+// output is equal to the value. Only the == and != operators are supported.
+func compareEquality(equal bool, op, name string) (bool, error) {
+	switch op {
+	case config.PostconditionOperatorEqual:
+		return equal, nil
+	case config.PostconditionOperatorNotEqual:
+		return !equal, nil
+	default:
+		return false, fmt.Errorf("operator %q is not supported for output %q, "+
+			"only == and != are supported for string and bool outputs", op, name)
+	}
+}
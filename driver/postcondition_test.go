@@ -0,0 +1,97 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPostcondition(t *testing.T) {
+	t.Parallel()
+
+	outputs := map[string]json.RawMessage{
+		"members_count": json.RawMessage(`2`),
+		"members":       json.RawMessage(`["a", "b"]`),
+		"tags":          json.RawMessage(`{}`),
+		"address":       json.RawMessage(`"10.0.0.1"`),
+		"healthy":       json.RawMessage(`true`),
+		"unset":         json.RawMessage(`null`),
+	}
+
+	cases := []struct {
+		name     string
+		output   string
+		operator string
+		value    string
+		holds    bool
+		isError  bool
+	}{
+		{"number >=", "members_count", ">=", "1", true, false},
+		{"number > fails", "members_count", ">", "2", false, false},
+		{"number ==", "members_count", "==", "2", true, false},
+		{"number !=", "members_count", "!=", "2", false, false},
+		{"number <", "members_count", "<", "2.5", true, false},
+		{"number <=", "members_count", "<=", "1", false, false},
+		{"number invalid value", "members_count", ">=", "one", false, true},
+		{"list length", "members", ">=", "1", true, false},
+		{"empty map length", "tags", ">=", "1", false, false},
+		{"string ==", "address", "==", "10.0.0.1", true, false},
+		{"string !=", "address", "!=", "10.0.0.1", false, false},
+		{"string unsupported operator", "address", ">", "10.0.0.1", false, true},
+		{"bool ==", "healthy", "==", "true", true, false},
+		{"bool != ", "healthy", "!=", "true", false, false},
+		{"bool invalid value", "healthy", "==", "yes", false, true},
+		{"null output", "unset", "==", "1", false, true},
+		{"missing output", "missing", ">=", "1", false, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &config.PostconditionConfig{
+				Output:   config.String(tc.output),
+				Operator: config.String(tc.operator),
+				Value:    config.String(tc.value),
+			}
+			err := checkPostcondition(p, outputs)
+			if tc.holds {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			if !tc.isError {
+				assert.Contains(t, err.Error(), "is not")
+			}
+		})
+	}
+}
+
+func TestCheckPostconditions(t *testing.T) {
+	t.Parallel()
+
+	outputs := map[string]json.RawMessage{
+		"members_count": json.RawMessage(`0`),
+	}
+
+	t.Run("error message", func(t *testing.T) {
+		err := checkPostconditions(config.PostconditionConfigs{
+			{
+				Output:       config.String("members_count"),
+				Operator:     config.String(">="),
+				Value:        config.String("1"),
+				ErrorMessage: config.String("load balancer has no members"),
+			},
+		}, outputs)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "load balancer has no members")
+	})
+
+	t.Run("no postconditions", func(t *testing.T) {
+		err := checkPostconditions(config.PostconditionConfigs{}, outputs)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,226 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Actions of the resources in the progress of a Terraform run
+const (
+	ResourceActionCreate  = "create"
+	ResourceActionUpdate  = "update"
+	ResourceActionReplace = "replace"
+	ResourceActionDelete  = "delete"
+	ResourceActionRead    = "read"
+	ResourceActionRefresh = "refresh"
+)
+
+// Statuses of the resources in the progress of a Terraform run
+const (
+	ResourceStatusInProgress = "in_progress"
+	ResourceStatusComplete   = "complete"
+	ResourceStatusErrored    = "errored"
+)
+
+// Types of the messages of Terraform's machine-readable UI that are tracked.
+// https://developer.hashicorp.com/terraform/internals/machine-readable-ui
+const (
+	uiApplyStart      = "apply_start"
+	uiApplyProgress   = "apply_progress"
+	uiApplyComplete   = "apply_complete"
+	uiApplyErrored    = "apply_errored"
+	uiRefreshStart    = "refresh_start"
+	uiRefreshComplete = "refresh_complete"
+	uiChangeSummary   = "change_summary"
+)
+
+// uiMessage is the subset of a message of Terraform's machine-readable UI
+// that is needed to track the progress of a run
+type uiMessage struct {
+	Type string `json:"type"`
+	Hook struct {
+		Resource struct {
+			Addr string `json:"addr"`
+		} `json:"resource"`
+		Action string `json:"action"`
+	} `json:"hook"`
+	Changes *struct {
+		Add       int    `json:"add"`
+		Change    int    `json:"change"`
+		Remove    int    `json:"remove"`
+		Operation string `json:"operation"`
+	} `json:"changes"`
+}
+
+// Progress is the progress of the latest Terraform run of a task. Active is
+// true while the run is in-flight.
+type Progress struct {
+	TaskName  string             `json:"task_name"`
+	Active    bool               `json:"active"`
+	StartTime time.Time          `json:"start_time"`
+	EndTime   *time.Time         `json:"end_time,omitempty"`
+	Resources []ResourceProgress `json:"resources"`
+}
+
+// ResourceProgress is the progress of the latest action on a resource during
+// a Terraform run
+type ResourceProgress struct {
+	Address    string    `json:"address"`
+	Action     string    `json:"action"`
+	Status     string    `json:"status"`
+	UpdateTime time.Time `json:"update_time"`
+}
+
+// progressTracker tracks the progress of a Terraform run by consuming the
+// machine-readable UI of the plans and applies of the Terraform client, which
+// has one JSON message per line.
+type progressTracker struct {
+	mu sync.RWMutex
+
+	taskName  string
+	startTime time.Time
+	endTime   *time.Time
+
+	// resources is the progress of each resource by address. order is the
+	// order that each resource first appeared in the output.
+	resources map[string]*ResourceProgress
+	order     []string
+
+	// changes is the change summary of the apply, nil until it is reported
+	changes *ChangeSummary
+
+	// partial is the output of an incomplete line
+	partial []byte
+
+	now func() time.Time
+}
+
+// newProgressTracker returns a tracker for a Terraform run of a task that
+// starts now
+func newProgressTracker(taskName string) *progressTracker {
+	return &progressTracker{
+		taskName:  taskName,
+		startTime: time.Now(),
+		resources: make(map[string]*ResourceProgress),
+		now:       time.Now,
+	}
+}
+
+// Write consumes the machine-readable UI of the Terraform client. It
+// satisfies io.Writer and always consumes the full output.
+func (p *progressTracker) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.partial = append(p.partial, b...)
+	for {
+		i := bytes.IndexByte(p.partial, '\n')
+		if i < 0 {
+			break
+		}
+		p.parseLine(p.partial[:i])
+		p.partial = p.partial[i+1:]
+	}
+	return len(b), nil
+}
+
+// parseLine updates the progress of the resource of a message. Messages that
+// are not about the progress of a resource or the changes of the apply are
+// ignored, as well as lines that are not JSON.
+func (p *progressTracker) parseLine(line []byte) {
+	var msg uiMessage
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return
+	}
+
+	address := msg.Hook.Resource.Addr
+	switch msg.Type {
+	case uiApplyStart, uiApplyProgress:
+		p.update(address, msg.Hook.Action, ResourceStatusInProgress)
+	case uiApplyComplete:
+		p.update(address, msg.Hook.Action, ResourceStatusComplete)
+	case uiApplyErrored:
+		p.update(address, msg.Hook.Action, ResourceStatusErrored)
+	case uiRefreshStart:
+		p.update(address, ResourceActionRefresh, ResourceStatusInProgress)
+	case uiRefreshComplete:
+		p.update(address, ResourceActionRefresh, ResourceStatusComplete)
+	case uiChangeSummary:
+		// Plans also report a change summary of the proposed changes
+		if msg.Changes != nil && msg.Changes.Operation == "apply" {
+			p.changes = &ChangeSummary{
+				Add:     msg.Changes.Add,
+				Change:  msg.Changes.Change,
+				Destroy: msg.Changes.Remove,
+			}
+		}
+	}
+}
+
+func (p *progressTracker) update(address, action, status string) {
+	if address == "" {
+		return
+	}
+
+	r, ok := p.resources[address]
+	if !ok {
+		r = &ResourceProgress{Address: address}
+		p.resources[address] = r
+		p.order = append(p.order, address)
+	}
+	r.Action = action
+	r.Status = status
+	r.UpdateTime = p.now()
+}
+
+// finish marks the run as no longer in-flight
+func (p *progressTracker) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.partial) > 0 {
+		p.parseLine(p.partial)
+		p.partial = nil
+	}
+	end := p.now()
+	p.endTime = &end
+}
+
+// changeSummary returns the change summary of the apply. Returns nil if the
+// apply did not report one, e.g. it errored.
+func (p *progressTracker) changeSummary() *ChangeSummary {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.changes == nil {
+		return nil
+	}
+	changes := *p.changes
+	return &changes
+}
+
+// Progress returns a snapshot of the progress of the run
+func (p *progressTracker) Progress() Progress {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	progress := Progress{
+		TaskName:  p.taskName,
+		Active:    p.endTime == nil,
+		StartTime: p.startTime,
+		Resources: make([]ResourceProgress, 0, len(p.order)),
+	}
+	if p.endTime != nil {
+		end := *p.endTime
+		progress.EndTime = &end
+	}
+	for _, address := range p.order {
+		progress.Resources = append(progress.Resources, *p.resources[address])
+	}
+	return progress
+}
@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressTracker(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, time.June, 1, 10, 0, 0, 0, time.UTC)
+	p := newProgressTracker("task")
+	p.now = func() time.Time { return now }
+
+	write := func(s string) {
+		n, err := p.Write([]byte(s))
+		require.NoError(t, err)
+		assert.Equal(t, len(s), n)
+	}
+	line := func(msgType, addr, action string) string {
+		return fmt.Sprintf(`{"@level":"info","@message":"%s: message","type":%q,`+
+			`"hook":{"resource":{"addr":%q},"action":%q}}`+"\n",
+			strings.ReplaceAll(addr, `"`, `\"`), msgType, addr, action)
+	}
+
+	write(`{"@level":"info","@message":"Terraform 1.2.2","type":"version"}` + "\n" +
+		line("refresh_start", "module.task.local_file.a", "") +
+		line("refresh_complete", "module.task.local_file.a", "") +
+		`{"@level":"info","@message":"Plan: 1 to add, 1 to change, 1 to destroy.",` +
+		`"type":"change_summary","changes":{"add":1,"change":1,"remove":1,"operation":"plan"}}` + "\n" +
+		line("apply_start", "module.task.local_file.b", "create") +
+		line("apply_start", "module.task.local_file.c", "delete")[:40])
+
+	progress := p.Progress()
+	assert.Equal(t, "task", progress.TaskName)
+	assert.True(t, progress.Active)
+	assert.Nil(t, progress.EndTime)
+	assert.Equal(t, []ResourceProgress{
+		{Address: "module.task.local_file.a", Action: ResourceActionRefresh,
+			Status: ResourceStatusComplete, UpdateTime: now},
+		{Address: "module.task.local_file.b", Action: ResourceActionCreate,
+			Status: ResourceStatusInProgress, UpdateTime: now},
+	}, progress.Resources)
+
+	// The change summary of the plan is not the change summary of the apply
+	assert.Nil(t, p.changeSummary())
+
+	// Complete the partial line and the in-flight resources
+	write(line("apply_start", "module.task.local_file.c", "delete")[40:] +
+		line("apply_progress", "module.task.local_file.b", "create") +
+		line("apply_complete", "module.task.local_file.b", "create") +
+		line("apply_start", `module.task.local_file.d["a b"]`, "update") +
+		line("apply_errored", `module.task.local_file.d["a b"]`, "update") +
+		line("apply_start", "module.task.local_file.e", "replace") +
+		"not machine-readable output\n" +
+		`{"@level":"info","@message":"Apply complete! Resources: 1 added, 1 changed, 1 destroyed.",` +
+		`"type":"change_summary","changes":{"add":1,"change":1,"remove":1,"operation":"apply"}}` + "\n")
+	p.finish()
+
+	progress = p.Progress()
+	assert.False(t, progress.Active)
+	require.NotNil(t, progress.EndTime)
+	assert.Equal(t, now, *progress.EndTime)
+	assert.Equal(t, []ResourceProgress{
+		{Address: "module.task.local_file.a", Action: ResourceActionRefresh,
+			Status: ResourceStatusComplete, UpdateTime: now},
+		{Address: "module.task.local_file.b", Action: ResourceActionCreate,
+			Status: ResourceStatusComplete, UpdateTime: now},
+		{Address: "module.task.local_file.c", Action: ResourceActionDelete,
+			Status: ResourceStatusInProgress, UpdateTime: now},
+		{Address: `module.task.local_file.d["a b"]`, Action: ResourceActionUpdate,
+			Status: ResourceStatusErrored, UpdateTime: now},
+		{Address: "module.task.local_file.e", Action: ResourceActionReplace,
+			Status: ResourceStatusInProgress, UpdateTime: now},
+	}, progress.Resources)
+	assert.Equal(t, &ChangeSummary{Add: 1, Change: 1, Destroy: 1}, p.changeSummary())
+}
+
+func TestProgressTracker_Finish(t *testing.T) {
+	t.Parallel()
+
+	p := newProgressTracker("task")
+	_, err := p.Write([]byte(`{"type":"apply_complete","hook":{"resource":` +
+		`{"addr":"module.task.local_file.a"},"action":"delete"}}`))
+	require.NoError(t, err)
+	assert.Empty(t, p.Progress().Resources)
+
+	// The last line is parsed when the run finishes
+	p.finish()
+	progress := p.Progress()
+	assert.False(t, progress.Active)
+	require.Len(t, progress.Resources, 1)
+	assert.Equal(t, ResourceActionDelete, progress.Resources[0].Action)
+	assert.Equal(t, ResourceStatusComplete, progress.Resources[0].Status)
+}
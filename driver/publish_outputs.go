@@ -0,0 +1,64 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	consulapi "github.com/hashicorp/consul/api"
+)
+
+// outputKVPairs returns the Consul KV pairs to publish the Terraform outputs.
+// Each output is keyed by its name under the path. String outputs are
+// published as their value and other outputs as compact JSON.
+func outputKVPairs(publish PublishOutputs,
+	outputs map[string]json.RawMessage) (consulapi.KVPairs, error) {
+
+	names := publish.Outputs
+	if len(names) == 0 {
+		names = make([]string, 0, len(outputs))
+		for name := range outputs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	prefix := strings.TrimSuffix(publish.Path, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	pairs := make(consulapi.KVPairs, 0, len(names))
+	for _, name := range names {
+		raw, ok := outputs[name]
+		if !ok {
+			return nil, fmt.Errorf("output %q does not exist", name)
+		}
+
+		value, err := outputKVValue(raw)
+		if err != nil {
+			return nil, fmt.Errorf("unable to publish output %q: %s", name, err)
+		}
+		pairs = append(pairs, &consulapi.KVPair{Key: prefix + name, Value: value})
+	}
+	return pairs, nil
+}
+
+// outputKVValue returns the value of a Terraform output to write to Consul KV
+func outputKVValue(raw json.RawMessage) ([]byte, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return []byte(s), nil
+	}
+
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,78 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"encoding/json"
+	"testing"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputKVPairs(t *testing.T) {
+	t.Parallel()
+
+	outputs := map[string]json.RawMessage{
+		"id":      json.RawMessage(`"lb-123"`),
+		"count":   json.RawMessage(`2`),
+		"enabled": json.RawMessage(`true`),
+		"tags": json.RawMessage(`{
+  "env": "prod"
+}`),
+	}
+
+	cases := []struct {
+		name        string
+		publish     PublishOutputs
+		expected    consulapi.KVPairs
+		expectError bool
+	}{
+		{
+			"all outputs",
+			PublishOutputs{Path: "cts/outputs/task"},
+			consulapi.KVPairs{
+				{Key: "cts/outputs/task/count", Value: []byte("2")},
+				{Key: "cts/outputs/task/enabled", Value: []byte("true")},
+				{Key: "cts/outputs/task/id", Value: []byte("lb-123")},
+				{Key: "cts/outputs/task/tags", Value: []byte(`{"env":"prod"}`)},
+			},
+			false,
+		},
+		{
+			"selected outputs",
+			PublishOutputs{Path: "cts/", Outputs: []string{"id"}},
+			consulapi.KVPairs{
+				{Key: "cts/id", Value: []byte("lb-123")},
+			},
+			false,
+		},
+		{
+			"empty path",
+			PublishOutputs{Outputs: []string{"count"}},
+			consulapi.KVPairs{
+				{Key: "count", Value: []byte("2")},
+			},
+			false,
+		},
+		{
+			"missing output",
+			PublishOutputs{Path: "cts", Outputs: []string{"arn"}},
+			nil,
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pairs, err := outputKVPairs(tc.publish, outputs)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, pairs)
+		})
+	}
+}
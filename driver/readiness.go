@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import "sort"
+
+// Readiness is the progress of resolving the template of a task for its
+// first run. The task is ready once all of the dependencies of the template
+// have been fetched at least once and the template has been rendered.
+type Readiness struct {
+	TaskName string `json:"task_name"`
+	Ready    bool   `json:"ready"`
+
+	// Fetched and Total are the number of dependencies of the template that
+	// have been fetched and the number of dependencies of the template.
+	// Dependencies are only known after the template is first executed, and
+	// dependencies that are only requested with the data of others are
+	// counted once the data is fetched.
+	Fetched      int                   `json:"fetched"`
+	Total        int                   `json:"total"`
+	Dependencies []DependencyReadiness `json:"dependencies"`
+}
+
+// DependencyReadiness is whether a dependency of the template of a task has
+// been fetched at least once
+type DependencyReadiness struct {
+	Name    string `json:"name"`
+	Fetched bool   `json:"fetched"`
+}
+
+// newReadiness returns the readiness of a task from the dependencies of its
+// template by name and whether each has been fetched. The dependencies that
+// have not been fetched are listed first to surface the ones that are stuck.
+func newReadiness(taskName string, ready bool, deps map[string]bool) Readiness {
+	r := Readiness{
+		TaskName:     taskName,
+		Ready:        ready,
+		Total:        len(deps),
+		Dependencies: make([]DependencyReadiness, 0, len(deps)),
+	}
+	for name, fetched := range deps {
+		if fetched {
+			r.Fetched++
+		}
+		r.Dependencies = append(r.Dependencies, DependencyReadiness{
+			Name:    name,
+			Fetched: fetched,
+		})
+	}
+
+	sort.Slice(r.Dependencies, func(i, j int) bool {
+		a, b := r.Dependencies[i], r.Dependencies[j]
+		if a.Fetched != b.Fetched {
+			return !a.Fetched
+		}
+		return a.Name < b.Name
+	})
+	return r
+}
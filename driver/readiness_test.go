@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReadiness(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not executed", func(t *testing.T) {
+		r := newReadiness("task", false, nil)
+		assert.Equal(t, Readiness{
+			TaskName:     "task",
+			Dependencies: []DependencyReadiness{},
+		}, r)
+	})
+
+	t.Run("pending dependencies first", func(t *testing.T) {
+		r := newReadiness("task", false, map[string]bool{
+			"health.service(web)": true,
+			"kv.block(key)":       false,
+			"health.service(api)": true,
+			"catalog.services":    false,
+		})
+		assert.Equal(t, Readiness{
+			TaskName: "task",
+			Fetched:  2,
+			Total:    4,
+			Dependencies: []DependencyReadiness{
+				{Name: "catalog.services"},
+				{Name: "kv.block(key)"},
+				{Name: "health.service(api)", Fetched: true},
+				{Name: "health.service(web)", Fetched: true},
+			},
+		}, r)
+	})
+
+	t.Run("ready", func(t *testing.T) {
+		r := newReadiness("task", true, map[string]bool{
+			"health.service(api)": true,
+		})
+		assert.True(t, r.Ready)
+		assert.Equal(t, 1, r.Fetched)
+		assert.Equal(t, 1, r.Total)
+	})
+}
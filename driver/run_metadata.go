@@ -0,0 +1,68 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// changeSummaryRegexp matches the resource change summary that Terraform
+// outputs at the end of an apply, e.g.
+// "Apply complete! Resources: 1 added, 0 changed, 2 destroyed."
+var changeSummaryRegexp = regexp.MustCompile(
+	`Resources: (?:\d+ imported, )?(\d+) added, (\d+) changed, (\d+) destroyed`)
+
+// RunMetadata captures details about the latest Terraform run of a task
+type RunMetadata struct {
+	// Changes is nil when the change summary is not available from the
+	// Terraform output
+	Changes          *ChangeSummary
+	TerraformVersion string
+	ModuleVersion    string
+	ApplyDuration    time.Duration
+}
+
+// ChangeSummary is the number of resources added, changed, and destroyed by
+// a Terraform apply
+type ChangeSummary struct {
+	Add     int
+	Change  int
+	Destroy int
+}
+
+// Copy returns a deep copy of the run metadata
+func (m *RunMetadata) Copy() *RunMetadata {
+	if m == nil {
+		return nil
+	}
+
+	c := *m
+	if m.Changes != nil {
+		changes := *m.Changes
+		c.Changes = &changes
+	}
+	return &c
+}
+
+// parseChangeSummary parses the resource change summary from the output of a
+// Terraform apply. Returns nil if the output does not have a summary.
+func parseChangeSummary(output string) *ChangeSummary {
+	matches := changeSummaryRegexp.FindAllStringSubmatch(output, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	// The summary is the last line of the apply output
+	m := matches[len(matches)-1]
+	add, _ := strconv.Atoi(m[1])
+	change, _ := strconv.Atoi(m[2])
+	destroy, _ := strconv.Atoi(m[3])
+	return &ChangeSummary{
+		Add:     add,
+		Change:  change,
+		Destroy: destroy,
+	}
+}
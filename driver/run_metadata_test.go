@@ -0,0 +1,49 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseChangeSummary(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		output   string
+		expected *ChangeSummary
+	}{
+		{
+			"apply complete",
+			"module.task.local_file.a: Creating...\n" +
+				"Apply complete! Resources: 1 added, 2 changed, 3 destroyed.\n",
+			&ChangeSummary{Add: 1, Change: 2, Destroy: 3},
+		},
+		{
+			"no changes",
+			"No changes. Your infrastructure matches the configuration.\n\n" +
+				"Apply complete! Resources: 0 added, 0 changed, 0 destroyed.\n",
+			&ChangeSummary{},
+		},
+		{
+			"imported resources",
+			"Apply complete! Resources: 2 imported, 1 added, 0 changed, 0 destroyed.\n",
+			&ChangeSummary{Add: 1},
+		},
+		{
+			"no summary",
+			"Error: creating resource\n",
+			nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, parseChangeSummary(tc.output))
+		})
+	}
+}
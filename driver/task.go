@@ -6,10 +6,12 @@ package driver
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/hashicorp/consul-terraform-sync/alertmanager"
 	"github.com/hashicorp/consul-terraform-sync/client"
 	"github.com/hashicorp/consul-terraform-sync/config"
 	"github.com/hashicorp/consul-terraform-sync/logging"
@@ -23,6 +25,14 @@ const (
 	RunOptionNow = "now"
 	// RunOptionInspect does a dry-run task update and returns dry-run info
 	RunOptionInspect = "inspect"
+
+	// PlanFilename is the name of the Terraform plan file saved for tasks
+	// that save their plans
+	PlanFilename = "tfplan"
+
+	// PlanJSONFilename is the name of the JSON representation of the saved
+	// Terraform plan file
+	PlanJSONFilename = "tfplan.json"
 )
 
 // PatchTask holds the information to patch update a task. It will only include
@@ -52,6 +62,45 @@ type BufferPeriod struct {
 	Max time.Duration
 }
 
+// CircuitBreaker contains the task's circuit breaker configuration
+// information if enabled
+type CircuitBreaker struct {
+	Threshold  int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// MaintenanceWindow contains the task's maintenance window configuration
+// information if enabled
+type MaintenanceWindow struct {
+	Cron     string
+	Duration time.Duration
+}
+
+// KVGate contains the configuration of a task's gate that is open while the
+// value of a Consul KV key equals the configured value
+type KVGate struct {
+	Path       string
+	Equals     string
+	Datacenter string
+	Namespace  string
+}
+
+// PublishOutputs contains the task's configuration to publish its Terraform
+// outputs to Consul KV if enabled
+type PublishOutputs struct {
+	Path    string
+	Outputs []string // all outputs are published when empty
+}
+
+// AlertSilence contains the task's configuration to silence alerts in the
+// Alertmanager before it applies changes if enabled
+type AlertSilence struct {
+	Duration time.Duration
+	Comment  string // a default comment is used when empty
+	Matchers []alertmanager.Matcher
+}
+
 // Task contains task configuration information
 type Task struct {
 	mu sync.RWMutex
@@ -59,17 +108,44 @@ type Task struct {
 	description  string
 	name         string
 	enabled      bool
+	renderOnly   bool
+	savePlan     bool
+	servicesDiff bool
+	targeted     bool
+	applyTargets map[string][]string
 	env          map[string]string
 	providers    TerraformProviderBlocks // task.providers config info
+	providerDCs  bool                    // providers are aliased by datacenter
 	providerInfo map[string]interface{}  // driver.required_provider config info
+	backend      map[string]interface{}  // nil when the driver backend is used
+	environments []TaskEnvironment
+	extraTmpls   []string
+	postconds    config.PostconditionConfigs
+	computed     map[string]string // computed input expressions by name
+	publish      *PublishOutputs   // nil when disabled
+	silence      *AlertSilence     // nil when disabled
+	handlers     config.HandlerConfigs
 	services     []Service
 	module       string
 	variables    hcltmpl.Variables // loaded variables
+	sensitive    []string          // names of sensitive variables
 	version      string
+	tfVersion    string
 	bufferPeriod *BufferPeriod // nil when disabled
+	cooldown     time.Duration
+	priority     int
+	snapshots    int
+	breaker      *CircuitBreaker    // nil when disabled
+	window       *MaintenanceWindow // nil when disabled
+	gates        []KVGate
+	dependsOn    []string
+	skipOnDepErr bool
 	condition    config.ConditionConfig
 	moduleInputs config.ModuleInputConfigs
+	anyInput     bool // trigger on module input changes
 	workingDir   string
+	lastRun      *RunMetadata     // nil until the task runs Terraform
+	progress     *progressTracker // nil until the task runs Terraform
 	logger       logging.Logger
 
 	// Enterprise
@@ -78,20 +154,54 @@ type Task struct {
 }
 
 type TaskConfig struct {
-	Description  string
-	Name         string
-	Enabled      bool
-	Env          map[string]string
-	Providers    TerraformProviderBlocks
-	ProviderInfo map[string]interface{}
-	Services     []Service
-	Module       string
-	Variables    map[string]string
-	Version      string
-	BufferPeriod *BufferPeriod
-	Condition    config.ConditionConfig
-	ModuleInputs config.ModuleInputConfigs
-	WorkingDir   string
+	Description       string
+	Name              string
+	Enabled           bool
+	RenderOnly        bool
+	SavePlan          bool
+	ServicesChanged   bool
+	TargetedApply     bool
+	ApplyTargets      map[string][]string
+	Env               map[string]string
+	Providers         TerraformProviderBlocks
+	ProviderInfo      map[string]interface{}
+	Backend           map[string]interface{}
+	Environments      []TaskEnvironment
+	ExtraTemplates    []string
+	Postconditions    config.PostconditionConfigs
+	ComputedInputs    map[string]string
+	PublishOutputs    *PublishOutputs
+	AlertSilence      *AlertSilence
+	Handlers          config.HandlerConfigs
+	Services          []Service
+	Module            string
+	Variables         map[string]string
+	Version           string
+	TFVersion         string
+	BufferPeriod      *BufferPeriod
+	Cooldown          time.Duration
+	Priority          int
+	InputSnapshots    int
+	CircuitBreaker    *CircuitBreaker
+	MaintenanceWindow *MaintenanceWindow
+	Gates             []KVGate
+	DependsOn         []string
+	SkipOnDepFailure  bool
+	Condition         config.ConditionConfig
+	ModuleInputs      config.ModuleInputConfigs
+	WorkingDir        string
+
+	// TriggerOnAnyInputChange is whether the task is triggered by changes
+	// to the data of the module inputs in addition to the condition
+	TriggerOnAnyInputChange bool
+
+	// ProviderForeachDatacenter is whether the providers are aliased by
+	// datacenter and passed to the module by alias
+	ProviderForeachDatacenter bool
+
+	// SensitiveVariables are the names of the variables whose values are
+	// written separately from the other variables with restricted permissions
+	SensitiveVariables []string
 
 	// Enterprise
 	DeprecatedTFVersion string
@@ -115,16 +225,41 @@ func NewTask(conf TaskConfig) (*Task, error) {
 		description:  conf.Description,
 		name:         conf.Name,
 		enabled:      conf.Enabled,
+		renderOnly:   conf.RenderOnly,
+		savePlan:     conf.SavePlan,
+		servicesDiff: conf.ServicesChanged,
+		targeted:     conf.TargetedApply,
+		applyTargets: conf.ApplyTargets,
 		env:          conf.Env,
 		providers:    conf.Providers,
+		providerDCs:  conf.ProviderForeachDatacenter,
 		providerInfo: conf.ProviderInfo,
+		backend:      conf.Backend,
+		environments: conf.Environments,
+		extraTmpls:   conf.ExtraTemplates,
+		postconds:    conf.Postconditions,
+		computed:     conf.ComputedInputs,
+		publish:      conf.PublishOutputs,
+		silence:      conf.AlertSilence,
+		handlers:     conf.Handlers,
 		services:     conf.Services,
 		module:       conf.Module,
 		variables:    loadedVars,
+		sensitive:    conf.SensitiveVariables,
 		version:      conf.Version,
+		tfVersion:    conf.TFVersion,
 		bufferPeriod: conf.BufferPeriod,
+		cooldown:     conf.Cooldown,
+		priority:     conf.Priority,
+		snapshots:    conf.InputSnapshots,
+		breaker:      conf.CircuitBreaker,
+		window:       conf.MaintenanceWindow,
+		gates:        conf.Gates,
+		dependsOn:    conf.DependsOn,
+		skipOnDepErr: conf.SkipOnDepFailure,
 		condition:    conf.Condition,
 		moduleInputs: conf.ModuleInputs,
+		anyInput:     conf.TriggerOnAnyInputChange,
 		workingDir:   conf.WorkingDir,
 		logger:       logging.Global().Named(logSystemName),
 
@@ -145,6 +280,87 @@ func (t *Task) BufferPeriod() (BufferPeriod, bool) {
 	return *t.bufferPeriod, true
 }
 
+// Cooldown returns the minimum interval between consecutive runs of the
+// task. A zero value means the cooldown is disabled.
+func (t *Task) Cooldown() time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.cooldown
+}
+
+// Priority returns the priority of the task. Tasks with a higher priority run
+// first when multiple tasks are pending at the same time.
+func (t *Task) Priority() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.priority
+}
+
+// InputSnapshotRetention returns the number of snapshots of the task's
+// applied input variables to retain. Snapshots are disabled when 0.
+func (t *Task) InputSnapshotRetention() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.snapshots
+}
+
+// CircuitBreaker returns a copy of the circuit breaker. If the circuit
+// breaker is not enabled, the second parameter returns false.
+func (t *Task) CircuitBreaker() (CircuitBreaker, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.breaker == nil {
+		return CircuitBreaker{}, false
+	}
+	return *t.breaker, true
+}
+
+// MaintenanceWindow returns a copy of the maintenance window. If the
+// maintenance window is not enabled, the second parameter returns false.
+func (t *Task) MaintenanceWindow() (MaintenanceWindow, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.window == nil {
+		return MaintenanceWindow{}, false
+	}
+	return *t.window, true
+}
+
+// Gates returns a copy of the gates that must be open for the task to apply
+func (t *Task) Gates() []KVGate {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if len(t.gates) == 0 {
+		return nil
+	}
+	gates := make([]KVGate, len(t.gates))
+	copy(gates, t.gates)
+	return gates
+}
+
+// DependsOn returns the names of the tasks that the task depends on
+func (t *Task) DependsOn() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return append([]string(nil), t.dependsOn...)
+}
+
+// SkipOnDependencyFailure returns true if the task is skipped when the latest
+// run of any of its dependencies failed
+func (t *Task) SkipOnDependencyFailure() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.skipOnDepErr
+}
+
+// TriggerOnAnyInputChange returns true if the task is triggered by changes to
+// the data of its module inputs in addition to the changes of its condition
+func (t *Task) TriggerOnAnyInputChange() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.anyInput
+}
+
 // Condition returns the type of condition for the task to run
 func (t *Task) Condition() config.ConditionConfig {
 	t.mu.RLock()
@@ -188,6 +404,102 @@ func (t *Task) IsEnabled() bool {
 	return t.enabled
 }
 
+// IsRenderOnly returns whether the task only renders the module input files
+// without running Terraform
+func (t *Task) IsRenderOnly() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.renderOnly
+}
+
+// RenderedFiles returns the paths of the files that are rendered for the task
+// when its dependencies change
+func (t *Task) RenderedFiles() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return []string{filepath.Join(t.workingDir, tftmpl.TFVarsFilename)}
+}
+
+// LastRun returns the metadata of the task's latest Terraform run. Returns nil
+// if the latest run of the task did not run Terraform.
+func (t *Task) LastRun() *RunMetadata {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.lastRun.Copy()
+}
+
+// setLastRun sets the metadata of the task's latest Terraform run
+func (t *Task) setLastRun(m *RunMetadata) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lastRun = m
+}
+
+// Progress returns the progress of the task's latest Terraform run, which is
+// in-flight if the progress is active. Returns false if the task has not run
+// Terraform.
+func (t *Task) Progress() (Progress, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.progress == nil {
+		return Progress{}, false
+	}
+	return t.progress.Progress(), true
+}
+
+// setProgress sets the tracker of the progress of the task's latest Terraform
+// run
+func (t *Task) setProgress(p *progressTracker) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.progress = p
+}
+
+// SavesPlan returns whether the plan for the task is saved to a file before
+// it is applied
+func (t *Task) SavesPlan() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.savePlan
+}
+
+// ServicesChanged returns whether the task passes the services_changed
+// variable to the module
+func (t *Task) ServicesChanged() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.servicesDiff
+}
+
+// TargetedApply returns whether the task applies only the resources mapped to
+// the services that changed
+func (t *Task) TargetedApply() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.targeted
+}
+
+// ApplyTargets returns the resource addresses that are mapped to a service
+// name for targeted applies, and whether the service is mapped
+func (t *Task) ApplyTargets(service string) ([]string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	targets, ok := t.applyTargets[service]
+	if !ok {
+		return nil, false
+	}
+	return append([]string{}, targets...), true
+}
+
+// PlanFiles returns the paths of the saved Terraform plan file and its JSON
+// representation for tasks that save their plans
+func (t *Task) PlanFiles() (string, string) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return filepath.Join(t.workingDir, PlanFilename),
+		filepath.Join(t.workingDir, PlanJSONFilename)
+}
+
 // Enable sets the task as enabled
 func (t *Task) Enable() {
 	t.mu.Lock()
@@ -279,6 +591,15 @@ func (t *Task) Variables() hcltmpl.Variables {
 	return vars
 }
 
+// SensitiveVariables returns the names of the sensitive input variables of
+// the module
+func (t *Task) SensitiveVariables() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return append([]string{}, t.sensitive...)
+}
+
 // Version returns the configured version for the module of the task
 func (t *Task) Version() string {
 	t.mu.RLock()
@@ -294,6 +615,92 @@ func (t *Task) WorkingDir() string {
 	return t.workingDir
 }
 
+// TFVersion returns the Terraform version configured for the task. Returns
+// an empty string if the task uses the Terraform version of the driver.
+func (t *Task) TFVersion() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.tfVersion
+}
+
+// Backend returns the Terraform backend configured for the task. Returns nil
+// if the task uses the backend of the driver.
+func (t *Task) Backend() map[string]interface{} {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.backend
+}
+
+// Environments returns a copy of the environments of the task. A task without
+// environments runs Terraform in the task's workspace.
+func (t *Task) Environments() []TaskEnvironment {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if len(t.environments) == 0 {
+		return nil
+	}
+	envs := make([]TaskEnvironment, len(t.environments))
+	for i, env := range t.environments {
+		envs[i] = env.copy()
+	}
+	return envs
+}
+
+// ExtraTemplates returns the paths of the additional templates that are
+// rendered into the task's working directory
+func (t *Task) ExtraTemplates() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.extraTmpls
+}
+
+// Postconditions returns the assertions on the Terraform outputs that are
+// evaluated after the task is applied
+func (t *Task) Postconditions() config.PostconditionConfigs {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.postconds
+}
+
+// ComputedInputs returns the expressions of the computed input variables of
+// the task by the name of the variable
+func (t *Task) ComputedInputs() map[string]string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.computed
+}
+
+// PublishOutputs returns a copy of the configuration to publish the Terraform
+// outputs to Consul KV. If publishing is not enabled, the second parameter
+// returns false.
+func (t *Task) PublishOutputs() (PublishOutputs, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.publish == nil {
+		return PublishOutputs{}, false
+	}
+	return *t.publish, true
+}
+
+// AlertSilence returns a copy of the configuration to silence alerts in the
+// Alertmanager before the task applies changes. If silencing is not enabled,
+// the second parameter returns false.
+func (t *Task) AlertSilence() (AlertSilence, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.silence == nil {
+		return AlertSilence{}, false
+	}
+	return *t.silence, true
+}
+
+// Handlers returns the user-defined post-apply handlers of the task
+func (t *Task) Handlers() config.HandlerConfigs {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.handlers
+}
+
 // DeprecatedTFVersion returns the Terraform version to use when using the Terraform Cloud
 // driver. Enterprise.
 // Deprecated, use the Terraform Version from TFCWorkspace() instead.
@@ -329,10 +736,17 @@ func (t *Task) configureRootModuleInput(input *tftmpl.RootModuleInputData) error
 	defer t.mu.RUnlock()
 
 	input.Task = tftmpl.Task{
-		Description: t.description,
-		Name:        t.name,
-		Module:      t.module,
-		Version:     t.version,
+		Description:     t.description,
+		Name:            t.name,
+		Module:          t.module,
+		Version:         t.version,
+		ServicesChanged: t.servicesDiff,
+	}
+	if t.providerDCs {
+		input.Task.ProviderAliases = make([]string, len(t.providers))
+		for i, p := range t.providers {
+			input.Task.ProviderAliases[i] = p.ID()
+		}
 	}
 
 	var templates []tftmpl.Template
@@ -371,17 +785,31 @@ func (t *Task) configureRootModuleInput(input *tftmpl.RootModuleInputData) error
 			Regexp:     *v.Regexp,
 			Datacenter: *v.Datacenter,
 			Namespace:  *v.Namespace,
+			Kind:       config.StringVal(v.Kind),
 			NodeMeta:   v.NodeMeta,
 			RenderVar:  *v.UseAsModuleInput,
+
+			CountInstances: v.HasInstanceThresholds(),
 		}
 	case *config.ServicesConditionConfig:
-		if v.Regexp != nil {
+		if query := config.StringVal(v.Query); query != "" {
+			condition = &tftmpl.ServicesQueryTemplate{
+				Query:      query,
+				Datacenter: *v.Datacenter,
+				RenderVar:  *v.UseAsModuleInput,
+
+				IncludeExtendedMetadata: config.BoolVal(v.IncludeExtendedMetadata),
+			}
+		} else if regexp := v.CombinedRegexp(); regexp != nil {
 			condition = &tftmpl.ServicesRegexTemplate{
-				Regexp:     *v.Regexp,
+				Regexp:     *regexp,
 				Datacenter: *v.Datacenter,
 				Namespace:  *v.Namespace,
 				Filter:     *v.Filter,
 				RenderVar:  *v.UseAsModuleInput,
+				Bootstrap:  config.BoolVal(v.Bootstrap),
+
+				IncludeExtendedMetadata: config.BoolVal(v.IncludeExtendedMetadata),
 			}
 		} else {
 			condition = &tftmpl.ServicesTemplate{
@@ -390,16 +818,40 @@ func (t *Task) configureRootModuleInput(input *tftmpl.RootModuleInputData) error
 				Namespace:  *v.Namespace,
 				Filter:     *v.Filter,
 				RenderVar:  *v.UseAsModuleInput,
+
+				IncludeExtendedMetadata: config.BoolVal(v.IncludeExtendedMetadata),
 			}
 		}
 	case *config.ConsulKVConditionConfig:
 		condition = &tftmpl.ConsulKVTemplate{
 			Path:       *v.Path,
+			Paths:      v.Paths,
 			Datacenter: *v.Datacenter,
 			Recurse:    *v.Recurse,
 			Namespace:  *v.Namespace,
 			RenderVar:  *v.UseAsModuleInput,
 		}
+	case *config.DNSConditionConfig:
+		condition = &tftmpl.DNSTemplate{
+			Name:       *v.Name,
+			RecordType: *v.RecordType,
+			Interval:   *v.Interval,
+			RenderVar:  *v.UseAsModuleInput,
+		}
+	case *config.FileConditionConfig:
+		condition = &tftmpl.FileTemplate{
+			Paths:          v.Paths,
+			Interval:       *v.Interval,
+			IncludeContent: *v.IncludeContent,
+			RenderVar:      *v.UseAsModuleInput,
+		}
+	case *config.PluginConditionConfig:
+		condition = &tftmpl.PluginTemplate{
+			Command:   *v.Command,
+			Args:      v.Args,
+			Interval:  *v.Interval,
+			RenderVar: *v.UseAsModuleInput,
+		}
 	default:
 		// no-op: condition block currently not required since services.list
 		// can be used alternatively
@@ -416,14 +868,26 @@ func (t *Task) configureRootModuleInput(input *tftmpl.RootModuleInputData) error
 	for ix, moduleInput := range t.moduleInputs {
 		switch v := moduleInput.(type) {
 		case *config.ServicesModuleInputConfig:
-			if v.Regexp != nil {
+			if query := config.StringVal(v.Query); query != "" {
+				moduleInputs[ix] = &tftmpl.ServicesQueryTemplate{
+					Query:      query,
+					Datacenter: *v.Datacenter,
+					// always render var for module_input config
+					RenderVar: true,
+
+					IncludeExtendedMetadata: config.BoolVal(v.IncludeExtendedMetadata),
+				}
+			} else if regexp := v.CombinedRegexp(); regexp != nil {
 				moduleInputs[ix] = &tftmpl.ServicesRegexTemplate{
-					Regexp:     *v.Regexp,
+					Regexp:     *regexp,
 					Datacenter: *v.Datacenter,
 					Namespace:  *v.Namespace,
 					Filter:     *v.Filter,
 					// always render var for module_input config
 					RenderVar: true,
+					Bootstrap: config.BoolVal(v.Bootstrap),
+
+					IncludeExtendedMetadata: config.BoolVal(v.IncludeExtendedMetadata),
 				}
 			} else {
 				moduleInputs[ix] = &tftmpl.ServicesTemplate{
@@ -433,17 +897,28 @@ func (t *Task) configureRootModuleInput(input *tftmpl.RootModuleInputData) error
 					Filter:     *v.Filter,
 					// always render var for module_input config
 					RenderVar: true,
+
+					IncludeExtendedMetadata: config.BoolVal(v.IncludeExtendedMetadata),
 				}
 			}
 		case *config.ConsulKVModuleInputConfig:
 			moduleInputs[ix] = &tftmpl.ConsulKVTemplate{
 				Path:       *v.Path,
+				Paths:      v.Paths,
 				Datacenter: *v.Datacenter,
 				Recurse:    *v.Recurse,
 				Namespace:  *v.Namespace,
 				// always render var for module_input config
 				RenderVar: true,
 			}
+		case *config.FileModuleInputConfig:
+			moduleInputs[ix] = &tftmpl.FileTemplate{
+				Paths:          v.Paths,
+				Interval:       *v.Interval,
+				IncludeContent: *v.IncludeContent,
+				// always render var for module_input config
+				RenderVar: true,
+			}
 		default:
 			return fmt.Errorf("task %q has unsupported type of module_input "+
 				" block configuration %T", t.name, v)
@@ -470,6 +945,23 @@ func (t *Task) configureRootModuleInput(input *tftmpl.RootModuleInputData) error
 	for k, v := range t.variables {
 		input.Variables[k] = v
 	}
+	input.SensitiveVariables = append([]string{}, t.sensitive...)
+
+	input.ComputedInputs = make([]string, 0, len(t.computed))
+	for name := range t.computed {
+		input.ComputedInputs = append(input.ComputedInputs, name)
+	}
+
+	if len(t.environments) > 0 {
+		input.Environments = make(map[string]hcltmpl.Variables, len(t.environments))
+	}
+	for _, env := range t.environments {
+		vars, err := tftmpl.ParseModuleVariablesFromMap(env.Variables)
+		if err != nil {
+			return fmt.Errorf("environment %q: %s", env.Name, err)
+		}
+		input.Environments[env.Name] = vars
+	}
 
 	return nil
 }
@@ -479,16 +971,37 @@ type clientConfig struct {
 	clientType string
 	log        bool
 	taskName   string
+	workspace  string // defaults to the task name
 	persistLog bool
 	path       string
 	workingDir string
+	sandbox    *client.SandboxConfig
+	initLock   sync.Locker // optional, held while initializing the workspace
+
+	// environments of the task, each with its own client
+	environments []TaskEnvironment
+	backendType  string
+
+	// dataDir, varFiles, and backendConfig configure the client of an
+	// environment
+	dataDir       string
+	varFiles      []string
+	backendConfig []string
 }
 
 // newClient initializes a specific type of client given a task
 func newClient(conf *clientConfig) (client.Client, error) {
+	if len(conf.environments) > 0 {
+		return newEnvironmentsClient(conf)
+	}
+
 	var err error
 	var c client.Client
 	taskName := conf.taskName
+	workspace := conf.workspace
+	if workspace == "" {
+		workspace = taskName
+	}
 
 	tnlog := logging.Global().Named(logSystemName).With(taskNameLogKey, taskName)
 	switch conf.clientType {
@@ -497,7 +1010,7 @@ func newClient(conf *clientConfig) (client.Client, error) {
 		c, err = client.NewPrinter(&client.PrinterConfig{
 			ExecPath:   conf.path,
 			WorkingDir: conf.workingDir,
-			Workspace:  taskName,
+			Workspace:  workspace,
 			Writer:     os.Stdout,
 		})
 	case testClient:
@@ -510,7 +1023,13 @@ func newClient(conf *clientConfig) (client.Client, error) {
 			PersistLog: conf.persistLog,
 			ExecPath:   conf.path,
 			WorkingDir: conf.workingDir,
-			Workspace:  taskName,
+			Workspace:  workspace,
+			Sandbox:    conf.sandbox,
+			InitLock:   conf.initLock,
+
+			DataDir:       conf.dataDir,
+			VarFiles:      conf.varFiles,
+			BackendConfig: conf.backendConfig,
 		})
 	}
 
@@ -6,6 +6,7 @@ package driver
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/consul-terraform-sync/client"
 	"github.com/hashicorp/consul-terraform-sync/config"
@@ -62,6 +63,76 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestNewClient_Workspace(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults to task name", func(t *testing.T) {
+		c, err := newClient(&clientConfig{
+			clientType: developmentClient,
+			taskName:   "web",
+		})
+		require.NoError(t, err)
+		assert.Contains(t, c.GoString(), "WorkSpace:web,")
+	})
+
+	t.Run("configured workspace", func(t *testing.T) {
+		c, err := newClient(&clientConfig{
+			clientType: developmentClient,
+			taskName:   "web",
+			workspace:  "cts-prod-web",
+		})
+		require.NoError(t, err)
+		assert.Contains(t, c.GoString(), "WorkSpace:cts-prod-web,")
+	})
+}
+
+func TestNewClient_Environments(t *testing.T) {
+	t.Parallel()
+
+	envs := []TaskEnvironment{
+		{Name: "staging"},
+		{Name: "prod", Workspace: "web-production", BackendKey: "cts/prod"},
+	}
+
+	t.Run("environment clients", func(t *testing.T) {
+		c, err := newClient(&clientConfig{
+			clientType:   developmentClient,
+			taskName:     "web",
+			environments: envs,
+			backendType:  "consul",
+		})
+		require.NoError(t, err)
+		assert.IsType(t, &client.Environments{}, c)
+		assert.Contains(t, c.GoString(), "WorkSpace:web-staging,")
+		assert.Contains(t, c.GoString(), "WorkSpace:web-production,")
+	})
+
+	t.Run("unsupported backend key", func(t *testing.T) {
+		_, err := newClient(&clientConfig{
+			clientType:   developmentClient,
+			taskName:     "web",
+			environments: envs,
+			backendType:  "remote",
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestTask_Environments(t *testing.T) {
+	var task Task
+	assert.Nil(t, task.Environments())
+
+	task.environments = []TaskEnvironment{
+		{Name: "prod", Variables: map[string]string{"size": `"large"`}},
+	}
+	envs := task.Environments()
+	assert.Equal(t, task.environments, envs)
+
+	// returns a copy
+	envs[0].Variables["size"] = `"small"`
+	assert.Equal(t, `"large"`, task.environments[0].Variables["size"])
+}
+
 func TestTask_BufferPeriod(t *testing.T) {
 	t.Parallel()
 
@@ -346,6 +417,13 @@ func TestTask_Variables(t *testing.T) {
 	assert.Equal(t, task.variables, variables)
 }
 
+func TestTask_SensitiveVariables(t *testing.T) {
+	var task Task
+	task.sensitive = []string{"password"}
+	sensitive := task.SensitiveVariables()
+	assert.Equal(t, task.sensitive, sensitive)
+}
+
 func TestTask_Version(t *testing.T) {
 	var task Task
 	task.version = "some version"
@@ -360,6 +438,41 @@ func TestTask_WorkingDir(t *testing.T) {
 	assert.Equal(t, task.workingDir, workingDir)
 }
 
+func TestTask_IsRenderOnly(t *testing.T) {
+	var task Task
+	task.renderOnly = true
+	assert.True(t, task.IsRenderOnly())
+}
+
+func TestTask_RenderedFiles(t *testing.T) {
+	var task Task
+	task.workingDir = "working-dir"
+	files := task.RenderedFiles()
+	assert.Equal(t, []string{"working-dir/terraform.tfvars"}, files)
+}
+
+func TestTask_SavesPlan(t *testing.T) {
+	var task Task
+	assert.False(t, task.SavesPlan())
+	task.savePlan = true
+	assert.True(t, task.SavesPlan())
+}
+
+func TestTask_PlanFiles(t *testing.T) {
+	var task Task
+	task.workingDir = "working-dir"
+	planFile, jsonFile := task.PlanFiles()
+	assert.Equal(t, "working-dir/tfplan", planFile)
+	assert.Equal(t, "working-dir/tfplan.json", jsonFile)
+}
+
+func TestTask_TFVersion(t *testing.T) {
+	var task Task
+	assert.Equal(t, "", task.TFVersion())
+	task.tfVersion = "1.0.0"
+	assert.Equal(t, "1.0.0", task.TFVersion())
+}
+
 func TestTask_DeprecatedTFVersion(t *testing.T) {
 	var task Task
 	task.deprecatedTFVersion = "1.0.0"
@@ -444,10 +557,11 @@ func TestTask_configureRootModuleInput(t *testing.T) {
 			task: &Task{
 				condition: &config.ServicesConditionConfig{
 					ServicesMonitorConfig: config.ServicesMonitorConfig{
-						Names:      []string{"api"},
-						Datacenter: config.String("dc1"),
-						Namespace:  config.String("ns1"),
-						Filter:     config.String("filter"),
+						Names:                   []string{"api"},
+						Datacenter:              config.String("dc1"),
+						Namespace:               config.String("ns1"),
+						Filter:                  config.String("filter"),
+						IncludeExtendedMetadata: config.Bool(true),
 					},
 					UseAsModuleInput: config.Bool(false),
 				},
@@ -459,6 +573,73 @@ func TestTask_configureRootModuleInput(t *testing.T) {
 					Namespace:  "ns1",
 					Filter:     "filter",
 					RenderVar:  false,
+
+					IncludeExtendedMetadata: true,
+				},
+			},
+		},
+		{
+			name: "templates: services cond regex and names",
+			task: &Task{
+				condition: &config.ServicesConditionConfig{
+					ServicesMonitorConfig: config.ServicesMonitorConfig{
+						Regexp:     config.String("^web.*"),
+						Names:      []string{"api"},
+						Datacenter: config.String("dc1"),
+						Namespace:  config.String("ns1"),
+						Filter:     config.String("filter"),
+					},
+					UseAsModuleInput: config.Bool(true),
+				},
+			},
+			expectedTemplates: []tftmpl.Template{
+				&tftmpl.ServicesRegexTemplate{
+					Regexp:     "(?:^web.*)|^(?:api)$",
+					Datacenter: "dc1",
+					Namespace:  "ns1",
+					Filter:     "filter",
+					RenderVar:  true,
+				},
+			},
+		},
+		{
+			name: "templates: services cond query",
+			task: &Task{
+				condition: &config.ServicesConditionConfig{
+					ServicesMonitorConfig: config.ServicesMonitorConfig{
+						Query:      config.String("nearest-api"),
+						Datacenter: config.String("dc1"),
+					},
+					UseAsModuleInput: config.Bool(true),
+				},
+			},
+			expectedTemplates: []tftmpl.Template{
+				&tftmpl.ServicesQueryTemplate{
+					Query:      "nearest-api",
+					Datacenter: "dc1",
+					RenderVar:  true,
+				},
+			},
+		},
+		{
+			name: "templates: services cond regexp bootstrap",
+			task: &Task{
+				condition: &config.ServicesConditionConfig{
+					ServicesMonitorConfig: config.ServicesMonitorConfig{
+						Regexp:     config.String("^web.*"),
+						Datacenter: config.String(""),
+						Namespace:  config.String(""),
+						Filter:     config.String(""),
+						Bootstrap:  config.Bool(true),
+					},
+					UseAsModuleInput: config.Bool(false),
+				},
+			},
+			expectedTemplates: []tftmpl.Template{
+				&tftmpl.ServicesRegexTemplate{
+					Regexp:    "^web.*",
+					RenderVar: false,
+					Bootstrap: true,
 				},
 			},
 		},
@@ -470,6 +651,7 @@ func TestTask_configureRootModuleInput(t *testing.T) {
 						Regexp:           config.String("^web.*"),
 						Datacenter:       config.String("dc1"),
 						Namespace:        config.String("ns1"),
+						Kind:             config.String("typical"),
 						NodeMeta:         map[string]string{"test": "test"},
 						UseAsModuleInput: config.Bool(true),
 					},
@@ -480,6 +662,7 @@ func TestTask_configureRootModuleInput(t *testing.T) {
 					Regexp:     "^web.*",
 					Datacenter: "dc1",
 					Namespace:  "ns1",
+					Kind:       "typical",
 					NodeMeta:   map[string]string{"test": "test"},
 					RenderVar:  true,
 				},
@@ -508,6 +691,91 @@ func TestTask_configureRootModuleInput(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "templates: dns condition",
+			task: &Task{
+				condition: &config.DNSConditionConfig{
+					DNSMonitorConfig: config.DNSMonitorConfig{
+						Name:       config.String("_http._tcp.example.com"),
+						RecordType: config.String("SRV"),
+						Interval:   config.TimeDuration(10 * time.Second),
+					},
+					UseAsModuleInput: config.Bool(true),
+				},
+			},
+			expectedTemplates: []tftmpl.Template{
+				&tftmpl.DNSTemplate{
+					Name:       "_http._tcp.example.com",
+					RecordType: "SRV",
+					Interval:   10 * time.Second,
+					RenderVar:  true,
+				},
+			},
+		},
+		{
+			name: "templates: file condition",
+			task: &Task{
+				condition: &config.FileConditionConfig{
+					FileMonitorConfig: config.FileMonitorConfig{
+						Paths:          []string{"/etc/cts/allowlist.txt"},
+						Interval:       config.TimeDuration(5 * time.Second),
+						IncludeContent: config.Bool(true),
+					},
+					UseAsModuleInput: config.Bool(true),
+				},
+			},
+			expectedTemplates: []tftmpl.Template{
+				&tftmpl.FileTemplate{
+					Paths:          []string{"/etc/cts/allowlist.txt"},
+					Interval:       5 * time.Second,
+					IncludeContent: true,
+					RenderVar:      true,
+				},
+			},
+		},
+		{
+			name: "templates: plugin condition",
+			task: &Task{
+				condition: &config.PluginConditionConfig{
+					PluginMonitorConfig: config.PluginMonitorConfig{
+						Command:  config.String("/opt/cts/plugins/s3-bucket"),
+						Args:     []string{"-bucket", "releases"},
+						Interval: config.TimeDuration(time.Minute),
+					},
+					UseAsModuleInput: config.Bool(true),
+				},
+			},
+			expectedTemplates: []tftmpl.Template{
+				&tftmpl.PluginTemplate{
+					Command:   "/opt/cts/plugins/s3-bucket",
+					Args:      []string{"-bucket", "releases"},
+					Interval:  time.Minute,
+					RenderVar: true,
+				},
+			},
+		},
+		{
+			name: "templates: file module_input",
+			task: &Task{
+				moduleInputs: config.ModuleInputConfigs{
+					&config.FileModuleInputConfig{
+						FileMonitorConfig: config.FileMonitorConfig{
+							Paths:          []string{"/etc/cts/rules"},
+							Interval:       config.TimeDuration(10 * time.Second),
+							IncludeContent: config.Bool(false),
+						},
+					},
+				},
+			},
+			expectedTemplates: []tftmpl.Template{
+				&tftmpl.FileTemplate{
+					Paths:          []string{"/etc/cts/rules"},
+					Interval:       10 * time.Second,
+					IncludeContent: false,
+					RenderVar:      true,
+				},
+			},
+		},
 		{
 			name: "templates: services module_input regex",
 			task: &Task{
@@ -6,13 +6,18 @@ package driver
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/consul-terraform-sync/client"
 	"github.com/hashicorp/consul-terraform-sync/config"
@@ -22,6 +27,7 @@ import (
 	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl"
 	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl/notifier"
 	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl/tmplfunc"
+	consulapi "github.com/hashicorp/consul/api"
 	"github.com/hashicorp/hcat"
 	"github.com/hashicorp/hcat/dep"
 	"github.com/pkg/errors"
@@ -46,6 +52,19 @@ var (
 
 	errUnsupportedTerraformVersion = fmt.Errorf("unsupported Terraform version: %s", errSuggestion)
 	errIncompatibleTerraformBinary = fmt.Errorf("incompatible Terraform binary: %s", errSuggestion)
+
+	// renderHashFilenames are the files of the task's working directory that
+	// determine whether the task changed since its last successful run
+	renderHashFilenames = []string{
+		tftmpl.RootFilename,
+		tftmpl.VarsFilename,
+		tftmpl.ModuleVarsFilename,
+		tftmpl.VarsTFVarsFileName,
+		tftmpl.SecretsTFVarsFilename,
+		tftmpl.ProvidersTFVarsFilename,
+		tftmpl.TFVarsFilename,
+		tftmpl.ComputedTFVarsFilename,
+	}
 )
 
 // Terraform is a CTS driver that uses the Terraform CLI to interface with
@@ -62,9 +81,14 @@ type Terraform struct {
 	watcher    templates.Watcher
 	fileReader func(string) ([]byte, error)
 
+	// extraTemplates are the additional templates of the task that are
+	// rendered alongside the template. They do not trigger the task.
+	extraTemplates []templates.Template
+
 	client    client.Client
 	logClient bool
 	postApply handler.Handler
+	silencer  Silencer
 
 	inited bool
 
@@ -82,8 +106,25 @@ type TerraformConfig struct {
 	Backend           map[string]interface{}
 	RequiredProviders map[string]interface{}
 	Watcher           templates.Watcher
+
+	// Workspace is the name of the task's Terraform workspace. Defaults to
+	// the task name when empty.
+	Workspace string
+
 	// empty/unknown string will default to TerraformCLI client
 	ClientType string
+
+	// Sandbox configures Terraform to execute with reduced privileges.
+	// Terraform is executed with the privileges of CTS if nil.
+	Sandbox *client.SandboxConfig
+
+	// PluginCache configures the provider plugin cache for the task.
+	// Providers are downloaded into the task's working directory if nil.
+	PluginCache *PluginCache
+
+	// Silencer creates the silences of the task in the Alertmanager. Alerts
+	// are not silenced if nil.
+	Silencer Silencer
 }
 
 // NewTerraform configures and initializes a new Terraform driver for a task.
@@ -100,26 +141,68 @@ func NewTerraform(config *TerraformConfig) (*Terraform, error) {
 		}
 	}
 
+	// Tasks that share a plugin cache initialize one at a time, and a sandbox
+	// that confines writes to the working directory can write to the cache
+	sandbox := config.Sandbox
+	var cacheEnv map[string]string
+	var initLock sync.Locker
+	if cache := config.PluginCache; cache != nil {
+		var err error
+		cacheEnv, err = initPluginCache(cache)
+		if err != nil {
+			logger.Error("error creating plugin cache directory", "dir",
+				cache.Dir, "error", err)
+			return nil, err
+		}
+		if cache.Shared {
+			initLock = pluginCacheLock(cache.Dir)
+		}
+		if sandbox != nil && sandbox.ConfineWorkingDir {
+			sb := *sandbox
+			sb.WritablePaths = make([]string, 0, len(sandbox.WritablePaths)+1)
+			sb.WritablePaths = append(sb.WritablePaths, sandbox.WritablePaths...)
+			sb.WritablePaths = append(sb.WritablePaths, cache.Dir)
+			sandbox = &sb
+		}
+	}
+
 	tfClient, err := newClient(&clientConfig{
 		clientType: config.ClientType,
 		log:        config.Log,
 		taskName:   taskName,
+		workspace:  config.Workspace,
 		persistLog: config.PersistLog,
 		path:       config.Path,
 		workingDir: wd,
+		sandbox:    sandbox,
+		initLock:   initLock,
+
+		environments: task.Environments(),
+		backendType:  backendType(config.Backend),
 	})
 	if err != nil {
 		logger.Error("init client type error", "client_type", config.ClientType, "error", err)
 		return nil, err
 	}
 
-	if taskEnv := task.Env(); len(taskEnv) > 0 {
+	// The environment of the task takes precedence over the plugin cache
+	taskEnv := task.Env()
+	for k, v := range cacheEnv {
+		if _, ok := taskEnv[k]; !ok {
+			taskEnv[k] = v
+		}
+	}
+	if len(taskEnv) > 0 {
 		// Terraform init requires discovering git in the PATH env.
 		//
 		// The terraform-exec package disables inheriting from the os environment
 		// when using tfexec.SetEnv(). So for CTS purposes, we'll force inheritance
 		// to allow Terraform commands to use the os environment as necessary.
-		env := envMap(os.Environ())
+		// In a sandbox, the client only passes the allowlisted os environment.
+		env := make(map[string]string, len(taskEnv))
+		if config.Sandbox == nil {
+			env = envMap(os.Environ())
+		}
 		for k, v := range taskEnv {
 			env[k] = v
 		}
@@ -131,7 +214,7 @@ func NewTerraform(config *TerraformConfig) (*Terraform, error) {
 		}
 	}
 
-	h, err := getTerraformHandlers(taskName, task.Providers())
+	h, err := getTerraformHandlers(task)
 	if err != nil {
 		return nil, err
 	}
@@ -143,6 +226,7 @@ func NewTerraform(config *TerraformConfig) (*Terraform, error) {
 		client:            tfClient,
 		logClient:         config.Log,
 		postApply:         h,
+		silencer:          config.Silencer,
 		resolver:          hcat.NewResolver(),
 		watcher:           config.Watcher,
 		fileReader:        ioutil.ReadFile,
@@ -167,6 +251,52 @@ func (tf *Terraform) OnceDone() bool {
 	return tf.onceNotifier.OnceDone()
 }
 
+// DependencyTriggers returns the number of times that each monitored
+// dependency triggered the task, by the name of the dependency. The counts
+// are reset when the task's template is re-initialized.
+func (tf *Terraform) DependencyTriggers() map[string]int {
+	if tf.onceNotifier == nil {
+		return map[string]int{}
+	}
+	return tf.onceNotifier.DependencyTriggers()
+}
+
+// Readiness returns the progress of resolving the template of the task for
+// its first run, i.e. which of the dependencies of the template have been
+// fetched. The task is ready once the template has been rendered with the data
+// of all of the dependencies.
+func (tf *Terraform) Readiness() Readiness {
+	if tf.onceNotifier == nil {
+		return newReadiness(tf.task.Name(), false, nil)
+	}
+	return newReadiness(tf.task.Name(), tf.onceNotifier.OnceDone(),
+		tf.onceNotifier.Dependencies())
+}
+
+// RenderChanged returns whether the generated root module files or the
+// rendered variables of the task changed since the last successful run of the
+// task. Returns true if the task has not run successfully.
+func (tf *Terraform) RenderChanged() (bool, error) {
+	tf.mu.RLock()
+	defer tf.mu.RUnlock()
+
+	current, err := tf.renderHash()
+	if err != nil {
+		return false, err
+	}
+
+	path := filepath.Join(tf.task.WorkingDir(), tftmpl.RenderHashFilename)
+	previous, err := tf.fileReader(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	return strings.TrimSpace(string(previous)) != current, nil
+}
+
 // InitTask initializes the task by creating the Terraform root module and related
 // files to execute on.
 func (tf *Terraform) InitTask(ctx context.Context) error {
@@ -240,6 +370,89 @@ func (tf *Terraform) RenderTemplate(_ context.Context) (bool, error) {
 	return re.Complete && !re.NoChange, err
 }
 
+// RenderTask renders the template of the task with the latest data of its
+// dependencies, even if the data did not change since the template was last
+// rendered. The rendered files are written to the working directory of the
+// task without running Terraform. Files are not rendered until the data of
+// all of the dependencies is fetched.
+func (tf *Terraform) RenderTask(_ context.Context) (RenderResult, error) {
+	tf.mu.Lock()
+	defer tf.mu.Unlock()
+
+	return tf.renderTask()
+}
+
+// ReinitTask re-initializes the task without restarting, e.g. after the
+// files of a local module change on disk. The root module and the template
+// of the task are regenerated, the Terraform workspace is re-initialized and
+// validated, and the template is rendered with the latest data of its
+// dependencies.
+func (tf *Terraform) ReinitTask(ctx context.Context) (RenderResult, error) {
+	tf.mu.Lock()
+	defer tf.mu.Unlock()
+
+	tf.logger.Info("re-initializing task", taskNameLogKey, tf.task.Name())
+	if err := tf.initTask(ctx); err != nil {
+		return RenderResult{}, err
+	}
+	return tf.renderTask()
+}
+
+// renderTask renders the template of the task with the latest data of its
+// dependencies. Must be called with the lock held.
+func (tf *Terraform) renderTask() (RenderResult, error) {
+	taskName := tf.task.Name()
+	tnlog := tf.logger.With(taskNameLogKey, taskName)
+
+	if tf.template == nil {
+		return RenderResult{}, fmt.Errorf("template for task %s is not "+
+			"initialized", taskName)
+	}
+
+	contents, err := tf.template.Execute(tf.watcher.Recaller(tf.template))
+	if err != nil {
+		tnlog.Error("error executing template for task", "error", err)
+		return RenderResult{}, fmt.Errorf("error executing template for task "+
+			"%s: %s", taskName, err)
+	}
+	if !tf.watcher.Complete(tf.template) {
+		tnlog.Debug("template data for task is not yet fetched, skipping render")
+		return RenderResult{}, nil
+	}
+
+	complete, err := tf.renderExtraTemplates()
+	if err != nil {
+		return RenderResult{}, err
+	}
+	if !complete {
+		tnlog.Debug("extra templates data for task is not yet fetched, " +
+			"skipping render")
+		return RenderResult{}, nil
+	}
+
+	rendered, err := tf.template.Render(contents)
+	if err != nil {
+		tnlog.Error("rendering template for task", "error", err)
+		return RenderResult{}, err
+	}
+
+	if exprs := tf.task.ComputedInputs(); len(exprs) > 0 {
+		err := tftmpl.RenderComputedInputs(tf.task.WorkingDir(), exprs, filePerms)
+		if err != nil {
+			tnlog.Error("rendering computed inputs for task", "error", err)
+			return RenderResult{}, err
+		}
+	}
+
+	tnlog.Info("template for task rendered without running Terraform",
+		"changed", rendered.DidRender)
+	return RenderResult{
+		Complete: true,
+		Changed:  rendered.DidRender,
+		Files:    tf.task.RenderedFiles(),
+	}, nil
+}
+
 // InspectTask inspects for any differences pertaining to the task between
 // the state of Consul and network infrastructure using the Terraform plan command
 func (tf *Terraform) InspectTask(ctx context.Context) (InspectPlan, error) {
@@ -270,6 +483,7 @@ func (tf *Terraform) ApplyTask(ctx context.Context) error {
 		return nil
 	}
 
+	tf.task.setLastRun(nil)
 	return tf.applyTask(ctx)
 }
 
@@ -280,6 +494,20 @@ type InspectPlan struct {
 	URL            string `json:"url,omitempty"`
 }
 
+// RenderResult is the result of rendering the template of a task without
+// running Terraform
+type RenderResult struct {
+	// Complete is whether the data of all of the dependencies of the template
+	// is fetched. The files are only rendered once complete.
+	Complete bool `json:"complete"`
+
+	// Changed is whether the contents of the rendered files changed
+	Changed bool `json:"changed"`
+
+	// Files are the paths of the rendered files
+	Files []string `json:"files,omitempty"`
+}
+
 // UpdateTask updates the task on the driver. Makes any calls to re-init
 // depending on the fields updated. If update task is requested with the inspect
 // run option, then dry run the updates by returning the inspected plan for the
@@ -297,6 +525,10 @@ func (tf *Terraform) UpdateTask(ctx context.Context, patch PatchTask) (InspectPl
 	tf.mu.Lock()
 	defer tf.mu.Unlock()
 
+	if patch.RunOption == RunOptionNow {
+		tf.task.setLastRun(nil)
+	}
+
 	originalEnabled := tf.task.IsEnabled()
 
 	// for inspect, dry-run the task with the planned change and then make sure
@@ -420,13 +652,25 @@ func (tf *Terraform) initTask(ctx context.Context) error {
 		return err
 	}
 
+	if err := tf.initExtraTemplates(); err != nil {
+		return err
+	}
+
 	// initTask() can be called more than once. It's very likely initializing a
 	// task will require re-initializing terraform. Reset to false so terraform
 	// will reinit
 	tf.inited = false
 
-	// initialize workspace
+	// render-only tasks never run Terraform, so the workspace does not need to
+	// be initialized
 	taskName := tf.task.Name()
+	if tf.task.IsRenderOnly() {
+		tf.logger.Trace("task is render-only. skip initializing workspace",
+			taskNameLogKey, taskName)
+		return nil
+	}
+
+	// initialize workspace
 	if err := tf.init(ctx); err != nil {
 		tf.logger.Error("error initializing workspace for task", taskNameLogKey, taskName)
 		return err
@@ -440,9 +684,13 @@ func (tf *Terraform) initTask(ctx context.Context) error {
 	return nil
 }
 
-// deregisterTemplate attempts to deregister the hashicat template
+// deregisterTemplate attempts to deregister the hashicat template and the
+// extra templates of the task
 func (tf *Terraform) deregisterTemplate() {
 	tf.watcher.Deregister(tf.template)
+	for _, tmpl := range tf.extraTemplates {
+		tf.watcher.Deregister(tmpl)
+	}
 }
 
 // renderTemplate attempts to render the hashicat template
@@ -467,16 +715,47 @@ func (tf *Terraform) renderTemplate() (hcat.ResolveEvent, error) {
 		return result, nil
 	}
 
+	// Dependencies are refetched after the connection to Consul is restored,
+	// which notifies the template even when the data is identical. Skip the
+	// run when the contents are identical to what was last applied.
+	if result.Complete && !result.NoChange && tf.OnceDone() &&
+		tf.onceNotifier.Unchanged(result.Contents) {
+		tnlog.Debug("template data unchanged since last run for task, skipping")
+		return hcat.ResolveEvent{Complete: true, NoChange: true}, nil
+	}
+
 	if result.Complete && !result.NoChange {
 		tnlog.Debug("change detected for task")
 
+		// Extra templates are rendered before the template so that the files
+		// are up to date when the task runs. Wait for their data if incomplete.
+		complete, err := tf.renderExtraTemplates()
+		if err != nil {
+			return hcat.ResolveEvent{}, err
+		}
+		if !complete {
+			tnlog.Trace("waiting for extra templates data for task")
+			return hcat.ResolveEvent{Complete: false}, nil
+		}
+
 		rendered, err := tf.template.Render(result.Contents)
 		if err != nil {
 			tnlog.Error("rendering template for task", "error", err)
 
 			return hcat.ResolveEvent{}, err
 		}
-		tnlog.Trace("template for task rendered", "rendered_template", rendered)
+		tnlog.Trace("template for task rendered", "did_render", rendered.DidRender,
+			"size", len(result.Contents))
+
+		// Computed inputs are evaluated from the rendered variables
+		if exprs := tf.task.ComputedInputs(); len(exprs) > 0 {
+			err := tftmpl.RenderComputedInputs(tf.task.WorkingDir(), exprs, filePerms)
+			if err != nil {
+				tnlog.Error("rendering computed inputs for task", "error", err)
+				return hcat.ResolveEvent{}, err
+			}
+			tnlog.Trace("computed inputs for task rendered")
+		}
 		tf.onceNotifier.SetOnceDone()
 	}
 
@@ -488,17 +767,27 @@ func (tf *Terraform) renderTemplate() (hcat.ResolveEvent, error) {
 func (tf *Terraform) inspectTask(ctx context.Context, returnPlan bool) (InspectPlan, error) {
 	taskName := tf.task.Name()
 
+	if tf.task.IsRenderOnly() {
+		tf.logger.Trace("task is render-only. skip inspecting", taskNameLogKey, taskName)
+		return InspectPlan{
+			Plan: "Task is render-only, inspection was skipped.",
+		}, nil
+	}
+
+	if err := tf.writeServicesChanged(); err != nil {
+		return InspectPlan{}, err
+	}
+
+	resetTargets, err := tf.setTargets()
+	if err != nil {
+		return InspectPlan{}, err
+	}
+	defer resetTargets()
+
 	var buf bytes.Buffer
 	if returnPlan {
 		tf.client.SetStdout(&buf)
-
-		var tfLogger *log.Logger
-		if tf.logClient {
-			tfLogger = log.New(log.Writer(), "", log.Flags())
-		} else {
-			tfLogger = log.New(ioutil.Discard, "", 0)
-		}
-		defer tf.client.SetStdout(tfLogger.Writer())
+		defer tf.client.SetStdout(tf.clientStdout())
 	}
 
 	tf.logger.Trace("plan", taskNameLogKey, taskName)
@@ -518,9 +807,74 @@ func (tf *Terraform) inspectTask(ctx context.Context, returnPlan bool) (InspectP
 func (tf *Terraform) applyTask(ctx context.Context) error {
 	taskName := tf.task.Name()
 
-	tf.logger.Trace("apply", taskNameLogKey, taskName)
-	if err := tf.client.Apply(ctx); err != nil {
-		return errors.Wrap(err, fmt.Sprintf("error tf-apply for '%s'", taskName))
+	if tf.task.IsRenderOnly() {
+		tf.logger.Trace("task is render-only. skip applying", taskNameLogKey,
+			taskName, "rendered_files", tf.task.RenderedFiles())
+		tf.setApplied()
+		return nil
+	}
+
+	if err := tf.writeServicesChanged(); err != nil {
+		return err
+	}
+
+	resetTargets, err := tf.setTargets()
+	if err != nil {
+		return err
+	}
+	defer resetTargets()
+
+	tf.silenceAlerts(ctx)
+
+	// The machine-readable UI of the plan and the apply is streamed to track
+	// the progress of the in-flight run. The output is captured to summarize
+	// the resource changes for clients without the machine-readable UI.
+	var out bytes.Buffer
+	progress := newProgressTracker(taskName)
+	tf.task.setProgress(progress)
+	tf.client.SetStdout(io.MultiWriter(tf.clientStdout(), &out))
+	tf.client.SetProgress(progress)
+	defer func() {
+		tf.client.SetProgress(nil)
+		tf.client.SetStdout(tf.clientStdout())
+	}()
+
+	start := time.Now()
+	if tf.task.SavesPlan() {
+		err = tf.applySavedPlan(ctx)
+	} else {
+		tf.logger.Trace("apply", taskNameLogKey, taskName)
+		if err = tf.client.Apply(ctx); err != nil {
+			err = errors.Wrap(err, fmt.Sprintf("error tf-apply for '%s'", taskName))
+		}
+	}
+	progress.finish()
+	changes := progress.changeSummary()
+	if changes == nil {
+		changes = parseChangeSummary(out.String())
+	}
+	tf.recordRun(changes, time.Since(start))
+	if err != nil {
+		return err
+	}
+
+	// The services snapshot is not saved when a postcondition fails, so that
+	// the services changed are included in the next run
+	if err := tf.verifyPostconditions(ctx); err != nil {
+		return err
+	}
+
+	if err := tf.saveServicesSnapshot(); err != nil {
+		return err
+	}
+
+	if err := tf.saveRenderHash(); err != nil {
+		return err
+	}
+	tf.setApplied()
+
+	if err := tf.publishOutputs(ctx); err != nil {
+		return err
 	}
 
 	if tf.postApply != nil {
@@ -533,6 +887,298 @@ func (tf *Terraform) applyTask(ctx context.Context) error {
 	return nil
 }
 
+// clientStdout returns the writer for the standard out of the client when
+// its output is not captured
+func (tf *Terraform) clientStdout() io.Writer {
+	if tf.logClient {
+		return log.Writer()
+	}
+	return ioutil.Discard
+}
+
+// recordRun records the metadata of the task's Terraform run. Failing to
+// resolve the module version is only logged.
+func (tf *Terraform) recordRun(changes *ChangeSummary, duration time.Duration) {
+	_, moduleVersion, err := readInstalledModule(tf.task.WorkingDir(), tf.task.Name())
+	if err != nil {
+		tf.logger.Warn("unable to read the resolved module version",
+			taskNameLogKey, tf.task.Name(), "error", err)
+	}
+
+	// The version is unset when Terraform was not installed or checked by
+	// CTS, e.g. for clients that do not run the Terraform CLI
+	var tfVersion string
+	if TerraformVersion != nil {
+		tfVersion = TerraformVersion.String()
+	}
+
+	tf.task.setLastRun(&RunMetadata{
+		Changes:          changes,
+		TerraformVersion: tfVersion,
+		ModuleVersion:    moduleVersion,
+		ApplyDuration:    duration,
+	})
+}
+
+// writeServicesChanged writes the variable file for the services_changed
+// variable if it is enabled for the task. The variable is the difference
+// between the services variable of the last successful run and the services
+// variable that is currently rendered.
+func (tf *Terraform) writeServicesChanged() error {
+	if !tf.task.ServicesChanged() {
+		return nil
+	}
+
+	wd := tf.task.WorkingDir()
+	current, err := tf.fileReader(filepath.Join(wd, tftmpl.TFVarsFilename))
+	if err != nil {
+		return err
+	}
+
+	previous, err := tf.fileReader(filepath.Join(wd, tftmpl.ServicesSnapshotFilename))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	content, err := tftmpl.NewServicesChangedTFVars(previous, current)
+	if err != nil {
+		tf.logger.Error("unable to determine changed services", taskNameLogKey,
+			tf.task.Name(), "error", err)
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(wd, tftmpl.ServicesChangedFilename),
+		content, filePerms)
+}
+
+// saveServicesSnapshot saves the rendered variables of a successful run if
+// the services_changed variable or targeted apply is enabled for the task.
+// The snapshot is compared to the rendered variables of the next run to
+// determine the services that changed. The variables are streamed to the
+// snapshot rather than copied in memory.
+func (tf *Terraform) saveServicesSnapshot() error {
+	if !tf.task.ServicesChanged() && !tf.task.TargetedApply() {
+		return nil
+	}
+
+	wd := tf.task.WorkingDir()
+	if err := templates.CopyFile(filepath.Join(wd, tftmpl.ServicesSnapshotFilename),
+		filepath.Join(wd, tftmpl.TFVarsFilename), filePerms); err != nil {
+		tf.logger.Error("unable to save snapshot of services", taskNameLogKey,
+			tf.task.Name(), "error", err)
+		return err
+	}
+	return nil
+}
+
+// setApplied records the last rendered template contents as applied, so that
+// identical data does not run the task again
+func (tf *Terraform) setApplied() {
+	if tf.onceNotifier != nil {
+		tf.onceNotifier.SetApplied()
+	}
+}
+
+// renderHash returns the hash of the generated root module files and the
+// rendered variables of the task. Files that do not exist are skipped.
+func (tf *Terraform) renderHash() (string, error) {
+	wd := tf.task.WorkingDir()
+	h := sha256.New()
+	for _, name := range renderHashFilenames {
+		content, err := tf.fileReader(filepath.Join(wd, name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return "", err
+		}
+		fmt.Fprintf(h, "%s:%d\n", name, len(content))
+		h.Write(content)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// saveRenderHash saves the hash of the generated root module files and the
+// rendered variables of a successful run. The hash is compared to the files
+// of the next run to determine whether the task changed.
+func (tf *Terraform) saveRenderHash() error {
+	hash, err := tf.renderHash()
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(tf.task.WorkingDir(), tftmpl.RenderHashFilename)
+	if err := os.WriteFile(path, []byte(hash), filePerms); err != nil {
+		tf.logger.Error("unable to save render hash", taskNameLogKey,
+			tf.task.Name(), "error", err)
+		return err
+	}
+	return nil
+}
+
+// setTargets sets the resource addresses for the client to target if
+// targeted apply is enabled for the task. Returns a function to reset the
+// client to target all resources.
+func (tf *Terraform) setTargets() (func(), error) {
+	noop := func() {}
+	if !tf.task.TargetedApply() {
+		return noop, nil
+	}
+
+	targets, err := tf.targets()
+	if err != nil {
+		return noop, err
+	}
+	if len(targets) == 0 {
+		return noop, nil
+	}
+
+	tf.logger.Debug("targeting resources for changed services",
+		taskNameLogKey, tf.task.Name(), "targets", targets)
+	tf.client.SetTargets(targets)
+	return func() { tf.client.SetTargets(nil) }, nil
+}
+
+// targets returns the resource addresses mapped to the services that changed
+// since the last successful run of the task. Returns no targets to apply all
+// resources when the task has not run successfully, when no services
+// changed, or when a changed service is not mapped to resource addresses.
+func (tf *Terraform) targets() ([]string, error) {
+	taskName := tf.task.Name()
+	wd := tf.task.WorkingDir()
+	current, err := tf.fileReader(filepath.Join(wd, tftmpl.TFVarsFilename))
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := tf.fileReader(filepath.Join(wd, tftmpl.ServicesSnapshotFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			tf.logger.Debug("no previous successful run, applying all resources",
+				taskNameLogKey, taskName)
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	names, err := tftmpl.ChangedServiceNames(previous, current)
+	if err != nil {
+		tf.logger.Error("unable to determine changed services", taskNameLogKey,
+			taskName, "error", err)
+		return nil, err
+	}
+	if len(names) == 0 {
+		tf.logger.Debug("no services changed, applying all resources",
+			taskNameLogKey, taskName)
+		return nil, nil
+	}
+
+	seen := make(map[string]bool)
+	var targets []string
+	for _, name := range names {
+		addrs, ok := tf.task.ApplyTargets(name)
+		if !ok {
+			tf.logger.Debug("changed service is not mapped to apply targets, "+
+				"applying all resources", taskNameLogKey, taskName,
+				"service", name)
+			return nil, nil
+		}
+		for _, addr := range addrs {
+			if !seen[addr] {
+				seen[addr] = true
+				targets = append(targets, addr)
+			}
+		}
+	}
+	sort.Strings(targets)
+	return targets, nil
+}
+
+// applySavedPlan saves the plan for the task along with its JSON
+// representation and then applies the saved plan. Applying the saved plan
+// ensures that the changes applied are the changes that were planned.
+func (tf *Terraform) applySavedPlan(ctx context.Context) error {
+	taskName := tf.task.Name()
+	planFile, jsonFile := tf.task.PlanFiles()
+
+	tf.logger.Trace("plan and save", taskNameLogKey, taskName, "plan_file", planFile)
+	planJSON, err := tf.client.SavePlan(ctx, planFile)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error tf-plan for '%s'", taskName))
+	}
+
+	if err := os.WriteFile(jsonFile, planJSON, filePerms); err != nil {
+		tf.logger.Error("unable to write plan JSON file", taskNameLogKey,
+			taskName, "error", err)
+		return err
+	}
+
+	tf.logger.Trace("apply saved plan", taskNameLogKey, taskName, "plan_file", planFile)
+	if err := tf.client.ApplyPlan(ctx, planFile); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error tf-apply for '%s'", taskName))
+	}
+	return nil
+}
+
+// verifyPostconditions reads the Terraform outputs after an apply and
+// evaluates the task's postconditions against them
+func (tf *Terraform) verifyPostconditions(ctx context.Context) error {
+	postconds := tf.task.Postconditions()
+	if len(postconds) == 0 {
+		return nil
+	}
+
+	taskName := tf.task.Name()
+	tf.logger.Trace("verify postconditions", taskNameLogKey, taskName)
+	outputs, err := tf.client.Outputs(ctx)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error tf-output for '%s'", taskName))
+	}
+
+	if err := checkPostconditions(postconds, outputs); err != nil {
+		tf.logger.Error("task applied but a postcondition failed",
+			taskNameLogKey, taskName, "error", err)
+		return errors.Wrap(err, fmt.Sprintf("error verifying postconditions for '%s'", taskName))
+	}
+	return nil
+}
+
+// publishOutputs writes the Terraform outputs to Consul KV after an apply if
+// publishing is enabled for the task. The outputs are written with the Consul
+// client of the task's watcher, which uses the task's Consul token if set.
+func (tf *Terraform) publishOutputs(ctx context.Context) error {
+	publish, ok := tf.task.PublishOutputs()
+	if !ok {
+		return nil
+	}
+
+	taskName := tf.task.Name()
+	tf.logger.Trace("publish outputs", taskNameLogKey, taskName, "path", publish.Path)
+	outputs, err := tf.client.Outputs(ctx)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error tf-output for '%s'", taskName))
+	}
+
+	pairs, err := outputKVPairs(publish, outputs)
+	if err != nil {
+		return errors.Wrap(err, fmt.Sprintf("error publishing outputs for '%s'", taskName))
+	}
+
+	kv := tf.watcher.Clients().Consul().KV()
+	opts := (&consulapi.WriteOptions{}).WithContext(ctx)
+	for _, pair := range pairs {
+		if _, err := kv.Put(pair, opts); err != nil {
+			tf.logger.Error("task applied but unable to publish output to Consul KV",
+				taskNameLogKey, taskName, "key", pair.Key, "error", err)
+			return errors.Wrap(err, fmt.Sprintf("error publishing outputs for '%s'", taskName))
+		}
+	}
+
+	tf.logger.Debug("published outputs to Consul KV", taskNameLogKey, taskName,
+		"path", publish.Path, "outputs_count", len(pairs))
+	return nil
+}
+
 // initTaskTemplate creates templates to be monitored and rendered.
 func (tf *Terraform) initTaskTemplate() error {
 	wd := tf.task.WorkingDir()
@@ -546,7 +1192,7 @@ func (tf *Terraform) initTaskTemplate() error {
 		return err
 	}
 
-	renderer := hcat.NewFileRenderer(hcat.FileRendererInput{
+	renderer := templates.NewFileRenderer(templates.FileRendererInput{
 		Path:  tfvarsFilepath,
 		Perms: filePerms,
 	})
@@ -599,6 +1245,107 @@ func (tf *Terraform) initTaskTemplate() error {
 	return nil
 }
 
+// initExtraTemplates creates the extra templates of the task to be monitored
+// and rendered into the working directory. Changes to the data of the extra
+// templates do not trigger the task.
+func (tf *Terraform) initExtraTemplates() error {
+	wd := tf.task.WorkingDir()
+	logger := tf.logger.With(taskNameLogKey, tf.task.Name())
+
+	// cleanup old extra templates from watcher
+	for _, tmpl := range tf.extraTemplates {
+		tf.watcher.Deregister(tmpl)
+	}
+	tf.extraTemplates = nil
+
+	tmplPaths := tf.task.ExtraTemplates()
+	if len(tmplPaths) == 0 {
+		return nil
+	}
+
+	servicesMeta, err := getServicesMetaData(tf.logger, tf.task)
+	if err != nil {
+		return err
+	}
+
+	extraTemplates := make([]templates.Template, 0, len(tmplPaths))
+	for _, tmplPath := range tmplPaths {
+		content, err := tf.fileReader(tmplPath)
+		if err != nil {
+			logger.Error("unable to read extra template", "path", tmplPath,
+				"error", err)
+			return err
+		}
+
+		renderer := templates.NewFileRenderer(templates.FileRendererInput{
+			Path:  filepath.Join(wd, config.ExtraTemplateFilename(tmplPath)),
+			Perms: filePerms,
+		})
+
+		tmpl := hcat.NewTemplate(hcat.TemplateInput{
+			Contents:     string(content),
+			Renderer:     renderer,
+			FuncMapMerge: tmplfunc.HCLMap(servicesMeta),
+		})
+
+		logger.Debug("validating extra template", "path", tmplPath)
+		if err = validateTemplate(tmpl, tf.watcher.Clients()); err != nil {
+			logger.Error("error validating extra template", "path", tmplPath,
+				"error", err)
+			return errors.Wrap(err, "unable to retrieve data from Consul")
+		}
+
+		// suppress triggers so that only the task's condition triggers the task
+		n := notifier.NewOnceNotifier(notifier.TriggerCheckSuppress, tmpl)
+		n.SetOnceDone()
+
+		err = tf.watcher.Register(n)
+		if err != nil && err != hcat.ErrRegistry {
+			logger.Error("unable to register extra template", "path", tmplPath,
+				"error", err)
+			return err
+		}
+		extraTemplates = append(extraTemplates, n)
+	}
+
+	tf.extraTemplates = extraTemplates
+	return nil
+}
+
+// renderExtraTemplates fetches data for the extra templates and renders the
+// templates that changed. Returns false if the data for any of the extra
+// templates is not yet complete.
+func (tf *Terraform) renderExtraTemplates() (bool, error) {
+	taskName := tf.task.Name()
+	tnlog := tf.logger.With(taskNameLogKey, taskName)
+
+	complete := true
+	for _, tmpl := range tf.extraTemplates {
+		result, err := tf.resolver.Run(tmpl, tf.watcher)
+		if err != nil {
+			tnlog.Error("error checking dependency changes for extra template",
+				"error", err)
+			return false, fmt.Errorf("error fetching extra template "+
+				"dependencies for task %s: %s", taskName, err)
+		}
+
+		if !result.Complete {
+			complete = false
+			continue
+		}
+
+		if !result.NoChange {
+			if _, err := tmpl.Render(result.Contents); err != nil {
+				tnlog.Error("rendering extra template for task", "error", err)
+				return false, err
+			}
+			tnlog.Trace("extra template for task rendered")
+		}
+	}
+
+	return complete, nil
+}
+
 // validateTemplate verifies that executing the fetch requests of
 // a template's dependencies does not error.
 func validateTemplate(t *hcat.Template, clients hcat.Looker) error {
@@ -618,20 +1365,54 @@ func validateTemplate(t *hcat.Template, clients hcat.Looker) error {
 }
 
 // setNotifier sets a notifier on the template to ensure only the condition's
-// monitored changes (and not the module input's changes) trigger the task.
+// monitored changes (and not the module input's changes) trigger the task,
+// unless the task is configured to trigger on any module input change.
 func (tf *Terraform) setNotifier(tmpl templates.Template) error {
 	var notifyTrigger notifier.TriggerCheck
-	switch tf.task.Condition().(type) {
+	// conditionDep is the prefix of the dependency name of the condition's
+	// data to tell it apart from the module inputs' data
+	var conditionDep string
+	switch c := tf.task.Condition().(type) {
 	case *config.ServicesConditionConfig:
-		notifyTrigger = notifier.TriggerCheckService
+		if fs := c.FlapSuppression; fs != nil && config.BoolVal(fs.Enabled) {
+			notifyTrigger = notifier.MakeTriggerCheckServiceFlapSuppression(
+				tf.logger, config.IntVal(fs.Transitions),
+				config.TimeDurationVal(fs.Window))
+		} else {
+			notifyTrigger = notifier.TriggerCheckService
+		}
+		conditionDep = "services"
 	case *config.CatalogServicesConditionConfig:
-		notifyTrigger = notifier.MakeTriggerCheckCatalogService()
+		if c.HasInstanceThresholds() {
+			notifyTrigger = notifier.MakeTriggerCheckCatalogServiceThresholds(
+				config.IntVal(c.MinInstances), config.IntVal(c.MaxInstances))
+		} else if config.BoolVal(c.TriggerOnTagChanges) {
+			notifyTrigger = notifier.MakeTriggerCheckCatalogServiceTags()
+		} else {
+			notifyTrigger = notifier.MakeTriggerCheckCatalogService()
+		}
+		conditionDep = "catalog_services"
 	case *config.ConsulKVConditionConfig:
 		notifyTrigger = notifier.TriggerCheckConsulKV
+		conditionDep = "consul_kv"
+	case *config.DNSConditionConfig:
+		notifyTrigger = notifier.TriggerCheckDNS
+		conditionDep = "dns"
+	case *config.FileConditionConfig:
+		notifyTrigger = notifier.TriggerCheckFile
+		conditionDep = "files"
+	case *config.PluginConditionConfig:
+		notifyTrigger = notifier.TriggerCheckPlugin
+		conditionDep = "plugin"
 	case *config.ScheduleConditionConfig:
 		notifyTrigger = notifier.TriggerCheckSuppress
 	default:
 		notifyTrigger = notifier.TriggerCheckService
+		conditionDep = "services"
+	}
+	if conditionDep != "" && tf.task.TriggerOnAnyInputChange() {
+		notifyTrigger = notifier.MakeTriggerCheckAnyInputChange(
+			notifyTrigger, conditionDep)
 	}
 	tf.onceNotifier = notifier.NewOnceNotifier(notifyTrigger, tmpl)
 	tf.template = tf.onceNotifier
@@ -647,15 +1428,32 @@ func (tf *Terraform) validateTask(ctx context.Context) error {
 }
 
 // getTerraformHandlers returns the first handler in a chain of handlers
-// for a Terraform driver.
+// for a Terraform driver. The handlers of the providers run first, followed
+// by the user-defined handlers of the task in the order they are configured.
 //
 // Returned handler may be nil even if returned err is nil. This happens when
-// no providers have a handler.
-func getTerraformHandlers(taskName string, providers TerraformProviderBlocks) (handler.Handler, error) {
+// no providers have a handler and the task has no user-defined handlers.
+func getTerraformHandlers(task *Task) (handler.Handler, error) {
+	taskName := task.Name()
 	counter := 0
 	var next handler.Handler
 	logger := logging.Global().Named(logSystemName).Named(terraformSubsystemName)
-	for _, p := range providers {
+
+	// Chain is built in reverse since each handler is set before the next
+	handlers := task.Handlers()
+	for i := len(handlers) - 1; i >= 0; i-- {
+		h, err := newExecHandler(task, handlers[i].Exec)
+		if err != nil {
+			logger.Error("error, could not initialize exec handler for task",
+				taskNameLogKey, taskName, "error", err)
+			return nil, err
+		}
+		counter++
+		h.SetNext(next)
+		next = h
+	}
+
+	for _, p := range task.Providers() {
 		h, err := handler.TerraformProviderHandler(p.Name(), p.ProviderBlock().RawConfig())
 		if err != nil {
 			logger.Error("error, could not initialize handler for provider",
@@ -673,6 +1471,30 @@ func getTerraformHandlers(taskName string, providers TerraformProviderBlocks) (h
 	return next, nil
 }
 
+// newExecHandler returns the exec handler for the task's configuration
+func newExecHandler(task *Task, conf *config.ExecHandlerConfig) (handler.Handler, error) {
+	if conf == nil {
+		return nil, errors.New("unsupported handler type")
+	}
+
+	return handler.NewExec(handler.ExecConfig{
+		Command:       config.StringVal(conf.Command),
+		Args:          conf.Args,
+		Timeout:       config.TimeDurationVal(conf.Timeout),
+		IgnoreFailure: config.StringVal(conf.FailurePolicy) == config.HandlerFailurePolicyIgnore,
+		WorkingDir:    task.WorkingDir(),
+		Input: handler.ExecInput{
+			Task: handler.ExecTaskInput{
+				Name:        task.Name(),
+				Description: task.Description(),
+				Module:      task.Module(),
+				Providers:   task.ProviderIDs(),
+				WorkingDir:  task.WorkingDir(),
+			},
+		},
+	})
+}
+
 // getServicesMetaData helps retrieve metadata which can come from a number of
 // configuration sources: task.services' related service block, condition
 // "service" block, module_input "service" block.
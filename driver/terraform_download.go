@@ -4,12 +4,20 @@
 package driver
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/hashicorp/consul-terraform-sync/config"
 	"github.com/hashicorp/consul-terraform-sync/logging"
@@ -30,15 +38,28 @@ const (
 
 const fallbackTFVersion = "1.1.8"
 
+// terraformVersionsDir is the directory within the Terraform path where the
+// Terraform versions configured for individual tasks are installed
+const terraformVersionsDir = "versions"
+
 // TerraformVersion is the version of Terraform CLI for the Terraform driver.
 var TerraformVersion *goVersion.Version
 
+// installVersionMu serializes installs of task Terraform versions so that a
+// version shared by multiple tasks is only downloaded once
+var installVersionMu sync.Mutex
+
 // InstallTerraform installs the Terraform binary to the configured path.
 // If an existing Terraform exists in the path, it is checked for compatibility.
 func InstallTerraform(ctx context.Context, conf *config.TerraformConfig) error {
 	path := *conf.Path
 
 	logger := logging.Global().Named(logSystemName).Named(terraformSubsystemName)
+	if err := writeTerraformCLIConfig(conf); err != nil {
+		logger.Error("error writing Terraform CLI configuration", "error", err)
+		return err
+	}
+
 	if isTFInstalled(path) {
 		tfVersion, compatible, err := verifyInstalledTF(ctx, conf)
 		if err != nil {
@@ -72,6 +93,72 @@ func InstallTerraform(ctx context.Context, conf *config.TerraformConfig) error {
 	return nil
 }
 
+// InstallTerraformVersion installs the Terraform version configured for a task
+// and returns the path to the directory of the Terraform binary for the
+// version. Versions are installed within the Terraform path at
+// versions/<version> and are cached across tasks. If the version is the same
+// as the Terraform driver's version, the Terraform path is returned.
+func InstallTerraformVersion(ctx context.Context, conf *config.TerraformConfig,
+	version string) (string, error) {
+
+	tfVersion, err := goVersion.NewVersion(version)
+	if err != nil {
+		return "", err
+	}
+
+	if TerraformVersion != nil && TerraformVersion.Equal(tfVersion) {
+		return *conf.Path, nil
+	}
+
+	if config.StringVal(conf.BinarySource) != "" {
+		return "", fmt.Errorf("unable to install Terraform version %s, only "+
+			"the Terraform binary of the binary_source is available", version)
+	}
+
+	if err := isTFCompatible(conf, tfVersion); err != nil {
+		return "", err
+	}
+
+	installVersionMu.Lock()
+	defer installVersionMu.Unlock()
+
+	logger := logging.Global().Named(logSystemName).Named(terraformSubsystemName)
+	path := terraformVersionPath(*conf.Path, tfVersion)
+	if _, err := os.Stat(filepath.Join(path, "terraform")); err == nil {
+		logger.Debug("skipping install, terraform version already exists",
+			"tf_version", tfVersion.String(), "install_path", path)
+		return path, nil
+	}
+
+	logger.Info("install terraform version for task", "tf_version",
+		tfVersion.String(), "install_path", path)
+	if err := os.MkdirAll(path, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	installer := hcinstall.NewInstaller()
+	_, err = installer.Ensure(ctx, []src.Source{
+		&releases.ExactVersion{
+			Product:    product.Terraform,
+			Version:    tfVersion,
+			InstallDir: path,
+		},
+	})
+	if err != nil {
+		logger.Error("error installing terraform version", "tf_version",
+			tfVersion.String(), "error", err)
+		return "", err
+	}
+
+	return path, nil
+}
+
+// terraformVersionPath returns the directory within the Terraform path to
+// install a Terraform version to
+func terraformVersionPath(tfPath string, version *goVersion.Version) string {
+	return filepath.Join(tfPath, terraformVersionsDir, version.String())
+}
+
 // isTFInstalled checks to see if terraform already exists at path.
 func isTFInstalled(tfPath string) bool {
 	tfPath = filepath.Join(tfPath, "terraform")
@@ -164,6 +251,10 @@ func isTFCompatible(conf *config.TerraformConfig, version *goVersion.Version) er
 // the path. If the latest version is outside of the known supported range for
 // CTS, the fall back version 0.13.5 is downloaded.
 func installTerraform(ctx context.Context, conf *config.TerraformConfig) (*goVersion.Version, error) {
+	if config.StringVal(conf.BinarySource) != "" {
+		return installTerraformSource(ctx, conf)
+	}
+
 	var tfVersion *goVersion.Version
 	logger := logging.Global().Named(logSystemName).Named(terraformSubsystemName)
 	if conf.Version != nil && *conf.Version != "" {
@@ -212,3 +303,151 @@ func installTerraform(ctx context.Context, conf *config.TerraformConfig) (*goVer
 	logger.Debug("successfully installed terraform", "version", tfVersion.String(), "install_path", installedPath)
 	return tfVersion, nil
 }
+
+// installTerraformSource installs Terraform from the zip archive of the binary
+// source into the path. The checksum of the archive is verified before the
+// binary is extracted, and the version of the binary is verified after.
+func installTerraformSource(ctx context.Context, conf *config.TerraformConfig) (*goVersion.Version, error) {
+	logger := logging.Global().Named(logSystemName).Named(terraformSubsystemName)
+	source := *conf.BinarySource
+
+	archive, err := readBinarySource(ctx, source)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read binary_source %q: %s", source, err)
+	}
+
+	sum := sha256.Sum256(archive)
+	if checksum := hex.EncodeToString(sum[:]); checksum != conf.BinarySHA256() {
+		return nil, fmt.Errorf("checksum mismatch for binary_source %q: "+
+			"expected sha256:%s, got sha256:%s", source, conf.BinarySHA256(), checksum)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read zip archive of binary_source "+
+			"%q: %s", source, err)
+	}
+
+	var binary *zip.File
+	for _, f := range zr.File {
+		if f.Name == "terraform" {
+			binary = f
+			break
+		}
+	}
+	if binary == nil {
+		return nil, fmt.Errorf("terraform binary not found in zip archive "+
+			"of binary_source %q", source)
+	}
+
+	if err := os.MkdirAll(*conf.Path, os.ModePerm); err != nil {
+		return nil, err
+	}
+	if err := extractZipFile(binary, filepath.Join(*conf.Path, "terraform")); err != nil {
+		return nil, err
+	}
+
+	tfVersion, compatible, err := verifyInstalledTF(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+	if !compatible {
+		return nil, errUnsupportedTerraformVersion
+	}
+
+	logger.Debug("successfully installed terraform from binary source",
+		"version", tfVersion.String(), "binary_source", source)
+	return tfVersion, nil
+}
+
+// readBinarySource reads the file of the binary source URL. Supports the file,
+// http, and https schemes.
+func readBinarySource(ctx context.Context, source string) ([]byte, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "file":
+		return os.ReadFile(u.Path)
+	case "http", "https":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected response code %d", resp.StatusCode)
+		}
+		return io.ReadAll(resp.Body)
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+}
+
+// extractZipFile extracts the file of the zip archive as an executable to the
+// path. The file is extracted to a temporary file first so that an existing
+// binary is replaced atomically.
+func extractZipFile(f *zip.File, path string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".terraform-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, rc); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// writeTerraformCLIConfig writes the Terraform CLI configuration file for
+// Terraform to install providers from the network mirror. The file is not
+// written if a network mirror is not configured.
+func writeTerraformCLIConfig(conf *config.TerraformConfig) error {
+	path := conf.CLIConfigFile()
+	if path == "" {
+		return nil
+	}
+
+	// Terraform requires the URL of the network mirror to end with a slash
+	mirror := *conf.NetworkMirror
+	if !strings.HasSuffix(mirror, "/") {
+		mirror += "/"
+	}
+
+	content := fmt.Sprintf(`# Generated by Consul-Terraform-Sync for the network_mirror of the
+# Terraform driver. Changes will be overwritten.
+disable_checkpoint = true
+
+provider_installation {
+  network_mirror {
+    url = %q
+  }
+}
+`, mirror)
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
@@ -4,11 +4,21 @@
 package driver
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/consul-terraform-sync/config"
 	"github.com/hashicorp/go-version"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIsTFCompatible(t *testing.T) {
@@ -53,3 +63,182 @@ func TestIsTFCompatible(t *testing.T) {
 		})
 	}
 }
+
+func TestInstallTerraformVersion(t *testing.T) {
+	origVersion := TerraformVersion
+	defer func() { TerraformVersion = origVersion }()
+	TerraformVersion = version.Must(version.NewSemver("1.1.8"))
+
+	tfPath := t.TempDir()
+	conf := &config.TerraformConfig{
+		Path:    config.String(tfPath),
+		Backend: make(map[string]interface{}),
+	}
+	ctx := context.Background()
+
+	t.Run("driver version", func(t *testing.T) {
+		path, err := InstallTerraformVersion(ctx, conf, "1.1.8")
+		require.NoError(t, err)
+		assert.Equal(t, tfPath, path)
+	})
+
+	t.Run("cached version", func(t *testing.T) {
+		cached := filepath.Join(tfPath, "versions", "1.0.0")
+		require.NoError(t, os.MkdirAll(cached, 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(cached, "terraform"), []byte{}, 0750))
+
+		path, err := InstallTerraformVersion(ctx, conf, "1.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, cached, path)
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		_, err := InstallTerraformVersion(ctx, conf, "latest")
+		assert.Error(t, err)
+	})
+
+	t.Run("incompatible version", func(t *testing.T) {
+		pgConf := &config.TerraformConfig{
+			Path:    config.String(tfPath),
+			Backend: map[string]interface{}{"pg": nil},
+		}
+		_, err := InstallTerraformVersion(ctx, pgConf, "0.13.5")
+		assert.Error(t, err)
+	})
+}
+
+// testTerraformZip returns a zip archive with a fake Terraform binary that
+// outputs the version, and the SHA-256 checksum of the archive
+func testTerraformZip(t *testing.T, name string) ([]byte, string) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create(name)
+	require.NoError(t, err)
+	_, err = w.Write([]byte(`#!/bin/sh
+echo '{"terraform_version":"1.1.8","platform":"linux_amd64","provider_selections":{},"terraform_outdated":false}'
+`))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	sum := sha256.Sum256(buf.Bytes())
+	return buf.Bytes(), "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func TestInstallTerraformSource(t *testing.T) {
+	ctx := context.Background()
+
+	newConf := func(t *testing.T, archive []byte, checksum string) *config.TerraformConfig {
+		source := filepath.Join(t.TempDir(), "terraform.zip")
+		require.NoError(t, os.WriteFile(source, archive, 0644))
+		return &config.TerraformConfig{
+			Version:        config.String(""),
+			Path:           config.String(t.TempDir()),
+			Backend:        make(map[string]interface{}),
+			BinarySource:   config.String("file://" + source),
+			BinaryChecksum: config.String(checksum),
+		}
+	}
+
+	t.Run("success", func(t *testing.T) {
+		archive, checksum := testTerraformZip(t, "terraform")
+		conf := newConf(t, archive, checksum)
+
+		tfVersion, err := installTerraformSource(ctx, conf)
+		require.NoError(t, err)
+		assert.Equal(t, "1.1.8", tfVersion.String())
+
+		info, err := os.Stat(filepath.Join(*conf.Path, "terraform"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		archive, _ := testTerraformZip(t, "terraform")
+		conf := newConf(t, archive, "sha256:"+hex.EncodeToString(make([]byte, 32)))
+
+		_, err := installTerraformSource(ctx, conf)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "checksum mismatch")
+		assert.NoFileExists(t, filepath.Join(*conf.Path, "terraform"))
+	})
+
+	t.Run("binary not found", func(t *testing.T) {
+		archive, checksum := testTerraformZip(t, "README.md")
+		conf := newConf(t, archive, checksum)
+
+		_, err := installTerraformSource(ctx, conf)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("source not found", func(t *testing.T) {
+		conf := newConf(t, nil, "")
+		conf.BinarySource = config.String("file:///does/not/exist.zip")
+
+		_, err := installTerraformSource(ctx, conf)
+		assert.Error(t, err)
+	})
+}
+
+func TestReadBinarySource(t *testing.T) {
+	ctx := context.Background()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/terraform.zip" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("archive"))
+	}))
+	defer ts.Close()
+
+	b, err := readBinarySource(ctx, ts.URL+"/terraform.zip")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("archive"), b)
+
+	_, err = readBinarySource(ctx, ts.URL+"/missing.zip")
+	assert.Error(t, err)
+
+	_, err = readBinarySource(ctx, "ftp://mirror.internal/terraform.zip")
+	assert.Error(t, err)
+}
+
+func TestInstallTerraformVersion_BinarySource(t *testing.T) {
+	origVersion := TerraformVersion
+	defer func() { TerraformVersion = origVersion }()
+	TerraformVersion = version.Must(version.NewSemver("1.1.8"))
+
+	conf := &config.TerraformConfig{
+		Path:         config.String(t.TempDir()),
+		Backend:      make(map[string]interface{}),
+		BinarySource: config.String("file:///opt/tf/terraform.zip"),
+	}
+
+	_, err := InstallTerraformVersion(context.Background(), conf, "1.0.0")
+	assert.Error(t, err)
+}
+
+func TestWriteTerraformCLIConfig(t *testing.T) {
+	t.Run("network mirror", func(t *testing.T) {
+		conf := &config.TerraformConfig{
+			Path:          config.String(t.TempDir()),
+			NetworkMirror: config.String("https://mirror.internal/providers"),
+		}
+		require.NoError(t, writeTerraformCLIConfig(conf))
+
+		b, err := os.ReadFile(conf.CLIConfigFile())
+		require.NoError(t, err)
+		assert.Contains(t, string(b), "disable_checkpoint = true")
+		assert.Contains(t, string(b), `url = "https://mirror.internal/providers/"`)
+	})
+
+	t.Run("no network mirror", func(t *testing.T) {
+		path := t.TempDir()
+		conf := &config.TerraformConfig{
+			Path:          config.String(path),
+			NetworkMirror: config.String(""),
+		}
+		require.NoError(t, writeTerraformCLIConfig(conf))
+		assert.NoFileExists(t, filepath.Join(path, config.TerraformCLIConfigFilename))
+	})
+}
@@ -31,7 +31,8 @@ func NewTerraformProviderBlocks(blocks []hcltmpl.NamedBlock) TerraformProviderBl
 }
 
 // NewTerraformProviderBlock creates a provider block with the environment
-// variables separated from provider arguments from the base hcl block.
+// variables separated from provider arguments from the base hcl block. The
+// rate_limit block is enforced by CTS and is removed from the arguments.
 func NewTerraformProviderBlock(b hcltmpl.NamedBlock) TerraformProviderBlock {
 	env := make(map[string]string)
 
@@ -45,6 +46,7 @@ func NewTerraformProviderBlock(b hcltmpl.NamedBlock) TerraformProviderBlock {
 			break
 		}
 	}
+	delete(cp.Variables, "rate_limit")
 
 	return TerraformProviderBlock{
 		block: cp,
@@ -63,6 +65,48 @@ func (p TerraformProviderBlock) Copy() TerraformProviderBlock {
 	}
 }
 
+// Override creates a copy of the provider block with the arguments replaced
+// by the override values. Environment variables configured by overriding
+// task_env are merged with the provider's existing environment variables.
+func (p TerraformProviderBlock) Override(args map[string]interface{}) TerraformProviderBlock {
+	o := NewTerraformProviderBlock(p.block.Override(args))
+
+	env := make(map[string]string, len(p.env)+len(o.env))
+	for k, v := range p.env {
+		env[k] = v
+	}
+	for k, v := range o.env {
+		env[k] = v
+	}
+	o.env = env
+	return o
+}
+
+// Equal returns whether the provider block has the same arguments and
+// environment variables as the other provider block.
+func (p TerraformProviderBlock) Equal(o TerraformProviderBlock) bool {
+	if p.block.Name != o.block.Name ||
+		len(p.block.Variables) != len(o.block.Variables) ||
+		len(p.env) != len(o.env) {
+		return false
+	}
+
+	for k, v := range p.block.Variables {
+		ov, ok := o.block.Variables[k]
+		if !ok || !v.RawEquals(ov) {
+			return false
+		}
+	}
+
+	for k, v := range p.env {
+		if ov, ok := o.env[k]; !ok || v != ov {
+			return false
+		}
+	}
+
+	return true
+}
+
 // Name returns the name of the provider. This is the label of the HCL named
 // block.
 func (p TerraformProviderBlock) Name() string {
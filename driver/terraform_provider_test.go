@@ -46,6 +46,21 @@ func TestTerraformProviderBlocks_NewTerraformProviderBlocks(t *testing.T) {
 	assert.ElementsMatch(t, expectedTerraformProviderBlocks, providerBlocks)
 }
 
+func TestNewTerraformProviderBlock_RateLimit(t *testing.T) {
+	p := NewTerraformProviderBlock(hcltmpl.NewNamedBlock(
+		map[string]interface{}{
+			"panos": map[string]interface{}{
+				"hostname": "fw-1",
+				"rate_limit": map[string]interface{}{
+					"runs_per_minute": 2,
+				},
+			},
+		}))
+
+	assert.NotContains(t, p.ProviderBlock().Variables, "rate_limit")
+	assert.Equal(t, cty.StringVal("fw-1"), p.ProviderBlock().Variables["hostname"])
+}
+
 func TestTerraformProviderBlock_Copy(t *testing.T) {
 	cases := []struct {
 		name          string
@@ -93,6 +108,47 @@ func TestTerraformProviderBlock_Copy(t *testing.T) {
 	}
 }
 
+func TestTerraformProviderBlock_Override(t *testing.T) {
+	original := NewTerraformProviderBlock(hcltmpl.NewNamedBlock(
+		map[string]interface{}{
+			"panos": map[string]interface{}{
+				"hostname": "fw-1",
+				"username": "admin",
+				"task_env": map[string]interface{}{
+					"PANOS_PASSWORD": "password",
+				},
+			},
+		}))
+
+	t.Run("arguments", func(t *testing.T) {
+		o := original.Override(map[string]interface{}{"hostname": "fw-2"})
+
+		assert.Equal(t, "panos", o.Name())
+		assert.Equal(t, cty.StringVal("fw-2"), o.ProviderBlock().Variables["hostname"])
+		assert.Equal(t, cty.StringVal("admin"), o.ProviderBlock().Variables["username"])
+		assert.Equal(t, "fw-2", o.ProviderBlock().RawConfig()["hostname"])
+		assert.Equal(t, original.Env(), o.Env())
+
+		// Original is not modified
+		assert.Equal(t, cty.StringVal("fw-1"), original.ProviderBlock().Variables["hostname"])
+		assert.Equal(t, "fw-1", original.ProviderBlock().RawConfig()["hostname"])
+	})
+
+	t.Run("task_env", func(t *testing.T) {
+		o := original.Override(map[string]interface{}{
+			"task_env": map[string]interface{}{
+				"PANOS_API_KEY": "key",
+			},
+		})
+
+		assert.NotContains(t, o.ProviderBlock().Variables, "task_env")
+		assert.Equal(t, map[string]string{
+			"PANOS_PASSWORD": "password",
+			"PANOS_API_KEY":  "key",
+		}, o.Env())
+	})
+}
+
 func TestTerraformProviderBlock_Name(t *testing.T) {
 	expectedName := "local"
 	tpb := TerraformProviderBlock{
@@ -316,3 +372,70 @@ func TestTerraformProviderBlocks_Copy(t *testing.T) {
 		})
 	}
 }
+
+func TestTerraformProviderBlock_Equal(t *testing.T) {
+	newBlock := func(args map[string]interface{}) TerraformProviderBlock {
+		return NewTerraformProviderBlock(hcltmpl.NewNamedBlockTest(
+			map[string]interface{}{"providerA": args}))
+	}
+
+	p := newBlock(map[string]interface{}{
+		"endpoint": "https://a.example.com",
+		"task_env": map[string]interface{}{"PROVIDER_TOKEN": "token"},
+	})
+
+	cases := []struct {
+		name     string
+		other    TerraformProviderBlock
+		expected bool
+	}{
+		{
+			"equal",
+			newBlock(map[string]interface{}{
+				"endpoint": "https://a.example.com",
+				"task_env": map[string]interface{}{"PROVIDER_TOKEN": "token"},
+			}),
+			true,
+		},
+		{
+			"changed argument",
+			newBlock(map[string]interface{}{
+				"endpoint": "https://b.example.com",
+				"task_env": map[string]interface{}{"PROVIDER_TOKEN": "token"},
+			}),
+			false,
+		},
+		{
+			"changed env",
+			newBlock(map[string]interface{}{
+				"endpoint": "https://a.example.com",
+				"task_env": map[string]interface{}{"PROVIDER_TOKEN": "rotated"},
+			}),
+			false,
+		},
+		{
+			"added argument",
+			newBlock(map[string]interface{}{
+				"endpoint": "https://a.example.com",
+				"region":   "us-east-1",
+				"task_env": map[string]interface{}{"PROVIDER_TOKEN": "token"},
+			}),
+			false,
+		},
+		{
+			"different provider",
+			NewTerraformProviderBlock(hcltmpl.NewNamedBlockTest(
+				map[string]interface{}{"providerB": map[string]interface{}{
+					"endpoint": "https://a.example.com",
+					"task_env": map[string]interface{}{"PROVIDER_TOKEN": "token"},
+				}})),
+			false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, p.Equal(tc.other))
+		})
+	}
+}
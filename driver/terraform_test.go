@@ -5,8 +5,16 @@ package driver
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -15,12 +23,15 @@ import (
 	"github.com/hashicorp/consul-terraform-sync/logging"
 	mocks "github.com/hashicorp/consul-terraform-sync/mocks/client"
 	mocksTmpl "github.com/hashicorp/consul-terraform-sync/mocks/templates"
+	"github.com/hashicorp/consul-terraform-sync/templates"
 	"github.com/hashicorp/consul-terraform-sync/templates/hcltmpl"
+	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl"
 	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl/tmplfunc"
 	"github.com/hashicorp/consul-terraform-sync/testutils"
 	"github.com/hashicorp/go-uuid"
 	goVersion "github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcat"
+	"github.com/hashicorp/hcat/dep"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -111,6 +122,341 @@ func TestRenderTemplate(t *testing.T) {
 	}
 }
 
+func TestRenderTemplate_ExtraTemplates(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("extra template data not complete", func(t *testing.T) {
+		extra := new(mocksTmpl.Template)
+
+		r := new(mocksTmpl.Resolver)
+		r.On("Run", extra, mock.Anything).
+			Return(hcat.ResolveEvent{Complete: false}, nil).Once()
+		r.On("Run", mock.Anything, mock.Anything).
+			Return(hcat.ResolveEvent{Complete: true}, nil).Once()
+
+		tmpl := new(mocksTmpl.Template)
+
+		tf := &Terraform{
+			task:           &Task{name: "task", enabled: true, logger: logging.NewNullLogger()},
+			resolver:       r,
+			watcher:        new(mocksTmpl.Watcher),
+			extraTemplates: []templates.Template{extra},
+			logger:         logging.NewNullLogger(),
+		}
+		tf.setNotifier(tmpl)
+
+		rendered, err := tf.RenderTemplate(ctx)
+		assert.NoError(t, err)
+		assert.False(t, rendered)
+		tmpl.AssertNotCalled(t, "Render", mock.Anything)
+		extra.AssertNotCalled(t, "Render", mock.Anything)
+		assert.False(t, tf.OnceDone())
+	})
+
+	t.Run("extra templates rendered", func(t *testing.T) {
+		changed := new(mocksTmpl.Template)
+		changed.On("Render", mock.Anything).Return(hcat.RenderResult{}, nil).Once()
+		unchanged := new(mocksTmpl.Template)
+
+		r := new(mocksTmpl.Resolver)
+		r.On("Run", changed, mock.Anything).
+			Return(hcat.ResolveEvent{Complete: true}, nil).Once()
+		r.On("Run", unchanged, mock.Anything).
+			Return(hcat.ResolveEvent{Complete: true, NoChange: true}, nil).Once()
+		r.On("Run", mock.Anything, mock.Anything).
+			Return(hcat.ResolveEvent{Complete: true}, nil).Once()
+
+		tmpl := new(mocksTmpl.Template)
+		tmpl.On("Render", mock.Anything).Return(hcat.RenderResult{}, nil).Once()
+
+		tf := &Terraform{
+			task:           &Task{name: "task", enabled: true, logger: logging.NewNullLogger()},
+			resolver:       r,
+			watcher:        new(mocksTmpl.Watcher),
+			extraTemplates: []templates.Template{changed, unchanged},
+			logger:         logging.NewNullLogger(),
+		}
+		tf.setNotifier(tmpl)
+
+		rendered, err := tf.RenderTemplate(ctx)
+		assert.NoError(t, err)
+		assert.True(t, rendered)
+		tmpl.AssertExpectations(t)
+		changed.AssertExpectations(t)
+		unchanged.AssertNotCalled(t, "Render", mock.Anything)
+	})
+}
+
+func TestRenderTask(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	newTerraform := func(tmpl *mocksTmpl.Template, w *mocksTmpl.Watcher) *Terraform {
+		return &Terraform{
+			task: &Task{name: "task", enabled: true, workingDir: "sync-tasks/task",
+				logger: logging.NewNullLogger()},
+			resolver: new(mocksTmpl.Resolver),
+			template: tmpl,
+			watcher:  w,
+			logger:   logging.NewNullLogger(),
+		}
+	}
+
+	t.Run("rendered", func(t *testing.T) {
+		tmpl := new(mocksTmpl.Template)
+		tmpl.On("Execute", mock.Anything).Return([]byte("contents"), nil).Once()
+		tmpl.On("Render", []byte("contents")).
+			Return(hcat.RenderResult{DidRender: true}, nil).Once()
+		w := new(mocksTmpl.Watcher)
+		w.On("Recaller", tmpl).Return(nil)
+		w.On("Complete", tmpl).Return(true)
+
+		result, err := newTerraform(tmpl, w).RenderTask(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, RenderResult{
+			Complete: true,
+			Changed:  true,
+			Files:    []string{filepath.Join("sync-tasks/task", tftmpl.TFVarsFilename)},
+		}, result)
+		tmpl.AssertExpectations(t)
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		tmpl := new(mocksTmpl.Template)
+		tmpl.On("Execute", mock.Anything).Return([]byte("contents"), nil).Once()
+		tmpl.On("Render", mock.Anything).Return(hcat.RenderResult{}, nil).Once()
+		w := new(mocksTmpl.Watcher)
+		w.On("Recaller", tmpl).Return(nil)
+		w.On("Complete", tmpl).Return(true)
+
+		result, err := newTerraform(tmpl, w).RenderTask(ctx)
+		assert.NoError(t, err)
+		assert.True(t, result.Complete)
+		assert.False(t, result.Changed)
+	})
+
+	t.Run("data not complete", func(t *testing.T) {
+		tmpl := new(mocksTmpl.Template)
+		tmpl.On("Execute", mock.Anything).Return([]byte("contents"), nil).Once()
+		w := new(mocksTmpl.Watcher)
+		w.On("Recaller", tmpl).Return(nil)
+		w.On("Complete", tmpl).Return(false)
+
+		result, err := newTerraform(tmpl, w).RenderTask(ctx)
+		assert.NoError(t, err)
+		assert.False(t, result.Complete)
+		tmpl.AssertNotCalled(t, "Render", mock.Anything)
+	})
+
+	t.Run("execute error", func(t *testing.T) {
+		tmpl := new(mocksTmpl.Template)
+		tmpl.On("Execute", mock.Anything).Return(nil, errors.New("error")).Once()
+		w := new(mocksTmpl.Watcher)
+		w.On("Recaller", tmpl).Return(nil)
+
+		_, err := newTerraform(tmpl, w).RenderTask(ctx)
+		assert.Error(t, err)
+		tmpl.AssertNotCalled(t, "Render", mock.Anything)
+	})
+
+	t.Run("render error", func(t *testing.T) {
+		tmpl := new(mocksTmpl.Template)
+		tmpl.On("Execute", mock.Anything).Return([]byte("contents"), nil).Once()
+		tmpl.On("Render", mock.Anything).
+			Return(hcat.RenderResult{}, errors.New("error")).Once()
+		w := new(mocksTmpl.Watcher)
+		w.On("Recaller", tmpl).Return(nil)
+		w.On("Complete", tmpl).Return(true)
+
+		_, err := newTerraform(tmpl, w).RenderTask(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("template not initialized", func(t *testing.T) {
+		tf := newTerraform(nil, new(mocksTmpl.Watcher))
+		tf.template = nil
+		_, err := tf.RenderTask(ctx)
+		assert.Error(t, err)
+	})
+}
+
+func TestReinitTask(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	newTerraform := func(t *testing.T, c *mocks.Client, w *mocksTmpl.Watcher) *Terraform {
+		dirName := "reinit-task-test-" + strings.ReplaceAll(t.Name(), "/", "-")
+		deleteTemp := testutils.MakeTempDir(t, dirName)
+		t.Cleanup(func() { deleteTemp() })
+
+		return &Terraform{
+			task: &Task{name: "task", enabled: true, workingDir: dirName,
+				logger: logging.NewNullLogger()},
+			client:     c,
+			fileReader: func(string) ([]byte, error) { return []byte{}, nil },
+			watcher:    w,
+			logger:     logging.NewNullLogger(),
+			// workspace was initialized when the task was created
+			inited: true,
+		}
+	}
+
+	t.Run("reinitialized", func(t *testing.T) {
+		c := new(mocks.Client)
+		c.On("Init", ctx).Return(nil).Once()
+		c.On("Validate", ctx).Return(nil).Once()
+		w := new(mocksTmpl.Watcher)
+		w.On("Clients").Return(nil).Once()
+		w.On("Register", mock.Anything).Return(nil).Once()
+		w.On("Recaller", mock.Anything).Return(nil)
+		w.On("Complete", mock.Anything).Return(false)
+
+		tf := newTerraform(t, c, w)
+		result, err := tf.ReinitTask(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, RenderResult{}, result,
+			"render should not complete before the data is fetched")
+		assert.True(t, tf.inited)
+		c.AssertExpectations(t)
+		w.AssertExpectations(t)
+	})
+
+	t.Run("validate error", func(t *testing.T) {
+		c := new(mocks.Client)
+		c.On("Init", ctx).Return(nil).Once()
+		c.On("Validate", ctx).Return(errors.New("error on validate()")).Once()
+		w := new(mocksTmpl.Watcher)
+		w.On("Clients").Return(nil).Once()
+		w.On("Register", mock.Anything).Return(nil).Once()
+
+		_, err := newTerraform(t, c, w).ReinitTask(ctx)
+		assert.Error(t, err)
+		w.AssertNotCalled(t, "Recaller", mock.Anything)
+	})
+
+	t.Run("init error", func(t *testing.T) {
+		c := new(mocks.Client)
+		c.On("Init", ctx).Return(errors.New("error on init()")).Once()
+		w := new(mocksTmpl.Watcher)
+		w.On("Clients").Return(nil).Once()
+		w.On("Register", mock.Anything).Return(nil).Once()
+
+		_, err := newTerraform(t, c, w).ReinitTask(ctx)
+		assert.Error(t, err)
+		c.AssertNotCalled(t, "Validate", mock.Anything)
+		w.AssertNotCalled(t, "Recaller", mock.Anything)
+	})
+}
+
+func TestRenderTemplate_Unchanged(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	applied := hcat.ResolveEvent{Complete: true, Contents: []byte("services = {}")}
+	changed := hcat.ResolveEvent{Complete: true, Contents: []byte("services = {web}")}
+
+	newTerraform := func(results ...hcat.ResolveEvent) (*Terraform, *mocksTmpl.Template) {
+		r := new(mocksTmpl.Resolver)
+		for _, result := range results {
+			r.On("Run", mock.Anything, mock.Anything).Return(result, nil).Once()
+		}
+		tmpl := new(mocksTmpl.Template)
+		tmpl.On("Render", mock.Anything).Return(hcat.RenderResult{}, nil)
+
+		tf := &Terraform{
+			task:     &Task{name: "task", enabled: true, logger: logging.NewNullLogger()},
+			resolver: r,
+			watcher:  new(mocksTmpl.Watcher),
+			logger:   logging.NewNullLogger(),
+		}
+		tf.setNotifier(tmpl)
+		return tf, tmpl
+	}
+
+	t.Run("identical data after applied", func(t *testing.T) {
+		tf, tmpl := newTerraform(applied, applied)
+
+		rendered, err := tf.RenderTemplate(ctx)
+		require.NoError(t, err)
+		require.True(t, rendered)
+		tf.setApplied()
+
+		// refetched data is identical, e.g. after reconnecting to Consul
+		rendered, err = tf.RenderTemplate(ctx)
+		require.NoError(t, err)
+		assert.False(t, rendered)
+		tmpl.AssertNumberOfCalls(t, "Render", 1)
+	})
+
+	t.Run("changed data after applied", func(t *testing.T) {
+		tf, tmpl := newTerraform(applied, changed)
+
+		_, err := tf.RenderTemplate(ctx)
+		require.NoError(t, err)
+		tf.setApplied()
+
+		rendered, err := tf.RenderTemplate(ctx)
+		require.NoError(t, err)
+		assert.True(t, rendered)
+		tmpl.AssertNumberOfCalls(t, "Render", 2)
+	})
+
+	t.Run("identical data not applied", func(t *testing.T) {
+		// the previous run failed, so identical data runs the task again
+		tf, tmpl := newTerraform(applied, applied)
+
+		_, err := tf.RenderTemplate(ctx)
+		require.NoError(t, err)
+
+		rendered, err := tf.RenderTemplate(ctx)
+		require.NoError(t, err)
+		assert.True(t, rendered)
+		tmpl.AssertNumberOfCalls(t, "Render", 2)
+	})
+}
+
+func TestSetNotifier_TriggerOnAnyInputChange(t *testing.T) {
+	t.Parallel()
+
+	condition := &config.ConsulKVConditionConfig{
+		ConsulKVMonitorConfig: config.ConsulKVMonitorConfig{
+			Path: config.String("key"),
+		},
+	}
+	services := []*dep.HealthService{{Name: "api"}}
+
+	cases := []struct {
+		name     string
+		anyInput bool
+		expected bool
+	}{
+		{"condition only", false, false},
+		{"any input change", true, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl := new(mocksTmpl.Template)
+			tmpl.On("Notify", mock.Anything).Return(true)
+
+			tf := &Terraform{
+				task: &Task{name: "task", condition: condition,
+					anyInput: tc.anyInput, logger: logging.NewNullLogger()},
+				logger: logging.NewNullLogger(),
+			}
+			require.NoError(t, tf.setNotifier(tmpl))
+			tf.onceNotifier.SetOnceDone()
+
+			// module input data
+			assert.Equal(t, tc.expected, tf.onceNotifier.Notify(services))
+			// condition data
+			assert.True(t, tf.onceNotifier.Notify(&dep.KeyPair{Key: "key"}))
+		})
+	}
+}
+
 func TestTerraform_Version(t *testing.T) {
 	var err error
 	TerraformVersion, err = goVersion.NewVersion("1.2")
@@ -197,13 +543,17 @@ func TestApplyTask(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			c := new(mocks.Client)
+			c.On("SetStdout", mock.Anything)
+			c.On("SetProgress", mock.Anything)
 			c.On("Apply", ctx).Return(tc.applyReturn).Once()
 
 			tf := &Terraform{
-				task:      &Task{name: "ApplyTaskTest", enabled: true, logger: logging.NewNullLogger()},
-				client:    c,
-				postApply: tc.postApply,
-				logger:    logging.NewNullLogger(),
+				task: &Task{name: "ApplyTaskTest", enabled: true,
+					workingDir: t.TempDir(), logger: logging.NewNullLogger()},
+				client:     c,
+				fileReader: os.ReadFile,
+				postApply:  tc.postApply,
+				logger:     logging.NewNullLogger(),
 			}
 
 			err := tf.ApplyTask(ctx)
@@ -216,6 +566,214 @@ func TestApplyTask(t *testing.T) {
 	}
 }
 
+func TestApplyTask_RunMetadata(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	wd := t.TempDir()
+	manifest := filepath.Join(wd, modulesManifestPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(manifest), 0755))
+	require.NoError(t, os.WriteFile(manifest, []byte(`{"Modules":[`+
+		`{"Key":"","Source":"","Dir":"."},`+
+		`{"Key":"task","Source":"registry.terraform.io/org/mod/local","Version":"0.2.1"}]}`), 0644))
+
+	var stdout io.Writer
+	c := new(mocks.Client)
+	c.On("SetStdout", mock.Anything).Run(func(args mock.Arguments) {
+		stdout = args.Get(0).(io.Writer)
+	})
+	c.On("SetProgress", mock.Anything)
+	c.On("Apply", ctx).Run(func(mock.Arguments) {
+		fmt.Fprintln(stdout, "Apply complete! Resources: 1 added, 2 changed, 3 destroyed.")
+	}).Return(nil).Once()
+
+	task := &Task{name: "task", enabled: true, workingDir: wd,
+		logger: logging.NewNullLogger()}
+	tf := &Terraform{
+		task:       task,
+		client:     c,
+		fileReader: os.ReadFile,
+		logger:     logging.NewNullLogger(),
+	}
+
+	require.NoError(t, tf.ApplyTask(ctx))
+
+	run := task.LastRun()
+	require.NotNil(t, run)
+	assert.Equal(t, &ChangeSummary{Add: 1, Change: 2, Destroy: 3}, run.Changes)
+	assert.Equal(t, "0.2.1", run.ModuleVersion)
+	assert.True(t, run.ApplyDuration > 0)
+}
+
+func TestApplyTask_Progress(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// uiLine returns a message of Terraform's machine-readable UI for a
+	// resource
+	uiLine := func(msgType, action string) string {
+		return fmt.Sprintf(`{"@level":"info","@message":"module.task.local_file.a",`+
+			`"type":%q,"hook":{"resource":{"addr":"module.task.local_file.a"},`+
+			`"action":%q}}`, msgType, action)
+	}
+	summary := `{"@level":"info","@message":"Apply complete! Resources: 1 added, 0 changed, 0 destroyed.",` +
+		`"type":"change_summary","changes":{"add":1,"change":0,"remove":0,"operation":"apply"}}`
+
+	t.Run("apply", func(t *testing.T) {
+		task := &Task{name: "task", enabled: true, workingDir: t.TempDir(),
+			logger: logging.NewNullLogger()}
+
+		_, ok := task.Progress()
+		assert.False(t, ok)
+
+		var progressW io.Writer
+		c := new(mocks.Client)
+		c.On("SetStdout", mock.Anything)
+		c.On("SetProgress", mock.Anything).Run(func(args mock.Arguments) {
+			if w, ok := args.Get(0).(io.Writer); ok {
+				progressW = w
+			}
+		})
+		c.On("Apply", ctx).Run(func(mock.Arguments) {
+			fmt.Fprintln(progressW, uiLine("apply_start", "create"))
+
+			// The progress of the in-flight run is tracked
+			progress, ok := task.Progress()
+			require.True(t, ok)
+			assert.True(t, progress.Active)
+			require.Len(t, progress.Resources, 1)
+			assert.Equal(t, ResourceStatusInProgress, progress.Resources[0].Status)
+
+			fmt.Fprintln(progressW, uiLine("apply_complete", "create"))
+			fmt.Fprintln(progressW, summary)
+		}).Return(nil).Once()
+
+		tf := &Terraform{
+			task:       task,
+			client:     c,
+			fileReader: os.ReadFile,
+			logger:     logging.NewNullLogger(),
+		}
+		require.NoError(t, tf.ApplyTask(ctx))
+
+		progress, ok := task.Progress()
+		require.True(t, ok)
+		assert.Equal(t, "task", progress.TaskName)
+		assert.False(t, progress.Active)
+		require.Len(t, progress.Resources, 1)
+		assert.Equal(t, "module.task.local_file.a", progress.Resources[0].Address)
+		assert.Equal(t, ResourceActionCreate, progress.Resources[0].Action)
+		assert.Equal(t, ResourceStatusComplete, progress.Resources[0].Status)
+
+		// The change summary of the machine-readable UI is recorded
+		run := task.LastRun()
+		require.NotNil(t, run)
+		assert.Equal(t, &ChangeSummary{Add: 1}, run.Changes)
+
+		// The progress writer is unset after the run
+		c.AssertCalled(t, "SetProgress", nil)
+	})
+
+	t.Run("saved plan", func(t *testing.T) {
+		task := &Task{name: "task", enabled: true, workingDir: t.TempDir(),
+			savePlan: true, logger: logging.NewNullLogger()}
+		planFile, _ := task.PlanFiles()
+
+		var progressW io.Writer
+		c := new(mocks.Client)
+		c.On("SetStdout", mock.Anything)
+		c.On("SetProgress", mock.Anything).Run(func(args mock.Arguments) {
+			if w, ok := args.Get(0).(io.Writer); ok {
+				progressW = w
+			}
+		})
+		c.On("SavePlan", ctx, planFile).Run(func(mock.Arguments) {
+			fmt.Fprintln(progressW, uiLine("refresh_start", ""))
+
+			// The plan is tracked before the saved plan is applied
+			progress, ok := task.Progress()
+			require.True(t, ok)
+			require.Len(t, progress.Resources, 1)
+			assert.Equal(t, ResourceActionRefresh, progress.Resources[0].Action)
+			assert.Equal(t, ResourceStatusInProgress, progress.Resources[0].Status)
+
+			fmt.Fprintln(progressW, uiLine("refresh_complete", ""))
+		}).Return([]byte(`{}`), nil).Once()
+		c.On("ApplyPlan", ctx, planFile).Run(func(mock.Arguments) {
+			fmt.Fprintln(progressW, uiLine("apply_start", "update"))
+			fmt.Fprintln(progressW, uiLine("apply_errored", "update"))
+		}).Return(errors.New("apply error")).Once()
+
+		tf := &Terraform{
+			task:       task,
+			client:     c,
+			fileReader: os.ReadFile,
+			logger:     logging.NewNullLogger(),
+		}
+		require.Error(t, tf.ApplyTask(ctx))
+
+		progress, ok := task.Progress()
+		require.True(t, ok)
+		assert.False(t, progress.Active)
+		require.Len(t, progress.Resources, 1)
+		assert.Equal(t, ResourceActionUpdate, progress.Resources[0].Action)
+		assert.Equal(t, ResourceStatusErrored, progress.Resources[0].Status)
+		assert.Nil(t, task.LastRun().Changes)
+	})
+}
+
+func TestApplyTask_SavePlan(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	wd := t.TempDir()
+	task := &Task{name: "ApplyTaskSavePlanTest", enabled: true, savePlan: true,
+		workingDir: wd, logger: logging.NewNullLogger()}
+	planFile, jsonFile := task.PlanFiles()
+
+	t.Run("happy path", func(t *testing.T) {
+		c := new(mocks.Client)
+		c.On("SetStdout", mock.Anything)
+		c.On("SetProgress", mock.Anything)
+		c.On("SavePlan", ctx, planFile).Return([]byte(`{"format_version":"1.1"}`), nil).Once()
+		c.On("ApplyPlan", ctx, planFile).Return(nil).Once()
+
+		tf := &Terraform{
+			task:       task,
+			client:     c,
+			fileReader: os.ReadFile,
+			logger:     logging.NewNullLogger(),
+		}
+
+		err := tf.ApplyTask(ctx)
+		require.NoError(t, err)
+		c.AssertExpectations(t)
+		c.AssertNotCalled(t, "Apply", mock.Anything)
+
+		content, err := os.ReadFile(jsonFile)
+		require.NoError(t, err)
+		assert.Equal(t, `{"format_version":"1.1"}`, string(content))
+	})
+
+	t.Run("error on plan", func(t *testing.T) {
+		c := new(mocks.Client)
+		c.On("SetStdout", mock.Anything)
+		c.On("SetProgress", mock.Anything)
+		c.On("SavePlan", ctx, planFile).Return(nil, errors.New("plan error")).Once()
+
+		tf := &Terraform{
+			task:   task,
+			client: c,
+			logger: logging.NewNullLogger(),
+		}
+
+		err := tf.ApplyTask(ctx)
+		assert.Error(t, err)
+		c.AssertNotCalled(t, "ApplyPlan", mock.Anything, mock.Anything)
+	})
+}
+
 func TestUpdateTask(t *testing.T) {
 	t.Parallel()
 
@@ -310,6 +868,8 @@ func TestUpdateTask(t *testing.T) {
 				c.On("SetStdout", mock.Anything).Twice()
 			}
 			if tc.callApply {
+				c.On("SetStdout", mock.Anything).Twice()
+				c.On("SetProgress", mock.Anything)
 				c.On("Apply", ctx).Return(nil).Once()
 			}
 
@@ -419,6 +979,7 @@ func TestUpdateTask(t *testing.T) {
 			c.On("Validate", ctx).Return(nil).Once()
 			c.On("Plan", ctx).Return(true, tc.planErr).Once()
 			c.On("SetStdout", mock.Anything).Twice()
+			c.On("SetProgress", mock.Anything)
 			c.On("Apply", ctx).Return(tc.applyErr).Once()
 
 			w := new(mocksTmpl.Watcher)
@@ -654,12 +1215,14 @@ func TestGetTerraformHandlers(t *testing.T) {
 		expectError bool
 		nilHandler  bool
 		providers   TerraformProviderBlocks
+		handlers    config.HandlerConfigs
 	}{
 		{
 			"no provider",
 			false,
 			true,
 			TerraformProviderBlocks{},
+			nil,
 		},
 		{
 			"provider without handler (no error)",
@@ -672,6 +1235,7 @@ func TestGetTerraformHandlers(t *testing.T) {
 					},
 				})},
 			),
+			nil,
 		},
 		{
 			"provider without handler (no error)",
@@ -682,6 +1246,7 @@ func TestGetTerraformHandlers(t *testing.T) {
 					"provider-no-handler": map[string]interface{}{},
 				})},
 			),
+			nil,
 		},
 		{
 			"happy path - provider with handler",
@@ -694,12 +1259,37 @@ func TestGetTerraformHandlers(t *testing.T) {
 					},
 				})},
 			),
+			nil,
+		},
+		{
+			"happy path - exec handler",
+			false,
+			false,
+			TerraformProviderBlocks{},
+			config.HandlerConfigs{
+				{Exec: &config.ExecHandlerConfig{
+					Command:       config.String("./notify.sh"),
+					Timeout:       config.TimeDuration(time.Minute),
+					FailurePolicy: config.String(config.HandlerFailurePolicyFail),
+				}},
+			},
+		},
+		{
+			"invalid exec handler",
+			true,
+			true,
+			TerraformProviderBlocks{},
+			config.HandlerConfigs{
+				{Exec: &config.ExecHandlerConfig{Command: config.String("./notify.sh")}},
+			},
 		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			h, err := getTerraformHandlers(tc.name, tc.providers)
+			task := &Task{name: tc.name, providers: tc.providers,
+				handlers: tc.handlers, logger: logging.NewNullLogger()}
+			h, err := getTerraformHandlers(task)
 			if tc.expectError {
 				assert.Error(t, err)
 				return
@@ -762,6 +1352,49 @@ func TestDisabledTask(t *testing.T) {
 	})
 }
 
+func TestRenderOnlyTask(t *testing.T) {
+	t.Run("render-only-tasks", func(t *testing.T) {
+		// tests that render-only tasks do not run any Terraform commands
+
+		ctx := context.Background()
+		c := new(mocks.Client)
+
+		w := new(mocksTmpl.Watcher)
+		w.On("Register", mock.Anything).Return(nil).Once()
+		w.On("Clients").Return(nil).Once()
+		w.On("Deregister", mock.Anything).Return().Once()
+
+		dirName := "render-only-task-test"
+		deleteTemp := testutils.MakeTempDir(t, dirName)
+		defer deleteTemp()
+
+		tf := &Terraform{
+			task: &Task{name: "render_only_task", enabled: true, renderOnly: true,
+				workingDir: dirName, logger: logging.NewNullLogger()},
+			client:     c,
+			fileReader: func(string) ([]byte, error) { return []byte{}, nil },
+			watcher:    w,
+			logger:     logging.NewNullLogger(),
+			postApply:  testHandler(true),
+		}
+
+		err := tf.InitTask(ctx)
+		assert.NoError(t, err)
+
+		plan, err := tf.InspectTask(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, InspectPlan{
+			Plan: "Task is render-only, inspection was skipped.",
+		}, plan)
+
+		err = tf.ApplyTask(ctx)
+		assert.NoError(t, err)
+
+		c.AssertExpectations(t)
+		w.AssertExpectations(t)
+	})
+}
+
 func TestInitTask(t *testing.T) {
 	t.Parallel()
 
@@ -841,6 +1474,64 @@ func TestInitTask(t *testing.T) {
 	}
 }
 
+func TestInitExtraTemplates(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no extra templates", func(t *testing.T) {
+		tf := &Terraform{
+			task:    &Task{name: "test", enabled: true},
+			watcher: new(mocksTmpl.Watcher),
+			logger:  logging.NewNullLogger(),
+		}
+		assert.NoError(t, tf.initExtraTemplates())
+		assert.Empty(t, tf.extraTemplates)
+	})
+
+	t.Run("error on reading file", func(t *testing.T) {
+		w := new(mocksTmpl.Watcher)
+		tf := &Terraform{
+			fileReader: func(string) ([]byte, error) {
+				return nil, errors.New("error reading file")
+			},
+			task: &Task{name: "test", enabled: true,
+				extraTmpls: []string{"lb.json.tpl"}},
+			watcher: w,
+			logger:  logging.NewNullLogger(),
+		}
+		assert.Error(t, tf.initExtraTemplates())
+		assert.Empty(t, tf.extraTemplates)
+	})
+
+	t.Run("happy path", func(t *testing.T) {
+		var read []string
+		w := new(mocksTmpl.Watcher)
+		w.On("Register", mock.Anything).Return(nil).Twice()
+		w.On("Clients").Return(nil).Twice()
+		tf := &Terraform{
+			fileReader: func(path string) ([]byte, error) {
+				read = append(read, path)
+				return []byte(path), nil
+			},
+			task: &Task{name: "test", enabled: true,
+				extraTmpls: []string{"lb.json.tpl", "haproxy.cfg.tmpl"}},
+			watcher: w,
+			logger:  logging.NewNullLogger(),
+		}
+		require.NoError(t, tf.initExtraTemplates())
+		assert.Equal(t, []string{"lb.json.tpl", "haproxy.cfg.tmpl"}, read)
+		assert.Len(t, tf.extraTemplates, 2)
+		w.AssertExpectations(t)
+
+		// re-initializing deregisters the previous extra templates
+		w.On("Deregister", mock.Anything).Return().Twice()
+		w.On("Register", mock.Anything).Return(nil).Twice()
+		w.On("Clients").Return(nil).Twice()
+		require.NoError(t, tf.initExtraTemplates())
+		assert.Len(t, tf.extraTemplates, 2)
+		w.AssertExpectations(t)
+	})
+}
+
 func TestTerraform_DestroyTask(t *testing.T) {
 	var w mocksTmpl.Watcher
 	tf := Terraform{
@@ -968,3 +1659,324 @@ func testHandler(err bool) handler.Handler {
 	h, _ := handler.NewFake(c)
 	return h
 }
+
+func TestApplyTask_ServicesChanged(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	tfvars := filepath.Join(dir, tftmpl.TFVarsFilename)
+	changed := filepath.Join(dir, tftmpl.ServicesChangedFilename)
+
+	c := new(mocks.Client)
+	c.On("SetStdout", mock.Anything)
+	c.On("SetProgress", mock.Anything)
+	c.On("Apply", ctx).Return(nil)
+
+	tf := &Terraform{
+		task: &Task{name: "task", enabled: true, servicesDiff: true,
+			workingDir: dir, logger: logging.NewNullLogger()},
+		client:     c,
+		fileReader: os.ReadFile,
+		logger:     logging.NewNullLogger(),
+	}
+
+	// First run, all services are added
+	require.NoError(t, os.WriteFile(tfvars, []byte(`services = {
+  "web" = { id = "web" }
+}
+`), 0640))
+	require.NoError(t, tf.ApplyTask(ctx))
+
+	assertChanged := func(changeType string) {
+		content, err := os.ReadFile(changed)
+		require.NoError(t, err)
+		vars, err := tftmpl.ParseModuleVariables(content, changed)
+		require.NoError(t, err)
+		for _, ct := range []string{"added", "modified", "removed"} {
+			val := vars["services_changed"].GetAttr(ct)
+			assert.Equal(t, ct == changeType, val.Type().HasAttribute("web"), ct)
+		}
+	}
+	assertChanged("added")
+
+	// Second run, the service is removed since the last successful run
+	require.NoError(t, os.WriteFile(tfvars, []byte("services = {}\n"), 0640))
+	require.NoError(t, tf.ApplyTask(ctx))
+
+	assertChanged("removed")
+
+	c.AssertExpectations(t)
+}
+
+func TestApplyTask_TargetedApply(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	tfvars := filepath.Join(dir, tftmpl.TFVarsFilename)
+
+	c := new(mocks.Client)
+	c.On("SetStdout", mock.Anything)
+	c.On("SetProgress", mock.Anything)
+	c.On("Apply", ctx).Return(nil)
+	c.On("SetTargets", []string{"module.web"}).Return().Once()
+	c.On("SetTargets", []string{"module.api", "module.web"}).Return().Once()
+	c.On("SetTargets", []string(nil)).Return().Twice()
+
+	tf := &Terraform{
+		task: &Task{name: "task", enabled: true, targeted: true,
+			applyTargets: map[string][]string{
+				"web": {"module.web"},
+				"api": {"module.api", "module.web"},
+			},
+			workingDir: dir, logger: logging.NewNullLogger()},
+		client:     c,
+		fileReader: os.ReadFile,
+		logger:     logging.NewNullLogger(),
+	}
+
+	writeServices := func(services ...string) {
+		content := "services = {\n"
+		for _, s := range services {
+			content += fmt.Sprintf("  %q = { id = %q, name = %q }\n", s, s, s)
+		}
+		content += "}\n"
+		require.NoError(t, os.WriteFile(tfvars, []byte(content), 0640))
+	}
+
+	// First run, no previous successful run so all resources are applied
+	writeServices("web")
+	require.NoError(t, tf.ApplyTask(ctx))
+	c.AssertNotCalled(t, "SetTargets", mock.Anything)
+
+	// No changes, all resources are applied
+	require.NoError(t, tf.ApplyTask(ctx))
+	c.AssertNotCalled(t, "SetTargets", mock.Anything)
+
+	// Web is removed, targets the web resources
+	writeServices()
+	require.NoError(t, tf.ApplyTask(ctx))
+
+	// Web and api are added, targets the deduplicated resources of both
+	writeServices("api", "web")
+	require.NoError(t, tf.ApplyTask(ctx))
+
+	// Unmapped service is added, all resources are applied
+	writeServices("api", "db", "web")
+	require.NoError(t, tf.ApplyTask(ctx))
+
+	c.AssertExpectations(t)
+}
+
+func TestApplyTask_Postconditions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	postconds := config.PostconditionConfigs{
+		{
+			Output:   config.String("members_count"),
+			Operator: config.String(">="),
+			Value:    config.String("1"),
+		},
+	}
+
+	cases := []struct {
+		name        string
+		outputs     map[string]json.RawMessage
+		outputsErr  error
+		expectError bool
+	}{
+		{
+			"postconditions hold",
+			map[string]json.RawMessage{"members_count": json.RawMessage(`2`)},
+			nil,
+			false,
+		},
+		{
+			"postcondition fails",
+			map[string]json.RawMessage{"members_count": json.RawMessage(`0`)},
+			nil,
+			true,
+		},
+		{
+			"error reading outputs",
+			nil,
+			errors.New("output error"),
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := new(mocks.Client)
+			c.On("SetStdout", mock.Anything)
+			c.On("SetProgress", mock.Anything)
+			c.On("Apply", ctx).Return(nil).Once()
+			c.On("Outputs", ctx).Return(tc.outputs, tc.outputsErr).Once()
+
+			tf := &Terraform{
+				task: &Task{name: "task", enabled: true, postconds: postconds,
+					workingDir: t.TempDir(), logger: logging.NewNullLogger()},
+				client:     c,
+				fileReader: os.ReadFile,
+				logger:     logging.NewNullLogger(),
+			}
+
+			err := tf.ApplyTask(ctx)
+			if tc.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			c.AssertExpectations(t)
+		})
+	}
+}
+
+func TestApplyTask_PublishOutputs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	outputs := map[string]json.RawMessage{
+		"id":    json.RawMessage(`"lb-123"`),
+		"ports": json.RawMessage(`[80, 443]`),
+	}
+
+	var mu sync.Mutex
+	written := make(map[string]string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the client set waits for a Consul leader
+		if r.URL.Path == "/v1/status/leader" {
+			fmt.Fprint(w, `"127.0.0.1:8300"`)
+			return
+		}
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		mu.Lock()
+		written[r.URL.Path] = string(b)
+		mu.Unlock()
+		fmt.Fprint(w, "true")
+	}))
+	defer srv.Close()
+
+	clients := hcat.NewClientSet()
+	require.NoError(t, clients.AddConsul(hcat.ConsulInput{
+		Address: srv.Listener.Addr().String(),
+	}))
+	w := new(mocksTmpl.Watcher)
+	w.On("Clients").Return(clients)
+
+	t.Run("happy path", func(t *testing.T) {
+		c := new(mocks.Client)
+		c.On("SetStdout", mock.Anything)
+		c.On("SetProgress", mock.Anything)
+		c.On("Apply", ctx).Return(nil).Once()
+		c.On("Outputs", ctx).Return(outputs, nil).Once()
+
+		tf := &Terraform{
+			task: &Task{name: "task", enabled: true, workingDir: t.TempDir(),
+				publish: &PublishOutputs{Path: "cts/outputs/task"},
+				logger:  logging.NewNullLogger()},
+			client:     c,
+			watcher:    w,
+			fileReader: os.ReadFile,
+			logger:     logging.NewNullLogger(),
+		}
+
+		err := tf.ApplyTask(ctx)
+		require.NoError(t, err)
+		c.AssertExpectations(t)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, map[string]string{
+			"/v1/kv/cts/outputs/task/id":    "lb-123",
+			"/v1/kv/cts/outputs/task/ports": "[80,443]",
+		}, written)
+	})
+
+	t.Run("missing output", func(t *testing.T) {
+		c := new(mocks.Client)
+		c.On("SetStdout", mock.Anything)
+		c.On("SetProgress", mock.Anything)
+		c.On("Apply", ctx).Return(nil).Once()
+		c.On("Outputs", ctx).Return(outputs, nil).Once()
+
+		tf := &Terraform{
+			task: &Task{name: "task", enabled: true, workingDir: t.TempDir(),
+				publish: &PublishOutputs{Path: "cts", Outputs: []string{"arn"}},
+				logger:  logging.NewNullLogger()},
+			client:     c,
+			watcher:    w,
+			fileReader: os.ReadFile,
+			logger:     logging.NewNullLogger(),
+		}
+
+		err := tf.ApplyTask(ctx)
+		assert.Error(t, err)
+	})
+}
+
+func TestRenderChanged(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	tfvars := filepath.Join(dir, tftmpl.TFVarsFilename)
+
+	c := new(mocks.Client)
+	c.On("SetStdout", mock.Anything)
+	c.On("SetProgress", mock.Anything)
+	c.On("Apply", ctx).Return(nil)
+
+	tf := &Terraform{
+		task: &Task{name: "task", enabled: true, workingDir: dir,
+			logger: logging.NewNullLogger()},
+		client:     c,
+		fileReader: os.ReadFile,
+		logger:     logging.NewNullLogger(),
+	}
+
+	// Task has not run successfully
+	require.NoError(t, os.WriteFile(tfvars, []byte("services = {}\n"), 0640))
+	changed, err := tf.RenderChanged()
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	// No changes since the last successful run
+	require.NoError(t, tf.ApplyTask(ctx))
+	changed, err = tf.RenderChanged()
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	// Rendered variables changed
+	require.NoError(t, os.WriteFile(tfvars, []byte(`services = {
+  "web" = { id = "web" }
+}
+`), 0640))
+	changed, err = tf.RenderChanged()
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	// Generated root module changed
+	require.NoError(t, tf.ApplyTask(ctx))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, tftmpl.VarsTFVarsFileName),
+		[]byte("count = 2\n"), 0640))
+	changed, err = tf.RenderChanged()
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	t.Run("file reader error", func(t *testing.T) {
+		tf := &Terraform{
+			task: &Task{name: "task", workingDir: dir,
+				logger: logging.NewNullLogger()},
+			fileReader: func(string) ([]byte, error) {
+				return nil, errors.New("read error")
+			},
+			logger: logging.NewNullLogger(),
+		}
+		_, err := tf.RenderChanged()
+		assert.Error(t, err)
+	})
+}
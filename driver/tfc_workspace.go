@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// WorkspaceVariable is a Terraform variable of the Terraform Cloud workspace
+// of a task. Enterprise only.
+type WorkspaceVariable struct {
+	Key   string
+	Value string
+
+	// HCL is whether the value is an HCL expression. String values are set
+	// as is and all other values are encoded as HCL.
+	HCL bool
+
+	// Sensitive is whether the value is write-only in Terraform Cloud
+	Sensitive bool
+}
+
+// WorkspaceVariablesPlan are the changes to apply to the variables of a
+// Terraform Cloud workspace so that they match the variables of the task.
+// Enterprise only.
+type WorkspaceVariablesPlan struct {
+	Create []WorkspaceVariable
+	Update []WorkspaceVariable
+}
+
+// IsEmpty returns whether there are no changes to the workspace variables
+func (p WorkspaceVariablesPlan) IsEmpty() bool {
+	return len(p.Create) == 0 && len(p.Update) == 0
+}
+
+// WorkspaceVariables returns the variables of the task to create or update
+// for the Terraform Cloud workspace of the task when the task is initialized,
+// sorted by key. Enterprise only.
+func (t *Task) WorkspaceVariables() []WorkspaceVariable {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	sensitive := make(map[string]bool, len(t.sensitive))
+	for _, name := range t.sensitive {
+		sensitive[name] = true
+	}
+
+	vars := make([]WorkspaceVariable, 0, len(t.variables))
+	for _, key := range t.variables.Keys() {
+		v := workspaceVariable(key, t.variables[key])
+		v.Sensitive = sensitive[key]
+		vars = append(vars, v)
+	}
+	return vars
+}
+
+// PlanWorkspaceVariables compares the existing variables of a Terraform
+// Cloud workspace with the variables of the task and returns the variables
+// to create and update. Sensitive variables are always updated because their
+// existing values cannot be read. Existing variables that are not variables
+// of the task, e.g. set manually or by variable sets, are left unchanged.
+func PlanWorkspaceVariables(existing, desired []WorkspaceVariable) WorkspaceVariablesPlan {
+	current := make(map[string]WorkspaceVariable, len(existing))
+	for _, v := range existing {
+		current[v.Key] = v
+	}
+
+	var plan WorkspaceVariablesPlan
+	for _, v := range desired {
+		c, ok := current[v.Key]
+		switch {
+		case !ok:
+			plan.Create = append(plan.Create, v)
+		case v.Sensitive || c.Sensitive || c != v:
+			plan.Update = append(plan.Update, v)
+		}
+	}
+
+	sort.Slice(plan.Create, func(i, j int) bool {
+		return plan.Create[i].Key < plan.Create[j].Key
+	})
+	sort.Slice(plan.Update, func(i, j int) bool {
+		return plan.Update[i].Key < plan.Update[j].Key
+	})
+	return plan
+}
+
+// workspaceVariable encodes the value of a task variable for a Terraform
+// Cloud workspace
+func workspaceVariable(key string, value cty.Value) WorkspaceVariable {
+	if value.Type() == cty.String && value.IsKnown() && !value.IsNull() {
+		return WorkspaceVariable{Key: key, Value: value.AsString()}
+	}
+
+	tokens := hclwrite.TokensForValue(value)
+	return WorkspaceVariable{
+		Key:   key,
+		Value: strings.TrimSpace(string(hclwrite.Format(tokens.Bytes()))),
+		HCL:   true,
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/templates/hcltmpl"
+	"github.com/stretchr/testify/assert"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestTask_WorkspaceVariables(t *testing.T) {
+	t.Parallel()
+
+	task := Task{
+		variables: hcltmpl.Variables{
+			"region":   cty.StringVal("us-east-1"),
+			"port":     cty.NumberIntVal(8080),
+			"enabled":  cty.True,
+			"zones":    cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+			"password": cty.StringVal("secret"),
+		},
+		sensitive: []string{"password"},
+	}
+
+	expected := []WorkspaceVariable{
+		{Key: "enabled", Value: "true", HCL: true},
+		{Key: "password", Value: "secret", Sensitive: true},
+		{Key: "port", Value: "8080", HCL: true},
+		{Key: "region", Value: "us-east-1"},
+		{Key: "zones", Value: `["a", "b"]`, HCL: true},
+	}
+	assert.Equal(t, expected, task.WorkspaceVariables())
+
+	var empty Task
+	assert.Empty(t, empty.WorkspaceVariables())
+}
+
+func TestPlanWorkspaceVariables(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		existing []WorkspaceVariable
+		desired  []WorkspaceVariable
+		expected WorkspaceVariablesPlan
+	}{
+		{
+			"no variables",
+			nil,
+			nil,
+			WorkspaceVariablesPlan{},
+		},
+		{
+			"create",
+			nil,
+			[]WorkspaceVariable{{Key: "b", Value: "2"}, {Key: "a", Value: "1"}},
+			WorkspaceVariablesPlan{
+				Create: []WorkspaceVariable{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}},
+			},
+		},
+		{
+			"unchanged",
+			[]WorkspaceVariable{{Key: "a", Value: "1"}},
+			[]WorkspaceVariable{{Key: "a", Value: "1"}},
+			WorkspaceVariablesPlan{},
+		},
+		{
+			"update changed value and type",
+			[]WorkspaceVariable{{Key: "a", Value: "1"}, {Key: "b", Value: "[]", HCL: true}},
+			[]WorkspaceVariable{{Key: "a", Value: "2"}, {Key: "b", Value: "[]"}},
+			WorkspaceVariablesPlan{
+				Update: []WorkspaceVariable{{Key: "a", Value: "2"}, {Key: "b", Value: "[]"}},
+			},
+		},
+		{
+			"update sensitive",
+			[]WorkspaceVariable{{Key: "password", Sensitive: true}},
+			[]WorkspaceVariable{{Key: "password", Value: "secret", Sensitive: true}},
+			WorkspaceVariablesPlan{
+				Update: []WorkspaceVariable{{Key: "password", Value: "secret", Sensitive: true}},
+			},
+		},
+		{
+			"ignore variables not of the task",
+			[]WorkspaceVariable{{Key: "manual", Value: "1"}},
+			[]WorkspaceVariable{{Key: "a", Value: "1"}},
+			WorkspaceVariablesPlan{
+				Create: []WorkspaceVariable{{Key: "a", Value: "1"}},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			plan := PlanWorkspaceVariables(tc.existing, tc.desired)
+			assert.Equal(t, tc.expected, plan)
+			assert.Equal(t, len(tc.expected.Create)+len(tc.expected.Update) == 0,
+				plan.IsEmpty())
+		})
+	}
+}
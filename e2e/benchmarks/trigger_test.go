@@ -0,0 +1,230 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+//go:build e2e
+// +build e2e
+
+// Benchmarks for CTS running with a high number of tasks. The benchmarks
+// require the CTS binary and run the same setup as the e2e tests.
+//
+// $ go test ./e2e/benchmarks -tags=e2e -run=XXX -bench=. -benchtime=5x -timeout=30m
+// $ go test ./e2e/benchmarks -tags=e2e -run=XXX -bench=. -task-counts=500,1000
+package benchmarks
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/api"
+	"github.com/hashicorp/consul-terraform-sync/testutils"
+	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	tempDirPrefix = "tmp_"
+	configFile    = "config.hcl"
+	serviceName   = "bench"
+
+	// readiness and apply timeouts are scaled by the number of tasks
+	waitForReadinessPerTask = 2 * time.Second
+	waitForApplyPerTask     = 500 * time.Millisecond
+	pollInterval            = 100 * time.Millisecond
+)
+
+var taskCounts = flag.String("task-counts", "10,100,500",
+	"comma separated list of the number of tasks to benchmark")
+
+// BenchmarkTriggerToApply measures the latency from a change in Consul to
+// the completion of the triggered run for all of the tasks that monitor the
+// changed service. Each iteration registers a new service instance.
+func BenchmarkTriggerToApply(b *testing.B) {
+	counts, err := parseTaskCounts(*taskCounts)
+	require.NoError(b, err)
+
+	for _, count := range counts {
+		b.Run(fmt.Sprintf("tasks_%d", count), func(b *testing.B) {
+			benchmarkTriggerToApply(b, count)
+		})
+	}
+}
+
+func benchmarkTriggerToApply(b *testing.B, taskCount int) {
+	srv := testutils.NewTestConsulServer(b, testutils.TestConsulServerConfig{
+		HTTPSRelPath: "../../testutils",
+	})
+	b.Cleanup(func() { _ = srv.Stop() })
+
+	tempDir := filepath.Join(".", fmt.Sprintf("%strigger_%d", tempDirPrefix, taskCount))
+	cleanup := testutils.MakeTempDir(b, tempDir)
+	b.Cleanup(func() { _ = cleanup() })
+
+	cts := startCTS(b, srv, tempDir, taskCount)
+	err := cts.WaitForTestReadiness(waitForReadinessPerTask * time.Duration(taskCount))
+	require.NoError(b, err)
+
+	applyTimeout := waitForApplyPerTask * time.Duration(taskCount)
+	latencies := make([]time.Duration, 0, b.N)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		start := time.Now()
+		testutils.RegisterConsulService(b, srv, testutil.TestService{
+			ID:      fmt.Sprintf("%s-%d", serviceName, i),
+			Name:    serviceName,
+			Address: "10.0.0.1",
+		}, 0)
+
+		waitForAllTasks(b, cts, taskCount, start, applyTimeout)
+		latencies = append(latencies, time.Since(start))
+	}
+	b.StopTimer()
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	b.ReportMetric(float64(percentile(latencies, 50).Milliseconds()), "p50-ms")
+	b.ReportMetric(float64(percentile(latencies, 99).Milliseconds()), "p99-ms")
+	b.ReportMetric(float64(latencies[len(latencies)-1].Milliseconds()), "max-ms")
+}
+
+// waitForAllTasks polls the task status API until every task has an event
+// that started after the given time
+func waitForAllTasks(b *testing.B, cts *api.Client, taskCount int,
+	start time.Time, timeout time.Duration) {
+
+	deadline := time.Now().Add(timeout)
+	q := &api.QueryParam{IncludeEvents: true}
+	for time.Now().Before(deadline) {
+		statuses, err := cts.Status().Task("", q)
+		if err == nil && tasksRanSince(statuses, start) == taskCount {
+			return
+		}
+		time.Sleep(pollInterval)
+	}
+	b.Fatalf("timed out after %s waiting for %d tasks to run", timeout, taskCount)
+}
+
+// tasksRanSince returns the number of tasks with a successful event that
+// started after the given time
+func tasksRanSince(statuses map[string]api.TaskStatus, start time.Time) int {
+	count := 0
+	for _, status := range statuses {
+		if len(status.Events) == 0 {
+			continue
+		}
+		latest := status.Events[0]
+		if latest.Success && latest.StartTime.After(start) {
+			count++
+		}
+	}
+	return count
+}
+
+// startCTS writes the configuration with the number of tasks and starts CTS.
+// CTS is stopped when the benchmark completes.
+func startCTS(b *testing.B, srv *testutil.TestServer, tempDir string,
+	taskCount int) *api.Client {
+
+	module, err := filepath.Abs("../test_modules/local_instances_file")
+	require.NoError(b, err)
+	cwd, err := os.Getwd()
+	require.NoError(b, err)
+
+	port := testutils.FreePort(b)
+	var config strings.Builder
+	config.WriteString(fmt.Sprintf(`log_level = "INFO"
+working_dir = "%s"
+port = %d
+
+buffer_period {
+	enabled = false
+}
+
+consul {
+	address = "%s"
+	tls {
+		enabled = true
+		ca_cert = "%s"
+	}
+}
+
+driver "terraform" {
+	path = "%s"
+}
+
+terraform_provider "local" {}
+`, tempDir, port, srv.HTTPSAddr, srv.Config.CertFile, cwd))
+
+	for i := 0; i < taskCount; i++ {
+		config.WriteString(fmt.Sprintf(`
+task {
+	name = "bench_task_%d"
+	providers = ["local"]
+	module = "%s"
+	condition "services" {
+		names = ["%s"]
+	}
+}
+`, i, module, serviceName))
+	}
+
+	configPath := filepath.Join(tempDir, configFile)
+	testutils.WriteFile(b, configPath, config.String())
+
+	cmd := exec.Command("consul-terraform-sync", "start",
+		fmt.Sprintf("--config-file=%s", configPath))
+	var buf bytes.Buffer
+	cmd.Stdout = &buf
+	cmd.Stderr = &buf
+	require.NoError(b, cmd.Start())
+
+	b.Cleanup(func() {
+		if b.Failed() {
+			b.Logf("CTS logs:\n%s", buf.String())
+		}
+		_ = cmd.Process.Signal(os.Interrupt)
+		if err := cmd.Wait(); err != nil {
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) {
+				b.Logf("error stopping CTS: %s", err)
+			}
+		}
+	})
+
+	client, err := api.NewClient(&api.ClientConfig{
+		URL: fmt.Sprintf("http://localhost:%d", port),
+	}, nil)
+	require.NoError(b, err)
+	return client
+}
+
+// parseTaskCounts parses the comma separated list of task counts
+func parseTaskCounts(s string) ([]int, error) {
+	var counts []int
+	for _, v := range strings.Split(s, ",") {
+		count, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil || count < 1 {
+			return nil, fmt.Errorf("invalid task count %q", v)
+		}
+		counts = append(counts, count)
+	}
+	return counts, nil
+}
+
+// percentile returns the p-th percentile of the sorted durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	idx := (len(sorted)*p + 99) / 100
+	if idx > 0 {
+		idx--
+	}
+	return sorted[idx]
+}
@@ -0,0 +1,163 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/logging"
+)
+
+const (
+	execSubsystemName = "exec"
+
+	// execTaskNameEnv is the environment variable with the task name that is
+	// set for the program of an exec handler
+	execTaskNameEnv = "CTS_TASK_NAME"
+
+	// execWaitDelay is the time to wait for the output of the program to
+	// close after the program is killed on timeout, e.g. when a child process
+	// of the program is still running
+	execWaitDelay = time.Second
+)
+
+var _ Handler = (*Exec)(nil)
+
+// ExecConfig configures an exec handler
+type ExecConfig struct {
+	// Command is the path of the program to run. Relative paths are relative
+	// to the working directory.
+	Command string
+	Args    []string
+
+	// Timeout is the time limit for the program to complete
+	Timeout time.Duration
+
+	// IgnoreFailure logs the failure of the program instead of returning an
+	// error
+	IgnoreFailure bool
+
+	// WorkingDir is the directory to run the program in
+	WorkingDir string
+
+	// Input is the task context that is passed to the program on stdin
+	Input ExecInput
+}
+
+// ExecInput is the task context that is passed to the program of an exec
+// handler as JSON on stdin
+type ExecInput struct {
+	Task ExecTaskInput `json:"task"`
+}
+
+// ExecTaskInput is the information of the task that ran the exec handler
+type ExecTaskInput struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Module      string   `json:"module"`
+	Providers   []string `json:"providers"`
+	WorkingDir  string   `json:"working_dir"`
+}
+
+// Exec is a user-defined post-apply handler that runs a program with the task
+// context as JSON on stdin. The handler fails if the program exits with a
+// non-zero status or does not complete within the timeout.
+type Exec struct {
+	next          Handler
+	command       string
+	args          []string
+	timeout       time.Duration
+	ignoreFailure bool
+	workingDir    string
+	taskName      string
+	input         []byte
+	logger        logging.Logger
+}
+
+// NewExec configures and returns a new exec handler
+func NewExec(c ExecConfig) (*Exec, error) {
+	if c.Command == "" {
+		return nil, errors.New("exec handler: missing command")
+	}
+	if c.Timeout <= 0 {
+		return nil, errors.New("exec handler: timeout must be greater than 0")
+	}
+
+	input, err := json.Marshal(c.Input)
+	if err != nil {
+		return nil, fmt.Errorf("exec handler: unable to encode input: %s", err)
+	}
+
+	logger := logging.Global().Named(logSystemName).Named(execSubsystemName)
+	logger.Info("creating handler", "command", c.Command)
+
+	return &Exec{
+		command:       c.Command,
+		args:          c.Args,
+		timeout:       c.Timeout,
+		ignoreFailure: c.IgnoreFailure,
+		workingDir:    c.WorkingDir,
+		taskName:      c.Input.Task.Name,
+		input:         input,
+		logger:        logger,
+	}, nil
+}
+
+// Do executes the program of the exec handler. A failure of the program is
+// logged instead of returned if the handler is configured to ignore failures.
+func (h *Exec) Do(ctx context.Context, prevErr error) error {
+	err := h.run(ctx)
+	if err != nil && h.ignoreFailure {
+		h.logger.Warn("ignoring failure of exec handler", "task_name",
+			h.taskName, "command", h.command, "error", err)
+		err = nil
+	}
+
+	return callNext(ctx, h.next, prevErr, err)
+}
+
+// SetNext sets the next handler that should be called
+func (h *Exec) SetNext(next Handler) {
+	h.next = next
+}
+
+// run runs the program and waits for it to complete
+func (h *Exec) run(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, h.command, h.args...)
+	cmd.Dir = h.workingDir
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", execTaskNameEnv, h.taskName))
+	cmd.Stdin = bytes.NewReader(h.input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = execWaitDelay
+
+	h.logger.Debug("running exec handler", "task_name", h.taskName,
+		"command", h.command)
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("exec handler %q timed out after %s", h.command, h.timeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("exec handler %q failed: %s: %s", h.command, err, msg)
+		}
+		return fmt.Errorf("exec handler %q failed: %s", h.command, err)
+	}
+
+	h.logger.Debug("exec handler completed", "task_name", h.taskName,
+		"command", h.command, "output", strings.TrimSpace(stdout.String()))
+	return nil
+}
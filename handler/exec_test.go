@@ -0,0 +1,148 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewExec(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		expectError bool
+		config      ExecConfig
+	}{
+		{
+			"happy path",
+			false,
+			ExecConfig{Command: "./notify.sh", Timeout: time.Second},
+		},
+		{
+			"missing command",
+			true,
+			ExecConfig{Timeout: time.Second},
+		},
+		{
+			"missing timeout",
+			true,
+			ExecConfig{Command: "./notify.sh"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h, err := NewExec(tc.config)
+			if tc.expectError {
+				assert.Error(t, err)
+				assert.Nil(t, h)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, h)
+		})
+	}
+}
+
+func TestExec_Do(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	input := ExecInput{Task: ExecTaskInput{
+		Name:      "task",
+		Module:    "org/module",
+		Providers: []string{"local"},
+	}}
+
+	cases := []struct {
+		name          string
+		script        string
+		timeout       time.Duration
+		ignoreFailure bool
+		expectError   string
+	}{
+		{
+			"happy path",
+			`cat > input.json && test "$CTS_TASK_NAME" = "task"`,
+			time.Minute,
+			false,
+			"",
+		},
+		{
+			"failure",
+			`echo "bad request" >&2; exit 1`,
+			time.Minute,
+			false,
+			"bad request",
+		},
+		{
+			"failure ignored",
+			`exit 1`,
+			time.Minute,
+			true,
+			"",
+		},
+		{
+			"timeout",
+			`sleep 5`,
+			100 * time.Millisecond,
+			false,
+			"timed out",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			wd := filepath.Join(dir, tc.name)
+			require.NoError(t, os.MkdirAll(wd, 0755))
+
+			h, err := NewExec(ExecConfig{
+				Command:       "sh",
+				Args:          []string{"-c", tc.script},
+				Timeout:       tc.timeout,
+				IgnoreFailure: tc.ignoreFailure,
+				WorkingDir:    wd,
+				Input:         input,
+			})
+			require.NoError(t, err)
+
+			err = h.Do(context.Background(), nil)
+			if tc.expectError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("input", func(t *testing.T) {
+		b, err := os.ReadFile(filepath.Join(dir, "happy path", "input.json"))
+		require.NoError(t, err)
+
+		var actual ExecInput
+		require.NoError(t, json.Unmarshal(b, &actual))
+		assert.Equal(t, input, actual)
+	})
+
+	t.Run("calls next", func(t *testing.T) {
+		h, err := NewExec(ExecConfig{Command: "true", Timeout: time.Minute})
+		require.NoError(t, err)
+		next, err := NewFake(map[string]interface{}{"name": "next", "err": true})
+		require.NoError(t, err)
+		h.SetNext(next)
+
+		err = h.Do(context.Background(), nil)
+		assert.EqualError(t, err, "error next")
+	})
+}
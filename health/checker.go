@@ -3,7 +3,10 @@
 
 package health
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+)
 
 //go:generate mockery --name=Checker --filename=checker.go --output=../mocks/health
 
@@ -38,3 +41,39 @@ type BasicChecker struct{}
 func (h *BasicChecker) Check() error {
 	return nil
 }
+
+var _ Checker = (*StatusChecker)(nil)
+
+// StatusChecker reports whether CTS is healthy or degraded. CTS is degraded
+// while it is running but unable to make progress, e.g. while the connection
+// to Consul is lost. A degraded system is unhealthy, so that the health
+// endpoint responds with a `503` until CTS recovers.
+type StatusChecker struct {
+	mu  sync.RWMutex
+	err error
+}
+
+// SetDegraded marks the system as degraded with the error that caused it
+func (h *StatusChecker) SetDegraded(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.err = err
+}
+
+// SetHealthy marks the system as healthy
+func (h *StatusChecker) SetHealthy() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.err = nil
+}
+
+// Check returns an UnhealthySystemError if the system is degraded
+func (h *StatusChecker) Check() error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.err != nil {
+		return &UnhealthySystemError{Err: fmt.Errorf("degraded: %v", h.err)}
+	}
+	return nil
+}
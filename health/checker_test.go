@@ -17,6 +17,22 @@ func Test_BasicHealth_Check(t *testing.T) {
 	assert.NoError(t, h.Check())
 }
 
+func TestStatusChecker_Check(t *testing.T) {
+	t.Parallel()
+
+	h := &StatusChecker{}
+	assert.NoError(t, h.Check())
+
+	h.SetDegraded(errors.New("connection refused"))
+	err := h.Check()
+	var unhealthyErr *UnhealthySystemError
+	assert.True(t, errors.As(err, &unhealthyErr))
+	assert.Equal(t, "CTS is not healthy: degraded: connection refused", err.Error())
+
+	h.SetHealthy()
+	assert.NoError(t, h.Check())
+}
+
 func TestUnhealthySystemError_Error(t *testing.T) {
 	err := UnhealthySystemError{Err: errors.New("some error")}
 	var nonEnterpriseConsulError *UnhealthySystemError
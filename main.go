@@ -4,12 +4,22 @@
 package main
 
 import (
+	"fmt"
 	"os"
 
+	"github.com/hashicorp/consul-terraform-sync/client"
 	"github.com/hashicorp/consul-terraform-sync/command"
 )
 
 func main() {
+	// CTS executes itself as the wrapper of Terraform to execute Terraform
+	// with reduced privileges when the Terraform sandbox is configured
+	if client.InTerraformSandbox() {
+		err := client.ExecTerraformSandbox(os.Args[1:])
+		fmt.Fprintf(os.Stderr, "error executing Terraform in sandbox: %s\n", err)
+		os.Exit(1)
+	}
+
 	cli := command.NewCLI(os.Stdout, os.Stderr)
 	os.Exit(cli.Run(os.Args))
 }
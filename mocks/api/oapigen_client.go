@@ -106,20 +106,20 @@ func (_m *ClientWithResponsesInterface) DeleteTaskByNameWithResponse(ctx context
 	return r0, r1
 }
 
-// GetAllTasksWithResponse provides a mock function with given fields: ctx, reqEditors
-func (_m *ClientWithResponsesInterface) GetAllTasksWithResponse(ctx context.Context, reqEditors ...oapigen.RequestEditorFn) (*oapigen.GetAllTasksResponse, error) {
+// GetAllTasksWithResponse provides a mock function with given fields: ctx, params, reqEditors
+func (_m *ClientWithResponsesInterface) GetAllTasksWithResponse(ctx context.Context, params *oapigen.GetAllTasksParams, reqEditors ...oapigen.RequestEditorFn) (*oapigen.GetAllTasksResponse, error) {
 	_va := make([]interface{}, len(reqEditors))
 	for _i := range reqEditors {
 		_va[_i] = reqEditors[_i]
 	}
 	var _ca []interface{}
-	_ca = append(_ca, ctx)
+	_ca = append(_ca, ctx, params)
 	_ca = append(_ca, _va...)
 	ret := _m.Called(_ca...)
 
 	var r0 *oapigen.GetAllTasksResponse
-	if rf, ok := ret.Get(0).(func(context.Context, ...oapigen.RequestEditorFn) *oapigen.GetAllTasksResponse); ok {
-		r0 = rf(ctx, reqEditors...)
+	if rf, ok := ret.Get(0).(func(context.Context, *oapigen.GetAllTasksParams, ...oapigen.RequestEditorFn) *oapigen.GetAllTasksResponse); ok {
+		r0 = rf(ctx, params, reqEditors...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*oapigen.GetAllTasksResponse)
@@ -127,8 +127,8 @@ func (_m *ClientWithResponsesInterface) GetAllTasksWithResponse(ctx context.Cont
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, ...oapigen.RequestEditorFn) error); ok {
-		r1 = rf(ctx, reqEditors...)
+	if rf, ok := ret.Get(1).(func(context.Context, *oapigen.GetAllTasksParams, ...oapigen.RequestEditorFn) error); ok {
+		r1 = rf(ctx, params, reqEditors...)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -226,6 +226,36 @@ func (_m *ClientWithResponsesInterface) GetTaskByNameWithResponse(ctx context.Co
 	return r0, r1
 }
 
+// GetVersionWithResponse provides a mock function with given fields: ctx, reqEditors
+func (_m *ClientWithResponsesInterface) GetVersionWithResponse(ctx context.Context, reqEditors ...oapigen.RequestEditorFn) (*oapigen.GetVersionResponse, error) {
+	_va := make([]interface{}, len(reqEditors))
+	for _i := range reqEditors {
+		_va[_i] = reqEditors[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *oapigen.GetVersionResponse
+	if rf, ok := ret.Get(0).(func(context.Context, ...oapigen.RequestEditorFn) *oapigen.GetVersionResponse); ok {
+		r0 = rf(ctx, reqEditors...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*oapigen.GetVersionResponse)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, ...oapigen.RequestEditorFn) error); ok {
+		r1 = rf(ctx, reqEditors...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewClientWithResponsesInterface interface {
 	mock.TestingT
 	Cleanup(func())
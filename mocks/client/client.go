@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	json "encoding/json"
 	io "io"
 
 	mock "github.com/stretchr/testify/mock"
@@ -28,6 +29,20 @@ func (_m *Client) Apply(ctx context.Context) error {
 	return r0
 }
 
+// ApplyPlan provides a mock function with given fields: ctx, planFile
+func (_m *Client) ApplyPlan(ctx context.Context, planFile string) error {
+	ret := _m.Called(ctx, planFile)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, planFile)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GoString provides a mock function with given fields:
 func (_m *Client) GoString() string {
 	ret := _m.Called()
@@ -56,6 +71,29 @@ func (_m *Client) Init(ctx context.Context) error {
 	return r0
 }
 
+// Outputs provides a mock function with given fields: ctx
+func (_m *Client) Outputs(ctx context.Context) (map[string]json.RawMessage, error) {
+	ret := _m.Called(ctx)
+
+	var r0 map[string]json.RawMessage
+	if rf, ok := ret.Get(0).(func(context.Context) map[string]json.RawMessage); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]json.RawMessage)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Plan provides a mock function with given fields: ctx
 func (_m *Client) Plan(ctx context.Context) (bool, error) {
 	ret := _m.Called(ctx)
@@ -77,6 +115,29 @@ func (_m *Client) Plan(ctx context.Context) (bool, error) {
 	return r0, r1
 }
 
+// SavePlan provides a mock function with given fields: ctx, planFile
+func (_m *Client) SavePlan(ctx context.Context, planFile string) ([]byte, error) {
+	ret := _m.Called(ctx, planFile)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
+		r0 = rf(ctx, planFile)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, planFile)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SetEnv provides a mock function with given fields: _a0
 func (_m *Client) SetEnv(_a0 map[string]string) error {
 	ret := _m.Called(_a0)
@@ -91,11 +152,21 @@ func (_m *Client) SetEnv(_a0 map[string]string) error {
 	return r0
 }
 
+// SetProgress provides a mock function with given fields: w
+func (_m *Client) SetProgress(w io.Writer) {
+	_m.Called(w)
+}
+
 // SetStdout provides a mock function with given fields: w
 func (_m *Client) SetStdout(w io.Writer) {
 	_m.Called(w)
 }
 
+// SetTargets provides a mock function with given fields: targets
+func (_m *Client) SetTargets(targets []string) {
+	_m.Called(targets)
+}
+
 // Validate provides a mock function with given fields: ctx
 func (_m *Client) Validate(ctx context.Context) error {
 	ret := _m.Called(ctx)
@@ -39,6 +39,27 @@ func (_m *TerraformExec) Apply(ctx context.Context, opts ...tfexec.ApplyOption)
 	return r0
 }
 
+// ApplyJSON provides a mock function with given fields: ctx, w, opts
+func (_m *TerraformExec) ApplyJSON(ctx context.Context, w io.Writer, opts ...tfexec.ApplyOption) error {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, w)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.Writer, ...tfexec.ApplyOption) error); ok {
+		r0 = rf(ctx, w, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Init provides a mock function with given fields: ctx, opts
 func (_m *TerraformExec) Init(ctx context.Context, opts ...tfexec.InitOption) error {
 	_va := make([]interface{}, len(opts))
@@ -60,6 +81,36 @@ func (_m *TerraformExec) Init(ctx context.Context, opts ...tfexec.InitOption) er
 	return r0
 }
 
+// Output provides a mock function with given fields: ctx, opts
+func (_m *TerraformExec) Output(ctx context.Context, opts ...tfexec.OutputOption) (map[string]tfexec.OutputMeta, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 map[string]tfexec.OutputMeta
+	if rf, ok := ret.Get(0).(func(context.Context, ...tfexec.OutputOption) map[string]tfexec.OutputMeta); ok {
+		r0 = rf(ctx, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]tfexec.OutputMeta)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, ...tfexec.OutputOption) error); ok {
+		r1 = rf(ctx, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Plan provides a mock function with given fields: ctx, opts
 func (_m *TerraformExec) Plan(ctx context.Context, opts ...tfexec.PlanOption) (bool, error) {
 	_va := make([]interface{}, len(opts))
@@ -88,6 +139,34 @@ func (_m *TerraformExec) Plan(ctx context.Context, opts ...tfexec.PlanOption) (b
 	return r0, r1
 }
 
+// PlanJSON provides a mock function with given fields: ctx, w, opts
+func (_m *TerraformExec) PlanJSON(ctx context.Context, w io.Writer, opts ...tfexec.PlanOption) (bool, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, w)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, io.Writer, ...tfexec.PlanOption) bool); ok {
+		r0 = rf(ctx, w, opts...)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, io.Writer, ...tfexec.PlanOption) error); ok {
+		r1 = rf(ctx, w, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SetEnv provides a mock function with given fields: env
 func (_m *TerraformExec) SetEnv(env map[string]string) error {
 	ret := _m.Called(env)
@@ -107,6 +186,36 @@ func (_m *TerraformExec) SetStdout(w io.Writer) {
 	_m.Called(w)
 }
 
+// ShowPlanFile provides a mock function with given fields: ctx, planPath, opts
+func (_m *TerraformExec) ShowPlanFile(ctx context.Context, planPath string, opts ...tfexec.ShowOption) (*tfjson.Plan, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, planPath)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *tfjson.Plan
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...tfexec.ShowOption) *tfjson.Plan); ok {
+		r0 = rf(ctx, planPath, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*tfjson.Plan)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...tfexec.ShowOption) error); ok {
+		r1 = rf(ctx, planPath, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Validate provides a mock function with given fields: ctx
 func (_m *TerraformExec) Validate(ctx context.Context) (*tfjson.ValidateOutput, error) {
 	ret := _m.Called(ctx)
@@ -28,6 +28,22 @@ func (_m *Driver) ApplyTask(ctx context.Context) error {
 	return r0
 }
 
+// DependencyTriggers provides a mock function with given fields:
+func (_m *Driver) DependencyTriggers() map[string]int {
+	ret := _m.Called()
+
+	var r0 map[string]int
+	if rf, ok := ret.Get(0).(func() map[string]int); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+
+	return r0
+}
+
 // DestroyTask provides a mock function with given fields: ctx
 func (_m *Driver) DestroyTask(ctx context.Context) {
 	_m.Called(ctx)
@@ -68,6 +84,83 @@ func (_m *Driver) InspectTask(ctx context.Context) (driver.InspectPlan, error) {
 	return r0, r1
 }
 
+// Readiness provides a mock function with given fields:
+func (_m *Driver) Readiness() driver.Readiness {
+	ret := _m.Called()
+
+	var r0 driver.Readiness
+	if rf, ok := ret.Get(0).(func() driver.Readiness); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(driver.Readiness)
+	}
+
+	return r0
+}
+
+// ReinitTask provides a mock function with given fields: ctx
+func (_m *Driver) ReinitTask(ctx context.Context) (driver.RenderResult, error) {
+	ret := _m.Called(ctx)
+
+	var r0 driver.RenderResult
+	if rf, ok := ret.Get(0).(func(context.Context) driver.RenderResult); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(driver.RenderResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RenderChanged provides a mock function with given fields:
+func (_m *Driver) RenderChanged() (bool, error) {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RenderTask provides a mock function with given fields: ctx
+func (_m *Driver) RenderTask(ctx context.Context) (driver.RenderResult, error) {
+	ret := _m.Called(ctx)
+
+	var r0 driver.RenderResult
+	if rf, ok := ret.Get(0).(func(context.Context) driver.RenderResult); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(driver.RenderResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RenderTemplate provides a mock function with given fields: ctx
 func (_m *Driver) RenderTemplate(ctx context.Context) (bool, error) {
 	ret := _m.Called(ctx)
@@ -7,9 +7,21 @@ import (
 
 	config "github.com/hashicorp/consul-terraform-sync/config"
 
+	driver "github.com/hashicorp/consul-terraform-sync/driver"
+
 	event "github.com/hashicorp/consul-terraform-sync/state/event"
 
+	io "io"
+
 	mock "github.com/stretchr/testify/mock"
+
+	plan "github.com/hashicorp/consul-terraform-sync/state/plan"
+
+	revision "github.com/hashicorp/consul-terraform-sync/state/revision"
+
+	templates "github.com/hashicorp/consul-terraform-sync/templates"
+
+	time "time"
 )
 
 // Server is an autogenerated mock type for the Server type
@@ -31,6 +43,20 @@ func (_m *Server) Config() config.Config {
 	return r0
 }
 
+// DependencyStats provides a mock function with given fields: ctx
+func (_m *Server) DependencyStats(ctx context.Context) templates.DependencyStats {
+	ret := _m.Called(ctx)
+
+	var r0 templates.DependencyStats
+	if rf, ok := ret.Get(0).(func(context.Context) templates.DependencyStats); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(templates.DependencyStats)
+	}
+
+	return r0
+}
+
 // Events provides a mock function with given fields: ctx, taskName
 func (_m *Server) Events(ctx context.Context, taskName string) (map[string][]event.Event, error) {
 	ret := _m.Called(ctx, taskName)
@@ -75,6 +101,27 @@ func (_m *Server) Task(ctx context.Context, taskName string) (config.TaskConfig,
 	return r0, r1
 }
 
+// TaskCircuitBreakerOpen provides a mock function with given fields: ctx, taskName
+func (_m *Server) TaskCircuitBreakerOpen(ctx context.Context, taskName string) (time.Time, bool) {
+	ret := _m.Called(ctx, taskName)
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func(context.Context, string) time.Time); ok {
+		r0 = rf(ctx, taskName)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(context.Context, string) bool); ok {
+		r1 = rf(ctx, taskName)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
 // TaskCreate provides a mock function with given fields: _a0, _a1
 func (_m *Server) TaskCreate(_a0 context.Context, _a1 config.TaskConfig) (config.TaskConfig, error) {
 	ret := _m.Called(_a0, _a1)
@@ -131,6 +178,43 @@ func (_m *Server) TaskDelete(ctx context.Context, taskName string) error {
 	return r0
 }
 
+// TaskDependencyTriggers provides a mock function with given fields: ctx, taskName
+func (_m *Server) TaskDependencyTriggers(ctx context.Context, taskName string) map[string]int {
+	ret := _m.Called(ctx, taskName)
+
+	var r0 map[string]int
+	if rf, ok := ret.Get(0).(func(context.Context, string) map[string]int); ok {
+		r0 = rf(ctx, taskName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+
+	return r0
+}
+
+// TaskInputsDiff provides a mock function with given fields: ctx, taskName, from, to
+func (_m *Server) TaskInputsDiff(ctx context.Context, taskName string, from string, to string) (driver.InputsDiff, error) {
+	ret := _m.Called(ctx, taskName, from, to)
+
+	var r0 driver.InputsDiff
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) driver.InputsDiff); ok {
+		r0 = rf(ctx, taskName, from, to)
+	} else {
+		r0 = ret.Get(0).(driver.InputsDiff)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, taskName, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // TaskInspect provides a mock function with given fields: _a0, _a1
 func (_m *Server) TaskInspect(_a0 context.Context, _a1 config.TaskConfig) (bool, string, string, error) {
 	ret := _m.Called(_a0, _a1)
@@ -166,6 +250,290 @@ func (_m *Server) TaskInspect(_a0 context.Context, _a1 config.TaskConfig) (bool,
 	return r0, r1, r2, r3
 }
 
+// TaskInventory provides a mock function with given fields: ctx, taskName
+func (_m *Server) TaskInventory(ctx context.Context, taskName string) (driver.Inventory, error) {
+	ret := _m.Called(ctx, taskName)
+
+	var r0 driver.Inventory
+	if rf, ok := ret.Get(0).(func(context.Context, string) driver.Inventory); ok {
+		r0 = rf(ctx, taskName)
+	} else {
+		r0 = ret.Get(0).(driver.Inventory)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, taskName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TaskLock provides a mock function with given fields: ctx, taskName, holder, reason
+func (_m *Server) TaskLock(ctx context.Context, taskName string, holder string, reason string) (driver.TaskLock, error) {
+	ret := _m.Called(ctx, taskName, holder, reason)
+
+	var r0 driver.TaskLock
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) driver.TaskLock); ok {
+		r0 = rf(ctx, taskName, holder, reason)
+	} else {
+		r0 = ret.Get(0).(driver.TaskLock)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, taskName, holder, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TaskLockStatus provides a mock function with given fields: ctx, taskName
+func (_m *Server) TaskLockStatus(ctx context.Context, taskName string) (driver.TaskLock, bool) {
+	ret := _m.Called(ctx, taskName)
+
+	var r0 driver.TaskLock
+	if rf, ok := ret.Get(0).(func(context.Context, string) driver.TaskLock); ok {
+		r0 = rf(ctx, taskName)
+	} else {
+		r0 = ret.Get(0).(driver.TaskLock)
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(context.Context, string) bool); ok {
+		r1 = rf(ctx, taskName)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// TaskNextScheduledRun provides a mock function with given fields: ctx, taskName
+func (_m *Server) TaskNextScheduledRun(ctx context.Context, taskName string) (time.Time, bool) {
+	ret := _m.Called(ctx, taskName)
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func(context.Context, string) time.Time); ok {
+		r0 = rf(ctx, taskName)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(context.Context, string) bool); ok {
+		r1 = rf(ctx, taskName)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// TaskPendingRuns provides a mock function with given fields: ctx, taskName
+func (_m *Server) TaskPendingRuns(ctx context.Context, taskName string) []time.Time {
+	ret := _m.Called(ctx, taskName)
+
+	var r0 []time.Time
+	if rf, ok := ret.Get(0).(func(context.Context, string) []time.Time); ok {
+		r0 = rf(ctx, taskName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]time.Time)
+		}
+	}
+
+	return r0
+}
+
+// TaskPlan provides a mock function with given fields: ctx, taskName, eventID
+func (_m *Server) TaskPlan(ctx context.Context, taskName string, eventID string) (plan.Artifact, error) {
+	ret := _m.Called(ctx, taskName, eventID)
+
+	var r0 plan.Artifact
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) plan.Artifact); ok {
+		r0 = rf(ctx, taskName, eventID)
+	} else {
+		r0 = ret.Get(0).(plan.Artifact)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, taskName, eventID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TaskProgress provides a mock function with given fields: ctx, taskName
+func (_m *Server) TaskProgress(ctx context.Context, taskName string) (driver.Progress, error) {
+	ret := _m.Called(ctx, taskName)
+
+	var r0 driver.Progress
+	if rf, ok := ret.Get(0).(func(context.Context, string) driver.Progress); ok {
+		r0 = rf(ctx, taskName)
+	} else {
+		r0 = ret.Get(0).(driver.Progress)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, taskName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TaskReadiness provides a mock function with given fields: ctx, taskName
+func (_m *Server) TaskReadiness(ctx context.Context, taskName string) (driver.Readiness, error) {
+	ret := _m.Called(ctx, taskName)
+
+	var r0 driver.Readiness
+	if rf, ok := ret.Get(0).(func(context.Context, string) driver.Readiness); ok {
+		r0 = rf(ctx, taskName)
+	} else {
+		r0 = ret.Get(0).(driver.Readiness)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, taskName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TaskReinit provides a mock function with given fields: ctx, taskName
+func (_m *Server) TaskReinit(ctx context.Context, taskName string) (driver.RenderResult, error) {
+	ret := _m.Called(ctx, taskName)
+
+	var r0 driver.RenderResult
+	if rf, ok := ret.Get(0).(func(context.Context, string) driver.RenderResult); ok {
+		r0 = rf(ctx, taskName)
+	} else {
+		r0 = ret.Get(0).(driver.RenderResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, taskName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TaskRender provides a mock function with given fields: ctx, taskName
+func (_m *Server) TaskRender(ctx context.Context, taskName string) (driver.RenderResult, error) {
+	ret := _m.Called(ctx, taskName)
+
+	var r0 driver.RenderResult
+	if rf, ok := ret.Get(0).(func(context.Context, string) driver.RenderResult); ok {
+		r0 = rf(ctx, taskName)
+	} else {
+		r0 = ret.Get(0).(driver.RenderResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, taskName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TaskRestoreRevision provides a mock function with given fields: ctx, taskName, id
+func (_m *Server) TaskRestoreRevision(ctx context.Context, taskName string, id int) (config.TaskConfig, error) {
+	ret := _m.Called(ctx, taskName, id)
+
+	var r0 config.TaskConfig
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) config.TaskConfig); ok {
+		r0 = rf(ctx, taskName, id)
+	} else {
+		r0 = ret.Get(0).(config.TaskConfig)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, taskName, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TaskRevisions provides a mock function with given fields: ctx, taskName
+func (_m *Server) TaskRevisions(ctx context.Context, taskName string) ([]revision.Revision, error) {
+	ret := _m.Called(ctx, taskName)
+
+	var r0 []revision.Revision
+	if rf, ok := ret.Get(0).(func(context.Context, string) []revision.Revision); ok {
+		r0 = rf(ctx, taskName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]revision.Revision)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, taskName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TaskStats provides a mock function with given fields: ctx, taskName
+func (_m *Server) TaskStats(ctx context.Context, taskName string) (event.Stats, bool) {
+	ret := _m.Called(ctx, taskName)
+
+	var r0 event.Stats
+	if rf, ok := ret.Get(0).(func(context.Context, string) event.Stats); ok {
+		r0 = rf(ctx, taskName)
+	} else {
+		r0 = ret.Get(0).(event.Stats)
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(context.Context, string) bool); ok {
+		r1 = rf(ctx, taskName)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// TaskUnlock provides a mock function with given fields: ctx, taskName
+func (_m *Server) TaskUnlock(ctx context.Context, taskName string) error {
+	ret := _m.Called(ctx, taskName)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, taskName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // TaskUpdate provides a mock function with given fields: ctx, updateConf, runOp
 func (_m *Server) TaskUpdate(ctx context.Context, updateConf config.TaskConfig, runOp string) (bool, string, string, error) {
 	ret := _m.Called(ctx, updateConf, runOp)
@@ -201,6 +569,27 @@ func (_m *Server) TaskUpdate(ctx context.Context, updateConf config.TaskConfig,
 	return r0, r1, r2, r3
 }
 
+// TaskValidate provides a mock function with given fields: _a0, _a1
+func (_m *Server) TaskValidate(_a0 context.Context, _a1 config.TaskConfig) (driver.ModuleValidation, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 driver.ModuleValidation
+	if rf, ok := ret.Get(0).(func(context.Context, config.TaskConfig) driver.ModuleValidation); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(driver.ModuleValidation)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, config.TaskConfig) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Tasks provides a mock function with given fields: _a0
 func (_m *Server) Tasks(_a0 context.Context) config.TaskConfigs {
 	ret := _m.Called(_a0)
@@ -217,6 +606,20 @@ func (_m *Server) Tasks(_a0 context.Context) config.TaskConfigs {
 	return r0
 }
 
+// WriteDiagnostics provides a mock function with given fields: ctx, w
+func (_m *Server) WriteDiagnostics(ctx context.Context, w io.Writer) error {
+	ret := _m.Called(ctx, w)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.Writer) error); ok {
+		r0 = rf(ctx, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewServer interface {
 	mock.TestingT
 	Cleanup(func())
@@ -5,6 +5,7 @@ package mocks
 import (
 	config "github.com/hashicorp/consul-terraform-sync/config"
 	event "github.com/hashicorp/consul-terraform-sync/state/event"
+	revision "github.com/hashicorp/consul-terraform-sync/state/revision"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -28,6 +29,20 @@ func (_m *Store) AddTaskEvent(_a0 event.Event) error {
 	return r0
 }
 
+// AddTaskRevision provides a mock function with given fields: taskConf, actor
+func (_m *Store) AddTaskRevision(taskConf config.TaskConfig, actor string) error {
+	ret := _m.Called(taskConf, actor)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(config.TaskConfig, string) error); ok {
+		r0 = rf(taskConf, actor)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteTask provides a mock function with given fields: taskName
 func (_m *Store) DeleteTask(taskName string) error {
 	ret := _m.Called(taskName)
@@ -123,6 +138,43 @@ func (_m *Store) GetTaskEvents(taskName string) map[string][]event.Event {
 	return r0
 }
 
+// GetTaskStats provides a mock function with given fields: taskName
+func (_m *Store) GetTaskStats(taskName string) (event.Stats, bool) {
+	ret := _m.Called(taskName)
+
+	var r0 event.Stats
+	if rf, ok := ret.Get(0).(func(string) event.Stats); ok {
+		r0 = rf(taskName)
+	} else {
+		r0 = ret.Get(0).(event.Stats)
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(string) bool); ok {
+		r1 = rf(taskName)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// GetTaskRevisions provides a mock function with given fields: taskName
+func (_m *Store) GetTaskRevisions(taskName string) []revision.Revision {
+	ret := _m.Called(taskName)
+
+	var r0 []revision.Revision
+	if rf, ok := ret.Get(0).(func(string) []revision.Revision); ok {
+		r0 = rf(taskName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]revision.Revision)
+		}
+	}
+
+	return r0
+}
+
 // SetTask provides a mock function with given fields: taskConf
 func (_m *Store) SetTask(taskConf config.TaskConfig) error {
 	ret := _m.Called(taskConf)
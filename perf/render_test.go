@@ -0,0 +1,206 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Benchmarks for the memory usage and latency of rendering the variables of
+// tasks that monitor a high number of service instances. The benchmarks do
+// not require Consul or the CTS binary.
+//
+// $ go test ./perf -run=XXX -bench=. -benchmem
+// $ go test ./perf -run=XXX -bench=Render -benchmem -instance-counts=1000,10000
+package perf
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/templates"
+	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl"
+	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl/tmplfunc"
+	"github.com/hashicorp/hcat"
+	"github.com/hashicorp/hcat/dep"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	serviceName = "bench"
+	filePerms   = os.FileMode(0640)
+)
+
+var instanceCounts = flag.String("instance-counts", "100,1000,5000",
+	"comma separated list of the number of service instances to benchmark")
+
+// BenchmarkExecute measures executing the template of the services variable,
+// which encodes each service instance into HCL
+func BenchmarkExecute(b *testing.B) {
+	forEachCount(b, func(b *testing.B, count int) {
+		tmpl := newServicesTemplate(b)
+		recaller := newRecaller(count)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := tmpl.Execute(recaller); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkRender measures rendering the executed template to the variables
+// file when the contents changed and when the contents are unchanged
+func BenchmarkRender(b *testing.B) {
+	forEachCount(b, func(b *testing.B, count int) {
+		contents, err := newServicesTemplate(b).Execute(newRecaller(count))
+		require.NoError(b, err)
+
+		// a copy with a change in the last service instance
+		changed := append([]byte{}, contents...)
+		changed[len(changed)-3] = '#'
+
+		b.Run("changed", func(b *testing.B) {
+			r := templates.NewFileRenderer(templates.FileRendererInput{
+				Path:  filepath.Join(b.TempDir(), tftmpl.TFVarsFilename),
+				Perms: filePerms,
+			})
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				next := contents
+				if i%2 == 1 {
+					next = changed
+				}
+				if _, err := r.Render(next); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run("unchanged", func(b *testing.B) {
+			r := templates.NewFileRenderer(templates.FileRendererInput{
+				Path:  filepath.Join(b.TempDir(), tftmpl.TFVarsFilename),
+				Perms: filePerms,
+			})
+			_, err := r.Render(contents)
+			require.NoError(b, err)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := r.Render(contents); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	})
+}
+
+// BenchmarkSnapshot measures saving the snapshot of the rendered variables
+// that is compared to the next run for the services_changed variable
+func BenchmarkSnapshot(b *testing.B) {
+	forEachCount(b, func(b *testing.B, count int) {
+		contents, err := newServicesTemplate(b).Execute(newRecaller(count))
+		require.NoError(b, err)
+
+		dir := b.TempDir()
+		src := filepath.Join(dir, tftmpl.TFVarsFilename)
+		dst := filepath.Join(dir, tftmpl.ServicesSnapshotFilename)
+		require.NoError(b, os.WriteFile(src, contents, filePerms))
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := templates.CopyFile(dst, src, filePerms); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// forEachCount runs the benchmark for each of the instance counts
+func forEachCount(b *testing.B, fn func(*testing.B, int)) {
+	counts, err := parseInstanceCounts(*instanceCounts)
+	require.NoError(b, err)
+
+	for _, count := range counts {
+		b.Run(fmt.Sprintf("instances_%d", count), func(b *testing.B) {
+			fn(b, count)
+		})
+	}
+}
+
+// newServicesTemplate generates the root module of a task that monitors the
+// bench service and returns the template of the task's variables
+func newServicesTemplate(b *testing.B) *hcat.Template {
+	dir := b.TempDir()
+	err := tftmpl.InitRootModule(&tftmpl.RootModuleInputData{
+		Task: tftmpl.Task{
+			Name:   "bench",
+			Module: "example/module",
+		},
+		Templates: []tftmpl.Template{&tftmpl.ServicesTemplate{
+			Names:     []string{serviceName},
+			RenderVar: true,
+		}},
+		Path:      dir,
+		FilePerms: filePerms,
+	})
+	require.NoError(b, err)
+
+	content, err := os.ReadFile(filepath.Join(dir, tftmpl.TFVarsTmplFilename))
+	require.NoError(b, err)
+
+	return hcat.NewTemplate(hcat.TemplateInput{
+		Contents:     string(content),
+		FuncMapMerge: tmplfunc.HCLMap(nil),
+	})
+}
+
+// newRecaller returns a recaller that returns the number of instances of
+// the bench service for each dependency
+func newRecaller(count int) hcat.Recaller {
+	instances := make([]*dep.HealthService, 0, count)
+	for i := 0; i < count; i++ {
+		instances = append(instances, &dep.HealthService{
+			Node:           fmt.Sprintf("node-%d", i%50),
+			NodeID:         fmt.Sprintf("6b5b5c1c-6d0b-4d5f-9f3b-%012d", i%50),
+			NodeAddress:    fmt.Sprintf("10.0.%d.%d", i/250, i%250),
+			NodeDatacenter: "dc1",
+			NodeTaggedAddresses: map[string]string{
+				"lan": fmt.Sprintf("10.0.%d.%d", i/250, i%250),
+			},
+			NodeMeta:    map[string]string{"consul-network-segment": ""},
+			ServiceMeta: map[string]string{"version": "v1"},
+			Address:     fmt.Sprintf("10.1.%d.%d", i/250, i%250),
+			ID:          fmt.Sprintf("%s-%d", serviceName, i),
+			Name:        serviceName,
+			Tags:        dep.ServiceTags{"primary", "bench"},
+			Status:      "passing",
+			Port:        8080,
+			Namespace:   "default",
+		})
+	}
+
+	return func(dep.Dependency) (interface{}, bool) {
+		return instances, true
+	}
+}
+
+// parseInstanceCounts parses the comma separated instance counts flag
+func parseInstanceCounts(s string) ([]int, error) {
+	parts := strings.Split(s, ",")
+	counts := make([]int, 0, len(parts))
+	for _, p := range parts {
+		count, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil {
+			return nil, fmt.Errorf("invalid instance count %q: %s", p, err)
+		}
+		counts = append(counts, count)
+	}
+	return counts, nil
+}
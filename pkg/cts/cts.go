@@ -0,0 +1,187 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package cts provides a Go API for embedding Consul-Terraform-Sync (CTS) as a
+// library. It allows other Go programs to create and manage tasks, receive
+// task events through callbacks, and run tasks in once-mode or long-running
+// mode without shelling out to the CTS binary or using the HTTP API.
+//
+// A minimal example of running all configured tasks once:
+//
+//	conf, _ := config.BuildConfig([]string{"config.hcl"})
+//	c, err := cts.New(conf, cts.WithEventHandler(func(e event.Event) {
+//		log.Printf("task %s ran, success: %t", e.TaskName, e.Success)
+//	}))
+//	if err != nil {
+//		return err
+//	}
+//	defer c.Stop()
+//
+//	if err := c.Init(ctx); err != nil {
+//		return err
+//	}
+//	return c.RunOnce(ctx)
+package cts
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/controller"
+	"github.com/hashicorp/consul-terraform-sync/state"
+	"github.com/hashicorp/consul-terraform-sync/state/event"
+)
+
+// EventHandler is called with every task event that is stored once the event
+// is complete. Handlers are called synchronously in the order that they were
+// registered and should return quickly.
+type EventHandler func(event.Event)
+
+// Option configures optional behavior of CTS
+type Option func(*CTS)
+
+// WithEventHandler registers a handler that is called with every task event.
+func WithEventHandler(h EventHandler) Option {
+	return func(c *CTS) {
+		if h != nil {
+			c.handlers = append(c.handlers, h)
+		}
+	}
+}
+
+// CTS is an embeddable instance of Consul-Terraform-Sync. It should be
+// created with New.
+type CTS struct {
+	conf         *config.Config
+	daemon       *controller.Daemon
+	tasksManager *controller.TasksManager
+
+	handlers []EventHandler
+}
+
+// New finalizes and validates the configuration and then sets up a new CTS
+// instance. It tests the connection to Consul, so Consul is expected to be
+// reachable. The returned instance must be initialized with Init before
+// running tasks.
+func New(conf *config.Config, opts ...Option) (*CTS, error) {
+	if conf == nil {
+		return nil, fmt.Errorf("missing configuration")
+	}
+
+	c := &CTS{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if err := conf.Finalize(); err != nil {
+		return nil, fmt.Errorf("error finalizing configuration: %s", err)
+	}
+	if err := conf.Validate(); err != nil {
+		return nil, fmt.Errorf("error validating configuration: %s", err)
+	}
+	c.conf = conf
+
+	s := newEventNotifyStore(state.NewInMemoryStore(conf), c.handlers)
+	d, err := controller.NewDaemonWithStore(conf, s)
+	if err != nil {
+		return nil, err
+	}
+	c.daemon = d
+	c.tasksManager = d.TasksManager()
+
+	return c, nil
+}
+
+// Init checks the configuration against the connected Consul, installs the
+// driver, and initializes CTS before tasks can be run.
+func (c *CTS) Init(ctx context.Context) error {
+	if err := controller.CheckConsulCompatibility(ctx, c.conf); err != nil {
+		return fmt.Errorf("error checking compatibility with Consul: %s", err)
+	}
+	if err := controller.InstallDriver(ctx, c.conf); err != nil {
+		return fmt.Errorf("error installing driver: %s", err)
+	}
+	return c.daemon.Init(ctx)
+}
+
+// RunOnce runs all configured tasks once and returns after all tasks have
+// completed. Tasks remain managed by CTS afterwards and can be run in
+// long-running mode with Run.
+func (c *CTS) RunOnce(ctx context.Context) error {
+	return c.daemon.Once(ctx)
+}
+
+// Run runs CTS in long-running mode until the context is canceled or an error
+// occurs. If the tasks have not yet been run once, they are run once first.
+// This also starts daemon-only features such as the API server.
+func (c *CTS) Run(ctx context.Context) error {
+	return c.daemon.Run(ctx)
+}
+
+// Stop stops underlying clients and connections.
+func (c *CTS) Stop() {
+	c.daemon.Stop()
+}
+
+// Config returns a copy of the finalized CTS configuration.
+func (c *CTS) Config() config.Config {
+	return c.tasksManager.Config()
+}
+
+// Task returns the configuration of the task with the given name.
+func (c *CTS) Task(ctx context.Context, name string) (config.TaskConfig, error) {
+	return c.tasksManager.Task(ctx, name)
+}
+
+// Tasks returns the configuration of all tasks.
+func (c *CTS) Tasks(ctx context.Context) config.TaskConfigs {
+	return c.tasksManager.Tasks(ctx)
+}
+
+// CreateTask creates a new task without running it. The task configuration is
+// finalized and validated before the task is created.
+func (c *CTS) CreateTask(ctx context.Context, conf config.TaskConfig) (config.TaskConfig, error) {
+	return c.tasksManager.TaskCreate(ctx, conf)
+}
+
+// CreateAndRunTask creates a new task and runs it once. The task is only
+// added if it runs successfully.
+func (c *CTS) CreateAndRunTask(ctx context.Context, conf config.TaskConfig) (config.TaskConfig, error) {
+	return c.tasksManager.TaskCreateAndRun(ctx, conf)
+}
+
+// RunTask runs an existing task now.
+func (c *CTS) RunTask(ctx context.Context, name string) error {
+	return c.tasksManager.TaskRunNow(ctx, name)
+}
+
+// DeleteTask marks an existing task for deletion. The task is deleted
+// asynchronously once it is no longer running.
+func (c *CTS) DeleteTask(ctx context.Context, name string) error {
+	return c.tasksManager.TaskDelete(ctx, name)
+}
+
+// EnableTask enables an existing task.
+func (c *CTS) EnableTask(ctx context.Context, name string) error {
+	return c.setTaskEnabled(ctx, name, true)
+}
+
+// DisableTask disables an existing task.
+func (c *CTS) DisableTask(ctx context.Context, name string) error {
+	return c.setTaskEnabled(ctx, name, false)
+}
+
+// Events returns the stored events for a task. If no task name is specified,
+// then it returns events for all tasks.
+func (c *CTS) Events(ctx context.Context, name string) (map[string][]event.Event, error) {
+	return c.tasksManager.Events(ctx, name)
+}
+
+func (c *CTS) setTaskEnabled(ctx context.Context, name string, enabled bool) error {
+	_, _, _, err := c.tasksManager.TaskUpdate(ctx, config.TaskConfig{
+		Name:    config.String(name),
+		Enabled: config.Bool(enabled),
+	}, "")
+	return err
+}
@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cts
+
+import (
+	"github.com/hashicorp/consul-terraform-sync/state"
+	"github.com/hashicorp/consul-terraform-sync/state/event"
+)
+
+var _ state.Store = (*eventNotifyStore)(nil)
+
+// eventNotifyStore wraps a state store and calls the registered event
+// handlers after each task event is successfully stored.
+type eventNotifyStore struct {
+	state.Store
+
+	handlers []EventHandler
+}
+
+func newEventNotifyStore(s state.Store, handlers []EventHandler) state.Store {
+	if len(handlers) == 0 {
+		return s
+	}
+	return &eventNotifyStore{
+		Store:    s,
+		handlers: handlers,
+	}
+}
+
+// AddTaskEvent adds the event to the wrapped store and then calls each
+// handler with the event.
+func (s *eventNotifyStore) AddTaskEvent(e event.Event) error {
+	if err := s.Store.AddTaskEvent(e); err != nil {
+		return err
+	}
+
+	for _, h := range s.handlers {
+		h(e)
+	}
+	return nil
+}
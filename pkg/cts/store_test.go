@@ -0,0 +1,71 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cts
+
+import (
+	"errors"
+	"testing"
+
+	mocks "github.com/hashicorp/consul-terraform-sync/mocks/state"
+	"github.com/hashicorp/consul-terraform-sync/state/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestEventNotifyStore_AddTaskEvent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("handlers called in order", func(t *testing.T) {
+		e := event.Event{TaskName: "task", Success: true}
+		m := new(mocks.Store)
+		m.On("AddTaskEvent", e).Return(nil).Once()
+
+		var calls []string
+		s := newEventNotifyStore(m, []EventHandler{
+			func(act event.Event) {
+				assert.Equal(t, e, act)
+				calls = append(calls, "first")
+			},
+			func(act event.Event) {
+				calls = append(calls, "second")
+			},
+		})
+
+		err := s.AddTaskEvent(e)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"first", "second"}, calls)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		m := new(mocks.Store)
+		m.On("AddTaskEvent", mock.Anything).Return(errors.New("error")).Once()
+
+		called := false
+		s := newEventNotifyStore(m, []EventHandler{
+			func(event.Event) { called = true },
+		})
+
+		err := s.AddTaskEvent(event.Event{TaskName: "task"})
+		assert.Error(t, err)
+		assert.False(t, called)
+	})
+
+	t.Run("no handlers", func(t *testing.T) {
+		m := new(mocks.Store)
+		s := newEventNotifyStore(m, nil)
+		assert.Equal(t, m, s)
+	})
+}
+
+func TestWithEventHandler(t *testing.T) {
+	t.Parallel()
+
+	c := &CTS{}
+	WithEventHandler(nil)(c)
+	assert.Empty(t, c.handlers)
+
+	WithEventHandler(func(event.Event) {})(c)
+	assert.Len(t, c.handlers, 1)
+}
@@ -28,6 +28,33 @@ type Event struct {
 	TaskName   string    `json:"task_name"`
 	EventError *Error    `json:"error"`
 
+	// Suppressed is true when the event records triggers for the task that
+	// were suppressed because the task was within its cooldown. The task is
+	// not run for a suppressed event.
+	Suppressed bool `json:"suppressed,omitempty"`
+
+	// Degraded is true when the event records that the task was paused
+	// because its circuit breaker opened after consecutive failures.
+	Degraded bool `json:"degraded,omitempty"`
+
+	// Expired is true when the event records that the task expired and was
+	// disabled or deleted. The task is not run for an expired event.
+	Expired bool `json:"expired,omitempty"`
+
+	// RenderedFiles are the paths of the files rendered for a render-only
+	// task. Render-only tasks do not run Terraform, so other processes are
+	// expected to consume these files.
+	RenderedFiles []string `json:"rendered_files,omitempty"`
+
+	// PlanSaved is true when the Terraform plan for the event was saved as a
+	// plan artifact. The artifact can be retrieved with the task name and
+	// event ID.
+	PlanSaved bool `json:"plan_saved,omitempty"`
+
+	// Run is the metadata of the Terraform run for the event. It is nil for
+	// events that did not run Terraform.
+	Run *RunMetadata `json:"run,omitempty"`
+
 	// Config is deprecated in v0.5. This is configuration details about the
 	// task rather than status information. Users should switch to using the
 	// Get Task API to request the task's config information.
@@ -41,6 +68,26 @@ type Error struct {
 	Message string `json:"message"`
 }
 
+// RunMetadata captures details about the Terraform run of an event
+type RunMetadata struct {
+	// Changes summarizes the resource changes of the apply. It is nil when
+	// the summary is not available, e.g. when the apply failed before any
+	// changes were made.
+	Changes *ChangeSummary `json:"changes,omitempty"`
+
+	TerraformVersion string        `json:"terraform_version"`
+	ModuleVersion    string        `json:"module_version,omitempty"`
+	ApplyDuration    time.Duration `json:"apply_duration"`
+}
+
+// ChangeSummary is the number of resources added, changed, and destroyed by
+// a Terraform apply
+type ChangeSummary struct {
+	Add     int `json:"add"`
+	Change  int `json:"change"`
+	Destroy int `json:"destroy"`
+}
+
 // Config provides details on an event's task configuration. It is deprecated
 // in v0.5 and should be removed in 0.8
 type Config struct {
@@ -113,6 +160,31 @@ func (c *Config) GoString() string {
 	)
 }
 
+// GoString defines the printable version of this struct.
+func (m *RunMetadata) GoString() string {
+	if m == nil {
+		return "(*RunMetadata)(nil)"
+	}
+
+	changes := "(*ChangeSummary)(nil)"
+	if m.Changes != nil {
+		changes = fmt.Sprintf("&ChangeSummary{Add:%d, Change:%d, Destroy:%d}",
+			m.Changes.Add, m.Changes.Change, m.Changes.Destroy)
+	}
+
+	return fmt.Sprintf("&RunMetadata{"+
+		"Changes:%s, "+
+		"TerraformVersion:%s, "+
+		"ModuleVersion:%s, "+
+		"ApplyDuration:%s"+
+		"}",
+		changes,
+		m.TerraformVersion,
+		m.ModuleVersion,
+		m.ApplyDuration,
+	)
+}
+
 // GoString defines the printable version of this struct.
 func (e *Event) GoString() string {
 	if e == nil {
@@ -123,17 +195,29 @@ func (e *Event) GoString() string {
 		"ID:%s, "+
 		"TaskName:%s, "+
 		"Success:%t, "+
+		"Suppressed:%t, "+
+		"Degraded:%t, "+
+		"Expired:%t, "+
 		"StartTime:%s, "+
 		"EndTime:%s, "+
 		"EventError:%s, "+
+		"RenderedFiles:%s, "+
+		"PlanSaved:%t, "+
+		"Run:%s, "+
 		"Config:%s"+
 		"}",
 		e.ID,
 		e.TaskName,
 		e.Success,
+		e.Suppressed,
+		e.Degraded,
+		e.Expired,
 		e.StartTime,
 		e.EndTime,
 		e.EventError,
+		e.RenderedFiles,
+		e.PlanSaved,
+		e.Run.GoString(),
 		e.Config.GoString(),
 	)
 }
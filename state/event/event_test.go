@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -216,11 +217,36 @@ func TestEvent_GoString(t *testing.T) {
 					Source:    "/my-module",
 				},
 			},
-			"&Event{ID:123, TaskName:happy, Success:false, " +
-				"StartTime:0001-01-01 00:00:00 +0000 UTC, " +
+			"&Event{ID:123, TaskName:happy, Success:false, Suppressed:false, " +
+				"Degraded:false, Expired:false, StartTime:0001-01-01 00:00:00 +0000 UTC, " +
 				"EndTime:0001-01-01 00:00:00 +0000 UTC, EventError:&{error!}, " +
+				"RenderedFiles:[], " +
+				"PlanSaved:false, " +
+				"Run:(*RunMetadata)(nil), " +
 				"Config:&Config{Providers:[local], Services:[web api], Source:/my-module}}",
 		},
+		{
+			"run metadata",
+			&Event{
+				ID:       "123",
+				TaskName: "happy",
+				Success:  true,
+				Run: &RunMetadata{
+					Changes:          &ChangeSummary{Add: 1, Change: 2, Destroy: 3},
+					TerraformVersion: "1.2.3",
+					ModuleVersion:    "0.1.0",
+					ApplyDuration:    2 * time.Second,
+				},
+			},
+			"&Event{ID:123, TaskName:happy, Success:true, Suppressed:false, " +
+				"Degraded:false, Expired:false, StartTime:0001-01-01 00:00:00 +0000 UTC, " +
+				"EndTime:0001-01-01 00:00:00 +0000 UTC, EventError:%!s(*event.Error=<nil>), " +
+				"RenderedFiles:[], " +
+				"PlanSaved:false, " +
+				"Run:&RunMetadata{Changes:&ChangeSummary{Add:1, Change:2, Destroy:3}, " +
+				"TerraformVersion:1.2.3, ModuleVersion:0.1.0, ApplyDuration:2s}, " +
+				"Config:(*Config)(nil)}",
+		},
 	}
 
 	for _, tc := range cases {
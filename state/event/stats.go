@@ -0,0 +1,28 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package event
+
+import "time"
+
+// StatsWindow is the window of the rolling statistics of the runs of a task
+const StatsWindow = 24 * time.Hour
+
+// Stats are the rolling statistics of the runs of a task. Events that did
+// not run the task, i.e. suppressed and expired events, are not runs.
+type Stats struct {
+	// Runs and Successes are the number of runs and the number of successful
+	// runs of the task within the last 24 hours. SuccessRate is the ratio of
+	// successful runs, between 0 and 1, and is 0 if the task has not run.
+	Runs        int     `json:"runs_24h"`
+	Successes   int     `json:"successes_24h"`
+	SuccessRate float64 `json:"success_rate_24h"`
+
+	// AverageDuration is the average duration of the runs of the task within
+	// the last 24 hours
+	AverageDuration time.Duration `json:"average_duration_24h"`
+
+	// FailureStreak is the number of consecutive failed runs since the last
+	// successful run of the task, regardless of the window
+	FailureStreak int `json:"failure_streak"`
+}
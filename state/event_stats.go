@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/state/event"
+)
+
+// maxStatsRuns is the maximum number of runs kept per task for the rolling
+// statistics. It bounds the memory of tasks that run very frequently.
+const maxStatsRuns = 10000
+
+// taskStats tracks the runs of a task within the window of the rolling
+// statistics. Unlike the events of a task, which are limited to the latest
+// events, the runs are kept for the whole window.
+type taskStats struct {
+	runs          []statsRun // oldest first
+	failureStreak int
+}
+
+// statsRun is the result of a run of a task
+type statsRun struct {
+	end      time.Time
+	success  bool
+	duration time.Duration
+}
+
+// add records the run of the event. Events that did not run the task are
+// ignored. Runs that ended before the window of the event are removed.
+func (s *taskStats) add(e event.Event) {
+	if e.Suppressed || e.Expired {
+		return
+	}
+
+	if e.Success {
+		s.failureStreak = 0
+	} else {
+		s.failureStreak++
+	}
+
+	var duration time.Duration
+	if !e.StartTime.IsZero() && e.EndTime.After(e.StartTime) {
+		duration = e.EndTime.Sub(e.StartTime)
+	}
+	s.runs = append(s.runs, statsRun{
+		end:      e.EndTime,
+		success:  e.Success,
+		duration: duration,
+	})
+
+	cutoff := e.EndTime.Add(-event.StatsWindow)
+	i := 0
+	for i < len(s.runs) && (s.runs[i].end.Before(cutoff) || len(s.runs)-i > maxStatsRuns) {
+		i++
+	}
+	if i > 0 {
+		s.runs = append(s.runs[:0:0], s.runs[i:]...)
+	}
+}
+
+// stats returns the statistics of the runs within the window before now
+func (s *taskStats) stats(now time.Time) event.Stats {
+	stats := event.Stats{FailureStreak: s.failureStreak}
+
+	cutoff := now.Add(-event.StatsWindow)
+	var total time.Duration
+	for _, r := range s.runs {
+		if r.end.Before(cutoff) {
+			continue
+		}
+		stats.Runs++
+		if r.success {
+			stats.Successes++
+		}
+		total += r.duration
+	}
+
+	if stats.Runs > 0 {
+		stats.SuccessRate = float64(stats.Successes) / float64(stats.Runs)
+		stats.AverageDuration = total / time.Duration(stats.Runs)
+	}
+	return stats
+}
@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/state/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_taskStats(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2022, time.March, 2, 12, 0, 0, 0, time.UTC)
+	run := func(ago time.Duration, success bool, duration time.Duration) event.Event {
+		end := now.Add(-ago)
+		return event.Event{
+			TaskName:  "task",
+			Success:   success,
+			StartTime: end.Add(-duration),
+			EndTime:   end,
+		}
+	}
+
+	t.Run("no runs", func(t *testing.T) {
+		var s taskStats
+		assert.Equal(t, event.Stats{}, s.stats(now))
+	})
+
+	t.Run("rolling window", func(t *testing.T) {
+		var s taskStats
+		s.add(run(30*time.Hour, true, time.Hour)) // outside of the window
+		s.add(run(3*time.Hour, true, 4*time.Second))
+		s.add(run(2*time.Hour, false, 2*time.Second))
+		s.add(event.Event{TaskName: "task", Suppressed: true, EndTime: now})
+		s.add(run(time.Hour, true, 3*time.Second))
+		s.add(run(time.Minute, false, 1*time.Second))
+		s.add(event.Event{TaskName: "task", Expired: true, EndTime: now})
+
+		assert.Equal(t, event.Stats{
+			Runs:            4,
+			Successes:       2,
+			SuccessRate:     0.5,
+			AverageDuration: 2500 * time.Millisecond,
+			FailureStreak:   1,
+		}, s.stats(now))
+		assert.Len(t, s.runs, 4, "expected run outside of the window to be removed")
+
+		// runs age out of the window as time passes
+		later := s.stats(now.Add(event.StatsWindow - 90*time.Minute))
+		assert.Equal(t, 2, later.Runs)
+		assert.Equal(t, 1, later.FailureStreak)
+	})
+
+	t.Run("failure streak", func(t *testing.T) {
+		var s taskStats
+		s.add(run(4*time.Hour, true, time.Second))
+		s.add(run(3*time.Hour, false, time.Second))
+		s.add(run(2*time.Hour, false, time.Second))
+		s.add(run(time.Hour, false, time.Second))
+		assert.Equal(t, 3, s.stats(now).FailureStreak)
+
+		s.add(run(time.Minute, true, time.Second))
+		assert.Equal(t, 0, s.stats(now).FailureStreak)
+	})
+}
+
+func Test_eventStorage_Stats(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	storage := newEventStorage()
+
+	_, ok := storage.Stats("task", now)
+	assert.False(t, ok)
+
+	for i := 0; i < defaultEventCountLimit+2; i++ {
+		err := storage.Add(event.Event{
+			TaskName:  "task",
+			Success:   i%2 == 0,
+			StartTime: now.Add(-time.Second),
+			EndTime:   now,
+		})
+		assert.NoError(t, err)
+	}
+
+	// statistics are not limited to the events stored
+	stats, ok := storage.Stats("task", now)
+	assert.True(t, ok)
+	assert.Equal(t, defaultEventCountLimit+2, stats.Runs)
+	assert.Equal(t, time.Second, stats.AverageDuration)
+
+	storage.Delete("task")
+	_, ok = storage.Stats("task", now)
+	assert.False(t, ok)
+
+	storage.Set("task", []event.Event{
+		{TaskName: "task", Success: false, EndTime: now},
+		{TaskName: "task", Success: true, EndTime: now.Add(-time.Minute)},
+	})
+	stats, ok = storage.Stats("task", now)
+	assert.True(t, ok)
+	assert.Equal(t, 2, stats.Runs)
+	assert.Equal(t, 1, stats.FailureStreak)
+}
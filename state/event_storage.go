@@ -5,26 +5,49 @@ package state
 
 import (
 	"fmt"
+	"hash/fnv"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/consul-terraform-sync/state/event"
 )
 
-const defaultEventCountLimit = 5
+const (
+	defaultEventCountLimit = 5
 
-// eventStorage is the storage for events
-type eventStorage struct {
-	mu *sync.RWMutex
+	// defaultEventShardCount is the number of shards of the event storage.
+	// Tasks are distributed across shards by task name so that events for
+	// different tasks can be added and read concurrently.
+	defaultEventShardCount = 32
+)
 
-	events map[string][]event.Event // taskname => events
+// eventStorage is the storage for events. Events are stored in a ring buffer
+// per task, and the rings are distributed across shards that are each guarded
+// by their own lock. The rolling statistics of the runs of each task are
+// tracked alongside the events.
+type eventStorage struct {
+	shards []*eventShard
 	limit  int
 }
 
+// eventShard is a shard of the event storage
+type eventShard struct {
+	mu     sync.RWMutex
+	events map[string]*eventRing // taskname => events
+	stats  map[string]*taskStats // taskname => statistics
+}
+
 // newEventStorage returns a new storage for event
 func newEventStorage() *eventStorage {
+	shards := make([]*eventShard, defaultEventShardCount)
+	for i := range shards {
+		shards[i] = &eventShard{
+			events: make(map[string]*eventRing),
+			stats:  make(map[string]*taskStats),
+		}
+	}
 	return &eventStorage{
-		mu:     &sync.RWMutex{},
-		events: make(map[string][]event.Event),
+		shards: shards,
 		limit:  defaultEventCountLimit,
 	}
 }
@@ -35,15 +58,23 @@ func (s *eventStorage) Add(e event.Event) error {
 		return fmt.Errorf("error adding event: taskname cannot be empty %s", e.GoString())
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	shard := s.shard(e.TaskName)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
 
-	events := s.events[e.TaskName]
-	events = append([]event.Event{e}, events...) // prepend
-	if len(events) > s.limit {
-		events = events[:len(events)-1]
+	ring, ok := shard.events[e.TaskName]
+	if !ok {
+		ring = newEventRing(s.limit)
+		shard.events[e.TaskName] = ring
 	}
-	s.events[e.TaskName] = events
+	ring.push(e)
+
+	stats, ok := shard.stats[e.TaskName]
+	if !ok {
+		stats = &taskStats{}
+		shard.stats[e.TaskName] = stats
+	}
+	stats.add(e)
 	return nil
 }
 
@@ -51,44 +82,129 @@ func (s *eventStorage) Add(e event.Event) error {
 // events for all tasks. Returned events are sorted in reverse chronological
 // order based on the end time.
 func (s *eventStorage) Read(taskName string) map[string][]event.Event {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
-	data := make(map[string][]event.Event)
+	ret := make(map[string][]event.Event)
 	if taskName != "" {
-		if e, ok := s.events[taskName]; ok {
-			data[taskName] = e
+		if events := s.taskEvents(taskName); events != nil {
+			ret[taskName] = events
 		}
-	} else {
-		data = s.events
+		return ret
 	}
 
-	ret := make(map[string][]event.Event)
-	for k, v := range data {
-		events := make([]event.Event, len(v))
-		copy(events, v)
-		ret[k] = events
+	// Shards are read one at a time so that reading events for all tasks does
+	// not block adding events to tasks of other shards.
+	for _, shard := range s.shards {
+		shard.mu.RLock()
+		for name, ring := range shard.events {
+			ret[name] = ring.list()
+		}
+		shard.mu.RUnlock()
 	}
 	return ret
 }
 
 // Delete removes all events for a task name.
 func (s *eventStorage) Delete(taskName string) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	if taskName != "" {
-		delete(s.events, taskName)
+	if taskName == "" {
+		return
 	}
+
+	shard := s.shard(taskName)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	delete(shard.events, taskName)
+	delete(shard.stats, taskName)
 }
 
 // Set overwrites all events for a task name.
-// Any events exceeding the configured limit will be removed.
+// Any events exceeding the configured limit will be removed. The statistics
+// of the task are recomputed from the events.
 func (s *eventStorage) Set(taskName string, events []event.Event) {
 	if len(events) > s.limit {
 		events = events[0:s.limit]
 	}
-	eventsCopy := make([]event.Event, len(events))
-	copy(eventsCopy, events)
-	s.events[taskName] = eventsCopy
+
+	ring := newEventRing(s.limit)
+	stats := &taskStats{}
+	// events are ordered latest first, push the oldest first
+	for i := len(events) - 1; i >= 0; i-- {
+		ring.push(events[i])
+		stats.add(events[i])
+	}
+
+	shard := s.shard(taskName)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	shard.events[taskName] = ring
+	shard.stats[taskName] = stats
+}
+
+// Stats returns the rolling statistics of the runs of a task as of now.
+// Returns false if no events have been added for the task.
+func (s *eventStorage) Stats(taskName string, now time.Time) (event.Stats, bool) {
+	shard := s.shard(taskName)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+
+	stats, ok := shard.stats[taskName]
+	if !ok {
+		return event.Stats{}, false
+	}
+	return stats.stats(now), true
+}
+
+// taskEvents returns a copy of the events for a task name, latest first.
+// Returns nil if there are no events stored for the task.
+func (s *eventStorage) taskEvents(taskName string) []event.Event {
+	shard := s.shard(taskName)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+
+	ring, ok := shard.events[taskName]
+	if !ok {
+		return nil
+	}
+	return ring.list()
+}
+
+// shard returns the shard that stores the events for a task name
+func (s *eventStorage) shard(taskName string) *eventShard {
+	h := fnv.New32a()
+	h.Write([]byte(taskName))
+	return s.shards[h.Sum32()%uint32(len(s.shards))]
+}
+
+// eventRing is a fixed size ring buffer of events. Once the ring is full,
+// adding an event overwrites the oldest event.
+type eventRing struct {
+	buf  []event.Event
+	next int // index to write the next event
+	size int // number of events stored
+}
+
+// newEventRing returns a new ring that stores up to limit events
+func newEventRing(limit int) *eventRing {
+	return &eventRing{buf: make([]event.Event, limit)}
+}
+
+// push adds an event as the latest event of the ring
+func (r *eventRing) push(e event.Event) {
+	if len(r.buf) == 0 {
+		return
+	}
+
+	r.buf[r.next] = e
+	r.next = (r.next + 1) % len(r.buf)
+	if r.size < len(r.buf) {
+		r.size++
+	}
+}
+
+// list returns a copy of the events of the ring, latest first
+func (r *eventRing) list() []event.Event {
+	events := make([]event.Event, r.size)
+	for i := range events {
+		idx := (r.next - 1 - i + len(r.buf)) % len(r.buf)
+		events[i] = r.buf[idx]
+	}
+	return events
 }
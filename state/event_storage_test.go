@@ -5,6 +5,7 @@ package state
 
 import (
 	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/hashicorp/consul-terraform-sync/state/event"
@@ -39,7 +40,7 @@ func Test_eventStorage_Add(t *testing.T) {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
-				events := storage.events[tc.event.TaskName]
+				events := storage.taskEvents(tc.event.TaskName)
 				assert.Len(t, events, 1)
 				e := events[0]
 				assert.Equal(t, tc.event, e)
@@ -54,21 +55,21 @@ func Test_eventStorage_Add(t *testing.T) {
 		// fill storage
 		err := storage.Add(event.Event{ID: "1", TaskName: "task"})
 		require.NoError(t, err)
-		assert.Len(t, storage.events["task"], 1)
+		assert.Len(t, storage.taskEvents("task"), 1)
 
 		err = storage.Add(event.Event{ID: "2", TaskName: "task"})
 		require.NoError(t, err)
-		assert.Len(t, storage.events["task"], 2)
+		assert.Len(t, storage.taskEvents("task"), 2)
 
 		// check storage did not grow beyond limit
 		err = storage.Add(event.Event{ID: "3", TaskName: "task"})
 		require.NoError(t, err)
-		assert.Len(t, storage.events["task"], 2)
+		assert.Len(t, storage.taskEvents("task"), 2)
 
 		// confirm events in storage
-		event3 := storage.events["task"][0]
+		event3 := storage.taskEvents("task")[0]
 		assert.Equal(t, "3", event3.ID)
-		event2 := storage.events["task"][1]
+		event2 := storage.taskEvents("task")[1]
 		assert.Equal(t, "2", event2.ID)
 	})
 }
@@ -244,7 +245,9 @@ func Test_eventStorage_Set(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			storage := newEventStorage()
-			storage.events = tc.initialState
+			for name, events := range tc.initialState {
+				storage.Set(name, events)
+			}
 			storage.Set(tc.inputTask, tc.inputValues)
 			after := storage.Read("")
 			assert.Equal(t, tc.expected, after)
@@ -254,3 +257,83 @@ func Test_eventStorage_Set(t *testing.T) {
 		})
 	}
 }
+
+func Test_eventStorage_Concurrent(t *testing.T) {
+	storage := newEventStorage()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		taskName := fmt.Sprintf("task_%d", i)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				err := storage.Add(event.Event{ID: fmt.Sprint(j), TaskName: taskName})
+				assert.NoError(t, err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				storage.Read("")
+				storage.Read(taskName)
+			}
+		}()
+	}
+	wg.Wait()
+
+	actual := storage.Read("")
+	require.Len(t, actual, 50)
+	for name, events := range actual {
+		require.Len(t, events, defaultEventCountLimit, name)
+		// latest first
+		assert.Equal(t, "19", events[0].ID, name)
+		assert.Equal(t, "15", events[defaultEventCountLimit-1].ID, name)
+	}
+}
+
+func Benchmark_eventStorage_Add(b *testing.B) {
+	for _, taskCount := range []int{10, 500, 5000} {
+		b.Run(fmt.Sprintf("tasks_%d", taskCount), func(b *testing.B) {
+			storage := newEventStorage()
+			names := make([]string, taskCount)
+			for i := range names {
+				names[i] = fmt.Sprintf("task_%d", i)
+			}
+
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					storage.Add(event.Event{TaskName: names[i%taskCount]})
+					i++
+				}
+			})
+		})
+	}
+}
+
+func Benchmark_eventStorage_Read(b *testing.B) {
+	for _, taskCount := range []int{10, 500, 5000} {
+		storage := newEventStorage()
+		for i := 0; i < taskCount; i++ {
+			for j := 0; j < defaultEventCountLimit; j++ {
+				storage.Add(event.Event{TaskName: fmt.Sprintf("task_%d", i)})
+			}
+		}
+
+		b.Run(fmt.Sprintf("one_task_%d", taskCount), func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					storage.Read("task_0")
+				}
+			})
+		})
+
+		b.Run(fmt.Sprintf("all_tasks_%d", taskCount), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				storage.Read("")
+			}
+		})
+	}
+}
@@ -5,9 +5,11 @@ package state
 
 import (
 	"sync"
+	"time"
 
 	"github.com/hashicorp/consul-terraform-sync/config"
 	"github.com/hashicorp/consul-terraform-sync/state/event"
+	"github.com/hashicorp/consul-terraform-sync/state/revision"
 )
 
 var (
@@ -16,8 +18,9 @@ var (
 
 // InMemoryStore implements the CTS state Store interface.
 type InMemoryStore struct {
-	conf   *configStorage
-	events *eventStorage
+	conf      *configStorage
+	events    *eventStorage
+	revisions *revisionStorage
 }
 
 // configStorage is the storage for the configuration with its own mutex lock
@@ -34,8 +37,9 @@ func NewInMemoryStore(conf *config.Config) *InMemoryStore {
 	}
 
 	return &InMemoryStore{
-		conf:   &configStorage{Config: *conf.Copy()},
-		events: newEventStorage(),
+		conf:      &configStorage{Config: *conf.Copy()},
+		events:    newEventStorage(),
+		revisions: newRevisionStorage(),
 	}
 }
 
@@ -150,6 +154,25 @@ func (s *InMemoryStore) AddTaskEvent(event event.Event) error {
 	return s.events.Add(event)
 }
 
+// GetTaskStats returns the rolling statistics of the runs of a task. Returns
+// false if there are no events for the task
+func (s *InMemoryStore) GetTaskStats(taskName string) (event.Stats, bool) {
+	return s.events.Stats(taskName, time.Now())
+}
+
+// GetTaskRevisions returns the revisions of a task's configuration, latest
+// first. Revisions are kept after the task is deleted
+func (s *InMemoryStore) GetTaskRevisions(taskName string) []revision.Revision {
+	return s.revisions.Read(taskName)
+}
+
+// AddTaskRevision records a revision of the task configuration made by the
+// actor
+func (s *InMemoryStore) AddTaskRevision(taskConf config.TaskConfig, actor string) error {
+	_, err := s.revisions.Add(taskConf, actor)
+	return err
+}
+
 // setTaskEvents sets all the events for a given task.
 func (s *InMemoryStore) setTaskEvents(taskName string, events []event.Event) {
 	s.events.Set(taskName, events)
@@ -27,7 +27,8 @@ func Test_NewInMemoryStore(t *testing.T) {
 				conf: &configStorage{
 					Config: *config.DefaultConfig(),
 				},
-				events: newEventStorage(),
+				events:    newEventStorage(),
+				revisions: newRevisionStorage(),
 			},
 		},
 		{
@@ -41,7 +42,8 @@ func Test_NewInMemoryStore(t *testing.T) {
 						Port: config.Int(1234),
 					},
 				},
-				events: newEventStorage(),
+				events:    newEventStorage(),
+				revisions: newRevisionStorage(),
 			},
 		},
 	}
@@ -457,7 +459,7 @@ func Test_InMemoryStore_AddTaskEvent(t *testing.T) {
 			actualEvents := actual[taskName]
 			exists := false
 			for _, actualEvent := range actualEvents {
-				if actualEvent == tc.event {
+				if assert.ObjectsAreEqual(tc.event, actualEvent) {
 					exists = true
 				}
 			}
@@ -466,3 +468,36 @@ func Test_InMemoryStore_AddTaskEvent(t *testing.T) {
 		})
 	}
 }
+
+func Test_InMemoryStore_TaskRevisions(t *testing.T) {
+	t.Parallel()
+
+	store := NewInMemoryStore(nil)
+	assert.Empty(t, store.GetTaskRevisions("task_a"))
+
+	err := store.AddTaskRevision(config.TaskConfig{
+		Name:    config.String("task_a"),
+		Enabled: config.Bool(true),
+	}, "config")
+	require.NoError(t, err)
+	err = store.AddTaskRevision(config.TaskConfig{
+		Name:    config.String("task_a"),
+		Enabled: config.Bool(false),
+	}, "api")
+	require.NoError(t, err)
+
+	// revisions are kept when the task is deleted
+	require.NoError(t, store.DeleteTask("task_a"))
+
+	revs := store.GetTaskRevisions("task_a")
+	require.Len(t, revs, 2)
+	assert.Equal(t, 2, revs[0].ID)
+	assert.Equal(t, "api", revs[0].Actor)
+	assert.False(t, *revs[0].Config.Enabled)
+	assert.Equal(t, 1, revs[1].ID)
+	assert.Equal(t, "config", revs[1].Actor)
+	assert.True(t, *revs[1].Config.Enabled)
+
+	err = store.AddTaskRevision(config.TaskConfig{}, "api")
+	assert.Error(t, err)
+}
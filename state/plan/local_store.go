@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package plan
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const (
+	// Names of the files for an artifact within the artifact's directory
+	planFilename     = "tfplan"
+	planJSONFilename = "tfplan.json"
+
+	// Permissions for created directories and files
+	dirPerms  = os.FileMode(0750) // drwxr-x---
+	filePerms = os.FileMode(0640) // -rw-r-----
+)
+
+var _ Store = (*LocalStore)(nil)
+
+// LocalStore stores plan artifacts in a directory on the local filesystem.
+// Artifacts are stored at <path>/<task name>/<event ID>/.
+type LocalStore struct {
+	path string
+}
+
+// NewLocalStore returns a store that persists plan artifacts under the path
+func NewLocalStore(path string) *LocalStore {
+	return &LocalStore{path: path}
+}
+
+// Save writes the plan file and its JSON representation to the artifact's
+// directory
+func (s *LocalStore) Save(artifact Artifact) error {
+	if err := validateKey(artifact.TaskName, artifact.EventID); err != nil {
+		return err
+	}
+
+	dir := filepath.Join(s.path, artifact.TaskName, artifact.EventID)
+	if err := os.MkdirAll(dir, dirPerms); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, planFilename), artifact.Plan, filePerms); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, planJSONFilename), artifact.JSON, filePerms)
+}
+
+// Get reads the plan file and its JSON representation from the artifact's
+// directory
+func (s *LocalStore) Get(taskName, eventID string) (Artifact, error) {
+	if err := validateKey(taskName, eventID); err != nil {
+		return Artifact{}, err
+	}
+
+	dir := filepath.Join(s.path, taskName, eventID)
+	planBytes, err := os.ReadFile(filepath.Join(dir, planFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Artifact{}, ErrNotFound
+		}
+		return Artifact{}, err
+	}
+
+	jsonBytes, err := os.ReadFile(filepath.Join(dir, planJSONFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Artifact{}, ErrNotFound
+		}
+		return Artifact{}, err
+	}
+
+	return Artifact{
+		TaskName: taskName,
+		EventID:  eventID,
+		Plan:     planBytes,
+		JSON:     jsonBytes,
+	}, nil
+}
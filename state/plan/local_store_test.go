@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package plan
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore_SaveGet(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	s := NewLocalStore(dir)
+
+	artifact := Artifact{
+		TaskName: "task_a",
+		EventID:  "123",
+		Plan:     []byte("binary plan"),
+		JSON:     []byte(`{"format_version":"1.1"}`),
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := s.Get("task_a", "does-not-exist")
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("save and get", func(t *testing.T) {
+		err := s.Save(artifact)
+		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join(dir, "task_a", "123", "tfplan"))
+		assert.FileExists(t, filepath.Join(dir, "task_a", "123", "tfplan.json"))
+
+		actual, err := s.Get("task_a", "123")
+		require.NoError(t, err)
+		assert.Equal(t, artifact, actual)
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		updated := artifact
+		updated.JSON = []byte(`{"format_version":"1.2"}`)
+		err := s.Save(updated)
+		require.NoError(t, err)
+
+		actual, err := s.Get("task_a", "123")
+		require.NoError(t, err)
+		assert.Equal(t, updated, actual)
+	})
+}
+
+func TestLocalStore_InvalidKey(t *testing.T) {
+	t.Parallel()
+
+	s := NewLocalStore(t.TempDir())
+
+	cases := []struct {
+		name     string
+		taskName string
+		eventID  string
+	}{
+		{"empty task name", "", "123"},
+		{"empty event ID", "task_a", ""},
+		{"parent directory", "..", "123"},
+		{"path separator", "task_a", "../../etc"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := s.Save(Artifact{TaskName: tc.taskName, EventID: tc.eventID})
+			assert.Error(t, err)
+
+			_, err = s.Get(tc.taskName, tc.eventID)
+			assert.Error(t, err)
+			assert.NotErrorIs(t, err, ErrNotFound)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNotFound is returned when a plan artifact does not exist in the store
+var ErrNotFound = errors.New("plan artifact not found")
+
+// Artifact is the Terraform plan saved for a run of a task. It is identified
+// by the task name and the ID of the event that recorded the run.
+type Artifact struct {
+	TaskName string
+	EventID  string
+
+	// Plan is the binary Terraform plan file
+	Plan []byte
+
+	// JSON is the JSON representation of the plan file
+	JSON []byte
+}
+
+// Store persists the plan artifacts for task runs
+type Store interface {
+	// Save persists the plan artifact. Saving an artifact with the same task
+	// name and event ID as an existing artifact overwrites it.
+	Save(artifact Artifact) error
+
+	// Get returns the plan artifact for a task's event. Returns ErrNotFound
+	// if the artifact does not exist.
+	Get(taskName, eventID string) (Artifact, error)
+}
+
+// validateKey checks that the task name and event ID are usable as keys for a
+// store and do not reference any other location
+func validateKey(taskName, eventID string) error {
+	for _, k := range []string{taskName, eventID} {
+		if k == "" || k == "." || k == ".." || strings.ContainsAny(k, `/\`) {
+			return fmt.Errorf("invalid plan artifact key for task %q "+
+				"and event %q", taskName, eventID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package revision
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+)
+
+const (
+	// ActorConfig is the actor of revisions for tasks configured in the CTS
+	// configuration file
+	ActorConfig = "config"
+
+	// ActorAPI is the actor of revisions for tasks created or updated through
+	// the CTS API
+	ActorAPI = "api"
+
+	// ActorExpiration is the actor of revisions for tasks disabled when they
+	// expired
+	ActorExpiration = "expiration"
+)
+
+type actorContextKey struct{}
+
+// Revision is a snapshot of a task's configuration. A revision is recorded
+// each time a task is created or its configuration is updated.
+type Revision struct {
+	// ID identifies the revision for the task. IDs increase with each
+	// revision of the task starting at 1.
+	ID        int       `json:"id"`
+	TaskName  string    `json:"task_name"`
+	Timestamp time.Time `json:"timestamp"`
+
+	// Actor is the source of the change that resulted in the revision
+	Actor string `json:"actor"`
+
+	Config config.TaskConfig `json:"-"`
+}
+
+// WithActor returns a copy of the context with the actor of changes made to
+// tasks within the context
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext returns the actor of the context. Changes without an actor
+// originate from the CTS configuration.
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorContextKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return ActorConfig
+}
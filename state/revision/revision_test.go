@@ -0,0 +1,20 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package revision
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActorFromContext(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	assert.Equal(t, ActorConfig, ActorFromContext(ctx))
+	assert.Equal(t, ActorAPI, ActorFromContext(WithActor(ctx, ActorAPI)))
+	assert.Equal(t, ActorConfig, ActorFromContext(WithActor(ctx, "")))
+}
@@ -0,0 +1,92 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/hashicorp/consul-terraform-sync/state/revision"
+)
+
+const defaultRevisionCountLimit = 10
+
+// revisionStorage is the storage for the revisions of task configurations.
+// Revisions are kept after a task is deleted so that a deleted task can be
+// restored.
+type revisionStorage struct {
+	mu        sync.RWMutex
+	revisions map[string]*taskRevisions // taskname => revisions
+	limit     int
+}
+
+// taskRevisions are the revisions of a task, oldest first
+type taskRevisions struct {
+	lastID    int
+	revisions []revision.Revision
+}
+
+// newRevisionStorage returns a new storage for revisions
+func newRevisionStorage() *revisionStorage {
+	return &revisionStorage{
+		revisions: make(map[string]*taskRevisions),
+		limit:     defaultRevisionCountLimit,
+	}
+}
+
+// Add adds a revision of the task configuration and manages the limit of
+// number of revisions stored per task. The oldest revisions are removed
+// first. Revision IDs are not reused for a task after they are removed.
+func (s *revisionStorage) Add(conf config.TaskConfig, actor string) (revision.Revision, error) {
+	taskName := config.StringVal(conf.Name)
+	if taskName == "" {
+		return revision.Revision{}, fmt.Errorf("error adding revision: " +
+			"taskname cannot be empty")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	revs, ok := s.revisions[taskName]
+	if !ok {
+		revs = &taskRevisions{}
+		s.revisions[taskName] = revs
+	}
+
+	revs.lastID++
+	rev := revision.Revision{
+		ID:        revs.lastID,
+		TaskName:  taskName,
+		Timestamp: time.Now(),
+		Actor:     actor,
+		Config:    *conf.Copy(),
+	}
+	revs.revisions = append(revs.revisions, rev)
+	if len(revs.revisions) > s.limit {
+		revs.revisions = revs.revisions[len(revs.revisions)-s.limit:]
+	}
+
+	return rev, nil
+}
+
+// Read returns a copy of the revisions for a task name sorted in reverse
+// chronological order
+func (s *revisionStorage) Read(taskName string) []revision.Revision {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	revs, ok := s.revisions[taskName]
+	if !ok {
+		return []revision.Revision{}
+	}
+
+	ret := make([]revision.Revision, len(revs.revisions))
+	for i, rev := range revs.revisions {
+		rev.Config = *rev.Config.Copy()
+		ret[len(ret)-1-i] = rev
+	}
+	return ret
+}
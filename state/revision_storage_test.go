@@ -0,0 +1,81 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package state
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_revisionStorage_Add(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty task name", func(t *testing.T) {
+		storage := newRevisionStorage()
+		_, err := storage.Add(config.TaskConfig{}, "config")
+		assert.Error(t, err)
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		storage := newRevisionStorage()
+		storage.limit = 3
+
+		for i := 0; i < 5; i++ {
+			rev, err := storage.Add(config.TaskConfig{
+				Name:        config.String("task"),
+				Description: config.String(fmt.Sprintf("description %d", i)),
+			}, "api")
+			require.NoError(t, err)
+			assert.Equal(t, i+1, rev.ID)
+		}
+
+		revs := storage.Read("task")
+		require.Len(t, revs, 3)
+		for i, rev := range revs {
+			// latest revisions first and IDs are not reused after removal
+			assert.Equal(t, 5-i, rev.ID)
+			assert.Equal(t, fmt.Sprintf("description %d", 4-i),
+				config.StringVal(rev.Config.Description))
+			assert.Equal(t, "task", rev.TaskName)
+			assert.False(t, rev.Timestamp.IsZero())
+		}
+	})
+
+	t.Run("stored config is copied", func(t *testing.T) {
+		storage := newRevisionStorage()
+		conf := config.TaskConfig{
+			Name:    config.String("task"),
+			Enabled: config.Bool(true),
+		}
+		_, err := storage.Add(conf, "config")
+		require.NoError(t, err)
+
+		*conf.Enabled = false
+		revs := storage.Read("task")
+		require.Len(t, revs, 1)
+		assert.True(t, *revs[0].Config.Enabled)
+
+		*revs[0].Config.Enabled = false
+		revs = storage.Read("task")
+		assert.True(t, *revs[0].Config.Enabled)
+	})
+}
+
+func Test_revisionStorage_Read(t *testing.T) {
+	t.Parallel()
+
+	storage := newRevisionStorage()
+	_, err := storage.Add(config.TaskConfig{Name: config.String("task_a")}, "config")
+	require.NoError(t, err)
+	_, err = storage.Add(config.TaskConfig{Name: config.String("task_b")}, "config")
+	require.NoError(t, err)
+
+	assert.Len(t, storage.Read("task_a"), 1)
+	assert.Len(t, storage.Read("task_b"), 1)
+	assert.Empty(t, storage.Read("task_c"))
+}
@@ -6,6 +6,7 @@ package state
 import (
 	"github.com/hashicorp/consul-terraform-sync/config"
 	"github.com/hashicorp/consul-terraform-sync/state/event"
+	"github.com/hashicorp/consul-terraform-sync/state/revision"
 )
 
 //go:generate mockery --name=Store --filename=store.go  --output=../mocks/state
@@ -40,4 +41,16 @@ type Store interface {
 	// AddTaskEvent adds an event to the store for the task configured in the
 	// event
 	AddTaskEvent(event event.Event) error
+
+	// GetTaskStats returns the rolling statistics of the runs of a task.
+	// Returns false if there are no events for the task
+	GetTaskStats(taskName string) (event.Stats, bool)
+
+	// GetTaskRevisions returns the revisions of a task's configuration, latest
+	// first. Revisions are kept after the task is deleted
+	GetTaskRevisions(taskName string) []revision.Revision
+
+	// AddTaskRevision records a revision of the task configuration made by
+	// the actor
+	AddTaskRevision(taskConf config.TaskConfig, actor string) error
 }
@@ -0,0 +1,162 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package templates
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hashicorp/hcat"
+)
+
+var _ hcat.Renderer = (*FileRenderer)(nil)
+
+// bufferSize is the size of the buffers that are reused to compare rendered
+// contents with existing files
+const bufferSize = 32 * 1024
+
+// bufferPool holds the buffers to compare rendered contents with existing
+// files, so that the existing files are read in chunks instead of in full
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, bufferSize)
+		return &b
+	},
+}
+
+// FileRendererInput is the input to create a FileRenderer
+type FileRendererInput struct {
+	// Path is the path of the file to render to
+	Path string
+
+	// Perms is the file mode of the rendered file
+	Perms os.FileMode
+}
+
+// FileRenderer renders the contents of a template to a file. Unlike hcat's
+// file renderer, the existing file is streamed in chunks to determine
+// whether the contents changed rather than read into memory in full. This
+// keeps the memory usage of large rendered files, e.g. the variables of
+// tasks monitoring thousands of service instances, to the rendered contents.
+type FileRenderer struct {
+	path  string
+	perms os.FileMode
+}
+
+// NewFileRenderer creates a new file renderer
+func NewFileRenderer(i FileRendererInput) *FileRenderer {
+	return &FileRenderer{
+		path:  i.Path,
+		perms: i.Perms,
+	}
+}
+
+// Render writes the contents to the file if they are different from the
+// contents of the existing file. The file is replaced atomically.
+func (r *FileRenderer) Render(contents []byte) (hcat.RenderResult, error) {
+	equal, err := fileEqual(r.path, contents)
+	if err != nil {
+		return hcat.RenderResult{}, err
+	}
+	if equal {
+		return hcat.RenderResult{
+			WouldRender: true,
+		}, nil
+	}
+
+	if err := WriteFileAtomic(r.path, bytes.NewReader(contents), r.perms); err != nil {
+		return hcat.RenderResult{}, err
+	}
+	return hcat.RenderResult{
+		DidRender:   true,
+		WouldRender: true,
+	}, nil
+}
+
+// CopyFile copies the file at the source path to the destination path. The
+// file is streamed rather than read into memory and the destination is
+// replaced atomically.
+func CopyFile(dst, src string, perms os.FileMode) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return WriteFileAtomic(dst, f, perms)
+}
+
+// WriteFileAtomic writes the content of the reader to a temporary file in the
+// directory of the path and renames the temporary file to the path. Missing
+// parent directories are created.
+func WriteFileAtomic(path string, r io.Reader, perms os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp, perms); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// fileEqual returns true if the file at the path exists and has the same
+// contents. The file is compared in chunks with a reused buffer.
+func fileEqual(path string, contents []byte) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+	if info.Size() != int64(len(contents)) {
+		return false, nil
+	}
+
+	bp := bufferPool.Get().(*[]byte)
+	defer bufferPool.Put(bp)
+	buf := *bp
+
+	for offset := 0; offset < len(contents); {
+		n, err := io.ReadFull(f, buf)
+		if n > len(contents)-offset ||
+			!bytes.Equal(buf[:n], contents[offset:offset+n]) {
+			return false, nil
+		}
+		offset += n
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return offset == len(contents), nil
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
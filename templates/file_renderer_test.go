@@ -0,0 +1,91 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package templates
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileRenderer_Render(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nested", "terraform.tfvars")
+	r := NewFileRenderer(FileRendererInput{Path: path, Perms: 0640})
+
+	// larger than a buffer to compare the file in multiple chunks
+	contents := bytes.Repeat([]byte("services = {}\n"), bufferSize)
+
+	t.Run("new file", func(t *testing.T) {
+		result, err := r.Render(contents)
+		require.NoError(t, err)
+		assert.True(t, result.DidRender)
+		assert.True(t, result.WouldRender)
+
+		actual, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, contents, actual)
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		result, err := r.Render(contents)
+		require.NoError(t, err)
+		assert.False(t, result.DidRender)
+		assert.True(t, result.WouldRender)
+	})
+
+	t.Run("changed last chunk", func(t *testing.T) {
+		changed := append([]byte{}, contents...)
+		changed[len(changed)-2] = '!'
+
+		result, err := r.Render(changed)
+		require.NoError(t, err)
+		assert.True(t, result.DidRender)
+
+		actual, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, changed, actual)
+	})
+
+	t.Run("changed size", func(t *testing.T) {
+		result, err := r.Render([]byte("services = {}\n"))
+		require.NoError(t, err)
+		assert.True(t, result.DidRender)
+
+		entries, err := os.ReadDir(filepath.Dir(path))
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "temporary files should be removed")
+	})
+}
+
+func TestCopyFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "terraform.tfvars")
+	dst := filepath.Join(dir, "snapshot.tfvars")
+	require.NoError(t, os.WriteFile(src, []byte("services = {}\n"), 0644))
+
+	require.NoError(t, CopyFile(dst, src, 0600))
+	actual, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, "services = {}\n", string(actual))
+
+	info, err := os.Stat(dst)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	err = CopyFile(dst, filepath.Join(dir, "missing"), 0600)
+	assert.True(t, os.IsNotExist(err))
+}
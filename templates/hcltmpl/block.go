@@ -83,6 +83,32 @@ func (b *NamedBlock) Copy() NamedBlock {
 	}
 }
 
+// Override creates a copy of the NamedBlock with the attributes replaced by
+// the override values. Attributes that are not overridden are unchanged.
+func (b *NamedBlock) Override(overrides map[string]interface{}) NamedBlock {
+	o := b.Copy()
+	if len(overrides) == 0 {
+		return o
+	}
+
+	var raw map[string]interface{}
+	if b.rawConfig != nil {
+		raw = make(map[string]interface{}, len(b.rawConfig)+len(overrides))
+		for k, v := range b.rawConfig {
+			raw[k] = v
+		}
+	}
+
+	for k, v := range overrides {
+		o.Variables[k] = hcl2shim.HCL2ValueFromConfigValue(v)
+		if raw != nil {
+			raw[k] = v
+		}
+	}
+	o.rawConfig = raw
+	return o
+}
+
 // SortedAttributes returns a list of sorted attribute names
 func (b *NamedBlock) SortedAttributes() []string {
 	if b.blockKeysCache != nil {
@@ -42,30 +42,42 @@ func ContainsVaultSecret(s string) bool {
 // configuration.
 func LoadDynamicConfig(ctx context.Context, w tmpls.Watcher, r tmpls.Resolver,
 	config map[string]interface{}) (NamedBlock, error) {
+	block, _, err := LoadDynamicConfigWithTemplateIDs(ctx, w, r, config)
+	return block, err
+}
+
+// LoadDynamicConfigWithTemplateIDs loads the dynamic configuration like
+// LoadDynamicConfig and also returns the IDs of the templates registered with
+// the watcher to fetch the dynamic values. The watcher notifies the template
+// IDs when the dynamic values change.
+func LoadDynamicConfigWithTemplateIDs(ctx context.Context, w tmpls.Watcher,
+	r tmpls.Resolver, config map[string]interface{}) (NamedBlock, []string, error) {
 	block := NewNamedBlock(config)
 
 	// First pass, check if the block has any templated variables before continuing
 	// with slower processing
 	if !ContainsDynamicTemplate(fmt.Sprint(config)) {
-		return block, nil
+		return block, nil, nil
 	}
 
 	logging.Global().Named(logSystemName).Named(hcltmplSubsystemName).Info(
 		"evaluating dynamic configuration for block", "block_name", block.Name)
 
 	// Traverse all variables and nested variables to evaluate any dynamic values
+	var tmplIDs []string
 	for attrName, v := range block.Variables {
-		value, err := dynamicValue(ctx, w, r, v)
+		value, err := dynamicValue(ctx, w, r, v, &tmplIDs)
 		if err != nil {
-			return block, err
+			return block, tmplIDs, err
 		}
 		block.Variables[attrName] = value
 	}
 
-	return block, nil
+	return block, tmplIDs, nil
 }
 
-func dynamicValue(ctx context.Context, w tmpls.Watcher, r tmpls.Resolver, v cty.Value) (cty.Value, error) {
+func dynamicValue(ctx context.Context, w tmpls.Watcher, r tmpls.Resolver,
+	v cty.Value, tmplIDs *[]string) (cty.Value, error) {
 	select {
 	case <-ctx.Done():
 		return cty.Value{}, ctx.Err()
@@ -103,6 +115,7 @@ func dynamicValue(ctx context.Context, w tmpls.Watcher, r tmpls.Resolver, v cty.
 		})
 
 		w.Register(tmpl)
+		*tmplIDs = append(*tmplIDs, tmpl.ID())
 		rendered, err := renderDynamicValue(ctx, w, r, tmpl)
 		if err != nil {
 			return cty.Value{}, err
@@ -112,7 +125,7 @@ func dynamicValue(ctx context.Context, w tmpls.Watcher, r tmpls.Resolver, v cty.
 	case t.IsListType(), t.IsTupleType():
 		values := v.AsValueSlice()
 		for i, value := range values {
-			dValue, err := dynamicValue(ctx, w, r, value)
+			dValue, err := dynamicValue(ctx, w, r, value, tmplIDs)
 			if err != nil {
 				return cty.Value{}, err
 			}
@@ -123,7 +136,7 @@ func dynamicValue(ctx context.Context, w tmpls.Watcher, r tmpls.Resolver, v cty.
 	case t.IsMapType(), t.IsObjectType():
 		values := v.AsValueMap()
 		for attrName, value := range values {
-			dValue, err := dynamicValue(ctx, w, r, value)
+			dValue, err := dynamicValue(ctx, w, r, value, tmplIDs)
 			if err != nil {
 				return cty.Value{}, err
 			}
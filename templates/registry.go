@@ -0,0 +1,273 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package templates
+
+import (
+	"sync"
+
+	"github.com/hashicorp/hcat"
+)
+
+var _ Watcher = (*Registry)(nil)
+
+// DependencyStats are the statistics of the dependencies of the templates
+// registered with a watcher and how many of them are shared across templates.
+// Each unique dependency is a single blocking query to Consul regardless of
+// the number of templates that reference it.
+type DependencyStats struct {
+	// Templates is the number of registered templates
+	Templates int `json:"templates"`
+
+	// Dependencies is the number of unique dependencies of the templates
+	Dependencies int `json:"dependencies"`
+
+	// Shared is the number of dependencies that are referenced by more than
+	// one template
+	Shared int `json:"shared"`
+
+	// References is the number of dependencies referenced by each template,
+	// i.e. the number of blocking queries if dependencies were not shared
+	References int `json:"references"`
+
+	// Deduplicated is the number of blocking queries avoided by sharing
+	// dependencies across templates
+	Deduplicated int `json:"deduplicated"`
+}
+
+// Add returns the sum of the statistics. Dependencies of different watchers
+// are not shared, so the statistics of each watcher can be added together.
+func (s DependencyStats) Add(o DependencyStats) DependencyStats {
+	return DependencyStats{
+		Templates:    s.Templates + o.Templates,
+		Dependencies: s.Dependencies + o.Dependencies,
+		Shared:       s.Shared + o.Shared,
+		References:   s.References + o.References,
+		Deduplicated: s.Deduplicated + o.Deduplicated,
+	}
+}
+
+// dependencyLister is implemented by notifiers that record the dependencies
+// of their template, e.g. notifier.OnceNotifier
+type dependencyLister interface {
+	Dependencies() map[string]bool
+}
+
+// Registry wraps a Watcher to reference-count the registrations of templates
+// by their ID. The watcher shares the dependencies of templates, but it
+// tracks the ownership of the dependencies by template ID and registering a
+// template with an ID that is already registered errors. Multiple tasks can
+// have templates with the same ID, e.g. a temporary task created to inspect a
+// task. The Registry registers a single notifier for each template ID with
+// the watcher that notifies each of the holders of the ID. The template is
+// only deregistered or swept from the watcher once it is released by all of
+// its holders, so that releasing a template does not stop and restart the
+// blocking queries of the dependencies that are still used.
+type Registry struct {
+	Watcher
+
+	mu     sync.Mutex
+	shared map[string]*sharedNotifier // template ID => notifier of holders
+}
+
+// NewRegistry returns a new registry for the templates of the watcher
+func NewRegistry(w Watcher) *Registry {
+	return &Registry{
+		Watcher: w,
+		shared:  make(map[string]*sharedNotifier),
+	}
+}
+
+// Register registers the notifiers. A notifier with the ID of a template
+// that is already registered is added as a holder of the template and is
+// notified of the changes to the template's dependencies.
+func (r *Registry) Register(ns ...hcat.Notifier) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, n := range ns {
+		id := n.ID()
+		if s, ok := r.shared[id]; ok {
+			s.add(n)
+			continue
+		}
+
+		s := newSharedNotifier(n)
+		if err := r.Watcher.Register(s); err != nil && err != hcat.ErrRegistry {
+			return err
+		}
+		r.shared[id] = s
+	}
+	return nil
+}
+
+// Deregister releases the notifiers. The template of a notifier is
+// deregistered from the watcher once it is released by all of its holders.
+func (r *Registry) Deregister(ns ...hcat.Notifier) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, n := range ns {
+		s, ok := r.shared[n.ID()]
+		if !ok {
+			// not registered through the registry
+			r.Watcher.Deregister(n)
+			continue
+		}
+		if s.remove(n) > 0 {
+			continue
+		}
+		delete(r.shared, n.ID())
+		r.Watcher.Deregister(s)
+	}
+}
+
+// MarkForSweep marks the template for the next sweep. The template is only
+// marked with the watcher if it has no other holders.
+func (r *Registry) MarkForSweep(n hcat.IDer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s, ok := r.shared[n.ID()]; ok && s.holders() > 1 {
+		return
+	}
+	r.Watcher.MarkForSweep(n)
+}
+
+// Sweep releases the template. The dependencies of the template are only
+// swept from the watcher if the template has no other holders.
+func (r *Registry) Sweep(n hcat.IDer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s, ok := r.shared[n.ID()]; ok && s.remove(n) > 0 {
+		return
+	}
+
+	delete(r.shared, n.ID())
+	r.Watcher.Sweep(n)
+}
+
+// Holders returns the number of holders of the template with the ID
+func (r *Registry) Holders(tmplID string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s, ok := r.shared[tmplID]; ok {
+		return s.holders()
+	}
+	return 0
+}
+
+// DependencyStats returns the statistics of the dependencies of the
+// registered templates. Only the dependencies of templates whose notifiers
+// record their dependencies are counted, and a template's dependencies are
+// known once it has been executed.
+func (r *Registry) DependencyStats() DependencyStats {
+	r.mu.Lock()
+	templates := make([]*sharedNotifier, 0, len(r.shared))
+	for _, s := range r.shared {
+		templates = append(templates, s)
+	}
+	r.mu.Unlock()
+
+	refs := make(map[string]int)
+	var stats DependencyStats
+	for _, s := range templates {
+		stats.Templates++
+		for name := range s.dependencies() {
+			refs[name]++
+		}
+	}
+
+	stats.Dependencies = len(refs)
+	for _, count := range refs {
+		stats.References += count
+		if count > 1 {
+			stats.Shared++
+		}
+	}
+	stats.Deduplicated = stats.References - stats.Dependencies
+	return stats
+}
+
+// sharedNotifier is the notifier registered with the watcher for a template
+// ID. It notifies each of the holders of the template ID.
+type sharedNotifier struct {
+	id string
+
+	mu        sync.RWMutex
+	notifiers []hcat.Notifier
+}
+
+func newSharedNotifier(n hcat.Notifier) *sharedNotifier {
+	return &sharedNotifier{
+		id:        n.ID(),
+		notifiers: []hcat.Notifier{n},
+	}
+}
+
+// ID returns the ID of the template
+func (s *sharedNotifier) ID() string {
+	return s.id
+}
+
+// Notify notifies each of the holders and returns true if any of the holders
+// need to be triggered
+func (s *sharedNotifier) Notify(d interface{}) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	trigger := false
+	for _, n := range s.notifiers {
+		if n.Notify(d) {
+			trigger = true
+		}
+	}
+	return trigger
+}
+
+// dependencies returns the dependencies of the template recorded by the
+// holders. The holders share the same template content so the dependencies
+// of the first holder that records them are used.
+func (s *sharedNotifier) dependencies() map[string]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, n := range s.notifiers {
+		if l, ok := n.(dependencyLister); ok {
+			return l.Dependencies()
+		}
+	}
+	return nil
+}
+
+func (s *sharedNotifier) holders() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.notifiers)
+}
+
+func (s *sharedNotifier) add(n hcat.Notifier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notifiers = append(s.notifiers, n)
+}
+
+// remove removes the holder and returns the number of remaining holders. The
+// most recent holder is removed if the notifier is not a holder.
+func (s *sharedNotifier) remove(n hcat.IDer) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, holder := range s.notifiers {
+		if hcat.IDer(holder) == n {
+			s.notifiers = append(s.notifiers[:i], s.notifiers[i+1:]...)
+			return len(s.notifiers)
+		}
+	}
+	if len(s.notifiers) > 0 {
+		s.notifiers = s.notifiers[:len(s.notifiers)-1]
+	}
+	return len(s.notifiers)
+}
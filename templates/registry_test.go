@@ -0,0 +1,188 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package templates
+
+import (
+	"errors"
+	"testing"
+
+	mocks "github.com/hashicorp/consul-terraform-sync/mocks/templates"
+	"github.com/hashicorp/hcat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testNotifier is a notifier of a template that records its notifications
+type testNotifier struct {
+	id       string
+	deps     map[string]bool
+	trigger  bool
+	notified int
+}
+
+func (n *testNotifier) ID() string {
+	return n.id
+}
+
+func (n *testNotifier) Notify(interface{}) bool {
+	n.notified++
+	return n.trigger
+}
+
+func (n *testNotifier) Dependencies() map[string]bool {
+	return n.deps
+}
+
+func TestRegistry_Register(t *testing.T) {
+	t.Parallel()
+
+	t.Run("shared template", func(t *testing.T) {
+		w := new(mocks.Watcher)
+		w.On("Register", mock.Anything).Return(nil).Once()
+		r := NewRegistry(w)
+
+		a := &testNotifier{id: "tmpl"}
+		b := &testNotifier{id: "tmpl", trigger: true}
+		require.NoError(t, r.Register(a))
+		require.NoError(t, r.Register(b))
+		assert.Equal(t, 2, r.Holders("tmpl"))
+		w.AssertNumberOfCalls(t, "Register", 1)
+
+		// the watcher notifies each of the holders
+		registered := w.Calls[0].Arguments.Get(0).(hcat.Notifier)
+		assert.Equal(t, "tmpl", registered.ID())
+		assert.True(t, registered.Notify(nil))
+		assert.Equal(t, 1, a.notified)
+		assert.Equal(t, 1, b.notified)
+	})
+
+	t.Run("already registered with watcher", func(t *testing.T) {
+		w := new(mocks.Watcher)
+		w.On("Register", mock.Anything).Return(hcat.ErrRegistry)
+		r := NewRegistry(w)
+
+		require.NoError(t, r.Register(&testNotifier{id: "tmpl"}))
+		assert.Equal(t, 1, r.Holders("tmpl"))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		w := new(mocks.Watcher)
+		w.On("Register", mock.Anything).Return(errors.New("error"))
+		r := NewRegistry(w)
+
+		assert.Error(t, r.Register(&testNotifier{id: "tmpl"}))
+		assert.Zero(t, r.Holders("tmpl"))
+	})
+}
+
+func TestRegistry_Deregister(t *testing.T) {
+	t.Parallel()
+
+	w := new(mocks.Watcher)
+	w.On("Register", mock.Anything).Return(nil)
+	w.On("Deregister", mock.Anything).Return()
+	r := NewRegistry(w)
+
+	a := &testNotifier{id: "tmpl"}
+	b := &testNotifier{id: "tmpl"}
+	require.NoError(t, r.Register(a, b))
+
+	// the template is not deregistered while it has another holder
+	r.Deregister(b)
+	w.AssertNotCalled(t, "Deregister", mock.Anything)
+	assert.Equal(t, 1, r.Holders("tmpl"))
+
+	registered := w.Calls[0].Arguments.Get(0).(hcat.Notifier)
+	registered.Notify(nil)
+	assert.Equal(t, 1, a.notified)
+	assert.Zero(t, b.notified, "released holder should not be notified")
+
+	r.Deregister(a)
+	w.AssertCalled(t, "Deregister", registered)
+	assert.Zero(t, r.Holders("tmpl"))
+
+	// templates not registered through the registry are deregistered
+	other := &testNotifier{id: "other"}
+	r.Deregister(other)
+	w.AssertCalled(t, "Deregister", other)
+}
+
+func TestRegistry_Sweep(t *testing.T) {
+	t.Parallel()
+
+	w := new(mocks.Watcher)
+	w.On("Register", mock.Anything).Return(nil)
+	w.On("MarkForSweep", mock.Anything).Return()
+	w.On("Sweep", mock.Anything).Return()
+	r := NewRegistry(w)
+
+	a := &testNotifier{id: "tmpl"}
+	b := &testNotifier{id: "tmpl"}
+	require.NoError(t, r.Register(a, b))
+
+	// the dependencies are not swept while the template has another holder
+	r.MarkForSweep(a)
+	r.Sweep(a)
+	w.AssertNotCalled(t, "MarkForSweep", mock.Anything)
+	w.AssertNotCalled(t, "Sweep", mock.Anything)
+	assert.Equal(t, 1, r.Holders("tmpl"))
+
+	r.MarkForSweep(b)
+	r.Sweep(b)
+	w.AssertCalled(t, "MarkForSweep", b)
+	w.AssertCalled(t, "Sweep", b)
+	assert.Zero(t, r.Holders("tmpl"))
+}
+
+func TestRegistry_DependencyStats(t *testing.T) {
+	t.Parallel()
+
+	w := new(mocks.Watcher)
+	w.On("Register", mock.Anything).Return(nil)
+	r := NewRegistry(w)
+	assert.Equal(t, DependencyStats{}, r.DependencyStats())
+
+	require.NoError(t, r.Register(
+		&testNotifier{id: "task_a", deps: map[string]bool{
+			"health.service(web)": true,
+			"health.service(api)": true,
+		}},
+		&testNotifier{id: "task_b", deps: map[string]bool{
+			"health.service(web)": true,
+			"kv.block(key)":       false,
+		}},
+		&testNotifier{id: "task_c", deps: map[string]bool{
+			"health.service(web)": true,
+			"health.service(api)": false,
+		}},
+		// the holders of a template share its dependencies
+		&testNotifier{id: "task_c", deps: map[string]bool{
+			"health.service(web)": true,
+			"health.service(api)": false,
+		}},
+	))
+
+	assert.Equal(t, DependencyStats{
+		Templates:    3,
+		Dependencies: 3,
+		Shared:       2,
+		References:   6,
+		Deduplicated: 3,
+	}, r.DependencyStats())
+}
+
+func TestDependencyStats_Add(t *testing.T) {
+	t.Parallel()
+
+	a := DependencyStats{Templates: 2, Dependencies: 3, Shared: 1, References: 4, Deduplicated: 1}
+	b := DependencyStats{Templates: 1, Dependencies: 1, References: 1}
+	assert.Equal(t, DependencyStats{
+		Templates:    3,
+		Dependencies: 4,
+		Shared:       1,
+		References:   5,
+		Deduplicated: 1,
+	}, a.Add(b))
+}
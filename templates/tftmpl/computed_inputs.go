@@ -0,0 +1,164 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tftmpl
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+// computedInputFuncs are the functions that are available to the expressions
+// of computed inputs. The functions are a subset of the Terraform functions
+// with the same names.
+var computedInputFuncs = map[string]function.Function{
+	"coalesce":  stdlib.CoalesceFunc,
+	"compact":   stdlib.CompactFunc,
+	"concat":    stdlib.ConcatFunc,
+	"contains":  stdlib.ContainsFunc,
+	"distinct":  stdlib.DistinctFunc,
+	"flatten":   stdlib.FlattenFunc,
+	"format":    stdlib.FormatFunc,
+	"join":      stdlib.JoinFunc,
+	"keys":      stdlib.KeysFunc,
+	"length":    lengthFunc,
+	"lookup":    stdlib.LookupFunc,
+	"lower":     stdlib.LowerFunc,
+	"merge":     stdlib.MergeFunc,
+	"regex":     stdlib.RegexFunc,
+	"replace":   stdlib.ReplaceFunc,
+	"sort":      stdlib.SortFunc,
+	"split":     stdlib.SplitFunc,
+	"trimspace": stdlib.TrimSpaceFunc,
+	"upper":     stdlib.UpperFunc,
+	"values":    stdlib.ValuesFunc,
+}
+
+// lengthFunc returns the length of a collection, structural value, or
+// string. Like the Terraform function, objects are supported in addition to
+// the collections that are supported by stdlib.LengthFunc, e.g. the services
+// module input which is an object of the services by ID.
+var lengthFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:             "value",
+			Type:             cty.DynamicPseudoType,
+			AllowDynamicType: true,
+			AllowUnknown:     true,
+		},
+	},
+	Type: function.StaticReturnType(cty.Number),
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		v := args[0]
+		ty := v.Type()
+		switch {
+		case ty == cty.DynamicPseudoType:
+			return cty.UnknownVal(cty.Number), nil
+		case ty == cty.String:
+			return stdlib.Strlen(v)
+		case ty.IsObjectType(), ty.IsTupleType(), ty.IsCollectionType():
+			return v.Length(), nil
+		default:
+			return cty.UnknownVal(cty.Number), fmt.Errorf("argument must be a " +
+				"string, a collection, or a structural type")
+		}
+	},
+})
+
+// appendComputedInputVariable appends the variable block for a computed
+// input. The type of the value is determined by the expression.
+func appendComputedInputVariable(body *hclwrite.Body, name string) {
+	vBody := body.AppendNewBlock("variable", []string{name}).Body()
+	vBody.SetAttributeValue("description", cty.StringVal(fmt.Sprintf(
+		"Computed input %s of the task", name)))
+	vBody.SetAttributeTraversal("type", hcl.Traversal{
+		hcl.TraverseRoot{Name: "any"},
+	})
+}
+
+// RenderComputedInputs evaluates the expressions of the computed inputs by
+// name and writes the values to ComputedTFVarsFilename in the directory. The
+// expressions can reference the variables rendered to TFVarsFilename in the
+// directory by name, e.g. services.
+func RenderComputedInputs(dir string, exprs map[string]string, perms os.FileMode) error {
+	tfvarsPath := filepath.Join(dir, TFVarsFilename)
+	content, err := os.ReadFile(tfvarsPath)
+	if err != nil {
+		return err
+	}
+
+	vars, err := parseTFVars(content, tfvarsPath)
+	if err != nil {
+		return err
+	}
+
+	ctx := &hcl.EvalContext{
+		Variables: vars,
+		Functions: computedInputFuncs,
+	}
+
+	names := make([]string, 0, len(exprs))
+	for name := range exprs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hclFile := hclwrite.NewEmptyFile()
+	body := hclFile.Body()
+	for _, name := range names {
+		expr, diags := hclsyntax.ParseExpression([]byte(exprs[name]), name,
+			hcl.InitialPos)
+		if diags.HasErrors() {
+			return fmt.Errorf("unable to parse computed input %q: %s", name, diags)
+		}
+
+		val, diags := expr.Value(ctx)
+		if diags.HasErrors() {
+			return fmt.Errorf("unable to evaluate computed input %q: %s", name, diags)
+		}
+
+		body.SetAttributeValue(name, val)
+	}
+
+	var b bytes.Buffer
+	b.Write(RootPreamble)
+	if _, err := hclFile.WriteTo(&b); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, ComputedTFVarsFilename), b.Bytes(), perms)
+}
+
+// parseTFVars parses the values of the attributes of a rendered .tfvars file
+func parseTFVars(content []byte, filename string) (map[string]cty.Value, error) {
+	p := hclparse.NewParser()
+	hclFile, diags := p.ParseHCL(content, filename)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	attrs, diags := hclFile.Body.JustAttributes()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	vars := make(map[string]cty.Value, len(attrs))
+	for name, attr := range attrs {
+		val, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		vars[name] = val
+	}
+	return vars, nil
+}
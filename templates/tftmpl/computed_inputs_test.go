@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tftmpl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderComputedInputs(t *testing.T) {
+	t.Parallel()
+
+	tfvars := `
+services = {
+  "api.n1.dc1" : {
+    address = "10.0.0.1"
+    name    = "api"
+    status  = "passing"
+  },
+  "api.n2.dc1" : {
+    address = "10.0.0.2"
+    name    = "api"
+    status  = "critical"
+  },
+}
+`
+
+	cases := []struct {
+		name     string
+		exprs    map[string]string
+		expected []string
+		err      bool
+	}{
+		{
+			"passing addresses",
+			map[string]string{
+				"lb_members": `[for s in services : s.address if s.status == "passing"]`,
+			},
+			[]string{`lb_members = ["10.0.0.1"]`},
+			false,
+		},
+		{
+			"functions",
+			map[string]string{
+				"lb_count": `length(services)`,
+				"names":    `distinct([for s in services : upper(s.name)])`,
+			},
+			[]string{`lb_count = 2`, `names    = ["API"]`},
+			false,
+		},
+		{
+			"unknown variable",
+			map[string]string{"lb_members": `consul_kv`},
+			nil,
+			true,
+		},
+		{
+			"unknown function",
+			map[string]string{"lb_members": `file("/etc/passwd")`},
+			nil,
+			true,
+		},
+		{
+			"invalid expression",
+			map[string]string{"lb_members": `[for s in services`},
+			nil,
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, TFVarsFilename),
+				[]byte(tfvars), 0644))
+
+			err := RenderComputedInputs(dir, tc.exprs, 0644)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			content, err := os.ReadFile(filepath.Join(dir, ComputedTFVarsFilename))
+			require.NoError(t, err)
+			assert.Contains(t, string(content), string(RootPreamble))
+			for _, e := range tc.expected {
+				assert.Contains(t, string(content), e)
+			}
+		})
+	}
+
+	t.Run("missing tfvars", func(t *testing.T) {
+		err := RenderComputedInputs(t.TempDir(), map[string]string{"a": "1"}, 0644)
+		assert.Error(t, err)
+	})
+}
@@ -260,6 +260,7 @@ func TestNewFiles(t *testing.T) {
 					&CatalogServicesTemplate{
 						Regexp:     "^web.*|^api.*",
 						Datacenter: "dc1",
+						Kind:       "typical",
 						NodeMeta:   map[string]string{"k": "v"},
 						RenderVar:  true,
 					},
@@ -290,6 +291,20 @@ func TestNewFiles(t *testing.T) {
 				},
 				Task: task,
 			},
+		}, {
+			Name:   "terraform.tfvars.tmpl (catalog-services w instances)",
+			Func:   newTFVarsTmpl,
+			Golden: "testdata/catalog-services/terraform_instances.tfvars.tmpl",
+			Input: RootModuleInputData{
+				Templates: []Template{
+					&CatalogServicesTemplate{
+						Regexp:         ".*",
+						CountInstances: true,
+						RenderVar:      false,
+					},
+				},
+				Task: task,
+			},
 		}, {
 			Name:   "terraform.tfvars.tmpl (consul-kv w no namespace - no var)",
 			Func:   newTFVarsTmpl,
@@ -495,6 +510,16 @@ func TestNewFiles(t *testing.T) {
 				},
 				Task: task,
 			},
+		}, {
+			Name:   "environment.prod.tfvars",
+			Func:   newEnvironmentTFVars(hcltmpl.Variables{"size": cty.StringVal("large")}),
+			Golden: "testdata/environment.prod.tfvars",
+			Input: RootModuleInputData{
+				Variables: hcltmpl.Variables{
+					"size": cty.StringVal("small"),
+				},
+				Task: task,
+			},
 		},
 	}
 
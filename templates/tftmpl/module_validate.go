@@ -0,0 +1,298 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tftmpl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// moduleVariablesSchema is the schema to decode the variable blocks of a
+// Terraform module
+var moduleVariablesSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "variable", LabelNames: []string{"name"}},
+	},
+}
+
+// moduleVariableSchema is the schema to decode the type of a variable block
+var moduleVariableSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "type"},
+	},
+}
+
+// ModuleValidateInput is the input to validate a module against the variables
+// rendered by CTS for a task
+type ModuleValidateInput struct {
+	// Path is the directory of the module
+	Path string
+
+	// Condition is the type of the task condition, e.g. "services"
+	Condition string
+
+	// ModuleInputs are the types of the task module inputs
+	ModuleInputs []string
+
+	// ExtendedMetadata determines if the services variable includes the
+	// extended service metadata
+	ExtendedMetadata bool
+}
+
+// ModuleValidateResult is the result of validating a module
+type ModuleValidateResult struct {
+	// Mismatches are the incompatibilities between the module variables and
+	// the variables rendered by CTS
+	Mismatches []VariableMismatch
+
+	// Warnings are the module variables that could not be checked
+	Warnings []string
+}
+
+// VariableMismatch is an incompatibility between the type of a module
+// variable and the type of the variable rendered by CTS
+type VariableMismatch struct {
+	// Variable is the name of the variable
+	Variable string
+
+	// Path is the path within the variable type of the incompatibility,
+	// e.g. services[*].port. The path is the variable name for an
+	// incompatibility with the variable itself.
+	Path string
+
+	// Message describes the incompatibility
+	Message string
+}
+
+// String returns the mismatch as the path and the message
+func (m VariableMismatch) String() string {
+	return fmt.Sprintf("%s: %s", m.Path, m.Message)
+}
+
+// ValidateModule checks that the module declares the variables rendered by
+// CTS for the condition and module inputs, and that the types of the
+// variables are compatible with the values passed by CTS. Variables declared
+// without a type accept any value.
+func ValidateModule(input ModuleValidateInput) (ModuleValidateResult, error) {
+	contract, err := ScaffoldVariables(ModuleScaffoldInput{
+		Condition:        input.Condition,
+		ModuleInputs:     input.ModuleInputs,
+		ExtendedMetadata: input.ExtendedMetadata,
+	})
+	if err != nil {
+		return ModuleValidateResult{}, err
+	}
+
+	parser := hclparse.NewParser()
+	expected, _, err := parseVariables(parser, "cts_variables.tf", contract)
+	if err != nil {
+		return ModuleValidateResult{}, err
+	}
+
+	actual, warnings, err := loadModuleVariableTypes(input.Path)
+	if err != nil {
+		return ModuleValidateResult{}, err
+	}
+
+	var result ModuleValidateResult
+	result.Warnings = warnings
+
+	names := make([]string, 0, len(expected))
+	for name := range expected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		ty, ok := actual[name]
+		if !ok {
+			result.Mismatches = append(result.Mismatches, VariableMismatch{
+				Variable: name,
+				Path:     name,
+				Message: fmt.Sprintf("variable is not declared. CTS passes "+
+					"%s to the module", typeexpr.TypeString(expected[name])),
+			})
+			continue
+		}
+		if ty == cty.NilType {
+			// type could not be parsed and is included in the warnings
+			continue
+		}
+		for _, m := range compareVariableTypes(name, expected[name], ty) {
+			m.Variable = name
+			result.Mismatches = append(result.Mismatches, m)
+		}
+	}
+
+	return result, nil
+}
+
+// loadModuleVariableTypes parses the Terraform files of the module and
+// returns the type of each declared variable. Variables with types that
+// cannot be parsed have the type cty.NilType and are returned as warnings.
+func loadModuleVariableTypes(dir string) (map[string]cty.Type, []string, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !info.IsDir() {
+		return nil, nil, fmt.Errorf("module path %q is not a directory", dir)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(files) == 0 {
+		return nil, nil, fmt.Errorf("no Terraform files found in module "+
+			"path %q", dir)
+	}
+	sort.Strings(files)
+
+	parser := hclparse.NewParser()
+	types := make(map[string]cty.Type)
+	var warnings []string
+	for _, file := range files {
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		fileTypes, fileWarnings, err := parseVariables(parser, file, src)
+		if err != nil {
+			return nil, nil, err
+		}
+		for name, ty := range fileTypes {
+			types[name] = ty
+		}
+		warnings = append(warnings, fileWarnings...)
+	}
+
+	return types, warnings, nil
+}
+
+// parseVariables parses the variable blocks of a Terraform file and returns
+// the type of each variable. Variables without a type accept any value.
+// Variables with types that cannot be parsed, such as types with optional
+// object attributes, have the type cty.NilType and are returned as warnings.
+func parseVariables(parser *hclparse.Parser, filename string, src []byte) (
+	map[string]cty.Type, []string, error) {
+
+	f, diags := parser.ParseHCL(src, filename)
+	if diags.HasErrors() {
+		return nil, nil, diags
+	}
+
+	content, _, diags := f.Body.PartialContent(moduleVariablesSchema)
+	if diags.HasErrors() {
+		return nil, nil, diags
+	}
+
+	types := make(map[string]cty.Type)
+	var warnings []string
+	for _, block := range content.Blocks {
+		name := block.Labels[0]
+		vc, _, diags := block.Body.PartialContent(moduleVariableSchema)
+		if diags.HasErrors() {
+			return nil, nil, diags
+		}
+
+		attr, ok := vc.Attributes["type"]
+		if !ok {
+			types[name] = cty.DynamicPseudoType
+			continue
+		}
+
+		ty, diags := typeexpr.TypeConstraint(attr.Expr)
+		if diags.HasErrors() {
+			types[name] = cty.NilType
+			warnings = append(warnings, fmt.Sprintf("%s: type could not be "+
+				"checked: %s", name, diags.Error()))
+			continue
+		}
+		types[name] = ty
+	}
+
+	return types, warnings, nil
+}
+
+// compareVariableTypes compares the type of the value passed by CTS (want)
+// with the type declared by the module (got) and returns the
+// incompatibilities. Object attributes of the CTS value that are not
+// declared by the module are ignored since they are dropped by Terraform
+// when the value is converted.
+func compareVariableTypes(path string, want, got cty.Type) []VariableMismatch {
+	if got == cty.DynamicPseudoType || want.Equals(got) {
+		return nil
+	}
+
+	mismatch := func() []VariableMismatch {
+		return []VariableMismatch{{
+			Path: path,
+			Message: fmt.Sprintf("module declares %s, but CTS passes %s",
+				typeexpr.TypeString(got), typeexpr.TypeString(want)),
+		}}
+	}
+
+	switch {
+	case got.IsObjectType():
+		if !want.IsObjectType() {
+			return mismatch()
+		}
+
+		var mismatches []VariableMismatch
+		attrs := make([]string, 0, len(got.AttributeTypes()))
+		for attr := range got.AttributeTypes() {
+			attrs = append(attrs, attr)
+		}
+		sort.Strings(attrs)
+		for _, attr := range attrs {
+			attrPath := fmt.Sprintf("%s.%s", path, attr)
+			if !want.HasAttribute(attr) {
+				mismatches = append(mismatches, VariableMismatch{
+					Path:    attrPath,
+					Message: "attribute is not provided by CTS",
+				})
+				continue
+			}
+			mismatches = append(mismatches, compareVariableTypes(attrPath,
+				want.AttributeType(attr), got.AttributeType(attr))...)
+		}
+		return mismatches
+
+	case got.IsMapType():
+		if !want.IsMapType() {
+			return mismatch()
+		}
+		return compareVariableTypes(path+"[*]", want.ElementType(),
+			got.ElementType())
+
+	case got.IsListType(), got.IsSetType():
+		if !want.IsListType() && !want.IsSetType() {
+			return mismatch()
+		}
+		return compareVariableTypes(path+"[*]", want.ElementType(),
+			got.ElementType())
+
+	case got.IsPrimitiveType():
+		if want.IsPrimitiveType() && convert.GetConversion(want, got) != nil {
+			return nil
+		}
+		return mismatch()
+
+	default:
+		if convert.GetConversion(want, got) != nil {
+			return nil
+		}
+		return mismatch()
+	}
+}
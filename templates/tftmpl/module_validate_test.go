@@ -0,0 +1,163 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tftmpl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateModule(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		input      ModuleValidateInput
+		variables  string
+		mismatches []string
+		warnings   int
+	}{
+		{
+			name:      "scaffold variables",
+			input:     ModuleValidateInput{ModuleInputs: []string{"consul-kv", "catalog-services"}},
+			variables: string(VariableServices) + string(variableConsulKV) + string(variableCatalogServices),
+		},
+		{
+			name:  "subset of attributes",
+			input: ModuleValidateInput{},
+			variables: `
+variable "services" {
+  type = map(object({
+    address = string
+    port    = string
+    tags    = set(string)
+  }))
+}`,
+		},
+		{
+			name:  "untyped variable",
+			input: ModuleValidateInput{Condition: "consul-kv"},
+			variables: `
+variable "services" {}
+variable "consul_kv" {
+  type = any
+}`,
+		},
+		{
+			name:      "missing variable",
+			input:     ModuleValidateInput{ModuleInputs: []string{"catalog-services"}},
+			variables: string(VariableServices),
+			mismatches: []string{
+				"catalog_services: variable is not declared. CTS passes map(list(string)) to the module",
+			},
+		},
+		{
+			name:  "type mismatches",
+			input: ModuleValidateInput{ModuleInputs: []string{"consul-kv"}},
+			variables: `
+variable "services" {
+  type = map(object({
+    port     = number
+    address  = number
+    meta     = list(string)
+    priority = number
+  }))
+}
+variable "consul_kv" {
+  type = list(string)
+}`,
+			mismatches: []string{
+				"consul_kv: module declares list(string), but CTS passes map(string)",
+				"services[*].address: module declares number, but CTS passes string",
+				"services[*].meta: module declares list(string), but CTS passes map(string)",
+				"services[*].priority: attribute is not provided by CTS",
+			},
+		},
+		{
+			name:  "extended metadata",
+			input: ModuleValidateInput{ExtendedMetadata: true},
+			variables: `
+variable "services" {
+  type = map(object({
+    weights = object({
+      passing = number
+    })
+  }))
+}`,
+		},
+		{
+			name:  "extended metadata not included",
+			input: ModuleValidateInput{},
+			variables: `
+variable "services" {
+  type = map(object({
+    weights = object({
+      passing = number
+    })
+  }))
+}`,
+			mismatches: []string{
+				"services[*].weights: attribute is not provided by CTS",
+			},
+		},
+		{
+			name:  "unsupported type expression",
+			input: ModuleValidateInput{},
+			variables: `
+variable "services" {
+  type = map(object({
+    address = optional(string)
+  }))
+}`,
+			warnings: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, VarsFilename)
+			require.NoError(t, os.WriteFile(path, []byte(tc.variables), 0644))
+
+			result, err := ValidateModule(ModuleValidateInput{
+				Path:             dir,
+				Condition:        tc.input.Condition,
+				ModuleInputs:     tc.input.ModuleInputs,
+				ExtendedMetadata: tc.input.ExtendedMetadata,
+			})
+			require.NoError(t, err)
+
+			var mismatches []string
+			for _, m := range result.Mismatches {
+				mismatches = append(mismatches, m.String())
+			}
+			assert.Equal(t, tc.mismatches, mismatches)
+			assert.Len(t, result.Warnings, tc.warnings)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := ValidateModule(ModuleValidateInput{Path: t.TempDir()})
+		assert.Error(t, err, "no Terraform files")
+
+		_, err = ValidateModule(ModuleValidateInput{Path: "does-not-exist"})
+		assert.Error(t, err)
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, RootFilename),
+			[]byte(`variable "services" {`), 0644))
+		_, err = ValidateModule(ModuleValidateInput{Path: dir})
+		assert.Error(t, err, "invalid HCL")
+
+		_, err = ValidateModule(ModuleValidateInput{
+			Path:      t.TempDir(),
+			Condition: "nodes",
+		})
+		assert.Error(t, err, "unsupported type")
+	})
+}
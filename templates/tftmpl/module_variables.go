@@ -100,6 +100,9 @@ func newModuleVariablesTF(w io.Writer, filename string, input *RootModuleInputDa
 			Bytes: []byte(rawTypeAttr),
 		}})
 		vBody.AppendNewline()
+		if input.isSensitive(name) {
+			vBody.SetAttributeValue("sensitive", cty.True)
+		}
 		if i != lastIdx {
 			rootBody.AppendNewline()
 		}
@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package notifier
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl/tmplfunc"
+	"github.com/hashicorp/hcat/dep"
+)
+
+// DependencyName returns a name that identifies the monitored dependency of
+// the data that the notifiers receive, e.g. "services.web" for the instances
+// of the web service and "consul_kv.path/to/key" for a Consul KV path. The
+// name is prefixed by the variable that the dependency is rendered to.
+func DependencyName(dependency interface{}) string {
+	switch d := dependency.(type) {
+	case []*dep.HealthService:
+		if len(d) == 0 {
+			// the service name is not known when there are no instances
+			return "services"
+		}
+		return "services." + d[0].Name
+	case []*dep.CatalogSnippet, []*tmplfunc.CatalogServiceInstances:
+		return "catalog_services"
+	case *dep.KeyPair:
+		if d == nil {
+			return "consul_kv"
+		}
+		return "consul_kv." + d.Key
+	case []*dep.KeyPair:
+		if len(d) == 0 {
+			// the path is not known when there are no keys
+			return "consul_kv"
+		}
+		// the path of the recursive query is the full path of a key without
+		// the relative key
+		return "consul_kv." + strings.TrimSuffix(d[0].Path, d[0].Key)
+	case []*tmplfunc.DNSRecord:
+		return "dns"
+	case []*tmplfunc.File:
+		return "files"
+	case *tmplfunc.PluginOutput:
+		return "plugin"
+	default:
+		return fmt.Sprintf("%T", dependency)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package notifier
+
+import (
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl/tmplfunc"
+	"github.com/hashicorp/hcat/dep"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencyName(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		dependency interface{}
+		expected   string
+	}{
+		{
+			"services",
+			[]*dep.HealthService{{Name: "web", ID: "web-1"}, {Name: "web", ID: "web-2"}},
+			"services.web",
+		},
+		{
+			"services without instances",
+			[]*dep.HealthService{},
+			"services",
+		},
+		{
+			"catalog services",
+			[]*dep.CatalogSnippet{{Name: "web"}},
+			"catalog_services",
+		},
+		{
+			"catalog services instances",
+			[]*tmplfunc.CatalogServiceInstances{{Name: "web", Instances: 2}},
+			"catalog_services",
+		},
+		{
+			"consul kv key",
+			&dep.KeyPair{Path: "config/key", Key: "config/key"},
+			"consul_kv.config/key",
+		},
+		{
+			"consul kv recurse",
+			[]*dep.KeyPair{{Path: "config/app/key", Key: "app/key"}},
+			"consul_kv.config/",
+		},
+		{
+			"consul kv recurse without keys",
+			[]*dep.KeyPair{},
+			"consul_kv",
+		},
+		{
+			"dns",
+			[]*tmplfunc.DNSRecord{},
+			"dns",
+		},
+		{
+			"files",
+			[]*tmplfunc.File{},
+			"files",
+		},
+		{
+			"plugin",
+			&tmplfunc.PluginOutput{},
+			"plugin",
+		},
+		{
+			"unknown",
+			"data",
+			"string",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, DependencyName(tc.dependency))
+		})
+	}
+}
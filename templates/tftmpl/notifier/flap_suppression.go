@@ -0,0 +1,173 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package notifier
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/hashicorp/hcat/dep"
+)
+
+// MakeTriggerCheckServiceFlapSuppression creates a function that renders on
+// every service change like TriggerCheckService, but does not trigger when
+// all of the registrations and deregistrations of the change are of flapping
+// instances. An instance is flapping when it registers or deregisters more
+// than the transitions number of times within the window.
+//
+// The data for a service without instances does not identify the service.
+// The deregistration of the last instance of a service is only attributed
+// to the service when it is the only known service with instances. Otherwise
+// the change triggers, and the known instances are reset.
+func MakeTriggerCheckServiceFlapSuppression(logger logging.Logger,
+	transitions int, window time.Duration) TriggerCheck {
+	return newFlapTracker(logger, transitions, window).check
+}
+
+// flapTracker tracks the instances of services and the times of their
+// recent transitions to detect flapping instances
+type flapTracker struct {
+	logger      logging.Logger
+	transitions int
+	window      time.Duration
+	now         func() time.Time
+
+	mu sync.Mutex
+	// instances are the fingerprints of the known instances by the service
+	// name and the instance key
+	instances map[string]map[string]string
+	// history are the times of the transitions within the window by the
+	// instance key
+	history map[string][]time.Time
+}
+
+func newFlapTracker(logger logging.Logger, transitions int,
+	window time.Duration) *flapTracker {
+	return &flapTracker{
+		logger:      logger,
+		transitions: transitions,
+		window:      window,
+		now:         time.Now,
+		instances:   make(map[string]map[string]string),
+		history:     make(map[string][]time.Time),
+	}
+}
+
+func (t *flapTracker) check(d interface{}) (render, trigger bool) {
+	services, ok := d.([]*dep.HealthService)
+	if !ok {
+		return false, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	t.prune(now)
+
+	updates := make(map[string]map[string]string)
+	for _, s := range services {
+		if updates[s.Name] == nil {
+			updates[s.Name] = make(map[string]string)
+		}
+		updates[s.Name][flapInstanceKey(s)] = flapInstanceFingerprint(s)
+	}
+	if len(services) == 0 {
+		var emptied []string
+		for name, instances := range t.instances {
+			if len(instances) > 0 {
+				emptied = append(emptied, name)
+			}
+		}
+		switch len(emptied) {
+		case 0:
+			return true, true
+		case 1:
+			updates[emptied[0]] = make(map[string]string)
+		default:
+			// the service can't be identified, treat the next update of each
+			// service as its initial instances
+			t.instances = make(map[string]map[string]string)
+			return true, true
+		}
+	}
+
+	var changed, flapping []string
+	for name, update := range updates {
+		old, known := t.instances[name]
+		t.instances[name] = update
+		if !known {
+			changed = append(changed, name)
+			continue
+		}
+		for key, fingerprint := range update {
+			oldFingerprint, ok := old[key]
+			switch {
+			case !ok:
+				if t.recordTransition(key, now) {
+					flapping = append(flapping, key)
+				} else {
+					changed = append(changed, key)
+				}
+			case oldFingerprint != fingerprint:
+				changed = append(changed, key)
+			}
+		}
+		for key := range old {
+			if _, ok := update[key]; ok {
+				continue
+			}
+			if t.recordTransition(key, now) {
+				flapping = append(flapping, key)
+			} else {
+				changed = append(changed, key)
+			}
+		}
+	}
+
+	if len(flapping) == 0 || len(changed) > 0 {
+		return true, true
+	}
+	sort.Strings(flapping)
+	t.logger.Info("suppressing trigger for flapping service instances",
+		"instances", flapping)
+	return true, false
+}
+
+// recordTransition records a registration or deregistration of the instance
+// and returns true if the instance is flapping
+func (t *flapTracker) recordTransition(key string, now time.Time) bool {
+	t.history[key] = append(t.history[key], now)
+	return len(t.history[key]) > t.transitions
+}
+
+// prune removes the transitions that are older than the window
+func (t *flapTracker) prune(now time.Time) {
+	cutoff := now.Add(-t.window)
+	for key, times := range t.history {
+		i := 0
+		for i < len(times) && !times[i].After(cutoff) {
+			i++
+		}
+		if i == len(times) {
+			delete(t.history, key)
+			continue
+		}
+		t.history[key] = times[i:]
+	}
+}
+
+// flapInstanceKey returns the key that identifies a service instance
+func flapInstanceKey(s *dep.HealthService) string {
+	return fmt.Sprintf("%s/%s/%s", s.Name, s.Node, s.ID)
+}
+
+// flapInstanceFingerprint returns the attributes of a service instance that
+// are compared to detect changes other than a registration or deregistration
+func flapInstanceFingerprint(s *dep.HealthService) string {
+	return fmt.Sprintf("%s|%s|%d|%s|%v", s.Status, s.Address, s.Port,
+		sortedTags(s.Tags), s.ServiceMeta)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package notifier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/hashicorp/hcat/dep"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeTriggerCheckServiceFlapSuppression(t *testing.T) {
+	t.Parallel()
+
+	api1 := &dep.HealthService{Name: "api", Node: "node", ID: "api-1", Status: "passing"}
+	api2 := &dep.HealthService{Name: "api", Node: "node", ID: "api-2", Status: "passing"}
+	web1 := &dep.HealthService{Name: "web", Node: "node", ID: "web-1", Status: "passing"}
+
+	// newTracker returns a tracker that allows 2 transitions per minute with
+	// a clock that is advanced by the returned function
+	newTracker := func() (*flapTracker, func(time.Duration)) {
+		tracker := newFlapTracker(logging.NewNullLogger(), 2, time.Minute)
+		now := time.Now()
+		tracker.now = func() time.Time { return now }
+		return tracker, func(d time.Duration) { now = now.Add(d) }
+	}
+
+	t.Run("only services", func(t *testing.T) {
+		tracker, _ := newTracker()
+		re, tr := tracker.check(nil)
+		assert.False(t, re)
+		assert.False(t, tr)
+	})
+
+	t.Run("suppress flapping instance", func(t *testing.T) {
+		tracker, _ := newTracker()
+		updates := []struct {
+			services []*dep.HealthService
+			trigger  bool
+		}{
+			{[]*dep.HealthService{api1}, true},       // initial instances
+			{[]*dep.HealthService{api1, api2}, true}, // 1st transition
+			{[]*dep.HealthService{api1}, true},       // 2nd transition
+			{[]*dep.HealthService{api1, api2}, false},
+			{[]*dep.HealthService{api1}, false},
+		}
+		for i, u := range updates {
+			re, tr := tracker.check(u.services)
+			assert.True(t, re, "update %d", i)
+			assert.Equal(t, u.trigger, tr, "update %d", i)
+		}
+	})
+
+	t.Run("trigger when window elapses", func(t *testing.T) {
+		tracker, advance := newTracker()
+		tracker.check([]*dep.HealthService{api1})
+		tracker.check([]*dep.HealthService{api1, api2})
+		tracker.check([]*dep.HealthService{api1})
+		_, tr := tracker.check([]*dep.HealthService{api1, api2})
+		assert.False(t, tr)
+
+		advance(2 * time.Minute)
+		_, tr = tracker.check([]*dep.HealthService{api1})
+		assert.True(t, tr)
+	})
+
+	t.Run("trigger on other changes", func(t *testing.T) {
+		tracker, _ := newTracker()
+		tracker.check([]*dep.HealthService{api1})
+		tracker.check([]*dep.HealthService{api1, api2})
+		tracker.check([]*dep.HealthService{api1})
+
+		critical := *api1
+		critical.Status = "critical"
+		_, tr := tracker.check([]*dep.HealthService{&critical, api2})
+		assert.True(t, tr)
+
+		// unchanged instances trigger like TriggerCheckService
+		_, tr = tracker.check([]*dep.HealthService{&critical, api2})
+		assert.True(t, tr)
+	})
+
+	t.Run("last instance of single service", func(t *testing.T) {
+		tracker, _ := newTracker()
+		tracker.check([]*dep.HealthService{api1})
+		tracker.check([]*dep.HealthService{})
+		tracker.check([]*dep.HealthService{api1})
+		_, tr := tracker.check([]*dep.HealthService{})
+		assert.False(t, tr)
+	})
+
+	t.Run("last instance of multiple services", func(t *testing.T) {
+		tracker, _ := newTracker()
+		tracker.check([]*dep.HealthService{api1})
+		tracker.check([]*dep.HealthService{web1})
+		_, tr := tracker.check([]*dep.HealthService{})
+		assert.True(t, tr)
+
+		// instances are reset and the next updates are initial instances
+		_, tr = tracker.check([]*dep.HealthService{api1})
+		assert.True(t, tr)
+		assert.Empty(t, tracker.history)
+	})
+}
@@ -0,0 +1,98 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package notifier
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl/tmplfunc"
+)
+
+// MakeTriggerCheckCatalogServiceThresholds creates a function that tracks the
+// number of registered instances of catalog services between calls. It renders
+// when the service names or tags change, and only triggers when the number of
+// instances of a service crosses the min or max threshold, e.g. when a service
+// scales out past the max. A threshold of 0 is not set. A service that is no
+// longer registered has 0 instances.
+func MakeTriggerCheckCatalogServiceThresholds(min, max int) TriggerCheck {
+	t := &thresholdTracker{min: min, max: max}
+	return t.check
+}
+
+// thresholdTracker tracks the instance counts of catalog services
+type thresholdTracker struct {
+	min, max int
+
+	mu sync.Mutex
+	// counts are the numbers of instances by the service name. Nil until the
+	// first call.
+	counts map[string]int
+	// services are the service names with their sorted tags
+	services []string
+}
+
+func (t *thresholdTracker) check(d interface{}) (render, trigger bool) {
+	new, ok := d.([]*tmplfunc.CatalogServiceInstances)
+	if !ok {
+		return false, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	counts := make(map[string]int, len(new))
+	services := make([]string, len(new))
+	for ix, s := range new {
+		counts[s.Name] = s.Instances
+		services[ix] = s.Name + "=" + sortedTags(s.Tags)
+	}
+	sort.Strings(services)
+
+	if t.counts == nil {
+		t.counts, t.services = counts, services
+		return true, true
+	}
+
+	render = !equalStrings(t.services, services)
+	for name, count := range counts {
+		if t.band(t.counts[name]) != t.band(count) {
+			trigger = true
+		}
+	}
+	for name, count := range t.counts {
+		if _, ok := counts[name]; !ok && t.band(count) != t.band(0) {
+			trigger = true
+		}
+	}
+
+	t.counts, t.services = counts, services
+	return render || trigger, trigger
+}
+
+// band returns -1 if the count is below the min threshold, 1 if the count is
+// above the max threshold, and 0 otherwise
+func (t *thresholdTracker) band(count int) int {
+	switch {
+	case t.min > 0 && count < t.min:
+		return -1
+	case t.max > 0 && count > t.max:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// equalStrings returns true if the slices have the same values in order
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package notifier
+
+import (
+	"testing"
+
+	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl/tmplfunc"
+	"github.com/hashicorp/hcat/dep"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeTriggerCheckCatalogServiceThresholds(t *testing.T) {
+	t.Parallel()
+
+	api := func(instances int, tags ...string) *tmplfunc.CatalogServiceInstances {
+		return &tmplfunc.CatalogServiceInstances{
+			Name: "api", Tags: dep.ServiceTags(tags), Instances: instances}
+	}
+	web := func(instances int) *tmplfunc.CatalogServiceInstances {
+		return &tmplfunc.CatalogServiceInstances{Name: "web", Instances: instances}
+	}
+
+	t.Run("other dependency", func(t *testing.T) {
+		check := MakeTriggerCheckCatalogServiceThresholds(2, 10)
+		re, tr := check([]*dep.CatalogSnippet{{Name: "api"}})
+		assert.False(t, re)
+		assert.False(t, tr)
+	})
+
+	type update struct {
+		services []*tmplfunc.CatalogServiceInstances
+		render   bool
+		trigger  bool
+	}
+
+	cases := []struct {
+		name     string
+		min, max int
+		updates  []update
+	}{
+		{
+			"max",
+			0, 10,
+			[]update{
+				{[]*tmplfunc.CatalogServiceInstances{api(5)}, true, true}, // initial
+				{[]*tmplfunc.CatalogServiceInstances{api(10)}, false, false},
+				{[]*tmplfunc.CatalogServiceInstances{api(11)}, true, true},
+				{[]*tmplfunc.CatalogServiceInstances{api(15)}, false, false},
+				{[]*tmplfunc.CatalogServiceInstances{api(9)}, true, true},
+			},
+		},
+		{
+			"min",
+			2, 0,
+			[]update{
+				{[]*tmplfunc.CatalogServiceInstances{api(3)}, true, true},
+				{[]*tmplfunc.CatalogServiceInstances{api(2)}, false, false},
+				{[]*tmplfunc.CatalogServiceInstances{api(1)}, true, true},
+				{[]*tmplfunc.CatalogServiceInstances{api(3)}, true, true},
+			},
+		},
+		{
+			"deregistered service has no instances",
+			2, 10,
+			[]update{
+				{[]*tmplfunc.CatalogServiceInstances{api(3), web(1)}, true, true},
+				{[]*tmplfunc.CatalogServiceInstances{api(3)}, true, false},
+				{[]*tmplfunc.CatalogServiceInstances{}, true, true},
+			},
+		},
+		{
+			"new service",
+			0, 10,
+			[]update{
+				{[]*tmplfunc.CatalogServiceInstances{}, true, true},
+				{[]*tmplfunc.CatalogServiceInstances{web(1)}, true, false},
+				{[]*tmplfunc.CatalogServiceInstances{web(1), api(12)}, true, true},
+			},
+		},
+		{
+			"tag change renders",
+			0, 10,
+			[]update{
+				{[]*tmplfunc.CatalogServiceInstances{api(1, "a")}, true, true},
+				{[]*tmplfunc.CatalogServiceInstances{api(1, "b")}, true, false},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			check := MakeTriggerCheckCatalogServiceThresholds(tc.min, tc.max)
+			for i, u := range tc.updates {
+				re, tr := check(u.services)
+				assert.Equal(t, u.render, re, "update %d", i)
+				assert.Equal(t, u.trigger, tr, "update %d", i)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl/tmplfunc"
 	"github.com/hashicorp/hcat/dep"
 )
 
@@ -28,6 +29,13 @@ func logDependency(logger logging.Logger, dependency interface{}) {
 		}
 		logger.Debug("received dependency",
 			"variable", "catalog_services", "names", serviceNames)
+	case []*tmplfunc.CatalogServiceInstances:
+		serviceNames := make([]string, len(d))
+		for ix, cs := range d {
+			serviceNames[ix] = cs.Name
+		}
+		logger.Debug("received dependency",
+			"variable", "catalog_services", "names", serviceNames)
 	case *dep.KeyPair:
 		logger.Debug("received dependency",
 			"variable", "consul_kv", "recurse", false, "key", d.Key)
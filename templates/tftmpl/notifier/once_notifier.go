@@ -4,10 +4,14 @@
 package notifier
 
 import (
+	"crypto/sha256"
 	"sort"
+	"strings"
 	"sync"
 
 	"github.com/hashicorp/consul-terraform-sync/templates"
+	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl/tmplfunc"
+	"github.com/hashicorp/hcat"
 	"github.com/hashicorp/hcat/dep"
 )
 
@@ -26,17 +30,41 @@ type TriggerCheck func(d interface{}) (render, trigger bool)
 // Due to the way we interface with hcat, the only safe way to know if a task has all
 // data is to check the `hcat.ResolveEvent.Complete` field. Therefore, it is expected
 // that some other portion of code will be responsible for calling `SetOnceDone()`.
+//
+// After once-mode is done, the OnceNotifier counts the triggers by the name of
+// the dependency that caused the trigger.
+//
+// The OnceNotifier also records the dependencies of the template from its
+// latest execution and whether each has been fetched, which is the progress
+// of resolving the template before once-mode is done.
+//
+// The OnceNotifier also tracks the hash of the contents that were last
+// rendered and last applied. Dependencies are refetched when the connection
+// to Consul is lost and restored, which notifies the template even if the
+// data is identical. Comparing the hash of the executed contents to the
+// applied snapshot avoids running the task when nothing changed.
 type OnceNotifier struct {
 	templates.Template
 	mu           sync.Mutex
 	triggerCheck TriggerCheck
 	onceDone     bool
+	triggers     map[string]int
+
+	// dependencies are the dependencies of the latest execution of the
+	// template by name, and whether each has been fetched
+	dependencies map[string]bool
+
+	// rendered and applied are the hashes of the contents that were last
+	// rendered and last applied. Nil if not yet rendered or applied.
+	rendered []byte
+	applied  []byte
 }
 
 func NewOnceNotifier(triggerCheck TriggerCheck, template templates.Template) *OnceNotifier {
 	return &OnceNotifier{
 		Template:     template,
 		triggerCheck: triggerCheck,
+		triggers:     make(map[string]int),
 	}
 }
 
@@ -61,11 +89,100 @@ func (n *OnceNotifier) Notify(d interface{}) bool {
 	if render || !n.onceDone {
 		n.Template.Notify(d)
 	}
+	if trigger && n.onceDone {
+		n.triggers[DependencyName(d)]++
+	}
 	// Trigger task if once mode is not completed or if the trigger indicates.
 	// The task will check and prevent execution if the template isn't ready.
 	return trigger || !n.onceDone
 }
 
+// Execute executes the template and records the dependencies that the
+// template requested and whether each has been fetched
+func (n *OnceNotifier) Execute(recall hcat.Recaller) ([]byte, error) {
+	deps := make(map[string]bool)
+	var mu sync.Mutex
+	recorder := func(d dep.Dependency) (interface{}, bool) {
+		data, ok := recall(d)
+		mu.Lock()
+		deps[d.String()] = deps[d.String()] || ok
+		mu.Unlock()
+		return data, ok
+	}
+
+	contents, err := n.Template.Execute(recorder)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.dependencies = deps
+	return contents, err
+}
+
+// Dependencies returns the dependencies of the latest execution of the
+// template by name, and whether each has been fetched. Returns an empty map
+// if the template has not been executed.
+func (n *OnceNotifier) Dependencies() map[string]bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	deps := make(map[string]bool, len(n.dependencies))
+	for name, fetched := range n.dependencies {
+		deps[name] = fetched
+	}
+	return deps
+}
+
+// Render renders the contents and records the hash of the contents when
+// successfully rendered
+func (n *OnceNotifier) Render(content []byte) (hcat.RenderResult, error) {
+	result, err := n.Template.Render(content)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if err != nil {
+		n.rendered = nil
+		return result, err
+	}
+	n.rendered = contentHash(content)
+	return result, nil
+}
+
+// SetApplied records the last rendered contents as the applied snapshot.
+// Expected to be called after the task successfully runs with the rendered
+// contents.
+func (n *OnceNotifier) SetApplied() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.applied = n.rendered
+}
+
+// Unchanged returns true if the contents are identical to the applied
+// snapshot and the applied snapshot is what was last rendered. Returns false
+// if nothing has been applied.
+func (n *OnceNotifier) Unchanged(content []byte) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.applied == nil || string(n.applied) != string(n.rendered) {
+		return false
+	}
+	return string(contentHash(content)) == string(n.applied)
+}
+
+func contentHash(content []byte) []byte {
+	sum := sha256.Sum256(content)
+	return sum[:]
+}
+
+// DependencyTriggers returns the number of times that each dependency
+// triggered the task after once-mode, by the name of the dependency
+func (n *OnceNotifier) DependencyTriggers() map[string]int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	triggers := make(map[string]int, len(n.triggers))
+	for name, count := range n.triggers {
+		triggers[name] = count
+	}
+	return triggers
+}
+
 // TriggerCheckSuppress never triggers a task execution but renders on every call.
 func TriggerCheckSuppress(d interface{}) (render, trigger bool) {
 	return true, false
@@ -79,17 +196,66 @@ func TriggerCheckConsulKV(d interface{}) (render, trigger bool) {
 	return ok, ok
 }
 
+// TriggerCheckDNS triggers and renders on every DNS record change.
+func TriggerCheckDNS(d interface{}) (render, trigger bool) {
+	_, ok := d.([]*tmplfunc.DNSRecord)
+	return ok, ok
+}
+
+// TriggerCheckFile triggers and renders on every local file change.
+func TriggerCheckFile(d interface{}) (render, trigger bool) {
+	_, ok := d.([]*tmplfunc.File)
+	return ok, ok
+}
+
+// TriggerCheckPlugin triggers and renders on every condition plugin output
+// change.
+func TriggerCheckPlugin(d interface{}) (render, trigger bool) {
+	_, ok := d.(*tmplfunc.PluginOutput)
+	return ok, ok
+}
+
 // TriggerCheckService triggers and renders on every service change.
 func TriggerCheckService(d interface{}) (render, trigger bool) {
 	_, ok := d.([]*dep.HealthService)
 	return ok, ok
 }
 
+// MakeTriggerCheckAnyInputChange creates a function that triggers and renders
+// on every change to the data of the module inputs in addition to the changes
+// of the condition. The data of the condition is identified by the prefix of
+// its dependency name, see DependencyName, and whether it renders or triggers
+// is decided by the condition's TriggerCheck.
+func MakeTriggerCheckAnyInputChange(condition TriggerCheck,
+	conditionDependency string) TriggerCheck {
+
+	return func(d interface{}) (render, trigger bool) {
+		// Always call the condition's check so that it can track changes.
+		render, trigger = condition(d)
+		if d == nil || strings.HasPrefix(DependencyName(d), conditionDependency) {
+			return render, trigger
+		}
+		return true, true
+	}
+}
+
 // MakeTriggerCheckCatalogService creates a function that tracks
 // catalog service state between calls. If any change is detected
 // to the service names, then it will trigger and render. Otherwise,
 // no trigger or render should occur.
 func MakeTriggerCheckCatalogService() TriggerCheck {
+	return makeTriggerCheckCatalogService(false)
+}
+
+// MakeTriggerCheckCatalogServiceTags creates a function that tracks
+// catalog service state between calls. It behaves like
+// MakeTriggerCheckCatalogService but additionally triggers and renders
+// when the set of tags for any of the services changes.
+func MakeTriggerCheckCatalogServiceTags() TriggerCheck {
+	return makeTriggerCheckCatalogService(true)
+}
+
+func makeTriggerCheckCatalogService(includeTags bool) TriggerCheck {
 	var mu sync.Mutex
 	var oldServices []string
 	return func(d interface{}) (render, trigger bool) {
@@ -103,6 +269,9 @@ func MakeTriggerCheckCatalogService() TriggerCheck {
 		newServices := make([]string, len(new))
 		for ix, s := range new {
 			newServices[ix] = s.Name
+			if includeTags {
+				newServices[ix] += "=" + sortedTags(s.Tags)
+			}
 		}
 		sort.Strings(newServices)
 		// Different length should immediately notify.
@@ -121,3 +290,12 @@ func MakeTriggerCheckCatalogService() TriggerCheck {
 		return false, false
 	}
 }
+
+// sortedTags returns the tags as a sorted, comma-separated string so that
+// tag sets can be compared regardless of ordering.
+func sortedTags(tags []string) string {
+	t := make([]string, len(tags))
+	copy(t, tags)
+	sort.Strings(t)
+	return strings.Join(t, ",")
+}
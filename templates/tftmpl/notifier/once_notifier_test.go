@@ -4,11 +4,16 @@
 package notifier
 
 import (
+	"errors"
 	"testing"
 
 	mocks "github.com/hashicorp/consul-terraform-sync/mocks/templates"
+	"github.com/hashicorp/consul-terraform-sync/templates/tftmpl/tmplfunc"
+	"github.com/hashicorp/hcat"
 	"github.com/hashicorp/hcat/dep"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestOnceNotifier(t *testing.T) {
@@ -37,6 +42,105 @@ func TestOnceNotifier(t *testing.T) {
 	})
 }
 
+func TestOnceNotifier_DependencyTriggers(t *testing.T) {
+	triggerCheck := func(d interface{}) (bool, bool) {
+		_, ok := d.([]*dep.HealthService)
+		return ok, ok
+	}
+	web := []*dep.HealthService{{Name: "web"}}
+	api := []*dep.HealthService{{Name: "api"}}
+
+	tmpl := &mocks.Template{}
+	tmpl.EXPECT().Notify(web).Return(false)
+	tmpl.EXPECT().Notify(api).Return(false)
+	n := NewOnceNotifier(triggerCheck, tmpl)
+
+	// Triggers in once-mode are not counted
+	n.Notify(web)
+	assert.Empty(t, n.DependencyTriggers())
+
+	n.SetOnceDone()
+	n.Notify(web)
+	n.Notify(web)
+	n.Notify(api)
+	// Data that does not trigger the task is not counted
+	n.Notify(&dep.KeyPair{Key: "key"})
+
+	assert.Equal(t, map[string]int{
+		"services.web": 2,
+		"services.api": 1,
+	}, n.DependencyTriggers())
+}
+
+func TestOnceNotifier_Unchanged(t *testing.T) {
+	t.Parallel()
+
+	tmpl := &mocks.Template{}
+	tmpl.EXPECT().Render([]byte("a")).Return(hcat.RenderResult{}, nil)
+	tmpl.EXPECT().Render([]byte("b")).Return(hcat.RenderResult{}, nil)
+	tmpl.EXPECT().Render([]byte("err")).Return(hcat.RenderResult{}, errors.New("error"))
+	n := NewOnceNotifier(TriggerCheckService, tmpl)
+
+	// Nothing applied yet
+	assert.False(t, n.Unchanged([]byte("a")))
+	_, err := n.Render([]byte("a"))
+	require.NoError(t, err)
+	assert.False(t, n.Unchanged([]byte("a")))
+
+	n.SetApplied()
+	assert.True(t, n.Unchanged([]byte("a")))
+	assert.False(t, n.Unchanged([]byte("b")))
+
+	// Rendered but not applied, e.g. the run failed
+	_, err = n.Render([]byte("b"))
+	require.NoError(t, err)
+	assert.False(t, n.Unchanged([]byte("a")))
+	assert.False(t, n.Unchanged([]byte("b")))
+
+	n.SetApplied()
+	assert.True(t, n.Unchanged([]byte("b")))
+
+	// Render errors
+	_, err = n.Render([]byte("err"))
+	assert.Error(t, err)
+	assert.False(t, n.Unchanged([]byte("b")))
+}
+
+// testDependency is a dependency that is only identified by its name
+type testDependency struct {
+	dep.Dependency
+	name string
+}
+
+func (d testDependency) String() string {
+	return d.name
+}
+
+func TestOnceNotifier_Dependencies(t *testing.T) {
+	t.Parallel()
+
+	recaller := func(d dep.Dependency) (interface{}, bool) {
+		return nil, d.String() == "health.service(api)"
+	}
+
+	tmpl := &mocks.Template{}
+	tmpl.EXPECT().Execute(mock.Anything).Run(func(recall hcat.Recaller) {
+		recall(testDependency{name: "health.service(api)"})
+		recall(testDependency{name: "kv.block(key)"})
+	}).Return([]byte{}, nil)
+	n := NewOnceNotifier(TriggerCheckService, tmpl)
+
+	// Not yet executed
+	assert.Empty(t, n.Dependencies())
+
+	_, err := n.Execute(recaller)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{
+		"health.service(api)": true,
+		"kv.block(key)":       false,
+	}, n.Dependencies())
+}
+
 func TestTriggerCheckConsulKV(t *testing.T) {
 	re, tr := TriggerCheckConsulKV((*dep.KeyPair)(nil))
 	assert.True(t, re)
@@ -49,6 +153,33 @@ func TestTriggerCheckConsulKV(t *testing.T) {
 	assert.False(t, tr)
 }
 
+func TestTriggerCheckDNS(t *testing.T) {
+	re, tr := TriggerCheckDNS(([]*tmplfunc.DNSRecord)(nil))
+	assert.True(t, re)
+	assert.True(t, tr)
+	re, tr = TriggerCheckDNS(nil)
+	assert.False(t, re)
+	assert.False(t, tr)
+}
+
+func TestTriggerCheckFile(t *testing.T) {
+	re, tr := TriggerCheckFile(([]*tmplfunc.File)(nil))
+	assert.True(t, re)
+	assert.True(t, tr)
+	re, tr = TriggerCheckFile(nil)
+	assert.False(t, re)
+	assert.False(t, tr)
+}
+
+func TestTriggerCheckPlugin(t *testing.T) {
+	re, tr := TriggerCheckPlugin((*tmplfunc.PluginOutput)(nil))
+	assert.True(t, re)
+	assert.True(t, tr)
+	re, tr = TriggerCheckPlugin(nil)
+	assert.False(t, re)
+	assert.False(t, tr)
+}
+
 func TestTriggerCheckService(t *testing.T) {
 	re, tr := TriggerCheckService(([]*dep.HealthService)(nil))
 	assert.True(t, re)
@@ -58,6 +189,33 @@ func TestTriggerCheckService(t *testing.T) {
 	assert.False(t, tr)
 }
 
+func TestMakeTriggerCheckAnyInputChange(t *testing.T) {
+	check := MakeTriggerCheckAnyInputChange(MakeTriggerCheckCatalogService(),
+		"catalog_services")
+
+	t.Run("condition decides for its data", func(t *testing.T) {
+		re, tr := check([]*dep.CatalogSnippet{{Name: "one"}})
+		assert.True(t, re)
+		assert.True(t, tr)
+		re, tr = check([]*dep.CatalogSnippet{{Name: "one"}})
+		assert.False(t, re)
+		assert.False(t, tr)
+	})
+	t.Run("trigger on module input data", func(t *testing.T) {
+		re, tr := check([]*dep.HealthService{{Name: "api"}})
+		assert.True(t, re)
+		assert.True(t, tr)
+		re, tr = check(&dep.KeyPair{Key: "key"})
+		assert.True(t, re)
+		assert.True(t, tr)
+	})
+	t.Run("no trigger on nil", func(t *testing.T) {
+		re, tr := check(nil)
+		assert.False(t, re)
+		assert.False(t, tr)
+	})
+}
+
 func TestMakeTriggerCheckCatalogService(t *testing.T) {
 	t.Run("only trigger on snippets", func(t *testing.T) {
 		check := MakeTriggerCheckCatalogService()
@@ -91,3 +249,48 @@ func TestMakeTriggerCheckCatalogService(t *testing.T) {
 		assert.True(t, tr)
 	})
 }
+
+func TestMakeTriggerCheckCatalogServiceTags(t *testing.T) {
+	t.Run("only trigger on snippets", func(t *testing.T) {
+		check := MakeTriggerCheckCatalogServiceTags()
+		re, tr := check(nil)
+		assert.False(t, re)
+		assert.False(t, tr)
+	})
+	t.Run("trigger when change detected", func(t *testing.T) {
+		check := MakeTriggerCheckCatalogServiceTags()
+		re, tr := check([]*dep.CatalogSnippet{
+			{Name: "one", Tags: dep.ServiceTags{"a"}},
+		})
+		assert.True(t, re)
+		assert.True(t, tr)
+		re, tr = check([]*dep.CatalogSnippet{
+			{Name: "one", Tags: dep.ServiceTags{"a", "b"}},
+		})
+		assert.True(t, re)
+		assert.True(t, tr)
+		re, tr = check([]*dep.CatalogSnippet{
+			{Name: "one", Tags: dep.ServiceTags{"b", "a"}},
+		})
+		assert.False(t, re)
+		assert.False(t, tr)
+		re, tr = check([]*dep.CatalogSnippet{
+			{Name: "one", Tags: dep.ServiceTags{"b"}},
+		})
+		assert.True(t, re)
+		assert.True(t, tr)
+	})
+	t.Run("tags ignored without tag check", func(t *testing.T) {
+		check := MakeTriggerCheckCatalogService()
+		re, tr := check([]*dep.CatalogSnippet{
+			{Name: "one", Tags: dep.ServiceTags{"a"}},
+		})
+		assert.True(t, re)
+		assert.True(t, tr)
+		re, tr = check([]*dep.CatalogSnippet{
+			{Name: "one", Tags: dep.ServiceTags{"b"}},
+		})
+		assert.False(t, re)
+		assert.False(t, tr)
+	})
+}
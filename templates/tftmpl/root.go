@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/hashicorp/consul-terraform-sync/internal/hcl2shim"
 	"github.com/hashicorp/consul-terraform-sync/logging"
@@ -49,6 +50,14 @@ const (
 	// working directory
 	VarsTFVarsFileName = "variables.auto.tfvars"
 
+	// SecretsTFVarsFilename is the file name for the values of the sensitive
+	// variables of the task. The file is only readable by the owner.
+	SecretsTFVarsFilename = "secrets.auto.tfvars"
+
+	// secretsFilePerms are the most permissive file permissions of files that
+	// contain the values of sensitive variables
+	secretsFilePerms os.FileMode = 0600
+
 	// TFVarsTmplFilename is the template file for TFVarsFilename. This is used
 	// by hcat for monitoring service changes from Consul.
 	TFVarsTmplFilename = "terraform.tfvars.tmpl"
@@ -58,6 +67,23 @@ const (
 	// written in a separate file from terraform.tfvars because it may contain
 	// sensitive or secret values.
 	ProvidersTFVarsFilename = "providers.auto.tfvars"
+
+	// ComputedTFVarsFilename is the file name for the values of the computed
+	// input variables of a task. The values are evaluated from the variables
+	// rendered to TFVarsFilename.
+	ComputedTFVarsFilename = "computed.auto.tfvars"
+
+	// RenderHashFilename is the file name for the hash of the generated root
+	// module files and rendered variables of the last successful run of a
+	// task. The hash is compared to the current files to determine whether
+	// the task changed since it last ran.
+	RenderHashFilename = "render.sha256"
+
+	// environmentTFVarsFilenameFmt is the format of the file name for the
+	// input variables of an environment of a task. The file does not use the
+	// *.auto.tfvars naming convention since it is only passed to Terraform
+	// for the environment.
+	environmentTFVarsFilenameFmt = "environment.%s.tfvars"
 )
 
 var (
@@ -98,6 +124,15 @@ type Task struct {
 	Name        string
 	Module      string
 	Version     string
+
+	// ServicesChanged determines if the services_changed variable is passed
+	// to the module
+	ServicesChanged bool
+
+	// ProviderAliases are the IDs of the aliased providers, <name>.<alias>,
+	// that are passed to the module with the providers meta-argument. The
+	// input variables of the aliased providers are named <name>_<alias>.
+	ProviderAliases []string
 }
 
 type tfFileFunc func(io.Writer, string, *RootModuleInputData) error
@@ -112,6 +147,19 @@ type RootModuleInputData struct {
 	Variables        hcltmpl.Variables
 	Templates        []Template
 
+	// SensitiveVariables are the names of the variables whose values are
+	// sensitive. Their values are written to SecretsTFVarsFilename instead of
+	// VarsTFVarsFileName.
+	SensitiveVariables []string
+
+	// ComputedInputs are the names of the variables of the module that are
+	// computed from the rendered variables, see RenderComputedInputs
+	ComputedInputs []string
+
+	// Environments are the variables that override the task's variables for
+	// each environment of the task by the environment name
+	Environments map[string]hcltmpl.Variables
+
 	Path      string
 	FilePerms os.FileMode
 
@@ -119,6 +167,10 @@ type RootModuleInputData struct {
 	skipOverride bool
 
 	backend *hcltmpl.NamedBlock
+
+	// secretFiles are the files that contain the values of sensitive
+	// variables and are written with restricted permissions
+	secretFiles map[string]bool
 }
 
 // init processes input data used to generate a Terraform root module. It
@@ -133,8 +185,13 @@ func (d *RootModuleInputData) init() {
 	}
 
 	sort.Slice(d.Providers, func(i, j int) bool {
+		if d.Providers[i].Name == d.Providers[j].Name {
+			return providerVarName(d.Providers[i], true) < providerVarName(d.Providers[j], true)
+		}
 		return d.Providers[i].Name < d.Providers[j].Name
 	})
+
+	sort.Strings(d.ComputedInputs)
 }
 
 // InitRootModule generates the root module and writes the following files to
@@ -147,18 +204,65 @@ func InitRootModule(input *RootModuleInputData) error {
 	input.init()
 
 	fileFuncs := make(map[string]tfFileFunc)
+	input.secretFiles = make(map[string]bool)
 	if len(input.Variables) != 0 {
 		fileFuncs[VarsTFVarsFileName] = newVariablesTFVars
 	}
+	if len(input.sensitiveVariables(input.Variables)) != 0 {
+		fileFuncs[SecretsTFVarsFilename] = newSecretsTFVars
+		input.secretFiles[SecretsTFVarsFilename] = true
+	}
 	for k, v := range rootFileFuncs {
 		fileFuncs[k] = v
 	}
 	for k, v := range tfvarsFileFuncs {
 		fileFuncs[k] = v
 	}
+	for name, vars := range input.Environments {
+		filename := EnvironmentTFVarsFilename(name)
+		fileFuncs[filename] = newEnvironmentTFVars(vars)
+		if len(input.sensitiveVariables(vars)) != 0 {
+			input.secretFiles[filename] = true
+		}
+	}
 	return initModule(input, fileFuncs)
 }
 
+// isSensitive returns whether the variable is sensitive
+func (d *RootModuleInputData) isSensitive(name string) bool {
+	for _, v := range d.SensitiveVariables {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// sensitiveVariables returns the sensitive variables of vars
+func (d *RootModuleInputData) sensitiveVariables(vars hcltmpl.Variables) hcltmpl.Variables {
+	sensitive := make(hcltmpl.Variables)
+	for k, v := range vars {
+		if d.isSensitive(k) {
+			sensitive[k] = v
+		}
+	}
+	return sensitive
+}
+
+// filePerms returns the file permissions of a file of the root module
+func (d *RootModuleInputData) filePerms(filename string) os.FileMode {
+	if d.secretFiles[filename] {
+		return d.FilePerms & secretsFilePerms
+	}
+	return d.FilePerms
+}
+
+// EnvironmentTFVarsFilename returns the file name for the input variables of
+// an environment of a task
+func EnvironmentTFVarsFilename(name string) string {
+	return fmt.Sprintf(environmentTFVarsFilenameFmt, name)
+}
+
 func initModule(input *RootModuleInputData, fileFuncs map[string]tfFileFunc) error {
 	for filename, newFileFunc := range fileFuncs {
 		if filename == ModuleVarsFilename && len(input.Variables) == 0 {
@@ -186,7 +290,7 @@ func initModule(input *RootModuleInputData, fileFuncs map[string]tfFileFunc) err
 		}
 		defer f.Close()
 
-		if err := f.Chmod(input.FilePerms); err != nil {
+		if err := f.Chmod(input.filePerms(filename)); err != nil {
 			logger.Error("error, unable to change permissions for file in root module", "error", err)
 			return err
 		}
@@ -214,9 +318,12 @@ func newMainTF(w io.Writer, filename string, input *RootModuleInputData) error {
 	rootBody.AppendNewline()
 	appendRootTerraformBlock(rootBody, input.backend, input.ProviderInfo)
 	rootBody.AppendNewline()
-	appendRootProviderBlocks(rootBody, input.Providers)
+	appendRootProviderBlocks(rootBody, input.Providers, len(input.Task.ProviderAliases) > 0)
 	rootBody.AppendNewline()
-	appendRootModuleBlock(rootBody, input.Task, input.Variables.Keys(), input.Templates...)
+
+	// computed inputs are passed to the module like the task's variables
+	varNames := append(input.Variables.Keys(), input.ComputedInputs...)
+	appendRootModuleBlock(rootBody, input.Task, varNames, input.Templates...)
 
 	// Format the file before writing
 	content := hclFile.Bytes()
@@ -256,11 +363,15 @@ func appendRootTerraformBlock(body *hclwrite.Body, backend *hcltmpl.NamedBlock,
 }
 
 // appendRootProviderBlocks appends Terraform provider blocks for the providers
-// the task requires.
-func appendRootProviderBlocks(body *hclwrite.Body, providers []hcltmpl.NamedBlock) {
+// the task requires. The alias of aliased providers is kept so that the
+// providers can be passed to the module.
+func appendRootProviderBlocks(body *hclwrite.Body, providers []hcltmpl.NamedBlock,
+	aliased bool) {
+
 	lastIdx := len(providers) - 1
 	for i, p := range providers {
 		providerBody := body.AppendNewBlock("provider", []string{p.Name}).Body()
+		varName := providerVarName(p, aliased)
 
 		// Convert user provider config to provider block arguments from variables
 		// and sort the attributes / sub-attributes for consistency. Format
@@ -271,9 +382,12 @@ func appendRootProviderBlocks(body *hclwrite.Body, providers []hcltmpl.NamedBloc
 		// }
 		providerAttrs := p.SortedAttributes()
 		for _, attr := range providerAttrs {
-			// Drop the alias meta attribute. Each provider instance will be ran as
-			// a separate task
+			// Drop the alias meta attribute unless the providers are aliased.
+			// Otherwise, each provider instance will be ran as a separate task
 			if attr == "alias" {
+				if aliased {
+					providerBody.SetAttributeValue(attr, p.Variables[attr])
+				}
 				continue
 			}
 			// auto_commit is an internal setting
@@ -292,7 +406,7 @@ func appendRootProviderBlocks(body *hclwrite.Body, providers []hcltmpl.NamedBloc
 				for _, subAttr := range sortedKeys(subAttrs) {
 					objProviderBody.SetAttributeTraversal(subAttr, hcl.Traversal{
 						hcl.TraverseRoot{Name: "var"},
-						hcl.TraverseAttr{Name: p.Name},
+						hcl.TraverseAttr{Name: varName},
 						hcl.TraverseAttr{Name: attr},
 						hcl.TraverseAttr{Name: subAttr},
 					})
@@ -302,7 +416,7 @@ func appendRootProviderBlocks(body *hclwrite.Body, providers []hcltmpl.NamedBloc
 
 			providerBody.SetAttributeTraversal(attr, hcl.Traversal{
 				hcl.TraverseRoot{Name: "var"},
-				hcl.TraverseAttr{Name: p.Name},
+				hcl.TraverseAttr{Name: varName},
 				hcl.TraverseAttr{Name: attr},
 			})
 		}
@@ -312,6 +426,20 @@ func appendRootProviderBlocks(body *hclwrite.Body, providers []hcltmpl.NamedBloc
 	}
 }
 
+// providerVarName returns the name of the input variable for the provider
+// block, which is the provider name. The variable of an aliased provider is
+// named <name>_<alias> so that each alias has its own variable.
+func providerVarName(p hcltmpl.NamedBlock, aliased bool) string {
+	if !aliased {
+		return p.Name
+	}
+	alias, ok := p.Variables["alias"]
+	if !ok || alias.IsNull() || alias.Type() != cty.String {
+		return p.Name
+	}
+	return fmt.Sprintf("%s_%s", p.Name, alias.AsString())
+}
+
 // appendRootModuleBlock appends a Terraform module block for the task
 func appendRootModuleBlock(body *hclwrite.Body, task Task, varNames []string, templates ...Template) {
 
@@ -329,11 +457,22 @@ func appendRootModuleBlock(body *hclwrite.Body, task Task, varNames []string, te
 		moduleBody.SetAttributeValue("version", cty.StringVal(task.Version))
 	}
 
+	if len(task.ProviderAliases) > 0 {
+		appendModuleProviders(moduleBody, task.ProviderAliases)
+	}
+
 	moduleBody.SetAttributeTraversal("services", hcl.Traversal{
 		hcl.TraverseRoot{Name: "var"},
 		hcl.TraverseAttr{Name: "services"},
 	})
 
+	if task.ServicesChanged {
+		moduleBody.SetAttributeTraversal(servicesChangedVarName, hcl.Traversal{
+			hcl.TraverseRoot{Name: "var"},
+			hcl.TraverseAttr{Name: servicesChangedVarName},
+		})
+	}
+
 	for _, t := range templates {
 		if t != nil && t.RendersVar() {
 			t.appendModuleAttribute(moduleBody)
@@ -351,6 +490,36 @@ func appendRootModuleBlock(body *hclwrite.Body, task Task, varNames []string, te
 	}
 }
 
+// appendModuleProviders sets the providers meta-argument of the module to pass
+// the aliased providers to the module by alias.
+//
+//	providers = {
+//	  aws.dc1 = aws.dc1
+//	}
+func appendModuleProviders(moduleBody *hclwrite.Body, ids []string) {
+	sorted := make([]string, len(ids))
+	copy(sorted, ids)
+	sort.Strings(sorted)
+
+	attrs := make([]hclwrite.ObjectAttrTokens, 0, len(sorted))
+	for _, id := range sorted {
+		var traversal hcl.Traversal
+		for i, part := range strings.SplitN(id, ".", 2) {
+			if i == 0 {
+				traversal = append(traversal, hcl.TraverseRoot{Name: part})
+				continue
+			}
+			traversal = append(traversal, hcl.TraverseAttr{Name: part})
+		}
+		tokens := hclwrite.TokensForTraversal(traversal)
+		attrs = append(attrs, hclwrite.ObjectAttrTokens{
+			Name:  tokens,
+			Value: tokens,
+		})
+	}
+	moduleBody.SetAttributeRaw("providers", hclwrite.TokensForObject(attrs))
+}
+
 // appendComment appends a single HCL comment line
 func appendComment(b *hclwrite.Body, comment string) {
 	b.AppendUnstructuredTokens(hclwrite.Tokens{{
@@ -4,11 +4,16 @@
 package tftmpl
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/consul-terraform-sync/templates/hcltmpl"
+	goVersion "github.com/hashicorp/go-version"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func TestAppendRootTerraformBlock_backend(t *testing.T) {
@@ -162,7 +167,7 @@ func TestAppendRootProviderBlocks(t *testing.T) {
 			body := hclFile.Body()
 
 			backend := []hcltmpl.NamedBlock{hcltmpl.NewNamedBlock(tc.rawBackend)}
-			appendRootProviderBlocks(body, backend)
+			appendRootProviderBlocks(body, backend, false)
 
 			content := hclFile.Bytes()
 			content = hclwrite.Format(content)
@@ -171,6 +176,53 @@ func TestAppendRootProviderBlocks(t *testing.T) {
 	}
 }
 
+func TestAppendRootProviderBlocks_aliased(t *testing.T) {
+	providers := []hcltmpl.NamedBlock{
+		hcltmpl.NewNamedBlock(map[string]interface{}{"aws": map[string]interface{}{
+			"alias":  "dc1",
+			"region": "us-east-1",
+		}}),
+		hcltmpl.NewNamedBlock(map[string]interface{}{"aws": map[string]interface{}{
+			"alias":  "dc2",
+			"region": "us-west-2",
+			"assume_role": map[string]interface{}{
+				"role_arn": "arn",
+			},
+		}}),
+	}
+
+	hclFile := hclwrite.NewEmptyFile()
+	appendRootProviderBlocks(hclFile.Body(), providers, true)
+
+	expected := `provider "aws" {
+  alias  = "dc1"
+  region = var.aws_dc1.region
+}
+
+provider "aws" {
+  alias = "dc2"
+  assume_role {
+    role_arn = var.aws_dc2.assume_role.role_arn
+  }
+  region = var.aws_dc2.region
+}
+`
+	assert.Equal(t, expected, string(hclwrite.Format(hclFile.Bytes())))
+}
+
+func TestProviderVarName(t *testing.T) {
+	aliased := hcltmpl.NewNamedBlock(map[string]interface{}{
+		"aws": map[string]interface{}{"alias": "dc1"},
+	})
+	unaliased := hcltmpl.NewNamedBlock(map[string]interface{}{
+		"aws": map[string]interface{}{"region": "us-east-1"},
+	})
+
+	assert.Equal(t, "aws", providerVarName(aliased, false))
+	assert.Equal(t, "aws_dc1", providerVarName(aliased, true))
+	assert.Equal(t, "aws", providerVarName(unaliased, true))
+}
+
 func TestAppendRootModuleBlocks(t *testing.T) {
 	testCases := []struct {
 		name      string
@@ -261,6 +313,41 @@ module "test" {
   services         = var.services
   catalog_services = var.catalog_services
 }
+`},
+		{
+			name: "module with services changed",
+			task: Task{
+				Name:            "test",
+				Module:          "namespace/example/test-module",
+				ServicesChanged: true,
+			},
+			templates: []Template{},
+			varNames:  nil,
+			expected: `module "test" {
+  source           = "namespace/example/test-module"
+  services         = var.services
+  services_changed = var.services_changed
+}
+`},
+		{
+			name: "module with provider aliases",
+			task: Task{
+				Name:            "test",
+				Module:          "namespace/example/test-module",
+				Version:         "1.2.0",
+				ProviderAliases: []string{"aws.dc2", "aws.dc1"},
+			},
+			templates: []Template{},
+			varNames:  nil,
+			expected: `module "test" {
+  source  = "namespace/example/test-module"
+  version = "1.2.0"
+  providers = {
+    aws.dc1 = aws.dc1
+    aws.dc2 = aws.dc2
+  }
+  services = var.services
+}
 `},
 	}
 
@@ -276,3 +363,55 @@ module "test" {
 		})
 	}
 }
+
+func TestInitRootModule_sensitiveVariables(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	input := RootModuleInputData{
+		TerraformVersion: goVersion.Must(goVersion.NewSemver("0.99.9")),
+		Task: Task{
+			Name:   "test",
+			Module: "namespace/consul-terraform-sync/consul//modules/test",
+		},
+		Variables: hcltmpl.Variables{
+			"password": cty.StringVal("secret"),
+			"region":   cty.StringVal("us-east-1"),
+		},
+		SensitiveVariables: []string{"password"},
+		Environments: map[string]hcltmpl.Variables{
+			"prod":    {"password": cty.StringVal("prod-secret")},
+			"staging": {"region": cty.StringVal("us-west-2")},
+		},
+		Path:      dir,
+		FilePerms: os.FileMode(0640),
+	}
+	require.NoError(t, InitRootModule(&input))
+
+	read := func(filename string) (string, os.FileMode) {
+		path := filepath.Join(dir, filename)
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		return string(content), info.Mode().Perm()
+	}
+
+	vars, perm := read(VarsTFVarsFileName)
+	assert.Contains(t, vars, `region = "us-east-1"`)
+	assert.NotContains(t, vars, "secret")
+	assert.Equal(t, os.FileMode(0640), perm)
+
+	secrets, perm := read(SecretsTFVarsFilename)
+	assert.Contains(t, secrets, `password = "secret"`)
+	assert.NotContains(t, secrets, "region")
+	assert.Equal(t, os.FileMode(0600), perm)
+
+	moduleVars, _ := read(ModuleVarsFilename)
+	assert.Contains(t, moduleVars, "sensitive = true")
+
+	_, perm = read(EnvironmentTFVarsFilename("prod"))
+	assert.Equal(t, os.FileMode(0600), perm)
+	_, perm = read(EnvironmentTFVarsFilename("staging"))
+	assert.Equal(t, os.FileMode(0640), perm)
+}
@@ -0,0 +1,144 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tftmpl
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// scaffoldVariables are the variable definitions of the CTS variable contract
+// for each monitored object type that is passed as an input to modules
+var scaffoldVariables = map[string][]byte{
+	"catalog-services": variableCatalogServices,
+	"consul-kv":        variableConsulKV,
+	"dns":              variableDNSRecords,
+	"file":             variableFiles,
+}
+
+// scaffoldTypes are the monitored object types that do not render a
+// variable in addition to the services variable
+var scaffoldTypes = map[string]bool{
+	"services": true,
+	"schedule": true,
+}
+
+// scaffoldMainTF is the starter content of the main.tf file for a module
+var scaffoldMainTF = []byte(`terraform {
+  required_version = ">= 0.13.0"
+}
+
+# Add the resources that automate the network infrastructure for the
+# services monitored by Consul-Terraform-Sync. For example:
+#
+# resource "local_file" "addresses" {
+#   content  = join("\n", [for s in var.services : s.address])
+#   filename = "addresses.txt"
+# }
+`)
+
+// scaffoldOutputsTF is the starter content of the outputs.tf file for a module
+var scaffoldOutputsTF = []byte(`# Outputs of the module. For example:
+#
+# output "service_ids" {
+#   value = keys(var.services)
+# }
+`)
+
+// ModuleScaffoldInput is the input to generate a starter module for CTS
+type ModuleScaffoldInput struct {
+	// Path is the directory to write the module files to
+	Path string
+
+	// Condition is the type of the task condition, e.g. "services"
+	Condition string
+
+	// ModuleInputs are the types of the task module inputs
+	ModuleInputs []string
+
+	// ExtendedMetadata determines if the services variable includes the
+	// extended service metadata
+	ExtendedMetadata bool
+}
+
+// ScaffoldModule generates a starter module with the variable definitions
+// that match the variables rendered by CTS for the configured condition and
+// module inputs. Existing files are not overwritten.
+func ScaffoldModule(input ModuleScaffoldInput) error {
+	variables, err := ScaffoldVariables(input)
+	if err != nil {
+		return err
+	}
+
+	files := []struct {
+		name    string
+		content []byte
+	}{
+		{RootFilename, scaffoldMainTF},
+		{VarsFilename, variables},
+		{"outputs.tf", scaffoldOutputsTF},
+	}
+
+	for _, f := range files {
+		path := filepath.Join(input.Path, f.name)
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("file %q already exists", path)
+		}
+	}
+
+	if err := os.MkdirAll(input.Path, 0755); err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		path := filepath.Join(input.Path, f.name)
+		if err := os.WriteFile(path, f.content, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ScaffoldVariables returns the content of the variables.tf file for a module
+// with the variable definitions for the condition and module inputs. The
+// services variable is always included since it is passed to every module.
+func ScaffoldVariables(input ModuleScaffoldInput) ([]byte, error) {
+	types := make(map[string]bool)
+	if input.Condition != "" {
+		types[input.Condition] = true
+	}
+	for _, mi := range input.ModuleInputs {
+		if mi == "schedule" {
+			return nil, fmt.Errorf("schedule is not a supported module input type")
+		}
+		types[mi] = true
+	}
+
+	var names []string
+	for t := range types {
+		if _, ok := scaffoldVariables[t]; !ok && !scaffoldTypes[t] {
+			return nil, fmt.Errorf("unsupported type %q", t)
+		}
+		if !scaffoldTypes[t] {
+			names = append(names, t)
+		}
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	servicesVar := VariableServices
+	if input.ExtendedMetadata {
+		servicesVar = VariableServicesExtended
+	}
+	buf.Write(bytes.TrimLeft(servicesVar, "\n"))
+	for _, name := range names {
+		buf.Write(scaffoldVariables[name])
+	}
+
+	return buf.Bytes(), nil
+}
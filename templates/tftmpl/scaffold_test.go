@@ -0,0 +1,126 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tftmpl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScaffoldVariables(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		input     ModuleScaffoldInput
+		expected  []string
+		expectErr bool
+	}{
+		{
+			"services condition",
+			ModuleScaffoldInput{Condition: "services"},
+			[]string{"services"},
+			false,
+		},
+		{
+			"schedule condition",
+			ModuleScaffoldInput{Condition: "schedule"},
+			[]string{"services"},
+			false,
+		},
+		{
+			"condition and module inputs",
+			ModuleScaffoldInput{
+				Condition:    "catalog-services",
+				ModuleInputs: []string{"consul-kv", "services"},
+			},
+			[]string{"services", "catalog_services", "consul_kv"},
+			false,
+		},
+		{
+			"duplicate types",
+			ModuleScaffoldInput{
+				Condition:    "consul-kv",
+				ModuleInputs: []string{"consul-kv"},
+			},
+			[]string{"services", "consul_kv"},
+			false,
+		},
+		{
+			"schedule module input",
+			ModuleScaffoldInput{ModuleInputs: []string{"schedule"}},
+			nil,
+			true,
+		},
+		{
+			"unsupported type",
+			ModuleScaffoldInput{Condition: "nodes"},
+			nil,
+			true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			content, err := ScaffoldVariables(tc.input)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			f, diags := hclparse.NewParser().ParseHCL(content, VarsFilename)
+			require.False(t, diags.HasErrors(), diags.Error())
+
+			body, _, diags := f.Body.PartialContent(&hcl.BodySchema{
+				Blocks: []hcl.BlockHeaderSchema{
+					{Type: "variable", LabelNames: []string{"name"}},
+				},
+			})
+			require.False(t, diags.HasErrors(), diags.Error())
+
+			var names []string
+			for _, b := range body.Blocks {
+				names = append(names, b.Labels[0])
+			}
+			assert.Equal(t, tc.expected, names)
+		})
+	}
+
+	t.Run("extended metadata", func(t *testing.T) {
+		content, err := ScaffoldVariables(ModuleScaffoldInput{
+			Condition:        "services",
+			ExtendedMetadata: true,
+		})
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "weights = object({")
+	})
+}
+
+func TestScaffoldModule(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "my-module")
+	input := ModuleScaffoldInput{
+		Path:      dir,
+		Condition: "services",
+	}
+	require.NoError(t, ScaffoldModule(input))
+
+	for _, name := range []string{RootFilename, VarsFilename, "outputs.tf"} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		assert.NoError(t, err, name)
+	}
+
+	t.Run("existing files", func(t *testing.T) {
+		err := ScaffoldModule(input)
+		assert.Error(t, err)
+	})
+}
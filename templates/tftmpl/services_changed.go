@@ -0,0 +1,172 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tftmpl
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	// ServicesChangedFilename is the file name for the services_changed
+	// variable. Terraform automatically loads variable files with the
+	// .auto.tfvars extension.
+	ServicesChangedFilename = "services_changed.auto.tfvars"
+
+	// ServicesSnapshotFilename is the file name for the snapshot of the
+	// variables of the last successful run of a task. The services_changed
+	// variable is the difference between the snapshot and the current
+	// services variable.
+	ServicesSnapshotFilename = "terraform.tfvars.snapshot"
+
+	servicesChangedVarName = "services_changed"
+)
+
+// VariableServicesChanged is the variable for the service instances that
+// changed since the last successful run of a task. The instances have the same
+// object type as the instances of the services variable and are keyed by
+// service instance ID. Removed instances are the values from the last
+// successful run.
+var VariableServicesChanged = []byte(`
+# Services changed definition protocol v0
+variable "services_changed" {
+  description = "Consul service instances added, removed, or modified since the last successful run of the task"
+  type = object({
+    added    = map(any)
+    modified = map(any)
+    removed  = map(any)
+  })
+  default = {
+    added    = {}
+    modified = {}
+    removed  = {}
+  }
+}
+`)
+
+// NewServicesChangedTFVars returns the content of the variable file for the
+// services_changed variable. The delta is determined by comparing the
+// services variable of the previous variable file content, which can be nil
+// for the first run, to the services variable of the current content.
+func NewServicesChangedTFVars(previous, current []byte) ([]byte, error) {
+	prevServices, err := parseServicesVar(previous)
+	if err != nil {
+		return nil, err
+	}
+
+	currServices, err := parseServicesVar(current)
+	if err != nil {
+		return nil, err
+	}
+
+	added := make(map[string]cty.Value)
+	modified := make(map[string]cty.Value)
+	removed := make(map[string]cty.Value)
+	for id, curr := range currServices {
+		prev, ok := prevServices[id]
+		if !ok {
+			added[id] = curr
+		} else if !prev.RawEquals(curr) {
+			modified[id] = curr
+		}
+	}
+	for id, prev := range prevServices {
+		if _, ok := currServices[id]; !ok {
+			removed[id] = prev
+		}
+	}
+
+	hclFile := hclwrite.NewEmptyFile()
+	hclFile.Body().SetAttributeValue(servicesChangedVarName, cty.ObjectVal(
+		map[string]cty.Value{
+			"added":    objectVal(added),
+			"modified": objectVal(modified),
+			"removed":  objectVal(removed),
+		}))
+
+	return hclwrite.Format(hclFile.Bytes()), nil
+}
+
+// ChangedServiceNames returns the sorted names of the services that have
+// service instances added, removed, or modified between the services variable
+// of the previous variable file content and the current content.
+func ChangedServiceNames(previous, current []byte) ([]string, error) {
+	prevServices, err := parseServicesVar(previous)
+	if err != nil {
+		return nil, err
+	}
+
+	currServices, err := parseServicesVar(current)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for id, curr := range currServices {
+		if prev, ok := prevServices[id]; !ok || !prev.RawEquals(curr) {
+			names[serviceName(curr)] = true
+		}
+	}
+	for id, prev := range prevServices {
+		if _, ok := currServices[id]; !ok {
+			names[serviceName(prev)] = true
+		}
+	}
+
+	changed := make([]string, 0, len(names))
+	for name := range names {
+		changed = append(changed, name)
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// serviceName returns the name attribute of a service instance value
+func serviceName(instance cty.Value) string {
+	if !instance.Type().IsObjectType() || !instance.Type().HasAttribute("name") {
+		return ""
+	}
+
+	name := instance.GetAttr("name")
+	if name.IsNull() || !name.IsKnown() || name.Type() != cty.String {
+		return ""
+	}
+	return name.AsString()
+}
+
+// parseServicesVar parses variable file content and returns the service
+// instances of the services variable keyed by ID
+func parseServicesVar(content []byte) (map[string]cty.Value, error) {
+	services := make(map[string]cty.Value)
+	if len(content) == 0 {
+		return services, nil
+	}
+
+	variables, err := ParseModuleVariables(content, TFVarsFilename)
+	if err != nil {
+		return nil, err
+	}
+
+	val, ok := variables["services"]
+	if !ok || val.IsNull() || !val.CanIterateElements() {
+		return services, nil
+	}
+
+	for it := val.ElementIterator(); it.Next(); {
+		k, v := it.Element()
+		services[k.AsString()] = v
+	}
+	return services, nil
+}
+
+// objectVal returns an object value for the map of values, which can have
+// different types
+func objectVal(m map[string]cty.Value) cty.Value {
+	if len(m) == 0 {
+		return cty.EmptyObjectVal
+	}
+	return cty.ObjectVal(m)
+}
@@ -0,0 +1,178 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tftmpl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestNewServicesChangedTFVars(t *testing.T) {
+	t.Parallel()
+
+	web := `"web.node.dc1" = {
+    id      = "web"
+    address = "10.0.0.1"
+  }`
+	webModified := `"web.node.dc1" = {
+    id      = "web"
+    address = "10.0.0.2"
+  }`
+	api := `"api.node.dc1" = {
+    id      = "api"
+    address = "10.0.0.3"
+  }`
+
+	testCases := []struct {
+		name     string
+		previous string
+		current  string
+		added    []string
+		modified []string
+		removed  []string
+	}{
+		{
+			"first run",
+			"",
+			"services = {\n  " + web + "\n}\n",
+			[]string{"web.node.dc1"},
+			nil,
+			nil,
+		},
+		{
+			"no changes",
+			"services = {\n  " + web + "\n}\n",
+			"services = {\n  " + web + "\n}\n",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"added modified and removed",
+			"services = {\n  " + web + ",\n  " + api + "\n}\n",
+			"services = {\n  " + webModified + "\n}\n",
+			nil,
+			[]string{"web.node.dc1"},
+			[]string{"api.node.dc1"},
+		},
+		{
+			"no services",
+			"services = {\n  " + api + "\n}\n",
+			"services = {}\n",
+			nil,
+			nil,
+			[]string{"api.node.dc1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			content, err := NewServicesChangedTFVars([]byte(tc.previous),
+				[]byte(tc.current))
+			require.NoError(t, err)
+
+			vars, err := ParseModuleVariables(content, ServicesChangedFilename)
+			require.NoError(t, err)
+			changed, ok := vars["services_changed"]
+			require.True(t, ok)
+
+			assert.Equal(t, tc.added, objectKeys(changed.GetAttr("added")))
+			assert.Equal(t, tc.modified, objectKeys(changed.GetAttr("modified")))
+			assert.Equal(t, tc.removed, objectKeys(changed.GetAttr("removed")))
+		})
+	}
+
+	t.Run("removed value is the previous value", func(t *testing.T) {
+		content, err := NewServicesChangedTFVars(
+			[]byte("services = {\n  "+api+"\n}\n"), []byte("services = {}\n"))
+		require.NoError(t, err)
+
+		vars, err := ParseModuleVariables(content, ServicesChangedFilename)
+		require.NoError(t, err)
+		removed := vars["services_changed"].GetAttr("removed").GetAttr("api.node.dc1")
+		assert.Equal(t, cty.StringVal("10.0.0.3"), removed.GetAttr("address"))
+	})
+
+	t.Run("invalid content", func(t *testing.T) {
+		_, err := NewServicesChangedTFVars(nil, []byte("services = {"))
+		assert.Error(t, err)
+	})
+}
+
+func TestChangedServiceNames(t *testing.T) {
+	t.Parallel()
+
+	web := `"web.node.dc1" = {
+    id      = "web"
+    name    = "web"
+    address = "10.0.0.1"
+  }`
+	webModified := `"web.node.dc1" = {
+    id      = "web"
+    name    = "web"
+    address = "10.0.0.2"
+  }`
+	api := `"api.node.dc1" = {
+    id      = "api"
+    name    = "api"
+    address = "10.0.0.3"
+  }`
+	db := `"db.node.dc1" = {
+    id      = "db"
+    name    = "db"
+    address = "10.0.0.4"
+  }`
+
+	testCases := []struct {
+		name     string
+		previous string
+		current  string
+		expected []string
+	}{
+		{
+			"first run",
+			"",
+			"services = {\n  " + web + "\n}\n",
+			[]string{"web"},
+		},
+		{
+			"no changes",
+			"services = {\n  " + web + "\n}\n",
+			"services = {\n  " + web + "\n}\n",
+			[]string{},
+		},
+		{
+			"added modified and removed",
+			"services = {\n  " + web + ",\n  " + api + "\n}\n",
+			"services = {\n  " + webModified + ",\n  " + db + "\n}\n",
+			[]string{"api", "db", "web"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			names, err := ChangedServiceNames([]byte(tc.previous),
+				[]byte(tc.current))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, names)
+		})
+	}
+
+	t.Run("invalid content", func(t *testing.T) {
+		_, err := ChangedServiceNames(nil, []byte("services = {"))
+		assert.Error(t, err)
+	})
+}
+
+func objectKeys(val cty.Value) []string {
+	var keys []string
+	for it := val.ElementIterator(); it.Next(); {
+		k, _ := it.Element()
+		keys = append(keys, k.AsString())
+	}
+	return keys
+}
@@ -41,3 +41,32 @@ type Template interface {
 	// the variables.tf file.
 	appendVariable(io.Writer) error
 }
+
+// extendedMetadataTemplate is implemented by templates for the services
+// variable that can optionally include extended service metadata
+type extendedMetadataTemplate interface {
+	includesExtendedMetadata() bool
+}
+
+// includesExtendedMetadata returns true if a template renders the services
+// variable with extended service metadata
+func includesExtendedMetadata(templates []Template) bool {
+	for _, t := range templates {
+		if !t.RendersVar() || !t.IsServicesVar() {
+			continue
+		}
+		if et, ok := t.(extendedMetadataTemplate); ok && et.includesExtendedMetadata() {
+			return true
+		}
+	}
+	return false
+}
+
+// hclServiceFuncName returns the name of the template function to marshal
+// a service into HCL
+func hclServiceFuncName(extended bool) string {
+	if extended {
+		return "HCLServiceExtended"
+	}
+	return "HCLService"
+}
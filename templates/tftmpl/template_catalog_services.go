@@ -22,8 +22,14 @@ type CatalogServicesTemplate struct {
 	Regexp     string
 	Datacenter string
 	Namespace  string
+	Kind       string
 	NodeMeta   map[string]string
 
+	// CountInstances informs whether the template should also monitor the
+	// number of registered instances of the services. Aligns with the task
+	// condition configuration `min_instances` and `max_instances`
+	CountInstances bool
+
 	// RenderVar informs whether the template should render the variable or not.
 	// Aligns with the task condition configuration `UseAsModuleInput``
 	RenderVar bool
@@ -48,9 +54,13 @@ func (t CatalogServicesTemplate) appendModuleAttribute(body *hclwrite.Body) {
 
 func (t CatalogServicesTemplate) appendTemplate(w io.Writer) error {
 	q := t.hcatQuery()
+	f := "catalogServicesRegistration"
+	if t.CountInstances {
+		f = "catalogServicesInstances"
+	}
 
 	if t.RenderVar {
-		_, err := fmt.Fprintf(w, catalogServicesSetVarTmpl, q)
+		_, err := fmt.Fprintf(w, catalogServicesSetVarTmpl, f, q)
 		if err != nil {
 			err = fmt.Errorf("unable to write catalog-service template with variable, error: %v", err)
 			return err
@@ -58,7 +68,7 @@ func (t CatalogServicesTemplate) appendTemplate(w io.Writer) error {
 		return nil
 	}
 
-	if _, err := fmt.Fprintf(w, catalogServicesEmptyTmpl, q); err != nil {
+	if _, err := fmt.Fprintf(w, catalogServicesEmptyTmpl, f, q); err != nil {
 		err = fmt.Errorf("unable to write catalog-service empty template, error %v", err)
 		return err
 	}
@@ -85,6 +95,10 @@ func (t CatalogServicesTemplate) hcatQuery() string {
 		opts = append(opts, fmt.Sprintf("ns=%s", t.Namespace))
 	}
 
+	if t.Kind != "" {
+		opts = append(opts, fmt.Sprintf("kind=%s", t.Kind))
+	}
+
 	for k, v := range t.NodeMeta {
 		opts = append(opts, fmt.Sprintf("node-meta=%s:%s", k, v))
 	}
@@ -100,14 +114,14 @@ catalog_services = {%s}
 `, catalogServicesBaseTmpl)
 
 const catalogServicesBaseTmpl = `
-{{- with $catalogServices := catalogServicesRegistration %s}}
+{{- with $catalogServices := %s %s}}
   {{- range $cs := $catalogServices }}
   "{{ $cs.Name }}" = {{ HCLServiceTags $cs.Tags }}
 {{- end}}{{- end}}
 `
 
 const catalogServicesEmptyTmpl = `
-{{- with $catalogServices := catalogServicesRegistration %s}}
+{{- with $catalogServices := %s %s}}
   {{- range $cs := $catalogServices }}
     {{- /* Empty template. Detects changes in catalog-services */ -}}
 {{- end}}{{- end}}
@@ -20,7 +20,12 @@ var (
 // ConsulKVTemplate handles the template for the consul_kv variable for the
 // template functions: `{{ key }}` and `{{ keyExistsGet }}`
 type ConsulKVTemplate struct {
-	Path       string
+	Path string
+
+	// Paths are the paths to monitor as an alternative to Path. The
+	// key-values of the paths are rendered to a single consul_kv variable.
+	Paths []string
+
 	Recurse    bool
 	Datacenter string
 	Namespace  string
@@ -51,17 +56,21 @@ func (t ConsulKVTemplate) appendModuleAttribute(body *hclwrite.Body) {
 // It determines which template to use based on the values of the RenderVar and
 // recurse options. If RenderVar is true, then set the consul_kv variable to
 // the template. If recurse is set to true, then use the 'keys' template,
-// otherwise use the 'keyExists'/'key' template.
+// otherwise use the 'keyExists'/'key' template. With multiple paths, the
+// templates of each path are concatenated so that the key-values of all of
+// the paths are combined into the consul_kv variable.
 func (t ConsulKVTemplate) appendTemplate(w io.Writer) error {
 	logger := logging.Global().Named(logSystemName).Named(tftmplSubsystemName)
-	q := t.hcatQuery()
 
 	if t.RenderVar {
 		var baseTmpl string
-		if t.Recurse {
-			baseTmpl = fmt.Sprintf(consulKVRecurseBaseTmpl, q)
-		} else {
-			baseTmpl = fmt.Sprintf(consulKVBaseTmpl, q)
+		for _, p := range t.paths() {
+			q := t.hcatQuery(p)
+			if t.Recurse {
+				baseTmpl += fmt.Sprintf(consulKVRecurseBaseTmpl, q)
+			} else {
+				baseTmpl += fmt.Sprintf(consulKVBaseTmpl, q)
+			}
 		}
 
 		if _, err := fmt.Fprintf(w, consulKVSetVarTmpl, baseTmpl); err != nil {
@@ -72,10 +81,13 @@ func (t ConsulKVTemplate) appendTemplate(w io.Writer) error {
 	}
 
 	var emptyTmpl string
-	if t.Recurse {
-		emptyTmpl = fmt.Sprintf(consulKVRecurseEmptyTmpl, q)
-	} else {
-		emptyTmpl = fmt.Sprintf(consulKVEmptyTmpl, q)
+	for _, p := range t.paths() {
+		q := t.hcatQuery(p)
+		if t.Recurse {
+			emptyTmpl += fmt.Sprintf(consulKVRecurseEmptyTmpl, q)
+		} else {
+			emptyTmpl += fmt.Sprintf(consulKVEmptyTmpl, q)
+		}
 	}
 	if _, err := w.Write([]byte(emptyTmpl)); err != nil {
 		logger.Error("unable to write consul-kv empty template", "error", err)
@@ -89,10 +101,18 @@ func (t ConsulKVTemplate) appendVariable(w io.Writer) error {
 	return err
 }
 
-func (t ConsulKVTemplate) hcatQuery() string {
+// paths returns the paths to monitor, either the paths or the path
+func (t ConsulKVTemplate) paths() []string {
+	if len(t.Paths) > 0 {
+		return t.Paths
+	}
+	return []string{t.Path}
+}
+
+func (t ConsulKVTemplate) hcatQuery(path string) string {
 	var opts []string
 
-	opts = append(opts, t.Path)
+	opts = append(opts, path)
 
 	if t.Datacenter != "" {
 		opts = append(opts, fmt.Sprintf("dc=%s", t.Datacenter))
@@ -37,7 +37,7 @@ func TestConsulKVTemplate_hcatQuery(t *testing.T) {
 
 	for _, tc := range testcase {
 		t.Run(tc.name, func(t *testing.T) {
-			actual := tc.c.hcatQuery()
+			actual := tc.c.hcatQuery(tc.c.Path)
 			assert.Equal(t, tc.exp, actual)
 		})
 	}
@@ -85,6 +85,45 @@ consul_kv = {
   {{- end}}
 {{- end}}
 }
+`,
+		},
+		{
+			"paths & render var",
+			&ConsulKVTemplate{
+				Paths:     []string{"a/b", "c/d"},
+				Recurse:   true,
+				RenderVar: true,
+			},
+			`
+consul_kv = {
+{{- with $kv := keys "a/b" }}
+  {{- range $k := $kv }}
+  "{{ .Path }}" = "{{ .Value }}"
+  {{- end}}
+{{- end}}
+
+{{- with $kv := keys "c/d" }}
+  {{- range $k := $kv }}
+  "{{ .Path }}" = "{{ .Value }}"
+  {{- end}}
+{{- end}}
+}
+`,
+		},
+		{
+			"paths & no var",
+			&ConsulKVTemplate{
+				Paths:     []string{"a/b", "c/d"},
+				RenderVar: false,
+			},
+			`
+{{- with $kv := keyExistsGet "a/b" }}
+  {{- /* Empty template. Detects changes in Consul KV */ -}}
+{{- end}}
+
+{{- with $kv := keyExistsGet "c/d" }}
+  {{- /* Empty template. Detects changes in Consul KV */ -}}
+{{- end}}
 `,
 		},
 		{
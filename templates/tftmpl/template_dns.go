@@ -0,0 +1,124 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tftmpl
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+var (
+	_ Template = (*DNSTemplate)(nil)
+)
+
+// DNSTemplate handles the template for the dns_records variable for the
+// template function: `{{ dnsRecords }}`
+type DNSTemplate struct {
+	Name       string
+	RecordType string
+	Interval   time.Duration
+
+	// RenderVar informs whether the template should render the variable or not.
+	// Aligns with the task condition configuration `UseAsModuleInput``
+	RenderVar bool
+}
+
+// IsServicesVar returns false because the template returns a dns_records
+// variable, not a services variable
+func (t DNSTemplate) IsServicesVar() bool {
+	return false
+}
+
+func (t DNSTemplate) RendersVar() bool {
+	return t.RenderVar
+}
+
+func (t DNSTemplate) appendModuleAttribute(body *hclwrite.Body) {
+	body.SetAttributeTraversal("dns_records", hcl.Traversal{
+		hcl.TraverseRoot{Name: "var"},
+		hcl.TraverseAttr{Name: "dns_records"},
+	})
+}
+
+func (t DNSTemplate) appendTemplate(w io.Writer) error {
+	q := t.hcatQuery()
+
+	if t.RenderVar {
+		_, err := fmt.Fprintf(w, dnsSetVarTmpl, q)
+		if err != nil {
+			err = fmt.Errorf("unable to write dns template with variable, error: %v", err)
+			return err
+		}
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(w, dnsEmptyTmpl, q); err != nil {
+		err = fmt.Errorf("unable to write dns empty template, error %v", err)
+		return err
+	}
+	return nil
+}
+
+func (t DNSTemplate) appendVariable(w io.Writer) error {
+	_, err := w.Write(variableDNSRecords)
+	return err
+}
+
+func (t DNSTemplate) hcatQuery() string {
+	opts := []string{fmt.Sprintf("name=%s", t.Name)}
+
+	if t.RecordType != "" {
+		opts = append(opts, fmt.Sprintf("type=%s", t.RecordType))
+	}
+
+	if t.Interval > 0 {
+		opts = append(opts, fmt.Sprintf("interval=%s", t.Interval))
+	}
+
+	return `"` + strings.Join(opts, `" "`) + `" ` // deliberate space at end
+}
+
+var dnsSetVarTmpl = fmt.Sprintf(`
+dns_records = [%s]
+`, dnsBaseTmpl)
+
+const dnsBaseTmpl = `
+{{- with $records := dnsRecords %s}}
+  {{- range $r := $records }}
+  {
+    address  = "{{ $r.Address }}"
+    port     = {{ $r.Port }}
+    priority = {{ $r.Priority }}
+    weight   = {{ $r.Weight }}
+  },
+{{- end}}{{- end}}
+`
+
+const dnsEmptyTmpl = `
+{{- with $records := dnsRecords %s}}
+  {{- range $r := $records }}
+    {{- /* Empty template. Detects changes in DNS records */ -}}
+{{- end}}{{- end}}
+`
+
+// variableDNSRecords is required for modules that include DNS record
+// information. It is versioned to track compatibility between the generated
+// root module and modules that include DNS records.
+var variableDNSRecords = []byte(`
+# DNS records definition protocol v0
+variable "dns_records" {
+  description = "Records resolved for the DNS name monitored by the task"
+  type = list(object({
+    address  = string
+    port     = number
+    priority = number
+    weight   = number
+  }))
+}
+`)
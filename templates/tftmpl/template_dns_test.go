@@ -0,0 +1,98 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tftmpl
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDNSTemplate_hcatQuery(t *testing.T) {
+	testcase := []struct {
+		name string
+		c    *DNSTemplate
+		exp  string
+	}{
+		{
+			"name only",
+			&DNSTemplate{
+				Name: "example.com",
+			},
+			`"name=example.com" `,
+		},
+		{
+			"all_parameters",
+			&DNSTemplate{
+				Name:       "_http._tcp.example.com",
+				RecordType: "SRV",
+				Interval:   10 * time.Second,
+			},
+			`"name=_http._tcp.example.com" "type=SRV" "interval=10s" `,
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tc.c.hcatQuery()
+			assert.Equal(t, tc.exp, actual)
+		})
+	}
+}
+
+func TestDNSTemplate_appendTemplate(t *testing.T) {
+	testcases := []struct {
+		name string
+		c    *DNSTemplate
+		exp  string
+	}{
+		{
+			"render var",
+			&DNSTemplate{
+				Name:       "example.com",
+				RecordType: "A",
+				RenderVar:  true,
+			},
+			`
+dns_records = [
+{{- with $records := dnsRecords "name=example.com" "type=A" }}
+  {{- range $r := $records }}
+  {
+    address  = "{{ $r.Address }}"
+    port     = {{ $r.Port }}
+    priority = {{ $r.Priority }}
+    weight   = {{ $r.Weight }}
+  },
+{{- end}}{{- end}}
+]
+`,
+		},
+		{
+			"no render var",
+			&DNSTemplate{
+				Name:       "example.com",
+				RecordType: "A",
+				RenderVar:  false,
+			},
+			`
+{{- with $records := dnsRecords "name=example.com" "type=A" }}
+  {{- range $r := $records }}
+    {{- /* Empty template. Detects changes in DNS records */ -}}
+{{- end}}{{- end}}
+`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := new(strings.Builder)
+			err := tc.c.appendTemplate(w)
+			require.NoError(t, err)
+			assert.Equal(t, tc.exp, w.String())
+		})
+	}
+}
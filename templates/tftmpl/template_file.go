@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tftmpl
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+var (
+	_ Template = (*FileTemplate)(nil)
+)
+
+// FileTemplate handles the template for the files variable for the
+// template function: `{{ localFiles }}`
+type FileTemplate struct {
+	Paths          []string
+	Interval       time.Duration
+	IncludeContent bool
+
+	// RenderVar informs whether the template should render the variable or not.
+	// Aligns with the task condition configuration `UseAsModuleInput``
+	RenderVar bool
+}
+
+// IsServicesVar returns false because the template returns a files
+// variable, not a services variable
+func (t FileTemplate) IsServicesVar() bool {
+	return false
+}
+
+func (t FileTemplate) RendersVar() bool {
+	return t.RenderVar
+}
+
+func (t FileTemplate) appendModuleAttribute(body *hclwrite.Body) {
+	body.SetAttributeTraversal("files", hcl.Traversal{
+		hcl.TraverseRoot{Name: "var"},
+		hcl.TraverseAttr{Name: "files"},
+	})
+}
+
+func (t FileTemplate) appendTemplate(w io.Writer) error {
+	q := t.hcatQuery()
+
+	if t.RenderVar {
+		_, err := fmt.Fprintf(w, fileSetVarTmpl, q)
+		if err != nil {
+			err = fmt.Errorf("unable to write file template with variable, error: %v", err)
+			return err
+		}
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(w, fileEmptyTmpl, q); err != nil {
+		err = fmt.Errorf("unable to write file empty template, error %v", err)
+		return err
+	}
+	return nil
+}
+
+func (t FileTemplate) appendVariable(w io.Writer) error {
+	_, err := w.Write(variableFiles)
+	return err
+}
+
+func (t FileTemplate) hcatQuery() string {
+	opts := make([]string, 0, len(t.Paths)+2)
+	for _, p := range t.Paths {
+		opts = append(opts, fmt.Sprintf("path=%s", p))
+	}
+
+	if t.Interval > 0 {
+		opts = append(opts, fmt.Sprintf("interval=%s", t.Interval))
+	}
+
+	opts = append(opts, fmt.Sprintf("content=%t", t.IncludeContent))
+
+	return `"` + strings.Join(opts, `" "`) + `" ` // deliberate space at end
+}
+
+var fileSetVarTmpl = fmt.Sprintf(`
+files = {%s}
+`, fileBaseTmpl)
+
+const fileBaseTmpl = `
+{{- with $files := localFiles %s}}
+  {{- range $f := $files }}
+  {{ hclString $f.Path }} = {
+    sha256  = "{{ $f.SHA256 }}"
+    content = {{ hclString $f.Content }}
+  }
+{{- end}}{{- end}}
+`
+
+const fileEmptyTmpl = `
+{{- with $files := localFiles %s}}
+  {{- range $f := $files }}
+    {{- /* Empty template. Detects changes in local files */ -}}
+{{- end}}{{- end}}
+`
+
+// variableFiles is required for modules that include local file information.
+// It is versioned to track compatibility between the generated root module
+// and modules that include local files.
+var variableFiles = []byte(`
+# Local files definition protocol v0
+variable "files" {
+  description = "Local files monitored by the task, keyed by path"
+  type = map(object({
+    sha256  = string
+    content = string
+  }))
+}
+`)
@@ -0,0 +1,96 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tftmpl
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileTemplate_hcatQuery(t *testing.T) {
+	testcase := []struct {
+		name string
+		c    *FileTemplate
+		exp  string
+	}{
+		{
+			"paths only",
+			&FileTemplate{
+				Paths: []string{"/etc/cts/rules"},
+			},
+			`"path=/etc/cts/rules" "content=false" `,
+		},
+		{
+			"all_parameters",
+			&FileTemplate{
+				Paths:          []string{"/etc/cts/allowlist.txt", "/etc/cts/rules"},
+				Interval:       10 * time.Second,
+				IncludeContent: true,
+			},
+			`"path=/etc/cts/allowlist.txt" "path=/etc/cts/rules" "interval=10s" "content=true" `,
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tc.c.hcatQuery()
+			assert.Equal(t, tc.exp, actual)
+		})
+	}
+}
+
+func TestFileTemplate_appendTemplate(t *testing.T) {
+	testcases := []struct {
+		name string
+		c    *FileTemplate
+		exp  string
+	}{
+		{
+			"render var",
+			&FileTemplate{
+				Paths:          []string{"/etc/cts/rules"},
+				IncludeContent: true,
+				RenderVar:      true,
+			},
+			`
+files = {
+{{- with $files := localFiles "path=/etc/cts/rules" "content=true" }}
+  {{- range $f := $files }}
+  {{ hclString $f.Path }} = {
+    sha256  = "{{ $f.SHA256 }}"
+    content = {{ hclString $f.Content }}
+  }
+{{- end}}{{- end}}
+}
+`,
+		},
+		{
+			"no render var",
+			&FileTemplate{
+				Paths:          []string{"/etc/cts/rules"},
+				IncludeContent: true,
+				RenderVar:      false,
+			},
+			`
+{{- with $files := localFiles "path=/etc/cts/rules" "content=true" }}
+  {{- range $f := $files }}
+    {{- /* Empty template. Detects changes in local files */ -}}
+{{- end}}{{- end}}
+`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := new(strings.Builder)
+			err := tc.c.appendTemplate(w)
+			require.NoError(t, err)
+			assert.Equal(t, tc.exp, w.String())
+		})
+	}
+}
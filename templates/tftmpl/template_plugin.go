@@ -0,0 +1,110 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tftmpl
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+var (
+	_ Template = (*PluginTemplate)(nil)
+)
+
+// PluginTemplate handles the template for the plugin_data variable for the
+// template function: `{{ plugin }}`
+type PluginTemplate struct {
+	Command  string
+	Args     []string
+	Interval time.Duration
+
+	// RenderVar informs whether the template should render the variable or not.
+	// Aligns with the task condition configuration `UseAsModuleInput``
+	RenderVar bool
+}
+
+// IsServicesVar returns false because the template returns a plugin_data
+// variable, not a services variable
+func (t PluginTemplate) IsServicesVar() bool {
+	return false
+}
+
+func (t PluginTemplate) RendersVar() bool {
+	return t.RenderVar
+}
+
+func (t PluginTemplate) appendModuleAttribute(body *hclwrite.Body) {
+	body.SetAttributeTraversal("plugin_data", hcl.Traversal{
+		hcl.TraverseRoot{Name: "var"},
+		hcl.TraverseAttr{Name: "plugin_data"},
+	})
+}
+
+func (t PluginTemplate) appendTemplate(w io.Writer) error {
+	q := t.hcatQuery()
+
+	if t.RenderVar {
+		_, err := fmt.Fprintf(w, pluginSetVarTmpl, q)
+		if err != nil {
+			err = fmt.Errorf("unable to write plugin template with variable, error: %v", err)
+			return err
+		}
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(w, pluginEmptyTmpl, q); err != nil {
+		err = fmt.Errorf("unable to write plugin empty template, error %v", err)
+		return err
+	}
+	return nil
+}
+
+func (t PluginTemplate) appendVariable(w io.Writer) error {
+	_, err := w.Write(variablePluginData)
+	return err
+}
+
+// hcatQuery returns the options of the template function. Unlike the other
+// templates, the options are quoted as Go strings since plugin arguments can
+// contain any character.
+func (t PluginTemplate) hcatQuery() string {
+	opts := make([]string, 0, len(t.Args)+2)
+	opts = append(opts, strconv.Quote("command="+t.Command))
+
+	for _, a := range t.Args {
+		opts = append(opts, strconv.Quote("arg="+a))
+	}
+
+	if t.Interval > 0 {
+		opts = append(opts, strconv.Quote(fmt.Sprintf("interval=%s", t.Interval)))
+	}
+
+	return strings.Join(opts, " ") + " " // deliberate space at end
+}
+
+const pluginSetVarTmpl = `
+plugin_data = {{ with $p := plugin %s}}{{ $p.Data }}{{ else }}null{{ end }}
+`
+
+const pluginEmptyTmpl = `
+{{- with $p := plugin %s}}
+  {{- /* Empty template. Detects changes in the plugin output */ -}}
+{{- end}}
+`
+
+// variablePluginData is required for modules that include the output of a
+// condition plugin. The type of the output is defined by the plugin.
+var variablePluginData = []byte(`
+# Condition plugin data definition protocol v0
+variable "plugin_data" {
+  description = "JSON output of the condition plugin monitored by the task"
+  type = any
+}
+`)
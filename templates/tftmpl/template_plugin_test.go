@@ -0,0 +1,85 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tftmpl
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginTemplate_hcatQuery(t *testing.T) {
+	testcase := []struct {
+		name string
+		c    *PluginTemplate
+		exp  string
+	}{
+		{
+			"command only",
+			&PluginTemplate{
+				Command: "/opt/cts/plugins/s3-bucket",
+			},
+			`"command=/opt/cts/plugins/s3-bucket" `,
+		},
+		{
+			"all_parameters",
+			&PluginTemplate{
+				Command:  "/opt/cts/plugins/s3-bucket",
+				Args:     []string{"-bucket", `"releases"`},
+				Interval: 10 * time.Second,
+			},
+			`"command=/opt/cts/plugins/s3-bucket" "arg=-bucket" "arg=\"releases\"" "interval=10s" `,
+		},
+	}
+
+	for _, tc := range testcase {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tc.c.hcatQuery()
+			assert.Equal(t, tc.exp, actual)
+		})
+	}
+}
+
+func TestPluginTemplate_appendTemplate(t *testing.T) {
+	testcases := []struct {
+		name string
+		c    *PluginTemplate
+		exp  string
+	}{
+		{
+			"render var",
+			&PluginTemplate{
+				Command:   "/opt/cts/plugins/s3-bucket",
+				RenderVar: true,
+			},
+			`
+plugin_data = {{ with $p := plugin "command=/opt/cts/plugins/s3-bucket" }}{{ $p.Data }}{{ else }}null{{ end }}
+`,
+		},
+		{
+			"no render var",
+			&PluginTemplate{
+				Command:   "/opt/cts/plugins/s3-bucket",
+				RenderVar: false,
+			},
+			`
+{{- with $p := plugin "command=/opt/cts/plugins/s3-bucket" }}
+  {{- /* Empty template. Detects changes in the plugin output */ -}}
+{{- end}}
+`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := new(strings.Builder)
+			err := tc.c.appendTemplate(w)
+			require.NoError(t, err)
+			assert.Equal(t, tc.exp, w.String())
+		})
+	}
+}
@@ -38,6 +38,10 @@ type ServicesTemplate struct {
 	// filtering configured. Services or the set of {Datacenter,
 	// Namespace, Filter} can be configured but not both.
 	Services map[string]Service
+
+	// IncludeExtendedMetadata informs whether the services variable includes
+	// extended metadata for each service instance, like weights
+	IncludeExtendedMetadata bool
 }
 
 // Service contains additional Consul service filtering information for services
@@ -55,6 +59,10 @@ func (t ServicesTemplate) IsServicesVar() bool {
 
 func (t ServicesTemplate) appendModuleAttribute(*hclwrite.Body) {}
 
+func (t ServicesTemplate) includesExtendedMetadata() bool {
+	return t.IncludeExtendedMetadata
+}
+
 func (t ServicesTemplate) appendTemplate(w io.Writer) error {
 	tmpl, err := t.concatServiceTemplates()
 	if err != nil {
@@ -104,7 +112,8 @@ func (t ServicesTemplate) concatServiceTemplates() (string, error) {
 		}
 
 		if t.RenderVar {
-			tmpl += fmt.Sprintf(serviceBaseTmpl, query)
+			tmpl += fmt.Sprintf(serviceBaseTmpl, query,
+				hclServiceFuncName(t.IncludeExtendedMetadata))
 		} else {
 			tmpl += fmt.Sprintf(serviceEmptyTmpl, query)
 		}
@@ -158,12 +167,13 @@ services = {%s}
 // serviceBaseTmpl is a template for a single monitored service. Multiple
 // service requires concatenating multiple base templates. There is no newline
 // at the end of this template (unlike other templates) to prevent a gap in the
-// templates
+// templates. The template expects the hcat query and the name of the template
+// function to marshal the service.
 const serviceBaseTmpl = `
 {{- with $srv := service %s }}
   {{- range $s := $srv}}
   "{{ joinStrings "." .ID .Node .Namespace .NodeDatacenter }}" = {
-{{ HCLService $s | indent 4 }}
+{{ %s $s | indent 4 }}
   },
   {{- end}}
 {{- end}}`
@@ -0,0 +1,103 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tftmpl
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/consul-terraform-sync/logging"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+var (
+	_ Template = (*ServicesQueryTemplate)(nil)
+)
+
+// ServicesQueryTemplate handles the template for the services variable for the
+// template function: `{{ servicesQuery }}`
+type ServicesQueryTemplate struct {
+	// Query is the name or ID of the Consul prepared query to execute
+	Query      string
+	Datacenter string
+
+	// RenderVar informs whether the template should render the variable or not.
+	// Aligns with the task condition configuration `UseAsModuleInput``
+	RenderVar bool
+
+	// IncludeExtendedMetadata informs whether the services variable includes
+	// extended metadata for each service instance, like weights
+	IncludeExtendedMetadata bool
+}
+
+// IsServicesVar returns true because the template is for the services variable
+func (t ServicesQueryTemplate) IsServicesVar() bool {
+	return true
+}
+
+func (t ServicesQueryTemplate) appendModuleAttribute(*hclwrite.Body) {}
+
+func (t ServicesQueryTemplate) includesExtendedMetadata() bool {
+	return t.IncludeExtendedMetadata
+}
+
+func (t ServicesQueryTemplate) appendTemplate(w io.Writer) error {
+	q := t.hcatQuery()
+
+	tmpl := ""
+	if t.RenderVar {
+		tmpl = fmt.Sprintf(servicesQuerySetVarTmpl, q,
+			hclServiceFuncName(t.IncludeExtendedMetadata))
+	} else {
+		tmpl = fmt.Sprintf(servicesQueryEmptyTmpl, q)
+	}
+
+	if _, err := fmt.Fprint(w, tmpl); err != nil {
+		logging.Global().Named(logSystemName).Named(tftmplSubsystemName).Error(
+			"unable to write services query template", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (t ServicesQueryTemplate) appendVariable(io.Writer) error {
+	return nil
+}
+
+func (t ServicesQueryTemplate) RendersVar() bool {
+	return t.RenderVar
+}
+
+func (t ServicesQueryTemplate) hcatQuery() string {
+	opts := []string{fmt.Sprintf("query=%s", t.Query)}
+
+	if t.Datacenter != "" {
+		opts = append(opts, fmt.Sprintf("dc=%s", t.Datacenter))
+	}
+
+	return `"` + strings.Join(opts, `" "`) + `"`
+}
+
+var servicesQuerySetVarTmpl = fmt.Sprintf(`
+services = {%s}
+`, servicesQueryBaseTmpl)
+
+const servicesQueryBaseTmpl = `
+{{- with $srv := servicesQuery %s }}
+  {{- range $s := $srv}}
+  "{{ joinStrings "." .ID .Node .Namespace .NodeDatacenter }}" = {
+{{ %s $s | indent 4 }}
+  },
+  {{- end}}
+{{- end}}
+`
+
+const servicesQueryEmptyTmpl = `
+{{- with $srv := servicesQuery %s }}
+  {{- range $s := $srv}}
+  {{- /* Empty template. Detects changes in Services */ -}}
+  {{- end}}
+{{- end}}
+`
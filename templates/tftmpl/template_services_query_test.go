@@ -0,0 +1,82 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tftmpl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServicesQueryTemplate_appendTemplate(t *testing.T) {
+	testcases := []struct {
+		name string
+		c    *ServicesQueryTemplate
+		exp  string
+	}{
+		{
+			"fully configured & render var",
+			&ServicesQueryTemplate{
+				Query:      "nearest-api",
+				Datacenter: "dc1",
+				RenderVar:  true,
+			},
+			`
+services = {
+{{- with $srv := servicesQuery "query=nearest-api" "dc=dc1" }}
+  {{- range $s := $srv}}
+  "{{ joinStrings "." .ID .Node .Namespace .NodeDatacenter }}" = {
+{{ HCLService $s | indent 4 }}
+  },
+  {{- end}}
+{{- end}}
+}
+`,
+		},
+		{
+			"include extended metadata",
+			&ServicesQueryTemplate{
+				Query:                   "nearest-api",
+				RenderVar:               true,
+				IncludeExtendedMetadata: true,
+			},
+			`
+services = {
+{{- with $srv := servicesQuery "query=nearest-api" }}
+  {{- range $s := $srv}}
+  "{{ joinStrings "." .ID .Node .Namespace .NodeDatacenter }}" = {
+{{ HCLServiceExtended $s | indent 4 }}
+  },
+  {{- end}}
+{{- end}}
+}
+`,
+		},
+		{
+			"no render var",
+			&ServicesQueryTemplate{
+				Query:     "nearest-api",
+				RenderVar: false,
+			},
+			`
+{{- with $srv := servicesQuery "query=nearest-api" }}
+  {{- range $s := $srv}}
+  {{- /* Empty template. Detects changes in Services */ -}}
+  {{- end}}
+{{- end}}
+`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := new(strings.Builder)
+			err := tc.c.appendTemplate(w)
+			require.NoError(t, err)
+			assert.Equal(t, tc.exp, w.String())
+		})
+	}
+}
@@ -27,6 +27,14 @@ type ServicesRegexTemplate struct {
 	// RenderVar informs whether the template should render the variable or not.
 	// Aligns with the task condition configuration `UseAsModuleInput``
 	RenderVar bool
+
+	// IncludeExtendedMetadata informs whether the services variable includes
+	// extended metadata for each service instance, like weights
+	IncludeExtendedMetadata bool
+
+	// Bootstrap informs whether the initial load of the services fetches the
+	// health of the matching services concurrently
+	Bootstrap bool
 }
 
 // IsServicesVar returns true because the template is for the services variable
@@ -36,12 +44,17 @@ func (t ServicesRegexTemplate) IsServicesVar() bool {
 
 func (t ServicesRegexTemplate) appendModuleAttribute(*hclwrite.Body) {}
 
+func (t ServicesRegexTemplate) includesExtendedMetadata() bool {
+	return t.IncludeExtendedMetadata
+}
+
 func (t ServicesRegexTemplate) appendTemplate(w io.Writer) error {
 	q := t.hcatQuery()
 
 	tmpl := ""
 	if t.RenderVar {
-		tmpl = fmt.Sprintf(servicesRegexSetVarTmpl, q)
+		tmpl = fmt.Sprintf(servicesRegexSetVarTmpl, q,
+			hclServiceFuncName(t.IncludeExtendedMetadata))
 	} else {
 		tmpl = fmt.Sprintf(servicesRegexEmptyTmpl, q)
 	}
@@ -76,6 +89,10 @@ func (t ServicesRegexTemplate) hcatQuery() string {
 		opts = append(opts, fmt.Sprintf("ns=%s", t.Namespace))
 	}
 
+	if t.Bootstrap {
+		opts = append(opts, "bootstrap=true")
+	}
+
 	if t.Filter != "" {
 		filter := strings.ReplaceAll(t.Filter, `"`, `\"`)
 		filter = strings.Trim(filter, "\n")
@@ -96,7 +113,7 @@ const servicesRegexBaseTmpl = `
 {{- with $srv := servicesRegex %s }}
   {{- range $s := $srv}}
   "{{ joinStrings "." .ID .Node .Namespace .NodeDatacenter }}" = {
-{{ HCLService $s | indent 4 }}
+{{ %s $s | indent 4 }}
   },
   {{- end}}
 {{- end}}
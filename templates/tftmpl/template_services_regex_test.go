@@ -36,6 +36,41 @@ services = {
   {{- end}}
 {{- end}}
 }
+`,
+		},
+		{
+			"include extended metadata",
+			&ServicesRegexTemplate{
+				Regexp:                  ".*",
+				RenderVar:               true,
+				IncludeExtendedMetadata: true,
+			},
+			`
+services = {
+{{- with $srv := servicesRegex "regexp=.*" }}
+  {{- range $s := $srv}}
+  "{{ joinStrings "." .ID .Node .Namespace .NodeDatacenter }}" = {
+{{ HCLServiceExtended $s | indent 4 }}
+  },
+  {{- end}}
+{{- end}}
+}
+`,
+		},
+		{
+			"bootstrap",
+			&ServicesRegexTemplate{
+				Regexp:    ".*",
+				Filter:    "filter",
+				RenderVar: false,
+				Bootstrap: true,
+			},
+			`
+{{- with $srv := servicesRegex "regexp=.*" "bootstrap=true" "filter" }}
+  {{- range $s := $srv}}
+  {{- /* Empty template. Detects changes in Services */ -}}
+  {{- end}}
+{{- end}}
 `,
 		},
 		{
@@ -235,6 +235,25 @@ services = {
   {{- end}}
 {{- end}}
 }
+`,
+		},
+		{
+			name: "include extended metadata",
+			tmpl: &ServicesTemplate{
+				Names:                   []string{"api"},
+				RenderVar:               true,
+				IncludeExtendedMetadata: true,
+			},
+			exp: `
+services = {
+{{- with $srv := service "api" }}
+  {{- range $s := $srv}}
+  "{{ joinStrings "." .ID .Node .Namespace .NodeDatacenter }}" = {
+{{ HCLServiceExtended $s | indent 4 }}
+  },
+  {{- end}}
+{{- end}}
+}
 `,
 		},
 	}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"sort"
 
+	"github.com/hashicorp/consul-terraform-sync/templates/hcltmpl"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
 )
@@ -58,10 +59,11 @@ func newProvidersTFVars(w io.Writer, filename string, input *RootModuleInputData
 	body := hclFile.Body()
 	body.AppendNewline()
 
+	aliased := len(input.Task.ProviderAliases) > 0
 	lastIdx := len(input.Providers) - 1
 	for i, p := range input.Providers {
 		obj := p.ObjectVal()
-		body.SetAttributeValue(p.Name, *obj)
+		body.SetAttributeValue(providerVarName(p, aliased), *obj)
 		if i != lastIdx {
 			body.AppendNewline()
 		}
@@ -71,9 +73,35 @@ func newProvidersTFVars(w io.Writer, filename string, input *RootModuleInputData
 	return err
 }
 
-// newVariablesTFVars writes input variables for configured variables.
+// newVariablesTFVars writes input variables for configured variables. The
+// values of sensitive variables are written by newSecretsTFVars instead.
 func newVariablesTFVars(w io.Writer, filename string, input *RootModuleInputData) error {
-	err := writePreamble(w, input.Task, filename)
+	vars := make(hcltmpl.Variables)
+	for k, v := range input.Variables {
+		if !input.isSensitive(k) {
+			vars[k] = v
+		}
+	}
+	return writeTFVars(w, filename, input.Task, vars)
+}
+
+// newSecretsTFVars writes the input variables for the configured variables
+// that are sensitive.
+func newSecretsTFVars(w io.Writer, filename string, input *RootModuleInputData) error {
+	return writeTFVars(w, filename, input.Task, input.sensitiveVariables(input.Variables))
+}
+
+// newEnvironmentTFVars returns a function that writes the input variables
+// that override the configured variables for an environment of the task.
+func newEnvironmentTFVars(vars hcltmpl.Variables) tfFileFunc {
+	return func(w io.Writer, filename string, input *RootModuleInputData) error {
+		return writeTFVars(w, filename, input.Task, vars)
+	}
+}
+
+// writeTFVars writes the input variables to a tfvars file
+func writeTFVars(w io.Writer, filename string, task Task, vars hcltmpl.Variables) error {
+	err := writePreamble(w, task, filename)
 	if err != nil {
 		return err
 	}
@@ -84,14 +112,14 @@ func newVariablesTFVars(w io.Writer, filename string, input *RootModuleInputData
 
 	// Order the keys so that we are always guaranteed to generate the same file given
 	// the same variables
-	keys := make([]string, 0, len(input.Variables))
-	for k := range input.Variables {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
 
 	for _, k := range keys {
-		body.SetAttributeValue(k, input.Variables[k])
+		body.SetAttributeValue(k, vars[k])
 	}
 
 	body.AppendNewline()
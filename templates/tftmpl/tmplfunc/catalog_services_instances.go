@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tmplfunc
+
+import (
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/hashicorp/hcat"
+	"github.com/hashicorp/hcat/dep"
+)
+
+// CatalogServiceInstances is a registered Consul service with its tags and the
+// number of its registered instances, regardless of their health.
+type CatalogServiceInstances struct {
+	Name      string
+	Tags      dep.ServiceTags
+	Instances int
+}
+
+// catalogServicesInstancesFunc returns information on registered Consul
+// services along with the number of registered instances of each service. It
+// supports the same options as catalogServicesRegistration. The instances
+// are counted with the Catalog Service API for each of the matched services.
+//
+// Endpoint: /v1/catalog/services, /v1/catalog/service/:service
+// Template: {{ catalogServicesInstances  <filter options> ... }}
+func catalogServicesInstancesFunc(recall hcat.Recaller) interface{} {
+	return func(opts ...string) ([]*CatalogServiceInstances, error) {
+		result := []*CatalogServiceInstances{}
+
+		d, err := newCatalogServicesInstancesQuery(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if value, ok := recall(d); ok {
+			return value.([]*CatalogServiceInstances), nil
+		}
+
+		return result, nil
+	}
+}
+
+// newCatalogServicesInstancesQuery processes options in the same format as
+// newCatalogServicesRegistrationQuery and configures the query to count the
+// instances of the services.
+func newCatalogServicesInstancesQuery(opts []string) (*catalogServicesRegistrationQuery, error) {
+	query, err := newCatalogServicesRegistrationQuery(opts)
+	if err != nil {
+		return nil, err
+	}
+	query.instances = true
+	return query, nil
+}
+
+// countInstances looks up the number of registered instances of each service.
+// The lookups are not blocking queries, the blocking query on the list of
+// services returns on any change to the registered instances.
+func (d *catalogServicesRegistrationQuery) countInstances(clients dep.Clients,
+	opts *consulapi.QueryOptions, services []*dep.CatalogSnippet) (
+	[]*CatalogServiceInstances, error) {
+
+	countOpts := *opts
+	countOpts.WaitIndex = 0
+	countOpts.WaitTime = 0
+
+	result := make([]*CatalogServiceInstances, len(services))
+	for ix, s := range services {
+		entries, _, err := clients.Consul().Catalog().Service(s.Name, "", &countOpts)
+		if err != nil {
+			return nil, err
+		}
+		result[ix] = &CatalogServiceInstances{
+			Name:      s.Name,
+			Tags:      s.Tags,
+			Instances: len(entries),
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,60 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tmplfunc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul-terraform-sync/testutils"
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/hashicorp/consul/sdk/testutil"
+	"github.com/hashicorp/hcat/dep"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogServicesInstancesQuery_String(t *testing.T) {
+	t.Parallel()
+
+	d, err := newCatalogServicesInstancesQuery([]string{})
+	require.NoError(t, err)
+	assert.Equal(t, "catalog.services.instances", d.String())
+
+	d, err = newCatalogServicesInstancesQuery([]string{"regexp=api", "dc=dc1"})
+	require.NoError(t, err)
+	assert.Equal(t, "catalog.services.instances(dc=dc1&regexp=api)", d.String())
+
+	_, err = newCatalogServicesInstancesQuery([]string{"invalid"})
+	assert.Error(t, err)
+}
+
+func TestCatalogServicesInstancesQuery_Fetch(t *testing.T) {
+	t.Parallel()
+
+	srv := testutils.NewTestConsulServer(t, testutils.TestConsulServerConfig{})
+	defer srv.Stop()
+
+	service := testutil.TestService{ID: "api-1", Name: "api", Tags: []string{"tag1"}}
+	testutils.RegisterConsulService(t, srv, service, 8*time.Second)
+	service = testutil.TestService{ID: "api-2", Name: "api", Tags: []string{"tag1"}}
+	testutils.RegisterConsulService(t, srv, service, 8*time.Second)
+	service = testutil.TestService{ID: "web-1", Name: "web"}
+	testutils.RegisterConsulService(t, srv, service, 8*time.Second)
+
+	consulConfig := consulapi.DefaultConfig()
+	consulConfig.Address = srv.HTTPAddr
+	client, err := consulapi.NewClient(consulConfig)
+	require.NoError(t, err, "failed to make consul client")
+
+	d, err := newCatalogServicesInstancesQuery([]string{"regexp=api|web"})
+	require.NoError(t, err)
+
+	actual, _, err := d.Fetch(&testClient{consul: client})
+	require.NoError(t, err)
+	assert.Equal(t, []*CatalogServiceInstances{
+		{Name: "api", Tags: dep.ServiceTags([]string{"tag1"}), Instances: 2},
+		{Name: "web", Tags: dep.ServiceTags([]string{}), Instances: 1},
+	}, actual)
+}
@@ -22,6 +22,8 @@ var _ hcatQuery = (*catalogServicesRegistrationQuery)(nil)
 // parameters dc, ns, and node-meta. It also adds an additional layer of
 // custom functionality on the API response:
 //   - Adds regex filtering on service name option e.g. "regexp=api"
+//   - Adds filtering on service kind option e.g. "kind=typical", which
+//     requires Consul support for filtering the Catalog List Services API
 //
 // Endpoint: /v1/catalog/services
 // Template: {{ catalogServicesRegistration  <filter options> ... }}
@@ -51,8 +53,13 @@ type catalogServicesRegistrationQuery struct {
 	regexp   *regexp.Regexp // custom
 	dc       string
 	ns       string
+	kind     string
 	nodeMeta map[string]string
 	opts     hcat.QueryOptions
+
+	// instances configures the query to also count the registered instances
+	// of each service, see catalogServicesInstancesFunc
+	instances bool
 }
 
 // newCatalogServicesRegistrationQuery processes options in the format of
@@ -83,6 +90,8 @@ func newCatalogServicesRegistrationQuery(opts []string) (*catalogServicesRegistr
 			query.dc = value
 		case "ns", "namespace":
 			query.ns = value
+		case "kind":
+			query.kind = value
 		case "node-meta":
 			if query.nodeMeta == nil {
 				query.nodeMeta = make(map[string]string)
@@ -120,6 +129,9 @@ func (d *catalogServicesRegistrationQuery) Fetch(clients dep.Clients) (interface
 	if len(d.nodeMeta) != 0 {
 		opts.NodeMeta = d.nodeMeta
 	}
+	if d.kind != "" {
+		opts.Filter = serviceKindFilter(d.kind)
+	}
 
 	entries, qm, err := clients.Consul().Catalog().Services(opts)
 	if err != nil {
@@ -139,6 +151,14 @@ func (d *catalogServicesRegistrationQuery) Fetch(clients dep.Clients) (interface
 
 	sort.Stable(ByName(catalogServices))
 
+	var result interface{} = catalogServices
+	if d.instances {
+		result, err = d.countInstances(clients, opts, catalogServices)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, d.String())
+		}
+	}
+
 	rm := &dep.ResponseMetadata{
 		LastIndex:   qm.LastIndex,
 		LastContact: qm.LastContact,
@@ -163,7 +183,7 @@ func (d *catalogServicesRegistrationQuery) Fetch(clients dep.Clients) (interface
 	// so have not implemented this complexity at this time
 	time.Sleep(1 * time.Second)
 
-	return catalogServices, rm, nil
+	return result, rm, nil
 }
 
 // SetOptions satisfies the hcat.QueryOptionsSetter interface which enables
@@ -184,15 +204,21 @@ func (d *catalogServicesRegistrationQuery) ID() string {
 	if d.ns != "" {
 		opts = append(opts, fmt.Sprintf("ns=%s", d.ns))
 	}
+	if d.kind != "" {
+		opts = append(opts, fmt.Sprintf("kind=%s", d.kind))
+	}
 	for k, v := range d.nodeMeta {
 		opts = append(opts, fmt.Sprintf("node-meta=%s:%s", k, v))
 	}
+	name := "catalog.services.registration"
+	if d.instances {
+		name = "catalog.services.instances"
+	}
 	if len(opts) > 0 {
 		sort.Strings(opts)
-		return fmt.Sprintf("catalog.services.registration(%s)",
-			strings.Join(opts, "&"))
+		return fmt.Sprintf("%s(%s)", name, strings.Join(opts, "&"))
 	}
-	return "catalog.services.registration"
+	return name
 }
 
 // Stringer interface reuses ID
@@ -205,6 +231,15 @@ func (d *catalogServicesRegistrationQuery) Stop() {
 	close(d.stopCh)
 }
 
+// serviceKindFilter returns the filter expression for services of a kind.
+// Typical services are registered in Consul without a kind.
+func serviceKindFilter(kind string) string {
+	if kind == "typical" {
+		kind = ""
+	}
+	return fmt.Sprintf("ServiceKind == %q", kind)
+}
+
 // ByName is a sortable slice of CatalogSnippet structs.
 type ByName []*dep.CatalogSnippet
 
@@ -57,6 +57,14 @@ func TestNewCatalogServicesRegistrationQuery(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"kind",
+			[]string{"kind=connect-proxy"},
+			&catalogServicesRegistrationQuery{
+				kind: "connect-proxy",
+			},
+			false,
+		},
 		{
 			"node-meta",
 			[]string{"node-meta=k:v", "node-meta=foo:bar"},
@@ -136,6 +144,11 @@ func TestCatalogServicesRegistrationQuery_String(t *testing.T) {
 			[]string{"ns=namespace"},
 			"catalog.services.registration(ns=namespace)",
 		},
+		{
+			"kind",
+			[]string{"kind=typical"},
+			"catalog.services.registration(kind=typical)",
+		},
 		{
 			"node-meta",
 			[]string{"node-meta=k:v", "node-meta=foo:bar"},
@@ -159,6 +172,14 @@ func TestCatalogServicesRegistrationQuery_String(t *testing.T) {
 	}
 }
 
+func TestServiceKindFilter(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `ServiceKind == ""`, serviceKindFilter("typical"))
+	assert.Equal(t, `ServiceKind == "connect-proxy"`,
+		serviceKindFilter("connect-proxy"))
+}
+
 func TestCatalogServicesRegistrationQuery_Fetch(t *testing.T) {
 	t.Parallel()
 
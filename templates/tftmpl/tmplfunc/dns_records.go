@@ -0,0 +1,203 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tmplfunc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcat"
+	"github.com/hashicorp/hcat/dep"
+	"github.com/pkg/errors"
+)
+
+const (
+	dnsRecordTypeA   = "A"
+	dnsRecordTypeSRV = "SRV"
+
+	defaultDNSInterval = 30 * time.Second
+	dnsLookupTimeout   = 10 * time.Second
+)
+
+var _ dep.Dependency = (*dnsRecordsQuery)(nil)
+
+// DNSRecord is a resolved DNS record. For A records, only the Address is set.
+// For SRV records, the Address is the target host of the record.
+type DNSRecord struct {
+	Address  string
+	Port     uint16
+	Priority uint16
+	Weight   uint16
+}
+
+// dnsResolver is the subset of net.Resolver used to resolve DNS records.
+type dnsResolver interface {
+	LookupIP(ctx context.Context, network, host string) ([]net.IP, error)
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+// dnsRecordsFunc returns the resolved records for a DNS name. Unlike the other
+// template functions, it does not query Consul. It periodically resolves the
+// DNS name and only reports a change when the resolved record set changes.
+//
+// Template: {{ dnsRecords "name=<dns-name>" <options> ... }}
+func dnsRecordsFunc(recall hcat.Recaller) interface{} {
+	return func(opts ...string) ([]*DNSRecord, error) {
+		result := []*DNSRecord{}
+
+		d, err := newDNSRecordsQuery(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if value, ok := recall(d); ok {
+			return value.([]*DNSRecord), nil
+		}
+
+		return result, nil
+	}
+}
+
+// dnsRecordsQuery is the representation of a requested DNS lookup from inside
+// a template.
+type dnsRecordsQuery struct {
+	stopCh chan struct{}
+
+	name       string
+	recordType string
+	interval   time.Duration
+
+	resolver dnsResolver
+	fetched  bool
+}
+
+// newDNSRecordsQuery processes options in the format of "key=value"
+// e.g. "name=example.com"
+func newDNSRecordsQuery(opts []string) (*dnsRecordsQuery, error) {
+	query := dnsRecordsQuery{
+		stopCh:     make(chan struct{}, 1),
+		recordType: dnsRecordTypeA,
+		interval:   defaultDNSInterval,
+		resolver:   net.DefaultResolver,
+	}
+
+	for _, opt := range opts {
+		if strings.TrimSpace(opt) == "" {
+			continue
+		}
+
+		param, value, err := stringsSplit2(opt, "=")
+		if err != nil {
+			return nil, fmt.Errorf("dns.records: invalid query parameter "+
+				"format: %q", opt)
+		}
+		switch param {
+		case "name":
+			query.name = value
+		case "type":
+			t := strings.ToUpper(value)
+			if t != dnsRecordTypeA && t != dnsRecordTypeSRV {
+				return nil, fmt.Errorf("dns.records: unsupported record type %q", value)
+			}
+			query.recordType = t
+		case "interval":
+			i, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("dns.records: invalid interval %q: %s", value, err)
+			}
+			query.interval = i
+		default:
+			return nil, fmt.Errorf("dns.records: invalid query parameter: %q", opt)
+		}
+	}
+
+	if query.name == "" {
+		return nil, fmt.Errorf("dns.records: name is required")
+	}
+
+	return &query, nil
+}
+
+// Fetch resolves the DNS name and returns a sorted slice of DNSRecord objects.
+// DNS does not support blocking queries, so every Fetch after the first waits
+// for the configured interval before resolving the name again.
+func (d *dnsRecordsQuery) Fetch(clients dep.Clients) (interface{}, *dep.ResponseMetadata, error) {
+	if d.fetched {
+		select {
+		case <-d.stopCh:
+			return nil, nil, dep.ErrStopped
+		case <-time.After(d.interval):
+		}
+	}
+
+	select {
+	case <-d.stopCh:
+		return nil, nil, dep.ErrStopped
+	default:
+	}
+	d.fetched = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), dnsLookupTimeout)
+	defer cancel()
+
+	var records []*DNSRecord
+	switch d.recordType {
+	case dnsRecordTypeSRV:
+		_, srvs, err := d.resolver.LookupSRV(ctx, "", "", d.name)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, d.String())
+		}
+		for _, srv := range srvs {
+			records = append(records, &DNSRecord{
+				Address:  strings.TrimSuffix(srv.Target, "."),
+				Port:     srv.Port,
+				Priority: srv.Priority,
+				Weight:   srv.Weight,
+			})
+		}
+	default:
+		ips, err := d.resolver.LookupIP(ctx, "ip4", d.name)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, d.String())
+		}
+		for _, ip := range ips {
+			records = append(records, &DNSRecord{Address: ip.String()})
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Address != records[j].Address {
+			return records[i].Address < records[j].Address
+		}
+		return records[i].Port < records[j].Port
+	})
+
+	// DNS has no index to block on. Similar to other non-Consul dependencies,
+	// use the current time as the index so each lookup is considered new data
+	// and the watcher compares the records to detect changes.
+	rm := &dep.ResponseMetadata{
+		LastIndex: uint64(time.Now().UnixNano()),
+	}
+
+	return records, rm, nil
+}
+
+// ID returns the human-friendly version of this query.
+func (d *dnsRecordsQuery) ID() string {
+	return fmt.Sprintf("dns.records(%s|type=%s)", d.name, d.recordType)
+}
+
+// Stringer interface reuses ID
+func (d *dnsRecordsQuery) String() string {
+	return d.ID()
+}
+
+// Stop halts the query's fetch function.
+func (d *dnsRecordsQuery) Stop() {
+	close(d.stopCh)
+}
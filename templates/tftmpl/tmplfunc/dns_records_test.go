@@ -0,0 +1,168 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tmplfunc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDNSResolver struct {
+	ips  []net.IP
+	srvs []*net.SRV
+	err  error
+}
+
+func (r *fakeDNSResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	return r.ips, r.err
+}
+
+func (r *fakeDNSResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	return "", r.srvs, r.err
+}
+
+func TestNewDNSRecordsQuery(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		opts []string
+		exp  *dnsRecordsQuery
+		err  bool
+	}{
+		{
+			"name only",
+			[]string{"name=example.com"},
+			&dnsRecordsQuery{
+				name:       "example.com",
+				recordType: dnsRecordTypeA,
+				interval:   defaultDNSInterval,
+			},
+			false,
+		},
+		{
+			"all options",
+			[]string{"name=_http._tcp.example.com", "type=srv", "interval=5s"},
+			&dnsRecordsQuery{
+				name:       "_http._tcp.example.com",
+				recordType: dnsRecordTypeSRV,
+				interval:   5 * time.Second,
+			},
+			false,
+		},
+		{
+			"missing name",
+			[]string{"type=A"},
+			nil,
+			true,
+		},
+		{
+			"unsupported type",
+			[]string{"name=example.com", "type=MX"},
+			nil,
+			true,
+		},
+		{
+			"invalid interval",
+			[]string{"name=example.com", "interval=soon"},
+			nil,
+			true,
+		},
+		{
+			"invalid query",
+			[]string{"name=example.com", "invalid=true"},
+			nil,
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			act, err := newDNSRecordsQuery(tc.opts)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+
+			if act != nil {
+				act.stopCh = nil
+				act.resolver = nil
+			}
+
+			assert.NoError(t, err, err)
+			assert.Equal(t, tc.exp, act)
+		})
+	}
+}
+
+func TestDNSRecordsQuery_Fetch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("a records", func(t *testing.T) {
+		d, err := newDNSRecordsQuery([]string{"name=example.com"})
+		require.NoError(t, err)
+		d.resolver = &fakeDNSResolver{
+			ips: []net.IP{net.ParseIP("10.0.0.2"), net.ParseIP("10.0.0.1")},
+		}
+
+		data, rm, err := d.Fetch(nil)
+		require.NoError(t, err)
+		assert.NotNil(t, rm)
+		assert.Equal(t, []*DNSRecord{
+			{Address: "10.0.0.1"},
+			{Address: "10.0.0.2"},
+		}, data)
+	})
+
+	t.Run("srv records", func(t *testing.T) {
+		d, err := newDNSRecordsQuery([]string{"name=_http._tcp.example.com", "type=SRV"})
+		require.NoError(t, err)
+		d.resolver = &fakeDNSResolver{
+			srvs: []*net.SRV{
+				{Target: "web-2.example.com.", Port: 8080, Priority: 10, Weight: 5},
+				{Target: "web-1.example.com.", Port: 8080, Priority: 10, Weight: 5},
+			},
+		}
+
+		data, _, err := d.Fetch(nil)
+		require.NoError(t, err)
+		assert.Equal(t, []*DNSRecord{
+			{Address: "web-1.example.com", Port: 8080, Priority: 10, Weight: 5},
+			{Address: "web-2.example.com", Port: 8080, Priority: 10, Weight: 5},
+		}, data)
+	})
+
+	t.Run("lookup error", func(t *testing.T) {
+		d, err := newDNSRecordsQuery([]string{"name=example.com"})
+		require.NoError(t, err)
+		d.resolver = &fakeDNSResolver{err: errors.New("no such host")}
+
+		_, _, err = d.Fetch(nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		d, err := newDNSRecordsQuery([]string{"name=example.com"})
+		require.NoError(t, err)
+		d.resolver = &fakeDNSResolver{}
+		d.Stop()
+
+		_, _, err = d.Fetch(nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestDNSRecordsQuery_String(t *testing.T) {
+	t.Parallel()
+
+	d, err := newDNSRecordsQuery([]string{"name=example.com", "type=SRV"})
+	require.NoError(t, err)
+	assert.Equal(t, "dns.records(example.com|type=SRV)", d.String())
+}
@@ -4,35 +4,75 @@
 package tmplfunc
 
 import (
-	"strings"
+	"bytes"
+	"sync"
 
 	"github.com/hashicorp/hcat/dep"
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
 )
 
+// encodeBufferPool holds the buffers to encode service instances into HCL.
+// Services are encoded for each instance on every render, so the buffers are
+// reused rather than allocated for each instance.
+var encodeBufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
 // hclServiceFunc is a wrapper of the template function to marshal Consul
 // service information into HCL. The function accepts a map representing
 // metadata for services in scope of a task.
 func hclServiceFunc(meta *ServicesMeta) func(sDep *dep.HealthService) string {
 	return func(sDep *dep.HealthService) string {
-		if sDep == nil {
-			return ""
-		}
-
-		// Find any user-defined metadata for this service and append to variable
-		var serviceMeta map[string]string
-		if meta != nil {
-			serviceMeta = meta.Get(sDep.Name)
-		}
-
-		// Convert the hcat type to an HCL marshal-able object
-		s := newHealthService(sDep, serviceMeta)
-
-		f := hclwrite.NewEmptyFile()
-		gohcl.EncodeIntoBody(s, f.Body())
-		return strings.TrimSpace(string(f.Bytes()))
+		return encodeHealthService(meta, sDep, false)
+	}
+}
+
+// hclServiceExtendedFunc is a wrapper of the template function to marshal
+// Consul service information into HCL, including extended metadata for the
+// service instance such as weights. The function accepts a map representing
+// metadata for services in scope of a task.
+func hclServiceExtendedFunc(meta *ServicesMeta) func(sDep *dep.HealthService) string {
+	return func(sDep *dep.HealthService) string {
+		return encodeHealthService(meta, sDep, true)
+	}
+}
+
+func encodeHealthService(meta *ServicesMeta, sDep *dep.HealthService, extended bool) string {
+	if sDep == nil {
+		return ""
+	}
+
+	// Find any user-defined metadata for this service and append to variable
+	var serviceMeta map[string]string
+	if meta != nil {
+		serviceMeta = meta.Get(sDep.Name)
+	}
+
+	// Convert the hcat type to an HCL marshal-able object
+	s := newHealthService(sDep, serviceMeta)
+
+	f := hclwrite.NewEmptyFile()
+	gohcl.EncodeIntoBody(s, f.Body())
+
+	if extended {
+		f.Body().SetAttributeValue("weights", cty.ObjectVal(map[string]cty.Value{
+			"passing": cty.NumberIntVal(int64(sDep.Weights.Passing)),
+			"warning": cty.NumberIntVal(int64(sDep.Weights.Warning)),
+		}))
+	}
+
+	buf := encodeBufferPool.Get().(*bytes.Buffer)
+	defer encodeBufferPool.Put(buf)
+	buf.Reset()
+
+	if _, err := f.WriteTo(buf); err != nil {
+		return ""
 	}
+	return string(bytes.TrimSpace(buf.Bytes()))
 }
 
 type healthService struct {
@@ -6,6 +6,7 @@ package tmplfunc
 import (
 	"testing"
 
+	consulapi "github.com/hashicorp/consul/api"
 	"github.com/hashicorp/hcat/dep"
 	"github.com/stretchr/testify/assert"
 )
@@ -181,3 +182,56 @@ cts_user_defined_meta = {
 		})
 	}
 }
+
+func TestHCLServiceExtendedFunc(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  *dep.HealthService
+		expected string
+	}{
+		{
+			"nil",
+			nil,
+			"",
+		}, {
+			"weights",
+			&dep.HealthService{
+				ID:      "api",
+				Name:    "api",
+				Address: "1.2.3.4",
+				Port:    8080,
+				Weights: consulapi.AgentWeights{
+					Passing: 10,
+					Warning: 1,
+				},
+			},
+			`id                    = "api"
+name                  = "api"
+kind                  = ""
+address               = "1.2.3.4"
+port                  = 8080
+meta                  = {}
+tags                  = []
+namespace             = ""
+status                = ""
+node                  = ""
+node_id               = ""
+node_address          = ""
+node_datacenter       = ""
+node_tagged_addresses = {}
+node_meta             = {}
+cts_user_defined_meta = {}
+weights = {
+  passing = 10
+  warning = 1
+}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := hclServiceExtendedFunc(nil)(tc.content)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
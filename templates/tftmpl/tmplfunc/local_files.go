@@ -0,0 +1,256 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tmplfunc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcat"
+	"github.com/hashicorp/hcat/dep"
+	"github.com/pkg/errors"
+)
+
+const defaultLocalFilesInterval = 10 * time.Second
+
+var _ dep.Dependency = (*localFilesQuery)(nil)
+
+// File is a local file watched by CTS. Content is only set when the content
+// of the file is requested.
+type File struct {
+	Path    string
+	SHA256  string
+	Content string
+}
+
+// localFilesFunc returns the local files for the requested paths. Unlike the
+// other template functions, it does not query Consul. It periodically hashes
+// the files and only reports a change when a file is added, removed, or its
+// content changes.
+//
+// Template: {{ localFiles "path=<path>" <options> ... }}
+func localFilesFunc(recall hcat.Recaller) interface{} {
+	return func(opts ...string) ([]*File, error) {
+		result := []*File{}
+
+		d, err := newLocalFilesQuery(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if value, ok := recall(d); ok {
+			return value.([]*File), nil
+		}
+
+		return result, nil
+	}
+}
+
+// localFilesQuery is the representation of a requested set of local files
+// from inside a template.
+type localFilesQuery struct {
+	stopCh chan struct{}
+
+	paths          []string
+	interval       time.Duration
+	includeContent bool
+
+	fetched bool
+	last    []*File
+}
+
+// newLocalFilesQuery processes options in the format of "key=value"
+// e.g. "path=/etc/cts/allowlist.txt". The path option can be repeated.
+func newLocalFilesQuery(opts []string) (*localFilesQuery, error) {
+	query := localFilesQuery{
+		stopCh:         make(chan struct{}, 1),
+		interval:       defaultLocalFilesInterval,
+		includeContent: true,
+	}
+
+	for _, opt := range opts {
+		if strings.TrimSpace(opt) == "" {
+			continue
+		}
+
+		param, value, err := stringsSplit2(opt, "=")
+		if err != nil {
+			return nil, fmt.Errorf("local.files: invalid query parameter "+
+				"format: %q", opt)
+		}
+		switch param {
+		case "path":
+			query.paths = append(query.paths, value)
+		case "interval":
+			i, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("local.files: invalid interval %q: %s", value, err)
+			}
+			query.interval = i
+		case "content":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("local.files: invalid content %q: %s", value, err)
+			}
+			query.includeContent = b
+		default:
+			return nil, fmt.Errorf("local.files: invalid query parameter: %q", opt)
+		}
+	}
+
+	if len(query.paths) == 0 {
+		return nil, fmt.Errorf("local.files: path is required")
+	}
+	sort.Strings(query.paths)
+
+	return &query, nil
+}
+
+// Fetch reads the local files and returns a slice of File objects sorted by
+// path. Local files do not support blocking queries, so every Fetch after the
+// first checks the files once per interval and only returns when they have
+// changed.
+func (d *localFilesQuery) Fetch(clients dep.Clients) (interface{}, *dep.ResponseMetadata, error) {
+	for {
+		if d.fetched {
+			select {
+			case <-d.stopCh:
+				return nil, nil, dep.ErrStopped
+			case <-time.After(d.interval):
+			}
+		}
+
+		select {
+		case <-d.stopCh:
+			return nil, nil, dep.ErrStopped
+		default:
+		}
+
+		files, err := d.readFiles()
+		if err != nil {
+			return nil, nil, errors.Wrap(err, d.String())
+		}
+
+		if d.fetched && sameFiles(d.last, files) {
+			continue
+		}
+		d.fetched = true
+		d.last = files
+
+		// Local files have no index to block on. Similar to other non-Consul
+		// dependencies, use the current time as the index so the files are
+		// considered new data.
+		rm := &dep.ResponseMetadata{
+			LastIndex: uint64(time.Now().UnixNano()),
+		}
+
+		return files, rm, nil
+	}
+}
+
+// readFiles reads and hashes the files for the configured paths. Directories
+// include the regular files directly within them. Paths that do not exist are
+// skipped so that a file being created is detected as a change.
+func (d *localFilesQuery) readFiles() ([]*File, error) {
+	seen := make(map[string]bool)
+	var files []*File
+
+	add := func(path string) error {
+		if seen[path] {
+			return nil
+		}
+		seen[path] = true
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+
+		sum := sha256.Sum256(b)
+		f := &File{
+			Path:   path,
+			SHA256: hex.EncodeToString(sum[:]),
+		}
+		if d.includeContent {
+			f.Content = string(b)
+		}
+		files = append(files, f)
+		return nil
+	}
+
+	for _, p := range d.paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			if err := add(p); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		entries, err := os.ReadDir(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if !e.Type().IsRegular() {
+				continue
+			}
+			if err := add(filepath.Join(p, e.Name())); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	return files, nil
+}
+
+// sameFiles returns true if both sets of files have the same paths and hashes
+func sameFiles(a, b []*File) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Path != b[i].Path || a[i].SHA256 != b[i].SHA256 {
+			return false
+		}
+	}
+	return true
+}
+
+// ID returns the human-friendly version of this query.
+func (d *localFilesQuery) ID() string {
+	return fmt.Sprintf("local.files(%s|interval=%s|content=%t)",
+		strings.Join(d.paths, ","), d.interval, d.includeContent)
+}
+
+// Stringer interface reuses ID
+func (d *localFilesQuery) String() string {
+	return d.ID()
+}
+
+// Stop halts the query's fetch function.
+func (d *localFilesQuery) Stop() {
+	close(d.stopCh)
+}
@@ -0,0 +1,181 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tmplfunc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLocalFilesQuery(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		opts []string
+		exp  *localFilesQuery
+		err  bool
+	}{
+		{
+			"path only",
+			[]string{"path=/etc/cts/rules"},
+			&localFilesQuery{
+				paths:          []string{"/etc/cts/rules"},
+				interval:       defaultLocalFilesInterval,
+				includeContent: true,
+			},
+			false,
+		},
+		{
+			"all options",
+			[]string{"path=/b", "path=/a", "interval=5s", "content=false"},
+			&localFilesQuery{
+				paths:          []string{"/a", "/b"},
+				interval:       5 * time.Second,
+				includeContent: false,
+			},
+			false,
+		},
+		{
+			"missing path",
+			[]string{"interval=5s"},
+			nil,
+			true,
+		},
+		{
+			"invalid interval",
+			[]string{"path=/a", "interval=soon"},
+			nil,
+			true,
+		},
+		{
+			"invalid content",
+			[]string{"path=/a", "content=maybe"},
+			nil,
+			true,
+		},
+		{
+			"invalid query",
+			[]string{"path=/a", "invalid=true"},
+			nil,
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			act, err := newLocalFilesQuery(tc.opts)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+
+			if act != nil {
+				act.stopCh = nil
+			}
+
+			assert.NoError(t, err, err)
+			assert.Equal(t, tc.exp, act)
+		})
+	}
+}
+
+func TestLocalFilesQuery_Fetch(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	rulesDir := filepath.Join(dir, "rules")
+	require.NoError(t, os.Mkdir(rulesDir, 0755))
+	require.NoError(t, os.Mkdir(filepath.Join(rulesDir, "nested"), 0755))
+
+	allowlist := filepath.Join(dir, "allowlist.txt")
+	require.NoError(t, os.WriteFile(allowlist, []byte("10.0.0.1"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(rulesDir, "b.json"), []byte("{}"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(rulesDir, "a.json"), []byte("[]"), 0644))
+
+	t.Run("files and directories", func(t *testing.T) {
+		d, err := newLocalFilesQuery([]string{
+			"path=" + rulesDir,
+			"path=" + allowlist,
+			"path=" + filepath.Join(dir, "missing.txt"),
+		})
+		require.NoError(t, err)
+
+		data, rm, err := d.Fetch(nil)
+		require.NoError(t, err)
+		assert.NotNil(t, rm)
+		assert.Equal(t, []*File{
+			{
+				Path:    allowlist,
+				SHA256:  "f5047344122f0dee9974ba6761e61c6b8649e1f3968d13a635ebbf7be53a3a0d",
+				Content: "10.0.0.1",
+			},
+			{
+				Path:    filepath.Join(rulesDir, "a.json"),
+				SHA256:  "4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945",
+				Content: "[]",
+			},
+			{
+				Path:    filepath.Join(rulesDir, "b.json"),
+				SHA256:  "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a",
+				Content: "{}",
+			},
+		}, data)
+	})
+
+	t.Run("without content", func(t *testing.T) {
+		d, err := newLocalFilesQuery([]string{"path=" + allowlist, "content=false"})
+		require.NoError(t, err)
+
+		data, _, err := d.Fetch(nil)
+		require.NoError(t, err)
+		files := data.([]*File)
+		require.Len(t, files, 1)
+		assert.Empty(t, files[0].Content)
+		assert.NotEmpty(t, files[0].SHA256)
+	})
+
+	t.Run("returns on change", func(t *testing.T) {
+		path := filepath.Join(dir, "changing.txt")
+		require.NoError(t, os.WriteFile(path, []byte("v1"), 0644))
+
+		d, err := newLocalFilesQuery([]string{"path=" + path, "interval=10ms"})
+		require.NoError(t, err)
+
+		data, _, err := d.Fetch(nil)
+		require.NoError(t, err)
+		assert.Equal(t, "v1", data.([]*File)[0].Content)
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			os.WriteFile(path, []byte("v2"), 0644)
+		}()
+
+		data, _, err = d.Fetch(nil)
+		require.NoError(t, err)
+		assert.Equal(t, "v2", data.([]*File)[0].Content)
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		d, err := newLocalFilesQuery([]string{"path=" + allowlist})
+		require.NoError(t, err)
+		d.Stop()
+
+		_, _, err = d.Fetch(nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestLocalFilesQuery_String(t *testing.T) {
+	t.Parallel()
+
+	d, err := newLocalFilesQuery([]string{"path=/b", "path=/a"})
+	require.NoError(t, err)
+	assert.Equal(t, "local.files(/a,/b|interval=10s|content=true)", d.String())
+}
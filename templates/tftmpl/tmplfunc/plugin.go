@@ -0,0 +1,265 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tmplfunc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcat"
+	"github.com/hashicorp/hcat/dep"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultPluginInterval = 30 * time.Second
+
+	// pluginTimeout is the time limit for a single execution of a plugin
+	pluginTimeout = time.Minute
+
+	// pluginWaitDelay is the time to wait for the output of the plugin to
+	// close after the plugin is killed on timeout
+	pluginWaitDelay = time.Second
+
+	// PluginProtocolVersionEnv is the environment variable with the version
+	// of the condition plugin protocol that is set for the plugin. Plugins can
+	// use it to detect incompatible versions of CTS.
+	PluginProtocolVersionEnv = "CTS_PLUGIN_PROTOCOL_VERSION"
+
+	// PluginProtocolVersion is the version of the condition plugin protocol.
+	// A plugin is executed once per interval and writes a single JSON value to
+	// stdout. A non-zero exit status is treated as an error.
+	PluginProtocolVersion = "0"
+)
+
+var _ dep.Dependency = (*pluginQuery)(nil)
+
+// PluginOutput is the output of a condition plugin.
+type PluginOutput struct {
+	// Data is the JSON value written by the plugin, normalized so that
+	// equivalent values are equal and escaped so that it can be rendered as
+	// an HCL expression.
+	Data string
+}
+
+// pluginRunner executes the plugin and returns what it wrote to stdout.
+type pluginRunner func(ctx context.Context, command string, args []string) ([]byte, error)
+
+// pluginFunc returns the output of a condition plugin. Unlike the other
+// template functions, it does not query Consul. It periodically executes the
+// plugin and only reports a change when the output of the plugin changes.
+//
+// Template: {{ plugin "command=<path>" <options> ... }}
+func pluginFunc(recall hcat.Recaller) interface{} {
+	return func(opts ...string) (*PluginOutput, error) {
+		d, err := newPluginQuery(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if value, ok := recall(d); ok {
+			return value.(*PluginOutput), nil
+		}
+
+		return nil, nil
+	}
+}
+
+// pluginQuery is the representation of a requested condition plugin from
+// inside a template.
+type pluginQuery struct {
+	stopCh chan struct{}
+
+	command  string
+	args     []string
+	interval time.Duration
+
+	run     pluginRunner
+	fetched bool
+	last    *PluginOutput
+}
+
+// newPluginQuery processes options in the format of "key=value"
+// e.g. "command=/opt/cts/plugins/s3-bucket". The arg option can be repeated
+// and the arguments are passed to the plugin in order.
+func newPluginQuery(opts []string) (*pluginQuery, error) {
+	query := pluginQuery{
+		stopCh:   make(chan struct{}, 1),
+		interval: defaultPluginInterval,
+		run:      execPlugin,
+	}
+
+	for _, opt := range opts {
+		if strings.TrimSpace(opt) == "" {
+			continue
+		}
+
+		param, value, err := stringsSplit2(opt, "=")
+		if err != nil {
+			return nil, fmt.Errorf("plugin: invalid query parameter "+
+				"format: %q", opt)
+		}
+		switch param {
+		case "command":
+			query.command = value
+		case "arg":
+			query.args = append(query.args, value)
+		case "interval":
+			i, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("plugin: invalid interval %q: %s", value, err)
+			}
+			query.interval = i
+		default:
+			return nil, fmt.Errorf("plugin: invalid query parameter: %q", opt)
+		}
+	}
+
+	if query.command == "" {
+		return nil, fmt.Errorf("plugin: command is required")
+	}
+
+	return &query, nil
+}
+
+// Fetch executes the plugin and returns its output. Plugins do not support
+// blocking queries, so every Fetch after the first executes the plugin once
+// per interval and only returns when the output has changed.
+func (d *pluginQuery) Fetch(clients dep.Clients) (interface{}, *dep.ResponseMetadata, error) {
+	for {
+		if d.fetched {
+			select {
+			case <-d.stopCh:
+				return nil, nil, dep.ErrStopped
+			case <-time.After(d.interval):
+			}
+		}
+
+		select {
+		case <-d.stopCh:
+			return nil, nil, dep.ErrStopped
+		default:
+		}
+
+		out, err := d.execute()
+		if err != nil {
+			return nil, nil, errors.Wrap(err, d.String())
+		}
+
+		if d.fetched && d.last.Data == out.Data {
+			continue
+		}
+		d.fetched = true
+		d.last = out
+
+		// Plugins have no index to block on. Similar to other non-Consul
+		// dependencies, use the current time as the index so the output is
+		// considered new data.
+		rm := &dep.ResponseMetadata{
+			LastIndex: uint64(time.Now().UnixNano()),
+		}
+
+		return out, rm, nil
+	}
+}
+
+// execute runs the plugin and normalizes its output. The plugin is stopped
+// if the query is stopped while it is running.
+func (d *pluginQuery) execute() (*PluginOutput, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pluginTimeout)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-d.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	b, err := d.run(ctx, d.command, d.args)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := normalizePluginOutput(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PluginOutput{Data: data}, nil
+}
+
+// execPlugin executes the plugin with the protocol version set in its
+// environment and returns what it wrote to stdout.
+func execPlugin(ctx context.Context, command string, args []string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s",
+		PluginProtocolVersionEnv, PluginProtocolVersion))
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = pluginWaitDelay
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("plugin %q timed out after %s", command, pluginTimeout)
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("plugin %q failed: %s: %s", command, err, msg)
+		}
+		return nil, fmt.Errorf("plugin %q failed: %s", command, err)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// normalizePluginOutput decodes the single JSON value written by a plugin and
+// encodes it again so that the order of object keys and whitespace do not
+// cause changes. JSON is a valid HCL expression except for template
+// sequences in strings, which are escaped.
+func normalizePluginOutput(b []byte) (string, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return "", fmt.Errorf("plugin output is not valid JSON: %s", err)
+	}
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		return "", fmt.Errorf("plugin output must be a single JSON value")
+	}
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	r := strings.NewReplacer("${", "$${", "%{", "%%{")
+	return r.Replace(string(out)), nil
+}
+
+// ID returns the human-friendly version of this query.
+func (d *pluginQuery) ID() string {
+	return fmt.Sprintf("plugin(%s|args=%s|interval=%s)", d.command,
+		strings.Join(d.args, ","), d.interval)
+}
+
+// Stringer interface reuses ID
+func (d *pluginQuery) String() string {
+	return d.ID()
+}
+
+// Stop halts the query's fetch function.
+func (d *pluginQuery) Stop() {
+	close(d.stopCh)
+}
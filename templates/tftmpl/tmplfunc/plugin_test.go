@@ -0,0 +1,204 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tmplfunc
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPluginQuery(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		opts []string
+		exp  *pluginQuery
+		err  bool
+	}{
+		{
+			"command only",
+			[]string{"command=/opt/cts/plugins/s3-bucket"},
+			&pluginQuery{
+				command:  "/opt/cts/plugins/s3-bucket",
+				interval: defaultPluginInterval,
+			},
+			false,
+		},
+		{
+			"all options",
+			[]string{"command=/opt/cts/plugins/s3-bucket", "arg=-bucket",
+				"arg=releases", "interval=5s"},
+			&pluginQuery{
+				command:  "/opt/cts/plugins/s3-bucket",
+				args:     []string{"-bucket", "releases"},
+				interval: 5 * time.Second,
+			},
+			false,
+		},
+		{
+			"missing command",
+			[]string{"arg=-bucket"},
+			nil,
+			true,
+		},
+		{
+			"invalid interval",
+			[]string{"command=/a", "interval=soon"},
+			nil,
+			true,
+		},
+		{
+			"invalid query",
+			[]string{"command=/a", "invalid=true"},
+			nil,
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			act, err := newPluginQuery(tc.opts)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+
+			if act != nil {
+				act.stopCh = nil
+				act.run = nil
+			}
+
+			assert.NoError(t, err, err)
+			assert.Equal(t, tc.exp, act)
+		})
+	}
+}
+
+func TestPluginQuery_Fetch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("change", func(t *testing.T) {
+		d, err := newPluginQuery([]string{"command=/a", "interval=10ms"})
+		require.NoError(t, err)
+
+		outputs := []string{`{"b": 1, "a": "x"}`, `{"a":"x","b":1}`, `{"a":"y"}`}
+		calls := 0
+		d.run = func(ctx context.Context, command string, args []string) ([]byte, error) {
+			out := outputs[calls]
+			calls++
+			return []byte(out), nil
+		}
+
+		data, rm, err := d.Fetch(nil)
+		require.NoError(t, err)
+		assert.NotNil(t, rm)
+		assert.Equal(t, &PluginOutput{Data: `{"a":"x","b":1}`}, data)
+
+		// the second output is equivalent and is not reported as a change
+		data, _, err = d.Fetch(nil)
+		require.NoError(t, err)
+		assert.Equal(t, &PluginOutput{Data: `{"a":"y"}`}, data)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		d, err := newPluginQuery([]string{"command=/a"})
+		require.NoError(t, err)
+		d.run = func(ctx context.Context, command string, args []string) ([]byte, error) {
+			return nil, errors.New("plugin failed")
+		}
+
+		_, _, err = d.Fetch(nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		d, err := newPluginQuery([]string{"command=/a"})
+		require.NoError(t, err)
+		d.fetched = true
+		d.Stop()
+
+		_, _, err = d.Fetch(nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestNormalizePluginOutput(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		out  string
+		exp  string
+		err  bool
+	}{
+		{
+			"object",
+			`{ "b": [1, 2.50], "a": {"c": null} }`,
+			`{"a":{"c":null},"b":[1,2.50]}`,
+			false,
+		},
+		{
+			"string with template sequences",
+			`"${var.a} %{ if true }"`,
+			`"$${var.a} %%{ if true }"`,
+			false,
+		},
+		{
+			"invalid json",
+			`{"a":`,
+			"",
+			true,
+		},
+		{
+			"multiple values",
+			`{"a":1} {"a":2}`,
+			"",
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			act, err := normalizePluginOutput([]byte(tc.out))
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.exp, act)
+		})
+	}
+}
+
+func TestExecPlugin(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	plugin := filepath.Join(dir, "plugin.sh")
+	script := `#!/bin/sh
+if [ "$1" = "fail" ]; then
+  echo "bucket not found" >&2
+  exit 1
+fi
+echo "{\"protocol\": \"$CTS_PLUGIN_PROTOCOL_VERSION\"}"
+`
+	require.NoError(t, os.WriteFile(plugin, []byte(script), 0700))
+
+	out, err := execPlugin(context.Background(), plugin, nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"protocol": "0"}`, string(out))
+
+	_, err = execPlugin(context.Background(), plugin, []string{"fail"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bucket not found")
+}
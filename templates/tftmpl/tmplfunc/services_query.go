@@ -0,0 +1,220 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tmplfunc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/hashicorp/hcat"
+	"github.com/hashicorp/hcat/dep"
+	"github.com/pkg/errors"
+)
+
+// defaultServicesQueryInterval is the interval between executions of a
+// prepared query. Prepared queries do not support blocking queries.
+const defaultServicesQueryInterval = 30 * time.Second
+
+var _ dep.Dependency = (*servicesQuery)(nil)
+
+// preparedQueryExecutor is the subset of the Consul prepared query API used to
+// execute a prepared query.
+type preparedQueryExecutor interface {
+	Execute(queryIDOrName string, q *consulapi.QueryOptions) (
+		*consulapi.PreparedQueryExecuteResponse, *consulapi.QueryMeta, error)
+}
+
+// servicesQueryFunc returns information on the healthy service instances
+// returned by executing a Consul prepared query. The results reflect the
+// failover resolution of the query, e.g. instances from a failover datacenter
+// or sameness group member when no local instances are healthy.
+//
+// Endpoint:
+//
+//	/v1/query/:query/execute
+//
+// Template: {{ servicesQuery "query=<name-or-id>" <options> ... }}
+func servicesQueryFunc(recall hcat.Recaller) interface{} {
+	return func(opts ...string) ([]*dep.HealthService, error) {
+		result := []*dep.HealthService{}
+
+		d, err := newServicesQuery(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if value, ok := recall(d); ok {
+			return value.([]*dep.HealthService), nil
+		}
+
+		return result, nil
+	}
+}
+
+// servicesQuery is the representation of a requested prepared query execution
+// from inside a template.
+type servicesQuery struct {
+	isConsul
+	stopCh chan struct{}
+
+	query    string
+	dc       string
+	near     string
+	interval time.Duration
+
+	executor preparedQueryExecutor // defaults to the Consul client when nil
+	fetched  bool
+}
+
+// newServicesQuery processes options in the format of "key=value"
+// e.g. "query=nearest-api"
+func newServicesQuery(opts []string) (*servicesQuery, error) {
+	query := servicesQuery{
+		stopCh:   make(chan struct{}, 1),
+		interval: defaultServicesQueryInterval,
+	}
+
+	for _, opt := range opts {
+		if strings.TrimSpace(opt) == "" {
+			continue
+		}
+
+		param, value, err := stringsSplit2(opt, "=")
+		if err != nil {
+			return nil, fmt.Errorf("service.query: invalid query parameter "+
+				"format: %q", opt)
+		}
+		switch param {
+		case "query":
+			query.query = value
+		case "dc", "datacenter":
+			query.dc = value
+		case "near":
+			query.near = value
+		case "interval":
+			i, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("service.query: invalid interval %q: %s", value, err)
+			}
+			query.interval = i
+		default:
+			return nil, fmt.Errorf("service.query: invalid query parameter: %q", opt)
+		}
+	}
+
+	if query.query == "" {
+		return nil, fmt.Errorf("service.query: query is required")
+	}
+
+	return &query, nil
+}
+
+// Fetch executes the prepared query and returns a slice of HealthService
+// objects for the service instances returned by the query. Prepared queries
+// do not support blocking queries, so every Fetch after the first waits for
+// the interval before executing the query again.
+func (d *servicesQuery) Fetch(clients dep.Clients) (interface{}, *dep.ResponseMetadata, error) {
+	if d.fetched {
+		select {
+		case <-d.stopCh:
+			return nil, nil, dep.ErrStopped
+		case <-time.After(d.interval):
+		}
+	}
+
+	select {
+	case <-d.stopCh:
+		return nil, nil, dep.ErrStopped
+	default:
+	}
+	d.fetched = true
+
+	executor := d.executor
+	if executor == nil {
+		executor = clients.Consul().PreparedQuery()
+	}
+
+	resp, qm, err := executor.Execute(d.query, &consulapi.QueryOptions{
+		Datacenter: d.dc,
+		Near:       d.near,
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, d.String())
+	}
+
+	services := make([]*dep.HealthService, 0, len(resp.Nodes))
+	for _, entry := range resp.Nodes {
+		services = append(services, healthServiceFromEntry(entry))
+	}
+	sort.Stable(ByNodeThenID(services))
+
+	// The index of a prepared query execution does not change with the
+	// results. Use the current time as the index so each execution is
+	// considered new data and the watcher compares the results to detect
+	// changes.
+	rm := &dep.ResponseMetadata{
+		LastIndex: uint64(time.Now().UnixNano()),
+	}
+	if qm != nil {
+		rm.LastContact = qm.LastContact
+	}
+
+	return services, rm, nil
+}
+
+// ID returns the human-friendly version of this query.
+func (d *servicesQuery) ID() string {
+	opts := []string{fmt.Sprintf("query=%s", d.query)}
+	if d.dc != "" {
+		opts = append(opts, fmt.Sprintf("dc=%s", d.dc))
+	}
+	if d.near != "" {
+		opts = append(opts, fmt.Sprintf("near=%s", d.near))
+	}
+
+	return fmt.Sprintf("service.query(%s)", strings.Join(opts, "&"))
+}
+
+// Stringer interface reuses ID
+func (d *servicesQuery) String() string {
+	return d.ID()
+}
+
+// Stop halts the query's fetch function.
+func (d *servicesQuery) Stop() {
+	close(d.stopCh)
+}
+
+// healthServiceFromEntry converts a Consul service entry to the hcat type for
+// a service instance
+func healthServiceFromEntry(entry consulapi.ServiceEntry) *dep.HealthService {
+	address := entry.Service.Address
+	if address == "" {
+		address = entry.Node.Address
+	}
+
+	return &dep.HealthService{
+		Node:                entry.Node.Node,
+		NodeID:              entry.Node.ID,
+		Kind:                string(entry.Service.Kind),
+		NodeAddress:         entry.Node.Address,
+		NodeDatacenter:      entry.Node.Datacenter,
+		NodeTaggedAddresses: entry.Node.TaggedAddresses,
+		NodeMeta:            entry.Node.Meta,
+		ServiceMeta:         entry.Service.Meta,
+		Address:             address,
+		ID:                  entry.Service.ID,
+		Name:                entry.Service.Service,
+		Tags: dep.ServiceTags(
+			deepCopyAndSortTags(entry.Service.Tags)),
+		Status:    entry.Checks.AggregatedStatus(),
+		Checks:    entry.Checks,
+		Port:      entry.Service.Port,
+		Weights:   entry.Service.Weights,
+		Namespace: entry.Service.Namespace,
+	}
+}
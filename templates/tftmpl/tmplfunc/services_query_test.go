@@ -0,0 +1,175 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tmplfunc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/hashicorp/hcat/dep"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePreparedQuery is a preparedQueryExecutor that returns a fixed response
+type fakePreparedQuery struct {
+	resp *consulapi.PreparedQueryExecuteResponse
+	err  error
+
+	query string
+	opts  *consulapi.QueryOptions
+}
+
+func (q *fakePreparedQuery) Execute(queryIDOrName string, opts *consulapi.QueryOptions) (
+	*consulapi.PreparedQueryExecuteResponse, *consulapi.QueryMeta, error) {
+	q.query = queryIDOrName
+	q.opts = opts
+	return q.resp, &consulapi.QueryMeta{}, q.err
+}
+
+func TestNewServicesQuery(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		opts []string
+		exp  *servicesQuery
+		err  bool
+	}{
+		{
+			"query only",
+			[]string{"query=nearest-api"},
+			&servicesQuery{
+				query:    "nearest-api",
+				interval: defaultServicesQueryInterval,
+			},
+			false,
+		},
+		{
+			"all parameters",
+			[]string{"query=nearest-api", "dc=dc2", "near=_agent", "interval=5s"},
+			&servicesQuery{
+				query:    "nearest-api",
+				dc:       "dc2",
+				near:     "_agent",
+				interval: 5 * time.Second,
+			},
+			false,
+		},
+		{
+			"missing query",
+			[]string{"dc=dc2"},
+			nil,
+			true,
+		},
+		{
+			"invalid parameter",
+			[]string{"query=api", "regexp=.*"},
+			nil,
+			true,
+		},
+		{
+			"invalid format",
+			[]string{"query"},
+			nil,
+			true,
+		},
+		{
+			"invalid interval",
+			[]string{"query=api", "interval=soon"},
+			nil,
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := newServicesQuery(tc.opts)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, actual.stopCh)
+			tc.exp.stopCh = actual.stopCh
+			assert.Equal(t, tc.exp, actual)
+		})
+	}
+}
+
+func TestServicesQuery_Fetch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("failover results", func(t *testing.T) {
+		d, err := newServicesQuery([]string{"query=nearest-api", "dc=dc1"})
+		require.NoError(t, err)
+		q := &fakePreparedQuery{
+			resp: &consulapi.PreparedQueryExecuteResponse{
+				Service:    "api",
+				Datacenter: "dc2",
+				Failovers:  1,
+				Nodes: []consulapi.ServiceEntry{
+					{
+						Node: &consulapi.Node{Node: "node-b", Address: "10.0.0.2",
+							Datacenter: "dc2"},
+						Service: &consulapi.AgentService{ID: "api-2", Service: "api",
+							Tags: []string{"b", "a"}},
+					},
+					{
+						Node: &consulapi.Node{Node: "node-a", Address: "10.0.0.1",
+							Datacenter: "dc2"},
+						Service: &consulapi.AgentService{ID: "api-1", Service: "api",
+							Address: "10.1.0.1", Port: 8080},
+					},
+				},
+			},
+		}
+		d.executor = q
+
+		data, rm, err := d.Fetch(nil)
+		require.NoError(t, err)
+		assert.NotNil(t, rm)
+		assert.Equal(t, "nearest-api", q.query)
+		assert.Equal(t, "dc1", q.opts.Datacenter)
+
+		services, ok := data.([]*dep.HealthService)
+		require.True(t, ok)
+		require.Len(t, services, 2)
+		assert.Equal(t, "api-1", services[0].ID)
+		assert.Equal(t, "10.1.0.1", services[0].Address)
+		assert.Equal(t, "dc2", services[0].NodeDatacenter)
+		assert.Equal(t, "api-2", services[1].ID)
+		assert.Equal(t, "10.0.0.2", services[1].Address)
+		assert.Equal(t, dep.ServiceTags{"a", "b"}, services[1].Tags)
+	})
+
+	t.Run("execute error", func(t *testing.T) {
+		d, err := newServicesQuery([]string{"query=nearest-api"})
+		require.NoError(t, err)
+		d.executor = &fakePreparedQuery{err: errors.New("query not found")}
+
+		_, _, err = d.Fetch(nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		d, err := newServicesQuery([]string{"query=nearest-api"})
+		require.NoError(t, err)
+		d.executor = &fakePreparedQuery{}
+		d.Stop()
+
+		_, _, err = d.Fetch(nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestServicesQuery_String(t *testing.T) {
+	t.Parallel()
+
+	d, err := newServicesQuery([]string{"query=nearest-api", "dc=dc2"})
+	require.NoError(t, err)
+	assert.Equal(t, "service.query(query=nearest-api&dc=dc2)", d.String())
+}
@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	consulapi "github.com/hashicorp/consul/api"
@@ -17,6 +19,11 @@ import (
 	"github.com/pkg/errors"
 )
 
+// bootstrapConcurrency is the number of concurrent requests to fetch the
+// health of the matching services during the initial fetch of a bootstrapped
+// query
+const bootstrapConcurrency = 16
+
 var (
 	// queryParamOptRe is the regular expression to distinguish between query
 	// params and filters, excluding the regex parameter. Non-regex query parameters
@@ -33,6 +40,13 @@ var (
 // It supports parameters filter, dc, ns, and node-meta on the
 // Health API query only.
 //
+// The bootstrap parameter speeds up the initial load of queries matching many
+// services. The initial fetch queries the Health API for the matching
+// services concurrently instead of one at a time, and afterwards the query
+// switches to blocking queries on the Catalog List Services API. The number
+// of requests still scales with the number of matching services since Consul
+// has no public API to fetch the instances of multiple services at once.
+//
 // Endpoints:
 //
 //	/v1/catalog/services
@@ -64,11 +78,14 @@ type servicesRegexQuery struct {
 
 	regexp *regexp.Regexp
 
-	filter   string
-	dc       string
-	ns       string
-	nodeMeta map[string]string
-	opts     hcat.QueryOptions
+	filter    string
+	dc        string
+	ns        string
+	nodeMeta  map[string]string
+	bootstrap bool
+	opts      hcat.QueryOptions
+
+	fetched bool
 }
 
 // newServicesRegexQuery processes options in the format of
@@ -115,6 +132,15 @@ func newServicesRegexQuery(opts []string) (*servicesRegexQuery, error) {
 				}
 				servicesRegexQuery.nodeMeta[k] = v
 				continue
+			case "bootstrap":
+				b, err := strconv.ParseBool(value)
+				if err != nil {
+					return nil, fmt.Errorf(
+						"service.regex: invalid value for query "+
+							"parameter %q: %s", query, value)
+				}
+				servicesRegexQuery.bootstrap = b
+				continue
 			}
 		}
 
@@ -203,45 +229,85 @@ func (d *servicesRegexQuery) Fetch(clients dep.Clients) (interface{}, *dep.Respo
 	// Without this delay, CatalogServices may have services that are not yet
 	// propagated to HealthServices as healthy services since services are initially
 	// set as critical. https://www.consul.io/docs/discovery/checks#initial-health-check-status
-	time.Sleep(10 * time.Second)
+	//
+	// The initial fetch of a bootstrapped query is not the result of a change,
+	// so it skips the delay and fetches the health of the services concurrently.
+	var services []*dep.HealthService
+	if d.bootstrap && !d.fetched {
+		services, err = fetchHealthServicesBulk(clients, matchServices, opts)
+	} else {
+		time.Sleep(10 * time.Second)
+		services, err = fetchHealthServices(clients, matchServices, opts)
+	}
+	if err != nil {
+		return nil, nil, errors.Wrap(err, d.String())
+	}
+	d.fetched = true
+
+	sort.Stable(ByNodeThenID(services))
+	return services, rm, nil
+}
+
+// fetchHealthServices fetches the healthy instances of the services with
+// synchronous requests to the Health API for each service
+func fetchHealthServices(clients dep.Clients, names []string,
+	opts *consulapi.QueryOptions) ([]*dep.HealthService, error) {
 
 	var services []*dep.HealthService
-	for _, s := range matchServices {
-		var entries []*consulapi.ServiceEntry
-		entries, _, err = clients.Consul().Health().Service(s, "", true, opts)
+	for _, s := range names {
+		entries, _, err := clients.Consul().Health().Service(s, "", true, opts)
 		if err != nil {
-			return nil, nil, errors.Wrap(err, d.String())
+			return nil, err
 		}
 		for _, entry := range entries {
-			address := entry.Service.Address
-			if address == "" {
-				address = entry.Node.Address
-			}
-			services = append(services, &dep.HealthService{
-				Node:                entry.Node.Node,
-				NodeID:              entry.Node.ID,
-				Kind:                string(entry.Service.Kind),
-				NodeAddress:         entry.Node.Address,
-				NodeDatacenter:      entry.Node.Datacenter,
-				NodeTaggedAddresses: entry.Node.TaggedAddresses,
-				NodeMeta:            entry.Node.Meta,
-				ServiceMeta:         entry.Service.Meta,
-				Address:             address,
-				ID:                  entry.Service.ID,
-				Name:                entry.Service.Service,
-				Tags: dep.ServiceTags(
-					deepCopyAndSortTags(entry.Service.Tags)),
-				Status:    entry.Checks.AggregatedStatus(),
-				Checks:    entry.Checks,
-				Port:      entry.Service.Port,
-				Weights:   entry.Service.Weights,
-				Namespace: entry.Service.Namespace,
-			})
+			services = append(services, healthServiceFromEntry(*entry))
 		}
 	}
+	return services, nil
+}
 
-	sort.Stable(ByNodeThenID(services))
-	return services, rm, nil
+// fetchHealthServicesBulk fetches the healthy instances of the services with
+// up to bootstrapConcurrency concurrent requests to the Health API.
+//
+// Consul does not have a public API to fetch the instances of multiple
+// services in a single request: the Health API is per service, the health
+// state endpoint only returns the checks without the service instances, and
+// the endpoints that aggregate services for the UI are internal. The bootstrap
+// fetch therefore still makes one request per matching service, and the
+// results are not a consistent snapshot across services. The concurrency
+// bounds the load on Consul while reducing the time of the initial load.
+func fetchHealthServicesBulk(clients dep.Clients, names []string,
+	opts *consulapi.QueryOptions) ([]*dep.HealthService, error) {
+
+	results := make([][]*consulapi.ServiceEntry, len(names))
+	errs := make([]error, len(names))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, bootstrapConcurrency)
+	for i, s := range names {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, s string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i], _, errs[i] = clients.Consul().Health().Service(
+				s, "", true, opts)
+		}(i, s)
+	}
+	wg.Wait()
+
+	var services []*dep.HealthService
+	for i := range names {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		for _, entry := range results[i] {
+			services = append(services, healthServiceFromEntry(*entry))
+		}
+	}
+	return services, nil
 }
 
 // SetOptions satisfies the hcat.QueryOptionsSetter interface which enables
@@ -264,6 +330,9 @@ func (d *servicesRegexQuery) ID() string {
 	for k, v := range d.nodeMeta {
 		opts = append(opts, fmt.Sprintf("node-meta=%s:%s", k, v))
 	}
+	if d.bootstrap {
+		opts = append(opts, "bootstrap=true")
+	}
 	if d.filter != "" {
 		opts = append(opts, fmt.Sprintf("filter=%s", d.filter))
 	}
@@ -62,12 +62,27 @@ func TestNewServicesRegexQuery(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"bootstrap",
+			[]string{"regexp=.*", "bootstrap=true"},
+			&servicesRegexQuery{
+				regexp:    regexp.MustCompile(".*"),
+				bootstrap: true,
+			},
+			false,
+		},
 		{
 			"invalid query",
 			[]string{"regexp=.*", "invalid=true"},
 			nil,
 			true,
 		},
+		{
+			"invalid bootstrap",
+			[]string{"regexp=.*", "bootstrap=sometimes"},
+			nil,
+			true,
+		},
 		{
 			"invalid query format",
 			[]string{"regexp"},
@@ -112,6 +127,11 @@ func TestServicesRegexQuery_String(t *testing.T) {
 			[]string{"node-meta=k:v", "dc=dc1", "ns=namespace", "regexp=web", "\"my-tag\" in Service.Tags"},
 			`service.regex(dc=dc1&filter="my-tag" in Service.Tags&node-meta=k:v&ns=namespace&regexp=web)`,
 		},
+		{
+			"bootstrap",
+			[]string{"regexp=web", "bootstrap=true"},
+			"service.regex(bootstrap=true&regexp=web)",
+		},
 	}
 
 	for _, tc := range cases {
@@ -267,6 +287,17 @@ func TestServicesRegexQuery_Fetch(t *testing.T) {
 				consulSrv2,
 			},
 		},
+		{
+			"bootstrap",
+			[]string{"regexp=.*", "bootstrap=true"},
+			[]*dep.HealthService{
+				apiSrv,
+				apiWebSrv,
+				webSrv,
+				consulSrv1,
+				consulSrv2,
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -18,11 +18,18 @@ import (
 func HCLMap(meta *ServicesMeta) template.FuncMap {
 	tmplFuncs := tfunc.FuncMapConsulV1()
 	tmplFuncs["catalogServicesRegistration"] = catalogServicesRegistrationFunc
+	tmplFuncs["catalogServicesInstances"] = catalogServicesInstancesFunc
 	tmplFuncs["servicesRegex"] = servicesRegexFunc
+	tmplFuncs["servicesQuery"] = servicesQueryFunc
+	tmplFuncs["dnsRecords"] = dnsRecordsFunc
+	tmplFuncs["localFiles"] = localFilesFunc
+	tmplFuncs["plugin"] = pluginFunc
 	tmplFuncs["indent"] = tfunc.Helpers()["indent"]
 	tmplFuncs["subtract"] = tfunc.Math()["subtract"]
 	tmplFuncs["joinStrings"] = joinStringsFunc
+	tmplFuncs["hclString"] = hclStringFunc
 	tmplFuncs["HCLService"] = hclServiceFunc(meta)
+	tmplFuncs["HCLServiceExtended"] = hclServiceExtendedFunc(meta)
 	tmplFuncs["HCLServiceTags"] = hclServiceTagsFunc()
 	return tmplFuncs
 }
@@ -45,6 +52,43 @@ func joinStringsFunc(sep string, values ...string) string {
 	return strings.Join(cleaned, sep)
 }
 
+// hclStringFunc returns the value as a quoted HCL string. Characters that
+// would otherwise be interpreted by HCL, such as quotes, newlines, and
+// template sequences "${" and "%{", are escaped.
+func hclStringFunc(v string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		switch c {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '$', '%':
+			// escape template sequences by doubling the leading character
+			if i+1 < len(v) && v[i+1] == '{' {
+				b.WriteByte(c)
+			}
+			b.WriteByte(c)
+		default:
+			if c < 0x20 || c == 0x7f {
+				fmt.Fprintf(&b, `\u%04x`, c)
+				continue
+			}
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
 // hclServiceTagsFunc is a wrapper of the template function to marshal Consul
 // catalog service tag information into HCL. It returns the list of tags with
 // formatted like: "["tag1", "tag2"]". It returns an empty array string "[]"
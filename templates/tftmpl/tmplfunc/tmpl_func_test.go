@@ -41,3 +41,44 @@ func TestJoinStringsFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestHCLStringFunc(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			"empty",
+			"",
+			`""`,
+		}, {
+			"string",
+			"foobar",
+			`"foobar"`,
+		}, {
+			"quotes and backslashes",
+			`say "hi" \o/`,
+			`"say \"hi\" \\o/"`,
+		}, {
+			"whitespace",
+			"a\tb\r\nc",
+			`"a\tb\r\nc"`,
+		}, {
+			"template sequences",
+			"${var.a} %{if x} $5 100%",
+			`"$${var.a} %%{if x} $5 100%"`,
+		}, {
+			"control characters",
+			"a\x00b",
+			`"a\u0000b"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := hclStringFunc(tc.content)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
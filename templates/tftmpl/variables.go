@@ -51,6 +51,43 @@ variable "services" {
 }
 `)
 
+// VariableServicesExtended is the services variable with extended metadata
+// for each service instance. It is used instead of VariableServices when a
+// task is configured to include extended metadata.
+var VariableServicesExtended = []byte(`
+# Service definition protocol v0
+variable "services" {
+  description = "Consul services monitored by Consul-Terraform-Sync"
+  type = map(
+    object({
+      id        = string
+      name      = string
+      kind      = string
+      address   = string
+      port      = number
+      meta      = map(string)
+      tags      = list(string)
+      namespace = string
+      status    = string
+
+      node                  = string
+      node_id               = string
+      node_address          = string
+      node_datacenter       = string
+      node_tagged_addresses = map(string)
+      node_meta             = map(string)
+
+      cts_user_defined_meta = map(string)
+
+      weights = object({
+        passing = number
+        warning = number
+      })
+    })
+  )
+}
+`)
+
 // newVariablesTF writes variable definitions to a file. This includes the
 // required services variable and generated provider variables based on CTS
 // user configuration for the task.
@@ -61,10 +98,20 @@ func newVariablesTF(w io.Writer, filename string, input *RootModuleInputData) er
 	}
 
 	// service variable is required to append
-	if _, err = w.Write(VariableServices); err != nil {
+	servicesVar := VariableServices
+	if includesExtendedMetadata(input.Templates) {
+		servicesVar = VariableServicesExtended
+	}
+	if _, err = w.Write(servicesVar); err != nil {
 		return err
 	}
 
+	if input.Task.ServicesChanged {
+		if _, err = w.Write(VariableServicesChanged); err != nil {
+			return err
+		}
+	}
+
 	// append a variable for each template unless template's variable is
 	// a services variable. services variable already appended above.
 	// note: assumes templates' variables are unique type. otherwise would
@@ -85,9 +132,17 @@ func newVariablesTF(w io.Writer, filename string, input *RootModuleInputData) er
 
 	hclFile := hclwrite.NewEmptyFile()
 	rootBody := hclFile.Body()
+	aliased := len(input.Task.ProviderAliases) > 0
 	for _, p := range input.Providers {
 		rootBody.AppendNewline()
-		appendNamedBlockVariable(rootBody, p, input.TerraformVersion, true)
+		block := p.Copy()
+		block.Name = providerVarName(p, aliased)
+		appendNamedBlockVariable(rootBody, block, input.TerraformVersion, true)
+	}
+
+	for _, name := range input.ComputedInputs {
+		rootBody.AppendNewline()
+		appendComputedInputVariable(rootBody, name)
 	}
 
 	// Format the file before writing
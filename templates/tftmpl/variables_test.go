@@ -274,3 +274,52 @@ tup = tuple([string, string, number])
 		})
 	}
 }
+
+func TestIncludesExtendedMetadata(t *testing.T) {
+	testCases := []struct {
+		name      string
+		templates []Template
+		expected  bool
+	}{
+		{
+			"no templates",
+			nil,
+			false,
+		}, {
+			"services not extended",
+			[]Template{&ServicesTemplate{Names: []string{"api"}, RenderVar: true}},
+			false,
+		}, {
+			"services extended",
+			[]Template{
+				&ConsulKVTemplate{Path: "key", RenderVar: true},
+				&ServicesTemplate{
+					Names:                   []string{"api"},
+					RenderVar:               true,
+					IncludeExtendedMetadata: true,
+				},
+			},
+			true,
+		}, {
+			"services regex extended",
+			[]Template{&ServicesRegexTemplate{
+				Regexp:                  ".*",
+				RenderVar:               true,
+				IncludeExtendedMetadata: true,
+			}},
+			true,
+		}, {
+			"extended but not rendered",
+			[]Template{&ServicesTemplate{
+				Names:                   []string{"api"},
+				IncludeExtendedMetadata: true,
+			}},
+			false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, includesExtendedMetadata(tc.templates))
+		})
+	}
+}
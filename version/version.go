@@ -32,8 +32,46 @@ var (
 	VersionPrerelease = ""
 
 	VersionMetadata = ""
+
+	// Features are the optional features that are compiled into the binary,
+	// such as FeatureEnterprise. Builds that include a feature add it to the
+	// list.
+	Features []string
+)
+
+const (
+	// FeatureEnterprise is the feature of CTS Enterprise builds
+	FeatureEnterprise = "enterprise"
+
+	// FeatureHighAvailability is the feature of builds that can run CTS
+	// instances as a cluster with high availability
+	FeatureHighAvailability = "high_availability"
 )
 
+// GetSemanticVersion returns the version with the pre-release marker and
+// metadata but without the git information. The result can be parsed by
+// github.com/hashicorp/go-version to compare versions.
+func GetSemanticVersion() string {
+	version := Version
+	if VersionPrerelease != "" {
+		version += fmt.Sprintf("-%s", VersionPrerelease)
+	}
+	if VersionMetadata != "" {
+		version += fmt.Sprintf("+%s", VersionMetadata)
+	}
+	return version
+}
+
+// HasFeature returns true if the feature is compiled into the binary
+func HasFeature(feature string) bool {
+	for _, f := range Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
 // GetHumanVersion composes the parts of the version in a way that's suitable
 // for displaying to humans.
 func GetHumanVersion() string {
@@ -0,0 +1,26 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package version
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSemanticVersion(t *testing.T) {
+	v := GetSemanticVersion()
+	_, err := version.NewVersion(v)
+	assert.NoError(t, err, "version %q", v)
+}
+
+func TestHasFeature(t *testing.T) {
+	features := Features
+	Features = []string{FeatureEnterprise}
+	t.Cleanup(func() { Features = features })
+
+	assert.True(t, HasFeature(FeatureEnterprise))
+	assert.False(t, HasFeature(FeatureHighAvailability))
+}